@@ -0,0 +1,1301 @@
+// Package app wires the driver service together: MongoDB, repositories, use
+// cases, background jobs and the HTTP router. cmd/driver-service serves it;
+// the end-to-end tests boot it in-process next to the gateway.
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/blacklist"
+	"github.com/bitaksi/driver-service/internal/changestream"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/events"
+	"github.com/bitaksi/driver-service/internal/fieldcrypt"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/geoindex"
+	"github.com/bitaksi/driver-service/internal/handler"
+	"github.com/bitaksi/driver-service/internal/jobrunner"
+	"github.com/bitaksi/driver-service/internal/kyc"
+	"github.com/bitaksi/driver-service/internal/logging"
+	"github.com/bitaksi/driver-service/internal/matching"
+	"github.com/bitaksi/driver-service/internal/metrics"
+	"github.com/bitaksi/driver-service/internal/middleware"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"github.com/bitaksi/driver-service/internal/repository/shadow"
+	"github.com/bitaksi/driver-service/internal/routing"
+	"github.com/bitaksi/driver-service/internal/rules"
+	"github.com/bitaksi/driver-service/internal/sms"
+	"github.com/bitaksi/driver-service/internal/storage"
+	"github.com/bitaksi/driver-service/internal/taxitype"
+	"github.com/bitaksi/driver-service/internal/telemetry"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/bitaksi/driver-service/internal/warmup"
+	"github.com/bitaksi/driver-service/internal/webhook"
+	"github.com/bitaksi/driver-service/pkg/errreport"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	// The runtime image has no zoneinfo; earnings are bucketed in a named time zone
+	_ "time/tzdata"
+)
+
+// App is a wired driver service
+type App struct {
+	cfg    *config.Config
+	logger *zap.Logger
+	db     *mongo.Database
+	// shadowDB is the database driver reads are mirrored to; nil unless enabled
+	shadowDB *mongo.Database
+	router   *gin.Engine
+	// admin serves the operational endpoints on ADMIN_PORT; nil when unset
+	admin   *gin.Engine
+	limiter *middleware.ConcurrencyLimiter
+	// reporter sends errors and panics to the error tracker
+	reporter errreport.Reporter
+
+	jobs     []func(ctx context.Context)
+	stopJobs context.CancelFunc
+	running  sync.WaitGroup
+}
+
+// LoadConfig loads the service configuration from the environment
+func LoadConfig() *config.Config {
+	return config.Load()
+}
+
+// New connects to MongoDB, ensures indexes and wires the service. Loggers are
+// named after the package they serve so LOG_LEVELS and /admin/loglevel can tune
+// them separately; logLevels may be nil when levels are not adjustable.
+func New(cfg *config.Config, logger *zap.Logger, logLevels *logging.Levels) (_ *App, err error) {
+	if logLevels == nil {
+		if logLevels, err = logging.NewLevels(cfg.Logging.Level, cfg.Logging.Levels); err != nil {
+			return nil, err
+		}
+	}
+	// Report panics and the errors of the configured loggers to the error tracker
+	reporter, err := errreport.New(errreport.Options{
+		DSN:         cfg.ErrorReports.DSN,
+		Environment: cfg.ErrorReports.Environment,
+		Release:     cfg.ErrorReports.Release,
+		SampleRate:  cfg.ErrorReports.SampleRate,
+		ScrubFields: cfg.ErrorReports.ScrubFields,
+	}, logger.Named("errreport"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize error reporting: %w", err)
+	}
+	logger = errreport.WithCore(logger, reporter, cfg.ErrorReports.Loggers)
+	repoLogger := logger.Named("mongodb")
+	useCaseLogger := logger.Named("usecase")
+	handlerLogger := logger.Named("handler")
+
+	// Retry transient errors during replica-set failovers
+	retrier := mongodb.NewRetrier(mongodb.RetryPolicy{
+		MaxAttempts: cfg.MongoDB.RetryAttempts,
+		BaseDelay:   cfg.MongoDB.RetryBaseDelay,
+		MaxDelay:    cfg.MongoDB.RetryMaxDelay,
+	}, repoLogger.Named("retry"))
+	// Time every command and log slow ones, to spot missing indexes
+	queryMonitor := mongodb.NewQueryMonitor(cfg.MongoDB.SlowQueryThreshold, repoLogger.Named("queries"))
+
+	// Connect to MongoDB
+	db, err := ConnectMongoDB(cfg.MongoDB, retrier, queryMonitor, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			db.Client().Disconnect(context.Background())
+		}
+	}()
+
+	// Initialize repositories
+	repoOpts, err := FieldEncryptionOptions(cfg.Encryption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize field encryption: %w", err)
+	}
+	readPrefs, err := ReadPreferences(cfg.MongoDB)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MongoDB read preference: %w", err)
+	}
+	repoOpts = append(repoOpts, mongodb.WithRetries(retrier), mongodb.WithReadPreferences(readPrefs))
+	cityOpts, err := CityOptions(cfg.Cities, logger)
+	if err != nil {
+		return nil, err
+	}
+	repoOpts = append(repoOpts, cityOpts...)
+	if cfg.MongoDB.GeoNear {
+		// MongoDB sorts and pages nearby searches on the position index
+		repoOpts = append(repoOpts, mongodb.WithGeoNear())
+	}
+	if cfg.GeoCache.Enabled {
+		// Nearby searches are answered from memory once the background job has loaded the cache
+		repoOpts = append(repoOpts, mongodb.WithGeoCache(geoindex.New(cfg.GeoCache.CellKm), cfg.GeoCache.MaxStaleness))
+	}
+	driverRepo := mongodb.NewDriverRepository(db, repoLogger, repoOpts...)
+	shadowDB, shadowReads, err := ShadowReads(cfg, driverRepo, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil && shadowDB != nil {
+			shadowDB.Client().Disconnect(context.Background())
+		}
+	}()
+	// The driver use case reads through the shadow mirror when it is enabled
+	var driverReads domain.DriverRepository = driverRepo
+	if shadowReads != nil {
+		driverReads = shadowReads
+	}
+	verificationRepo := mongodb.NewVerificationRepository(db, repoLogger)
+	tripRepo := mongodb.NewTripRepository(db, repoLogger)
+	activityRepo := mongodb.NewActivityRepository(db, repoLogger)
+	utilizationRepo := mongodb.NewUtilizationRepository(db, repoLogger)
+	fleetRepo := mongodb.NewFleetRepository(db, repoLogger)
+	webhookRepo := mongodb.NewWebhookRepository(db, repoLogger)
+	riderRepo := mongodb.NewRiderRepository(db, repoLogger)
+	earningsRepo := mongodb.NewEarningsRepository(db, repoLogger)
+	kycRepo := mongodb.NewKYCRepository(db, repoLogger)
+	deviceTokenRepo := mongodb.NewDeviceTokenRepository(db, repoLogger)
+	retentionRepo := mongodb.NewRetentionRepository(db, repoLogger)
+	scheduleRepo := mongodb.NewScheduleRepository(db, repoLogger)
+	incidentRepo := mongodb.NewIncidentRepository(db, repoLogger)
+
+	// Missing indexes are created before serving; readiness checks them again
+	if cfg.MongoDB.EnsureIndexes {
+		indexCtx, indexCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer indexCancel()
+		if err := driverRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure driver indexes: %w", err)
+		}
+		if err := verificationRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure verification indexes: %w", err)
+		}
+		if err := tripRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure trip indexes: %w", err)
+		}
+		if err := activityRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure activity indexes: %w", err)
+		}
+		if err := fleetRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure fleet indexes: %w", err)
+		}
+		if err := webhookRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure webhook indexes: %w", err)
+		}
+		if err := riderRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure rider indexes: %w", err)
+		}
+		if err := earningsRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure earnings indexes: %w", err)
+		}
+		if err := kycRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure kyc indexes: %w", err)
+		}
+		if err := deviceTokenRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure device token indexes: %w", err)
+		}
+		if err := utilizationRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure utilization indexes: %w", err)
+		}
+		if err := retentionRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure retention indexes: %w", err)
+		}
+		if err := scheduleRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure schedule indexes: %w", err)
+		}
+		if err := incidentRepo.EnsureIndexes(indexCtx); err != nil {
+			return nil, fmt.Errorf("failed to ensure incident indexes: %w", err)
+		}
+	}
+
+	routeProvider, err := routing.NewRouter(cfg.Routing.Provider, routing.Options{
+		OSRMURL:     cfg.Routing.OSRMURL,
+		OSRMProfile: cfg.Routing.OSRMProfile,
+		Timeout:     cfg.Routing.Timeout,
+		SpeedKmh:    cfg.Routing.SpeedKmh,
+	}, logger.Named("routing"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid routing configuration: %w", err)
+	}
+	logger.Info("routing configured", zap.String("provider", routeProvider.Name()))
+
+	fileStore, err := storage.NewStore(cfg.Storage.Provider, storage.Options{
+		Dir:     cfg.Storage.Dir,
+		BaseURL: cfg.Storage.BaseURL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage configuration: %w", err)
+	}
+
+	kycProvider, err := kyc.NewProvider(cfg.KYC.Provider, kyc.Options{
+		URL:     cfg.KYC.URL,
+		APIKey:  cfg.KYC.APIKey,
+		Timeout: cfg.KYC.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid kyc configuration: %w", err)
+	}
+	if kycProvider.Name() == kyc.ProviderSandbox {
+		logger.Warn("sandbox KYC provider in use; identity checks are decided without verification")
+	}
+
+	var blacklistCheck usecase.BlacklistCheck
+	if cfg.Blacklist.Checker != "" {
+		if cfg.Blacklist.FailMode != "open" && cfg.Blacklist.FailMode != "closed" {
+			return nil, fmt.Errorf("invalid blacklist fail mode %q: must be open or closed", cfg.Blacklist.FailMode)
+		}
+		checker, err := blacklist.NewChecker(cfg.Blacklist.Checker, blacklist.Options{
+			URL:      cfg.Blacklist.URL,
+			APIKey:   cfg.Blacklist.APIKey,
+			Timeout:  cfg.Blacklist.Timeout,
+			CacheTTL: cfg.Blacklist.CacheTTL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid blacklist configuration: %w", err)
+		}
+		if checker.Name() == blacklist.CheckerSandbox {
+			logger.Warn("sandbox blacklist checker in use; drivers are not checked against a real blacklist")
+		}
+		blacklistCheck = usecase.BlacklistCheck{Checker: checker, FailClosed: cfg.Blacklist.FailMode == "closed"}
+	}
+
+	// The registry decides which taxi types are valid, so it is installed before
+	// the validation rules are compiled against it
+	taxiTypes, err := taxitype.Load(cfg.TaxiTypes.File)
+	if err != nil {
+		return nil, fmt.Errorf("invalid taxi types: %w", err)
+	}
+	domain.SetTaxiTypes(taxiTypes.IDs())
+	// Map clients bucket drivers into tiles by the geohash beside their coordinates
+	domain.SetGeohashPrecision(cfg.Geohash.Precision)
+	for taxiType := range cfg.Fares.Multipliers {
+		if !domain.TaxiType(taxiType).IsValid() {
+			return nil, fmt.Errorf("invalid FARE_TAXI_TYPE_MULTIPLIERS: unknown taxi type %q", taxiType)
+		}
+	}
+	logger.Info("taxi types loaded", zap.Int("count", len(taxiTypes.IDs())))
+
+	validationRules, err := rules.Load(cfg.Validation.RulesFile, cfg.Validation.Country)
+	if err != nil {
+		return nil, fmt.Errorf("invalid validation rules: %w", err)
+	}
+	effectiveRules := validationRules.Effective()
+	logger.Info("validation rules loaded",
+		zap.String("country", effectiveRules.Country), zap.Int("tenants", len(effectiveRules.Tenants)))
+
+	// Stored positions are rounded and kept inside the service area
+	var serviceArea *geofence.Area
+	if cfg.ServiceArea.Enabled {
+		if serviceArea, err = geofence.Load(cfg.ServiceArea.File, cfg.ServiceArea.Precision); err != nil {
+			return nil, fmt.Errorf("invalid service area: %w", err)
+		}
+		logger.Info("service area loaded", zap.Int("regions", len(serviceArea.Regions())), zap.Int("precision", cfg.ServiceArea.Precision))
+	}
+
+	// Anonymized nearby searches feed demand heatmaps unless opted out; driver
+	// changes and fleet quota thresholds go to the same sink
+	var analyticsBus *events.Bus
+	if cfg.Analytics.SearchEvents || (cfg.ChangeStream.Enabled && cfg.ChangeStream.PublishEvents) || cfg.Quotas.Enabled() {
+		sink, err := events.NewSink(cfg.Analytics.Sink, events.Options{
+			URL:     cfg.Analytics.URL,
+			Timeout: cfg.Analytics.Timeout,
+		}, logger.Named("analytics"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid analytics configuration: %w", err)
+		}
+		analyticsBus = events.NewBus(sink, cfg.Analytics.BufferSize, logger.Named("analytics"))
+		logger.Info("analytics enabled", zap.String("sink", cfg.Analytics.Sink),
+			zap.Bool("searchEvents", cfg.Analytics.SearchEvents), zap.Float64("sampleRate", cfg.Analytics.SampleRate))
+	}
+
+	earningsLocation, err := time.LoadLocation(cfg.Earnings.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid earnings timezone: %w", err)
+	}
+
+	// Initialize use cases
+	webhookUseCase := usecase.NewWebhookUseCase(
+		webhookRepo,
+		fleetRepo,
+		webhook.NewSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateTargets),
+		usecase.WebhookOptions{
+			MaxAttempts:         cfg.Webhooks.MaxAttempts,
+			BackoffBase:         cfg.Webhooks.BackoffBase,
+			BackoffMax:          cfg.Webhooks.BackoffMax,
+			Lease:               cfg.Webhooks.Timeout + 30*time.Second,
+			AllowPrivateTargets: cfg.Webhooks.AllowPrivateTargets,
+			// Plain http is only accepted in debug mode, as in setupRouter
+			RequireHTTPS: cfg.Logging.Level != "debug",
+		},
+		useCaseLogger,
+	)
+	quotaOpts := usecase.QuotaOptions{
+		MaxDrivers: cfg.Quotas.MaxDrivers,
+		Tenants:    cfg.Quotas.Tenants,
+		Thresholds: cfg.Quotas.Thresholds,
+	}
+	var quotaEvents domain.EventPublisher
+	if analyticsBus != nil {
+		quotaEvents = analyticsBus
+	}
+	driverQuotas := usecase.NewDriverQuotas(driverRepo, quotaOpts, quotaEvents, useCaseLogger)
+	if cfg.Quotas.Enabled() {
+		logger.Info("fleet driver quotas enabled", zap.Int("maxDrivers", cfg.Quotas.MaxDrivers),
+			zap.Int("tenants", len(cfg.Quotas.Tenants)), zap.Ints("thresholds", cfg.Quotas.Thresholds))
+	}
+	driverOpts := []usecase.DriverUseCaseOption{
+		usecase.WithActivityRecording(activityRepo),
+		usecase.WithFleets(fleetRepo),
+		usecase.WithEvents(webhookUseCase),
+		usecase.WithHeartbeatFilter(cfg.Heartbeat.Timeout, cfg.Heartbeat.FilterNearby),
+		usecase.WithPageSizes(cfg.Pagination.DefaultPageSize, cfg.Pagination.MaxPageSize),
+		usecase.WithNearbyLimits(cfg.Pagination.DefaultNearbyLimit, cfg.Pagination.MaxNearbyLimit),
+		usecase.WithRouter(routeProvider),
+		usecase.WithValidationRules(validationRules),
+		usecase.WithDriverQuotas(driverQuotas),
+	}
+	if serviceArea != nil {
+		driverOpts = append(driverOpts, usecase.WithServiceArea(serviceArea))
+	}
+	if cfg.Plausibility.MaxSpeedKmh > 0 {
+		if cfg.Plausibility.Action != "flag" && cfg.Plausibility.Action != "reject" {
+			return nil, fmt.Errorf("invalid location jump action %q: must be flag or reject", cfg.Plausibility.Action)
+		}
+		driverOpts = append(driverOpts, usecase.WithLocationPlausibility(activityRepo, usecase.LocationPlausibilityOptions{
+			MaxSpeedKmh:   cfg.Plausibility.MaxSpeedKmh,
+			MinDistanceKm: cfg.Plausibility.MinDistanceKm,
+			Reject:        cfg.Plausibility.Action == "reject",
+		}))
+	}
+	if blacklistCheck.Checker != nil {
+		driverOpts = append(driverOpts, usecase.WithBlacklistCheck(blacklistCheck))
+	}
+	if cfg.Analytics.SearchEvents {
+		driverOpts = append(driverOpts, usecase.WithSearchAnalytics(analyticsBus, usecase.SearchAnalyticsOptions{
+			SampleRate: cfg.Analytics.SampleRate,
+			Precision:  cfg.Analytics.Precision,
+		}))
+	}
+	businessMetrics := metrics.New(driverRepo, cfg.Metrics.StatusRefresh)
+	driverOpts = append(driverOpts, usecase.WithMetrics(businessMetrics))
+	driverUseCase := usecase.NewDriverUseCase(driverReads, useCaseLogger, driverOpts...)
+	heartbeatUseCase := usecase.NewHeartbeatUseCase(driverRepo, cfg.Heartbeat.Timeout, useCaseLogger)
+	fleetUseCase := usecase.NewFleetUseCase(fleetRepo, driverRepo, useCaseLogger, usecase.WithFleetQuotas(driverQuotas))
+	indexUseCase := usecase.NewIndexUseCase(mongodb.NewIndexCatalog(repoLogger, indexSets(driverRepo, verificationRepo, tripRepo, activityRepo, fleetRepo, webhookRepo, riderRepo, earningsRepo, kycRepo, deviceTokenRepo, utilizationRepo, retentionRepo, scheduleRepo, incidentRepo)...), useCaseLogger)
+	statsUseCase := usecase.NewStatsUseCase(driverRepo, activityRepo, cfg.Stats.CacheTTL, useCaseLogger)
+	utilizationUseCase := usecase.NewUtilizationUseCase(utilizationRepo, usecase.UtilizationOptions{
+		Location:   earningsLocation,
+		RollupDays: cfg.Reports.RollupDays,
+	}, useCaseLogger)
+	documentUseCase := usecase.NewDocumentUseCase(driverRepo, useCaseLogger)
+	photoUseCase := usecase.NewPhotoUseCase(driverRepo, fileStore, usecase.PhotoOptions{
+		MaxBytes:       cfg.Photos.MaxBytes,
+		MinDimension:   cfg.Photos.MinDimension,
+		MaxDimension:   cfg.Photos.MaxDimension,
+		FullSize:       cfg.Photos.FullSize,
+		ThumbnailSizes: cfg.Photos.ThumbnailSizes,
+		Quality:        cfg.Photos.Quality,
+	}, useCaseLogger)
+	retentionUseCase := usecase.NewRetentionUseCase(retentionRepo, driverRepo, fileStore, usecase.RetentionOptions{
+		DeletedDriverRetention: cfg.Retention.DeletedDriverWindow,
+		LocationRetention:      cfg.Retention.LocationHistoryWindow,
+	}, useCaseLogger)
+	jobRunner, err := jobrunner.New(jobrunner.Options{
+		Dir:       cfg.Jobs.Dir,
+		Workers:   cfg.Jobs.Workers,
+		QueueSize: cfg.Jobs.QueueSize,
+		Retention: cfg.Jobs.Retention,
+	}, logger.Named("jobs"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jobs configuration: %w", err)
+	}
+	exportUseCase := usecase.NewExportUseCase(driverRepo, jobRunner, useCaseLogger)
+	riderUseCase := usecase.NewRiderUseCase(riderRepo, useCaseLogger)
+	syncUseCase := usecase.NewSyncUseCase(driverRepo, useCaseLogger)
+	autocompleteUseCase := usecase.NewAutocompleteUseCase(driverRepo, cfg.Autocomplete.Budget, cfg.Autocomplete.DefaultLimit, cfg.Autocomplete.MaxLimit, useCaseLogger)
+	streamUseCase := usecase.NewStreamUseCase(driverRepo, useCaseLogger)
+	licenseUseCase := usecase.NewLicenseUseCase(driverRepo, activityRepo, webhookUseCase, useCaseLogger)
+	bulkUpdateUseCase := usecase.NewBulkUpdateUseCase(driverRepo, activityRepo, webhookUseCase, validationRules, cfg.BulkUpdate.MaxDrivers, useCaseLogger)
+	kycUseCase := usecase.NewKYCUseCase(driverRepo, kycRepo, kycProvider, usecase.KYCOptions{
+		WebhookSecret: cfg.KYC.WebhookSecret,
+		Blacklist:     blacklistCheck,
+	}, useCaseLogger)
+	deviceTokenUseCase := usecase.NewDeviceTokenUseCase(deviceTokenRepo, driverRepo, useCaseLogger)
+	scheduleUseCase := usecase.NewScheduleUseCase(scheduleRepo, driverRepo, driverUseCase, useCaseLogger)
+	incidentUseCase := usecase.NewIncidentUseCase(incidentRepo, driverRepo, tripRepo, driverUseCase, useCaseLogger)
+	earningsUseCase := usecase.NewEarningsUseCase(earningsRepo, driverRepo, usecase.EarningsOptions{
+		CommissionRate: cfg.Earnings.CommissionRate,
+		Currency:       cfg.Fares.Currency,
+		Location:       earningsLocation,
+	}, useCaseLogger)
+	verificationUseCase := usecase.NewVerificationUseCase(
+		driverRepo,
+		riderRepo,
+		verificationRepo,
+		newSMSProvider(cfg.SMS, logger.Named("sms")),
+		usecase.VerificationOptions{
+			CodeLength:     cfg.Verification.CodeLength,
+			CodeTTL:        cfg.Verification.CodeTTL,
+			MaxAttempts:    cfg.Verification.MaxAttempts,
+			ResendCooldown: cfg.Verification.ResendCooldown,
+		},
+		useCaseLogger,
+	)
+	strategy, err := matching.NewStrategy(cfg.Matching.Strategy, matching.Options{
+		RatingRadiusKm: cfg.Matching.RatingRadiusKm,
+		ZoneSizeDeg:    cfg.Matching.ZoneSizeDeg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid matching configuration: %w", err)
+	}
+	tripUseCase := usecase.NewTripUseCase(
+		tripRepo,
+		driverRepo,
+		strategy,
+		usecase.MatchingOptions{
+			SearchRadiusKm: cfg.Matching.SearchRadiusKm,
+			OfferTimeout:   cfg.Matching.OfferTimeout,
+			MaxOffers:      cfg.Matching.MaxOffers,
+		},
+		useCaseLogger,
+		usecase.WithRiders(riderRepo),
+		usecase.WithFareEstimation(routeProvider, fareTariff(cfg.Fares)),
+		usecase.WithEarnings(earningsUseCase),
+	)
+	logger.Info("trip matching configured", zap.String("strategy", strategy.Name()))
+
+	// Initialize handlers
+	driverHandler := handler.NewDriverHandler(driverUseCase, handlerLogger)
+	verificationHandler := handler.NewVerificationHandler(verificationUseCase, handlerLogger)
+	documentHandler := handler.NewDocumentHandler(documentUseCase, handlerLogger)
+	photoHandler := handler.NewPhotoHandler(photoUseCase, cfg.Photos.MaxBytes, handlerLogger)
+	tripHandler := handler.NewTripHandler(tripUseCase, handlerLogger)
+	statsHandler := handler.NewStatsHandler(statsUseCase, handlerLogger)
+	reportHandler := handler.NewReportHandler(utilizationUseCase, handlerLogger)
+	fleetHandler := handler.NewFleetHandler(fleetUseCase, handlerLogger)
+	indexHandler := handler.NewIndexHandler(indexUseCase, handlerLogger)
+	heartbeatHandler := handler.NewHeartbeatHandler(heartbeatUseCase, handlerLogger)
+	webhookHandler := handler.NewWebhookHandler(webhookUseCase, handlerLogger)
+	failoverHandler := handler.NewFailoverHandler(retrier, handlerLogger)
+	queryStatsHandler := handler.NewQueryStatsHandler(queryMonitor, handlerLogger)
+	metricsHandler := handler.NewMetricsHandler(businessMetrics, handlerLogger)
+	shadowReadHandler := handler.NewShadowReadHandler(nil, handlerLogger)
+	if shadowReads != nil {
+		shadowReadHandler = handler.NewShadowReadHandler(shadowReads, handlerLogger)
+	}
+	retentionHandler := handler.NewRetentionHandler(retentionUseCase, handlerLogger)
+	exportHandler := handler.NewExportHandler(exportUseCase, handlerLogger)
+	riderHandler := handler.NewRiderHandler(riderUseCase, handlerLogger)
+	syncHandler := handler.NewSyncHandler(syncUseCase, handlerLogger)
+	autocompleteHandler := handler.NewAutocompleteHandler(autocompleteUseCase, handlerLogger)
+	streamHandler := handler.NewStreamHandler(streamUseCase, cfg.Stream.FlushInterval, cfg.Stream.WriteTimeout, handlerLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logLevels, handlerLogger)
+	licenseHandler := handler.NewLicenseHandler(licenseUseCase, handlerLogger)
+	bulkUpdateHandler := handler.NewBulkUpdateHandler(bulkUpdateUseCase, handlerLogger)
+	earningsHandler := handler.NewEarningsHandler(earningsUseCase, handlerLogger)
+	rulesHandler := handler.NewRulesHandler(validationRules, handlerLogger)
+	taxiTypeHandler := handler.NewTaxiTypeHandler(taxiTypes, validationRules, handlerLogger)
+	kycHandler := handler.NewKYCHandler(kycUseCase, handlerLogger)
+	deviceTokenHandler := handler.NewDeviceTokenHandler(deviceTokenUseCase, handlerLogger)
+	scheduleHandler := handler.NewScheduleHandler(scheduleUseCase, handlerLogger)
+	incidentHandler := handler.NewIncidentHandler(incidentUseCase, handlerLogger)
+
+	// Fleet devices that speak MQTT report locations and heartbeats through a broker
+	var telemetryBridge *telemetry.Bridge
+	var telemetryStats handler.TelemetryStatsSource
+	if cfg.MQTT.Enabled {
+		if cfg.MQTT.QoS < 0 || cfg.MQTT.QoS > 2 {
+			return nil, fmt.Errorf("invalid MQTT_QOS %d: must be 0, 1 or 2", cfg.MQTT.QoS)
+		}
+		telemetryBridge = telemetry.NewBridge(telemetry.Options{
+			BrokerURL:    cfg.MQTT.BrokerURL,
+			ClientID:     cfg.MQTT.ClientID,
+			Username:     cfg.MQTT.Username,
+			Password:     cfg.MQTT.Password,
+			TopicPrefix:  cfg.MQTT.TopicPrefix,
+			QoS:          byte(cfg.MQTT.QoS),
+			AuthCacheTTL: cfg.MQTT.AuthCacheTTL,
+		}, driverUseCase, heartbeatUseCase, deviceTokenUseCase, logger.Named("telemetry"))
+		telemetryStats = telemetryBridge
+	}
+	telemetryHandler := handler.NewTelemetryHandler(telemetryStats, handlerLogger)
+
+	// Bound concurrent requests so overload is answered with 503 instead of queueing
+	limiter := middleware.NewConcurrencyLimiter(cfg.Server.MaxInFlight, cfg.Server.OverloadRetryAfter, logger.Named("middleware"))
+	saturationHandler := handler.NewSaturationHandler(limiter, handlerLogger)
+
+	// Readiness is held back until queries no longer hit missing indexes or a cold geo cache
+	warm := warmup.New(cfg.Warmup.RetryInterval, logger.Named("warmup"), warmupChecks(cfg, indexUseCase, driverRepo)...)
+
+	router, adminRouter := setupRouter(driverHandler, verificationHandler, documentHandler, photoHandler, tripHandler, statsHandler, fleetHandler, indexHandler, heartbeatHandler, webhookHandler, failoverHandler, riderHandler, syncHandler, logLevelHandler, saturationHandler, licenseHandler, earningsHandler, rulesHandler, kycHandler, deviceTokenHandler, scheduleHandler, incidentHandler, reportHandler, queryStatsHandler, metricsHandler, shadowReadHandler, retentionHandler, exportHandler, streamHandler, telemetryHandler, autocompleteHandler, bulkUpdateHandler, taxiTypeHandler, warm, limiter, reporter, logger, cfg)
+
+	jobs := []func(ctx context.Context){
+		// Re-offer trips whose offers timed out
+		func(ctx context.Context) { runOfferSweeper(ctx, tripUseCase, cfg.Matching.SweepInterval, logger) },
+		func(ctx context.Context) { runWebhookWorker(ctx, webhookUseCase, cfg.Webhooks.SweepInterval, logger) },
+		func(ctx context.Context) { runLicenseChecker(ctx, licenseUseCase, cfg.Licenses.CheckInterval, logger) },
+		func(ctx context.Context) { runScheduler(ctx, scheduleUseCase, cfg.Schedule.CheckInterval, logger) },
+		func(ctx context.Context) { runUtilizationRollup(ctx, utilizationUseCase, cfg.Reports.RollupInterval, logger) },
+	}
+	// Exports wait in the runner's queue for a worker
+	jobs = append(jobs, jobRunner.Run)
+	jobs = append(jobs, warm.Run)
+	if cfg.Retention.Interval > 0 {
+		jobs = append(jobs, func(ctx context.Context) { runRetention(ctx, retentionUseCase, cfg.Retention.Interval, logger) })
+	}
+	if cfg.KYC.PollInterval > 0 {
+		// Catch decisions whose webhook never arrived
+		jobs = append(jobs, func(ctx context.Context) { runKYCPoller(ctx, kycUseCase, cfg.KYC.PollInterval, logger) })
+	}
+	if analyticsBus != nil {
+		jobs = append(jobs, func(ctx context.Context) { analyticsBus.Run(ctx, cfg.Analytics.FlushInterval) })
+	}
+	if telemetryBridge != nil {
+		jobs = append(jobs, telemetryBridge.Run)
+	}
+	if cfg.GeoCache.Enabled {
+		jobs = append(jobs, func(ctx context.Context) {
+			driverRepo.RunGeoCache(ctx, cfg.GeoCache.PollInterval, cfg.GeoCache.ReconcileInterval)
+		})
+	}
+	if cfg.ChangeStream.Enabled {
+		// Writes by any instance reach the subscribers within moments
+		listener := changestream.NewListener(cfg.ChangeStream.Name, driverRepo, mongodb.NewResumeTokenRepository(db, repoLogger),
+			cfg.ChangeStream.SaveInterval, logger.Named("changestream"))
+		if cfg.GeoCache.Enabled {
+			listener.Subscribe("geo-cache", driverRepo.ApplyChange)
+		}
+		if cfg.ChangeStream.PublishEvents {
+			listener.Subscribe("events", changestream.PublishTo(analyticsBus))
+		}
+		jobs = append(jobs, listener.Run)
+	}
+
+	return &App{
+		cfg:     cfg,
+		logger:  logger,
+		db:       db,
+		shadowDB: shadowDB,
+		router:   router,
+		admin:    adminRouter,
+		limiter:  limiter,
+		reporter: reporter,
+		jobs:     jobs,
+	}, nil
+}
+
+// Logger returns the root logger of the service
+func (a *App) Logger() *zap.Logger {
+	return a.logger
+}
+
+// Handler returns the HTTP handler serving the API
+func (a *App) Handler() http.Handler {
+	return a.router
+}
+
+// AdminHandler returns the HTTP handler serving the operational endpoints,
+// which is Handler unless ADMIN_PORT is set
+func (a *App) AdminHandler() http.Handler {
+	if a.admin == nil {
+		return a.router
+	}
+	return a.admin
+}
+
+// Server returns an HTTP server for the API configured from the server settings
+func (a *App) Server() *http.Server {
+	return &http.Server{
+		Addr:           ":" + a.cfg.Server.Port,
+		Handler:        a.router,
+		ReadTimeout:    a.cfg.Server.ReadTimeout,
+		WriteTimeout:   a.cfg.Server.WriteTimeout,
+		IdleTimeout:    a.cfg.Server.IdleTimeout,
+		MaxHeaderBytes: a.cfg.Server.MaxHeaderBytes,
+		ConnState:      a.limiter.ConnState,
+	}
+}
+
+// AdminServer returns an HTTP server for the operational endpoints on
+// ADMIN_PORT, or nil when they are served by Server. It is not limited by
+// MAX_IN_FLIGHT_REQUESTS so the service stays observable while saturated.
+func (a *App) AdminServer() *http.Server {
+	if a.admin == nil {
+		return nil
+	}
+	return &http.Server{
+		Addr:           ":" + a.cfg.Server.AdminPort,
+		Handler:        a.admin,
+		ReadTimeout:    a.cfg.Server.ReadTimeout,
+		WriteTimeout:   a.cfg.Server.WriteTimeout,
+		IdleTimeout:    a.cfg.Server.IdleTimeout,
+		MaxHeaderBytes: a.cfg.Server.MaxHeaderBytes,
+	}
+}
+
+// Start runs the background jobs: the offer sweeper, webhook deliveries, the
+// licence check, the utilization rollup, the export workers and, when enabled, the retention job,
+// the identity check poll, the analytics event flush, the MQTT telemetry
+// bridge, the refresh of the driver position cache and the driver change
+// stream listener
+func (a *App) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopJobs = cancel
+	for _, job := range a.jobs {
+		a.running.Add(1)
+		go func(job func(ctx context.Context)) {
+			defer a.running.Done()
+			defer errreport.Recover(a.reporter)
+			job(ctx)
+		}(job)
+	}
+}
+
+// Close stops the background jobs, sends the pending error reports and
+// disconnects from MongoDB
+func (a *App) Close(ctx context.Context) error {
+	if a.stopJobs != nil {
+		a.stopJobs()
+		a.running.Wait()
+	}
+	a.reporter.Flush(ctx)
+	if a.shadowDB != nil {
+		if err := a.shadowDB.Client().Disconnect(ctx); err != nil {
+			a.logger.Warn("failed to disconnect from the shadow MongoDB", zap.Error(err))
+		}
+	}
+	return a.db.Client().Disconnect(ctx)
+}
+
+// ShadowReads connects to the shadow backend and wraps the driver repository
+// to mirror a sample of its reads there. It returns nils when shadow reads are
+// disabled; the database is returned for the caller to disconnect.
+func ShadowReads(cfg *config.Config, primary *mongodb.DriverRepository, logger *zap.Logger) (*mongo.Database, *shadow.DriverRepository, error) {
+	if !cfg.ShadowReads.Enabled {
+		return nil, nil, nil
+	}
+	if cfg.ShadowReads.Backend != "mongodb" {
+		return nil, nil, fmt.Errorf("unsupported shadow backend %q: must be mongodb", cfg.ShadowReads.Backend)
+	}
+	if cfg.ShadowReads.URI == "" {
+		return nil, nil, errors.New("SHADOW_MONGODB_URI is required when shadow reads are enabled")
+	}
+
+	shadowDB, err := ConnectMongoDB(config.MongoDBConfig{URI: cfg.ShadowReads.URI, Database: cfg.ShadowReads.Database}, nil, nil, logger.Named("shadow"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to the shadow backend: %w", err)
+	}
+	// The shadow holds the same data, so it is read with the same keys
+	repoOpts, err := FieldEncryptionOptions(cfg.Encryption)
+	if err != nil {
+		shadowDB.Client().Disconnect(context.Background())
+		return nil, nil, fmt.Errorf("failed to initialize field encryption: %w", err)
+	}
+	shadowRepo := mongodb.NewDriverRepository(shadowDB, logger.Named("mongodb").Named("shadow"), repoOpts...)
+
+	logger.Info("shadow reads enabled", zap.String("backend", cfg.ShadowReads.Backend),
+		zap.String("database", cfg.ShadowReads.Database), zap.Float64("sampleRate", cfg.ShadowReads.SampleRate))
+	return shadowDB, shadow.New(primary, shadowRepo, shadow.Options{
+		Backend:     cfg.ShadowReads.Backend,
+		SampleRate:  cfg.ShadowReads.SampleRate,
+		Timeout:     cfg.ShadowReads.Timeout,
+		MaxInFlight: cfg.ShadowReads.MaxInFlight,
+	}, logger.Named("shadow")), nil
+}
+
+// CityOptions builds the driver repository options for storing drivers with their city
+func CityOptions(cfg config.CitiesConfig, logger *zap.Logger) ([]mongodb.DriverRepositoryOption, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	cities, err := geofence.LoadCities(cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cities: %w", err)
+	}
+	logger.Info("cities loaded", zap.Strings("cities", cities.Names()))
+	return []mongodb.DriverRepositoryOption{mongodb.WithCities(cities)}, nil
+}
+
+// FieldEncryptionOptions builds the driver repository options for encrypting personal fields at rest
+func FieldEncryptionOptions(cfg config.EncryptionConfig) ([]mongodb.DriverRepositoryOption, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	var unwrapper fieldcrypt.KeyUnwrapper
+	switch cfg.KMS {
+	case "":
+	case "vault":
+		unwrapper = fieldcrypt.NewVaultTransit(cfg.Vault.Addr, cfg.Vault.Token, cfg.Vault.TransitKey)
+	default:
+		return nil, fmt.Errorf("unsupported FIELD_ENCRYPTION_KMS %q", cfg.KMS)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	keyring, err := fieldcrypt.LoadKeyring(ctx, cfg.Keys, cfg.ActiveKey, unwrapper)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := fieldcrypt.NewAESGCM(keyring)
+	if err != nil {
+		return nil, err
+	}
+
+	hashKey, err := base64.StdEncoding.DecodeString(cfg.HashKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid FIELD_HASH_KEY: %w", err)
+	}
+	hasher, err := fieldcrypt.NewHMACHasher(hashKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return []mongodb.DriverRepositoryOption{mongodb.WithFieldEncryption(cipher, hasher)}, nil
+}
+
+// fareTariff converts the fare configuration into the tariff trips are quoted with
+func fareTariff(cfg config.FareConfig) usecase.FareTariff {
+	multipliers := make(map[domain.TaxiType]float64, len(cfg.Multipliers))
+	for taxiType, multiplier := range cfg.Multipliers {
+		multipliers[domain.TaxiType(taxiType)] = multiplier
+	}
+	return usecase.FareTariff{
+		Base:        cfg.Base,
+		PerKm:       cfg.PerKm,
+		PerMinute:   cfg.PerMinute,
+		Minimum:     cfg.Minimum,
+		Currency:    cfg.Currency,
+		Multipliers: multipliers,
+	}
+}
+
+func newSMSProvider(cfg config.SMSConfig, logger *zap.Logger) sms.Provider {
+	switch cfg.Provider {
+	case "http":
+		return sms.NewHTTPProvider(cfg.URL, cfg.APIKey, cfg.Sender)
+	default:
+		logger.Warn("using log SMS provider, verification codes are not delivered")
+		return sms.NewLogProvider(logger)
+	}
+}
+
+// runOfferSweeper periodically expires unanswered trip offers until ctx is cancelled
+func runOfferSweeper(ctx context.Context, tripUseCase usecase.TripUseCase, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := tripUseCase.ExpireOffers(ctx); err != nil {
+				logger.Warn("offer sweep failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runWebhookWorker periodically sends due webhook deliveries until ctx is cancelled
+func runWebhookWorker(ctx context.Context, webhookUseCase usecase.WebhookUseCase, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := webhookUseCase.DeliverDue(ctx); err != nil {
+				logger.Warn("webhook delivery sweep failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// runLicenseChecker takes drivers with an expired licence off dispatch at start
+// and then on every interval until ctx is cancelled
+func runLicenseChecker(ctx context.Context, licenseUseCase usecase.LicenseUseCase, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		flagged, err := licenseUseCase.FlagExpiredLicenses(ctx)
+		if err != nil {
+			logger.Warn("license check failed", zap.Error(err))
+		} else if flagged > 0 {
+			logger.Info("drivers with expired licenses taken off dispatch", zap.Int("count", flagged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runScheduler puts drivers on and off their planned shifts at start and then
+// on every interval until ctx is cancelled
+func runScheduler(ctx context.Context, scheduleUseCase usecase.ScheduleUseCase, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		started, ended, err := scheduleUseCase.ApplyDueShifts(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Warn("shift scheduler run failed", zap.Error(err))
+		}
+		if started > 0 || ended > 0 {
+			logger.Info("planned shifts applied", zap.Int("started", started), zap.Int("ended", ended))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runRetention erases the personal data of drivers past the retention window
+// at start and then on every interval until ctx is cancelled
+func runRetention(ctx context.Context, retentionUseCase usecase.RetentionUseCase, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		erased, err := retentionUseCase.EraseExpired(ctx)
+		if err != nil {
+			logger.Warn("retention run failed", zap.Error(err))
+		} else if erased > 0 {
+			logger.Info("personal data of deleted drivers erased", zap.Int("count", erased))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runUtilizationRollup recomputes the daily utilization rollups at start and
+// on every interval until ctx is cancelled
+func runUtilizationRollup(ctx context.Context, utilizationUseCase usecase.UtilizationUseCase, interval time.Duration, logger *zap.Logger) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := utilizationUseCase.RollUp(ctx); err != nil {
+			logger.Warn("utilization rollup failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runKYCPoller asks the KYC provider about pending identity checks on every
+// interval until ctx is cancelled
+func runKYCPoller(ctx context.Context, kycUseCase usecase.KYCUseCase, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		decided, err := kycUseCase.PollPending(ctx)
+		if err != nil {
+			logger.Warn("kyc poll failed", zap.Error(err))
+		} else if decided > 0 {
+			logger.Info("identity checks decided", zap.Int("count", decided))
+		}
+	}
+}
+
+// ReadPreferences builds the read preferences of the two request classes
+func ReadPreferences(cfg config.MongoDBConfig) (mongodb.ReadPreferences, error) {
+	reads, err := mongodb.NewReadPreference(cfg.ReadPreference, cfg.ReadTagSets, cfg.ReadMaxStaleness)
+	if err != nil {
+		return mongodb.ReadPreferences{}, err
+	}
+	writes, err := mongodb.NewReadPreference(cfg.WriteReadPreference, cfg.ReadTagSets, cfg.ReadMaxStaleness)
+	if err != nil {
+		return mongodb.ReadPreferences{}, err
+	}
+	return mongodb.ReadPreferences{Reads: reads, Writes: writes}, nil
+}
+
+// ConnectMongoDB connects to the configured database and checks it answers. The
+// retrier, if given, watches the topology so failovers are reported, and the
+// query monitor, if given, times every command.
+func ConnectMongoDB(cfg config.MongoDBConfig, retrier *mongodb.Retrier, queries *mongodb.QueryMonitor, logger *zap.Logger) (*mongo.Database, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	clientOptions := options.Client().ApplyURI(cfg.URI)
+	if retrier != nil {
+		clientOptions.SetServerMonitor(retrier.ServerMonitor())
+	}
+	if queries != nil {
+		clientOptions.SetMonitor(queries.CommandMonitor())
+	}
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	// Ping the database
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	logger.Info("connected to MongoDB", zap.String("database", cfg.Database))
+	return client.Database(cfg.Database), nil
+}
+
+// warmupChecks lists what the service waits for before it reports ready
+func warmupChecks(cfg *config.Config, indexes usecase.IndexUseCase, driverRepo *mongodb.DriverRepository) []warmup.Check {
+	var checks []warmup.Check
+	if cfg.Warmup.RequireIndexes {
+		checks = append(checks, warmup.Check{Name: "indexes", Run: func(ctx context.Context) error {
+			report, err := indexes.Report(ctx)
+			if err != nil {
+				return err
+			}
+			if !report.InSync {
+				return fmt.Errorf("%d indexes missing, %d mismatched; see GET /api/v1/admin/indexes", report.Missing, report.Mismatched)
+			}
+			return nil
+		}})
+	}
+	if cfg.GeoCache.Enabled && cfg.Warmup.GeoCache {
+		checks = append(checks, warmup.Check{Name: "geo-cache", Run: func(ctx context.Context) error {
+			if !driverRepo.GeoCacheLoaded() {
+				return errors.New("geo cache not loaded yet")
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// indexSets collects the indexes every repository expects
+func indexSets(repos ...interface{ Indexes() []mongodb.IndexSet }) []mongodb.IndexSet {
+	var sets []mongodb.IndexSet
+	for _, repo := range repos {
+		sets = append(sets, repo.Indexes()...)
+	}
+	return sets
+}
+
+func setupRouter(
+	driverHandler *handler.DriverHandler,
+	verificationHandler *handler.VerificationHandler,
+	documentHandler *handler.DocumentHandler,
+	photoHandler *handler.PhotoHandler,
+	tripHandler *handler.TripHandler,
+	statsHandler *handler.StatsHandler,
+	fleetHandler *handler.FleetHandler,
+	indexHandler *handler.IndexHandler,
+	heartbeatHandler *handler.HeartbeatHandler,
+	webhookHandler *handler.WebhookHandler,
+	failoverHandler *handler.FailoverHandler,
+	riderHandler *handler.RiderHandler,
+	syncHandler *handler.SyncHandler,
+	logLevelHandler *handler.LogLevelHandler,
+	saturationHandler *handler.SaturationHandler,
+	licenseHandler *handler.LicenseHandler,
+	earningsHandler *handler.EarningsHandler,
+	rulesHandler *handler.RulesHandler,
+	kycHandler *handler.KYCHandler,
+	deviceTokenHandler *handler.DeviceTokenHandler,
+	scheduleHandler *handler.ScheduleHandler,
+	incidentHandler *handler.IncidentHandler,
+	reportHandler *handler.ReportHandler,
+	queryStatsHandler *handler.QueryStatsHandler,
+	metricsHandler *handler.MetricsHandler,
+	shadowReadHandler *handler.ShadowReadHandler,
+	retentionHandler *handler.RetentionHandler,
+	exportHandler *handler.ExportHandler,
+	streamHandler *handler.StreamHandler,
+	telemetryHandler *handler.TelemetryHandler,
+	autocompleteHandler *handler.AutocompleteHandler,
+	bulkUpdateHandler *handler.BulkUpdateHandler,
+	taxiTypeHandler *handler.TaxiTypeHandler,
+	warm *warmup.Warmup,
+	limiter *middleware.ConcurrencyLimiter,
+	reporter errreport.Reporter,
+	logger *zap.Logger,
+	cfg *config.Config,
+) (*gin.Engine, *gin.Engine) {
+	if cfg.Logging.Level != "debug" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+
+	// Middleware
+	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.Compress(cfg.Compression))
+	router.Use(middleware.Decompress(cfg.Compression))
+	router.Use(middleware.ErrorHandler(logger.Named("middleware")))
+	router.Use(middleware.Identity())
+	if cfg.ServiceToken.Secret != "" {
+		// Internal services may call directly with a token exchanged at the
+		// gateway; the gateway's own calls carry a token it signs. Probes and the
+		// KYC provider, which signs its webhooks, call without one.
+		router.Use(middleware.ServiceToken(cfg.ServiceToken.Secret, []string{"/health", "/ready", "/api/v1/kyc/webhook"}, logger.Named("middleware")))
+	}
+	router.Use(middleware.Operation())
+	router.Use(middleware.Consistency())
+	router.Use(middleware.RequestLogger(logger.Named("middleware")))
+	router.Use(limiter.Limit())
+	router.Use(middleware.Recovery(logger.Named("middleware"), reporter))
+
+	// With ADMIN_PORT set the operational endpoints get their own router, without
+	// CORS or the in-flight limit, and the public one only serves the API
+	adminRouter := router
+	var separateAdmin *gin.Engine
+	if cfg.Server.AdminPort != "" {
+		separateAdmin = gin.New()
+		separateAdmin.Use(middleware.ErrorHandler(logger.Named("middleware")))
+		separateAdmin.Use(middleware.Identity())
+		separateAdmin.Use(middleware.Operation())
+		separateAdmin.Use(middleware.Consistency())
+		separateAdmin.Use(middleware.RequestLogger(logger.Named("middleware")))
+		separateAdmin.Use(middleware.Recovery(logger.Named("middleware"), reporter))
+		registerDebugRoutes(separateAdmin)
+		adminRouter = separateAdmin
+	}
+
+	// Health check
+	adminRouter.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	// Readiness check; fails until the warm-up checks have passed
+	adminRouter.GET("/ready", func(c *gin.Context) {
+		if !warm.Ready() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "warming_up", "checks": warm.Status()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": warm.Status()})
+	})
+
+	// API routes
+	v1 := router.Group("/api/v1")
+	{
+		drivers := v1.Group("/drivers")
+		{
+			drivers.POST("", driverHandler.CreateDriver)
+			drivers.POST("/bulk-update", bulkUpdateHandler.BulkUpdateDrivers)
+			drivers.PUT("/:id", driverHandler.UpdateDriver)
+			drivers.DELETE("/:id", driverHandler.DeleteDriver)
+			drivers.DELETE("/:id/personal-data", retentionHandler.ErasePersonalData)
+			drivers.GET("/:id", driverHandler.GetDriver)
+			drivers.GET("", driverHandler.ListDrivers)
+			drivers.GET("/nearby", driverHandler.FindNearbyDrivers)
+			drivers.POST("/nearby/route", driverHandler.FindDriversAlongRoute)
+			drivers.GET("/stats", heartbeatHandler.GetOnlineStats)
+			drivers.GET("/changes", syncHandler.GetDriverChanges)
+			drivers.GET("/autocomplete", autocompleteHandler.AutocompleteDrivers)
+			drivers.GET("/stream", streamHandler.StreamDrivers)
+			drivers.PUT("/:id/availability", driverHandler.SetAvailability)
+			drivers.PUT("/:id/suspension", driverHandler.SetSuspension)
+			drivers.GET("/:id/stats", statsHandler.GetDriverStats)
+			drivers.GET("/:id/earnings", earningsHandler.GetDriverEarnings)
+			drivers.POST("/:id/heartbeat", heartbeatHandler.Heartbeat)
+			drivers.POST("/:id/verify-phone/send", verificationHandler.SendPhoneCode)
+			drivers.POST("/:id/verify-phone", verificationHandler.VerifyPhone)
+			drivers.POST("/:id/documents", documentHandler.UploadDocument)
+			drivers.DELETE("/:id/documents/:type", documentHandler.DeleteDocument)
+			drivers.POST("/:id/kyc", kycHandler.SubmitIdentity)
+			drivers.GET("/:id/kyc", kycHandler.GetIdentityCheck)
+			drivers.POST("/:id/photo", photoHandler.UploadPhoto)
+			drivers.PUT("/:id/fleet", fleetHandler.AssignDriver)
+			drivers.POST("/:id/device-tokens", deviceTokenHandler.IssueDeviceToken)
+			drivers.GET("/:id/device-tokens", deviceTokenHandler.ListDriverDeviceTokens)
+			drivers.POST("/:id/shifts", scheduleHandler.CreateShift)
+			drivers.GET("/:id/shifts", scheduleHandler.ListDriverShifts)
+			drivers.POST("/:id/incidents", incidentHandler.ReportIncident)
+			drivers.GET("/:id/incidents", incidentHandler.ListDriverIncidents)
+
+			// Existence checks with HEAD and Allow headers on OPTIONS
+			handler.RegisterHeadAndOptions(router, drivers)
+		}
+
+		// Called by the KYC provider; the request signature is its authentication
+		v1.POST("/kyc/webhook", kycHandler.ReceiveWebhook)
+
+		// Called by the gateway to authenticate driver devices
+		v1.POST("/device-tokens/verify", deviceTokenHandler.VerifyDeviceToken)
+
+		shifts := v1.Group("/shifts")
+		{
+			shifts.GET("", scheduleHandler.ListShifts)
+			shifts.GET("/:id", scheduleHandler.GetShift)
+			shifts.PUT("/:id", scheduleHandler.UpdateShift)
+			shifts.DELETE("/:id", scheduleHandler.DeleteShift)
+		}
+
+		incidents := v1.Group("/incidents")
+		{
+			incidents.GET("", incidentHandler.ListIncidents)
+			incidents.GET("/:id", incidentHandler.GetIncident)
+			incidents.PUT("/:id/status", incidentHandler.UpdateIncidentStatus)
+		}
+
+		fleets := v1.Group("/fleets")
+		{
+			fleets.POST("", fleetHandler.CreateFleet)
+			fleets.GET("", fleetHandler.ListFleets)
+			fleets.GET("/:id", fleetHandler.GetFleet)
+		}
+
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/retry", webhookHandler.RetryDelivery)
+		}
+
+		// Exports run in the background; poll the job, then download the file
+		exports := v1.Group("/exports")
+		{
+			exports.POST("", exportHandler.StartExport)
+			exports.GET("/:id", exportHandler.GetExport)
+			exports.GET("/:id/download", exportHandler.DownloadExport)
+		}
+
+		// Clients list the taxi types to offer from the registry
+		v1.GET("/taxi-types", taxiTypeHandler.ListTaxiTypes)
+
+		riders := v1.Group("/riders")
+		{
+			riders.POST("", riderHandler.RegisterRider)
+			riders.POST("/sign-in/send", verificationHandler.SendRiderSignInCode)
+			riders.POST("/sign-in", verificationHandler.SignInRider)
+			riders.GET("/:id", riderHandler.GetRider)
+			riders.PUT("/:id", riderHandler.UpdateRider)
+			riders.POST("/:id/favorites", riderHandler.AddFavoriteLocation)
+			riders.DELETE("/:id/favorites/:favoriteId", riderHandler.RemoveFavoriteLocation)
+		}
+
+		trips := v1.Group("/trips")
+		{
+			trips.POST("", tripHandler.RequestTrip)
+			trips.POST("/estimate", tripHandler.EstimateFare)
+			trips.GET("/:id", tripHandler.GetTrip)
+			trips.POST("/:id/accept", tripHandler.AcceptOffer)
+			trips.POST("/:id/decline", tripHandler.DeclineOffer)
+			trips.POST("/:id/complete", tripHandler.CompleteTrip)
+			trips.POST("/:id/cancel", tripHandler.CancelTrip)
+		}
+
+	}
+
+	// Operational endpoints; only the gateway's authenticated /admin API should reach them
+	admin := adminRouter.Group("/api/v1/admin")
+	if separateAdmin != nil && cfg.ServiceToken.Secret != "" {
+		admin.Use(middleware.ServiceToken(cfg.ServiceToken.Secret, nil, logger.Named("middleware")))
+	}
+	{
+		admin.GET("/indexes", indexHandler.GetIndexes)
+		admin.POST("/indexes/sync", indexHandler.SyncIndexes)
+		admin.GET("/indexes/sync", indexHandler.GetIndexSync)
+		admin.GET("/failover", failoverHandler.GetFailoverStats)
+		admin.GET("/telemetry", telemetryHandler.GetTelemetryStats)
+		admin.GET("/query-stats", queryStatsHandler.GetQueryStats)
+		admin.GET("/metrics", metricsHandler.GetBusinessMetrics)
+		admin.GET("/shadow-reads", shadowReadHandler.GetShadowReadStats)
+		admin.GET("/location-anomalies", statsHandler.GetLocationAnomalies)
+		admin.GET("/erasures", retentionHandler.ListErasures)
+		admin.GET("/loglevel", logLevelHandler.GetLogLevels)
+		admin.PUT("/loglevel", logLevelHandler.SetLogLevel)
+		admin.DELETE("/loglevel/:logger", logLevelHandler.ResetLogLevel)
+		admin.GET("/saturation", saturationHandler.GetSaturation)
+		admin.GET("/licenses/expiring", licenseHandler.GetExpiringLicenses)
+		admin.GET("/validation-rules", rulesHandler.GetValidationRules)
+		admin.GET("/tenants/:id/quota", fleetHandler.GetQuota)
+		admin.POST("/earnings/adjustments", earningsHandler.CreateAdjustment)
+		admin.GET("/earnings/adjustments", earningsHandler.ListAdjustments)
+		admin.POST("/payouts", earningsHandler.CreatePayout)
+		admin.GET("/payouts", earningsHandler.ListPayouts)
+		admin.GET("/payouts/:id", earningsHandler.GetPayout)
+		admin.GET("/payouts/:id/export", earningsHandler.ExportPayout)
+		admin.GET("/device-tokens", deviceTokenHandler.ListDeviceTokens)
+		admin.DELETE("/device-tokens/:id", deviceTokenHandler.RevokeDeviceToken)
+		admin.POST("/device-tokens/:id/rotate", deviceTokenHandler.RotateDeviceToken)
+		admin.GET("/reports/utilization", reportHandler.GetUtilization)
+	}
+
+	// Uploaded files written by the local storage provider
+	if cfg.Storage.Provider == storage.ProviderLocal {
+		router.Static("/media", cfg.Storage.Dir)
+	}
+
+	// Swagger
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return router, separateAdmin
+}
+
+// registerDebugRoutes serves the expvar runtime metrics as JSON on /metrics
+// and the pprof profiles under /debug/pprof. Only the admin listener gets
+// them; they must never be reachable from the public port.
+func registerDebugRoutes(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(expvar.Handler()))
+
+	debug := router.Group("/debug/pprof")
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// Named profiles such as heap and goroutine
+		debug.GET("/:profile", gin.WrapF(pprof.Index))
+	}
+}
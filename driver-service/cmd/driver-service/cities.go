@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"go.uber.org/zap"
+)
+
+// runCities implements the "cities" command, which sets the city of drivers
+// stored before cities were configured and, with -shard, then shards the
+// drivers collection by city. Run it against mongos to shard.
+func runCities(args []string) {
+	flags := flag.NewFlagSet("cities", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report how many drivers lack a city, by city, without changing them")
+	shard := flags.Bool("shard", false, "shard the drivers collection by city once every driver has one")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	if !cfg.Cities.Enabled {
+		logger.Fatal("cities are disabled, set CITIES_ENABLED=true")
+	}
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	repoOpts, err := app.CityOptions(cfg.Cities, logger)
+	if err != nil {
+		logger.Fatal("failed to load cities", zap.Error(err))
+	}
+	driverRepo := mongodb.NewDriverRepository(db, logger, repoOpts...)
+
+	stats, err := driverRepo.BackfillCities(context.Background(), *dryRun)
+	if err != nil {
+		logger.Fatal("city backfill failed", zap.Error(err))
+	}
+	out, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+
+	if *shard && !*dryRun {
+		if err := driverRepo.ShardByCity(context.Background()); err != nil {
+			logger.Fatal("sharding by city failed", zap.Error(err))
+		}
+	}
+}
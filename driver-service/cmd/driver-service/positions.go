@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"go.uber.org/zap"
+)
+
+// runPositions implements the "positions" command, which sets the indexed
+// position of drivers stored before nearby searches could use $geoNear. Run it
+// once before setting MONGODB_GEO_NEAR; drivers written since carry theirs.
+func runPositions(args []string) {
+	flags := flag.NewFlagSet("positions", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report how many drivers lack a position without changing them")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	driverRepo := mongodb.NewDriverRepository(db, logger)
+	stats, err := driverRepo.BackfillPositions(context.Background(), *dryRun)
+	if err != nil {
+		logger.Fatal("position backfill failed", zap.Error(err))
+	}
+
+	out, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"go.uber.org/zap"
+)
+
+// runReencrypt implements the "reencrypt" command, which migrates stored drivers to the
+// active field encryption key. Run it after enabling encryption or adding a new key.
+func runReencrypt(args []string) {
+	flags := flag.NewFlagSet("reencrypt", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report how many drivers would be rewritten without changing them")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	if !cfg.Encryption.Enabled() {
+		logger.Fatal("field encryption is not configured, set FIELD_ENCRYPTION_KEYS")
+	}
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	repoOpts, err := app.FieldEncryptionOptions(cfg.Encryption)
+	if err != nil {
+		logger.Fatal("failed to initialize field encryption", zap.Error(err))
+	}
+	driverRepo := mongodb.NewDriverRepository(db, logger, repoOpts...)
+
+	stats, err := driverRepo.Reencrypt(context.Background(), *dryRun)
+	if err != nil {
+		logger.Fatal("re-encryption failed", zap.Error(err))
+	}
+
+	out, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/replay"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// runReplay implements the "replay" command, which streams the recorded driver
+// activity of a time range back through projectors to rebuild the read models
+// derived from it. An interrupted replay resumes from its checkpoint when it is
+// run again with the same projectors and range.
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	from := flags.String("from", "", "start of the range, YYYY-MM-DD in the earnings time zone or RFC 3339 (required)")
+	to := flags.String("to", "", "end of the range, exclusive, in the same formats (default now)")
+	names := flags.String("projectors", "utilization", "comma separated projectors to replay through: utilization")
+	batch := flags.Int("batch", 1000, "events replayed between checkpoints")
+	restart := flags.Bool("restart", false, "ignore the checkpoint of an interrupted replay")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	location, err := time.LoadLocation(cfg.Earnings.Timezone)
+	if err != nil {
+		logger.Fatal("invalid earnings timezone", zap.Error(err))
+	}
+	if *from == "" {
+		logger.Fatal("-from is required")
+	}
+	opts := replay.Options{BatchSize: *batch, Restart: *restart, Progress: os.Stderr, To: time.Now()}
+	if opts.From, err = parseReplayTime(*from, location); err != nil {
+		logger.Fatal("invalid -from", zap.Error(err))
+	}
+	if *to != "" {
+		if opts.To, err = parseReplayTime(*to, location); err != nil {
+			logger.Fatal("invalid -to", zap.Error(err))
+		}
+	}
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	utilization := replay.NewUtilizationProjector(usecase.NewUtilizationUseCase(mongodb.NewUtilizationRepository(db, logger), usecase.UtilizationOptions{
+		Location: location,
+	}, logger), location)
+	available := map[string]replay.Projector{
+		utilization.Name(): utilization,
+	}
+	var projectors []replay.Projector
+	for _, name := range strings.Split(*names, ",") {
+		projector, ok := available[strings.TrimSpace(name)]
+		if !ok {
+			known := make([]string, 0, len(available))
+			for name := range available {
+				known = append(known, name)
+			}
+			sort.Strings(known)
+			logger.Fatal("unknown projector", zap.String("projector", name), zap.Strings("known", known))
+		}
+		projectors = append(projectors, projector)
+	}
+
+	// Stopping the replay keeps its checkpoint, so it can be resumed
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	replayer := replay.New(mongodb.NewActivityRepository(db, logger), mongodb.NewResumeTokenRepository(db, logger), logger, projectors...)
+	result, err := replayer.Run(ctx, opts)
+	if err != nil {
+		logger.Fatal("replay failed", zap.Error(err))
+	}
+
+	out, _ := json.MarshalIndent(struct {
+		*replay.Result
+		UtilizationDays int `json:"utilizationDays"`
+	}{result, utilization.RolledUp()}, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+}
+
+// parseReplayTime parses a day, which starts at midnight in location, or an RFC 3339 time
+func parseReplayTime(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, location); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
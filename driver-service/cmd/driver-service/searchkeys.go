@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"go.uber.org/zap"
+)
+
+// runSearchKeys implements the "search-keys" command, which sets the
+// autocomplete search keys of drivers stored before autocomplete existed. Run
+// it once after upgrading; drivers written since carry their keys already.
+func runSearchKeys(args []string) {
+	flags := flag.NewFlagSet("search-keys", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report how many drivers lack search keys without changing them")
+	flags.Parse(args)
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	// Keys are hashed when personal fields are encrypted
+	repoOpts, err := app.FieldEncryptionOptions(cfg.Encryption)
+	if err != nil {
+		logger.Fatal("failed to initialize field encryption", zap.Error(err))
+	}
+	driverRepo := mongodb.NewDriverRepository(db, logger, repoOpts...)
+
+	stats, err := driverRepo.BackfillSearchKeys(context.Background(), *dryRun)
+	if err != nil {
+		logger.Fatal("search key backfill failed", zap.Error(err))
+	}
+
+	out, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+}
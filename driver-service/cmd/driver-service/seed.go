@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/repository/mongodb"
+	"github.com/bitaksi/driver-service/internal/seed"
+	"go.uber.org/zap"
+)
+
+// seedStats reports the outcome of a seed run
+type seedStats struct {
+	Seed     int64 `json:"seed"`
+	Inserted int   `json:"inserted"`
+	// Skipped drivers collided with a stored phone number, e.g. from an earlier run with the same seed
+	Skipped int `json:"skipped"`
+	// OutsideArea drivers were generated outside the service area and dropped
+	OutsideArea int    `json:"outsideArea"`
+	Elapsed     string `json:"elapsed"`
+}
+
+// runSeed implements the "seed" command, which fills the configured database with
+// synthetic drivers for testing nearby search. The same --seed reproduces the same drivers.
+func runSeed(args []string) {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	count := flags.Int("count", 1000, "number of drivers to generate")
+	center := flags.String("center", "41.01,28.97", "centre of the generated area as lat,lon")
+	radius := flags.Float64("radius", 30, "radius of the generated area in km")
+	seedValue := flags.Int64("seed", 0, "random seed; 0 picks one and prints it")
+	available := flags.Float64("available", 0.6, "share of drivers on shift, between 0 and 1")
+	batchSize := flags.Int("batch", 1000, "drivers inserted per batch")
+	flags.Parse(args)
+
+	centerLocation, err := parseLocation(*center)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --center: %v\n", err)
+		os.Exit(2)
+	}
+	if *count <= 0 || *radius <= 0 || *batchSize <= 0 || *available < 0 || *available > 1 {
+		fmt.Fprintln(os.Stderr, "--count, --radius and --batch must be positive and --available between 0 and 1")
+		os.Exit(2)
+	}
+	if *seedValue == 0 {
+		*seedValue = time.Now().UnixNano()
+	}
+
+	cfg := config.Load()
+	logger, _ := initLogger(cfg.Logging)
+	defer logger.Sync()
+
+	db, err := app.ConnectMongoDB(cfg.MongoDB, nil, nil, logger)
+	if err != nil {
+		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+	defer db.Client().Disconnect(context.Background())
+
+	repoOpts, err := app.FieldEncryptionOptions(cfg.Encryption)
+	if err != nil {
+		logger.Fatal("failed to initialize field encryption", zap.Error(err))
+	}
+	driverRepo := mongodb.NewDriverRepository(db, logger, repoOpts...)
+	if err := driverRepo.EnsureIndexes(context.Background()); err != nil {
+		logger.Fatal("failed to ensure driver indexes", zap.Error(err))
+	}
+
+	// Seeded drivers follow the same service area as drivers created through the API
+	var area *geofence.Area
+	if cfg.ServiceArea.Enabled {
+		if area, err = geofence.Load(cfg.ServiceArea.File, cfg.ServiceArea.Precision); err != nil {
+			logger.Fatal("invalid service area", zap.Error(err))
+		}
+		if _, err := area.Normalize(centerLocation); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid --center: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	generator := seed.NewGenerator(seed.Options{
+		Center:         centerLocation,
+		RadiusKm:       *radius,
+		Seed:           *seedValue,
+		AvailableRatio: *available,
+	})
+	stats := seedStats{Seed: *seedValue}
+	started := time.Now()
+	for done := 0; done < *count; {
+		batch := make([]*domain.Driver, 0, *batchSize)
+		for done < *count && len(batch) < *batchSize {
+			driver := generator.Next()
+			if area != nil {
+				location, err := area.Normalize(driver.Location)
+				if err != nil {
+					stats.OutsideArea++
+					continue
+				}
+				driver.Location = location
+			}
+			batch = append(batch, driver)
+			done++
+		}
+		inserted, err := driverRepo.CreateMany(context.Background(), batch)
+		stats.Inserted += inserted
+		stats.Skipped += len(batch) - inserted
+		if err != nil {
+			logger.Fatal("seeding failed", zap.Error(err), zap.Int("inserted", stats.Inserted))
+		}
+		logger.Info("seeded drivers", zap.Int("done", done), zap.Int("count", *count))
+	}
+	stats.Elapsed = time.Since(started).Round(time.Millisecond).String()
+
+	out, _ := json.MarshalIndent(stats, "", "  ")
+	fmt.Fprintln(os.Stdout, string(out))
+}
+
+// parseLocation parses a "lat,lon" pair
+func parseLocation(value string) (domain.Location, error) {
+	latValue, lonValue, ok := strings.Cut(value, ",")
+	if !ok {
+		return domain.Location{}, fmt.Errorf("expected lat,lon, got %q", value)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latValue), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return domain.Location{}, fmt.Errorf("invalid latitude %q", latValue)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonValue), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return domain.Location{}, fmt.Errorf("invalid longitude %q", lonValue)
+	}
+	return domain.Location{Lat: lat, Lon: lon}, nil
+}
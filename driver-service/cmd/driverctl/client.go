@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+)
+
+// maxErrorBody bounds the error responses read from the API
+const maxErrorBody = 64 << 10
+
+// client calls the driver service API
+type client struct {
+	baseURL string
+	// adminURL is where /admin paths are sent when it is set, for a driver
+	// service serving them on ADMIN_PORT
+	adminURL   string
+	httpClient *http.Client
+	// actor is sent as X-Admin-Actor so admin actions are attributed in the audit log
+	actor string
+}
+
+// apiError is an error response of the API
+type apiError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.Status, e.Message)
+}
+
+// do sends a request with an optional JSON body and decodes a JSON response
+// into out. Responses outside 2xx are returned as *apiError.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	baseURL := c.baseURL
+	if c.adminURL != "" && strings.HasPrefix(path, "/admin/") {
+		baseURL = c.adminURL
+	}
+	target := strings.TrimRight(baseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Admin-Actor", c.actor)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call driver service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return decodeError(resp)
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// decodeError reads the {"error":{"code","message"}} body of a failed request
+func decodeError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	apiErr := &apiError{Status: resp.StatusCode}
+	if json.Unmarshal(data, &body) == nil && body.Error.Code != "" {
+		apiErr.Code = body.Error.Code
+		apiErr.Message = body.Error.Message
+		return apiErr
+	}
+	apiErr.Code = strings.ToUpper(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+	apiErr.Message = strings.TrimSpace(string(data))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+// handlerTransport serves requests with an in-process handler instead of the
+// network, so --direct goes through the same validation as the API. Admin
+// paths go to the admin handler, which may be a separate router.
+type handlerTransport struct {
+	handler http.Handler
+	admin   http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	if t.admin != nil && strings.HasPrefix(req.URL.Path, "/api/v1/admin/") {
+		t.admin.ServeHTTP(recorder, req)
+	} else {
+		t.handler.ServeHTTP(recorder, req)
+	}
+	return recorder.Result(), nil
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+)
+
+// run executes driverctl against the API served by handler
+func run(t *testing.T, handler http.HandlerFunc, args ...string) (string, error) {
+	t.Helper()
+	return runWithInput(t, handler, "", args...)
+}
+
+// runWithInput executes driverctl with stdin against the API served by handler
+func runWithInput(t *testing.T, handler http.HandlerFunc, stdin string, args ...string) (string, error) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"--url", server.URL + "/api/v1"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestGet(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/drivers/d1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"driver not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(domain.Driver{ID: "d1", FirstName: "Ahmet", LastName: "Demir", Plate: "34ABC123"})
+	}
+
+	out, err := run(t, handler, "get", "d1")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if !strings.Contains(out, "Ahmet Demir") || !strings.Contains(out, "34ABC123") {
+		t.Errorf("table output is missing the driver:\n%s", out)
+	}
+
+	out, err = run(t, handler, "get", "d1", "-o", "json")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	var driver domain.Driver
+	if err := json.Unmarshal([]byte(out), &driver); err != nil || driver.ID != "d1" {
+		t.Errorf("json output = %q, err %v", out, err)
+	}
+
+	_, err = run(t, handler, "get", "missing")
+	apiErr, ok := err.(*apiError)
+	if !ok || apiErr.Status != http.StatusNotFound || apiErr.Code != "NOT_FOUND" || apiErr.Message != "driver not found" {
+		t.Errorf("err = %v, want the NOT_FOUND API error", err)
+	}
+}
+
+func TestAdminURL(t *testing.T) {
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/admin/indexes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(domain.IndexReport{Missing: 2})
+	}))
+	defer admin.Close()
+	// The public port no longer serves admin paths
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"not found"}}`))
+	}
+
+	out, err := run(t, notFound, "--admin-url", admin.URL+"/api/v1", "index", "report", "-o", "json")
+	if err != nil {
+		t.Fatalf("index report failed: %v", err)
+	}
+	var report domain.IndexReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil || report.Missing != 2 {
+		t.Errorf("json output = %q, err %v", out, err)
+	}
+
+	if _, err := run(t, notFound, "index", "report"); err == nil {
+		t.Error("index report without --admin-url reached the admin API")
+	}
+}
+
+func TestUpdate_SendsOnlyGivenFields(t *testing.T) {
+	var body map[string]interface{}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(domain.Driver{ID: "d1"})
+	}
+
+	if _, err := run(t, handler, "update", "d1", "--plate", "34KYZ789", "--lat", "0"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if len(body) != 2 || body["plate"] != "34KYZ789" || body["lat"] != 0.0 {
+		t.Errorf("body = %v, want plate and lat only", body)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	stored := []*domain.Driver{
+		{ID: "d1", FirstName: "Ahmet", Plate: "34ABC123", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41, Lon: 29}},
+		{ID: "d2", FirstName: "Mehmet", Plate: "34KYZ789", TaxiType: domain.TaxiTypeSiyah, Location: domain.Location{Lat: 40, Lon: 28}},
+	}
+	var created []usecase.CreateDriverRequest
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			// One driver per page so the export has to follow the pages
+			page := 1
+			if r.URL.Query().Get("page") == "2" {
+				page = 2
+			}
+			json.NewEncoder(w).Encode(usecase.ListDriversResponse{
+				Drivers: stored[page-1 : page],
+				Page:    page,
+				HasNext: page == 1,
+			})
+		case http.MethodPost:
+			var req usecase.CreateDriverRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Plate == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"code":"VALIDATION_ERROR","message":"plate is required"}}`))
+				return
+			}
+			created = append(created, req)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{}`))
+		}
+	}
+
+	for _, format := range []string{formatNDJSON, formatJSON} {
+		t.Run(format, func(t *testing.T) {
+			exported, err := run(t, handler, "export", "--format", format)
+			if err != nil {
+				t.Fatalf("export failed: %v", err)
+			}
+
+			created = nil
+			if _, err := runWithInput(t, handler, exported, "import"); err != nil {
+				t.Fatalf("import failed: %v", err)
+			}
+			if len(created) != 2 || created[1].Plate != "34KYZ789" || created[1].TaxiType != domain.TaxiTypeSiyah || created[1].Lat != 40 {
+				t.Errorf("created = %+v, want both exported drivers", created)
+			}
+		})
+	}
+
+	// Create requests are accepted too, and rejected records do not stop the import
+	created = nil
+	out, err := runWithInput(t, handler, `{"firstName":"Ali","plate":"06XYZ42","taksiType":"turkuaz","lat":39.9,"lon":32.8}
+{"firstName":"Veli"}
+`, "import")
+	if err == nil {
+		t.Error("import with a rejected record should fail")
+	}
+	if len(created) != 1 || created[0].TaxiType != domain.TaxiTypeTurkuaz || created[0].Lat != 39.9 {
+		t.Errorf("created = %+v, want the create request", created)
+	}
+	if !strings.Contains(out, "record 2") || !strings.Contains(out, "plate is required") {
+		t.Errorf("output does not report the rejected record:\n%s", out)
+	}
+}
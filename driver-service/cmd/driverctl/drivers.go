@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) listCommand() *cobra.Command {
+	var page, pageSize int
+	var fleetID string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List drivers, one page at a time",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{"page": {strconv.Itoa(page)}}
+			if pageSize > 0 {
+				query.Set("pageSize", strconv.Itoa(pageSize))
+			}
+			if fleetID != "" {
+				query.Set("fleetId", fleetID)
+			}
+			var response usecase.ListDriversResponse
+			if err := c.client.do(cmd.Context(), http.MethodGet, "/drivers", query, nil, &response); err != nil {
+				return err
+			}
+			if c.output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), response)
+			}
+			if err := printDrivers(cmd.OutOrStdout(), response.Drivers); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "page %d of %d, %d drivers\n", response.Page, response.TotalPages, response.TotalCount)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 1, "page number")
+	cmd.Flags().IntVar(&pageSize, "page-size", 0, "drivers per page; 0 uses the service default")
+	cmd.Flags().StringVar(&fleetID, "fleet", "", "only list drivers of this fleet")
+	return cmd
+}
+
+func (c *cli) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <id>",
+		Short: "Show a driver",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var driver domain.Driver
+			if err := c.client.do(cmd.Context(), http.MethodGet, "/drivers/"+url.PathEscape(args[0]), nil, nil, &driver); err != nil {
+				return err
+			}
+			return c.printDriver(cmd, &driver)
+		},
+	}
+}
+
+func (c *cli) createCommand() *cobra.Command {
+	var req usecase.CreateDriverRequest
+	var taxiType, file string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a driver from flags or a JSON file",
+		Example: "  driverctl create --first-name Ahmet --last-name Demir --plate 34ABC123 --taxi-type sari \\\n" +
+			"    --car-brand Toyota --car-model Corolla --lat 41.0431 --lon 29.0099\n" +
+			"  driverctl create --file driver.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file != "" {
+				if err := readJSON(cmd, file, &req); err != nil {
+					return err
+				}
+			} else {
+				req.TaxiType = domain.TaxiType(taxiType)
+			}
+			var driver domain.Driver
+			if err := c.client.do(cmd.Context(), http.MethodPost, "/drivers", nil, &req, &driver); err != nil {
+				return err
+			}
+			return c.printDriver(cmd, &driver)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&file, "file", "", "JSON request body to send instead of the flags; - reads stdin")
+	flags.StringVar(&req.FirstName, "first-name", "", "first name")
+	flags.StringVar(&req.LastName, "last-name", "", "last name")
+	flags.StringVar(&req.Plate, "plate", "", "licence plate")
+	flags.StringVar(&taxiType, "taxi-type", "", "taxi type, e.g. sari; GET /taxi-types lists them")
+	flags.StringVar(&req.CarBrand, "car-brand", "", "car brand")
+	flags.StringVar(&req.CarModel, "car-model", "", "car model")
+	flags.Float64Var(&req.Lat, "lat", 0, "latitude")
+	flags.Float64Var(&req.Lon, "lon", 0, "longitude")
+	flags.StringVar(&req.Phone, "phone", "", "phone number")
+	flags.StringVar(&req.Email, "email", "", "email address")
+	flags.StringVar(&req.FleetID, "fleet", "", "fleet the driver works for")
+	return cmd
+}
+
+func (c *cli) updateCommand() *cobra.Command {
+	var values usecase.CreateDriverRequest
+	var taxiType, file string
+	cmd := &cobra.Command{
+		Use:     "update <id>",
+		Short:   "Update the fields of a driver given as flags or in a JSON file",
+		Example: "  driverctl update 507f1f77bcf86cd799439011 --plate 34KYZ789 --car-model Civic",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var req usecase.UpdateDriverRequest
+			if file != "" {
+				if err := readJSON(cmd, file, &req); err != nil {
+					return err
+				}
+			} else {
+				// Only the flags given are sent, the other fields are left as they are
+				changed := cmd.Flags().Changed
+				if changed("first-name") {
+					req.FirstName = &values.FirstName
+				}
+				if changed("last-name") {
+					req.LastName = &values.LastName
+				}
+				if changed("plate") {
+					req.Plate = &values.Plate
+				}
+				if changed("taxi-type") {
+					tt := domain.TaxiType(taxiType)
+					req.TaxiType = &tt
+				}
+				if changed("car-brand") {
+					req.CarBrand = &values.CarBrand
+				}
+				if changed("car-model") {
+					req.CarModel = &values.CarModel
+				}
+				if changed("lat") {
+					req.Lat = &values.Lat
+				}
+				if changed("lon") {
+					req.Lon = &values.Lon
+				}
+				if changed("phone") {
+					req.Phone = &values.Phone
+				}
+				if changed("email") {
+					req.Email = &values.Email
+				}
+			}
+			var driver domain.Driver
+			if err := c.client.do(cmd.Context(), http.MethodPut, "/drivers/"+url.PathEscape(args[0]), nil, &req, &driver); err != nil {
+				return err
+			}
+			return c.printDriver(cmd, &driver)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&file, "file", "", "JSON request body to send instead of the flags; - reads stdin")
+	flags.StringVar(&values.FirstName, "first-name", "", "first name")
+	flags.StringVar(&values.LastName, "last-name", "", "last name")
+	flags.StringVar(&values.Plate, "plate", "", "licence plate")
+	flags.StringVar(&taxiType, "taxi-type", "", "taxi type, e.g. sari; GET /taxi-types lists them")
+	flags.StringVar(&values.CarBrand, "car-brand", "", "car brand")
+	flags.StringVar(&values.CarModel, "car-model", "", "car model")
+	flags.Float64Var(&values.Lat, "lat", 0, "latitude")
+	flags.Float64Var(&values.Lon, "lon", 0, "longitude")
+	flags.StringVar(&values.Phone, "phone", "", "phone number")
+	flags.StringVar(&values.Email, "email", "", "email address")
+	return cmd
+}
+
+func (c *cli) suspendCommand() *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "suspend <id>",
+		Short: "Suspend a driver, taking them off shift",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.setSuspension(cmd, args[0], true, reason)
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the driver is suspended")
+	return cmd
+}
+
+func (c *cli) reinstateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reinstate <id>",
+		Short: "Lift a driver's suspension",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.setSuspension(cmd, args[0], false, "")
+		},
+	}
+}
+
+func (c *cli) setSuspension(cmd *cobra.Command, id string, suspended bool, reason string) error {
+	req := usecase.SetSuspensionRequest{Suspended: &suspended, Reason: reason}
+	var driver domain.Driver
+	if err := c.client.do(cmd.Context(), http.MethodPut, "/drivers/"+url.PathEscape(id)+"/suspension", nil, &req, &driver); err != nil {
+		return err
+	}
+	return c.printDriver(cmd, &driver)
+}
+
+func (c *cli) nearbyCommand() *cobra.Command {
+	var lat, lon float64
+	var taxiType, fleetID string
+	var live bool
+	cmd := &cobra.Command{
+		Use:     "nearby",
+		Short:   "Find the drivers near a location",
+		Example: "  driverctl nearby --lat 41.0431 --lon 29.0099 --taxi-type sari",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{
+				"lat": {strconv.FormatFloat(lat, 'f', -1, 64)},
+				"lon": {strconv.FormatFloat(lon, 'f', -1, 64)},
+			}
+			if taxiType != "" {
+				query.Set("taksiType", taxiType)
+			}
+			if fleetID != "" {
+				query.Set("fleetId", fleetID)
+			}
+			if cmd.Flags().Changed("live") {
+				query.Set("live", strconv.FormatBool(live))
+			}
+			var drivers []*usecase.NearbyDriverResponse
+			if err := c.client.do(cmd.Context(), http.MethodGet, "/drivers/nearby", query, nil, &drivers); err != nil {
+				return err
+			}
+			if c.output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), drivers)
+			}
+			return printNearby(cmd.OutOrStdout(), drivers)
+		},
+	}
+	flags := cmd.Flags()
+	flags.Float64Var(&lat, "lat", 0, "latitude")
+	flags.Float64Var(&lon, "lon", 0, "longitude")
+	flags.StringVar(&taxiType, "taxi-type", "", "only drivers of this taxi type")
+	flags.StringVar(&fleetID, "fleet", "", "only drivers of this fleet")
+	flags.BoolVar(&live, "live", false, "only drivers with a recent heartbeat; defaults to the service setting")
+	cmd.MarkFlagRequired("lat")
+	cmd.MarkFlagRequired("lon")
+	return cmd
+}
+
+// printDriver writes a driver in the selected output format
+func (c *cli) printDriver(cmd *cobra.Command, driver *domain.Driver) error {
+	if c.output == outputJSON {
+		return printJSON(cmd.OutOrStdout(), driver)
+	}
+	return printDriver(cmd.OutOrStdout(), driver)
+}
+
+// readJSON decodes the JSON file at path into v; - reads stdin
+func readJSON(cmd *cobra.Command, path string, v interface{}) error {
+	r, err := openInput(cmd, path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// openInput opens the file at path; - or an empty path is stdin
+func openInput(cmd *cobra.Command, path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(cmd.InOrStdin()), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return f, nil
+}
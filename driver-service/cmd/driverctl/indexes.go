@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+func (c *cli) indexCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Inspect and create MongoDB indexes",
+	}
+	cmd.AddCommand(c.indexReportCommand(), c.indexSyncCommand())
+	return cmd
+}
+
+func (c *cli) indexReportCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "report",
+		Short: "Compare the expected indexes with the existing ones",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var report domain.IndexReport
+			if err := c.client.do(cmd.Context(), http.MethodGet, "/admin/indexes", nil, nil, &report); err != nil {
+				return err
+			}
+			if c.output == outputJSON {
+				return printJSON(cmd.OutOrStdout(), report)
+			}
+			return printIndexReport(cmd.OutOrStdout(), &report)
+		},
+	}
+}
+
+func (c *cli) indexSyncCommand() *cobra.Command {
+	var wait bool
+	var interval time.Duration
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Create the missing indexes",
+		Long: "sync starts building the missing indexes and, unless --wait=false, waits until the job\n" +
+			"finishes. Mismatched indexes are skipped and must be dropped by hand.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var job domain.IndexSyncJob
+			if err := c.client.do(cmd.Context(), http.MethodPost, "/admin/indexes/sync", nil, nil, &job); err != nil {
+				return err
+			}
+			for wait && job.Status == domain.IndexSyncRunning {
+				select {
+				case <-cmd.Context().Done():
+					return cmd.Context().Err()
+				case <-time.After(interval):
+				}
+				if err := c.client.do(cmd.Context(), http.MethodGet, "/admin/indexes/sync", nil, nil, &job); err != nil {
+					return err
+				}
+			}
+
+			if c.output == outputJSON {
+				if err := printJSON(cmd.OutOrStdout(), job); err != nil {
+					return err
+				}
+			} else if err := printIndexSync(cmd.OutOrStdout(), &job); err != nil {
+				return err
+			}
+			if len(job.Failed) > 0 {
+				return fmt.Errorf("%d indexes could not be created", len(job.Failed))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&wait, "wait", true, "wait for the sync to finish")
+	cmd.Flags().DurationVar(&interval, "poll-interval", time.Second, "how often to check the progress while waiting")
+	return cmd
+}
@@ -0,0 +1,15 @@
+// Command driverctl manages drivers from the command line. It talks to the
+// driver service API, or with --direct wires the service in-process against
+// the configured MongoDB, so operators can work without Postman and while the
+// service is down.
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+)
+
+// Output formats accepted by --output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// printJSON writes v as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// printDrivers writes drivers as a table, one row per driver
+func printDrivers(w io.Writer, drivers []*domain.Driver) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPLATE\tTYPE\tCAR\tAVAILABLE\tSUSPENDED\tFLEET")
+	for _, d := range drivers {
+		fmt.Fprintf(tw, "%s\t%s %s\t%s\t%s\t%s %s\t%s\t%s\t%s\n",
+			d.ID, d.FirstName, d.LastName, d.Plate, d.TaxiType, d.CarBrand, d.CarModel,
+			yesNo(d.Available), yesNo(d.Suspended), orDash(d.FleetID))
+	}
+	return tw.Flush()
+}
+
+// printDriver writes one driver as a field/value table
+func printDriver(w io.Writer, d *domain.Driver) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rows := [][2]string{
+		{"ID", d.ID},
+		{"Name", d.FirstName + " " + d.LastName},
+		{"Plate", d.Plate},
+		{"Taxi type", string(d.TaxiType)},
+		{"Car", d.CarBrand + " " + d.CarModel},
+		{"Location", formatLocation(d.Location)},
+		{"Phone", orDash(d.Phone)},
+		{"Email", orDash(d.Email)},
+		{"Available", yesNo(d.Available)},
+		{"Suspended", yesNo(d.Suspended)},
+	}
+	if d.Suspended && d.SuspendReason != "" {
+		rows = append(rows, [2]string{"Suspension reason", d.SuspendReason})
+	}
+	rows = append(rows,
+		[2]string{"Rating", fmt.Sprintf("%.2f (%d)", d.Rating, d.RatingCount)},
+		[2]string{"Fleet", orDash(d.FleetID)},
+		[2]string{"Created", d.CreatedAt.Format("2006-01-02 15:04:05")},
+		[2]string{"Updated", d.UpdatedAt.Format("2006-01-02 15:04:05")},
+	)
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s:\t%s\n", row[0], row[1])
+	}
+	return tw.Flush()
+}
+
+// printNearby writes nearby search results, closest first
+func printNearby(w io.Writer, drivers []*usecase.NearbyDriverResponse) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPLATE\tTYPE\tDISTANCE KM\tETA SEC\tSTALE SEC")
+	for _, d := range drivers {
+		fmt.Fprintf(tw, "%s\t%s %s\t%s\t%s\t%.2f\t%d\t%d\n",
+			d.ID, d.FirstName, d.LastName, d.Plate, d.TaxiType, d.DistanceKm, d.DurationSec, d.StaleSeconds)
+	}
+	return tw.Flush()
+}
+
+// printIndexReport writes the state of every index
+func printIndexReport(w io.Writer, report *domain.IndexReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLLECTION\tNAME\tKEYS\tSTATE")
+	for _, index := range report.Indexes {
+		keys := index.Keys
+		if index.ActualKeys != "" {
+			keys += " (actual " + index.ActualKeys + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", index.Collection, index.Name, keys, index.State)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d missing, %d mismatched, %d unexpected\n", report.Missing, report.Mismatched, report.Unexpected)
+	return err
+}
+
+// printIndexSync writes the progress of an index sync job
+func printIndexSync(w io.Writer, job *domain.IndexSyncJob) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Job:\t%s\n", job.ID)
+	fmt.Fprintf(tw, "Status:\t%s\n", job.Status)
+	fmt.Fprintf(tw, "Progress:\t%d/%d\n", job.Completed, job.Total)
+	for _, name := range job.Created {
+		fmt.Fprintf(tw, "Created:\t%s\n", name)
+	}
+	for _, failure := range job.Failed {
+		fmt.Fprintf(tw, "Failed:\t%s.%s: %s\n", failure.Collection, failure.Name, failure.Error)
+	}
+	for _, name := range job.Skipped {
+		fmt.Fprintf(tw, "Skipped:\t%s (mismatched, drop it by hand)\n", name)
+	}
+	return tw.Flush()
+}
+
+func formatLocation(l domain.Location) string {
+	return strconv.FormatFloat(l.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(l.Lon, 'f', -1, 64)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// directBaseURL addresses the in-process handler used by --direct
+const directBaseURL = "http://driverctl/api/v1"
+
+// cli holds the global flags and the client the commands share
+type cli struct {
+	url      string
+	adminURL string
+	direct   bool
+	output   string
+	actor    string
+	timeout  time.Duration
+
+	client *client
+	app    *app.App
+}
+
+func newRootCommand() *cobra.Command {
+	c := &cli{}
+	root := &cobra.Command{
+		Use:   "driverctl",
+		Short: "Manage drivers of the driver service",
+		Long: "driverctl manages drivers through the driver service API. With --direct it wires the\n" +
+			"service in-process against the MongoDB configured in the environment instead.",
+		SilenceUsage:      true,
+		PersistentPreRunE: c.connect,
+		PersistentPostRun: c.close,
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&c.url, "url", envOr("DRIVERCTL_URL", "http://localhost:8081/api/v1"), "driver service API base URL (DRIVERCTL_URL)")
+	flags.StringVar(&c.adminURL, "admin-url", os.Getenv("DRIVERCTL_ADMIN_URL"), "driver service admin API base URL when it listens on ADMIN_PORT, defaults to --url (DRIVERCTL_ADMIN_URL)")
+	flags.BoolVar(&c.direct, "direct", false, "skip the API and use the MongoDB configured in the environment")
+	flags.StringVarP(&c.output, "output", "o", outputTable, "output format: table or json")
+	flags.StringVar(&c.actor, "actor", os.Getenv("USER"), "who is running the command, for the audit log")
+	flags.DurationVar(&c.timeout, "timeout", 30*time.Second, "timeout of each API call")
+
+	root.AddCommand(
+		c.listCommand(),
+		c.getCommand(),
+		c.createCommand(),
+		c.updateCommand(),
+		c.suspendCommand(),
+		c.reinstateCommand(),
+		c.exportCommand(),
+		c.importCommand(),
+		c.indexCommand(),
+		c.nearbyCommand(),
+	)
+	return root
+}
+
+// connect creates the client: over HTTP by default, in-process with --direct
+func (c *cli) connect(cmd *cobra.Command, args []string) error {
+	if c.output != outputTable && c.output != outputJSON {
+		return fmt.Errorf("unknown output format %q, use %s or %s", c.output, outputTable, outputJSON)
+	}
+	if !c.direct {
+		c.client = &client{
+			baseURL:    c.url,
+			adminURL:   c.adminURL,
+			httpClient: &http.Client{Timeout: c.timeout},
+			actor:      c.actor,
+		}
+		return nil
+	}
+
+	cfg := app.LoadConfig()
+	// Only warnings go to stderr so the output stays machine-readable
+	loggerConfig := zap.NewProductionConfig()
+	loggerConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+	logger, err := loggerConfig.Build()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	// Background jobs are not started; the running service owns them
+	c.app, err = app.New(cfg, logger, nil)
+	if err != nil {
+		return fmt.Errorf("failed to initialize driver service: %w", err)
+	}
+	c.client = &client{
+		baseURL:    directBaseURL,
+		httpClient: &http.Client{Transport: handlerTransport{handler: c.app.Handler(), admin: c.app.AdminHandler()}, Timeout: c.timeout},
+		actor:      c.actor,
+	}
+	return nil
+}
+
+// close disconnects from MongoDB after a --direct command
+func (c *cli) close(cmd *cobra.Command, args []string) {
+	if c.app == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.app.Close(ctx)
+}
+
+func envOr(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/spf13/cobra"
+)
+
+// Formats of exported driver files
+const (
+	formatNDJSON = "ndjson"
+	formatJSON   = "json"
+)
+
+// importRecord is a driver to import: either an exported driver or a create
+// request, which names the taxi type taksiType and has a flat lat and lon
+type importRecord struct {
+	domain.Driver
+	TaksiType domain.TaxiType `json:"taksiType"`
+	Lat       *float64        `json:"lat"`
+	Lon       *float64        `json:"lon"`
+}
+
+// createRequest converts the record to the request creating the driver
+func (r *importRecord) createRequest() *usecase.CreateDriverRequest {
+	req := &usecase.CreateDriverRequest{
+		FirstName: r.FirstName,
+		LastName:  r.LastName,
+		Plate:     r.Plate,
+		TaxiType:  r.TaxiType,
+		CarBrand:  r.CarBrand,
+		CarModel:  r.CarModel,
+		Lat:       r.Location.Lat,
+		Lon:       r.Location.Lon,
+		Phone:     r.Phone,
+		Email:     r.Email,
+		FleetID:   r.FleetID,
+		License:   r.License,
+	}
+	if r.TaksiType != "" {
+		req.TaxiType = r.TaksiType
+	}
+	if r.Lat != nil {
+		req.Lat = *r.Lat
+	}
+	if r.Lon != nil {
+		req.Lon = *r.Lon
+	}
+	return req
+}
+
+// importFailure is a record the service rejected
+type importFailure struct {
+	// Record is the position of the record in the file, starting at 1
+	Record int    `json:"record"`
+	Plate  string `json:"plate,omitempty"`
+	Error  string `json:"error"`
+}
+
+// importResult reports the outcome of an import
+type importResult struct {
+	Created int             `json:"created"`
+	Failed  []importFailure `json:"failed"`
+}
+
+func (c *cli) exportCommand() *cobra.Command {
+	var file, format, fleetID string
+	var pageSize int
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export every driver as NDJSON or a JSON array",
+		Example: "  driverctl export --file drivers.ndjson\n" +
+			"  driverctl export --fleet 6570a1f2c3d4e5f6a7b8c9d0 --format json > fleet.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != formatNDJSON && format != formatJSON {
+				return fmt.Errorf("unknown format %q, use %s or %s", format, formatNDJSON, formatJSON)
+			}
+			out := cmd.OutOrStdout()
+			if file != "" && file != "-" {
+				f, err := os.Create(file)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", file, err)
+				}
+				defer f.Close()
+				out = f
+			}
+			w := bufio.NewWriter(out)
+
+			exported := 0
+			encoder := json.NewEncoder(w)
+			if format == formatJSON {
+				w.WriteString("[")
+			}
+			for page := 1; ; page++ {
+				query := url.Values{"page": {strconv.Itoa(page)}, "pageSize": {strconv.Itoa(pageSize)}}
+				if fleetID != "" {
+					query.Set("fleetId", fleetID)
+				}
+				var response usecase.ListDriversResponse
+				if err := c.client.do(cmd.Context(), http.MethodGet, "/drivers", query, nil, &response); err != nil {
+					return err
+				}
+				for _, driver := range response.Drivers {
+					if format == formatJSON && exported > 0 {
+						w.WriteString(",")
+					}
+					if err := encoder.Encode(driver); err != nil {
+						return fmt.Errorf("failed to write driver %s: %w", driver.ID, err)
+					}
+					exported++
+				}
+				if !response.HasNext {
+					break
+				}
+			}
+			if format == formatJSON {
+				w.WriteString("]\n")
+			}
+			if err := w.Flush(); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %d drivers\n", exported)
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&file, "file", "", "file to write; stdout when empty")
+	flags.StringVar(&format, "format", formatNDJSON, "ndjson (one driver per line) or json (an array)")
+	flags.StringVar(&fleetID, "fleet", "", "only export drivers of this fleet")
+	flags.IntVar(&pageSize, "page-size", 100, "drivers fetched per request; capped by MAX_PAGE_SIZE")
+	return cmd
+}
+
+func (c *cli) importCommand() *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create drivers from an NDJSON or JSON array file",
+		Long: "import creates a driver for every record of the file. Records are exported drivers or\n" +
+			"create requests; IDs and server-managed fields such as availability are not imported.\n" +
+			"Rejected records are reported and the rest are still created.",
+		Example: "  driverctl import --file drivers.ndjson",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := openInput(cmd, file)
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+
+			result := importResult{Failed: []importFailure{}}
+			err = decodeRecords(r, func(n int, record *importRecord) {
+				req := record.createRequest()
+				if err := c.client.do(cmd.Context(), http.MethodPost, "/drivers", nil, req, nil); err != nil {
+					result.Failed = append(result.Failed, importFailure{Record: n, Plate: req.Plate, Error: err.Error()})
+					return
+				}
+				result.Created++
+			})
+			if err != nil {
+				return err
+			}
+
+			if c.output == outputJSON {
+				if err := printJSON(cmd.OutOrStdout(), result); err != nil {
+					return err
+				}
+			} else {
+				for _, failure := range result.Failed {
+					fmt.Fprintf(cmd.OutOrStdout(), "record %d (%s): %s\n", failure.Record, orDash(failure.Plate), failure.Error)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "created %d drivers, %d failed\n", result.Created, len(result.Failed))
+			}
+			if len(result.Failed) > 0 {
+				return fmt.Errorf("%d of %d drivers were not imported", len(result.Failed), result.Created+len(result.Failed))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "-", "NDJSON or JSON array file to import; - reads stdin")
+	return cmd
+}
+
+// decodeRecords calls fn with every record of an NDJSON stream or a JSON
+// array, numbered from 1
+func decodeRecords(r io.Reader, fn func(n int, record *importRecord)) error {
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+
+	array := false
+	if first, err := peekNonSpace(reader); err == nil && first == '[' {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("failed to parse import file: %w", err)
+		}
+		array = true
+	}
+
+	for n := 1; ; n++ {
+		if array && !decoder.More() {
+			return nil
+		}
+		var record importRecord
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) && !array {
+				return nil
+			}
+			return fmt.Errorf("failed to parse record %d: %w", n, err)
+		}
+		fn(n, &record)
+	}
+}
+
+// peekNonSpace returns the first byte after leading whitespace without consuming it
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
@@ -23,93 +23,91 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/drivers": {
+        "/admin/device-tokens": {
             "get": {
-                "description": "Get a paginated list of drivers",
+                "description": "List device tokens of every driver, or of one, newest first. Token values are not included.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "List drivers",
+                "summary": "List device tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "example": 1,
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only list tokens of this driver",
+                        "name": "driverId",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "example": 20,
-                        "description": "Page size",
-                        "name": "pageSize",
+                        "type": "boolean",
+                        "description": "Only list tokens that are not revoked",
+                        "name": "active",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated list of drivers\" example({\"drivers\":[{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"}],\"totalCount\":1,\"page\":1,\"pageSize\":20})",
+                        "description": "Tokens",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ListDriversResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"invalid page number\"}})",
+                    "404": {
+                        "description": "Driver not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list drivers\"}})",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new taxi driver",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/device-tokens/{id}": {
+            "delete": {
+                "description": "Revoke a device token; the device has to be issued a new one. Revoking a revoked token succeeds.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Create a new driver",
+                "summary": "Revoke a device token",
                 "parameters": [
                     {
-                        "description": "Driver information",
-                        "name": "driver",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateDriverRequest"
-                        }
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Driver created successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"})",
+                    "200": {
+                        "description": "Revoked token",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
                         }
                     },
-                    "400": {
-                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"plate must be in format: 2-3 digits, 1-3 letters, 1-4 digits (e.g., 34ABC123)\"}})",
+                    "404": {
+                        "description": "Token not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"device token not found\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create driver\"}})",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -117,61 +115,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/drivers/nearby": {
-            "get": {
-                "description": "Find drivers within 6km radius",
+        "/admin/device-tokens/{id}/rotate": {
+            "post": {
+                "description": "Issue a new token for the same device and revoke this one. The new token is only returned in this response.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Find nearby drivers",
+                "summary": "Rotate a device token",
                 "parameters": [
-                    {
-                        "type": "number",
-                        "format": "float64",
-                        "example": 41.0431,
-                        "description": "Latitude",
-                        "name": "lat",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "number",
-                        "format": "float64",
-                        "example": 29.0099,
-                        "description": "Longitude",
-                        "name": "lon",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
-                        "example": "sari",
-                        "description": "Taxi type (sari, turkuaz, siyah)",
-                        "name": "taksiType",
-                        "in": "query"
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of nearby drivers sorted by distance\" example([{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"distance\":0.5}])",
+                    "201": {
+                        "description": "New token",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.NearbyDriverResponse"
-                            }
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
                         }
                     },
-                    "400": {
-                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"latitude is required\"}})",
+                    "404": {
+                        "description": "Token not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"device token not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Token is revoked\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"device token is revoked\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to find nearby drivers\"}})",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -179,49 +163,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/drivers/{id}": {
+        "/admin/earnings/adjustments": {
             "get": {
-                "description": "Get driver details by ID",
+                "description": "Audit trail of manual earning adjustments, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Get a driver by ID",
+                "summary": "List earning adjustments",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Driver ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only list adjustments of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum adjustments to return (max 200)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Driver details\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"})",
+                        "description": "Adjustments",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Earning"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                    "400": {
+                        "description": "Invalid limit\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"limit must be a positive integer\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get driver\"}})",
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list adjustments\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing driver. Location can be updated using top-level lat/lon fields (same format as create): {\"lat\": 41.0, \"lon\": 29.0}",
+            "post": {
+                "description": "Credit or deduct an amount from a driver's earnings, e.g. a toll reimbursement or a refunded fare. Adjustments are never edited; each one keeps its reason and the caller that made it and is included in the next payout.",
                 "consumes": [
                     "application/json"
                 ],
@@ -229,37 +222,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Update a driver",
+                "summary": "Adjust driver earnings",
                 "parameters": [
                     {
-                        "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Driver ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Driver update information. Location uses top-level lat/lon fields.",
-                        "name": "driver",
+                        "description": "Adjustment",
+                        "name": "adjustment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.UpdateDriverRequest"
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateAdjustmentRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Driver updated successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ali\",\"lastName\":\"Kurt\",\"plate\":\"34G99\",\"taxiType\":\"siyah\",\"carBrand\":\"Mercedes\",\"carModel\":\"G Class\",\"location\":{\"lat\":42.0082,\"lon\":28.9784},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:30:00Z\"})",
+                    "201": {
+                        "description": "Adjustment recorded",
                         "schema": {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Earning"
                         }
                     },
                     "400": {
-                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"both lat and lon must be provided together\"}})",
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"reason is required\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -271,170 +256,7796 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create adjustment\"}})",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_bitaksi_driver-service_internal_domain.Driver": {
-            "type": "object",
-            "properties": {
-                "carBrand": {
-                    "type": "string",
-                    "example": "Toyota"
-                },
-                "carModel": {
-                    "type": "string",
-                    "example": "Corolla"
-                },
-                "createdAt": {
-                    "type": "string",
-                    "example": "2025-12-06T01:00:00Z"
-                },
-                "firstName": {
-                    "type": "string",
-                    "example": "Ahmet"
-                },
-                "id": {
-                    "type": "string",
-                    "example": "507f1f77bcf86cd799439011"
-                },
-                "lastName": {
-                    "type": "string",
-                    "example": "Demir"
-                },
-                "location": {
-                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
-                },
-                "plate": {
-                    "type": "string",
-                    "example": "34ABC123"
-                },
-                "taxiType": {
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+        },
+        "/admin/erasures": {
+            "get": {
+                "description": "Audit trail of driver personal data erasures, by the retention job and on request, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List personal data erasures",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only list erasures of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum erasures to return (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Erasures",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Erasure"
+                            }
                         }
-                    ],
-                    "example": "sari"
-                },
-                "updatedAt": {
-                    "type": "string",
-                    "example": "2025-12-06T01:00:00Z"
+                    },
+                    "400": {
+                        "description": "Invalid limit\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"limit must be a positive integer\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list erasures\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "github_com_bitaksi_driver-service_internal_domain.Location": {
-            "type": "object",
-            "properties": {
-                "lat": {
-                    "type": "number",
-                    "example": 41.0431
-                },
-                "lon": {
-                    "type": "number",
-                    "example": 29.0099
+        "/admin/failover": {
+            "get": {
+                "description": "Current replica-set primary, observed primary changes, and how many operations were retried, recovered or answered with 503 since the service started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report MongoDB failover metrics",
+                "responses": {
+                    "200": {
+                        "description": "Failover metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.FailoverStats"
+                        }
+                    }
                 }
             }
         },
-        "github_com_bitaksi_driver-service_internal_domain.TaxiType": {
-            "type": "string",
-            "enum": [
-                "sari",
-                "turkuaz",
-                "siyah"
-            ],
-            "x-enum-varnames": [
-                "TaxiTypeSari",
-                "TaxiTypeTurkuaz",
-                "TaxiTypeSiyah"
-            ]
-        },
-        "github_com_bitaksi_driver-service_internal_usecase.CreateDriverRequest": {
-            "type": "object",
-            "required": [
-                "carBrand",
-                "carModel",
-                "firstName",
-                "lastName",
-                "lat",
-                "lon",
+        "/admin/indexes": {
+            "get": {
+                "description": "Compare the indexes the repositories expect with the ones that exist. Indexes are missing, mismatched (same name, different keys) or unexpected (not declared by any repository).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report MongoDB indexes",
+                "responses": {
+                    "200": {
+                        "description": "Index report",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IndexReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list indexes\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/indexes/sync": {
+            "get": {
+                "description": "Get the progress of the latest index sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get index sync progress",
+                "responses": {
+                    "200": {
+                        "description": "Latest sync job",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IndexSyncJob"
+                        }
+                    },
+                    "404": {
+                        "description": "No sync started\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"no index sync has been started\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start building the missing indexes in the background and return the job; poll GET /admin/indexes/sync for progress. Mismatched indexes are skipped and must be dropped by hand. The caller named in X-Admin-Actor is recorded in the audit log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create missing MongoDB indexes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Who started the sync, for the audit log",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Sync started",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IndexSyncJob"
+                        }
+                    },
+                    "409": {
+                        "description": "Sync already running\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"an index sync is already running\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to start index sync\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/licenses/expiring": {
+            "get": {
+                "description": "List drivers whose licence expires within the given number of days, soonest first. Already expired licences are included; flagged drivers have been taken off dispatch by the licence check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List expiring driver licences",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 30,
+                        "description": "Report window in days, 1-365 (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only report drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Expiring licences",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ExpiringLicensesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid window\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"days must be between 1 and 365\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list expiring licenses\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/location-anomalies": {
+            "get": {
+                "description": "Drivers whose location updates implied an implausible speed, e.g. spoofed GPS, with the number of such updates, how many were rejected and the highest implied speed; most anomalies first. The range defaults to the last 30 days and cannot exceed 366 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Location anomalies per driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of drivers to list (1-1000, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Anomalies per driver",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.LocationAnomalyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or limit\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"limit must be between 1 and 1000\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get location anomalies\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/loglevel": {
+            "get": {
+                "description": "Get the root log level and the levels of named loggers that override it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get log levels",
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{\"mongodb\":\"debug\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_logging.LevelsSnapshot"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change the root log level, or override the level of a named logger and the loggers below it. The change lasts until the service restarts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change a log level",
+                "parameters": [
+                    {
+                        "description": "Logger and level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetLogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{\"mongodb\":\"debug\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_logging.LevelsSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown level\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"unknown log level \\\"verbose\\\"\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/loglevel/{logger}": {
+            "delete": {
+                "description": "Remove the level override of a named logger so it follows the root level again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset a logger's level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "mongodb",
+                        "description": "Logger name",
+                        "name": "logger",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_logging.LevelsSnapshot"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/metrics": {
+            "get": {
+                "description": "Drivers registered, nearby searches with a histogram of the drivers they found and location updates stored since the instance started, as OpenMetrics counters to scrape from every instance, and the stored drivers by status (available, off_shift, suspended) as a gauge counted at most once every METRICS_STATUS_REFRESH_SEC. When drivers cannot be counted the last count is served, or the gauge is left out until the first succeeds.",
+                "produces": [
+                    "application/openmetrics-text"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report business metrics",
+                "responses": {
+                    "200": {
+                        "description": "Business metrics in the OpenMetrics text format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts": {
+            "get": {
+                "description": "Payout batches, newest first, without their lines.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List payouts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum payouts to return (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payouts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Payout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"limit must be a positive integer\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list payouts\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Batch every unpaid trip earning and adjustment earned in the range into a payout with one line per driver. Entries are paid out once; running the same range again only picks up entries recorded since. The range ends at the start of the current day by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a payout batch",
+                "parameters": [
+                    {
+                        "description": "Payout range",
+                        "name": "payout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreatePayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payout created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"to cannot be in the future\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Nothing to pay\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"no unpaid earnings in range\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create payout\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts/{id}": {
+            "get": {
+                "description": "Get a payout batch with what it owes each driver.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a payout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573b2f3c4d5e6f7a8b9c0d1\"",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Payout"
+                        }
+                    },
+                    "404": {
+                        "description": "Payout not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"payout not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts/{id}/export": {
+            "get": {
+                "description": "Download a payout batch for finance as CSV with one row per driver: payoutId, driverId, trips, gross, commission, adjustments, net and currency.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export a payout as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573b2f3c4d5e6f7a8b9c0d1\"",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payout CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Payout not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"payout not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/query-stats": {
+            "get": {
+                "description": "Duration histogram of every MongoDB command per collection since the service started, the most time-consuming first. Commands slower than MONGODB_SLOW_QUERY_MS are also logged with their redacted filter and the route that sent them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report MongoDB query latencies",
+                "responses": {
+                    "200": {
+                        "description": "Query latencies",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.QueryStats"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/utilization": {
+            "get": {
+                "description": "Online hours (time on shift) against busy hours (time between accepting and completing trips) per calendar day and taxi type, with busy over online as utilization. Days are calendar days in the earnings time zone; time is attributed to the taxi type the driver has now. The range defaults to the last 7 days and cannot exceed 92 days. Complete days come from daily rollups; today is computed for the request and marked partial. Send format=csv or \"Accept: text/csv\" for a CSV export.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Driver utilization report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "First day (YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-07\"",
+                        "description": "Last day (YYYY-MM-DD, inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Utilization report",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.UtilizationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"from must be before to\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get utilization\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation": {
+            "get": {
+                "description": "Requests in flight against MAX_IN_FLIGHT_REQUESTS, the peak since start, how many requests were rejected with 503 while saturated, and the open connections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report server saturation",
+                "responses": {
+                    "200": {
+                        "description": "Saturation metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_middleware.SaturationStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/shadow-reads": {
+            "get": {
+                "description": "With SHADOW_READS_ENABLED, a SHADOW_READ_SAMPLE_RATE share of driver reads (get by ID, phone or email, list and nearby search) is run again against the shadow backend in the background and compared with the primary's answer. Counts per repository method since the service started how many matched, diverged or timed out, and how many sampled reads were dropped because SHADOW_READ_MAX_IN_FLIGHT were already running. Divergences are logged with the names of the differing fields.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report shadow read comparisons",
+                "responses": {
+                    "200": {
+                        "description": "Shadow read comparisons",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ShadowReadStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/telemetry": {
+            "get": {
+                "description": "Whether the MQTT bridge is connected to the broker, the topics it subscribes to, and the location and heartbeat messages it received, applied, rejected (by reason) or left for redelivery since the service started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report MQTT telemetry metrics",
+                "responses": {
+                    "200": {
+                        "description": "Telemetry metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TelemetryStats"
+                        }
+                    },
+                    "404": {
+                        "description": "MQTT bridge disabled\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"MQTT bridge is disabled\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{id}/quota": {
+            "get": {
+                "description": "How many drivers a tenant (fleet) has against the quota it pays for. Creating a driver in, or moving one into, a fleet that has used up its quota is refused with QUOTA_EXCEEDED. Quotas are soft: concurrent creations may overshoot by the requests in flight.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Tenant driver quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6570a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Fleet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quota usage",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverQuota"
+                        }
+                    },
+                    "404": {
+                        "description": "Fleet not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"fleet not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get quota\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/validation-rules": {
+            "get": {
+                "description": "The effective rules of the service country, every configured country and every tenant, after inheritance is applied. With fleetId, only the rules applied to drivers of that fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Inspect driver validation rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only return the rules applied to this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Effective rules",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_rules.Effective"
+                        }
+                    }
+                }
+            }
+        },
+        "/device-tokens/verify": {
+            "post": {
+                "description": "Called by the gateway to authenticate a device: returns the token's driver and scopes when it is valid and records its use.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "device-tokens"
+                ],
+                "summary": "Verify a device token",
+                "parameters": [
+                    {
+                        "description": "Presented token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.VerifyDeviceTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Valid token",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or revoked token\" example({\"error\":{\"code\":\"UNAUTHORIZED\",\"message\":\"invalid or revoked device token\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers": {
+            "get": {
+                "description": "Get a paginated list of drivers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "List drivers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 20,
+                        "description": "Page size; defaults to DEFAULT_PAGE_SIZE and is capped at MAX_PAGE_SIZE",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only list drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "istanbul",
+                        "description": "Only list drivers of this city, or other for drivers outside every configured city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pet-friendly,wheelchair-accessible",
+                        "description": "Only list drivers carrying all of these comma-separated tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "language:en",
+                        "description": "Only list drivers with all of these comma-separated key:value attributes; a key alone matches any value",
+                        "name": "attributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of drivers\" example({\"drivers\":[{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"}],\"totalCount\":1,\"page\":1,\"pageSize\":20,\"totalPages\":1,\"hasNext\":false,\"hasPrev\":false})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ListDriversResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"invalid page number\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new taxi driver",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Create a new driver",
+                "parameters": [
+                    {
+                        "description": "Driver information",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateDriverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Driver created successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, or OUTSIDE_SERVICE_AREA for a location outside the service area\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"plate must be in format: 2-3 digits, 1-3 letters, 1-4 digits (e.g., 34ABC123)\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Fleet has reached its driver quota\" example({\"error\":{\"code\":\"QUOTA_EXCEEDED\",\"message\":\"fleet has reached its driver quota of 50 drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admin creating a driver in another fleet, or BLACKLISTED for a driver on the blacklist\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"fleet admins can only create drivers in their own fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plate, phone or email taken by another driver, or REQUEST_IN_PROGRESS while another request is changing the same plate, phone or email\" example({\"error\":{\"code\":\"REQUEST_IN_PROGRESS\",\"message\":\"another request is changing the same driver, plate, phone or email; retry shortly\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header, or BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and BLACKLIST_FAIL_MODE is closed\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/autocomplete": {
+            "get": {
+                "description": "Suggest drivers as a dispatcher types. Every word of q must start a word of the driver's name, the plate or a letter or digit group of the plate; case and Turkish diacritics are ignored, so \"sukru 34\" finds Şükrü with plate 34ABC123. Highlights mark the matched characters. A search that runs past AUTOCOMPLETE_BUDGET_MS returns no suggestions with timedOut set.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Autocomplete drivers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ahm",
+                        "description": "Start of the name or plate, up to 5 words",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 8,
+                        "description": "Maximum suggestions; defaults to AUTOCOMPLETE_DEFAULT_LIMIT and is capped at AUTOCOMPLETE_MAX_LIMIT",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only suggest drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggestions\" example({\"suggestions\":[{\"id\":\"507f1f77bcf86cd799439011\",\"name\":\"Ahmet Demir\",\"plate\":\"34ABC123\",\"highlights\":[{\"field\":\"name\",\"start\":0,\"length\":3}]}],\"timedOut\":false})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.AutocompleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"q must contain 1 to 5 words of letters or digits\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to search drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/bulk-update": {
+            "post": {
+                "description": "Apply a patch to every driver matching a filter, e.g. suspend a fleet for inspection or move its cars to another taxi type, in a single database update. Fleet admins only reach their own fleet. An update may change at most BULK_UPDATE_MAX_DRIVERS drivers; narrow the filter or split the ids when it matches more. Set dryRun to count the drivers without changing them. Suspended drivers are taken off shift, and subscribers receive a driver.suspended or driver.updated event per changed driver. Every update, dry runs included, is recorded with the caller for the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Update many drivers at once",
+                "parameters": [
+                    {
+                        "description": "Filter, patch and dry run",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.BulkUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matched and modified drivers",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.BulkUpdate"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"invalid bulk update: patch must set carBrand, carModel, taksiType or suspended\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Filter matches too many drivers\" example({\"error\":{\"code\":\"BULK_LIMIT_EXCEEDED\",\"message\":\"bulk update matches too many drivers: the filter matches 2400 drivers, at most 1000 can be updated at once\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/changes": {
+            "get": {
+                "description": "Get the drivers created, updated and deleted since a marker so apps can update an offline cache. Pass nextToken as since to continue while hasMore is true, and keep the last nextToken for the next sync. Omit since for a full sync.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "2025-12-06T01:00:00Z",
+                        "description": "RFC3339 timestamp or nextToken from an earlier response",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "example": 100,
+                        "description": "Maximum changes per response; defaults to 100 and is capped at 500",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only sync drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed drivers\" example({\"created\":[],\"updated\":[{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-01T09:00:00Z\",\"updatedAt\":\"2025-12-06T01:01:30Z\"}],\"deleted\":[\"507f1f77bcf86cd799439012\"],\"nextToken\":\"eyJzIjoiMjAyNS0xMi0wNlQwMTowMDowMFoiLCJ1IjoiMjAyNS0xMi0wNlQwMTowMTozMFoiLCJpIjoiNTA3ZjFmNzdiY2Y4NmNkNzk5NDM5MDExIn0\",\"hasMore\":false})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.DriverChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid marker\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"since must be an RFC3339 timestamp or a nextToken from an earlier sync\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list driver changes\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/nearby": {
+            "get": {
+                "description": "Find drivers within 6km radius. With live=true, drivers whose latest heartbeat is older than HEARTBEAT_TIMEOUT_SEC are excluded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Find nearby drivers",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "example": 41.0431,
+                        "description": "Latitude; required unless geohash is given",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "example": 29.0099,
+                        "description": "Longitude; required unless geohash is given",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "sxk9kp",
+                        "description": "Search around the center of this geohash cell instead of lat and lon",
+                        "name": "geohash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "sari",
+                        "description": "Taxi type, one of GET /taxi-types",
+                        "name": "taksiType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only return drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "istanbul",
+                        "description": "Only return drivers of this city; without it the search reads the cities the search circle reaches",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "description": "Only return drivers with a recent heartbeat; defaults to the HEARTBEAT_FILTER_NEARBY setting",
+                        "name": "live",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pet-friendly",
+                        "description": "Only return drivers carrying all of these comma-separated tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "language:en",
+                        "description": "Only return drivers with all of these comma-separated key:value attributes; a key alone matches any value",
+                        "name": "attributes",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "km",
+                            "m",
+                            "mi"
+                        ],
+                        "type": "string",
+                        "example": "m",
+                        "description": "Also give each distance as distance in km, m or mi, rounded to about a meter",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "example": 10,
+                        "description": "Most drivers returned, nearest first; defaults to NEARBY_DEFAULT_LIMIT and is capped at NEARBY_MAX_LIMIT",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "example": 10,
+                        "description": "Nearest drivers skipped, for the next page",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of nearby drivers sorted by distance\" example([{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"distanceKm\":0.5,\"durationSec\":95,\"lastLocationUpdate\":\"2025-12-06T01:00:00Z\",\"staleSeconds\":42}])",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.NearbyDriverResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Distance-Unit": {
+                                "type": "string",
+                                "description": "Unit of distance: km, m or mi"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"latitude is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to find nearby drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/nearby/route": {
+            "post": {
+                "description": "Find drivers within widthKm (default 0.5, at most 2) of the route through 2 to 25 ordered waypoints, for riders on a highway or any road where a radius around one point misses the drivers that can reach them. Routes can be up to 100 km long. Each driver is returned once, sorted by distance to the route; routeKm tells where along the route the driver is. With live=true, drivers whose latest heartbeat is older than HEARTBEAT_TIMEOUT_SEC are excluded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Find drivers along a route",
+                "parameters": [
+                    {
+                        "enum": [
+                            "km",
+                            "m",
+                            "mi"
+                        ],
+                        "type": "string",
+                        "example": "m",
+                        "description": "Also give distanceKm and routeKm as distance and routeDistance in km, m or mi, rounded to about a meter; widthKm stays in km",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Route and corridor",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.RouteSearchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drivers along the route sorted by distance to it\" example([{\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"location\":{\"lat\":41.0612,\"lon\":29.0171},\"distanceKm\":0.2,\"routeKm\":2.1}])",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.RouteDriverResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Distance-Unit": {
+                                "type": "string",
+                                "description": "Unit of distance and routeDistance: km, m or mi"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"route must have between 2 and 25 waypoints\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to find drivers along route\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/stats": {
+            "get": {
+                "description": "Count drivers that are online (heartbeat within HEARTBEAT_TIMEOUT_SEC) and offline",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get online driver counts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only count drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Online and offline counts",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.OnlineStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get online stats\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/stream": {
+            "get": {
+                "description": "Stream every driver as NDJSON, one driver per line in ID order, straight from a database cursor instead of page by page; for data pipelines pulling the whole table. Clients sending \"Accept-Encoding: zstd\" get a zstd-compressed stream. The X-Stream-Status trailer is complete once every driver was sent, or failed when the stream ended early; pass the X-Stream-Last-Id trailer, or the ID of the last line received, as afterId to resume. Fleet admins only stream their own fleet's drivers.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Stream drivers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only stream drivers after this driver ID, to resume a stream",
+                        "name": "afterId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only stream drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "istanbul",
+                        "description": "Only stream drivers of this city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pet-friendly",
+                        "description": "Only stream drivers with all of these tags, comma-separated",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "language:en",
+                        "description": "Only stream drivers with these attributes, comma-separated key:value pairs; a key alone matches any value",
+                        "name": "attributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drivers, one per line",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        },
+                        "headers": {
+                            "X-Stream-Last-Id": {
+                                "type": "string",
+                                "description": "Trailer: ID of the last driver sent"
+                            },
+                            "X-Stream-Status": {
+                                "type": "string",
+                                "description": "Trailer: complete, or failed when the stream ended early"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid afterId or filter\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"afterId must be a driver ID\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to stream drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}": {
+            "get": {
+                "description": "Get driver details by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get a driver by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver details\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ahmet\",\"lastName\":\"Demir\",\"plate\":\"34ABC123\",\"taxiType\":\"sari\",\"carBrand\":\"Toyota\",\"carModel\":\"Corolla\",\"location\":{\"lat\":41.0431,\"lon\":29.0099},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:00:00Z\"})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing driver. Location can be updated using top-level lat/lon fields (same format as create): {\"lat\": 41.0, \"lon\": 29.0}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Update a driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver update information. Location uses top-level lat/lon fields.",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.UpdateDriverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver updated successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ali\",\"lastName\":\"Kurt\",\"plate\":\"34G1234\",\"taxiType\":\"siyah\",\"carBrand\":\"Mercedes\",\"carModel\":\"G Class\",\"location\":{\"lat\":42.0082,\"lon\":28.9784},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:30:00Z\"})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, OUTSIDE_SERVICE_AREA for a location outside the service area, or IMPLAUSIBLE_LOCATION for a rejected location jump\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"both lat and lon must be provided together\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"driver does not belong to your fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plate, phone or email taken by another driver, or REQUEST_IN_PROGRESS while another request is changing the same driver, plate, phone or email\" example({\"error\":{\"code\":\"REQUEST_IN_PROGRESS\",\"message\":\"another request is changing the same driver, plate, phone or email; retry shortly\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a driver; used to roll back a failed onboarding. Personal data is erased and a tombstone is kept so delta syncs report the deletion.",
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Delete driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Driver deleted"
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"driver does not belong to your fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to delete driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/availability": {
+            "put": {
+                "description": "Put a driver on or off shift. Going on shift requires a verified phone number and an unexpired licence.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Set driver availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Availability",
+                        "name": "availability",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.SetAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver availability updated",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"available is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"driver does not belong to your fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Contact not verified, licence expired or driver suspended\" example({\"error\":{\"code\":\"CONTACT_NOT_VERIFIED\",\"message\":\"phone must be verified before going on shift\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/device-tokens": {
+            "get": {
+                "description": "List the driver's device tokens, newest first. Token values are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "device-tokens"
+                ],
+                "summary": "List a driver's device tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list tokens that are not revoked",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Issue a long-lived token for one of the driver's devices. It only allows location updates and heartbeats of this driver. Issuing a token for a device revokes the device's previous token. The token is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "device-tokens"
+                ],
+                "summary": "Issue a device token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Device",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.IssueDeviceTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token issued",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeviceToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"deviceId is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many active tokens\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"a driver can have at most 10 active device tokens\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to issue device token\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/documents": {
+            "post": {
+                "description": "Attach a document reference (license, registration or insurance) to a driver, replacing any previous document of the same type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Upload driver document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Document reference",
+                        "name": "document",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.UploadDocumentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document stored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"invalid document type: passport\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/documents/{type}": {
+            "delete": {
+                "description": "Remove the driver's document of the given type",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "documents"
+                ],
+                "summary": "Delete driver document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "license",
+                            "registration",
+                            "insurance"
+                        ],
+                        "type": "string",
+                        "description": "Document type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Document removed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid document type",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver or document not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"document not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/earnings": {
+            "get": {
+                "description": "Sum a driver's trip earnings, commission and adjustments per day or per week (starting Monday) in the configured time zone. The range defaults to the last 30 days (daily) or 12 weeks (weekly) and cannot exceed 366 days; days without earnings are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver earnings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "Bucket size",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver earnings",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.EarningsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"period must be daily or weekly\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get driver earnings\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/fleet": {
+            "put": {
+                "description": "Move a driver into a fleet. An empty fleetId removes the driver from its fleet. A fleet that has used up its driver quota takes no more drivers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Assign a driver to a fleet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fleet assignment",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.AssignFleetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated driver",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown fleet\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"fleet not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "402": {
+                        "description": "Fleet has reached its driver quota\" example({\"error\":{\"code\":\"QUOTA_EXCEEDED\",\"message\":\"fleet has reached its driver quota of 50 drivers\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/heartbeat": {
+            "post": {
+                "description": "Mark the driver as seen now. Driver apps call this periodically; it takes no body and only updates lastSeenAt.",
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Record a driver heartbeat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Heartbeat recorded"
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to record heartbeat\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/incidents": {
+            "get": {
+                "description": "Incidents reported against the driver, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Driver incidents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "open",
+                        "description": "open, investigating or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high",
+                        "description": "low, medium, high or critical",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "safety",
+                        "description": "safety, conduct, vehicle, fare, route or other",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6571f1f77bcf86cd79943901",
+                        "description": "Only incidents of this trip",
+                        "name": "tripId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 20,
+                        "description": "Page size, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incidents",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ListIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"status must be open, investigating or resolved\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Report an incident against the driver, optionally linked to a trip the driver drove; riders must link one of their own trips. High and critical incidents reported by operators and fleet admins suspend the driver until they are dismissed; critical ones also revoke the driver's identity verification, which then needs a new identity check. Rider reports are stored open without effects until an investigator confirms their severity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Report an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ReportIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Incident reported",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"severity must be low, medium, high or critical\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet, or the trip to another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver or trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to report incident\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/kyc": {
+            "get": {
+                "description": "Get the driver's latest identity check",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Get identity check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest check",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.KYCCheck"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver or check not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"no identity check found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Send the driver's name and documents to the KYC provider. The check starts pending; identityVerified is set on the driver once the provider approves it. When a blacklist is configured the driver is looked up in it first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Submit identity check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Check submitted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.KYCCheck"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet, or BLACKLISTED for a driver on the blacklist\" example({\"error\":{\"code\":\"BLACKLISTED\",\"message\":\"driver is on the blacklist\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Identity verified, check pending or license document missing\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"an identity check is already pending\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to submit identity check\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Blacklist cannot be reached and BLACKLIST_FAIL_MODE is closed\" example({\"error\":{\"code\":\"BLACKLIST_UNAVAILABLE\",\"message\":\"blacklist check is unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/personal-data": {
+            "delete": {
+                "description": "Erase a driver's personal data at once, e.g. on a KVKK/GDPR request, instead of waiting for the retention window. A live driver is deleted first. Names, contact details, plate, location history, documents, licence, photo files, device tokens and phone verifications are erased; trips, shifts, earnings and identity check outcomes are kept for statistics and the books. The erasure is recorded with the caller named in X-Admin-Actor and the reason.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Erase a driver's personal data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"KVKK request #4711\"",
+                        "description": "Why the data is erased, e.g. a ticket number",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who requested the erasure, for the audit record",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Erasure record",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Erasure"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"driver does not belong to your fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to erase personal data\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/photo": {
+            "post": {
+                "description": "Set the driver's profile picture from a JPEG or PNG upload. The picture is scaled down to a full-size copy and cropped into square thumbnails, all re-encoded as JPEG; the driver's previous photo is removed. The returned URLs change with every upload and can be cached indefinitely.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Upload driver photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG or PNG picture",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Photo stored",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing file, unsupported format or dimensions out of range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"photo must be a JPEG or PNG image\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Photo too large\" example({\"error\":{\"code\":\"PAYLOAD_TOO_LARGE\",\"message\":\"photo exceeds the maximum upload size\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to upload photo\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/shifts": {
+            "get": {
+                "description": "Shifts of the driver overlapping the range, by start time. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or \"Accept: text/calendar\" for an iCalendar export.",
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Driver shift calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-08\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-15\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or ics",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ShiftCalendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"calendar range cannot exceed 92 days\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Plan a shift for the driver. The driver is put on shift when it starts and off shift when it ends. Shifts of a driver cannot overlap and last at most 24 hours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Schedule a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift times",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ScheduleShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Shift scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ScheduledShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"endsAt must be after startsAt\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlaps another shift\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"shift overlaps another shift of the driver (2025-12-08T06:00:00Z to 2025-12-08T14:00:00Z)\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create shift\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/stats": {
+            "get": {
+                "description": "Aggregate completed trips, distance driven, online hours and average rating over a date range. The range defaults to the last 30 days and cannot exceed 366 days. Results are cached briefly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver statistics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"from must be before to\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get driver stats\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/suspension": {
+            "put": {
+                "description": "Suspended drivers are taken off shift, cannot go on shift again and are left out of nearby searches. Webhook subscribers receive a driver.suspended event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Suspend or reinstate a driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Suspension",
+                        "name": "suspension",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.SetSuspensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver suspension updated",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"suspended is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"driver does not belong to your fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Database failing over, retry after the Retry-After header\" example({\"error\":{\"code\":\"SERVICE_UNAVAILABLE\",\"message\":\"database is temporarily unavailable, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/verify-phone": {
+            "post": {
+                "description": "Confirm the one-time code sent to the driver's phone and mark the phone as verified",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Verify phone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verification code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.VerifyPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Phone verified",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired code\" example({\"error\":{\"code\":\"INVALID_CODE\",\"message\":\"invalid verification code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts\" example({\"error\":{\"code\":\"TOO_MANY_ATTEMPTS\",\"message\":\"too many verification attempts, request a new code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to verify phone\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/verify-phone/send": {
+            "post": {
+                "description": "Send a one-time verification code to the driver's phone via SMS",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Send phone verification code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Code sent\" example({\"status\":\"sent\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone missing or already verified\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"phone is already verified\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Code sent recently\" example({\"error\":{\"code\":\"RATE_LIMIT_EXCEEDED\",\"message\":\"verification code was sent recently, try again later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to send verification code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports": {
+            "post": {
+                "description": "Export every driver, or one fleet's, in the background; fleet admins always export their own fleet. The export is a snapshot of the drivers that existed when it started; drivers updated since are flagged with changedDuringExport. Poll GET /exports/{id} until status is completed, then download the file from GET /exports/{id}/download. Exports and their files are kept for JOB_RETENTION_HOURS after they finish, on the instance that ran them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Start a driver export",
+                "parameters": [
+                    {
+                        "description": "Format and fleet; an empty body exports every driver as NDJSON",
+                        "name": "export",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ExportRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who started the export, for the audit log",
+                        "name": "X-Admin-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Export queued",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_jobrunner.Job"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the export status"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"format must be ndjson or json\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many jobs waiting\" example({\"error\":{\"code\":\"TOO_MANY_JOBS\",\"message\":\"too many jobs are waiting, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to start export\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}": {
+            "get": {
+                "description": "Status and progress of an export: processed against total drivers, and once completed the file name, type and size to download.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Get a driver export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"job-9f86d081884c7d65\"",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_jobrunner.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found or expired\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"export not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}/download": {
+            "get": {
+                "description": "The exported drivers, as NDJSON (one driver per line) or a JSON array.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Download a driver export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"job-9f86d081884c7d65\"",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported drivers",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found or expired\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"export not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Export still running or failed\" example({\"error\":{\"code\":\"EXPORT_NOT_READY\",\"message\":\"export has not completed\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleets": {
+            "get": {
+                "description": "List all fleets ordered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "List fleets",
+                "responses": {
+                    "200": {
+                        "description": "Fleets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Fleet"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list fleets\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a fleet (taxi company) that drivers can be assigned to. Fleet names are unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Create a fleet",
+                "parameters": [
+                    {
+                        "description": "Fleet information",
+                        "name": "fleet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateFleetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Fleet created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Fleet"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"fleet name is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Name taken\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"fleet name already exists\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create fleet\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleets/{id}": {
+            "get": {
+                "description": "Get a fleet by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Get a fleet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6570a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Fleet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fleet",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Fleet"
+                        }
+                    },
+                    "404": {
+                        "description": "Fleet not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"fleet not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "description": "Incidents matching the filters, newest first. Fleet admins only see incidents reported while the driver was in their fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Incident queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only incidents of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only incidents of this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "open",
+                        "description": "open, investigating or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high",
+                        "description": "low, medium, high or critical",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "safety",
+                        "description": "safety, conduct, vehicle, fare, route or other",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6571f1f77bcf86cd79943901",
+                        "description": "Only incidents of this trip",
+                        "name": "tripId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 20,
+                        "description": "Page size, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incidents",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ListIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Riders cannot list incidents, or the driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}": {
+            "get": {
+                "description": "Get an incident with its investigation status and outcome.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6575a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incident",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Incident"
+                        }
+                    },
+                    "403": {
+                        "description": "Incident of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"incident not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/status": {
+            "put": {
+                "description": "Move an incident from open to investigating or resolved, or from investigating to resolved; resolved incidents cannot change. Moving to investigating with a severity confirms it: a high or critical one then suspends the driver, and a critical one revokes identity verification, if the report did not already. Resolving takes an outcome: upheld keeps a suspension the incident caused, dismissed lifts it unless another unresolved incident holds the driver. Revoked identity verification is not restored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Move an incident's investigation on",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6575a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.IncidentStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incident updated",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"outcome must be upheld or dismissed, and is only given when resolving\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Incident of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status does not allow the change\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"incident status does not allow this change\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kyc/webhook": {
+            "post": {
+                "description": "Inbound webhook for the KYC provider. The body {\"reference\",\"status\",\"reason\"} must be signed in the X-KYC-Signature header as \"t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of '\u003ct\u003e.\u003cbody\u003e'\u003e\" with the shared webhook secret; signatures older than 5 minutes are refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Receive KYC decision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "t=1733446800,v1=5d41402abc4b2a76b9719d911017c592",
+                        "description": "Webhook signature",
+                        "name": "X-KYC-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decision applied",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.KYCCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"webhook body must carry a reference and a valid status\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature\" example({\"error\":{\"code\":\"INVALID_SIGNATURE\",\"message\":\"invalid webhook signature\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown check\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"no identity check found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders": {
+            "post": {
+                "description": "Register a rider who can request trips. Phone numbers are normalized to E.164 and must be unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Register a rider",
+                "parameters": [
+                    {
+                        "description": "Rider information",
+                        "name": "rider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.RegisterRiderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rider registered",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"phone must be in E.164 format (e.g., +905321234567)\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone taken\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"phone is already registered to another rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to register rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/sign-in": {
+            "post": {
+                "description": "Confirm the one-time code sent to the rider's phone and return the rider",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Sign a rider in",
+                "parameters": [
+                    {
+                        "description": "Rider phone and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.RiderSignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider signed in",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired code\" example({\"error\":{\"code\":\"INVALID_CODE\",\"message\":\"invalid verification code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts\" example({\"error\":{\"code\":\"TOO_MANY_ATTEMPTS\",\"message\":\"too many verification attempts, request a new code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to sign in\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/sign-in/send": {
+            "post": {
+                "description": "Send a one-time sign-in code via SMS to the rider registered with the phone. Unknown phones get the same answer, so the response does not tell whether the phone is registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Send rider sign-in code",
+                "parameters": [
+                    {
+                        "description": "Rider phone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.RiderSignInCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Code sent if the phone is registered\" example({\"status\":\"sent\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"phone is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Code sent recently\" example({\"error\":{\"code\":\"RATE_LIMIT_EXCEEDED\",\"message\":\"verification code was sent recently, try again later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to send verification code\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}": {
+            "get": {
+                "description": "Get a rider's profile and favorite locations by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Get a rider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"rider not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to get rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a rider's profile. Only the fields in the request body change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Update a rider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile fields to change",
+                        "name": "rider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.UpdateRiderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated rider",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"email must be a valid email address\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"rider not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone taken\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"phone is already registered to another rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}/favorites": {
+            "post": {
+                "description": "Save a labelled place, such as home or work, that the rider can pick as pickup or dropoff",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Save a favorite location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Place to save",
+                        "name": "favorite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.FavoriteLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rider with the saved place",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"latitude must be between -90 and 90\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"rider not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many favorites\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"a rider can save at most 20 favorite locations\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}/favorites/{favoriteId}": {
+            "delete": {
+                "description": "Remove a saved place from the rider's favorites",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Remove a favorite location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"fav_3f9a1c2b\"",
+                        "description": "Favorite location ID",
+                        "name": "favoriteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider without the removed place",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Rider"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider or favorite not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"favorite location not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update rider\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts": {
+            "get": {
+                "description": "Shifts overlapping the range, by start time, for a fleet or a driver. Fleet admins only see their own fleet. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or \"Accept: text/calendar\" for an iCalendar export.",
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Shift calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only shifts of this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only shifts of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-08\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-15\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or ics",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ShiftCalendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"from must be before to\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}": {
+            "get": {
+                "description": "Get a planned shift, with when the driver was put on and off shift.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ScheduledShift"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"shift not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Move a shift. Once the driver was put on shift only its end can move; ended shifts cannot change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Reschedule a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift times",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ScheduleShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ScheduledShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"a shift cannot be longer than 24 hours\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"shift not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlaps another shift, or the shift started or ended\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"shift has started, only its end can change\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a planned shift. A running shift cannot be deleted; move its end instead.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Cancel a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Shift deleted"
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"shift not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Shift is running\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"shift has started, only its end can change\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxi-types": {
+            "get": {
+                "description": "The taxi types the service runs, in the order clients should show them, with their name, seats and icon. Every type can be searched for and requested; with fleetId, only the types drivers of that fleet may register with under its validation rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxi-types"
+                ],
+                "summary": "List taxi types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only list the types this fleet's drivers may register with",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Taxi types\" example({\"taxiTypes\":[{\"id\":\"sari\",\"name\":\"Sarı Taksi\",\"capacity\":4,\"icon\":\"taxi-yellow\"},{\"id\":\"turkuaz\",\"name\":\"Turkuaz Taksi\",\"capacity\":4,\"icon\":\"taxi-turquoise\"},{\"id\":\"siyah\",\"name\":\"Siyah Taksi\",\"capacity\":4,\"icon\":\"taxi-black\"}]})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_taxitype.Catalog"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips": {
+            "post": {
+                "description": "Create a ride request and offer it to a driver selected by the configured matching strategy.\nThe trip status is \"offered\" when a driver was found and \"no_driver_found\" otherwise.\nA riderId, when given, must belong to a registered rider.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Request a trip",
+                "parameters": [
+                    {
+                        "description": "Ride request",
+                        "name": "trip",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateTripRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Trip created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"pickup location is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Rider requesting for another rider\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"riders can only request trips for themselves\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create trip\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/estimate": {
+            "post": {
+                "description": "Quote the fare from pickup to dropoff without requesting a trip. The route is measured by the configured routing provider (straight line or OSRM road distance).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Estimate a fare",
+                "parameters": [
+                    {
+                        "description": "Pickup and dropoff",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.EstimateFareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fare estimate",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.FareEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"dropoff location is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to estimate fare\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}": {
+            "get": {
+                "description": "Get a trip and its current offer by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Get trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider's trip\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"trip does not belong to you\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"trip not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/accept": {
+            "post": {
+                "description": "Accept the trip offered to the driver. Fails once the offer has expired or moved on.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Accept trip offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver answering the offer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.TripOfferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"driverId is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"trip not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No active offer\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"trip has no active offer for this driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/cancel": {
+            "post": {
+                "description": "Cancel a trip that has not finished; an assigned driver becomes available again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Cancel trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider's trip\" example({\"error\":{\"code\":\"FORBIDDEN\",\"message\":\"trip does not belong to you\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"trip not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Trip already finished\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"trip status does not allow this action\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/complete": {
+            "post": {
+                "description": "Finish an accepted trip, optionally rating the driver from 1 to 5. The fare is the metered fare when given, otherwise the driven distance priced with the tariff or the quote; the driver is credited with it less commission.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Complete trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trip summary",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CompleteTripRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip completed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"rating must be between 1 and 5\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"trip not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Trip not accepted\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"trip status does not allow this action\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/decline": {
+            "post": {
+                "description": "Decline the trip offered to the driver; the trip is re-offered to the next driver",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Decline trip offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver answering the offer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.TripOfferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip re-offered",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"driverId is required\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"trip not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No active offer\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"trip has no active offer for this driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List webhook subscriptions, optionally of one fleet. Secrets are not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only list subscriptions of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to list webhook subscriptions\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe an endpoint to driver events (driver.created, driver.updated, driver.suspended, driver.license_expired). Deliveries are signed with HMAC-SHA256 in the X-Bitaksi-Signature header and retried with exponential backoff. The secret is only returned in this response. The url must use https outside debug mode and must not point to a loopback, private or link-local address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"webhook url must be an absolute http(s) URL\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to create webhook subscription\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "description": "Get a webhook subscription by ID. The secret is not included.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.WebhookSubscription"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"webhook subscription not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a subscription; its pending deliveries are not retried",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription deleted"
+                    },
+                    "404": {
+                        "description": "Subscription not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"webhook subscription not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Delivery log of a subscription, newest first, with attempts, last response status and next retry. Deliveries are kept for 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum deliveries to return (max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"limit must be a positive integer\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"webhook subscription not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/retry": {
+            "post": {
+                "description": "Send a delivery again as soon as possible. Failed deliveries get a fresh set of attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6572b1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Delivery rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.WebhookDelivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"webhook delivery not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery in progress\" example({\"error\":{\"code\":\"CONFLICT\",\"message\":\"webhook delivery is being sent, retry later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_bitaksi_driver-service_internal_domain.BulkFilter": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Fiat"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Egea"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "istanbul"
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439011"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly"
+                    ]
+                },
+                "taksiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.BulkUpdate": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "description": "ActorID, ActorRole and TenantID identify the caller, when the gateway forwarded one",
+                    "type": "string",
+                    "example": "fleet-admin"
+                },
+                "actorRole": {
+                    "type": "string",
+                    "example": "fleet_admin"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "dryRun": {
+                    "description": "DryRun updates only counted the drivers they would change",
+                    "type": "boolean",
+                    "example": false
+                },
+                "filter": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.BulkFilter"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6578a1f2c3d4e5f6a7b8c9d0"
+                },
+                "matched": {
+                    "description": "Matched is the number of drivers the filter matched",
+                    "type": "integer",
+                    "example": 42
+                },
+                "modified": {
+                    "description": "Modified is the number of drivers the patch changed, or would change on\na dry run; drivers that already had the patched values are not counted",
+                    "type": "integer",
+                    "example": 40
+                },
+                "patch": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverPatch"
+                },
+                "tenantId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivering",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliveryInProgress",
+                "DeliverySucceeded",
+                "DeliveryFailed"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DeviceToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "deviceId": {
+                    "description": "DeviceID identifies the device; issuing a token for a device revokes its previous one",
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastUsedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T09:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mehmet's Android"
+                },
+                "prefix": {
+                    "description": "Prefix is the start of the token, enough to tell tokens apart in listings",
+                    "type": "string",
+                    "example": "dtk_4f1c9a7e"
+                },
+                "replacedBy": {
+                    "description": "ReplacedBy is the token a rotation issued in place of this one",
+                    "type": "string",
+                    "example": "6573b1f2c3d4e5f6a7b8c9d0"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "location:update",
+                        "heartbeat"
+                    ]
+                },
+                "token": {
+                    "description": "Token authenticates the device; it is only returned when the token is issued or rotated",
+                    "type": "string",
+                    "example": "dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DocumentType": {
+            "type": "string",
+            "enum": [
+                "license",
+                "registration",
+                "insurance"
+            ],
+            "x-enum-varnames": [
+                "DocumentTypeLicense",
+                "DocumentTypeRegistration",
+                "DocumentTypeInsurance"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Driver": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are free-form facts about the driver, such as language: en",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "description": "Available reports whether the driver is on shift and can receive rides",
+                    "type": "boolean",
+                    "example": false
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "city": {
+                    "description": "City is derived from Location when the driver is stored; empty for\ndrivers stored before cities were configured",
+                    "type": "string",
+                    "example": "istanbul"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set on the tombstone a deleted driver leaves for delta syncs",
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "documents": {
+                    "description": "Documents holds at most one document per type",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverDocument"
+                    }
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ahmet.demir@example.com"
+                },
+                "emailVerified": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "fleetId": {
+                    "description": "FleetID is the fleet the driver works for; empty for independent drivers",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "identityVerified": {
+                    "description": "IdentityVerified is set once a KYC provider approved the driver's identity",
+                    "type": "boolean",
+                    "example": false
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "lastSeenAt": {
+                    "description": "LastSeenAt is the time of the driver app's latest heartbeat",
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "license": {
+                    "description": "License is optional until the driver's licence has been recorded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverLicense"
+                        }
+                    ]
+                },
+                "licenseExpired": {
+                    "description": "LicenseExpired drivers are not dispatchable until the licence is renewed",
+                    "type": "boolean",
+                    "example": false
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "locationUpdatedAt": {
+                    "description": "LocationUpdatedAt is when the location was last reported; nil for drivers\nstored before it was recorded",
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "phone": {
+                    "description": "Contact details; Phone is stored in E.164 format",
+                    "type": "string",
+                    "example": "+905321234567"
+                },
+                "phoneVerified": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "photo": {
+                    "description": "Photo is the driver's profile picture, if one was uploaded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverPhoto"
+                        }
+                    ]
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "rating": {
+                    "description": "Rating is the average rider rating (0-5) over RatingCount completed trips",
+                    "type": "number",
+                    "example": 4.8
+                },
+                "ratingCount": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "suspended": {
+                    "description": "Suspended drivers cannot go on shift and are left out of nearby searches",
+                    "type": "boolean",
+                    "example": false
+                },
+                "suspensionReason": {
+                    "type": "string",
+                    "example": "expired license"
+                },
+                "tags": {
+                    "description": "Tags are dispatch features such as pet-friendly or wheelchair-accessible",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taxiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverDocument": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DocumentType"
+                        }
+                    ],
+                    "example": "license"
+                },
+                "uploadedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://files.bitaksi.com/docs/license.pdf"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverLicense": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "type": "string",
+                    "example": "B"
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverPatch": {
+            "type": "object",
+            "properties": {
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "suspended": {
+                    "description": "Suspended suspends or reinstates the drivers. Suspending also takes them\noff shift and records SuspendReason.",
+                    "type": "boolean",
+                    "example": true
+                },
+                "suspensionReason": {
+                    "type": "string",
+                    "example": "fleet inspection"
+                },
+                "taksiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "turkuaz"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverPhoto": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer",
+                    "example": 768
+                },
+                "thumbnails": {
+                    "description": "Thumbnails are square crops keyed by their side in pixels",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "256": "https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/256.jpg"
+                    }
+                },
+                "uploadedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "url": {
+                    "description": "URL is the full-size picture, scaled down to the configured maximum",
+                    "type": "string",
+                    "example": "https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/full.jpg"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverQuota": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "maxDrivers": {
+                    "description": "MaxDrivers is the quota; 0 is unlimited",
+                    "type": "integer",
+                    "example": 50
+                },
+                "remaining": {
+                    "description": "Remaining is left out for unlimited tenants, and is 0 once the quota is\nused up even if concurrent creations overshot it",
+                    "type": "integer",
+                    "example": 8
+                },
+                "tenantId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "thresholds": {
+                    "description": "Thresholds are the usage percentages a TenantQuotaThresholdReached event is emitted at",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        80,
+                        100
+                    ]
+                },
+                "usedPercent": {
+                    "type": "number",
+                    "example": 84
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.DriverStats": {
+            "type": "object",
+            "properties": {
+                "averageRating": {
+                    "description": "AverageRating is the mean of the ratings given on trips in the range; 0 when unrated",
+                    "type": "number",
+                    "example": 4.8
+                },
+                "completedTrips": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "distanceDrivenKm": {
+                    "description": "DistanceDrivenKm is computed from the recorded location history",
+                    "type": "number",
+                    "example": 528.3
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-01T00:00:00Z"
+                },
+                "locationAnomalies": {
+                    "description": "LocationAnomalies counts the location updates that implied an implausible speed",
+                    "type": "integer",
+                    "example": 0
+                },
+                "onlineHours": {
+                    "description": "OnlineHours is the time spent on shift within the range",
+                    "type": "number",
+                    "example": 96.5
+                },
+                "ratingCount": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "tripDistanceKm": {
+                    "description": "TripDistanceKm is the distance reported for completed trips",
+                    "type": "number",
+                    "example": 311.6
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Earning": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "description": "Commission is the platform's share of the fare",
+                    "type": "number",
+                    "example": 34.86
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "earnedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "gross": {
+                    "description": "Gross is the fare of a trip; it is zero for adjustments",
+                    "type": "number",
+                    "example": 232.4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
+                },
+                "net": {
+                    "description": "Net is what the driver is owed; adjustments may be negative",
+                    "type": "number",
+                    "example": 197.54
+                },
+                "payoutId": {
+                    "type": "string",
+                    "example": "6573b2f3c4d5e6f7a8b9c0d1"
+                },
+                "reason": {
+                    "description": "Reason and CreatedBy form the audit trail of an adjustment",
+                    "type": "string",
+                    "example": "Toll reimbursement"
+                },
+                "tripId": {
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.EarningType"
+                        }
+                    ],
+                    "example": "trip"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.EarningType": {
+            "type": "string",
+            "enum": [
+                "trip",
+                "adjustment"
+            ],
+            "x-enum-varnames": [
+                "EarningTypeTrip",
+                "EarningTypeAdjustment"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.EarningsBucket": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00+03:00"
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.EarningsPeriod": {
+            "type": "string",
+            "enum": [
+                "daily",
+                "weekly"
+            ],
+            "x-enum-varnames": [
+                "EarningsPeriodDaily",
+                "EarningsPeriodWeekly"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.EarningsTotals": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Erasure": {
+            "type": "object",
+            "properties": {
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "erasedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "files": {
+                    "description": "Files is the number of stored files, such as photos, that were deleted",
+                    "type": "integer",
+                    "example": 4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6574a1f2c3d4e5f6a7b8c9d0"
+                },
+                "reason": {
+                    "description": "Reason is the operator's note on an explicit request, e.g. a ticket number",
+                    "type": "string",
+                    "example": "KVKK request #4711"
+                },
+                "records": {
+                    "description": "Records counts the records deleted or scrubbed per collection",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "requestedBy": {
+                    "description": "RequestedBy is the operator who made an explicit request",
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "trigger": {
+                    "enum": [
+                        "retention",
+                        "request"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ErasureTrigger"
+                        }
+                    ],
+                    "example": "request"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.ErasureTrigger": {
+            "type": "string",
+            "enum": [
+                "retention",
+                "request"
+            ],
+            "x-enum-varnames": [
+                "ErasureTriggerRetention",
+                "ErasureTriggerRequest"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.FailoverStats": {
+            "type": "object",
+            "properties": {
+                "lastPrimaryChangeAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "lastTransientAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:03Z"
+                },
+                "primary": {
+                    "description": "Primary is the address of the current replica-set primary, empty while there is none",
+                    "type": "string",
+                    "example": "mongo-1:27017"
+                },
+                "primaryChanges": {
+                    "description": "PrimaryChanges counts elections that moved the primary to another member",
+                    "type": "integer",
+                    "example": 2
+                },
+                "recovered": {
+                    "description": "Recovered counts operations that succeeded after at least one retry",
+                    "type": "integer",
+                    "example": 9
+                },
+                "retries": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "transientErrors": {
+                    "description": "TransientErrors counts operation attempts that failed with a retryable error",
+                    "type": "integer",
+                    "example": 14
+                },
+                "unavailable": {
+                    "description": "Unavailable counts operations that gave up and were answered with 503",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.FareEstimate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 232.4
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "durationSec": {
+                    "type": "integer",
+                    "example": 1260
+                },
+                "provider": {
+                    "description": "Provider is the routing provider that measured the route",
+                    "type": "string",
+                    "example": "osrm"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.FavoriteLocation": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Caferağa Mah. Moda Cad. No:1, Kadıköy"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "fav_3f9a1c2b"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Home"
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Fleet": {
+            "type": "object",
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi Ltd. Şti."
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Incident": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "enum": [
+                        "safety",
+                        "conduct",
+                        "vehicle",
+                        "fare",
+                        "route",
+                        "other"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentCategory"
+                        }
+                    ],
+                    "example": "conduct"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Driver was on the phone during the whole ride"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "driverSuspended": {
+                    "description": "DriverSuspended is set when reporting the incident, or confirming its\nseverity, suspended the driver",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fleetId": {
+                    "description": "FleetID is the driver's fleet when the incident was reported",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6575a1f2c3d4e5f6a7b8c9d0"
+                },
+                "identityRevoked": {
+                    "description": "IdentityRevoked is set when reporting the incident, or confirming its\nseverity, revoked the driver's identity verification",
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "description": "Note is the latest remark of the investigation",
+                    "type": "string",
+                    "example": "Rider confirmed the driver used a hands-free set"
+                },
+                "outcome": {
+                    "enum": [
+                        "upheld",
+                        "dismissed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentOutcome"
+                        }
+                    ],
+                    "example": "dismissed"
+                },
+                "reportedBy": {
+                    "description": "ReportedBy and ReporterRole identify the caller who reported it; empty for operators",
+                    "type": "string",
+                    "example": "rider-42"
+                },
+                "reporterRole": {
+                    "type": "string",
+                    "example": "rider"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2025-12-07T09:30:00Z"
+                },
+                "severity": {
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "critical"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentSeverity"
+                        }
+                    ],
+                    "example": "medium"
+                },
+                "status": {
+                    "enum": [
+                        "open",
+                        "investigating",
+                        "resolved"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentStatus"
+                        }
+                    ],
+                    "example": "open"
+                },
+                "tripId": {
+                    "description": "TripID is the trip the incident happened on, if any",
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IncidentCategory": {
+            "type": "string",
+            "enum": [
+                "safety",
+                "conduct",
+                "vehicle",
+                "fare",
+                "route",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "IncidentCategorySafety",
+                "IncidentCategoryConduct",
+                "IncidentCategoryVehicle",
+                "IncidentCategoryFare",
+                "IncidentCategoryRoute",
+                "IncidentCategoryOther"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IncidentOutcome": {
+            "type": "string",
+            "enum": [
+                "upheld",
+                "dismissed"
+            ],
+            "x-enum-varnames": [
+                "IncidentOutcomeUpheld",
+                "IncidentOutcomeDismissed"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IncidentSeverity": {
+            "type": "string",
+            "enum": [
+                "low",
+                "medium",
+                "high",
+                "critical"
+            ],
+            "x-enum-varnames": [
+                "IncidentSeverityLow",
+                "IncidentSeverityMedium",
+                "IncidentSeverityHigh",
+                "IncidentSeverityCritical"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IncidentStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "investigating",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "IncidentStatusOpen",
+                "IncidentStatusInvestigating",
+                "IncidentStatusResolved"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IndexReport": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "inSync": {
+                    "description": "InSync is true when every expected index exists with the expected keys",
+                    "type": "boolean",
+                    "example": false
+                },
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IndexStatus"
+                    }
+                },
+                "mismatched": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "unexpected": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IndexStatus": {
+            "type": "object",
+            "properties": {
+                "actualKeys": {
+                    "description": "ActualKeys is set when an index with the expected name has different keys",
+                    "type": "string",
+                    "example": "fleetId:1"
+                },
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "keys": {
+                    "description": "Keys is the expected key pattern, or the existing one for unexpected indexes",
+                    "type": "string",
+                    "example": "fleetId:1,createdAt:-1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "fleetId_createdAt"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "present",
+                        "missing",
+                        "mismatch",
+                        "unexpected"
+                    ],
+                    "example": "present"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IndexSyncError": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "E11000 duplicate key error"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "phone_unique"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.IndexSyncJob": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor identifies who started the sync, for the audit log",
+                    "type": "string",
+                    "example": "ops@bitaksi.com"
+                },
+                "completed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "current": {
+                    "description": "Current is the index being built, as \"collection.name\"",
+                    "type": "string",
+                    "example": "drivers.fleetId_createdAt"
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IndexSyncError"
+                    }
+                },
+                "finishedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "idxsync-1733446800000"
+                },
+                "skipped": {
+                    "description": "Skipped lists mismatched indexes, which must be dropped by hand before they can be rebuilt",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "completed",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.KYCCheck": {
+            "type": "object",
+            "properties": {
+                "decidedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:05:00Z"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d1"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "sandbox"
+                },
+                "reason": {
+                    "description": "Reason explains a rejection, as given by the provider",
+                    "type": "string",
+                    "example": "document is not legible"
+                },
+                "reference": {
+                    "description": "Reference identifies the check at the provider",
+                    "type": "string",
+                    "example": "sbx_9f3c2a1b7e6d5c4a"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.KYCStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "submittedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.KYCStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "KYCStatusPending",
+                "KYCStatusApproved",
+                "KYCStatusRejected"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Location": {
+            "type": "object",
+            "properties": {
+                "geohash": {
+                    "description": "Geohash is the cell of the coordinates with GEOHASH_PRECISION characters,\nfor clients bucketing drivers into map tiles; it is only rendered",
+                    "type": "string",
+                    "example": "sxk9kp"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.LocationAnomalyCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "lastDetectedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "maxSpeedKmh": {
+                    "type": "number",
+                    "example": 11520
+                },
+                "rejected": {
+                    "description": "Rejected is how many of the updates were refused",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.LocationAnomalyReport": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.LocationAnomalyCount"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-06T00:00:00Z"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-06T00:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.OnlineStats": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "heartbeatTimeoutSec": {
+                    "description": "HeartbeatTimeoutSec is how recent a heartbeat must be for a driver to count as online",
+                    "type": "integer",
+                    "example": 120
+                },
+                "offline": {
+                    "type": "integer",
+                    "example": 70
+                },
+                "online": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Payout": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "drivers": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "description": "From is unset when the payout covers everything unpaid before To",
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573b2f3c4d5e6f7a8b9c0d1"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.PayoutLine"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00Z"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 2420.48
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.PayoutLine": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.QueryBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 47890
+                },
+                "leMs": {
+                    "type": "number",
+                    "example": 50
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.QueryStats": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Buckets is the duration histogram; each bucket counts the commands that\ntook at most LeMs, and the last one, without LeMs, counts all of them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.QueryBucket"
+                    }
+                },
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "command": {
+                    "description": "Command is the database command, e.g. find, aggregate or update",
+                    "type": "string",
+                    "example": "find"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 48210
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "maxMs": {
+                    "type": "number",
+                    "example": 812.4
+                },
+                "slow": {
+                    "description": "Slow counts commands that took at least the slow query threshold",
+                    "type": "integer",
+                    "example": 12
+                },
+                "totalMs": {
+                    "type": "number",
+                    "example": 96420.5
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Rider": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "elif@example.com"
+                },
+                "favoriteLocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.FavoriteLocation"
+                    }
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Yılmaz"
+                },
+                "phone": {
+                    "description": "Phone is the rider's E.164 phone number and identifies the rider uniquely",
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.ScheduledShift": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "endedAt": {
+                    "description": "EndedAt is when the scheduler took the driver off shift",
+                    "type": "string",
+                    "example": "2025-12-08T14:00:09Z"
+                },
+                "endsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T14:00:00Z"
+                },
+                "fleetId": {
+                    "description": "FleetID is the driver's fleet when the shift was planned, for fleet calendars",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6574a1f2c3d4e5f6a7b8c9d0"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Airport rank"
+                },
+                "startError": {
+                    "description": "StartError is why the driver could not be put on shift, e.g. an unverified phone",
+                    "type": "string",
+                    "example": "phone must be verified before going on shift"
+                },
+                "startedAt": {
+                    "description": "StartedAt is when the scheduler put the driver on shift",
+                    "type": "string",
+                    "example": "2025-12-08T06:00:12Z"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.ShadowReadOpStats": {
+            "type": "object",
+            "properties": {
+                "diverged": {
+                    "description": "Diverged counts reads whose shadow result or error differed from the primary's",
+                    "type": "integer",
+                    "example": 9
+                },
+                "matchRatePercent": {
+                    "type": "number",
+                    "example": 99.81
+                },
+                "matched": {
+                    "type": "integer",
+                    "example": 4810
+                },
+                "mirrored": {
+                    "type": "integer",
+                    "example": 4821
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "GetByID"
+                },
+                "timedOut": {
+                    "description": "TimedOut counts shadow reads that did not answer within the timeout",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.ShadowReadStats": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "description": "Backend is the kind of store mirrored to, e.g. mongodb",
+                    "type": "string",
+                    "example": "mongodb"
+                },
+                "dropped": {
+                    "description": "Dropped counts sampled reads not mirrored because too many were in flight",
+                    "type": "integer",
+                    "example": 0
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "inFlight": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ShadowReadOpStats"
+                    }
+                },
+                "sampleRatePercent": {
+                    "description": "SampleRatePercent is the share of reads mirrored",
+                    "type": "number",
+                    "example": 10
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.TaxiType": {
+            "type": "string",
+            "enum": [
+                "sari",
+                "turkuaz",
+                "siyah"
+            ],
+            "x-enum-varnames": [
+                "TaxiTypeSari",
+                "TaxiTypeTurkuaz",
+                "TaxiTypeSiyah"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.TelemetryCounts": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "description": "Failed messages hit a transient error and are left for the broker to\nredeliver when subscribed with QoS 1 or 2",
+                    "type": "integer",
+                    "example": 9
+                },
+                "processed": {
+                    "description": "Processed messages reached the driver's location or heartbeat",
+                    "type": "integer",
+                    "example": 182101
+                },
+                "received": {
+                    "type": "integer",
+                    "example": 182340
+                },
+                "rejected": {
+                    "description": "Rejected messages were acknowledged and dropped; sending them again would not help",
+                    "type": "integer",
+                    "example": 230
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.TelemetryStats": {
+            "type": "object",
+            "properties": {
+                "broker": {
+                    "type": "string",
+                    "example": "tcp://mqtt:1883"
+                },
+                "connected": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "lastDisconnectAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "lastMessageAt": {
+                    "type": "string",
+                    "example": "2025-12-06T09:30:00Z"
+                },
+                "messages": {
+                    "description": "Messages counts messages by kind: location or heartbeat",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TelemetryCounts"
+                    }
+                },
+                "qos": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reconnects": {
+                    "description": "Reconnects counts attempts to reconnect after the connection was lost",
+                    "type": "integer",
+                    "example": 1
+                },
+                "rejections": {
+                    "description": "Rejections counts rejected messages by reason: topic, payload, token,\nscope or invalid",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "drivers/+/location",
+                        "drivers/+/heartbeat"
+                    ]
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.Trip": {
+            "type": "object",
+            "properties": {
+                "acceptedAt": {
+                    "description": "AcceptedAt is when the driver accepted the trip; busy time runs from it to CompletedAt",
+                    "type": "string"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "declinedDriverIds": {
+                    "description": "DeclinedDriverIDs holds drivers that declined or let an offer expire; they are not offered again",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "estimate": {
+                    "description": "Estimate is the fare quoted when the trip was requested with a dropoff",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.FareEstimate"
+                        }
+                    ]
+                },
+                "fare": {
+                    "description": "Fare is what the rider paid for a completed trip",
+                    "type": "number",
+                    "example": 232.4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "offerCount": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "offerExpiresAt": {
+                    "type": "string"
+                },
+                "offeredDriverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "rating": {
+                    "description": "Rating is the rider's 1-5 rating of the driver for this trip, if given",
+                    "type": "number",
+                    "example": 5
+                },
+                "riderId": {
+                    "type": "string",
+                    "example": "rider-42"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TripStatus"
+                        }
+                    ],
+                    "example": "offered"
+                },
+                "strategy": {
+                    "type": "string",
+                    "example": "nearest"
+                },
+                "taxiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.TripStatus": {
+            "type": "string",
+            "enum": [
+                "requested",
+                "offered",
+                "accepted",
+                "completed",
+                "cancelled",
+                "no_driver_found"
+            ],
+            "x-enum-varnames": [
+                "TripStatusRequested",
+                "TripStatusOffered",
+                "TripStatusAccepted",
+                "TripStatusCompleted",
+                "TripStatusCancelled",
+                "TripStatusNoDriver"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_domain.UtilizationDay": {
+            "type": "object",
+            "properties": {
+                "busyHours": {
+                    "description": "BusyHours is the time drivers spent between accepting and completing trips",
+                    "type": "number",
+                    "example": 268.1
+                },
+                "day": {
+                    "description": "Day is the calendar day in the report time zone",
+                    "type": "string",
+                    "example": "2025-12-01"
+                },
+                "onlineHours": {
+                    "description": "OnlineHours is the time drivers spent on shift",
+                    "type": "number",
+                    "example": 412.5
+                },
+                "partial": {
+                    "description": "Partial is set on the current day, which is computed for the request",
+                    "type": "boolean",
+                    "example": false
+                },
+                "taxiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                },
+                "utilization": {
+                    "description": "Utilization is BusyHours over OnlineHours; 0 without online time",
+                    "type": "number",
+                    "example": 0.65
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.UtilizationReport": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.UtilizationDay"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-01"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Istanbul"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-07"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "deliveredAt": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string",
+                    "example": "driver.suspended"
+                },
+                "eventId": {
+                    "type": "string",
+                    "example": "evt_5f0c2a9e8b7d4c3a"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6572b1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastError": {
+                    "type": "string",
+                    "example": "endpoint returned status 503"
+                },
+                "lastStatusCode": {
+                    "type": "integer",
+                    "example": 503
+                },
+                "nextAttemptAt": {
+                    "description": "NextAttemptAt is when a pending delivery is retried, or when the lease of an in-progress one expires",
+                    "type": "string",
+                    "example": "2025-12-06T01:00:40Z"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "delivering",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DeliveryStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "subscriptionId": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:10Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_domain.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi dispatch system"
+                },
+                "events": {
+                    "description": "Events lists the event types to deliver; empty delivers every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "driver.created",
+                        "driver.suspended"
+                    ]
+                },
+                "fleetId": {
+                    "description": "FleetID limits the subscription to drivers of one fleet; empty receives events of every driver",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
+                },
+                "secret": {
+                    "description": "Secret signs every delivery; it is only returned when the subscription is created",
+                    "type": "string",
+                    "example": "whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/bitaksi"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_jobrunner.Job": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor identifies who started the job, for the audit log",
+                    "type": "string",
+                    "example": "ops@bitaksi.com"
+                },
+                "changed": {
+                    "description": "Changed counts processed items that were modified while the job ran",
+                    "type": "integer",
+                    "example": 12
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "error": {
+                    "description": "Error tells why a failed job failed",
+                    "type": "string",
+                    "example": "failed to list drivers"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is when a finished job and its result are deleted",
+                    "type": "string",
+                    "example": "2025-12-07T01:04:10Z"
+                },
+                "finishedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:04:10Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "job-9f86d081884c7d65"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "export"
+                },
+                "owner": {
+                    "description": "Owner is the tenant whose data the job covers; empty for all tenants",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 420000
+                },
+                "result": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_jobrunner.Result"
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:01Z"
+                },
+                "status": {
+                    "description": "Status is queued, running, completed or failed",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "completed",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_jobrunner.Status"
+                        }
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "description": "Total is how many items the job expects to process; 0 until it knows",
+                    "type": "integer",
+                    "example": 1000000
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_jobrunner.Result": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "type": "string",
+                    "example": "application/x-ndjson"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "drivers-20251206-010000.ndjson"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 48210331
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_jobrunner.Status": {
+            "type": "string",
+            "enum": [
+                "queued",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "StatusQueued",
+                "StatusRunning",
+                "StatusCompleted",
+                "StatusFailed"
+            ]
+        },
+        "github_com_bitaksi_driver-service_internal_logging.LevelsSnapshot": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                },
+                "loggers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_middleware.SaturationStats": {
+            "type": "object",
+            "properties": {
+                "inFlight": {
+                    "type": "integer",
+                    "example": 420
+                },
+                "maxInFlight": {
+                    "description": "MaxInFlight is 0 when requests are not limited",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "openConnections": {
+                    "type": "integer",
+                    "example": 230
+                },
+                "peakInFlight": {
+                    "type": "integer",
+                    "example": 812
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "saturation": {
+                    "description": "Saturation is InFlight / MaxInFlight, or 0 without a limit",
+                    "type": "number",
+                    "example": 0.42
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_rules.Effective": {
+            "type": "object",
+            "properties": {
+                "countries": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_rules.Ruleset"
+                    }
+                },
+                "country": {
+                    "description": "Country is the market the service runs in; Default holds its rules",
+                    "type": "string",
+                    "example": "TR"
+                },
+                "default": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_rules.Ruleset"
+                },
+                "tenants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_rules.Ruleset"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_rules.Ruleset": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "TR"
+                },
+                "fieldPatterns": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "plateExample": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "platePattern": {
+                    "type": "string",
+                    "example": "^[A-Z]{2}[0-9]{3}[A-Z]{2}$"
+                },
+                "requiredFields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "firstName",
+                        "lastName",
+                        "carBrand",
+                        "carModel"
+                    ]
+                },
+                "taxiTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                    },
+                    "example": [
+                        "sari",
+                        "turkuaz",
+                        "siyah"
+                    ]
+                },
+                "tenant": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_taxitype.Catalog": {
+            "type": "object",
+            "properties": {
+                "taxiTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_taxitype.Type"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_taxitype.Type": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Capacity is the number of passenger seats",
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "description": "Description is an optional line shown under the name",
+                    "type": "string",
+                    "example": "Standard city taxi"
+                },
+                "icon": {
+                    "description": "Icon is the name of the icon clients draw the type with",
+                    "type": "string",
+                    "example": "taxi-yellow"
+                },
+                "id": {
+                    "description": "ID is what drivers, searches and trips refer to the type by",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sarı Taksi"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.AssignFleetRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.AutocompleteResponse": {
+            "type": "object",
+            "properties": {
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.DriverSuggestion"
+                    }
+                },
+                "timedOut": {
+                    "description": "TimedOut is set when the search ran past its time budget and returned\nno suggestions; the next keystroke's search usually finishes in time",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.BulkUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "DryRun counts the drivers the update would change without changing them",
+                    "type": "boolean",
+                    "example": true
+                },
+                "filter": {
+                    "description": "Filter must set at least one field; fleet admins are always limited to their own fleet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.BulkFilter"
+                        }
+                    ]
+                },
+                "patch": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverPatch"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CompleteTripRequest": {
+            "type": "object",
+            "properties": {
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "fare": {
+                    "description": "Fare is the metered fare; when omitted the distance is priced with the tariff",
+                    "type": "number",
+                    "example": 232.4
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreateAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "driverId",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount is credited to the driver; negative amounts deduct",
+                    "type": "number",
+                    "example": 50
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Toll reimbursement for trip 6571f1f77bcf86cd79943901"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreateDriverRequest": {
+            "type": "object",
+            "required": [
+                "carBrand",
+                "carModel",
+                "firstName",
+                "lastName",
+                "lat",
+                "lon",
                 "plate",
                 "taksiType"
             ],
             "properties": {
-                "carBrand": {
+                "attributes": {
+                    "description": "Attributes are at most 20 keys, named like tags, with values of up to 64 characters",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ahmet.demir@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverLicense"
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905321234567"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "tags": {
+                    "description": "Tags are at most 20 lowercase words joined by dashes, e.g. pet-friendly",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taksiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreateFleetRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi Ltd. Şti."
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreatePayoutRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From is optional; without it every unpaid entry before To is paid out",
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00+03:00"
+                },
+                "to": {
+                    "description": "To defaults to the start of the current day",
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00+03:00"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreateTripRequest": {
+            "type": "object",
+            "properties": {
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "riderId": {
+                    "type": "string",
+                    "example": "rider-42"
+                },
+                "taxiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi dispatch system"
+                },
+                "events": {
+                    "description": "Events filters the event types to deliver; empty delivers every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "driver.created",
+                        "driver.suspended"
+                    ]
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "secret": {
+                    "description": "Secret signs deliveries; one is generated when empty",
+                    "type": "string",
+                    "example": "whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/bitaksi"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.DriverChangesResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439012"
+                    ]
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "nextToken": {
+                    "description": "NextToken is passed as since on the next request, both to fetch the\nrest of a large window and for the next sync once HasMore is false",
+                    "type": "string",
+                    "example": "eyJzIjoiMjAyNS0xMi0wNlQwMTowMDowMFoiLCJ1IjoiMjAyNS0xMi0wNlQwMTowMTozMFoiLCJpIjoiNTA3ZjFmNzdiY2Y4NmNkNzk5NDM5MDExIn0"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.DriverSuggestion": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "description": "Highlights mark the parts of the name and plate the query matched",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.SearchHighlight"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ahmet Demir"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.EarningsSummary": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.EarningsBucket"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-06T00:00:00+03:00"
+                },
+                "period": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.EarningsPeriod"
+                        }
+                    ],
+                    "example": "daily"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Istanbul"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-06T00:00:00+03:00"
+                },
+                "totals": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.EarningsTotals"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.EstimateFareRequest": {
+            "type": "object",
+            "properties": {
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
+                "taxiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ExpiringLicense": {
+            "type": "object",
+            "properties": {
+                "daysLeft": {
+                    "description": "DaysLeft is negative once the licence has expired",
+                    "type": "integer",
+                    "example": 12
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "expired": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "flagged": {
+                    "description": "Flagged drivers have been taken off dispatch by the licence check",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverLicense"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ExpiringLicensesResponse": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "days": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "licenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_usecase.ExpiringLicense"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ExportRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "description": "FleetID only exports drivers of this fleet; fleet admins always export their own",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "format": {
+                    "description": "Format is ndjson (one driver per line, the default) or json (an array)",
+                    "type": "string",
+                    "enum": [
+                        "ndjson",
+                        "json"
+                    ],
+                    "example": "ndjson"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.FavoriteLocationRequest": {
+            "type": "object",
+            "required": [
+                "label"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Caferağa Mah. Moda Cad. No:1, Kadıköy"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Home"
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.IncidentStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Rider confirmed the driver used a hands-free set"
+                },
+                "outcome": {
+                    "description": "Outcome is required when resolving: upheld or dismissed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentOutcome"
+                        }
+                    ],
+                    "example": "dismissed"
+                },
+                "severity": {
+                    "description": "Severity confirms or corrects the reported severity when moving to\ninvestigating; a confirmed high or critical severity takes effect then",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentSeverity"
+                        }
+                    ],
+                    "example": "high"
+                },
+                "status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentStatus"
+                        }
+                    ],
+                    "example": "resolved"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.IssueDeviceTokenRequest": {
+            "type": "object",
+            "required": [
+                "deviceId"
+            ],
+            "properties": {
+                "deviceId": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mehmet's Android"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ListDriversResponse": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
+                    }
+                },
+                "hasNext": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "hasPrev": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "totalCount": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "totalPages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ListIncidentsResponse": {
+            "type": "object",
+            "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "hasPrev": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Incident"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "totalCount": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "totalPages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.NearbyDriverResponse": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "Distance is DistanceKm in the units asked for; left out when none were",
+                    "type": "number",
+                    "example": 500
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "durationSec": {
+                    "description": "DurationSec is the estimated driving time to the rider",
+                    "type": "integer",
+                    "example": 95
+                },
+                "firstName": {
                     "type": "string",
-                    "example": "Toyota"
+                    "example": "Ahmet"
                 },
-                "carModel": {
+                "id": {
                     "type": "string",
-                    "example": "Corolla"
+                    "example": "507f1f77bcf86cd799439011"
                 },
-                "firstName": {
+                "lastLocationUpdate": {
+                    "description": "LastLocationUpdate is when the driver's position was last reported",
                     "type": "string",
-                    "example": "Ahmet"
+                    "example": "2025-12-06T01:00:00Z"
                 },
                 "lastName": {
                     "type": "string",
                     "example": "Demir"
                 },
-                "lat": {
-                    "type": "number",
-                    "example": 41.0431
-                },
-                "lon": {
-                    "type": "number",
-                    "example": 29.0099
-                },
                 "plate": {
                     "type": "string",
                     "example": "34ABC123"
                 },
-                "taksiType": {
+                "staleSeconds": {
+                    "description": "StaleSeconds is the age of the position at search time, so clients can\ngray out drivers whose position is old",
+                    "type": "integer",
+                    "example": 42
+                },
+                "taxiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.RegisterRiderRequest": {
+            "type": "object",
+            "required": [
+                "firstName",
+                "lastName",
+                "phone"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "elif@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Yılmaz"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ReportIncidentRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "description",
+                "severity"
+            ],
+            "properties": {
+                "category": {
                     "allOf": [
                         {
-                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentCategory"
                         }
                     ],
-                    "example": "sari"
+                    "example": "conduct"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Driver was on the phone during the whole ride"
+                },
+                "severity": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.IncidentSeverity"
+                        }
+                    ],
+                    "example": "medium"
+                },
+                "tripId": {
+                    "description": "TripID links the trip the incident happened on; riders must give one of their trips",
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
                 }
             }
         },
-        "github_com_bitaksi_driver-service_internal_usecase.ListDriversResponse": {
+        "github_com_bitaksi_driver-service_internal_usecase.RiderSignInCodeRequest": {
             "type": "object",
+            "required": [
+                "phone"
+            ],
             "properties": {
-                "drivers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Driver"
-                    }
-                },
-                "page": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "pageSize": {
-                    "type": "integer",
-                    "example": 20
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.RiderSignInRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "phone"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 },
-                "totalCount": {
-                    "type": "integer",
-                    "example": 1
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
                 }
             }
         },
-        "github_com_bitaksi_driver-service_internal_usecase.NearbyDriverResponse": {
+        "github_com_bitaksi_driver-service_internal_usecase.RouteDriverResponse": {
             "type": "object",
             "properties": {
+                "distance": {
+                    "description": "Distance and RouteDistance are DistanceKm and RouteKm in the units asked\nfor; left out when none were",
+                    "type": "number",
+                    "example": 200
+                },
                 "distanceKm": {
+                    "description": "DistanceKm is the straight-line distance from the driver to the route",
                     "type": "number",
-                    "example": 0.5
+                    "example": 0.2
                 },
                 "firstName": {
                     "type": "string",
@@ -448,19 +8059,174 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Demir"
                 },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                },
                 "plate": {
                     "type": "string",
                     "example": "34ABC123"
                 },
+                "routeDistance": {
+                    "type": "number",
+                    "example": 3400
+                },
+                "routeKm": {
+                    "description": "RouteKm is how far along the route, from its first waypoint, the driver is closest to it",
+                    "type": "number",
+                    "example": 3.4
+                },
                 "taxiType": {
                     "type": "string",
                     "example": "sari"
                 }
             }
         },
+        "github_com_bitaksi_driver-service_internal_usecase.RouteSearchRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "live": {
+                    "description": "Live only returns drivers with a recent heartbeat; nil leaves it to the service default",
+                    "type": "boolean",
+                    "example": true
+                },
+                "taksiType": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.TaxiType"
+                        }
+                    ],
+                    "example": "sari"
+                },
+                "waypoints": {
+                    "description": "Waypoints are the ordered points of the route, 2 to 25 of them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.Location"
+                    }
+                },
+                "widthKm": {
+                    "description": "WidthKm is how far from the route drivers may be; defaults to 0.5, at most 2",
+                    "type": "number",
+                    "example": 0.5
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ScheduleShiftRequest": {
+            "type": "object",
+            "required": [
+                "endsAt",
+                "startsAt"
+            ],
+            "properties": {
+                "endsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T14:00:00Z"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Airport rank"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.SearchHighlight": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field is name or plate",
+                    "type": "string",
+                    "example": "name"
+                },
+                "length": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.SetAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "available"
+            ],
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.SetSuspensionRequest": {
+            "type": "object",
+            "required": [
+                "suspended"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "expired license"
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.ShiftCalendar": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00Z"
+                },
+                "shifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.ScheduledShift"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-15T00:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.TripOfferRequest": {
+            "type": "object",
+            "required": [
+                "driverId"
+            ],
+            "properties": {
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
         "github_com_bitaksi_driver-service_internal_usecase.UpdateDriverRequest": {
             "type": "object",
             "properties": {
+                "attributes": {
+                    "description": "Attributes replaces the driver's attributes; an empty object removes them",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "carBrand": {
                     "type": "string",
                     "example": "Honda"
@@ -469,6 +8235,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Civic"
                 },
+                "email": {
+                    "type": "string",
+                    "example": "mehmet.kurt@example.com"
+                },
                 "firstName": {
                     "type": "string",
                     "example": "Mehmet"
@@ -481,13 +8251,35 @@ const docTemplate = `{
                     "type": "number",
                     "example": 41.0082
                 },
+                "license": {
+                    "description": "License replaces the driver's licence; a valid one clears the expired flag",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DriverLicense"
+                        }
+                    ]
+                },
                 "lon": {
                     "type": "number",
                     "example": 28.9784
                 },
+                "phone": {
+                    "type": "string",
+                    "example": "+905329876543"
+                },
                 "plate": {
                     "type": "string",
-                    "example": "34XYZ789"
+                    "example": "34KYZ789"
+                },
+                "tags": {
+                    "description": "Tags replaces the driver's tags; an empty list removes them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly"
+                    ]
                 },
                 "taksiType": {
                     "allOf": [
@@ -499,6 +8291,80 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_bitaksi_driver-service_internal_usecase.UpdateRiderRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "elif.kaya@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Kaya"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905557654321"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.UploadDocumentRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "url"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_driver-service_internal_domain.DocumentType"
+                        }
+                    ],
+                    "example": "license"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://files.bitaksi.com/docs/license.pdf"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.VerifyDeviceTokenRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a"
+                }
+            }
+        },
+        "github_com_bitaksi_driver-service_internal_usecase.VerifyPhoneRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
         "internal_handler.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -516,6 +8382,23 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "internal_handler.SetLogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                },
+                "logger": {
+                    "description": "Logger is the logger name, such as \"mongodb\" or \"handler\"; empty changes the root level",
+                    "type": "string",
+                    "example": "mongodb"
+                }
+            }
         }
     }
 }`
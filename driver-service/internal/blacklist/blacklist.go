@@ -0,0 +1,216 @@
+// Package blacklist checks drivers against government or partner blacklists
+// before they are allowed to drive.
+//
+// A checker receives the driver's identifying details and answers at once
+// whether the driver is listed. Answers are cached, since the same driver is
+// checked when created and again when their identity is verified.
+package blacklist
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/cache"
+)
+
+// Checker names accepted by NewChecker
+const (
+	CheckerSandbox = "sandbox"
+	CheckerHTTP    = "http"
+)
+
+// Subject is what a checker needs to look a driver up
+type Subject struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	Phone         string `json:"phone,omitempty"`
+	Plate         string `json:"plate,omitempty"`
+	LicenseNumber string `json:"licenseNumber,omitempty"`
+}
+
+// Result is a checker's answer for a subject
+type Result struct {
+	Listed bool `json:"listed"`
+	// Reason is why the subject is listed, as given by the blacklist
+	Reason string `json:"reason,omitempty"`
+	// Reference identifies the listing at the blacklist, for follow-up
+	Reference string `json:"reference,omitempty"`
+}
+
+// Checker looks drivers up in a blacklist
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, subject *Subject) (*Result, error)
+}
+
+// Options configures the checkers
+type Options struct {
+	// URL is the base URL of the http checker's API
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+	// CacheTTL is how long answers are reused; zero disables caching
+	CacheTTL time.Duration
+}
+
+// NewChecker creates the checker with the given name, caching its answers
+// when opts.CacheTTL is set
+func NewChecker(name string, opts Options) (Checker, error) {
+	var checker Checker
+	switch name {
+	case CheckerSandbox:
+		checker = NewSandboxChecker()
+	case CheckerHTTP:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("a URL is required for the %s blacklist checker", CheckerHTTP)
+		}
+		checker = NewHTTPChecker(opts.URL, opts.APIKey, opts.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown blacklist checker %q", name)
+	}
+	if opts.CacheTTL > 0 {
+		checker = NewCachedChecker(checker, opts.CacheTTL, 10000)
+	}
+	return checker, nil
+}
+
+// SandboxChecker answers without contacting anyone, for development and
+// tests. Subjects whose last name, plate or licence number starts with
+// "BLACKLISTED" are listed, and those starting with "UNAVAILABLE" fail the
+// check so fail-open and fail-closed handling can be tried out.
+type SandboxChecker struct{}
+
+// NewSandboxChecker creates a sandbox checker
+func NewSandboxChecker() *SandboxChecker {
+	return &SandboxChecker{}
+}
+
+// Name returns "sandbox"
+func (c *SandboxChecker) Name() string {
+	return CheckerSandbox
+}
+
+// Check decides from the subject's details
+func (c *SandboxChecker) Check(ctx context.Context, subject *Subject) (*Result, error) {
+	result := &Result{}
+	for _, marker := range []string{subject.LastName, subject.Plate, subject.LicenseNumber} {
+		marker = strings.ToUpper(marker)
+		switch {
+		case strings.HasPrefix(marker, "UNAVAILABLE"):
+			return nil, errors.New("sandbox blacklist is unavailable")
+		case strings.HasPrefix(marker, "BLACKLISTED"):
+			result.Listed, result.Reason, result.Reference = true, "sandbox listing", "sbx_"+marker
+		}
+	}
+	return result, nil
+}
+
+// HTTPChecker talks to a blacklist service over a small JSON API: POST /checks
+// with a Subject returns a Result
+type HTTPChecker struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPChecker creates a checker backed by a blacklist service's HTTP API
+func NewHTTPChecker(baseURL, apiKey string, timeout time.Duration) *HTTPChecker {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &HTTPChecker{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns "http"
+func (c *HTTPChecker) Name() string {
+	return CheckerHTTP
+}
+
+// Check posts the subject to the blacklist service
+func (c *HTTPChecker) Check(ctx context.Context, subject *Subject) (*Result, error) {
+	payload, err := json.Marshal(subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal blacklist subject: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/checks", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blacklist request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call blacklist service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("blacklist service returned status %d", resp.StatusCode)
+	}
+	var result Result
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode blacklist response: %w", err)
+	}
+	return &result, nil
+}
+
+// CachedChecker reuses the answers of another checker. Failed checks are not
+// cached, so the next check asks again.
+type CachedChecker struct {
+	checker Checker
+	results *cache.TTL[string, Result]
+}
+
+// NewCachedChecker caches up to maxSize answers of checker for ttl each
+func NewCachedChecker(checker Checker, ttl time.Duration, maxSize int) *CachedChecker {
+	return &CachedChecker{
+		checker: checker,
+		results: cache.NewTTL[string, Result](ttl, maxSize),
+	}
+}
+
+// Name returns the name of the cached checker
+func (c *CachedChecker) Name() string {
+	return c.checker.Name()
+}
+
+// Check answers from the cache, asking the cached checker on a miss
+func (c *CachedChecker) Check(ctx context.Context, subject *Subject) (*Result, error) {
+	key := subjectKey(subject)
+	if result, ok := c.results.Get(key); ok {
+		return &result, nil
+	}
+	result, err := c.checker.Check(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	c.results.Set(key, *result)
+	return result, nil
+}
+
+// subjectKey identifies a subject in the cache without keeping their
+// details in memory
+func subjectKey(subject *Subject) string {
+	fields := []string{subject.FirstName, subject.LastName, subject.Phone, subject.Plate, subject.LicenseNumber}
+	for i, field := range fields {
+		fields[i] = strings.ToUpper(strings.TrimSpace(field))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,81 @@
+package blacklist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSandboxChecker(t *testing.T) {
+	c := NewSandboxChecker()
+	ctx := context.Background()
+	cases := []struct {
+		subject    Subject
+		wantListed bool
+		wantErr    bool
+	}{
+		{subject: Subject{LastName: "Demir", Plate: "34ABC123"}},
+		{subject: Subject{LastName: "Blacklisted"}, wantListed: true},
+		{subject: Subject{LastName: "Demir", LicenseNumber: "blacklisted-1"}, wantListed: true},
+		{subject: Subject{LastName: "Demir", Plate: "UNAVAILABLE"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		result, err := c.Check(ctx, &tc.subject)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("Check(%+v) err = %v, want error %v", tc.subject, err, tc.wantErr)
+		}
+		if err == nil && result.Listed != tc.wantListed {
+			t.Errorf("Check(%+v) = %+v, want listed %v", tc.subject, result, tc.wantListed)
+		}
+	}
+}
+
+func TestHTTPChecker(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.URL.Path != "/checks" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var subject Subject
+		_ = json.NewDecoder(r.Body).Decode(&subject)
+		if subject.LicenseNumber == "TR-FAIL" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(Result{Listed: subject.LicenseNumber == "TR-2", Reason: "fraud", Reference: "gov-7"})
+	}))
+	defer server.Close()
+
+	checker, err := NewChecker(CheckerHTTP, Options{URL: server.URL + "/", APIKey: "key", CacheTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewChecker: %v", err)
+	}
+	ctx := context.Background()
+
+	result, err := checker.Check(ctx, &Subject{LastName: "Demir", LicenseNumber: "TR-2"})
+	if err != nil || !result.Listed || result.Reference != "gov-7" {
+		t.Fatalf("Check = %+v, %v; want listed with a reference", result, err)
+	}
+	if _, err := checker.Check(ctx, &Subject{LastName: "demir ", LicenseNumber: "tr-2"}); err != nil || calls != 1 {
+		t.Errorf("repeated check: err = %v, %d calls; want the cached answer", err, calls)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := checker.Check(ctx, &Subject{LastName: "Demir", LicenseNumber: "TR-FAIL"}); err == nil {
+			t.Error("expected an error for a failed check")
+		}
+	}
+	if calls != 3 {
+		t.Errorf("expected failed checks to be asked again, got %d calls", calls)
+	}
+
+	if _, err := NewChecker(CheckerHTTP, Options{}); err == nil {
+		t.Error("expected an error without a URL")
+	}
+	if _, err := NewChecker("registry", Options{}); err == nil {
+		t.Error("expected an error for an unknown checker")
+	}
+}
@@ -0,0 +1,92 @@
+// Package cache provides a small in-memory cache with per-entry expiry.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTL is a size-bounded cache whose entries expire after a fixed time-to-live.
+// When full, expired entries are evicted first, then an arbitrary entry.
+type TTL[K comparable, V any] struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[K]entry[V]
+	now     func() time.Time
+}
+
+// NewTTL creates a cache holding at most maxSize entries for ttl each
+func NewTTL[K comparable, V any](ttl time.Duration, maxSize int) *TTL[K, V] {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	return &TTL[K, V]{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[K]entry[V]),
+		now:     time.Now,
+	}
+}
+
+// Get returns the cached value if present and not expired
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores a value, evicting entries if the cache is full
+func (c *TTL[K, V]) Set(key K, value V) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxSize {
+		c.evictLocked(now)
+	}
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Delete removes a key
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// Len returns the number of entries, including expired ones not yet evicted
+func (c *TTL[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *TTL[K, V]) evictLocked(now time.Time) {
+	for key, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.maxSize {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
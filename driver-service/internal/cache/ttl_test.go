@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTL_Expiry(t *testing.T) {
+	now := time.Now()
+	c := NewTTL[string, int](time.Minute, 10)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected cached value, got %v %v", v, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected entry to expire")
+	}
+}
+
+func TestTTL_Eviction(t *testing.T) {
+	now := time.Now()
+	c := NewTTL[string, int](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(30 * time.Second)
+	c.Set("b", 2)
+	now = now.Add(40 * time.Second)
+
+	// "a" has expired and is evicted first
+	c.Set("c", 3)
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 entries, got %d", c.Len())
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Errorf("expected unexpired entry to survive eviction")
+	}
+
+	c.Set("d", 4)
+	if c.Len() != 2 {
+		t.Errorf("expected cache to stay bounded, got %d entries", c.Len())
+	}
+}
+
+func TestTTL_Disabled(t *testing.T) {
+	c := NewTTL[string, int](0, 10)
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected zero TTL to disable caching")
+	}
+}
@@ -0,0 +1,210 @@
+// Package changestream follows the driver change stream and fans every change
+// out to in-process subscribers, such as the geo cache or the events bus.
+//
+// The position in the stream is saved every few seconds and on shutdown, so a
+// restarted instance resumes where it stopped. Changes after the last saved
+// position are delivered again after a crash: delivery is at least once and
+// subscribers must tolerate repeats.
+package changestream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Handler receives a change. Handlers run one after another on the listener
+// goroutine, so they must return quickly; slow work belongs on a queue.
+type Handler func(change *domain.DriverChange)
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Listener follows a change stream on behalf of its subscribers
+type Listener struct {
+	name         string
+	source       domain.DriverChangeSource
+	tokens       domain.ResumeTokenStore
+	saveInterval time.Duration
+	logger       *zap.Logger
+	now          func() time.Time
+
+	// minBackoff and maxBackoff bound the wait before reopening a failed stream
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+// NewListener creates a listener for the stream with the given name; the name
+// keys its saved position, so instances must not share one
+func NewListener(name string, source domain.DriverChangeSource, tokens domain.ResumeTokenStore, saveInterval time.Duration, logger *zap.Logger) *Listener {
+	if saveInterval <= 0 {
+		saveInterval = 5 * time.Second
+	}
+	return &Listener{
+		name:         name,
+		source:       source,
+		tokens:       tokens,
+		saveInterval: saveInterval,
+		logger:       logger,
+		now:          time.Now,
+		minBackoff:   time.Second,
+		maxBackoff:   30 * time.Second,
+	}
+}
+
+// Subscribe registers a handler for every change read from now on
+func (l *Listener) Subscribe(name string, handle Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, subscriber{name: name, handle: handle})
+}
+
+// Run follows the stream until ctx is cancelled, reopening it with a growing
+// delay when it fails. It stops early when the database cannot stream changes.
+func (l *Listener) Run(ctx context.Context) {
+	token, ok := l.loadToken(ctx)
+	if !ok {
+		return
+	}
+	saved := token
+	savedAt := l.now()
+	save := func(ctx context.Context) {
+		if string(token) == string(saved) {
+			return
+		}
+		if err := l.tokens.SaveToken(ctx, l.name, token); err != nil {
+			l.logger.Warn("failed to save change stream position", zap.Error(err))
+			return
+		}
+		saved = token
+		savedAt = l.now()
+	}
+	defer func() {
+		saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		save(saveCtx)
+	}()
+
+	backoff := l.minBackoff
+	for {
+		received := false
+		err := l.source.WatchChanges(ctx, token, func(change *domain.DriverChange, next []byte) error {
+			received = true
+			l.dispatch(change)
+			token = next
+			if l.now().Sub(savedAt) >= l.saveInterval {
+				save(ctx)
+			}
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = l.minBackoff
+		}
+
+		switch {
+		case errors.Is(err, domain.ErrChangeStreamUnsupported):
+			l.logger.Error("change streams unavailable, real-time driver changes are off", zap.Error(err))
+			return
+		case errors.Is(err, domain.ErrChangeHistoryLost):
+			// Subscribers catch up through their own reconciliation
+			l.logger.Warn("change stream position lost, restarting from now", zap.Error(err))
+			token = nil
+			continue
+		case err == nil:
+			err = fmt.Errorf("change stream closed")
+		}
+
+		l.logger.Warn("change stream failed, reopening", zap.Error(err), zap.Duration("backoff", backoff))
+		if !wait(ctx, backoff) {
+			return
+		}
+		if backoff *= 2; backoff > l.maxBackoff {
+			backoff = l.maxBackoff
+		}
+	}
+}
+
+// loadToken reads the saved position, retrying until it succeeds or ctx ends
+func (l *Listener) loadToken(ctx context.Context) ([]byte, bool) {
+	backoff := l.minBackoff
+	for {
+		token, err := l.tokens.LoadToken(ctx, l.name)
+		if err == nil {
+			if token == nil {
+				l.logger.Info("no saved change stream position, starting from now", zap.String("stream", l.name))
+			}
+			return token, true
+		}
+		if ctx.Err() != nil {
+			return nil, false
+		}
+		l.logger.Warn("failed to load change stream position", zap.Error(err), zap.Duration("backoff", backoff))
+		if !wait(ctx, backoff) {
+			return nil, false
+		}
+		if backoff *= 2; backoff > l.maxBackoff {
+			backoff = l.maxBackoff
+		}
+	}
+}
+
+// dispatch hands a change to every subscriber; a panicking subscriber is
+// logged and does not stop the others
+func (l *Listener) dispatch(change *domain.DriverChange) {
+	l.mu.RLock()
+	subscribers := l.subscribers
+	l.mu.RUnlock()
+
+	for _, s := range subscribers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					l.logger.Error("change subscriber panicked",
+						zap.String("subscriber", s.name), zap.String("driverId", change.DriverID), zap.Any("panic", r))
+				}
+			}()
+			s.handle(change)
+		}()
+	}
+}
+
+// wait sleeps for d and reports whether ctx is still live
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// PublishTo returns a handler publishing every change as an analytics event
+func PublishTo(publisher domain.EventPublisher) Handler {
+	return func(change *domain.DriverChange) {
+		event := domain.DriverChanged{
+			DriverID:  change.DriverID,
+			Operation: change.Operation,
+			Deleted:   change.Driver == nil || change.Driver.DeletedAt != nil,
+			Timestamp: change.At,
+		}
+		if change.Driver != nil {
+			event.Suspended = change.Driver.Suspended
+		}
+		publisher.Publish(domain.EventDriverChanged, event)
+	}
+}
@@ -0,0 +1,168 @@
+package changestream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// fakeSource replays one scripted stream per call to WatchChanges
+type fakeSource struct {
+	mu      sync.Mutex
+	streams []fakeStream
+	starts  [][]byte
+	cancel  context.CancelFunc
+}
+
+type fakeStream struct {
+	changes []string
+	err     error
+}
+
+func (s *fakeSource) WatchChanges(ctx interface{}, resumeToken []byte, handle func(change *domain.DriverChange, token []byte) error) error {
+	s.mu.Lock()
+	s.starts = append(s.starts, resumeToken)
+	if len(s.streams) == 0 {
+		s.mu.Unlock()
+		s.cancel()
+		<-ctx.(context.Context).Done()
+		return ctx.(context.Context).Err()
+	}
+	stream := s.streams[0]
+	s.streams = s.streams[1:]
+	s.mu.Unlock()
+
+	for _, id := range stream.changes {
+		if err := handle(&domain.DriverChange{Operation: domain.ChangeUpdate, DriverID: id}, []byte("after-"+id)); err != nil {
+			return err
+		}
+	}
+	return stream.err
+}
+
+type memoryTokens struct {
+	tokens map[string][]byte
+	saves  int
+}
+
+func (m *memoryTokens) LoadToken(ctx interface{}, stream string) ([]byte, error) {
+	return m.tokens[stream], nil
+}
+
+func (m *memoryTokens) SaveToken(ctx interface{}, stream string, token []byte) error {
+	m.saves++
+	if token == nil {
+		delete(m.tokens, stream)
+	} else {
+		m.tokens[stream] = token
+	}
+	return nil
+}
+
+func newTestListener(source *fakeSource, tokens *memoryTokens, saveInterval time.Duration) *Listener {
+	l := NewListener("test", source, tokens, saveInterval, zap.NewNop())
+	l.minBackoff, l.maxBackoff = time.Millisecond, time.Millisecond
+	return l
+}
+
+func run(t *testing.T, l *Listener, source *fakeSource) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	source.cancel = cancel
+	done := make(chan struct{})
+	go func() {
+		l.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener did not stop")
+	}
+}
+
+func TestListener_ResumesAfterFailure(t *testing.T) {
+	source := &fakeSource{streams: []fakeStream{
+		{changes: []string{"d1", "d2"}, err: errors.New("connection reset")},
+		{changes: []string{"d3"}},
+	}}
+	tokens := &memoryTokens{tokens: map[string][]byte{"test": []byte("saved")}}
+	l := newTestListener(source, tokens, time.Hour)
+
+	var got []string
+	l.Subscribe("panics", func(change *domain.DriverChange) { panic("boom") })
+	l.Subscribe("records", func(change *domain.DriverChange) { got = append(got, change.DriverID) })
+	run(t, l, source)
+
+	if len(got) != 3 || got[0] != "d1" || got[2] != "d3" {
+		t.Errorf("delivered %v, want [d1 d2 d3]", got)
+	}
+	wantStarts := []string{"saved", "after-d2", "after-d3"}
+	for i, want := range wantStarts {
+		if i >= len(source.starts) || string(source.starts[i]) != want {
+			t.Fatalf("stream starts = %q, want %q", source.starts, wantStarts)
+		}
+	}
+	// The position is only saved on shutdown with a long save interval
+	if tokens.saves != 1 || string(tokens.tokens["test"]) != "after-d3" {
+		t.Errorf("saved %d times, token %q; want once, after-d3", tokens.saves, tokens.tokens["test"])
+	}
+}
+
+func TestListener_HistoryLost(t *testing.T) {
+	source := &fakeSource{streams: []fakeStream{
+		{err: domain.ErrChangeHistoryLost},
+	}}
+	tokens := &memoryTokens{tokens: map[string][]byte{"test": []byte("too-old")}}
+	l := newTestListener(source, tokens, 0)
+	run(t, l, source)
+
+	if len(source.starts) != 2 || source.starts[1] != nil {
+		t.Fatalf("stream starts = %q, want a restart from now", source.starts)
+	}
+	if _, ok := tokens.tokens["test"]; ok {
+		t.Error("the lost position was kept")
+	}
+}
+
+func TestListener_Unsupported(t *testing.T) {
+	source := &fakeSource{streams: []fakeStream{
+		{err: domain.ErrChangeStreamUnsupported},
+		{changes: []string{"d1"}},
+	}}
+	l := newTestListener(source, &memoryTokens{tokens: map[string][]byte{}}, 0)
+	source.cancel = func() {}
+	l.Run(context.Background())
+
+	if len(source.starts) != 1 {
+		t.Errorf("stream opened %d times, want once", len(source.starts))
+	}
+}
+
+type recordingPublisher struct {
+	events []interface{}
+}
+
+func (p *recordingPublisher) Publish(eventType string, data interface{}) {
+	p.events = append(p.events, data)
+}
+
+func TestPublishTo(t *testing.T) {
+	p := &recordingPublisher{}
+	handle := PublishTo(p)
+	handle(&domain.DriverChange{Operation: domain.ChangeUpdate, DriverID: "d1", Driver: &domain.Driver{Suspended: true}})
+	handle(&domain.DriverChange{Operation: domain.ChangeDelete, DriverID: "d2"})
+
+	first, second := p.events[0].(domain.DriverChanged), p.events[1].(domain.DriverChanged)
+	if !first.Suspended || first.Deleted {
+		t.Errorf("update event = %+v", first)
+	}
+	if !second.Deleted {
+		t.Errorf("delete event = %+v", second)
+	}
+}
@@ -2,34 +2,129 @@ package config
 
 import (
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all configuration for the driver service
 type Config struct {
-	Server  ServerConfig
-	MongoDB MongoDBConfig
-	Logging LoggingConfig
-	JWT     JWTConfig
+	Server       ServerConfig
+	MongoDB      MongoDBConfig
+	Logging      LoggingConfig
+	JWT          JWTConfig
+	CORS         CORSConfig
+	Compression  CompressionConfig
+	Verification VerificationConfig
+	SMS          SMSConfig
+	Matching     MatchingConfig
+	Encryption   EncryptionConfig
+	Stats        StatsConfig
+	Heartbeat    HeartbeatConfig
+	Webhooks     WebhookConfig
+	Pagination   PaginationConfig
+	Licenses     LicenseConfig
+	Schedule     ScheduleConfig
+	Reports      ReportsConfig
+	Retention    RetentionConfig
+	Jobs         JobsConfig
+	Stream       StreamConfig
+	Routing      RoutingConfig
+	Fares        FareConfig
+	Earnings     EarningsConfig
+	Storage      StorageConfig
+	Photos       PhotoConfig
+	Validation   ValidationConfig
+	ServiceArea  ServiceAreaConfig
+	Plausibility PlausibilityConfig
+	Analytics    AnalyticsConfig
+	GeoCache     GeoCacheConfig
+	ChangeStream ChangeStreamConfig
+	KYC          KYCConfig
+	Blacklist    BlacklistConfig
+	MQTT         MQTTConfig
+	Autocomplete AutocompleteConfig
+	Cities       CitiesConfig
+	BulkUpdate   BulkUpdateConfig
+	TaxiTypes    TaxiTypesConfig
+	Metrics      MetricsConfig
+	ShadowReads  ShadowReadsConfig
+	Geohash      GeohashConfig
+	ServiceToken ServiceTokenConfig
+	Warmup       WarmupConfig
+	Quotas       QuotaConfig
+	ErrorReports ErrorReportConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port         string
+	Port string
+	// AdminPort moves /health, /metrics, pprof and the /admin endpoints to a
+	// second listener so only the API is served on Port; empty serves
+	// everything but pprof and /metrics on Port
+	AdminPort    string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections that sit unused
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	// MaxInFlight bounds the requests served at once; 0 disables the limit
+	MaxInFlight int
+	// OverloadRetryAfter is the Retry-After sent with 503s while saturated
+	OverloadRetryAfter time.Duration
 }
 
 // MongoDBConfig holds MongoDB configuration
 type MongoDBConfig struct {
 	URI      string
 	Database string
+	// RetryAttempts bounds how often an operation is tried when MongoDB fails
+	// transiently, e.g. during a replica-set election
+	RetryAttempts  int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// SlowQueryThreshold is how long a command may take before it is logged
+	// with its redacted filter; zero turns the slow query log off
+	SlowQueryThreshold time.Duration
+	// ReadPreference picks the members serving requests that only read and
+	// WriteReadPreference those serving the reads of requests that write,
+	// e.g. "primary", "nearest" or "secondaryPreferred"
+	ReadPreference      string
+	WriteReadPreference string
+	// ReadTagSets narrow the secondaries reads may go to, tried in order; each
+	// set is "name:value" pairs separated by commas, e.g. "region:eu-west,zone:a"
+	ReadTagSets []string
+	// ReadMaxStaleness keeps reads off secondaries lagging further behind the
+	// primary; zero leaves it unbounded
+	ReadMaxStaleness time.Duration
+	// EnsureIndexes creates missing indexes at startup; turn it off where index
+	// builds on large collections are run by hand or through the admin API
+	EnsureIndexes bool
+	// GeoNear answers nearby searches with $geoNear on the position index, so
+	// MongoDB sorts and pages them; run the positions command before enabling it
+	GeoNear bool
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level string
+	// Encoding is "json" or "console"; empty picks console for debug and json otherwise
+	Encoding string
+	// Outputs are "stdout", "stderr" or file paths; files are rotated
+	Outputs []string
+	// Levels overrides the level of named loggers, e.g. "mongodb" -> "debug"
+	Levels   map[string]string
+	Rotation LogRotationConfig
+}
+
+// LogRotationConfig controls rotation of file log outputs
+type LogRotationConfig struct {
+	MaxSizeMB int
+	// MaxAgeDays and MaxBackups of 0 keep rotated files forever
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
 }
 
 // JWTConfig holds JWT configuration
@@ -37,27 +132,1096 @@ type JWTConfig struct {
 	Secret string
 }
 
+// QuotaConfig caps the drivers each tenant (fleet) pays seats for
+type QuotaConfig struct {
+	// MaxDrivers is the quota of fleets without one of their own; 0 is unlimited
+	MaxDrivers int
+	// Tenants are quotas by fleet ID; 0 makes a fleet unlimited
+	Tenants map[string]int
+	// Thresholds are the usage percentages that emit an event when reached
+	Thresholds []int
+}
+
+// Enabled reports whether any fleet has a quota
+func (c QuotaConfig) Enabled() bool {
+	if c.MaxDrivers > 0 {
+		return true
+	}
+	for _, max := range c.Tenants {
+		if max > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrorReportConfig sends errors and panics to a Sentry-compatible error tracker
+type ErrorReportConfig struct {
+	// DSN is the tracker's project DSN; empty disables reporting
+	DSN         string
+	Environment string
+	// Release defaults to the VCS revision the binary was built from
+	Release string
+	// SampleRate is the share of errors sent, from 0 to 1
+	SampleRate float64
+	// Loggers are the loggers whose error entries are reported, with the
+	// loggers below them; panics are always reported
+	Loggers []string
+	// ScrubFields are field names whose values are never sent, on top of the defaults
+	ScrubFields []string
+}
+
+// CORSConfig holds CORS policy configuration
+type CORSConfig struct {
+	// AllowedOrigins may contain "*" or wildcard subdomain patterns such as "https://*.bitaksi.com"
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CompressionConfig holds response compression configuration
+type CompressionConfig struct {
+	Enabled bool
+	// MinSize is the smallest body in bytes that is compressed
+	MinSize int
+	// Level is a gzip level from 1 (fastest) to 9 (smallest); -1 uses the default
+	Level int
+	// ContentTypes lists compressible media types; "text/*" matches every text type
+	ContentTypes []string
+	// Requests accepts gzip request bodies and advertises it on every response
+	Requests bool
+	// MaxRequestSize bounds a decoded request body in bytes
+	MaxRequestSize int64
+}
+
+// VerificationConfig holds phone verification (OTP) configuration
+type VerificationConfig struct {
+	CodeLength     int
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+}
+
+// SMSConfig holds SMS provider configuration
+type SMSConfig struct {
+	Provider string // "log" or "http"
+	URL      string
+	APIKey   string
+	Sender   string
+}
+
+// MatchingConfig holds trip dispatch configuration
+type MatchingConfig struct {
+	Strategy       string // "nearest", "best_rating" or "round_robin"
+	SearchRadiusKm float64
+	OfferTimeout   time.Duration
+	MaxOffers      int
+	RatingRadiusKm float64
+	ZoneSizeDeg    float64
+	SweepInterval  time.Duration
+}
+
+// StatsConfig holds driver statistics configuration
+type StatsConfig struct {
+	CacheTTL time.Duration
+}
+
+// HeartbeatConfig holds driver liveness configuration
+type HeartbeatConfig struct {
+	// Timeout is how long after the latest heartbeat a driver still counts as online
+	Timeout time.Duration
+	// FilterNearby excludes offline drivers from nearby searches unless a request sets live=false
+	FilterNearby bool
+}
+
+// PaginationConfig holds list paging limits
+type PaginationConfig struct {
+	// DefaultPageSize applies when a request does not set pageSize
+	DefaultPageSize int
+	// MaxPageSize caps the pageSize a request may ask for
+	MaxPageSize int
+	// DefaultNearbyLimit is how many drivers a nearby search returns when the request does not set limit
+	DefaultNearbyLimit int
+	// MaxNearbyLimit caps the limit a nearby search may ask for
+	MaxNearbyLimit int
+}
+
+// RoutingConfig selects how distances between locations are measured
+type RoutingConfig struct {
+	Provider string // "haversine" or "osrm"
+	OSRMURL  string
+	// OSRMProfile is the OSRM routing profile, usually "driving"
+	OSRMProfile string
+	Timeout     time.Duration
+	// SpeedKmh estimates straight-line durations, and OSRM durations while it is unavailable
+	SpeedKmh float64
+}
+
+// FareConfig holds the tariff fares are estimated with
+type FareConfig struct {
+	Base      float64
+	PerKm     float64
+	PerMinute float64
+	Minimum   float64
+	Currency  string
+	// Multipliers scale the fare per taxi type, e.g. "siyah" -> 2
+	Multipliers map[string]float64
+}
+
+// EarningsConfig holds driver earnings and payout configuration
+type EarningsConfig struct {
+	// CommissionRate is the share of each fare kept by the platform, between 0 and 1
+	CommissionRate float64
+	// Timezone is the IANA zone daily and weekly earnings are bucketed in
+	Timezone string
+}
+
+// StorageConfig selects where uploaded files are kept
+type StorageConfig struct {
+	Provider string // "local"
+	// Dir is where the local provider writes files; they are served under /media
+	Dir string
+	// BaseURL prefixes stored file keys in returned URLs; point it at a CDN in front of /media
+	BaseURL string
+}
+
+// PhotoConfig bounds driver photo uploads and sets the sizes they are stored in
+type PhotoConfig struct {
+	MaxBytes     int64
+	MinDimension int
+	MaxDimension int
+	// FullSize is the longest side of the full-size copy
+	FullSize       int
+	ThumbnailSizes []int
+	Quality        int
+}
+
+// ValidationConfig locates the driver field validation rules
+type ValidationConfig struct {
+	// RulesFile is a JSON file of per-country and per-tenant rules; empty keeps the built-in Turkish rules
+	RulesFile string
+	// Country is the market the service runs in; empty takes it from the rules file, or TR
+	Country string
+}
+
+// ServiceAreaConfig controls how stored driver positions are normalized
+type ServiceAreaConfig struct {
+	// Enabled rounds positions and rejects 0,0 and positions outside the regions
+	Enabled bool
+	// File is a JSON file of region polygons; empty uses the built-in outline of Turkey
+	File string
+	// Precision is the number of decimals positions are rounded to
+	Precision int
+}
+
+// CitiesConfig controls the city drivers are stored with
+type CitiesConfig struct {
+	// Enabled stores drivers with their city and scopes nearby searches to
+	// the cities they reach
+	Enabled bool
+	// File is a JSON file of city polygons; empty uses the built-in outlines
+	File string
+}
+
+// TaxiTypesConfig holds the taxi type registry configuration
+type TaxiTypesConfig struct {
+	// File is a JSON file of taxi types; empty uses the built-in types
+	File string
+}
+
+// MetricsConfig holds the business metrics configuration
+type MetricsConfig struct {
+	// StatusRefresh is how long drivers per status are reused before they are
+	// counted again, so frequent scrapes do not each scan the drivers
+	StatusRefresh time.Duration
+}
+
+// WarmupConfig controls what the service waits for after startup before it
+// reports ready on /ready
+type WarmupConfig struct {
+	// RequireIndexes waits until every index the repositories rely on exists
+	// with the expected keys
+	RequireIndexes bool
+	// GeoCache waits for the first load of the geo cache when it is enabled
+	GeoCache bool
+	// RetryInterval is how often a check that failed is tried again
+	RetryInterval time.Duration
+}
+
+// GeohashConfig controls the geohash rendered beside coordinates
+type GeohashConfig struct {
+	// Precision is the number of geohash characters, at most 12; 6 is a
+	// cell of about 1.2km by 0.6km. 0 leaves geohashes out of responses
+	Precision int
+}
+
+// ServiceTokenConfig controls the tokens internal services call the service
+// with directly, exchanged at the gateway for a user's access token
+type ServiceTokenConfig struct {
+	// Secret validates the tokens, and those the gateway signs its own calls
+	// with, and must match the gateway's TOKEN_EXCHANGE_SECRET. When set every
+	// request but health checks and the KYC webhook needs one of them; empty
+	// ignores bearer tokens, leaving requests to the identity headers
+	Secret string
+}
+
+// ShadowReadsConfig controls mirroring driver reads to the backend a migration
+// moves to, to compare its answers before switching
+type ShadowReadsConfig struct {
+	Enabled bool
+	// Backend is the kind of store mirrored to; only "mongodb" is available
+	Backend string
+	// URI and Database locate the shadow MongoDB
+	URI      string
+	Database string
+	// SampleRate is the share of reads mirrored, from 0 to 1
+	SampleRate  float64
+	Timeout     time.Duration
+	MaxInFlight int
+}
+
+// BulkUpdateConfig holds the fleet-wide driver update configuration
+type BulkUpdateConfig struct {
+	// MaxDrivers is the most drivers a single bulk update may change
+	MaxDrivers int
+}
+
+// PlausibilityConfig controls the checks that catch spoofed GPS positions
+type PlausibilityConfig struct {
+	// MaxSpeedKmh is the fastest speed a location update may imply; 0 disables the check
+	MaxSpeedKmh float64
+	// MinDistanceKm is the shortest jump checked, so GPS jitter is ignored
+	MinDistanceKm float64
+	// Action is "flag" to record anomalous updates and "reject" to also refuse them
+	Action string
+}
+
+// AnalyticsConfig controls the analytics events sent for demand heatmaps
+type AnalyticsConfig struct {
+	// SearchEvents reports anonymized nearby searches; turn it off to opt out
+	SearchEvents bool
+	// SampleRate is the share of searches reported, from 0 to 1
+	SampleRate float64
+	// Precision is the number of decimal places search coordinates are rounded to
+	Precision int
+	Sink      string // "log" or "http"
+	// URL receives batches of events with the http sink
+	URL           string
+	BufferSize    int
+	FlushInterval time.Duration
+	Timeout       time.Duration
+}
+
+// GeoCacheConfig controls the in-memory index of driver positions that
+// answers nearby searches without scanning MongoDB
+type GeoCacheConfig struct {
+	Enabled bool
+	// CellKm is the size of the grid cells drivers are bucketed into
+	CellKm float64
+	// PollInterval is how often changes made by other instances are picked up
+	PollInterval time.Duration
+	// ReconcileInterval is how often the index is rebuilt from MongoDB
+	ReconcileInterval time.Duration
+	// MaxStaleness is how long after the last successful refresh the index is
+	// still used; searches go to MongoDB once it is older
+	MaxStaleness time.Duration
+}
+
+// ChangeStreamConfig controls the listener that follows writes to drivers in
+// MongoDB and hands them to in-process subscribers
+type ChangeStreamConfig struct {
+	// Enabled needs MongoDB to run as a replica set
+	Enabled bool
+	// Name keys the saved stream position; every instance needs its own
+	Name string
+	// SaveInterval is how often the stream position is saved
+	SaveInterval time.Duration
+	// PublishEvents sends every change to the analytics sink
+	PublishEvents bool
+}
+
+// KYCConfig holds the driver identity verification provider settings
+type KYCConfig struct {
+	// Provider is "sandbox" or "http"
+	Provider string
+	URL      string
+	APIKey   string
+	// WebhookSecret verifies the provider's webhook calls; webhooks are refused without it
+	WebhookSecret string
+	// PollInterval is how often pending checks are polled; zero relies on webhooks alone
+	PollInterval time.Duration
+	Timeout      time.Duration
+}
+
+// BlacklistConfig holds the driver blacklist check settings
+type BlacklistConfig struct {
+	// Checker is "sandbox" or "http"; empty disables the check
+	Checker string
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+	// CacheTTL is how long answers are reused; zero asks every time
+	CacheTTL time.Duration
+	// FailMode is "open" to let drivers through while the blacklist cannot be
+	// reached and "closed" to refuse them
+	FailMode string
+}
+
+// MQTTConfig holds the MQTT bridge for driver telemetry configuration
+type MQTTConfig struct {
+	Enabled bool
+	// BrokerURL is e.g. tcp://mqtt:1883 or ssl://mqtt:8883
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	// TopicPrefix is the first level of <prefix>/<driverId>/location and
+	// <prefix>/<driverId>/heartbeat
+	TopicPrefix string
+	// QoS is the quality of service the topics are subscribed with, 0 to 2
+	QoS int
+	// AuthCacheTTL is how long a verified device token is trusted before it
+	// is checked again
+	AuthCacheTTL time.Duration
+}
+
+// AutocompleteConfig holds the driver autocomplete configuration
+type AutocompleteConfig struct {
+	// Budget bounds the time a search may spend in MongoDB; slower searches
+	// return no suggestions with timedOut set
+	Budget time.Duration
+	// DefaultLimit and MaxLimit bound the suggestions per search
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// LicenseConfig holds driver licence expiry tracking configuration
+type LicenseConfig struct {
+	// CheckInterval is how often drivers with an expired licence are taken off dispatch
+	CheckInterval time.Duration
+}
+
+// ScheduleConfig holds the planned shift scheduler configuration
+type ScheduleConfig struct {
+	// CheckInterval is how often drivers are put on and off their planned shifts
+	CheckInterval time.Duration
+}
+
+// ReportsConfig holds the operational report configuration
+type ReportsConfig struct {
+	// RollupInterval is how often the daily utilization rollups are recomputed
+	RollupInterval time.Duration
+	// RollupDays is how many complete days every rollup recomputes
+	RollupDays int
+}
+
+// RetentionConfig holds the personal data retention windows
+type RetentionConfig struct {
+	// DeletedDriverWindow is how long a deleted driver's remaining personal data
+	// is kept before the retention job erases it
+	DeletedDriverWindow time.Duration
+	// LocationHistoryWindow is how long location history is kept; it cannot
+	// exceed the 400 days the TTL index keeps points
+	LocationHistoryWindow time.Duration
+	// Interval is how often the retention job runs; zero disables it
+	Interval time.Duration
+}
+
+// JobsConfig holds the background job runner configuration used by exports
+type JobsConfig struct {
+	// Dir is where job results such as export files are written
+	Dir string
+	// Workers is how many jobs run at once
+	Workers int
+	// QueueSize is how many jobs may wait for a worker before new ones are refused
+	QueueSize int
+	// Retention is how long finished jobs and their results are kept
+	Retention time.Duration
+}
+
+// StreamConfig holds the settings of the NDJSON driver stream
+type StreamConfig struct {
+	// FlushInterval is how often streamed drivers are sent on to the client
+	FlushInterval time.Duration
+	// WriteTimeout bounds each flush; it replaces the server's write timeout,
+	// which would otherwise cut off long streams
+	WriteTimeout time.Duration
+}
+
+// WebhookConfig holds outbound webhook delivery configuration
+type WebhookConfig struct {
+	// MaxAttempts is the number of attempts before a delivery is marked failed
+	MaxAttempts int
+	// BackoffBase is the delay before the first retry; it doubles with every attempt up to BackoffMax
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+	Timeout       time.Duration
+	SweepInterval time.Duration
+	// AllowPrivateTargets lets endpoints be loopback, private or link-local
+	// addresses, for local development; off, they are refused at
+	// registration and when connecting
+	AllowPrivateTargets bool
+}
+
+// EncryptionConfig holds field encryption configuration. Encryption is disabled when no keys are set.
+type EncryptionConfig struct {
+	// Keys maps key IDs to base64 data keys, or to KMS ciphertexts when KMS is set
+	Keys      map[string]string
+	ActiveKey string
+	HashKey   string // base64 key for phone lookup hashes
+	KMS       string // "" or "vault"
+	Vault     VaultConfig
+}
+
+// VaultConfig holds HashiCorp Vault transit configuration
+type VaultConfig struct {
+	Addr       string
+	Token      string
+	TransitKey string
+}
+
+// Enabled reports whether field encryption is configured
+func (c EncryptionConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	readTimeout, _ := strconv.Atoi(getEnv("READ_TIMEOUT_SEC", "30"))
 	writeTimeout, _ := strconv.Atoi(getEnv("WRITE_TIMEOUT_SEC", "30"))
+	idleTimeout, _ := strconv.Atoi(getEnv("IDLE_TIMEOUT_SEC", "120"))
+	maxHeaderBytes, _ := strconv.Atoi(getEnv("MAX_HEADER_BYTES", "1048576"))
+	maxInFlight, _ := strconv.Atoi(getEnv("MAX_IN_FLIGHT_REQUESTS", "1000"))
+	overloadRetryAfter, _ := strconv.Atoi(getEnv("OVERLOAD_RETRY_AFTER_SEC", "1"))
+	logLevel := getEnv("LOG_LEVEL", "info")
+	otpLength, _ := strconv.Atoi(getEnv("OTP_CODE_LENGTH", "6"))
+	otpTTL, _ := strconv.Atoi(getEnv("OTP_TTL_SEC", "300"))
+	otpMaxAttempts, _ := strconv.Atoi(getEnv("OTP_MAX_ATTEMPTS", "5"))
+	otpResendCooldown, _ := strconv.Atoi(getEnv("OTP_RESEND_COOLDOWN_SEC", "60"))
+	matchRadius, _ := strconv.ParseFloat(getEnv("MATCHING_SEARCH_RADIUS_KM", "6"), 64)
+	offerTimeout, _ := strconv.Atoi(getEnv("MATCHING_OFFER_TIMEOUT_SEC", "15"))
+	maxOffers, _ := strconv.Atoi(getEnv("MATCHING_MAX_OFFERS", "5"))
+	ratingRadius, _ := strconv.ParseFloat(getEnv("MATCHING_RATING_RADIUS_KM", "3"), 64)
+	zoneSize, _ := strconv.ParseFloat(getEnv("MATCHING_ZONE_SIZE_DEG", "0.02"), 64)
+	sweepInterval, _ := strconv.Atoi(getEnv("MATCHING_SWEEP_INTERVAL_MS", "1000"))
+	statsCacheTTL, _ := strconv.Atoi(getEnv("STATS_CACHE_TTL_SEC", "60"))
+	heartbeatTimeout, _ := strconv.Atoi(getEnv("HEARTBEAT_TIMEOUT_SEC", "120"))
+	mongoRetryAttempts, _ := strconv.Atoi(getEnv("MONGODB_RETRY_ATTEMPTS", "4"))
+	mongoRetryBase, _ := strconv.Atoi(getEnv("MONGODB_RETRY_BASE_MS", "100"))
+	mongoRetryMax, _ := strconv.Atoi(getEnv("MONGODB_RETRY_MAX_MS", "2000"))
+	mongoSlowQuery, _ := strconv.Atoi(getEnv("MONGODB_SLOW_QUERY_MS", "100"))
+	mongoMaxStaleness, _ := strconv.Atoi(getEnv("MONGODB_READ_MAX_STALENESS_SEC", "0"))
+	webhookAttempts, _ := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	webhookBackoffBase, _ := strconv.Atoi(getEnv("WEBHOOK_BACKOFF_BASE_SEC", "10"))
+	webhookBackoffMax, _ := strconv.Atoi(getEnv("WEBHOOK_BACKOFF_MAX_SEC", "3600"))
+	webhookTimeout, _ := strconv.Atoi(getEnv("WEBHOOK_TIMEOUT_SEC", "10"))
+	webhookSweep, _ := strconv.Atoi(getEnv("WEBHOOK_SWEEP_INTERVAL_MS", "2000"))
+	defaultPageSize, _ := strconv.Atoi(getEnv("DEFAULT_PAGE_SIZE", "20"))
+	maxPageSize, _ := strconv.Atoi(getEnv("MAX_PAGE_SIZE", "100"))
+	defaultNearbyLimit, _ := strconv.Atoi(getEnv("NEARBY_DEFAULT_LIMIT", "100"))
+	maxNearbyLimit, _ := strconv.Atoi(getEnv("NEARBY_MAX_LIMIT", "100"))
+	licenseCheckInterval, _ := strconv.Atoi(getEnv("LICENSE_CHECK_INTERVAL_MIN", "60"))
+	scheduleCheckInterval, _ := strconv.Atoi(getEnv("SCHEDULE_CHECK_INTERVAL_SEC", "60"))
+	rollupInterval, _ := strconv.Atoi(getEnv("UTILIZATION_ROLLUP_INTERVAL_MIN", "60"))
+	rollupDays, _ := strconv.Atoi(getEnv("UTILIZATION_ROLLUP_DAYS", "3"))
+	commissionRate, _ := strconv.ParseFloat(getEnv("EARNINGS_COMMISSION_RATE", "0.15"), 64)
 
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8081"),
-			ReadTimeout:  time.Duration(readTimeout) * time.Second,
-			WriteTimeout: time.Duration(writeTimeout) * time.Second,
+			Port:               getEnv("PORT", "8081"),
+			AdminPort:          getEnv("ADMIN_PORT", ""),
+			ReadTimeout:        time.Duration(readTimeout) * time.Second,
+			WriteTimeout:       time.Duration(writeTimeout) * time.Second,
+			IdleTimeout:        time.Duration(idleTimeout) * time.Second,
+			MaxHeaderBytes:     maxHeaderBytes,
+			MaxInFlight:        maxInFlight,
+			OverloadRetryAfter: time.Duration(overloadRetryAfter) * time.Second,
 		},
 		MongoDB: MongoDBConfig{
-			URI:      getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-			Database: getEnv("MONGODB_DATABASE", "taxihub"),
-		},
-		Logging: LoggingConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
+			URI:                 getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+			Database:            getEnv("MONGODB_DATABASE", "taxihub"),
+			RetryAttempts:       mongoRetryAttempts,
+			RetryBaseDelay:      time.Duration(mongoRetryBase) * time.Millisecond,
+			RetryMaxDelay:       time.Duration(mongoRetryMax) * time.Millisecond,
+			SlowQueryThreshold:  time.Duration(mongoSlowQuery) * time.Millisecond,
+			ReadPreference:      getEnv("MONGODB_READ_PREFERENCE", "primary"),
+			WriteReadPreference: getEnv("MONGODB_WRITE_READ_PREFERENCE", "primary"),
+			ReadTagSets:         splitTagSets(getEnv("MONGODB_READ_TAG_SETS", "")),
+			ReadMaxStaleness:    time.Duration(mongoMaxStaleness) * time.Second,
+			EnsureIndexes:       getEnv("MONGODB_ENSURE_INDEXES", "true") == "true",
+			GeoNear:             getEnv("MONGODB_GEO_NEAR", "false") == "true",
 		},
+		Logging: loadLoggingConfig(logLevel),
 		JWT: JWTConfig{
 			Secret: getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
 		},
+		CORS:        loadCORSConfig(logLevel == "debug"),
+		Compression: loadCompressionConfig(),
+		Verification: VerificationConfig{
+			CodeLength:     otpLength,
+			CodeTTL:        time.Duration(otpTTL) * time.Second,
+			MaxAttempts:    otpMaxAttempts,
+			ResendCooldown: time.Duration(otpResendCooldown) * time.Second,
+		},
+		SMS: SMSConfig{
+			Provider: getEnv("SMS_PROVIDER", "log"),
+			URL:      getEnv("SMS_HTTP_URL", ""),
+			APIKey:   getEnv("SMS_HTTP_API_KEY", ""),
+			Sender:   getEnv("SMS_SENDER", "TaxiHub"),
+		},
+		Matching: MatchingConfig{
+			Strategy:       getEnv("MATCHING_STRATEGY", "nearest"),
+			SearchRadiusKm: matchRadius,
+			OfferTimeout:   time.Duration(offerTimeout) * time.Second,
+			MaxOffers:      maxOffers,
+			RatingRadiusKm: ratingRadius,
+			ZoneSizeDeg:    zoneSize,
+			SweepInterval:  time.Duration(sweepInterval) * time.Millisecond,
+		},
+		Encryption: loadEncryptionConfig(),
+		Stats: StatsConfig{
+			CacheTTL: time.Duration(statsCacheTTL) * time.Second,
+		},
+		Heartbeat: HeartbeatConfig{
+			Timeout:      time.Duration(heartbeatTimeout) * time.Second,
+			FilterNearby: getEnv("HEARTBEAT_FILTER_NEARBY", "false") == "true",
+		},
+		Webhooks: WebhookConfig{
+			MaxAttempts:         webhookAttempts,
+			BackoffBase:         time.Duration(webhookBackoffBase) * time.Second,
+			BackoffMax:          time.Duration(webhookBackoffMax) * time.Second,
+			Timeout:             time.Duration(webhookTimeout) * time.Second,
+			SweepInterval:       time.Duration(webhookSweep) * time.Millisecond,
+			AllowPrivateTargets: getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false") == "true",
+		},
+		Pagination: PaginationConfig{
+			DefaultPageSize:    defaultPageSize,
+			MaxPageSize:        maxPageSize,
+			DefaultNearbyLimit: defaultNearbyLimit,
+			MaxNearbyLimit:     maxNearbyLimit,
+		},
+		Licenses: LicenseConfig{
+			CheckInterval: time.Duration(licenseCheckInterval) * time.Minute,
+		},
+		Schedule: ScheduleConfig{
+			CheckInterval: time.Duration(scheduleCheckInterval) * time.Second,
+		},
+		Reports: ReportsConfig{
+			RollupInterval: time.Duration(rollupInterval) * time.Minute,
+			RollupDays:     rollupDays,
+		},
+		Retention: loadRetentionConfig(),
+		Jobs:      loadJobsConfig(),
+		Stream:    loadStreamConfig(),
+		Routing:   loadRoutingConfig(),
+		Fares:     loadFareConfig(),
+		Storage: StorageConfig{
+			Provider: getEnv("STORAGE_PROVIDER", "local"),
+			Dir:      getEnv("STORAGE_DIR", "./data/media"),
+			BaseURL:  getEnv("STORAGE_BASE_URL", "http://localhost:8081/media"),
+		},
+		Photos: loadPhotoConfig(),
+		Validation: ValidationConfig{
+			RulesFile: getEnv("VALIDATION_RULES_FILE", ""),
+			Country:   getEnv("VALIDATION_COUNTRY", ""),
+		},
+		Earnings: EarningsConfig{
+			CommissionRate: commissionRate,
+			Timezone:       getEnv("EARNINGS_TIMEZONE", "Europe/Istanbul"),
+		},
+		ServiceArea:  loadServiceAreaConfig(),
+		Plausibility: loadPlausibilityConfig(),
+		Analytics:    loadAnalyticsConfig(),
+		GeoCache:     loadGeoCacheConfig(),
+		ChangeStream: loadChangeStreamConfig(),
+		KYC:          loadKYCConfig(),
+		Blacklist:    loadBlacklistConfig(),
+		MQTT:         loadMQTTConfig(),
+		Autocomplete: loadAutocompleteConfig(),
+		Cities: CitiesConfig{
+			Enabled: getEnv("CITIES_ENABLED", "true") == "true",
+			File:    getEnv("CITIES_FILE", ""),
+		},
+		BulkUpdate: loadBulkUpdateConfig(),
+		TaxiTypes: TaxiTypesConfig{
+			File: getEnv("TAXI_TYPES_FILE", ""),
+		},
+		Metrics:     loadMetricsConfig(),
+		ShadowReads: loadShadowReadsConfig(),
+		Geohash:     loadGeohashConfig(),
+		Warmup:      loadWarmupConfig(),
+		ServiceToken: ServiceTokenConfig{
+			Secret: getEnv("TOKEN_EXCHANGE_SECRET", ""),
+		},
+		Quotas:       loadQuotaConfig(),
+		ErrorReports: loadErrorReportConfig(),
+	}
+}
+
+// loadAnalyticsConfig loads the analytics event settings
+func loadServiceAreaConfig() ServiceAreaConfig {
+	precision, err := strconv.Atoi(getEnv("LOCATION_PRECISION", "6"))
+	if err != nil {
+		precision = 6
+	}
+
+	return ServiceAreaConfig{
+		Enabled:   getEnv("SERVICE_AREA_ENABLED", "true") == "true",
+		File:      getEnv("SERVICE_AREA_FILE", ""),
+		Precision: precision,
+	}
+}
+
+func loadPlausibilityConfig() PlausibilityConfig {
+	maxSpeed, err := strconv.ParseFloat(getEnv("LOCATION_MAX_SPEED_KMH", "250"), 64)
+	if err != nil {
+		maxSpeed = 250
+	}
+	minDistance, err := strconv.ParseFloat(getEnv("LOCATION_JUMP_MIN_DISTANCE_M", "500"), 64)
+	if err != nil {
+		minDistance = 500
+	}
+
+	return PlausibilityConfig{
+		MaxSpeedKmh:   maxSpeed,
+		MinDistanceKm: minDistance / 1000,
+		Action:        getEnv("LOCATION_JUMP_ACTION", "flag"),
+	}
+}
+
+func loadAnalyticsConfig() AnalyticsConfig {
+	sampleRate, _ := strconv.ParseFloat(getEnv("ANALYTICS_SEARCH_SAMPLE_RATE", "0.1"), 64)
+	precision, _ := strconv.Atoi(getEnv("ANALYTICS_COORDINATE_PRECISION", "2"))
+	bufferSize, _ := strconv.Atoi(getEnv("ANALYTICS_BUFFER_SIZE", "10000"))
+	flushInterval, _ := strconv.Atoi(getEnv("ANALYTICS_FLUSH_INTERVAL_SEC", "5"))
+	timeout, _ := strconv.Atoi(getEnv("ANALYTICS_TIMEOUT_SEC", "5"))
+
+	return AnalyticsConfig{
+		SearchEvents:  getEnv("ANALYTICS_SEARCH_EVENTS_ENABLED", "true") == "true",
+		SampleRate:    sampleRate,
+		Precision:     precision,
+		Sink:          getEnv("ANALYTICS_SINK", "log"),
+		URL:           getEnv("ANALYTICS_URL", ""),
+		BufferSize:    bufferSize,
+		FlushInterval: time.Duration(flushInterval) * time.Second,
+		Timeout:       time.Duration(timeout) * time.Second,
+	}
+}
+
+// loadGeoCacheConfig loads the driver position cache settings
+func loadGeoCacheConfig() GeoCacheConfig {
+	cellKm, _ := strconv.ParseFloat(getEnv("GEO_CACHE_CELL_KM", "1"), 64)
+	pollInterval, _ := strconv.Atoi(getEnv("GEO_CACHE_POLL_INTERVAL_MS", "2000"))
+	reconcileInterval, _ := strconv.Atoi(getEnv("GEO_CACHE_RECONCILE_INTERVAL_SEC", "300"))
+	maxStaleness, _ := strconv.Atoi(getEnv("GEO_CACHE_MAX_STALENESS_SEC", "30"))
+
+	return GeoCacheConfig{
+		Enabled:           getEnv("GEO_CACHE_ENABLED", "false") == "true",
+		CellKm:            cellKm,
+		PollInterval:      time.Duration(pollInterval) * time.Millisecond,
+		ReconcileInterval: time.Duration(reconcileInterval) * time.Second,
+		MaxStaleness:      time.Duration(maxStaleness) * time.Second,
+	}
+}
+
+// loadChangeStreamConfig loads the change stream listener settings
+func loadChangeStreamConfig() ChangeStreamConfig {
+	hostname, _ := os.Hostname()
+	saveInterval, _ := strconv.Atoi(getEnv("CHANGE_STREAM_TOKEN_SAVE_INTERVAL_SEC", "5"))
+
+	return ChangeStreamConfig{
+		Enabled:       getEnv("CHANGE_STREAMS_ENABLED", "false") == "true",
+		Name:          getEnv("CHANGE_STREAM_NAME", "driver-service-"+hostname),
+		SaveInterval:  time.Duration(saveInterval) * time.Second,
+		PublishEvents: getEnv("CHANGE_STREAM_PUBLISH_EVENTS", "false") == "true",
+	}
+}
+
+// loadKYCConfig loads the identity verification provider settings
+func loadKYCConfig() KYCConfig {
+	pollInterval, _ := strconv.Atoi(getEnv("KYC_POLL_INTERVAL_SEC", "60"))
+	timeout, _ := strconv.Atoi(getEnv("KYC_TIMEOUT_SEC", "10"))
+
+	return KYCConfig{
+		Provider:      getEnv("KYC_PROVIDER", "sandbox"),
+		URL:           getEnv("KYC_HTTP_URL", ""),
+		APIKey:        getEnv("KYC_HTTP_API_KEY", ""),
+		WebhookSecret: getEnv("KYC_WEBHOOK_SECRET", ""),
+		PollInterval:  time.Duration(pollInterval) * time.Second,
+		Timeout:       time.Duration(timeout) * time.Second,
+	}
+}
+
+// loadBlacklistConfig loads the driver blacklist check settings
+func loadBlacklistConfig() BlacklistConfig {
+	timeout, _ := strconv.Atoi(getEnv("BLACKLIST_TIMEOUT_SEC", "5"))
+	cacheTTL, _ := strconv.Atoi(getEnv("BLACKLIST_CACHE_TTL_SEC", "3600"))
+
+	return BlacklistConfig{
+		Checker:  getEnv("BLACKLIST_CHECKER", ""),
+		URL:      getEnv("BLACKLIST_HTTP_URL", ""),
+		APIKey:   getEnv("BLACKLIST_HTTP_API_KEY", ""),
+		Timeout:  time.Duration(timeout) * time.Second,
+		CacheTTL: time.Duration(cacheTTL) * time.Second,
+		FailMode: getEnv("BLACKLIST_FAIL_MODE", "open"),
+	}
+}
+
+// loadMQTTConfig loads the MQTT bridge settings
+func loadMQTTConfig() MQTTConfig {
+	qos, err := strconv.Atoi(getEnv("MQTT_QOS", "1"))
+	if err != nil {
+		qos = 1
+	}
+	authCacheTTL, _ := strconv.Atoi(getEnv("MQTT_AUTH_CACHE_TTL_SEC", "60"))
+
+	return MQTTConfig{
+		Enabled:      getEnv("MQTT_ENABLED", "false") == "true",
+		BrokerURL:    getEnv("MQTT_BROKER_URL", "tcp://localhost:1883"),
+		ClientID:     getEnv("MQTT_CLIENT_ID", "driver-service"),
+		Username:     getEnv("MQTT_USERNAME", ""),
+		Password:     getEnv("MQTT_PASSWORD", ""),
+		TopicPrefix:  getEnv("MQTT_TOPIC_PREFIX", "drivers"),
+		QoS:          qos,
+		AuthCacheTTL: time.Duration(authCacheTTL) * time.Second,
+	}
+}
+
+// loadAutocompleteConfig loads the driver autocomplete settings
+func loadAutocompleteConfig() AutocompleteConfig {
+	budget, _ := strconv.Atoi(getEnv("AUTOCOMPLETE_BUDGET_MS", "40"))
+	defaultLimit, _ := strconv.Atoi(getEnv("AUTOCOMPLETE_DEFAULT_LIMIT", "8"))
+	maxLimit, _ := strconv.Atoi(getEnv("AUTOCOMPLETE_MAX_LIMIT", "20"))
+
+	return AutocompleteConfig{
+		Budget:       time.Duration(budget) * time.Millisecond,
+		DefaultLimit: defaultLimit,
+		MaxLimit:     maxLimit,
+	}
+}
+
+// loadBulkUpdateConfig loads the bulk update safety cap
+func loadBulkUpdateConfig() BulkUpdateConfig {
+	maxDrivers, err := strconv.Atoi(getEnv("BULK_UPDATE_MAX_DRIVERS", "1000"))
+	if err != nil || maxDrivers <= 0 {
+		maxDrivers = 1000
+	}
+
+	return BulkUpdateConfig{MaxDrivers: maxDrivers}
+}
+
+// loadMetricsConfig loads the business metrics settings
+func loadMetricsConfig() MetricsConfig {
+	refresh, err := strconv.Atoi(getEnv("METRICS_STATUS_REFRESH_SEC", "30"))
+	if err != nil || refresh <= 0 {
+		refresh = 30
+	}
+
+	return MetricsConfig{StatusRefresh: time.Duration(refresh) * time.Second}
+}
+
+// loadWarmupConfig loads the warm-up settings
+func loadWarmupConfig() WarmupConfig {
+	retry, err := strconv.Atoi(getEnv("WARMUP_RETRY_INTERVAL_SEC", "5"))
+	if err != nil || retry <= 0 {
+		retry = 5
+	}
+
+	return WarmupConfig{
+		RequireIndexes: getEnv("WARMUP_REQUIRE_INDEXES", "true") == "true",
+		GeoCache:       getEnv("WARMUP_GEO_CACHE", "true") == "true",
+		RetryInterval:  time.Duration(retry) * time.Second,
+	}
+}
+
+// loadGeohashConfig loads the geohash rendering settings
+func loadGeohashConfig() GeohashConfig {
+	precision, err := strconv.Atoi(getEnv("GEOHASH_PRECISION", "0"))
+	if err != nil || precision < 0 {
+		precision = 0
+	}
+	if precision > 12 {
+		precision = 12
+	}
+
+	return GeohashConfig{Precision: precision}
+}
+
+// loadShadowReadsConfig loads the shadow read settings
+func loadShadowReadsConfig() ShadowReadsConfig {
+	sampleRate, err := strconv.ParseFloat(getEnv("SHADOW_READ_SAMPLE_RATE", "0.1"), 64)
+	if err != nil {
+		sampleRate = 0.1
+	}
+	timeout, err := strconv.Atoi(getEnv("SHADOW_READ_TIMEOUT_MS", "2000"))
+	if err != nil || timeout <= 0 {
+		timeout = 2000
+	}
+	maxInFlight, err := strconv.Atoi(getEnv("SHADOW_READ_MAX_IN_FLIGHT", "50"))
+	if err != nil || maxInFlight <= 0 {
+		maxInFlight = 50
+	}
+
+	return ShadowReadsConfig{
+		Enabled:     getEnv("SHADOW_READS_ENABLED", "false") == "true",
+		Backend:     getEnv("SHADOW_BACKEND", "mongodb"),
+		URI:         getEnv("SHADOW_MONGODB_URI", ""),
+		Database:    getEnv("SHADOW_MONGODB_DATABASE", getEnv("MONGODB_DATABASE", "taxihub")),
+		SampleRate:  sampleRate,
+		Timeout:     time.Duration(timeout) * time.Millisecond,
+		MaxInFlight: maxInFlight,
+	}
+}
+
+// loadRetentionConfig loads the personal data retention windows
+func loadRetentionConfig() RetentionConfig {
+	deletedDays, _ := strconv.Atoi(getEnv("RETENTION_DELETED_DRIVER_DAYS", "30"))
+	locationDays, _ := strconv.Atoi(getEnv("RETENTION_LOCATION_HISTORY_DAYS", "400"))
+	interval, _ := strconv.Atoi(getEnv("RETENTION_INTERVAL_MIN", "60"))
+
+	return RetentionConfig{
+		DeletedDriverWindow:   time.Duration(deletedDays) * 24 * time.Hour,
+		LocationHistoryWindow: time.Duration(locationDays) * 24 * time.Hour,
+		Interval:              time.Duration(interval) * time.Minute,
+	}
+}
+
+// loadJobsConfig loads the background job runner settings
+func loadJobsConfig() JobsConfig {
+	workers, _ := strconv.Atoi(getEnv("JOB_WORKERS", "2"))
+	queueSize, _ := strconv.Atoi(getEnv("JOB_QUEUE_SIZE", "20"))
+	retention, _ := strconv.Atoi(getEnv("JOB_RETENTION_HOURS", "24"))
+
+	return JobsConfig{
+		Dir:       getEnv("JOB_DIR", "./data/jobs"),
+		Workers:   workers,
+		QueueSize: queueSize,
+		Retention: time.Duration(retention) * time.Hour,
+	}
+}
+
+// loadStreamConfig loads the driver stream settings
+func loadStreamConfig() StreamConfig {
+	flushInterval, _ := strconv.Atoi(getEnv("STREAM_FLUSH_INTERVAL_MS", "1000"))
+	writeTimeout, _ := strconv.Atoi(getEnv("STREAM_WRITE_TIMEOUT_SEC", "30"))
+
+	return StreamConfig{
+		FlushInterval: time.Duration(flushInterval) * time.Millisecond,
+		WriteTimeout:  time.Duration(writeTimeout) * time.Second,
+	}
+}
+
+// loadRoutingConfig loads the routing provider settings
+func loadRoutingConfig() RoutingConfig {
+	timeout, _ := strconv.Atoi(getEnv("ROUTING_TIMEOUT_MS", "2000"))
+	speed, _ := strconv.ParseFloat(getEnv("ROUTING_AVERAGE_SPEED_KMH", "25"), 64)
+
+	return RoutingConfig{
+		Provider:    getEnv("ROUTING_PROVIDER", "haversine"),
+		OSRMURL:     getEnv("OSRM_URL", ""),
+		OSRMProfile: getEnv("OSRM_PROFILE", "driving"),
+		Timeout:     time.Duration(timeout) * time.Millisecond,
+		SpeedKmh:    speed,
+	}
+}
+
+// loadPhotoConfig loads the photo upload limits. PHOTO_THUMBNAIL_SIZES is a
+// comma-separated list of square thumbnail sides in pixels.
+func loadPhotoConfig() PhotoConfig {
+	maxBytes, _ := strconv.ParseInt(getEnv("PHOTO_MAX_BYTES", "5242880"), 10, 64)
+	minDimension, _ := strconv.Atoi(getEnv("PHOTO_MIN_DIMENSION", "200"))
+	maxDimension, _ := strconv.Atoi(getEnv("PHOTO_MAX_DIMENSION", "6000"))
+	fullSize, _ := strconv.Atoi(getEnv("PHOTO_FULL_SIZE", "1024"))
+	quality, _ := strconv.Atoi(getEnv("PHOTO_JPEG_QUALITY", "85"))
+
+	var sizes []int
+	for _, item := range splitList(getEnv("PHOTO_THUMBNAIL_SIZES", "256,64")) {
+		if size, err := strconv.Atoi(item); err == nil && size > 0 {
+			sizes = append(sizes, size)
+		}
+	}
+
+	return PhotoConfig{
+		MaxBytes:       maxBytes,
+		MinDimension:   minDimension,
+		MaxDimension:   maxDimension,
+		FullSize:       fullSize,
+		ThumbnailSizes: sizes,
+		Quality:        quality,
+	}
+}
+
+// loadFareConfig loads the fare tariff. FARE_TAXI_TYPE_MULTIPLIERS is a
+// comma-separated list of "taxiType:multiplier" entries.
+func loadFareConfig() FareConfig {
+	base, _ := strconv.ParseFloat(getEnv("FARE_BASE", "40"), 64)
+	perKm, _ := strconv.ParseFloat(getEnv("FARE_PER_KM", "26"), 64)
+	perMinute, _ := strconv.ParseFloat(getEnv("FARE_PER_MINUTE", "0"), 64)
+	minimum, _ := strconv.ParseFloat(getEnv("FARE_MINIMUM", "135"), 64)
+
+	multipliers := make(map[string]float64)
+	for _, item := range splitList(getEnv("FARE_TAXI_TYPE_MULTIPLIERS", "turkuaz:1.15,siyah:2")) {
+		taxiType, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		if multiplier, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			multipliers[strings.TrimSpace(taxiType)] = multiplier
+		}
+	}
+
+	return FareConfig{
+		Base:        base,
+		PerKm:       perKm,
+		PerMinute:   perMinute,
+		Minimum:     minimum,
+		Currency:    getEnv("FARE_CURRENCY", "TRY"),
+		Multipliers: multipliers,
+	}
+}
+
+// loadQuotaConfig loads the tenant driver quotas. TENANT_QUOTAS is a
+// comma-separated list of "fleetId:maxDrivers" entries.
+func loadQuotaConfig() QuotaConfig {
+	maxDrivers, err := strconv.Atoi(getEnv("TENANT_QUOTA_MAX_DRIVERS", "0"))
+	if err != nil || maxDrivers < 0 {
+		maxDrivers = 0
+	}
+
+	tenants := make(map[string]int)
+	for _, item := range splitList(getEnv("TENANT_QUOTAS", "")) {
+		fleetID, value, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		if max, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && max >= 0 {
+			tenants[strings.TrimSpace(fleetID)] = max
+		}
+	}
+
+	var thresholds []int
+	for _, item := range splitList(getEnv("TENANT_QUOTA_THRESHOLDS", "80,100")) {
+		if percent, err := strconv.Atoi(item); err == nil && percent > 0 && percent <= 100 {
+			thresholds = append(thresholds, percent)
+		}
+	}
+	sort.Ints(thresholds)
+
+	return QuotaConfig{
+		MaxDrivers: maxDrivers,
+		Tenants:    tenants,
+		Thresholds: thresholds,
+	}
+}
+
+// loadErrorReportConfig loads the error tracker settings
+func loadErrorReportConfig() ErrorReportConfig {
+	sampleRate, err := strconv.ParseFloat(getEnv("ERROR_REPORTING_SAMPLE_RATE", "1"), 64)
+	if err != nil || sampleRate < 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	return ErrorReportConfig{
+		DSN:         getEnv("ERROR_REPORTING_DSN", ""),
+		Environment: getEnv("ERROR_REPORTING_ENVIRONMENT", "production"),
+		Release:     getEnv("ERROR_REPORTING_RELEASE", ""),
+		SampleRate:  sampleRate,
+		Loggers:     splitList(getEnv("ERROR_REPORTING_LOGGERS", "usecase,jobs,changestream,telemetry,warmup,analytics,sms")),
+		ScrubFields: splitList(getEnv("ERROR_REPORTING_SCRUB_FIELDS", "")),
+	}
+}
+
+// loadCORSConfig loads the CORS policy. Development mode defaults to a permissive
+// policy, while release mode denies cross-origin requests unless origins are configured.
+func loadCORSConfig(devMode bool) CORSConfig {
+	defaultOrigins := ""
+	defaultCredentials := "false"
+	if devMode {
+		defaultOrigins = "*"
+		defaultCredentials = "true"
+	}
+	maxAge, _ := strconv.Atoi(getEnv("CORS_MAX_AGE_SEC", "600"))
+
+	return CORSConfig{
+		AllowedOrigins:   splitList(getEnv("CORS_ALLOWED_ORIGINS", defaultOrigins)),
+		AllowedMethods:   splitList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS")),
+		AllowedHeaders:   splitList(getEnv("CORS_ALLOWED_HEADERS", "Content-Type,Content-Length,Accept-Encoding,X-CSRF-Token,Authorization,Accept,Origin,Cache-Control,X-Requested-With")),
+		AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", defaultCredentials) == "true",
+		MaxAge:           time.Duration(maxAge) * time.Second,
+	}
+}
+
+// loadEncryptionConfig parses FIELD_ENCRYPTION_KEYS ("id:key,id:key"); the active key
+// defaults to the last one listed so appending a key rotates to it
+func loadEncryptionConfig() EncryptionConfig {
+	keys := make(map[string]string)
+	active := ""
+	for _, item := range splitList(getEnv("FIELD_ENCRYPTION_KEYS", "")) {
+		id, key, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		keys[strings.TrimSpace(id)] = strings.TrimSpace(key)
+		active = strings.TrimSpace(id)
+	}
+
+	return EncryptionConfig{
+		Keys:      keys,
+		ActiveKey: getEnv("FIELD_ENCRYPTION_ACTIVE_KEY", active),
+		HashKey:   getEnv("FIELD_HASH_KEY", ""),
+		KMS:       getEnv("FIELD_ENCRYPTION_KMS", ""),
+		Vault: VaultConfig{
+			Addr:       getEnv("VAULT_ADDR", "http://localhost:8200"),
+			Token:      getEnv("VAULT_TOKEN", ""),
+			TransitKey: getEnv("VAULT_TRANSIT_KEY", "taxihub-fields"),
+		},
+	}
+}
+
+// loadCompressionConfig loads the response compression settings
+func loadCompressionConfig() CompressionConfig {
+	minSize, _ := strconv.Atoi(getEnv("COMPRESSION_MIN_SIZE", "1024"))
+	level, _ := strconv.Atoi(getEnv("COMPRESSION_LEVEL", "-1"))
+	maxRequestSize, _ := strconv.ParseInt(getEnv("COMPRESSION_MAX_REQUEST_SIZE", "33554432"), 10, 64)
+
+	return CompressionConfig{
+		Enabled:        getEnv("COMPRESSION_ENABLED", "true") == "true",
+		MinSize:        minSize,
+		Level:          level,
+		ContentTypes:   splitList(getEnv("COMPRESSION_CONTENT_TYPES", "application/json,application/problem+json,application/x-ndjson,text/*")),
+		Requests:       getEnv("COMPRESSION_REQUESTS_ENABLED", "true") == "true",
+		MaxRequestSize: maxRequestSize,
+	}
+}
+
+// loadLoggingConfig loads the log outputs, rotation and LOG_LEVELS ("name=level,name=level") overrides
+func loadLoggingConfig(level string) LoggingConfig {
+	maxSize, _ := strconv.Atoi(getEnv("LOG_MAX_SIZE_MB", "100"))
+	maxAge, _ := strconv.Atoi(getEnv("LOG_MAX_AGE_DAYS", "0"))
+	maxBackups, _ := strconv.Atoi(getEnv("LOG_MAX_BACKUPS", "0"))
+
+	levels := make(map[string]string)
+	for _, item := range splitList(getEnv("LOG_LEVELS", "")) {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		levels[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return LoggingConfig{
+		Level:    level,
+		Encoding: getEnv("LOG_ENCODING", ""),
+		Outputs:  splitList(getEnv("LOG_OUTPUTS", "stdout")),
+		Levels:   levels,
+		Rotation: LogRotationConfig{
+			MaxSizeMB:  maxSize,
+			MaxAgeDays: maxAge,
+			MaxBackups: maxBackups,
+			Compress:   getEnv("LOG_COMPRESS", "false") == "true",
+		},
 	}
 }
 
@@ -67,3 +1231,26 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// splitTagSets splits read preference tag sets separated by semicolons; the
+// pairs within a set stay separated by commas
+func splitTagSets(value string) []string {
+	var sets []string
+	for _, set := range strings.Split(value, ";") {
+		if trimmed := strings.TrimSpace(set); trimmed != "" {
+			sets = append(sets, trimmed)
+		}
+	}
+	return sets
+}
+
+// splitList splits a comma-separated value into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Kinds of recorded driver activity
+const (
+	ActivityLocation     = "location"
+	ActivityShiftStarted = "shift_started"
+	ActivityShiftEnded   = "shift_ended"
+)
+
+// ActivityEvent is a driver activity as recorded, replayed to rebuild the
+// read models derived from it
+type ActivityEvent struct {
+	Kind     string
+	DriverID string
+	At       time.Time
+	// ID identifies the event and orders events recorded at the same time
+	ID string
+	// Location is set on location events
+	Location *Location
+	// ShiftStartedAt is set on shift_ended events
+	ShiftStartedAt *time.Time
+}
+
+// ActivityPosition is where a replay of the activity history stands: the
+// last event handled
+type ActivityPosition struct {
+	At time.Time `bson:"at"`
+	ID string    `bson:"id"`
+}
+
+// ActivityLog streams the recorded driver activity in the order it happened
+type ActivityLog interface {
+	// StreamActivity calls handle with the activity in [from, to), oldest
+	// first, skipping what is not after the position when one is given; an
+	// error from handle ends the stream
+	StreamActivity(ctx interface{}, from, to time.Time, after *ActivityPosition, handle func(event *ActivityEvent) error) error
+}
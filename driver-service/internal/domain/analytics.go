@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Analytics event types
+const (
+	// EventNearbySearchPerformed records where riders look for taxis, for demand heatmaps
+	EventNearbySearchPerformed = "NearbySearchPerformed"
+)
+
+// NearbySearchPerformed is an anonymized nearby driver search: it carries no
+// caller identity, coordinates are rounded and the time is cut to the minute
+type NearbySearchPerformed struct {
+	Lat         float64   `json:"lat" example:"41.01"`
+	Lon         float64   `json:"lon" example:"28.98"`
+	TaxiType    string    `json:"taxiType,omitempty" example:"sari"`
+	ResultCount int       `json:"resultCount" example:"7"`
+	Timestamp   time.Time `json:"timestamp" example:"2025-12-06T01:00:00Z"`
+}
+
+// EventPublisher emits analytics events. Publishing never blocks or fails the
+// caller, so implementations drop events they cannot keep up with.
+type EventPublisher interface {
+	Publish(eventType string, data interface{})
+}
+
+// EventDriverChanged records a write to a driver by any instance, as read from
+// the change stream
+const EventDriverChanged = "DriverChanged"
+
+// DriverChanged is a driver change as published to the analytics sink
+type DriverChanged struct {
+	DriverID  string    `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Operation string    `json:"operation" example:"update"`
+	Suspended bool      `json:"suspended" example:"false"`
+	Deleted   bool      `json:"deleted" example:"false"`
+	Timestamp time.Time `json:"timestamp" example:"2025-12-06T01:00:00Z"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// LocationAnomaly is a location update that moved the driver further than it
+// could have travelled since the previous update, e.g. a spoofed GPS position
+type LocationAnomaly struct {
+	DriverID string   `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	From     Location `bson:"from" json:"from"`
+	To       Location `bson:"to" json:"to"`
+	// DistanceKm is the straight-line distance between the two positions
+	DistanceKm float64 `bson:"distanceKm" json:"distanceKm" example:"38.4"`
+	// ElapsedSec is the time since the previous position was reported
+	ElapsedSec float64 `bson:"elapsedSec" json:"elapsedSec" example:"12"`
+	// SpeedKmh is the speed the jump implies
+	SpeedKmh float64 `bson:"speedKmh" json:"speedKmh" example:"11520"`
+	// Rejected is set when the update was refused rather than only flagged
+	Rejected   bool      `bson:"rejected" json:"rejected" example:"false"`
+	DetectedAt time.Time `bson:"detectedAt" json:"detectedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// LocationAnomalyCount counts the anomalies of one driver over a range
+type LocationAnomalyCount struct {
+	DriverID string `bson:"_id" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Count    int    `bson:"count" json:"count" example:"7"`
+	// Rejected is how many of the updates were refused
+	Rejected       int       `bson:"rejected" json:"rejected" example:"2"`
+	MaxSpeedKmh    float64   `bson:"maxSpeedKmh" json:"maxSpeedKmh" example:"11520"`
+	LastDetectedAt time.Time `bson:"lastDetectedAt" json:"lastDetectedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// LocationAnomalyReport lists the drivers with location anomalies in a range,
+// most anomalies first
+type LocationAnomalyReport struct {
+	From    time.Time               `json:"from" example:"2025-11-06T00:00:00Z"`
+	To      time.Time               `json:"to" example:"2025-12-06T00:00:00Z"`
+	Drivers []*LocationAnomalyCount `json:"drivers"`
+}
+
+// LocationAnomalyRecorder stores location anomalies
+type LocationAnomalyRecorder interface {
+	RecordLocationAnomaly(ctx interface{}, anomaly *LocationAnomaly) error
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrBulkUpdateTooLarge is returned when a bulk update filter matches more
+// drivers than a single update may change
+var ErrBulkUpdateTooLarge = errors.New("bulk update matches too many drivers")
+
+// BulkFilter selects the drivers a bulk update changes. Every set field must
+// match; deleted drivers never do.
+type BulkFilter struct {
+	IDs        []string          `bson:"ids,omitempty" json:"ids,omitempty" example:"507f1f77bcf86cd799439011"`
+	FleetID    string            `bson:"fleetId,omitempty" json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	City       string            `bson:"city,omitempty" json:"city,omitempty" example:"istanbul"`
+	TaxiType   TaxiType          `bson:"taxiType,omitempty" json:"taksiType,omitempty" example:"sari"`
+	CarBrand   string            `bson:"carBrand,omitempty" json:"carBrand,omitempty" example:"Fiat"`
+	CarModel   string            `bson:"carModel,omitempty" json:"carModel,omitempty" example:"Egea"`
+	Tags       []string          `bson:"tags,omitempty" json:"tags,omitempty" example:"pet-friendly"`
+	Attributes map[string]string `bson:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+// Empty reports whether the filter would match every driver
+func (f BulkFilter) Empty() bool {
+	return len(f.IDs) == 0 && f.FleetID == "" && f.City == "" && f.TaxiType == "" &&
+		f.CarBrand == "" && f.CarModel == "" && len(f.Tags) == 0 && len(f.Attributes) == 0
+}
+
+// DriverPatch is the fields a bulk update sets; nil fields are left alone
+type DriverPatch struct {
+	CarBrand *string   `bson:"carBrand,omitempty" json:"carBrand,omitempty" example:"Toyota"`
+	CarModel *string   `bson:"carModel,omitempty" json:"carModel,omitempty" example:"Corolla"`
+	TaxiType *TaxiType `bson:"taxiType,omitempty" json:"taksiType,omitempty" example:"turkuaz"`
+	// Suspended suspends or reinstates the drivers. Suspending also takes them
+	// off shift and records SuspendReason.
+	Suspended     *bool  `bson:"suspended,omitempty" json:"suspended,omitempty" example:"true"`
+	SuspendReason string `bson:"suspensionReason,omitempty" json:"suspensionReason,omitempty" example:"fleet inspection"`
+}
+
+// Empty reports whether the patch sets no field
+func (p DriverPatch) Empty() bool {
+	return p.CarBrand == nil && p.CarModel == nil && p.TaxiType == nil && p.Suspended == nil
+}
+
+// Apply sets the patched fields on a driver and reports whether any changed
+func (p DriverPatch) Apply(d *Driver) bool {
+	before := *d
+	if p.CarBrand != nil {
+		d.CarBrand = *p.CarBrand
+	}
+	if p.CarModel != nil {
+		d.CarModel = *p.CarModel
+	}
+	if p.TaxiType != nil {
+		d.TaxiType = *p.TaxiType
+	}
+	if p.Suspended != nil {
+		d.Suspended = *p.Suspended
+		d.SuspendReason = ""
+		if *p.Suspended {
+			d.SuspendReason = p.SuspendReason
+			d.Available = false
+		}
+	}
+	return d.CarBrand != before.CarBrand || d.CarModel != before.CarModel || d.TaxiType != before.TaxiType ||
+		d.Suspended != before.Suspended || d.SuspendReason != before.SuspendReason || d.Available != before.Available
+}
+
+// BulkUpdate is the audit record of a bulk update
+type BulkUpdate struct {
+	ID     string      `bson:"_id" json:"id" example:"6578a1f2c3d4e5f6a7b8c9d0"`
+	Filter BulkFilter  `bson:"filter" json:"filter"`
+	Patch  DriverPatch `bson:"patch" json:"patch"`
+	// DryRun updates only counted the drivers they would change
+	DryRun bool `bson:"dryRun" json:"dryRun" example:"false"`
+	// Matched is the number of drivers the filter matched
+	Matched int64 `bson:"matched" json:"matched" example:"42"`
+	// Modified is the number of drivers the patch changed, or would change on
+	// a dry run; drivers that already had the patched values are not counted
+	Modified int64 `bson:"modified" json:"modified" example:"40"`
+	// ActorID, ActorRole and TenantID identify the caller, when the gateway forwarded one
+	ActorID   string    `bson:"actorId,omitempty" json:"actorId,omitempty" example:"fleet-admin"`
+	ActorRole string    `bson:"actorRole,omitempty" json:"actorRole,omitempty" example:"fleet_admin"`
+	TenantID  string    `bson:"tenantId,omitempty" json:"tenantId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// BulkUpdateResult is what a bulk update found and changed
+type BulkUpdateResult struct {
+	Matched  int64
+	Modified int64
+	// Changed are the drivers the patch changes, as they were before it was
+	// applied, so callers can tell who was on shift
+	Changed []*Driver
+}
+
+// DriverBulkRepository changes many drivers at once and keeps the audit trail
+type DriverBulkRepository interface {
+	// BulkUpdateDrivers applies patch to the drivers matching filter with a
+	// single UpdateMany. It returns ErrBulkUpdateTooLarge, with Matched set,
+	// when the filter matches more than limit drivers. A dry run only finds
+	// the drivers the patch would change.
+	BulkUpdateDrivers(ctx interface{}, filter BulkFilter, patch DriverPatch, limit int, dryRun bool, at time.Time) (*BulkUpdateResult, error)
+	RecordBulkUpdate(ctx interface{}, update *BulkUpdate) error
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Operations of a driver change
+const (
+	ChangeInsert  = "insert"
+	ChangeUpdate  = "update"
+	ChangeReplace = "replace"
+	ChangeDelete  = "delete"
+)
+
+// DriverChange is a write to the drivers collection, by any instance
+type DriverChange struct {
+	Operation string
+	DriverID  string
+	// Driver is the driver after the write; a soft delete has DeletedAt set.
+	// It is nil for hard deletes and when the driver is gone by the time the
+	// change is read.
+	Driver *Driver
+	At     time.Time
+}
+
+// ErrChangeHistoryLost is returned when a change stream cannot resume because
+// the oplog no longer holds its position; changes since then are lost
+var ErrChangeHistoryLost = errors.New("change stream history lost")
+
+// ErrChangeStreamUnsupported is returned when the database cannot stream
+// changes, e.g. a standalone MongoDB instead of a replica set
+var ErrChangeStreamUnsupported = errors.New("change streams are not supported by the database")
+
+// DriverChangeSource streams driver changes. handle receives every change with
+// the token to resume after it; an error from handle ends the stream.
+type DriverChangeSource interface {
+	WatchChanges(ctx interface{}, resumeToken []byte, handle func(change *DriverChange, token []byte) error) error
+}
+
+// ResumeTokenStore keeps the position of change streams across restarts
+type ResumeTokenStore interface {
+	// LoadToken returns nil when no token is stored for the stream
+	LoadToken(ctx interface{}, stream string) ([]byte, error)
+	SaveToken(ctx interface{}, stream string, token []byte) error
+}
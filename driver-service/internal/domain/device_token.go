@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Scopes a device token can carry. Device tokens only ever get these; every
+// other route still needs a fleet admin or service token.
+const (
+	ScopeLocationUpdate = "location:update"
+	ScopeHeartbeat      = "heartbeat"
+)
+
+// DeviceTokenScopes are the scopes every device token is issued with
+var DeviceTokenScopes = []string{ScopeLocationUpdate, ScopeHeartbeat}
+
+// DeviceToken is a long-lived credential of one driver device. Only the hash of
+// the token is stored.
+type DeviceToken struct {
+	ID       string `bson:"_id" json:"id" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	// DeviceID identifies the device; issuing a token for a device revokes its previous one
+	DeviceID string `bson:"deviceId" json:"deviceId" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
+	Name     string `bson:"name,omitempty" json:"name,omitempty" example:"Mehmet's Android"`
+	// Token authenticates the device; it is only returned when the token is issued or rotated
+	Token string `bson:"-" json:"token,omitempty" example:"dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a"`
+	// TokenHash is the SHA-256 of the token in hex
+	TokenHash string `bson:"tokenHash" json:"-"`
+	// Prefix is the start of the token, enough to tell tokens apart in listings
+	Prefix     string     `bson:"prefix" json:"prefix" example:"dtk_4f1c9a7e"`
+	Scopes     []string   `bson:"scopes" json:"scopes" example:"location:update,heartbeat"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	LastUsedAt *time.Time `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty" example:"2025-12-06T09:30:00Z"`
+	RevokedAt  *time.Time `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	// ReplacedBy is the token a rotation issued in place of this one
+	ReplacedBy string `bson:"replacedBy,omitempty" json:"replacedBy,omitempty" example:"6573b1f2c3d4e5f6a7b8c9d0"`
+}
+
+// Active reports whether the token has not been revoked
+func (t *DeviceToken) Active() bool {
+	return t.RevokedAt == nil
+}
+
+// HasScope reports whether the token carries scope
+func (t *DeviceToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// DeviceTokenRepository defines the interface for device token data access
+type DeviceTokenRepository interface {
+	Create(ctx interface{}, token *DeviceToken) error
+	GetByID(ctx interface{}, id string) (*DeviceToken, error)
+	GetByHash(ctx interface{}, tokenHash string) (*DeviceToken, error)
+	// List returns the tokens of a driver, newest first; an empty driverID lists every token
+	List(ctx interface{}, driverID string, activeOnly bool) ([]*DeviceToken, error)
+	// Revoke marks the token revoked; replacedBy names the token that replaces it, if any.
+	// It reports false when the token was already revoked.
+	Revoke(ctx interface{}, id, replacedBy string, at time.Time) (bool, error)
+	// RevokeDevice revokes the active tokens of a driver's device and returns how many there were
+	RevokeDevice(ctx interface{}, driverID, deviceID string, at time.Time) (int64, error)
+	// Touch records that the token was used
+	Touch(ctx interface{}, id string, at time.Time) error
+}
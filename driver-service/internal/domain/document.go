@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// DocumentType identifies a driver onboarding document
+type DocumentType string
+
+const (
+	DocumentTypeLicense      DocumentType = "license"
+	DocumentTypeRegistration DocumentType = "registration"
+	DocumentTypeInsurance    DocumentType = "insurance"
+)
+
+// IsValid checks if the document type is valid
+func (t DocumentType) IsValid() bool {
+	return t == DocumentTypeLicense || t == DocumentTypeRegistration || t == DocumentTypeInsurance
+}
+
+// DriverDocument describes a document a driver submitted during onboarding.
+// The file itself lives in object storage; only its reference is kept here.
+type DriverDocument struct {
+	Type       DocumentType `bson:"type" json:"type" example:"license"`
+	Number     string       `bson:"number,omitempty" json:"number,omitempty" example:"TR-1234567"`
+	URL        string       `bson:"url" json:"url" example:"https://files.bitaksi.com/docs/license.pdf"`
+	ExpiresAt  *time.Time   `bson:"expiresAt,omitempty" json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+	UploadedAt time.Time    `bson:"uploadedAt" json:"uploadedAt" example:"2025-12-06T01:00:00Z"`
+}
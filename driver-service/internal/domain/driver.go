@@ -1,39 +1,111 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"time"
+)
 
 // TaxiType represents the type of taxi
 type TaxiType string
 
+// The built-in taxi types; more are added through the taxi type registry
 const (
 	TaxiTypeSari    TaxiType = "sari"
 	TaxiTypeTurkuaz TaxiType = "turkuaz"
 	TaxiTypeSiyah   TaxiType = "siyah"
 )
 
-// IsValid checks if the taxi type is valid
-func (t TaxiType) IsValid() bool {
-	return t == TaxiTypeSari || t == TaxiTypeTurkuaz || t == TaxiTypeSiyah
-}
-
 // Location represents geographic coordinates
 type Location struct {
 	Lat float64 `bson:"lat" json:"lat" example:"41.0431"`
 	Lon float64 `bson:"lon" json:"lon" example:"29.0099"`
+	// Geohash is the cell of the coordinates with GEOHASH_PRECISION characters,
+	// for clients bucketing drivers into map tiles; it is only rendered
+	Geohash string `bson:"-" json:"geohash,omitempty" example:"sxk9kp"`
 }
 
+// CityOther is the city of drivers outside every configured city
+const CityOther = "other"
+
+// Errors a driver repository returns when a unique index rejects a write, e.g.
+// when two registrations with the same phone race past the availability check
+var (
+	ErrDuplicatePhone = errors.New("phone is already stored for another driver")
+	ErrDuplicateEmail = errors.New("email is already stored for another driver")
+	ErrDuplicatePlate = errors.New("plate is already stored for another driver")
+)
+
 // Driver represents a taxi driver entity
 type Driver struct {
-	ID        string    `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
-	FirstName string    `bson:"firstName" json:"firstName" example:"Ahmet"`
-	LastName  string    `bson:"lastName" json:"lastName" example:"Demir"`
-	Plate     string    `bson:"plate" json:"plate" example:"34ABC123"`
-	TaxiType  TaxiType  `bson:"taxiType" json:"taxiType" example:"sari"`
-	CarBrand  string    `bson:"carBrand" json:"carBrand" example:"Toyota"`
-	CarModel  string    `bson:"carModel" json:"carModel" example:"Corolla"`
-	Location  Location  `bson:"location" json:"location"`
-	CreatedAt time.Time `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
-	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+	ID        string   `bson:"_id,omitempty" json:"id" example:"507f1f77bcf86cd799439011"`
+	FirstName string   `bson:"firstName" json:"firstName" example:"Ahmet"`
+	LastName  string   `bson:"lastName" json:"lastName" example:"Demir"`
+	Plate     string   `bson:"plate" json:"plate" example:"34ABC123"`
+	TaxiType  TaxiType `bson:"taxiType" json:"taxiType" example:"sari"`
+	CarBrand  string   `bson:"carBrand" json:"carBrand" example:"Toyota"`
+	CarModel  string   `bson:"carModel" json:"carModel" example:"Corolla"`
+	Location  Location `bson:"location" json:"location"`
+	// LocationUpdatedAt is when the location was last reported; nil for drivers
+	// stored before it was recorded
+	LocationUpdatedAt *time.Time `bson:"locationUpdatedAt,omitempty" json:"locationUpdatedAt,omitempty" example:"2025-12-06T01:00:00Z"`
+	// City is derived from Location when the driver is stored; empty for
+	// drivers stored before cities were configured
+	City string `bson:"city,omitempty" json:"city,omitempty" example:"istanbul"`
+	// Contact details; Phone is stored in E.164 format
+	Phone         string `bson:"phone,omitempty" json:"phone,omitempty" example:"+905321234567"`
+	Email         string `bson:"email,omitempty" json:"email,omitempty" example:"ahmet.demir@example.com"`
+	PhoneVerified bool   `bson:"phoneVerified" json:"phoneVerified" example:"false"`
+	EmailVerified bool   `bson:"emailVerified" json:"emailVerified" example:"false"`
+	// IdentityVerified is set once a KYC provider approved the driver's identity
+	IdentityVerified bool `bson:"identityVerified,omitempty" json:"identityVerified" example:"false"`
+	// Available reports whether the driver is on shift and can receive rides
+	Available bool `bson:"available" json:"available" example:"false"`
+	// Suspended drivers cannot go on shift and are left out of nearby searches
+	Suspended     bool   `bson:"suspended" json:"suspended" example:"false"`
+	SuspendReason string `bson:"suspensionReason,omitempty" json:"suspensionReason,omitempty" example:"expired license"`
+	// Rating is the average rider rating (0-5) over RatingCount completed trips
+	Rating      float64 `bson:"rating" json:"rating" example:"4.8"`
+	RatingCount int     `bson:"ratingCount" json:"ratingCount" example:"120"`
+	// License is optional until the driver's licence has been recorded
+	License *DriverLicense `bson:"license,omitempty" json:"license,omitempty"`
+	// LicenseExpired drivers are not dispatchable until the licence is renewed
+	LicenseExpired bool `bson:"licenseExpired,omitempty" json:"licenseExpired,omitempty" example:"false"`
+	// FleetID is the fleet the driver works for; empty for independent drivers
+	FleetID string `bson:"fleetId,omitempty" json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// Tags are dispatch features such as pet-friendly or wheelchair-accessible
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty" example:"pet-friendly,wheelchair-accessible"`
+	// Attributes are free-form facts about the driver, such as language: en
+	Attributes map[string]string `bson:"attributes,omitempty" json:"attributes,omitempty"`
+	// LastSeenAt is the time of the driver app's latest heartbeat
+	LastSeenAt *time.Time `bson:"lastSeenAt,omitempty" json:"lastSeenAt,omitempty" example:"2025-12-06T01:00:00Z"`
+	// Photo is the driver's profile picture, if one was uploaded
+	Photo *DriverPhoto `bson:"photo,omitempty" json:"photo,omitempty"`
+	// Documents holds at most one document per type
+	Documents []DriverDocument `bson:"documents,omitempty" json:"documents,omitempty"`
+	CreatedAt time.Time        `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt time.Time        `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+	// DeletedAt is set on the tombstone a deleted driver leaves for delta syncs
+	DeletedAt *time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty" example:"2025-12-06T01:00:00Z"`
+}
+
+// NearbyPage is the part of a nearby search returned: Offset drivers are
+// skipped, nearest first, and at most Limit returned. A zero Limit returns
+// every driver after Offset.
+type NearbyPage struct {
+	Limit  int
+	Offset int
+}
+
+// Apply returns the page of drivers, which are sorted nearest first
+func (p NearbyPage) Apply(drivers []*Driver) []*Driver {
+	if p.Offset >= len(drivers) {
+		return drivers[len(drivers):]
+	}
+	drivers = drivers[p.Offset:]
+	if p.Limit > 0 && p.Limit < len(drivers) {
+		drivers = drivers[:p.Limit]
+	}
+	return drivers
 }
 
 // DriverRepository defines the interface for driver data access
@@ -41,6 +113,42 @@ type DriverRepository interface {
 	Create(ctx interface{}, driver *Driver) error
 	Update(ctx interface{}, id string, driver *Driver) error
 	GetByID(ctx interface{}, id string) (*Driver, error)
-	List(ctx interface{}, page, pageSize int) ([]*Driver, int64, error)
-	FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *TaxiType) ([]*Driver, error)
+	List(ctx interface{}, filter DriverFilter, page, pageSize int) ([]*Driver, int64, error)
+	// FindNearby returns the page of drivers within radiusKm, nearest first
+	FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *TaxiType, filter DriverFilter, page NearbyPage) ([]*Driver, error)
+	GetByPhone(ctx interface{}, phone string) (*Driver, error)
+	GetByEmail(ctx interface{}, email string) (*Driver, error)
+	GetByPlate(ctx interface{}, plate string) (*Driver, error)
+	Delete(ctx interface{}, id string) error
+}
+
+// DriverStreamRepository reads drivers in ID order without loading them all at once
+type DriverStreamRepository interface {
+	// StreamDrivers calls fn with every driver matching the filter whose ID is
+	// after afterID, in ID order, and stops at the first error fn returns
+	StreamDrivers(ctx interface{}, filter DriverFilter, afterID string, fn func(*Driver) error) error
+}
+
+// DriverPageRepository reads drivers in pages ordered by ID. Unlike offset
+// pages, keyset pages neither skip nor repeat drivers while others are created
+// or deleted between them.
+type DriverPageRepository interface {
+	CountDrivers(ctx interface{}, filter DriverFilter) (int64, error)
+	// ListDriversAfter returns up to limit drivers matching the filter whose ID
+	// is after afterID, in ID order; an empty afterID starts at the first driver
+	ListDriversAfter(ctx interface{}, filter DriverFilter, afterID string, limit int) ([]*Driver, error)
+}
+
+// ChangePosition is a point in the driver change feed, which is ordered by
+// updatedAt and then by ID. An empty ID includes every change at UpdatedAt.
+type ChangePosition struct {
+	UpdatedAt time.Time
+	ID        string
+}
+
+// DriverChangeRepository lists changed drivers for delta syncs
+type DriverChangeRepository interface {
+	// ListChanges returns up to limit drivers matching the filter, tombstones of
+	// deleted drivers included, changed after the position and before until
+	ListChanges(ctx interface{}, filter DriverFilter, after ChangePosition, until time.Time, limit int) ([]*Driver, error)
 }
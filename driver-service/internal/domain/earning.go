@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// EarningType distinguishes trip earnings from manual corrections
+type EarningType string
+
+const (
+	EarningTypeTrip       EarningType = "trip"
+	EarningTypeAdjustment EarningType = "adjustment"
+)
+
+// EarningsPeriod is the bucket size earnings are aggregated by
+type EarningsPeriod string
+
+const (
+	EarningsPeriodDaily  EarningsPeriod = "daily"
+	EarningsPeriodWeekly EarningsPeriod = "weekly"
+)
+
+// IsValid reports whether the period is supported
+func (p EarningsPeriod) IsValid() bool {
+	return p == EarningsPeriodDaily || p == EarningsPeriodWeekly
+}
+
+// Earning is an entry in a driver's earnings ledger. Entries are append-only:
+// they are only ever attached to a payout, and mistakes are corrected with an
+// adjustment rather than by editing the entry.
+type Earning struct {
+	ID       string      `bson:"_id,omitempty" json:"id" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string      `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Type     EarningType `bson:"type" json:"type" example:"trip"`
+	TripID   string      `bson:"tripId,omitempty" json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+	// Gross is the fare of a trip; it is zero for adjustments
+	Gross float64 `bson:"gross" json:"gross" example:"232.4"`
+	// Commission is the platform's share of the fare
+	Commission float64 `bson:"commission" json:"commission" example:"34.86"`
+	// Net is what the driver is owed; adjustments may be negative
+	Net      float64 `bson:"net" json:"net" example:"197.54"`
+	Currency string  `bson:"currency" json:"currency" example:"TRY"`
+	// Reason and CreatedBy form the audit trail of an adjustment
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty" example:"Toll reimbursement"`
+	CreatedBy string    `bson:"createdBy,omitempty" json:"createdBy,omitempty" example:"ops-admin"`
+	PayoutID  string    `bson:"payoutId,omitempty" json:"payoutId,omitempty" example:"6573b2f3c4d5e6f7a8b9c0d1"`
+	EarnedAt  time.Time `bson:"earnedAt" json:"earnedAt" example:"2025-12-06T01:00:00Z"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// EarningsTotals sums ledger entries
+type EarningsTotals struct {
+	Trips       int     `bson:"trips" json:"trips" example:"12"`
+	Gross       float64 `bson:"gross" json:"gross" example:"2788.8"`
+	Commission  float64 `bson:"commission" json:"commission" example:"418.32"`
+	Adjustments float64 `bson:"adjustments" json:"adjustments" example:"50"`
+	Net         float64 `bson:"net" json:"net" example:"2420.48"`
+}
+
+// Add sums other into t, rounded to kuruş
+func (t *EarningsTotals) Add(other EarningsTotals) {
+	t.Trips += other.Trips
+	t.Gross = math.Round((t.Gross+other.Gross)*100) / 100
+	t.Commission = math.Round((t.Commission+other.Commission)*100) / 100
+	t.Adjustments = math.Round((t.Adjustments+other.Adjustments)*100) / 100
+	t.Net = math.Round((t.Net+other.Net)*100) / 100
+}
+
+// EarningsBucket sums a driver's ledger over one day or week
+type EarningsBucket struct {
+	Start          time.Time `bson:"_id" json:"start" example:"2025-12-01T00:00:00+03:00"`
+	EarningsTotals `bson:",inline"`
+}
+
+// PayoutLine is what a payout owes one driver
+type PayoutLine struct {
+	DriverID       string `bson:"_id" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	EarningsTotals `bson:",inline"`
+}
+
+// Payout is a batch of unpaid earnings handed to finance for settlement
+type Payout struct {
+	ID string `bson:"_id,omitempty" json:"id" example:"6573b2f3c4d5e6f7a8b9c0d1"`
+	// From is unset when the payout covers everything unpaid before To
+	From      *time.Time   `bson:"from,omitempty" json:"from,omitempty" example:"2025-12-01T00:00:00Z"`
+	To        time.Time    `bson:"to" json:"to" example:"2025-12-08T00:00:00Z"`
+	Currency  string       `bson:"currency" json:"currency" example:"TRY"`
+	Drivers   int          `bson:"drivers" json:"drivers" example:"1"`
+	Total     float64      `bson:"total" json:"total" example:"2420.48"`
+	Lines     []PayoutLine `bson:"lines" json:"lines"`
+	CreatedBy string       `bson:"createdBy,omitempty" json:"createdBy,omitempty" example:"ops-admin"`
+	CreatedAt time.Time    `bson:"createdAt" json:"createdAt" example:"2025-12-08T06:00:00Z"`
+}
+
+// EarningsRepository stores the earnings ledger and the payouts drawn from it
+type EarningsRepository interface {
+	// Record appends an entry. Recording a trip that already has an entry is a no-op.
+	Record(ctx interface{}, earning *Earning) error
+	// Summarize buckets the driver's entries earned in [from, to) by day or
+	// week, starting on Monday, in the given location; empty buckets are omitted
+	Summarize(ctx interface{}, driverID string, from, to time.Time, period EarningsPeriod, location *time.Location) ([]EarningsBucket, error)
+	// ListAdjustments returns the newest adjustments first, optionally of one driver
+	ListAdjustments(ctx interface{}, driverID string, limit int) ([]*Earning, error)
+	// CreatePayout claims the unpaid entries earned in [payout.From, payout.To)
+	// for the payout, fills in its lines and stores it. Nothing is stored and
+	// payout.Lines stays empty when there is nothing to pay.
+	CreatePayout(ctx interface{}, payout *Payout) error
+	GetPayout(ctx interface{}, id string) (*Payout, error)
+	// ListPayouts returns the newest payouts first, without their lines
+	ListPayouts(ctx interface{}, limit int) ([]*Payout, error)
+}
@@ -0,0 +1,11 @@
+package domain
+
+// FareEstimate is the expected route and price of a trip from pickup to dropoff
+type FareEstimate struct {
+	DistanceKm  float64 `bson:"distanceKm" json:"distanceKm" example:"7.4"`
+	DurationSec int     `bson:"durationSec" json:"durationSec" example:"1260"`
+	Amount      float64 `bson:"amount" json:"amount" example:"232.4"`
+	Currency    string  `bson:"currency" json:"currency" example:"TRY"`
+	// Provider is the routing provider that measured the route
+	Provider string `bson:"provider" json:"provider" example:"osrm"`
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Fleet groups the drivers that work for the same taxi company
+type Fleet struct {
+	ID          string    `bson:"_id,omitempty" json:"id" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Name        string    `bson:"name" json:"name" example:"Kadıköy Taksi"`
+	CompanyName string    `bson:"companyName,omitempty" json:"companyName,omitempty" example:"Kadıköy Taksi Ltd. Şti."`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// DriverFilter narrows driver listings and nearby searches; zero values match every driver
+type DriverFilter struct {
+	FleetID string
+	// City only matches drivers stored in the city
+	City string
+	// Tags only matches drivers carrying every one of them
+	Tags []string
+	// Attributes only matches drivers with every one of them; an empty value
+	// matches any value of the key
+	Attributes map[string]string
+	// Live asks for drivers with a recent heartbeat only; nil leaves it to the service default
+	Live *bool
+	// SeenSince excludes drivers whose last heartbeat is older; set from Live by the use case
+	SeenSince time.Time
+	// CreatedBefore excludes drivers created after it, so a snapshot read in
+	// pages does not pick up drivers created while it runs
+	CreatedBefore time.Time
+}
+
+// FleetRepository defines the interface for fleet data access
+type FleetRepository interface {
+	Create(ctx interface{}, fleet *Fleet) error
+	GetByID(ctx interface{}, id string) (*Fleet, error)
+	List(ctx interface{}) ([]*Fleet, error)
+}
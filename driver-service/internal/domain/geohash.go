@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"encoding/json"
+	"sync/atomic"
+
+	"github.com/bitaksi/driver-service/pkg/geo"
+)
+
+// geohashPrecision is the length of the geohash locations are rendered with;
+// 0 leaves it out
+var geohashPrecision atomic.Int32
+
+// SetGeohashPrecision sets the length of the geohash rendered beside the
+// coordinates of every location. It is called once at startup; 0 turns it off.
+func SetGeohashPrecision(precision int) {
+	if precision > geo.MaxPrecision {
+		precision = geo.MaxPrecision
+	}
+	if precision < 0 {
+		precision = 0
+	}
+	geohashPrecision.Store(int32(precision))
+}
+
+// MarshalJSON renders the location with its geohash when one is configured
+func (l Location) MarshalJSON() ([]byte, error) {
+	// plain has the fields of Location without this method
+	type plain Location
+	rendered := plain(l)
+	rendered.Geohash = ""
+	if precision := int(geohashPrecision.Load()); precision > 0 && l.onGlobe() {
+		rendered.Geohash = geo.Encode(l.Lat, l.Lon, precision)
+	}
+	return json.Marshal(rendered)
+}
+
+// onGlobe reports whether the coordinates are in range
+func (l Location) onGlobe() bool {
+	return l.Lat >= -90 && l.Lat <= 90 && l.Lon >= -180 && l.Lon <= 180
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "context"
+
+// Roles of the callers the gateway forwards requests for. Callers without a
+// role have full access.
+const (
+	RoleFleetAdmin = "fleet_admin"
+	RoleRider      = "rider"
+	// RoleDriverDevice is a driver app authenticated with a device token; its
+	// UserID is the driver's ID
+	RoleDriverDevice = "driver_device"
+)
+
+// Identity is the verified caller the gateway performed a request for
+type Identity struct {
+	UserID string
+	Role   string
+	// TenantID is the fleet a fleet admin manages
+	TenantID string
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity returns a copy of ctx carrying the caller's identity
+func ContextWithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the caller's identity; ok is false for requests
+// that did not come with one, such as internal jobs or direct calls
+func IdentityFromContext(ctx context.Context) (identity Identity, ok bool) {
+	identity, ok = ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,146 @@
+package domain
+
+import "time"
+
+// IncidentCategory is what kind of complaint an incident is
+type IncidentCategory string
+
+const (
+	IncidentCategorySafety  IncidentCategory = "safety"
+	IncidentCategoryConduct IncidentCategory = "conduct"
+	IncidentCategoryVehicle IncidentCategory = "vehicle"
+	IncidentCategoryFare    IncidentCategory = "fare"
+	IncidentCategoryRoute   IncidentCategory = "route"
+	IncidentCategoryOther   IncidentCategory = "other"
+)
+
+// IsValid checks if the category is known
+func (c IncidentCategory) IsValid() bool {
+	switch c {
+	case IncidentCategorySafety, IncidentCategoryConduct, IncidentCategoryVehicle,
+		IncidentCategoryFare, IncidentCategoryRoute, IncidentCategoryOther:
+		return true
+	}
+	return false
+}
+
+// IncidentSeverity is how serious an incident is
+type IncidentSeverity string
+
+const (
+	IncidentSeverityLow      IncidentSeverity = "low"
+	IncidentSeverityMedium   IncidentSeverity = "medium"
+	IncidentSeverityHigh     IncidentSeverity = "high"
+	IncidentSeverityCritical IncidentSeverity = "critical"
+)
+
+// IsValid checks if the severity is known
+func (s IncidentSeverity) IsValid() bool {
+	switch s {
+	case IncidentSeverityLow, IncidentSeverityMedium, IncidentSeverityHigh, IncidentSeverityCritical:
+		return true
+	}
+	return false
+}
+
+// IsSevere reports whether the driver is suspended while an incident of this
+// severity is investigated
+func (s IncidentSeverity) IsSevere() bool {
+	return s == IncidentSeverityHigh || s == IncidentSeverityCritical
+}
+
+// IncidentStatus is the state of an incident's investigation
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen          IncidentStatus = "open"
+	IncidentStatusInvestigating IncidentStatus = "investigating"
+	IncidentStatusResolved      IncidentStatus = "resolved"
+)
+
+// IsValid checks if the status is known
+func (s IncidentStatus) IsValid() bool {
+	return s == IncidentStatusOpen || s == IncidentStatusInvestigating || s == IncidentStatusResolved
+}
+
+// CanMoveTo reports whether an incident may go from s to next: open incidents
+// are investigated or resolved, investigated ones resolved, and resolved ones
+// stay resolved
+func (s IncidentStatus) CanMoveTo(next IncidentStatus) bool {
+	switch s {
+	case IncidentStatusOpen:
+		return next == IncidentStatusInvestigating || next == IncidentStatusResolved
+	case IncidentStatusInvestigating:
+		return next == IncidentStatusResolved
+	}
+	return false
+}
+
+// IncidentOutcome is the finding an incident is resolved with
+type IncidentOutcome string
+
+const (
+	// IncidentOutcomeUpheld incidents keep the driver suspended until reinstated by hand
+	IncidentOutcomeUpheld IncidentOutcome = "upheld"
+	// IncidentOutcomeDismissed incidents lift the suspension they caused
+	IncidentOutcomeDismissed IncidentOutcome = "dismissed"
+)
+
+// IsValid checks if the outcome is known
+func (o IncidentOutcome) IsValid() bool {
+	return o == IncidentOutcomeUpheld || o == IncidentOutcomeDismissed
+}
+
+// Incident is a complaint about a driver reported by a rider or by operations
+type Incident struct {
+	ID       string `bson:"_id,omitempty" json:"id" example:"6575a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	// FleetID is the driver's fleet when the incident was reported
+	FleetID string `bson:"fleetId,omitempty" json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// TripID is the trip the incident happened on, if any
+	TripID      string           `bson:"tripId,omitempty" json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+	Category    IncidentCategory `bson:"category" json:"category" example:"conduct" enums:"safety,conduct,vehicle,fare,route,other"`
+	Severity    IncidentSeverity `bson:"severity" json:"severity" example:"medium" enums:"low,medium,high,critical"`
+	Description string           `bson:"description" json:"description" example:"Driver was on the phone during the whole ride"`
+	Status      IncidentStatus   `bson:"status" json:"status" example:"open" enums:"open,investigating,resolved"`
+	// ReportedBy and ReporterRole identify the caller who reported it; empty for operators
+	ReportedBy   string `bson:"reportedBy,omitempty" json:"reportedBy,omitempty" example:"rider-42"`
+	ReporterRole string `bson:"reporterRole,omitempty" json:"reporterRole,omitempty" example:"rider"`
+	// DriverSuspended is set when reporting the incident, or confirming its
+	// severity, suspended the driver
+	DriverSuspended bool `bson:"driverSuspended,omitempty" json:"driverSuspended" example:"false"`
+	// IdentityRevoked is set when reporting the incident, or confirming its
+	// severity, revoked the driver's identity verification
+	IdentityRevoked bool            `bson:"identityRevoked,omitempty" json:"identityRevoked" example:"false"`
+	Outcome         IncidentOutcome `bson:"outcome,omitempty" json:"outcome,omitempty" example:"dismissed" enums:"upheld,dismissed"`
+	// Note is the latest remark of the investigation
+	Note       string     `bson:"note,omitempty" json:"note,omitempty" example:"Rider confirmed the driver used a hands-free set"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt  time.Time  `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+	ResolvedAt *time.Time `bson:"resolvedAt,omitempty" json:"resolvedAt,omitempty" example:"2025-12-07T09:30:00Z"`
+}
+
+// IncidentFilter selects incidents; empty fields match every incident
+type IncidentFilter struct {
+	DriverID string
+	FleetID  string
+	TripID   string
+	Category IncidentCategory
+	Severity IncidentSeverity
+	Status   IncidentStatus
+}
+
+// IncidentRepository defines the interface for incident data access
+type IncidentRepository interface {
+	Create(ctx interface{}, incident *Incident) error
+	GetByID(ctx interface{}, id string) (*Incident, error)
+	// List returns a page of the incidents matching the filter, newest first,
+	// and how many match in total
+	List(ctx interface{}, filter IncidentFilter, page, pageSize int) ([]*Incident, int64, error)
+	// Transition stores the status, severity, effects, outcome, note and times
+	// of an incident that is still in status from; it reports false when the
+	// status changed meanwhile
+	Transition(ctx interface{}, incident *Incident, from IncidentStatus) (bool, error)
+	// CountUnresolvedSuspensions counts the driver's unresolved incidents that suspended the driver
+	CountUnresolvedSuspensions(ctx interface{}, driverID string) (int64, error)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// Index states reported by the index catalog
+const (
+	IndexPresent    = "present"
+	IndexMissing    = "missing"
+	IndexMismatch   = "mismatch"
+	IndexUnexpected = "unexpected"
+)
+
+// Index sync job statuses
+const (
+	IndexSyncRunning   = "running"
+	IndexSyncCompleted = "completed"
+	IndexSyncFailed    = "failed"
+)
+
+// IndexStatus compares one expected index with what exists in MongoDB
+type IndexStatus struct {
+	Collection string `json:"collection" example:"drivers"`
+	Name       string `json:"name" example:"fleetId_createdAt"`
+	// Keys is the expected key pattern, or the existing one for unexpected indexes
+	Keys string `json:"keys" example:"fleetId:1,createdAt:-1"`
+	// ActualKeys is set when an index with the expected name has different keys
+	ActualKeys string `json:"actualKeys,omitempty" example:"fleetId:1"`
+	State      string `json:"state" example:"present" enums:"present,missing,mismatch,unexpected"`
+}
+
+// IndexReport lists expected and existing indexes across all collections
+type IndexReport struct {
+	Indexes    []IndexStatus `json:"indexes"`
+	Missing    int           `json:"missing" example:"1"`
+	Mismatched int           `json:"mismatched" example:"0"`
+	Unexpected int           `json:"unexpected" example:"0"`
+	// InSync is true when every expected index exists with the expected keys
+	InSync    bool      `json:"inSync" example:"false"`
+	CheckedAt time.Time `json:"checkedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// IndexSyncError records an index that could not be created
+type IndexSyncError struct {
+	Collection string `json:"collection" example:"drivers"`
+	Name       string `json:"name" example:"phone_unique"`
+	Error      string `json:"error" example:"E11000 duplicate key error"`
+}
+
+// IndexSyncJob tracks the creation of missing indexes
+type IndexSyncJob struct {
+	ID     string `json:"id" example:"idxsync-1733446800000"`
+	Status string `json:"status" example:"running" enums:"running,completed,failed"`
+	// Actor identifies who started the sync, for the audit log
+	Actor      string     `json:"actor,omitempty" example:"ops@bitaksi.com"`
+	StartedAt  time.Time  `json:"startedAt" example:"2025-12-06T01:00:00Z"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" example:"2025-12-06T01:00:05Z"`
+	Total      int        `json:"total" example:"2"`
+	Completed  int        `json:"completed" example:"1"`
+	// Current is the index being built, as "collection.name"
+	Current string           `json:"current,omitempty" example:"drivers.fleetId_createdAt"`
+	Created []string         `json:"created"`
+	Failed  []IndexSyncError `json:"failed"`
+	// Skipped lists mismatched indexes, which must be dropped by hand before they can be rebuilt
+	Skipped []string `json:"skipped"`
+}
+
+// IndexCatalog inspects and creates the indexes the repositories rely on
+type IndexCatalog interface {
+	Report(ctx interface{}) (*IndexReport, error)
+	CreateIndex(ctx interface{}, collection, name string) error
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// KYCStatus is the state of an identity check
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// IsValid checks if the status is known
+func (s KYCStatus) IsValid() bool {
+	return s == KYCStatusPending || s == KYCStatusApproved || s == KYCStatusRejected
+}
+
+// KYCCheck is an identity check of a driver by a KYC provider. A driver may
+// have several over time; the latest one tells the state of verification.
+type KYCCheck struct {
+	ID       string `bson:"_id" json:"id" example:"6570a1f2c3d4e5f6a7b8c9d1"`
+	DriverID string `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Provider string `bson:"provider" json:"provider" example:"sandbox"`
+	// Reference identifies the check at the provider
+	Reference string    `bson:"reference" json:"reference" example:"sbx_9f3c2a1b7e6d5c4a"`
+	Status    KYCStatus `bson:"status" json:"status" example:"pending" enums:"pending,approved,rejected"`
+	// Reason explains a rejection, as given by the provider
+	Reason      string     `bson:"reason,omitempty" json:"reason,omitempty" example:"document is not legible"`
+	SubmittedAt time.Time  `bson:"submittedAt" json:"submittedAt" example:"2025-12-06T01:00:00Z"`
+	DecidedAt   *time.Time `bson:"decidedAt,omitempty" json:"decidedAt,omitempty" example:"2025-12-06T01:05:00Z"`
+}
+
+// KYCRepository defines the interface for identity check data access
+type KYCRepository interface {
+	Create(ctx interface{}, check *KYCCheck) error
+	// GetLatest returns the driver's most recent check
+	GetLatest(ctx interface{}, driverID string) (*KYCCheck, error)
+	GetByReference(ctx interface{}, provider, reference string) (*KYCCheck, error)
+	// ListPending returns pending checks submitted up to the given time, oldest first
+	ListPending(ctx interface{}, submittedUntil time.Time, limit int) ([]*KYCCheck, error)
+	// Decide records the outcome of a pending check; it reports false when the
+	// check was already decided
+	Decide(ctx interface{}, id string, status KYCStatus, reason string, at time.Time) (bool, error)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// LicenseClass is a Turkish driving licence class
+type LicenseClass string
+
+// licenseClasses lists the classes that allow driving a taxi
+var licenseClasses = map[LicenseClass]bool{
+	"B": true, "BE": true,
+	"C1": true, "C1E": true, "C": true, "CE": true,
+	"D1": true, "D1E": true, "D": true, "DE": true,
+}
+
+// IsValid reports whether the class allows driving a taxi
+func (c LicenseClass) IsValid() bool {
+	return licenseClasses[c]
+}
+
+// DriverLicense is the driving licence a driver holds
+type DriverLicense struct {
+	Number    string       `bson:"number" json:"number" example:"TR-1234567"`
+	Class     LicenseClass `bson:"class" json:"class" example:"B"`
+	ExpiresAt time.Time    `bson:"expiresAt" json:"expiresAt" example:"2030-01-01T00:00:00Z"`
+}
+
+// Expired reports whether the licence is no longer valid at now
+func (l *DriverLicense) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// LicenseRepository tracks licence expiry across drivers
+type LicenseRepository interface {
+	// ListExpiringLicenses returns drivers matching the filter whose licence
+	// expires before the given time, soonest first
+	ListExpiringLicenses(ctx interface{}, filter DriverFilter, before time.Time) ([]*Driver, error)
+	// FlagExpiredLicenses marks drivers whose licence expired by now as not
+	// dispatchable and unavailable, and returns the drivers it flagged as they
+	// were before the update
+	FlagExpiredLicenses(ctx interface{}, now time.Time) ([]*Driver, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// DriverPhoto is a driver's profile picture. The picture is stored in every
+// size it was rendered in; URLs change with each upload so they can be cached
+// indefinitely.
+type DriverPhoto struct {
+	// URL is the full-size picture, scaled down to the configured maximum
+	URL string `bson:"url" json:"url" example:"https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/full.jpg"`
+	// Thumbnails are square crops keyed by their side in pixels
+	Thumbnails map[string]string `bson:"thumbnails" json:"thumbnails" example:"256:https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/256.jpg"`
+	Width      int               `bson:"width" json:"width" example:"1024"`
+	Height     int               `bson:"height" json:"height" example:"768"`
+	// Keys are the storage keys of the files, removed when the photo is replaced
+	Keys       []string  `bson:"keys" json:"-"`
+	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt" example:"2025-12-06T01:00:00Z"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// DriverQuota is the driver seats a tenant pays for and how many are taken.
+// Tenants are fleets; drivers outside a fleet are not counted.
+type DriverQuota struct {
+	TenantID string `json:"tenantId" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// MaxDrivers is the quota; 0 is unlimited
+	MaxDrivers int   `json:"maxDrivers" example:"50"`
+	Drivers    int64 `json:"drivers" example:"42"`
+	// Remaining is left out for unlimited tenants, and is 0 once the quota is
+	// used up even if concurrent creations overshot it
+	Remaining   *int64  `json:"remaining,omitempty" example:"8"`
+	UsedPercent float64 `json:"usedPercent" example:"84"`
+	// Thresholds are the usage percentages a TenantQuotaThresholdReached event is emitted at
+	Thresholds []int `json:"thresholds" example:"80,100"`
+}
+
+// EventTenantQuotaThresholdReached records a tenant's driver count reaching a
+// share of its quota, so sales can offer more seats before drivers are refused
+const EventTenantQuotaThresholdReached = "TenantQuotaThresholdReached"
+
+// TenantQuotaThresholdReached is emitted when a driver joining a tenant takes
+// its usage to or past a threshold
+type TenantQuotaThresholdReached struct {
+	TenantID   string    `json:"tenantId" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Threshold  int       `json:"threshold" example:"80"`
+	Drivers    int64     `json:"drivers" example:"40"`
+	MaxDrivers int       `json:"maxDrivers" example:"50"`
+	Timestamp  time.Time `json:"timestamp" example:"2025-12-06T01:00:00Z"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ErasureTrigger tells why a driver's personal data was erased
+type ErasureTrigger string
+
+const (
+	// ErasureTriggerRetention is the retention job erasing a deleted driver past the retention window
+	ErasureTriggerRetention ErasureTrigger = "retention"
+	// ErasureTriggerRequest is an explicit erasure request, e.g. from the driver
+	ErasureTriggerRequest ErasureTrigger = "request"
+)
+
+// Erasure is the audit record of erasing a driver's personal data. It keeps
+// what was erased and why, and nothing about the driver but the ID.
+type Erasure struct {
+	ID       string         `bson:"_id" json:"id" example:"6574a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string         `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Trigger  ErasureTrigger `bson:"trigger" json:"trigger" example:"request" enums:"retention,request"`
+	// RequestedBy is the operator who made an explicit request
+	RequestedBy string `bson:"requestedBy,omitempty" json:"requestedBy,omitempty" example:"ops-admin"`
+	// Reason is the operator's note on an explicit request, e.g. a ticket number
+	Reason string `bson:"reason,omitempty" json:"reason,omitempty" example:"KVKK request #4711"`
+	// Records counts the records deleted or scrubbed per collection
+	Records map[string]int64 `bson:"records" json:"records"`
+	// Files is the number of stored files, such as photos, that were deleted
+	Files    int       `bson:"files" json:"files" example:"4"`
+	ErasedAt time.Time `bson:"erasedAt" json:"erasedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// RetentionRepository erases personal data across the driver collections.
+// Trips, shifts and earnings are kept, with nothing left to tie their driver
+// ID to a person, so statistics and the books stay whole.
+type RetentionRepository interface {
+	// ListExpired returns the IDs of up to limit drivers deleted before the
+	// given time whose personal data has not been erased yet
+	ListExpired(ctx interface{}, deletedBefore time.Time, limit int) ([]string, error)
+	// Anonymize erases the personal data of a deleted driver and marks the
+	// tombstone erased. It returns the records it erased per collection and the
+	// storage keys of files the driver still referenced, which the caller deletes.
+	Anonymize(ctx interface{}, driverID string, at time.Time) (map[string]int64, []string, error)
+	// PurgeLocations deletes location history recorded before the given time
+	PurgeLocations(ctx interface{}, before time.Time) (int64, error)
+	RecordErasure(ctx interface{}, erasure *Erasure) error
+	// ListErasures returns the newest erasures first, optionally of one driver
+	ListErasures(ctx interface{}, driverID string, limit int) ([]*Erasure, error)
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// MaxFavoriteLocations caps the saved places of a rider
+const MaxFavoriteLocations = 20
+
+// Rider is a passenger who requests trips
+type Rider struct {
+	ID        string `bson:"_id,omitempty" json:"id" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	FirstName string `bson:"firstName" json:"firstName" example:"Elif"`
+	LastName  string `bson:"lastName" json:"lastName" example:"Yılmaz"`
+	// Phone is the rider's E.164 phone number and identifies the rider uniquely
+	Phone             string             `bson:"phone" json:"phone" example:"+905551234567"`
+	Email             string             `bson:"email,omitempty" json:"email,omitempty" example:"elif@example.com"`
+	FavoriteLocations []FavoriteLocation `bson:"favoriteLocations" json:"favoriteLocations"`
+	CreatedAt         time.Time          `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt         time.Time          `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// FavoriteLocation is a place a rider saved for quick trip requests
+type FavoriteLocation struct {
+	ID       string   `bson:"id" json:"id" example:"fav_3f9a1c2b"`
+	Label    string   `bson:"label" json:"label" example:"Home"`
+	Address  string   `bson:"address,omitempty" json:"address,omitempty" example:"Caferağa Mah. Moda Cad. No:1, Kadıköy"`
+	Location Location `bson:"location" json:"location"`
+}
+
+// FavoriteLocation returns the saved place with the given ID, or nil
+func (r *Rider) FavoriteLocation(id string) *FavoriteLocation {
+	for i := range r.FavoriteLocations {
+		if r.FavoriteLocations[i].ID == id {
+			return &r.FavoriteLocations[i]
+		}
+	}
+	return nil
+}
+
+// RiderRepository defines the interface for rider data access.
+// Create and Update fail with "phone is already registered to another rider"
+// when the phone belongs to someone else.
+type RiderRepository interface {
+	Create(ctx interface{}, rider *Rider) error
+	Update(ctx interface{}, rider *Rider) error
+	GetByID(ctx interface{}, id string) (*Rider, error)
+	// GetByPhone fails with "rider not found" when no rider has the phone
+	GetByPhone(ctx interface{}, phone string) (*Rider, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// ScheduledShift is a shift a fleet plans ahead for a driver. The scheduler
+// puts the driver on shift when it starts and off shift when it ends.
+type ScheduledShift struct {
+	ID       string `bson:"_id,omitempty" json:"id" example:"6574a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `bson:"driverId" json:"driverId" example:"507f1f77bcf86cd799439011"`
+	// FleetID is the driver's fleet when the shift was planned, for fleet calendars
+	FleetID  string    `bson:"fleetId,omitempty" json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	StartsAt time.Time `bson:"startsAt" json:"startsAt" example:"2025-12-08T06:00:00Z"`
+	EndsAt   time.Time `bson:"endsAt" json:"endsAt" example:"2025-12-08T14:00:00Z"`
+	Note     string    `bson:"note,omitempty" json:"note,omitempty" example:"Airport rank"`
+	// StartedAt is when the scheduler put the driver on shift
+	StartedAt *time.Time `bson:"startedAt,omitempty" json:"startedAt,omitempty" example:"2025-12-08T06:00:12Z"`
+	// StartError is why the driver could not be put on shift, e.g. an unverified phone
+	StartError string `bson:"startError,omitempty" json:"startError,omitempty" example:"phone must be verified before going on shift"`
+	// EndedAt is when the scheduler took the driver off shift
+	EndedAt   *time.Time `bson:"endedAt,omitempty" json:"endedAt,omitempty" example:"2025-12-08T14:00:09Z"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt time.Time  `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// Started reports whether the scheduler has handled the start of the shift
+func (s *ScheduledShift) Started() bool {
+	return s.StartedAt != nil
+}
+
+// ScheduleFilter selects shifts overlapping [From, To); empty fields match every shift
+type ScheduleFilter struct {
+	DriverID string
+	FleetID  string
+	From     time.Time
+	To       time.Time
+}
+
+// ScheduleRepository defines the interface for planned shift data access
+type ScheduleRepository interface {
+	Create(ctx interface{}, shift *ScheduledShift) error
+	GetByID(ctx interface{}, id string) (*ScheduledShift, error)
+	// Update stores the times and note of a shift that has not ended
+	Update(ctx interface{}, shift *ScheduledShift) error
+	Delete(ctx interface{}, id string) error
+	// List returns the shifts matching the filter by start time
+	List(ctx interface{}, filter ScheduleFilter) ([]*ScheduledShift, error)
+	// ClaimStart marks the start of one shift running at now as handled and
+	// returns it, or nil when there is none. Each shift is claimed once, by
+	// whichever instance gets to it first.
+	ClaimStart(ctx interface{}, now time.Time) (*ScheduledShift, error)
+	// ClaimEnd marks the end of one started shift that ended by now as
+	// handled and returns it, or nil when there is none
+	ClaimEnd(ctx interface{}, now time.Time) (*ScheduledShift, error)
+	// SetStartError records why the driver could not be put on shift
+	SetStartError(ctx interface{}, id, reason string) error
+}
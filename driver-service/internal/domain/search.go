@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// MaxSearchPrefix is the longest prefix stored for autocomplete. Longer query
+// words are matched on their first MaxSearchPrefix characters.
+const MaxSearchPrefix = 20
+
+// Fields an autocomplete query word can match
+const (
+	SearchFieldName  = "name"
+	SearchFieldPlate = "plate"
+)
+
+// ErrSearchTimeout is returned when a search runs past its time budget
+var ErrSearchTimeout = errors.New("search exceeded its time budget")
+
+// DriverSearchRepository finds drivers by the start of their name or plate
+type DriverSearchRepository interface {
+	// SearchDriverPrefixes returns up to limit drivers matching the filter that
+	// have, for every one of words, a key from SearchKeys equal to it. It
+	// returns ErrSearchTimeout once ctx's deadline passes.
+	SearchDriverPrefixes(ctx interface{}, words []string, filter DriverFilter, limit int) ([]*Driver, error)
+}
+
+// searchFolds removes the Turkish diacritics after lowercasing, so "Şükrü"
+// matches "sukru" and the other way round
+var searchFolds = map[rune]rune{
+	'ı': 'i', 'ç': 'c', 'ğ': 'g', 'ö': 'o', 'ş': 's', 'ü': 'u',
+	'â': 'a', 'î': 'i', 'û': 'u',
+}
+
+// foldSearchRune lowercases a letter the Turkish way and removes its
+// diacritic; it returns 0 for characters that separate words
+func foldSearchRune(r rune) rune {
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+		return 0
+	}
+	r = unicode.TurkishCase.ToLower(r)
+	if folded, ok := searchFolds[r]; ok {
+		return folded
+	}
+	return r
+}
+
+// SearchWord is a folded word of a name or plate
+type SearchWord struct {
+	Text string
+	// Start is the index of the word's first character in the original text,
+	// counted in characters. Folding maps each character to one character, so
+	// a prefix of Text covers as many characters of the original.
+	Start int
+}
+
+// SplitSearchWords folds s and splits it into words at every character that
+// is not a letter or digit. Plates are also split where letters and digits
+// meet, so "34ABC123" has the words 34, abc and 123.
+func SplitSearchWords(s string, plate bool) []SearchWord {
+	var words []SearchWord
+	var word []rune
+	start := 0
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, SearchWord{Text: string(word), Start: start})
+			word = word[:0]
+		}
+	}
+
+	for i, r := range []rune(s) {
+		folded := foldSearchRune(r)
+		if folded == 0 {
+			flush()
+			continue
+		}
+		if plate && len(word) > 0 && unicode.IsDigit(folded) != unicode.IsDigit(word[len(word)-1]) {
+			flush()
+		}
+		if len(word) == 0 {
+			start = i
+		}
+		word = append(word, folded)
+	}
+	flush()
+	return words
+}
+
+// FoldSearchPlate folds a plate into one word, ignoring separators
+func FoldSearchPlate(plate string) string {
+	var b strings.Builder
+	for _, r := range plate {
+		if folded := foldSearchRune(r); folded != 0 {
+			b.WriteRune(folded)
+		}
+	}
+	return b.String()
+}
+
+// SearchQueryWords folds an autocomplete query into the words to match, each
+// cut to MaxSearchPrefix characters; duplicates are dropped
+func SearchQueryWords(query string) []string {
+	var words []string
+	seen := map[string]bool{}
+	for _, word := range SplitSearchWords(query, false) {
+		text := truncateRunes(word.Text, MaxSearchPrefix)
+		if !seen[text] {
+			seen[text] = true
+			words = append(words, text)
+		}
+	}
+	return words
+}
+
+// SearchKeys returns the keys a driver is found by in autocomplete: every
+// prefix, up to MaxSearchPrefix characters, of each word of the name, of the
+// whole plate and of each letter or digit group of the plate
+func SearchKeys(d *Driver) []string {
+	var words []string
+	for _, word := range SplitSearchWords(d.FirstName+" "+d.LastName, false) {
+		words = append(words, word.Text)
+	}
+	words = append(words, FoldSearchPlate(d.Plate))
+	for _, word := range SplitSearchWords(d.Plate, true) {
+		words = append(words, word.Text)
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, word := range words {
+		runes := []rune(word)
+		for n := 1; n <= len(runes) && n <= MaxSearchPrefix; n++ {
+			key := string(runes[:n])
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
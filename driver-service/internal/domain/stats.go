@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+// DriverStats aggregates a driver's activity over a date range
+type DriverStats struct {
+	DriverID       string    `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	From           time.Time `json:"from" example:"2025-11-01T00:00:00Z"`
+	To             time.Time `json:"to" example:"2025-12-01T00:00:00Z"`
+	CompletedTrips int       `json:"completedTrips" example:"42"`
+	// TripDistanceKm is the distance reported for completed trips
+	TripDistanceKm float64 `json:"tripDistanceKm" example:"311.6"`
+	// DistanceDrivenKm is computed from the recorded location history
+	DistanceDrivenKm float64 `json:"distanceDrivenKm" example:"528.3"`
+	// OnlineHours is the time spent on shift within the range
+	OnlineHours float64 `json:"onlineHours" example:"96.5"`
+	// AverageRating is the mean of the ratings given on trips in the range; 0 when unrated
+	AverageRating float64 `json:"averageRating" example:"4.8"`
+	RatingCount   int     `json:"ratingCount" example:"37"`
+	// LocationAnomalies counts the location updates that implied an implausible speed
+	LocationAnomalies int `json:"locationAnomalies" example:"0"`
+}
+
+// OnlineStats counts drivers by heartbeat liveness
+type OnlineStats struct {
+	Total   int64 `json:"total" example:"250"`
+	Online  int64 `json:"online" example:"180"`
+	Offline int64 `json:"offline" example:"70"`
+	// HeartbeatTimeoutSec is how recent a heartbeat must be for a driver to count as online
+	HeartbeatTimeoutSec int       `json:"heartbeatTimeoutSec" example:"120"`
+	AsOf                time.Time `json:"asOf" example:"2025-12-06T01:00:00Z"`
+}
+
+// DriverStatusCounts counts the stored drivers by status
+type DriverStatusCounts struct {
+	// Available drivers are on shift and not suspended
+	Available int64
+	OffShift  int64
+	Suspended int64
+}
+
+// DriverStatusRepository counts drivers by status for the business metrics
+type DriverStatusRepository interface {
+	CountByStatus(ctx interface{}) (*DriverStatusCounts, error)
+}
+
+// BusinessMetrics counts driver registrations, nearby searches and location
+// updates as the use case layer handles them
+type BusinessMetrics interface {
+	DriverCreated()
+	NearbySearch(results int)
+	LocationUpdated()
+}
+
+// HeartbeatRepository records driver app heartbeats
+type HeartbeatRepository interface {
+	RecordHeartbeat(ctx interface{}, driverID string, at time.Time) error
+	// CountOnline counts the drivers matching the filter and those seen since the given time
+	CountOnline(ctx interface{}, filter DriverFilter, since time.Time) (online, total int64, err error)
+}
+
+// ActivityRepository records the driver activity that statistics are computed from
+type ActivityRepository interface {
+	RecordLocation(ctx interface{}, driverID string, location Location, at time.Time) error
+	StartShift(ctx interface{}, driverID string, at time.Time) error
+	EndShift(ctx interface{}, driverID string, at time.Time) error
+}
+
+// StatsRepository aggregates driver statistics
+type StatsRepository interface {
+	DriverStats(ctx interface{}, driverID string, from, to time.Time) (*DriverStats, error)
+	// LocationAnomalies counts location anomalies per driver in [from, to),
+	// most first, for at most limit drivers
+	LocationAnomalies(ctx interface{}, from, to time.Time, limit int) ([]*LocationAnomalyCount, error)
+}
+
+// UtilizationDay compares how long the drivers of a taxi type were online on a
+// day with how long they spent on trips
+type UtilizationDay struct {
+	// Day is the calendar day in the report time zone
+	Day      string   `bson:"day" json:"day" example:"2025-12-01"`
+	TaxiType TaxiType `bson:"taxiType" json:"taxiType" example:"sari"`
+	// OnlineHours is the time drivers spent on shift
+	OnlineHours float64 `bson:"onlineHours" json:"onlineHours" example:"412.5"`
+	// BusyHours is the time drivers spent between accepting and completing trips
+	BusyHours float64 `bson:"busyHours" json:"busyHours" example:"268.1"`
+	// Utilization is BusyHours over OnlineHours; 0 without online time
+	Utilization float64 `bson:"utilization" json:"utilization" example:"0.65"`
+	// Partial is set on the current day, which is computed for the request
+	Partial bool `bson:"-" json:"partial,omitempty" example:"false"`
+}
+
+// UtilizationReport lists driver utilization per day and taxi type
+type UtilizationReport struct {
+	From     string           `json:"from" example:"2025-12-01"`
+	To       string           `json:"to" example:"2025-12-07"`
+	Timezone string           `json:"timezone" example:"Europe/Istanbul"`
+	Days     []UtilizationDay `json:"days"`
+}
+
+// UtilizationRepository aggregates driver time per taxi type and keeps the
+// daily rollups reports are read from
+type UtilizationRepository interface {
+	// AggregateUtilization sums online and busy time in [start, end) per taxi
+	// type; Day and Utilization are left to the caller
+	AggregateUtilization(ctx interface{}, start, end time.Time) ([]UtilizationDay, error)
+	// SaveRollup replaces the rollup of a day
+	SaveRollup(ctx interface{}, day string, rows []UtilizationDay) error
+	// Rollups returns the rollups of the days from to to, inclusive, by day and taxi type
+	Rollups(ctx interface{}, from, to string) ([]UtilizationDay, error)
+}
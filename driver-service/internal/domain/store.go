@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStoreUnavailable is returned when the database kept failing with transient
+// errors, e.g. while a replica set elects a new primary. Callers should retry later.
+var ErrStoreUnavailable = errors.New("database temporarily unavailable")
+
+// FailoverStats reports how the service has weathered MongoDB failovers since it started
+type FailoverStats struct {
+	// Primary is the address of the current replica-set primary, empty while there is none
+	Primary string `json:"primary" example:"mongo-1:27017"`
+	// PrimaryChanges counts elections that moved the primary to another member
+	PrimaryChanges      int64      `json:"primaryChanges" example:"2"`
+	LastPrimaryChangeAt *time.Time `json:"lastPrimaryChangeAt,omitempty" example:"2025-12-06T01:00:00Z"`
+	// TransientErrors counts operation attempts that failed with a retryable error
+	TransientErrors int64 `json:"transientErrors" example:"14"`
+	Retries         int64 `json:"retries" example:"12"`
+	// Recovered counts operations that succeeded after at least one retry
+	Recovered int64 `json:"recovered" example:"9"`
+	// Unavailable counts operations that gave up and were answered with 503
+	Unavailable     int64      `json:"unavailable" example:"1"`
+	LastTransientAt *time.Time `json:"lastTransientAt,omitempty" example:"2025-12-06T01:00:03Z"`
+}
+
+// QueryStats is the latency of one MongoDB command on one collection since the service started
+type QueryStats struct {
+	Collection string `json:"collection" example:"drivers"`
+	// Command is the database command, e.g. find, aggregate or update
+	Command string `json:"command" example:"find"`
+	Count   int64  `json:"count" example:"48210"`
+	Errors  int64  `json:"errors" example:"3"`
+	// Slow counts commands that took at least the slow query threshold
+	Slow    int64   `json:"slow" example:"12"`
+	TotalMs float64 `json:"totalMs" example:"96420.5"`
+	MaxMs   float64 `json:"maxMs" example:"812.4"`
+	// Buckets is the duration histogram; each bucket counts the commands that
+	// took at most LeMs, and the last one, without LeMs, counts all of them
+	Buckets []QueryBucket `json:"buckets"`
+}
+
+// QueryBucket is a cumulative bucket of a QueryStats histogram
+type QueryBucket struct {
+	LeMs  float64 `json:"leMs,omitempty" example:"50"`
+	Count int64   `json:"count" example:"47890"`
+}
+
+// ShadowReadStats reports how the driver reads mirrored to the shadow backend
+// compared with the primary since the service started
+type ShadowReadStats struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Backend is the kind of store mirrored to, e.g. mongodb
+	Backend string `json:"backend,omitempty" example:"mongodb"`
+	// SampleRatePercent is the share of reads mirrored
+	SampleRatePercent float64 `json:"sampleRatePercent" example:"10"`
+	InFlight          int     `json:"inFlight" example:"3"`
+	// Dropped counts sampled reads not mirrored because too many were in flight
+	Dropped    int64               `json:"dropped" example:"0"`
+	Operations []ShadowReadOpStats `json:"operations"`
+}
+
+// ShadowReadOpStats compares the mirrored reads of one repository method
+type ShadowReadOpStats struct {
+	Operation string `json:"operation" example:"GetByID"`
+	Mirrored  int64  `json:"mirrored" example:"4821"`
+	Matched   int64  `json:"matched" example:"4810"`
+	// Diverged counts reads whose shadow result or error differed from the primary's
+	Diverged int64 `json:"diverged" example:"9"`
+	// TimedOut counts shadow reads that did not answer within the timeout
+	TimedOut         int64   `json:"timedOut" example:"2"`
+	MatchRatePercent float64 `json:"matchRatePercent" example:"99.81"`
+}
+
+type operationKey struct{}
+
+// ContextWithOperation returns a copy of ctx naming the operation its database
+// commands serve, e.g. the HTTP route, so slow queries can be traced back to it
+func ContextWithOperation(ctx context.Context, operation string) context.Context {
+	return context.WithValue(ctx, operationKey{}, operation)
+}
+
+// OperationFromContext returns the operation ctx was labelled with, if any
+func OperationFromContext(ctx context.Context) string {
+	operation, _ := ctx.Value(operationKey{}).(string)
+	return operation
+}
+
+// ConsistencyToken is a point in the database's history: the operation time of
+// a command. A read given the token of an earlier response waits until the
+// member serving it has caught up with that point.
+type ConsistencyToken struct {
+	T uint32
+	I uint32
+}
+
+// ErrInvalidConsistencyToken is returned for a token that was not issued by the service
+var ErrInvalidConsistencyToken = errors.New("invalid consistency token")
+
+// ParseConsistencyToken parses the "seconds.increment" form of a token
+func ParseConsistencyToken(s string) (ConsistencyToken, error) {
+	seconds, increment, ok := strings.Cut(s, ".")
+	if !ok {
+		return ConsistencyToken{}, ErrInvalidConsistencyToken
+	}
+	t, err := strconv.ParseUint(seconds, 10, 32)
+	if err != nil || t == 0 {
+		return ConsistencyToken{}, ErrInvalidConsistencyToken
+	}
+	i, err := strconv.ParseUint(increment, 10, 32)
+	if err != nil {
+		return ConsistencyToken{}, ErrInvalidConsistencyToken
+	}
+	return ConsistencyToken{T: uint32(t), I: uint32(i)}, nil
+}
+
+// String returns the "seconds.increment" form of the token
+func (t ConsistencyToken) String() string {
+	return strconv.FormatUint(uint64(t.T), 10) + "." + strconv.FormatUint(uint64(t.I), 10)
+}
+
+// After reports whether t is a later point than other
+func (t ConsistencyToken) After(other ConsistencyToken) bool {
+	return t.T > other.T || (t.T == other.T && t.I > other.I)
+}
+
+// Consistency is the read-your-writes state of one request. Requests that
+// write read from the writes read preference, so the driver an update changes
+// is loaded from an up-to-date member; the others read from the reads one.
+type Consistency struct {
+	// Write is set for requests that change data
+	Write bool
+	// ReadAfter is the token the caller sent; nil reads whatever the member has
+	ReadAfter *ConsistencyToken
+
+	mu       sync.Mutex
+	observed ConsistencyToken
+}
+
+// Observe records the operation time of a command run for the request
+func (c *Consistency) Observe(token ConsistencyToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if token.After(c.observed) {
+		c.observed = token
+	}
+}
+
+// Token returns the latest point the request read or wrote, for the caller to
+// send with its next request, and false when it ran no command that reported one
+func (c *Consistency) Token() (ConsistencyToken, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ReadAfter != nil && c.ReadAfter.After(c.observed) {
+		return *c.ReadAfter, true
+	}
+	return c.observed, c.observed != ConsistencyToken{}
+}
+
+type consistencyKey struct{}
+
+// ContextWithConsistency returns a copy of ctx carrying the request's consistency state
+func ContextWithConsistency(ctx context.Context, consistency *Consistency) context.Context {
+	return context.WithValue(ctx, consistencyKey{}, consistency)
+}
+
+// ConsistencyFromContext returns the consistency state of ctx; nil outside requests
+func ConsistencyFromContext(ctx context.Context) *Consistency {
+	consistency, _ := ctx.Value(consistencyKey{}).(*Consistency)
+	return consistency
+}
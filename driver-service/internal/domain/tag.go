@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Limits on the tags and attributes a driver may carry
+const (
+	MaxDriverTags           = 20
+	MaxDriverAttributes     = 20
+	MaxAttributeValueLength = 64
+)
+
+// tagPattern matches a tag or attribute key: lowercase words joined by dashes
+var tagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NormalizeTag trims and lowercases a tag or attribute key. ok is false when
+// it is not 1-32 lowercase letters, digits and single dashes, e.g. "pet-friendly".
+func NormalizeTag(tag string) (normalized string, ok bool) {
+	normalized = strings.ToLower(strings.TrimSpace(tag))
+	return normalized, len(normalized) <= 32 && tagPattern.MatchString(normalized)
+}
+
+// HasTags reports whether the driver carries every one of tags
+func (d *Driver) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, own := range d.Tags {
+			if own == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAttributes reports whether the driver has every one of attributes; an
+// empty value matches any value of the key
+func (d *Driver) HasAttributes(attributes map[string]string) bool {
+	for key, value := range attributes {
+		own, ok := d.Attributes[key]
+		if !ok || (value != "" && own != value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"strings"
+	"sync"
+)
+
+// knownTaxiTypes are the taxi types drivers, searches and trips may use. They
+// are the built-in types until the taxi type registry is installed at startup.
+var knownTaxiTypes = struct {
+	sync.RWMutex
+	ids []TaxiType
+	set map[TaxiType]bool
+}{
+	ids: []TaxiType{TaxiTypeSari, TaxiTypeTurkuaz, TaxiTypeSiyah},
+	set: map[TaxiType]bool{TaxiTypeSari: true, TaxiTypeTurkuaz: true, TaxiTypeSiyah: true},
+}
+
+// IsValid checks if the taxi type is a known one
+func (t TaxiType) IsValid() bool {
+	knownTaxiTypes.RLock()
+	defer knownTaxiTypes.RUnlock()
+	return knownTaxiTypes.set[t]
+}
+
+// SetTaxiTypes replaces the known taxi types. It is called once at startup,
+// before the validation rules are compiled, with the types of the registry.
+func SetTaxiTypes(types []TaxiType) {
+	set := make(map[TaxiType]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	knownTaxiTypes.Lock()
+	defer knownTaxiTypes.Unlock()
+	knownTaxiTypes.ids = append([]TaxiType(nil), types...)
+	knownTaxiTypes.set = set
+}
+
+// TaxiTypes returns the known taxi types in the order they are listed
+func TaxiTypes() []TaxiType {
+	knownTaxiTypes.RLock()
+	defer knownTaxiTypes.RUnlock()
+	return append([]TaxiType(nil), knownTaxiTypes.ids...)
+}
+
+// TaxiTypeList joins the known taxi types for error messages, e.g.
+// "sari, turkuaz, siyah"
+func TaxiTypeList() string {
+	types := TaxiTypes()
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// Kinds of telemetry devices send over MQTT
+const (
+	TelemetryLocation  = "location"
+	TelemetryHeartbeat = "heartbeat"
+)
+
+// TelemetryStats reports what the MQTT bridge received since the service started
+type TelemetryStats struct {
+	Broker    string   `json:"broker" example:"tcp://mqtt:1883"`
+	Connected bool     `json:"connected" example:"true"`
+	Topics    []string `json:"topics" example:"drivers/+/location,drivers/+/heartbeat"`
+	QoS       int      `json:"qos" example:"1"`
+	// Messages counts messages by kind: location or heartbeat
+	Messages map[string]TelemetryCounts `json:"messages"`
+	// Rejections counts rejected messages by reason: topic, payload, token,
+	// scope or invalid
+	Rejections map[string]int64 `json:"rejections"`
+	// Reconnects counts attempts to reconnect after the connection was lost
+	Reconnects       int64      `json:"reconnects" example:"1"`
+	LastMessageAt    *time.Time `json:"lastMessageAt,omitempty" example:"2025-12-06T09:30:00Z"`
+	LastDisconnectAt *time.Time `json:"lastDisconnectAt,omitempty" example:"2025-12-06T01:00:00Z"`
+}
+
+// TelemetryCounts counts the messages of one kind
+type TelemetryCounts struct {
+	Received int64 `json:"received" example:"182340"`
+	// Processed messages reached the driver's location or heartbeat
+	Processed int64 `json:"processed" example:"182101"`
+	// Rejected messages were acknowledged and dropped; sending them again would not help
+	Rejected int64 `json:"rejected" example:"230"`
+	// Failed messages hit a transient error and are left for the broker to
+	// redeliver when subscribed with QoS 1 or 2
+	Failed int64 `json:"failed" example:"9"`
+}
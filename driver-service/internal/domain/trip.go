@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// TripStatus represents the lifecycle state of a trip
+type TripStatus string
+
+const (
+	TripStatusRequested TripStatus = "requested"
+	TripStatusOffered   TripStatus = "offered"
+	TripStatusAccepted  TripStatus = "accepted"
+	TripStatusCompleted TripStatus = "completed"
+	TripStatusCancelled TripStatus = "cancelled"
+	TripStatusNoDriver  TripStatus = "no_driver_found"
+)
+
+// IsFinal reports whether the trip can no longer change state
+func (s TripStatus) IsFinal() bool {
+	return s == TripStatusCompleted || s == TripStatusCancelled || s == TripStatusNoDriver
+}
+
+// Trip represents a ride request and its assignment to a driver
+type Trip struct {
+	ID              string     `bson:"_id,omitempty" json:"id" example:"6571f1f77bcf86cd79943901"`
+	RiderID         string     `bson:"riderId,omitempty" json:"riderId,omitempty" example:"rider-42"`
+	Pickup          Location   `bson:"pickup" json:"pickup"`
+	Dropoff         *Location  `bson:"dropoff,omitempty" json:"dropoff,omitempty"`
+	TaxiType        *TaxiType  `bson:"taxiType,omitempty" json:"taxiType,omitempty" example:"sari"`
+	Status          TripStatus `bson:"status" json:"status" example:"offered"`
+	Strategy        string     `bson:"strategy" json:"strategy" example:"nearest"`
+	DriverID        string     `bson:"driverId,omitempty" json:"driverId,omitempty" example:"507f1f77bcf86cd799439011"`
+	OfferedDriverID string     `bson:"offeredDriverId,omitempty" json:"offeredDriverId,omitempty" example:"507f1f77bcf86cd799439011"`
+	OfferExpiresAt  *time.Time `bson:"offerExpiresAt,omitempty" json:"offerExpiresAt,omitempty"`
+	// DeclinedDriverIDs holds drivers that declined or let an offer expire; they are not offered again
+	DeclinedDriverIDs []string `bson:"declinedDriverIds" json:"declinedDriverIds"`
+	OfferCount        int      `bson:"offerCount" json:"offerCount" example:"1"`
+	DistanceKm        float64  `bson:"distanceKm,omitempty" json:"distanceKm,omitempty" example:"7.4"`
+	// Estimate is the fare quoted when the trip was requested with a dropoff
+	Estimate *FareEstimate `bson:"estimate,omitempty" json:"estimate,omitempty"`
+	// Fare is what the rider paid for a completed trip
+	Fare float64 `bson:"fare,omitempty" json:"fare,omitempty" example:"232.4"`
+	// Rating is the rider's 1-5 rating of the driver for this trip, if given
+	Rating    *float64  `bson:"rating,omitempty" json:"rating,omitempty" example:"5"`
+	Version   int       `bson:"version" json:"-"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+	// AcceptedAt is when the driver accepted the trip; busy time runs from it to CompletedAt
+	AcceptedAt  *time.Time `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`
+	CompletedAt *time.Time `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
+
+// HasDeclined reports whether the driver already declined this trip
+func (t *Trip) HasDeclined(driverID string) bool {
+	for _, id := range t.DeclinedDriverIDs {
+		if id == driverID {
+			return true
+		}
+	}
+	return false
+}
+
+// TripRepository defines the interface for trip data access.
+// Update uses optimistic concurrency: it fails with "trip was modified concurrently"
+// when the stored version no longer matches trip.Version.
+type TripRepository interface {
+	Create(ctx interface{}, trip *Trip) error
+	Update(ctx interface{}, trip *Trip) error
+	GetByID(ctx interface{}, id string) (*Trip, error)
+	FindExpiredOffers(ctx interface{}, now time.Time, limit int) ([]*Trip, error)
+}
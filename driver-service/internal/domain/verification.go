@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// PhoneVerification represents a pending one-time-password challenge for a
+// driver's phone, or for a rider signing in
+type PhoneVerification struct {
+	// DriverID keys the challenge: the driver's ID, or "rider:" and the rider's
+	// ID for a sign-in challenge
+	DriverID  string    `bson:"_id"`
+	Phone     string    `bson:"phone"`
+	CodeHash  string    `bson:"codeHash"`
+	Attempts  int       `bson:"attempts"`
+	ExpiresAt time.Time `bson:"expiresAt"`
+	CreatedAt time.Time `bson:"createdAt"`
+}
+
+// IsExpired reports whether the challenge can no longer be used
+func (v *PhoneVerification) IsExpired(now time.Time) bool {
+	return now.After(v.ExpiresAt)
+}
+
+// VerificationRepository defines the interface for phone verification data access
+type VerificationRepository interface {
+	Save(ctx interface{}, verification *PhoneVerification) error
+	Get(ctx interface{}, driverID string) (*PhoneVerification, error)
+	Delete(ctx interface{}, driverID string) error
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Driver event types delivered to webhook subscribers
+const (
+	EventDriverCreated   = "driver.created"
+	EventDriverUpdated   = "driver.updated"
+	EventDriverSuspended = "driver.suspended"
+	// EventDriverLicenseExpired alerts that a driver was taken off dispatch by the licence check
+	EventDriverLicenseExpired = "driver.license_expired"
+)
+
+// IsDriverEvent reports whether event is a known driver event type
+func IsDriverEvent(event string) bool {
+	return event == EventDriverCreated || event == EventDriverUpdated || event == EventDriverSuspended ||
+		event == EventDriverLicenseExpired
+}
+
+// WebhookSubscription registers a partner endpoint for driver events
+type WebhookSubscription struct {
+	ID string `bson:"_id,omitempty" json:"id" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	// FleetID limits the subscription to drivers of one fleet; empty receives events of every driver
+	FleetID string `bson:"fleetId,omitempty" json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	URL     string `bson:"url" json:"url" example:"https://partner.example.com/hooks/bitaksi"`
+	// Secret signs every delivery; it is only returned when the subscription is created
+	Secret string `bson:"secret" json:"secret,omitempty" example:"whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"`
+	// Events lists the event types to deliver; empty delivers every event
+	Events      []string  `bson:"events,omitempty" json:"events,omitempty" example:"driver.created,driver.suspended"`
+	Description string    `bson:"description,omitempty" json:"description,omitempty" example:"Kadıköy Taksi dispatch system"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// Wants reports whether the subscription receives event
+func (s *WebhookSubscription) Wants(event string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus represents the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are waiting for their next attempt
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryInProgress deliveries are claimed by a worker until their lease expires
+	DeliveryInProgress DeliveryStatus = "delivering"
+	DeliverySucceeded  DeliveryStatus = "succeeded"
+	// DeliveryFailed deliveries ran out of attempts
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// DriverEvent is the body posted to webhook endpoints
+type DriverEvent struct {
+	ID         string    `json:"id" example:"evt_5f0c2a9e8b7d4c3a"`
+	Type       string    `json:"type" example:"driver.suspended"`
+	OccurredAt time.Time `json:"occurredAt" example:"2025-12-06T01:00:00Z"`
+	Driver     *Driver   `json:"driver"`
+	// Reason explains a suspension
+	Reason string `json:"reason,omitempty" example:"expired license"`
+}
+
+// WebhookDelivery is one event sent to one subscription, including its retries
+type WebhookDelivery struct {
+	ID             string          `bson:"_id,omitempty" json:"id" example:"6572b1f2c3d4e5f6a7b8c9d0"`
+	SubscriptionID string          `bson:"subscriptionId" json:"subscriptionId" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	EventID        string          `bson:"eventId" json:"eventId" example:"evt_5f0c2a9e8b7d4c3a"`
+	Event          string          `bson:"event" json:"event" example:"driver.suspended"`
+	Payload        json.RawMessage `bson:"payload" json:"payload" swaggertype:"object"`
+	Status         DeliveryStatus  `bson:"status" json:"status" example:"pending" enums:"pending,delivering,succeeded,failed"`
+	Attempts       int             `bson:"attempts" json:"attempts" example:"2"`
+	// NextAttemptAt is when a pending delivery is retried, or when the lease of an in-progress one expires
+	NextAttemptAt  *time.Time `bson:"nextAttemptAt,omitempty" json:"nextAttemptAt,omitempty" example:"2025-12-06T01:00:40Z"`
+	LastStatusCode int        `bson:"lastStatusCode,omitempty" json:"lastStatusCode,omitempty" example:"503"`
+	LastError      string     `bson:"lastError,omitempty" json:"lastError,omitempty" example:"endpoint returned status 503"`
+	DeliveredAt    *time.Time `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time  `bson:"createdAt" json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt      time.Time  `bson:"updatedAt" json:"updatedAt" example:"2025-12-06T01:00:10Z"`
+}
+
+// WebhookRepository defines the interface for webhook subscription and delivery data access
+type WebhookRepository interface {
+	CreateSubscription(ctx interface{}, sub *WebhookSubscription) error
+	GetSubscription(ctx interface{}, id string) (*WebhookSubscription, error)
+	// ListSubscriptions lists the subscriptions of a fleet; an empty fleetID lists all of them
+	ListSubscriptions(ctx interface{}, fleetID string) ([]*WebhookSubscription, error)
+	DeleteSubscription(ctx interface{}, id string) error
+	// MatchSubscriptions returns the subscriptions that receive event for a driver of fleetID
+	MatchSubscriptions(ctx interface{}, event, fleetID string) ([]*WebhookSubscription, error)
+	CreateDeliveries(ctx interface{}, deliveries []*WebhookDelivery) error
+	GetDelivery(ctx interface{}, id string) (*WebhookDelivery, error)
+	// ClaimDelivery atomically moves the oldest due delivery to in progress until leaseUntil;
+	// it returns nil when nothing is due
+	ClaimDelivery(ctx interface{}, now, leaseUntil time.Time) (*WebhookDelivery, error)
+	UpdateDelivery(ctx interface{}, delivery *WebhookDelivery) error
+	// ListDeliveries returns the latest deliveries of a subscription, newest first
+	ListDeliveries(ctx interface{}, subscriptionID string, limit int) ([]*WebhookDelivery, error)
+}
+
+// DriverEventPublisher is notified of driver changes. Publishing never fails the
+// change itself, so implementations handle their own errors.
+type DriverEventPublisher interface {
+	PublishDriverEvent(ctx interface{}, event string, driver *Driver, reason string)
+}
@@ -0,0 +1,229 @@
+// Package events carries analytics events off the request path. Events are
+// queued in memory and written to a sink in batches by a background job. When
+// the queue is full new events are dropped, so a slow or failing sink never
+// slows down the requests that emit them.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Sink names accepted by NewSink
+const (
+	SinkLog  = "log"
+	SinkHTTP = "http"
+)
+
+// maxBatchSize bounds the events written to the sink at once
+const maxBatchSize = 500
+
+// Envelope is an event as written to the sink
+type Envelope struct {
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       interface{} `json:"data"`
+}
+
+// Sink stores or forwards batches of events
+type Sink interface {
+	Write(ctx context.Context, batch []Envelope) error
+}
+
+// Options configures the sinks
+type Options struct {
+	// URL receives batches as a JSON array with the http sink
+	URL     string
+	Timeout time.Duration
+}
+
+// NewSink creates the sink with the given name
+func NewSink(name string, opts Options, logger *zap.Logger) (Sink, error) {
+	switch name {
+	case SinkLog, "":
+		return NewLogSink(logger), nil
+	case SinkHTTP:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("a URL is required for the %s events sink", SinkHTTP)
+		}
+		return NewHTTPSink(opts.URL, opts.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown events sink %q", name)
+	}
+}
+
+// Stats counts what happened to published events
+type Stats struct {
+	Published int64 `json:"published"`
+	Written   int64 `json:"written"`
+	// Dropped events found the queue full or were in a batch the sink rejected
+	Dropped int64 `json:"dropped"`
+}
+
+// Bus queues events for a sink
+type Bus struct {
+	queue  chan Envelope
+	sink   Sink
+	logger *zap.Logger
+	now    func() time.Time
+
+	published atomic.Int64
+	written   atomic.Int64
+	dropped   atomic.Int64
+}
+
+// NewBus creates a bus holding up to bufferSize events between flushes
+func NewBus(sink Sink, bufferSize int, logger *zap.Logger) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = 10000
+	}
+	return &Bus{
+		queue:  make(chan Envelope, bufferSize),
+		sink:   sink,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Publish queues an event without blocking; it is dropped when the queue is full
+func (b *Bus) Publish(eventType string, data interface{}) {
+	b.published.Add(1)
+	select {
+	case b.queue <- Envelope{Type: eventType, OccurredAt: b.now().UTC(), Data: data}:
+	default:
+		if b.dropped.Add(1)%1000 == 1 {
+			b.logger.Warn("events queue full, dropping events", zap.Int64("dropped", b.dropped.Load()))
+		}
+	}
+}
+
+// Run writes queued events to the sink every interval until ctx is cancelled,
+// then writes what is left
+func (b *Bus) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			b.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			b.Flush(ctx)
+		}
+	}
+}
+
+// Flush writes the queued events to the sink in batches. Batches the sink
+// rejects are dropped; analytics events are not worth retrying.
+func (b *Bus) Flush(ctx context.Context) {
+	for {
+		batch := b.drain()
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.sink.Write(ctx, batch); err != nil {
+			b.dropped.Add(int64(len(batch)))
+			b.logger.Warn("failed to write events", zap.Error(err), zap.Int("events", len(batch)))
+			return
+		}
+		b.written.Add(int64(len(batch)))
+	}
+}
+
+// drain takes up to maxBatchSize events off the queue
+func (b *Bus) drain() []Envelope {
+	var batch []Envelope
+	for len(batch) < maxBatchSize {
+		select {
+		case event := <-b.queue:
+			batch = append(batch, event)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// Stats returns the event counters since start
+func (b *Bus) Stats() Stats {
+	return Stats{
+		Published: b.published.Load(),
+		Written:   b.written.Load(),
+		Dropped:   b.dropped.Load(),
+	}
+}
+
+// LogSink writes events to a logger, one entry per event
+type LogSink struct {
+	logger *zap.Logger
+}
+
+// NewLogSink creates a sink logging to logger
+func NewLogSink(logger *zap.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Write logs every event in the batch
+func (s *LogSink) Write(ctx context.Context, batch []Envelope) error {
+	for _, event := range batch {
+		s.logger.Info("event",
+			zap.String("type", event.Type),
+			zap.Time("occurredAt", event.OccurredAt),
+			zap.Any("data", event.Data),
+		)
+	}
+	return nil
+}
+
+// HTTPSink posts batches to a collector as a JSON array
+type HTTPSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPSink creates a sink posting to url; requests time out after timeout
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Write posts the batch; any status outside 2xx is an error
+func (s *HTTPSink) Write(ctx context.Context, batch []Envelope) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create events request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send events: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("events collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
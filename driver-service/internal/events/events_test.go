@@ -0,0 +1,105 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// memorySink keeps the batches it is given
+type memorySink struct {
+	batches [][]Envelope
+	fail    bool
+}
+
+func (s *memorySink) Write(ctx context.Context, batch []Envelope) error {
+	if s.fail {
+		return errors.New("collector unavailable")
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func TestBus_PublishAndFlush(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewBus(sink, 2, zap.NewNop())
+
+	bus.Publish("NearbySearchPerformed", map[string]int{"resultCount": 1})
+	bus.Publish("NearbySearchPerformed", map[string]int{"resultCount": 2})
+	// The queue is full; publishing must not block
+	bus.Publish("NearbySearchPerformed", map[string]int{"resultCount": 3})
+
+	bus.Flush(context.Background())
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 2 {
+		t.Fatalf("expected one batch of 2 events, got %v", sink.batches)
+	}
+	if sink.batches[0][0].Type != "NearbySearchPerformed" || sink.batches[0][0].OccurredAt.IsZero() {
+		t.Errorf("unexpected envelope %+v", sink.batches[0][0])
+	}
+	if stats := bus.Stats(); stats != (Stats{Published: 3, Written: 2, Dropped: 1}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// Batches the sink rejects are dropped
+	sink.fail = true
+	bus.Publish("NearbySearchPerformed", nil)
+	bus.Flush(context.Background())
+	if stats := bus.Stats(); stats.Dropped != 2 {
+		t.Errorf("expected 2 dropped events, got %d", stats.Dropped)
+	}
+}
+
+func TestBus_RunFlushesOnStop(t *testing.T) {
+	sink := &memorySink{}
+	bus := NewBus(sink, 10, zap.NewNop())
+	bus.Publish("NearbySearchPerformed", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Run(ctx, 0)
+
+	if len(sink.batches) != 1 {
+		t.Errorf("expected the queued event to be written on stop, got %d batches", len(sink.batches))
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []Envelope
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(SinkHTTP, Options{URL: server.URL}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sink.Write(context.Background(), []Envelope{{Type: "NearbySearchPerformed"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(received) != 1 || received[0].Type != "NearbySearchPerformed" {
+		t.Errorf("unexpected batch %+v", received)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := sink.Write(context.Background(), []Envelope{{Type: "NearbySearchPerformed"}}); err == nil {
+		t.Error("expected an error for a 503")
+	}
+
+	if _, err := NewSink(SinkHTTP, Options{}, zap.NewNop()); err == nil {
+		t.Error("expected an error without a URL")
+	}
+	if _, err := NewSink("kafka", Options{}, zap.NewNop()); err == nil {
+		t.Error("expected an error for an unknown sink")
+	}
+}
@@ -0,0 +1,180 @@
+// Package fieldcrypt encrypts individual document fields at rest.
+//
+// Values are sealed with AES-256-GCM using data keys from a Keyring. Data keys
+// can be supplied directly or wrapped by a KMS master key and unwrapped at
+// startup (envelope encryption). Encrypted values carry the ID of the data key
+// that sealed them, so keys can be rotated and old values re-encrypted later.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values; the format is "enc:v1:<keyID>:<base64(nonce|ciphertext)>"
+const prefix = "enc:v1:"
+
+// ErrUnknownKey is returned when a value was sealed with a key that is not in the keyring
+var ErrUnknownKey = errors.New("fieldcrypt: unknown key")
+
+// Cipher encrypts and decrypts individual field values
+type Cipher interface {
+	Encrypt(plaintext string) (string, error)
+	// Decrypt returns plaintext values unchanged so unmigrated documents stay readable
+	Decrypt(value string) (string, error)
+	// NeedsRotation reports whether the value is plaintext or sealed with a non-active key
+	NeedsRotation(value string) bool
+}
+
+// Hasher computes deterministic lookup hashes (blind indexes) for encrypted fields
+type Hasher interface {
+	Hash(value string) string
+}
+
+// Keyring holds the data keys by ID and the key used for new values
+type Keyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+// NewKeyring creates a keyring; every key must be 32 bytes (AES-256)
+func NewKeyring(keys map[string][]byte, active string) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("fieldcrypt: keyring is empty")
+	}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("fieldcrypt: invalid key ID %q", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be 32 bytes, got %d", id, len(key))
+		}
+	}
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("fieldcrypt: active key %q is not in the keyring", active)
+	}
+	return &Keyring{keys: keys, active: active}, nil
+}
+
+// ActiveKeyID returns the ID of the key used to encrypt new values
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// AESGCM is a Cipher backed by a keyring of AES-256 keys
+type AESGCM struct {
+	keyring *Keyring
+	aeads   map[string]cipher.AEAD
+}
+
+// NewAESGCM creates an AES-GCM cipher for the keyring
+func NewAESGCM(keyring *Keyring) (*AESGCM, error) {
+	aeads := make(map[string]cipher.AEAD, len(keyring.keys))
+	for id, key := range keyring.keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %q: %w", id, err)
+		}
+		aeads[id] = aead
+	}
+	return &AESGCM{keyring: keyring, aeads: aeads}, nil
+}
+
+// Encrypt seals the value with the active key. Empty values stay empty.
+func (c *AESGCM) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	keyID := c.keyring.active
+	aead := c.aeads[keyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("fieldcrypt: failed to generate nonce: %w", err)
+	}
+	// The key ID is authenticated so a value cannot be replayed under another key
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(keyID))
+	return prefix + keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens an encrypted value with the key it was sealed with
+func (c *AESGCM) Decrypt(value string) (string, error) {
+	keyID, payload, ok := parse(value)
+	if !ok {
+		return value, nil
+	}
+	aead, found := c.aeads[keyID]
+	if !found {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("fieldcrypt: malformed ciphertext")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: failed to decrypt with key %q: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether the value should be re-encrypted with the active key
+func (c *AESGCM) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	keyID, _, ok := parse(value)
+	return !ok || keyID != c.keyring.active
+}
+
+// IsEncrypted reports whether the value carries the encrypted value prefix
+func IsEncrypted(value string) bool {
+	_, _, ok := parse(value)
+	return ok
+}
+
+// parse splits an encrypted value into key ID and payload
+func parse(value string) (keyID, payload string, ok bool) {
+	if !strings.HasPrefix(value, prefix) {
+		return "", "", false
+	}
+	keyID, payload, ok = strings.Cut(value[len(prefix):], ":")
+	return keyID, payload, ok && keyID != ""
+}
+
+// HMACHasher hashes values with HMAC-SHA256 so equal inputs can be matched without decrypting
+type HMACHasher struct {
+	key []byte
+}
+
+// NewHMACHasher creates a hasher; the key must be at least 32 bytes
+func NewHMACHasher(key []byte) (*HMACHasher, error) {
+	if len(key) < 32 {
+		return nil, errors.New("fieldcrypt: hash key must be at least 32 bytes")
+	}
+	return &HMACHasher{key: key}, nil
+}
+
+// Hash returns the hex encoded HMAC of the value; empty values hash to ""
+func (h *HMACHasher) Hash(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, h.key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,186 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testKeyring(t *testing.T, active string) *Keyring {
+	t.Helper()
+	keyring, err := NewKeyring(map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}, active)
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	return keyring
+}
+
+func TestNewKeyring(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+
+	tests := []struct {
+		name    string
+		keys    map[string][]byte
+		active  string
+		wantErr bool
+	}{
+		{name: "valid", keys: map[string][]byte{"k1": key}, active: "k1"},
+		{name: "empty", keys: map[string][]byte{}, active: "k1", wantErr: true},
+		{name: "short key", keys: map[string][]byte{"k1": key[:16]}, active: "k1", wantErr: true},
+		{name: "missing active key", keys: map[string][]byte{"k1": key}, active: "k2", wantErr: true},
+		{name: "colon in key ID", keys: map[string][]byte{"k:1": key}, active: "k:1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(tt.keys, tt.active)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAESGCM_RoundTrip(t *testing.T) {
+	cipher, err := NewAESGCM(testKeyring(t, "k1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sealed, err := cipher.Encrypt("Ahmet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(sealed, "enc:v1:k1:") {
+		t.Errorf("expected value sealed with k1, got %s", sealed)
+	}
+
+	again, _ := cipher.Encrypt("Ahmet")
+	if again == sealed {
+		t.Error("expected randomized ciphertexts for equal plaintexts")
+	}
+
+	plain, err := cipher.Decrypt(sealed)
+	if err != nil || plain != "Ahmet" {
+		t.Errorf("expected Ahmet, got %q (%v)", plain, err)
+	}
+
+	empty, _ := cipher.Encrypt("")
+	if empty != "" {
+		t.Errorf("expected empty value to stay empty, got %q", empty)
+	}
+}
+
+func TestAESGCM_Decrypt(t *testing.T) {
+	old, _ := NewAESGCM(testKeyring(t, "k1"))
+	current, _ := NewAESGCM(testKeyring(t, "k2"))
+	sealedWithOld, _ := old.Encrypt("Demir")
+
+	t.Run("plaintext passes through", func(t *testing.T) {
+		plain, err := current.Decrypt("Demir")
+		if err != nil || plain != "Demir" {
+			t.Errorf("expected Demir, got %q (%v)", plain, err)
+		}
+	})
+
+	t.Run("retired key still decrypts", func(t *testing.T) {
+		plain, err := current.Decrypt(sealedWithOld)
+		if err != nil || plain != "Demir" {
+			t.Errorf("expected Demir, got %q (%v)", plain, err)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := current.Decrypt("enc:v1:k9:AAAA")
+		if !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("expected ErrUnknownKey, got %v", err)
+		}
+	})
+
+	t.Run("tampered key ID fails authentication", func(t *testing.T) {
+		tampered := strings.Replace(sealedWithOld, "enc:v1:k1:", "enc:v1:k2:", 1)
+		if _, err := current.Decrypt(tampered); err == nil {
+			t.Error("expected error for value relabelled with another key")
+		}
+	})
+}
+
+func TestAESGCM_NeedsRotation(t *testing.T) {
+	old, _ := NewAESGCM(testKeyring(t, "k1"))
+	current, _ := NewAESGCM(testKeyring(t, "k2"))
+	sealedWithOld, _ := old.Encrypt("+905321234567")
+	sealedWithCurrent, _ := current.Encrypt("+905321234567")
+
+	if !current.NeedsRotation("+905321234567") {
+		t.Error("expected plaintext to need rotation")
+	}
+	if !current.NeedsRotation(sealedWithOld) {
+		t.Error("expected value sealed with retired key to need rotation")
+	}
+	if current.NeedsRotation(sealedWithCurrent) {
+		t.Error("expected value sealed with active key not to need rotation")
+	}
+	if current.NeedsRotation("") {
+		t.Error("expected empty value not to need rotation")
+	}
+}
+
+func TestHMACHasher(t *testing.T) {
+	if _, err := NewHMACHasher([]byte("short")); err == nil {
+		t.Fatal("expected error for short hash key")
+	}
+
+	hasher, _ := NewHMACHasher(bytes.Repeat([]byte{3}, 32))
+	a := hasher.Hash("+905321234567")
+	if a != hasher.Hash("+905321234567") {
+		t.Error("expected deterministic hash")
+	}
+	if a == hasher.Hash("+905329876543") {
+		t.Error("expected different hashes for different values")
+	}
+	if hasher.Hash("") != "" {
+		t.Error("expected empty hash for empty value")
+	}
+}
+
+func TestLoadKeyring_Vault(t *testing.T) {
+	dataKey := bytes.Repeat([]byte{7}, 32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/transit/decrypt/fields" || r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["ciphertext"] != "vault:v1:wrapped" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)},
+		})
+	}))
+	defer server.Close()
+
+	vault := NewVaultTransit(server.URL, "token", "fields")
+	keyring, err := LoadKeyring(context.Background(), map[string]string{"k1": "vault:v1:wrapped"}, "k1", vault)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(keyring.keys["k1"], dataKey) {
+		t.Error("expected data key to be unwrapped by vault")
+	}
+
+	if _, err := LoadKeyring(context.Background(), map[string]string{"k1": "vault:v1:other"}, "k1", vault); err == nil {
+		t.Error("expected error when vault rejects the ciphertext")
+	}
+}
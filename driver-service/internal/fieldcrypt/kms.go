@@ -0,0 +1,95 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// KeyUnwrapper decrypts data keys that were wrapped by a KMS master key
+type KeyUnwrapper interface {
+	Unwrap(ctx context.Context, wrapped string) ([]byte, error)
+}
+
+// LoadKeyring builds a keyring from encoded data keys. Without an unwrapper the
+// values are base64 encoded raw keys; with one they are KMS ciphertexts.
+func LoadKeyring(ctx context.Context, encoded map[string]string, active string, unwrapper KeyUnwrapper) (*Keyring, error) {
+	keys := make(map[string][]byte, len(encoded))
+	for id, value := range encoded {
+		var (
+			key []byte
+			err error
+		)
+		if unwrapper != nil {
+			key, err = unwrapper.Unwrap(ctx, value)
+		} else {
+			key, err = base64.StdEncoding.DecodeString(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: failed to load key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return NewKeyring(keys, active)
+}
+
+// VaultTransit unwraps data keys with the HashiCorp Vault transit secrets engine
+type VaultTransit struct {
+	addr       string
+	token      string
+	keyName    string
+	httpClient *http.Client
+}
+
+// NewVaultTransit creates an unwrapper for the given Vault address, token and transit key
+func NewVaultTransit(addr, token, keyName string) *VaultTransit {
+	return &VaultTransit{
+		addr:    strings.TrimRight(addr, "/"),
+		token:   token,
+		keyName: keyName,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Unwrap decrypts a "vault:v1:..." ciphertext and returns the raw data key
+func (v *VaultTransit) Unwrap(ctx context.Context, wrapped string) ([]byte, error) {
+	payload, err := json.Marshal(map[string]string{"ciphertext": wrapped})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/v1/transit/decrypt/%s", v.addr, v.keyName)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.token)
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(result.Data.Plaintext)
+}
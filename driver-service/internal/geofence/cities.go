@@ -0,0 +1,133 @@
+package geofence
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+//go:embed cities.json
+var builtinCities []byte
+
+// kmPerDegreeLat is the length of a degree of latitude, close enough
+// everywhere for distances within a city
+const kmPerDegreeLat = 110.574
+
+// CitiesConfig is the cities file
+type CitiesConfig struct {
+	Cities []Region `json:"cities"`
+}
+
+// Cities tells which city a position is in. Drivers are stored with their
+// city so queries for one city, which most are, skip the drivers of the others.
+type Cities struct {
+	cities []Region
+}
+
+// NewCities creates a city lookup. Names must be lowercase words joined by
+// dashes, e.g. "istanbul", and cities should not overlap; a position in more
+// than one belongs to the first listed.
+func NewCities(cities []Region) (*Cities, error) {
+	seen := map[string]bool{}
+	for _, city := range cities {
+		if name, ok := domain.NormalizeTag(city.Name); !ok || name != city.Name || city.Name == domain.CityOther {
+			return nil, fmt.Errorf("invalid city name %q", city.Name)
+		}
+		if seen[city.Name] {
+			return nil, fmt.Errorf("city %q is listed twice", city.Name)
+		}
+		seen[city.Name] = true
+		if len(city.Polygon) < 3 {
+			return nil, fmt.Errorf("city %q needs at least 3 corners", city.Name)
+		}
+	}
+	return &Cities{cities: cities}, nil
+}
+
+// LoadCities creates a city lookup from the cities file at path; an empty path
+// uses the built-in outlines of the largest cities
+func LoadCities(path string) (*Cities, error) {
+	data := builtinCities
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read cities: %w", err)
+		}
+	}
+	var cfg CitiesConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse cities %s: %w", path, err)
+	}
+	if len(cfg.Cities) == 0 {
+		return nil, fmt.Errorf("cities %s lists no cities", path)
+	}
+	return NewCities(cfg.Cities)
+}
+
+// Names returns the names of the cities, in the order they are listed
+func (c *Cities) Names() []string {
+	names := make([]string, len(c.cities))
+	for i, city := range c.cities {
+		names[i] = city.Name
+	}
+	return names
+}
+
+// Locate returns the city a position is in, or domain.CityOther
+func (c *Cities) Locate(location domain.Location) string {
+	for _, city := range c.cities {
+		if contains(city.Polygon, location) {
+			return city.Name
+		}
+	}
+	return domain.CityOther
+}
+
+// Touching returns the cities a circle reaches into, with domain.CityOther
+// unless the circle lies inside a single city
+func (c *Cities) Touching(center domain.Location, radiusKm float64) []string {
+	var touching []string
+	for _, city := range c.cities {
+		inside := contains(city.Polygon, center)
+		distance := edgeDistanceKm(city.Polygon, center)
+		if inside && distance > radiusKm {
+			return []string{city.Name}
+		}
+		if inside || distance <= radiusKm {
+			touching = append(touching, city.Name)
+		}
+	}
+	return append(touching, domain.CityOther)
+}
+
+// edgeDistanceKm is the distance from a point to the nearest edge of a
+// polygon. Corners are projected onto a plane around the point, which is
+// accurate for the few kilometres searches reach.
+func edgeDistanceKm(polygon []domain.Location, point domain.Location) float64 {
+	kmPerDegreeLon := kmPerDegreeLat * math.Cos(point.Lat*math.Pi/180)
+	project := func(l domain.Location) (float64, float64) {
+		return (l.Lon - point.Lon) * kmPerDegreeLon, (l.Lat - point.Lat) * kmPerDegreeLat
+	}
+
+	nearest := math.Inf(1)
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		ax, ay := project(polygon[j])
+		bx, by := project(polygon[i])
+		nearest = math.Min(nearest, originToSegment(ax, ay, bx, by))
+	}
+	return nearest
+}
+
+// originToSegment is the distance from the origin to the segment from a to b
+func originToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
@@ -0,0 +1,133 @@
+// Package geofence normalizes the driver positions the service stores. Positions
+// are rounded to a fixed number of decimals, 0,0 (the default of clients that
+// have no fix yet) is refused, and positions outside the regions the service
+// runs in are rejected. Regions are polygons in a JSON file; without one the
+// built-in, deliberately generous outline of Turkey applies.
+package geofence
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// DefaultPrecision keeps six decimals, about 10cm
+const DefaultPrecision = 6
+
+//go:embed turkey.json
+var builtinRegions []byte
+
+var (
+	// ErrUnknownLocation is returned for 0,0, which clients send before they have a fix
+	ErrUnknownLocation = errors.New("location 0,0 is not a valid position")
+	// ErrOutsideServiceArea is returned for positions outside every service region
+	ErrOutsideServiceArea = errors.New("location is outside the service area")
+)
+
+// Region is an area the service runs in
+type Region struct {
+	Name string `json:"name" example:"turkey"`
+	// Polygon lists the corners in order; the last one connects back to the first
+	Polygon []domain.Location `json:"polygon"`
+}
+
+// Config is the service area file
+type Config struct {
+	Regions []Region `json:"regions"`
+}
+
+// Area checks and rounds positions
+type Area struct {
+	precision int
+	scale     float64
+	regions   []Region
+}
+
+// New creates an area keeping precision decimals. Without regions every
+// position in range except 0,0 is accepted.
+func New(precision int, regions []Region) (*Area, error) {
+	if precision < 0 || precision > 10 {
+		return nil, fmt.Errorf("location precision must be between 0 and 10, got %d", precision)
+	}
+	for _, region := range regions {
+		if len(region.Polygon) < 3 {
+			return nil, fmt.Errorf("service region %q needs at least 3 corners", region.Name)
+		}
+		for _, corner := range region.Polygon {
+			if corner.Lat < -90 || corner.Lat > 90 || corner.Lon < -180 || corner.Lon > 180 {
+				return nil, fmt.Errorf("service region %q has a corner out of range: %v,%v", region.Name, corner.Lat, corner.Lon)
+			}
+		}
+	}
+	return &Area{
+		precision: precision,
+		scale:     math.Pow(10, float64(precision)),
+		regions:   regions,
+	}, nil
+}
+
+// Load creates an area from the regions file at path; an empty path uses the
+// built-in regions
+func Load(path string, precision int) (*Area, error) {
+	data := builtinRegions
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read service area: %w", err)
+		}
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse service area %s: %w", path, err)
+	}
+	if len(cfg.Regions) == 0 {
+		return nil, fmt.Errorf("service area %s has no regions", path)
+	}
+	return New(precision, cfg.Regions)
+}
+
+// Regions returns the regions positions must be in
+func (a *Area) Regions() []Region {
+	return a.regions
+}
+
+// Normalize rounds a position and checks it is a real one inside the service
+// area. Coordinates out of range are left to the caller's own checks.
+func (a *Area) Normalize(location domain.Location) (domain.Location, error) {
+	rounded := domain.Location{
+		Lat: math.Round(location.Lat*a.scale) / a.scale,
+		Lon: math.Round(location.Lon*a.scale) / a.scale,
+	}
+	if rounded.Lat == 0 && rounded.Lon == 0 {
+		return location, ErrUnknownLocation
+	}
+	if len(a.regions) == 0 {
+		return rounded, nil
+	}
+	for _, region := range a.regions {
+		if contains(region.Polygon, rounded) {
+			return rounded, nil
+		}
+	}
+	return location, ErrOutsideServiceArea
+}
+
+// contains reports whether the point is inside the polygon by counting the
+// edges a ray going east from it crosses. Regions are small enough for
+// coordinates to be treated as planar.
+func contains(polygon []domain.Location, point domain.Location) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lon < (b.Lon-a.Lon)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
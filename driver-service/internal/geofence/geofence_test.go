@@ -0,0 +1,181 @@
+package geofence
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+func TestBuiltinArea(t *testing.T) {
+	area, err := Load("", DefaultPrecision)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	inside := map[string]domain.Location{
+		"istanbul": {Lat: 41.0431, Lon: 29.0099},
+		"ankara":   {Lat: 39.9334, Lon: 32.8597},
+		"izmir":    {Lat: 38.4237, Lon: 27.1428},
+		"antalya":  {Lat: 36.8969, Lon: 30.7133},
+		"edirne":   {Lat: 41.6771, Lon: 26.5557},
+		"kars":     {Lat: 40.6013, Lon: 43.0975},
+		"hakkari":  {Lat: 37.5744, Lon: 43.7408},
+		"antakya":  {Lat: 36.2021, Lon: 36.1604},
+	}
+	for name, location := range inside {
+		if _, err := area.Normalize(location); err != nil {
+			t.Errorf("%s: Normalize() error = %v", name, err)
+		}
+	}
+
+	outside := map[string]domain.Location{
+		"athens":    {Lat: 37.9838, Lon: 23.7275},
+		"sofia":     {Lat: 42.6977, Lon: 23.3219},
+		"tbilisi":   {Lat: 41.7151, Lon: 44.8271},
+		"nicosia":   {Lat: 35.1856, Lon: 33.3823},
+		"aleppo":    {Lat: 36.2021, Lon: 37.1343},
+		"black sea": {Lat: 43.0, Lon: 34.0},
+	}
+	for name, location := range outside {
+		if _, err := area.Normalize(location); !errors.Is(err, ErrOutsideServiceArea) {
+			t.Errorf("%s: Normalize() error = %v, want ErrOutsideServiceArea", name, err)
+		}
+	}
+}
+
+func TestArea_Normalize(t *testing.T) {
+	area, err := New(4, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	got, err := area.Normalize(domain.Location{Lat: 41.043149, Lon: -29.009951})
+	if err != nil {
+		t.Fatalf("Normalize() error = %v", err)
+	}
+	if got.Lat != 41.0431 || got.Lon != -29.01 {
+		t.Errorf("Normalize() = %v, want 41.0431,-29.01", got)
+	}
+
+	if _, err := area.Normalize(domain.Location{}); !errors.Is(err, ErrUnknownLocation) {
+		t.Errorf("Normalize(0,0) error = %v, want ErrUnknownLocation", err)
+	}
+	// Rounds to 0,0 at this precision
+	if _, err := area.Normalize(domain.Location{Lat: 0.00001, Lon: -0.00002}); !errors.Is(err, ErrUnknownLocation) {
+		t.Errorf("Normalize(~0,0) error = %v, want ErrUnknownLocation", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	square := write("square.json", `{"regions":[{"name":"square","polygon":[
+		{"lat":10,"lon":10},{"lat":10,"lon":20},{"lat":20,"lon":20},{"lat":20,"lon":10}]}]}`)
+	area, err := Load(square, DefaultPrecision)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if _, err := area.Normalize(domain.Location{Lat: 15, Lon: 15}); err != nil {
+		t.Errorf("Normalize() inside error = %v", err)
+	}
+	if _, err := area.Normalize(domain.Location{Lat: 41.0431, Lon: 29.0099}); !errors.Is(err, ErrOutsideServiceArea) {
+		t.Errorf("Normalize() outside error = %v, want ErrOutsideServiceArea", err)
+	}
+
+	invalid := map[string]string{
+		"empty.json":   `{"regions":[]}`,
+		"line.json":    `{"regions":[{"name":"line","polygon":[{"lat":1,"lon":1},{"lat":2,"lon":2}]}]}`,
+		"range.json":   `{"regions":[{"name":"far","polygon":[{"lat":1,"lon":1},{"lat":2,"lon":2},{"lat":95,"lon":2}]}]}`,
+		"garbled.json": `{"regions":`,
+	}
+	for name, content := range invalid {
+		if _, err := Load(write(name, content), DefaultPrecision); err == nil {
+			t.Errorf("Load(%s) succeeded, want an error", name)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.json"), DefaultPrecision); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+	if _, err := New(11, nil); err == nil {
+		t.Error("New() accepted a precision of 11")
+	}
+}
+
+func TestBuiltinCities(t *testing.T) {
+	cities, err := LoadCities("")
+	if err != nil {
+		t.Fatalf("LoadCities() error = %v", err)
+	}
+
+	tests := []struct {
+		location domain.Location
+		want     string
+	}{
+		{domain.Location{Lat: 41.0431, Lon: 29.0099}, "istanbul"},
+		{domain.Location{Lat: 39.9334, Lon: 32.8597}, "ankara"},
+		{domain.Location{Lat: 38.4237, Lon: 27.1428}, "izmir"},
+		{domain.Location{Lat: 40.1885, Lon: 29.0610}, "bursa"},
+		{domain.Location{Lat: 36.8969, Lon: 30.7133}, "antalya"},
+		{domain.Location{Lat: 39.7767, Lon: 30.5206}, domain.CityOther},
+		{domain.Location{}, domain.CityOther},
+	}
+	for _, tt := range tests {
+		if got := cities.Locate(tt.location); got != tt.want {
+			t.Errorf("Locate(%v) = %q, want %q", tt.location, got, tt.want)
+		}
+	}
+}
+
+func TestCities_Touching(t *testing.T) {
+	cities, err := NewCities([]Region{
+		{Name: "west", Polygon: []domain.Location{{Lat: 0, Lon: 0}, {Lat: 1, Lon: 0}, {Lat: 1, Lon: 1}, {Lat: 0, Lon: 1}}},
+		{Name: "east", Polygon: []domain.Location{{Lat: 0, Lon: 1.2}, {Lat: 1, Lon: 1.2}, {Lat: 1, Lon: 2}, {Lat: 0, Lon: 2}}},
+	})
+	if err != nil {
+		t.Fatalf("NewCities() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		center   domain.Location
+		radiusKm float64
+		want     string
+	}{
+		// A degree is about 111km; the cities are 0.2 degrees apart
+		{"inside one city", domain.Location{Lat: 0.5, Lon: 0.5}, 6, "[west]"},
+		{"near an edge", domain.Location{Lat: 0.5, Lon: 0.97}, 6, "[west other]"},
+		{"between cities", domain.Location{Lat: 0.5, Lon: 1.1}, 15, "[west east other]"},
+		{"outside both", domain.Location{Lat: 5, Lon: 5}, 6, "[other]"},
+		{"just outside", domain.Location{Lat: 0.5, Lon: 2.03}, 6, "[east other]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(cities.Touching(tt.center, tt.radiusKm)); got != tt.want {
+				t.Errorf("Touching() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	invalid := [][]Region{
+		{{Name: "Istanbul", Polygon: []domain.Location{{}, {Lat: 1}, {Lon: 1}}}},
+		{{Name: domain.CityOther, Polygon: []domain.Location{{}, {Lat: 1}, {Lon: 1}}}},
+		{{Name: "twice", Polygon: []domain.Location{{}, {Lat: 1}, {Lon: 1}}}, {Name: "twice", Polygon: []domain.Location{{}, {Lat: 1}, {Lon: 1}}}},
+		{{Name: "line", Polygon: []domain.Location{{}, {Lat: 1}}}},
+	}
+	for _, regions := range invalid {
+		if _, err := NewCities(regions); err == nil {
+			t.Errorf("NewCities(%v) succeeded, want an error", regions)
+		}
+	}
+}
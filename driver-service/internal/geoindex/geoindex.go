@@ -0,0 +1,263 @@
+// Package geoindex keeps driver positions in memory, bucketed into a grid of
+// cells, so a nearby search only measures the drivers in the cells around the
+// rider instead of every stored driver. The index applies the same rules as
+// the MongoDB search; keeping it in step with the database is up to its owner.
+package geoindex
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/haversine"
+)
+
+// kmPerDegree is the length of a degree of latitude
+const kmPerDegree = 111.32
+
+// cell identifies a grid cell by its row and column
+type cell struct {
+	lat, lon int
+}
+
+// entry is an indexed driver; placed is false for drivers without a valid
+// location, which are kept for updates but never found by a search
+type entry struct {
+	driver *domain.Driver
+	cell   cell
+	placed bool
+}
+
+// Index is a grid of driver positions, safe for concurrent use
+type Index struct {
+	mu      sync.RWMutex
+	cellDeg float64
+	drivers map[string]*entry
+	cells   map[cell]map[string]*entry
+
+	refreshedAt time.Time
+}
+
+// New creates an empty index with cells of about cellKm on each side
+func New(cellKm float64) *Index {
+	if cellKm <= 0 {
+		cellKm = 1
+	}
+	return &Index{
+		cellDeg: cellKm / kmPerDegree,
+		drivers: make(map[string]*entry),
+		cells:   make(map[cell]map[string]*entry),
+	}
+}
+
+// Upsert stores a copy of the driver, replacing the one with the same ID.
+// Deleted drivers are removed. A heartbeat newer than the one the driver
+// carries is kept, since heartbeats are written separately from the driver.
+func (ix *Index) Upsert(driver *domain.Driver) {
+	if driver.DeletedAt != nil {
+		ix.Remove(driver.ID)
+		return
+	}
+	d := *driver
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if old, ok := ix.drivers[d.ID]; ok {
+		if seen := old.driver.LastSeenAt; seen != nil && (d.LastSeenAt == nil || seen.After(*d.LastSeenAt)) {
+			d.LastSeenAt = seen
+		}
+		ix.unplace(old)
+	}
+	ix.place(&d)
+}
+
+// Remove drops a driver from the index
+func (ix *Index) Remove(id string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if old, ok := ix.drivers[id]; ok {
+		ix.unplace(old)
+		delete(ix.drivers, id)
+	}
+}
+
+// Touch records a heartbeat of an indexed driver; older heartbeats are ignored
+func (ix *Index) Touch(id string, at time.Time) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	e, ok := ix.drivers[id]
+	if !ok || (e.driver.LastSeenAt != nil && !at.After(*e.driver.LastSeenAt)) {
+		return
+	}
+	// Drivers handed out by Nearby are copies, so the stored one can be replaced
+	d := *e.driver
+	d.LastSeenAt = &at
+	e.driver = &d
+}
+
+// Replace swaps the whole content of the index for the given drivers
+func (ix *Index) Replace(drivers []*domain.Driver) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.drivers = make(map[string]*entry, len(drivers))
+	ix.cells = make(map[cell]map[string]*entry)
+	for _, driver := range drivers {
+		if driver.DeletedAt != nil {
+			continue
+		}
+		d := *driver
+		ix.place(&d)
+	}
+}
+
+// place adds a driver to the map and, when its location is valid, to its cell
+func (ix *Index) place(d *domain.Driver) {
+	e := &entry{driver: d}
+	ix.drivers[d.ID] = e
+	if !validLocation(d.Location) {
+		return
+	}
+	e.cell = ix.cellOf(d.Location.Lat, d.Location.Lon)
+	e.placed = true
+	bucket, ok := ix.cells[e.cell]
+	if !ok {
+		bucket = make(map[string]*entry)
+		ix.cells[e.cell] = bucket
+	}
+	bucket[d.ID] = e
+}
+
+// unplace takes a driver out of its cell
+func (ix *Index) unplace(e *entry) {
+	if !e.placed {
+		return
+	}
+	bucket := ix.cells[e.cell]
+	delete(bucket, e.driver.ID)
+	if len(bucket) == 0 {
+		delete(ix.cells, e.cell)
+	}
+}
+
+func (ix *Index) cellOf(lat, lon float64) cell {
+	return cell{lat: int(math.Floor(lat / ix.cellDeg)), lon: int(math.Floor(lon / ix.cellDeg))}
+}
+
+// Nearby returns copies of the drivers within radiusKm of the point, nearest
+// first. Like the MongoDB search it leaves out suspended drivers, drivers
+// with an expired licence and drivers without a valid location.
+func (ix *Index) Nearby(lat, lon, radiusKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter) []*domain.Driver {
+	type found struct {
+		driver   *domain.Driver
+		distance float64
+	}
+	var results []found
+	consider := func(e *entry) {
+		d := e.driver
+		if !matches(d, taxiType, filter) {
+			return
+		}
+		distance := haversine.Distance(lat, lon, d.Location.Lat, d.Location.Lon)
+		if distance <= radiusKm {
+			results = append(results, found{driver: d, distance: distance})
+		}
+	}
+
+	ix.mu.RLock()
+	dLat := radiusKm / kmPerDegree
+	dLon := 360.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0.01 {
+		dLon = math.Min(radiusKm/(kmPerDegree*cos), 360)
+	}
+	from, to := ix.cellOf(lat-dLat, lon-dLon), ix.cellOf(lat+dLat, lon+dLon)
+	// Walking more cells than there are drivers costs more than a plain scan
+	if (to.lat-from.lat+1)*(to.lon-from.lon+1) > len(ix.drivers) {
+		for _, e := range ix.drivers {
+			if e.placed {
+				consider(e)
+			}
+		}
+	} else {
+		for row := from.lat; row <= to.lat; row++ {
+			for col := from.lon; col <= to.lon; col++ {
+				for _, e := range ix.cells[cell{lat: row, lon: col}] {
+					consider(e)
+				}
+			}
+		}
+	}
+	ix.mu.RUnlock()
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].distance != results[j].distance {
+			return results[i].distance < results[j].distance
+		}
+		return results[i].driver.ID < results[j].driver.ID
+	})
+	drivers := make([]*domain.Driver, len(results))
+	for i, r := range results {
+		d := *r.driver
+		drivers[i] = &d
+	}
+	return drivers
+}
+
+// matches applies the search filters other than the distance
+func matches(d *domain.Driver, taxiType *domain.TaxiType, filter domain.DriverFilter) bool {
+	if d.Suspended || d.LicenseExpired {
+		return false
+	}
+	if taxiType != nil && d.TaxiType != *taxiType {
+		return false
+	}
+	if filter.FleetID != "" && d.FleetID != filter.FleetID {
+		return false
+	}
+	if filter.City != "" && d.City != filter.City {
+		return false
+	}
+	if !filter.SeenSince.IsZero() && (d.LastSeenAt == nil || d.LastSeenAt.Before(filter.SeenSince)) {
+		return false
+	}
+	return d.HasTags(filter.Tags) && d.HasAttributes(filter.Attributes)
+}
+
+// validLocation rejects coordinates out of range and the zero value, which
+// is where drivers without a location end up
+func validLocation(l domain.Location) bool {
+	if l.Lat == 0 && l.Lon == 0 {
+		return false
+	}
+	return l.Lat >= -90 && l.Lat <= 90 && l.Lon >= -180 && l.Lon <= 180
+}
+
+// Len returns the number of indexed drivers
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.drivers)
+}
+
+// MarkRefreshed records that the index was brought in step with the database
+func (ix *Index) MarkRefreshed(at time.Time) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.refreshedAt = at
+}
+
+// Loaded reports whether the index was ever brought in step with the database
+func (ix *Index) Loaded() bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return !ix.refreshedAt.IsZero()
+}
+
+// Fresh reports whether the index was refreshed within maxAge of now; an
+// index that was never loaded is not fresh
+func (ix *Index) Fresh(now time.Time, maxAge time.Duration) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return !ix.refreshedAt.IsZero() && now.Sub(ix.refreshedAt) <= maxAge
+}
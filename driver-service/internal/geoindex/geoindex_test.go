@@ -0,0 +1,164 @@
+package geoindex
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/haversine"
+)
+
+func ids(drivers []*domain.Driver) []string {
+	out := make([]string, len(drivers))
+	for i, d := range drivers {
+		out[i] = d.ID
+	}
+	return out
+}
+
+func TestIndex_Nearby(t *testing.T) {
+	ix := New(1)
+	sari, siyah := domain.TaxiTypeSari, domain.TaxiTypeSiyah
+	ix.Replace([]*domain.Driver{
+		{ID: "near", TaxiType: sari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}},
+		{ID: "mid", TaxiType: siyah, FleetID: "f1", City: "istanbul", Location: domain.Location{Lat: 41.06, Lon: 29.02}},
+		{ID: "far", TaxiType: sari, Location: domain.Location{Lat: 41.2, Lon: 29.2}},
+		{ID: "suspended", TaxiType: sari, Suspended: true, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}},
+		{ID: "expired", TaxiType: sari, LicenseExpired: true, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}},
+		{ID: "nowhere", TaxiType: sari},
+	})
+
+	got := ids(ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{}))
+	if fmt.Sprint(got) != "[near mid]" {
+		t.Errorf("Nearby = %v, want [near mid]", got)
+	}
+	if got := ids(ix.Nearby(41.043, 29.01, 6, &siyah, domain.DriverFilter{})); fmt.Sprint(got) != "[mid]" {
+		t.Errorf("Nearby(siyah) = %v, want [mid]", got)
+	}
+	if got := ids(ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{FleetID: "f1"})); fmt.Sprint(got) != "[mid]" {
+		t.Errorf("Nearby(fleet) = %v, want [mid]", got)
+	}
+	if got := ids(ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{City: "istanbul"})); fmt.Sprint(got) != "[mid]" {
+		t.Errorf("Nearby(city) = %v, want [mid]", got)
+	}
+
+	// Moving a driver changes its cell; deleting it drops it
+	ix.Upsert(&domain.Driver{ID: "far", TaxiType: sari, Location: domain.Location{Lat: 41.044, Lon: 29.011}})
+	deleted := time.Now()
+	ix.Upsert(&domain.Driver{ID: "mid", DeletedAt: &deleted})
+	if got := ids(ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{})); fmt.Sprint(got) != "[near far]" {
+		t.Errorf("Nearby after updates = %v, want [near far]", got)
+	}
+	if ix.Len() != 5 {
+		t.Errorf("Len = %d, want 5", ix.Len())
+	}
+
+	// Results are copies
+	ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{})[0].Plate = "changed"
+	if got := ix.Nearby(41.043, 29.01, 6, nil, domain.DriverFilter{})[0]; got.Plate != "" {
+		t.Error("changing a result changed the index")
+	}
+}
+
+func TestIndex_TagFilter(t *testing.T) {
+	ix := New(1)
+	at := domain.Location{Lat: 41.0431, Lon: 29.0099}
+	ix.Replace([]*domain.Driver{
+		{ID: "pet", Location: at, Tags: []string{"pet-friendly"}, Attributes: map[string]string{"language": "en"}},
+		{ID: "both", Location: at, Tags: []string{"pet-friendly", "wheelchair-accessible"}, Attributes: map[string]string{"language": "tr"}},
+		{ID: "none", Location: at},
+	})
+
+	cases := []struct {
+		filter domain.DriverFilter
+		want   string
+	}{
+		{domain.DriverFilter{Tags: []string{"pet-friendly"}}, "[both pet]"},
+		{domain.DriverFilter{Tags: []string{"pet-friendly", "wheelchair-accessible"}}, "[both]"},
+		{domain.DriverFilter{Attributes: map[string]string{"language": "en"}}, "[pet]"},
+		{domain.DriverFilter{Attributes: map[string]string{"language": ""}}, "[both pet]"},
+		{domain.DriverFilter{Tags: []string{"wheelchair-accessible"}, Attributes: map[string]string{"language": "en"}}, "[]"},
+	}
+	for _, c := range cases {
+		if got := ids(ix.Nearby(41.043, 29.01, 6, nil, c.filter)); fmt.Sprint(got) != c.want {
+			t.Errorf("Nearby(%+v) = %v, want %s", c.filter, got, c.want)
+		}
+	}
+}
+
+func TestIndex_Heartbeats(t *testing.T) {
+	ix := New(1)
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	ix.Upsert(&domain.Driver{ID: "d1", Location: domain.Location{Lat: 41, Lon: 29}, LastSeenAt: &earlier})
+
+	live := domain.DriverFilter{SeenSince: now.Add(-time.Minute)}
+	if got := ix.Nearby(41, 29, 1, nil, live); len(got) != 0 {
+		t.Fatalf("Nearby(live) = %v, want none", ids(got))
+	}
+
+	ix.Touch("d1", now)
+	if got := ix.Nearby(41, 29, 1, nil, live); len(got) != 1 {
+		t.Fatalf("Nearby(live) after heartbeat = %v, want d1", ids(got))
+	}
+
+	// An update carrying an older heartbeat keeps the newer one
+	ix.Upsert(&domain.Driver{ID: "d1", Location: domain.Location{Lat: 41, Lon: 29}, LastSeenAt: &earlier})
+	if got := ix.Nearby(41, 29, 1, nil, live); len(got) != 1 || !got[0].LastSeenAt.Equal(now) {
+		t.Errorf("update discarded the newer heartbeat")
+	}
+}
+
+// TestIndex_MatchesLinearScan checks the grid finds exactly the drivers a
+// scan of every driver would, for cell sizes smaller and larger than the radius
+func TestIndex_MatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	drivers := make([]*domain.Driver, 2000)
+	for i := range drivers {
+		drivers[i] = &domain.Driver{
+			ID:       fmt.Sprintf("d%04d", i),
+			Location: domain.Location{Lat: 41 + rng.Float64()*0.3, Lon: 28.8 + rng.Float64()*0.4},
+		}
+	}
+
+	for _, cellKm := range []float64{0.2, 1, 10} {
+		ix := New(cellKm)
+		ix.Replace(drivers)
+		for q := 0; q < 50; q++ {
+			lat, lon := 41+rng.Float64()*0.3, 28.8+rng.Float64()*0.4
+
+			var want []string
+			for _, d := range drivers {
+				if haversine.Distance(lat, lon, d.Location.Lat, d.Location.Lon) <= 6 {
+					want = append(want, d.ID)
+				}
+			}
+			got := ids(ix.Nearby(lat, lon, 6, nil, domain.DriverFilter{}))
+			sort.Strings(got)
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Fatalf("cell %vkm: grid found %d drivers, scan %d", cellKm, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestIndex_Fresh(t *testing.T) {
+	ix := New(1)
+	now := time.Now()
+	if ix.Fresh(now, time.Minute) || ix.Loaded() {
+		t.Error("an index never loaded should not be fresh")
+	}
+	ix.MarkRefreshed(now)
+	if !ix.Fresh(now.Add(30*time.Second), time.Minute) {
+		t.Error("index should be fresh within maxAge")
+	}
+	if ix.Fresh(now.Add(2*time.Minute), time.Minute) {
+		t.Error("index should be stale after maxAge")
+	}
+	if !ix.Loaded() {
+		t.Error("a stale index should still count as loaded")
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AutocompleteHandler handles HTTP requests for driver type-ahead search
+type AutocompleteHandler struct {
+	useCase usecase.AutocompleteUseCase
+	logger  *zap.Logger
+}
+
+// NewAutocompleteHandler creates a new autocomplete handler
+func NewAutocompleteHandler(useCase usecase.AutocompleteUseCase, logger *zap.Logger) *AutocompleteHandler {
+	return &AutocompleteHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// AutocompleteDrivers handles GET /drivers/autocomplete
+// @Summary Autocomplete drivers
+// @Description Suggest drivers as a dispatcher types. Every word of q must start a word of the driver's name, the plate or a letter or digit group of the plate; case and Turkish diacritics are ignored, so "sukru 34" finds Şükrü with plate 34ABC123. Highlights mark the matched characters. A search that runs past AUTOCOMPLETE_BUDGET_MS returns no suggestions with timedOut set.
+// @Tags drivers
+// @Produce json
+// @Param q query string true "Start of the name or plate, up to 5 words" example(ahm)
+// @Param limit query int false "Maximum suggestions; defaults to AUTOCOMPLETE_DEFAULT_LIMIT and is capped at AUTOCOMPLETE_MAX_LIMIT" example(8)
+// @Param fleetId query string false "Only suggest drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} usecase.AutocompleteResponse "Suggestions" example({"suggestions":[{"id":"507f1f77bcf86cd799439011","name":"Ahmet Demir","plate":"34ABC123","highlights":[{"field":"name","start":0,"length":3}]}],"timedOut":false})
+// @Failure 400 {object} ErrorResponse "Invalid query" example({"error":{"code":"VALIDATION_ERROR","message":"q must contain 1 to 5 words of letters or digits"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to search drivers"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/autocomplete [get]
+func (h *AutocompleteHandler) AutocompleteDrivers(c *gin.Context) {
+	// A missing limit parses as 0 and gets the default
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.useCase.Autocomplete(c.Request.Context(), c.Query("q"), driverFilter(c), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidSearchQuery) {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to search drivers")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/rules"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BulkUpdateHandler handles HTTP requests for fleet-wide driver updates
+type BulkUpdateHandler struct {
+	useCase usecase.BulkUpdateUseCase
+	logger  *zap.Logger
+}
+
+// NewBulkUpdateHandler creates a new bulk update handler
+func NewBulkUpdateHandler(useCase usecase.BulkUpdateUseCase, logger *zap.Logger) *BulkUpdateHandler {
+	return &BulkUpdateHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// BulkUpdateDrivers handles POST /drivers/bulk-update
+// @Summary Update many drivers at once
+// @Description Apply a patch to every driver matching a filter, e.g. suspend a fleet for inspection or move its cars to another taxi type, in a single database update. Fleet admins only reach their own fleet. An update may change at most BULK_UPDATE_MAX_DRIVERS drivers; narrow the filter or split the ids when it matches more. Set dryRun to count the drivers without changing them. Suspended drivers are taken off shift, and subscribers receive a driver.suspended or driver.updated event per changed driver. Every update, dry runs included, is recorded with the caller for the audit trail.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param update body usecase.BulkUpdateRequest true "Filter, patch and dry run"
+// @Success 200 {object} domain.BulkUpdate "Matched and modified drivers"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"invalid bulk update: patch must set carBrand, carModel, taksiType or suspended"}})
+// @Failure 409 {object} ErrorResponse "Filter matches too many drivers" example({"error":{"code":"BULK_LIMIT_EXCEEDED","message":"bulk update matches too many drivers: the filter matches 2400 drivers, at most 1000 can be updated at once"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update drivers"}})
+// @Router /drivers/bulk-update [post]
+func (h *BulkUpdateHandler) BulkUpdateDrivers(c *gin.Context) {
+	var req usecase.BulkUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	update, err := h.useCase.BulkUpdate(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidBulkUpdate), errors.As(err, new(*rules.ValidationError)):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		case errors.Is(err, domain.ErrBulkUpdateTooLarge):
+			respondError(c, http.StatusConflict, "BULK_LIMIT_EXCEEDED", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to update drivers")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
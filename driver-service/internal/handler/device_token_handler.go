@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceTokenHandler handles HTTP requests for driver device tokens
+type DeviceTokenHandler struct {
+	useCase usecase.DeviceTokenUseCase
+	logger  *zap.Logger
+}
+
+// NewDeviceTokenHandler creates a new device token handler
+func NewDeviceTokenHandler(useCase usecase.DeviceTokenUseCase, logger *zap.Logger) *DeviceTokenHandler {
+	return &DeviceTokenHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// IssueDeviceToken handles POST /drivers/:id/device-tokens
+// @Summary Issue a device token
+// @Description Issue a long-lived token for one of the driver's devices. It only allows location updates and heartbeats of this driver. Issuing a token for a device revokes the device's previous token. The token is only returned in this response.
+// @Tags device-tokens
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body usecase.IssueDeviceTokenRequest true "Device"
+// @Success 201 {object} domain.DeviceToken "Token issued"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"deviceId is required"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Too many active tokens" example({"error":{"code":"CONFLICT","message":"a driver can have at most 10 active device tokens"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to issue device token"}})
+// @Router /drivers/{id}/device-tokens [post]
+func (h *DeviceTokenHandler) IssueDeviceToken(c *gin.Context) {
+	var req usecase.IssueDeviceTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	var token *domain.DeviceToken
+	token, err := h.useCase.Issue(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to issue device token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// ListDriverDeviceTokens handles GET /drivers/:id/device-tokens
+// @Summary List a driver's device tokens
+// @Description List the driver's device tokens, newest first. Token values are not included.
+// @Tags device-tokens
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param active query bool false "Only list tokens that are not revoked"
+// @Success 200 {array} domain.DeviceToken "Tokens"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/device-tokens [get]
+func (h *DeviceTokenHandler) ListDriverDeviceTokens(c *gin.Context) {
+	h.list(c, c.Param("id"))
+}
+
+// ListDeviceTokens handles GET /admin/device-tokens
+// @Summary List device tokens
+// @Description List device tokens of every driver, or of one, newest first. Token values are not included.
+// @Tags admin
+// @Produce json
+// @Param driverId query string false "Only list tokens of this driver" example(507f1f77bcf86cd799439011)
+// @Param active query bool false "Only list tokens that are not revoked"
+// @Success 200 {array} domain.DeviceToken "Tokens"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/device-tokens [get]
+func (h *DeviceTokenHandler) ListDeviceTokens(c *gin.Context) {
+	h.list(c, c.Query("driverId"))
+}
+
+func (h *DeviceTokenHandler) list(c *gin.Context, driverID string) {
+	tokens, err := h.useCase.List(c.Request.Context(), driverID, c.Query("active") == "true")
+	if err != nil {
+		h.handleError(c, err, "failed to list device tokens")
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RevokeDeviceToken handles DELETE /admin/device-tokens/:id
+// @Summary Revoke a device token
+// @Description Revoke a device token; the device has to be issued a new one. Revoking a revoked token succeeds.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Token ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.DeviceToken "Revoked token"
+// @Failure 404 {object} ErrorResponse "Token not found" example({"error":{"code":"NOT_FOUND","message":"device token not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/device-tokens/{id} [delete]
+func (h *DeviceTokenHandler) RevokeDeviceToken(c *gin.Context) {
+	token, err := h.useCase.Revoke(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to revoke device token")
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// RotateDeviceToken handles POST /admin/device-tokens/:id/rotate
+// @Summary Rotate a device token
+// @Description Issue a new token for the same device and revoke this one. The new token is only returned in this response.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Token ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 201 {object} domain.DeviceToken "New token"
+// @Failure 404 {object} ErrorResponse "Token not found" example({"error":{"code":"NOT_FOUND","message":"device token not found"}})
+// @Failure 409 {object} ErrorResponse "Token is revoked" example({"error":{"code":"CONFLICT","message":"device token is revoked"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/device-tokens/{id}/rotate [post]
+func (h *DeviceTokenHandler) RotateDeviceToken(c *gin.Context) {
+	token, err := h.useCase.Rotate(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to rotate device token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// VerifyDeviceToken handles POST /device-tokens/verify
+// @Summary Verify a device token
+// @Description Called by the gateway to authenticate a device: returns the token's driver and scopes when it is valid and records its use.
+// @Tags device-tokens
+// @Accept json
+// @Produce json
+// @Param request body usecase.VerifyDeviceTokenRequest true "Presented token"
+// @Success 200 {object} domain.DeviceToken "Valid token"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Invalid or revoked token" example({"error":{"code":"UNAUTHORIZED","message":"invalid or revoked device token"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /device-tokens/verify [post]
+func (h *DeviceTokenHandler) VerifyDeviceToken(c *gin.Context) {
+	var req usecase.VerifyDeviceTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	token, err := h.useCase.Verify(c.Request.Context(), req.Token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDeviceToken) {
+			respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to verify device token")
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// handleError maps device token use case errors to HTTP responses
+func (h *DeviceTokenHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrDeviceTokenNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case isForbiddenError(err):
+		respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, usecase.ErrDeviceIDRequired):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrTooManyDeviceTokens), errors.Is(err, usecase.ErrDeviceTokenRevoked):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
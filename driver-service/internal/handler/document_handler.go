@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DocumentHandler handles HTTP requests for driver onboarding documents
+type DocumentHandler struct {
+	useCase usecase.DocumentUseCase
+	logger  *zap.Logger
+}
+
+// NewDocumentHandler creates a new document handler
+func NewDocumentHandler(useCase usecase.DocumentUseCase, logger *zap.Logger) *DocumentHandler {
+	return &DocumentHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// UploadDocument handles POST /drivers/:id/documents
+// @Summary Upload driver document
+// @Description Attach a document reference (license, registration or insurance) to a driver, replacing any previous document of the same type
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param document body usecase.UploadDocumentRequest true "Document reference"
+// @Success 200 {object} domain.Driver "Document stored"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"invalid document type: passport"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Router /drivers/{id}/documents [post]
+func (h *DocumentHandler) UploadDocument(c *gin.Context) {
+	var req usecase.UploadDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	driver, err := h.useCase.UploadDocument(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// DeleteDocument handles DELETE /drivers/:id/documents/:type
+// @Summary Delete driver document
+// @Description Remove the driver's document of the given type
+// @Tags documents
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param type path string true "Document type" Enums(license, registration, insurance)
+// @Success 200 {object} domain.Driver "Document removed"
+// @Failure 400 {object} ErrorResponse "Invalid document type"
+// @Failure 404 {object} ErrorResponse "Driver or document not found" example({"error":{"code":"NOT_FOUND","message":"document not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Router /drivers/{id}/documents/{type} [delete]
+func (h *DocumentHandler) DeleteDocument(c *gin.Context) {
+	driver, err := h.useCase.DeleteDocument(c.Request.Context(), c.Param("id"), domain.DocumentType(c.Param("type")))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// handleError maps document use case errors to HTTP responses
+func (h *DocumentHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrDocumentNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrInvalidDocumentType),
+		errors.Is(err, usecase.ErrInvalidDocumentURL),
+		errors.Is(err, usecase.ErrDocumentExpired):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, "failed to update driver")
+	}
+}
@@ -1,15 +1,27 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/bitaksi/driver-service/internal/rules"
 	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/bitaksi/driver-service/pkg/geo"
+	"github.com/bitaksi/driver-service/pkg/plate"
+	"github.com/bitaksi/driver-service/pkg/units"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// DistanceUnitHeader names the unit of the distances of nearby search results
+const DistanceUnitHeader = "X-Distance-Unit"
+
 // DriverHandler handles HTTP requests for drivers
 type DriverHandler struct {
 	useCase usecase.DriverUseCase
@@ -32,8 +44,12 @@ func NewDriverHandler(useCase usecase.DriverUseCase, logger *zap.Logger) *Driver
 // @Produce json
 // @Param driver body usecase.CreateDriverRequest true "Driver information" example({"firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taksiType":"sari","carBrand":"Toyota","carModel":"Corolla","lat":41.0431,"lon":29.0099})
 // @Success 201 {object} domain.Driver "Driver created successfully" example({"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:00:00Z"})
-// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"plate must be in format: 2-3 digits, 1-3 letters, 1-4 digits (e.g., 34ABC123)"}})
+// @Failure 400 {object} ErrorResponse "Validation error, or OUTSIDE_SERVICE_AREA for a location outside the service area" example({"error":{"code":"VALIDATION_ERROR","message":"plate must be in format: 2-3 digits, 1-3 letters, 1-4 digits (e.g., 34ABC123)"}})
+// @Failure 403 {object} ErrorResponse "Fleet admin creating a driver in another fleet, or BLACKLISTED for a driver on the blacklist" example({"error":{"code":"FORBIDDEN","message":"fleet admins can only create drivers in their own fleet"}})
+// @Failure 402 {object} ErrorResponse "Fleet has reached its driver quota" example({"error":{"code":"QUOTA_EXCEEDED","message":"fleet has reached its driver quota of 50 drivers"}})
+// @Failure 409 {object} ErrorResponse "Plate, phone or email taken by another driver, or REQUEST_IN_PROGRESS while another request is changing the same plate, phone or email" example({"error":{"code":"REQUEST_IN_PROGRESS","message":"another request is changing the same driver, plate, phone or email; retry shortly"}})
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header, or BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and BLACKLIST_FAIL_MODE is closed" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
 // @Router /drivers [post]
 func (h *DriverHandler) CreateDriver(c *gin.Context) {
 	var req usecase.CreateDriverRequest
@@ -44,12 +60,39 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 
 	driver, err := h.useCase.CreateDriver(c.Request.Context(), &req)
 	if err != nil {
+		if isConflictError(err) {
+			h.respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrDriverChangeInProgress) {
+			h.respondError(c, http.StatusConflict, "REQUEST_IN_PROGRESS", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrOutsideServiceArea) {
+			h.respondError(c, http.StatusBadRequest, "OUTSIDE_SERVICE_AREA", err.Error())
+			return
+		}
 		if isValidationError(err) {
 			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 			return
 		}
-		h.logger.Error("failed to create driver", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create driver")
+		if isForbiddenError(err) {
+			h.respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrDriverBlacklisted) {
+			h.respondError(c, http.StatusForbidden, "BLACKLISTED", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrBlacklistUnavailable) {
+			h.respondError(c, http.StatusServiceUnavailable, "BLACKLIST_UNAVAILABLE", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrQuotaExceeded) {
+			h.respondError(c, http.StatusPaymentRequired, "QUOTA_EXCEEDED", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to create driver")
 		return
 	}
 
@@ -63,11 +106,14 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
-// @Param driver body usecase.UpdateDriverRequest true "Driver update information. Location uses top-level lat/lon fields." example({"firstName":"Ali","lastName":"Kurt","plate":"34G99","taksiType":"siyah","carBrand":"Mercedes","carModel":"G Class","lat":42.0082,"lon":28.9784})
-// @Success 200 {object} domain.Driver "Driver updated successfully" example({"id":"507f1f77bcf86cd799439011","firstName":"Ali","lastName":"Kurt","plate":"34G99","taxiType":"siyah","carBrand":"Mercedes","carModel":"G Class","location":{"lat":42.0082,"lon":28.9784},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:30:00Z"})
-// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"both lat and lon must be provided together"}})
+// @Param driver body usecase.UpdateDriverRequest true "Driver update information. Location uses top-level lat/lon fields." example({"firstName":"Ali","lastName":"Kurt","plate":"34G1234","taksiType":"siyah","carBrand":"Mercedes","carModel":"G Class","lat":42.0082,"lon":28.9784})
+// @Success 200 {object} domain.Driver "Driver updated successfully" example({"id":"507f1f77bcf86cd799439011","firstName":"Ali","lastName":"Kurt","plate":"34G1234","taxiType":"siyah","carBrand":"Mercedes","carModel":"G Class","location":{"lat":42.0082,"lon":28.9784},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:30:00Z"})
+// @Failure 400 {object} ErrorResponse "Validation error, OUTSIDE_SERVICE_AREA for a location outside the service area, or IMPLAUSIBLE_LOCATION for a rejected location jump" example({"error":{"code":"VALIDATION_ERROR","message":"both lat and lon must be provided together"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet" example({"error":{"code":"FORBIDDEN","message":"driver does not belong to your fleet"}})
 // @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Plate, phone or email taken by another driver, or REQUEST_IN_PROGRESS while another request is changing the same driver, plate, phone or email" example({"error":{"code":"REQUEST_IN_PROGRESS","message":"another request is changing the same driver, plate, phone or email; retry shortly"}})
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
 // @Router /drivers/{id} [put]
 func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 	id := c.Param("id")
@@ -88,12 +134,31 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 			h.respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
 			return
 		}
+		if isConflictError(err) {
+			h.respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrDriverChangeInProgress) {
+			h.respondError(c, http.StatusConflict, "REQUEST_IN_PROGRESS", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrOutsideServiceArea) {
+			h.respondError(c, http.StatusBadRequest, "OUTSIDE_SERVICE_AREA", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrImplausibleLocation) {
+			h.respondError(c, http.StatusBadRequest, "IMPLAUSIBLE_LOCATION", err.Error())
+			return
+		}
 		if isValidationError(err) {
 			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 			return
 		}
-		h.logger.Error("failed to update driver", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update driver")
+		if isForbiddenError(err) {
+			h.respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to update driver")
 		return
 	}
 
@@ -109,6 +174,7 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 // @Success 200 {object} domain.Driver "Driver details" example({"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:00:00Z"})
 // @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
 // @Router /drivers/{id} [get]
 func (h *DriverHandler) GetDriver(c *gin.Context) {
 	id := c.Param("id")
@@ -123,8 +189,7 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 			h.respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
 			return
 		}
-		h.logger.Error("failed to get driver", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get driver")
+		respondInternalError(c, h.logger, err, "failed to get driver")
 		return
 	}
 
@@ -137,19 +202,30 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 // @Tags drivers
 // @Produce json
 // @Param page query int false "Page number" default(1) example(1)
-// @Param pageSize query int false "Page size" default(20) example(20)
-// @Success 200 {object} usecase.ListDriversResponse "Paginated list of drivers" example({"drivers":[{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:00:00Z"}],"totalCount":1,"page":1,"pageSize":20})
+// @Param pageSize query int false "Page size; defaults to DEFAULT_PAGE_SIZE and is capped at MAX_PAGE_SIZE" default(20) example(20)
+// @Param fleetId query string false "Only list drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param city query string false "Only list drivers of this city, or other for drivers outside every configured city" example(istanbul)
+// @Param tags query string false "Only list drivers carrying all of these comma-separated tags" example(pet-friendly,wheelchair-accessible)
+// @Param attributes query string false "Only list drivers with all of these comma-separated key:value attributes; a key alone matches any value" example(language:en)
+// @Success 200 {object} usecase.ListDriversResponse "Paginated list of drivers" example({"drivers":[{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:00:00Z"}],"totalCount":1,"page":1,"pageSize":20,"totalPages":1,"hasNext":false,"hasPrev":false})
 // @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"invalid page number"}})
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list drivers"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
 // @Router /drivers [get]
 func (h *DriverHandler) ListDrivers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	// A missing pageSize parses as 0 and gets the configured default
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
 
-	response, err := h.useCase.ListDrivers(c.Request.Context(), page, pageSize)
+	filter, err := driverSearchFilter(c)
 	if err != nil {
-		h.logger.Error("failed to list drivers", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list drivers")
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	response, err := h.useCase.ListDrivers(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to list drivers")
 		return
 	}
 
@@ -158,62 +234,406 @@ func (h *DriverHandler) ListDrivers(c *gin.Context) {
 
 // FindNearbyDrivers handles GET /drivers/nearby
 // @Summary Find nearby drivers
-// @Description Find drivers within 6km radius
+// @Description Find drivers within 6km radius. With live=true, drivers whose latest heartbeat is older than HEARTBEAT_TIMEOUT_SEC are excluded.
 // @Tags drivers
 // @Produce json
-// @Param lat query float64 true "Latitude" example(41.0431)
-// @Param lon query float64 true "Longitude" example(29.0099)
-// @Param taksiType query string false "Taxi type (sari, turkuaz, siyah)" example(sari)
-// @Success 200 {array} usecase.NearbyDriverResponse "List of nearby drivers sorted by distance" example([{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"distance":0.5}])
+// @Param lat query float64 false "Latitude; required unless geohash is given" example(41.0431)
+// @Param lon query float64 false "Longitude; required unless geohash is given" example(29.0099)
+// @Param geohash query string false "Search around the center of this geohash cell instead of lat and lon" example(sxk9kp)
+// @Param taksiType query string false "Taxi type, one of GET /taxi-types" example(sari)
+// @Param fleetId query string false "Only return drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param city query string false "Only return drivers of this city; without it the search reads the cities the search circle reaches" example(istanbul)
+// @Param live query bool false "Only return drivers with a recent heartbeat; defaults to the HEARTBEAT_FILTER_NEARBY setting" example(true)
+// @Param tags query string false "Only return drivers carrying all of these comma-separated tags" example(pet-friendly)
+// @Param attributes query string false "Only return drivers with all of these comma-separated key:value attributes; a key alone matches any value" example(language:en)
+// @Param units query string false "Also give each distance as distance in km, m or mi, rounded to about a meter" Enums(km, m, mi) example(m)
+// @Param limit query int false "Most drivers returned, nearest first; defaults to NEARBY_DEFAULT_LIMIT and is capped at NEARBY_MAX_LIMIT" example(10)
+// @Param offset query int false "Nearest drivers skipped, for the next page" default(0) example(10)
+// @Success 200 {array} usecase.NearbyDriverResponse "List of nearby drivers sorted by distance" example([{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","distanceKm":0.5,"durationSec":95,"lastLocationUpdate":"2025-12-06T01:00:00Z","staleSeconds":42}])
+// @Header 200 {string} X-Distance-Unit "Unit of distance: km, m or mi"
 // @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"latitude is required"}})
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to find nearby drivers"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
 // @Router /drivers/nearby [get]
 func (h *DriverHandler) FindNearbyDrivers(c *gin.Context) {
 	latStr := c.Query("lat")
 	lonStr := c.Query("lon")
+	geohash := c.Query("geohash")
 	taksiTypeStr := c.Query("taksiType")
 
+	lat, lon, ok := h.searchCenter(c, latStr, lonStr, geohash)
+	if !ok {
+		return
+	}
+
+	var taxiType *domain.TaxiType
+	if taksiTypeStr != "" {
+		tt := domain.TaxiType(taksiTypeStr)
+		if !tt.IsValid() {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid taksiType. Must be one of: "+domain.TaxiTypeList())
+			return
+		}
+		taxiType = &tt
+	}
+
+	filter, err := driverSearchFilter(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if liveStr := c.Query("live"); liveStr != "" {
+		live, err := strconv.ParseBool(liveStr)
+		if err != nil {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "live must be true or false")
+			return
+		}
+		filter.Live = &live
+	}
+
+	unit, convert, ok := h.distanceUnit(c)
+	if !ok {
+		return
+	}
+	page, ok := h.nearbyPage(c)
+	if !ok {
+		return
+	}
+
+	drivers, err := h.useCase.FindNearbyDrivers(c.Request.Context(), lat, lon, taxiType, filter, page)
+	if err != nil {
+		if isValidationError(err) {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to find nearby drivers")
+		return
+	}
+
+	if convert {
+		for _, driver := range drivers {
+			distance := unit.Convert(driver.DistanceKm)
+			driver.Distance = &distance
+		}
+	}
+	c.Header(DistanceUnitHeader, string(unit))
+	c.JSON(http.StatusOK, drivers)
+}
+
+// nearbyPage parses the limit and offset of a nearby search. A missing limit
+// is left for the use case to default. It responds with a validation error and
+// reports false for values that are not whole numbers in range.
+func (h *DriverHandler) nearbyPage(c *gin.Context) (domain.NearbyPage, bool) {
+	var page domain.NearbyPage
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "limit must be a positive integer")
+			return page, false
+		}
+		page.Limit = limit
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "offset must be a non-negative integer")
+			return page, false
+		}
+		page.Offset = offset
+	}
+	return page, true
+}
+
+// distanceUnit parses the units of the distances in nearby search results and
+// reports whether any were asked for; without them distances are only given
+// in kilometers. It responds with a validation error and reports false for
+// unknown units.
+func (h *DriverHandler) distanceUnit(c *gin.Context) (unit units.Unit, convert, ok bool) {
+	unit, err := units.Parse(c.Query("units"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return "", false, false
+	}
+	return unit, c.Query("units") != "", true
+}
+
+// searchCenter parses the center of a nearby search, given either as lat and
+// lon or as a geohash whose cell center is searched around. It responds with
+// a validation error and reports false when neither or both are given.
+func (h *DriverHandler) searchCenter(c *gin.Context, latStr, lonStr, geohash string) (lat, lon float64, ok bool) {
+	if geohash != "" {
+		if latStr != "" || lonStr != "" {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "give either lat and lon or geohash, not both")
+			return 0, 0, false
+		}
+		lat, lon, err := geo.Decode(geohash)
+		if err != nil {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid geohash")
+			return 0, 0, false
+		}
+		return lat, lon, true
+	}
+
 	if latStr == "" || lonStr == "" {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "lat and lon are required")
-		return
+		return 0, 0, false
 	}
 
 	lat, err := strconv.ParseFloat(latStr, 64)
 	if err != nil {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid lat format")
-		return
+		return 0, 0, false
 	}
 
-	lon, err := strconv.ParseFloat(lonStr, 64)
+	lon, err = strconv.ParseFloat(lonStr, 64)
 	if err != nil {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid lon format")
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// FindDriversAlongRoute handles POST /drivers/nearby/route
+// @Summary Find drivers along a route
+// @Description Find drivers within widthKm (default 0.5, at most 2) of the route through 2 to 25 ordered waypoints, for riders on a highway or any road where a radius around one point misses the drivers that can reach them. Routes can be up to 100 km long. Each driver is returned once, sorted by distance to the route; routeKm tells where along the route the driver is. With live=true, drivers whose latest heartbeat is older than HEARTBEAT_TIMEOUT_SEC are excluded.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param units query string false "Also give distanceKm and routeKm as distance and routeDistance in km, m or mi, rounded to about a meter; widthKm stays in km" Enums(km, m, mi) example(m)
+// @Param route body usecase.RouteSearchRequest true "Route and corridor" example({"waypoints":[{"lat":41.0431,"lon":29.0099},{"lat":41.0766,"lon":29.0257},{"lat":41.1086,"lon":29.0436}],"widthKm":0.5,"taksiType":"sari"})
+// @Success 200 {array} usecase.RouteDriverResponse "Drivers along the route sorted by distance to it" example([{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","location":{"lat":41.0612,"lon":29.0171},"distanceKm":0.2,"routeKm":2.1}])
+// @Header 200 {string} X-Distance-Unit "Unit of distance and routeDistance: km, m or mi"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"route must have between 2 and 25 waypoints"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to find drivers along route"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/nearby/route [post]
+func (h *DriverHandler) FindDriversAlongRoute(c *gin.Context) {
+	var req usecase.RouteSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.TaxiType != nil && !req.TaxiType.IsValid() {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid taksiType. Must be one of: "+domain.TaxiTypeList())
 		return
 	}
 
-	var taxiType *domain.TaxiType
-	if taksiTypeStr != "" {
-		tt := domain.TaxiType(taksiTypeStr)
-		if !tt.IsValid() {
-			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid taksiType. Must be one of: sari, turkuaz, siyah")
-			return
-		}
-		taxiType = &tt
+	filter := domain.DriverFilter{FleetID: req.FleetID, Live: req.Live}
+	if identity, ok := domain.IdentityFromContext(c.Request.Context()); ok && identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+
+	unit, convert, ok := h.distanceUnit(c)
+	if !ok {
+		return
 	}
 
-	drivers, err := h.useCase.FindNearbyDrivers(c.Request.Context(), lat, lon, taxiType)
+	drivers, err := h.useCase.FindDriversAlongRoute(c.Request.Context(), req.Waypoints, req.WidthKm, req.TaxiType, filter)
 	if err != nil {
-		if isValidationError(err) {
+		if isValidationError(err) ||
+			errors.Is(err, usecase.ErrRouteWaypoints) ||
+			errors.Is(err, usecase.ErrRouteTooLong) ||
+			errors.Is(err, usecase.ErrInvalidCorridorWidth) {
 			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 			return
 		}
-		h.logger.Error("failed to find nearby drivers", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to find nearby drivers")
+		respondInternalError(c, h.logger, err, "failed to find drivers along route")
 		return
 	}
 
+	if convert {
+		for _, driver := range drivers {
+			distance, routeDistance := unit.Convert(driver.DistanceKm), unit.Convert(driver.RouteKm)
+			driver.Distance, driver.RouteDistance = &distance, &routeDistance
+		}
+	}
+	c.Header(DistanceUnitHeader, string(unit))
 	c.JSON(http.StatusOK, drivers)
 }
 
+// driverFilter reads the optional driver filters from the query string. Fleet
+// admins are always limited to their own fleet.
+func driverFilter(c *gin.Context) domain.DriverFilter {
+	filter := domain.DriverFilter{FleetID: c.Query("fleetId")}
+	if identity, ok := domain.IdentityFromContext(c.Request.Context()); ok && identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+	return filter
+}
+
+// driverSearchFilter adds the city, tag and attribute filters of driver
+// searches to driverFilter. Tags and attributes are comma-separated and may
+// also be repeated.
+func driverSearchFilter(c *gin.Context) (domain.DriverFilter, error) {
+	filter := driverFilter(c)
+	if city := c.Query("city"); city != "" {
+		normalized, ok := domain.NormalizeTag(city)
+		if !ok {
+			return filter, fmt.Errorf("invalid city %q", city)
+		}
+		filter.City = normalized
+	}
+	for _, tag := range queryList(c, "tags") {
+		normalized, ok := domain.NormalizeTag(tag)
+		if !ok {
+			return filter, fmt.Errorf("invalid tag %q", tag)
+		}
+		filter.Tags = append(filter.Tags, normalized)
+	}
+	for _, attribute := range queryList(c, "attributes") {
+		key, value, _ := strings.Cut(attribute, ":")
+		normalized, ok := domain.NormalizeTag(key)
+		if !ok {
+			return filter, fmt.Errorf("invalid attribute %q", attribute)
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[normalized] = strings.TrimSpace(value)
+	}
+	return filter, nil
+}
+
+// queryList splits the comma-separated values of a query parameter
+func queryList(c *gin.Context, name string) []string {
+	var values []string
+	for _, param := range c.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// SetAvailability handles PUT /drivers/:id/availability
+// @Summary Set driver availability
+// @Description Put a driver on or off shift. Going on shift requires a verified phone number and an unexpired licence.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param availability body usecase.SetAvailabilityRequest true "Availability"
+// @Success 200 {object} domain.Driver "Driver availability updated"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"available is required"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet" example({"error":{"code":"FORBIDDEN","message":"driver does not belong to your fleet"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Contact not verified, licence expired or driver suspended" example({"error":{"code":"CONTACT_NOT_VERIFIED","message":"phone must be verified before going on shift"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/{id}/availability [put]
+func (h *DriverHandler) SetAvailability(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "driver ID is required")
+		return
+	}
+
+	var req usecase.SetAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "available is required")
+		return
+	}
+
+	driver, err := h.useCase.SetAvailability(c.Request.Context(), id, *req.Available)
+	if err != nil {
+		if err.Error() == "driver not found" {
+			h.respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+			return
+		}
+		if errors.Is(err, usecase.ErrContactNotVerified) {
+			h.respondError(c, http.StatusConflict, "CONTACT_NOT_VERIFIED", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrDriverSuspended) {
+			h.respondError(c, http.StatusConflict, "DRIVER_SUSPENDED", err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrLicenseExpired) {
+			h.respondError(c, http.StatusConflict, "LICENSE_EXPIRED", err.Error())
+			return
+		}
+		if isForbiddenError(err) {
+			h.respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to update driver")
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// SetSuspension handles PUT /drivers/:id/suspension
+// @Summary Suspend or reinstate a driver
+// @Description Suspended drivers are taken off shift, cannot go on shift again and are left out of nearby searches. Webhook subscribers receive a driver.suspended event.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param suspension body usecase.SetSuspensionRequest true "Suspension"
+// @Success 200 {object} domain.Driver "Driver suspension updated"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"suspended is required"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet" example({"error":{"code":"FORBIDDEN","message":"driver does not belong to your fleet"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/{id}/suspension [put]
+func (h *DriverHandler) SetSuspension(c *gin.Context) {
+	var req usecase.SetSuspensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "suspended is required")
+		return
+	}
+
+	var driver *domain.Driver
+	driver, err := h.useCase.SetSuspension(c.Request.Context(), c.Param("id"), *req.Suspended, req.Reason)
+	if err != nil {
+		if err.Error() == "driver not found" {
+			h.respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+			return
+		}
+		if isForbiddenError(err) {
+			h.respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to update driver")
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// DeleteDriver handles DELETE /drivers/:id
+// @Summary Delete driver
+// @Description Remove a driver; used to roll back a failed onboarding. Personal data is erased and a tombstone is kept so delta syncs report the deletion.
+// @Tags drivers
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 204 "Driver deleted"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet" example({"error":{"code":"FORBIDDEN","message":"driver does not belong to your fleet"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to delete driver"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/{id} [delete]
+func (h *DriverHandler) DeleteDriver(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.useCase.DeleteDriver(c.Request.Context(), id); err != nil {
+		if err.Error() == "driver not found" {
+			h.respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+			return
+		}
+		if isForbiddenError(err) {
+			h.respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to delete driver")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ErrorResponse represents an error response
 type ErrorResponse struct {
 	Error struct {
@@ -223,10 +643,29 @@ type ErrorResponse struct {
 }
 
 func (h *DriverHandler) respondError(c *gin.Context, status int, code, message string) {
-	var errResp ErrorResponse
-	errResp.Error.Code = code
-	errResp.Error.Message = message
-	c.JSON(status, errResp)
+	respondError(c, status, code, message)
+}
+
+// respondError sends an error response in the format negotiated with the client
+func respondError(c *gin.Context, status int, code, message string) {
+	problem.Respond(c, status, code, message)
+}
+
+// storeRetryAfter is advertised when MongoDB is failing over; elections
+// usually settle within a few seconds
+const storeRetryAfter = 5 * time.Second
+
+// respondInternalError answers 503 with Retry-After while the database is
+// unavailable and logs and answers 500 for any other unexpected error
+func respondInternalError(c *gin.Context, logger *zap.Logger, err error, message string) {
+	if errors.Is(err, domain.ErrStoreUnavailable) {
+		logger.Warn(message, zap.Error(err))
+		c.Header("Retry-After", strconv.Itoa(int(storeRetryAfter/time.Second)))
+		respondError(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "database is temporarily unavailable, retry later")
+		return
+	}
+	logger.Error(message, zap.Error(err))
+	respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", message)
 }
 
 func isValidationError(err error) bool {
@@ -238,5 +677,27 @@ func isValidationError(err error) bool {
 		err.Error() == "latitude must be between -90 and 90" ||
 		err.Error() == "longitude must be between -180 and 180" ||
 		err.Error() == "driver not found" ||
-		err.Error() == "invalid driver ID")
+		err.Error() == "invalid driver ID" ||
+		errors.As(err, new(*plate.ValidationError)) ||
+		errors.As(err, new(*rules.ValidationError)) ||
+		errors.Is(err, usecase.ErrInvalidPhone) ||
+		errors.Is(err, usecase.ErrInvalidEmail) ||
+		errors.Is(err, usecase.ErrInvalidLicense) ||
+		errors.Is(err, usecase.ErrLicenseExpired) ||
+		errors.Is(err, usecase.ErrInvalidTags) ||
+		errors.Is(err, usecase.ErrInvalidAttributes) ||
+		errors.Is(err, usecase.ErrFleetNotFound) ||
+		errors.Is(err, usecase.ErrUnknownLocation))
+}
+
+// isForbiddenError reports whether the caller may not act on the driver
+func isForbiddenError(err error) bool {
+	return errors.Is(err, usecase.ErrDriverNotInFleet) || errors.Is(err, usecase.ErrFleetScope) ||
+		errors.Is(err, usecase.ErrDriverNotOwned)
+}
+
+// isConflictError reports whether the error is caused by a uniqueness conflict
+func isConflictError(err error) bool {
+	return errors.Is(err, usecase.ErrPhoneTaken) || errors.Is(err, usecase.ErrEmailTaken) ||
+		errors.Is(err, usecase.ErrPlateTaken)
 }
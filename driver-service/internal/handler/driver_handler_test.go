@@ -5,12 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/bitaksi/driver-service/internal/domain"
 	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/bitaksi/driver-service/pkg/plate"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -23,6 +26,12 @@ type mockDriverUseCase struct {
 	getDriverFunc         func(ctx context.Context, id string) (*domain.Driver, error)
 	listDriversFunc       func(ctx context.Context, page, pageSize int) (*usecase.ListDriversResponse, error)
 	findNearbyDriversFunc func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error)
+	findAlongRouteFunc    func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error)
+	setAvailabilityFunc   func(ctx context.Context, id string, available bool) (*domain.Driver, error)
+	setSuspensionFunc     func(ctx context.Context, id string, suspended bool, reason string) (*domain.Driver, error)
+	deleteDriverFunc      func(ctx context.Context, id string) error
+	lastFilter            domain.DriverFilter
+	lastNearbyPage        domain.NearbyPage
 }
 
 func (m *mockDriverUseCase) CreateDriver(ctx context.Context, req *usecase.CreateDriverRequest) (*domain.Driver, error) {
@@ -46,20 +55,52 @@ func (m *mockDriverUseCase) GetDriver(ctx context.Context, id string) (*domain.D
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockDriverUseCase) ListDrivers(ctx context.Context, page, pageSize int) (*usecase.ListDriversResponse, error) {
+func (m *mockDriverUseCase) ListDrivers(ctx context.Context, filter domain.DriverFilter, page, pageSize int) (*usecase.ListDriversResponse, error) {
+	m.lastFilter = filter
 	if m.listDriversFunc != nil {
 		return m.listDriversFunc(ctx, page, pageSize)
 	}
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockDriverUseCase) FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+func (m *mockDriverUseCase) FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*usecase.NearbyDriverResponse, error) {
+	m.lastFilter = filter
+	m.lastNearbyPage = page
 	if m.findNearbyDriversFunc != nil {
 		return m.findNearbyDriversFunc(ctx, lat, lon, taxiType)
 	}
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockDriverUseCase) FindDriversAlongRoute(ctx context.Context, waypoints []domain.Location, widthKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter) ([]*usecase.RouteDriverResponse, error) {
+	m.lastFilter = filter
+	if m.findAlongRouteFunc != nil {
+		return m.findAlongRouteFunc(ctx, waypoints, widthKm)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDriverUseCase) SetAvailability(ctx context.Context, id string, available bool) (*domain.Driver, error) {
+	if m.setAvailabilityFunc != nil {
+		return m.setAvailabilityFunc(ctx, id, available)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDriverUseCase) SetSuspension(ctx context.Context, id string, suspended bool, reason string) (*domain.Driver, error) {
+	if m.setSuspensionFunc != nil {
+		return m.setSuspensionFunc(ctx, id, suspended, reason)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDriverUseCase) DeleteDriver(ctx context.Context, id string) error {
+	if m.deleteDriverFunc != nil {
+		return m.deleteDriverFunc(ctx, id)
+	}
+	return errors.New("not implemented")
+}
+
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -143,6 +184,61 @@ func TestDriverHandler_CreateDriver(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "INTERNAL_ERROR",
 		},
+		{
+			name: "blacklisted driver",
+			requestBody: map[string]interface{}{
+				"firstName": "Ahmet",
+				"lastName":  "Demir",
+				"plate":     "34ABC123",
+				"taksiType": "sari",
+				"carBrand":  "Toyota",
+				"carModel":  "Corolla",
+				"lat":       41.0431,
+				"lon":       29.0099,
+			},
+			mockFunc: func(ctx context.Context, req *usecase.CreateDriverRequest) (*domain.Driver, error) {
+				return nil, usecase.ErrDriverBlacklisted
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "BLACKLISTED",
+		},
+		{
+			name: "blacklist unavailable",
+			requestBody: map[string]interface{}{
+				"firstName": "Ahmet",
+				"lastName":  "Demir",
+				"plate":     "34ABC123",
+				"taksiType": "sari",
+				"carBrand":  "Toyota",
+				"carModel":  "Corolla",
+				"lat":       41.0431,
+				"lon":       29.0099,
+			},
+			mockFunc: func(ctx context.Context, req *usecase.CreateDriverRequest) (*domain.Driver, error) {
+				return nil, usecase.ErrBlacklistUnavailable
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedError:  "BLACKLIST_UNAVAILABLE",
+		},
+		{
+			name: "fleet quota used up",
+			requestBody: map[string]interface{}{
+				"firstName": "Ahmet",
+				"lastName":  "Demir",
+				"plate":     "34ABC123",
+				"taksiType": "sari",
+				"carBrand":  "Toyota",
+				"carModel":  "Corolla",
+				"lat":       41.0431,
+				"lon":       29.0099,
+				"fleetId":   "6570a1f2c3d4e5f6a7b8c9d0",
+			},
+			mockFunc: func(ctx context.Context, req *usecase.CreateDriverRequest) (*domain.Driver, error) {
+				return nil, fmt.Errorf("%w of %d drivers", usecase.ErrQuotaExceeded, 50)
+			},
+			expectedStatus: http.StatusPaymentRequired,
+			expectedError:  "QUOTA_EXCEEDED",
+		},
 		{
 			name:           "invalid JSON",
 			requestBody:    "invalid json",
@@ -338,6 +434,15 @@ func TestDriverHandler_GetDriver(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedError:  "INTERNAL_ERROR",
 		},
+		{
+			name: "database failing over",
+			id:   "test-id",
+			mockFunc: func(ctx context.Context, id string) (*domain.Driver, error) {
+				return nil, fmt.Errorf("%w: get driver: no primary", domain.ErrStoreUnavailable)
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedError:  "SERVICE_UNAVAILABLE",
+		},
 	}
 
 	for _, tt := range tests {
@@ -356,6 +461,9 @@ func TestDriverHandler_GetDriver(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusServiceUnavailable {
+				assert.Equal(t, "5", w.Header().Get("Retry-After"))
+			}
 			if tt.expectedError != "" && w.Body.Len() > 0 {
 				var response map[string]interface{}
 				if err := json.Unmarshal(w.Body.Bytes(), &response); err == nil {
@@ -368,6 +476,49 @@ func TestDriverHandler_GetDriver(t *testing.T) {
 	}
 }
 
+func TestRegisterHeadAndOptions(t *testing.T) {
+	mockUC := &mockDriverUseCase{
+		getDriverFunc: func(ctx context.Context, id string) (*domain.Driver, error) {
+			if id == "missing" {
+				return nil, errors.New("driver not found")
+			}
+			return &domain.Driver{ID: id, FirstName: "Ahmet"}, nil
+		},
+	}
+	handler := NewDriverHandler(mockUC, zap.NewNop())
+
+	router := setupRouter()
+	drivers := router.Group("/drivers")
+	drivers.POST("", handler.CreateDriver)
+	drivers.GET("/:id", handler.GetDriver)
+	drivers.PUT("/:id", handler.UpdateDriver)
+	drivers.DELETE("/:id", handler.DeleteDriver)
+	RegisterHeadAndOptions(router, drivers)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodHead, "/drivers/d1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	assert.Zero(t, w.Body.Len())
+
+	w = serve(http.MethodHead, "/drivers/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Zero(t, w.Body.Len())
+
+	w = serve(http.MethodOptions, "/drivers/d1")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, PUT, DELETE, OPTIONS", w.Header().Get("Allow"))
+
+	w = serve(http.MethodOptions, "/drivers")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "POST, OPTIONS", w.Header().Get("Allow"))
+}
+
 func TestDriverHandler_ListDrivers(t *testing.T) {
 	logger := zap.NewNop()
 
@@ -496,6 +647,36 @@ func TestDriverHandler_FindNearbyDrivers(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "VALIDATION_ERROR",
 		},
+		{
+			name:        "geohash instead of coordinates",
+			queryParams: "?geohash=sxk9kp",
+			mockFunc: func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+				// The center of the cell is searched around
+				if math.Abs(lat-41.0422) > 0.0001 || math.Abs(lon-29.0094) > 0.0001 {
+					return nil, errors.New("database error")
+				}
+				return []*usecase.NearbyDriverResponse{}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid geohash",
+			queryParams:    "?geohash=sxk9ka",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:           "geohash and coordinates",
+			queryParams:    "?geohash=sxk9kp&lat=41.0431&lon=29.0099",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:           "unknown units",
+			queryParams:    "?lat=41.0431&lon=29.0099&units=ft",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
 		{
 			name:           "invalid taxi type",
 			queryParams:    "?lat=41.0431&lon=29.0099&taksiType=invalid",
@@ -550,6 +731,211 @@ func TestDriverHandler_FindNearbyDrivers(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_FindDriversAlongRoute(t *testing.T) {
+	logger := zap.NewNop()
+	route := `"waypoints":[{"lat":41.0431,"lon":29.0099},{"lat":41.1086,"lon":29.0436}]`
+
+	tests := []struct {
+		name           string
+		body           string
+		mockFunc       func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "successful search",
+			body: `{` + route + `,"widthKm":1}`,
+			mockFunc: func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error) {
+				if len(waypoints) != 2 || widthKm != 1 {
+					return nil, errors.New("unexpected route")
+				}
+				return []*usecase.RouteDriverResponse{}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid body",
+			body:           `{"waypoints":"nope"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:           "invalid taxi type",
+			body:           `{` + route + `,"taksiType":"invalid"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "too few waypoints",
+			body: `{"waypoints":[{"lat":41.0431,"lon":29.0099}]}`,
+			mockFunc: func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error) {
+				return nil, usecase.ErrRouteWaypoints
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "corridor too wide",
+			body: `{` + route + `,"widthKm":5}`,
+			mockFunc: func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error) {
+				return nil, usecase.ErrInvalidCorridorWidth
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name: "internal error",
+			body: `{` + route + `}`,
+			mockFunc: func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "INTERNAL_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := &mockDriverUseCase{
+				findAlongRouteFunc: tt.mockFunc,
+			}
+			handler := NewDriverHandler(mockUC, logger)
+
+			router := setupRouter()
+			router.POST("/drivers/nearby/route", handler.FindDriversAlongRoute)
+
+			req := httptest.NewRequest("POST", "/drivers/nearby/route", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response)) {
+					errorObj, _ := response["error"].(map[string]interface{})
+					assert.Equal(t, tt.expectedError, errorObj["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestDriverHandler_NearbyUnits(t *testing.T) {
+	mockUC := &mockDriverUseCase{
+		findNearbyDriversFunc: func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+			return []*usecase.NearbyDriverResponse{{ID: "507f1f77bcf86cd799439011", DistanceKm: 1.2345}}, nil
+		},
+		findAlongRouteFunc: func(ctx context.Context, waypoints []domain.Location, widthKm float64) ([]*usecase.RouteDriverResponse, error) {
+			return []*usecase.RouteDriverResponse{{ID: "507f1f77bcf86cd799439011", DistanceKm: 0.2, RouteKm: 3.21869}}, nil
+		},
+	}
+	handler := NewDriverHandler(mockUC, zap.NewNop())
+	router := setupRouter()
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+	router.POST("/drivers/nearby/route", handler.FindDriversAlongRoute)
+
+	t.Run("nearby in meters", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&units=m", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "m", w.Header().Get(DistanceUnitHeader))
+		assert.Contains(t, w.Body.String(), `"distanceKm":1.2345,"distance":1235`)
+	})
+
+	t.Run("nearby without units", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "km", w.Header().Get(DistanceUnitHeader))
+		assert.NotContains(t, w.Body.String(), `"distance"`)
+	})
+
+	t.Run("route in miles", func(t *testing.T) {
+		body := `{"waypoints":[{"lat":41.0431,"lon":29.0099},{"lat":41.1086,"lon":29.0436}]}`
+		req := httptest.NewRequest("POST", "/drivers/nearby/route?units=mi", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "mi", w.Header().Get(DistanceUnitHeader))
+		assert.Contains(t, w.Body.String(), `"distance":0.1243,"routeDistance":2`)
+	})
+}
+
+func TestDriverHandler_SetAvailability(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockFunc       func(ctx context.Context, id string, available bool) (*domain.Driver, error)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:        "go on shift",
+			requestBody: map[string]interface{}{"available": true},
+			mockFunc: func(ctx context.Context, id string, available bool) (*domain.Driver, error) {
+				return &domain.Driver{ID: id, Available: available}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing available",
+			requestBody:    map[string]interface{}{},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:        "contact not verified",
+			requestBody: map[string]interface{}{"available": true},
+			mockFunc: func(ctx context.Context, id string, available bool) (*domain.Driver, error) {
+				return nil, usecase.ErrContactNotVerified
+			},
+			expectedStatus: http.StatusConflict,
+			expectedError:  "CONTACT_NOT_VERIFIED",
+		},
+		{
+			name:        "driver not found",
+			requestBody: map[string]interface{}{"available": false},
+			mockFunc: func(ctx context.Context, id string, available bool) (*domain.Driver, error) {
+				return nil, errors.New("driver not found")
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := &mockDriverUseCase{setAvailabilityFunc: tt.mockFunc}
+			handler := NewDriverHandler(mockUC, logger)
+
+			router := setupRouter()
+			router.PUT("/drivers/:id/availability", handler.SetAvailability)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest("PUT", "/drivers/test-id/availability", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Equal(t, tt.expectedError, response["error"].(map[string]interface{})["code"])
+			}
+		})
+	}
+}
+
 func TestDriverHandler_respondError(t *testing.T) {
 	logger := zap.NewNop()
 	handler := NewDriverHandler(&mockDriverUseCase{}, logger)
@@ -612,6 +998,11 @@ func TestIsValidationError(t *testing.T) {
 			err:      errors.New("longitude must be between -180 and 180"),
 			expected: true,
 		},
+		{
+			name:     "validation error - plate format",
+			err:      plate.Validate("82ABC123"),
+			expected: true,
+		},
 		{
 			name:     "not validation error",
 			err:      errors.New("database error"),
@@ -636,3 +1027,91 @@ func TestIsValidationError(t *testing.T) {
 		})
 	}
 }
+
+func TestDriverHandler_FleetFilter(t *testing.T) {
+	mockUC := &mockDriverUseCase{
+		listDriversFunc: func(ctx context.Context, page, pageSize int) (*usecase.ListDriversResponse, error) {
+			return &usecase.ListDriversResponse{Drivers: []*domain.Driver{}}, nil
+		},
+		findNearbyDriversFunc: func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+			return []*usecase.NearbyDriverResponse{}, nil
+		},
+	}
+	handler := NewDriverHandler(mockUC, zap.NewNop())
+
+	router := setupRouter()
+	router.GET("/drivers", handler.ListDrivers)
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers?fleetId=fleet-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "fleet-1", mockUC.lastFilter.FleetID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&fleetId=fleet-2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "fleet-2", mockUC.lastFilter.FleetID)
+}
+
+func TestDriverHandler_NearbyPage(t *testing.T) {
+	mockUC := &mockDriverUseCase{
+		findNearbyDriversFunc: func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+			return []*usecase.NearbyDriverResponse{}, nil
+		},
+	}
+	handler := NewDriverHandler(mockUC, zap.NewNop())
+
+	router := setupRouter()
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&limit=10&offset=20", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.NearbyPage{Limit: 10, Offset: 20}, mockUC.lastNearbyPage)
+
+	// Left to the use case to default
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.NearbyPage{}, mockUC.lastNearbyPage)
+
+	for _, query := range []string{"limit=0", "limit=-1", "limit=ten", "offset=-1", "offset=1.5"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestDriverHandler_TagFilter(t *testing.T) {
+	mockUC := &mockDriverUseCase{
+		listDriversFunc: func(ctx context.Context, page, pageSize int) (*usecase.ListDriversResponse, error) {
+			return &usecase.ListDriversResponse{Drivers: []*domain.Driver{}}, nil
+		},
+		findNearbyDriversFunc: func(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*usecase.NearbyDriverResponse, error) {
+			return []*usecase.NearbyDriverResponse{}, nil
+		},
+	}
+	handler := NewDriverHandler(mockUC, zap.NewNop())
+
+	router := setupRouter()
+	router.GET("/drivers", handler.ListDrivers)
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers?tags=Pet-Friendly,wheelchair-accessible&tags=quiet&attributes=language:en,child-seat", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"pet-friendly", "wheelchair-accessible", "quiet"}, mockUC.lastFilter.Tags)
+	assert.Equal(t, map[string]string{"language": "en", "child-seat": ""}, mockUC.lastFilter.Attributes)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&tags=pet-friendly", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"pet-friendly"}, mockUC.lastFilter.Tags)
+
+	for _, query := range []string{"/drivers?tags=pet_friendly", "/drivers/nearby?lat=41.0431&lon=29.0099&attributes=$where:1"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EarningsHandler handles HTTP requests for driver earnings and payouts
+type EarningsHandler struct {
+	useCase usecase.EarningsUseCase
+	logger  *zap.Logger
+}
+
+// NewEarningsHandler creates a new earnings handler
+func NewEarningsHandler(useCase usecase.EarningsUseCase, logger *zap.Logger) *EarningsHandler {
+	return &EarningsHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetDriverEarnings handles GET /drivers/:id/earnings
+// @Summary Get driver earnings
+// @Description Sum a driver's trip earnings, commission and adjustments per day or per week (starting Monday) in the configured time zone. The range defaults to the last 30 days (daily) or 12 weeks (weekly) and cannot exceed 366 days; days without earnings are left out.
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param period query string false "Bucket size" Enums(daily, weekly) default(daily)
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Success 200 {object} usecase.EarningsSummary "Driver earnings"
+// @Failure 400 {object} ErrorResponse "Invalid period or range" example({"error":{"code":"VALIDATION_ERROR","message":"period must be daily or weekly"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get driver earnings"}})
+// @Router /drivers/{id}/earnings [get]
+func (h *EarningsHandler) GetDriverEarnings(c *gin.Context) {
+	from, err := parseRangeParam(c.Query("from"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	to, err := parseRangeParam(c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+
+	summary, err := h.useCase.GetDriverEarnings(c.Request.Context(), c.Param("id"), domain.EarningsPeriod(c.Query("period")), from, to)
+	if err != nil {
+		h.handleError(c, err, "failed to get driver earnings")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// CreateAdjustment handles POST /admin/earnings/adjustments
+// @Summary Adjust driver earnings
+// @Description Credit or deduct an amount from a driver's earnings, e.g. a toll reimbursement or a refunded fare. Adjustments are never edited; each one keeps its reason and the caller that made it and is included in the next payout.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param adjustment body usecase.CreateAdjustmentRequest true "Adjustment"
+// @Success 201 {object} domain.Earning "Adjustment recorded"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"reason is required"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create adjustment"}})
+// @Router /admin/earnings/adjustments [post]
+func (h *EarningsHandler) CreateAdjustment(c *gin.Context) {
+	var req usecase.CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	adjustment, err := h.useCase.CreateAdjustment(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create adjustment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, adjustment)
+}
+
+// ListAdjustments handles GET /admin/earnings/adjustments
+// @Summary List earning adjustments
+// @Description Audit trail of manual earning adjustments, newest first.
+// @Tags admin
+// @Produce json
+// @Param driverId query string false "Only list adjustments of this driver" example(507f1f77bcf86cd799439011)
+// @Param limit query int false "Maximum adjustments to return (max 200)" default(50)
+// @Success 200 {array} domain.Earning "Adjustments"
+// @Failure 400 {object} ErrorResponse "Invalid limit" example({"error":{"code":"VALIDATION_ERROR","message":"limit must be a positive integer"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list adjustments"}})
+// @Router /admin/earnings/adjustments [get]
+func (h *EarningsHandler) ListAdjustments(c *gin.Context) {
+	limit, ok := limitParam(c)
+	if !ok {
+		return
+	}
+
+	adjustments, err := h.useCase.ListAdjustments(c.Request.Context(), c.Query("driverId"), limit)
+	if err != nil {
+		h.handleError(c, err, "failed to list adjustments")
+		return
+	}
+
+	c.JSON(http.StatusOK, adjustments)
+}
+
+// CreatePayout handles POST /admin/payouts
+// @Summary Create a payout batch
+// @Description Batch every unpaid trip earning and adjustment earned in the range into a payout with one line per driver. Entries are paid out once; running the same range again only picks up entries recorded since. The range ends at the start of the current day by default.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param payout body usecase.CreatePayoutRequest false "Payout range"
+// @Success 201 {object} domain.Payout "Payout created"
+// @Failure 400 {object} ErrorResponse "Invalid range" example({"error":{"code":"VALIDATION_ERROR","message":"to cannot be in the future"}})
+// @Failure 409 {object} ErrorResponse "Nothing to pay" example({"error":{"code":"CONFLICT","message":"no unpaid earnings in range"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create payout"}})
+// @Router /admin/payouts [post]
+func (h *EarningsHandler) CreatePayout(c *gin.Context) {
+	var req usecase.CreatePayoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+	}
+
+	payout, err := h.useCase.CreatePayout(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create payout")
+		return
+	}
+
+	c.JSON(http.StatusCreated, payout)
+}
+
+// ListPayouts handles GET /admin/payouts
+// @Summary List payouts
+// @Description Payout batches, newest first, without their lines.
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum payouts to return (max 200)" default(50)
+// @Success 200 {array} domain.Payout "Payouts"
+// @Failure 400 {object} ErrorResponse "Invalid limit" example({"error":{"code":"VALIDATION_ERROR","message":"limit must be a positive integer"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list payouts"}})
+// @Router /admin/payouts [get]
+func (h *EarningsHandler) ListPayouts(c *gin.Context) {
+	limit, ok := limitParam(c)
+	if !ok {
+		return
+	}
+
+	payouts, err := h.useCase.ListPayouts(c.Request.Context(), limit)
+	if err != nil {
+		h.handleError(c, err, "failed to list payouts")
+		return
+	}
+
+	c.JSON(http.StatusOK, payouts)
+}
+
+// GetPayout handles GET /admin/payouts/:id
+// @Summary Get a payout
+// @Description Get a payout batch with what it owes each driver.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Payout ID" example("6573b2f3c4d5e6f7a8b9c0d1")
+// @Success 200 {object} domain.Payout "Payout"
+// @Failure 404 {object} ErrorResponse "Payout not found" example({"error":{"code":"NOT_FOUND","message":"payout not found"}})
+// @Router /admin/payouts/{id} [get]
+func (h *EarningsHandler) GetPayout(c *gin.Context) {
+	payout, err := h.useCase.GetPayout(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get payout")
+		return
+	}
+
+	c.JSON(http.StatusOK, payout)
+}
+
+// ExportPayout handles GET /admin/payouts/:id/export
+// @Summary Export a payout as CSV
+// @Description Download a payout batch for finance as CSV with one row per driver: payoutId, driverId, trips, gross, commission, adjustments, net and currency.
+// @Tags admin
+// @Produce text/csv
+// @Param id path string true "Payout ID" example("6573b2f3c4d5e6f7a8b9c0d1")
+// @Success 200 {string} string "Payout CSV"
+// @Failure 404 {object} ErrorResponse "Payout not found" example({"error":{"code":"NOT_FOUND","message":"payout not found"}})
+// @Router /admin/payouts/{id}/export [get]
+func (h *EarningsHandler) ExportPayout(c *gin.Context) {
+	payout, err := h.useCase.GetPayout(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get payout")
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="payout-`+payout.ID+`.csv"`)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := usecase.WritePayoutCSV(c.Writer, payout); err != nil {
+		h.logger.Error("failed to write payout export", zap.Error(err), zap.String("id", payout.ID))
+	}
+}
+
+func (h *EarningsHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrPayoutNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrInvalidEarningsPeriod),
+		errors.Is(err, usecase.ErrInvalidStatsRange),
+		errors.Is(err, usecase.ErrEarningsRangeTooLong),
+		errors.Is(err, usecase.ErrInvalidAdjustmentAmount),
+		errors.Is(err, usecase.ErrAdjustmentReasonRequired),
+		errors.Is(err, usecase.ErrPayoutInFuture):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrNothingToPay):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
+
+// limitParam parses the optional limit query parameter; it responds with 400
+// and returns false when the value is not a positive integer
+func limitParam(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "limit must be a positive integer")
+		return 0, false
+	}
+	return limit, true
+}
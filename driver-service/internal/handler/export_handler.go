@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/jobrunner"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportHandler handles HTTP requests for background driver exports
+type ExportHandler struct {
+	useCase usecase.ExportUseCase
+	logger  *zap.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(useCase usecase.ExportUseCase, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// StartExport handles POST /exports
+// @Summary Start a driver export
+// @Description Export every driver, or one fleet's, in the background; fleet admins always export their own fleet. The export is a snapshot of the drivers that existed when it started; drivers updated since are flagged with changedDuringExport. Poll GET /exports/{id} until status is completed, then download the file from GET /exports/{id}/download. Exports and their files are kept for JOB_RETENTION_HOURS after they finish, on the instance that ran them.
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param export body usecase.ExportRequest false "Format and fleet; an empty body exports every driver as NDJSON"
+// @Param X-Admin-Actor header string false "Who started the export, for the audit log"
+// @Success 202 {object} jobrunner.Job "Export queued"
+// @Header 202 {string} Location "URL of the export status"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"format must be ndjson or json"}})
+// @Failure 429 {object} ErrorResponse "Too many jobs waiting" example({"error":{"code":"TOO_MANY_JOBS","message":"too many jobs are waiting, retry later"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to start export"}})
+// @Router /exports [post]
+func (h *ExportHandler) StartExport(c *gin.Context) {
+	var req usecase.ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	var job *jobrunner.Job
+	job, err := h.useCase.StartExport(c.Request.Context(), &req, c.GetHeader("X-Admin-Actor"))
+	if err != nil {
+		h.handleError(c, err, "failed to start export")
+		return
+	}
+
+	c.Header("Location", "/api/v1/exports/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetExport handles GET /exports/:id
+// @Summary Get a driver export
+// @Description Status and progress of an export: processed against total drivers, and once completed the file name, type and size to download.
+// @Tags exports
+// @Produce json
+// @Param id path string true "Export ID" example("job-9f86d081884c7d65")
+// @Success 200 {object} jobrunner.Job "Export"
+// @Failure 404 {object} ErrorResponse "Export not found or expired" example({"error":{"code":"NOT_FOUND","message":"export not found"}})
+// @Router /exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	job, err := h.useCase.GetExport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get export")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadExport handles GET /exports/:id/download
+// @Summary Download a driver export
+// @Description The exported drivers, as NDJSON (one driver per line) or a JSON array.
+// @Tags exports
+// @Produce json
+// @Produce application/x-ndjson
+// @Param id path string true "Export ID" example("job-9f86d081884c7d65")
+// @Success 200 {file} file "Exported drivers"
+// @Failure 404 {object} ErrorResponse "Export not found or expired" example({"error":{"code":"NOT_FOUND","message":"export not found"}})
+// @Failure 409 {object} ErrorResponse "Export still running or failed" example({"error":{"code":"EXPORT_NOT_READY","message":"export has not completed"}})
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	f, job, err := h.useCase.OpenExport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to download export")
+		return
+	}
+	defer f.Close()
+
+	c.Header("Content-Disposition", `attachment; filename="`+job.Result.FileName+`"`)
+	c.Header("Content-Length", strconv.FormatInt(job.Result.Size, 10))
+	c.Header("Content-Type", job.Result.ContentType)
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, f); err != nil {
+		h.logger.Warn("export download interrupted", zap.Error(err), zap.String("id", job.ID))
+	}
+}
+
+func (h *ExportHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidExportFormat):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrExportNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrExportNotReady):
+		respondError(c, http.StatusConflict, "EXPORT_NOT_READY", err.Error())
+	case errors.Is(err, usecase.ErrTooManyJobs):
+		respondError(c, http.StatusTooManyRequests, "TOO_MANY_JOBS", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FailoverStatsSource reports MongoDB failover metrics
+type FailoverStatsSource interface {
+	Stats() domain.FailoverStats
+}
+
+// FailoverHandler exposes how the service weathered MongoDB failovers
+type FailoverHandler struct {
+	source FailoverStatsSource
+	logger *zap.Logger
+}
+
+// NewFailoverHandler creates a new failover handler
+func NewFailoverHandler(source FailoverStatsSource, logger *zap.Logger) *FailoverHandler {
+	return &FailoverHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetFailoverStats handles GET /admin/failover
+// @Summary Report MongoDB failover metrics
+// @Description Current replica-set primary, observed primary changes, and how many operations were retried, recovered or answered with 503 since the service started
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.FailoverStats "Failover metrics"
+// @Router /admin/failover [get]
+func (h *FailoverHandler) GetFailoverStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.source.Stats())
+}
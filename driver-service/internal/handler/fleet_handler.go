@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FleetHandler handles HTTP requests for fleets and fleet membership
+type FleetHandler struct {
+	useCase usecase.FleetUseCase
+	logger  *zap.Logger
+}
+
+// NewFleetHandler creates a new fleet handler
+func NewFleetHandler(useCase usecase.FleetUseCase, logger *zap.Logger) *FleetHandler {
+	return &FleetHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// CreateFleet handles POST /fleets
+// @Summary Create a fleet
+// @Description Create a fleet (taxi company) that drivers can be assigned to. Fleet names are unique.
+// @Tags fleets
+// @Accept json
+// @Produce json
+// @Param fleet body usecase.CreateFleetRequest true "Fleet information"
+// @Success 201 {object} domain.Fleet "Fleet created"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"fleet name is required"}})
+// @Failure 409 {object} ErrorResponse "Name taken" example({"error":{"code":"CONFLICT","message":"fleet name already exists"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create fleet"}})
+// @Router /fleets [post]
+func (h *FleetHandler) CreateFleet(c *gin.Context) {
+	var req usecase.CreateFleetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	var fleet *domain.Fleet
+	fleet, err := h.useCase.CreateFleet(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create fleet")
+		return
+	}
+
+	c.JSON(http.StatusCreated, fleet)
+}
+
+// GetFleet handles GET /fleets/:id
+// @Summary Get a fleet
+// @Description Get a fleet by ID
+// @Tags fleets
+// @Produce json
+// @Param id path string true "Fleet ID" example("6570a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.Fleet "Fleet"
+// @Failure 404 {object} ErrorResponse "Fleet not found" example({"error":{"code":"NOT_FOUND","message":"fleet not found"}})
+// @Router /fleets/{id} [get]
+func (h *FleetHandler) GetFleet(c *gin.Context) {
+	fleet, err := h.useCase.GetFleet(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get fleet")
+		return
+	}
+
+	c.JSON(http.StatusOK, fleet)
+}
+
+// ListFleets handles GET /fleets
+// @Summary List fleets
+// @Description List all fleets ordered by name
+// @Tags fleets
+// @Produce json
+// @Success 200 {array} domain.Fleet "Fleets"
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list fleets"}})
+// @Router /fleets [get]
+func (h *FleetHandler) ListFleets(c *gin.Context) {
+	fleets, err := h.useCase.ListFleets(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "failed to list fleets")
+		return
+	}
+
+	c.JSON(http.StatusOK, fleets)
+}
+
+// AssignDriver handles PUT /drivers/:id/fleet
+// @Summary Assign a driver to a fleet
+// @Description Move a driver into a fleet. An empty fleetId removes the driver from its fleet. A fleet that has used up its driver quota takes no more drivers.
+// @Tags fleets
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param assignment body usecase.AssignFleetRequest true "Fleet assignment"
+// @Success 200 {object} domain.Driver "Updated driver"
+// @Failure 400 {object} ErrorResponse "Unknown fleet" example({"error":{"code":"VALIDATION_ERROR","message":"fleet not found"}})
+// @Failure 402 {object} ErrorResponse "Fleet has reached its driver quota" example({"error":{"code":"QUOTA_EXCEEDED","message":"fleet has reached its driver quota of 50 drivers"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
+// @Router /drivers/{id}/fleet [put]
+func (h *FleetHandler) AssignDriver(c *gin.Context) {
+	var req usecase.AssignFleetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	driver, err := h.useCase.AssignDriver(c.Request.Context(), c.Param("id"), req.FleetID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrFleetNotFound) {
+			// The fleet is part of the request body, not the resource path
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		h.handleError(c, err, "failed to update driver")
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// GetQuota handles GET /admin/tenants/:id/quota
+// @Summary Tenant driver quota
+// @Description How many drivers a tenant (fleet) has against the quota it pays for. Creating a driver in, or moving one into, a fleet that has used up its quota is refused with QUOTA_EXCEEDED. Quotas are soft: concurrent creations may overshoot by the requests in flight.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Fleet ID" example("6570a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.DriverQuota "Quota usage"
+// @Failure 404 {object} ErrorResponse "Fleet not found" example({"error":{"code":"NOT_FOUND","message":"fleet not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get quota"}})
+// @Router /admin/tenants/{id}/quota [get]
+func (h *FleetHandler) GetQuota(c *gin.Context) {
+	quota, err := h.useCase.GetQuota(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get quota")
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// handleError maps fleet use case errors to HTTP responses
+func (h *FleetHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrFleetNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrFleetNameRequired):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrFleetNameTaken):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	case errors.Is(err, usecase.ErrQuotaExceeded):
+		respondError(c, http.StatusPaymentRequired, "QUOTA_EXCEEDED", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
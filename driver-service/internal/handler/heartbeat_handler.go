@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HeartbeatHandler handles HTTP requests for driver heartbeats and liveness
+type HeartbeatHandler struct {
+	useCase usecase.HeartbeatUseCase
+	logger  *zap.Logger
+}
+
+// NewHeartbeatHandler creates a new heartbeat handler
+func NewHeartbeatHandler(useCase usecase.HeartbeatUseCase, logger *zap.Logger) *HeartbeatHandler {
+	return &HeartbeatHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// Heartbeat handles POST /drivers/:id/heartbeat
+// @Summary Record a driver heartbeat
+// @Description Mark the driver as seen now. Driver apps call this periodically; it takes no body and only updates lastSeenAt.
+// @Tags drivers
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 204 "Heartbeat recorded"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to record heartbeat"}})
+// @Router /drivers/{id}/heartbeat [post]
+func (h *HeartbeatHandler) Heartbeat(c *gin.Context) {
+	if err := h.useCase.Heartbeat(c.Request.Context(), c.Param("id")); err != nil {
+		if err.Error() == "driver not found" {
+			respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to record heartbeat")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetOnlineStats handles GET /drivers/stats
+// @Summary Get online driver counts
+// @Description Count drivers that are online (heartbeat within HEARTBEAT_TIMEOUT_SEC) and offline
+// @Tags drivers
+// @Produce json
+// @Param fleetId query string false "Only count drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} domain.OnlineStats "Online and offline counts"
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get online stats"}})
+// @Router /drivers/stats [get]
+func (h *HeartbeatHandler) GetOnlineStats(c *gin.Context) {
+	var stats *domain.OnlineStats
+	stats, err := h.useCase.OnlineStats(c.Request.Context(), driverFilter(c))
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to get online stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
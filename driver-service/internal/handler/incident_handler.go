@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IncidentHandler handles HTTP requests for complaints about drivers
+type IncidentHandler struct {
+	useCase usecase.IncidentUseCase
+	logger  *zap.Logger
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(useCase usecase.IncidentUseCase, logger *zap.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// ReportIncident handles POST /drivers/:id/incidents
+// @Summary Report an incident
+// @Description Report an incident against the driver, optionally linked to a trip the driver drove; riders must link one of their own trips. High and critical incidents reported by operators and fleet admins suspend the driver until they are dismissed; critical ones also revoke the driver's identity verification, which then needs a new identity check. Rider reports are stored open without effects until an investigator confirms their severity.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body usecase.ReportIncidentRequest true "Incident"
+// @Success 201 {object} domain.Incident "Incident reported"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"severity must be low, medium, high or critical"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet, or the trip to another rider"
+// @Failure 404 {object} ErrorResponse "Driver or trip not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to report incident"}})
+// @Router /drivers/{id}/incidents [post]
+func (h *IncidentHandler) ReportIncident(c *gin.Context) {
+	var req usecase.ReportIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	incident, err := h.useCase.ReportIncident(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to report incident")
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// ListDriverIncidents handles GET /drivers/:id/incidents
+// @Summary Driver incidents
+// @Description Incidents reported against the driver, newest first.
+// @Tags incidents
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param status query string false "open, investigating or resolved" example(open)
+// @Param severity query string false "low, medium, high or critical" example(high)
+// @Param category query string false "safety, conduct, vehicle, fare, route or other" example(safety)
+// @Param tripId query string false "Only incidents of this trip" example(6571f1f77bcf86cd79943901)
+// @Param page query int false "Page number" default(1) example(1)
+// @Param pageSize query int false "Page size, at most 100" default(20) example(20)
+// @Success 200 {object} usecase.ListIncidentsResponse "Incidents"
+// @Failure 400 {object} ErrorResponse "Invalid filter" example({"error":{"code":"VALIDATION_ERROR","message":"status must be open, investigating or resolved"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/incidents [get]
+func (h *IncidentHandler) ListDriverIncidents(c *gin.Context) {
+	h.list(c, c.Param("id"))
+}
+
+// ListIncidents handles GET /incidents
+// @Summary Incident queue
+// @Description Incidents matching the filters, newest first. Fleet admins only see incidents reported while the driver was in their fleet.
+// @Tags incidents
+// @Produce json
+// @Param driverId query string false "Only incidents of this driver" example(507f1f77bcf86cd799439011)
+// @Param fleetId query string false "Only incidents of this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param status query string false "open, investigating or resolved" example(open)
+// @Param severity query string false "low, medium, high or critical" example(high)
+// @Param category query string false "safety, conduct, vehicle, fare, route or other" example(safety)
+// @Param tripId query string false "Only incidents of this trip" example(6571f1f77bcf86cd79943901)
+// @Param page query int false "Page number" default(1) example(1)
+// @Param pageSize query int false "Page size, at most 100" default(20) example(20)
+// @Success 200 {object} usecase.ListIncidentsResponse "Incidents"
+// @Failure 400 {object} ErrorResponse "Invalid filter"
+// @Failure 403 {object} ErrorResponse "Riders cannot list incidents, or the driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents [get]
+func (h *IncidentHandler) ListIncidents(c *gin.Context) {
+	h.list(c, c.Query("driverId"))
+}
+
+func (h *IncidentHandler) list(c *gin.Context, driverID string) {
+	filter := domain.IncidentFilter{
+		DriverID: driverID,
+		FleetID:  c.Query("fleetId"),
+		TripID:   c.Query("tripId"),
+		Category: domain.IncidentCategory(c.Query("category")),
+		Severity: domain.IncidentSeverity(c.Query("severity")),
+		Status:   domain.IncidentStatus(c.Query("status")),
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.Query("pageSize"))
+
+	response, err := h.useCase.ListIncidents(c.Request.Context(), filter, page, pageSize)
+	if err != nil {
+		h.handleError(c, err, "failed to list incidents")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetIncident handles GET /incidents/:id
+// @Summary Get an incident
+// @Description Get an incident with its investigation status and outcome.
+// @Tags incidents
+// @Produce json
+// @Param id path string true "Incident ID" example("6575a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.Incident "Incident"
+// @Failure 403 {object} ErrorResponse "Incident of another fleet"
+// @Failure 404 {object} ErrorResponse "Incident not found" example({"error":{"code":"NOT_FOUND","message":"incident not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents/{id} [get]
+func (h *IncidentHandler) GetIncident(c *gin.Context) {
+	incident, err := h.useCase.GetIncident(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// UpdateIncidentStatus handles PUT /incidents/:id/status
+// @Summary Move an incident's investigation on
+// @Description Move an incident from open to investigating or resolved, or from investigating to resolved; resolved incidents cannot change. Moving to investigating with a severity confirms it: a high or critical one then suspends the driver, and a critical one revokes identity verification, if the report did not already. Resolving takes an outcome: upheld keeps a suspension the incident caused, dismissed lifts it unless another unresolved incident holds the driver. Revoked identity verification is not restored.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID" example("6575a1f2c3d4e5f6a7b8c9d0")
+// @Param request body usecase.IncidentStatusRequest true "New status"
+// @Success 200 {object} domain.Incident "Incident updated"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"outcome must be upheld or dismissed, and is only given when resolving"}})
+// @Failure 403 {object} ErrorResponse "Incident of another fleet"
+// @Failure 404 {object} ErrorResponse "Incident not found"
+// @Failure 409 {object} ErrorResponse "Status does not allow the change" example({"error":{"code":"CONFLICT","message":"incident status does not allow this change"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents/{id}/status [put]
+func (h *IncidentHandler) UpdateIncidentStatus(c *gin.Context) {
+	var req usecase.IncidentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	incident, err := h.useCase.UpdateIncidentStatus(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to update incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// handleError maps incident use case errors to HTTP responses
+func (h *IncidentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrIncidentNotFound), errors.Is(err, usecase.ErrTripNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case isForbiddenError(err), errors.Is(err, usecase.ErrTripNotOwned), errors.Is(err, usecase.ErrIncidentReportOnly):
+		respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, usecase.ErrInvalidIncidentCategory), errors.Is(err, usecase.ErrInvalidIncidentSeverity),
+		errors.Is(err, usecase.ErrInvalidIncidentStatus), errors.Is(err, usecase.ErrInvalidIncidentText),
+		errors.Is(err, usecase.ErrIncidentTripRequired), errors.Is(err, usecase.ErrIncidentTripMismatch),
+		errors.Is(err, usecase.ErrIncidentOutcome), errors.Is(err, usecase.ErrIncidentSeverityConfirm):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrIncidentTransition):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
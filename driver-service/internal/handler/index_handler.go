@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IndexHandler handles the operational MongoDB index endpoints
+type IndexHandler struct {
+	useCase usecase.IndexUseCase
+	logger  *zap.Logger
+}
+
+// NewIndexHandler creates a new index handler
+func NewIndexHandler(useCase usecase.IndexUseCase, logger *zap.Logger) *IndexHandler {
+	return &IndexHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetIndexes handles GET /admin/indexes
+// @Summary Report MongoDB indexes
+// @Description Compare the indexes the repositories expect with the ones that exist. Indexes are missing, mismatched (same name, different keys) or unexpected (not declared by any repository).
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.IndexReport "Index report"
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list indexes"}})
+// @Router /admin/indexes [get]
+func (h *IndexHandler) GetIndexes(c *gin.Context) {
+	var report *domain.IndexReport
+	report, err := h.useCase.Report(c.Request.Context())
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to list indexes")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// SyncIndexes handles POST /admin/indexes/sync
+// @Summary Create missing MongoDB indexes
+// @Description Start building the missing indexes in the background and return the job; poll GET /admin/indexes/sync for progress. Mismatched indexes are skipped and must be dropped by hand. The caller named in X-Admin-Actor is recorded in the audit log.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Actor header string false "Who started the sync, for the audit log"
+// @Success 202 {object} domain.IndexSyncJob "Sync started"
+// @Failure 409 {object} ErrorResponse "Sync already running" example({"error":{"code":"CONFLICT","message":"an index sync is already running"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to start index sync"}})
+// @Router /admin/indexes/sync [post]
+func (h *IndexHandler) SyncIndexes(c *gin.Context) {
+	job, err := h.useCase.StartSync(c.Request.Context(), c.GetHeader("X-Admin-Actor"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrIndexSyncRunning) {
+			respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to start index sync")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetIndexSync handles GET /admin/indexes/sync
+// @Summary Get index sync progress
+// @Description Get the progress of the latest index sync
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.IndexSyncJob "Latest sync job"
+// @Failure 404 {object} ErrorResponse "No sync started" example({"error":{"code":"NOT_FOUND","message":"no index sync has been started"}})
+// @Router /admin/indexes/sync [get]
+func (h *IndexHandler) GetIndexSync(c *gin.Context) {
+	job, err := h.useCase.SyncStatus()
+	if err != nil {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	_ "github.com/bitaksi/driver-service/internal/domain" // domain.KYCCheck in the swagger annotations
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxKYCWebhookBody bounds the body read from the inbound KYC webhook
+const maxKYCWebhookBody = 1 << 20
+
+// KYCHandler handles HTTP requests for driver identity verification
+type KYCHandler struct {
+	useCase usecase.KYCUseCase
+	logger  *zap.Logger
+}
+
+// NewKYCHandler creates a new identity verification handler
+func NewKYCHandler(useCase usecase.KYCUseCase, logger *zap.Logger) *KYCHandler {
+	return &KYCHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// SubmitIdentity handles POST /drivers/:id/kyc
+// @Summary Submit identity check
+// @Description Send the driver's name and documents to the KYC provider. The check starts pending; identityVerified is set on the driver once the provider approves it. When a blacklist is configured the driver is looked up in it first.
+// @Tags verification
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 202 {object} domain.KYCCheck "Check submitted"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet, or BLACKLISTED for a driver on the blacklist" example({"error":{"code":"BLACKLISTED","message":"driver is on the blacklist"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Identity verified, check pending or license document missing" example({"error":{"code":"CONFLICT","message":"an identity check is already pending"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to submit identity check"}})
+// @Failure 503 {object} ErrorResponse "Blacklist cannot be reached and BLACKLIST_FAIL_MODE is closed" example({"error":{"code":"BLACKLIST_UNAVAILABLE","message":"blacklist check is unavailable, retry later"}})
+// @Router /drivers/{id}/kyc [post]
+func (h *KYCHandler) SubmitIdentity(c *gin.Context) {
+	check, err := h.useCase.SubmitIdentity(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found":
+			respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+		case isForbiddenError(err):
+			respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+		case errors.Is(err, usecase.ErrDriverBlacklisted):
+			respondError(c, http.StatusForbidden, "BLACKLISTED", err.Error())
+		case errors.Is(err, usecase.ErrBlacklistUnavailable):
+			respondError(c, http.StatusServiceUnavailable, "BLACKLIST_UNAVAILABLE", err.Error())
+		case errors.Is(err, usecase.ErrIdentityAlreadyVerified),
+			errors.Is(err, usecase.ErrKYCCheckPending),
+			errors.Is(err, usecase.ErrIdentityDocumentsMissing):
+			respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to submit identity check")
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, check)
+}
+
+// GetIdentityCheck handles GET /drivers/:id/kyc
+// @Summary Get identity check
+// @Description Get the driver's latest identity check
+// @Tags verification
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 200 {object} domain.KYCCheck "Latest check"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver or check not found" example({"error":{"code":"NOT_FOUND","message":"no identity check found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/kyc [get]
+func (h *KYCHandler) GetIdentityCheck(c *gin.Context) {
+	check, err := h.useCase.GetIdentityCheck(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found", errors.Is(err, usecase.ErrKYCCheckNotFound):
+			respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		case isForbiddenError(err):
+			respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to get identity check")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
+
+// ReceiveWebhook handles POST /kyc/webhook
+// @Summary Receive KYC decision
+// @Description Inbound webhook for the KYC provider. The body {"reference","status","reason"} must be signed in the X-KYC-Signature header as "t=<unix seconds>,v1=<hex HMAC-SHA256 of '<t>.<body>'>" with the shared webhook secret; signatures older than 5 minutes are refused.
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param X-KYC-Signature header string true "Webhook signature" example(t=1733446800,v1=5d41402abc4b2a76b9719d911017c592)
+// @Success 200 {object} domain.KYCCheck "Decision applied"
+// @Failure 400 {object} ErrorResponse "Invalid body" example({"error":{"code":"VALIDATION_ERROR","message":"webhook body must carry a reference and a valid status"}})
+// @Failure 401 {object} ErrorResponse "Invalid signature" example({"error":{"code":"INVALID_SIGNATURE","message":"invalid webhook signature"}})
+// @Failure 404 {object} ErrorResponse "Unknown check" example({"error":{"code":"NOT_FOUND","message":"no identity check found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /kyc/webhook [post]
+func (h *KYCHandler) ReceiveWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKYCWebhookBody))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "failed to read webhook body")
+		return
+	}
+
+	check, err := h.useCase.HandleWebhook(c.Request.Context(), c.Request.Header, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidKYCSignature):
+			respondError(c, http.StatusUnauthorized, "INVALID_SIGNATURE", err.Error())
+		case errors.Is(err, usecase.ErrKYCCheckNotFound):
+			respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		case errors.Is(err, usecase.ErrInvalidKYCWebhookBody):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to process webhook")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, check)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LicenseHandler handles HTTP requests for driver licence tracking
+type LicenseHandler struct {
+	useCase usecase.LicenseUseCase
+	logger  *zap.Logger
+}
+
+// NewLicenseHandler creates a new licence handler
+func NewLicenseHandler(useCase usecase.LicenseUseCase, logger *zap.Logger) *LicenseHandler {
+	return &LicenseHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetExpiringLicenses handles GET /admin/licenses/expiring
+// @Summary List expiring driver licences
+// @Description List drivers whose licence expires within the given number of days, soonest first. Already expired licences are included; flagged drivers have been taken off dispatch by the licence check.
+// @Tags admin
+// @Produce json
+// @Param days query int false "Report window in days, 1-365 (default 30)" example(30)
+// @Param fleetId query string false "Only report drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} usecase.ExpiringLicensesResponse "Expiring licences"
+// @Failure 400 {object} ErrorResponse "Invalid window" example({"error":{"code":"VALIDATION_ERROR","message":"days must be between 1 and 365"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list expiring licenses"}})
+// @Router /admin/licenses/expiring [get]
+func (h *LicenseHandler) GetExpiringLicenses(c *gin.Context) {
+	days := 0
+	if value := c.Query("days"); value != "" {
+		var err error
+		if days, err = strconv.Atoi(value); err != nil || days == 0 {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", usecase.ErrInvalidLicenseWindow.Error())
+			return
+		}
+	}
+
+	report, err := h.useCase.ExpiringLicenses(c.Request.Context(), days, driverFilter(c))
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidLicenseWindow) {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to list expiring licenses")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
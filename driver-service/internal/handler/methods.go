@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// methodOrder orders the methods listed in Allow headers
+var methodOrder = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+	http.MethodPatch, http.MethodDelete, http.MethodOptions,
+}
+
+// RegisterHeadAndOptions adds HEAD and OPTIONS to the routes registered under a
+// group, so call it after them. GET routes without a HEAD route answer HEAD
+// like GET but without a body, and every path answers OPTIONS with 204 and an
+// Allow header listing its methods. Middleware given to a single GET route is
+// not repeated for its HEAD route; middleware of the group is.
+func RegisterHeadAndOptions(router *gin.Engine, group *gin.RouterGroup) {
+	base := group.BasePath()
+	methods := make(map[string][]string)
+	gets := make(map[string]gin.HandlerFunc)
+	var paths []string
+	for _, route := range router.Routes() {
+		if route.Path != base && !strings.HasPrefix(route.Path, base+"/") {
+			continue
+		}
+		if _, seen := methods[route.Path]; !seen {
+			paths = append(paths, route.Path)
+		}
+		methods[route.Path] = append(methods[route.Path], route.Method)
+		if route.Method == http.MethodGet {
+			gets[route.Path] = route.HandlerFunc
+		}
+	}
+
+	for _, path := range paths {
+		relative := strings.TrimPrefix(path, base)
+		allowed := methods[path]
+		if get, ok := gets[path]; ok && !hasMethod(allowed, http.MethodHead) {
+			group.HEAD(relative, headOnly(get))
+			allowed = append(allowed, http.MethodHead)
+		}
+		if hasMethod(allowed, http.MethodOptions) {
+			continue
+		}
+		allowed = append(allowed, http.MethodOptions)
+		sortMethods(allowed)
+		allow := strings.Join(allowed, ", ")
+		group.OPTIONS(relative, func(c *gin.Context) {
+			c.Header("Allow", allow)
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
+
+// headOnly runs a GET handler for a HEAD request, keeping its status and
+// headers but dropping the body
+func headOnly(get gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &headWriter{ResponseWriter: c.Writer}
+		get(c)
+	}
+}
+
+// headWriter discards the body written to it
+type headWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *headWriter) Write(data []byte) (int, error) {
+	w.ResponseWriter.WriteHeaderNow()
+	return len(data), nil
+}
+
+func (w *headWriter) WriteString(s string) (int, error) {
+	w.ResponseWriter.WriteHeaderNow()
+	return len(s), nil
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func sortMethods(methods []string) {
+	rank := func(method string) int {
+		for i, m := range methodOrder {
+			if m == method {
+				return i
+			}
+		}
+		return len(methodOrder)
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		return rank(methods[i]) < rank(methods[j])
+	})
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/metrics"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MetricsHandler exposes the business metrics for product dashboards
+type MetricsHandler struct {
+	business *metrics.Business
+	logger   *zap.Logger
+}
+
+// NewMetricsHandler creates a new metrics handler
+func NewMetricsHandler(business *metrics.Business, logger *zap.Logger) *MetricsHandler {
+	return &MetricsHandler{
+		business: business,
+		logger:   logger,
+	}
+}
+
+// GetBusinessMetrics handles GET /admin/metrics
+// @Summary Report business metrics
+// @Description Drivers registered, nearby searches with a histogram of the drivers they found and location updates stored since the instance started, as OpenMetrics counters to scrape from every instance, and the stored drivers by status (available, off_shift, suspended) as a gauge counted at most once every METRICS_STATUS_REFRESH_SEC. When drivers cannot be counted the last count is served, or the gauge is left out until the first succeeds.
+// @Tags admin
+// @Produce application/openmetrics-text
+// @Success 200 {string} string "Business metrics in the OpenMetrics text format"
+// @Router /admin/metrics [get]
+func (h *MetricsHandler) GetBusinessMetrics(c *gin.Context) {
+	var body bytes.Buffer
+	if err := h.business.WriteOpenMetrics(c.Request.Context(), &body); err != nil {
+		h.logger.Warn("failed to count drivers by status for the business metrics", zap.Error(err))
+	}
+	c.Data(http.StatusOK, metrics.ContentType, body.Bytes())
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	_ "github.com/bitaksi/driver-service/internal/domain" // domain.Driver in the swagger annotations
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead is allowed on top of the photo for the multipart framing
+const multipartOverhead = 64 << 10
+
+// PhotoHandler handles HTTP requests for driver profile pictures
+type PhotoHandler struct {
+	useCase  usecase.PhotoUseCase
+	maxBytes int64
+	logger   *zap.Logger
+}
+
+// NewPhotoHandler creates a new photo handler; uploads over maxBytes are rejected
+func NewPhotoHandler(useCase usecase.PhotoUseCase, maxBytes int64, logger *zap.Logger) *PhotoHandler {
+	return &PhotoHandler{
+		useCase:  useCase,
+		maxBytes: maxBytes,
+		logger:   logger,
+	}
+}
+
+// UploadPhoto handles POST /drivers/:id/photo
+// @Summary Upload driver photo
+// @Description Set the driver's profile picture from a JPEG or PNG upload. The picture is scaled down to a full-size copy and cropped into square thumbnails, all re-encoded as JPEG; the driver's previous photo is removed. The returned URLs change with every upload and can be cached indefinitely.
+// @Tags drivers
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param photo formData file true "JPEG or PNG picture"
+// @Success 200 {object} domain.Driver "Photo stored"
+// @Failure 400 {object} ErrorResponse "Missing file, unsupported format or dimensions out of range" example({"error":{"code":"VALIDATION_ERROR","message":"photo must be a JPEG or PNG image"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 413 {object} ErrorResponse "Photo too large" example({"error":{"code":"PAYLOAD_TOO_LARGE","message":"photo exceeds the maximum upload size"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to upload photo"}})
+// @Router /drivers/{id}/photo [post]
+func (h *PhotoHandler) UploadPhoto(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+multipartOverhead)
+
+	file, err := c.FormFile("photo")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", usecase.ErrPhotoTooLarge.Error())
+			return
+		}
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", usecase.ErrPhotoRequired.Error())
+		return
+	}
+	if file.Size > h.maxBytes {
+		respondError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", usecase.ErrPhotoTooLarge.Error())
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to upload photo")
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to upload photo")
+		return
+	}
+
+	driver, err := h.useCase.UploadPhoto(c.Request.Context(), c.Param("id"), data)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// handleError maps photo use case errors to HTTP responses
+func (h *PhotoHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "driver not found":
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrPhotoTooLarge):
+		respondError(c, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", err.Error())
+	case errors.Is(err, usecase.ErrPhotoRequired),
+		errors.Is(err, usecase.ErrInvalidPhotoFormat),
+		errors.Is(err, usecase.ErrPhotoDimensions):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, "failed to upload photo")
+	}
+}
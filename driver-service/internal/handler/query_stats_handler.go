@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// QueryStatsSource reports MongoDB command latencies
+type QueryStatsSource interface {
+	Stats() []domain.QueryStats
+}
+
+// QueryStatsHandler exposes how long the service's MongoDB commands take
+type QueryStatsHandler struct {
+	source QueryStatsSource
+	logger *zap.Logger
+}
+
+// NewQueryStatsHandler creates a new query stats handler
+func NewQueryStatsHandler(source QueryStatsSource, logger *zap.Logger) *QueryStatsHandler {
+	return &QueryStatsHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetQueryStats handles GET /admin/query-stats
+// @Summary Report MongoDB query latencies
+// @Description Duration histogram of every MongoDB command per collection since the service started, the most time-consuming first. Commands slower than MONGODB_SLOW_QUERY_MS are also logged with their redacted filter and the route that sent them.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} domain.QueryStats "Query latencies"
+// @Router /admin/query-stats [get]
+func (h *QueryStatsHandler) GetQueryStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.source.Stats())
+}
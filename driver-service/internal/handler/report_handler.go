@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReportHandler handles HTTP requests for operational reports
+type ReportHandler struct {
+	utilization usecase.UtilizationUseCase
+	logger      *zap.Logger
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(utilization usecase.UtilizationUseCase, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		utilization: utilization,
+		logger:      logger,
+	}
+}
+
+// GetUtilization handles GET /admin/reports/utilization
+// @Summary Driver utilization report
+// @Description Online hours (time on shift) against busy hours (time between accepting and completing trips) per calendar day and taxi type, with busy over online as utilization. Days are calendar days in the earnings time zone; time is attributed to the taxi type the driver has now. The range defaults to the last 7 days and cannot exceed 92 days. Complete days come from daily rollups; today is computed for the request and marked partial. Send format=csv or "Accept: text/csv" for a CSV export.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "First day (YYYY-MM-DD, inclusive)" example("2025-12-01")
+// @Param to query string false "Last day (YYYY-MM-DD, inclusive)" example("2025-12-07")
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} domain.UtilizationReport "Utilization report"
+// @Failure 400 {object} ErrorResponse "Invalid range" example({"error":{"code":"VALIDATION_ERROR","message":"from must be before to"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get utilization"}})
+// @Router /admin/reports/utilization [get]
+func (h *ReportHandler) GetUtilization(c *gin.Context) {
+	from, err := parseRangeParam(c.Query("from"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be a YYYY-MM-DD date")
+		return
+	}
+	to, err := parseRangeParam(c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be a YYYY-MM-DD date")
+		return
+	}
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "csv" {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "format must be json or csv")
+		return
+	}
+
+	var report *domain.UtilizationReport
+	report, err = h.utilization.GetUtilization(c.Request.Context(), from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidStatsRange), errors.Is(err, usecase.ErrUtilizationRangeTooLong):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to get utilization")
+		}
+		return
+	}
+
+	if format == "csv" || (format == "" && c.NegotiateFormat(gin.MIMEJSON, "text/csv") == "text/csv") {
+		c.Header("Content-Disposition", `attachment; filename="utilization-`+report.From+`-`+report.To+`.csv"`)
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := usecase.WriteUtilizationCSV(c.Writer, report); err != nil {
+			h.logger.Error("failed to write utilization export", zap.Error(err))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
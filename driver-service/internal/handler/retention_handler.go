@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RetentionHandler handles HTTP requests for personal data erasure
+type RetentionHandler struct {
+	useCase usecase.RetentionUseCase
+	logger  *zap.Logger
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(useCase usecase.RetentionUseCase, logger *zap.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// ErasePersonalData handles DELETE /drivers/:id/personal-data
+// @Summary Erase a driver's personal data
+// @Description Erase a driver's personal data at once, e.g. on a KVKK/GDPR request, instead of waiting for the retention window. A live driver is deleted first. Names, contact details, plate, location history, documents, licence, photo files, device tokens and phone verifications are erased; trips, shifts, earnings and identity check outcomes are kept for statistics and the books. The erasure is recorded with the caller named in X-Admin-Actor and the reason.
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param reason query string false "Why the data is erased, e.g. a ticket number" example("KVKK request #4711")
+// @Param X-Admin-Actor header string false "Who requested the erasure, for the audit record"
+// @Success 200 {object} domain.Erasure "Erasure record"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet" example({"error":{"code":"FORBIDDEN","message":"driver does not belong to your fleet"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to erase personal data"}})
+// @Router /drivers/{id}/personal-data [delete]
+func (h *RetentionHandler) ErasePersonalData(c *gin.Context) {
+	var erasure *domain.Erasure
+	erasure, err := h.useCase.ErasePersonalData(c.Request.Context(), c.Param("id"), c.GetHeader("X-Admin-Actor"), c.Query("reason"))
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found":
+			respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+		case isForbiddenError(err):
+			respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to erase personal data")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, erasure)
+}
+
+// ListErasures handles GET /admin/erasures
+// @Summary List personal data erasures
+// @Description Audit trail of driver personal data erasures, by the retention job and on request, newest first.
+// @Tags admin
+// @Produce json
+// @Param driverId query string false "Only list erasures of this driver" example(507f1f77bcf86cd799439011)
+// @Param limit query int false "Maximum erasures to return (max 200)" default(50)
+// @Success 200 {array} domain.Erasure "Erasures"
+// @Failure 400 {object} ErrorResponse "Invalid limit" example({"error":{"code":"VALIDATION_ERROR","message":"limit must be a positive integer"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list erasures"}})
+// @Router /admin/erasures [get]
+func (h *RetentionHandler) ListErasures(c *gin.Context) {
+	limit, ok := limitParam(c)
+	if !ok {
+		return
+	}
+
+	erasures, err := h.useCase.ListErasures(c.Request.Context(), c.Query("driverId"), limit)
+	if err != nil {
+		respondInternalError(c, h.logger, err, "failed to list erasures")
+		return
+	}
+
+	c.JSON(http.StatusOK, erasures)
+}
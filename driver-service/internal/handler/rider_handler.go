@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RiderHandler handles HTTP requests for rider profiles and favorite locations
+type RiderHandler struct {
+	useCase usecase.RiderUseCase
+	logger  *zap.Logger
+}
+
+// NewRiderHandler creates a new rider handler
+func NewRiderHandler(useCase usecase.RiderUseCase, logger *zap.Logger) *RiderHandler {
+	return &RiderHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// RegisterRider handles POST /riders
+// @Summary Register a rider
+// @Description Register a rider who can request trips. Phone numbers are normalized to E.164 and must be unique.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param rider body usecase.RegisterRiderRequest true "Rider information"
+// @Success 201 {object} domain.Rider "Rider registered"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"phone must be in E.164 format (e.g., +905321234567)"}})
+// @Failure 409 {object} ErrorResponse "Phone taken" example({"error":{"code":"CONFLICT","message":"phone is already registered to another rider"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to register rider"}})
+// @Router /riders [post]
+func (h *RiderHandler) RegisterRider(c *gin.Context) {
+	var req usecase.RegisterRiderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	var rider *domain.Rider
+	rider, err := h.useCase.RegisterRider(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to register rider")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rider)
+}
+
+// GetRider handles GET /riders/:id
+// @Summary Get a rider
+// @Description Get a rider's profile and favorite locations by ID
+// @Tags riders
+// @Produce json
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.Rider "Rider"
+// @Failure 404 {object} ErrorResponse "Rider not found" example({"error":{"code":"NOT_FOUND","message":"rider not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get rider"}})
+// @Router /riders/{id} [get]
+func (h *RiderHandler) GetRider(c *gin.Context) {
+	rider, err := h.useCase.GetRider(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get rider")
+		return
+	}
+
+	c.JSON(http.StatusOK, rider)
+}
+
+// UpdateRider handles PUT /riders/:id
+// @Summary Update a rider
+// @Description Update a rider's profile. Only the fields in the request body change.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param rider body usecase.UpdateRiderRequest true "Profile fields to change"
+// @Success 200 {object} domain.Rider "Updated rider"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"email must be a valid email address"}})
+// @Failure 404 {object} ErrorResponse "Rider not found" example({"error":{"code":"NOT_FOUND","message":"rider not found"}})
+// @Failure 409 {object} ErrorResponse "Phone taken" example({"error":{"code":"CONFLICT","message":"phone is already registered to another rider"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update rider"}})
+// @Router /riders/{id} [put]
+func (h *RiderHandler) UpdateRider(c *gin.Context) {
+	var req usecase.UpdateRiderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	rider, err := h.useCase.UpdateRider(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to update rider")
+		return
+	}
+
+	c.JSON(http.StatusOK, rider)
+}
+
+// AddFavoriteLocation handles POST /riders/:id/favorites
+// @Summary Save a favorite location
+// @Description Save a labelled place, such as home or work, that the rider can pick as pickup or dropoff
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param favorite body usecase.FavoriteLocationRequest true "Place to save"
+// @Success 201 {object} domain.Rider "Rider with the saved place"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"latitude must be between -90 and 90"}})
+// @Failure 404 {object} ErrorResponse "Rider not found" example({"error":{"code":"NOT_FOUND","message":"rider not found"}})
+// @Failure 409 {object} ErrorResponse "Too many favorites" example({"error":{"code":"CONFLICT","message":"a rider can save at most 20 favorite locations"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update rider"}})
+// @Router /riders/{id}/favorites [post]
+func (h *RiderHandler) AddFavoriteLocation(c *gin.Context) {
+	var req usecase.FavoriteLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	rider, err := h.useCase.AddFavoriteLocation(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to update rider")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rider)
+}
+
+// RemoveFavoriteLocation handles DELETE /riders/:id/favorites/:favoriteId
+// @Summary Remove a favorite location
+// @Description Remove a saved place from the rider's favorites
+// @Tags riders
+// @Produce json
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param favoriteId path string true "Favorite location ID" example("fav_3f9a1c2b")
+// @Success 200 {object} domain.Rider "Rider without the removed place"
+// @Failure 404 {object} ErrorResponse "Rider or favorite not found" example({"error":{"code":"NOT_FOUND","message":"favorite location not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update rider"}})
+// @Router /riders/{id}/favorites/{favoriteId} [delete]
+func (h *RiderHandler) RemoveFavoriteLocation(c *gin.Context) {
+	rider, err := h.useCase.RemoveFavoriteLocation(c.Request.Context(), c.Param("id"), c.Param("favoriteId"))
+	if err != nil {
+		h.handleError(c, err, "failed to update rider")
+		return
+	}
+
+	c.JSON(http.StatusOK, rider)
+}
+
+// handleError maps rider use case errors to HTTP responses
+func (h *RiderHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrRiderNotFound), errors.Is(err, usecase.ErrFavoriteNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrNameRequired),
+		errors.Is(err, usecase.ErrFavoriteLabelRequired),
+		isValidationError(err):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrRiderPhoneTaken), errors.Is(err, usecase.ErrTooManyFavorites):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
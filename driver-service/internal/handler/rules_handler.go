@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/rules"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RulesHandler exposes the driver field validation rules in effect
+type RulesHandler struct {
+	engine *rules.Engine
+	logger *zap.Logger
+}
+
+// NewRulesHandler creates a new validation rules handler
+func NewRulesHandler(engine *rules.Engine, logger *zap.Logger) *RulesHandler {
+	return &RulesHandler{
+		engine: engine,
+		logger: logger,
+	}
+}
+
+// GetValidationRules handles GET /admin/validation-rules
+// @Summary Inspect driver validation rules
+// @Description The effective rules of the service country, every configured country and every tenant, after inheritance is applied. With fleetId, only the rules applied to drivers of that fleet.
+// @Tags admin
+// @Produce json
+// @Param fleetId query string false "Only return the rules applied to this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} rules.Effective "Effective rules"
+// @Router /admin/validation-rules [get]
+func (h *RulesHandler) GetValidationRules(c *gin.Context) {
+	if _, ok := c.GetQuery("fleetId"); ok {
+		c.JSON(http.StatusOK, h.engine.For(c.Query("fleetId")))
+		return
+	}
+	c.JSON(http.StatusOK, h.engine.Effective())
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SaturationStatsSource reports how busy the HTTP server is
+type SaturationStatsSource interface {
+	Stats() middleware.SaturationStats
+}
+
+// SaturationHandler exposes the server's concurrency saturation
+type SaturationHandler struct {
+	source SaturationStatsSource
+	logger *zap.Logger
+}
+
+// NewSaturationHandler creates a new saturation handler
+func NewSaturationHandler(source SaturationStatsSource, logger *zap.Logger) *SaturationHandler {
+	return &SaturationHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetSaturation handles GET /admin/saturation
+// @Summary Report server saturation
+// @Description Requests in flight against MAX_IN_FLIGHT_REQUESTS, the peak since start, how many requests were rejected with 503 while saturated, and the open connections
+// @Tags admin
+// @Produce json
+// @Success 200 {object} middleware.SaturationStats "Saturation metrics"
+// @Router /admin/saturation [get]
+func (h *SaturationHandler) GetSaturation(c *gin.Context) {
+	c.JSON(http.StatusOK, h.source.Stats())
+}
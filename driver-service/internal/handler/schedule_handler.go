@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// mimeCalendar is the media type of iCalendar exports
+const mimeCalendar = "text/calendar"
+
+// ScheduleHandler handles HTTP requests for shifts planned ahead
+type ScheduleHandler struct {
+	useCase usecase.ScheduleUseCase
+	logger  *zap.Logger
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(useCase usecase.ScheduleUseCase, logger *zap.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// CreateShift handles POST /drivers/:id/shifts
+// @Summary Schedule a shift
+// @Description Plan a shift for the driver. The driver is put on shift when it starts and off shift when it ends. Shifts of a driver cannot overlap and last at most 24 hours.
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body usecase.ScheduleShiftRequest true "Shift times"
+// @Success 201 {object} domain.ScheduledShift "Shift scheduled"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"endsAt must be after startsAt"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Overlaps another shift" example({"error":{"code":"CONFLICT","message":"shift overlaps another shift of the driver (2025-12-08T06:00:00Z to 2025-12-08T14:00:00Z)"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create shift"}})
+// @Router /drivers/{id}/shifts [post]
+func (h *ScheduleHandler) CreateShift(c *gin.Context) {
+	var req usecase.ScheduleShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	shift, err := h.useCase.CreateShift(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create shift")
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+// ListDriverShifts handles GET /drivers/:id/shifts
+// @Summary Driver shift calendar
+// @Description Shifts of the driver overlapping the range, by start time. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or "Accept: text/calendar" for an iCalendar export.
+// @Tags schedule
+// @Produce json
+// @Produce text/calendar
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-12-08")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-15")
+// @Param format query string false "json (default) or ics"
+// @Success 200 {object} usecase.ShiftCalendar "Shifts"
+// @Failure 400 {object} ErrorResponse "Invalid range" example({"error":{"code":"VALIDATION_ERROR","message":"calendar range cannot exceed 92 days"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/shifts [get]
+func (h *ScheduleHandler) ListDriverShifts(c *gin.Context) {
+	h.calendar(c, domain.ScheduleFilter{DriverID: c.Param("id")}, "driver-"+c.Param("id"))
+}
+
+// ListShifts handles GET /shifts
+// @Summary Shift calendar
+// @Description Shifts overlapping the range, by start time, for a fleet or a driver. Fleet admins only see their own fleet. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or "Accept: text/calendar" for an iCalendar export.
+// @Tags schedule
+// @Produce json
+// @Produce text/calendar
+// @Param fleetId query string false "Only shifts of this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param driverId query string false "Only shifts of this driver" example(507f1f77bcf86cd799439011)
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-12-08")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-15")
+// @Param format query string false "json (default) or ics"
+// @Success 200 {object} usecase.ShiftCalendar "Shifts"
+// @Failure 400 {object} ErrorResponse "Invalid range" example({"error":{"code":"VALIDATION_ERROR","message":"from must be before to"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts [get]
+func (h *ScheduleHandler) ListShifts(c *gin.Context) {
+	filter := domain.ScheduleFilter{DriverID: c.Query("driverId"), FleetID: c.Query("fleetId")}
+	name := "shifts"
+	switch {
+	case filter.DriverID != "":
+		name = "driver-" + filter.DriverID
+	case filter.FleetID != "":
+		name = "fleet-" + filter.FleetID
+	}
+	h.calendar(c, filter, name)
+}
+
+func (h *ScheduleHandler) calendar(c *gin.Context, filter domain.ScheduleFilter, name string) {
+	from, err := parseRangeParam(c.Query("from"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	to, err := parseRangeParam(c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	format := c.Query("format")
+	if format != "" && format != "json" && format != "ics" {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "format must be json or ics")
+		return
+	}
+	if from != nil {
+		filter.From = *from
+	}
+	if to != nil {
+		filter.To = *to
+	}
+
+	calendar, err := h.useCase.Calendar(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err, "failed to list shifts")
+		return
+	}
+
+	if format == "ics" || (format == "" && c.NegotiateFormat(gin.MIMEJSON, mimeCalendar) == mimeCalendar) {
+		c.Header("Content-Disposition", `attachment; filename="`+name+`.ics"`)
+		c.Header("Content-Type", mimeCalendar+"; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := usecase.WriteScheduleICS(c.Writer, name, calendar.Shifts); err != nil {
+			h.logger.Error("failed to write shift calendar", zap.Error(err))
+		}
+		return
+	}
+	c.JSON(http.StatusOK, calendar)
+}
+
+// GetShift handles GET /shifts/:id
+// @Summary Get a shift
+// @Description Get a planned shift, with when the driver was put on and off shift.
+// @Tags schedule
+// @Produce json
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.ScheduledShift "Shift"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found" example({"error":{"code":"NOT_FOUND","message":"shift not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [get]
+func (h *ScheduleHandler) GetShift(c *gin.Context) {
+	shift, err := h.useCase.GetShift(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// UpdateShift handles PUT /shifts/:id
+// @Summary Reschedule a shift
+// @Description Move a shift. Once the driver was put on shift only its end can move; ended shifts cannot change.
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Param request body usecase.ScheduleShiftRequest true "Shift times"
+// @Success 200 {object} domain.ScheduledShift "Shift rescheduled"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"a shift cannot be longer than 24 hours"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found" example({"error":{"code":"NOT_FOUND","message":"shift not found"}})
+// @Failure 409 {object} ErrorResponse "Overlaps another shift, or the shift started or ended" example({"error":{"code":"CONFLICT","message":"shift has started, only its end can change"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [put]
+func (h *ScheduleHandler) UpdateShift(c *gin.Context) {
+	var req usecase.ScheduleShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	shift, err := h.useCase.UpdateShift(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to update shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// DeleteShift handles DELETE /shifts/:id
+// @Summary Cancel a shift
+// @Description Delete a planned shift. A running shift cannot be deleted; move its end instead.
+// @Tags schedule
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Success 204 "Shift deleted"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found" example({"error":{"code":"NOT_FOUND","message":"shift not found"}})
+// @Failure 409 {object} ErrorResponse "Shift is running" example({"error":{"code":"CONFLICT","message":"shift has started, only its end can change"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [delete]
+func (h *ScheduleHandler) DeleteShift(c *gin.Context) {
+	if err := h.useCase.DeleteShift(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "failed to delete shift")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// handleError maps schedule use case errors to HTTP responses
+func (h *ScheduleHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case err.Error() == "driver not found", errors.Is(err, usecase.ErrShiftNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case isForbiddenError(err):
+		respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, usecase.ErrInvalidShiftTimes), errors.Is(err, usecase.ErrShiftTooLong),
+		errors.Is(err, usecase.ErrShiftInPast), errors.Is(err, usecase.ErrInvalidStatsRange),
+		errors.Is(err, usecase.ErrCalendarRangeTooLong):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrShiftConflict), errors.Is(err, usecase.ErrShiftStarted), errors.Is(err, usecase.ErrShiftEnded):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
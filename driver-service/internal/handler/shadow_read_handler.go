@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ShadowReadStatsSource reports how mirrored driver reads compared
+type ShadowReadStatsSource interface {
+	Stats() domain.ShadowReadStats
+}
+
+// ShadowReadHandler exposes how the shadow backend's answers compare with the primary's
+type ShadowReadHandler struct {
+	// source is nil unless shadow reads are enabled
+	source ShadowReadStatsSource
+	logger *zap.Logger
+}
+
+// NewShadowReadHandler creates a new shadow read handler; a nil source reports shadow reads as disabled
+func NewShadowReadHandler(source ShadowReadStatsSource, logger *zap.Logger) *ShadowReadHandler {
+	return &ShadowReadHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetShadowReadStats handles GET /admin/shadow-reads
+// @Summary Report shadow read comparisons
+// @Description With SHADOW_READS_ENABLED, a SHADOW_READ_SAMPLE_RATE share of driver reads (get by ID, phone or email, list and nearby search) is run again against the shadow backend in the background and compared with the primary's answer. Counts per repository method since the service started how many matched, diverged or timed out, and how many sampled reads were dropped because SHADOW_READ_MAX_IN_FLIGHT were already running. Divergences are logged with the names of the differing fields.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.ShadowReadStats "Shadow read comparisons"
+// @Router /admin/shadow-reads [get]
+func (h *ShadowReadHandler) GetShadowReadStats(c *gin.Context) {
+	if h.source == nil {
+		c.JSON(http.StatusOK, domain.ShadowReadStats{Operations: []domain.ShadowReadOpStats{}})
+		return
+	}
+	c.JSON(http.StatusOK, h.source.Stats())
+}
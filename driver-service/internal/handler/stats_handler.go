@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// StatsHandler handles HTTP requests for driver statistics
+type StatsHandler struct {
+	useCase usecase.StatsUseCase
+	logger  *zap.Logger
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(useCase usecase.StatsUseCase, logger *zap.Logger) *StatsHandler {
+	return &StatsHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetDriverStats handles GET /drivers/:id/stats
+// @Summary Get driver statistics
+// @Description Aggregate completed trips, distance driven, online hours and average rating over a date range. The range defaults to the last 30 days and cannot exceed 366 days. Results are cached briefly.
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Success 200 {object} domain.DriverStats "Driver statistics"
+// @Failure 400 {object} ErrorResponse "Invalid range" example({"error":{"code":"VALIDATION_ERROR","message":"from must be before to"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get driver stats"}})
+// @Router /drivers/{id}/stats [get]
+func (h *StatsHandler) GetDriverStats(c *gin.Context) {
+	from, err := parseRangeParam(c.Query("from"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	to, err := parseRangeParam(c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+
+	var stats *domain.DriverStats
+	stats, err = h.useCase.GetDriverStats(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found":
+			respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+		case errors.Is(err, usecase.ErrInvalidStatsRange), errors.Is(err, usecase.ErrStatsRangeTooLong):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to get driver stats")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetLocationAnomalies handles GET /admin/location-anomalies
+// @Summary Location anomalies per driver
+// @Description Drivers whose location updates implied an implausible speed, e.g. spoofed GPS, with the number of such updates, how many were rejected and the highest implied speed; most anomalies first. The range defaults to the last 30 days and cannot exceed 366 days.
+// @Tags admin
+// @Produce json
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Param limit query int false "Number of drivers to list (1-1000, default 50)"
+// @Success 200 {object} domain.LocationAnomalyReport "Anomalies per driver"
+// @Failure 400 {object} ErrorResponse "Invalid range or limit" example({"error":{"code":"VALIDATION_ERROR","message":"limit must be between 1 and 1000"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to get location anomalies"}})
+// @Router /admin/location-anomalies [get]
+func (h *StatsHandler) GetLocationAnomalies(c *gin.Context) {
+	from, err := parseRangeParam(c.Query("from"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	to, err := parseRangeParam(c.Query("to"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		return
+	}
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", usecase.ErrInvalidAnomalyLimit.Error())
+			return
+		}
+	}
+
+	report, err := h.useCase.GetLocationAnomalies(c.Request.Context(), from, to, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidStatsRange), errors.Is(err, usecase.ErrStatsRangeTooLong),
+			errors.Is(err, usecase.ErrInvalidAnomalyLimit):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to get location anomalies")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseRangeParam accepts an RFC3339 timestamp or a UTC calendar date; empty means unset
+func parseRangeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
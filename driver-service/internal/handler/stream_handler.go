@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+)
+
+// Stream trailers tell clients whether a stream ran to its end and where to
+// resume one that did not
+const (
+	streamStatusTrailer = "X-Stream-Status"
+	streamLastIDTrailer = "X-Stream-Last-Id"
+)
+
+// StreamHandler handles HTTP requests streaming drivers to data pipelines
+type StreamHandler struct {
+	useCase       usecase.StreamUseCase
+	flushInterval time.Duration
+	writeTimeout  time.Duration
+	logger        *zap.Logger
+}
+
+// NewStreamHandler creates a new stream handler that sends streamed drivers on
+// every flushInterval and allows each flush writeTimeout
+func NewStreamHandler(useCase usecase.StreamUseCase, flushInterval, writeTimeout time.Duration, logger *zap.Logger) *StreamHandler {
+	return &StreamHandler{
+		useCase:       useCase,
+		flushInterval: flushInterval,
+		writeTimeout:  writeTimeout,
+		logger:        logger,
+	}
+}
+
+// StreamDrivers handles GET /drivers/stream
+// @Summary Stream drivers
+// @Description Stream every driver as NDJSON, one driver per line in ID order, straight from a database cursor instead of page by page; for data pipelines pulling the whole table. Clients sending "Accept-Encoding: zstd" get a zstd-compressed stream. The X-Stream-Status trailer is complete once every driver was sent, or failed when the stream ended early; pass the X-Stream-Last-Id trailer, or the ID of the last line received, as afterId to resume. Fleet admins only stream their own fleet's drivers.
+// @Tags drivers
+// @Produce application/x-ndjson
+// @Param afterId query string false "Only stream drivers after this driver ID, to resume a stream" example(507f1f77bcf86cd799439011)
+// @Param fleetId query string false "Only stream drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param city query string false "Only stream drivers of this city" example(istanbul)
+// @Param tags query string false "Only stream drivers with all of these tags, comma-separated" example(pet-friendly)
+// @Param attributes query string false "Only stream drivers with these attributes, comma-separated key:value pairs; a key alone matches any value" example(language:en)
+// @Success 200 {object} domain.Driver "Drivers, one per line"
+// @Header 200 {string} X-Stream-Status "Trailer: complete, or failed when the stream ended early"
+// @Header 200 {string} X-Stream-Last-Id "Trailer: ID of the last driver sent"
+// @Failure 400 {object} ErrorResponse "Invalid afterId or filter" example({"error":{"code":"VALIDATION_ERROR","message":"afterId must be a driver ID"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to stream drivers"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/stream [get]
+func (h *StreamHandler) StreamDrivers(c *gin.Context) {
+	filter, err := driverSearchFilter(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	stream := &driverStream{
+		c:             c,
+		zstd:          acceptsEncoding(c.GetHeader("Accept-Encoding"), "zstd"),
+		flushInterval: h.flushInterval,
+		writeTimeout:  h.writeTimeout,
+		controller:    http.NewResponseController(c.Writer),
+	}
+	err = h.useCase.StreamDrivers(c.Request.Context(), filter, c.Query("afterId"), stream.write)
+
+	if !stream.started() {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidStreamPosition):
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		case err != nil:
+			respondInternalError(c, h.logger, err, "failed to stream drivers")
+		default:
+			// Nothing matched: an empty, complete stream
+			stream.start()
+			stream.finish(nil)
+		}
+		return
+	}
+	if closeErr := stream.finish(err); closeErr != nil && err == nil {
+		h.logger.Warn("failed to finish driver stream", zap.Error(closeErr))
+	}
+}
+
+// driverStream writes drivers as NDJSON, compressed with zstd when asked, and
+// flushes them to the client every flushInterval
+type driverStream struct {
+	c             *gin.Context
+	zstd          bool
+	flushInterval time.Duration
+	writeTimeout  time.Duration
+	controller    *http.ResponseController
+
+	compressor *zstd.Encoder
+	buf        *bufio.Writer
+	encoder    *json.Encoder
+	lastFlush  time.Time
+	lastID     string
+}
+
+func (s *driverStream) started() bool {
+	return s.buf != nil
+}
+
+// start sends the headers and sets up the writers. It waits for the first
+// driver so errors before it still get an error response.
+func (s *driverStream) start() {
+	header := s.c.Writer.Header()
+	header.Set("Content-Type", "application/x-ndjson")
+	header.Set("Trailer", streamStatusTrailer+", "+streamLastIDTrailer)
+	header.Add("Vary", "Accept-Encoding")
+
+	var w io.Writer = s.c.Writer
+	if s.zstd {
+		header.Set("Content-Encoding", "zstd")
+		// Neither option fails
+		s.compressor, _ = zstd.NewWriter(s.c.Writer, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+		w = s.compressor
+	}
+	s.extendDeadline()
+	s.c.Status(http.StatusOK)
+	s.c.Writer.WriteHeaderNow()
+
+	s.buf = bufio.NewWriterSize(w, 32*1024)
+	s.encoder = json.NewEncoder(s.buf)
+	s.lastFlush = time.Now()
+}
+
+// write sends one driver
+func (s *driverStream) write(driver *domain.Driver) error {
+	if !s.started() {
+		s.start()
+	}
+	if err := s.encoder.Encode(driver); err != nil {
+		return err
+	}
+	s.lastID = driver.ID
+	if time.Since(s.lastFlush) >= s.flushInterval {
+		return s.flush()
+	}
+	return nil
+}
+
+// flush sends what is buffered on to the client
+func (s *driverStream) flush() error {
+	s.extendDeadline()
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	if s.compressor != nil {
+		if err := s.compressor.Flush(); err != nil {
+			return err
+		}
+	}
+	s.lastFlush = time.Now()
+	return s.controller.Flush()
+}
+
+// extendDeadline gives the next flush writeTimeout; streams outlast the
+// server's write timeout, which is set once per request
+func (s *driverStream) extendDeadline() {
+	if s.writeTimeout > 0 {
+		// Not every writer supports deadlines, e.g. in tests
+		_ = s.controller.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+	}
+}
+
+// finish flushes the rest of the stream and sets the trailers
+func (s *driverStream) finish(streamErr error) error {
+	err := s.flush()
+	if s.compressor != nil {
+		if closeErr := s.compressor.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	status := "complete"
+	if streamErr != nil || err != nil {
+		status = "failed"
+	}
+	header := s.c.Writer.Header()
+	header.Set(streamStatusTrailer, status)
+	header.Set(streamLastIDTrailer, s.lastID)
+	return err
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header allows coding
+func acceptsEncoding(header, coding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), coding) {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
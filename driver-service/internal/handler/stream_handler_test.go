@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mockStreamRepository streams drivers kept in memory; with err set it fails
+// once failAfter drivers were sent
+type mockStreamRepository struct {
+	drivers    []*domain.Driver
+	err        error
+	failAfter  int
+	lastFilter domain.DriverFilter
+}
+
+func (m *mockStreamRepository) StreamDrivers(ctx interface{}, filter domain.DriverFilter, afterID string, fn func(*domain.Driver) error) error {
+	m.lastFilter = filter
+	if afterID != "" && len(afterID) != 24 {
+		return errors.New("invalid driver ID")
+	}
+	sent := 0
+	for _, driver := range m.drivers {
+		if driver.ID <= afterID {
+			continue
+		}
+		if m.err != nil && sent == m.failAfter {
+			return m.err
+		}
+		if err := fn(driver); err != nil {
+			return err
+		}
+		sent++
+	}
+	return nil
+}
+
+func setupStreamRouter(repo *mockStreamRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := NewStreamHandler(usecase.NewStreamUseCase(repo, zap.NewNop()), time.Hour, 0, zap.NewNop())
+	router.GET("/api/v1/drivers/stream", h.StreamDrivers)
+	return router
+}
+
+// readLines decodes the driver IDs of an NDJSON body
+func readLines(t *testing.T, body io.Reader) []string {
+	t.Helper()
+	var ids []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		var driver domain.Driver
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &driver))
+		ids = append(ids, driver.ID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
+
+func TestStreamHandler_StreamDrivers(t *testing.T) {
+	repo := &mockStreamRepository{drivers: []*domain.Driver{
+		{ID: "507f1f77bcf86cd799439011", FirstName: "Ahmet", FleetID: "fleet-1"},
+		{ID: "507f1f77bcf86cd799439012", FirstName: "Ayse", FleetID: "fleet-1"},
+		{ID: "507f1f77bcf86cd799439013", FirstName: "Mehmet", FleetID: "fleet-2"},
+	}}
+	router := setupStreamRouter(repo)
+
+	t.Run("streams NDJSON with trailers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream?tags=pet-friendly", nil))
+		resp := w.Result()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+		assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		assert.Equal(t, []string{"507f1f77bcf86cd799439011", "507f1f77bcf86cd799439012", "507f1f77bcf86cd799439013"}, readLines(t, resp.Body))
+		assert.Equal(t, "complete", resp.Trailer.Get("X-Stream-Status"))
+		assert.Equal(t, "507f1f77bcf86cd799439013", resp.Trailer.Get("X-Stream-Last-Id"))
+		assert.Equal(t, []string{"pet-friendly"}, repo.lastFilter.Tags)
+	})
+
+	t.Run("resumes after afterId", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream?afterId=507f1f77bcf86cd799439011", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"507f1f77bcf86cd799439012", "507f1f77bcf86cd799439013"}, readLines(t, w.Body))
+	})
+
+	t.Run("compresses with zstd when accepted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/drivers/stream", nil)
+		req.Header.Set("Accept-Encoding", "gzip, zstd")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"))
+		decoder, err := zstd.NewReader(w.Body)
+		require.NoError(t, err)
+		defer decoder.Close()
+		assert.Len(t, readLines(t, decoder), 3)
+	})
+
+	t.Run("invalid afterId", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream?afterId=nope", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_ERROR","message":"afterId must be a driver ID"}}`, w.Body.String())
+	})
+
+	t.Run("nothing to stream", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream?afterId=507f1f77bcf86cd799439013", nil))
+		resp := w.Result()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, "complete", resp.Trailer.Get("X-Stream-Status"))
+	})
+}
+
+func TestStreamHandler_StreamDrivers_Failure(t *testing.T) {
+	repo := &mockStreamRepository{
+		drivers: []*domain.Driver{
+			{ID: "507f1f77bcf86cd799439011"},
+			{ID: "507f1f77bcf86cd799439012"},
+		},
+		err: errors.New("cursor lost"),
+	}
+	router := setupStreamRouter(repo)
+
+	t.Run("before the first driver", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"failed to stream drivers"}}`, w.Body.String())
+	})
+
+	t.Run("part way", func(t *testing.T) {
+		repo.failAfter = 1
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/stream", nil))
+		resp := w.Result()
+
+		// The status went out with the first driver; the trailers tell the stream is incomplete
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"507f1f77bcf86cd799439011"}, readLines(t, resp.Body))
+		assert.Equal(t, "failed", resp.Trailer.Get("X-Stream-Status"))
+		assert.Equal(t, "507f1f77bcf86cd799439011", resp.Trailer.Get("X-Stream-Last-Id"))
+	})
+}
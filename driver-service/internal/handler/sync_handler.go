@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SyncHandler handles HTTP requests for delta syncs to mobile apps
+type SyncHandler struct {
+	useCase usecase.SyncUseCase
+	logger  *zap.Logger
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(useCase usecase.SyncUseCase, logger *zap.Logger) *SyncHandler {
+	return &SyncHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// GetDriverChanges handles GET /drivers/changes
+// @Summary Get driver changes
+// @Description Get the drivers created, updated and deleted since a marker so apps can update an offline cache. Pass nextToken as since to continue while hasMore is true, and keep the last nextToken for the next sync. Omit since for a full sync.
+// @Tags drivers
+// @Produce json
+// @Param since query string false "RFC3339 timestamp or nextToken from an earlier response" example(2025-12-06T01:00:00Z)
+// @Param limit query int false "Maximum changes per response; defaults to 100 and is capped at 500" default(100) example(100)
+// @Param fleetId query string false "Only sync drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} usecase.DriverChangesResponse "Changed drivers" example({"created":[],"updated":[{"id":"507f1f77bcf86cd799439011","firstName":"Ahmet","lastName":"Demir","plate":"34ABC123","taxiType":"sari","carBrand":"Toyota","carModel":"Corolla","location":{"lat":41.0431,"lon":29.0099},"createdAt":"2025-12-01T09:00:00Z","updatedAt":"2025-12-06T01:01:30Z"}],"deleted":["507f1f77bcf86cd799439012"],"nextToken":"eyJzIjoiMjAyNS0xMi0wNlQwMTowMDowMFoiLCJ1IjoiMjAyNS0xMi0wNlQwMTowMTozMFoiLCJpIjoiNTA3ZjFmNzdiY2Y4NmNkNzk5NDM5MDExIn0","hasMore":false})
+// @Failure 400 {object} ErrorResponse "Invalid marker" example({"error":{"code":"VALIDATION_ERROR","message":"since must be an RFC3339 timestamp or a nextToken from an earlier sync"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list driver changes"}})
+// @Failure 503 {object} ErrorResponse "Database failing over, retry after the Retry-After header" example({"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}})
+// @Router /drivers/changes [get]
+func (h *SyncHandler) GetDriverChanges(c *gin.Context) {
+	// A missing limit parses as 0 and gets the default
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	var changes *usecase.DriverChangesResponse
+	changes, err := h.useCase.DriverChanges(c.Request.Context(), c.Query("since"), driverFilter(c), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidSyncMarker) {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		respondInternalError(c, h.logger, err, "failed to list driver changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
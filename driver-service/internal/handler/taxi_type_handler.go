@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/rules"
+	"github.com/bitaksi/driver-service/internal/taxitype"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TaxiTypeHandler exposes the taxi type registry to clients
+type TaxiTypeHandler struct {
+	registry *taxitype.Registry
+	rules    *rules.Engine
+	logger   *zap.Logger
+}
+
+// NewTaxiTypeHandler creates a new taxi type handler
+func NewTaxiTypeHandler(registry *taxitype.Registry, engine *rules.Engine, logger *zap.Logger) *TaxiTypeHandler {
+	return &TaxiTypeHandler{
+		registry: registry,
+		rules:    engine,
+		logger:   logger,
+	}
+}
+
+// ListTaxiTypes handles GET /taxi-types
+// @Summary List taxi types
+// @Description The taxi types the service runs, in the order clients should show them, with their name, seats and icon. Every type can be searched for and requested; with fleetId, only the types drivers of that fleet may register with under its validation rules.
+// @Tags taxi-types
+// @Produce json
+// @Param fleetId query string false "Only list the types this fleet's drivers may register with" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} taxitype.Catalog "Taxi types" example({"taxiTypes":[{"id":"sari","name":"Sarı Taksi","capacity":4,"icon":"taxi-yellow"},{"id":"turkuaz","name":"Turkuaz Taksi","capacity":4,"icon":"taxi-turquoise"},{"id":"siyah","name":"Siyah Taksi","capacity":4,"icon":"taxi-black"}]})
+// @Router /taxi-types [get]
+func (h *TaxiTypeHandler) ListTaxiTypes(c *gin.Context) {
+	if fleetID := c.Query("fleetId"); fleetID != "" {
+		c.JSON(http.StatusOK, h.registry.Catalog(h.rules.For(fleetID).TaxiTypes))
+		return
+	}
+	c.JSON(http.StatusOK, h.registry.Catalog(nil))
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TelemetryStatsSource reports what the MQTT bridge received
+type TelemetryStatsSource interface {
+	Stats() domain.TelemetryStats
+}
+
+// TelemetryHandler exposes the MQTT bridge for driver telemetry
+type TelemetryHandler struct {
+	// source is nil when MQTT_ENABLED is off
+	source TelemetryStatsSource
+	logger *zap.Logger
+}
+
+// NewTelemetryHandler creates a new telemetry handler
+func NewTelemetryHandler(source TelemetryStatsSource, logger *zap.Logger) *TelemetryHandler {
+	return &TelemetryHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetTelemetryStats handles GET /admin/telemetry
+// @Summary Report MQTT telemetry metrics
+// @Description Whether the MQTT bridge is connected to the broker, the topics it subscribes to, and the location and heartbeat messages it received, applied, rejected (by reason) or left for redelivery since the service started
+// @Tags admin
+// @Produce json
+// @Success 200 {object} domain.TelemetryStats "Telemetry metrics"
+// @Failure 404 {object} ErrorResponse "MQTT bridge disabled" example({"error":{"code":"NOT_FOUND","message":"MQTT bridge is disabled"}})
+// @Router /admin/telemetry [get]
+func (h *TelemetryHandler) GetTelemetryStats(c *gin.Context) {
+	if h.source == nil {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "MQTT bridge is disabled")
+		return
+	}
+	c.JSON(http.StatusOK, h.source.Stats())
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TripHandler handles HTTP requests for trips and driver offers
+type TripHandler struct {
+	useCase usecase.TripUseCase
+	logger  *zap.Logger
+}
+
+// NewTripHandler creates a new trip handler
+func NewTripHandler(useCase usecase.TripUseCase, logger *zap.Logger) *TripHandler {
+	return &TripHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// RequestTrip handles POST /trips
+// @Summary Request a trip
+// @Description Create a ride request and offer it to a driver selected by the configured matching strategy.
+// @Description The trip status is "offered" when a driver was found and "no_driver_found" otherwise.
+// @Description A riderId, when given, must belong to a registered rider.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param trip body usecase.CreateTripRequest true "Ride request"
+// @Success 201 {object} domain.Trip "Trip created"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"pickup location is required"}})
+// @Failure 403 {object} ErrorResponse "Rider requesting for another rider" example({"error":{"code":"FORBIDDEN","message":"riders can only request trips for themselves"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create trip"}})
+// @Router /trips [post]
+func (h *TripHandler) RequestTrip(c *gin.Context) {
+	var req usecase.CreateTripRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.TaxiType != nil && !req.TaxiType.IsValid() {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid taxiType. Must be one of: "+domain.TaxiTypeList())
+		return
+	}
+
+	var trip *domain.Trip
+	trip, err := h.useCase.RequestTrip(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create trip")
+		return
+	}
+
+	c.JSON(http.StatusCreated, trip)
+}
+
+// EstimateFare handles POST /trips/estimate
+// @Summary Estimate a fare
+// @Description Quote the fare from pickup to dropoff without requesting a trip. The route is measured by the configured routing provider (straight line or OSRM road distance).
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param route body usecase.EstimateFareRequest true "Pickup and dropoff"
+// @Success 200 {object} domain.FareEstimate "Fare estimate"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"dropoff location is required"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to estimate fare"}})
+// @Router /trips/estimate [post]
+func (h *TripHandler) EstimateFare(c *gin.Context) {
+	var req usecase.EstimateFareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.TaxiType != nil && !req.TaxiType.IsValid() {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid taxiType. Must be one of: "+domain.TaxiTypeList())
+		return
+	}
+
+	estimate, err := h.useCase.EstimateFare(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to estimate fare")
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetTrip handles GET /trips/:id
+// @Summary Get trip
+// @Description Get a trip and its current offer by ID
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Success 200 {object} domain.Trip "Trip found"
+// @Failure 403 {object} ErrorResponse "Another rider's trip" example({"error":{"code":"FORBIDDEN","message":"trip does not belong to you"}})
+// @Failure 404 {object} ErrorResponse "Trip not found" example({"error":{"code":"NOT_FOUND","message":"trip not found"}})
+// @Router /trips/{id} [get]
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	trip, err := h.useCase.GetTrip(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// AcceptOffer handles POST /trips/:id/accept
+// @Summary Accept trip offer
+// @Description Accept the trip offered to the driver. Fails once the offer has expired or moved on.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body usecase.TripOfferRequest true "Driver answering the offer"
+// @Success 200 {object} domain.Trip "Trip accepted"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"driverId is required"}})
+// @Failure 404 {object} ErrorResponse "Trip not found" example({"error":{"code":"NOT_FOUND","message":"trip not found"}})
+// @Failure 409 {object} ErrorResponse "No active offer" example({"error":{"code":"CONFLICT","message":"trip has no active offer for this driver"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Router /trips/{id}/accept [post]
+func (h *TripHandler) AcceptOffer(c *gin.Context) {
+	var req usecase.TripOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "driverId is required")
+		return
+	}
+
+	trip, err := h.useCase.AcceptOffer(c.Request.Context(), c.Param("id"), req.DriverID)
+	if err != nil {
+		h.handleError(c, err, "failed to accept trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// DeclineOffer handles POST /trips/:id/decline
+// @Summary Decline trip offer
+// @Description Decline the trip offered to the driver; the trip is re-offered to the next driver
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body usecase.TripOfferRequest true "Driver answering the offer"
+// @Success 200 {object} domain.Trip "Trip re-offered"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"driverId is required"}})
+// @Failure 404 {object} ErrorResponse "Trip not found" example({"error":{"code":"NOT_FOUND","message":"trip not found"}})
+// @Failure 409 {object} ErrorResponse "No active offer" example({"error":{"code":"CONFLICT","message":"trip has no active offer for this driver"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Router /trips/{id}/decline [post]
+func (h *TripHandler) DeclineOffer(c *gin.Context) {
+	var req usecase.TripOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "driverId is required")
+		return
+	}
+
+	trip, err := h.useCase.DeclineOffer(c.Request.Context(), c.Param("id"), req.DriverID)
+	if err != nil {
+		h.handleError(c, err, "failed to decline trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// CompleteTrip handles POST /trips/:id/complete
+// @Summary Complete trip
+// @Description Finish an accepted trip, optionally rating the driver from 1 to 5. The fare is the metered fare when given, otherwise the driven distance priced with the tariff or the quote; the driver is credited with it less commission.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body usecase.CompleteTripRequest false "Trip summary"
+// @Success 200 {object} domain.Trip "Trip completed"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"rating must be between 1 and 5"}})
+// @Failure 404 {object} ErrorResponse "Trip not found" example({"error":{"code":"NOT_FOUND","message":"trip not found"}})
+// @Failure 409 {object} ErrorResponse "Trip not accepted" example({"error":{"code":"CONFLICT","message":"trip status does not allow this action"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Router /trips/{id}/complete [post]
+func (h *TripHandler) CompleteTrip(c *gin.Context) {
+	var req usecase.CompleteTripRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+	}
+
+	trip, err := h.useCase.CompleteTrip(c.Request.Context(), c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to complete trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// CancelTrip handles POST /trips/:id/cancel
+// @Summary Cancel trip
+// @Description Cancel a trip that has not finished; an assigned driver becomes available again
+// @Tags trips
+// @Produce json
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Success 200 {object} domain.Trip "Trip cancelled"
+// @Failure 403 {object} ErrorResponse "Another rider's trip" example({"error":{"code":"FORBIDDEN","message":"trip does not belong to you"}})
+// @Failure 404 {object} ErrorResponse "Trip not found" example({"error":{"code":"NOT_FOUND","message":"trip not found"}})
+// @Failure 409 {object} ErrorResponse "Trip already finished" example({"error":{"code":"CONFLICT","message":"trip status does not allow this action"}})
+// @Router /trips/{id}/cancel [post]
+func (h *TripHandler) CancelTrip(c *gin.Context) {
+	trip, err := h.useCase.CancelTrip(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to cancel trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// handleError maps trip use case errors to HTTP responses
+func (h *TripHandler) handleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, usecase.ErrTripNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrTripNotOwned), errors.Is(err, usecase.ErrRiderMismatch), isForbiddenError(err):
+		respondError(c, http.StatusForbidden, "FORBIDDEN", err.Error())
+	case errors.Is(err, usecase.ErrOfferNotActive),
+		errors.Is(err, usecase.ErrInvalidTripState),
+		errors.Is(err, usecase.ErrTripConflict):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	case errors.Is(err, usecase.ErrPickupRequired),
+		errors.Is(err, usecase.ErrDropoffRequired),
+		errors.Is(err, usecase.ErrInvalidRating),
+		errors.Is(err, usecase.ErrInvalidDistance),
+		errors.Is(err, usecase.ErrInvalidFare),
+		errors.Is(err, usecase.ErrRiderNotFound),
+		isValidationError(err):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, fallback)
+	}
+}
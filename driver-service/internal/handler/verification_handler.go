@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// VerificationHandler handles HTTP requests for driver contact verification
+// and rider sign-in by phone
+type VerificationHandler struct {
+	useCase usecase.VerificationUseCase
+	logger  *zap.Logger
+}
+
+// NewVerificationHandler creates a new verification handler
+func NewVerificationHandler(useCase usecase.VerificationUseCase, logger *zap.Logger) *VerificationHandler {
+	return &VerificationHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// SendPhoneCode handles POST /drivers/:id/verify-phone/send
+// @Summary Send phone verification code
+// @Description Send a one-time verification code to the driver's phone via SMS
+// @Tags verification
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 202 {object} map[string]string "Code sent" example({"status":"sent"})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Phone missing or already verified" example({"error":{"code":"CONFLICT","message":"phone is already verified"}})
+// @Failure 429 {object} ErrorResponse "Code sent recently" example({"error":{"code":"RATE_LIMIT_EXCEEDED","message":"verification code was sent recently, try again later"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to send verification code"}})
+// @Router /drivers/{id}/verify-phone/send [post]
+func (h *VerificationHandler) SendPhoneCode(c *gin.Context) {
+	id := c.Param("id")
+
+	err := h.useCase.SendPhoneCode(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found":
+			respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+		case errors.Is(err, usecase.ErrPhoneMissing), errors.Is(err, usecase.ErrPhoneAlreadyVerified):
+			respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+		case errors.Is(err, usecase.ErrVerificationSendLimit):
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to send verification code")
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "sent"})
+}
+
+// VerifyPhone handles POST /drivers/:id/verify-phone
+// @Summary Verify phone
+// @Description Confirm the one-time code sent to the driver's phone and mark the phone as verified
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body usecase.VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} domain.Driver "Phone verified"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code" example({"error":{"code":"INVALID_CODE","message":"invalid verification code"}})
+// @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 429 {object} ErrorResponse "Too many attempts" example({"error":{"code":"TOO_MANY_ATTEMPTS","message":"too many verification attempts, request a new code"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to verify phone"}})
+// @Router /drivers/{id}/verify-phone [post]
+func (h *VerificationHandler) VerifyPhone(c *gin.Context) {
+	id := c.Param("id")
+
+	var req usecase.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "code is required")
+		return
+	}
+
+	var driver *domain.Driver
+	driver, err := h.useCase.VerifyPhone(c.Request.Context(), id, req.Code)
+	if err != nil {
+		switch {
+		case err.Error() == "driver not found":
+			respondError(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+		case errors.Is(err, usecase.ErrInvalidCode),
+			errors.Is(err, usecase.ErrVerificationExpired),
+			errors.Is(err, usecase.ErrVerificationNotFound):
+			respondError(c, http.StatusBadRequest, "INVALID_CODE", err.Error())
+		case errors.Is(err, usecase.ErrTooManyAttempts):
+			respondError(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to verify phone")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, driver)
+}
+
+// SendRiderSignInCode handles POST /riders/sign-in/send
+// @Summary Send rider sign-in code
+// @Description Send a one-time sign-in code via SMS to the rider registered with the phone. Unknown phones get the same answer, so the response does not tell whether the phone is registered.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param request body usecase.RiderSignInCodeRequest true "Rider phone"
+// @Success 202 {object} map[string]string "Code sent if the phone is registered" example({"status":"sent"})
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"phone is required"}})
+// @Failure 429 {object} ErrorResponse "Code sent recently" example({"error":{"code":"RATE_LIMIT_EXCEEDED","message":"verification code was sent recently, try again later"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to send verification code"}})
+// @Router /riders/sign-in/send [post]
+func (h *VerificationHandler) SendRiderSignInCode(c *gin.Context) {
+	var req usecase.RiderSignInCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "phone is required")
+		return
+	}
+
+	if err := h.useCase.SendRiderSignInCode(c.Request.Context(), req.Phone); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrVerificationSendLimit):
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to send verification code")
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "sent"})
+}
+
+// SignInRider handles POST /riders/sign-in
+// @Summary Sign a rider in
+// @Description Confirm the one-time code sent to the rider's phone and return the rider
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param request body usecase.RiderSignInRequest true "Rider phone and code"
+// @Success 200 {object} domain.Rider "Rider signed in"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code" example({"error":{"code":"INVALID_CODE","message":"invalid verification code"}})
+// @Failure 429 {object} ErrorResponse "Too many attempts" example({"error":{"code":"TOO_MANY_ATTEMPTS","message":"too many verification attempts, request a new code"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to sign in"}})
+// @Router /riders/sign-in [post]
+func (h *VerificationHandler) SignInRider(c *gin.Context) {
+	var req usecase.RiderSignInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "phone and code are required")
+		return
+	}
+
+	rider, err := h.useCase.SignInRider(c.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidCode),
+			errors.Is(err, usecase.ErrVerificationExpired),
+			errors.Is(err, usecase.ErrVerificationNotFound):
+			respondError(c, http.StatusBadRequest, "INVALID_CODE", err.Error())
+		case errors.Is(err, usecase.ErrTooManyAttempts):
+			respondError(c, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", err.Error())
+		default:
+			respondInternalError(c, h.logger, err, "failed to sign in")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rider)
+}
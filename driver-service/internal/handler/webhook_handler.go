@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions and their delivery log
+type WebhookHandler struct {
+	useCase usecase.WebhookUseCase
+	logger  *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(useCase usecase.WebhookUseCase, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		useCase: useCase,
+		logger:  logger,
+	}
+}
+
+// CreateWebhook handles POST /webhooks
+// @Summary Register a webhook endpoint
+// @Description Subscribe an endpoint to driver events (driver.created, driver.updated, driver.suspended, driver.license_expired). Deliveries are signed with HMAC-SHA256 in the X-Bitaksi-Signature header and retried with exponential backoff. The secret is only returned in this response. The url must use https outside debug mode and must not point to a loopback, private or link-local address.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body usecase.CreateWebhookRequest true "Subscription"
+// @Success 201 {object} domain.WebhookSubscription "Subscription created"
+// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"webhook url must be an absolute http(s) URL"}})
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to create webhook subscription"}})
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req usecase.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	var sub *domain.WebhookSubscription
+	sub, err := h.useCase.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err, "failed to create webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListWebhooks handles GET /webhooks
+// @Summary List webhook subscriptions
+// @Description List webhook subscriptions, optionally of one fleet. Secrets are not included.
+// @Tags webhooks
+// @Produce json
+// @Param fleetId query string false "Only list subscriptions of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {array} domain.WebhookSubscription "Subscriptions"
+// @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to list webhook subscriptions"}})
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	subs, err := h.useCase.ListSubscriptions(c.Request.Context(), c.Query("fleetId"))
+	if err != nil {
+		h.handleError(c, err, "failed to list webhook subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, subs)
+}
+
+// GetWebhook handles GET /webhooks/:id
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription by ID. The secret is not included.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} domain.WebhookSubscription "Subscription"
+// @Failure 404 {object} ErrorResponse "Subscription not found" example({"error":{"code":"NOT_FOUND","message":"webhook subscription not found"}})
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	sub, err := h.useCase.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "failed to get webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+// @Summary Delete a webhook subscription
+// @Description Remove a subscription; its pending deliveries are not retried
+// @Tags webhooks
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Success 204 "Subscription deleted"
+// @Failure 404 {object} ErrorResponse "Subscription not found" example({"error":{"code":"NOT_FOUND","message":"webhook subscription not found"}})
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.useCase.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		h.handleError(c, err, "failed to delete webhook subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+// @Summary List webhook deliveries
+// @Description Delivery log of a subscription, newest first, with attempts, last response status and next retry. Deliveries are kept for 30 days.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Param limit query int false "Maximum deliveries to return (max 500)" default(50)
+// @Success 200 {array} domain.WebhookDelivery "Deliveries"
+// @Failure 400 {object} ErrorResponse "Invalid limit" example({"error":{"code":"VALIDATION_ERROR","message":"limit must be a positive integer"}})
+// @Failure 404 {object} ErrorResponse "Subscription not found" example({"error":{"code":"NOT_FOUND","message":"webhook subscription not found"}})
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.useCase.ListDeliveries(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.handleError(c, err, "failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// RetryDelivery handles POST /webhooks/:id/deliveries/:deliveryId/retry
+// @Summary Retry a webhook delivery
+// @Description Send a delivery again as soon as possible. Failed deliveries get a fresh set of attempts.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Param deliveryId path string true "Delivery ID" example("6572b1f2c3d4e5f6a7b8c9d0")
+// @Success 202 {object} domain.WebhookDelivery "Delivery rescheduled"
+// @Failure 404 {object} ErrorResponse "Delivery not found" example({"error":{"code":"NOT_FOUND","message":"webhook delivery not found"}})
+// @Failure 409 {object} ErrorResponse "Delivery in progress" example({"error":{"code":"CONFLICT","message":"webhook delivery is being sent, retry later"}})
+// @Router /webhooks/{id}/deliveries/{deliveryId}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	delivery, err := h.useCase.RetryDelivery(c.Request.Context(), c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		h.handleError(c, err, "failed to retry webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// handleError maps webhook use case errors to HTTP responses
+func (h *WebhookHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, usecase.ErrWebhookNotFound), errors.Is(err, usecase.ErrDeliveryNotFound):
+		respondError(c, http.StatusNotFound, "NOT_FOUND", err.Error())
+	case errors.Is(err, usecase.ErrInvalidWebhookURL), errors.Is(err, usecase.ErrInvalidWebhookEvent),
+		errors.Is(err, usecase.ErrWebhookHTTPSRequired), errors.Is(err, usecase.ErrWebhookPrivateTarget),
+		errors.Is(err, usecase.ErrWebhookSecretTooShort), errors.Is(err, usecase.ErrFleetNotFound):
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	case errors.Is(err, usecase.ErrDeliveryInProgress):
+		respondError(c, http.StatusConflict, "CONFLICT", err.Error())
+	default:
+		respondInternalError(c, h.logger, err, message)
+	}
+}
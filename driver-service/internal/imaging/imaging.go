@@ -0,0 +1,119 @@
+// Package imaging decodes uploaded pictures and renders the resized copies
+// that are stored and served. Every copy is re-encoded as JPEG, which also
+// drops metadata such as EXIF locations from the original.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+)
+
+// ErrUnsupportedFormat is returned for uploads that are not JPEG or PNG pictures
+var ErrUnsupportedFormat = errors.New("image must be a JPEG or PNG")
+
+// Supported formats as reported by image.DecodeConfig
+var formats = map[string]bool{"jpeg": true, "png": true}
+
+// Size reads the dimensions from the picture's header without decoding its
+// pixels, so oversized uploads can be rejected cheaply
+func Size(data []byte) (width, height int, err error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !formats[format] {
+		return 0, 0, ErrUnsupportedFormat
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Decode decodes a JPEG or PNG picture
+func Decode(data []byte) (image.Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil || !formats[format] {
+		return nil, ErrUnsupportedFormat
+	}
+	return img, nil
+}
+
+// Fit scales img down to fit within maxSide pixels per side, keeping its
+// aspect ratio. Smaller pictures are returned unchanged.
+func Fit(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		return Resize(img, b, maxSide, max(1, h*maxSide/w))
+	}
+	return Resize(img, b, max(1, w*maxSide/h), maxSide)
+}
+
+// Thumbnail crops the centre square of img and scales it to size x size
+func Thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	return Resize(img, image.Rect(x, y, x+side, y+side), size, size)
+}
+
+// Resize scales the src rectangle of img to width x height by averaging the
+// source pixels each destination pixel covers, which keeps downscaled
+// pictures free of aliasing
+func Resize(img image.Image, src image.Rectangle, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	sw, sh := src.Dx(), src.Dy()
+
+	for y := 0; y < height; y++ {
+		y0 := src.Min.Y + y*sh/height
+		y1 := max(y0+1, src.Min.Y+(y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := src.Min.X + x*sw/width
+			x1 := max(x0+1, src.Min.X+(x+1)*sw/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// EncodeJPEG encodes img at the given quality (1-100). Transparent areas of
+// PNGs are flattened onto white.
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	b := img.Bounds()
+	flat := image.NewRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, a := img.At(x, y).RGBA()
+			// Colours are alpha-premultiplied, so adding the uncovered share of white flattens them
+			white := 0xffff - a
+			flat.Set(x, y, color.RGBA64{R: uint16(r + white), G: uint16(g + white), B: uint16(bl + white), A: 0xffff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
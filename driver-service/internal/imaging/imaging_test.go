@@ -0,0 +1,92 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func solid(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestSizeAndDecode(t *testing.T) {
+	data := encodePNG(t, solid(200, 150, color.White))
+
+	width, height, err := Size(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if width != 200 || height != 150 {
+		t.Errorf("expected 200x150, got %dx%d", width, height)
+	}
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img.Bounds().Dx() != 200 || img.Bounds().Dy() != 150 {
+		t.Errorf("unexpected bounds %v", img.Bounds())
+	}
+
+	if _, _, err := Size([]byte("GIF89a not really")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := Decode(data[:len(data)/2]); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat for a truncated image, got %v", err)
+	}
+}
+
+func TestFitAndThumbnail(t *testing.T) {
+	img := solid(400, 200, color.RGBA{R: 200, G: 10, B: 10, A: 255})
+
+	fitted := Fit(img, 100)
+	if fitted.Bounds().Dx() != 100 || fitted.Bounds().Dy() != 50 {
+		t.Errorf("expected 100x50, got %v", fitted.Bounds())
+	}
+	if Fit(img, 1000) != img {
+		t.Error("expected small images to be returned unchanged")
+	}
+
+	thumb := Thumbnail(img, 64)
+	if thumb.Bounds().Dx() != 64 || thumb.Bounds().Dy() != 64 {
+		t.Errorf("expected 64x64, got %v", thumb.Bounds())
+	}
+	r, g, b, _ := thumb.At(32, 32).RGBA()
+	if r>>8 != 200 || g>>8 != 10 || b>>8 != 10 {
+		t.Errorf("expected colour to be preserved, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestEncodeJPEG_FlattensTransparency(t *testing.T) {
+	data, err := EncodeJPEG(solid(16, 16, color.Transparent), 90)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a JPEG, got %v", err)
+	}
+	r, g, b, _ := img.At(8, 8).RGBA()
+	if r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("expected transparent pixels to become white, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
@@ -0,0 +1,433 @@
+// Package jobrunner runs long tasks, such as driver exports and imports, in
+// the background so they do not have to finish within one request.
+//
+// Submitted jobs wait in a bounded queue for one of a fixed number of workers.
+// A task reports its progress and may write a result file, which is kept with
+// the job for the retention period after the job finishes. Jobs are kept in
+// memory: their status and results are only known to the instance that ran
+// them, and jobs that were queued or running are lost on restart.
+package jobrunner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/pkg/panics"
+	"go.uber.org/zap"
+)
+
+// Status is the state of a job
+type Status string
+
+// Job statuses
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Errors returned by the runner
+var (
+	ErrNotFound  = errors.New("job not found")
+	ErrQueueFull = errors.New("too many jobs are waiting, retry later")
+	ErrNoResult  = errors.New("job has no result")
+)
+
+// Options configures a Runner
+type Options struct {
+	// Dir is where result files are written
+	Dir string
+	// Workers is how many jobs run at once
+	Workers int
+	// QueueSize is how many jobs may wait for a worker
+	QueueSize int
+	// Retention is how long a finished job and its result are kept
+	Retention time.Duration
+}
+
+// Result describes the file a job produced
+type Result struct {
+	FileName    string `json:"fileName" example:"drivers-20251206-010000.ndjson"`
+	ContentType string `json:"contentType" example:"application/x-ndjson"`
+	Size        int64  `json:"size" example:"48210331"`
+}
+
+// Job is a background task and its progress
+type Job struct {
+	ID   string `json:"id" example:"job-9f86d081884c7d65"`
+	Kind string `json:"kind" example:"export"`
+	// Status is queued, running, completed or failed
+	Status Status `json:"status" example:"running" enums:"queued,running,completed,failed"`
+	// Owner is the tenant whose data the job covers; empty for all tenants
+	Owner string `json:"owner,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// Actor identifies who started the job, for the audit log
+	Actor string `json:"actor,omitempty" example:"ops@bitaksi.com"`
+	// Total is how many items the job expects to process; 0 until it knows
+	Total     int64 `json:"total" example:"1000000"`
+	Processed int64 `json:"processed" example:"420000"`
+	// Changed counts processed items that were modified while the job ran
+	Changed int64 `json:"changed,omitempty" example:"12"`
+	// Error tells why a failed job failed
+	Error      string     `json:"error,omitempty" example:"failed to list drivers"`
+	Result     *Result    `json:"result,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	StartedAt  *time.Time `json:"startedAt,omitempty" example:"2025-12-06T01:00:01Z"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty" example:"2025-12-06T01:04:10Z"`
+	// ExpiresAt is when a finished job and its result are deleted
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2025-12-07T01:04:10Z"`
+}
+
+// Task does the work of a job. It reports progress and writes its result
+// through run, and should stop when ctx is done.
+type Task func(ctx context.Context, run *Run) error
+
+// entry is a job with what the runner needs to run and clean it up
+type entry struct {
+	job  *Job
+	task Task
+	// path is the result file, once the task has created one
+	path string
+}
+
+// Runner queues and runs jobs
+type Runner struct {
+	opts   Options
+	logger *zap.Logger
+	now    func() time.Time
+	queue  chan *entry
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+}
+
+// New creates a runner writing results to opts.Dir, which is created if
+// missing. Workers defaults to 2, QueueSize to 100 and Retention to 24 hours.
+func New(opts Options, logger *zap.Logger) (*Runner, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = 24 * time.Hour
+	}
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create job directory: %w", err)
+	}
+	return &Runner{
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+		queue:  make(chan *entry, opts.QueueSize),
+		jobs:   make(map[string]*entry),
+	}, nil
+}
+
+// Submit queues a job of the given kind and returns it
+func (r *Runner) Submit(kind, owner, actor string, task Task) (*Job, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	e := &entry{
+		job: &Job{
+			ID:        id,
+			Kind:      kind,
+			Status:    StatusQueued,
+			Owner:     owner,
+			Actor:     actor,
+			CreatedAt: r.now().UTC(),
+		},
+		task: task,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	select {
+	case r.queue <- e:
+	default:
+		return nil, ErrQueueFull
+	}
+	r.jobs[id] = e
+	return copyJob(e.job), nil
+}
+
+// Get returns a job by ID
+func (r *Runner) Get(id string) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyJob(e.job), nil
+}
+
+// List returns the jobs of a kind, newest first; an empty kind lists all
+func (r *Runner) List(kind string) []*Job {
+	r.mu.Lock()
+	jobs := make([]*Job, 0, len(r.jobs))
+	for _, e := range r.jobs {
+		if kind == "" || e.job.Kind == kind {
+			jobs = append(jobs, copyJob(e.job))
+		}
+	}
+	r.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	return jobs
+}
+
+// Open opens the result file of a completed job. The caller closes it.
+func (r *Runner) Open(id string) (*os.File, *Job, error) {
+	r.mu.Lock()
+	e, ok := r.jobs[id]
+	if !ok {
+		r.mu.Unlock()
+		return nil, nil, ErrNotFound
+	}
+	job, path := copyJob(e.job), e.path
+	r.mu.Unlock()
+
+	if job.Status != StatusCompleted || job.Result == nil {
+		return nil, job, ErrNoResult
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, job, fmt.Errorf("failed to open job result: %w", err)
+	}
+	return f, job, nil
+}
+
+// Run starts the workers and removes expired jobs until ctx is done. Jobs
+// still running then are cancelled and marked failed.
+func (r *Runner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < r.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case e := <-r.queue:
+					r.run(ctx, e)
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			r.sweep()
+		}
+	}
+}
+
+// run runs one job to its end
+func (r *Runner) run(ctx context.Context, e *entry) {
+	r.mu.Lock()
+	started := r.now().UTC()
+	e.job.Status = StatusRunning
+	e.job.StartedAt = &started
+	r.mu.Unlock()
+
+	r.logger.Info("job started", zap.String("jobId", e.job.ID), zap.String("kind", e.job.Kind))
+	run := &Run{runner: r, entry: e}
+	err := r.runTask(ctx, e, run)
+	if closeErr := run.close(); err == nil {
+		err = closeErr
+	}
+
+	r.mu.Lock()
+	finished := r.now().UTC()
+	expires := finished.Add(r.opts.Retention)
+	e.job.FinishedAt = &finished
+	e.job.ExpiresAt = &expires
+	if err != nil {
+		e.job.Status = StatusFailed
+		e.job.Error = err.Error()
+		e.job.Result = nil
+	} else {
+		e.job.Status = StatusCompleted
+	}
+	job := copyJob(e.job)
+	r.mu.Unlock()
+
+	if err != nil {
+		r.removeResult(e)
+		r.logger.Error("job failed", zap.String("jobId", job.ID), zap.String("kind", job.Kind), zap.Error(err))
+		return
+	}
+	r.logger.Info("job completed",
+		zap.String("jobId", job.ID),
+		zap.String("kind", job.Kind),
+		zap.Int64("processed", job.Processed),
+		zap.Duration("took", finished.Sub(started)),
+	)
+}
+
+// runTask runs the task of a job, failing the job instead of the service when
+// the task panics
+func (r *Runner) runTask(ctx context.Context, e *entry, run *Run) (err error) {
+	defer func() {
+		value := recover()
+		if value == nil {
+			return
+		}
+		report := panics.Capture(value)
+		r.logger.Error("job panicked",
+			zap.String("jobId", e.job.ID),
+			zap.String("kind", e.job.Kind),
+			zap.String("fingerprint", report.Fingerprint),
+			zap.String("culprit", report.Culprit()),
+			zap.String("panic", fmt.Sprint(value)),
+			zap.ByteString("stack", report.Stack),
+		)
+		err = fmt.Errorf("job panicked: %v", value)
+	}()
+	return e.task(ctx, run)
+}
+
+// sweep removes finished jobs past their retention and their results
+func (r *Runner) sweep() {
+	now := r.now()
+	r.mu.Lock()
+	var expired []*entry
+	for id, e := range r.jobs {
+		if e.job.ExpiresAt != nil && !now.Before(*e.job.ExpiresAt) {
+			expired = append(expired, e)
+			delete(r.jobs, id)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range expired {
+		r.removeResult(e)
+	}
+}
+
+func (r *Runner) removeResult(e *entry) {
+	if e.path == "" {
+		return
+	}
+	if err := os.Remove(e.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		r.logger.Warn("failed to remove job result", zap.String("jobId", e.job.ID), zap.Error(err))
+	}
+}
+
+// Run is a task's handle on its job
+type Run struct {
+	runner *Runner
+	entry  *entry
+	file   *os.File
+	out    *countingWriter
+}
+
+// SetTotal sets how many items the job expects to process
+func (run *Run) SetTotal(total int64) {
+	run.runner.mu.Lock()
+	defer run.runner.mu.Unlock()
+	run.entry.job.Total = total
+}
+
+// Add counts processed items
+func (run *Run) Add(n int64) {
+	run.runner.mu.Lock()
+	defer run.runner.mu.Unlock()
+	run.entry.job.Processed += n
+}
+
+// AddChanged counts processed items that were modified while the job ran
+func (run *Run) AddChanged(n int64) {
+	run.runner.mu.Lock()
+	defer run.runner.mu.Unlock()
+	run.entry.job.Changed += n
+}
+
+// Output creates the result file of the job, offered for download as
+// fileName. A task creates at most one.
+func (run *Run) Output(fileName, contentType string) (io.Writer, error) {
+	if run.file != nil {
+		return nil, errors.New("job result already created")
+	}
+	path := filepath.Join(run.runner.opts.Dir, run.entry.job.ID)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job result: %w", err)
+	}
+	run.file = f
+	run.out = &countingWriter{w: f}
+
+	run.runner.mu.Lock()
+	defer run.runner.mu.Unlock()
+	run.entry.path = path
+	run.entry.job.Result = &Result{FileName: fileName, ContentType: contentType}
+	return run.out, nil
+}
+
+// close closes the result file and records its size
+func (run *Run) close() error {
+	if run.file == nil {
+		return nil
+	}
+	err := run.file.Close()
+
+	run.runner.mu.Lock()
+	defer run.runner.mu.Unlock()
+	run.entry.job.Result.Size = run.out.n
+	if err != nil {
+		return fmt.Errorf("failed to write job result: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// newID returns a random job ID
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate job ID: %w", err)
+	}
+	return "job-" + hex.EncodeToString(b), nil
+}
+
+// copyJob copies a job so callers never read it while the runner updates it
+func copyJob(job *Job) *Job {
+	c := *job
+	if job.Result != nil {
+		result := *job.Result
+		c.Result = &result
+	}
+	return &c
+}
@@ -0,0 +1,216 @@
+package jobrunner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestRunner(t *testing.T, opts Options) *Runner {
+	t.Helper()
+	opts.Dir = t.TempDir()
+	r, err := New(opts, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return r
+}
+
+// waitFinished polls a job until it completes or fails
+func waitFinished(t *testing.T, r *Runner, id string) *Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := r.Get(id)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Status == StatusCompleted || job.Status == StatusFailed {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return nil
+}
+
+func TestRunner_CompletesWithResult(t *testing.T) {
+	r := newTestRunner(t, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	job, err := r.Submit("export", "fleet-1", "ops", func(ctx context.Context, run *Run) error {
+		run.SetTotal(3)
+		out, err := run.Output("drivers.ndjson", "application/x-ndjson")
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 3; i++ {
+			out.Write([]byte("{}\n"))
+			run.Add(1)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != StatusQueued || job.Owner != "fleet-1" || job.Actor != "ops" {
+		t.Errorf("unexpected queued job: %+v", job)
+	}
+
+	job = waitFinished(t, r, job.ID)
+	if job.Status != StatusCompleted {
+		t.Fatalf("expected completed, got %s (%s)", job.Status, job.Error)
+	}
+	if job.Total != 3 || job.Processed != 3 {
+		t.Errorf("expected 3/3 processed, got %d/%d", job.Processed, job.Total)
+	}
+	if job.Result == nil || job.Result.Size != 9 || job.Result.FileName != "drivers.ndjson" {
+		t.Errorf("unexpected result: %+v", job.Result)
+	}
+	if job.ExpiresAt == nil || job.ExpiresAt.Sub(*job.FinishedAt) != 24*time.Hour {
+		t.Errorf("expected the job to expire 24h after it finished")
+	}
+
+	f, _, err := r.Open(job.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	data, _ := io.ReadAll(f)
+	if string(data) != "{}\n{}\n{}\n" {
+		t.Errorf("unexpected result file: %q", data)
+	}
+
+	if jobs := r.List("export"); len(jobs) != 1 {
+		t.Errorf("expected 1 export job, got %d", len(jobs))
+	}
+	if jobs := r.List("import"); len(jobs) != 0 {
+		t.Errorf("expected no import jobs, got %d", len(jobs))
+	}
+}
+
+func TestRunner_FailedJobDropsResult(t *testing.T) {
+	r := newTestRunner(t, Options{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	job, err := r.Submit("export", "", "", func(ctx context.Context, run *Run) error {
+		out, err := run.Output("drivers.json", "application/json")
+		if err != nil {
+			return err
+		}
+		out.Write([]byte("["))
+		return errors.New("failed to list drivers")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	job = waitFinished(t, r, job.ID)
+	if job.Status != StatusFailed || job.Error != "failed to list drivers" {
+		t.Errorf("unexpected failed job: %+v", job)
+	}
+	if job.Result != nil {
+		t.Errorf("expected no result for a failed job")
+	}
+	if _, _, err := r.Open(job.ID); !errors.Is(err, ErrNoResult) {
+		t.Errorf("expected ErrNoResult, got %v", err)
+	}
+	entries, _ := os.ReadDir(r.opts.Dir)
+	if len(entries) != 0 {
+		t.Errorf("expected the partial result to be removed, found %d files", len(entries))
+	}
+}
+
+func TestRunner_PanickingJobFails(t *testing.T) {
+	r := newTestRunner(t, Options{Workers: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	job, err := r.Submit("import", "", "", func(ctx context.Context, run *Run) error {
+		var rows []string
+		_ = rows[3]
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job = waitFinished(t, r, job.ID)
+	if job.Status != StatusFailed || !strings.HasPrefix(job.Error, "job panicked: runtime error: index out of range") {
+		t.Errorf("unexpected failed job: %+v", job)
+	}
+
+	// The worker survives to run the next job
+	job, err = r.Submit("import", "", "", func(ctx context.Context, run *Run) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job = waitFinished(t, r, job.ID); job.Status != StatusCompleted {
+		t.Errorf("unexpected job after a panic: %+v", job)
+	}
+}
+
+func TestRunner_QueueFull(t *testing.T) {
+	// Without Run nothing leaves the queue
+	r := newTestRunner(t, Options{QueueSize: 1})
+	noop := func(ctx context.Context, run *Run) error { return nil }
+
+	job, err := r.Submit("export", "", "", noop)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.Submit("export", "", "", noop); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	if _, _, err := r.Open(job.ID); !errors.Is(err, ErrNoResult) {
+		t.Errorf("expected ErrNoResult for a queued job, got %v", err)
+	}
+	if _, err := r.Get("job-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRunner_SweepRemovesExpiredJobs(t *testing.T) {
+	r := newTestRunner(t, Options{Retention: time.Hour})
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	job, err := r.Submit("export", "", "", func(ctx context.Context, run *Run) error {
+		out, err := run.Output("drivers.ndjson", "application/x-ndjson")
+		if err != nil {
+			return err
+		}
+		_, err = out.Write([]byte("{}\n"))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r.run(context.Background(), <-r.queue)
+
+	now = now.Add(59 * time.Minute)
+	r.sweep()
+	if _, err := r.Get(job.ID); err != nil {
+		t.Fatalf("expected the job to be kept, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	r.sweep()
+	if _, err := r.Get(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected the job to expire, got %v", err)
+	}
+	entries, _ := os.ReadDir(r.opts.Dir)
+	if len(entries) != 0 {
+		t.Errorf("expected the result to be removed, found %d files", len(entries))
+	}
+}
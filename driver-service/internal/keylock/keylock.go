@@ -0,0 +1,63 @@
+// Package keylock keeps concurrent requests of one process from working on the
+// same key at once, e.g. two registrations of the same plate racing between
+// the uniqueness check and the insert it guards. Callers that lose the race
+// are turned away rather than queued, so a retried request is answered at once.
+package keylock
+
+import "sync"
+
+// Locker holds the keys taken by requests in flight
+type Locker struct {
+	mu   sync.Mutex
+	held map[string]struct{}
+}
+
+// New creates a locker with no keys held
+func New() *Locker {
+	return &Locker{held: make(map[string]struct{})}
+}
+
+// TryLock takes every key or none of them. ok is false when another caller
+// holds one of the keys; otherwise unlock releases them and must be called
+// once the guarded work is done. Empty keys are ignored.
+func (l *Locker) TryLock(keys ...string) (unlock func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	taken := make([]string, 0, len(keys))
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		if _, busy := l.held[key]; busy {
+			return nil, false
+		}
+		seen[key] = struct{}{}
+		taken = append(taken, key)
+	}
+	for _, key := range taken {
+		l.held[key] = struct{}{}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			for _, key := range taken {
+				delete(l.held, key)
+			}
+		})
+	}, true
+}
+
+// Held returns the number of keys currently taken
+func (l *Locker) Held() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.held)
+}
@@ -0,0 +1,77 @@
+package keylock
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLocker_TryLock(t *testing.T) {
+	l := New()
+
+	unlock, ok := l.TryLock("plate:34ABC123", "", "phone:+905321234567", "plate:34ABC123")
+	if !ok {
+		t.Fatal("expected the first caller to take the keys")
+	}
+	if l.Held() != 2 {
+		t.Errorf("Held() = %d, want 2", l.Held())
+	}
+
+	// One held key turns the whole set away and takes none of the others
+	if _, ok := l.TryLock("email:ahmet@example.com", "phone:+905321234567"); ok {
+		t.Error("expected a caller sharing a held key to be turned away")
+	}
+	if l.Held() != 2 {
+		t.Errorf("Held() = %d after a refused lock, want 2", l.Held())
+	}
+	other, ok := l.TryLock("email:ahmet@example.com")
+	if !ok {
+		t.Fatal("expected a caller with other keys to proceed")
+	}
+	other()
+
+	unlock()
+	unlock()
+	if l.Held() != 0 {
+		t.Errorf("Held() = %d after unlocking, want 0", l.Held())
+	}
+	if again, ok := l.TryLock("plate:34ABC123"); !ok {
+		t.Error("expected a released key to be taken again")
+	} else {
+		again()
+	}
+}
+
+func TestLocker_Concurrent(t *testing.T) {
+	l := New()
+	var attempts, holders sync.WaitGroup
+	var won atomic.Int32
+	done := make(chan struct{})
+
+	// Every caller tries while the winner still holds the key
+	for i := 0; i < 20; i++ {
+		attempts.Add(1)
+		holders.Add(1)
+		go func() {
+			defer holders.Done()
+			unlock, ok := l.TryLock("plate:34ABC123")
+			attempts.Done()
+			if !ok {
+				return
+			}
+			won.Add(1)
+			<-done
+			unlock()
+		}()
+	}
+	attempts.Wait()
+	close(done)
+	holders.Wait()
+
+	if won.Load() != 1 {
+		t.Errorf("expected exactly one caller to take the key, got %d", won.Load())
+	}
+	if l.Held() != 0 {
+		t.Errorf("Held() = %d after unlocking, want 0", l.Held())
+	}
+}
@@ -0,0 +1,262 @@
+// Package kyc connects driver identity verification to KYC providers.
+//
+// A provider receives the driver's identity documents, decides asynchronously
+// and reports the decision either when polled with Check or by calling the
+// inbound webhook. Webhook calls carry an X-KYC-Signature header signed like
+// outgoing webhooks, "t=<unix seconds>,v1=<hex HMAC-SHA256>" over "<t>.<body>"
+// with the shared webhook secret, and a JSON body
+// {"reference":"...","status":"approved|rejected|pending","reason":"..."}.
+package kyc
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/webhook"
+)
+
+// Provider names accepted by NewProvider
+const (
+	ProviderSandbox = "sandbox"
+	ProviderHTTP    = "http"
+)
+
+// SignatureHeader carries the signature of inbound webhook calls
+const SignatureHeader = "X-KYC-Signature"
+
+// signatureTolerance is how old a webhook signature may be
+const signatureTolerance = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned for webhook calls that are unsigned,
+	// wrongly signed or too old
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnknownReference is returned for checks the provider does not know
+	ErrUnknownReference = errors.New("unknown check reference")
+	// ErrInvalidWebhookBody is returned for signed webhook calls whose body is
+	// not a decision
+	ErrInvalidWebhookBody = errors.New("invalid webhook body")
+)
+
+// Submission is what a provider needs to check a driver's identity
+type Submission struct {
+	DriverID  string                  `json:"driverId"`
+	FirstName string                  `json:"firstName"`
+	LastName  string                  `json:"lastName"`
+	Documents []domain.DriverDocument `json:"documents"`
+}
+
+// Decision is a provider's verdict on a check; pending means not decided yet
+type Decision struct {
+	Reference string           `json:"reference"`
+	Status    domain.KYCStatus `json:"status"`
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// Provider checks driver identities
+type Provider interface {
+	Name() string
+	// Submit starts a check and returns its reference at the provider
+	Submit(ctx context.Context, submission *Submission) (string, error)
+	// Check returns the current state of a check
+	Check(ctx context.Context, reference string) (*Decision, error)
+}
+
+// Options configures the providers
+type Options struct {
+	// URL is the base URL of the http provider's API
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// NewProvider creates the provider with the given name
+func NewProvider(name string, opts Options) (Provider, error) {
+	switch name {
+	case ProviderSandbox, "":
+		return NewSandboxProvider(), nil
+	case ProviderHTTP:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("a URL is required for the %s KYC provider", ProviderHTTP)
+		}
+		return NewHTTPProvider(opts.URL, opts.APIKey, opts.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown KYC provider %q", name)
+	}
+}
+
+// ParseWebhook verifies the signature of an inbound webhook call and returns
+// the decision it carries
+func ParseWebhook(secret string, header http.Header, body []byte, now time.Time) (*Decision, error) {
+	if secret == "" || !webhook.Verify(secret, header.Get(SignatureHeader), body, signatureTolerance, now) {
+		return nil, ErrInvalidSignature
+	}
+	var decision Decision
+	if err := json.Unmarshal(body, &decision); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookBody, err)
+	}
+	if decision.Reference == "" || !decision.Status.IsValid() {
+		return nil, fmt.Errorf("%w: reference and a valid status are required", ErrInvalidWebhookBody)
+	}
+	return &decision, nil
+}
+
+// SandboxProvider decides checks at once without contacting anyone, for
+// development and tests. Checks whose last name or a document number starts
+// with "REJECT" are rejected, those starting with "PENDING" stay pending so
+// webhooks can be tried out, and all others are approved.
+type SandboxProvider struct {
+	mu        sync.Mutex
+	decisions map[string]Decision
+}
+
+// NewSandboxProvider creates a sandbox provider
+func NewSandboxProvider() *SandboxProvider {
+	return &SandboxProvider{decisions: make(map[string]Decision)}
+}
+
+// Name returns "sandbox"
+func (p *SandboxProvider) Name() string {
+	return ProviderSandbox
+}
+
+// Submit decides the check from the submitted details
+func (p *SandboxProvider) Submit(ctx context.Context, submission *Submission) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	reference := "sbx_" + hex.EncodeToString(b)
+
+	decision := Decision{Reference: reference, Status: domain.KYCStatusApproved}
+	markers := []string{strings.ToUpper(submission.LastName)}
+	for _, doc := range submission.Documents {
+		markers = append(markers, strings.ToUpper(doc.Number))
+	}
+	for _, marker := range markers {
+		switch {
+		case strings.HasPrefix(marker, "REJECT"):
+			decision.Status, decision.Reason = domain.KYCStatusRejected, "sandbox rejection"
+		case strings.HasPrefix(marker, "PENDING") && decision.Status != domain.KYCStatusRejected:
+			decision.Status = domain.KYCStatusPending
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.decisions[reference] = decision
+	return reference, nil
+}
+
+// Check returns the decision made at submission
+func (p *SandboxProvider) Check(ctx context.Context, reference string) (*Decision, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	decision, ok := p.decisions[reference]
+	if !ok {
+		return nil, ErrUnknownReference
+	}
+	return &decision, nil
+}
+
+// HTTPProvider talks to a KYC service over a small JSON API: POST /checks with
+// a Submission returns {"reference"}, and GET /checks/{reference} returns a Decision
+type HTTPProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates a provider backed by a KYC service's HTTP API
+func NewHTTPProvider(baseURL, apiKey string, timeout time.Duration) *HTTPProvider {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &HTTPProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns "http"
+func (p *HTTPProvider) Name() string {
+	return ProviderHTTP
+}
+
+// Submit posts the submission to the KYC service
+func (p *HTTPProvider) Submit(ctx context.Context, submission *Submission) (string, error) {
+	payload, err := json.Marshal(submission)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal kyc submission: %w", err)
+	}
+	var created struct {
+		Reference string `json:"reference"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/checks", payload, &created); err != nil {
+		return "", err
+	}
+	if created.Reference == "" {
+		return "", errors.New("kyc provider returned no reference")
+	}
+	return created.Reference, nil
+}
+
+// Check reads the state of a check from the KYC service
+func (p *HTTPProvider) Check(ctx context.Context, reference string) (*Decision, error) {
+	var decision Decision
+	if err := p.do(ctx, http.MethodGet, "/checks/"+url.PathEscape(reference), nil, &decision); err != nil {
+		return nil, err
+	}
+	if !decision.Status.IsValid() {
+		return nil, fmt.Errorf("kyc provider returned unknown status %q", decision.Status)
+	}
+	decision.Reference = reference
+	return &decision, nil
+}
+
+func (p *HTTPProvider) do(ctx context.Context, method, path string, payload []byte, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create kyc request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call kyc provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return ErrUnknownReference
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("kyc provider returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode kyc response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package kyc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/webhook"
+)
+
+func TestSandboxProvider(t *testing.T) {
+	p := NewSandboxProvider()
+	ctx := context.Background()
+	cases := []struct {
+		lastName, number string
+		want             domain.KYCStatus
+	}{
+		{"Demir", "TR-1", domain.KYCStatusApproved},
+		{"Reject", "TR-1", domain.KYCStatusRejected},
+		{"Demir", "pending-1", domain.KYCStatusPending},
+		{"Demir", "REJECT-1", domain.KYCStatusRejected},
+	}
+	for _, tc := range cases {
+		ref, err := p.Submit(ctx, &Submission{LastName: tc.lastName, Documents: []domain.DriverDocument{{Number: tc.number}}})
+		if err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+		decision, err := p.Check(ctx, ref)
+		if err != nil || decision.Status != tc.want {
+			t.Errorf("%s/%s: Check = %+v, %v; want %s", tc.lastName, tc.number, decision, err, tc.want)
+		}
+	}
+	if _, err := p.Check(ctx, "sbx_unknown"); !errors.Is(err, ErrUnknownReference) {
+		t.Errorf("Check(unknown) err = %v, want ErrUnknownReference", err)
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"reference":"ref-1","status":"rejected","reason":"blurry"}`)
+	header := func(secret string, at time.Time) http.Header {
+		h := http.Header{}
+		h.Set(SignatureHeader, webhook.Sign(secret, at, body))
+		return h
+	}
+
+	decision, err := ParseWebhook("secret", header("secret", now), body, now)
+	if err != nil || decision.Reference != "ref-1" || decision.Status != domain.KYCStatusRejected || decision.Reason != "blurry" {
+		t.Fatalf("ParseWebhook = %+v, %v", decision, err)
+	}
+	for name, h := range map[string]http.Header{
+		"wrong secret": header("other", now),
+		"stale":        header("secret", now.Add(-time.Hour)),
+		"unsigned":     {},
+	} {
+		if _, err := ParseWebhook("secret", h, body, now); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: err = %v, want ErrInvalidSignature", name, err)
+		}
+	}
+	if _, err := ParseWebhook("", header("", now), body, now); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("without a secret webhooks must be refused, got %v", err)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/checks":
+			var submission Submission
+			if err := json.NewDecoder(r.Body).Decode(&submission); err != nil || submission.DriverID != "d1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"reference": "chk_1"})
+		case r.Method == http.MethodGet && r.URL.Path == "/checks/chk_1":
+			json.NewEncoder(w).Encode(map[string]string{"status": "approved"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p, err := NewProvider(ProviderHTTP, Options{URL: server.URL + "/", APIKey: "api-key"})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	ctx := context.Background()
+	ref, err := p.Submit(ctx, &Submission{DriverID: "d1"})
+	if err != nil || ref != "chk_1" {
+		t.Fatalf("Submit = %q, %v", ref, err)
+	}
+	decision, err := p.Check(ctx, ref)
+	if err != nil || decision.Status != domain.KYCStatusApproved || decision.Reference != "chk_1" {
+		t.Errorf("Check = %+v, %v", decision, err)
+	}
+	if _, err := p.Check(ctx, "chk_2"); !errors.Is(err, ErrUnknownReference) {
+		t.Errorf("Check(unknown) err = %v, want ErrUnknownReference", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Package logging builds the service logger from configuration and lets its
+// levels change at runtime
+package logging
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// New builds a logger writing to the configured outputs. Debug level keeps the
+// development preset (console output, stack traces from warn); other levels
+// use the production preset with sampling. The returned levels control the
+// logger and every logger derived from it.
+func New(cfg config.LoggingConfig) (*zap.Logger, *Levels, error) {
+	levels, err := NewLevels(cfg.Level, cfg.Levels)
+	if err != nil {
+		return nil, nil, err
+	}
+	development := levels.Root() == zapcore.DebugLevel
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	if development {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	var encoder zapcore.Encoder
+	switch cfg.Encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case "console":
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case "":
+		if development {
+			encoder = zapcore.NewConsoleEncoder(encoderConfig)
+		} else {
+			encoder = zapcore.NewJSONEncoder(encoderConfig)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unknown log encoding %q", cfg.Encoding)
+	}
+
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = []string{"stdout"}
+	}
+	syncers := make([]zapcore.WriteSyncer, 0, len(outputs))
+	for _, output := range outputs {
+		syncers = append(syncers, openOutput(output, cfg.Rotation))
+	}
+
+	// Levels are checked by levelCore, so the encoding core takes everything
+	var core zapcore.Core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(syncers...), zapcore.DebugLevel)
+	opts := []zap.Option{zap.AddCaller(), zap.ErrorOutput(zapcore.Lock(os.Stderr))}
+	if development {
+		opts = append(opts, zap.Development(), zap.AddStacktrace(zapcore.WarnLevel))
+	} else {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, 100, 100)
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+
+	return zap.New(&levelCore{Core: core, levels: levels}, opts...), levels, nil
+}
+
+// openOutput opens a standard stream or a rotated log file
+func openOutput(output string, rotation config.LogRotationConfig) zapcore.WriteSyncer {
+	switch output {
+	case "stdout":
+		return zapcore.Lock(os.Stdout)
+	case "stderr":
+		return zapcore.Lock(os.Stderr)
+	}
+	return zapcore.AddSync(&lumberjack.Logger{
+		Filename:   output,
+		MaxSize:    rotation.MaxSizeMB,
+		MaxAge:     rotation.MaxAgeDays,
+		MaxBackups: rotation.MaxBackups,
+		Compress:   rotation.Compress,
+	})
+}
+
+// levelCore filters entries by the level of the logger that wrote them
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.anyEnabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.Enabled(entry.LoggerName, entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLevels(t *testing.T) {
+	levels, err := NewLevels("info", map[string]string{"mongodb": "debug", "mongodb.retry": "error"})
+	require.NoError(t, err)
+
+	assert.Equal(t, zapcore.InfoLevel, levels.Level(""))
+	assert.Equal(t, zapcore.InfoLevel, levels.Level("handler"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("mongodb"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("mongodb.driver"))
+	assert.Equal(t, zapcore.ErrorLevel, levels.Level("mongodb.retry"))
+	assert.True(t, levels.Enabled("mongodb", zapcore.DebugLevel))
+	assert.False(t, levels.Enabled("handler", zapcore.DebugLevel))
+
+	levels.SetLevel("", zapcore.WarnLevel)
+	levels.ResetLevel("mongodb")
+	assert.False(t, levels.Enabled("mongodb", zapcore.InfoLevel))
+	assert.Equal(t, LevelsSnapshot{Level: "warn", Loggers: map[string]string{"mongodb.retry": "error"}}, levels.Snapshot())
+
+	_, err = NewLevels("info", map[string]string{"mongodb": "verbose"})
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.log")
+	logger, levels, err := New(config.LoggingConfig{
+		Level:    "info",
+		Encoding: "json",
+		Outputs:  []string{path},
+		Levels:   map[string]string{"mongodb": "debug"},
+		Rotation: config.LogRotationConfig{MaxSizeMB: 1},
+	})
+	require.NoError(t, err)
+
+	logger.Debug("root debug")
+	logger.Named("mongodb").Debug("mongodb debug")
+	logger.Named("handler").Info("handler info")
+	levels.SetLevel("handler", zapcore.ErrorLevel)
+	logger.Named("handler").Info("handler muted")
+	require.NoError(t, logger.Sync())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"mongodb debug"`)
+	assert.Contains(t, lines[0], `"logger":"mongodb"`)
+	assert.Contains(t, lines[1], `"msg":"handler info"`)
+
+	_, _, err = New(config.LoggingConfig{Level: "info", Encoding: "xml"})
+	assert.Error(t, err)
+}
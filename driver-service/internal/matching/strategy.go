@@ -0,0 +1,144 @@
+package matching
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// Strategy names accepted by NewStrategy
+const (
+	StrategyNearest    = "nearest"
+	StrategyBestRating = "best_rating"
+	StrategyRoundRobin = "round_robin"
+)
+
+// Candidate is a driver eligible for an offer together with its distance to the pickup
+type Candidate struct {
+	Driver     *domain.Driver
+	DistanceKm float64
+}
+
+// Strategy selects the driver to offer a trip to.
+// Candidates are passed sorted by distance, nearest first, and are never empty.
+type Strategy interface {
+	Name() string
+	Select(pickup domain.Location, candidates []Candidate) Candidate
+}
+
+// Options holds the tuning parameters of the strategies
+type Options struct {
+	// RatingRadiusKm limits the best-rating strategy to drivers close to the pickup
+	RatingRadiusKm float64
+	// ZoneSizeDeg is the edge length of a round-robin zone cell in degrees
+	ZoneSizeDeg float64
+}
+
+// NewStrategy creates the strategy with the given name
+func NewStrategy(name string, opts Options) (Strategy, error) {
+	switch name {
+	case StrategyNearest, "":
+		return NearestStrategy{}, nil
+	case StrategyBestRating:
+		if opts.RatingRadiusKm <= 0 {
+			opts.RatingRadiusKm = 3
+		}
+		return BestRatingStrategy{RadiusKm: opts.RatingRadiusKm}, nil
+	case StrategyRoundRobin:
+		if opts.ZoneSizeDeg <= 0 {
+			opts.ZoneSizeDeg = 0.02
+		}
+		return NewRoundRobinStrategy(opts.ZoneSizeDeg), nil
+	default:
+		return nil, fmt.Errorf("unknown matching strategy %q", name)
+	}
+}
+
+// NearestStrategy offers the trip to the closest driver
+type NearestStrategy struct{}
+
+// Name returns the strategy name
+func (NearestStrategy) Name() string { return StrategyNearest }
+
+// Select returns the nearest candidate
+func (NearestStrategy) Select(_ domain.Location, candidates []Candidate) Candidate {
+	return candidates[0]
+}
+
+// BestRatingStrategy offers the trip to the highest rated driver within RadiusKm,
+// falling back to the nearest driver when nobody is that close
+type BestRatingStrategy struct {
+	RadiusKm float64
+}
+
+// Name returns the strategy name
+func (BestRatingStrategy) Name() string { return StrategyBestRating }
+
+// Select returns the best rated candidate within the radius; ties go to the nearer driver
+func (s BestRatingStrategy) Select(_ domain.Location, candidates []Candidate) Candidate {
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.DistanceKm > s.RadiusKm {
+			break
+		}
+		if c.Driver.Rating > best.Driver.Rating {
+			best = c
+		}
+	}
+	return best
+}
+
+// RoundRobinStrategy spreads trips across the drivers of a zone by offering each
+// trip to the driver who was offered a trip in that zone least recently.
+// State is kept in memory, so the rotation is per service instance.
+type RoundRobinStrategy struct {
+	zoneSizeDeg float64
+
+	mu       sync.Mutex
+	sequence uint64
+	lastSeen map[string]map[string]uint64 // zone -> driver ID -> sequence of last offer
+}
+
+// NewRoundRobinStrategy creates a round-robin strategy with square zones of the given size
+func NewRoundRobinStrategy(zoneSizeDeg float64) *RoundRobinStrategy {
+	return &RoundRobinStrategy{
+		zoneSizeDeg: zoneSizeDeg,
+		lastSeen:    make(map[string]map[string]uint64),
+	}
+}
+
+// Name returns the strategy name
+func (s *RoundRobinStrategy) Name() string { return StrategyRoundRobin }
+
+// Select returns the candidate least recently offered a trip in the pickup's zone
+func (s *RoundRobinStrategy) Select(pickup domain.Location, candidates []Candidate) Candidate {
+	zone := ZoneKey(pickup, s.zoneSizeDeg)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen, ok := s.lastSeen[zone]
+	if !ok {
+		seen = make(map[string]uint64)
+		s.lastSeen[zone] = seen
+	}
+
+	chosen := candidates[0]
+	for _, c := range candidates[1:] {
+		// Drivers never offered in this zone have sequence 0 and go first
+		if seen[c.Driver.ID] < seen[chosen.Driver.ID] {
+			chosen = c
+		}
+	}
+
+	s.sequence++
+	seen[chosen.Driver.ID] = s.sequence
+	return chosen
+}
+
+// ZoneKey returns the identifier of the grid cell containing the location
+func ZoneKey(loc domain.Location, sizeDeg float64) string {
+	return fmt.Sprintf("%d:%d", int(math.Floor(loc.Lat/sizeDeg)), int(math.Floor(loc.Lon/sizeDeg)))
+}
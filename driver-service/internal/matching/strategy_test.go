@@ -0,0 +1,126 @@
+package matching
+
+import (
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+func candidate(id string, distanceKm, rating float64) Candidate {
+	return Candidate{
+		Driver:     &domain.Driver{ID: id, Rating: rating},
+		DistanceKm: distanceKm,
+	}
+}
+
+func TestNewStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		wantErr  bool
+	}{
+		{name: "", expected: StrategyNearest},
+		{name: StrategyNearest, expected: StrategyNearest},
+		{name: StrategyBestRating, expected: StrategyBestRating},
+		{name: StrategyRoundRobin, expected: StrategyRoundRobin},
+		{name: "random", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewStrategy(tt.name, Options{})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error for unknown strategy")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strategy.Name() != tt.expected {
+				t.Errorf("expected strategy %s, got %s", tt.expected, strategy.Name())
+			}
+		})
+	}
+}
+
+func TestNearestStrategy(t *testing.T) {
+	candidates := []Candidate{candidate("a", 0.5, 3), candidate("b", 1, 5)}
+
+	chosen := NearestStrategy{}.Select(domain.Location{}, candidates)
+	if chosen.Driver.ID != "a" {
+		t.Errorf("expected nearest driver a, got %s", chosen.Driver.ID)
+	}
+}
+
+func TestBestRatingStrategy(t *testing.T) {
+	strategy := BestRatingStrategy{RadiusKm: 2}
+
+	tests := []struct {
+		name       string
+		candidates []Candidate
+		expected   string
+	}{
+		{
+			name:       "highest rating within radius",
+			candidates: []Candidate{candidate("a", 0.5, 4.2), candidate("b", 1.5, 4.9), candidate("c", 3, 5)},
+			expected:   "b",
+		},
+		{
+			name:       "tie goes to nearer driver",
+			candidates: []Candidate{candidate("a", 0.5, 4.8), candidate("b", 1, 4.8)},
+			expected:   "a",
+		},
+		{
+			name:       "nobody within radius falls back to nearest",
+			candidates: []Candidate{candidate("a", 2.5, 3), candidate("b", 4, 5)},
+			expected:   "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chosen := strategy.Select(domain.Location{}, tt.candidates)
+			if chosen.Driver.ID != tt.expected {
+				t.Errorf("expected driver %s, got %s", tt.expected, chosen.Driver.ID)
+			}
+		})
+	}
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	strategy := NewRoundRobinStrategy(0.02)
+	candidates := []Candidate{candidate("a", 0.5, 0), candidate("b", 1, 0), candidate("c", 2, 0)}
+	pickup := domain.Location{Lat: 41.0431, Lon: 29.0099}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, strategy.Select(pickup, candidates).Driver.ID)
+	}
+	expected := []string{"a", "b", "c", "a"}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected rotation %v, got %v", expected, got)
+		}
+	}
+
+	// A different zone keeps its own rotation
+	other := domain.Location{Lat: 40.99, Lon: 29.1}
+	if id := strategy.Select(other, candidates).Driver.ID; id != "a" {
+		t.Errorf("expected new zone to start with nearest driver a, got %s", id)
+	}
+}
+
+func TestZoneKey(t *testing.T) {
+	a := ZoneKey(domain.Location{Lat: 41.041, Lon: 29.001}, 0.02)
+	b := ZoneKey(domain.Location{Lat: 41.045, Lon: 29.009}, 0.02)
+	c := ZoneKey(domain.Location{Lat: 41.075, Lon: 29.009}, 0.02)
+
+	if a != b {
+		t.Errorf("expected nearby points to share a zone, got %s and %s", a, b)
+	}
+	if a == c {
+		t.Errorf("expected distant points to be in different zones, both got %s", a)
+	}
+}
@@ -0,0 +1,151 @@
+// Package metrics counts what happens to drivers, e.g. registrations, nearby
+// searches and location updates, and writes the counts with the drivers per
+// status in the OpenMetrics text format. Product dashboards read them from
+// Prometheus instead of querying the database.
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// ContentType is the OpenMetrics text format WriteOpenMetrics writes
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// resultBuckets are the upper bounds of the nearby search result histogram
+var resultBuckets = []int{0, 1, 2, 5, 10, 20, 50}
+
+// Business holds the business counters of one driver service instance. The
+// counters start at zero with the process; drivers per status are counted in
+// the database, so every instance reports the same numbers.
+type Business struct {
+	driversCreated  atomic.Int64
+	locationUpdates atomic.Int64
+
+	mu       sync.Mutex
+	searches int64
+	results  int64
+	// buckets counts the searches per bucket of resultBuckets, the last one
+	// for searches with more results than any bound
+	buckets []int64
+
+	statuses domain.DriverStatusRepository
+	// refresh is how long drivers per status are reused before they are
+	// counted again, so frequent scrapes do not each scan the drivers
+	refresh   time.Duration
+	countMu   sync.Mutex
+	counts    *domain.DriverStatusCounts
+	countedAt time.Time
+	now       func() time.Time
+}
+
+// New creates the business metrics. Drivers per status are counted with
+// statuses at most once every refresh; a nil statuses leaves them out.
+func New(statuses domain.DriverStatusRepository, refresh time.Duration) *Business {
+	return &Business{
+		buckets:  make([]int64, len(resultBuckets)+1),
+		statuses: statuses,
+		refresh:  refresh,
+		now:      time.Now,
+	}
+}
+
+// DriverCreated counts a registered driver
+func (b *Business) DriverCreated() {
+	b.driversCreated.Add(1)
+}
+
+// LocationUpdated counts a stored driver location report
+func (b *Business) LocationUpdated() {
+	b.locationUpdates.Add(1)
+}
+
+// NearbySearch counts a nearby search and the drivers it found
+func (b *Business) NearbySearch(results int) {
+	bucket := len(resultBuckets)
+	for i, bound := range resultBuckets {
+		if results <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.searches++
+	b.results += int64(results)
+	b.buckets[bucket]++
+}
+
+// statusCounts returns the drivers per status, counting them again once the
+// previous count is older than the refresh interval. A failed count falls
+// back to the previous one.
+func (b *Business) statusCounts(ctx context.Context) (*domain.DriverStatusCounts, error) {
+	if b.statuses == nil {
+		return nil, nil
+	}
+	b.countMu.Lock()
+	defer b.countMu.Unlock()
+
+	if b.counts != nil && b.now().Sub(b.countedAt) < b.refresh {
+		return b.counts, nil
+	}
+	counts, err := b.statuses.CountByStatus(ctx)
+	if err != nil {
+		return b.counts, fmt.Errorf("failed to count drivers by status: %w", err)
+	}
+	b.counts, b.countedAt = counts, b.now()
+	return counts, nil
+}
+
+// WriteOpenMetrics writes the counters, the nearby search result histogram and
+// the drivers per status. When drivers cannot be counted the last count is
+// written, or none before the first, and the error is returned after
+// everything else has been written.
+func (b *Business) WriteOpenMetrics(ctx context.Context, w io.Writer) error {
+	counts, countErr := b.statusCounts(ctx)
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintf(out, "# HELP drivers_created Drivers registered since the service started.\n# TYPE drivers_created counter\n")
+	fmt.Fprintf(out, "drivers_created_total %d\n", b.driversCreated.Load())
+
+	fmt.Fprintf(out, "# HELP location_updates Driver location reports stored since the service started.\n# TYPE location_updates counter\n")
+	fmt.Fprintf(out, "location_updates_total %d\n", b.locationUpdates.Load())
+
+	b.mu.Lock()
+	searches, results := b.searches, b.results
+	buckets := append([]int64(nil), b.buckets...)
+	b.mu.Unlock()
+
+	fmt.Fprintf(out, "# HELP nearby_searches Nearby driver searches since the service started.\n# TYPE nearby_searches counter\n")
+	fmt.Fprintf(out, "nearby_searches_total %d\n", searches)
+
+	fmt.Fprintf(out, "# HELP nearby_search_results Drivers found by a nearby search.\n# TYPE nearby_search_results histogram\n")
+	var cumulative int64
+	for i, bound := range resultBuckets {
+		cumulative += buckets[i]
+		fmt.Fprintf(out, "nearby_search_results_bucket{le=\"%d\"} %d\n", bound, cumulative)
+	}
+	fmt.Fprintf(out, "nearby_search_results_bucket{le=\"+Inf\"} %d\n", searches)
+	fmt.Fprintf(out, "nearby_search_results_count %d\nnearby_search_results_sum %d\n", searches, results)
+
+	if counts != nil {
+		fmt.Fprintf(out, "# HELP drivers_by_status Stored drivers by status.\n# TYPE drivers_by_status gauge\n")
+		fmt.Fprintf(out, "drivers_by_status{status=\"available\"} %d\n", counts.Available)
+		fmt.Fprintf(out, "drivers_by_status{status=\"off_shift\"} %d\n", counts.OffShift)
+		fmt.Fprintf(out, "drivers_by_status{status=\"suspended\"} %d\n", counts.Suspended)
+	}
+
+	fmt.Fprintf(out, "# EOF\n")
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	return countErr
+}
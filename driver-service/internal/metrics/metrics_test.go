@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+type fakeStatuses struct {
+	counts *domain.DriverStatusCounts
+	err    error
+	calls  int
+}
+
+func (f *fakeStatuses) CountByStatus(ctx interface{}) (*domain.DriverStatusCounts, error) {
+	f.calls++
+	return f.counts, f.err
+}
+
+func TestBusiness_WriteOpenMetrics(t *testing.T) {
+	statuses := &fakeStatuses{counts: &domain.DriverStatusCounts{Available: 12, OffShift: 30, Suspended: 2}}
+	b := New(statuses, 30*time.Second)
+
+	b.DriverCreated()
+	b.DriverCreated()
+	b.LocationUpdated()
+	for _, results := range []int{0, 3, 3, 60} {
+		b.NearbySearch(results)
+	}
+
+	var out strings.Builder
+	if err := b.WriteOpenMetrics(context.Background(), &out); err != nil {
+		t.Fatalf("WriteOpenMetrics() error = %v", err)
+	}
+	for _, line := range []string{
+		"# TYPE drivers_created counter",
+		"drivers_created_total 2",
+		"location_updates_total 1",
+		"nearby_searches_total 4",
+		"# TYPE nearby_search_results histogram",
+		`nearby_search_results_bucket{le="0"} 1`,
+		`nearby_search_results_bucket{le="2"} 1`,
+		`nearby_search_results_bucket{le="5"} 3`,
+		`nearby_search_results_bucket{le="50"} 3`,
+		`nearby_search_results_bucket{le="+Inf"} 4`,
+		"nearby_search_results_count 4",
+		"nearby_search_results_sum 66",
+		"# TYPE drivers_by_status gauge",
+		`drivers_by_status{status="available"} 12`,
+		`drivers_by_status{status="off_shift"} 30`,
+		`drivers_by_status{status="suspended"} 2`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("output is missing %q:\n%s", line, out.String())
+		}
+	}
+	if !strings.HasSuffix(out.String(), "# EOF\n") {
+		t.Errorf("output does not end with # EOF:\n%s", out.String())
+	}
+}
+
+func TestBusiness_StatusRefresh(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	statuses := &fakeStatuses{counts: &domain.DriverStatusCounts{Available: 1}}
+	b := New(statuses, 30*time.Second)
+	b.now = func() time.Time { return now }
+
+	var out strings.Builder
+	_ = b.WriteOpenMetrics(context.Background(), &out)
+	_ = b.WriteOpenMetrics(context.Background(), &out)
+	if statuses.calls != 1 {
+		t.Errorf("counted drivers %d times within the refresh interval, want 1", statuses.calls)
+	}
+
+	// A failed count keeps the previous one and reports the error
+	now = now.Add(30 * time.Second)
+	statuses.err = errors.New("connection refused")
+	out.Reset()
+	if err := b.WriteOpenMetrics(context.Background(), &out); err == nil {
+		t.Error("expected the count error to be returned")
+	}
+	if statuses.calls != 2 {
+		t.Errorf("counted drivers %d times after the refresh interval, want 2", statuses.calls)
+	}
+	if !strings.Contains(out.String(), `drivers_by_status{status="available"} 1`+"\n") {
+		t.Errorf("expected the previous count to be written:\n%s", out.String())
+	}
+
+	// Without a count yet the gauge is left out
+	none := New(&fakeStatuses{err: errors.New("connection refused")}, time.Minute)
+	out.Reset()
+	_ = none.WriteOpenMetrics(context.Background(), &out)
+	if strings.Contains(out.String(), "drivers_by_status") {
+		t.Errorf("expected no drivers_by_status without a count:\n%s", out.String())
+	}
+}
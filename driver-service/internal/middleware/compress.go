@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Compress returns a middleware that gzips responses for clients sending
+// "Accept-Encoding: gzip". Bodies are buffered until MinSize bytes are written,
+// so small responses and content types outside the allowlist go out unchanged.
+func Compress(cfg config.CompressionConfig) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	level := cfg.Level
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	pool := &sync.Pool{New: func() interface{} {
+		gz, _ := gzip.NewWriterLevel(nil, level)
+		return gz
+	}}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" ||
+			!acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipWriter{ResponseWriter: c.Writer, cfg: cfg, pool: pool}
+		c.Writer = writer
+		c.Header("Vary", "Accept-Encoding")
+
+		c.Next()
+
+		writer.finish()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter holds back the body until it knows whether to compress it
+type gzipWriter struct {
+	gin.ResponseWriter
+	cfg     config.CompressionConfig
+	pool    *sync.Pool
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		return w.write(data)
+	}
+	w.buf = append(w.buf, data...)
+	if len(w.buf) < w.cfg.MinSize {
+		return len(data), nil
+	}
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush commits to a decision with whatever is buffered so streamed responses
+// are not held back
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend
+// the write deadline of a long stream
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.decided = true
+	return w.ResponseWriter.Hijack()
+}
+
+func (w *gzipWriter) write(data []byte) (int, error) {
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide starts compressing when the response qualifies and writes out the buffer
+func (w *gzipWriter) decide() error {
+	w.decided = true
+	if w.shouldCompress() {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.write(buf)
+	return err
+}
+
+func (w *gzipWriter) shouldCompress() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || len(w.buf) == 0 {
+		return false
+	}
+	switch status := w.Status(); {
+	case status < 200, status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return contentTypeAllowed(header.Get("Content-Type"), w.cfg.ContentTypes)
+}
+
+// finish writes out a body that stayed below the size threshold and closes the gzip stream
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		if len(w.buf) < w.cfg.MinSize {
+			w.decided = true
+			if len(w.buf) > 0 {
+				w.ResponseWriter.Write(w.buf)
+			}
+			w.buf = nil
+		} else {
+			w.decide()
+		}
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(nil)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// contentTypeAllowed matches the media type against the allowlist, which may
+// contain wildcards such as "text/*"
+func contentTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == mediaType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
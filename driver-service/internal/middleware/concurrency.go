@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SaturationStats reports how close the server is to its concurrency limit
+type SaturationStats struct {
+	// MaxInFlight is 0 when requests are not limited
+	MaxInFlight  int64 `json:"maxInFlight" example:"1000"`
+	InFlight     int64 `json:"inFlight" example:"420"`
+	PeakInFlight int64 `json:"peakInFlight" example:"812"`
+	// Saturation is InFlight / MaxInFlight, or 0 without a limit
+	Saturation      float64 `json:"saturation" example:"0.42"`
+	Rejected        int64   `json:"rejected" example:"37"`
+	OpenConnections int64   `json:"openConnections" example:"230"`
+}
+
+// ConcurrencyLimiter bounds the number of requests served at once. Requests
+// over the limit are answered with 503 and Retry-After right away rather than
+// queued, so clients back off instead of piling up behind slow requests.
+type ConcurrencyLimiter struct {
+	slots      chan struct{}
+	retryAfter time.Duration
+	logger     *zap.Logger
+
+	inFlight    atomic.Int64
+	peak        atomic.Int64
+	rejected    atomic.Int64
+	connections atomic.Int64
+}
+
+// NewConcurrencyLimiter creates a limiter; a max of 0 or less only counts requests
+func NewConcurrencyLimiter(max int, retryAfter time.Duration, logger *zap.Logger) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{retryAfter: retryAfter, logger: logger}
+	if max > 0 {
+		l.slots = make(chan struct{}, max)
+	}
+	return l
+}
+
+// Limit returns a middleware that rejects requests while the server is saturated.
+// Health and readiness checks and admin calls always pass, so the service stays observable.
+func (l *ConcurrencyLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if path == "/health" || path == "/ready" || strings.HasPrefix(path, "/api/v1/admin/") {
+			c.Next()
+			return
+		}
+
+		if l.slots != nil {
+			select {
+			case l.slots <- struct{}{}:
+				defer func() { <-l.slots }()
+			default:
+				l.rejected.Add(1)
+				l.logger.Warn("server saturated, rejecting request",
+					zap.String("path", path),
+					zap.Int("maxInFlight", cap(l.slots)),
+				)
+				c.Header("Retry-After", strconv.Itoa(int(l.retryAfter.Round(time.Second)/time.Second)))
+				problem.Abort(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "server is overloaded, retry later")
+				return
+			}
+		}
+
+		l.raisePeak(l.inFlight.Add(1))
+		defer l.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// raisePeak records inFlight as the peak when no higher count was seen
+func (l *ConcurrencyLimiter) raisePeak(inFlight int64) {
+	for {
+		peak := l.peak.Load()
+		if inFlight <= peak || l.peak.CompareAndSwap(peak, inFlight) {
+			return
+		}
+	}
+}
+
+// ConnState counts open connections; set it as the http.Server's ConnState hook
+func (l *ConcurrencyLimiter) ConnState(_ net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		l.connections.Add(1)
+	case http.StateClosed, http.StateHijacked:
+		l.connections.Add(-1)
+	}
+}
+
+// Stats returns a snapshot of the saturation metrics
+func (l *ConcurrencyLimiter) Stats() SaturationStats {
+	stats := SaturationStats{
+		MaxInFlight:     int64(cap(l.slots)),
+		InFlight:        l.inFlight.Load(),
+		PeakInFlight:    l.peak.Load(),
+		Rejected:        l.rejected.Load(),
+		OpenConnections: l.connections.Load(),
+	}
+	if stats.MaxInFlight > 0 {
+		stats.Saturation = float64(stats.InFlight) / float64(stats.MaxInFlight)
+	}
+	return stats
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// ConsistencyTokenHeader carries the consistency token of a response, and of
+// the earlier response a request wants to read its writes from
+const ConsistencyTokenHeader = "X-Consistency-Token"
+
+// Consistency returns a middleware that gives every request its read-your-writes
+// state. GET and HEAD requests read from the reads read preference, other
+// requests from the writes one. Responses carry the token of the data they
+// read or wrote; a request sending that token back reads data at least as new,
+// even from a secondary that was lagging behind when the write was made.
+func Consistency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		consistency := &domain.Consistency{
+			Write: c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead,
+		}
+		if header := c.GetHeader(ConsistencyTokenHeader); header != "" {
+			token, err := domain.ParseConsistencyToken(header)
+			if err != nil {
+				problem.Abort(c, http.StatusBadRequest, "VALIDATION_ERROR", ConsistencyTokenHeader+" is not a token issued by the service")
+				return
+			}
+			consistency.ReadAfter = &token
+		}
+
+		c.Request = c.Request.WithContext(domain.ContextWithConsistency(c.Request.Context(), consistency))
+		writer := &consistencyWriter{ResponseWriter: c.Writer, consistency: consistency}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+	}
+}
+
+// consistencyWriter sets the token header just before the response headers
+// are written, once the handler has run its database commands
+type consistencyWriter struct {
+	gin.ResponseWriter
+	consistency *domain.Consistency
+}
+
+func (w *consistencyWriter) setToken() {
+	if w.ResponseWriter.Written() {
+		return
+	}
+	if token, ok := w.consistency.Token(); ok {
+		w.Header().Set(ConsistencyTokenHeader, token.String())
+	}
+}
+
+func (w *consistencyWriter) WriteHeaderNow() {
+	w.setToken()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *consistencyWriter) Write(data []byte) (int, error) {
+	w.setToken()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *consistencyWriter) WriteString(s string) (int, error) {
+	w.setToken()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *consistencyWriter) Flush() {
+	w.setToken()
+	w.ResponseWriter.Flush()
+}
+
+func (w *consistencyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -1,22 +1,90 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/config"
 	"github.com/gin-gonic/gin"
 )
 
-// CORS returns a middleware that handles CORS headers
-func CORS() gin.HandlerFunc {
+// CORS returns a middleware that applies the configured CORS policy
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	allowAll := false
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+			break
+		}
+	}
+	allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			// Not a cross-origin request
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if !allowAll && !isOriginAllowed(origin, cfg.AllowedOrigins) {
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		// Browsers reject a literal "*" together with credentials, so echo the origin instead
+		if allowAll && !cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Add("Vary", "Origin")
+		}
+		if cfg.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// isOriginAllowed checks the origin against the allowlist, supporting wildcard
+// subdomain patterns like "https://*.bitaksi.com"
+func isOriginAllowed(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == origin {
+			return true
+		}
+
+		idx := strings.Index(pattern, "*.")
+		if idx < 0 {
+			continue
+		}
+		prefix := pattern[:idx]   // e.g. "https://"
+		suffix := pattern[idx+1:] // e.g. ".bitaksi.com"
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			len(origin) > len(prefix)+len(suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/config"
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// Decompress returns a middleware that decodes gzip request bodies, so
+// handlers read them as sent. Every response carries "Accept-Encoding: gzip"
+// to tell clients such as the gateway that they may compress what they send
+// (RFC 7694); bodies in another coding are refused with 415. A decoded body is
+// cut off after MaxRequestSize bytes.
+func Decompress(cfg config.CompressionConfig) gin.HandlerFunc {
+	if !cfg.Requests {
+		return func(c *gin.Context) { c.Next() }
+	}
+	maxSize := cfg.MaxRequestSize
+	if maxSize <= 0 {
+		maxSize = 32 << 20
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Accept-Encoding", "gzip")
+
+		coding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+		switch coding {
+		case "", "identity":
+			c.Next()
+			return
+		case "gzip":
+		default:
+			problem.Abort(c, http.StatusUnsupportedMediaType, "UNSUPPORTED_MEDIA_TYPE", "request bodies can only be gzip-encoded")
+			return
+		}
+
+		gz, err := gzip.NewReader(c.Request.Body)
+		if err != nil {
+			problem.Abort(c, http.StatusBadRequest, "VALIDATION_ERROR", "request body is not valid gzip")
+			return
+		}
+		defer gz.Close()
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, gzipRequestBody{Reader: gz, body: c.Request.Body}, maxSize)
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.Header.Del("Content-Length")
+		c.Request.ContentLength = -1
+		c.Next()
+	}
+}
+
+// gzipRequestBody closes the request body behind the gzip reader
+type gzipRequestBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b gzipRequestBody) Close() error {
+	return b.body.Close()
+}
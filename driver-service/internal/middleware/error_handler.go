@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/bitaksi/driver-service/internal/problem"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -18,12 +19,7 @@ func ErrorHandler(logger *zap.Logger) gin.HandlerFunc {
 			logger.Error("request error", zap.Error(err))
 
 			// Respond with error
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":    "INTERNAL_ERROR",
-					"message": "an internal error occurred",
-				},
-			})
+			problem.Respond(c, http.StatusInternalServerError, "INTERNAL_ERROR", "an internal error occurred")
 		}
 	}
 }
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Identity headers set by the gateway after it verified the caller's token
+const (
+	UserIDHeader   = "X-User-Id"
+	UserRoleHeader = "X-User-Role"
+	TenantIDHeader = "X-Tenant-Id"
+)
+
+// Identity returns a middleware that puts the caller identity forwarded by the
+// gateway into the request context. The headers are trusted as is, so the
+// service must only be reachable through the gateway.
+func Identity() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity := domain.Identity{
+			UserID:   c.GetHeader(UserIDHeader),
+			Role:     c.GetHeader(UserRoleHeader),
+			TenantID: c.GetHeader(TenantIDHeader),
+		}
+		if identity != (domain.Identity{}) {
+			c.Request = c.Request.WithContext(domain.ContextWithIdentity(c.Request.Context(), identity))
+		}
+		c.Next()
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"time"
 
+	"github.com/bitaksi/driver-service/internal/domain"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -19,13 +20,22 @@ func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
 
 		// Log after request is processed
 		latency := time.Since(start)
-		logger.Info("HTTP request",
+		fields := []zap.Field{
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
 			zap.String("query", query),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
 			zap.String("ip", c.ClientIP()),
-		)
+		}
+		// Attribute the request to the caller the gateway forwarded it for
+		if identity, ok := domain.IdentityFromContext(c.Request.Context()); ok {
+			fields = append(fields,
+				zap.String("userId", identity.UserID),
+				zap.String("role", identity.Role),
+				zap.String("tenantId", identity.TenantID),
+			)
+		}
+		logger.Info("HTTP request", fields...)
 	}
 }
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Operation returns a middleware that labels the request context with its
+// route, e.g. "GET /api/v1/drivers/nearby", so slow database commands can be
+// traced back to the endpoint that sent them
+func Operation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if route := c.FullPath(); route != "" {
+			c.Request = c.Request.WithContext(domain.ContextWithOperation(c.Request.Context(), c.Request.Method+" "+route))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/bitaksi/driver-service/pkg/errreport"
+	"github.com/bitaksi/driver-service/pkg/panics"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// requestIDHeader carries the ID the gateway assigned to the request
+const requestIDHeader = "X-Request-ID"
+
+// Recovery returns a middleware that recovers panics in later handlers, logs
+// them with their stack, request ID and fingerprint, counts them, reports them
+// to reporter and responds with the standard 500 error
+func Recovery(logger *zap.Logger, reporter errreport.Reporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// A client that hung up is not a bug, and cannot be answered
+			if brokenPipe(value) {
+				logger.Warn("connection closed by client",
+					zap.String("requestId", c.GetHeader(requestIDHeader)),
+					zap.String("method", c.Request.Method),
+					zap.String("path", c.Request.URL.Path),
+					zap.Any("error", value),
+				)
+				c.Abort()
+				return
+			}
+
+			report := panics.Capture(value)
+			panics.Count(report)
+			logger.Error("panic recovered",
+				zap.String("requestId", c.GetHeader(requestIDHeader)),
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("fingerprint", report.Fingerprint),
+				zap.String("culprit", report.Culprit()),
+				zap.String("panic", fmt.Sprint(value)),
+				zap.ByteString("stack", report.Stack),
+			)
+			event := errreport.FromPanic(report)
+			event.Tags = map[string]string{
+				"requestId": c.GetHeader(requestIDHeader),
+				"route":     c.FullPath(),
+			}
+			event.Request = &errreport.Request{Method: c.Request.Method, URL: c.Request.URL.Path}
+			reporter.Report(event)
+
+			// Headers already sent cannot be replaced by the error
+			if !c.Writer.Written() {
+				problem.Respond(c, http.StatusInternalServerError, "INTERNAL_ERROR", "an internal error occurred")
+			}
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// brokenPipe reports whether value is the error of writing to a closed connection
+func brokenPipe(value interface{}) bool {
+	err, ok := value.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// Service token audience and scopes, as issued by the gateway's token exchange
+const (
+	ServiceTokenAudience = "driver-service"
+	ScopeDriversRead     = "drivers:read"
+	ScopeDriversWrite    = "drivers:write"
+)
+
+// GatewayTokenHeader carries the token the gateway signs its own calls with,
+// for the GatewayTokenAudience. The token exchange never issues that audience,
+// so services calling directly cannot pass for the gateway.
+const (
+	GatewayTokenHeader   = "X-Gateway-Token"
+	GatewayTokenAudience = "driver-service:gateway"
+)
+
+// ServiceToken returns a middleware that authenticates every caller but the
+// routes in open, such as health checks:
+//   - internal services calling the service directly with a bearer token
+//     exchanged at the gateway. The token is an HS256 JWT for the
+//     driver-service audience; the caller identity is taken from its claims
+//     instead of the identity headers, and its scope must allow the request:
+//     drivers:read GET and HEAD requests, drivers:write the others.
+//   - the gateway, whose calls carry a token for the gateway audience in
+//     GatewayTokenHeader. Only then are the identity headers it sets trusted.
+//
+// Requests with neither are rejected with 401.
+func ServiceToken(secret string, open []string, logger *zap.Logger) gin.HandlerFunc {
+	key := []byte(secret)
+	keyFunc := func(*jwt.Token) (interface{}, error) { return key, nil }
+	openRoutes := make(map[string]bool, len(open))
+	for _, route := range open {
+		openRoutes[route] = true
+	}
+	return func(c *gin.Context) {
+		tokenString, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			switch {
+			case openRoutes[c.FullPath()]:
+				c.Next()
+			case c.GetHeader(GatewayTokenHeader) == "":
+				problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "a service token is required")
+			default:
+				_, err := jwt.Parse(c.GetHeader(GatewayTokenHeader), keyFunc,
+					jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+					jwt.WithAudience(GatewayTokenAudience),
+					jwt.WithExpirationRequired(),
+				)
+				if err != nil {
+					logger.Debug("invalid gateway token", zap.Error(err))
+					problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired gateway token")
+					return
+				}
+				c.Next()
+			}
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(tokenString, claims, keyFunc,
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithAudience(ServiceTokenAudience),
+			jwt.WithExpirationRequired(),
+		)
+		if err != nil {
+			logger.Debug("invalid service token", zap.Error(err))
+			problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired service token")
+			return
+		}
+
+		scope, _ := claims["scope"].(string)
+		needed := ScopeDriversWrite
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			needed = ScopeDriversRead
+		}
+		if !hasScope(scope, needed) {
+			problem.Abort(c, http.StatusForbidden, "INSUFFICIENT_SCOPE", "service token lacks the "+needed+" scope")
+			return
+		}
+
+		identity := serviceTokenIdentity(claims)
+		c.Request = c.Request.WithContext(domain.ContextWithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
+
+// serviceTokenIdentity returns the caller a service acts for, the way the
+// gateway forwards it in the identity headers
+func serviceTokenIdentity(claims jwt.MapClaims) domain.Identity {
+	identity := domain.Identity{}
+	identity.UserID, _ = claims["username"].(string)
+	identity.Role, _ = claims["role"].(string)
+	switch identity.Role {
+	case domain.RoleFleetAdmin:
+		identity.TenantID, _ = claims["fleetId"].(string)
+	case domain.RoleRider:
+		identity.UserID, _ = claims["riderId"].(string)
+	}
+	return identity
+}
+
+// hasScope reports whether a space separated scope list holds scope
+func hasScope(scopes, scope string) bool {
+	for _, s := range strings.Fields(scopes) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServiceToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const secret = "exchange-secret"
+	sign := func(claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return signed
+	}
+	exp := time.Now().Add(time.Minute).Unix()
+	gateway := sign(jwt.MapClaims{"aud": GatewayTokenAudience, "exp": exp})
+	exchanged := sign(jwt.MapClaims{"aud": ServiceTokenAudience, "exp": exp, "scope": ScopeDriversRead, "username": "ops-1", "role": domain.RoleFleetAdmin, "fleetId": "fleet-1"})
+
+	router := gin.New()
+	router.Use(Identity())
+	router.Use(ServiceToken(secret, []string{"/health"}, zap.NewNop()))
+	var seen domain.Identity
+	answer := func(c *gin.Context) {
+		seen, _ = domain.IdentityFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	}
+	router.GET("/health", answer)
+	router.GET("/api/v1/drivers", answer)
+	router.POST("/api/v1/drivers", answer)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		header       map[string]string
+		want         int
+		wantIdentity domain.Identity
+	}{
+		{name: "open route", method: "GET", path: "/health", want: http.StatusOK},
+		{name: "no token", method: "GET", path: "/api/v1/drivers", want: http.StatusUnauthorized},
+		{
+			name:   "spoofed identity without a token",
+			method: "GET",
+			path:   "/api/v1/drivers",
+			header: map[string]string{UserRoleHeader: "", UserIDHeader: "dispatcher"},
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:         "gateway",
+			method:       "POST",
+			path:         "/api/v1/drivers",
+			header:       map[string]string{GatewayTokenHeader: gateway, UserIDHeader: "admin-1", UserRoleHeader: domain.RoleFleetAdmin, TenantIDHeader: "fleet-1"},
+			want:         http.StatusOK,
+			wantIdentity: domain.Identity{UserID: "admin-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-1"},
+		},
+		{
+			name:   "exchanged token passed as the gateway's",
+			method: "GET",
+			path:   "/api/v1/drivers",
+			header: map[string]string{GatewayTokenHeader: exchanged},
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "expired gateway token",
+			method: "GET",
+			path:   "/api/v1/drivers",
+			header: map[string]string{GatewayTokenHeader: sign(jwt.MapClaims{"aud": GatewayTokenAudience, "exp": time.Now().Add(-time.Minute).Unix()})},
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:         "exchanged token",
+			method:       "GET",
+			path:         "/api/v1/drivers",
+			header:       map[string]string{"Authorization": "Bearer " + exchanged, UserRoleHeader: ""},
+			want:         http.StatusOK,
+			wantIdentity: domain.Identity{UserID: "ops-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-1"},
+		},
+		{
+			name:   "exchanged token without the write scope",
+			method: "POST",
+			path:   "/api/v1/drivers",
+			header: map[string]string{"Authorization": "Bearer " + exchanged},
+			want:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = domain.Identity{}
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, tt.wantIdentity, seen)
+		})
+	}
+}
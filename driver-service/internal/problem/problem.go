@@ -0,0 +1,88 @@
+// Package problem renders error responses either in the service's default
+// {"error":{"code","message"}} format or, when the client asks for it with
+// "Accept: application/problem+json", as RFC 7807 problem details.
+package problem
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of RFC 7807 problem details
+const ContentType = "application/problem+json"
+
+// Details is an RFC 7807 problem document. Code carries the service error code
+// as an extension member.
+type Details struct {
+	Type     string `json:"type" example:"/problems/validation-error"`
+	Title    string `json:"title" example:"Bad Request"`
+	Status   int    `json:"status" example:"400"`
+	Detail   string `json:"detail,omitempty" example:"plate is required"`
+	Instance string `json:"instance,omitempty" example:"/api/v1/drivers"`
+	Code     string `json:"code" example:"VALIDATION_ERROR"`
+}
+
+// New builds problem details for an error code. The type is a relative URI
+// derived from the code, e.g. VALIDATION_ERROR becomes /problems/validation-error.
+func New(status int, code, detail, instance string) Details {
+	return Details{
+		Type:     TypeURI(code),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+	}
+}
+
+// TypeURI returns the problem type URI for an error code
+func TypeURI(code string) string {
+	if code == "" {
+		return "about:blank"
+	}
+	return "/problems/" + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// Wanted reports whether the request accepts problem details. Wildcards do not
+// count, so clients keep the default format unless they opt in.
+func Wanted(r *http.Request) bool {
+	if r == nil {
+		return false
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != ContentType {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Respond writes an error in the format negotiated with the client
+func Respond(c *gin.Context, status int, code, message string) {
+	if Wanted(c.Request) {
+		c.Header("Content-Type", ContentType)
+		c.JSON(status, New(status, code, message, c.Request.URL.Path))
+		return
+	}
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// Abort writes the error and stops the handler chain
+func Abort(c *gin.Context, status int, code, message string) {
+	Respond(c, status, code, message)
+	c.Abort()
+}
@@ -0,0 +1,79 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWanted(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{"*/*", false},
+		{"application/problem+json", true},
+		{"application/json, application/problem+json;q=0.9", true},
+		{"application/problem+json;q=0", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		if got := Wanted(req); got != tt.want {
+			t.Errorf("Wanted(%q) = %v, want %v", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/drivers/:id", func(c *gin.Context) {
+		Respond(c, http.StatusNotFound, "NOT_FOUND", "driver not found")
+	})
+
+	t.Run("default format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/drivers/42", nil))
+
+		var body map[string]map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		if body["error"]["code"] != "NOT_FOUND" || body["error"]["message"] != "driver not found" {
+			t.Errorf("unexpected body %s", w.Body.String())
+		}
+	})
+
+	t.Run("problem details", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/drivers/42", nil)
+		req.Header.Set("Accept", ContentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if ct := w.Header().Get("Content-Type"); ct != ContentType {
+			t.Errorf("expected content type %s, got %s", ContentType, ct)
+		}
+		var details Details
+		if err := json.Unmarshal(w.Body.Bytes(), &details); err != nil {
+			t.Fatalf("invalid body: %v", err)
+		}
+		want := Details{
+			Type:     "/problems/not-found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "driver not found",
+			Instance: "/api/v1/drivers/42",
+			Code:     "NOT_FOUND",
+		}
+		if details != want {
+			t.Errorf("expected %+v, got %+v", want, details)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Package replay streams the recorded driver activity of a time range back
+// through projectors that rebuild the read models derived from it. The service
+// keeps no separate event log: location points and shift starts and ends, as
+// recorded for statistics, are the history that is replayed. A replay
+// checkpoints its position as it goes, so an interrupted run resumes where it
+// stopped instead of starting over.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.uber.org/zap"
+)
+
+// defaultBatchSize is how many events are replayed between checkpoints by default
+const defaultBatchSize = 1000
+
+// ErrInvalidRange is returned when a replay range does not end after it starts
+var ErrInvalidRange = errors.New("replay range must end after it starts")
+
+// Projector rebuilds a read model from replayed activity
+type Projector interface {
+	Name() string
+	// Apply folds an event into the read model
+	Apply(ctx context.Context, event *domain.ActivityEvent) error
+	// Flush writes out what was applied since the last flush. The replay
+	// position is checkpointed only once every projector has flushed.
+	Flush(ctx context.Context) error
+}
+
+// Options holds the settings of a replay
+type Options struct {
+	// From and To bound the replayed activity to [From, To)
+	From, To time.Time
+	// BatchSize is how many events are replayed between checkpoints
+	BatchSize int
+	// Restart ignores the checkpoint an interrupted run left
+	Restart bool
+	// Progress receives a line at every checkpoint; nil for none
+	Progress io.Writer
+}
+
+// Result summarizes a replay
+type Result struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Projectors []string  `json:"projectors"`
+	// Resumed is set when the replay continued from a checkpoint
+	Resumed bool `json:"resumed"`
+	// Events counts the events replayed by this run, per kind in ByKind
+	Events    int            `json:"events"`
+	ByKind    map[string]int `json:"byKind"`
+	ElapsedMs int64          `json:"elapsedMs"`
+}
+
+// Replayer replays activity through projectors
+type Replayer struct {
+	log         domain.ActivityLog
+	checkpoints domain.ResumeTokenStore
+	projectors  []Projector
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// New creates a replayer of the activity log through the projectors,
+// checkpointing in the given store
+func New(log domain.ActivityLog, checkpoints domain.ResumeTokenStore, logger *zap.Logger, projectors ...Projector) *Replayer {
+	return &Replayer{
+		log:         log,
+		checkpoints: checkpoints,
+		projectors:  projectors,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Run replays the activity of the range. It resumes after the checkpoint of
+// an earlier run of the same projectors over the same range unless
+// opts.Restart is set, and clears the checkpoint once the range is replayed.
+func (r *Replayer) Run(ctx context.Context, opts Options) (*Result, error) {
+	if !opts.From.Before(opts.To) {
+		return nil, ErrInvalidRange
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	start := r.now()
+	result := &Result{From: opts.From, To: opts.To, ByKind: make(map[string]int)}
+	for _, projector := range r.projectors {
+		result.Projectors = append(result.Projectors, projector.Name())
+	}
+	name := checkpointName(result.Projectors, opts.From, opts.To)
+
+	var after *domain.ActivityPosition
+	if opts.Restart {
+		if err := r.checkpoints.SaveToken(ctx, name, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		token, err := r.checkpoints.LoadToken(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			after = &domain.ActivityPosition{}
+			if err := bson.Unmarshal(token, after); err != nil {
+				return nil, fmt.Errorf("invalid replay checkpoint %s: %w", name, err)
+			}
+			result.Resumed = true
+			r.logger.Info("resuming replay from checkpoint", zap.String("checkpoint", name), zap.Time("at", after.At))
+		}
+	}
+
+	var last *domain.ActivityPosition
+	pending := 0
+	checkpoint := func() error {
+		for _, projector := range r.projectors {
+			if err := projector.Flush(ctx); err != nil {
+				return fmt.Errorf("projector %s: %w", projector.Name(), err)
+			}
+		}
+		if last == nil {
+			return nil
+		}
+		token, err := bson.Marshal(last)
+		if err != nil {
+			return err
+		}
+		if err := r.checkpoints.SaveToken(ctx, name, token); err != nil {
+			return err
+		}
+		if opts.Progress != nil {
+			fmt.Fprintf(opts.Progress, "replayed %d events, up to %s (%.0f%%)\n",
+				result.Events, last.At.UTC().Format(time.RFC3339), progress(opts.From, opts.To, last.At))
+		}
+		return nil
+	}
+
+	err := r.log.StreamActivity(ctx, opts.From, opts.To, after, func(event *domain.ActivityEvent) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for _, projector := range r.projectors {
+			if err := projector.Apply(ctx, event); err != nil {
+				return fmt.Errorf("projector %s: %w", projector.Name(), err)
+			}
+		}
+		result.Events++
+		result.ByKind[event.Kind]++
+		last = &domain.ActivityPosition{At: event.At, ID: event.ID}
+
+		if pending++; pending >= opts.BatchSize {
+			pending = 0
+			return checkpoint()
+		}
+		return nil
+	})
+	if err == nil {
+		err = checkpoint()
+	}
+	result.ElapsedMs = r.now().Sub(start).Milliseconds()
+	if err != nil {
+		// The checkpoint stays at the last batch every projector flushed
+		r.logger.Error("replay stopped", zap.String("checkpoint", name), zap.Int("events", result.Events), zap.Error(err))
+		return result, err
+	}
+
+	// A finished replay starts over when it is run again
+	if err := r.checkpoints.SaveToken(ctx, name, nil); err != nil {
+		return result, err
+	}
+	r.logger.Info("replay complete",
+		zap.Strings("projectors", result.Projectors),
+		zap.Int("events", result.Events),
+		zap.Duration("elapsed", time.Duration(result.ElapsedMs)*time.Millisecond),
+	)
+	return result, nil
+}
+
+// checkpointName names the checkpoint of replaying a range through projectors
+func checkpointName(projectors []string, from, to time.Time) string {
+	return fmt.Sprintf("replay:%s:%s:%s", strings.Join(projectors, ","),
+		from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+}
+
+// progress returns how much of [from, to) lies before at, in percent
+func progress(from, to, at time.Time) float64 {
+	return float64(at.Sub(from)) / float64(to.Sub(from)) * 100
+}
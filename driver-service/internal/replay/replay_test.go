@@ -0,0 +1,202 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// memoryLog serves events kept in order in memory
+type memoryLog []*domain.ActivityEvent
+
+func (l memoryLog) StreamActivity(ctx interface{}, from, to time.Time, after *domain.ActivityPosition, handle func(event *domain.ActivityEvent) error) error {
+	for _, event := range l {
+		if event.At.Before(from) || !event.At.Before(to) {
+			continue
+		}
+		if after != nil && (event.At.Before(after.At) || event.At.Equal(after.At) && event.ID <= after.ID) {
+			continue
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memoryCheckpoints keeps checkpoints in memory
+type memoryCheckpoints map[string][]byte
+
+func (m memoryCheckpoints) LoadToken(ctx interface{}, stream string) ([]byte, error) {
+	return m[stream], nil
+}
+
+func (m memoryCheckpoints) SaveToken(ctx interface{}, stream string, token []byte) error {
+	if token == nil {
+		delete(m, stream)
+	} else {
+		m[stream] = token
+	}
+	return nil
+}
+
+// recordingProjector records the events it is given and fails on one of them
+type recordingProjector struct {
+	applied []string
+	flushed []string
+	failOn  string
+}
+
+func (p *recordingProjector) Name() string { return "recording" }
+
+func (p *recordingProjector) Apply(ctx context.Context, event *domain.ActivityEvent) error {
+	if event.ID == p.failOn {
+		return errors.New("read model unavailable")
+	}
+	p.applied = append(p.applied, event.ID)
+	return nil
+}
+
+func (p *recordingProjector) Flush(ctx context.Context) error {
+	p.flushed = append(p.flushed, p.applied...)
+	p.applied = nil
+	return nil
+}
+
+func activity(ids ...string) memoryLog {
+	base := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	log := make(memoryLog, len(ids))
+	for i, id := range ids {
+		log[i] = &domain.ActivityEvent{Kind: domain.ActivityLocation, DriverID: "d1", At: base.Add(time.Duration(i) * time.Hour), ID: id}
+	}
+	return log
+}
+
+func TestReplayer_Run(t *testing.T) {
+	log := activity("a", "b", "c", "d", "e")
+	checkpoints := memoryCheckpoints{}
+	opts := Options{
+		From:      time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC),
+		BatchSize: 2,
+	}
+
+	t.Run("an interrupted replay keeps the last flushed position", func(t *testing.T) {
+		projector := &recordingProjector{failOn: "d"}
+		result, err := New(log, checkpoints, zap.NewNop(), projector).Run(context.Background(), opts)
+		if err == nil || !strings.Contains(err.Error(), "projector recording") {
+			t.Fatalf("expected the projector error, got %v", err)
+		}
+		if result.Events != 3 || strings.Join(projector.flushed, "") != "ab" {
+			t.Errorf("expected 3 events replayed and a, b flushed, got %d and %v", result.Events, projector.flushed)
+		}
+		if len(checkpoints) != 1 {
+			t.Fatalf("expected a checkpoint, got %v", checkpoints)
+		}
+	})
+
+	t.Run("a rerun resumes after the checkpoint and clears it", func(t *testing.T) {
+		projector := &recordingProjector{}
+		var progress bytes.Buffer
+		opts := opts
+		opts.Progress = &progress
+		result, err := New(log, checkpoints, zap.NewNop(), projector).Run(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Resumed || result.Events != 3 || result.ByKind[domain.ActivityLocation] != 3 {
+			t.Errorf("unexpected result %+v", result)
+		}
+		if got := strings.Join(projector.flushed, ""); got != "cde" {
+			t.Errorf("expected c, d, e to be replayed, got %s", got)
+		}
+		want := "replayed 2 events, up to 2025-12-01T03:00:00Z (30%)\nreplayed 3 events, up to 2025-12-01T04:00:00Z (40%)\n"
+		if progress.String() != want {
+			t.Errorf("unexpected progress:\n%s", progress.String())
+		}
+		if len(checkpoints) != 0 {
+			t.Errorf("expected a finished replay to clear its checkpoint, got %v", checkpoints)
+		}
+	})
+
+	t.Run("restart ignores the checkpoint", func(t *testing.T) {
+		New(log, checkpoints, zap.NewNop(), &recordingProjector{failOn: "e"}).Run(context.Background(), opts)
+
+		projector := &recordingProjector{}
+		opts := opts
+		opts.Restart = true
+		result, err := New(log, checkpoints, zap.NewNop(), projector).Run(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Resumed || result.Events != 5 {
+			t.Errorf("expected all 5 events to be replayed, got %+v", result)
+		}
+	})
+
+	t.Run("rejects an empty range", func(t *testing.T) {
+		opts := opts
+		opts.To = opts.From
+		if _, err := New(log, checkpoints, zap.NewNop()).Run(context.Background(), opts); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("expected ErrInvalidRange, got %v", err)
+		}
+	})
+}
+
+// recordingUtilization records the days rolled up and treats the 6th as today
+type recordingUtilization struct {
+	usecase.UtilizationUseCase
+	days []time.Time
+}
+
+func (u *recordingUtilization) RollUpDay(ctx context.Context, at time.Time) (bool, error) {
+	u.days = append(u.days, at)
+	return at.Day() < 6, nil
+}
+
+func TestUtilizationProjector(t *testing.T) {
+	istanbul := time.FixedZone("Europe/Istanbul", 3*60*60)
+	utilization := &recordingUtilization{}
+	projector := NewUtilizationProjector(utilization, istanbul)
+	ctx := context.Background()
+
+	started := time.Date(2025, 12, 2, 22, 0, 0, 0, time.UTC)
+	events := []*domain.ActivityEvent{
+		// 22:00 UTC is the 3rd in Istanbul
+		{Kind: domain.ActivityShiftStarted, At: started},
+		{Kind: domain.ActivityLocation, At: time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)},
+		{Kind: domain.ActivityShiftEnded, At: time.Date(2025, 12, 5, 23, 0, 0, 0, time.UTC), ShiftStartedAt: &started},
+	}
+	for _, event := range events {
+		if err := projector.Apply(ctx, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := projector.Flush(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var days []string
+	for _, day := range utilization.days {
+		days = append(days, day.Format(dayLayout))
+	}
+	if got := strings.Join(days, ","); got != "2025-12-03,2025-12-04,2025-12-05,2025-12-06" {
+		t.Errorf("expected the days the shift spans to be rolled up, got %s", got)
+	}
+	if projector.RolledUp() != 3 {
+		t.Errorf("expected 3 complete days rolled up, got %d", projector.RolledUp())
+	}
+
+	// Flushed days are not rolled up again
+	projector.Flush(ctx)
+	if len(utilization.days) != 4 {
+		t.Errorf("expected no more rollups, got %d", len(utilization.days))
+	}
+}
@@ -0,0 +1,93 @@
+package replay
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+)
+
+// dayLayout keys the days a projector rolls up
+const dayLayout = "2006-01-02"
+
+// UtilizationProjector rebuilds the daily utilization rollups of every day a
+// replayed shift spans. Trips are aggregated with the shifts they fall in, and
+// location points do not count towards utilization, so they are skipped.
+type UtilizationProjector struct {
+	utilization usecase.UtilizationUseCase
+	location    *time.Location
+	days        map[string]time.Time
+	rolledUp    int
+}
+
+// NewUtilizationProjector creates a projector of the utilization rollups,
+// whose days are calendar days in location
+func NewUtilizationProjector(utilization usecase.UtilizationUseCase, location *time.Location) *UtilizationProjector {
+	if location == nil {
+		location = time.UTC
+	}
+	return &UtilizationProjector{
+		utilization: utilization,
+		location:    location,
+		days:        make(map[string]time.Time),
+	}
+}
+
+// Name implements Projector
+func (p *UtilizationProjector) Name() string {
+	return "utilization"
+}
+
+// Apply marks the days of a shift start, or of a whole shift when it ends
+func (p *UtilizationProjector) Apply(ctx context.Context, event *domain.ActivityEvent) error {
+	switch event.Kind {
+	case domain.ActivityShiftStarted:
+		p.mark(event.At, event.At)
+	case domain.ActivityShiftEnded:
+		start := event.At
+		if event.ShiftStartedAt != nil {
+			start = *event.ShiftStartedAt
+		}
+		p.mark(start, event.At)
+	}
+	return nil
+}
+
+// mark marks the days from the one of start through the one of end
+func (p *UtilizationProjector) mark(start, end time.Time) {
+	start, end = start.In(p.location), end.In(p.location)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, p.location)
+	for !day.After(end) {
+		p.days[day.Format(dayLayout)] = day
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// Flush rolls up the marked days, oldest first. Days that are not complete
+// yet are left to the regular rollup.
+func (p *UtilizationProjector) Flush(ctx context.Context) error {
+	keys := make([]string, 0, len(p.days))
+	for key := range p.days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		rolled, err := p.utilization.RollUpDay(ctx, p.days[key])
+		if err != nil {
+			return err
+		}
+		if rolled {
+			p.rolledUp++
+		}
+		delete(p.days, key)
+	}
+	return nil
+}
+
+// RolledUp returns how many day rollups the projector recomputed
+func (p *UtilizationProjector) RolledUp() int {
+	return p.rolledUp
+}
@@ -0,0 +1,472 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// locationHistoryRetention bounds how long location points are kept; it covers
+// the longest statistics range with some slack
+const locationHistoryRetention = 400 * 24 * time.Hour
+
+// earthRadiusKm is the mean Earth radius used by the distance aggregation
+const earthRadiusKm = 6371.0
+
+// ActivityRepository implements domain.ActivityRepository, domain.StatsRepository,
+// domain.LocationAnomalyRecorder and domain.ActivityLog using MongoDB
+type ActivityRepository struct {
+	locations *mongo.Collection
+	anomalies *mongo.Collection
+	shifts    *mongo.Collection
+	trips     *mongo.Collection
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewActivityRepository creates a new MongoDB activity repository
+func NewActivityRepository(db *mongo.Database, logger *zap.Logger) *ActivityRepository {
+	return &ActivityRepository{
+		locations: db.Collection("driver_locations"),
+		anomalies: db.Collection("location_anomalies"),
+		shifts:    db.Collection("driver_shifts"),
+		trips:     db.Collection("trips"),
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Indexes lists the location history, anomaly and shift indexes. A partial unique
+// index guarantees a driver has at most one open shift.
+func (r *ActivityRepository) Indexes() []IndexSet {
+	locations := IndexSet{Collection: r.locations, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "recordedAt", Value: 1}},
+			Options: options.Index().SetName("driverId_recordedAt"),
+		},
+		{
+			Keys: bson.D{{Key: "recordedAt", Value: 1}},
+			Options: options.Index().
+				SetName("recordedAt_ttl").
+				SetExpireAfterSeconds(int32(locationHistoryRetention.Seconds())),
+		},
+	}}
+	// Anomalies are kept as long as the location history they were found in
+	anomalies := IndexSet{Collection: r.anomalies, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "detectedAt", Value: 1}},
+			Options: options.Index().SetName("driverId_detectedAt"),
+		},
+		{
+			Keys: bson.D{{Key: "detectedAt", Value: 1}},
+			Options: options.Index().
+				SetName("detectedAt_ttl").
+				SetExpireAfterSeconds(int32(locationHistoryRetention.Seconds())),
+		},
+	}}
+	shifts := IndexSet{Collection: r.shifts, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "startedAt", Value: 1}},
+			Options: options.Index().SetName("driverId_startedAt"),
+		},
+		{
+			// Utilization reports look up the shifts of all drivers during a day
+			Keys:    bson.D{{Key: "startedAt", Value: 1}},
+			Options: options.Index().SetName("startedAt"),
+		},
+		{
+			// Replays look up the shifts that ended during a range
+			Keys:    bson.D{{Key: "endedAt", Value: 1}},
+			Options: options.Index().SetName("endedAt").SetSparse(true),
+		},
+		{
+			Keys: bson.D{{Key: "driverId", Value: 1}},
+			Options: options.Index().
+				SetName("driverId_open_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"open": true}),
+		},
+	}}
+	return []IndexSet{locations, anomalies, shifts}
+}
+
+// EnsureIndexes creates the location history and shift indexes
+func (r *ActivityRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create activity indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// RecordLocation appends a point to the driver's location history
+func (r *ActivityRepository) RecordLocation(ctx interface{}, driverID string, location domain.Location, at time.Time) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	_, err := r.locations.InsertOne(c, bson.M{
+		"driverId":   driverID,
+		"location":   location,
+		"recordedAt": at,
+	})
+	if err != nil {
+		r.logger.Error("failed to record driver location", zap.String("driverId", driverID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// RecordLocationAnomaly stores a location update that implied an implausible speed
+func (r *ActivityRepository) RecordLocationAnomaly(ctx interface{}, anomaly *domain.LocationAnomaly) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	if _, err := r.anomalies.InsertOne(c, anomaly); err != nil {
+		r.logger.Error("failed to record location anomaly", zap.String("driverId", anomaly.DriverID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// StartShift opens a shift for the driver; it is a no-op when a shift is already open
+func (r *ActivityRepository) StartShift(ctx interface{}, driverID string, at time.Time) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	_, err := r.shifts.UpdateOne(c,
+		bson.M{"driverId": driverID, "open": true},
+		bson.M{"$setOnInsert": bson.M{"startedAt": at}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		r.logger.Error("failed to start shift", zap.String("driverId", driverID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// EndShift closes the driver's open shift, if any
+func (r *ActivityRepository) EndShift(ctx interface{}, driverID string, at time.Time) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	_, err := r.shifts.UpdateOne(c,
+		bson.M{"driverId": driverID, "open": true},
+		bson.M{"$set": bson.M{"open": false, "endedAt": at}},
+	)
+	if err != nil {
+		r.logger.Error("failed to end shift", zap.String("driverId", driverID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// StreamActivity streams location points, shift starts and shift ends in
+// [from, to) as one history ordered by time. Shift events are identified by
+// the shift with a suffix, so the two events of a shift never share an ID.
+func (r *ActivityRepository) StreamActivity(ctx interface{}, from, to time.Time, after *domain.ActivityPosition, handle func(event *domain.ActivityEvent) error) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	window := bson.M{"$gte": from, "$lt": to}
+	shiftEvents := func(kind, field, suffix string) bson.D {
+		return bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll": r.shifts.Name(),
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{field: window}},
+				bson.M{"$project": bson.M{
+					"_id":            0,
+					"kind":           kind,
+					"driverId":       1,
+					"at":             "$" + field,
+					"id":             bson.M{"$concat": bson.A{bson.M{"$toString": "$_id"}, suffix}},
+					"shiftStartedAt": "$startedAt",
+				}},
+			},
+		}}}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"recordedAt": window}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"kind":     domain.ActivityLocation,
+			"driverId": 1,
+			"at":       "$recordedAt",
+			"id":       bson.M{"$toString": "$_id"},
+			"location": 1,
+		}}},
+		shiftEvents(domain.ActivityShiftStarted, "startedAt", ":start"),
+		shiftEvents(domain.ActivityShiftEnded, "endedAt", ":end"),
+	}
+	if after != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"at": bson.M{"$gt": after.At}},
+			bson.M{"at": after.At, "id": bson.M{"$gt": after.ID}},
+		}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "at", Value: 1}, {Key: "id", Value: 1}}}})
+
+	cursor, err := r.locations.Aggregate(c, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		r.logger.Error("failed to stream activity", zap.Error(err))
+		return err
+	}
+	defer cursor.Close(c)
+
+	for cursor.Next(c) {
+		var doc struct {
+			Kind           string           `bson:"kind"`
+			DriverID       string           `bson:"driverId"`
+			At             time.Time        `bson:"at"`
+			ID             string           `bson:"id"`
+			Location       *domain.Location `bson:"location"`
+			ShiftStartedAt *time.Time       `bson:"shiftStartedAt"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		event := &domain.ActivityEvent{
+			Kind:     doc.Kind,
+			DriverID: doc.DriverID,
+			At:       doc.At,
+			ID:       doc.ID,
+			Location: doc.Location,
+		}
+		if doc.Kind == domain.ActivityShiftEnded {
+			event.ShiftStartedAt = doc.ShiftStartedAt
+		}
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// DriverStats aggregates completed trips, distance driven and online hours in [from, to)
+func (r *ActivityRepository) DriverStats(ctx interface{}, driverID string, from, to time.Time) (*domain.DriverStats, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	stats := &domain.DriverStats{DriverID: driverID, From: from, To: to}
+	if err := r.aggregateTrips(c, stats); err != nil {
+		r.logger.Error("failed to aggregate trips", zap.String("driverId", driverID), zap.Error(err))
+		return nil, err
+	}
+	if err := r.aggregateDistance(c, stats); err != nil {
+		r.logger.Error("failed to aggregate location history", zap.String("driverId", driverID), zap.Error(err))
+		return nil, err
+	}
+	if err := r.aggregateShifts(c, stats); err != nil {
+		r.logger.Error("failed to aggregate shifts", zap.String("driverId", driverID), zap.Error(err))
+		return nil, err
+	}
+	anomalies, err := r.anomalies.CountDocuments(c, bson.M{
+		"driverId":   driverID,
+		"detectedAt": bson.M{"$gte": from, "$lt": to},
+	})
+	if err != nil {
+		r.logger.Error("failed to count location anomalies", zap.String("driverId", driverID), zap.Error(err))
+		return nil, err
+	}
+	stats.LocationAnomalies = int(anomalies)
+	return stats, nil
+}
+
+// LocationAnomalies counts location anomalies per driver in [from, to), most first
+func (r *ActivityRepository) LocationAnomalies(ctx interface{}, from, to time.Time, limit int) ([]*domain.LocationAnomalyCount, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"detectedAt": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$driverId",
+			"count": bson.M{"$sum": 1},
+			"rejected": bson.M{"$sum": bson.M{
+				"$cond": bson.A{"$rejected", 1, 0},
+			}},
+			"maxSpeedKmh":    bson.M{"$max": "$speedKmh"},
+			"lastDetectedAt": bson.M{"$max": "$detectedAt"},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "lastDetectedAt", Value: -1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.anomalies.Aggregate(c, pipeline)
+	if err != nil {
+		r.logger.Error("failed to aggregate location anomalies", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	counts := []*domain.LocationAnomalyCount{}
+	if err := cursor.All(c, &counts); err != nil {
+		r.logger.Error("failed to decode location anomalies", zap.Error(err))
+		return nil, err
+	}
+	return counts, nil
+}
+
+// aggregateTrips counts completed trips and averages their ratings
+func (r *ActivityRepository) aggregateTrips(ctx context.Context, stats *domain.DriverStats) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"driverId":    stats.DriverID,
+			"status":      domain.TripStatusCompleted,
+			"completedAt": bson.M{"$gte": stats.From, "$lt": stats.To},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           nil,
+			"count":         bson.M{"$sum": 1},
+			"distanceKm":    bson.M{"$sum": "$distanceKm"},
+			"averageRating": bson.M{"$avg": "$rating"},
+			"ratingCount": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$isNumber": "$rating"}, 1, 0},
+			}},
+		}}},
+	}
+
+	var result struct {
+		Count         int      `bson:"count"`
+		DistanceKm    float64  `bson:"distanceKm"`
+		AverageRating *float64 `bson:"averageRating"`
+		RatingCount   int      `bson:"ratingCount"`
+	}
+	found, err := aggregateOne(ctx, r.trips, pipeline, &result)
+	if err != nil || !found {
+		return err
+	}
+
+	stats.CompletedTrips = result.Count
+	stats.TripDistanceKm = result.DistanceKm
+	stats.RatingCount = result.RatingCount
+	if result.AverageRating != nil {
+		stats.AverageRating = *result.AverageRating
+	}
+	return nil
+}
+
+// aggregateDistance sums the great-circle distance between consecutive location points
+func (r *ActivityRepository) aggregateDistance(ctx context.Context, stats *domain.DriverStats) error {
+	toRadians := func(expr interface{}) bson.M {
+		return bson.M{"$degreesToRadians": expr}
+	}
+	lat := toRadians(bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 1}})
+	lng := toRadians(bson.M{"$arrayElemAt": bson.A{"$location.coordinates", 0}})
+	prevLat := toRadians(bson.M{"$arrayElemAt": bson.A{"$prev.coordinates", 1}})
+	prevLng := toRadians(bson.M{"$arrayElemAt": bson.A{"$prev.coordinates", 0}})
+	halfSinSquared := func(a, b interface{}) bson.M {
+		return bson.M{"$pow": bson.A{
+			bson.M{"$sin": bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{a, b}}, 2}}},
+			2,
+		}}
+	}
+
+	// haversine: d = 2R * asin(sqrt(sin²(Δφ/2) + cos φ1 * cos φ2 * sin²(Δλ/2)))
+	haversine := bson.M{"$multiply": bson.A{
+		2 * earthRadiusKm,
+		bson.M{"$asin": bson.M{"$sqrt": bson.M{"$add": bson.A{
+			halfSinSquared(lat, prevLat),
+			bson.M{"$multiply": bson.A{
+				bson.M{"$cos": prevLat},
+				bson.M{"$cos": lat},
+				halfSinSquared(lng, prevLng),
+			}},
+		}}}},
+	}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"driverId":   stats.DriverID,
+			"recordedAt": bson.M{"$gte": stats.From, "$lt": stats.To},
+		}}},
+		{{Key: "$setWindowFields", Value: bson.M{
+			"sortBy": bson.M{"recordedAt": 1},
+			"output": bson.M{
+				"prev": bson.M{"$shift": bson.M{"output": "$location", "by": -1, "default": nil}},
+			},
+		}}},
+		{{Key: "$match", Value: bson.M{"prev": bson.M{"$ne": nil}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":        nil,
+			"distanceKm": bson.M{"$sum": haversine},
+		}}},
+	}
+
+	var result struct {
+		DistanceKm float64 `bson:"distanceKm"`
+	}
+	found, err := aggregateOne(ctx, r.locations, pipeline, &result)
+	if err != nil || !found {
+		return err
+	}
+	stats.DistanceDrivenKm = result.DistanceKm
+	return nil
+}
+
+// aggregateShifts sums the part of each shift that overlaps the range. Open
+// shifts count up to now.
+func (r *ActivityRepository) aggregateShifts(ctx context.Context, stats *domain.DriverStats) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"driverId":  stats.DriverID,
+			"startedAt": bson.M{"$lt": stats.To},
+			"$or": bson.A{
+				bson.M{"open": true},
+				bson.M{"endedAt": bson.M{"$gt": stats.From}},
+			},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"ms": bson.M{"$subtract": bson.A{
+				bson.M{"$min": bson.A{bson.M{"$ifNull": bson.A{"$endedAt", r.now()}}, stats.To}},
+				bson.M{"$max": bson.A{"$startedAt", stats.From}},
+			}},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": nil,
+			"ms":  bson.M{"$sum": bson.M{"$max": bson.A{"$ms", 0}}},
+		}}},
+	}
+
+	var result struct {
+		Ms int64 `bson:"ms"`
+	}
+	found, err := aggregateOne(ctx, r.shifts, pipeline, &result)
+	if err != nil || !found {
+		return err
+	}
+	stats.OnlineHours = time.Duration(result.Ms * int64(time.Millisecond)).Hours()
+	return nil
+}
+
+// aggregateOne runs a pipeline that yields at most one document and decodes it
+func aggregateOne(ctx context.Context, collection *mongo.Collection, pipeline mongo.Pipeline, out interface{}) (bool, error) {
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return false, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return false, cursor.Err()
+	}
+	return true, cursor.Decode(out)
+}
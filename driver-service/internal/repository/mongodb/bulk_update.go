@@ -0,0 +1,193 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+// bulkFilterQuery builds the query matching a bulk update filter. IDs that are
+// not ObjectIDs match no driver.
+func bulkFilterQuery(filter domain.BulkFilter) bson.M {
+	query := driverFilterQuery(domain.DriverFilter{
+		FleetID:    filter.FleetID,
+		City:       filter.City,
+		Tags:       filter.Tags,
+		Attributes: filter.Attributes,
+	})
+	if len(filter.IDs) > 0 {
+		ids := bson.A{}
+		for _, id := range filter.IDs {
+			if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objectID)
+			}
+		}
+		query["_id"] = bson.M{"$in": ids}
+	}
+	if filter.TaxiType != "" {
+		query["taxiType"] = filter.TaxiType
+	}
+	if filter.CarBrand != "" {
+		query["carBrand"] = filter.CarBrand
+	}
+	if filter.CarModel != "" {
+		query["carModel"] = filter.CarModel
+	}
+	return query
+}
+
+// patchFields returns the stored fields a patch sets
+func patchFields(patch domain.DriverPatch) bson.M {
+	set := bson.M{}
+	if patch.CarBrand != nil {
+		set["carBrand"] = *patch.CarBrand
+	}
+	if patch.CarModel != nil {
+		set["carModel"] = *patch.CarModel
+	}
+	if patch.TaxiType != nil {
+		set["taxiType"] = *patch.TaxiType
+	}
+	if patch.Suspended != nil {
+		set["suspended"] = *patch.Suspended
+		set["suspensionReason"] = ""
+		if *patch.Suspended {
+			set["suspensionReason"] = patch.SuspendReason
+			set["available"] = false
+		}
+	}
+	return set
+}
+
+// BulkUpdateDrivers applies patch to the drivers matching filter. The drivers
+// the patch changes are looked up first, for the caller's events and the geo
+// cache, then updated with one UpdateMany that re-checks the filter and only
+// touches drivers that still differ, so a driver changed in between is not
+// overwritten with a stale match.
+func (r *DriverRepository) BulkUpdateDrivers(ctx interface{}, filter domain.BulkFilter, patch domain.DriverPatch, limit int, dryRun bool, at time.Time) (*domain.BulkUpdateResult, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	query := bulkFilterQuery(filter)
+	result := &domain.BulkUpdateResult{}
+	err := r.retrier.Do(c, "count bulk update drivers", true, func() (err error) {
+		result.Matched, err = r.collection.CountDocuments(c, query)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to count bulk update drivers", zap.Error(err))
+		return nil, err
+	}
+	if result.Matched > int64(limit) {
+		return result, domain.ErrBulkUpdateTooLarge
+	}
+
+	var docs []driverDocument
+	err = r.retrier.Do(c, "find bulk update drivers", true, func() error {
+		cursor, err := r.collection.Find(c, query)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
+	if err != nil {
+		r.logger.Error("failed to find bulk update drivers", zap.Error(err))
+		return nil, err
+	}
+	drivers, err := r.openAll(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := bson.A{}
+	for i, driver := range drivers {
+		patched := *driver
+		if patch.Apply(&patched) {
+			result.Changed = append(result.Changed, driver)
+			ids = append(ids, docs[i].ID)
+		}
+	}
+	result.Modified = int64(len(result.Changed))
+	if dryRun || len(result.Changed) == 0 {
+		return result, nil
+	}
+
+	set := patchFields(patch)
+	differs := bson.A{}
+	for field, value := range set {
+		differs = append(differs, bson.M{field: bson.M{"$ne": value}})
+	}
+	query["$and"] = bson.A{bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$or": differs}}
+	set["updatedAt"] = at
+
+	// Idempotent: updated drivers no longer differ from the patch
+	err = r.retrier.Do(c, "bulk update drivers", true, func() error {
+		updated, err := r.collection.UpdateMany(c, query, bson.M{"$set": set})
+		if err == nil {
+			result.Modified = updated.ModifiedCount
+		}
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to bulk update drivers", zap.Error(err))
+		return nil, err
+	}
+
+	for _, driver := range result.Changed {
+		patched := *driver
+		patch.Apply(&patched)
+		patched.UpdatedAt = at
+		r.cacheDriver(patched.ID, &patched)
+	}
+	return result, nil
+}
+
+// bulkUpdateDocument is the stored audit record of a bulk update
+type bulkUpdateDocument struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Filter    domain.BulkFilter  `bson:"filter"`
+	Patch     domain.DriverPatch `bson:"patch"`
+	DryRun    bool               `bson:"dryRun"`
+	Matched   int64              `bson:"matched"`
+	Modified  int64              `bson:"modified"`
+	ActorID   string             `bson:"actorId,omitempty"`
+	ActorRole string             `bson:"actorRole,omitempty"`
+	TenantID  string             `bson:"tenantId,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// RecordBulkUpdate stores the audit record of a bulk update
+func (r *DriverRepository) RecordBulkUpdate(ctx interface{}, update *domain.BulkUpdate) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &bulkUpdateDocument{
+		ID:        primitive.NewObjectID(),
+		Filter:    update.Filter,
+		Patch:     update.Patch,
+		DryRun:    update.DryRun,
+		Matched:   update.Matched,
+		Modified:  update.Modified,
+		ActorID:   update.ActorID,
+		ActorRole: update.ActorRole,
+		TenantID:  update.TenantID,
+		CreatedAt: update.CreatedAt,
+	}
+	if _, err := r.bulkUpdates.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to record bulk update", zap.Error(err))
+		return err
+	}
+
+	update.ID = doc.ID.Hex()
+	return nil
+}
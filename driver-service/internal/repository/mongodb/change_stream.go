@@ -0,0 +1,158 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Server error codes of change streams that cannot go on
+const (
+	codeChangeStreamFatal       = 280
+	codeChangeStreamHistoryLost = 286
+	codeChangeStreamNotReplica  = 40573
+)
+
+// changeEvent is a change stream event of the drivers collection
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID primitive.ObjectID `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument *driverDocument     `bson:"fullDocument"`
+	ClusterTime  primitive.Timestamp `bson:"clusterTime"`
+}
+
+// WatchChanges streams inserts, updates, replacements and deletes of drivers,
+// starting after resumeToken or, when it is nil, from now. Updated drivers are
+// read back in full, so a change carries the driver as stored at read time.
+// It returns when ctx is cancelled, the stream fails or handle returns an error.
+func (r *DriverRepository) WatchChanges(ctx interface{}, resumeToken []byte, handle func(change *domain.DriverChange, token []byte) error) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"operationType": bson.M{"$in": bson.A{domain.ChangeInsert, domain.ChangeUpdate, domain.ChangeReplace, domain.ChangeDelete}},
+	}}}}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != nil {
+		opts.SetStartAfter(bson.Raw(resumeToken))
+	}
+
+	stream, err := r.collection.Watch(c, pipeline, opts)
+	if err != nil {
+		return changeStreamError(err)
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(c) {
+		var event changeEvent
+		if err := stream.Decode(&event); err != nil {
+			return fmt.Errorf("failed to decode driver change: %w", err)
+		}
+		change := &domain.DriverChange{
+			Operation: event.OperationType,
+			DriverID:  event.DocumentKey.ID.Hex(),
+			At:        time.Unix(int64(event.ClusterTime.T), 0).UTC(),
+		}
+		if event.FullDocument != nil {
+			if change.Driver, err = r.open(event.FullDocument); err != nil {
+				return err
+			}
+		}
+		if err := handle(change, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+	return changeStreamError(stream.Err())
+}
+
+// changeStreamError maps the server errors of streams that cannot be resumed
+// or started to domain errors
+func changeStreamError(err error) error {
+	var serverErr mongo.ServerError
+	if err == nil || !errors.As(err, &serverErr) {
+		return err
+	}
+	switch {
+	case serverErr.HasErrorCode(codeChangeStreamHistoryLost), serverErr.HasErrorCode(codeChangeStreamFatal):
+		return fmt.Errorf("%w: %v", domain.ErrChangeHistoryLost, err)
+	case serverErr.HasErrorCode(codeChangeStreamNotReplica):
+		return fmt.Errorf("%w: %v", domain.ErrChangeStreamUnsupported, err)
+	}
+	return err
+}
+
+// ResumeTokenRepository implements domain.ResumeTokenStore using MongoDB
+type ResumeTokenRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// NewResumeTokenRepository creates a new MongoDB resume token repository
+func NewResumeTokenRepository(db *mongo.Database, logger *zap.Logger) *ResumeTokenRepository {
+	return &ResumeTokenRepository{
+		collection: db.Collection("changeStreamTokens"),
+		logger:     logger,
+	}
+}
+
+// resumeTokenDocument is the stored position of one change stream
+type resumeTokenDocument struct {
+	Stream    string    `bson:"_id"`
+	Token     bson.Raw  `bson:"token"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// LoadToken returns the stored token of the stream, or nil if there is none
+func (r *ResumeTokenRepository) LoadToken(ctx interface{}, stream string) ([]byte, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var doc resumeTokenDocument
+	err := r.collection.FindOne(c, bson.M{"_id": stream}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to load resume token", zap.Error(err), zap.String("stream", stream))
+		return nil, err
+	}
+	return doc.Token, nil
+}
+
+// SaveToken stores the token of the stream; a nil token clears it
+func (r *ResumeTokenRepository) SaveToken(ctx interface{}, stream string, token []byte) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var err error
+	if token == nil {
+		_, err = r.collection.DeleteOne(c, bson.M{"_id": stream})
+	} else {
+		_, err = r.collection.UpdateOne(c,
+			bson.M{"_id": stream},
+			bson.M{"$set": bson.M{"token": bson.Raw(token), "updatedAt": time.Now()}},
+			options.Update().SetUpsert(true),
+		)
+	}
+	if err != nil {
+		r.logger.Error("failed to save resume token", zap.Error(err), zap.String("stream", stream))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// DeviceTokenRepository implements domain.DeviceTokenRepository using MongoDB
+type DeviceTokenRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// deviceTokenDocument is the stored representation of a device token
+type deviceTokenDocument struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	DriverID   string             `bson:"driverId"`
+	DeviceID   string             `bson:"deviceId"`
+	Name       string             `bson:"name,omitempty"`
+	TokenHash  string             `bson:"tokenHash"`
+	Prefix     string             `bson:"prefix"`
+	Scopes     []string           `bson:"scopes"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time         `bson:"revokedAt,omitempty"`
+	ReplacedBy string             `bson:"replacedBy,omitempty"`
+}
+
+func (d *deviceTokenDocument) toDomain() *domain.DeviceToken {
+	return &domain.DeviceToken{
+		ID:         d.ID.Hex(),
+		DriverID:   d.DriverID,
+		DeviceID:   d.DeviceID,
+		Name:       d.Name,
+		TokenHash:  d.TokenHash,
+		Prefix:     d.Prefix,
+		Scopes:     d.Scopes,
+		CreatedAt:  d.CreatedAt,
+		LastUsedAt: d.LastUsedAt,
+		RevokedAt:  d.RevokedAt,
+		ReplacedBy: d.ReplacedBy,
+	}
+}
+
+// NewDeviceTokenRepository creates a new MongoDB device token repository
+func NewDeviceTokenRepository(db *mongo.Database, logger *zap.Logger) *DeviceTokenRepository {
+	return &DeviceTokenRepository{
+		collection: db.Collection("device_tokens"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the token lookup and per-driver listing indexes
+func (r *DeviceTokenRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetName("tokenHash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("driverId_createdAt"),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the device token indexes
+func (r *DeviceTokenRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create device token indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new token
+func (r *DeviceTokenRepository) Create(ctx interface{}, token *domain.DeviceToken) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &deviceTokenDocument{
+		ID:        primitive.NewObjectID(),
+		DriverID:  token.DriverID,
+		DeviceID:  token.DeviceID,
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Prefix:    token.Prefix,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create device token", zap.Error(err), zap.String("driverId", token.DriverID))
+		return err
+	}
+
+	token.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetByID retrieves a token by ID
+func (r *DeviceTokenRepository) GetByID(ctx interface{}, id string) (*domain.DeviceToken, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("device token not found")
+	}
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+// GetByHash retrieves the token with the given hash
+func (r *DeviceTokenRepository) GetByHash(ctx interface{}, tokenHash string) (*domain.DeviceToken, error) {
+	return r.findOne(ctx, bson.M{"tokenHash": tokenHash})
+}
+
+func (r *DeviceTokenRepository) findOne(ctx interface{}, filter bson.M) (*domain.DeviceToken, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var doc deviceTokenDocument
+	if err := r.collection.FindOne(c, filter).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("device token not found")
+		}
+		r.logger.Error("failed to get device token", zap.Error(err))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List returns tokens newest first
+func (r *DeviceTokenRepository) List(ctx interface{}, driverID string, activeOnly bool) ([]*domain.DeviceToken, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{}
+	if driverID != "" {
+		filter["driverId"] = driverID
+	}
+	if activeOnly {
+		filter["revokedAt"] = bson.M{"$exists": false}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(c, filter, opts)
+	if err != nil {
+		r.logger.Error("failed to list device tokens", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []deviceTokenDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode device tokens", zap.Error(err))
+		return nil, err
+	}
+	tokens := make([]*domain.DeviceToken, len(docs))
+	for i := range docs {
+		tokens[i] = docs[i].toDomain()
+	}
+	return tokens, nil
+}
+
+// Revoke marks an active token revoked
+func (r *DeviceTokenRepository) Revoke(ctx interface{}, id, replacedBy string, at time.Time) (bool, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, errors.New("device token not found")
+	}
+
+	set := bson.M{"revokedAt": at}
+	if replacedBy != "" {
+		set["replacedBy"] = replacedBy
+	}
+	filter := bson.M{"_id": objectID, "revokedAt": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateOne(c, filter, bson.M{"$set": set})
+	if err != nil {
+		r.logger.Error("failed to revoke device token", zap.Error(err), zap.String("id", id))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// RevokeDevice revokes the active tokens of a driver's device
+func (r *DeviceTokenRepository) RevokeDevice(ctx interface{}, driverID, deviceID string, at time.Time) (int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{"driverId": driverID, "deviceId": deviceID, "revokedAt": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateMany(c, filter, bson.M{"$set": bson.M{"revokedAt": at}})
+	if err != nil {
+		r.logger.Error("failed to revoke device tokens", zap.Error(err), zap.String("driverId", driverID))
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// Touch records when the token was last used
+func (r *DeviceTokenRepository) Touch(ctx interface{}, id string, at time.Time) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("device token not found")
+	}
+
+	if _, err := r.collection.UpdateOne(c, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"lastUsedAt": at}}); err != nil {
+		r.logger.Error("failed to touch device token", zap.Error(err), zap.String("id", id))
+		return err
+	}
+	return nil
+}
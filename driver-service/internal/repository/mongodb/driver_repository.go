@@ -3,9 +3,13 @@ package mongodb
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/fieldcrypt"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/geoindex"
 	"github.com/bitaksi/driver-service/pkg/haversine"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,15 +21,400 @@ import (
 // DriverRepository implements domain.DriverRepository using MongoDB
 type DriverRepository struct {
 	collection *mongo.Collection
+	// bulkUpdates keeps the audit records of bulk updates
+	bulkUpdates *mongo.Collection
+	// reads and writeReads are the collection read through by requests that
+	// only read and by requests that write; see WithReadPreferences
+	reads      *mongo.Collection
+	writeReads *mongo.Collection
 	logger     *zap.Logger
+	// cipher protects firstName, lastName and phone at rest; nil stores them as plaintext
+	cipher fieldcrypt.Cipher
+	hasher fieldcrypt.Hasher
+	// retrier retries operations that fail while the replica set fails over; nil runs them once
+	retrier *Retrier
+	// cities sets the city drivers are stored with and scopes nearby searches
+	// to the cities they reach; nil leaves the city as given
+	cities *geofence.Cities
+
+	// geoNear searches MongoDB with $geoNear on the position index instead of
+	// reading every candidate; see WithGeoNear
+	geoNear bool
+
+	// geo answers nearby searches from memory while it is fresh; nil always queries MongoDB
+	geo          *geoindex.Index
+	geoMaxStale  time.Duration
+	geoLoaded    bool
+	geoChanges   domain.ChangePosition
+	geoHeartbeat time.Time
+}
+
+// DriverRepositoryOption configures optional repository behavior
+type DriverRepositoryOption func(*DriverRepository)
+
+// WithFieldEncryption encrypts personal fields at rest. The hasher provides the
+// phone lookup hash used for uniqueness and GetByPhone, since ciphertexts are randomized.
+func WithFieldEncryption(cipher fieldcrypt.Cipher, hasher fieldcrypt.Hasher) DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.cipher = cipher
+		r.hasher = hasher
+	}
+}
+
+// WithRetries retries operations that fail with transient errors
+func WithRetries(retrier *Retrier) DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.retrier = retrier
+	}
+}
+
+// WithCities stores drivers with the city their location is in. Nearby
+// searches then only read the drivers of the cities the search circle reaches,
+// and the drivers collection can be sharded by city; see ShardByCity.
+func WithCities(cities *geofence.Cities) DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.cities = cities
+	}
+}
+
+// WithGeoNear lets MongoDB find, sort and page nearby drivers with $geoNear
+// on the position index, so a search reads only the drivers it returns. Drivers
+// stored before positions existed are not found until BackfillPositions ran.
+func WithGeoNear() DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.geoNear = true
+	}
+}
+
+// WithGeoCache answers nearby searches from an in-memory index of driver
+// positions. Writes through the repository update the index at once; RunGeoCache
+// picks up the writes of other instances. Searches fall back to MongoDB while
+// the index has not been refreshed within maxStaleness.
+func WithGeoCache(index *geoindex.Index, maxStaleness time.Duration) DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.geo = index
+		r.geoMaxStale = maxStaleness
+	}
+}
+
+// driverDocument is the stored representation of a driver with a native ObjectID
+type driverDocument struct {
+	ID                primitive.ObjectID      `bson:"_id"`
+	FirstName         string                  `bson:"firstName"`
+	LastName          string                  `bson:"lastName"`
+	Plate             string                  `bson:"plate"`
+	TaxiType          domain.TaxiType         `bson:"taxiType"`
+	CarBrand          string                  `bson:"carBrand"`
+	CarModel          string                  `bson:"carModel"`
+	Location          domain.Location         `bson:"location"`
+	Position          *geoPoint               `bson:"position,omitempty"`
+	LocationUpdatedAt *time.Time              `bson:"locationUpdatedAt,omitempty"`
+	City              string                  `bson:"city,omitempty"`
+	Phone             string                  `bson:"phone,omitempty"`
+	PhoneHash         string                  `bson:"phoneHash,omitempty"`
+	SearchKeys        []string                `bson:"searchKeys,omitempty"`
+	Email             string                  `bson:"email,omitempty"`
+	PhoneVerified     bool                    `bson:"phoneVerified"`
+	EmailVerified     bool                    `bson:"emailVerified"`
+	IdentityVerified  bool                    `bson:"identityVerified,omitempty"`
+	Available         bool                    `bson:"available"`
+	Suspended         bool                    `bson:"suspended,omitempty"`
+	SuspendReason     string                  `bson:"suspensionReason,omitempty"`
+	License           *domain.DriverLicense   `bson:"license,omitempty"`
+	LicenseExpired    bool                    `bson:"licenseExpired,omitempty"`
+	Rating            float64                 `bson:"rating"`
+	RatingCount       int                     `bson:"ratingCount"`
+	FleetID           string                  `bson:"fleetId,omitempty"`
+	Tags              []string                `bson:"tags,omitempty"`
+	Attributes        map[string]string       `bson:"attributes,omitempty"`
+	LastSeenAt        *time.Time              `bson:"lastSeenAt,omitempty"`
+	Photo             *domain.DriverPhoto     `bson:"photo,omitempty"`
+	Documents         []domain.DriverDocument `bson:"documents,omitempty"`
+	CreatedAt         time.Time               `bson:"createdAt"`
+	UpdatedAt         time.Time               `bson:"updatedAt"`
+	DeletedAt         *time.Time              `bson:"deletedAt,omitempty"`
+}
+
+// geoPoint is a GeoJSON point, the form the 2dsphere position index holds
+type geoPoint struct {
+	Type string `bson:"type"`
+	// Coordinates are longitude, then latitude
+	Coordinates []float64 `bson:"coordinates"`
+}
+
+// positionOf returns the indexed position of a location, or nil when the
+// location is 0,0 or out of range, which nearby searches skip
+func positionOf(location domain.Location) *geoPoint {
+	if location.Lat == 0 && location.Lon == 0 {
+		return nil
+	}
+	if location.Lat < -90 || location.Lat > 90 || location.Lon < -180 || location.Lon > 180 {
+		return nil
+	}
+	return &geoPoint{Type: "Point", Coordinates: []float64{location.Lon, location.Lat}}
+}
+
+// toDomain converts the stored document into a domain driver
+func (d *driverDocument) toDomain() *domain.Driver {
+	return &domain.Driver{
+		ID:                d.ID.Hex(),
+		FirstName:         d.FirstName,
+		LastName:          d.LastName,
+		Plate:             d.Plate,
+		TaxiType:          d.TaxiType,
+		CarBrand:          d.CarBrand,
+		CarModel:          d.CarModel,
+		Location:          d.Location,
+		LocationUpdatedAt: d.LocationUpdatedAt,
+		City:              d.City,
+		Phone:             d.Phone,
+		Email:             d.Email,
+		PhoneVerified:     d.PhoneVerified,
+		EmailVerified:     d.EmailVerified,
+		IdentityVerified:  d.IdentityVerified,
+		Available:         d.Available,
+		Suspended:         d.Suspended,
+		SuspendReason:     d.SuspendReason,
+		License:           d.License,
+		LicenseExpired:    d.LicenseExpired,
+		Rating:            d.Rating,
+		RatingCount:       d.RatingCount,
+		FleetID:           d.FleetID,
+		Tags:              d.Tags,
+		Attributes:        d.Attributes,
+		LastSeenAt:        d.LastSeenAt,
+		Photo:             d.Photo,
+		Documents:         d.Documents,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		DeletedAt:         d.DeletedAt,
+	}
+}
+
+// newDriverDocument builds the stored representation of a driver
+func newDriverDocument(id primitive.ObjectID, driver *domain.Driver) *driverDocument {
+	return &driverDocument{
+		ID:                id,
+		FirstName:         driver.FirstName,
+		LastName:          driver.LastName,
+		Plate:             driver.Plate,
+		TaxiType:          driver.TaxiType,
+		CarBrand:          driver.CarBrand,
+		CarModel:          driver.CarModel,
+		Location:          driver.Location,
+		Position:          positionOf(driver.Location),
+		LocationUpdatedAt: driver.LocationUpdatedAt,
+		City:              driver.City,
+		Phone:             driver.Phone,
+		SearchKeys:        domain.SearchKeys(driver),
+		Email:             driver.Email,
+		PhoneVerified:     driver.PhoneVerified,
+		EmailVerified:     driver.EmailVerified,
+		IdentityVerified:  driver.IdentityVerified,
+		Available:         driver.Available,
+		Suspended:         driver.Suspended,
+		SuspendReason:     driver.SuspendReason,
+		License:           driver.License,
+		LicenseExpired:    driver.LicenseExpired,
+		Rating:            driver.Rating,
+		RatingCount:       driver.RatingCount,
+		FleetID:           driver.FleetID,
+		Tags:              driver.Tags,
+		Attributes:        driver.Attributes,
+		LastSeenAt:        driver.LastSeenAt,
+		Photo:             driver.Photo,
+		Documents:         driver.Documents,
+		CreatedAt:         driver.CreatedAt,
+		UpdatedAt:         driver.UpdatedAt,
+	}
 }
 
 // NewDriverRepository creates a new MongoDB driver repository
-func NewDriverRepository(db *mongo.Database, logger *zap.Logger) *DriverRepository {
-	return &DriverRepository{
-		collection: db.Collection("drivers"),
-		logger:     logger,
+func NewDriverRepository(db *mongo.Database, logger *zap.Logger, opts ...DriverRepositoryOption) *DriverRepository {
+	collection := db.Collection("drivers")
+	r := &DriverRepository{
+		collection:  collection,
+		bulkUpdates: db.Collection("driver_bulk_updates"),
+		reads:       collection,
+		writeReads:  collection,
+		logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// seal encrypts the protected fields of the document in place, sets the phone
+// lookup hash and hashes the search keys, which are prefixes of the name
+func (r *DriverRepository) seal(doc *driverDocument) error {
+	if r.cipher == nil {
+		return nil
+	}
+	doc.PhoneHash = r.hasher.Hash(doc.Phone)
+	for i, key := range doc.SearchKeys {
+		doc.SearchKeys[i] = r.searchKey(key)
+	}
+	for _, field := range []*string{&doc.FirstName, &doc.LastName, &doc.Phone} {
+		sealed, err := r.cipher.Encrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = sealed
+	}
+	return nil
+}
+
+// searchKey is the stored form of a search key: the key itself, or its lookup
+// hash when personal fields are encrypted
+func (r *DriverRepository) searchKey(key string) string {
+	if r.hasher == nil {
+		return key
+	}
+	return r.hasher.Hash("search:" + key)
+}
+
+// locate sets the city of a driver from its location
+func (r *DriverRepository) locate(driver *domain.Driver) {
+	if r.cities != nil {
+		driver.City = r.cities.Locate(driver.Location)
+	}
+}
+
+// open converts a stored document into a domain driver, decrypting protected fields
+func (r *DriverRepository) open(doc *driverDocument) (*domain.Driver, error) {
+	driver := doc.toDomain()
+	if r.cipher == nil {
+		return driver, nil
+	}
+	for _, field := range []*string{&driver.FirstName, &driver.LastName, &driver.Phone} {
+		plain, err := r.cipher.Decrypt(*field)
+		if err != nil {
+			r.logger.Error("failed to decrypt driver field", zap.Error(err), zap.String("id", driver.ID))
+			return nil, err
+		}
+		*field = plain
+	}
+	return driver, nil
+}
+
+// openAll converts stored documents into domain drivers
+func (r *DriverRepository) openAll(docs []driverDocument) ([]*domain.Driver, error) {
+	drivers := make([]*domain.Driver, len(docs))
+	for i := range docs {
+		driver, err := r.open(&docs[i])
+		if err != nil {
+			return nil, err
+		}
+		drivers[i] = driver
+	}
+	return drivers, nil
+}
+
+// Indexes lists the indexes required by the repository. Contact fields and
+// the plate are unique only when set, so drivers without them do not collide.
+func (r *DriverRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "phone", Value: 1}},
+			Options: options.Index().
+				SetName("phone_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"phone": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().
+				SetName("email_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"email": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "plate", Value: 1}},
+			Options: options.Index().
+				SetName("plate_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"plate": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys: bson.D{{Key: "phoneHash", Value: 1}},
+			Options: options.Index().
+				SetName("phoneHash_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"phoneHash": bson.M{"$gt": ""}}),
+		},
+		{
+			Keys:    bson.D{{Key: "fleetId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("fleetId_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "lastSeenAt", Value: 1}},
+			Options: options.Index().SetName("lastSeenAt"),
+		},
+		{
+			// Searched by $geoNear; drivers without a valid location have no position and are left out
+			Keys:    bson.D{{Key: "position", Value: "2dsphere"}},
+			Options: options.Index().SetName("position_2dsphere"),
+		},
+		{
+			// CityShardKey; required before the collection can be sharded by city
+			Keys:    CityShardKey,
+			Options: options.Index().SetName("city_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "city", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("city_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "updatedAt", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("updatedAt_id"),
+		},
+		{
+			Keys: bson.D{{Key: "license.expiresAt", Value: 1}},
+			Options: options.Index().
+				SetName("license_expiresAt").
+				SetPartialFilterExpression(bson.M{"license.expiresAt": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("tags"),
+		},
+		{
+			// Autocomplete matches exact keys; each key is a prefix of a name or plate word
+			Keys:    bson.D{{Key: "searchKeys", Value: 1}},
+			Options: options.Index().SetName("searchKeys"),
+		},
+		{
+			// Attribute keys are free-form, so one wildcard index covers them all
+			Keys:    bson.D{{Key: "attributes.$**", Value: 1}},
+			Options: options.Index().SetName("attributes_wildcard"),
+		},
+		{
+			// The retention job finds tombstones whose personal data is due for erasure
+			Keys: bson.D{{Key: "deletedAt", Value: 1}},
+			Options: options.Index().
+				SetName("deletedAt").
+				SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$exists": true}}),
+		},
+	}}}
+}
+
+// CityShardKey is the key the drivers collection is sharded by. The city comes
+// first so queries for one city are routed to the shards holding it, and with
+// zones a busy city can be given shards of its own; _id splits a city across
+// chunks.
+var CityShardKey = bson.D{{Key: "city", Value: 1}, {Key: "_id", Value: 1}}
+
+// notDeleted matches drivers that have not been deleted
+var notDeleted = bson.M{"$exists": false}
+
+// EnsureIndexes creates the indexes required by the repository
+func (r *DriverRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create driver indexes", zap.Error(err))
+		return err
 	}
+	return nil
 }
 
 // Create inserts a new driver into MongoDB
@@ -34,21 +423,96 @@ func (r *DriverRepository) Create(ctx interface{}, driver *domain.Driver) error
 	if !ok {
 		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
 	driver.CreatedAt = time.Now()
 	driver.UpdatedAt = time.Now()
 
-	result, err := r.collection.InsertOne(c, driver)
+	objectID := primitive.NewObjectID()
+	if existing, err := primitive.ObjectIDFromHex(driver.ID); err == nil {
+		objectID = existing
+	}
+
+	r.locate(driver)
+	doc := newDriverDocument(objectID, driver)
+	if err := r.seal(doc); err != nil {
+		r.logger.Error("failed to encrypt driver fields", zap.Error(err))
+		return err
+	}
+
+	// The ID is fixed before the first attempt, so a retry that hits a duplicate
+	// _id means an earlier attempt was applied and only its reply got lost
+	attempt := 0
+	err := r.retrier.Do(c, "create driver", true, func() error {
+		attempt++
+		_, err := r.collection.InsertOne(c, doc)
+		if attempt > 1 && mongo.IsDuplicateKeyError(err) && r.exists(c, objectID) {
+			return nil
+		}
+		return err
+	})
 	if err != nil {
+		if dupErr := duplicateContact(err); dupErr != nil {
+			return dupErr
+		}
 		r.logger.Error("failed to create driver", zap.Error(err))
 		return err
 	}
 
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		driver.ID = oid.Hex()
+	driver.ID = doc.ID.Hex()
+	r.cacheDriver(driver.ID, driver)
+	return nil
+}
+
+// CreateMany inserts drivers in one unordered batch and returns how many were
+// stored. Drivers whose phone, email or plate is already taken are skipped; any other
+// write error fails the batch. It is meant for bulk loads such as seeding and
+// keeps the timestamps the drivers already carry.
+func (r *DriverRepository) CreateMany(ctx context.Context, drivers []*domain.Driver) (int, error) {
+	docs := make([]interface{}, len(drivers))
+	for i, driver := range drivers {
+		if driver.CreatedAt.IsZero() {
+			driver.CreatedAt = time.Now()
+		}
+		if driver.UpdatedAt.IsZero() {
+			driver.UpdatedAt = driver.CreatedAt
+		}
+		r.locate(driver)
+		doc := newDriverDocument(primitive.NewObjectID(), driver)
+		if err := r.seal(doc); err != nil {
+			r.logger.Error("failed to encrypt driver fields", zap.Error(err))
+			return 0, err
+		}
+		docs[i] = doc
 	}
 
-	return nil
+	result, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	inserted := 0
+	if result != nil {
+		inserted = len(result.InsertedIDs)
+	}
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				r.logger.Error("failed to insert drivers", zap.Error(err))
+				return inserted, err
+			}
+		}
+		return len(drivers) - len(bulkErr.WriteErrors), nil
+	}
+	if err != nil {
+		r.logger.Error("failed to insert drivers", zap.Error(err))
+		return inserted, err
+	}
+	return inserted, nil
+}
+
+// exists reports whether a driver with the given ID is stored
+func (r *DriverRepository) exists(ctx context.Context, id primitive.ObjectID) bool {
+	n, err := r.collection.CountDocuments(ctx, bson.M{"_id": id}, options.Count().SetLimit(1))
+	return err == nil && n > 0
 }
 
 // Update updates an existing driver in MongoDB
@@ -57,6 +521,8 @@ func (r *DriverRepository) Update(ctx interface{}, id string, driver *domain.Dri
 	if !ok {
 		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -65,23 +531,142 @@ func (r *DriverRepository) Update(ctx interface{}, id string, driver *domain.Dri
 
 	driver.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": objectID}
+	r.locate(driver)
+	doc := newDriverDocument(objectID, driver)
+	if err := r.seal(doc); err != nil {
+		r.logger.Error("failed to encrypt driver fields", zap.Error(err), zap.String("id", id))
+		return err
+	}
+
+	filter := bson.M{"_id": objectID, "deletedAt": notDeleted}
+	set := bson.M{
+		"firstName":         doc.FirstName,
+		"lastName":          doc.LastName,
+		"plate":             driver.Plate,
+		"taxiType":          driver.TaxiType,
+		"carBrand":          driver.CarBrand,
+		"carModel":          driver.CarModel,
+		"location":          driver.Location,
+		"locationUpdatedAt": driver.LocationUpdatedAt,
+		"phone":             doc.Phone,
+		"phoneHash":         doc.PhoneHash,
+		"searchKeys":        doc.SearchKeys,
+		"email":             driver.Email,
+		"phoneVerified":     driver.PhoneVerified,
+		"emailVerified":     driver.EmailVerified,
+		"identityVerified":  driver.IdentityVerified,
+		"available":         driver.Available,
+		"suspended":         driver.Suspended,
+		"suspensionReason":  driver.SuspendReason,
+		"license":           driver.License,
+		"licenseExpired":    driver.LicenseExpired,
+		"rating":            driver.Rating,
+		"ratingCount":       driver.RatingCount,
+		"fleetId":           driver.FleetID,
+		"tags":              driver.Tags,
+		"attributes":        driver.Attributes,
+		"photo":             driver.Photo,
+		"documents":         driver.Documents,
+		"updatedAt":         driver.UpdatedAt,
+	}
+	// Drivers keep the city they were stored with while cities are not configured
+	if driver.City != "" {
+		set["city"] = driver.City
+	}
+	update := bson.M{"$set": set}
+	if doc.Position != nil {
+		set["position"] = doc.Position
+	} else {
+		update["$unset"] = bson.M{"position": ""}
+	}
+
+	var result *mongo.UpdateResult
+	err = r.retrier.Do(c, "update driver", true, func() (err error) {
+		result, err = r.collection.UpdateOne(c, filter, update)
+		return err
+	})
+	if err != nil {
+		if dupErr := duplicateContact(err); dupErr != nil {
+			return dupErr
+		}
+		r.logger.Error("failed to update driver", zap.Error(err), zap.String("id", id))
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("driver not found")
+	}
+
+	r.cacheDriver(id, driver)
+	return nil
+}
+
+// duplicateContact maps a write rejected by the phone, email or plate unique
+// index to the domain error naming the field, and returns nil for any other error
+func duplicateContact(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	switch msg := err.Error(); {
+	case strings.Contains(msg, "email_unique"):
+		return domain.ErrDuplicateEmail
+	case strings.Contains(msg, "phone_unique"), strings.Contains(msg, "phoneHash_unique"):
+		return domain.ErrDuplicatePhone
+	case strings.Contains(msg, "plate_unique"):
+		return domain.ErrDuplicatePlate
+	}
+	return nil
+}
+
+// Delete removes a driver by ID. The document is kept as a tombstone with
+// deletedAt set so delta syncs can report the deletion; personal and contact
+// fields and the plate are cleared, which frees the phone, email and plate
+// for new drivers.
+func (r *DriverRepository) Delete(ctx interface{}, id string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid driver ID")
+	}
+
+	now := time.Now()
 	update := bson.M{
 		"$set": bson.M{
-			"firstName": driver.FirstName,
-			"lastName":  driver.LastName,
-			"plate":     driver.Plate,
-			"taxiType":  driver.TaxiType,
-			"carBrand":  driver.CarBrand,
-			"carModel":  driver.CarModel,
-			"location":  driver.Location,
-			"updatedAt": driver.UpdatedAt,
+			"deletedAt": now,
+			"updatedAt": now,
+			"available": false,
+		},
+		"$unset": bson.M{
+			"firstName":         "",
+			"lastName":          "",
+			"phone":             "",
+			"phoneHash":         "",
+			"searchKeys":        "",
+			"email":             "",
+			"plate":             "",
+			"location":          "",
+			"position":          "",
+			"locationUpdatedAt": "",
+			"documents":         "",
+			"license":           "",
+			"photo":             "",
 		},
 	}
 
-	result, err := r.collection.UpdateOne(c, filter, update)
+	// Not idempotent: a retry after a lost reply would report the driver as missing
+	var result *mongo.UpdateResult
+	err = r.retrier.Do(c, "delete driver", false, func() (err error) {
+		result, err = r.collection.UpdateOne(c, bson.M{"_id": objectID, "deletedAt": notDeleted}, update)
+		return err
+	})
 	if err != nil {
-		r.logger.Error("failed to update driver", zap.Error(err), zap.String("id", id))
+		r.logger.Error("failed to delete driver", zap.Error(err), zap.String("id", id))
 		return err
 	}
 
@@ -89,6 +674,9 @@ func (r *DriverRepository) Update(ctx interface{}, id string, driver *domain.Dri
 		return errors.New("driver not found")
 	}
 
+	if r.geo != nil {
+		r.geo.Remove(id)
+	}
 	return nil
 }
 
@@ -98,16 +686,20 @@ func (r *DriverRepository) GetByID(ctx interface{}, id string) (*domain.Driver,
 	if !ok {
 		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, errors.New("invalid driver ID")
 	}
 
-	var driver domain.Driver
-	filter := bson.M{"_id": objectID}
+	var doc driverDocument
+	filter := bson.M{"_id": objectID, "deletedAt": notDeleted}
 
-	err = r.collection.FindOne(c, filter).Decode(&driver)
+	err = r.retrier.Do(c, "get driver", true, func() error {
+		return r.readsFor(c).FindOne(c, filter).Decode(&doc)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, errors.New("driver not found")
@@ -116,21 +708,27 @@ func (r *DriverRepository) GetByID(ctx interface{}, id string) (*domain.Driver,
 		return nil, err
 	}
 
-	driver.ID = objectID.Hex()
-	return &driver, nil
+	return r.open(&doc)
 }
 
 // List retrieves a paginated list of drivers
-func (r *DriverRepository) List(ctx interface{}, page, pageSize int) ([]*domain.Driver, int64, error) {
+func (r *DriverRepository) List(ctx interface{}, filter domain.DriverFilter, page, pageSize int) ([]*domain.Driver, int64, error) {
 	c, ok := ctx.(context.Context)
 	if !ok {
 		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
 	skip := (page - 1) * pageSize
+	query := driverFilterQuery(filter)
 
 	// Get total count
-	totalCount, err := r.collection.CountDocuments(c, bson.M{})
+	var totalCount int64
+	err := r.retrier.Do(c, "count drivers", true, func() (err error) {
+		totalCount, err = r.readsFor(c).CountDocuments(c, query)
+		return err
+	})
 	if err != nil {
 		r.logger.Error("failed to count drivers", zap.Error(err))
 		return nil, 0, err
@@ -142,90 +740,499 @@ func (r *DriverRepository) List(ctx interface{}, page, pageSize int) ([]*domain.
 	findOptions.SetLimit(int64(pageSize))
 	findOptions.SetSort(bson.M{"createdAt": -1})
 
-	cursor, err := r.collection.Find(c, bson.M{}, findOptions)
+	var driversData []driverDocument
+	err = r.retrier.Do(c, "list drivers", true, func() error {
+		cursor, err := r.readsFor(c).Find(c, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		driversData = nil
+		return cursor.All(c, &driversData)
+	})
 	if err != nil {
 		r.logger.Error("failed to list drivers", zap.Error(err))
 		return nil, 0, err
 	}
-	defer cursor.Close(c)
 
-	var driversData []struct {
-		ID        primitive.ObjectID `bson:"_id"`
-		FirstName string             `bson:"firstName"`
-		LastName  string             `bson:"lastName"`
-		Plate     string             `bson:"plate"`
-		TaxiType  domain.TaxiType    `bson:"taxiType"`
-		CarBrand  string             `bson:"carBrand"`
-		CarModel  string             `bson:"carModel"`
-		Location  domain.Location    `bson:"location"`
-		CreatedAt time.Time          `bson:"createdAt"`
-		UpdatedAt time.Time          `bson:"updatedAt"`
-	}
-
-	if err = cursor.All(c, &driversData); err != nil {
-		r.logger.Error("failed to decode drivers", zap.Error(err))
+	// Convert to domain.Driver with string ID
+	drivers, err := r.openAll(driversData)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	// Convert to domain.Driver with string ID
-	drivers := make([]*domain.Driver, len(driversData))
-	for i, d := range driversData {
-		drivers[i] = &domain.Driver{
-			ID:        d.ID.Hex(),
-			FirstName: d.FirstName,
-			LastName:  d.LastName,
-			Plate:     d.Plate,
-			TaxiType:  d.TaxiType,
-			CarBrand:  d.CarBrand,
-			CarModel:  d.CarModel,
-			Location:  d.Location,
-			CreatedAt: d.CreatedAt,
-			UpdatedAt: d.UpdatedAt,
+	return drivers, totalCount, nil
+}
+
+// driverFilterQuery translates a driver filter into a MongoDB query
+func driverFilterQuery(filter domain.DriverFilter) bson.M {
+	query := bson.M{"deletedAt": notDeleted}
+	if filter.FleetID != "" {
+		query["fleetId"] = filter.FleetID
+	}
+	if filter.City != "" {
+		query["city"] = filter.City
+	}
+	if !filter.SeenSince.IsZero() {
+		query["lastSeenAt"] = bson.M{"$gte": filter.SeenSince}
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query["createdAt"] = bson.M{"$lte": filter.CreatedBefore}
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	for key, value := range filter.Attributes {
+		// Keys are checked by the handler, so they cannot reach into other fields
+		if value == "" {
+			query["attributes."+key] = bson.M{"$exists": true}
+		} else {
+			query["attributes."+key] = value
 		}
 	}
+	return query
+}
 
-	return drivers, totalCount, nil
+// CountDrivers counts the drivers matching the filter
+func (r *DriverRepository) CountDrivers(ctx interface{}, filter domain.DriverFilter) (int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	var count int64
+	err := r.retrier.Do(c, "count drivers", true, func() (err error) {
+		count, err = r.readsFor(c).CountDocuments(c, driverFilterQuery(filter))
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to count drivers", zap.Error(err))
+		return 0, err
+	}
+	return count, nil
 }
 
-// FindNearby finds drivers within a specified radius
-func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType) ([]*domain.Driver, error) {
+// ListDriversAfter reads a keyset page of drivers in ID order. Each page is
+// its own query, so a long read holds no cursor open between pages.
+func (r *DriverRepository) ListDriversAfter(ctx interface{}, filter domain.DriverFilter, afterID string, limit int) ([]*domain.Driver, error) {
 	c, ok := ctx.(context.Context)
 	if !ok {
 		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
-	// Build filter
-	filter := bson.M{}
+	query := driverFilterQuery(filter)
+	if afterID != "" {
+		id, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, errors.New("invalid driver ID")
+		}
+		query["_id"] = bson.M{"$gt": id}
+	}
+	findOptions := options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit))
 
-	// Add taxi type filter if provided
-	if taxiType != nil {
-		filter["taxiType"] = *taxiType
+	var driversData []driverDocument
+	err := r.retrier.Do(c, "list drivers after", true, func() error {
+		cursor, err := r.readsFor(c).Find(c, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		driversData = nil
+		return cursor.All(c, &driversData)
+	})
+	if err != nil {
+		r.logger.Error("failed to list drivers after", zap.Error(err), zap.String("afterId", afterID))
+		return nil, err
+	}
+	return r.openAll(driversData)
+}
+
+// ListChanges returns drivers, tombstones included, in change feed order
+func (r *DriverRepository) ListChanges(ctx interface{}, filter domain.DriverFilter, after domain.ChangePosition, until time.Time, limit int) ([]*domain.Driver, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
 	}
+	c, endSession := r.withSession(c)
+	defer endSession()
 
-	// Get all drivers (we'll filter by distance in memory since MongoDB geospatial queries
-	// require a geospatial index and we want to use Haversine formula)
-	cursor, err := r.collection.Find(c, filter)
+	// Tombstones are part of the feed, so only the fleet filter applies
+	query := driverFilterQuery(filter)
+	delete(query, "deletedAt")
+	query["updatedAt"] = bson.M{"$gte": after.UpdatedAt, "$lt": until}
+	if after.ID != "" {
+		afterID, err := primitive.ObjectIDFromHex(after.ID)
+		if err != nil {
+			return nil, errors.New("invalid driver ID")
+		}
+		// Skip the drivers up to and including the position at the same updatedAt
+		query["$or"] = bson.A{
+			bson.M{"updatedAt": bson.M{"$gt": after.UpdatedAt}},
+			bson.M{"_id": bson.M{"$gt": afterID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	var docs []driverDocument
+	err := r.retrier.Do(c, "list driver changes", true, func() error {
+		cursor, err := r.collection.Find(c, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
 	if err != nil {
-		r.logger.Error("failed to find nearby drivers", zap.Error(err))
+		r.logger.Error("failed to list driver changes", zap.Error(err))
 		return nil, err
 	}
+
+	return r.openAll(docs)
+}
+
+// searchProjection loads only what autocomplete suggestions show
+var searchProjection = bson.M{"firstName": 1, "lastName": 1, "plate": 1}
+
+// SearchDriverPrefixes finds drivers through the searchKeys index. The find
+// runs with maxTimeMS set to what is left until ctx's deadline, so MongoDB
+// stops a slow search itself rather than the client dropping the connection.
+func (r *DriverRepository) SearchDriverPrefixes(ctx interface{}, words []string, filter domain.DriverFilter, limit int) ([]*domain.Driver, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	keys := make(bson.A, len(words))
+	for i, word := range words {
+		keys[i] = r.searchKey(word)
+	}
+	query := driverFilterQuery(filter)
+	query["searchKeys"] = bson.M{"$all": keys}
+
+	findOptions := options.Find().SetProjection(searchProjection).SetLimit(int64(limit))
+	if deadline, ok := c.Deadline(); ok {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, domain.ErrSearchTimeout
+		}
+		findOptions.SetMaxTime(remaining)
+	}
+
+	var docs []driverDocument
+	err := r.retrier.Do(c, "search drivers", true, func() error {
+		cursor, err := r.readsFor(c).Find(c, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
+	if err != nil {
+		if mongo.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded) {
+			return nil, domain.ErrSearchTimeout
+		}
+		r.logger.Error("failed to search drivers", zap.Error(err))
+		return nil, err
+	}
+	return r.openAll(docs)
+}
+
+// StreamDrivers reads matching drivers from one cursor in ID order. It is not
+// retried: after a failure part way, fn would see drivers a second time.
+func (r *DriverRepository) StreamDrivers(ctx interface{}, filter domain.DriverFilter, afterID string, fn func(*domain.Driver) error) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	query := driverFilterQuery(filter)
+	if afterID != "" {
+		id, err := primitive.ObjectIDFromHex(afterID)
+		if err != nil {
+			return errors.New("invalid driver ID")
+		}
+		query["_id"] = bson.M{"$gt": id}
+	}
+
+	findOptions := options.Find().SetSort(bson.M{"_id": 1}).SetBatchSize(500)
+	cursor, err := r.readsFor(c).Find(c, query, findOptions)
+	if err != nil {
+		r.logger.Error("failed to stream drivers", zap.Error(err))
+		return err
+	}
 	defer cursor.Close(c)
 
-	var allDrivers []struct {
-		ID        primitive.ObjectID `bson:"_id"`
-		FirstName string             `bson:"firstName"`
-		LastName  string             `bson:"lastName"`
-		Plate     string             `bson:"plate"`
-		TaxiType  domain.TaxiType    `bson:"taxiType"`
-		CarBrand  string             `bson:"carBrand"`
-		CarModel  string             `bson:"carModel"`
-		Location  domain.Location    `bson:"location"`
-		CreatedAt time.Time          `bson:"createdAt"`
-		UpdatedAt time.Time          `bson:"updatedAt"`
-	}
-
-	if err = cursor.All(c, &allDrivers); err != nil {
-		r.logger.Error("failed to decode drivers", zap.Error(err))
+	for cursor.Next(c) {
+		var doc driverDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		driver, err := r.open(&doc)
+		if err != nil {
+			return err
+		}
+		if err := fn(driver); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// RecordHeartbeat stores the time of a driver's latest heartbeat. Only
+// lastSeenAt is written so heartbeats stay cheap and never race with updates.
+func (r *DriverRepository) RecordHeartbeat(ctx interface{}, driverID string, at time.Time) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return errors.New("invalid driver ID")
+	}
+
+	var result *mongo.UpdateResult
+	err = r.retrier.Do(c, "record heartbeat", true, func() (err error) {
+		result, err = r.collection.UpdateOne(c, bson.M{"_id": objectID, "deletedAt": notDeleted}, bson.M{"$set": bson.M{"lastSeenAt": at}})
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to record heartbeat", zap.Error(err), zap.String("id", driverID))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("driver not found")
+	}
+	if r.geo != nil {
+		r.geo.Touch(driverID, at)
+	}
+	return nil
+}
+
+// CountOnline counts the drivers matching the filter and those with a heartbeat since the given time
+func (r *DriverRepository) CountOnline(ctx interface{}, filter domain.DriverFilter, since time.Time) (int64, int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	var total, online int64
+	filter.SeenSince = time.Time{}
+	err := r.retrier.Do(c, "count drivers", true, func() (err error) {
+		total, err = r.readsFor(c).CountDocuments(c, driverFilterQuery(filter))
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to count drivers", zap.Error(err))
+		return 0, 0, err
+	}
+	onlineFilter := filter
+	onlineFilter.SeenSince = since
+	err = r.retrier.Do(c, "count online drivers", true, func() (err error) {
+		online, err = r.readsFor(c).CountDocuments(c, driverFilterQuery(onlineFilter))
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to count online drivers", zap.Error(err))
+		return 0, 0, err
+	}
+	return online, total, nil
+}
+
+// CountByStatus counts the drivers on shift, off shift and suspended in one pass
+func (r *DriverRepository) CountByStatus(ctx interface{}) (*domain.DriverStatusCounts, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deletedAt": notDeleted}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"available": "$available", "suspended": "$suspended"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	var groups []struct {
+		ID struct {
+			Available bool `bson:"available"`
+			Suspended bool `bson:"suspended"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	err := r.retrier.Do(c, "count drivers by status", true, func() error {
+		cursor, err := r.readsFor(c).Aggregate(c, pipeline)
+		if err != nil {
+			return err
+		}
+		return cursor.All(c, &groups)
+	})
+	if err != nil {
+		r.logger.Error("failed to count drivers by status", zap.Error(err))
+		return nil, err
+	}
+
+	counts := &domain.DriverStatusCounts{}
+	for _, group := range groups {
+		switch {
+		case group.ID.Suspended:
+			counts.Suspended += group.Count
+		case group.ID.Available:
+			counts.Available += group.Count
+		default:
+			counts.OffShift += group.Count
+		}
+	}
+	return counts, nil
+}
+
+// ListExpiringLicenses returns drivers whose licence expires before the given time, soonest first
+func (r *DriverRepository) ListExpiringLicenses(ctx interface{}, filter domain.DriverFilter, before time.Time) ([]*domain.Driver, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	query := driverFilterQuery(filter)
+	query["license.expiresAt"] = bson.M{"$lt": before}
+	findOptions := options.Find().SetSort(bson.D{{Key: "license.expiresAt", Value: 1}, {Key: "_id", Value: 1}})
+
+	var docs []driverDocument
+	err := r.retrier.Do(c, "list expiring licenses", true, func() error {
+		cursor, err := r.readsFor(c).Find(c, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
+	if err != nil {
+		r.logger.Error("failed to list expiring licenses", zap.Error(err))
+		return nil, err
+	}
+
+	return r.openAll(docs)
+}
+
+// FlagExpiredLicenses marks drivers whose licence expired by now as not
+// dispatchable and takes them off shift. The returned drivers keep their
+// previous availability so callers can tell who was on shift. Drivers renewed
+// between the lookup and the update are left alone because the update
+// re-checks the expiry.
+func (r *DriverRepository) FlagExpiredLicenses(ctx interface{}, now time.Time) ([]*domain.Driver, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	query := bson.M{
+		"license.expiresAt": bson.M{"$lte": now},
+		"licenseExpired":    bson.M{"$ne": true},
+		"deletedAt":         notDeleted,
+	}
+
+	var docs []driverDocument
+	err := r.retrier.Do(c, "find expired licenses", true, func() error {
+		cursor, err := r.collection.Find(c, query)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
+	if err != nil {
+		r.logger.Error("failed to find expired licenses", zap.Error(err))
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make(bson.A, len(docs))
+	for i := range docs {
+		ids[i] = docs[i].ID
+	}
+	query["_id"] = bson.M{"$in": ids}
+	update := bson.M{"$set": bson.M{"licenseExpired": true, "available": false, "updatedAt": now}}
+
+	// Idempotent: flagged drivers no longer match the query
+	err = r.retrier.Do(c, "flag expired licenses", true, func() error {
+		_, err := r.collection.UpdateMany(c, query, update)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to flag expired licenses", zap.Error(err))
+		return nil, err
+	}
+
+	drivers, err := r.openAll(docs)
+	if err != nil {
+		return nil, err
+	}
+	for _, driver := range drivers {
+		flagged := *driver
+		flagged.LicenseExpired = true
+		flagged.Available = false
+		r.cacheDriver(flagged.ID, &flagged)
+	}
+	return drivers, nil
+}
+
+// FindNearby finds drivers within a specified radius
+func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType, driverFilter domain.DriverFilter, page domain.NearbyPage) ([]*domain.Driver, error) {
+	if r.geo != nil && r.geo.Fresh(time.Now(), r.geoMaxStale) {
+		return page.Apply(r.geo.Nearby(lat, lon, radiusKm, taxiType, driverFilter)), nil
+	}
+
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	filter := r.nearbyQuery(lat, lon, radiusKm, taxiType, driverFilter)
+	if r.geoNear {
+		return r.findGeoNear(c, lat, lon, radiusKm, filter, page)
+	}
+
+	// Without $geoNear every candidate is read and filtered by distance in
+	// memory with the Haversine formula
+	var allDrivers []driverDocument
+	err := r.retrier.Do(c, "find nearby drivers", true, func() error {
+		cursor, err := r.readsFor(c).Find(c, filter)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		allDrivers = nil
+		return cursor.All(c, &allDrivers)
+	})
+	if err != nil {
+		r.logger.Error("failed to find nearby drivers", zap.Error(err))
 		return nil, err
 	}
 
@@ -236,7 +1243,8 @@ func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusK
 	}
 
 	var nearbyDrivers []driverWithDistance
-	for _, d := range allDrivers {
+	for i := range allDrivers {
+		d := &allDrivers[i]
 		// Skip drivers with invalid locations (zero coordinates or missing location)
 		// Zero coordinates (0, 0) are in the Gulf of Guinea and unlikely to be valid taxi locations
 		if d.Location.Lat == 0 && d.Location.Lon == 0 {
@@ -250,17 +1258,9 @@ func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusK
 
 		distance := haversine.Distance(lat, lon, d.Location.Lat, d.Location.Lon)
 		if distance <= radiusKm {
-			driver := &domain.Driver{
-				ID:        d.ID.Hex(),
-				FirstName: d.FirstName,
-				LastName:  d.LastName,
-				Plate:     d.Plate,
-				TaxiType:  d.TaxiType,
-				CarBrand:  d.CarBrand,
-				CarModel:  d.CarModel,
-				Location:  d.Location,
-				CreatedAt: d.CreatedAt,
-				UpdatedAt: d.UpdatedAt,
+			driver, err := r.open(d)
+			if err != nil {
+				return nil, err
 			}
 			nearbyDrivers = append(nearbyDrivers, driverWithDistance{
 				driver:   driver,
@@ -284,5 +1284,437 @@ func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusK
 		result[i] = nd.driver
 	}
 
-	return result, nil
+	return page.Apply(result), nil
+}
+
+// findGeoNear runs a nearby search in MongoDB: $geoNear sorts the drivers
+// matching filter within radiusKm by distance on the position index, and the
+// page is cut there so only the returned drivers are read
+func (r *DriverRepository) findGeoNear(c context.Context, lat, lon, radiusKm float64, filter bson.M, page domain.NearbyPage) ([]*domain.Driver, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$geoNear", Value: bson.D{
+			{Key: "near", Value: geoPoint{Type: "Point", Coordinates: []float64{lon, lat}}},
+			{Key: "key", Value: "position"},
+			{Key: "distanceField", Value: "distanceMeters"},
+			{Key: "maxDistance", Value: radiusKm * 1000},
+			{Key: "spherical", Value: true},
+			{Key: "query", Value: filter},
+		}}},
+	}
+	if page.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: page.Offset}})
+	}
+	if page.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: page.Limit}})
+	}
+
+	var docs []driverDocument
+	err := r.retrier.Do(c, "find nearby drivers", true, func() error {
+		cursor, err := r.readsFor(c).Aggregate(c, pipeline)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(c)
+		docs = nil
+		return cursor.All(c, &docs)
+	})
+	if err != nil {
+		r.logger.Error("failed to find nearby drivers", zap.Error(err))
+		return nil, err
+	}
+	return r.openAll(docs)
+}
+
+// nearbyQuery builds the query matching the drivers a nearby search may return
+func (r *DriverRepository) nearbyQuery(lat, lon, radiusKm float64, taxiType *domain.TaxiType, driverFilter domain.DriverFilter) bson.M {
+	filter := driverFilterQuery(driverFilter)
+
+	// Only the cities the search reaches are read, plus drivers stored before
+	// cities were configured until they are backfilled
+	if r.cities != nil && driverFilter.City == "" {
+		cities := bson.A{nil}
+		for _, city := range r.cities.Touching(domain.Location{Lat: lat, Lon: lon}, radiusKm) {
+			cities = append(cities, city)
+		}
+		filter["city"] = bson.M{"$in": cities}
+	}
+
+	// Suspended drivers and drivers without a valid licence are never offered to riders
+	filter["suspended"] = bson.M{"$ne": true}
+	filter["licenseExpired"] = bson.M{"$ne": true}
+
+	// Add taxi type filter if provided
+	if taxiType != nil {
+		filter["taxiType"] = *taxiType
+	}
+	return filter
+}
+
+// GetByPhone retrieves a driver by phone number
+func (r *DriverRepository) GetByPhone(ctx interface{}, phone string) (*domain.Driver, error) {
+	filter := bson.M{"phone": phone}
+	if r.hasher != nil {
+		// Match by lookup hash, plus plaintext for documents not migrated yet
+		filter = bson.M{"$or": bson.A{
+			bson.M{"phoneHash": r.hasher.Hash(phone)},
+			bson.M{"phone": phone},
+		}}
+	}
+	return r.findOne(ctx, filter, "phone")
+}
+
+// GetByEmail retrieves a driver by email address
+func (r *DriverRepository) GetByEmail(ctx interface{}, email string) (*domain.Driver, error) {
+	return r.findOne(ctx, bson.M{"email": email}, "email")
+}
+
+// GetByPlate retrieves a driver by normalized plate
+func (r *DriverRepository) GetByPlate(ctx interface{}, plate string) (*domain.Driver, error) {
+	return r.findOne(ctx, bson.M{"plate": plate}, "plate")
+}
+
+// findOne retrieves a single driver matching the filter
+func (r *DriverRepository) findOne(ctx interface{}, filter bson.M, field string) (*domain.Driver, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+	c, endSession := r.withSession(c)
+	defer endSession()
+
+	filter["deletedAt"] = notDeleted
+
+	var doc driverDocument
+	err := r.retrier.Do(c, "get driver by "+field, true, func() error {
+		return r.readsFor(c).FindOne(c, filter).Decode(&doc)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("driver not found")
+		}
+		r.logger.Error("failed to get driver", zap.Error(err), zap.String("field", field))
+		return nil, err
+	}
+
+	return r.open(&doc)
+}
+
+// ReencryptStats summarizes a re-encryption run
+type ReencryptStats struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// Reencrypt rewrites protected fields that are plaintext or sealed with a retired key
+// using the active key, and refreshes phone lookup hashes and search keys. Documents modified while
+// the migration runs are skipped; their writer already used the active key.
+func (r *DriverRepository) Reencrypt(ctx context.Context, dryRun bool) (*ReencryptStats, error) {
+	if r.cipher == nil {
+		return nil, errors.New("field encryption is not configured")
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"deletedAt": notDeleted}, options.Find().SetBatchSize(500))
+	if err != nil {
+		r.logger.Error("failed to scan drivers for re-encryption", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := &ReencryptStats{}
+	for cursor.Next(ctx) {
+		var stored driverDocument
+		if err := cursor.Decode(&stored); err != nil {
+			return stats, err
+		}
+		stats.Scanned++
+
+		driver, err := r.open(&stored)
+		if err != nil {
+			return stats, err
+		}
+		if !r.needsReencryption(&stored, driver.Phone) {
+			continue
+		}
+		if dryRun {
+			stats.Updated++
+			continue
+		}
+
+		doc := newDriverDocument(stored.ID, driver)
+		if err := r.seal(doc); err != nil {
+			return stats, err
+		}
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": stored.ID, "updatedAt": stored.UpdatedAt},
+			bson.M{"$set": bson.M{
+				"firstName":  doc.FirstName,
+				"lastName":   doc.LastName,
+				"phone":      doc.Phone,
+				"phoneHash":  doc.PhoneHash,
+				"searchKeys": doc.SearchKeys,
+			}},
+		)
+		if err != nil {
+			r.logger.Error("failed to re-encrypt driver", zap.Error(err), zap.String("id", driver.ID))
+			return stats, err
+		}
+		if result.MatchedCount == 0 {
+			stats.Skipped++
+			continue
+		}
+		stats.Updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return stats, err
+	}
+
+	r.logger.Info("driver re-encryption finished",
+		zap.Int("scanned", stats.Scanned),
+		zap.Int("updated", stats.Updated),
+		zap.Int("skipped", stats.Skipped),
+		zap.Bool("dryRun", dryRun),
+	)
+	return stats, nil
+}
+
+// CityBackfillStats summarizes a city backfill
+type CityBackfillStats struct {
+	Scanned int            `json:"scanned"`
+	Updated int            `json:"updated"`
+	Skipped int            `json:"skipped"`
+	Cities  map[string]int `json:"cities"`
+}
+
+// BackfillCities sets the city of drivers stored before cities were
+// configured. Documents modified while it runs are skipped; their writer
+// already set the city.
+func (r *DriverRepository) BackfillCities(ctx context.Context, dryRun bool) (*CityBackfillStats, error) {
+	if r.cities == nil {
+		return nil, errors.New("cities are not configured")
+	}
+
+	query := bson.M{"deletedAt": notDeleted, "city": bson.M{"$exists": false}}
+	projection := bson.M{"location": 1, "updatedAt": 1}
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetProjection(projection).SetBatchSize(500))
+	if err != nil {
+		r.logger.Error("failed to scan drivers for cities", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := &CityBackfillStats{Cities: map[string]int{}}
+	for cursor.Next(ctx) {
+		var stored driverDocument
+		if err := cursor.Decode(&stored); err != nil {
+			return stats, err
+		}
+		stats.Scanned++
+		city := r.cities.Locate(stored.Location)
+		if dryRun {
+			stats.Updated++
+			stats.Cities[city]++
+			continue
+		}
+
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": stored.ID, "updatedAt": stored.UpdatedAt},
+			bson.M{"$set": bson.M{"city": city}},
+		)
+		if err != nil {
+			r.logger.Error("failed to set driver city", zap.Error(err), zap.String("id", stored.ID.Hex()))
+			return stats, err
+		}
+		if result.MatchedCount == 0 {
+			stats.Skipped++
+			continue
+		}
+		stats.Updated++
+		stats.Cities[city]++
+	}
+	if err := cursor.Err(); err != nil {
+		return stats, err
+	}
+
+	r.logger.Info("driver city backfill finished",
+		zap.Int("scanned", stats.Scanned),
+		zap.Int("updated", stats.Updated),
+		zap.Int("skipped", stats.Skipped),
+		zap.Bool("dryRun", dryRun),
+	)
+	return stats, nil
+}
+
+// ShardByCity shards the drivers collection by CityShardKey. It needs a
+// connection through mongos and is a no-op for a collection already sharded
+// by that key. Backfill cities first: drivers without one all land in the
+// chunk of the missing city.
+func (r *DriverRepository) ShardByCity(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create driver indexes", zap.Error(err))
+		return err
+	}
+
+	db := r.collection.Database()
+	admin := db.Client().Database("admin")
+	// MongoDB before 6.0 shards only collections of databases enabled for it;
+	// code 23 is a database already enabled
+	err := admin.RunCommand(ctx, bson.D{{Key: "enableSharding", Value: db.Name()}}).Err()
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Code == 23) {
+		r.logger.Error("failed to enable sharding", zap.Error(err))
+		return err
+	}
+
+	command := bson.D{
+		{Key: "shardCollection", Value: db.Name() + "." + r.collection.Name()},
+		{Key: "key", Value: CityShardKey},
+	}
+	if err := admin.RunCommand(ctx, command).Err(); err != nil {
+		r.logger.Error("failed to shard drivers by city", zap.Error(err))
+		return err
+	}
+	r.logger.Info("drivers collection sharded by city", zap.String("collection", r.collection.Name()))
+	return nil
+}
+
+// SearchBackfillStats summarizes a search key backfill
+type SearchBackfillStats struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+// BackfillSearchKeys sets the search keys of drivers stored before autocomplete
+// existed. Like Reencrypt, it skips documents modified while it runs; their
+// writer already set the keys.
+func (r *DriverRepository) BackfillSearchKeys(ctx context.Context, dryRun bool) (*SearchBackfillStats, error) {
+	query := bson.M{"deletedAt": notDeleted, "searchKeys": bson.M{"$exists": false}}
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetBatchSize(500))
+	if err != nil {
+		r.logger.Error("failed to scan drivers for search keys", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := &SearchBackfillStats{}
+	for cursor.Next(ctx) {
+		var stored driverDocument
+		if err := cursor.Decode(&stored); err != nil {
+			return stats, err
+		}
+		stats.Scanned++
+		if dryRun {
+			stats.Updated++
+			continue
+		}
+
+		driver, err := r.open(&stored)
+		if err != nil {
+			return stats, err
+		}
+		doc := newDriverDocument(stored.ID, driver)
+		if err := r.seal(doc); err != nil {
+			return stats, err
+		}
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": stored.ID, "updatedAt": stored.UpdatedAt},
+			bson.M{"$set": bson.M{"searchKeys": doc.SearchKeys}},
+		)
+		if err != nil {
+			r.logger.Error("failed to set driver search keys", zap.Error(err), zap.String("id", driver.ID))
+			return stats, err
+		}
+		if result.MatchedCount == 0 {
+			stats.Skipped++
+			continue
+		}
+		stats.Updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return stats, err
+	}
+
+	r.logger.Info("driver search key backfill finished",
+		zap.Int("scanned", stats.Scanned),
+		zap.Int("updated", stats.Updated),
+		zap.Int("skipped", stats.Skipped),
+		zap.Bool("dryRun", dryRun),
+	)
+	return stats, nil
+}
+
+// PositionBackfillStats summarizes a position backfill
+type PositionBackfillStats struct {
+	Scanned int `json:"scanned"`
+	Updated int `json:"updated"`
+	// Invalid drivers have no valid location and stay out of nearby searches
+	Invalid int `json:"invalid"`
+	Skipped int `json:"skipped"`
+}
+
+// BackfillPositions sets the indexed position of drivers stored before
+// positions existed, so $geoNear searches find them; see WithGeoNear.
+// Documents modified while it runs are skipped; their writer already set the position.
+func (r *DriverRepository) BackfillPositions(ctx context.Context, dryRun bool) (*PositionBackfillStats, error) {
+	query := bson.M{"deletedAt": notDeleted, "position": bson.M{"$exists": false}}
+	projection := bson.M{"location": 1, "updatedAt": 1}
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetProjection(projection).SetBatchSize(500))
+	if err != nil {
+		r.logger.Error("failed to scan drivers for positions", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	stats := &PositionBackfillStats{}
+	for cursor.Next(ctx) {
+		var stored driverDocument
+		if err := cursor.Decode(&stored); err != nil {
+			return stats, err
+		}
+		stats.Scanned++
+		position := positionOf(stored.Location)
+		if position == nil {
+			stats.Invalid++
+			continue
+		}
+		if dryRun {
+			stats.Updated++
+			continue
+		}
+
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": stored.ID, "updatedAt": stored.UpdatedAt},
+			bson.M{"$set": bson.M{"position": position}},
+		)
+		if err != nil {
+			r.logger.Error("failed to set driver position", zap.Error(err), zap.String("id", stored.ID.Hex()))
+			return stats, err
+		}
+		if result.MatchedCount == 0 {
+			stats.Skipped++
+			continue
+		}
+		stats.Updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return stats, err
+	}
+
+	r.logger.Info("driver position backfill finished",
+		zap.Int("scanned", stats.Scanned),
+		zap.Int("updated", stats.Updated),
+		zap.Int("invalid", stats.Invalid),
+		zap.Int("skipped", stats.Skipped),
+		zap.Bool("dryRun", dryRun),
+	)
+	return stats, nil
+}
+
+// needsReencryption reports whether any protected field or the phone hash is outdated
+func (r *DriverRepository) needsReencryption(doc *driverDocument, phone string) bool {
+	return r.cipher.NeedsRotation(doc.FirstName) ||
+		r.cipher.NeedsRotation(doc.LastName) ||
+		r.cipher.NeedsRotation(doc.Phone) ||
+		doc.PhoneHash != r.hasher.Hash(phone)
 }
@@ -1,25 +1,52 @@
 package mongodb
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/fieldcrypt"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/geoindex"
+	"github.com/bitaksi/driver-service/pkg/testenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 )
 
+// env starts the MongoDB the repository tests run against once per test run
+var env = testenv.New()
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if err := env.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to remove test containers: %v\n", err)
+	}
+	os.Exit(code)
+}
+
 // setupTestDB creates a test MongoDB connection
 func setupTestDB(t *testing.T) (*mongo.Database, func()) {
+	uri, err := env.MongoURI()
+	if errors.Is(err, testenv.ErrDockerUnavailable) {
+		t.Skipf("%v; set MONGODB_TEST_URI to run against a running MongoDB", err)
+	}
+	require.NoError(t, err)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// Use test database
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	require.NoError(t, err)
 
 	db := client.Database("test_taxihub")
@@ -314,7 +341,7 @@ func TestDriverRepository_List(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			drivers, totalCount, err := repo.List(ctx, tt.page, tt.pageSize)
+			drivers, totalCount, err := repo.List(ctx, domain.DriverFilter{}, tt.page, tt.pageSize)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -424,7 +451,7 @@ func TestDriverRepository_FindNearby(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			drivers, err := repo.FindNearby(ctx, tt.lat, tt.lon, tt.radiusKm, tt.taxiType)
+			drivers, err := repo.FindNearby(ctx, tt.lat, tt.lon, tt.radiusKm, tt.taxiType, domain.DriverFilter{}, domain.NearbyPage{})
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -537,7 +564,7 @@ func TestDriverRepository_ListWithInvalidContext(t *testing.T) {
 	repo := NewDriverRepository(db, logger)
 
 	// Test with invalid context type
-	drivers, totalCount, err := repo.List("not-a-context", 1, 10)
+	drivers, totalCount, err := repo.List("not-a-context", domain.DriverFilter{}, 1, 10)
 	assert.NoError(t, err)
 	assert.NotNil(t, drivers)
 	assert.GreaterOrEqual(t, totalCount, int64(0))
@@ -551,7 +578,501 @@ func TestDriverRepository_FindNearbyWithInvalidContext(t *testing.T) {
 	repo := NewDriverRepository(db, logger)
 
 	// Test with invalid context type
-	drivers, err := repo.FindNearby("not-a-context", 41.0, 29.0, 6.0, nil)
+	drivers, err := repo.FindNearby("not-a-context", 41.0, 29.0, 6.0, nil, domain.DriverFilter{}, domain.NearbyPage{})
 	assert.NoError(t, err)
 	assert.NotNil(t, drivers)
 }
+
+func TestDriverRepository_FindNearbyPage(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewDriverRepository(db, zap.NewNop())
+	require.NoError(t, ensureIndexes(ctx, repo.Indexes()))
+	// Nearest first: about 0, 0.5, 1 and 1.5 km north of the search
+	for i, plate := range []string{"34ABC100", "34ABC200", "34ABC300", "34ABC400"} {
+		require.NoError(t, repo.Create(ctx, &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     plate,
+			TaxiType:  domain.TaxiTypeSari,
+			Location:  domain.Location{Lat: 41.0431 + float64(i)*0.0045, Lon: 29.0099},
+		}))
+	}
+	require.NoError(t, repo.Create(ctx, &domain.Driver{FirstName: "Driver", LastName: "Test", Plate: "00ZERO1", TaxiType: domain.TaxiTypeSari}))
+
+	repos := map[string]*DriverRepository{
+		"in memory": repo,
+		"geoNear":   NewDriverRepository(db, zap.NewNop(), WithGeoNear()),
+	}
+	for name, repo := range repos {
+		t.Run(name, func(t *testing.T) {
+			plates := func(page domain.NearbyPage) []string {
+				drivers, err := repo.FindNearby(ctx, 41.0431, 29.0099, 6, nil, domain.DriverFilter{}, page)
+				require.NoError(t, err)
+				found := []string{}
+				for _, d := range drivers {
+					found = append(found, d.Plate)
+				}
+				return found
+			}
+			assert.Equal(t, []string{"34ABC100", "34ABC200", "34ABC300", "34ABC400"}, plates(domain.NearbyPage{}))
+			assert.Equal(t, []string{"34ABC100", "34ABC200"}, plates(domain.NearbyPage{Limit: 2}))
+			assert.Equal(t, []string{"34ABC300", "34ABC400"}, plates(domain.NearbyPage{Limit: 2, Offset: 2}))
+			assert.Empty(t, plates(domain.NearbyPage{Limit: 2, Offset: 4}))
+		})
+	}
+}
+
+func TestDriverRepository_FieldEncryption(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	keyring, err := fieldcrypt.NewKeyring(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1")
+	require.NoError(t, err)
+	cipher, err := fieldcrypt.NewAESGCM(keyring)
+	require.NoError(t, err)
+	hasher, err := fieldcrypt.NewHMACHasher(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	plainRepo := NewDriverRepository(db, zap.NewNop())
+	repo := NewDriverRepository(db, zap.NewNop(), WithFieldEncryption(cipher, hasher))
+
+	// A driver written before encryption was enabled
+	legacy := &domain.Driver{FirstName: "Ayse", LastName: "Kaya", Plate: "34AYS34", TaxiType: domain.TaxiTypeSari, Phone: "+905321111111"}
+	require.NoError(t, plainRepo.Create(ctx, legacy))
+
+	driver := &domain.Driver{FirstName: "Ahmet", LastName: "Demir", Plate: "34ABC123", TaxiType: domain.TaxiTypeSari, Phone: "+905321234567"}
+	require.NoError(t, repo.Create(ctx, driver))
+
+	// Stored values are encrypted
+	var raw driverDocument
+	objectID, _ := primitive.ObjectIDFromHex(driver.ID)
+	require.NoError(t, db.Collection("drivers").FindOne(ctx, bson.M{"_id": objectID}).Decode(&raw))
+	assert.True(t, fieldcrypt.IsEncrypted(raw.FirstName))
+	assert.True(t, fieldcrypt.IsEncrypted(raw.LastName))
+	assert.True(t, fieldcrypt.IsEncrypted(raw.Phone))
+	assert.Equal(t, hasher.Hash("+905321234567"), raw.PhoneHash)
+
+	// Reads are transparent
+	got, err := repo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Ahmet", got.FirstName)
+	assert.Equal(t, "+905321234567", got.Phone)
+
+	byPhone, err := repo.GetByPhone(ctx, "+905321234567")
+	require.NoError(t, err)
+	assert.Equal(t, driver.ID, byPhone.ID)
+
+	legacyByPhone, err := repo.GetByPhone(ctx, "+905321111111")
+	require.NoError(t, err)
+	assert.Equal(t, "Ayse", legacyByPhone.FirstName)
+
+	// The migration encrypts the legacy driver only
+	stats, err := repo.Reencrypt(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Scanned)
+	assert.Equal(t, 1, stats.Updated)
+
+	objectID, _ = primitive.ObjectIDFromHex(legacy.ID)
+	require.NoError(t, db.Collection("drivers").FindOne(ctx, bson.M{"_id": objectID}).Decode(&raw))
+	assert.True(t, fieldcrypt.IsEncrypted(raw.FirstName))
+	assert.Equal(t, hasher.Hash("+905321111111"), raw.PhoneHash)
+}
+
+func TestDriverRepository_FleetFilter(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewDriverRepository(db, zap.NewNop())
+	ctx := context.Background()
+
+	for i, fleetID := range []string{"fleet-a", "fleet-a", "fleet-b", ""} {
+		driver := &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     "34ABC12" + string(rune('0'+i)),
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Location:  domain.Location{Lat: 41.0431, Lon: 29.0099},
+			FleetID:   fleetID,
+		}
+		require.NoError(t, repo.Create(ctx, driver))
+	}
+
+	drivers, totalCount, err := repo.List(ctx, domain.DriverFilter{FleetID: "fleet-a"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totalCount)
+	for _, d := range drivers {
+		assert.Equal(t, "fleet-a", d.FleetID)
+	}
+
+	nearby, err := repo.FindNearby(ctx, 41.0431, 29.0099, 6.0, nil, domain.DriverFilter{FleetID: "fleet-b"}, domain.NearbyPage{})
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, "fleet-b", nearby[0].FleetID)
+}
+
+func TestDriverRepository_TagFilter(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewDriverRepository(db, zap.NewNop())
+	ctx := context.Background()
+
+	for i, tags := range [][]string{{"pet-friendly"}, {"pet-friendly", "wheelchair-accessible"}, nil} {
+		driver := &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     "34ABC12" + string(rune('0'+i)),
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Location:  domain.Location{Lat: 41.0431, Lon: 29.0099},
+			Tags:      tags,
+		}
+		if i == 0 {
+			driver.Attributes = map[string]string{"language": "en"}
+		}
+		require.NoError(t, repo.Create(ctx, driver))
+	}
+
+	_, totalCount, err := repo.List(ctx, domain.DriverFilter{Tags: []string{"pet-friendly"}}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totalCount)
+
+	drivers, _, err := repo.List(ctx, domain.DriverFilter{Attributes: map[string]string{"language": ""}}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, drivers, 1)
+	assert.Equal(t, "en", drivers[0].Attributes["language"])
+
+	nearby, err := repo.FindNearby(ctx, 41.0431, 29.0099, 6.0, nil, domain.DriverFilter{Tags: []string{"pet-friendly", "wheelchair-accessible"}}, domain.NearbyPage{})
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, []string{"pet-friendly", "wheelchair-accessible"}, nearby[0].Tags)
+}
+
+func TestDriverRepository_Cities(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	cities, err := geofence.LoadCities("")
+	require.NoError(t, err)
+	repo := NewDriverRepository(db, zap.NewNop(), WithCities(cities))
+	ctx := context.Background()
+
+	locations := []domain.Location{
+		{Lat: 41.0431, Lon: 29.0099},
+		{Lat: 41.0500, Lon: 29.0200},
+		{Lat: 39.9334, Lon: 32.8597},
+		{Lat: 39.7767, Lon: 30.5206},
+	}
+	ids := make([]string, len(locations))
+	for i, location := range locations {
+		driver := &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     "34ABC12" + string(rune('0'+i)),
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Location:  location,
+		}
+		require.NoError(t, repo.Create(ctx, driver))
+		ids[i] = driver.ID
+	}
+
+	found, err := repo.GetByID(ctx, ids[2])
+	require.NoError(t, err)
+	assert.Equal(t, "ankara", found.City)
+
+	_, totalCount, err := repo.List(ctx, domain.DriverFilter{City: "istanbul"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totalCount)
+	_, totalCount, err = repo.List(ctx, domain.DriverFilter{City: domain.CityOther}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), totalCount)
+
+	nearby, err := repo.FindNearby(ctx, 41.0431, 29.0099, 6.0, nil, domain.DriverFilter{}, domain.NearbyPage{})
+	require.NoError(t, err)
+	assert.Len(t, nearby, 2)
+
+	// Moving a driver moves it to the city it is now in
+	require.NoError(t, repo.Update(ctx, ids[1], &domain.Driver{
+		FirstName: "Driver",
+		LastName:  "Test",
+		Plate:     "34ABC121",
+		TaxiType:  domain.TaxiTypeSari,
+		CarBrand:  "Toyota",
+		CarModel:  "Corolla",
+		Location:  locations[2],
+	}))
+	found, err = repo.GetByID(ctx, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, "ankara", found.City)
+
+	// Drivers stored before cities were configured get theirs from the backfill
+	_, err = db.Collection("drivers").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"city": ""}})
+	require.NoError(t, err)
+	stats, err := repo.BackfillCities(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Updated)
+	_, totalCount, err = repo.List(ctx, domain.DriverFilter{City: "ankara"}, 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, totalCount)
+
+	stats, err = repo.BackfillCities(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, stats.Updated)
+	assert.Equal(t, map[string]int{"istanbul": 1, "ankara": 2, domain.CityOther: 1}, stats.Cities)
+	_, totalCount, err = repo.List(ctx, domain.DriverFilter{City: "ankara"}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totalCount)
+}
+
+func TestDriverRepository_SearchDriverPrefixes(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	hasher, err := fieldcrypt.NewHMACHasher(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	keyring, err := fieldcrypt.NewKeyring(map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}, "k1")
+	require.NoError(t, err)
+	cipher, err := fieldcrypt.NewAESGCM(keyring)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for name, repo := range map[string]*DriverRepository{
+		"plaintext": NewDriverRepository(db, zap.NewNop()),
+		"encrypted": NewDriverRepository(db, zap.NewNop(), WithFieldEncryption(cipher, hasher)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, db.Collection("drivers").Drop(ctx))
+			require.NoError(t, repo.EnsureIndexes(ctx))
+
+			sukru := &domain.Driver{FirstName: "Şükrü", LastName: "Yılmaz", Plate: "34ABC123", TaxiType: domain.TaxiTypeSari, FleetID: "fleet-a"}
+			require.NoError(t, repo.Create(ctx, sukru))
+			ahmet := &domain.Driver{FirstName: "Ahmet", LastName: "Demir", Plate: "06XY42", TaxiType: domain.TaxiTypeSari, FleetID: "fleet-b"}
+			require.NoError(t, repo.Create(ctx, ahmet))
+
+			found, err := repo.SearchDriverPrefixes(ctx, []string{"sukru", "34a"}, domain.DriverFilter{}, 10)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			assert.Equal(t, sukru.ID, found[0].ID)
+			assert.Equal(t, "Şükrü", found[0].FirstName)
+
+			found, err = repo.SearchDriverPrefixes(ctx, []string{"xy"}, domain.DriverFilter{}, 10)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			assert.Equal(t, ahmet.ID, found[0].ID)
+
+			found, err = repo.SearchDriverPrefixes(ctx, []string{"y"}, domain.DriverFilter{FleetID: "fleet-b"}, 10)
+			require.NoError(t, err)
+			require.Len(t, found, 1, "Yılmaz is in another fleet")
+			assert.Equal(t, ahmet.ID, found[0].ID)
+
+			// Renamed drivers are found by the new name only
+			ahmet.FirstName = "Mehmet"
+			require.NoError(t, repo.Update(ctx, ahmet.ID, ahmet))
+			found, err = repo.SearchDriverPrefixes(ctx, []string{"ahm"}, domain.DriverFilter{}, 10)
+			require.NoError(t, err)
+			assert.Empty(t, found)
+
+			// Deleted drivers are not suggested
+			require.NoError(t, repo.Delete(ctx, sukru.ID))
+			found, err = repo.SearchDriverPrefixes(ctx, []string{"sukru"}, domain.DriverFilter{}, 10)
+			require.NoError(t, err)
+			assert.Empty(t, found)
+
+			// Drivers stored before autocomplete get their keys from the backfill
+			_, err = db.Collection("drivers").UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"searchKeys": ""}})
+			require.NoError(t, err)
+			stats, err := repo.BackfillSearchKeys(ctx, false)
+			require.NoError(t, err)
+			assert.Equal(t, 1, stats.Updated)
+			found, err = repo.SearchDriverPrefixes(ctx, []string{"meh"}, domain.DriverFilter{}, 10)
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+		})
+	}
+
+	expired, cancel := context.WithTimeout(ctx, -time.Second)
+	defer cancel()
+	_, err = NewDriverRepository(db, zap.NewNop()).SearchDriverPrefixes(expired, []string{"a"}, domain.DriverFilter{}, 10)
+	assert.ErrorIs(t, err, domain.ErrSearchTimeout)
+}
+
+func TestDriverRepository_SoftDeleteAndChanges(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewDriverRepository(db, zap.NewNop())
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+	start := time.Now().Add(-time.Second)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		driver := &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     "34SYN12" + string(rune('0'+i)),
+			TaxiType:  domain.TaxiTypeSari,
+			Location:  domain.Location{Lat: 41.0431, Lon: 29.0099},
+			Phone:     "+90532123456" + string(rune('0'+i)),
+		}
+		require.NoError(t, repo.Create(ctx, driver))
+		ids = append(ids, driver.ID)
+	}
+	require.NoError(t, repo.Delete(ctx, ids[1]))
+
+	// The deleted driver is gone from reads and frees its phone
+	_, err := repo.GetByID(ctx, ids[1])
+	assert.EqualError(t, err, "driver not found")
+	assert.EqualError(t, repo.Delete(ctx, ids[1]), "driver not found")
+	_, total, err := repo.List(ctx, domain.DriverFilter{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.NoError(t, repo.Create(ctx, &domain.Driver{FirstName: "New", LastName: "Driver", Plate: "34SYN999", Phone: "+905321234561"}))
+
+	// The feed keeps the tombstone and pages in updatedAt order
+	until := time.Now().Add(time.Second)
+	first, err := repo.ListChanges(ctx, domain.DriverFilter{}, domain.ChangePosition{UpdatedAt: start}, until, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, []string{ids[0], ids[2]}, []string{first[0].ID, first[1].ID})
+
+	last := first[1]
+	rest, err := repo.ListChanges(ctx, domain.DriverFilter{}, domain.ChangePosition{UpdatedAt: last.UpdatedAt, ID: last.ID}, until, 10)
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	assert.Equal(t, ids[1], rest[0].ID)
+	assert.NotNil(t, rest[0].DeletedAt)
+}
+
+func TestDriverRepository_DuplicateContact(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewDriverRepository(db, zap.NewNop())
+	ctx := context.Background()
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	first := &domain.Driver{FirstName: "Ahmet", LastName: "Demir", Plate: "34ABC123", Phone: "+905321234567", Email: "ahmet@example.com"}
+	require.NoError(t, repo.Create(ctx, first))
+	second := &domain.Driver{FirstName: "Ali", LastName: "Kurt", Plate: "34ABC124", Phone: "+905327654321"}
+	require.NoError(t, repo.Create(ctx, second))
+
+	// The unique indexes catch what slipped past the use case's checks
+	err := repo.Create(ctx, &domain.Driver{FirstName: "Can", LastName: "Yilmaz", Plate: "34ABC125", Phone: first.Phone})
+	assert.ErrorIs(t, err, domain.ErrDuplicatePhone)
+	second.Email = first.Email
+	assert.ErrorIs(t, repo.Update(ctx, second.ID, second), domain.ErrDuplicateEmail)
+}
+
+func TestDriverRepository_GeoCache(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	repo := NewDriverRepository(db, zap.NewNop(), WithGeoCache(geoindex.New(1), time.Minute))
+	// other stands in for another instance writing to the same database
+	other := NewDriverRepository(db, zap.NewNop())
+	require.NoError(t, repo.EnsureIndexes(ctx))
+
+	local := &domain.Driver{FirstName: "Local", Plate: "34GEO001", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	require.NoError(t, repo.Create(ctx, local))
+	remote := &domain.Driver{FirstName: "Remote", Plate: "34GEO002", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0435, Lon: 29.0105}}
+	require.NoError(t, other.Create(ctx, remote))
+
+	nearby := func() []string {
+		drivers, err := repo.FindNearby(ctx, 41.0431, 29.0099, 6, nil, domain.DriverFilter{}, domain.NearbyPage{})
+		require.NoError(t, err)
+		var ids []string
+		for _, d := range drivers {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+
+	// Until loaded, searches go to MongoDB
+	assert.ElementsMatch(t, []string{local.ID, remote.ID}, nearby())
+	repo.reconcileGeoCache(ctx)
+	assert.ElementsMatch(t, []string{local.ID, remote.ID}, nearby())
+
+	// Own writes show at once
+	local.Location = domain.Location{Lat: 39.9334, Lon: 32.8597}
+	require.NoError(t, repo.Update(ctx, local.ID, local))
+	assert.Equal(t, []string{remote.ID}, nearby())
+
+	// Writes of other instances show after the next poll
+	require.NoError(t, other.Delete(ctx, remote.ID))
+	assert.Equal(t, []string{remote.ID}, nearby())
+	time.Sleep(geoCacheLag + 100*time.Millisecond)
+	require.NoError(t, repo.pollGeoCache(ctx))
+	assert.Empty(t, nearby())
+}
+
+func TestDriverRepository_BulkUpdateDrivers(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewDriverRepository(db, zap.NewNop())
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	brands := []string{"Fiat", "Fiat", "Fiat", "Toyota"}
+	ids := make([]string, len(brands))
+	for i, brand := range brands {
+		driver := &domain.Driver{
+			FirstName: "Driver",
+			LastName:  "Test",
+			Plate:     "34BLK12" + string(rune('0'+i)),
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  brand,
+			CarModel:  "Egea",
+			Location:  domain.Location{Lat: 41.0431, Lon: 29.0099},
+		}
+		require.NoError(t, repo.Create(ctx, driver))
+		ids[i] = driver.ID
+	}
+	turkuaz := domain.TaxiTypeTurkuaz
+	filter := domain.BulkFilter{CarBrand: "Fiat"}
+	patch := domain.DriverPatch{TaxiType: &turkuaz}
+
+	result, err := repo.BulkUpdateDrivers(ctx, filter, patch, 2, false, now)
+	assert.ErrorIs(t, err, domain.ErrBulkUpdateTooLarge)
+	assert.Equal(t, int64(3), result.Matched)
+
+	result, err = repo.BulkUpdateDrivers(ctx, filter, patch, 10, true, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Modified)
+	found, err := repo.GetByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaxiTypeSari, found.TaxiType, "a dry run changes nothing")
+
+	result, err = repo.BulkUpdateDrivers(ctx, filter, patch, 10, false, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Matched)
+	assert.Equal(t, int64(3), result.Modified)
+	assert.Len(t, result.Changed, 3)
+	found, err = repo.GetByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaxiTypeTurkuaz, found.TaxiType)
+	found, err = repo.GetByID(ctx, ids[3])
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaxiTypeSari, found.TaxiType)
+
+	// Drivers that already have the patched values are not modified again
+	result, err = repo.BulkUpdateDrivers(ctx, filter, patch, 10, false, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), result.Matched)
+	assert.Equal(t, int64(0), result.Modified)
+
+	update := &domain.BulkUpdate{Filter: filter, Patch: patch, Matched: 3, Modified: 3, CreatedAt: now}
+	require.NoError(t, repo.RecordBulkUpdate(ctx, update))
+	assert.NotEmpty(t, update.ID)
+}
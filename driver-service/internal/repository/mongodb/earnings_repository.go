@@ -0,0 +1,410 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// EarningsRepository implements domain.EarningsRepository using MongoDB
+type EarningsRepository struct {
+	earnings *mongo.Collection
+	payouts  *mongo.Collection
+	logger   *zap.Logger
+}
+
+// earningDocument is the stored representation of a ledger entry with native ObjectIDs
+type earningDocument struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	DriverID   string             `bson:"driverId"`
+	Type       domain.EarningType `bson:"type"`
+	TripID     string             `bson:"tripId,omitempty"`
+	Gross      float64            `bson:"gross"`
+	Commission float64            `bson:"commission"`
+	Net        float64            `bson:"net"`
+	Currency   string             `bson:"currency"`
+	Reason     string             `bson:"reason,omitempty"`
+	CreatedBy  string             `bson:"createdBy,omitempty"`
+	PayoutID   primitive.ObjectID `bson:"payoutId,omitempty"`
+	EarnedAt   time.Time          `bson:"earnedAt"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+}
+
+// toDomain converts the stored document into a domain earning
+func (d *earningDocument) toDomain() *domain.Earning {
+	earning := &domain.Earning{
+		ID:         d.ID.Hex(),
+		DriverID:   d.DriverID,
+		Type:       d.Type,
+		TripID:     d.TripID,
+		Gross:      d.Gross,
+		Commission: d.Commission,
+		Net:        d.Net,
+		Currency:   d.Currency,
+		Reason:     d.Reason,
+		CreatedBy:  d.CreatedBy,
+		EarnedAt:   d.EarnedAt,
+		CreatedAt:  d.CreatedAt,
+	}
+	if !d.PayoutID.IsZero() {
+		earning.PayoutID = d.PayoutID.Hex()
+	}
+	return earning
+}
+
+// payoutDocument is the stored representation of a payout with a native ObjectID
+type payoutDocument struct {
+	ID        primitive.ObjectID  `bson:"_id"`
+	From      *time.Time          `bson:"from,omitempty"`
+	To        time.Time           `bson:"to"`
+	Currency  string              `bson:"currency"`
+	Drivers   int                 `bson:"drivers"`
+	Total     float64             `bson:"total"`
+	Lines     []domain.PayoutLine `bson:"lines"`
+	CreatedBy string              `bson:"createdBy,omitempty"`
+	CreatedAt time.Time           `bson:"createdAt"`
+}
+
+// toDomain converts the stored document into a domain payout
+func (d *payoutDocument) toDomain() *domain.Payout {
+	return &domain.Payout{
+		ID:        d.ID.Hex(),
+		From:      d.From,
+		To:        d.To,
+		Currency:  d.Currency,
+		Drivers:   d.Drivers,
+		Total:     d.Total,
+		Lines:     d.Lines,
+		CreatedBy: d.CreatedBy,
+		CreatedAt: d.CreatedAt,
+	}
+}
+
+// NewEarningsRepository creates a new MongoDB earnings repository
+func NewEarningsRepository(db *mongo.Database, logger *zap.Logger) *EarningsRepository {
+	return &EarningsRepository{
+		earnings: db.Collection("driver_earnings"),
+		payouts:  db.Collection("payouts"),
+		logger:   logger,
+	}
+}
+
+// Indexes lists the ledger and payout indexes. A partial unique index on
+// tripId makes recording a trip's earning idempotent.
+func (r *EarningsRepository) Indexes() []IndexSet {
+	earnings := IndexSet{Collection: r.earnings, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "earnedAt", Value: 1}},
+			Options: options.Index().SetName("driverId_earnedAt"),
+		},
+		{
+			Keys: bson.D{{Key: "tripId", Value: 1}},
+			Options: options.Index().
+				SetName("tripId_unique").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"tripId": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys:    bson.D{{Key: "payoutId", Value: 1}, {Key: "earnedAt", Value: 1}},
+			Options: options.Index().SetName("payoutId_earnedAt"),
+		},
+		{
+			Keys: bson.D{{Key: "type", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().
+				SetName("adjustments_createdAt").
+				SetPartialFilterExpression(bson.M{"type": domain.EarningTypeAdjustment}),
+		},
+	}}
+	payouts := IndexSet{Collection: r.payouts, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("createdAt"),
+		},
+	}}
+	return []IndexSet{earnings, payouts}
+}
+
+// EnsureIndexes creates the ledger and payout indexes
+func (r *EarningsRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create earnings indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Record appends an entry to the ledger
+func (r *EarningsRepository) Record(ctx interface{}, earning *domain.Earning) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	earning.CreatedAt = time.Now()
+	doc := &earningDocument{
+		ID:         primitive.NewObjectID(),
+		DriverID:   earning.DriverID,
+		Type:       earning.Type,
+		TripID:     earning.TripID,
+		Gross:      earning.Gross,
+		Commission: earning.Commission,
+		Net:        earning.Net,
+		Currency:   earning.Currency,
+		Reason:     earning.Reason,
+		CreatedBy:  earning.CreatedBy,
+		EarnedAt:   earning.EarnedAt,
+		CreatedAt:  earning.CreatedAt,
+	}
+	if _, err := r.earnings.InsertOne(c, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) && earning.TripID != "" {
+			r.logger.Debug("trip earning already recorded", zap.String("tripId", earning.TripID))
+			return nil
+		}
+		r.logger.Error("failed to record earning", zap.Error(err), zap.String("driverId", earning.DriverID))
+		return err
+	}
+
+	earning.ID = doc.ID.Hex()
+	return nil
+}
+
+// totalsGroup sums ledger entries into domain.EarningsTotals fields
+func totalsGroup(id interface{}) bson.M {
+	isTrip := bson.M{"$eq": bson.A{"$type", domain.EarningTypeTrip}}
+	return bson.M{
+		"_id":         id,
+		"trips":       bson.M{"$sum": bson.M{"$cond": bson.A{isTrip, 1, 0}}},
+		"gross":       bson.M{"$sum": "$gross"},
+		"commission":  bson.M{"$sum": "$commission"},
+		"adjustments": bson.M{"$sum": bson.M{"$cond": bson.A{isTrip, 0, "$net"}}},
+		"net":         bson.M{"$sum": "$net"},
+	}
+}
+
+// roundTotals rounds the summed amounts to kuruş
+var roundTotals = bson.D{{Key: "$set", Value: bson.M{
+	"gross":       bson.M{"$round": bson.A{"$gross", 2}},
+	"commission":  bson.M{"$round": bson.A{"$commission", 2}},
+	"adjustments": bson.M{"$round": bson.A{"$adjustments", 2}},
+	"net":         bson.M{"$round": bson.A{"$net", 2}},
+}}}
+
+// Summarize buckets the driver's ledger by calendar day or ISO week in location
+func (r *EarningsRepository) Summarize(ctx interface{}, driverID string, from, to time.Time, period domain.EarningsPeriod, location *time.Location) ([]domain.EarningsBucket, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	unit := "day"
+	if period == domain.EarningsPeriodWeekly {
+		unit = "week"
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"driverId": driverID,
+			"earnedAt": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: totalsGroup(bson.M{"$dateTrunc": bson.M{
+			"date":        "$earnedAt",
+			"unit":        unit,
+			"timezone":    location.String(),
+			"startOfWeek": "monday",
+		}})}},
+		roundTotals,
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	cursor, err := r.earnings.Aggregate(c, pipeline)
+	if err != nil {
+		r.logger.Error("failed to summarize earnings", zap.Error(err), zap.String("driverId", driverID))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	buckets := []domain.EarningsBucket{}
+	if err := cursor.All(c, &buckets); err != nil {
+		r.logger.Error("failed to decode earnings summary", zap.Error(err), zap.String("driverId", driverID))
+		return nil, err
+	}
+	for i := range buckets {
+		buckets[i].Start = buckets[i].Start.In(location)
+	}
+	return buckets, nil
+}
+
+// ListAdjustments returns the newest adjustments first
+func (r *EarningsRepository) ListAdjustments(ctx interface{}, driverID string, limit int) ([]*domain.Earning, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{"type": domain.EarningTypeAdjustment}
+	if driverID != "" {
+		filter["driverId"] = driverID
+	}
+	cursor, err := r.earnings.Find(c, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		r.logger.Error("failed to list earning adjustments", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []earningDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode earning adjustments", zap.Error(err))
+		return nil, err
+	}
+	adjustments := make([]*domain.Earning, len(docs))
+	for i := range docs {
+		adjustments[i] = docs[i].toDomain()
+	}
+	return adjustments, nil
+}
+
+// CreatePayout claims the unpaid entries first and totals what it claimed, so
+// an entry earned while the payout is built is either fully in it or left for
+// the next one, and concurrent payouts never claim the same entry
+func (r *EarningsRepository) CreatePayout(ctx interface{}, payout *domain.Payout) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	payoutID := primitive.NewObjectID()
+	earnedAt := bson.M{"$lt": payout.To}
+	if payout.From != nil {
+		earnedAt["$gte"] = *payout.From
+	}
+	claimed, err := r.earnings.UpdateMany(c,
+		bson.M{"payoutId": bson.M{"$exists": false}, "earnedAt": earnedAt},
+		bson.M{"$set": bson.M{"payoutId": payoutID}},
+	)
+	if err != nil {
+		r.logger.Error("failed to claim earnings for payout", zap.Error(err))
+		return err
+	}
+	if claimed.ModifiedCount == 0 {
+		return nil
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"payoutId": payoutID}}},
+		{{Key: "$group", Value: totalsGroup("$driverId")}},
+		roundTotals,
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	cursor, err := r.earnings.Aggregate(c, pipeline)
+	if err != nil {
+		r.logger.Error("failed to total payout", zap.Error(err), zap.String("payoutId", payoutID.Hex()))
+		return r.releasePayout(c, payoutID, err)
+	}
+	defer cursor.Close(c)
+
+	var lines []domain.PayoutLine
+	if err := cursor.All(c, &lines); err != nil {
+		r.logger.Error("failed to decode payout lines", zap.Error(err), zap.String("payoutId", payoutID.Hex()))
+		return r.releasePayout(c, payoutID, err)
+	}
+
+	payout.CreatedAt = time.Now()
+	payout.Lines = lines
+	payout.Drivers = len(lines)
+	var totals domain.EarningsTotals
+	for _, line := range lines {
+		totals.Add(line.EarningsTotals)
+	}
+	payout.Total = totals.Net
+	doc := &payoutDocument{
+		ID:        payoutID,
+		From:      payout.From,
+		To:        payout.To,
+		Currency:  payout.Currency,
+		Drivers:   payout.Drivers,
+		Total:     payout.Total,
+		Lines:     payout.Lines,
+		CreatedBy: payout.CreatedBy,
+		CreatedAt: payout.CreatedAt,
+	}
+	if _, err := r.payouts.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create payout", zap.Error(err), zap.String("payoutId", payoutID.Hex()))
+		return r.releasePayout(c, payoutID, err)
+	}
+
+	payout.ID = payoutID.Hex()
+	return nil
+}
+
+// releasePayout returns the entries claimed by a payout that could not be
+// stored to the unpaid pool and passes cause through
+func (r *EarningsRepository) releasePayout(ctx context.Context, payoutID primitive.ObjectID, cause error) error {
+	if _, err := r.earnings.UpdateMany(ctx,
+		bson.M{"payoutId": payoutID},
+		bson.M{"$unset": bson.M{"payoutId": ""}},
+	); err != nil {
+		r.logger.Error("failed to release earnings of failed payout", zap.Error(err), zap.String("payoutId", payoutID.Hex()))
+	}
+	return cause
+}
+
+// GetPayout retrieves a payout by ID
+func (r *EarningsRepository) GetPayout(ctx interface{}, id string) (*domain.Payout, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("payout not found")
+	}
+
+	var doc payoutDocument
+	if err := r.payouts.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("payout not found")
+		}
+		r.logger.Error("failed to get payout", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// ListPayouts returns the newest payouts first, without their lines
+func (r *EarningsRepository) ListPayouts(ctx interface{}, limit int) ([]*domain.Payout, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	cursor, err := r.payouts.Find(c, bson.M{}, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"lines": 0}))
+	if err != nil {
+		r.logger.Error("failed to list payouts", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []payoutDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode payouts", zap.Error(err))
+		return nil, err
+	}
+	payouts := make([]*domain.Payout, len(docs))
+	for i := range docs {
+		payouts[i] = docs[i].toDomain()
+	}
+	return payouts, nil
+}
@@ -0,0 +1,148 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// FleetRepository implements domain.FleetRepository using MongoDB
+type FleetRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// fleetDocument is the stored representation of a fleet with a native ObjectID
+type fleetDocument struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Name        string             `bson:"name"`
+	CompanyName string             `bson:"companyName,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+// toDomain converts the stored document into a domain fleet
+func (d *fleetDocument) toDomain() *domain.Fleet {
+	return &domain.Fleet{
+		ID:          d.ID.Hex(),
+		Name:        d.Name,
+		CompanyName: d.CompanyName,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
+
+// NewFleetRepository creates a new MongoDB fleet repository
+func NewFleetRepository(db *mongo.Database, logger *zap.Logger) *FleetRepository {
+	return &FleetRepository{
+		collection: db.Collection("fleets"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the unique index on fleet names
+func (r *FleetRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("name_unique").SetUnique(true),
+		},
+	}}}
+}
+
+// EnsureIndexes makes fleet names unique
+func (r *FleetRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create fleet indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new fleet into MongoDB
+func (r *FleetRepository) Create(ctx interface{}, fleet *domain.Fleet) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	fleet.CreatedAt = time.Now()
+	fleet.UpdatedAt = fleet.CreatedAt
+
+	doc := &fleetDocument{
+		ID:          primitive.NewObjectID(),
+		Name:        fleet.Name,
+		CompanyName: fleet.CompanyName,
+		CreatedAt:   fleet.CreatedAt,
+		UpdatedAt:   fleet.UpdatedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("fleet name already exists")
+		}
+		r.logger.Error("failed to create fleet", zap.Error(err))
+		return err
+	}
+
+	fleet.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetByID retrieves a fleet by ID
+func (r *FleetRepository) GetByID(ctx interface{}, id string) (*domain.Fleet, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid fleet ID")
+	}
+
+	var doc fleetDocument
+	err = r.collection.FindOne(c, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("fleet not found")
+		}
+		r.logger.Error("failed to get fleet by ID", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+
+	return doc.toDomain(), nil
+}
+
+// List returns all fleets ordered by name
+func (r *FleetRepository) List(ctx interface{}) ([]*domain.Fleet, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	cursor, err := r.collection.Find(c, bson.M{}, options.Find().SetSort(bson.M{"name": 1}))
+	if err != nil {
+		r.logger.Error("failed to list fleets", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []fleetDocument
+	if err = cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode fleets", zap.Error(err))
+		return nil, err
+	}
+
+	fleets := make([]*domain.Fleet, len(docs))
+	for i := range docs {
+		fleets[i] = docs[i].toDomain()
+	}
+	return fleets, nil
+}
@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// geoCacheLag holds back the newest changes when polling, like the delta sync
+// does: writers stamp updatedAt and lastSeenAt before their write commits, so
+// each poll re-reads a short window the previous one may have missed
+const geoCacheLag = 2 * time.Second
+
+// geoCacheBatch bounds the changes read per query while polling
+const geoCacheBatch = 1000
+
+// cacheDriver applies a write of this instance to the geo cache
+func (r *DriverRepository) cacheDriver(id string, driver *domain.Driver) {
+	if r.geo == nil {
+		return
+	}
+	cached := *driver
+	cached.ID = id
+	r.geo.Upsert(&cached)
+}
+
+// ApplyChange applies a streamed driver change to the geo cache, so writes of
+// other instances show up without waiting for the next poll
+func (r *DriverRepository) ApplyChange(change *domain.DriverChange) {
+	if r.geo == nil {
+		return
+	}
+	if change.Driver == nil {
+		r.geo.Remove(change.DriverID)
+		return
+	}
+	r.geo.Upsert(change.Driver)
+}
+
+// GeoCacheLoaded reports whether the geo cache has been loaded once; it is
+// always true without WithGeoCache
+func (r *DriverRepository) GeoCacheLoaded() bool {
+	return r.geo == nil || r.geo.Loaded()
+}
+
+// RunGeoCache keeps the geo cache in step with MongoDB until ctx is cancelled.
+// The cache is loaded in full at start and every reconcileInterval, which also
+// repairs anything a poll missed; in between, changed drivers and heartbeats
+// are polled every pollInterval. Without WithGeoCache it returns at once.
+func (r *DriverRepository) RunGeoCache(ctx context.Context, pollInterval, reconcileInterval time.Duration) {
+	if r.geo == nil {
+		return
+	}
+	if pollInterval <= 0 {
+		pollInterval = 2 * time.Second
+	}
+	if reconcileInterval <= 0 {
+		reconcileInterval = 5 * time.Minute
+	}
+
+	r.reconcileGeoCache(ctx)
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	reconcile := time.NewTicker(reconcileInterval)
+	defer reconcile.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reconcile.C:
+			r.reconcileGeoCache(ctx)
+		case <-poll.C:
+			// Keep trying to load a cache whose first load failed
+			if !r.geoLoaded {
+				r.reconcileGeoCache(ctx)
+				continue
+			}
+			if err := r.pollGeoCache(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Warn("failed to refresh geo cache", zap.Error(err))
+			}
+		}
+	}
+}
+
+// reconcileGeoCache replaces the cache with every stored driver
+func (r *DriverRepository) reconcileGeoCache(ctx context.Context) {
+	start := time.Now()
+
+	var docs []driverDocument
+	err := r.retrier.Do(ctx, "load geo cache", true, func() error {
+		cursor, err := r.collection.Find(ctx, bson.M{"deletedAt": notDeleted})
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		docs = nil
+		return cursor.All(ctx, &docs)
+	})
+	var drivers []*domain.Driver
+	if err == nil {
+		drivers, err = r.openAll(docs)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Warn("failed to load geo cache", zap.Error(err))
+		}
+		return
+	}
+
+	r.geo.Replace(drivers)
+	// Writes made while loading are read again by the next poll
+	r.geoChanges = domain.ChangePosition{UpdatedAt: start.Add(-geoCacheLag)}
+	r.geoHeartbeat = start.Add(-geoCacheLag)
+	r.geoLoaded = true
+	r.geo.MarkRefreshed(start)
+	r.logger.Info("geo cache loaded", zap.Int("drivers", len(drivers)), zap.Duration("elapsed", time.Since(start)))
+}
+
+// pollGeoCache applies the drivers changed and the heartbeats recorded since
+// the previous poll, by this or any other instance
+func (r *DriverRepository) pollGeoCache(ctx context.Context) error {
+	start := time.Now()
+	until := start.Add(-geoCacheLag)
+
+	for {
+		changes, err := r.ListChanges(ctx, domain.DriverFilter{}, r.geoChanges, until, geoCacheBatch)
+		if err != nil {
+			return err
+		}
+		for _, driver := range changes {
+			// Tombstones remove the driver
+			r.geo.Upsert(driver)
+			r.geoChanges = domain.ChangePosition{UpdatedAt: driver.UpdatedAt, ID: driver.ID}
+		}
+		if len(changes) < geoCacheBatch {
+			break
+		}
+	}
+
+	// Heartbeats only write lastSeenAt, so they are not in the change feed
+	var seen []struct {
+		ID         primitive.ObjectID `bson:"_id"`
+		LastSeenAt time.Time          `bson:"lastSeenAt"`
+	}
+	query := bson.M{"lastSeenAt": bson.M{"$gte": r.geoHeartbeat}, "deletedAt": notDeleted}
+	findOptions := options.Find().SetProjection(bson.M{"_id": 1, "lastSeenAt": 1})
+	err := r.retrier.Do(ctx, "poll heartbeats", true, func() error {
+		cursor, err := r.collection.Find(ctx, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+		seen = nil
+		return cursor.All(ctx, &seen)
+	})
+	if err != nil {
+		return err
+	}
+	for _, heartbeat := range seen {
+		r.geo.Touch(heartbeat.ID.Hex(), heartbeat.LastSeenAt)
+	}
+	r.geoHeartbeat = until
+
+	r.geo.MarkRefreshed(start)
+	return nil
+}
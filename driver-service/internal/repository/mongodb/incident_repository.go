@@ -0,0 +1,277 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// IncidentRepository implements domain.IncidentRepository using MongoDB
+type IncidentRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// incidentDocument is the stored representation of an incident
+type incidentDocument struct {
+	ID              primitive.ObjectID      `bson:"_id"`
+	DriverID        string                  `bson:"driverId"`
+	FleetID         string                  `bson:"fleetId,omitempty"`
+	TripID          string                  `bson:"tripId,omitempty"`
+	Category        domain.IncidentCategory `bson:"category"`
+	Severity        domain.IncidentSeverity `bson:"severity"`
+	Description     string                  `bson:"description"`
+	Status          domain.IncidentStatus   `bson:"status"`
+	ReportedBy      string                  `bson:"reportedBy,omitempty"`
+	ReporterRole    string                  `bson:"reporterRole,omitempty"`
+	DriverSuspended bool                    `bson:"driverSuspended,omitempty"`
+	IdentityRevoked bool                    `bson:"identityRevoked,omitempty"`
+	Outcome         domain.IncidentOutcome  `bson:"outcome,omitempty"`
+	Note            string                  `bson:"note,omitempty"`
+	CreatedAt       time.Time               `bson:"createdAt"`
+	UpdatedAt       time.Time               `bson:"updatedAt"`
+	ResolvedAt      *time.Time              `bson:"resolvedAt,omitempty"`
+}
+
+func (d *incidentDocument) toDomain() *domain.Incident {
+	return &domain.Incident{
+		ID:              d.ID.Hex(),
+		DriverID:        d.DriverID,
+		FleetID:         d.FleetID,
+		TripID:          d.TripID,
+		Category:        d.Category,
+		Severity:        d.Severity,
+		Description:     d.Description,
+		Status:          d.Status,
+		ReportedBy:      d.ReportedBy,
+		ReporterRole:    d.ReporterRole,
+		DriverSuspended: d.DriverSuspended,
+		IdentityRevoked: d.IdentityRevoked,
+		Outcome:         d.Outcome,
+		Note:            d.Note,
+		CreatedAt:       d.CreatedAt,
+		UpdatedAt:       d.UpdatedAt,
+		ResolvedAt:      d.ResolvedAt,
+	}
+}
+
+// NewIncidentRepository creates a new MongoDB incident repository
+func NewIncidentRepository(db *mongo.Database, logger *zap.Logger) *IncidentRepository {
+	return &IncidentRepository{
+		collection: db.Collection("incidents"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the incident listing indexes
+func (r *IncidentRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			// A driver's incidents, newest first, and the open suspensions of a driver
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("driverId_createdAt"),
+		},
+		{
+			// Fleet incident queues
+			Keys:    bson.D{{Key: "fleetId", Value: 1}, {Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("fleetId_status_createdAt"),
+		},
+		{
+			// Operations' incident queue
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("status_createdAt"),
+		},
+		{
+			// Incidents of a trip
+			Keys: bson.D{{Key: "tripId", Value: 1}},
+			Options: options.Index().SetName("tripId").
+				SetPartialFilterExpression(bson.M{"tripId": bson.M{"$exists": true}}),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the incident indexes
+func (r *IncidentRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create incident indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new incident
+func (r *IncidentRepository) Create(ctx interface{}, incident *domain.Incident) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &incidentDocument{
+		ID:              primitive.NewObjectID(),
+		DriverID:        incident.DriverID,
+		FleetID:         incident.FleetID,
+		TripID:          incident.TripID,
+		Category:        incident.Category,
+		Severity:        incident.Severity,
+		Description:     incident.Description,
+		Status:          incident.Status,
+		ReportedBy:      incident.ReportedBy,
+		ReporterRole:    incident.ReporterRole,
+		DriverSuspended: incident.DriverSuspended,
+		IdentityRevoked: incident.IdentityRevoked,
+		CreatedAt:       incident.CreatedAt,
+		UpdatedAt:       incident.UpdatedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create incident", zap.Error(err), zap.String("driverId", incident.DriverID))
+		return err
+	}
+
+	incident.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetByID retrieves an incident by ID
+func (r *IncidentRepository) GetByID(ctx interface{}, id string) (*domain.Incident, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("incident not found")
+	}
+
+	var doc incidentDocument
+	if err := r.collection.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("incident not found")
+		}
+		r.logger.Error("failed to get incident", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// List returns a page of the incidents matching the filter, newest first
+func (r *IncidentRepository) List(ctx interface{}, filter domain.IncidentFilter, page, pageSize int) ([]*domain.Incident, int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	query := bson.M{}
+	if filter.DriverID != "" {
+		query["driverId"] = filter.DriverID
+	}
+	if filter.FleetID != "" {
+		query["fleetId"] = filter.FleetID
+	}
+	if filter.TripID != "" {
+		query["tripId"] = filter.TripID
+	}
+	if filter.Category != "" {
+		query["category"] = filter.Category
+	}
+	if filter.Severity != "" {
+		query["severity"] = filter.Severity
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	total, err := r.collection.CountDocuments(c, query)
+	if err != nil {
+		r.logger.Error("failed to count incidents", zap.Error(err))
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64((page - 1) * pageSize)).
+		SetLimit(int64(pageSize))
+	cursor, err := r.collection.Find(c, query, opts)
+	if err != nil {
+		r.logger.Error("failed to list incidents", zap.Error(err))
+		return nil, 0, err
+	}
+	defer cursor.Close(c)
+
+	var docs []incidentDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode incidents", zap.Error(err))
+		return nil, 0, err
+	}
+	incidents := make([]*domain.Incident, len(docs))
+	for i := range docs {
+		incidents[i] = docs[i].toDomain()
+	}
+	return incidents, total, nil
+}
+
+// Transition stores the investigation fields of an incident still in status from
+func (r *IncidentRepository) Transition(ctx interface{}, incident *domain.Incident, from domain.IncidentStatus) (bool, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(incident.ID)
+	if err != nil {
+		return false, errors.New("incident not found")
+	}
+
+	set := bson.M{
+		"status":    incident.Status,
+		"severity":  incident.Severity,
+		"updatedAt": incident.UpdatedAt,
+	}
+	if incident.DriverSuspended {
+		set["driverSuspended"] = true
+	}
+	if incident.IdentityRevoked {
+		set["identityRevoked"] = true
+	}
+	if incident.Outcome != "" {
+		set["outcome"] = incident.Outcome
+	}
+	if incident.Note != "" {
+		set["note"] = incident.Note
+	}
+	if incident.ResolvedAt != nil {
+		set["resolvedAt"] = incident.ResolvedAt
+	}
+	result, err := r.collection.UpdateOne(c, bson.M{"_id": objectID, "status": from}, bson.M{"$set": set})
+	if err != nil {
+		r.logger.Error("failed to update incident", zap.Error(err), zap.String("id", incident.ID))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// CountUnresolvedSuspensions counts the driver's unresolved incidents that suspended the driver
+func (r *IncidentRepository) CountUnresolvedSuspensions(ctx interface{}, driverID string) (int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	count, err := r.collection.CountDocuments(c, bson.M{
+		"driverId":        driverID,
+		"driverSuspended": true,
+		"status":          bson.M{"$ne": domain.IncidentStatusResolved},
+	})
+	if err != nil {
+		r.logger.Error("failed to count incident suspensions", zap.Error(err), zap.String("driverId", driverID))
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// IndexSet is the indexes a repository expects on one collection. Every model
+// must be named so it can be matched against the existing indexes.
+type IndexSet struct {
+	Collection *mongo.Collection
+	Models     []mongo.IndexModel
+}
+
+// ensureIndexes creates every index of the sets; existing identical indexes are left alone
+func ensureIndexes(ctx context.Context, sets []IndexSet) error {
+	for _, set := range sets {
+		if _, err := set.Collection.Indexes().CreateMany(ctx, set.Models); err != nil {
+			return fmt.Errorf("%s: %w", set.Collection.Name(), err)
+		}
+	}
+	return nil
+}
+
+// IndexCatalog implements domain.IndexCatalog over the index sets of the repositories
+type IndexCatalog struct {
+	sets   []IndexSet
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewIndexCatalog creates a catalog of the given index sets
+func NewIndexCatalog(logger *zap.Logger, sets ...IndexSet) *IndexCatalog {
+	return &IndexCatalog{
+		sets:   sets,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// existingIndex is the subset of listIndexes output the catalog compares
+type existingIndex struct {
+	Name string `bson:"name"`
+	Key  bson.D `bson:"key"`
+}
+
+// Report compares the expected indexes with the ones that exist. The default
+// _id index is never reported as unexpected.
+func (r *IndexCatalog) Report(ctx interface{}) (*domain.IndexReport, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	report := &domain.IndexReport{Indexes: []domain.IndexStatus{}, CheckedAt: r.now().UTC()}
+	for _, set := range r.sets {
+		existing, err := listIndexes(c, set.Collection)
+		if err != nil {
+			r.logger.Error("failed to list indexes", zap.Error(err), zap.String("collection", set.Collection.Name()))
+			return nil, err
+		}
+
+		expected := make(map[string]bool, len(set.Models))
+		for _, model := range set.Models {
+			name := indexName(model)
+			expected[name] = true
+			status := domain.IndexStatus{
+				Collection: set.Collection.Name(),
+				Name:       name,
+				Keys:       formatKeys(model.Keys),
+				State:      domain.IndexPresent,
+			}
+			actual, found := existing[name]
+			switch {
+			case !found:
+				status.State = domain.IndexMissing
+				report.Missing++
+			case formatKeys(actual.Key) != status.Keys:
+				status.State = domain.IndexMismatch
+				status.ActualKeys = formatKeys(actual.Key)
+				report.Mismatched++
+			}
+			report.Indexes = append(report.Indexes, status)
+		}
+
+		extra := make([]string, 0)
+		for name := range existing {
+			if name != "_id_" && !expected[name] {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			report.Indexes = append(report.Indexes, domain.IndexStatus{
+				Collection: set.Collection.Name(),
+				Name:       name,
+				Keys:       formatKeys(existing[name].Key),
+				State:      domain.IndexUnexpected,
+			})
+			report.Unexpected++
+		}
+	}
+
+	report.InSync = report.Missing == 0 && report.Mismatched == 0
+	return report, nil
+}
+
+// CreateIndex builds one expected index in the background
+func (r *IndexCatalog) CreateIndex(ctx interface{}, collection, name string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	for _, set := range r.sets {
+		if set.Collection.Name() != collection {
+			continue
+		}
+		for _, model := range set.Models {
+			if indexName(model) != name {
+				continue
+			}
+			// Copy the options so the shared model is not modified. Servers before 4.2
+			// honour the background flag; newer ones never block the collection.
+			opts := options.Index()
+			if model.Options != nil {
+				*opts = *model.Options
+			}
+			background := true
+			opts.Background = &background
+			_, err := set.Collection.Indexes().CreateOne(c, mongo.IndexModel{Keys: model.Keys, Options: opts})
+			if err != nil {
+				r.logger.Error("failed to create index", zap.Error(err), zap.String("collection", collection), zap.String("index", name))
+				return err
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("index %s.%s is not expected", collection, name)
+}
+
+func listIndexes(ctx context.Context, collection *mongo.Collection) (map[string]existingIndex, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []existingIndex
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]existingIndex, len(indexes))
+	for _, index := range indexes {
+		byName[index.Name] = index
+	}
+	return byName, nil
+}
+
+func indexName(model mongo.IndexModel) string {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name
+	}
+	return formatKeys(model.Keys)
+}
+
+// formatKeys renders a key pattern as "field:direction,..." so numeric types
+// returned by the server compare equal to the ones in the models
+func formatKeys(keys interface{}) string {
+	doc, ok := keys.(bson.D)
+	if !ok {
+		return fmt.Sprint(keys)
+	}
+	parts := make([]string, len(doc))
+	for i, elem := range doc {
+		parts[i] = fmt.Sprintf("%s:%v", elem.Key, elem.Value)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,185 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// KYCRepository implements domain.KYCRepository using MongoDB
+type KYCRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// kycCheckDocument is the stored representation of an identity check
+type kycCheckDocument struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	DriverID    string             `bson:"driverId"`
+	Provider    string             `bson:"provider"`
+	Reference   string             `bson:"reference"`
+	Status      domain.KYCStatus   `bson:"status"`
+	Reason      string             `bson:"reason,omitempty"`
+	SubmittedAt time.Time          `bson:"submittedAt"`
+	DecidedAt   *time.Time         `bson:"decidedAt,omitempty"`
+}
+
+func (d *kycCheckDocument) toDomain() *domain.KYCCheck {
+	return &domain.KYCCheck{
+		ID:          d.ID.Hex(),
+		DriverID:    d.DriverID,
+		Provider:    d.Provider,
+		Reference:   d.Reference,
+		Status:      d.Status,
+		Reason:      d.Reason,
+		SubmittedAt: d.SubmittedAt,
+		DecidedAt:   d.DecidedAt,
+	}
+}
+
+// NewKYCRepository creates a new MongoDB identity check repository
+func NewKYCRepository(db *mongo.Database, logger *zap.Logger) *KYCRepository {
+	return &KYCRepository{
+		collection: db.Collection("kyc_checks"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the driver history, provider reference and pending-check indexes
+func (r *KYCRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "submittedAt", Value: -1}},
+			Options: options.Index().SetName("driverId_submittedAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "reference", Value: 1}},
+			Options: options.Index().SetName("provider_reference").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "submittedAt", Value: 1}},
+			Options: options.Index().SetName("status_submittedAt"),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the identity check indexes
+func (r *KYCRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create kyc indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new check
+func (r *KYCRepository) Create(ctx interface{}, check *domain.KYCCheck) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &kycCheckDocument{
+		ID:          primitive.NewObjectID(),
+		DriverID:    check.DriverID,
+		Provider:    check.Provider,
+		Reference:   check.Reference,
+		Status:      check.Status,
+		Reason:      check.Reason,
+		SubmittedAt: check.SubmittedAt,
+		DecidedAt:   check.DecidedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create kyc check", zap.Error(err), zap.String("driverId", check.DriverID))
+		return err
+	}
+
+	check.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetLatest returns the driver's most recent check
+func (r *KYCRepository) GetLatest(ctx interface{}, driverID string) (*domain.KYCCheck, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "submittedAt", Value: -1}})
+	return r.findOne(ctx, bson.M{"driverId": driverID}, opts)
+}
+
+// GetByReference returns the check a provider knows by reference
+func (r *KYCRepository) GetByReference(ctx interface{}, provider, reference string) (*domain.KYCCheck, error) {
+	return r.findOne(ctx, bson.M{"provider": provider, "reference": reference})
+}
+
+func (r *KYCRepository) findOne(ctx interface{}, filter bson.M, opts ...*options.FindOneOptions) (*domain.KYCCheck, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var doc kycCheckDocument
+	if err := r.collection.FindOne(c, filter, opts...).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("kyc check not found")
+		}
+		r.logger.Error("failed to get kyc check", zap.Error(err))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// ListPending returns pending checks submitted up to the given time, oldest first
+func (r *KYCRepository) ListPending(ctx interface{}, submittedUntil time.Time, limit int) ([]*domain.KYCCheck, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{"status": domain.KYCStatusPending, "submittedAt": bson.M{"$lte": submittedUntil}}
+	opts := options.Find().SetSort(bson.D{{Key: "submittedAt", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(c, filter, opts)
+	if err != nil {
+		r.logger.Error("failed to list pending kyc checks", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []kycCheckDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode pending kyc checks", zap.Error(err))
+		return nil, err
+	}
+	checks := make([]*domain.KYCCheck, len(docs))
+	for i := range docs {
+		checks[i] = docs[i].toDomain()
+	}
+	return checks, nil
+}
+
+// Decide records the outcome of a pending check; it reports false when the
+// check was already decided, e.g. by a webhook racing a poll
+func (r *KYCRepository) Decide(ctx interface{}, id string, status domain.KYCStatus, reason string, at time.Time) (bool, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, errors.New("kyc check not found")
+	}
+
+	filter := bson.M{"_id": objectID, "status": domain.KYCStatusPending}
+	update := bson.M{"$set": bson.M{"status": status, "reason": reason, "decidedAt": at}}
+	result, err := r.collection.UpdateOne(c, filter, update)
+	if err != nil {
+		r.logger.Error("failed to decide kyc check", zap.Error(err), zap.String("id", id))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
@@ -0,0 +1,259 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/event"
+	"go.uber.org/zap"
+)
+
+// queryBucketsMs are the upper bounds of the command duration histogram
+var queryBucketsMs = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// unmonitoredCommands are connection and session housekeeping, not queries
+var unmonitoredCommands = map[string]bool{
+	"hello": true, "isMaster": true, "ismaster": true, "ping": true, "buildInfo": true,
+	"saslStart": true, "saslContinue": true, "endSessions": true, "killCursors": true,
+}
+
+// filterFields names the field holding the filter of each command
+var filterFields = map[string]string{
+	"find":          "filter",
+	"count":         "query",
+	"distinct":      "query",
+	"findAndModify": "query",
+	"aggregate":     "pipeline",
+	"update":        "updates",
+	"delete":        "deletes",
+}
+
+// QueryMonitor times every MongoDB command the client sends, keeps a duration
+// histogram per collection and command, and logs commands slower than the
+// threshold with their filter. Filter values are redacted, so the log shows
+// which fields were queried, which is what a missing index needs, without the
+// personal data in them.
+type QueryMonitor struct {
+	threshold time.Duration
+	logger    *zap.Logger
+
+	mu      sync.Mutex
+	pending map[int64]pendingCommand
+	stats   map[queryKey]*queryHistogram
+}
+
+type queryKey struct {
+	collection string
+	command    string
+}
+
+// pendingCommand is what is kept of a command until it finishes
+type pendingCommand struct {
+	key    queryKey
+	filter bson.RawValue
+}
+
+type queryHistogram struct {
+	count   int64
+	errors  int64
+	slow    int64
+	total   time.Duration
+	max     time.Duration
+	buckets []int64
+}
+
+// NewQueryMonitor creates a monitor logging commands that take threshold or
+// longer; a threshold of 0 turns the slow query log off
+func NewQueryMonitor(threshold time.Duration, logger *zap.Logger) *QueryMonitor {
+	return &QueryMonitor{
+		threshold: threshold,
+		logger:    logger,
+		pending:   make(map[int64]pendingCommand),
+		stats:     make(map[queryKey]*queryHistogram),
+	}
+}
+
+// CommandMonitor returns the monitor to pass to options.Client().SetMonitor
+func (m *QueryMonitor) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: m.started,
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			m.finished(ctx, e.RequestID, e.Duration, nil)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			m.finished(ctx, e.RequestID, e.Duration, &e.Failure)
+		},
+	}
+}
+
+func (m *QueryMonitor) started(_ context.Context, e *event.CommandStartedEvent) {
+	if unmonitoredCommands[e.CommandName] {
+		return
+	}
+	pending := pendingCommand{key: queryKey{command: e.CommandName}}
+	// The first element of a command names it, with the collection as its value
+	if first, err := e.Command.IndexErr(0); err == nil {
+		pending.key.collection, _ = first.Value().StringValueOK()
+	}
+	if e.CommandName == "getMore" {
+		pending.key.collection, _ = e.Command.Lookup("collection").StringValueOK()
+	}
+	if field, ok := filterFields[e.CommandName]; ok {
+		if filter, err := e.Command.LookupErr(field); err == nil {
+			// The event's buffer is reused once the command is sent
+			filter.Value = append([]byte(nil), filter.Value...)
+			pending.filter = filter
+		}
+	}
+
+	m.mu.Lock()
+	m.pending[e.RequestID] = pending
+	m.mu.Unlock()
+}
+
+func (m *QueryMonitor) finished(ctx context.Context, requestID int64, elapsed time.Duration, failure *string) {
+	m.mu.Lock()
+	pending, ok := m.pending[requestID]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	delete(m.pending, requestID)
+
+	slow := m.threshold > 0 && elapsed >= m.threshold
+	stats := m.stats[pending.key]
+	if stats == nil {
+		stats = &queryHistogram{buckets: make([]int64, len(queryBucketsMs))}
+		m.stats[pending.key] = stats
+	}
+	stats.count++
+	stats.total += elapsed
+	if elapsed > stats.max {
+		stats.max = elapsed
+	}
+	if failure != nil {
+		stats.errors++
+	}
+	if slow {
+		stats.slow++
+	}
+	ms := milliseconds(elapsed)
+	for i, le := range queryBucketsMs {
+		if ms <= le {
+			stats.buckets[i]++
+		}
+	}
+	m.mu.Unlock()
+
+	if !slow {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("collection", pending.key.collection),
+		zap.String("command", pending.key.command),
+		zap.Duration("duration", elapsed),
+	}
+	if pending.filter.Type != 0 {
+		fields = append(fields, zap.String("filter", redactFilter(pending.filter)))
+	}
+	if operation := domain.OperationFromContext(ctx); operation != "" {
+		fields = append(fields, zap.String("operation", operation))
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.TenantID != "" {
+		fields = append(fields, zap.String("tenantId", identity.TenantID))
+	}
+	if failure != nil {
+		fields = append(fields, zap.String("error", *failure))
+	}
+	m.logger.Warn("slow MongoDB query", fields...)
+}
+
+// Stats returns the histograms, the collection and command taking the most
+// time in total first
+func (m *QueryMonitor) Stats() []domain.QueryStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]domain.QueryStats, 0, len(m.stats))
+	for key, h := range m.stats {
+		s := domain.QueryStats{
+			Collection: key.collection,
+			Command:    key.command,
+			Count:      h.count,
+			Errors:     h.errors,
+			Slow:       h.slow,
+			TotalMs:    milliseconds(h.total),
+			MaxMs:      milliseconds(h.max),
+			Buckets:    make([]domain.QueryBucket, 0, len(queryBucketsMs)+1),
+		}
+		for i, le := range queryBucketsMs {
+			s.Buckets = append(s.Buckets, domain.QueryBucket{LeMs: le, Count: h.buckets[i]})
+		}
+		s.Buckets = append(s.Buckets, domain.QueryBucket{Count: h.count})
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalMs != stats[j].TotalMs {
+			return stats[i].TotalMs > stats[j].TotalMs
+		}
+		return stats[i].Collection+stats[i].Command < stats[j].Collection+stats[j].Command
+	})
+	return stats
+}
+
+// redactFilter renders a filter as JSON with every value replaced by "?".
+// Field names and operators are kept, and arrays of values collapse to one
+// element, so filters that differ only in their values read the same.
+func redactFilter(filter bson.RawValue) string {
+	data, err := json.Marshal(redactValue(filter))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func redactValue(value bson.RawValue) interface{} {
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		elements, err := value.Document().Elements()
+		if err != nil {
+			return "?"
+		}
+		doc := make(map[string]interface{}, len(elements))
+		for _, element := range elements {
+			doc[element.Key()] = redactValue(element.Value())
+		}
+		return doc
+	case bsontype.Array:
+		values, err := value.Array().Values()
+		if err != nil {
+			return "?"
+		}
+		redacted := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			r := redactValue(v)
+			if s, ok := r.(string); ok && s == "?" {
+				// A list of values says no more than one value
+				if len(redacted) == 0 || redacted[len(redacted)-1] != "?" {
+					redacted = append(redacted, r)
+				}
+				continue
+			}
+			redacted = append(redacted, r)
+		}
+		return redacted
+	default:
+		return "?"
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,77 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func startCommand(t *testing.T, m *QueryMonitor, requestID int64, name string, command bson.D) {
+	t.Helper()
+	raw, err := bson.Marshal(command)
+	if err != nil {
+		t.Fatalf("marshal command: %v", err)
+	}
+	m.started(context.Background(), &event.CommandStartedEvent{Command: raw, CommandName: name, RequestID: requestID})
+}
+
+func TestRedactFilter(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "filter", Value: bson.D{
+		{Key: "location", Value: bson.D{{Key: "$near", Value: bson.D{{Key: "type", Value: "Point"}}}}},
+		{Key: "plate", Value: "34ABC123"},
+		{Key: "taxiType", Value: bson.D{{Key: "$in", Value: bson.A{"sari", "siyah"}}}},
+	}}})
+	if err != nil {
+		t.Fatalf("marshal filter: %v", err)
+	}
+
+	got := redactFilter(bson.Raw(raw).Lookup("filter"))
+	assert.Equal(t, `{"location":{"$near":{"type":"?"}},"plate":"?","taxiType":{"$in":["?"]}}`, got)
+}
+
+func TestQueryMonitor(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	m := NewQueryMonitor(100*time.Millisecond, zap.New(core))
+	ctx := context.Background()
+
+	startCommand(t, m, 1, "find", bson.D{{Key: "find", Value: "drivers"}, {Key: "filter", Value: bson.D{{Key: "plate", Value: "34ABC123"}}}})
+	m.finished(ctx, 1, 3*time.Millisecond, nil)
+	startCommand(t, m, 2, "find", bson.D{{Key: "find", Value: "drivers"}, {Key: "filter", Value: bson.D{{Key: "plate", Value: "34XYZ99"}}}})
+	m.finished(ctx, 2, 150*time.Millisecond, nil)
+	startCommand(t, m, 3, "insert", bson.D{{Key: "insert", Value: "trips"}})
+	failure := "duplicate key"
+	m.finished(ctx, 3, 20*time.Millisecond, &failure)
+	startCommand(t, m, 4, "ping", bson.D{{Key: "ping", Value: 1}})
+	m.finished(ctx, 4, time.Second, nil)
+
+	stats := m.Stats()
+	if assert.Len(t, stats, 2) {
+		find := stats[0]
+		assert.Equal(t, "drivers", find.Collection)
+		assert.Equal(t, "find", find.Command)
+		assert.Equal(t, int64(2), find.Count)
+		assert.Equal(t, int64(1), find.Slow)
+		assert.Equal(t, 153.0, find.TotalMs)
+		assert.Equal(t, 150.0, find.MaxMs)
+		assert.Equal(t, int64(1), find.Buckets[1].Count, "le 5ms")
+		assert.Equal(t, int64(2), find.Buckets[len(find.Buckets)-1].Count, "+Inf")
+
+		insert := stats[1]
+		assert.Equal(t, "trips", insert.Collection)
+		assert.Equal(t, int64(1), insert.Errors)
+		assert.Equal(t, int64(0), insert.Slow)
+	}
+
+	slow := logs.FilterMessage("slow MongoDB query").All()
+	if assert.Len(t, slow, 1) {
+		fields := slow[0].ContextMap()
+		assert.Equal(t, "drivers", fields["collection"])
+		assert.Equal(t, `{"plate":"?"}`, fields["filter"])
+	}
+}
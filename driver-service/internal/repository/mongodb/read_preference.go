@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+	"go.uber.org/zap"
+)
+
+// minMaxStaleness is the smallest max staleness MongoDB accepts
+const minMaxStaleness = 90 * time.Second
+
+// ReadPreferences picks the replica-set members reads are served from, by
+// the class of the request they serve. Writes always go to the primary.
+type ReadPreferences struct {
+	// Reads serves requests that only read, e.g. getting or listing drivers
+	Reads *readpref.ReadPref
+	// Writes serves the reads of requests that write, e.g. loading the
+	// driver an update changes; anything but the primary risks lost updates
+	Writes *readpref.ReadPref
+}
+
+// NewReadPreference builds a read preference from its mode, e.g. "nearest" or
+// "secondaryPreferred", tag sets such as "region:eu-west,zone:a" tried in
+// order, and the max staleness of secondaries, zero for none. Tags and max
+// staleness only pick secondaries, so the primary mode ignores them.
+func NewReadPreference(mode string, tagSets []string, maxStaleness time.Duration) (*readpref.ReadPref, error) {
+	parsed, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, fmt.Errorf("unknown read preference %q", mode)
+	}
+	if parsed == readpref.PrimaryMode {
+		return readpref.Primary(), nil
+	}
+	if maxStaleness > 0 && maxStaleness < minMaxStaleness {
+		return nil, fmt.Errorf("read preference max staleness must be at least %s", minMaxStaleness)
+	}
+
+	var opts []readpref.Option
+	if len(tagSets) > 0 {
+		sets := make([]tag.Set, 0, len(tagSets))
+		for _, spec := range tagSets {
+			set, err := parseTagSet(spec)
+			if err != nil {
+				return nil, err
+			}
+			sets = append(sets, set)
+		}
+		opts = append(opts, readpref.WithTagSets(sets...))
+	}
+	if maxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(maxStaleness))
+	}
+	return readpref.New(parsed, opts...)
+}
+
+// parseTagSet parses "name:value" pairs separated by commas; an empty set
+// matches any member
+func parseTagSet(spec string) (tag.Set, error) {
+	set := tag.Set{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid read preference tag %q, want name:value", pair)
+		}
+		set = append(set, tag.Tag{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	return set, nil
+}
+
+// WithReadPreferences serves reads from the members picked by prefs instead
+// of the primary. Requests sending a consistency token still see the writes
+// the token was issued for; see withSession.
+func WithReadPreferences(prefs ReadPreferences) DriverRepositoryOption {
+	return func(r *DriverRepository) {
+		r.reads = readCollection(r.collection, prefs.Reads)
+		r.writeReads = readCollection(r.collection, prefs.Writes)
+	}
+}
+
+// readCollection returns a handle on collection reading from pref. The read
+// concern is set so causally consistent sessions can wait for a token.
+func readCollection(collection *mongo.Collection, pref *readpref.ReadPref) *mongo.Collection {
+	if pref == nil {
+		pref = readpref.Primary()
+	}
+	opts := options.Collection().SetReadPreference(pref).SetReadConcern(readconcern.Local())
+	return collection.Database().Collection(collection.Name(), opts)
+}
+
+// readsFor returns the collection handle the reads of ctx go through
+func (r *DriverRepository) readsFor(ctx context.Context) *mongo.Collection {
+	if consistency := domain.ConsistencyFromContext(ctx); consistency != nil && consistency.Write {
+		return r.writeReads
+	}
+	return r.reads
+}
+
+// withSession runs the commands of a request in a causally consistent
+// session: they wait until the member serving them has caught up with the
+// token the caller sent, and their operation times become the token of the
+// response. Outside requests ctx is returned as is. The returned function
+// ends the session.
+func (r *DriverRepository) withSession(ctx context.Context) (context.Context, func()) {
+	consistency := domain.ConsistencyFromContext(ctx)
+	if consistency == nil {
+		return ctx, func() {}
+	}
+	session, err := r.collection.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		// The commands still run, only without read-your-writes
+		r.logger.Warn("failed to start causally consistent session", zap.Error(err))
+		return ctx, func() {}
+	}
+	if after := consistency.ReadAfter; after != nil {
+		_ = session.AdvanceOperationTime(&primitive.Timestamp{T: after.T, I: after.I})
+	}
+	return mongo.NewSessionContext(ctx, session), func() {
+		if operationTime := session.OperationTime(); operationTime != nil {
+			consistency.Observe(domain.ConsistencyToken{T: operationTime.T, I: operationTime.I})
+		}
+		session.EndSession(context.Background())
+	}
+}
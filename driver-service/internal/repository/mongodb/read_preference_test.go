@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
+	"go.uber.org/zap"
+)
+
+func TestNewReadPreference(t *testing.T) {
+	t.Run("nearest with tag sets", func(t *testing.T) {
+		pref, err := NewReadPreference("nearest", []string{"region:eu-west, zone:a", "region:eu-west", ""}, 120*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, readpref.NearestMode, pref.Mode())
+		assert.Equal(t, []tag.Set{
+			{{Name: "region", Value: "eu-west"}, {Name: "zone", Value: "a"}},
+			{{Name: "region", Value: "eu-west"}},
+			{},
+		}, pref.TagSets())
+		staleness, ok := pref.MaxStaleness()
+		assert.True(t, ok)
+		assert.Equal(t, 120*time.Second, staleness)
+	})
+
+	t.Run("primary ignores tags", func(t *testing.T) {
+		pref, err := NewReadPreference("primary", []string{"region:eu-west"}, 120*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, readpref.PrimaryMode, pref.Mode())
+		assert.Empty(t, pref.TagSets())
+	})
+
+	t.Run("mode is case insensitive", func(t *testing.T) {
+		pref, err := NewReadPreference("SecondaryPreferred", nil, 0)
+		require.NoError(t, err)
+		assert.Equal(t, readpref.SecondaryPreferredMode, pref.Mode())
+	})
+
+	for name, tt := range map[string]struct {
+		mode         string
+		tagSets      []string
+		maxStaleness time.Duration
+	}{
+		"unknown mode":        {mode: "closest"},
+		"tag without value":   {mode: "nearest", tagSets: []string{"region"}},
+		"tag without name":    {mode: "nearest", tagSets: []string{":eu-west"}},
+		"staleness under 90s": {mode: "secondary", maxStaleness: 30 * time.Second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewReadPreference(tt.mode, tt.tagSets, tt.maxStaleness)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDriverRepository_ReadsFor(t *testing.T) {
+	client, err := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
+	require.NoError(t, err)
+	db := client.Database("test")
+
+	t.Run("primary by default", func(t *testing.T) {
+		repo := NewDriverRepository(db, zap.NewNop())
+		assert.Same(t, repo.collection, repo.readsFor(context.Background()))
+	})
+
+	repo := NewDriverRepository(db, zap.NewNop(), WithReadPreferences(ReadPreferences{
+		Reads:  readpref.Nearest(),
+		Writes: readpref.Primary(),
+	}))
+	assert.NotSame(t, repo.reads, repo.writeReads)
+	assert.Equal(t, "drivers", repo.reads.Name())
+	assert.Equal(t, "drivers", repo.writeReads.Name())
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *mongo.Collection
+	}{
+		{"outside requests", context.Background(), repo.reads},
+		{"read request", domain.ContextWithConsistency(context.Background(), &domain.Consistency{}), repo.reads},
+		{"write request", domain.ContextWithConsistency(context.Background(), &domain.Consistency{Write: true}), repo.writeReads},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.want, repo.readsFor(tt.ctx))
+		})
+	}
+}
+
+func TestConsistencyToken(t *testing.T) {
+	token, err := domain.ParseConsistencyToken("1765000000.7")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ConsistencyToken{T: 1765000000, I: 7}, token)
+	assert.Equal(t, "1765000000.7", token.String())
+
+	for _, invalid := range []string{"", "1765000000", "0.1", "a.b", "1765000000.-1", "99999999999.1"} {
+		_, err := domain.ParseConsistencyToken(invalid)
+		assert.ErrorIs(t, err, domain.ErrInvalidConsistencyToken, invalid)
+	}
+
+	consistency := &domain.Consistency{ReadAfter: &domain.ConsistencyToken{T: 1765000000, I: 7}}
+	got, ok := consistency.Token()
+	assert.True(t, ok)
+	assert.Equal(t, *consistency.ReadAfter, got, "a request that saw nothing newer hands the caller's token back")
+
+	consistency.Observe(domain.ConsistencyToken{T: 1765000001, I: 2})
+	consistency.Observe(domain.ConsistencyToken{T: 1765000001, I: 1})
+	got, _ = consistency.Token()
+	assert.Equal(t, domain.ConsistencyToken{T: 1765000001, I: 2}, got)
+
+	_, ok = (&domain.Consistency{}).Token()
+	assert.False(t, ok)
+}
@@ -0,0 +1,280 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// anonymizedFields are the fields cleared from a driver tombstone on erasure.
+// Taxi type, fleet, tags, rating and timestamps are kept for statistics;
+// attributes are free-form and may describe the person.
+var anonymizedFields = bson.M{
+	"firstName":         "",
+	"lastName":          "",
+	"phone":             "",
+	"phoneHash":         "",
+	"searchKeys":        "",
+	"email":             "",
+	"plate":             "",
+	"location":          "",
+	"position":          "",
+	"locationUpdatedAt": "",
+	"lastSeenAt":        "",
+	"documents":         "",
+	"license":           "",
+	"photo":             "",
+	"suspensionReason":  "",
+	"attributes":        "",
+}
+
+// RetentionRepository implements domain.RetentionRepository using MongoDB
+type RetentionRepository struct {
+	drivers       *mongo.Collection
+	locations     *mongo.Collection
+	deviceTokens  *mongo.Collection
+	verifications *mongo.Collection
+	kycChecks     *mongo.Collection
+	erasures      *mongo.Collection
+	logger        *zap.Logger
+}
+
+// erasureDocument is the stored representation of an erasure audit record
+type erasureDocument struct {
+	ID          primitive.ObjectID    `bson:"_id"`
+	DriverID    string                `bson:"driverId"`
+	Trigger     domain.ErasureTrigger `bson:"trigger"`
+	RequestedBy string                `bson:"requestedBy,omitempty"`
+	Reason      string                `bson:"reason,omitempty"`
+	Records     map[string]int64      `bson:"records"`
+	Files       int                   `bson:"files"`
+	ErasedAt    time.Time             `bson:"erasedAt"`
+}
+
+func (d *erasureDocument) toDomain() *domain.Erasure {
+	return &domain.Erasure{
+		ID:          d.ID.Hex(),
+		DriverID:    d.DriverID,
+		Trigger:     d.Trigger,
+		RequestedBy: d.RequestedBy,
+		Reason:      d.Reason,
+		Records:     d.Records,
+		Files:       d.Files,
+		ErasedAt:    d.ErasedAt,
+	}
+}
+
+// NewRetentionRepository creates a new MongoDB retention repository
+func NewRetentionRepository(db *mongo.Database, logger *zap.Logger) *RetentionRepository {
+	return &RetentionRepository{
+		drivers:       db.Collection("drivers"),
+		locations:     db.Collection("driver_locations"),
+		deviceTokens:  db.Collection("device_tokens"),
+		verifications: db.Collection("phone_verifications"),
+		kycChecks:     db.Collection("kyc_checks"),
+		erasures:      db.Collection("data_erasures"),
+		logger:        logger,
+	}
+}
+
+// Indexes lists the erasure audit indexes
+func (r *RetentionRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.erasures, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "erasedAt", Value: -1}},
+			Options: options.Index().SetName("driverId_erasedAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "erasedAt", Value: -1}},
+			Options: options.Index().SetName("erasedAt"),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the erasure audit indexes
+func (r *RetentionRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create retention indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ListExpired returns the oldest tombstones first
+func (r *RetentionRepository) ListExpired(ctx interface{}, deletedBefore time.Time, limit int) ([]string, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	cursor, err := r.drivers.Find(c,
+		bson.M{"deletedAt": bson.M{"$lt": deletedBefore}, "anonymizedAt": bson.M{"$exists": false}},
+		options.Find().
+			SetProjection(bson.M{"_id": 1}).
+			SetSort(bson.D{{Key: "deletedAt", Value: 1}}).
+			SetLimit(int64(limit)))
+	if err != nil {
+		r.logger.Error("failed to list expired drivers", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode expired drivers", zap.Error(err))
+		return nil, err
+	}
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID.Hex()
+	}
+	return ids, nil
+}
+
+// Anonymize clears the tombstone first, so a failure part-way leaves the
+// driver for the next run to finish. Location history, device tokens and
+// pending phone verifications are deleted; identity checks keep their outcome
+// but lose the provider reference and the rejection reason.
+func (r *RetentionRepository) Anonymize(ctx interface{}, driverID string, at time.Time) (map[string]int64, []string, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(driverID)
+	if err != nil {
+		return nil, nil, errors.New("driver not found")
+	}
+
+	var before struct {
+		Photo *struct {
+			Keys []string `bson:"keys"`
+		} `bson:"photo"`
+	}
+	err = r.drivers.FindOneAndUpdate(c,
+		bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": true}},
+		bson.M{"$set": bson.M{"anonymizedAt": at}, "$unset": anonymizedFields},
+		options.FindOneAndUpdate().SetProjection(bson.M{"photo.keys": 1}),
+	).Decode(&before)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, errors.New("driver not found")
+		}
+		r.logger.Error("failed to anonymize driver", zap.Error(err), zap.String("driverId", driverID))
+		return nil, nil, err
+	}
+	records := map[string]int64{r.drivers.Name(): 1}
+	var files []string
+	if before.Photo != nil {
+		files = before.Photo.Keys
+	}
+
+	for _, collection := range []*mongo.Collection{r.locations, r.deviceTokens} {
+		result, err := collection.DeleteMany(c, bson.M{"driverId": driverID})
+		if err != nil {
+			r.logger.Error("failed to erase driver records", zap.Error(err), zap.String("collection", collection.Name()), zap.String("driverId", driverID))
+			return nil, nil, err
+		}
+		records[collection.Name()] = result.DeletedCount
+	}
+
+	verification, err := r.verifications.DeleteOne(c, bson.M{"_id": driverID})
+	if err != nil {
+		r.logger.Error("failed to erase phone verification", zap.Error(err), zap.String("driverId", driverID))
+		return nil, nil, err
+	}
+	records[r.verifications.Name()] = verification.DeletedCount
+
+	checks, err := r.kycChecks.UpdateMany(c,
+		bson.M{"driverId": driverID, "reference": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"reference": "", "reason": ""}})
+	if err != nil {
+		r.logger.Error("failed to erase identity checks", zap.Error(err), zap.String("driverId", driverID))
+		return nil, nil, err
+	}
+	records[r.kycChecks.Name()] = checks.ModifiedCount
+
+	return records, files, nil
+}
+
+// PurgeLocations deletes location points older than the given time
+func (r *RetentionRepository) PurgeLocations(ctx interface{}, before time.Time) (int64, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	result, err := r.locations.DeleteMany(c, bson.M{"recordedAt": bson.M{"$lt": before}})
+	if err != nil {
+		r.logger.Error("failed to purge location history", zap.Error(err), zap.Time("before", before))
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// RecordErasure stores an erasure audit record
+func (r *RetentionRepository) RecordErasure(ctx interface{}, erasure *domain.Erasure) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &erasureDocument{
+		ID:          primitive.NewObjectID(),
+		DriverID:    erasure.DriverID,
+		Trigger:     erasure.Trigger,
+		RequestedBy: erasure.RequestedBy,
+		Reason:      erasure.Reason,
+		Records:     erasure.Records,
+		Files:       erasure.Files,
+		ErasedAt:    erasure.ErasedAt,
+	}
+	if _, err := r.erasures.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to record erasure", zap.Error(err), zap.String("driverId", erasure.DriverID))
+		return err
+	}
+
+	erasure.ID = doc.ID.Hex()
+	return nil
+}
+
+// ListErasures returns the newest erasures first
+func (r *RetentionRepository) ListErasures(ctx interface{}, driverID string, limit int) ([]*domain.Erasure, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{}
+	if driverID != "" {
+		filter["driverId"] = driverID
+	}
+	cursor, err := r.erasures.Find(c, filter, options.Find().
+		SetSort(bson.D{{Key: "erasedAt", Value: -1}}).
+		SetLimit(int64(limit)))
+	if err != nil {
+		r.logger.Error("failed to list erasures", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []erasureDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode erasures", zap.Error(err))
+		return nil, err
+	}
+	erasures := make([]*domain.Erasure, len(docs))
+	for i := range docs {
+		erasures[i] = docs[i].toDomain()
+	}
+	return erasures, nil
+}
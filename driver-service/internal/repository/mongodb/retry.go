@@ -0,0 +1,259 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"go.uber.org/zap"
+)
+
+// Server error codes seen while a replica set steps down or elects a new primary
+var transientCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	189,   // PrimarySteppedDown
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// Codes of errors the server returns before executing a write, so the write was not applied
+var notAppliedCodes = []int{10107, 13435, 13436}
+
+// RetryPolicy bounds the retries of a failed operation
+type RetryPolicy struct {
+	// MaxAttempts includes the first try; values below 1 disable retries
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// Retrier retries repository operations that fail while MongoDB fails over and
+// keeps the failover metrics. A nil Retrier runs operations once.
+type Retrier struct {
+	policy RetryPolicy
+	logger *zap.Logger
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	transient      atomic.Int64
+	retries        atomic.Int64
+	recovered      atomic.Int64
+	unavailable    atomic.Int64
+	primaryChanges atomic.Int64
+
+	mu                  sync.Mutex
+	primary             string
+	seenPrimary         bool
+	lastPrimaryChangeAt time.Time
+	lastTransientAt     time.Time
+}
+
+// NewRetrier creates a retrier with the given policy
+func NewRetrier(policy RetryPolicy, logger *zap.Logger) *Retrier {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Retrier{
+		policy: policy,
+		logger: logger,
+		sleep:  sleepContext,
+	}
+}
+
+// Do runs fn until it succeeds, fails permanently or the attempts are used up.
+// Idempotent operations are retried on any transient error; other writes only
+// when the server rejected them without applying them. When the attempts run
+// out the error wraps domain.ErrStoreUnavailable.
+func (r *Retrier) Do(ctx context.Context, op string, idempotent bool, fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil {
+			if attempt > 1 {
+				r.recovered.Add(1)
+				r.logger.Info("MongoDB operation recovered after retry", zap.String("op", op), zap.Int("attempts", attempt))
+			}
+			return nil
+		}
+		if !IsTransientError(err) {
+			return err
+		}
+		r.recordTransient()
+
+		if attempt >= r.policy.MaxAttempts || (!idempotent && !notApplied(err)) || ctx.Err() != nil {
+			break
+		}
+		r.retries.Add(1)
+		r.logger.Warn("retrying MongoDB operation after transient error",
+			zap.String("op", op), zap.Int("attempt", attempt), zap.Error(err))
+		if r.sleep(ctx, r.backoff(attempt)) != nil {
+			break
+		}
+	}
+
+	r.unavailable.Add(1)
+	return fmt.Errorf("%w: %s: %v", domain.ErrStoreUnavailable, op, err)
+}
+
+// backoff doubles the delay per attempt up to MaxDelay, with jitter so that
+// instances do not retry in lockstep
+func (r *Retrier) backoff(attempt int) time.Duration {
+	delay := r.policy.BaseDelay
+	for i := 1; i < attempt && delay < r.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if r.policy.MaxDelay > 0 && delay > r.policy.MaxDelay {
+		delay = r.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func (r *Retrier) recordTransient() {
+	r.transient.Add(1)
+	r.mu.Lock()
+	r.lastTransientAt = time.Now()
+	r.mu.Unlock()
+}
+
+// ServerMonitor returns a monitor that tracks the replica-set primary. Pass it
+// to options.Client().SetServerMonitor to count primary changes.
+func (r *Retrier) ServerMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		TopologyDescriptionChanged: func(e *event.TopologyDescriptionChangedEvent) {
+			r.observePrimary(primaryAddr(e.NewDescription))
+		},
+	}
+}
+
+// observePrimary records the current primary. Losing the primary is not a change
+// by itself; the change is counted once a member takes over, except for the
+// first primary found at startup.
+func (r *Retrier) observePrimary(addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if addr == r.primary {
+		return
+	}
+	if addr == "" {
+		r.logger.Warn("MongoDB primary lost", zap.String("previous", r.primary))
+		r.primary = ""
+		return
+	}
+	if r.seenPrimary {
+		r.primaryChanges.Add(1)
+		r.lastPrimaryChangeAt = time.Now()
+		r.logger.Warn("MongoDB primary changed", zap.String("primary", addr))
+	}
+	r.seenPrimary = true
+	r.primary = addr
+}
+
+// Stats returns a snapshot of the failover metrics
+func (r *Retrier) Stats() domain.FailoverStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := domain.FailoverStats{
+		Primary:         r.primary,
+		PrimaryChanges:  r.primaryChanges.Load(),
+		TransientErrors: r.transient.Load(),
+		Retries:         r.retries.Load(),
+		Recovered:       r.recovered.Load(),
+		Unavailable:     r.unavailable.Load(),
+	}
+	if r.primaryChanges.Load() > 0 {
+		at := r.lastPrimaryChangeAt
+		stats.LastPrimaryChangeAt = &at
+	}
+	if !r.lastTransientAt.IsZero() {
+		at := r.lastTransientAt
+		stats.LastTransientAt = &at
+	}
+	return stats
+}
+
+// IsTransientError reports whether err is likely to go away once the replica
+// set has a reachable primary again
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if mongo.IsNetworkError(err) || errors.As(err, new(topology.ServerSelectionError)) {
+		return true
+	}
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+	if se.HasErrorLabel("RetryableWriteError") || se.HasErrorLabel("TransientTransactionError") {
+		return true
+	}
+	for _, code := range transientCodes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// notApplied reports whether a failed write certainly did not reach the data:
+// no server was selected, or a node that is not primary refused it
+func notApplied(err error) bool {
+	if errors.As(err, new(topology.ServerSelectionError)) {
+		return true
+	}
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+	for _, code := range notAppliedCodes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+func primaryAddr(topo description.Topology) string {
+	for _, server := range topo.Servers {
+		if server.Kind == description.RSPrimary || server.Kind == description.Standalone {
+			return server.Addr.String()
+		}
+	}
+	return ""
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+	"go.uber.org/zap"
+)
+
+func newTestRetrier(attempts int) *Retrier {
+	r := NewRetrier(RetryPolicy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}, zap.NewNop())
+	r.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return r
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"not primary", mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, true},
+		{"stepped down", mongo.CommandError{Code: 11602}, true},
+		{"network", mongo.CommandError{Labels: []string{"NetworkError"}}, true},
+		{"retryable write label", mongo.CommandError{Code: 1, Labels: []string{"RetryableWriteError"}}, true},
+		{"no server selected", topology.ServerSelectionError{Wrapped: topology.ErrServerSelectionTimeout}, true},
+		{"duplicate key", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, false},
+		{"no documents", mongo.ErrNoDocuments, false},
+		{"cancelled", context.Canceled, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransientError(tt.err))
+		})
+	}
+}
+
+func TestRetrier_Do(t *testing.T) {
+	ctx := context.Background()
+	notPrimary := mongo.CommandError{Code: 10107}
+	network := mongo.CommandError{Labels: []string{"NetworkError"}}
+
+	t.Run("recovers after election", func(t *testing.T) {
+		r := newTestRetrier(4)
+		calls := 0
+		err := r.Do(ctx, "update driver", true, func() error {
+			calls++
+			if calls < 3 {
+				return notPrimary
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		stats := r.Stats()
+		assert.Equal(t, int64(2), stats.TransientErrors)
+		assert.Equal(t, int64(2), stats.Retries)
+		assert.Equal(t, int64(1), stats.Recovered)
+	})
+
+	t.Run("gives up as unavailable", func(t *testing.T) {
+		r := newTestRetrier(3)
+		calls := 0
+		err := r.Do(ctx, "get driver", true, func() error {
+			calls++
+			return network
+		})
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, int64(1), r.Stats().Unavailable)
+	})
+
+	t.Run("permanent errors are returned as is", func(t *testing.T) {
+		r := newTestRetrier(3)
+		err := r.Do(ctx, "get driver", true, func() error { return mongo.ErrNoDocuments })
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+
+	t.Run("non-idempotent writes are not retried when they may have applied", func(t *testing.T) {
+		r := newTestRetrier(3)
+		calls := 0
+		err := r.Do(ctx, "delete driver", false, func() error {
+			calls++
+			return network
+		})
+		assert.ErrorIs(t, err, domain.ErrStoreUnavailable)
+		assert.Equal(t, 1, calls)
+
+		calls = 0
+		err = r.Do(ctx, "delete driver", false, func() error {
+			calls++
+			if calls == 1 {
+				return notPrimary
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("nil retrier runs once", func(t *testing.T) {
+		var r *Retrier
+		calls := 0
+		err := r.Do(ctx, "get driver", true, func() error {
+			calls++
+			return network
+		})
+		assert.Equal(t, network, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetrier_ObservePrimary(t *testing.T) {
+	r := newTestRetrier(1)
+
+	r.observePrimary("mongo-1:27017")
+	assert.Equal(t, int64(0), r.Stats().PrimaryChanges, "the first primary is not a failover")
+
+	r.observePrimary("")
+	r.observePrimary("mongo-2:27017")
+	stats := r.Stats()
+	assert.Equal(t, "mongo-2:27017", stats.Primary)
+	assert.Equal(t, int64(1), stats.PrimaryChanges)
+	assert.NotNil(t, stats.LastPrimaryChangeAt)
+}
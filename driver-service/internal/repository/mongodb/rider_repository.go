@@ -0,0 +1,188 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// RiderRepository implements domain.RiderRepository using MongoDB
+type RiderRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// riderDocument is the stored representation of a rider with a native ObjectID
+type riderDocument struct {
+	ID                primitive.ObjectID        `bson:"_id"`
+	FirstName         string                    `bson:"firstName"`
+	LastName          string                    `bson:"lastName"`
+	Phone             string                    `bson:"phone"`
+	Email             string                    `bson:"email,omitempty"`
+	FavoriteLocations []domain.FavoriteLocation `bson:"favoriteLocations"`
+	CreatedAt         time.Time                 `bson:"createdAt"`
+	UpdatedAt         time.Time                 `bson:"updatedAt"`
+}
+
+// toDomain converts the stored document into a domain rider
+func (d *riderDocument) toDomain() *domain.Rider {
+	favorites := d.FavoriteLocations
+	if favorites == nil {
+		favorites = []domain.FavoriteLocation{}
+	}
+	return &domain.Rider{
+		ID:                d.ID.Hex(),
+		FirstName:         d.FirstName,
+		LastName:          d.LastName,
+		Phone:             d.Phone,
+		Email:             d.Email,
+		FavoriteLocations: favorites,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+	}
+}
+
+// NewRiderRepository creates a new MongoDB rider repository
+func NewRiderRepository(db *mongo.Database, logger *zap.Logger) *RiderRepository {
+	return &RiderRepository{
+		collection: db.Collection("riders"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the unique index on rider phone numbers
+func (r *RiderRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "phone", Value: 1}},
+			Options: options.Index().SetName("phone_unique").SetUnique(true),
+		},
+	}}}
+}
+
+// EnsureIndexes makes rider phone numbers unique
+func (r *RiderRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create rider indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new rider into MongoDB
+func (r *RiderRepository) Create(ctx interface{}, rider *domain.Rider) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	rider.CreatedAt = time.Now()
+	rider.UpdatedAt = rider.CreatedAt
+	if rider.FavoriteLocations == nil {
+		rider.FavoriteLocations = []domain.FavoriteLocation{}
+	}
+
+	doc := &riderDocument{
+		ID:                primitive.NewObjectID(),
+		FirstName:         rider.FirstName,
+		LastName:          rider.LastName,
+		Phone:             rider.Phone,
+		Email:             rider.Email,
+		FavoriteLocations: rider.FavoriteLocations,
+		CreatedAt:         rider.CreatedAt,
+		UpdatedAt:         rider.UpdatedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("phone is already registered to another rider")
+		}
+		r.logger.Error("failed to create rider", zap.Error(err))
+		return err
+	}
+
+	rider.ID = doc.ID.Hex()
+	return nil
+}
+
+// Update stores the rider's profile and favorite locations
+func (r *RiderRepository) Update(ctx interface{}, rider *domain.Rider) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(rider.ID)
+	if err != nil {
+		return errors.New("rider not found")
+	}
+
+	rider.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"firstName":         rider.FirstName,
+		"lastName":          rider.LastName,
+		"phone":             rider.Phone,
+		"email":             rider.Email,
+		"favoriteLocations": rider.FavoriteLocations,
+		"updatedAt":         rider.UpdatedAt,
+	}}
+	result, err := r.collection.UpdateOne(c, bson.M{"_id": objectID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New("phone is already registered to another rider")
+		}
+		r.logger.Error("failed to update rider", zap.Error(err), zap.String("id", rider.ID))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("rider not found")
+	}
+	return nil
+}
+
+// GetByID retrieves a rider by ID
+func (r *RiderRepository) GetByID(ctx interface{}, id string) (*domain.Rider, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("rider not found")
+	}
+
+	var doc riderDocument
+	if err := r.collection.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("rider not found")
+		}
+		r.logger.Error("failed to get rider by ID", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// GetByPhone retrieves the rider with the given phone number
+func (r *RiderRepository) GetByPhone(ctx interface{}, phone string) (*domain.Rider, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var doc riderDocument
+	if err := r.collection.FindOne(c, bson.M{"phone": phone}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("rider not found")
+		}
+		r.logger.Error("failed to get rider by phone", zap.Error(err))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
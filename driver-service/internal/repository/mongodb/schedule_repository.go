@@ -0,0 +1,307 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// ScheduleRepository implements domain.ScheduleRepository using MongoDB
+type ScheduleRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// scheduledShiftDocument is the stored representation of a planned shift
+type scheduledShiftDocument struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	DriverID   string             `bson:"driverId"`
+	FleetID    string             `bson:"fleetId,omitempty"`
+	StartsAt   time.Time          `bson:"startsAt"`
+	EndsAt     time.Time          `bson:"endsAt"`
+	Note       string             `bson:"note,omitempty"`
+	StartedAt  *time.Time         `bson:"startedAt,omitempty"`
+	StartError string             `bson:"startError,omitempty"`
+	EndedAt    *time.Time         `bson:"endedAt,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt"`
+}
+
+func (d *scheduledShiftDocument) toDomain() *domain.ScheduledShift {
+	return &domain.ScheduledShift{
+		ID:         d.ID.Hex(),
+		DriverID:   d.DriverID,
+		FleetID:    d.FleetID,
+		StartsAt:   d.StartsAt,
+		EndsAt:     d.EndsAt,
+		Note:       d.Note,
+		StartedAt:  d.StartedAt,
+		StartError: d.StartError,
+		EndedAt:    d.EndedAt,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+// NewScheduleRepository creates a new MongoDB planned shift repository
+func NewScheduleRepository(db *mongo.Database, logger *zap.Logger) *ScheduleRepository {
+	return &ScheduleRepository{
+		collection: db.Collection("scheduled_shifts"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the calendar and scheduler indexes
+func (r *ScheduleRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			// Driver calendars and the overlap check
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "startsAt", Value: 1}},
+			Options: options.Index().SetName("driverId_startsAt"),
+		},
+		{
+			// Fleet calendars
+			Keys:    bson.D{{Key: "fleetId", Value: 1}, {Key: "startsAt", Value: 1}},
+			Options: options.Index().SetName("fleetId_startsAt"),
+		},
+		{
+			// The scheduler looks for shifts due to start
+			Keys: bson.D{{Key: "startsAt", Value: 1}},
+			Options: options.Index().SetName("startsAt_unstarted").
+				SetPartialFilterExpression(bson.M{"startedAt": bson.M{"$exists": false}}),
+		},
+		{
+			// and for started shifts due to end
+			Keys: bson.D{{Key: "endsAt", Value: 1}},
+			Options: options.Index().SetName("endsAt_unended").
+				SetPartialFilterExpression(bson.M{"startedAt": bson.M{"$exists": true}, "endedAt": bson.M{"$exists": false}}),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the planned shift indexes
+func (r *ScheduleRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create scheduled shift indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new shift
+func (r *ScheduleRepository) Create(ctx interface{}, shift *domain.ScheduledShift) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	doc := &scheduledShiftDocument{
+		ID:        primitive.NewObjectID(),
+		DriverID:  shift.DriverID,
+		FleetID:   shift.FleetID,
+		StartsAt:  shift.StartsAt,
+		EndsAt:    shift.EndsAt,
+		Note:      shift.Note,
+		CreatedAt: shift.CreatedAt,
+		UpdatedAt: shift.UpdatedAt,
+	}
+	if _, err := r.collection.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create scheduled shift", zap.Error(err), zap.String("driverId", shift.DriverID))
+		return err
+	}
+
+	shift.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetByID retrieves a shift by ID
+func (r *ScheduleRepository) GetByID(ctx interface{}, id string) (*domain.ScheduledShift, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("shift not found")
+	}
+
+	var doc scheduledShiftDocument
+	if err := r.collection.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("shift not found")
+		}
+		r.logger.Error("failed to get scheduled shift", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// Update stores the times and note of a shift. A shift the scheduler ended
+// meanwhile is not changed.
+func (r *ScheduleRepository) Update(ctx interface{}, shift *domain.ScheduledShift) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(shift.ID)
+	if err != nil {
+		return errors.New("shift not found")
+	}
+
+	set := bson.M{
+		"startsAt":  shift.StartsAt,
+		"endsAt":    shift.EndsAt,
+		"updatedAt": shift.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if shift.Note != "" {
+		set["note"] = shift.Note
+	} else {
+		update["$unset"] = bson.M{"note": ""}
+	}
+	filter := bson.M{"_id": objectID, "endedAt": bson.M{"$exists": false}}
+	result, err := r.collection.UpdateOne(c, filter, update)
+	if err != nil {
+		r.logger.Error("failed to update scheduled shift", zap.Error(err), zap.String("id", shift.ID))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("shift not found")
+	}
+	return nil
+}
+
+// Delete removes a shift
+func (r *ScheduleRepository) Delete(ctx interface{}, id string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("shift not found")
+	}
+
+	result, err := r.collection.DeleteOne(c, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("failed to delete scheduled shift", zap.Error(err), zap.String("id", id))
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("shift not found")
+	}
+	return nil
+}
+
+// List returns the shifts overlapping the filter's range by start time
+func (r *ScheduleRepository) List(ctx interface{}, filter domain.ScheduleFilter) ([]*domain.ScheduledShift, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	query := bson.M{}
+	if filter.DriverID != "" {
+		query["driverId"] = filter.DriverID
+	}
+	if filter.FleetID != "" {
+		query["fleetId"] = filter.FleetID
+	}
+	if !filter.To.IsZero() {
+		query["startsAt"] = bson.M{"$lt": filter.To}
+	}
+	if !filter.From.IsZero() {
+		query["endsAt"] = bson.M{"$gt": filter.From}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "startsAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(c, query, opts)
+	if err != nil {
+		r.logger.Error("failed to list scheduled shifts", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []scheduledShiftDocument
+	if err := cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode scheduled shifts", zap.Error(err))
+		return nil, err
+	}
+	shifts := make([]*domain.ScheduledShift, len(docs))
+	for i := range docs {
+		shifts[i] = docs[i].toDomain()
+	}
+	return shifts, nil
+}
+
+// ClaimStart marks the earliest unstarted shift running at now as started
+func (r *ScheduleRepository) ClaimStart(ctx interface{}, now time.Time) (*domain.ScheduledShift, error) {
+	filter := bson.M{
+		"startsAt":  bson.M{"$lte": now},
+		"endsAt":    bson.M{"$gt": now},
+		"startedAt": bson.M{"$exists": false},
+	}
+	return r.claim(ctx, filter, "startedAt", "startsAt", now)
+}
+
+// ClaimEnd marks the earliest ended shift whose driver was put on shift as ended
+func (r *ScheduleRepository) ClaimEnd(ctx interface{}, now time.Time) (*domain.ScheduledShift, error) {
+	filter := bson.M{
+		"endsAt":     bson.M{"$lte": now},
+		"startedAt":  bson.M{"$exists": true},
+		"startError": bson.M{"$exists": false},
+		"endedAt":    bson.M{"$exists": false},
+	}
+	return r.claim(ctx, filter, "endedAt", "endsAt", now)
+}
+
+// claim sets field to now on the first shift matching filter by sortField and returns it
+func (r *ScheduleRepository) claim(ctx interface{}, filter bson.M, field, sortField string, now time.Time) (*domain.ScheduledShift, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: sortField, Value: 1}}).
+		SetReturnDocument(options.After)
+	var doc scheduledShiftDocument
+	err := r.collection.FindOneAndUpdate(c, filter, bson.M{"$set": bson.M{field: now}}, opts).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		r.logger.Error("failed to claim scheduled shift", zap.Error(err), zap.String("field", field))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// SetStartError records why the driver could not be put on shift
+func (r *ScheduleRepository) SetStartError(ctx interface{}, id, reason string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("shift not found")
+	}
+
+	if _, err := r.collection.UpdateOne(c, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"startError": reason}}); err != nil {
+		r.logger.Error("failed to record scheduled shift start error", zap.Error(err), zap.String("id", id))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// TripRepository implements domain.TripRepository using MongoDB
+type TripRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// tripDocument is the stored representation of a trip with a native ObjectID
+type tripDocument struct {
+	ID                primitive.ObjectID   `bson:"_id"`
+	RiderID           string               `bson:"riderId,omitempty"`
+	Pickup            domain.Location      `bson:"pickup"`
+	Dropoff           *domain.Location     `bson:"dropoff,omitempty"`
+	TaxiType          *domain.TaxiType     `bson:"taxiType,omitempty"`
+	Status            domain.TripStatus    `bson:"status"`
+	Strategy          string               `bson:"strategy"`
+	DriverID          string               `bson:"driverId,omitempty"`
+	OfferedDriverID   string               `bson:"offeredDriverId,omitempty"`
+	OfferExpiresAt    *time.Time           `bson:"offerExpiresAt,omitempty"`
+	DeclinedDriverIDs []string             `bson:"declinedDriverIds"`
+	OfferCount        int                  `bson:"offerCount"`
+	DistanceKm        float64              `bson:"distanceKm,omitempty"`
+	Estimate          *domain.FareEstimate `bson:"estimate,omitempty"`
+	Fare              float64              `bson:"fare,omitempty"`
+	Rating            *float64             `bson:"rating,omitempty"`
+	Version           int                  `bson:"version"`
+	CreatedAt         time.Time            `bson:"createdAt"`
+	UpdatedAt         time.Time            `bson:"updatedAt"`
+	CompletedAt       *time.Time           `bson:"completedAt,omitempty"`
+}
+
+// toDomain converts the stored document into a domain trip
+func (d *tripDocument) toDomain() *domain.Trip {
+	declined := d.DeclinedDriverIDs
+	if declined == nil {
+		declined = []string{}
+	}
+	return &domain.Trip{
+		ID:                d.ID.Hex(),
+		RiderID:           d.RiderID,
+		Pickup:            d.Pickup,
+		Dropoff:           d.Dropoff,
+		TaxiType:          d.TaxiType,
+		Status:            d.Status,
+		Strategy:          d.Strategy,
+		DriverID:          d.DriverID,
+		OfferedDriverID:   d.OfferedDriverID,
+		OfferExpiresAt:    d.OfferExpiresAt,
+		DeclinedDriverIDs: declined,
+		OfferCount:        d.OfferCount,
+		DistanceKm:        d.DistanceKm,
+		Estimate:          d.Estimate,
+		Fare:              d.Fare,
+		Rating:            d.Rating,
+		Version:           d.Version,
+		CreatedAt:         d.CreatedAt,
+		UpdatedAt:         d.UpdatedAt,
+		CompletedAt:       d.CompletedAt,
+	}
+}
+
+// NewTripRepository creates a new MongoDB trip repository
+func NewTripRepository(db *mongo.Database, logger *zap.Logger) *TripRepository {
+	return &TripRepository{
+		collection: db.Collection("trips"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists the indexes used to find offers that timed out and to
+// aggregate a driver's completed trips
+func (r *TripRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "offerExpiresAt", Value: 1}},
+			Options: options.Index().SetName("status_offerExpiresAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "driverId", Value: 1}, {Key: "status", Value: 1}, {Key: "completedAt", Value: 1}},
+			Options: options.Index().SetName("driverId_status_completedAt"),
+		},
+		{
+			// Utilization reports look up the trips in progress during a day
+			Keys:    bson.D{{Key: "acceptedAt", Value: 1}},
+			Options: options.Index().SetName("acceptedAt"),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the trip indexes
+func (r *TripRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create trip indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Create inserts a new trip into MongoDB
+func (r *TripRepository) Create(ctx interface{}, trip *domain.Trip) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	trip.CreatedAt = time.Now()
+	trip.UpdatedAt = trip.CreatedAt
+	trip.Version = 1
+
+	result, err := r.collection.InsertOne(c, trip)
+	if err != nil {
+		r.logger.Error("failed to create trip", zap.Error(err))
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		trip.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+// Update saves the mutable trip fields if the stored version still matches trip.Version
+func (r *TripRepository) Update(ctx interface{}, trip *domain.Trip) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(trip.ID)
+	if err != nil {
+		return errors.New("invalid trip ID")
+	}
+
+	updatedAt := time.Now()
+	filter := bson.M{"_id": objectID, "version": trip.Version}
+	update := bson.M{
+		"$set": bson.M{
+			"status":            trip.Status,
+			"driverId":          trip.DriverID,
+			"offeredDriverId":   trip.OfferedDriverID,
+			"offerExpiresAt":    trip.OfferExpiresAt,
+			"declinedDriverIds": trip.DeclinedDriverIDs,
+			"offerCount":        trip.OfferCount,
+			"distanceKm":        trip.DistanceKm,
+			"rating":            trip.Rating,
+			"acceptedAt":        trip.AcceptedAt,
+			"completedAt":       trip.CompletedAt,
+			"updatedAt":         updatedAt,
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.collection.UpdateOne(c, filter, update)
+	if err != nil {
+		r.logger.Error("failed to update trip", zap.Error(err), zap.String("id", trip.ID))
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		count, err := r.collection.CountDocuments(c, bson.M{"_id": objectID})
+		if err == nil && count == 0 {
+			return errors.New("trip not found")
+		}
+		return errors.New("trip was modified concurrently")
+	}
+
+	trip.Version++
+	trip.UpdatedAt = updatedAt
+	return nil
+}
+
+// GetByID retrieves a trip by ID
+func (r *TripRepository) GetByID(ctx interface{}, id string) (*domain.Trip, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid trip ID")
+	}
+
+	var doc tripDocument
+	err = r.collection.FindOne(c, bson.M{"_id": objectID}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("trip not found")
+		}
+		r.logger.Error("failed to get trip by ID", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+
+	return doc.toDomain(), nil
+}
+
+// FindExpiredOffers returns offered trips whose offer expired before now, oldest first
+func (r *TripRepository) FindExpiredOffers(ctx interface{}, now time.Time, limit int) ([]*domain.Trip, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{
+		"status":         domain.TripStatusOffered,
+		"offerExpiresAt": bson.M{"$lte": now},
+	}
+	findOptions := options.Find().
+		SetSort(bson.M{"offerExpiresAt": 1}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(c, filter, findOptions)
+	if err != nil {
+		r.logger.Error("failed to find expired offers", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []tripDocument
+	if err = cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode trips", zap.Error(err))
+		return nil, err
+	}
+
+	trips := make([]*domain.Trip, len(docs))
+	for i := range docs {
+		trips[i] = docs[i].toDomain()
+	}
+	return trips, nil
+}
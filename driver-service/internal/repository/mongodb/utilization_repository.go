@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// UtilizationRepository implements domain.UtilizationRepository using MongoDB.
+// Online time comes from driver shifts and busy time from accepted trips; both
+// are attributed to the taxi type the driver has now.
+type UtilizationRepository struct {
+	shifts  *mongo.Collection
+	trips   *mongo.Collection
+	rollups *mongo.Collection
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewUtilizationRepository creates a new MongoDB utilization repository
+func NewUtilizationRepository(db *mongo.Database, logger *zap.Logger) *UtilizationRepository {
+	return &UtilizationRepository{
+		shifts:  db.Collection("driver_shifts"),
+		trips:   db.Collection("trips"),
+		rollups: db.Collection("utilization_daily"),
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Indexes lists the daily rollup indexes; a day has one rollup per taxi type
+func (r *UtilizationRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.rollups, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "day", Value: 1}, {Key: "taxiType", Value: 1}},
+			Options: options.Index().SetName("day_taxiType_unique").SetUnique(true),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the daily rollup indexes
+func (r *UtilizationRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create utilization indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// AggregateUtilization sums the part of every shift and every accepted trip
+// that overlaps [start, end) per taxi type. Open shifts and trips in progress
+// count up to now.
+func (r *UtilizationRepository) AggregateUtilization(ctx interface{}, start, end time.Time) ([]domain.UtilizationDay, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	now := r.now()
+	online, err := r.sumByTaxiType(c, r.shifts, bson.M{
+		"startedAt": bson.M{"$lt": end},
+		"$or": bson.A{
+			bson.M{"open": true},
+			bson.M{"endedAt": bson.M{"$gt": start}},
+		},
+	}, "$startedAt", bson.M{"$ifNull": bson.A{"$endedAt", now}}, start, end)
+	if err != nil {
+		r.logger.Error("failed to aggregate shifts", zap.Time("start", start), zap.Error(err))
+		return nil, err
+	}
+
+	busy, err := r.sumByTaxiType(c, r.trips, bson.M{
+		"acceptedAt": bson.M{"$lt": end},
+		"$or": bson.A{
+			bson.M{"status": domain.TripStatusAccepted},
+			bson.M{"status": domain.TripStatusCompleted, "completedAt": bson.M{"$gt": start}},
+		},
+	}, "$acceptedAt", bson.M{"$ifNull": bson.A{"$completedAt", now}}, start, end)
+	if err != nil {
+		r.logger.Error("failed to aggregate trips", zap.Time("start", start), zap.Error(err))
+		return nil, err
+	}
+
+	rows := make([]domain.UtilizationDay, 0, len(online)+len(busy))
+	for taxiType, ms := range online {
+		rows = append(rows, domain.UtilizationDay{TaxiType: taxiType, OnlineHours: hours(ms), BusyHours: hours(busy[taxiType])})
+	}
+	for taxiType, ms := range busy {
+		if _, ok := online[taxiType]; !ok {
+			rows = append(rows, domain.UtilizationDay{TaxiType: taxiType, BusyHours: hours(ms)})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].TaxiType < rows[j].TaxiType })
+	return rows, nil
+}
+
+// sumByTaxiType sums the milliseconds each matching span [from, to) overlaps
+// [start, end), grouped by the taxi type of the span's driver. Spans of drivers
+// that no longer exist are left out.
+func (r *UtilizationRepository) sumByTaxiType(ctx context.Context, collection *mongo.Collection, match bson.M, from, to interface{}, start, end time.Time) (map[domain.TaxiType]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$project", Value: bson.M{
+			"driverId": 1,
+			"ms": bson.M{"$subtract": bson.A{
+				bson.M{"$min": bson.A{to, end}},
+				bson.M{"$max": bson.A{from, start}},
+			}},
+		}}},
+		{{Key: "$match", Value: bson.M{"ms": bson.M{"$gt": 0}}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": "drivers",
+			"let": bson.M{"driverId": bson.M{"$convert": bson.M{
+				"input": "$driverId", "to": "objectId", "onError": nil, "onNull": nil,
+			}}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$driverId"}}}},
+				bson.M{"$project": bson.M{"taxiType": 1}},
+			},
+			"as": "driver",
+		}}},
+		{{Key: "$unwind", Value: "$driver"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": "$driver.taxiType",
+			"ms":  bson.M{"$sum": "$ms"},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		TaxiType domain.TaxiType `bson:"_id"`
+		Ms       int64           `bson:"ms"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	sums := make(map[domain.TaxiType]int64, len(results))
+	for _, result := range results {
+		sums[result.TaxiType] = result.Ms
+	}
+	return sums, nil
+}
+
+// SaveRollup replaces the rollup of a day with rows
+func (r *UtilizationRepository) SaveRollup(ctx interface{}, day string, rows []domain.UtilizationDay) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	models := []mongo.WriteModel{mongo.NewDeleteManyModel().SetFilter(bson.M{"day": day})}
+	for _, row := range rows {
+		row.Day = day
+		models = append(models, mongo.NewInsertOneModel().SetDocument(row))
+	}
+	if _, err := r.rollups.BulkWrite(c, models, options.BulkWrite().SetOrdered(true)); err != nil {
+		r.logger.Error("failed to save utilization rollup", zap.String("day", day), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Rollups returns the rollups of the days from to to, inclusive
+func (r *UtilizationRepository) Rollups(ctx interface{}, from, to string) ([]domain.UtilizationDay, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	cursor, err := r.rollups.Find(c,
+		bson.M{"day": bson.M{"$gte": from, "$lte": to}},
+		options.Find().SetSort(bson.D{{Key: "day", Value: 1}, {Key: "taxiType", Value: 1}}),
+	)
+	if err != nil {
+		r.logger.Error("failed to find utilization rollups", zap.String("from", from), zap.String("to", to), zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var rows []domain.UtilizationDay
+	if err := cursor.All(c, &rows); err != nil {
+		r.logger.Error("failed to decode utilization rollups", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+// hours converts milliseconds to hours
+func hours(ms int64) float64 {
+	return time.Duration(ms * int64(time.Millisecond)).Hours()
+}
@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// VerificationRepository implements domain.VerificationRepository using MongoDB
+type VerificationRepository struct {
+	collection *mongo.Collection
+	logger     *zap.Logger
+}
+
+// NewVerificationRepository creates a new MongoDB phone verification repository
+func NewVerificationRepository(db *mongo.Database, logger *zap.Logger) *VerificationRepository {
+	return &VerificationRepository{
+		collection: db.Collection("phone_verifications"),
+		logger:     logger,
+	}
+}
+
+// Indexes lists a TTL index so expired challenges are removed by MongoDB
+func (r *VerificationRepository) Indexes() []IndexSet {
+	return []IndexSet{{Collection: r.collection, Models: []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0),
+		},
+	}}}
+}
+
+// EnsureIndexes creates the verification indexes
+func (r *VerificationRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create verification indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Save creates or replaces the pending verification for a driver
+func (r *VerificationRepository) Save(ctx interface{}, verification *domain.PhoneVerification) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{"_id": verification.DriverID}
+	_, err := r.collection.ReplaceOne(c, filter, verification, options.Replace().SetUpsert(true))
+	if err != nil {
+		r.logger.Error("failed to save phone verification", zap.Error(err), zap.String("driverId", verification.DriverID))
+		return err
+	}
+	return nil
+}
+
+// Get retrieves the pending verification for a driver
+func (r *VerificationRepository) Get(ctx interface{}, driverID string) (*domain.PhoneVerification, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	var verification domain.PhoneVerification
+	err := r.collection.FindOne(c, bson.M{"_id": driverID}).Decode(&verification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("verification not found")
+		}
+		r.logger.Error("failed to get phone verification", zap.Error(err), zap.String("driverId", driverID))
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// Delete removes the pending verification for a driver
+func (r *VerificationRepository) Delete(ctx interface{}, driverID string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	if _, err := r.collection.DeleteOne(c, bson.M{"_id": driverID}); err != nil {
+		r.logger.Error("failed to delete phone verification", zap.Error(err), zap.String("driverId", driverID))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// deliveryRetention is how long delivery logs are kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+// WebhookRepository implements domain.WebhookRepository using MongoDB
+type WebhookRepository struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+	logger        *zap.Logger
+}
+
+// webhookSubscriptionDocument is the stored representation of a subscription
+type webhookSubscriptionDocument struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	FleetID     string             `bson:"fleetId"`
+	URL         string             `bson:"url"`
+	Secret      string             `bson:"secret"`
+	Events      []string           `bson:"events"`
+	Description string             `bson:"description,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+}
+
+func (d *webhookSubscriptionDocument) toDomain() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:          d.ID.Hex(),
+		FleetID:     d.FleetID,
+		URL:         d.URL,
+		Secret:      d.Secret,
+		Events:      d.Events,
+		Description: d.Description,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+// webhookDeliveryDocument is the stored representation of a delivery. The
+// payload is kept as a string so it is sent byte for byte as first signed.
+type webhookDeliveryDocument struct {
+	ID             primitive.ObjectID    `bson:"_id"`
+	SubscriptionID string                `bson:"subscriptionId"`
+	EventID        string                `bson:"eventId"`
+	Event          string                `bson:"event"`
+	Payload        string                `bson:"payload"`
+	Status         domain.DeliveryStatus `bson:"status"`
+	Attempts       int                   `bson:"attempts"`
+	NextAttemptAt  *time.Time            `bson:"nextAttemptAt,omitempty"`
+	LastStatusCode int                   `bson:"lastStatusCode,omitempty"`
+	LastError      string                `bson:"lastError,omitempty"`
+	DeliveredAt    *time.Time            `bson:"deliveredAt,omitempty"`
+	CreatedAt      time.Time             `bson:"createdAt"`
+	UpdatedAt      time.Time             `bson:"updatedAt"`
+}
+
+func (d *webhookDeliveryDocument) toDomain() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             d.ID.Hex(),
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
+
+// NewWebhookRepository creates a new MongoDB webhook repository
+func NewWebhookRepository(db *mongo.Database, logger *zap.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		subscriptions: db.Collection("webhook_subscriptions"),
+		deliveries:    db.Collection("webhook_deliveries"),
+		logger:        logger,
+	}
+}
+
+// Indexes lists the subscription lookup, due-delivery and delivery log indexes
+func (r *WebhookRepository) Indexes() []IndexSet {
+	return []IndexSet{
+		{Collection: r.subscriptions, Models: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "fleetId", Value: 1}},
+				Options: options.Index().SetName("fleetId"),
+			},
+		}},
+		{Collection: r.deliveries, Models: []mongo.IndexModel{
+			{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}},
+				Options: options.Index().SetName("status_nextAttemptAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "subscriptionId", Value: 1}, {Key: "createdAt", Value: -1}},
+				Options: options.Index().SetName("subscriptionId_createdAt"),
+			},
+			{
+				Keys:    bson.D{{Key: "createdAt", Value: 1}},
+				Options: options.Index().SetName("createdAt_ttl").SetExpireAfterSeconds(int32(deliveryRetention / time.Second)),
+			},
+		}},
+	}
+}
+
+// EnsureIndexes creates the webhook indexes
+func (r *WebhookRepository) EnsureIndexes(ctx context.Context) error {
+	if err := ensureIndexes(ctx, r.Indexes()); err != nil {
+		r.logger.Error("failed to create webhook indexes", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// CreateSubscription inserts a new subscription
+func (r *WebhookRepository) CreateSubscription(ctx interface{}, sub *domain.WebhookSubscription) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	// Store an empty array rather than null so MatchSubscriptions can tell "every event" apart
+	events := sub.Events
+	if events == nil {
+		events = []string{}
+	}
+	doc := &webhookSubscriptionDocument{
+		ID:          primitive.NewObjectID(),
+		FleetID:     sub.FleetID,
+		URL:         sub.URL,
+		Secret:      sub.Secret,
+		Events:      events,
+		Description: sub.Description,
+		CreatedAt:   sub.CreatedAt,
+	}
+	if _, err := r.subscriptions.InsertOne(c, doc); err != nil {
+		r.logger.Error("failed to create webhook subscription", zap.Error(err))
+		return err
+	}
+
+	sub.ID = doc.ID.Hex()
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID
+func (r *WebhookRepository) GetSubscription(ctx interface{}, id string) (*domain.WebhookSubscription, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("webhook subscription not found")
+	}
+
+	var doc webhookSubscriptionDocument
+	if err := r.subscriptions.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("webhook subscription not found")
+		}
+		r.logger.Error("failed to get webhook subscription", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// ListSubscriptions lists subscriptions ordered by creation time
+func (r *WebhookRepository) ListSubscriptions(ctx interface{}, fleetID string) ([]*domain.WebhookSubscription, error) {
+	filter := bson.M{}
+	if fleetID != "" {
+		filter["fleetId"] = fleetID
+	}
+	return r.findSubscriptions(ctx, filter)
+}
+
+// MatchSubscriptions returns the fleet's subscriptions and the global ones that receive event
+func (r *WebhookRepository) MatchSubscriptions(ctx interface{}, event, fleetID string) ([]*domain.WebhookSubscription, error) {
+	fleets := []string{""}
+	if fleetID != "" {
+		fleets = append(fleets, fleetID)
+	}
+	filter := bson.M{
+		"fleetId": bson.M{"$in": fleets},
+		"$or": bson.A{
+			bson.M{"events": event},
+			bson.M{"events": bson.M{"$size": 0}},
+		},
+	}
+	return r.findSubscriptions(ctx, filter)
+}
+
+func (r *WebhookRepository) findSubscriptions(ctx interface{}, filter bson.M) ([]*domain.WebhookSubscription, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	cursor, err := r.subscriptions.Find(c, filter, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		r.logger.Error("failed to list webhook subscriptions", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []webhookSubscriptionDocument
+	if err = cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode webhook subscriptions", zap.Error(err))
+		return nil, err
+	}
+
+	subs := make([]*domain.WebhookSubscription, len(docs))
+	for i := range docs {
+		subs[i] = docs[i].toDomain()
+	}
+	return subs, nil
+}
+
+// DeleteSubscription removes a subscription; its delivery log expires on its own
+func (r *WebhookRepository) DeleteSubscription(ctx interface{}, id string) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("webhook subscription not found")
+	}
+
+	result, err := r.subscriptions.DeleteOne(c, bson.M{"_id": objectID})
+	if err != nil {
+		r.logger.Error("failed to delete webhook subscription", zap.Error(err), zap.String("id", id))
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("webhook subscription not found")
+	}
+
+	// Stop retrying deliveries nobody is subscribed to anymore
+	_, err = r.deliveries.UpdateMany(c,
+		bson.M{"subscriptionId": id, "status": domain.DeliveryPending},
+		bson.M{"$set": bson.M{"status": domain.DeliveryFailed, "lastError": "subscription deleted", "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		r.logger.Warn("failed to cancel pending webhook deliveries", zap.Error(err), zap.String("id", id))
+	}
+	return nil
+}
+
+// CreateDeliveries inserts new deliveries and sets their IDs
+func (r *WebhookRepository) CreateDeliveries(ctx interface{}, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	docs := make([]interface{}, len(deliveries))
+	for i, d := range deliveries {
+		doc := newWebhookDeliveryDocument(primitive.NewObjectID(), d)
+		docs[i] = doc
+		d.ID = doc.ID.Hex()
+	}
+	if _, err := r.deliveries.InsertMany(c, docs); err != nil {
+		r.logger.Error("failed to create webhook deliveries", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx interface{}, id string) (*domain.WebhookDelivery, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("webhook delivery not found")
+	}
+
+	var doc webhookDeliveryDocument
+	if err := r.deliveries.FindOne(c, bson.M{"_id": objectID}).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New("webhook delivery not found")
+		}
+		r.logger.Error("failed to get webhook delivery", zap.Error(err), zap.String("id", id))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// ClaimDelivery leases the oldest due delivery. Deliveries whose lease expired
+// (the worker died mid-send) are claimed again.
+func (r *WebhookRepository) ClaimDelivery(ctx interface{}, now, leaseUntil time.Time) (*domain.WebhookDelivery, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	filter := bson.M{
+		"status":        bson.M{"$in": bson.A{domain.DeliveryPending, domain.DeliveryInProgress}},
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":        domain.DeliveryInProgress,
+		"nextAttemptAt": leaseUntil,
+		"updatedAt":     now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"nextAttemptAt": 1}).
+		SetReturnDocument(options.After)
+
+	var doc webhookDeliveryDocument
+	if err := r.deliveries.FindOneAndUpdate(c, filter, update, opts).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		r.logger.Error("failed to claim webhook delivery", zap.Error(err))
+		return nil, err
+	}
+	return doc.toDomain(), nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(ctx interface{}, delivery *domain.WebhookDelivery) error {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(delivery.ID)
+	if err != nil {
+		return errors.New("webhook delivery not found")
+	}
+
+	doc := newWebhookDeliveryDocument(objectID, delivery)
+	result, err := r.deliveries.ReplaceOne(c, bson.M{"_id": objectID}, doc)
+	if err != nil {
+		r.logger.Error("failed to update webhook delivery", zap.Error(err), zap.String("id", delivery.ID))
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("webhook delivery not found")
+	}
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a subscription
+func (r *WebhookRepository) ListDeliveries(ctx interface{}, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error) {
+	c, ok := ctx.(context.Context)
+	if !ok {
+		c = context.Background()
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(c, bson.M{"subscriptionId": subscriptionID}, findOptions)
+	if err != nil {
+		r.logger.Error("failed to list webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+	defer cursor.Close(c)
+
+	var docs []webhookDeliveryDocument
+	if err = cursor.All(c, &docs); err != nil {
+		r.logger.Error("failed to decode webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(docs))
+	for i := range docs {
+		deliveries[i] = docs[i].toDomain()
+	}
+	return deliveries, nil
+}
+
+func newWebhookDeliveryDocument(id primitive.ObjectID, d *domain.WebhookDelivery) *webhookDeliveryDocument {
+	return &webhookDeliveryDocument{
+		ID:             id,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        string(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package shadow
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// maxDiffs bounds the differing fields reported for one read
+const maxDiffs = 10
+
+// timePrecision is the precision times are compared at; backends store them
+// with different precision, MongoDB with milliseconds
+const timePrecision = time.Millisecond
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// copyDriver returns a shallow copy of driver, enough to keep the fields the
+// caller reassigns from changing under the comparison
+func copyDriver(driver *domain.Driver) *domain.Driver {
+	if driver == nil {
+		return nil
+	}
+	copied := *driver
+	return &copied
+}
+
+func copyDrivers(drivers []*domain.Driver) []*domain.Driver {
+	copied := make([]*domain.Driver, len(drivers))
+	for i, driver := range drivers {
+		copied[i] = copyDriver(driver)
+	}
+	return copied
+}
+
+// compareErrors reports whether the comparison is decided by the errors, and
+// the difference if it is. Reads that failed the same way on both sides match.
+func compareErrors(primaryErr, shadowErr error) (decided bool, diffs []string) {
+	if primaryErr == nil && shadowErr == nil {
+		return false, nil
+	}
+	if primaryErr != nil && shadowErr != nil && primaryErr.Error() == shadowErr.Error() {
+		return true, nil
+	}
+	return true, []string{"error"}
+}
+
+// compareDriver returns the fields in which the shadow's driver differs
+func compareDriver(primary *domain.Driver, primaryErr error, shadow *domain.Driver, shadowErr error) []string {
+	if decided, diffs := compareErrors(primaryErr, shadowErr); decided {
+		return diffs
+	}
+	return driverDiffs("", primary, shadow)
+}
+
+// compareDrivers returns how the shadow's drivers differ: by count, by the
+// drivers found, in order when ordered, and by the fields of each driver
+func compareDrivers(primary []*domain.Driver, primaryErr error, shadow []*domain.Driver, shadowErr error, ordered bool) []string {
+	if decided, diffs := compareErrors(primaryErr, shadowErr); decided {
+		return diffs
+	}
+	if len(primary) != len(shadow) {
+		return []string{"count"}
+	}
+	if !ordered {
+		primary, shadow = sortedByID(primary), sortedByID(shadow)
+	}
+	var diffs []string
+	for i := range primary {
+		if primary[i].ID != shadow[i].ID {
+			if ordered {
+				return []string{"order"}
+			}
+			return []string{"drivers"}
+		}
+		diffs = append(diffs, driverDiffs("["+strconv.Itoa(i)+"].", primary[i], shadow[i])...)
+		if len(diffs) >= maxDiffs {
+			return diffs[:maxDiffs]
+		}
+	}
+	return diffs
+}
+
+func sortedByID(drivers []*domain.Driver) []*domain.Driver {
+	sorted := append([]*domain.Driver(nil), drivers...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+// driverDiffs returns the names of the driver fields that differ, prefixed
+func driverDiffs(prefix string, primary, shadow *domain.Driver) []string {
+	if primary == nil || shadow == nil {
+		if primary != shadow {
+			return []string{prefix + "driver"}
+		}
+		return nil
+	}
+	var diffs []string
+	a, b := reflect.ValueOf(primary).Elem(), reflect.ValueOf(shadow).Elem()
+	for i := 0; i < a.NumField(); i++ {
+		if !equal(a.Field(i), b.Field(i)) {
+			diffs = append(diffs, prefix+a.Type().Field(i).Name)
+		}
+	}
+	return diffs
+}
+
+// equal compares two values of the same type the way two backends storing the
+// same data would agree: times at timePrecision, nil and empty slices and maps
+// alike, and only the exported fields of structs
+func equal(a, b reflect.Value) bool {
+	if a.Type() == timeType {
+		return a.Interface().(time.Time).Truncate(timePrecision).Equal(b.Interface().(time.Time).Truncate(timePrecision))
+	}
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if a.Type().Field(i).IsExported() && !equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !equal(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+}
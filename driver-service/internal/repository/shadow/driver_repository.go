@@ -0,0 +1,256 @@
+// Package shadow mirrors driver reads to a second backend ahead of a migration.
+// Every read is answered by the primary; a sample of them is run again against
+// the shadow backend in the background and the two results are compared, so
+// divergences show up in the logs before any traffic depends on the shadow.
+package shadow
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Options configures the mirroring
+type Options struct {
+	// Backend names the kind of store mirrored to, for the logs and stats
+	Backend string
+	// SampleRate is the share of reads mirrored, from 0 to 1
+	SampleRate float64
+	// Timeout bounds each shadow read, so a slow shadow cannot pile up work
+	Timeout time.Duration
+	// MaxInFlight bounds the shadow reads running at once; sampled reads
+	// beyond it are dropped rather than queued
+	MaxInFlight int
+}
+
+// opCounts are the running totals of one mirrored method
+type opCounts struct {
+	mirrored int64
+	matched  int64
+	diverged int64
+	timedOut int64
+}
+
+// DriverRepository answers driver reads from the primary and mirrors a sample
+// of them to the shadow. Writes only go to the primary; keeping the shadow in
+// step is left to the migration.
+type DriverRepository struct {
+	domain.DriverRepository
+
+	shadow domain.DriverRepository
+	opts   Options
+	logger *zap.Logger
+	sample func() float64
+	slots  chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	ops     map[string]*opCounts
+	dropped int64
+}
+
+// New creates a repository reading from primary and mirroring to shadow. A
+// timeout that is not positive falls back to two seconds and a MaxInFlight
+// below 1 to 50.
+func New(primary, shadow domain.DriverRepository, opts Options, logger *zap.Logger) *DriverRepository {
+	opts.SampleRate = math.Max(0, math.Min(1, opts.SampleRate))
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	if opts.MaxInFlight < 1 {
+		opts.MaxInFlight = 50
+	}
+	return &DriverRepository{
+		DriverRepository: primary,
+		shadow:           shadow,
+		opts:             opts,
+		logger:           logger,
+		sample:           rand.Float64,
+		slots:            make(chan struct{}, opts.MaxInFlight),
+		ops:              make(map[string]*opCounts),
+	}
+}
+
+// GetByID reads the driver from the primary and mirrors the read
+func (r *DriverRepository) GetByID(ctx interface{}, id string) (*domain.Driver, error) {
+	driver, err := r.DriverRepository.GetByID(ctx, id)
+	if r.sampled() {
+		primary := copyDriver(driver)
+		r.mirror("GetByID", func(c context.Context) []string {
+			shadowDriver, shadowErr := r.shadow.GetByID(c, id)
+			return compareDriver(primary, err, shadowDriver, shadowErr)
+		}, zap.String("id", id))
+	}
+	return driver, err
+}
+
+// GetByPhone reads the driver from the primary and mirrors the read
+func (r *DriverRepository) GetByPhone(ctx interface{}, phone string) (*domain.Driver, error) {
+	driver, err := r.DriverRepository.GetByPhone(ctx, phone)
+	if r.sampled() {
+		primary := copyDriver(driver)
+		r.mirror("GetByPhone", func(c context.Context) []string {
+			shadowDriver, shadowErr := r.shadow.GetByPhone(c, phone)
+			return compareDriver(primary, err, shadowDriver, shadowErr)
+		})
+	}
+	return driver, err
+}
+
+// GetByEmail reads the driver from the primary and mirrors the read
+func (r *DriverRepository) GetByEmail(ctx interface{}, email string) (*domain.Driver, error) {
+	driver, err := r.DriverRepository.GetByEmail(ctx, email)
+	if r.sampled() {
+		primary := copyDriver(driver)
+		r.mirror("GetByEmail", func(c context.Context) []string {
+			shadowDriver, shadowErr := r.shadow.GetByEmail(c, email)
+			return compareDriver(primary, err, shadowDriver, shadowErr)
+		})
+	}
+	return driver, err
+}
+
+// GetByPlate reads the driver from the primary and mirrors the read
+func (r *DriverRepository) GetByPlate(ctx interface{}, plate string) (*domain.Driver, error) {
+	driver, err := r.DriverRepository.GetByPlate(ctx, plate)
+	if r.sampled() {
+		primary := copyDriver(driver)
+		r.mirror("GetByPlate", func(c context.Context) []string {
+			shadowDriver, shadowErr := r.shadow.GetByPlate(c, plate)
+			return compareDriver(primary, err, shadowDriver, shadowErr)
+		})
+	}
+	return driver, err
+}
+
+// List reads the page from the primary and mirrors the read; both backends
+// must return the same drivers in the same order
+func (r *DriverRepository) List(ctx interface{}, filter domain.DriverFilter, page, pageSize int) ([]*domain.Driver, int64, error) {
+	drivers, total, err := r.DriverRepository.List(ctx, filter, page, pageSize)
+	if r.sampled() {
+		primary := copyDrivers(drivers)
+		r.mirror("List", func(c context.Context) []string {
+			shadowDrivers, shadowTotal, shadowErr := r.shadow.List(c, filter, page, pageSize)
+			diffs := compareDrivers(primary, err, shadowDrivers, shadowErr, true)
+			if err == nil && shadowErr == nil && total != shadowTotal {
+				diffs = append(diffs, "total")
+			}
+			return diffs
+		}, zap.Int("page", page), zap.Int("pageSize", pageSize))
+	}
+	return drivers, total, err
+}
+
+// FindNearby searches the primary and mirrors the search. Drivers at the same
+// distance may come back in either order, so only the drivers found are compared.
+func (r *DriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*domain.Driver, error) {
+	drivers, err := r.DriverRepository.FindNearby(ctx, lat, lon, radiusKm, taxiType, filter, page)
+	if r.sampled() {
+		primary := copyDrivers(drivers)
+		r.mirror("FindNearby", func(c context.Context) []string {
+			shadowDrivers, shadowErr := r.shadow.FindNearby(c, lat, lon, radiusKm, taxiType, filter, page)
+			return compareDrivers(primary, err, shadowDrivers, shadowErr, false)
+		}, zap.Float64("radiusKm", radiusKm))
+	}
+	return drivers, err
+}
+
+// sampled draws whether a read is mirrored
+func (r *DriverRepository) sampled() bool {
+	return r.opts.SampleRate > 0 && r.sample() < r.opts.SampleRate
+}
+
+// mirror runs compare in the background. compare reads from the shadow with
+// a context of its own, detached from the request, and returns the fields in
+// which the shadow's answer differed from a copy of the primary's; the caller
+// keeps the primary's answer and may change it meanwhile.
+func (r *DriverRepository) mirror(op string, compare func(c context.Context) []string, fields ...zap.Field) {
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.mu.Lock()
+		r.dropped++
+		r.mu.Unlock()
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.slots }()
+
+		c, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+		defer cancel()
+		diffs := compare(c)
+		timedOut := c.Err() != nil
+
+		r.mu.Lock()
+		counts := r.ops[op]
+		if counts == nil {
+			counts = &opCounts{}
+			r.ops[op] = counts
+		}
+		counts.mirrored++
+		switch {
+		case timedOut:
+			counts.timedOut++
+		case len(diffs) > 0:
+			counts.diverged++
+		default:
+			counts.matched++
+		}
+		r.mu.Unlock()
+
+		if len(diffs) > 0 && !timedOut {
+			// Only the names of the differing fields are logged, never their values
+			r.logger.Warn("shadow read diverged", append(fields,
+				zap.String("operation", op),
+				zap.String("backend", r.opts.Backend),
+				zap.Strings("fields", diffs),
+			)...)
+		}
+	}()
+}
+
+// Wait blocks until the shadow reads in flight have finished
+func (r *DriverRepository) Wait() {
+	r.wg.Wait()
+}
+
+// Stats returns how the mirrored reads compared, by method
+func (r *DriverRepository) Stats() domain.ShadowReadStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := domain.ShadowReadStats{
+		Enabled:           true,
+		Backend:           r.opts.Backend,
+		SampleRatePercent: r.opts.SampleRate * 100,
+		InFlight:          len(r.slots),
+		Dropped:           r.dropped,
+		Operations:        make([]domain.ShadowReadOpStats, 0, len(r.ops)),
+	}
+	for op, counts := range r.ops {
+		opStats := domain.ShadowReadOpStats{
+			Operation: op,
+			Mirrored:  counts.mirrored,
+			Matched:   counts.matched,
+			Diverged:  counts.diverged,
+			TimedOut:  counts.timedOut,
+		}
+		if compared := counts.matched + counts.diverged; compared > 0 {
+			opStats.MatchRatePercent = math.Round(float64(counts.matched)/float64(compared)*10000) / 100
+		}
+		stats.Operations = append(stats.Operations, opStats)
+	}
+	sort.Slice(stats.Operations, func(i, j int) bool {
+		return stats.Operations[i].Operation < stats.Operations[j].Operation
+	})
+	return stats
+}
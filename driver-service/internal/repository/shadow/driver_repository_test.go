@@ -0,0 +1,140 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeRepository serves fixed drivers; block delays every read until closed
+type fakeRepository struct {
+	domain.DriverRepository
+	drivers map[string]*domain.Driver
+	nearby  []*domain.Driver
+	block   chan struct{}
+}
+
+func (f *fakeRepository) wait(ctx interface{}) error {
+	if f.block == nil {
+		return nil
+	}
+	select {
+	case <-f.block:
+		return nil
+	case <-ctx.(context.Context).Done():
+		return ctx.(context.Context).Err()
+	}
+}
+
+func (f *fakeRepository) GetByID(ctx interface{}, id string) (*domain.Driver, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	driver, ok := f.drivers[id]
+	if !ok {
+		return nil, errors.New("driver not found")
+	}
+	copied := *driver
+	return &copied, nil
+}
+
+func (f *fakeRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*domain.Driver, error) {
+	return page.Apply(f.nearby), nil
+}
+
+func TestDriverRepository_Mirror(t *testing.T) {
+	updatedAt := time.Date(2025, 12, 6, 1, 0, 0, 123456789, time.UTC)
+	primary := &fakeRepository{drivers: map[string]*domain.Driver{
+		"d1": {ID: "d1", FirstName: "Ahmet", Plate: "34ABC123", UpdatedAt: updatedAt},
+		"d2": {ID: "d2", FirstName: "Ayşe", Plate: "34XYZ789", Tags: []string{}},
+	}}
+	shadowRepo := &fakeRepository{drivers: map[string]*domain.Driver{
+		// Stored with coarser times and nil instead of empty tags, which still match
+		"d1": {ID: "d1", FirstName: "Ahmet", Plate: "34ABC123", UpdatedAt: updatedAt.Truncate(time.Millisecond)},
+		"d2": {ID: "d2", FirstName: "Ayşe", Plate: "34XYZ999"},
+	}}
+	core, logs := observer.New(zap.WarnLevel)
+	repo := New(primary, shadowRepo, Options{Backend: "mongodb", SampleRate: 1}, zap.New(core))
+	ctx := context.Background()
+
+	driver, err := repo.GetByID(ctx, "d1")
+	if err != nil || driver.FirstName != "Ahmet" {
+		t.Fatalf("GetByID() = %v, %v; want the primary's driver", driver, err)
+	}
+	// The caller may change the driver while the shadow read runs
+	driver.FirstName = "Mehmet"
+	repo.GetByID(ctx, "d2")
+	repo.GetByID(ctx, "missing")
+	repo.Wait()
+
+	stats := repo.Stats()
+	want := []domain.ShadowReadOpStats{{Operation: "GetByID", Mirrored: 3, Matched: 2, Diverged: 1, MatchRatePercent: 66.67}}
+	if !reflect.DeepEqual(stats.Operations, want) {
+		t.Errorf("Operations = %+v, want %+v", stats.Operations, want)
+	}
+	if logs.Len() != 1 {
+		t.Fatalf("expected 1 divergence logged, got %d", logs.Len())
+	}
+	fields := logs.All()[0].ContextMap()
+	if fields["id"] != "d2" || !reflect.DeepEqual(fields["fields"], []interface{}{"Plate"}) {
+		t.Errorf("unexpected divergence log fields: %v", fields)
+	}
+}
+
+func TestDriverRepository_MirrorNearby(t *testing.T) {
+	a, b := &domain.Driver{ID: "a"}, &domain.Driver{ID: "b"}
+	primary := &fakeRepository{nearby: []*domain.Driver{a, b}}
+	shadowRepo := &fakeRepository{nearby: []*domain.Driver{b, a}}
+	repo := New(primary, shadowRepo, Options{SampleRate: 1}, zap.NewNop())
+
+	repo.FindNearby(context.Background(), 41.0431, 29.0099, 6, nil, domain.DriverFilter{}, domain.NearbyPage{})
+	repo.Wait()
+	shadowRepo.nearby = []*domain.Driver{a}
+	repo.FindNearby(context.Background(), 41.0431, 29.0099, 6, nil, domain.DriverFilter{}, domain.NearbyPage{})
+	repo.Wait()
+
+	op := repo.Stats().Operations[0]
+	if op.Matched != 1 || op.Diverged != 1 {
+		t.Errorf("expected drivers found in another order to match and a missing one to diverge, got %+v", op)
+	}
+}
+
+func TestDriverRepository_MirrorBounds(t *testing.T) {
+	primary := &fakeRepository{drivers: map[string]*domain.Driver{"d1": {ID: "d1"}}}
+	shadowRepo := &fakeRepository{drivers: primary.drivers, block: make(chan struct{})}
+	repo := New(primary, shadowRepo, Options{SampleRate: 0.5, Timeout: 100 * time.Millisecond, MaxInFlight: 1}, zap.NewNop())
+	ctx := context.Background()
+
+	// Reads drawn above the sample rate are not mirrored
+	repo.sample = func() float64 { return 0.7 }
+	repo.GetByID(ctx, "d1")
+	if stats := repo.Stats(); stats.InFlight != 0 || stats.Dropped != 0 {
+		t.Errorf("expected nothing mirrored, got %+v", stats)
+	}
+
+	// A slow shadow neither holds up the primary's answer nor queues reads
+	repo.sample = func() float64 { return 0.2 }
+	start := time.Now()
+	if _, err := repo.GetByID(ctx, "d1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.GetByID(ctx, "d1")
+	if elapsed := time.Since(start); elapsed >= 100*time.Millisecond {
+		t.Errorf("reads took %v, want them answered without waiting for the shadow", elapsed)
+	}
+	repo.Wait()
+
+	stats := repo.Stats()
+	if stats.Dropped != 1 {
+		t.Errorf("Dropped = %d, want 1", stats.Dropped)
+	}
+	if op := stats.Operations[0]; op.Mirrored != 1 || op.TimedOut != 1 || op.Diverged != 0 {
+		t.Errorf("expected the shadow read to time out without diverging, got %+v", op)
+	}
+}
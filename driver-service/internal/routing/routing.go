@@ -0,0 +1,241 @@
+// Package routing estimates travel distance and time between locations.
+//
+// The haversine router measures straight lines and is always available. The
+// OSRM router asks an OSRM server for road distances and durations, which
+// matter in dense cities where the straight line crosses water or blocks.
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/haversine"
+	"go.uber.org/zap"
+)
+
+// Provider names accepted by NewRouter
+const (
+	ProviderHaversine = "haversine"
+	ProviderOSRM      = "osrm"
+)
+
+// defaultSpeedKmh is the average city speed used to estimate straight-line durations
+const defaultSpeedKmh = 25.0
+
+// Route is the travel distance and time from an origin to one destination
+type Route struct {
+	DistanceKm  float64
+	DurationSec float64
+}
+
+// Router computes routes from one origin to many destinations. The returned
+// routes are in the order of the destinations.
+type Router interface {
+	Name() string
+	Routes(ctx context.Context, from domain.Location, to []domain.Location) ([]Route, error)
+}
+
+// Options configures the routers
+type Options struct {
+	// OSRMURL is the base URL of the OSRM server, e.g. http://osrm:5000
+	OSRMURL string
+	// OSRMProfile is the OSRM routing profile; defaults to "driving"
+	OSRMProfile string
+	Timeout     time.Duration
+	// SpeedKmh is the average speed haversine durations are estimated with
+	SpeedKmh float64
+}
+
+// NewRouter creates the router with the given name. The OSRM router falls back
+// to straight-line estimates when the OSRM server fails.
+func NewRouter(name string, opts Options, logger *zap.Logger) (Router, error) {
+	if opts.SpeedKmh <= 0 {
+		opts.SpeedKmh = defaultSpeedKmh
+	}
+	straight := HaversineRouter{SpeedKmh: opts.SpeedKmh}
+
+	switch name {
+	case ProviderHaversine, "":
+		return straight, nil
+	case ProviderOSRM:
+		if opts.OSRMURL == "" {
+			return nil, fmt.Errorf("OSRM_URL is required for the %s routing provider", ProviderOSRM)
+		}
+		return &fallbackRouter{
+			primary:  NewOSRMRouter(opts.OSRMURL, opts.OSRMProfile, opts.Timeout),
+			fallback: straight,
+			logger:   logger,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown routing provider %q", name)
+	}
+}
+
+// HaversineRouter estimates routes as straight lines travelled at a fixed speed
+type HaversineRouter struct {
+	SpeedKmh float64
+}
+
+// Name returns the provider name
+func (r HaversineRouter) Name() string {
+	return ProviderHaversine
+}
+
+// Routes returns the straight-line distance to every destination
+func (r HaversineRouter) Routes(ctx context.Context, from domain.Location, to []domain.Location) ([]Route, error) {
+	speed := r.SpeedKmh
+	if speed <= 0 {
+		speed = defaultSpeedKmh
+	}
+	routes := make([]Route, len(to))
+	for i, dest := range to {
+		distance := haversine.Distance(from.Lat, from.Lon, dest.Lat, dest.Lon)
+		routes[i] = Route{DistanceKm: distance, DurationSec: distance / speed * 3600}
+	}
+	return routes, nil
+}
+
+// osrmMaxTableSize is the default max-table-size of osrm-routed; larger
+// requests are split into batches
+const osrmMaxTableSize = 100
+
+// OSRMRouter computes road distances and durations with the OSRM table service
+type OSRMRouter struct {
+	baseURL    string
+	profile    string
+	httpClient *http.Client
+}
+
+// NewOSRMRouter creates a router backed by the OSRM server at baseURL
+func NewOSRMRouter(baseURL, profile string, timeout time.Duration) *OSRMRouter {
+	if profile == "" {
+		profile = "driving"
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &OSRMRouter{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		profile: profile,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Name returns the provider name
+func (r *OSRMRouter) Name() string {
+	return ProviderOSRM
+}
+
+// osrmTable is the part of an OSRM table response the router reads
+type osrmTable struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Distances [][]*float64 `json:"distances"`
+	Durations [][]*float64 `json:"durations"`
+}
+
+// Routes returns the road distance and duration to every destination.
+// Destinations OSRM cannot reach are estimated as straight lines.
+func (r *OSRMRouter) Routes(ctx context.Context, from domain.Location, to []domain.Location) ([]Route, error) {
+	routes := make([]Route, 0, len(to))
+	for start := 0; start < len(to); start += osrmMaxTableSize - 1 {
+		end := start + osrmMaxTableSize - 1
+		if end > len(to) {
+			end = len(to)
+		}
+		batch, err := r.table(ctx, from, to[start:end])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, batch...)
+	}
+	return routes, nil
+}
+
+// table asks OSRM for the routes from one origin to a batch of destinations
+func (r *OSRMRouter) table(ctx context.Context, from domain.Location, to []domain.Location) ([]Route, error) {
+	coordinates := make([]string, 0, len(to)+1)
+	destinations := make([]string, len(to))
+	coordinates = append(coordinates, osrmCoordinate(from))
+	for i, dest := range to {
+		coordinates = append(coordinates, osrmCoordinate(dest))
+		destinations[i] = strconv.Itoa(i + 1)
+	}
+
+	query := url.Values{}
+	query.Set("sources", "0")
+	query.Set("destinations", strings.Join(destinations, ";"))
+	query.Set("annotations", "distance,duration")
+	endpoint := fmt.Sprintf("%s/table/v1/%s/%s?%s", r.baseURL, r.profile, strings.Join(coordinates, ";"), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create osrm request: %w", err)
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query osrm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var table osrmTable
+	if err := json.NewDecoder(resp.Body).Decode(&table); err != nil {
+		return nil, fmt.Errorf("failed to decode osrm response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || table.Code != "Ok" {
+		return nil, fmt.Errorf("osrm returned %s: %s", table.Code, table.Message)
+	}
+	if len(table.Distances) != 1 || len(table.Distances[0]) != len(to) ||
+		len(table.Durations) != 1 || len(table.Durations[0]) != len(to) {
+		return nil, fmt.Errorf("osrm returned a table of unexpected size")
+	}
+
+	straight, _ := HaversineRouter{}.Routes(ctx, from, to)
+	routes := make([]Route, len(to))
+	for i := range to {
+		distance, duration := table.Distances[0][i], table.Durations[0][i]
+		if distance == nil || duration == nil {
+			routes[i] = straight[i]
+			continue
+		}
+		routes[i] = Route{DistanceKm: *distance / 1000, DurationSec: *duration}
+	}
+	return routes, nil
+}
+
+// osrmCoordinate formats a location as OSRM's "lon,lat"
+func osrmCoordinate(loc domain.Location) string {
+	return strconv.FormatFloat(loc.Lon, 'f', 6, 64) + "," + strconv.FormatFloat(loc.Lat, 'f', 6, 64)
+}
+
+// fallbackRouter answers from the fallback router while the primary one fails
+type fallbackRouter struct {
+	primary  Router
+	fallback Router
+	logger   *zap.Logger
+}
+
+func (r *fallbackRouter) Name() string {
+	return r.primary.Name()
+}
+
+func (r *fallbackRouter) Routes(ctx context.Context, from domain.Location, to []domain.Location) ([]Route, error) {
+	routes, err := r.primary.Routes(ctx, from, to)
+	if err == nil {
+		return routes, nil
+	}
+	r.logger.Warn("routing provider failed, using straight-line distances",
+		zap.String("provider", r.primary.Name()),
+		zap.Error(err),
+	)
+	return r.fallback.Routes(ctx, from, to)
+}
@@ -0,0 +1,156 @@
+package routing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+var (
+	taksim   = domain.Location{Lat: 41.0370, Lon: 28.9850}
+	kadikoy  = domain.Location{Lat: 40.9903, Lon: 29.0290}
+	besiktas = domain.Location{Lat: 41.0431, Lon: 29.0099}
+)
+
+func TestNewRouter(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		opts     Options
+		expected string
+		wantErr  bool
+	}{
+		{name: "default is haversine", provider: "", expected: ProviderHaversine},
+		{name: "haversine", provider: ProviderHaversine, expected: ProviderHaversine},
+		{name: "osrm", provider: ProviderOSRM, opts: Options{OSRMURL: "http://osrm:5000"}, expected: ProviderOSRM},
+		{name: "osrm without url", provider: ProviderOSRM, wantErr: true},
+		{name: "unknown", provider: "google", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := NewRouter(tt.provider, tt.opts, zap.NewNop())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if router.Name() != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, router.Name())
+			}
+		})
+	}
+}
+
+func TestHaversineRouter(t *testing.T) {
+	routes, err := HaversineRouter{SpeedKmh: 30}.Routes(context.Background(), taksim, []domain.Location{taksim, kadikoy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].DistanceKm != 0 || routes[0].DurationSec != 0 {
+		t.Errorf("expected an empty route to the origin, got %+v", routes[0])
+	}
+	if routes[1].DistanceKm < 6 || routes[1].DistanceKm > 7 {
+		t.Errorf("expected about 6.3 km to Kadıköy, got %.2f", routes[1].DistanceKm)
+	}
+	if want := routes[1].DistanceKm / 30 * 3600; math.Abs(routes[1].DurationSec-want) > 1e-6 {
+		t.Errorf("expected %.0f s at 30 km/h, got %.0f", want, routes[1].DurationSec)
+	}
+}
+
+func TestOSRMRouter(t *testing.T) {
+	t.Run("reads distances and durations from the table service", func(t *testing.T) {
+		var path, query string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, query = r.URL.Path, r.URL.RawQuery
+			w.Write([]byte(`{"code":"Ok","distances":[[8450.2,null]],"durations":[[912.5,null]]}`))
+		}))
+		defer server.Close()
+
+		routes, err := NewOSRMRouter(server.URL, "", 0).Routes(context.Background(), taksim, []domain.Location{kadikoy, besiktas})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if path != "/table/v1/driving/28.985000,41.037000;29.029000,40.990300;29.009900,41.043100" {
+			t.Errorf("unexpected path %s", path)
+		}
+		if !strings.Contains(query, "sources=0") || !strings.Contains(query, "destinations=1%3B2") {
+			t.Errorf("unexpected query %s", query)
+		}
+		if routes[0].DistanceKm != 8.4502 || routes[0].DurationSec != 912.5 {
+			t.Errorf("expected the road route, got %+v", routes[0])
+		}
+		straight, _ := HaversineRouter{}.Routes(context.Background(), taksim, []domain.Location{besiktas})
+		if routes[1] != straight[0] {
+			t.Errorf("expected an unroutable destination to fall back to a straight line, got %+v", routes[1])
+		}
+	})
+
+	t.Run("splits large requests into batches", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			n := len(strings.Split(r.URL.Query().Get("destinations"), ";"))
+			row := strings.TrimSuffix(strings.Repeat("1000,", n), ",")
+			w.Write([]byte(`{"code":"Ok","distances":[[` + row + `]],"durations":[[` + row + `]]}`))
+		}))
+		defer server.Close()
+
+		to := make([]domain.Location, 150)
+		for i := range to {
+			to[i] = kadikoy
+		}
+		routes, err := NewOSRMRouter(server.URL, "driving", 0).Routes(context.Background(), taksim, to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 2 || len(routes) != 150 {
+			t.Errorf("expected 150 routes from 2 calls, got %d from %d", len(routes), calls)
+		}
+	})
+
+	t.Run("reports osrm errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"InvalidQuery","message":"Query string malformed"}`))
+		}))
+		defer server.Close()
+
+		if _, err := NewOSRMRouter(server.URL, "driving", 0).Routes(context.Background(), taksim, []domain.Location{kadikoy}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestNewRouter_OSRMFallsBackToHaversine(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	router, err := NewRouter(ProviderOSRM, Options{OSRMURL: server.URL, SpeedKmh: 30}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	routes, err := router.Routes(context.Background(), taksim, []domain.Location{kadikoy})
+	if err != nil {
+		t.Fatalf("expected the fallback to answer, got %v", err)
+	}
+	straight, _ := HaversineRouter{SpeedKmh: 30}.Routes(context.Background(), taksim, []domain.Location{kadikoy})
+	if routes[0] != straight[0] {
+		t.Errorf("expected the straight-line route, got %+v", routes[0])
+	}
+}
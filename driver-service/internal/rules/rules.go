@@ -0,0 +1,349 @@
+// Package rules holds the validation rules applied to driver fields. Markets
+// differ in plate formats, required fields and the taxi types they run, so
+// rules are configured per country and per tenant (fleet) and compiled once at
+// startup. Without a rules file every driver gets the built-in Turkish rules.
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/plate"
+)
+
+// Driver fields rules can require or constrain with a pattern
+const (
+	FieldFirstName = "firstName"
+	FieldLastName  = "lastName"
+	FieldCarBrand  = "carBrand"
+	FieldCarModel  = "carModel"
+	FieldPhone     = "phone"
+	FieldEmail     = "email"
+	FieldLicense   = "license"
+)
+
+// DefaultCountry is the market the built-in rules are written for
+const DefaultCountry = "TR"
+
+var knownFields = map[string]bool{
+	FieldFirstName: true,
+	FieldLastName:  true,
+	FieldCarBrand:  true,
+	FieldCarModel:  true,
+	FieldPhone:     true,
+	FieldEmail:     true,
+	FieldLicense:   true,
+}
+
+// ValidationError reports a driver field that breaks the rules
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Config is the rules file. Countries and tenants only list what differs:
+// unset rules of a country are taken from the built-in defaults, and unset
+// rules of a tenant from its country.
+type Config struct {
+	// Country is the market the service runs in, used for tenants without a
+	// country of their own; defaults to TR
+	Country   string           `json:"country"`
+	Countries map[string]Rules `json:"countries"`
+	// Tenants are keyed by fleet ID
+	Tenants map[string]Rules `json:"tenants"`
+}
+
+// Rules is one country's or tenant's set of rules as written in the file
+type Rules struct {
+	// Country is the country a tenant operates in
+	Country string `json:"country,omitempty"`
+	// PlatePattern is a regular expression the upper-cased plate, without
+	// spaces, dashes and dots, must match. Empty keeps the Turkish plate format.
+	PlatePattern string `json:"platePattern,omitempty"`
+	// PlateExample is shown in the error for plates that do not match
+	PlateExample string `json:"plateExample,omitempty"`
+	// RequiredFields replaces the list of fields a new driver must have
+	RequiredFields []string `json:"requiredFields,omitempty"`
+	// TaxiTypes lists the taxi types drivers may register with, out of the
+	// types of the taxi type registry
+	TaxiTypes []domain.TaxiType `json:"taxiTypes,omitempty"`
+	// FieldPatterns are regular expressions fields must match when set
+	FieldPatterns map[string]string `json:"fieldPatterns,omitempty"`
+}
+
+// Ruleset is the effective rules of one country or tenant
+type Ruleset struct {
+	Country        string            `json:"country" example:"TR"`
+	Tenant         string            `json:"tenant,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	PlatePattern   string            `json:"platePattern,omitempty" example:"^[A-Z]{2}[0-9]{3}[A-Z]{2}$"`
+	PlateExample   string            `json:"plateExample" example:"34ABC123"`
+	RequiredFields []string          `json:"requiredFields" example:"firstName,lastName,carBrand,carModel"`
+	TaxiTypes      []domain.TaxiType `json:"taxiTypes" example:"sari,turkuaz,siyah"`
+	FieldPatterns  map[string]string `json:"fieldPatterns,omitempty"`
+
+	plate    *regexp.Regexp
+	patterns map[string]*regexp.Regexp
+	required map[string]bool
+}
+
+// Effective lists the compiled rules of every country and tenant
+type Effective struct {
+	// Country is the market the service runs in; Default holds its rules
+	Country   string              `json:"country" example:"TR"`
+	Default   *Ruleset            `json:"default"`
+	Countries map[string]*Ruleset `json:"countries"`
+	Tenants   map[string]*Ruleset `json:"tenants"`
+}
+
+// Engine resolves the rules that apply to a driver
+type Engine struct {
+	country   string
+	countries map[string]*Ruleset
+	tenants   map[string]*Ruleset
+}
+
+// builtin are the rules the service had before they became configurable.
+// Every taxi type of the registry is allowed.
+func builtin() Rules {
+	return Rules{
+		PlateExample:   "34ABC123",
+		RequiredFields: []string{FieldFirstName, FieldLastName, FieldCarBrand, FieldCarModel},
+		TaxiTypes:      domain.TaxiTypes(),
+	}
+}
+
+// Default returns an engine applying the built-in Turkish rules to everyone
+func Default() *Engine {
+	engine, _ := New(Config{})
+	return engine
+}
+
+// Load reads the rules file at path; an empty path uses the built-in rules.
+// A non-empty country overrides the one in the file.
+func Load(path, country string) (*Engine, error) {
+	var cfg Config
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read validation rules: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse validation rules %s: %w", path, err)
+		}
+	}
+	if country != "" {
+		cfg.Country = country
+	}
+	return New(cfg)
+}
+
+// New compiles the rules of every country and tenant in cfg
+func New(cfg Config) (*Engine, error) {
+	e := &Engine{
+		country:   strings.ToUpper(cfg.Country),
+		countries: make(map[string]*Ruleset),
+		tenants:   make(map[string]*Ruleset, len(cfg.Tenants)),
+	}
+	if e.country == "" {
+		e.country = DefaultCountry
+	}
+
+	countries := make(map[string]Rules, len(cfg.Countries)+1)
+	for code, rules := range cfg.Countries {
+		countries[strings.ToUpper(code)] = rules
+	}
+	if _, ok := countries[e.country]; !ok {
+		countries[e.country] = Rules{}
+	}
+	for code, rules := range countries {
+		merged := merge(builtin(), rules)
+		ruleset, err := compile(code, "", merged)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validation rules for country %s: %w", code, err)
+		}
+		e.countries[code] = ruleset
+	}
+
+	for tenant, rules := range cfg.Tenants {
+		code := strings.ToUpper(rules.Country)
+		if code == "" {
+			code = e.country
+		}
+		country, ok := countries[code]
+		if !ok {
+			return nil, fmt.Errorf("invalid validation rules for tenant %s: unknown country %s", tenant, code)
+		}
+		ruleset, err := compile(code, tenant, merge(merge(builtin(), country), rules))
+		if err != nil {
+			return nil, fmt.Errorf("invalid validation rules for tenant %s: %w", tenant, err)
+		}
+		e.tenants[tenant] = ruleset
+	}
+	return e, nil
+}
+
+// merge overlays the rules set in override onto base
+func merge(base, override Rules) Rules {
+	if override.PlatePattern != "" {
+		base.PlatePattern = override.PlatePattern
+		// An example of the old format would only mislead
+		base.PlateExample = ""
+	}
+	if override.PlateExample != "" {
+		base.PlateExample = override.PlateExample
+	}
+	if len(override.RequiredFields) > 0 {
+		base.RequiredFields = override.RequiredFields
+	}
+	if len(override.TaxiTypes) > 0 {
+		base.TaxiTypes = override.TaxiTypes
+	}
+	if len(override.FieldPatterns) > 0 {
+		patterns := make(map[string]string, len(base.FieldPatterns)+len(override.FieldPatterns))
+		for field, pattern := range base.FieldPatterns {
+			patterns[field] = pattern
+		}
+		for field, pattern := range override.FieldPatterns {
+			patterns[field] = pattern
+		}
+		base.FieldPatterns = patterns
+	}
+	return base
+}
+
+// compile checks the merged rules and compiles their patterns
+func compile(country, tenant string, rules Rules) (*Ruleset, error) {
+	r := &Ruleset{
+		Country:        country,
+		Tenant:         tenant,
+		PlatePattern:   rules.PlatePattern,
+		PlateExample:   rules.PlateExample,
+		RequiredFields: rules.RequiredFields,
+		TaxiTypes:      rules.TaxiTypes,
+		FieldPatterns:  rules.FieldPatterns,
+		patterns:       make(map[string]*regexp.Regexp, len(rules.FieldPatterns)),
+		required:       make(map[string]bool, len(rules.RequiredFields)),
+	}
+
+	if rules.PlatePattern != "" {
+		var err error
+		if r.plate, err = regexp.Compile(rules.PlatePattern); err != nil {
+			return nil, fmt.Errorf("platePattern: %w", err)
+		}
+	}
+	for _, field := range rules.RequiredFields {
+		if !knownFields[field] {
+			return nil, fmt.Errorf("unknown required field %q", field)
+		}
+		r.required[field] = true
+	}
+	for _, taxiType := range rules.TaxiTypes {
+		if !taxiType.IsValid() {
+			return nil, fmt.Errorf("unknown taxi type %q", taxiType)
+		}
+	}
+	for field, pattern := range rules.FieldPatterns {
+		if !knownFields[field] || field == FieldLicense {
+			return nil, fmt.Errorf("fieldPatterns: %q cannot have a pattern", field)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("fieldPatterns.%s: %w", field, err)
+		}
+		r.patterns[field] = re
+	}
+	return r, nil
+}
+
+// For returns the rules for a driver of the given tenant (fleet ID). Drivers
+// outside a tenant with rules of its own get the service country's rules.
+func (e *Engine) For(tenant string) *Ruleset {
+	if r, ok := e.tenants[tenant]; ok && tenant != "" {
+		return r
+	}
+	return e.countries[e.country]
+}
+
+// Effective returns every compiled ruleset, for operators to inspect
+func (e *Engine) Effective() Effective {
+	return Effective{
+		Country:   e.country,
+		Default:   e.countries[e.country],
+		Countries: e.countries,
+		Tenants:   e.tenants,
+	}
+}
+
+// Requires reports whether a new driver must have the field
+func (r *Ruleset) Requires(field string) bool {
+	return r.required[field]
+}
+
+// Check validates a new driver's field: required fields must be set, and set
+// fields must match their pattern
+func (r *Ruleset) Check(field, value string) error {
+	if value == "" && r.required[field] {
+		return &ValidationError{Field: field, Message: field + " is required"}
+	}
+	return r.CheckFormat(field, value)
+}
+
+// CheckFormat validates a set field against its pattern
+func (r *Ruleset) CheckFormat(field, value string) error {
+	if re, ok := r.patterns[field]; ok && value != "" && !re.MatchString(value) {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("%s does not match the %s format", field, r.Country)}
+	}
+	return nil
+}
+
+// NormalizePlate validates a plate and returns it in its canonical form.
+// Without a plate pattern the Turkish format applies and its errors are
+// returned as they are.
+func (r *Ruleset) NormalizePlate(s string) (string, error) {
+	if r.plate == nil {
+		return plate.Normalize(s)
+	}
+
+	compact := strings.Map(func(c rune) rune {
+		if c == ' ' || c == '-' || c == '.' {
+			return -1
+		}
+		return c
+	}, strings.ToUpper(strings.TrimSpace(s)))
+	if compact == "" {
+		return "", &ValidationError{Field: "plate", Message: "plate is required"}
+	}
+	if !r.plate.MatchString(compact) {
+		message := fmt.Sprintf("plate must be in the %s format", r.Country)
+		if r.PlateExample != "" {
+			message += " (e.g., " + r.PlateExample + ")"
+		}
+		return "", &ValidationError{Field: "plate", Message: message}
+	}
+	return compact, nil
+}
+
+// CheckTaxiType validates that drivers may register with the taxi type
+func (r *Ruleset) CheckTaxiType(taxiType domain.TaxiType) error {
+	for _, allowed := range r.TaxiTypes {
+		if taxiType == allowed {
+			return nil
+		}
+	}
+	names := make([]string, len(r.TaxiTypes))
+	for i, allowed := range r.TaxiTypes {
+		names[i] = string(allowed)
+	}
+	return &ValidationError{
+		Field:   "taxiType",
+		Message: fmt.Sprintf("invalid taxiType: %s. Must be one of: %s", taxiType, strings.Join(names, ", ")),
+	}
+}
@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/plate"
+)
+
+func testEngine(t *testing.T) *Engine {
+	t.Helper()
+	engine, err := New(Config{
+		Country: "tr",
+		Countries: map[string]Rules{
+			"AZ": {
+				PlatePattern:   `^[0-9]{2}[A-Z]{2}[0-9]{3}$`,
+				PlateExample:   "10AB123",
+				RequiredFields: []string{FieldFirstName, FieldLastName, FieldPhone},
+				TaxiTypes:      []domain.TaxiType{domain.TaxiTypeSari},
+				FieldPatterns:  map[string]string{FieldPhone: `^\+994`},
+			},
+		},
+		Tenants: map[string]Rules{
+			"fleet-az":    {Country: "AZ", RequiredFields: []string{FieldFirstName, FieldLastName, FieldPhone, FieldLicense}},
+			"fleet-local": {TaxiTypes: []domain.TaxiType{domain.TaxiTypeSiyah}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return engine
+}
+
+func TestEngine_Default(t *testing.T) {
+	r := Default().For("any-fleet")
+
+	if r.Country != DefaultCountry {
+		t.Errorf("expected %s, got %s", DefaultCountry, r.Country)
+	}
+	normalized, err := r.NormalizePlate("34 abc 123")
+	if err != nil || normalized != "34ABC123" {
+		t.Errorf("expected 34ABC123, got %q (%v)", normalized, err)
+	}
+	if _, err := r.NormalizePlate("99AB123"); !errors.As(err, new(*plate.ValidationError)) {
+		t.Errorf("expected the Turkish plate error, got %v", err)
+	}
+	if err := r.Check(FieldCarModel, ""); err == nil || err.Error() != "carModel is required" {
+		t.Errorf("expected carModel to be required, got %v", err)
+	}
+	if err := r.Check(FieldPhone, ""); err != nil {
+		t.Errorf("expected phone to be optional, got %v", err)
+	}
+	if err := r.CheckTaxiType("limo"); err == nil || err.Error() != "invalid taxiType: limo. Must be one of: sari, turkuaz, siyah" {
+		t.Errorf("unexpected taxi type error %v", err)
+	}
+}
+
+func TestEngine_RegisteredTaxiTypes(t *testing.T) {
+	builtin := domain.TaxiTypes()
+	domain.SetTaxiTypes([]domain.TaxiType{domain.TaxiTypeSari, "vip", "xl"})
+	defer domain.SetTaxiTypes(builtin)
+
+	r := Default().For("any-fleet")
+	if err := r.CheckTaxiType("xl"); err != nil {
+		t.Errorf("expected a registered type to be allowed, got %v", err)
+	}
+	if err := r.CheckTaxiType(domain.TaxiTypeSiyah); err == nil || err.Error() != "invalid taxiType: siyah. Must be one of: sari, vip, xl" {
+		t.Errorf("unexpected taxi type error %v", err)
+	}
+
+	// Rules may only name registered types
+	if _, err := New(Config{Tenants: map[string]Rules{"fleet-1": {TaxiTypes: []domain.TaxiType{"limo"}}}}); err == nil {
+		t.Error("expected an error for an unregistered taxi type")
+	}
+}
+
+func TestEngine_Inheritance(t *testing.T) {
+	engine := testEngine(t)
+
+	az := engine.For("fleet-az")
+	if az.Country != "AZ" || az.Tenant != "fleet-az" {
+		t.Fatalf("expected the AZ tenant rules, got %s/%s", az.Country, az.Tenant)
+	}
+	// Plate format and taxi types come from the country, required fields from the tenant
+	if normalized, err := az.NormalizePlate("10-ab-123"); err != nil || normalized != "10AB123" {
+		t.Errorf("expected 10AB123, got %q (%v)", normalized, err)
+	}
+	var validationErr *ValidationError
+	if _, err := az.NormalizePlate("34ABC123"); !errors.As(err, &validationErr) || validationErr.Field != "plate" {
+		t.Errorf("expected a plate validation error, got %v", err)
+	} else if validationErr.Message != "plate must be in the AZ format (e.g., 10AB123)" {
+		t.Errorf("unexpected message %q", validationErr.Message)
+	}
+	if !az.Requires(FieldLicense) || az.Requires(FieldCarBrand) {
+		t.Errorf("expected the tenant's required fields, got %v", az.RequiredFields)
+	}
+	if err := az.CheckTaxiType(domain.TaxiTypeSiyah); err == nil {
+		t.Error("expected siyah to be rejected in AZ")
+	}
+	if err := az.Check(FieldPhone, "+905321234567"); err == nil {
+		t.Error("expected a Turkish phone number to fail the AZ pattern")
+	}
+	if err := az.Check(FieldPhone, "+994501234567"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// Tenants without a country use the service country
+	local := engine.For("fleet-local")
+	if local.Country != "TR" || local.PlateExample != "34ABC123" || !local.Requires(FieldCarModel) {
+		t.Errorf("expected TR rules for fleet-local, got %+v", local)
+	}
+	if err := local.CheckTaxiType(domain.TaxiTypeSari); err == nil {
+		t.Error("expected sari to be rejected for fleet-local")
+	}
+
+	if engine.For("") != engine.For("unknown") {
+		t.Error("expected drivers outside a configured tenant to share the country rules")
+	}
+	effective := engine.Effective()
+	if effective.Country != "TR" || len(effective.Countries) != 2 || len(effective.Tenants) != 2 {
+		t.Errorf("unexpected effective rules %+v", effective)
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "bad plate pattern", cfg: Config{Countries: map[string]Rules{"AZ": {PlatePattern: "("}}}},
+		{name: "unknown required field", cfg: Config{Countries: map[string]Rules{"AZ": {RequiredFields: []string{"nickname"}}}}},
+		{name: "unknown taxi type", cfg: Config{Countries: map[string]Rules{"AZ": {TaxiTypes: []domain.TaxiType{"limo"}}}}},
+		{name: "pattern on license", cfg: Config{Countries: map[string]Rules{"AZ": {FieldPatterns: map[string]string{FieldLicense: "."}}}}},
+		{name: "tenant in unknown country", cfg: Config{Tenants: map[string]Rules{"fleet-1": {Country: "DE"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	data := `{"country":"AZ","countries":{"AZ":{"platePattern":"^[0-9]{2}[A-Z]{2}[0-9]{3}$"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write rules: %v", err)
+	}
+
+	engine, err := Load(path, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := engine.For(""); r.Country != "AZ" || r.PlatePattern == "" {
+		t.Errorf("expected the AZ rules from the file, got %+v", r)
+	}
+
+	// The configured country wins over the file's
+	engine, err = Load(path, "TR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := engine.For(""); r.Country != "TR" || r.PlatePattern != "" {
+		t.Errorf("expected the TR rules, got %+v", r)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,161 @@
+// Package seed generates realistic synthetic drivers for load and search testing.
+//
+// Drivers are spread uniformly over a disc around a centre point, carry valid
+// Turkish plates from the centre's province and follow the taxi type mix seen
+// in Istanbul. The same seed always produces the same drivers.
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// earthRadiusKm matches the radius used by the haversine package
+const earthRadiusKm = 6371.0
+
+// plateLetters is the Turkish plate alphabet
+const plateLetters = "ABCDEFGHIJKLMNOPRSTUVYZ"
+
+var (
+	firstNames = []string{
+		"Ahmet", "Mehmet", "Mustafa", "Ali", "Hüseyin", "Hasan", "İbrahim", "Murat", "Emre", "Burak",
+		"Serkan", "Volkan", "Kemal", "Yusuf", "Osman", "Ayşe", "Fatma", "Elif", "Zeynep", "Selin",
+	}
+	lastNames = []string{
+		"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Yıldırım", "Öztürk", "Aydın", "Özdemir",
+		"Arslan", "Doğan", "Kılıç", "Aslan", "Çetin", "Kara", "Koç", "Kurt", "Özkan", "Şimşek",
+	}
+	cars = []struct{ brand, model string }{
+		{"Fiat", "Doblo"}, {"Fiat", "Egea"}, {"Renault", "Clio"}, {"Renault", "Fluence"},
+		{"Toyota", "Corolla"}, {"Hyundai", "i20"}, {"Dacia", "Lodgy"}, {"Volkswagen", "Caddy"},
+	}
+	premiumCars = []struct{ brand, model string }{
+		{"Mercedes-Benz", "Vito"}, {"Mercedes-Benz", "E-Class"}, {"Volkswagen", "Caravelle"},
+	}
+)
+
+// Options configures a Generator
+type Options struct {
+	Center   domain.Location
+	RadiusKm float64
+	// Seed makes the generated drivers reproducible
+	Seed int64
+	// Province is the plate province code; 34 (Istanbul) when zero
+	Province int
+	// AvailableRatio is the share of drivers on shift, between 0 and 1
+	AvailableRatio float64
+	// Now stamps createdAt and the heartbeat of available drivers; time.Now when zero
+	Now time.Time
+}
+
+// Generator produces synthetic drivers
+type Generator struct {
+	opts Options
+	rng  *rand.Rand
+	n    int
+}
+
+// NewGenerator creates a generator for the given options
+func NewGenerator(opts Options) *Generator {
+	if opts.Province == 0 {
+		opts.Province = 34
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return &Generator{
+		opts: opts,
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+	}
+}
+
+// Next returns the next synthetic driver
+func (g *Generator) Next() *domain.Driver {
+	g.n++
+	taxiType := g.taxiType()
+	car := cars[g.rng.Intn(len(cars))]
+	if taxiType == domain.TaxiTypeSiyah {
+		car = premiumCars[g.rng.Intn(len(premiumCars))]
+	}
+
+	driver := &domain.Driver{
+		FirstName:     firstNames[g.rng.Intn(len(firstNames))],
+		LastName:      lastNames[g.rng.Intn(len(lastNames))],
+		Plate:         g.plate(),
+		TaxiType:      taxiType,
+		CarBrand:      car.brand,
+		CarModel:      car.model,
+		Location:      g.location(),
+		Phone:         fmt.Sprintf("+905%02d%07d", 30+g.rng.Intn(30), g.n%10000000),
+		PhoneVerified: true,
+		Available:     g.rng.Float64() < g.opts.AvailableRatio,
+		Rating:        math.Round((3.5+g.rng.Float64()*1.5)*10) / 10,
+		RatingCount:   g.rng.Intn(2000),
+		License: &domain.DriverLicense{
+			Number:    fmt.Sprintf("%06d", g.rng.Intn(1000000)),
+			Class:     domain.LicenseClass("B"),
+			ExpiresAt: g.opts.Now.AddDate(1+g.rng.Intn(9), 0, 0).UTC().Truncate(24 * time.Hour),
+		},
+		CreatedAt: g.opts.Now,
+		UpdatedAt: g.opts.Now,
+	}
+	located := g.opts.Now
+	driver.LocationUpdatedAt = &located
+	if driver.Available {
+		seen := g.opts.Now
+		driver.LastSeenAt = &seen
+	}
+	return driver
+}
+
+// taxiType picks a taxi type with Istanbul's rough mix: most taxis are yellow
+// (sari), about a fifth turquoise and a few black
+func (g *Generator) taxiType() domain.TaxiType {
+	switch p := g.rng.Float64(); {
+	case p < 0.75:
+		return domain.TaxiTypeSari
+	case p < 0.95:
+		return domain.TaxiTypeTurkuaz
+	default:
+		return domain.TaxiTypeSiyah
+	}
+}
+
+// plate returns a valid plate of the configured province: two letters and
+// three or four digits most of the time, otherwise three letters and two or
+// three digits
+func (g *Generator) plate() string {
+	letters := 2
+	if g.rng.Float64() < 0.3 {
+		letters = 3
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%02d", g.opts.Province)
+	for i := 0; i < letters; i++ {
+		b.WriteByte(plateLetters[g.rng.Intn(len(plateLetters))])
+	}
+	digits := 5 - letters + g.rng.Intn(2)
+	fmt.Fprintf(&b, "%0*d", digits, 1+g.rng.Intn(int(math.Pow10(digits))-1))
+	return b.String()
+}
+
+// location returns a point distributed uniformly over the disc around the centre
+func (g *Generator) location() domain.Location {
+	distance := g.opts.RadiusKm * math.Sqrt(g.rng.Float64()) / earthRadiusKm
+	bearing := g.rng.Float64() * 2 * math.Pi
+
+	lat1 := g.opts.Center.Lat * math.Pi / 180
+	lon1 := g.opts.Center.Lon * math.Pi / 180
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(distance) + math.Cos(lat1)*math.Sin(distance)*math.Cos(bearing))
+	lon2 := lon1 + math.Atan2(math.Sin(bearing)*math.Sin(distance)*math.Cos(lat1), math.Cos(distance)-math.Sin(lat1)*math.Sin(lat2))
+
+	return domain.Location{
+		Lat: math.Round(lat2*180/math.Pi*1e6) / 1e6,
+		Lon: math.Round(lon2*180/math.Pi*1e6) / 1e6,
+	}
+}
@@ -0,0 +1,80 @@
+package seed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/haversine"
+	"github.com/bitaksi/driver-service/pkg/plate"
+)
+
+func testOptions() Options {
+	return Options{
+		Center:         domain.Location{Lat: 41.01, Lon: 28.97},
+		RadiusKm:       30,
+		Seed:           42,
+		AvailableRatio: 0.6,
+		Now:            time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestGenerator_Deterministic(t *testing.T) {
+	a, b := NewGenerator(testOptions()), NewGenerator(testOptions())
+	for i := 0; i < 100; i++ {
+		if x, y := a.Next(), b.Next(); !reflect.DeepEqual(x, y) {
+			t.Fatalf("driver %d differs between generators with the same seed: %+v vs %+v", i, x, y)
+		}
+	}
+
+	opts := testOptions()
+	opts.Seed = 43
+	if reflect.DeepEqual(NewGenerator(testOptions()).Next(), NewGenerator(opts).Next()) {
+		t.Error("expected another seed to produce other drivers")
+	}
+}
+
+func TestGenerator_RealisticDrivers(t *testing.T) {
+	opts := testOptions()
+	g := NewGenerator(opts)
+	counts := map[domain.TaxiType]int{}
+	phones := map[string]bool{}
+	available := 0
+	const n = 5000
+
+	for i := 0; i < n; i++ {
+		driver := g.Next()
+		counts[driver.TaxiType]++
+
+		if normalized, err := plate.Normalize(driver.Plate); err != nil || normalized != driver.Plate || driver.Plate[:2] != "34" {
+			t.Fatalf("invalid plate %q: %v", driver.Plate, err)
+		}
+		if d := haversine.Distance(opts.Center.Lat, opts.Center.Lon, driver.Location.Lat, driver.Location.Lon); d > opts.RadiusKm+0.01 {
+			t.Fatalf("driver placed %.2f km from the centre, outside the %.0f km radius", d, opts.RadiusKm)
+		}
+		if phones[driver.Phone] {
+			t.Fatalf("duplicate phone %s", driver.Phone)
+		}
+		phones[driver.Phone] = true
+		if !driver.License.Class.IsValid() || driver.License.Expired(opts.Now) {
+			t.Fatalf("expected a valid licence, got %+v", driver.License)
+		}
+		if driver.Available {
+			available++
+			if driver.LastSeenAt == nil {
+				t.Fatal("expected available drivers to have a heartbeat")
+			}
+		}
+	}
+
+	if share := float64(counts[domain.TaxiTypeSari]) / n; share < 0.7 || share > 0.8 {
+		t.Errorf("expected about 75%% sari taxis, got %.2f", share)
+	}
+	if counts[domain.TaxiTypeTurkuaz] == 0 || counts[domain.TaxiTypeSiyah] == 0 {
+		t.Errorf("expected every taxi type, got %v", counts)
+	}
+	if share := float64(available) / n; share < 0.55 || share > 0.65 {
+		t.Errorf("expected about 60%% available drivers, got %.2f", share)
+	}
+}
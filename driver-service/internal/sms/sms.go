@@ -0,0 +1,85 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Provider sends text messages to phone numbers
+type Provider interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// LogProvider writes messages to the log instead of sending them (development only)
+type LogProvider struct {
+	logger *zap.Logger
+}
+
+// NewLogProvider creates a provider that only logs messages
+func NewLogProvider(logger *zap.Logger) *LogProvider {
+	return &LogProvider{logger: logger}
+}
+
+// Send logs the message
+func (p *LogProvider) Send(ctx context.Context, to, message string) error {
+	p.logger.Info("sms message", zap.String("to", to), zap.String("message", message))
+	return nil
+}
+
+// HTTPProvider posts messages as JSON to an SMS gateway endpoint
+type HTTPProvider struct {
+	url        string
+	apiKey     string
+	sender     string
+	httpClient *http.Client
+}
+
+// NewHTTPProvider creates a provider backed by a generic HTTP SMS gateway
+func NewHTTPProvider(url, apiKey, sender string) *HTTPProvider {
+	return &HTTPProvider{
+		url:    url,
+		apiKey: apiKey,
+		sender: sender,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send delivers the message through the HTTP gateway
+func (p *HTTPProvider) Send(ctx context.Context, to, message string) error {
+	payload, err := json.Marshal(map[string]string{
+		"from":    p.sender,
+		"to":      to,
+		"message": message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sms payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create sms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package storage keeps uploaded files and returns the URLs they are served
+// from. The local store writes to a directory the service serves itself; the
+// base URL can point at a CDN in front of it instead.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Provider names accepted by NewStore
+const (
+	ProviderLocal = "local"
+)
+
+// ErrInvalidKey is returned for keys that are empty or leave the store
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Store saves files under slash-separated keys such as "drivers/1/photo.jpg"
+type Store interface {
+	// Put saves data under key, replacing any existing file, and returns its public URL
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete removes the file under key; deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Options configures the stores
+type Options struct {
+	// Dir is where the local store writes files
+	Dir string
+	// BaseURL is prepended to keys to build public URLs, e.g. https://cdn.bitaksi.com/media
+	BaseURL string
+}
+
+// NewStore creates the store with the given name
+func NewStore(name string, opts Options) (Store, error) {
+	switch name {
+	case ProviderLocal, "":
+		if opts.Dir == "" {
+			return nil, fmt.Errorf("a directory is required for the %s storage provider", ProviderLocal)
+		}
+		return NewLocalStore(opts.Dir, opts.BaseURL), nil
+	default:
+		return nil, fmt.Errorf("unknown storage provider %q", name)
+	}
+}
+
+// LocalStore writes files to a directory on disk
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore creates a store writing under dir and serving from baseURL
+func NewLocalStore(dir, baseURL string) *LocalStore {
+	return &LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put writes the file through a temporary file so readers never see it half written
+func (s *LocalStore) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+// Delete removes the file
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// path maps a key to a file inside the store's directory
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore_PutAndDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := NewLocalStore(dir, "https://cdn.example.com/media/")
+	ctx := context.Background()
+
+	url, err := store.Put(ctx, "drivers/1/photo.jpg", "image/jpeg", []byte("jpeg"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if url != "https://cdn.example.com/media/drivers/1/photo.jpg" {
+		t.Errorf("unexpected url %q", url)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "drivers", "1", "photo.jpg"))
+	if err != nil || string(data) != "jpeg" {
+		t.Fatalf("expected file to be written, got %q, %v", data, err)
+	}
+
+	if err := store.Delete(ctx, "drivers/1/photo.jpg"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "drivers", "1", "photo.jpg")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected file to be removed, got %v", err)
+	}
+	if err := store.Delete(ctx, "drivers/1/photo.jpg"); err != nil {
+		t.Errorf("deleting a missing file should succeed, got %v", err)
+	}
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	store := NewLocalStore(t.TempDir(), "")
+
+	for _, key := range []string{"", "../escape.jpg", "drivers/../../escape.jpg", "/absolute.jpg", "drivers//photo.jpg"} {
+		if _, err := store.Put(context.Background(), key, "image/jpeg", nil); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("key %q: expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	if _, err := NewStore(ProviderLocal, Options{}); err == nil {
+		t.Error("expected an error without a directory")
+	}
+	if _, err := NewStore("s3", Options{Dir: t.TempDir()}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if _, err := NewStore(ProviderLocal, Options{Dir: t.TempDir()}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Package taxitype is the registry of the taxi types the service runs. Markets
+// add products such as "vip" or "xl" by listing them in a taxi types file
+// instead of changing code; without one the built-in Turkish types are used.
+// Clients read the registry to show riders and drivers the types and what
+// they offer.
+package taxitype
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+//go:embed taxi_types.json
+var builtinTaxiTypes []byte
+
+// maxCapacity bounds the seats a taxi type may list
+const maxCapacity = 20
+
+// Config is the taxi types file
+type Config struct {
+	TaxiTypes []Type `json:"taxiTypes"`
+}
+
+// Type is a taxi type and what clients show for it
+type Type struct {
+	// ID is what drivers, searches and trips refer to the type by
+	ID   domain.TaxiType `json:"id" example:"sari"`
+	Name string          `json:"name" example:"Sarı Taksi"`
+	// Description is an optional line shown under the name
+	Description string `json:"description,omitempty" example:"Standard city taxi"`
+	// Capacity is the number of passenger seats
+	Capacity int `json:"capacity" example:"4"`
+	// Icon is the name of the icon clients draw the type with
+	Icon string `json:"icon,omitempty" example:"taxi-yellow"`
+}
+
+// Catalog lists the taxi types clients may offer
+type Catalog struct {
+	TaxiTypes []Type `json:"taxiTypes"`
+}
+
+// Registry holds the taxi types, in the order they are listed
+type Registry struct {
+	types []Type
+	byID  map[domain.TaxiType]Type
+}
+
+// New creates a registry. IDs must be lowercase words joined by dashes, e.g.
+// "xl", and every type needs a name and 1-20 seats.
+func New(types []Type) (*Registry, error) {
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no taxi types")
+	}
+	r := &Registry{types: types, byID: make(map[domain.TaxiType]Type, len(types))}
+	for _, t := range types {
+		if id, ok := domain.NormalizeTag(string(t.ID)); !ok || id != string(t.ID) {
+			return nil, fmt.Errorf("invalid taxi type id %q", t.ID)
+		}
+		if _, ok := r.byID[t.ID]; ok {
+			return nil, fmt.Errorf("taxi type %q is listed twice", t.ID)
+		}
+		if t.Name == "" {
+			return nil, fmt.Errorf("taxi type %q has no name", t.ID)
+		}
+		if t.Capacity < 1 || t.Capacity > maxCapacity {
+			return nil, fmt.Errorf("taxi type %q: capacity must be between 1 and %d", t.ID, maxCapacity)
+		}
+		r.byID[t.ID] = t
+	}
+	return r, nil
+}
+
+// Load creates a registry from the taxi types file at path; an empty path
+// uses the built-in types
+func Load(path string) (*Registry, error) {
+	data := builtinTaxiTypes
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read taxi types: %w", err)
+		}
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse taxi types %s: %w", path, err)
+	}
+	r, err := New(cfg.TaxiTypes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid taxi types %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// IDs returns the IDs of the types, in the order they are listed
+func (r *Registry) IDs() []domain.TaxiType {
+	ids := make([]domain.TaxiType, len(r.types))
+	for i, t := range r.types {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// Get returns the type with the given ID
+func (r *Registry) Get(id domain.TaxiType) (Type, bool) {
+	t, ok := r.byID[id]
+	return t, ok
+}
+
+// Catalog lists the types among allowed, or every type when allowed is nil
+func (r *Registry) Catalog(allowed []domain.TaxiType) Catalog {
+	if allowed == nil {
+		return Catalog{TaxiTypes: append([]Type(nil), r.types...)}
+	}
+	catalog := Catalog{TaxiTypes: []Type{}}
+	for _, id := range allowed {
+		if t, ok := r.byID[id]; ok {
+			catalog.TaxiTypes = append(catalog.TaxiTypes, t)
+		}
+	}
+	return catalog
+}
@@ -0,0 +1,80 @@
+package taxitype
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+func TestBuiltinTaxiTypes(t *testing.T) {
+	registry, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []domain.TaxiType{domain.TaxiTypeSari, domain.TaxiTypeTurkuaz, domain.TaxiTypeSiyah}
+	ids := registry.IDs()
+	if len(ids) != len(want) {
+		t.Fatalf("IDs() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("IDs()[%d] = %q, want %q", i, ids[i], want[i])
+		}
+	}
+	if sari, ok := registry.Get(domain.TaxiTypeSari); !ok || sari.Capacity != 4 || sari.Icon == "" {
+		t.Errorf("Get(sari) = %+v, %v", sari, ok)
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "taxi_types.json")
+	data := `{"taxiTypes": [
+		{"id": "sari", "name": "Sarı Taksi", "capacity": 4},
+		{"id": "vip", "name": "VIP", "capacity": 3, "icon": "taxi-vip"},
+		{"id": "xl", "name": "XL", "description": "Room for luggage", "capacity": 7}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if xl, ok := registry.Get("xl"); !ok || xl.Capacity != 7 {
+		t.Errorf("Get(xl) = %+v, %v", xl, ok)
+	}
+	if _, ok := registry.Get(domain.TaxiTypeSiyah); ok {
+		t.Error("expected the file to replace the built-in types")
+	}
+
+	if got := registry.Catalog(nil).TaxiTypes; len(got) != 3 || got[1].ID != "vip" {
+		t.Errorf("Catalog(nil) = %+v", got)
+	}
+	if got := registry.Catalog([]domain.TaxiType{"xl", "limo"}).TaxiTypes; len(got) != 1 || got[0].ID != "xl" {
+		t.Errorf("Catalog(xl, limo) = %+v", got)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := map[string][]Type{
+		"no types":       nil,
+		"invalid id":     {{ID: "V I P", Name: "VIP", Capacity: 3}},
+		"uppercase id":   {{ID: "VIP", Name: "VIP", Capacity: 3}},
+		"listed twice":   {{ID: "xl", Name: "XL", Capacity: 7}, {ID: "xl", Name: "XL", Capacity: 7}},
+		"no name":        {{ID: "xl", Capacity: 7}},
+		"no seats":       {{ID: "xl", Name: "XL"}},
+		"too many seats": {{ID: "bus", Name: "Bus", Capacity: 50}},
+	}
+	for name, types := range tests {
+		if _, err := New(types); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
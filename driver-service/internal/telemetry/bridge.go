@@ -0,0 +1,363 @@
+// Package telemetry bridges driver devices that speak MQTT to the location and
+// heartbeat use cases.
+//
+// Devices publish to <prefix>/<driverId>/location the JSON
+// {"token":"dtk_...","lat":41.0431,"lon":29.0099} and to
+// <prefix>/<driverId>/heartbeat {"token":"dtk_..."}. The token is the
+// driver's device token; it must belong to the driver in the topic and carry
+// the scope of the message, as on the HTTP routes.
+//
+// Messages are handled in the order they arrive. With QoS 1 or 2 a message is
+// acknowledged once it was applied or rejected for good; messages that hit a
+// transient error, such as a database failover, are left unacknowledged so the
+// broker redelivers them to the persistent session after a reconnect.
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/cache"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.uber.org/zap"
+)
+
+// Reasons messages are rejected for
+const (
+	RejectTopic   = "topic"
+	RejectPayload = "payload"
+	RejectToken   = "token"
+	RejectScope   = "scope"
+	RejectInvalid = "invalid"
+)
+
+// authCacheSize bounds the verified device tokens kept in memory
+const authCacheSize = 100000
+
+// handleTimeout bounds the work done for one message
+const handleTimeout = 10 * time.Second
+
+// Drivers applies location reports
+type Drivers interface {
+	UpdateDriver(ctx context.Context, id string, req *usecase.UpdateDriverRequest) (*domain.Driver, error)
+}
+
+// Heartbeats records heartbeats
+type Heartbeats interface {
+	Heartbeat(ctx context.Context, driverID string) error
+}
+
+// Tokens verifies device tokens
+type Tokens interface {
+	Verify(ctx context.Context, token string) (*domain.DeviceToken, error)
+}
+
+// Options configures the bridge
+type Options struct {
+	BrokerURL   string
+	ClientID    string
+	Username    string
+	Password    string
+	TopicPrefix string
+	QoS         byte
+	// AuthCacheTTL is how long a verified token is trusted; zero verifies
+	// every message
+	AuthCacheTTL time.Duration
+}
+
+// message is the payload devices publish
+type message struct {
+	Token string   `json:"token"`
+	Lat   *float64 `json:"lat"`
+	Lon   *float64 `json:"lon"`
+}
+
+// rejection is a message that can never be applied
+type rejection struct {
+	reason string
+	err    error
+}
+
+func (r *rejection) Error() string {
+	return r.reason + ": " + r.err.Error()
+}
+
+func (r *rejection) Unwrap() error {
+	return r.err
+}
+
+func reject(reason string, err error) error {
+	return &rejection{reason: reason, err: err}
+}
+
+// Bridge subscribes to device telemetry and applies it
+type Bridge struct {
+	opts       Options
+	drivers    Drivers
+	heartbeats Heartbeats
+	tokens     Tokens
+	// verified holds tokens that passed verification, by their hash
+	verified *cache.TTL[string, *domain.DeviceToken]
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu    sync.Mutex
+	stats domain.TelemetryStats
+}
+
+// NewBridge creates a bridge that applies telemetry with the given use cases
+func NewBridge(opts Options, drivers Drivers, heartbeats Heartbeats, tokens Tokens, logger *zap.Logger) *Bridge {
+	b := &Bridge{
+		opts:       opts,
+		drivers:    drivers,
+		heartbeats: heartbeats,
+		tokens:     tokens,
+		logger:     logger,
+		now:        time.Now,
+		stats: domain.TelemetryStats{
+			Broker:     opts.BrokerURL,
+			Topics:     []string{opts.TopicPrefix + "/+/" + domain.TelemetryLocation, opts.TopicPrefix + "/+/" + domain.TelemetryHeartbeat},
+			QoS:        int(opts.QoS),
+			Messages:   map[string]domain.TelemetryCounts{},
+			Rejections: map[string]int64{},
+		},
+	}
+	if opts.AuthCacheTTL > 0 {
+		b.verified = cache.NewTTL[string, *domain.DeviceToken](opts.AuthCacheTTL, authCacheSize)
+	}
+	return b
+}
+
+// Run connects to the broker and applies telemetry until ctx is done. The
+// client reconnects on its own and subscribes again on every connect.
+func (b *Bridge) Run(ctx context.Context) {
+	filters := make(map[string]byte, len(b.stats.Topics))
+	for _, topic := range b.stats.Topics {
+		filters[topic] = b.opts.QoS
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(b.opts.BrokerURL).
+		SetClientID(b.opts.ClientID).
+		SetUsername(b.opts.Username).
+		SetPassword(b.opts.Password).
+		// A persistent session keeps unacknowledged messages for redelivery
+		SetCleanSession(false).
+		SetOrderMatters(true).
+		SetAutoAckDisabled(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(5 * time.Second)
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		b.setConnected(true)
+		b.logger.Info("connected to MQTT broker", zap.String("broker", b.opts.BrokerURL))
+		token := client.SubscribeMultiple(filters, func(_ mqtt.Client, msg mqtt.Message) {
+			b.deliver(ctx, msg)
+		})
+		if token.Wait() && token.Error() != nil {
+			b.logger.Error("failed to subscribe to telemetry topics", zap.Error(token.Error()))
+		}
+	})
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		b.setConnected(false)
+		b.logger.Warn("lost connection to MQTT broker", zap.Error(err))
+	})
+	opts.SetReconnectingHandler(func(mqtt.Client, *mqtt.ClientOptions) {
+		b.mu.Lock()
+		b.stats.Reconnects++
+		b.mu.Unlock()
+	})
+
+	client := mqtt.NewClient(opts)
+	// With ConnectRetry the token completes once connected or disconnected
+	client.Connect()
+	<-ctx.Done()
+	client.Disconnect(250)
+	b.setConnected(false)
+}
+
+func (b *Bridge) setConnected(connected bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stats.Connected && !connected {
+		now := b.now().UTC()
+		b.stats.LastDisconnectAt = &now
+	}
+	b.stats.Connected = connected
+}
+
+// deliver handles a message from the broker and acknowledges it unless it
+// should be redelivered
+func (b *Bridge) deliver(ctx context.Context, msg mqtt.Message) {
+	handleCtx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	err := b.Handle(handleCtx, msg.Topic(), msg.Payload())
+	var rejected *rejection
+	switch {
+	case err == nil:
+	case errors.As(err, &rejected):
+		b.logger.Debug("rejected telemetry message", zap.String("topic", msg.Topic()), zap.Error(err))
+	default:
+		b.logger.Warn("failed to apply telemetry message", zap.String("topic", msg.Topic()), zap.Error(err))
+		if msg.Qos() > 0 {
+			return
+		}
+	}
+	msg.Ack()
+}
+
+// Handle applies one message. Messages that can never be applied return an
+// error matching IsRejected; other errors are transient.
+func (b *Bridge) Handle(ctx context.Context, topic string, payload []byte) error {
+	driverID, kind, err := b.parseTopic(topic)
+	if err != nil {
+		b.record("", err)
+		return err
+	}
+	err = b.handle(ctx, driverID, kind, payload)
+	b.record(kind, err)
+	return err
+}
+
+// IsRejected reports whether Handle rejected a message for good
+func IsRejected(err error) bool {
+	var rejected *rejection
+	return errors.As(err, &rejected)
+}
+
+// parseTopic splits <prefix>/<driverId>/<kind>
+func (b *Bridge) parseTopic(topic string) (driverID, kind string, err error) {
+	rest, ok := strings.CutPrefix(topic, b.opts.TopicPrefix+"/")
+	if ok {
+		driverID, kind, ok = strings.Cut(rest, "/")
+	}
+	if !ok || driverID == "" || (kind != domain.TelemetryLocation && kind != domain.TelemetryHeartbeat) {
+		return "", "", reject(RejectTopic, fmt.Errorf("unexpected topic %q", topic))
+	}
+	return driverID, kind, nil
+}
+
+func (b *Bridge) handle(ctx context.Context, driverID, kind string, payload []byte) error {
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return reject(RejectPayload, err)
+	}
+	if msg.Token == "" {
+		return reject(RejectPayload, errors.New("token is required"))
+	}
+
+	scope := domain.ScopeLocationUpdate
+	if kind == domain.TelemetryHeartbeat {
+		scope = domain.ScopeHeartbeat
+	}
+	if err := b.authenticate(ctx, msg.Token, driverID, scope); err != nil {
+		return err
+	}
+	ctx = domain.ContextWithIdentity(ctx, domain.Identity{UserID: driverID, Role: domain.RoleDriverDevice})
+
+	if kind == domain.TelemetryHeartbeat {
+		return classify(b.heartbeats.Heartbeat(ctx, driverID))
+	}
+	if msg.Lat == nil || msg.Lon == nil {
+		return reject(RejectPayload, errors.New("lat and lon are required"))
+	}
+	_, err := b.drivers.UpdateDriver(ctx, driverID, &usecase.UpdateDriverRequest{Lat: msg.Lat, Lon: msg.Lon})
+	return classify(err)
+}
+
+// authenticate checks that the token is active, belongs to the driver and
+// carries the scope
+func (b *Bridge) authenticate(ctx context.Context, presented, driverID, scope string) error {
+	sum := sha256.Sum256([]byte(presented))
+	key := hex.EncodeToString(sum[:])
+
+	var token *domain.DeviceToken
+	if b.verified != nil {
+		token, _ = b.verified.Get(key)
+	}
+	if token == nil {
+		verified, err := b.tokens.Verify(ctx, presented)
+		if err != nil {
+			if errors.Is(err, usecase.ErrInvalidDeviceToken) {
+				return reject(RejectToken, err)
+			}
+			return err
+		}
+		token = verified
+		if b.verified != nil {
+			b.verified.Set(key, token)
+		}
+	}
+
+	if token.DriverID != driverID {
+		return reject(RejectToken, usecase.ErrDriverNotOwned)
+	}
+	if !token.HasScope(scope) {
+		return reject(RejectScope, fmt.Errorf("device token lacks the %s scope", scope))
+	}
+	return nil
+}
+
+// classify rejects use case errors that retrying cannot fix; database and
+// other internal failures stay transient
+func classify(err error) error {
+	if err == nil || errors.Is(err, domain.ErrStoreUnavailable) || strings.HasPrefix(err.Error(), "failed to") {
+		return err
+	}
+	return reject(RejectInvalid, err)
+}
+
+// record counts a handled message
+func (b *Bridge) record(kind string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now().UTC()
+	b.stats.LastMessageAt = &now
+	var rejected *rejection
+	if errors.As(err, &rejected) {
+		b.stats.Rejections[rejected.reason]++
+	}
+	if kind == "" {
+		return
+	}
+
+	counts := b.stats.Messages[kind]
+	counts.Received++
+	switch {
+	case err == nil:
+		counts.Processed++
+	case rejected != nil:
+		counts.Rejected++
+	default:
+		counts.Failed++
+	}
+	b.stats.Messages[kind] = counts
+}
+
+// Stats returns what the bridge received since it started
+func (b *Bridge) Stats() domain.TelemetryStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := b.stats
+	stats.Messages = make(map[string]domain.TelemetryCounts, len(b.stats.Messages))
+	for kind, counts := range b.stats.Messages {
+		stats.Messages[kind] = counts
+	}
+	stats.Rejections = make(map[string]int64, len(b.stats.Rejections))
+	for reason, count := range b.stats.Rejections {
+		stats.Rejections[reason] = count
+	}
+	return stats
+}
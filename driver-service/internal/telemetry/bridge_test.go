@@ -0,0 +1,158 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeDrivers struct {
+	updates []usecase.UpdateDriverRequest
+	err     error
+}
+
+func (f *fakeDrivers) UpdateDriver(ctx context.Context, id string, req *usecase.UpdateDriverRequest) (*domain.Driver, error) {
+	if identity, ok := domain.IdentityFromContext(ctx); !ok || identity.UserID != id || identity.Role != domain.RoleDriverDevice {
+		return nil, errors.New("missing device identity")
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.updates = append(f.updates, *req)
+	return &domain.Driver{ID: id}, nil
+}
+
+type fakeHeartbeats struct {
+	drivers []string
+}
+
+func (f *fakeHeartbeats) Heartbeat(ctx context.Context, driverID string) error {
+	f.drivers = append(f.drivers, driverID)
+	return nil
+}
+
+type fakeTokens struct {
+	tokens map[string]*domain.DeviceToken
+	calls  int
+	err    error
+}
+
+func (f *fakeTokens) Verify(ctx context.Context, token string) (*domain.DeviceToken, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if t, ok := f.tokens[token]; ok {
+		return t, nil
+	}
+	return nil, usecase.ErrInvalidDeviceToken
+}
+
+func newTestBridge(cacheTTL time.Duration) (*Bridge, *fakeDrivers, *fakeHeartbeats, *fakeTokens) {
+	drivers := &fakeDrivers{}
+	heartbeats := &fakeHeartbeats{}
+	tokens := &fakeTokens{tokens: map[string]*domain.DeviceToken{
+		"dtk_ali":      {DriverID: "d1", Scopes: domain.DeviceTokenScopes},
+		"dtk_location": {DriverID: "d1", Scopes: []string{domain.ScopeLocationUpdate}},
+	}}
+	bridge := NewBridge(Options{TopicPrefix: "drivers", QoS: 1, AuthCacheTTL: cacheTTL}, drivers, heartbeats, tokens, zap.NewNop())
+	return bridge, drivers, heartbeats, tokens
+}
+
+func TestBridge_Handle(t *testing.T) {
+	ctx := context.Background()
+	bridge, drivers, heartbeats, _ := newTestBridge(0)
+
+	require.NoError(t, bridge.Handle(ctx, "drivers/d1/location", []byte(`{"token":"dtk_ali","lat":41.0431,"lon":29.0099}`)))
+	require.Len(t, drivers.updates, 1)
+	assert.Equal(t, 41.0431, *drivers.updates[0].Lat)
+	assert.Equal(t, 29.0099, *drivers.updates[0].Lon)
+
+	require.NoError(t, bridge.Handle(ctx, "drivers/d1/heartbeat", []byte(`{"token":"dtk_ali"}`)))
+	assert.Equal(t, []string{"d1"}, heartbeats.drivers)
+
+	rejected := []struct {
+		name    string
+		topic   string
+		payload string
+		reason  string
+	}{
+		{"unknown topic", "drivers/d1/battery", `{"token":"dtk_ali"}`, RejectTopic},
+		{"other prefix", "fleet/d1/location", `{"token":"dtk_ali","lat":41,"lon":29}`, RejectTopic},
+		{"malformed payload", "drivers/d1/location", `lat=41`, RejectPayload},
+		{"missing token", "drivers/d1/location", `{"lat":41,"lon":29}`, RejectPayload},
+		{"missing coordinates", "drivers/d1/location", `{"token":"dtk_ali","lat":41}`, RejectPayload},
+		{"revoked token", "drivers/d1/location", `{"token":"dtk_gone","lat":41,"lon":29}`, RejectToken},
+		{"token of another driver", "drivers/d2/location", `{"token":"dtk_ali","lat":41,"lon":29}`, RejectToken},
+		{"missing scope", "drivers/d1/heartbeat", `{"token":"dtk_location"}`, RejectScope},
+	}
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bridge.Handle(ctx, tt.topic, []byte(tt.payload))
+			require.Error(t, err)
+			assert.True(t, IsRejected(err))
+			assert.Contains(t, err.Error(), tt.reason+": ")
+		})
+	}
+	assert.Len(t, drivers.updates, 1)
+
+	stats := bridge.Stats()
+	assert.Equal(t, []string{"drivers/+/location", "drivers/+/heartbeat"}, stats.Topics)
+	assert.Equal(t, domain.TelemetryCounts{Received: 6, Processed: 1, Rejected: 5}, stats.Messages[domain.TelemetryLocation])
+	assert.Equal(t, domain.TelemetryCounts{Received: 2, Processed: 1, Rejected: 1}, stats.Messages[domain.TelemetryHeartbeat])
+	assert.Equal(t, int64(2), stats.Rejections[RejectTopic])
+	assert.Equal(t, int64(2), stats.Rejections[RejectToken])
+	assert.NotNil(t, stats.LastMessageAt)
+}
+
+func TestBridge_Handle_UseCaseErrors(t *testing.T) {
+	ctx := context.Background()
+	bridge, drivers, _, tokens := newTestBridge(0)
+	payload := []byte(`{"token":"dtk_ali","lat":41,"lon":29}`)
+
+	drivers.err = usecase.ErrImplausibleLocation
+	err := bridge.Handle(ctx, "drivers/d1/location", payload)
+	assert.True(t, IsRejected(err), "a report the use case refuses is dropped")
+	assert.ErrorIs(t, err, usecase.ErrImplausibleLocation)
+
+	drivers.err = domain.ErrStoreUnavailable
+	err = bridge.Handle(ctx, "drivers/d1/location", payload)
+	require.Error(t, err)
+	assert.False(t, IsRejected(err), "a failover is left for redelivery")
+
+	drivers.err = nil
+	tokens.err = errors.New("failed to verify device token")
+	err = bridge.Handle(ctx, "drivers/d1/location", payload)
+	require.Error(t, err)
+	assert.False(t, IsRejected(err))
+
+	assert.Equal(t, domain.TelemetryCounts{Received: 3, Rejected: 1, Failed: 2}, bridge.Stats().Messages[domain.TelemetryLocation])
+}
+
+func TestBridge_AuthCache(t *testing.T) {
+	ctx := context.Background()
+	bridge, drivers, _, tokens := newTestBridge(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, bridge.Handle(ctx, "drivers/d1/location", []byte(`{"token":"dtk_ali","lat":41,"lon":29}`)))
+	}
+	assert.Len(t, drivers.updates, 3)
+	assert.Equal(t, 1, tokens.calls, "verified tokens are reused")
+
+	// A cached token still only reaches its own driver
+	err := bridge.Handle(ctx, "drivers/d2/location", []byte(`{"token":"dtk_ali","lat":41,"lon":29}`))
+	assert.True(t, IsRejected(err))
+
+	// Rejected tokens are checked again every time
+	for i := 0; i < 2; i++ {
+		assert.Error(t, bridge.Handle(ctx, "drivers/d1/location", []byte(`{"token":"dtk_gone","lat":41,"lon":29}`)))
+	}
+	assert.Equal(t, 3, tokens.calls)
+}
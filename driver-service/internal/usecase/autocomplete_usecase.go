@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// AutocompleteUseCase defines the interface for type-ahead search of drivers
+type AutocompleteUseCase interface {
+	Autocomplete(ctx context.Context, query string, filter domain.DriverFilter, limit int) (*AutocompleteResponse, error)
+}
+
+// AutocompleteResponse lists the drivers whose name or plate starts with the query words
+type AutocompleteResponse struct {
+	Suggestions []DriverSuggestion `json:"suggestions"`
+	// TimedOut is set when the search ran past its time budget and returned
+	// no suggestions; the next keystroke's search usually finishes in time
+	TimedOut bool `json:"timedOut" example:"false"`
+}
+
+// DriverSuggestion is a compact driver for autocomplete lists
+type DriverSuggestion struct {
+	ID    string `json:"id" example:"507f1f77bcf86cd799439011"`
+	Name  string `json:"name" example:"Ahmet Demir"`
+	Plate string `json:"plate" example:"34ABC123"`
+	// Highlights mark the parts of the name and plate the query matched
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchHighlight marks a matched part of a suggestion field. Start and
+// Length count characters, which are also UTF-16 code units for the letters
+// of Turkish names and plates.
+type SearchHighlight struct {
+	// Field is name or plate
+	Field  string `json:"field" example:"name"`
+	Start  int    `json:"start" example:"0"`
+	Length int    `json:"length" example:"2"`
+}
+
+// maxSearchWords bounds the words of an autocomplete query
+const maxSearchWords = 5
+
+// autocompleteUseCase implements AutocompleteUseCase
+type autocompleteUseCase struct {
+	repo         domain.DriverSearchRepository
+	budget       time.Duration
+	defaultLimit int
+	maxLimit     int
+	logger       *zap.Logger
+}
+
+// NewAutocompleteUseCase creates a new autocomplete use case. Searches are
+// given budget to run in MongoDB; zero leaves them unbounded.
+func NewAutocompleteUseCase(repo domain.DriverSearchRepository, budget time.Duration, defaultLimit, maxLimit int, logger *zap.Logger) AutocompleteUseCase {
+	return &autocompleteUseCase{
+		repo:         repo,
+		budget:       budget,
+		defaultLimit: defaultLimit,
+		maxLimit:     maxLimit,
+		logger:       logger,
+	}
+}
+
+// Autocomplete finds up to limit drivers with, for every word of the query, a
+// name word or plate starting with it, ignoring case and Turkish diacritics.
+// Drivers matching at the start of their name or plate come first.
+func (uc *autocompleteUseCase) Autocomplete(ctx context.Context, query string, filter domain.DriverFilter, limit int) (*AutocompleteResponse, error) {
+	words := domain.SearchQueryWords(query)
+	if len(words) == 0 || len(words) > maxSearchWords {
+		return nil, ErrInvalidSearchQuery
+	}
+	if limit < 1 {
+		limit = uc.defaultLimit
+	}
+	if limit > uc.maxLimit {
+		limit = uc.maxLimit
+	}
+
+	searchCtx := ctx
+	if uc.budget > 0 {
+		var cancel context.CancelFunc
+		searchCtx, cancel = context.WithTimeout(ctx, uc.budget)
+		defer cancel()
+	}
+
+	start := time.Now()
+	drivers, err := uc.repo.SearchDriverPrefixes(searchCtx, words, filter, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrSearchTimeout) && ctx.Err() == nil {
+			uc.logger.Warn("driver autocomplete exceeded its time budget",
+				zap.Int("words", len(words)), zap.Duration("elapsed", time.Since(start)))
+			return &AutocompleteResponse{Suggestions: []DriverSuggestion{}, TimedOut: true}, nil
+		}
+		return nil, err
+	}
+
+	suggestions := make([]DriverSuggestion, len(drivers))
+	for i, driver := range drivers {
+		suggestions[i] = newDriverSuggestion(driver, words)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		li, lj := leadingMatch(suggestions[i]), leadingMatch(suggestions[j])
+		if li != lj {
+			return li
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	return &AutocompleteResponse{Suggestions: suggestions}, nil
+}
+
+// newDriverSuggestion builds the suggestion for a driver, highlighting every
+// name word and plate part a query word is a prefix of
+func newDriverSuggestion(driver *domain.Driver, words []string) DriverSuggestion {
+	suggestion := DriverSuggestion{
+		ID:         driver.ID,
+		Name:       strings.TrimSpace(driver.FirstName + " " + driver.LastName),
+		Plate:      driver.Plate,
+		Highlights: []SearchHighlight{},
+	}
+	nameWords := domain.SplitSearchWords(suggestion.Name, false)
+	plate := domain.FoldSearchPlate(driver.Plate)
+	plateWords := domain.SplitSearchWords(driver.Plate, true)
+
+	for _, word := range words {
+		length := len([]rune(word))
+		for _, nameWord := range nameWords {
+			if strings.HasPrefix(nameWord.Text, word) {
+				suggestion.addHighlight(domain.SearchFieldName, nameWord.Start, length)
+			}
+		}
+		if strings.HasPrefix(plate, word) {
+			// Stored plates are compact, so the folded plate lines up with it
+			suggestion.addHighlight(domain.SearchFieldPlate, 0, length)
+			continue
+		}
+		for _, plateWord := range plateWords {
+			if strings.HasPrefix(plateWord.Text, word) {
+				suggestion.addHighlight(domain.SearchFieldPlate, plateWord.Start, length)
+			}
+		}
+	}
+	sort.Slice(suggestion.Highlights, func(i, j int) bool {
+		a, b := suggestion.Highlights[i], suggestion.Highlights[j]
+		if a.Field != b.Field {
+			return a.Field == domain.SearchFieldName
+		}
+		return a.Start < b.Start
+	})
+	return suggestion
+}
+
+// addHighlight adds a highlight, widening one that starts at the same place
+// instead of overlapping it
+func (s *DriverSuggestion) addHighlight(field string, start, length int) {
+	for i, highlight := range s.Highlights {
+		if highlight.Field == field && highlight.Start == start {
+			if length > highlight.Length {
+				s.Highlights[i].Length = length
+			}
+			return
+		}
+	}
+	s.Highlights = append(s.Highlights, SearchHighlight{Field: field, Start: start, Length: length})
+}
+
+// leadingMatch reports whether the query matched the start of the name or plate
+func leadingMatch(s DriverSuggestion) bool {
+	for _, highlight := range s.Highlights {
+		if highlight.Start == 0 {
+			return true
+		}
+	}
+	return false
+}
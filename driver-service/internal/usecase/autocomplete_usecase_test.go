@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// mockSearchRepository matches search keys against drivers kept in memory
+type mockSearchRepository struct {
+	drivers []*domain.Driver
+	words   []string
+	limit   int
+	delay   time.Duration
+}
+
+func (m *mockSearchRepository) SearchDriverPrefixes(ctx interface{}, words []string, filter domain.DriverFilter, limit int) ([]*domain.Driver, error) {
+	m.words, m.limit = words, limit
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.(context.Context).Done():
+			return nil, domain.ErrSearchTimeout
+		}
+	}
+
+	var found []*domain.Driver
+	for _, driver := range m.drivers {
+		keys := map[string]bool{}
+		for _, key := range domain.SearchKeys(driver) {
+			keys[key] = true
+		}
+		matches := filter.FleetID == "" || driver.FleetID == filter.FleetID
+		for _, word := range words {
+			matches = matches && keys[word]
+		}
+		if matches && len(found) < limit {
+			found = append(found, driver)
+		}
+	}
+	return found, nil
+}
+
+func TestSearchKeys(t *testing.T) {
+	keys := domain.SearchKeys(&domain.Driver{FirstName: "Şükrü", LastName: "IŞIK", Plate: "34ABC123"})
+	for _, key := range []string{"s", "su", "sukru", "i", "isik", "3", "34", "34abc", "34abc123", "a", "abc", "1", "123"} {
+		assert.Contains(t, keys, key)
+	}
+	assert.NotContains(t, keys, "şükrü")
+	assert.NotContains(t, keys, "ukru")
+	assert.NotContains(t, keys, "bc")
+
+	long := domain.SearchKeys(&domain.Driver{FirstName: strings.Repeat("a", 30)})
+	assert.Len(t, long, domain.MaxSearchPrefix)
+
+	assert.Equal(t, []string{"sukru", "34"}, domain.SearchQueryWords("  ŞÜKRÜ, 34 sukru "))
+	assert.Empty(t, domain.SearchQueryWords(" -- "))
+}
+
+func TestAutocompleteUseCase_Autocomplete(t *testing.T) {
+	repo := &mockSearchRepository{drivers: []*domain.Driver{
+		{ID: "d1", FirstName: "Ali", LastName: "Ahmetoğlu", Plate: "06AHM06", FleetID: "fleet-a"},
+		{ID: "d2", FirstName: "Ahmet", LastName: "Demir", Plate: "34ABC123", FleetID: "fleet-a"},
+		{ID: "d3", FirstName: "Şükrü", LastName: "Yılmaz", Plate: "34SY42", FleetID: "fleet-b"},
+	}}
+	uc := NewAutocompleteUseCase(repo, 0, 8, 20, zap.NewNop())
+	ctx := context.Background()
+
+	result, err := uc.Autocomplete(ctx, "ahm", domain.DriverFilter{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 8, repo.limit)
+	require.Len(t, result.Suggestions, 2)
+	// Matches at the start of the name come first
+	assert.Equal(t, DriverSuggestion{
+		ID:         "d2",
+		Name:       "Ahmet Demir",
+		Plate:      "34ABC123",
+		Highlights: []SearchHighlight{{Field: "name", Start: 0, Length: 3}},
+	}, result.Suggestions[0])
+	assert.Equal(t, DriverSuggestion{
+		ID:    "d1",
+		Name:  "Ali Ahmetoğlu",
+		Plate: "06AHM06",
+		Highlights: []SearchHighlight{
+			{Field: "name", Start: 4, Length: 3},
+			{Field: "plate", Start: 2, Length: 3},
+		},
+	}, result.Suggestions[1])
+	assert.False(t, result.TimedOut)
+
+	result, err = uc.Autocomplete(ctx, "SUKRU 34s", domain.DriverFilter{}, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 20, repo.limit)
+	require.Len(t, result.Suggestions, 1)
+	assert.Equal(t, []SearchHighlight{
+		{Field: "name", Start: 0, Length: 5},
+		{Field: "plate", Start: 0, Length: 3},
+	}, result.Suggestions[0].Highlights)
+
+	result, err = uc.Autocomplete(ctx, "a", domain.DriverFilter{FleetID: "fleet-b"}, 5)
+	require.NoError(t, err)
+	assert.Empty(t, result.Suggestions)
+
+	for _, query := range []string{"", "  ", "-", "a b c d e f"} {
+		_, err = uc.Autocomplete(ctx, query, domain.DriverFilter{}, 0)
+		assert.ErrorIs(t, err, ErrInvalidSearchQuery, query)
+	}
+}
+
+func TestAutocompleteUseCase_Budget(t *testing.T) {
+	repo := &mockSearchRepository{
+		drivers: []*domain.Driver{{ID: "d1", FirstName: "Ahmet", LastName: "Demir", Plate: "34ABC123"}},
+		delay:   time.Second,
+	}
+	uc := NewAutocompleteUseCase(repo, 10*time.Millisecond, 8, 20, zap.NewNop())
+
+	start := time.Now()
+	result, err := uc.Autocomplete(context.Background(), "ahm", domain.DriverFilter{}, 0)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.True(t, result.TimedOut)
+	assert.NotNil(t, result.Suggestions)
+	assert.Empty(t, result.Suggestions)
+
+	// A caller that went away gets the error rather than an empty list
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = uc.Autocomplete(ctx, "ahm", domain.DriverFilter{}, 0)
+	assert.ErrorIs(t, err, domain.ErrSearchTimeout)
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bitaksi/driver-service/internal/blacklist"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// BlacklistCheck looks drivers up in a government or partner blacklist
+type BlacklistCheck struct {
+	Checker blacklist.Checker
+	// FailClosed refuses drivers while the blacklist cannot be reached;
+	// otherwise they are let through and the failure is logged
+	FailClosed bool
+}
+
+// WithBlacklistCheck refuses to create drivers that are on the blacklist
+func WithBlacklistCheck(check BlacklistCheck) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.blacklist = check
+	}
+}
+
+// blacklistSubject is what the blacklist is asked about the driver
+func blacklistSubject(driver *domain.Driver) *blacklist.Subject {
+	subject := &blacklist.Subject{
+		FirstName: driver.FirstName,
+		LastName:  driver.LastName,
+		Phone:     driver.Phone,
+		Plate:     driver.Plate,
+	}
+	if driver.License != nil {
+		subject.LicenseNumber = driver.License.Number
+	}
+	return subject
+}
+
+// run returns ErrDriverBlacklisted when the driver is listed, and
+// ErrBlacklistUnavailable when the blacklist cannot answer and the check
+// fails closed. It passes everyone when no checker is configured.
+func (b BlacklistCheck) run(ctx context.Context, logger *zap.Logger, driver *domain.Driver) error {
+	if b.Checker == nil {
+		return nil
+	}
+	result, err := b.Checker.Check(ctx, blacklistSubject(driver))
+	if err != nil {
+		if b.FailClosed {
+			logger.Error("blacklist check failed, refusing driver", zap.Error(err), zap.String("id", driver.ID), zap.String("plate", driver.Plate))
+			return ErrBlacklistUnavailable
+		}
+		logger.Warn("blacklist check failed, letting driver through", zap.Error(err), zap.String("id", driver.ID), zap.String("plate", driver.Plate))
+		return nil
+	}
+	if result.Listed {
+		logger.Warn("blacklisted driver refused", append(actorFields(ctx),
+			zap.String("id", driver.ID),
+			zap.String("plate", driver.Plate),
+			zap.String("checker", b.Checker.Name()),
+			zap.String("reason", result.Reason),
+			zap.String("reference", result.Reference),
+		)...)
+		return ErrDriverBlacklisted
+	}
+	return nil
+}
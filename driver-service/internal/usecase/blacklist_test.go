@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/blacklist"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+func TestDriverUseCase_CreateDriverBlacklist(t *testing.T) {
+	ctx := context.Background()
+	request := func(lastName string) *CreateDriverRequest {
+		return &CreateDriverRequest{
+			FirstName: "Ahmet",
+			LastName:  lastName,
+			Plate:     "34ABC123",
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Lat:       41.0431,
+			Lon:       29.0099,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		lastName   string
+		failClosed bool
+		wantErr    error
+	}{
+		{name: "clean driver", lastName: "Demir"},
+		{name: "listed driver", lastName: "Blacklisted", wantErr: ErrDriverBlacklisted},
+		{name: "unavailable, fail open", lastName: "Unavailable"},
+		{name: "unavailable, fail closed", lastName: "Unavailable", failClosed: true, wantErr: ErrBlacklistUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockDriverRepository()
+			uc := NewDriverUseCase(repo, zap.NewNop(), WithBlacklistCheck(BlacklistCheck{
+				Checker:    blacklist.NewSandboxChecker(),
+				FailClosed: tt.failClosed,
+			}))
+
+			_, err := uc.CreateDriver(ctx, request(tt.lastName))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if created := len(repo.drivers) == 1; created != (tt.wantErr == nil) {
+				t.Errorf("expected created %v, got %d drivers", tt.wantErr == nil, len(repo.drivers))
+			}
+		})
+	}
+}
+
+func TestKYCUseCase_SubmitIdentityBlacklist(t *testing.T) {
+	uc, driverRepo, kycRepo := newTestKYCUseCase(t)
+	uc.opts.Blacklist = BlacklistCheck{Checker: blacklist.NewSandboxChecker()}
+	ctx := context.Background()
+	driverRepo.drivers["d1"] = licensedDriver("d1", "Demir", "TR-1")
+	driverRepo.drivers["d1"].License = &domain.DriverLicense{Number: "BLACKLISTED-1"}
+
+	if _, err := uc.SubmitIdentity(ctx, "d1"); !errors.Is(err, ErrDriverBlacklisted) {
+		t.Fatalf("expected ErrDriverBlacklisted, got %v", err)
+	}
+	if len(kycRepo.checks) != 0 {
+		t.Errorf("expected no identity check for a listed driver, got %+v", kycRepo.checks)
+	}
+}
@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/rules"
+	"go.uber.org/zap"
+)
+
+// BulkUpdateUseCase defines the interface for changing many drivers at once
+type BulkUpdateUseCase interface {
+	BulkUpdate(ctx context.Context, req *BulkUpdateRequest) (*domain.BulkUpdate, error)
+}
+
+// BulkUpdateRequest represents the request to patch every driver matching a filter
+type BulkUpdateRequest struct {
+	// Filter must set at least one field; fleet admins are always limited to their own fleet
+	Filter domain.BulkFilter  `json:"filter"`
+	Patch  domain.DriverPatch `json:"patch"`
+	// DryRun counts the drivers the update would change without changing them
+	DryRun bool `json:"dryRun,omitempty" example:"true"`
+}
+
+// bulkUpdateUseCase implements BulkUpdateUseCase
+type bulkUpdateUseCase struct {
+	repo       domain.DriverBulkRepository
+	activity   domain.ActivityRepository
+	events     domain.DriverEventPublisher
+	rules      *rules.Engine
+	maxDrivers int
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewBulkUpdateUseCase creates a new bulk update use case. A single update
+// may change at most maxDrivers drivers.
+func NewBulkUpdateUseCase(repo domain.DriverBulkRepository, activity domain.ActivityRepository, events domain.DriverEventPublisher, engine *rules.Engine, maxDrivers int, logger *zap.Logger) BulkUpdateUseCase {
+	if engine == nil {
+		engine = rules.Default()
+	}
+	return &bulkUpdateUseCase{
+		repo:       repo,
+		activity:   activity,
+		events:     events,
+		rules:      engine,
+		maxDrivers: maxDrivers,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// BulkUpdate applies the patch to every driver matching the filter and
+// records the update for the audit trail. Drivers taken off shift by a
+// suspension get their shift ended, and every changed driver is announced to
+// webhook subscribers as it would be when updated one at a time.
+func (uc *bulkUpdateUseCase) BulkUpdate(ctx context.Context, req *BulkUpdateRequest) (*domain.BulkUpdate, error) {
+	filter, patch := req.Filter, req.Patch
+	identity, _ := domain.IdentityFromContext(ctx)
+	if identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+	if err := uc.normalize(&filter, &patch); err != nil {
+		return nil, err
+	}
+
+	now := uc.now().UTC()
+	result, err := uc.repo.BulkUpdateDrivers(ctx, filter, patch, uc.maxDrivers, req.DryRun, now)
+	if err != nil {
+		if errors.Is(err, domain.ErrBulkUpdateTooLarge) {
+			return nil, fmt.Errorf("%w: the filter matches %d drivers, at most %d can be updated at once", domain.ErrBulkUpdateTooLarge, result.Matched, uc.maxDrivers)
+		}
+		uc.logger.Error("failed to bulk update drivers", zap.Error(err))
+		return nil, errors.New("failed to update drivers")
+	}
+
+	if !req.DryRun {
+		for _, driver := range result.Changed {
+			wasAvailable := driver.Available
+			patch.Apply(driver)
+			driver.UpdatedAt = now
+			if wasAvailable && !driver.Available && uc.activity != nil {
+				if err := uc.activity.EndShift(ctx, driver.ID, now); err != nil {
+					uc.logger.Warn("failed to end shift", zap.Error(err), zap.String("id", driver.ID))
+				}
+			}
+			if uc.events == nil {
+				continue
+			}
+			if patch.Suspended != nil && *patch.Suspended {
+				uc.events.PublishDriverEvent(ctx, domain.EventDriverSuspended, driver, driver.SuspendReason)
+			} else {
+				uc.events.PublishDriverEvent(ctx, domain.EventDriverUpdated, driver, "")
+			}
+		}
+	}
+
+	update := &domain.BulkUpdate{
+		Filter:    filter,
+		Patch:     patch,
+		DryRun:    req.DryRun,
+		Matched:   result.Matched,
+		Modified:  result.Modified,
+		ActorID:   identity.UserID,
+		ActorRole: identity.Role,
+		TenantID:  identity.TenantID,
+		CreatedAt: now,
+	}
+	// The drivers are already changed, so a lost audit record is logged rather than failing the request
+	if err := uc.repo.RecordBulkUpdate(ctx, update); err != nil {
+		uc.logger.Error("failed to record bulk update", zap.Error(err))
+	}
+	uc.logger.Info("audit: drivers bulk updated", append(actorFields(ctx),
+		zap.String("bulkUpdateId", update.ID),
+		zap.Bool("dryRun", update.DryRun),
+		zap.Int64("matched", update.Matched),
+		zap.Int64("modified", update.Modified),
+	)...)
+	return update, nil
+}
+
+// normalize validates a bulk update and puts its filter and patch in the form
+// drivers are stored in. Patched fields are checked with the rules of the
+// filtered fleet, or the country's rules when the update spans fleets.
+func (uc *bulkUpdateUseCase) normalize(filter *domain.BulkFilter, patch *domain.DriverPatch) error {
+	if filter.Empty() {
+		return fmt.Errorf("%w: filter must select drivers by ids, fleetId, city, taksiType, carBrand, carModel, tags or attributes", ErrInvalidBulkUpdate)
+	}
+	if len(filter.IDs) > uc.maxDrivers {
+		return fmt.Errorf("%w: %d ids given, at most %d can be updated at once", domain.ErrBulkUpdateTooLarge, len(filter.IDs), uc.maxDrivers)
+	}
+	if filter.TaxiType != "" && !filter.TaxiType.IsValid() {
+		return fmt.Errorf("%w: invalid taksiType %q", ErrInvalidBulkUpdate, filter.TaxiType)
+	}
+	if filter.City != "" {
+		city, ok := domain.NormalizeTag(filter.City)
+		if !ok {
+			return fmt.Errorf("%w: invalid city %q", ErrInvalidBulkUpdate, filter.City)
+		}
+		filter.City = city
+	}
+	filter.CarBrand = strings.TrimSpace(filter.CarBrand)
+	filter.CarModel = strings.TrimSpace(filter.CarModel)
+	for i, tag := range filter.Tags {
+		normalized, ok := domain.NormalizeTag(tag)
+		if !ok {
+			return fmt.Errorf("%w: invalid tag %q", ErrInvalidBulkUpdate, tag)
+		}
+		filter.Tags[i] = normalized
+	}
+	if len(filter.Attributes) > 0 {
+		attributes := make(map[string]string, len(filter.Attributes))
+		for key, value := range filter.Attributes {
+			// Keys become field paths of the query, so they must stay plain words
+			normalized, ok := domain.NormalizeTag(key)
+			if !ok {
+				return fmt.Errorf("%w: invalid attribute %q", ErrInvalidBulkUpdate, key)
+			}
+			attributes[normalized] = strings.TrimSpace(value)
+		}
+		filter.Attributes = attributes
+	}
+
+	if patch.Empty() {
+		return fmt.Errorf("%w: patch must set carBrand, carModel, taksiType or suspended", ErrInvalidBulkUpdate)
+	}
+	patch.SuspendReason = strings.TrimSpace(patch.SuspendReason)
+	if patch.SuspendReason != "" && (patch.Suspended == nil || !*patch.Suspended) {
+		return fmt.Errorf("%w: suspensionReason is only recorded when suspending", ErrInvalidBulkUpdate)
+	}
+	fieldRules := uc.rules.For(filter.FleetID)
+	if patch.CarBrand != nil {
+		brand := strings.TrimSpace(*patch.CarBrand)
+		if err := checkUpdate(fieldRules, rules.FieldCarBrand, brand); err != nil {
+			return err
+		}
+		patch.CarBrand = &brand
+	}
+	if patch.CarModel != nil {
+		model := strings.TrimSpace(*patch.CarModel)
+		if err := checkUpdate(fieldRules, rules.FieldCarModel, model); err != nil {
+			return err
+		}
+		patch.CarModel = &model
+	}
+	if patch.TaxiType != nil {
+		if err := fieldRules.CheckTaxiType(*patch.TaxiType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
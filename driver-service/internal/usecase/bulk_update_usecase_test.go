@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockBulkRepository applies bulk updates to drivers kept in memory, matching
+// them by fleet and car brand
+type mockBulkRepository struct {
+	drivers    []*domain.Driver
+	filters    []domain.BulkFilter
+	records    []*domain.BulkUpdate
+	shouldFail bool
+}
+
+func (m *mockBulkRepository) BulkUpdateDrivers(ctx interface{}, filter domain.BulkFilter, patch domain.DriverPatch, limit int, dryRun bool, at time.Time) (*domain.BulkUpdateResult, error) {
+	m.filters = append(m.filters, filter)
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	result := &domain.BulkUpdateResult{}
+	var matched []*domain.Driver
+	for _, driver := range m.drivers {
+		if (filter.FleetID != "" && driver.FleetID != filter.FleetID) || (filter.CarBrand != "" && driver.CarBrand != filter.CarBrand) {
+			continue
+		}
+		matched = append(matched, driver)
+	}
+	result.Matched = int64(len(matched))
+	if result.Matched > int64(limit) {
+		return result, domain.ErrBulkUpdateTooLarge
+	}
+	for _, driver := range matched {
+		previous := *driver
+		if !patch.Apply(driver) {
+			continue
+		}
+		if dryRun {
+			*driver = previous
+		}
+		result.Changed = append(result.Changed, &previous)
+	}
+	result.Modified = int64(len(result.Changed))
+	return result, nil
+}
+
+func (m *mockBulkRepository) RecordBulkUpdate(ctx interface{}, update *domain.BulkUpdate) error {
+	update.ID = "bulk-1"
+	m.records = append(m.records, update)
+	return nil
+}
+
+func TestBulkUpdateUseCase_BulkUpdate(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	repo := &mockBulkRepository{drivers: []*domain.Driver{
+		{ID: "on-shift", FleetID: "fleet-1", CarBrand: "Fiat", Available: true},
+		{ID: "off-shift", FleetID: "fleet-1", CarBrand: "Fiat"},
+		{ID: "suspended", FleetID: "fleet-1", CarBrand: "Fiat", Suspended: true, SuspendReason: "fleet inspection"},
+		{ID: "other-fleet", FleetID: "fleet-2", CarBrand: "Fiat", Available: true},
+	}}
+	activity := newMockActivityRepository()
+	activity.openShifts["on-shift"] = true
+	activity.openShifts["other-fleet"] = true
+	events := &recordingPublisher{}
+	uc := NewBulkUpdateUseCase(repo, activity, events, nil, 3, zap.NewNop()).(*bulkUpdateUseCase)
+	uc.now = func() time.Time { return now }
+	ctx := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "admin-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	suspend := true
+
+	// Fleet admins only reach their own fleet, whatever the filter says
+	req := &BulkUpdateRequest{
+		Filter: domain.BulkFilter{FleetID: "fleet-2", CarBrand: " Fiat "},
+		Patch:  domain.DriverPatch{Suspended: &suspend, SuspendReason: " fleet inspection "},
+		DryRun: true,
+	}
+	update, err := uc.BulkUpdate(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.filters[0]; got.FleetID != "fleet-1" || got.CarBrand != "Fiat" {
+		t.Errorf("expected the filter scoped to the admin's fleet, got %+v", got)
+	}
+	if !update.DryRun || update.Matched != 3 || update.Modified != 2 {
+		t.Errorf("expected a dry run changing 2 of 3 drivers, got %+v", update)
+	}
+	if repo.drivers[0].Suspended || len(events.events) != 0 || !activity.openShifts["on-shift"] {
+		t.Error("expected a dry run to leave drivers, shifts and subscribers alone")
+	}
+
+	req.DryRun = false
+	update, err = uc.BulkUpdate(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if update.ID != "bulk-1" || update.Modified != 2 || update.ActorID != "admin-1" || update.TenantID != "fleet-1" || !update.CreatedAt.Equal(now) {
+		t.Errorf("expected the recorded audit entry, got %+v", update)
+	}
+	if !repo.drivers[0].Suspended || repo.drivers[0].SuspendReason != "fleet inspection" || repo.drivers[3].Suspended {
+		t.Errorf("expected only the fleet's drivers suspended, got %+v", repo.drivers)
+	}
+	if activity.openShifts["on-shift"] || !activity.openShifts["other-fleet"] {
+		t.Errorf("expected only the suspended on-shift driver's shift to end, open shifts: %v", activity.openShifts)
+	}
+	if len(events.events) != 2 || events.events[0] != domain.EventDriverSuspended || events.reasons[0] != "fleet inspection" {
+		t.Errorf("expected two suspension events, got %v %v", events.events, events.reasons)
+	}
+	if len(repo.records) != 2 {
+		t.Errorf("expected the dry run and the update recorded, got %d records", len(repo.records))
+	}
+}
+
+func TestBulkUpdateUseCase_Validation(t *testing.T) {
+	repo := &mockBulkRepository{drivers: []*domain.Driver{
+		{ID: "d1", FleetID: "fleet-1"}, {ID: "d2", FleetID: "fleet-1"}, {ID: "d3", FleetID: "fleet-1"},
+	}}
+	uc := NewBulkUpdateUseCase(repo, nil, nil, nil, 2, zap.NewNop())
+	ctx := context.Background()
+	brand, suspend, reinstate := "Toyota", true, false
+	invalidType := domain.TaxiType("mor")
+
+	tests := []struct {
+		name    string
+		req     BulkUpdateRequest
+		wantErr error
+	}{
+		{"empty filter", BulkUpdateRequest{Patch: domain.DriverPatch{CarBrand: &brand}}, ErrInvalidBulkUpdate},
+		{"empty patch", BulkUpdateRequest{Filter: domain.BulkFilter{FleetID: "fleet-1"}}, ErrInvalidBulkUpdate},
+		{"invalid filter type", BulkUpdateRequest{Filter: domain.BulkFilter{TaxiType: invalidType}, Patch: domain.DriverPatch{CarBrand: &brand}}, ErrInvalidBulkUpdate},
+		{"invalid tag", BulkUpdateRequest{Filter: domain.BulkFilter{Tags: []string{"$where"}}, Patch: domain.DriverPatch{CarBrand: &brand}}, ErrInvalidBulkUpdate},
+		{"reason without suspending", BulkUpdateRequest{Filter: domain.BulkFilter{FleetID: "fleet-1"}, Patch: domain.DriverPatch{Suspended: &reinstate, SuspendReason: "late"}}, ErrInvalidBulkUpdate},
+		{"too many ids", BulkUpdateRequest{Filter: domain.BulkFilter{IDs: []string{"d1", "d2", "d3"}}, Patch: domain.DriverPatch{Suspended: &suspend}}, domain.ErrBulkUpdateTooLarge},
+		{"too many matches", BulkUpdateRequest{Filter: domain.BulkFilter{FleetID: "fleet-1"}, Patch: domain.DriverPatch{Suspended: &suspend}}, domain.ErrBulkUpdateTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.BulkUpdate(ctx, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(repo.records) != 0 {
+		t.Errorf("expected rejected updates not to be recorded, got %d records", len(repo.records))
+	}
+
+	repo.shouldFail = true
+	if _, err := uc.BulkUpdate(ctx, &BulkUpdateRequest{Filter: domain.BulkFilter{FleetID: "fleet-1"}, Patch: domain.DriverPatch{CarBrand: &brand}}); err == nil {
+		t.Error("expected an error when the repository fails")
+	}
+}
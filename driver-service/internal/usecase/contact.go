@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// e164Regex matches an E.164 phone number: "+" followed by up to 15 digits
+var e164Regex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// normalizePhone strips common formatting characters from a phone number
+func normalizePhone(phone string) string {
+	return strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(phone))
+}
+
+// normalizeEmail lowercases and trims an email address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validatePhone validates a normalized phone number
+func validatePhone(phone string) error {
+	if !e164Regex.MatchString(phone) {
+		return ErrInvalidPhone
+	}
+	return nil
+}
+
+// validateEmail validates a normalized email address
+func validateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
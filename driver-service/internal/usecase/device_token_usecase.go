@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Shape and limits of device tokens
+const (
+	deviceTokenPrefix     = "dtk_"
+	deviceTokenBytes      = 32
+	deviceTokenShownLen   = len(deviceTokenPrefix) + 8
+	maxActiveDeviceTokens = 10
+)
+
+// DeviceTokenUseCase defines the interface for driver device tokens
+type DeviceTokenUseCase interface {
+	// Issue creates a token for a device of the driver, revoking the device's previous token
+	Issue(ctx context.Context, driverID string, req *IssueDeviceTokenRequest) (*domain.DeviceToken, error)
+	// List returns tokens newest first; an empty driverID lists the tokens of every driver
+	List(ctx context.Context, driverID string, activeOnly bool) ([]*domain.DeviceToken, error)
+	Revoke(ctx context.Context, id string) (*domain.DeviceToken, error)
+	// Rotate issues a new token for the same device and revokes the old one
+	Rotate(ctx context.Context, id string) (*domain.DeviceToken, error)
+	// Verify returns the active token matching the presented one
+	Verify(ctx context.Context, token string) (*domain.DeviceToken, error)
+}
+
+// IssueDeviceTokenRequest represents the request to issue a device token
+type IssueDeviceTokenRequest struct {
+	DeviceID string `json:"deviceId" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890" binding:"required"`
+	Name     string `json:"name,omitempty" example:"Mehmet's Android"`
+}
+
+// VerifyDeviceTokenRequest carries a token presented by a device
+type VerifyDeviceTokenRequest struct {
+	Token string `json:"token" example:"dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a" binding:"required"`
+}
+
+// deviceTokenUseCase implements DeviceTokenUseCase
+type deviceTokenUseCase struct {
+	repo       domain.DeviceTokenRepository
+	driverRepo domain.DriverRepository
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewDeviceTokenUseCase creates a new device token use case
+func NewDeviceTokenUseCase(repo domain.DeviceTokenRepository, driverRepo domain.DriverRepository, logger *zap.Logger) DeviceTokenUseCase {
+	return &deviceTokenUseCase{
+		repo:       repo,
+		driverRepo: driverRepo,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Issue creates a token for a device of the driver. The returned token is the
+// only place its value is shown.
+func (uc *deviceTokenUseCase) Issue(ctx context.Context, driverID string, req *IssueDeviceTokenRequest) (*domain.DeviceToken, error) {
+	deviceID := strings.TrimSpace(req.DeviceID)
+	if deviceID == "" {
+		return nil, ErrDeviceIDRequired
+	}
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	active, err := uc.repo.List(ctx, driverID, true)
+	if err != nil {
+		uc.logger.Error("failed to list device tokens", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to issue device token")
+	}
+	others := 0
+	for _, token := range active {
+		if token.DeviceID != deviceID {
+			others++
+		}
+	}
+	if others >= maxActiveDeviceTokens {
+		return nil, ErrTooManyDeviceTokens
+	}
+
+	now := uc.now().UTC()
+	revoked, err := uc.repo.RevokeDevice(ctx, driverID, deviceID, now)
+	if err != nil {
+		uc.logger.Error("failed to revoke previous device token", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to issue device token")
+	}
+	token, err := uc.create(ctx, driverID, deviceID, strings.TrimSpace(req.Name), now)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("device token issued", append(actorFields(ctx),
+		zap.String("id", token.ID),
+		zap.String("driverId", driverID),
+		zap.String("deviceId", deviceID),
+		zap.Int64("revoked", revoked),
+	)...)
+	return token, nil
+}
+
+// List returns tokens newest first. Listing the tokens of a driver checks the
+// caller may act on the driver.
+func (uc *deviceTokenUseCase) List(ctx context.Context, driverID string, activeOnly bool) ([]*domain.DeviceToken, error) {
+	if driverID != "" {
+		driver, err := uc.driverRepo.GetByID(ctx, driverID)
+		if err != nil {
+			return nil, errors.New("driver not found")
+		}
+		if err := authorizeDriver(ctx, driver); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := uc.repo.List(ctx, driverID, activeOnly)
+	if err != nil {
+		uc.logger.Error("failed to list device tokens", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to list device tokens")
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a token; revoking a revoked token returns it unchanged
+func (uc *deviceTokenUseCase) Revoke(ctx context.Context, id string) (*domain.DeviceToken, error) {
+	token, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Active() {
+		return token, nil
+	}
+
+	now := uc.now().UTC()
+	if _, err := uc.repo.Revoke(ctx, id, "", now); err != nil {
+		uc.logger.Error("failed to revoke device token", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to revoke device token")
+	}
+	token.RevokedAt = &now
+
+	uc.logger.Info("device token revoked", append(actorFields(ctx),
+		zap.String("id", id), zap.String("driverId", token.DriverID), zap.String("deviceId", token.DeviceID))...)
+	return token, nil
+}
+
+// Rotate issues a new token for the device of an active token and revokes the
+// old one. The returned token is the only place the new value is shown.
+func (uc *deviceTokenUseCase) Rotate(ctx context.Context, id string) (*domain.DeviceToken, error) {
+	old, err := uc.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !old.Active() {
+		return nil, ErrDeviceTokenRevoked
+	}
+
+	now := uc.now().UTC()
+	token, err := uc.create(ctx, old.DriverID, old.DeviceID, old.Name, now)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := uc.repo.Revoke(ctx, id, token.ID, now)
+	if err != nil || !revoked {
+		// A concurrent revoke or rotation won; the new token must not outlive it
+		if _, cleanupErr := uc.repo.Revoke(ctx, token.ID, "", now); cleanupErr != nil {
+			uc.logger.Error("failed to revoke token of failed rotation", zap.Error(cleanupErr), zap.String("id", token.ID))
+		}
+		if err != nil {
+			uc.logger.Error("failed to revoke rotated device token", zap.Error(err), zap.String("id", id))
+			return nil, errors.New("failed to rotate device token")
+		}
+		return nil, ErrDeviceTokenRevoked
+	}
+
+	uc.logger.Info("device token rotated", append(actorFields(ctx),
+		zap.String("id", id), zap.String("replacedBy", token.ID), zap.String("driverId", token.DriverID))...)
+	return token, nil
+}
+
+// Verify returns the active token matching the presented one and records its
+// use. Tokens of deleted drivers no longer verify.
+func (uc *deviceTokenUseCase) Verify(ctx context.Context, presented string) (*domain.DeviceToken, error) {
+	if !strings.HasPrefix(presented, deviceTokenPrefix) {
+		return nil, ErrInvalidDeviceToken
+	}
+	token, err := uc.repo.GetByHash(ctx, hashDeviceToken(presented))
+	if err != nil {
+		if err.Error() == ErrDeviceTokenNotFound.Error() {
+			return nil, ErrInvalidDeviceToken
+		}
+		uc.logger.Error("failed to look up device token", zap.Error(err))
+		return nil, errors.New("failed to verify device token")
+	}
+	if !token.Active() {
+		return nil, ErrInvalidDeviceToken
+	}
+	if _, err := uc.driverRepo.GetByID(ctx, token.DriverID); err != nil {
+		switch err.Error() {
+		case "driver not found", "invalid driver ID":
+			return nil, ErrInvalidDeviceToken
+		}
+		uc.logger.Error("failed to look up driver of device token", zap.Error(err), zap.String("id", token.ID))
+		return nil, errors.New("failed to verify device token")
+	}
+
+	now := uc.now().UTC()
+	if err := uc.repo.Touch(ctx, token.ID, now); err != nil {
+		// Losing a last-used time is not worth failing the device's request
+		uc.logger.Warn("failed to record device token use", zap.Error(err), zap.String("id", token.ID))
+	} else {
+		token.LastUsedAt = &now
+	}
+	return token, nil
+}
+
+// get returns a token the caller may act on
+func (uc *deviceTokenUseCase) get(ctx context.Context, id string) (*domain.DeviceToken, error) {
+	token, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == ErrDeviceTokenNotFound.Error() {
+			return nil, ErrDeviceTokenNotFound
+		}
+		uc.logger.Error("failed to get device token", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get device token")
+	}
+	return token, nil
+}
+
+// create stores a new token for the device and returns it with its value
+func (uc *deviceTokenUseCase) create(ctx context.Context, driverID, deviceID, name string, now time.Time) (*domain.DeviceToken, error) {
+	value, err := newDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	token := &domain.DeviceToken{
+		DriverID:  driverID,
+		DeviceID:  deviceID,
+		Name:      name,
+		Token:     value,
+		TokenHash: hashDeviceToken(value),
+		Prefix:    value[:deviceTokenShownLen],
+		Scopes:    domain.DeviceTokenScopes,
+		CreatedAt: now,
+	}
+	if err := uc.repo.Create(ctx, token); err != nil {
+		uc.logger.Error("failed to create device token", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to issue device token")
+	}
+	return token, nil
+}
+
+func newDeviceToken() (string, error) {
+	b := make([]byte, deviceTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate device token: %w", err)
+	}
+	return deviceTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// hashDeviceToken returns the form a token is stored and looked up in
+func hashDeviceToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockDeviceTokenRepository is an in-memory DeviceTokenRepository
+type mockDeviceTokenRepository struct {
+	tokens []*domain.DeviceToken
+}
+
+func (m *mockDeviceTokenRepository) Create(ctx interface{}, token *domain.DeviceToken) error {
+	token.ID = "token-" + strconv.Itoa(len(m.tokens)+1)
+	copied := *token
+	copied.Token = ""
+	m.tokens = append(m.tokens, &copied)
+	return nil
+}
+
+func (m *mockDeviceTokenRepository) find(match func(*domain.DeviceToken) bool) (*domain.DeviceToken, error) {
+	for _, token := range m.tokens {
+		if match(token) {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("device token not found")
+}
+
+func (m *mockDeviceTokenRepository) GetByID(ctx interface{}, id string) (*domain.DeviceToken, error) {
+	return m.find(func(t *domain.DeviceToken) bool { return t.ID == id })
+}
+
+func (m *mockDeviceTokenRepository) GetByHash(ctx interface{}, tokenHash string) (*domain.DeviceToken, error) {
+	return m.find(func(t *domain.DeviceToken) bool { return t.TokenHash == tokenHash })
+}
+
+func (m *mockDeviceTokenRepository) List(ctx interface{}, driverID string, activeOnly bool) ([]*domain.DeviceToken, error) {
+	var tokens []*domain.DeviceToken
+	for i := len(m.tokens) - 1; i >= 0; i-- {
+		token := m.tokens[i]
+		if (driverID == "" || token.DriverID == driverID) && (!activeOnly || token.Active()) {
+			copied := *token
+			tokens = append(tokens, &copied)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *mockDeviceTokenRepository) Revoke(ctx interface{}, id, replacedBy string, at time.Time) (bool, error) {
+	for _, token := range m.tokens {
+		if token.ID == id && token.Active() {
+			token.RevokedAt, token.ReplacedBy = &at, replacedBy
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockDeviceTokenRepository) RevokeDevice(ctx interface{}, driverID, deviceID string, at time.Time) (int64, error) {
+	var n int64
+	for _, token := range m.tokens {
+		if token.DriverID == driverID && token.DeviceID == deviceID && token.Active() {
+			token.RevokedAt = &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *mockDeviceTokenRepository) Touch(ctx interface{}, id string, at time.Time) error {
+	for _, token := range m.tokens {
+		if token.ID == id {
+			token.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func newTestDeviceTokenUseCase() (*deviceTokenUseCase, *mockDeviceTokenRepository, *mockDriverRepository) {
+	driverRepo := newMockDriverRepository()
+	driverRepo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", FleetID: "fleet-1"}
+	driverRepo.drivers["driver-2"] = &domain.Driver{ID: "driver-2", FleetID: "fleet-2"}
+	repo := &mockDeviceTokenRepository{}
+	uc := NewDeviceTokenUseCase(repo, driverRepo, zap.NewNop()).(*deviceTokenUseCase)
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	return uc, repo, driverRepo
+}
+
+func TestDeviceTokenUseCase_IssueAndVerify(t *testing.T) {
+	uc, repo, driverRepo := newTestDeviceTokenUseCase()
+	ctx := context.Background()
+
+	issued, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "phone-a", Name: "Android"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if !strings.HasPrefix(issued.Token, "dtk_") || !strings.HasPrefix(issued.Token, issued.Prefix) {
+		t.Errorf("Issue() token = %q, prefix %q", issued.Token, issued.Prefix)
+	}
+	if strings.Contains(repo.tokens[0].TokenHash, issued.Token) || repo.tokens[0].Token != "" {
+		t.Error("token value was stored")
+	}
+	if !issued.HasScope(domain.ScopeLocationUpdate) || !issued.HasScope(domain.ScopeHeartbeat) || len(issued.Scopes) != 2 {
+		t.Errorf("Issue() scopes = %v", issued.Scopes)
+	}
+
+	verified, err := uc.Verify(ctx, issued.Token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if verified.DriverID != "driver-1" || verified.ID != issued.ID || verified.LastUsedAt == nil {
+		t.Errorf("Verify() = %+v", verified)
+	}
+	for _, presented := range []string{"", "dtk_unknown", strings.TrimPrefix(issued.Token, "dtk_")} {
+		if _, err := uc.Verify(ctx, presented); !errors.Is(err, ErrInvalidDeviceToken) {
+			t.Errorf("Verify(%q) error = %v, want ErrInvalidDeviceToken", presented, err)
+		}
+	}
+
+	// A new token for the same device replaces the old one
+	replacement, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "phone-a"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if _, err := uc.Verify(ctx, issued.Token); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("Verify() of replaced token error = %v, want ErrInvalidDeviceToken", err)
+	}
+	if _, err := uc.Verify(ctx, replacement.Token); err != nil {
+		t.Errorf("Verify() of replacement error = %v", err)
+	}
+
+	// Tokens stop working once their driver is gone
+	delete(driverRepo.drivers, "driver-1")
+	if _, err := uc.Verify(ctx, replacement.Token); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("Verify() for deleted driver error = %v, want ErrInvalidDeviceToken", err)
+	}
+}
+
+func TestDeviceTokenUseCase_Issue_Errors(t *testing.T) {
+	uc, _, _ := newTestDeviceTokenUseCase()
+	ctx := context.Background()
+
+	if _, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "  "}); !errors.Is(err, ErrDeviceIDRequired) {
+		t.Errorf("Issue() without device error = %v, want ErrDeviceIDRequired", err)
+	}
+	if _, err := uc.Issue(ctx, "missing", &IssueDeviceTokenRequest{DeviceID: "phone"}); err == nil || err.Error() != "driver not found" {
+		t.Errorf("Issue() for missing driver error = %v", err)
+	}
+
+	fleetAdmin := domain.ContextWithIdentity(ctx, domain.Identity{Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	if _, err := uc.Issue(fleetAdmin, "driver-2", &IssueDeviceTokenRequest{DeviceID: "phone"}); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("Issue() for another fleet error = %v, want ErrDriverNotInFleet", err)
+	}
+	device := domain.ContextWithIdentity(ctx, domain.Identity{Role: domain.RoleDriverDevice, UserID: "driver-1"})
+	if _, err := uc.List(device, "driver-2", false); !errors.Is(err, ErrDriverNotOwned) {
+		t.Errorf("List() by another driver's device error = %v, want ErrDriverNotOwned", err)
+	}
+
+	for i := 0; i < maxActiveDeviceTokens; i++ {
+		if _, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "phone-" + strconv.Itoa(i)}); err != nil {
+			t.Fatalf("Issue() %d error = %v", i, err)
+		}
+	}
+	if _, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "one-too-many"}); !errors.Is(err, ErrTooManyDeviceTokens) {
+		t.Errorf("Issue() over the limit error = %v, want ErrTooManyDeviceTokens", err)
+	}
+	// Re-issuing for a known device does not count against the limit
+	if _, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "phone-0"}); err != nil {
+		t.Errorf("Issue() for a known device error = %v", err)
+	}
+}
+
+func TestDeviceTokenUseCase_RevokeAndRotate(t *testing.T) {
+	uc, _, _ := newTestDeviceTokenUseCase()
+	ctx := context.Background()
+
+	issued, err := uc.Issue(ctx, "driver-1", &IssueDeviceTokenRequest{DeviceID: "phone-a", Name: "Android"})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	rotated, err := uc.Rotate(ctx, issued.ID)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated.ID == issued.ID || rotated.Token == issued.Token || rotated.DeviceID != "phone-a" || rotated.Name != "Android" {
+		t.Errorf("Rotate() = %+v", rotated)
+	}
+	if _, err := uc.Verify(ctx, issued.Token); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("Verify() of rotated token error = %v, want ErrInvalidDeviceToken", err)
+	}
+	old, _ := uc.repo.GetByID(ctx, issued.ID)
+	if old.ReplacedBy != rotated.ID {
+		t.Errorf("ReplacedBy = %q, want %q", old.ReplacedBy, rotated.ID)
+	}
+	if _, err := uc.Rotate(ctx, issued.ID); !errors.Is(err, ErrDeviceTokenRevoked) {
+		t.Errorf("Rotate() of revoked token error = %v, want ErrDeviceTokenRevoked", err)
+	}
+
+	revoked, err := uc.Revoke(ctx, rotated.ID)
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Error("Revoke() did not set revokedAt")
+	}
+	if _, err := uc.Verify(ctx, rotated.Token); !errors.Is(err, ErrInvalidDeviceToken) {
+		t.Errorf("Verify() of revoked token error = %v, want ErrInvalidDeviceToken", err)
+	}
+	if _, err := uc.Revoke(ctx, rotated.ID); err != nil {
+		t.Errorf("Revoke() twice error = %v", err)
+	}
+	if _, err := uc.Revoke(ctx, "missing"); !errors.Is(err, ErrDeviceTokenNotFound) {
+		t.Errorf("Revoke() of missing token error = %v, want ErrDeviceTokenNotFound", err)
+	}
+
+	active, err := uc.List(ctx, "driver-1", true)
+	if err != nil || len(active) != 0 {
+		t.Errorf("List(active) = %d tokens, %v", len(active), err)
+	}
+	all, err := uc.List(ctx, "", false)
+	if err != nil || len(all) != 2 {
+		t.Errorf("List(all) = %d tokens, %v", len(all), err)
+	}
+}
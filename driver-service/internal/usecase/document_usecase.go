@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// DocumentUseCase defines the interface for driver onboarding documents
+type DocumentUseCase interface {
+	UploadDocument(ctx context.Context, driverID string, req *UploadDocumentRequest) (*domain.Driver, error)
+	DeleteDocument(ctx context.Context, driverID string, docType domain.DocumentType) (*domain.Driver, error)
+}
+
+// UploadDocumentRequest represents the request to attach a document to a driver
+type UploadDocumentRequest struct {
+	Type      domain.DocumentType `json:"type" example:"license" binding:"required"`
+	Number    string              `json:"number,omitempty" example:"TR-1234567"`
+	URL       string              `json:"url" example:"https://files.bitaksi.com/docs/license.pdf" binding:"required"`
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+// documentUseCase implements DocumentUseCase
+type documentUseCase struct {
+	repo   domain.DriverRepository
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewDocumentUseCase creates a new document use case
+func NewDocumentUseCase(repo domain.DriverRepository, logger *zap.Logger) DocumentUseCase {
+	return &documentUseCase{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// UploadDocument stores the document reference, replacing any previous document of the same type
+func (uc *documentUseCase) UploadDocument(ctx context.Context, driverID string, req *UploadDocumentRequest) (*domain.Driver, error) {
+	if err := uc.validateUploadRequest(req); err != nil {
+		return nil, err
+	}
+
+	driver, err := uc.repo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	doc := domain.DriverDocument{
+		Type:       req.Type,
+		Number:     req.Number,
+		URL:        req.URL,
+		ExpiresAt:  req.ExpiresAt,
+		UploadedAt: uc.now().UTC(),
+	}
+	replaced := false
+	for i := range driver.Documents {
+		if driver.Documents[i].Type == req.Type {
+			driver.Documents[i] = doc
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		driver.Documents = append(driver.Documents, doc)
+	}
+
+	if err := uc.repo.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to save driver document", zap.Error(err), zap.String("id", driverID))
+		return nil, errors.New("failed to update driver")
+	}
+
+	uc.logger.Info("driver document uploaded", append(actorFields(ctx), zap.String("id", driverID), zap.String("type", string(req.Type)))...)
+	return driver, nil
+}
+
+// DeleteDocument removes the driver's document of the given type
+func (uc *documentUseCase) DeleteDocument(ctx context.Context, driverID string, docType domain.DocumentType) (*domain.Driver, error) {
+	if !docType.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDocumentType, docType)
+	}
+
+	driver, err := uc.repo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	kept := driver.Documents[:0]
+	for _, doc := range driver.Documents {
+		if doc.Type != docType {
+			kept = append(kept, doc)
+		}
+	}
+	if len(kept) == len(driver.Documents) {
+		return nil, ErrDocumentNotFound
+	}
+	driver.Documents = kept
+
+	if err := uc.repo.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to delete driver document", zap.Error(err), zap.String("id", driverID))
+		return nil, errors.New("failed to update driver")
+	}
+
+	uc.logger.Info("driver document deleted", append(actorFields(ctx), zap.String("id", driverID), zap.String("type", string(docType)))...)
+	return driver, nil
+}
+
+// validateUploadRequest validates the document type, file URL and expiry
+func (uc *documentUseCase) validateUploadRequest(req *UploadDocumentRequest) error {
+	if !req.Type.IsValid() {
+		return fmt.Errorf("%w: %s", ErrInvalidDocumentType, req.Type)
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return ErrInvalidDocumentURL
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(uc.now()) {
+		return ErrDocumentExpired
+	}
+	return nil
+}
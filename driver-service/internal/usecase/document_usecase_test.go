@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+func TestDocumentUseCase_UploadAndDelete(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	uc := NewDocumentUseCase(repo, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := uc.UploadDocument(ctx, "driver-1", &UploadDocumentRequest{Type: domain.DocumentTypeLicense, URL: "https://files.example.com/a.pdf"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	driver, err := uc.UploadDocument(ctx, "driver-1", &UploadDocumentRequest{Type: domain.DocumentTypeLicense, URL: "https://files.example.com/b.pdf"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(driver.Documents) != 1 || driver.Documents[0].URL != "https://files.example.com/b.pdf" {
+		t.Fatalf("expected license to be replaced, got %+v", driver.Documents)
+	}
+
+	driver, err = uc.DeleteDocument(ctx, "driver-1", domain.DocumentTypeLicense)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(driver.Documents) != 0 {
+		t.Errorf("expected no documents, got %+v", driver.Documents)
+	}
+	if _, err := uc.DeleteDocument(ctx, "driver-1", domain.DocumentTypeLicense); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("expected ErrDocumentNotFound, got %v", err)
+	}
+}
+
+func TestDocumentUseCase_UploadValidation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		req     *UploadDocumentRequest
+		wantErr error
+		errMsg  string
+	}{
+		{name: "invalid type", req: &UploadDocumentRequest{Type: "passport", URL: "https://files.example.com/a.pdf"}, wantErr: ErrInvalidDocumentType},
+		{name: "relative url", req: &UploadDocumentRequest{Type: domain.DocumentTypeInsurance, URL: "/a.pdf"}, wantErr: ErrInvalidDocumentURL},
+		{name: "expired", req: &UploadDocumentRequest{Type: domain.DocumentTypeInsurance, URL: "https://files.example.com/a.pdf", ExpiresAt: &past}, wantErr: ErrDocumentExpired},
+		{name: "driver not found", req: &UploadDocumentRequest{Type: domain.DocumentTypeInsurance, URL: "https://files.example.com/a.pdf"}, errMsg: "driver not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewDocumentUseCase(newMockDriverRepository(), zap.NewNop())
+
+			_, err := uc.UploadDocument(context.Background(), "missing", tt.req)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.errMsg != "" && (err == nil || err.Error() != tt.errMsg) {
+				t.Errorf("expected %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
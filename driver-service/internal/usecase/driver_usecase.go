@@ -4,10 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
+	"math"
+	"math/rand"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/keylock"
+	"github.com/bitaksi/driver-service/internal/routing"
+	"github.com/bitaksi/driver-service/internal/rules"
 	"github.com/bitaksi/driver-service/pkg/haversine"
 	"go.uber.org/zap"
 )
@@ -17,32 +24,63 @@ type DriverUseCase interface {
 	CreateDriver(ctx context.Context, req *CreateDriverRequest) (*domain.Driver, error)
 	UpdateDriver(ctx context.Context, id string, req *UpdateDriverRequest) (*domain.Driver, error)
 	GetDriver(ctx context.Context, id string) (*domain.Driver, error)
-	ListDrivers(ctx context.Context, page, pageSize int) (*ListDriversResponse, error)
-	FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*NearbyDriverResponse, error)
+	ListDrivers(ctx context.Context, filter domain.DriverFilter, page, pageSize int) (*ListDriversResponse, error)
+	FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*NearbyDriverResponse, error)
+	FindDriversAlongRoute(ctx context.Context, waypoints []domain.Location, widthKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter) ([]*RouteDriverResponse, error)
+	SetAvailability(ctx context.Context, id string, available bool) (*domain.Driver, error)
+	SetSuspension(ctx context.Context, id string, suspended bool, reason string) (*domain.Driver, error)
+	DeleteDriver(ctx context.Context, id string) error
 }
 
 // CreateDriverRequest represents the request to create a driver
 type CreateDriverRequest struct {
-	FirstName string          `json:"firstName" example:"Ahmet" binding:"required"`
-	LastName  string          `json:"lastName" example:"Demir" binding:"required"`
-	Plate     string          `json:"plate" example:"34ABC123" binding:"required"`
-	TaxiType  domain.TaxiType `json:"taksiType" example:"sari" binding:"required"`
-	CarBrand  string          `json:"carBrand" example:"Toyota" binding:"required"`
-	CarModel  string          `json:"carModel" example:"Corolla" binding:"required"`
-	Lat       float64         `json:"lat" example:"41.0431" binding:"required"`
-	Lon       float64         `json:"lon" example:"29.0099" binding:"required"`
+	FirstName string                `json:"firstName" example:"Ahmet" binding:"required"`
+	LastName  string                `json:"lastName" example:"Demir" binding:"required"`
+	Plate     string                `json:"plate" example:"34ABC123" binding:"required"`
+	TaxiType  domain.TaxiType       `json:"taksiType" example:"sari" binding:"required"`
+	CarBrand  string                `json:"carBrand" example:"Toyota" binding:"required"`
+	CarModel  string                `json:"carModel" example:"Corolla" binding:"required"`
+	Lat       float64               `json:"lat" example:"41.0431" binding:"required"`
+	Lon       float64               `json:"lon" example:"29.0099" binding:"required"`
+	Phone     string                `json:"phone,omitempty" example:"+905321234567"`
+	Email     string                `json:"email,omitempty" example:"ahmet.demir@example.com"`
+	FleetID   string                `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	License   *domain.DriverLicense `json:"license,omitempty"`
+	// Tags are at most 20 lowercase words joined by dashes, e.g. pet-friendly
+	Tags []string `json:"tags,omitempty" example:"pet-friendly,wheelchair-accessible"`
+	// Attributes are at most 20 keys, named like tags, with values of up to 64 characters
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // UpdateDriverRequest represents the request to update a driver
 type UpdateDriverRequest struct {
 	FirstName *string          `json:"firstName,omitempty" example:"Mehmet"`
 	LastName  *string          `json:"lastName,omitempty" example:"Kurt"`
-	Plate     *string          `json:"plate,omitempty" example:"34XYZ789"`
+	Plate     *string          `json:"plate,omitempty" example:"34KYZ789"`
 	TaxiType  *domain.TaxiType `json:"taksiType,omitempty" example:"turkuaz"`
 	CarBrand  *string          `json:"carBrand,omitempty" example:"Honda"`
 	CarModel  *string          `json:"carModel,omitempty" example:"Civic"`
 	Lat       *float64         `json:"lat,omitempty" example:"41.0082"`
 	Lon       *float64         `json:"lon,omitempty" example:"28.9784"`
+	Phone     *string          `json:"phone,omitempty" example:"+905329876543"`
+	Email     *string          `json:"email,omitempty" example:"mehmet.kurt@example.com"`
+	// License replaces the driver's licence; a valid one clears the expired flag
+	License *domain.DriverLicense `json:"license,omitempty"`
+	// Tags replaces the driver's tags; an empty list removes them
+	Tags []string `json:"tags,omitempty" example:"pet-friendly"`
+	// Attributes replaces the driver's attributes; an empty object removes them
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// SetAvailabilityRequest represents the request to go on or off shift
+type SetAvailabilityRequest struct {
+	Available *bool `json:"available" example:"true" binding:"required"`
+}
+
+// SetSuspensionRequest represents the request to suspend or reinstate a driver
+type SetSuspensionRequest struct {
+	Suspended *bool  `json:"suspended" example:"true" binding:"required"`
+	Reason    string `json:"reason,omitempty" example:"expired license"`
 }
 
 // ListDriversResponse represents the paginated list response
@@ -51,6 +89,9 @@ type ListDriversResponse struct {
 	TotalCount int64            `json:"totalCount" example:"1"`
 	Page       int              `json:"page" example:"1"`
 	PageSize   int              `json:"pageSize" example:"20"`
+	TotalPages int              `json:"totalPages" example:"1"`
+	HasNext    bool             `json:"hasNext" example:"false"`
+	HasPrev    bool             `json:"hasPrev" example:"false"`
 }
 
 // NearbyDriverResponse represents a driver in nearby search results
@@ -61,20 +102,243 @@ type NearbyDriverResponse struct {
 	Plate      string  `json:"plate" example:"34ABC123"`
 	TaxiType   string  `json:"taxiType" example:"sari"`
 	DistanceKm float64 `json:"distanceKm" example:"0.5"`
+	// Distance is DistanceKm in the units asked for; left out when none were
+	Distance *float64 `json:"distance,omitempty" example:"500"`
+	// DurationSec is the estimated driving time to the rider
+	DurationSec int `json:"durationSec" example:"95"`
+	// LastLocationUpdate is when the driver's position was last reported
+	LastLocationUpdate time.Time `json:"lastLocationUpdate" example:"2025-12-06T01:00:00Z"`
+	// StaleSeconds is the age of the position at search time, so clients can
+	// gray out drivers whose position is old
+	StaleSeconds int `json:"staleSeconds" example:"42"`
+}
+
+// RouteSearchRequest represents the request to find drivers along a route
+type RouteSearchRequest struct {
+	// Waypoints are the ordered points of the route, 2 to 25 of them
+	Waypoints []domain.Location `json:"waypoints"`
+	// WidthKm is how far from the route drivers may be; defaults to 0.5, at most 2
+	WidthKm  float64          `json:"widthKm,omitempty" example:"0.5"`
+	TaxiType *domain.TaxiType `json:"taksiType,omitempty" example:"sari"`
+	FleetID  string           `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// Live only returns drivers with a recent heartbeat; nil leaves it to the service default
+	Live *bool `json:"live,omitempty" example:"true"`
+}
+
+// RouteDriverResponse represents a driver in route corridor search results
+type RouteDriverResponse struct {
+	ID        string          `json:"id" example:"507f1f77bcf86cd799439011"`
+	FirstName string          `json:"firstName" example:"Ahmet"`
+	LastName  string          `json:"lastName" example:"Demir"`
+	Plate     string          `json:"plate" example:"34ABC123"`
+	TaxiType  string          `json:"taxiType" example:"sari"`
+	Location  domain.Location `json:"location"`
+	// DistanceKm is the straight-line distance from the driver to the route
+	DistanceKm float64 `json:"distanceKm" example:"0.2"`
+	// RouteKm is how far along the route, from its first waypoint, the driver is closest to it
+	RouteKm float64 `json:"routeKm" example:"3.4"`
+	// Distance and RouteDistance are DistanceKm and RouteKm in the units asked
+	// for; left out when none were
+	Distance      *float64 `json:"distance,omitempty" example:"200"`
+	RouteDistance *float64 `json:"routeDistance,omitempty" example:"3400"`
 }
 
 // driverUseCase implements DriverUseCase
 type driverUseCase struct {
-	repo   domain.DriverRepository
-	logger *zap.Logger
+	repo     domain.DriverRepository
+	activity domain.ActivityRepository
+	fleets   domain.FleetRepository
+	events   domain.DriverEventPublisher
+	router   routing.Router
+	rules    *rules.Engine
+	logger   *zap.Logger
+	now      func() time.Time
+
+	// area is nil unless stored positions are normalized
+	area *geofence.Area
+
+	// analytics is nil unless search events are enabled
+	analytics     domain.EventPublisher
+	analyticsOpts SearchAnalyticsOptions
+	sample        func() float64
+
+	// metrics is nil unless business metrics are exposed
+	metrics domain.BusinessMetrics
+
+	// anomalies is nil unless location updates are checked for implausible jumps
+	anomalies    domain.LocationAnomalyRecorder
+	plausibility LocationPlausibilityOptions
+
+	blacklist BlacklistCheck
+
+	// quotas is nil unless fleets are capped to the drivers they pay for
+	quotas *DriverQuotas
+
+	// locks keeps concurrent creates and updates of the same driver, plate,
+	// phone or email from racing past the uniqueness checks
+	locks *keylock.Locker
+
+	heartbeatTimeout time.Duration
+	liveByDefault    bool
+
+	defaultPageSize int
+	maxPageSize     int
+
+	defaultNearbyLimit int
+	maxNearbyLimit     int
+}
+
+// Page sizes used when WithPageSizes is not given
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// Nearby search limits used when WithNearbyLimits is not given
+const (
+	defaultNearbyLimit = 100
+	maxNearbyLimit     = 100
+)
+
+// DriverUseCaseOption configures optional driver use case behaviour
+type DriverUseCaseOption func(*driverUseCase)
+
+// WithActivityRecording records location history and shifts for driver statistics
+func WithActivityRecording(activity domain.ActivityRepository) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.activity = activity
+	}
+}
+
+// WithFleets checks that the fleet a new driver is created in exists
+func WithFleets(fleets domain.FleetRepository) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.fleets = fleets
+	}
+}
+
+// WithDriverQuotas refuses drivers created in a fleet that has used up its quota
+func WithDriverQuotas(quotas *DriverQuotas) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.quotas = quotas
+	}
+}
+
+// WithEvents publishes driver created, updated and suspended events
+func WithEvents(events domain.DriverEventPublisher) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.events = events
+	}
+}
+
+// WithRouter measures nearby drivers by road instead of in a straight line
+func WithRouter(router routing.Router) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.router = router
+	}
+}
+
+// WithHeartbeatFilter lets nearby searches skip drivers without a heartbeat in
+// the last timeout. liveByDefault applies the filter when the request does not choose.
+func WithHeartbeatFilter(timeout time.Duration, liveByDefault bool) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.heartbeatTimeout = timeout
+		uc.liveByDefault = liveByDefault
+	}
+}
+
+// WithPageSizes sets the page size used when a list request does not choose one
+// and the largest page size a request may ask for. Values below 1 keep the defaults.
+func WithPageSizes(defaultSize, maxSize int) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		if defaultSize > 0 {
+			uc.defaultPageSize = defaultSize
+		}
+		if maxSize > 0 {
+			uc.maxPageSize = maxSize
+		}
+	}
+}
+
+// WithNearbyLimits sets how many drivers a nearby search returns when the
+// request does not choose and the most a request may ask for. Values below 1
+// keep the defaults.
+func WithNearbyLimits(defaultLimit, maxLimit int) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		if defaultLimit > 0 {
+			uc.defaultNearbyLimit = defaultLimit
+		}
+		if maxLimit > 0 {
+			uc.maxNearbyLimit = maxLimit
+		}
+	}
+}
+
+// WithValidationRules validates driver fields with the rules of the driver's
+// tenant or country instead of the built-in Turkish rules
+func WithValidationRules(engine *rules.Engine) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.rules = engine
+	}
+}
+
+// WithServiceArea rounds the positions drivers are stored with and rejects
+// 0,0 and positions outside the service area
+func WithServiceArea(area *geofence.Area) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.area = area
+	}
+}
+
+// SearchAnalyticsOptions controls the nearby search events sent for demand analytics
+type SearchAnalyticsOptions struct {
+	// SampleRate is the share of searches reported, from 0 to 1
+	SampleRate float64
+	// Precision is the number of decimal places coordinates are rounded to; 2 is about 1km
+	Precision int
+}
+
+// WithSearchAnalytics publishes an anonymized NearbySearchPerformed event for a
+// sample of nearby searches
+func WithSearchAnalytics(publisher domain.EventPublisher, opts SearchAnalyticsOptions) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.analytics = publisher
+		uc.analyticsOpts = opts
+	}
+}
+
+// WithMetrics counts driver registrations, nearby searches and location updates
+func WithMetrics(metrics domain.BusinessMetrics) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.metrics = metrics
+	}
 }
 
 // NewDriverUseCase creates a new driver use case
-func NewDriverUseCase(repo domain.DriverRepository, logger *zap.Logger) DriverUseCase {
-	return &driverUseCase{
-		repo:   repo,
-		logger: logger,
+func NewDriverUseCase(repo domain.DriverRepository, logger *zap.Logger, opts ...DriverUseCaseOption) DriverUseCase {
+	uc := &driverUseCase{
+		repo:               repo,
+		router:             routing.HaversineRouter{},
+		rules:              rules.Default(),
+		logger:             logger,
+		now:                time.Now,
+		sample:             rand.Float64,
+		locks:              keylock.New(),
+		defaultPageSize:    defaultPageSize,
+		maxPageSize:        maxPageSize,
+		defaultNearbyLimit: defaultNearbyLimit,
+		maxNearbyLimit:     maxNearbyLimit,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	if uc.defaultPageSize > uc.maxPageSize {
+		uc.defaultPageSize = uc.maxPageSize
 	}
+	if uc.defaultNearbyLimit > uc.maxNearbyLimit {
+		uc.defaultNearbyLimit = uc.maxNearbyLimit
+	}
+	return uc
 }
 
 // CreateDriver creates a new driver
@@ -83,71 +347,172 @@ func (uc *driverUseCase) CreateDriver(ctx context.Context, req *CreateDriverRequ
 	if err := uc.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
+	if err := authorizeDriver(ctx, &domain.Driver{FleetID: req.FleetID}); err != nil {
+		return nil, ErrFleetScope
+	}
+
+	phone := normalizePhone(req.Phone)
+	email := normalizeEmail(req.Email)
+	unlock, ok := uc.locks.TryLock(lockKey("plate", req.Plate), lockKey("phone", phone), lockKey("email", email))
+	if !ok {
+		return nil, ErrDriverChangeInProgress
+	}
+	defer unlock()
+	if err := uc.ensureContactAvailable(ctx, "", phone, email); err != nil {
+		return nil, err
+	}
+	if err := uc.ensurePlateAvailable(ctx, "", req.Plate); err != nil {
+		return nil, err
+	}
+	if req.FleetID != "" && uc.fleets != nil {
+		if _, err := uc.fleets.GetByID(ctx, req.FleetID); err != nil {
+			return nil, ErrFleetNotFound
+		}
+	}
+	fleetDrivers, err := uc.quotas.admit(ctx, req.FleetID)
+	if err != nil {
+		return nil, err
+	}
+	var license *domain.DriverLicense
+	if req.License != nil {
+		var err error
+		if license, err = normalizeLicense(req.License, uc.now()); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := normalizeAttributes(req.Attributes)
+	if err != nil {
+		return nil, err
+	}
 
 	driver := &domain.Driver{
-		FirstName: req.FirstName,
-		LastName:  req.LastName,
-		Plate:     strings.ToUpper(req.Plate),
-		TaxiType:  req.TaxiType,
-		CarBrand:  req.CarBrand,
-		CarModel:  req.CarModel,
+		Phone:      phone,
+		Email:      email,
+		FirstName:  req.FirstName,
+		LastName:   req.LastName,
+		Plate:      req.Plate,
+		TaxiType:   req.TaxiType,
+		CarBrand:   req.CarBrand,
+		CarModel:   req.CarModel,
+		FleetID:    req.FleetID,
+		License:    license,
+		Tags:       tags,
+		Attributes: attributes,
 		Location: domain.Location{
 			Lat: req.Lat,
 			Lon: req.Lon,
 		},
 	}
+	locatedAt := uc.now().UTC()
+	driver.LocationUpdatedAt = &locatedAt
 
+	if err := uc.blacklist.run(ctx, uc.logger, driver); err != nil {
+		return nil, err
+	}
 	if err := uc.repo.Create(ctx, driver); err != nil {
+		if taken := contactTaken(err); taken != nil {
+			return nil, taken
+		}
 		uc.logger.Error("failed to create driver", zap.Error(err))
 		return nil, errors.New("failed to create driver")
 	}
 
-	uc.logger.Info("driver created", zap.String("id", driver.ID), zap.String("plate", driver.Plate))
+	if uc.metrics != nil {
+		uc.metrics.DriverCreated()
+	}
+	uc.quotas.joined(req.FleetID, fleetDrivers)
+	uc.recordLocation(ctx, driver.ID, driver.Location)
+	uc.logger.Info("driver created", append(actorFields(ctx), zap.String("id", driver.ID), zap.String("plate", driver.Plate))...)
+	uc.publish(ctx, domain.EventDriverCreated, driver, "")
 	return driver, nil
 }
 
 // UpdateDriver updates an existing driver
 func (uc *driverUseCase) UpdateDriver(ctx context.Context, id string, req *UpdateDriverRequest) (*domain.Driver, error) {
+	// Hold the driver, and the contact details it moves to, until the update is stored
+	keys := []string{lockKey("driver", id)}
+	if req.Phone != nil {
+		keys = append(keys, lockKey("phone", normalizePhone(*req.Phone)))
+	}
+	if req.Email != nil {
+		keys = append(keys, lockKey("email", normalizeEmail(*req.Email)))
+	}
+	unlock, ok := uc.locks.TryLock(keys...)
+	if !ok {
+		return nil, ErrDriverChangeInProgress
+	}
+	defer unlock()
+
 	// Get existing driver
 	existing, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("driver not found")
 	}
+	if err := authorizeDriver(ctx, existing); err != nil {
+		return nil, err
+	}
+	previousLocation, wasAvailable := existing.Location, existing.Available
 
-	// Update fields if provided
+	// Update fields if provided, under the rules of the driver's tenant
+	fieldRules := uc.rules.For(existing.FleetID)
 	if req.FirstName != nil {
-		if *req.FirstName == "" {
-			return nil, errors.New("firstName cannot be empty")
+		if err := checkUpdate(fieldRules, rules.FieldFirstName, *req.FirstName); err != nil {
+			return nil, err
+		}
+		if *req.FirstName != existing.FirstName {
+			// The verified identity was checked against the old name
+			existing.IdentityVerified = false
 		}
 		existing.FirstName = *req.FirstName
 	}
 	if req.LastName != nil {
-		if *req.LastName == "" {
-			return nil, errors.New("lastName cannot be empty")
+		if err := checkUpdate(fieldRules, rules.FieldLastName, *req.LastName); err != nil {
+			return nil, err
+		}
+		if *req.LastName != existing.LastName {
+			existing.IdentityVerified = false
 		}
 		existing.LastName = *req.LastName
 	}
 	if req.Plate != nil {
-		if err := uc.validatePlate(*req.Plate); err != nil {
+		normalized, err := fieldRules.NormalizePlate(*req.Plate)
+		if err != nil {
 			return nil, err
 		}
-		existing.Plate = strings.ToUpper(*req.Plate)
+		if normalized != existing.Plate {
+			// The plate is only normalized under the driver's rules, so it is
+			// held from here rather than with the driver and contact details
+			unlockPlate, ok := uc.locks.TryLock(lockKey("plate", normalized))
+			if !ok {
+				return nil, ErrDriverChangeInProgress
+			}
+			defer unlockPlate()
+			if err := uc.ensurePlateAvailable(ctx, existing.ID, normalized); err != nil {
+				return nil, err
+			}
+		}
+		existing.Plate = normalized
 	}
 	if req.TaxiType != nil {
-		if !req.TaxiType.IsValid() {
-			return nil, fmt.Errorf("invalid taxiType: %s", *req.TaxiType)
+		if err := fieldRules.CheckTaxiType(*req.TaxiType); err != nil {
+			return nil, err
 		}
 		existing.TaxiType = *req.TaxiType
 	}
 	if req.CarBrand != nil {
-		if *req.CarBrand == "" {
-			return nil, errors.New("carBrand cannot be empty")
+		if err := checkUpdate(fieldRules, rules.FieldCarBrand, *req.CarBrand); err != nil {
+			return nil, err
 		}
 		existing.CarBrand = *req.CarBrand
 	}
 	if req.CarModel != nil {
-		if *req.CarModel == "" {
-			return nil, errors.New("carModel cannot be empty")
+		if err := checkUpdate(fieldRules, rules.FieldCarModel, *req.CarModel); err != nil {
+			return nil, err
 		}
 		existing.CarModel = *req.CarModel
 	}
@@ -156,19 +521,63 @@ func (uc *driverUseCase) UpdateDriver(ctx context.Context, id string, req *Updat
 		if req.Lat == nil || req.Lon == nil {
 			return nil, errors.New("both lat and lon must be provided together")
 		}
-		if err := uc.validateLocation(*req.Lat, *req.Lon); err != nil {
+		location, err := uc.normalizeLocation(*req.Lat, *req.Lon)
+		if err != nil {
+			return nil, err
+		}
+		// A report of the same position still shows the driver is there
+		locatedAt := uc.now().UTC()
+		if err := uc.checkLocationJump(ctx, existing, location, locatedAt); err != nil {
+			return nil, err
+		}
+		existing.Location = location
+		existing.LocationUpdatedAt = &locatedAt
+	}
+	if req.License != nil {
+		license, err := normalizeLicense(req.License, uc.now())
+		if err != nil {
+			return nil, err
+		}
+		existing.License = license
+		existing.LicenseExpired = false
+	}
+	if req.Tags != nil {
+		tags, err := normalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		existing.Tags = tags
+	}
+	if req.Attributes != nil {
+		attributes, err := normalizeAttributes(req.Attributes)
+		if err != nil {
 			return nil, err
 		}
-		existing.Location.Lat = *req.Lat
-		existing.Location.Lon = *req.Lon
+		existing.Attributes = attributes
+	}
+	if err := uc.applyContactUpdate(ctx, existing, req.Phone, req.Email); err != nil {
+		return nil, err
 	}
 
 	if err := uc.repo.Update(ctx, id, existing); err != nil {
+		if taken := contactTaken(err); taken != nil {
+			return nil, taken
+		}
 		uc.logger.Error("failed to update driver", zap.Error(err), zap.String("id", id))
 		return nil, errors.New("failed to update driver")
 	}
 
-	uc.logger.Info("driver updated", zap.String("id", id))
+	if existing.Location != previousLocation {
+		uc.recordLocation(ctx, id, existing.Location)
+	}
+	if req.Lat != nil && uc.metrics != nil {
+		uc.metrics.LocationUpdated()
+	}
+	if wasAvailable && !existing.Available {
+		uc.recordShift(ctx, id, false)
+	}
+	uc.logger.Info("driver updated", append(actorFields(ctx), zap.String("id", id))...)
+	uc.publish(ctx, domain.EventDriverUpdated, existing, "")
 	return existing, nil
 }
 
@@ -181,34 +590,41 @@ func (uc *driverUseCase) GetDriver(ctx context.Context, id string) (*domain.Driv
 	return driver, nil
 }
 
-// ListDrivers retrieves a paginated list of drivers
-func (uc *driverUseCase) ListDrivers(ctx context.Context, page, pageSize int) (*ListDriversResponse, error) {
+// ListDrivers retrieves a paginated list of drivers matching the filter
+func (uc *driverUseCase) ListDrivers(ctx context.Context, filter domain.DriverFilter, page, pageSize int) (*ListDriversResponse, error) {
 	if page < 1 {
 		page = 1
 	}
 	if pageSize < 1 {
-		pageSize = 20
+		pageSize = uc.defaultPageSize
 	}
-	if pageSize > 100 {
-		pageSize = 100
+	if pageSize > uc.maxPageSize {
+		pageSize = uc.maxPageSize
 	}
 
-	drivers, totalCount, err := uc.repo.List(ctx, page, pageSize)
+	drivers, totalCount, err := uc.repo.List(ctx, filter, page, pageSize)
 	if err != nil {
 		uc.logger.Error("failed to list drivers", zap.Error(err))
 		return nil, errors.New("failed to list drivers")
 	}
 
+	totalPages := int((totalCount + int64(pageSize) - 1) / int64(pageSize))
 	return &ListDriversResponse{
 		Drivers:    drivers,
 		TotalCount: totalCount,
 		Page:       page,
 		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
 	}, nil
 }
 
-// FindNearbyDrivers finds drivers within 6km radius
-func (uc *driverUseCase) FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType) ([]*NearbyDriverResponse, error) {
+// FindNearbyDrivers finds drivers matching the filter within 6km radius. The
+// repository cuts the page from the drivers sorted by straight-line distance,
+// so only those are routed; a page without a limit gets the default limit and
+// larger limits are capped.
+func (uc *driverUseCase) FindNearbyDrivers(ctx context.Context, lat, lon float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*NearbyDriverResponse, error) {
 	// Validate location
 	if err := uc.validateLocation(lat, lon); err != nil {
 		return nil, err
@@ -219,77 +635,556 @@ func (uc *driverUseCase) FindNearbyDrivers(ctx context.Context, lat, lon float64
 		return nil, fmt.Errorf("invalid taxiType: %s", *taxiType)
 	}
 
+	if page.Limit < 1 {
+		page.Limit = uc.defaultNearbyLimit
+	}
+	if page.Limit > uc.maxNearbyLimit {
+		page.Limit = uc.maxNearbyLimit
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
 	const radiusKm = 6.0
-	drivers, err := uc.repo.FindNearby(ctx, lat, lon, radiusKm, taxiType)
+	drivers, err := uc.repo.FindNearby(ctx, lat, lon, radiusKm, taxiType, uc.liveFilter(filter), page)
 	if err != nil {
 		uc.logger.Error("failed to find nearby drivers", zap.Error(err))
 		return nil, errors.New("failed to find nearby drivers")
 	}
 
+	// The repository searches within a straight-line radius; the router measures
+	// the way there, which may be longer
+	locations := make([]domain.Location, len(drivers))
+	for i, driver := range drivers {
+		locations[i] = driver.Location
+	}
+	routes, err := uc.router.Routes(ctx, domain.Location{Lat: lat, Lon: lon}, locations)
+	if err != nil {
+		uc.logger.Error("failed to route nearby drivers", zap.Error(err), zap.String("provider", uc.router.Name()))
+		return nil, errors.New("failed to find nearby drivers")
+	}
+
 	// Convert to response format with distance
+	now := uc.now()
 	responses := make([]*NearbyDriverResponse, len(drivers))
 	for i, driver := range drivers {
-		// Calculate distance for response
-		// Note: We already filtered by distance, but we need to recalculate for the response
-		// In a real implementation, we might want to store the distance in the repository
-		// For now, we'll use a simple approach and recalculate
-		distance := haversine.Distance(lat, lon, driver.Location.Lat, driver.Location.Lon)
-
+		locatedAt := locationTime(driver)
 		responses[i] = &NearbyDriverResponse{
-			ID:         driver.ID,
-			FirstName:  driver.FirstName,
-			LastName:   driver.LastName,
-			Plate:      driver.Plate,
-			TaxiType:   string(driver.TaxiType),
-			DistanceKm: distance,
+			ID:                 driver.ID,
+			FirstName:          driver.FirstName,
+			LastName:           driver.LastName,
+			Plate:              driver.Plate,
+			TaxiType:           string(driver.TaxiType),
+			DistanceKm:         routes[i].DistanceKm,
+			DurationSec:        int(math.Round(routes[i].DurationSec)),
+			LastLocationUpdate: locatedAt,
+			StaleSeconds:       staleSeconds(locatedAt, now),
 		}
 	}
+	sort.SliceStable(responses, func(i, j int) bool {
+		return responses[i].DistanceKm < responses[j].DistanceKm
+	})
 
 	uc.logger.Info("found nearby drivers", zap.Int("count", len(responses)))
+	uc.recordSearch(lat, lon, taxiType, len(responses))
+	if uc.metrics != nil {
+		uc.metrics.NearbySearch(len(responses))
+	}
 	return responses, nil
 }
 
-// validateCreateRequest validates the create driver request
-func (uc *driverUseCase) validateCreateRequest(req *CreateDriverRequest) error {
-	if req.FirstName == "" {
-		return errors.New("firstName is required")
+// locationTime returns when the driver's position was reported. Drivers stored
+// before positions were timestamped fall back to their last update, which is
+// the latest the position can have been written.
+func locationTime(driver *domain.Driver) time.Time {
+	if driver.LocationUpdatedAt != nil {
+		return *driver.LocationUpdatedAt
 	}
-	if req.LastName == "" {
-		return errors.New("lastName is required")
+	return driver.UpdatedAt
+}
+
+// staleSeconds returns the whole seconds since locatedAt; clock skew between
+// instances never makes it negative
+func staleSeconds(locatedAt, now time.Time) int {
+	age := now.Sub(locatedAt)
+	if age < 0 {
+		return 0
 	}
-	if err := uc.validatePlate(req.Plate); err != nil {
-		return err
+	return int(age / time.Second)
+}
+
+// recordSearch publishes a sampled, anonymized event of a nearby search
+func (uc *driverUseCase) recordSearch(lat, lon float64, taxiType *domain.TaxiType, results int) {
+	if uc.analytics == nil || uc.sample() >= uc.analyticsOpts.SampleRate {
+		return
+	}
+
+	scale := math.Pow(10, float64(uc.analyticsOpts.Precision))
+	event := &domain.NearbySearchPerformed{
+		Lat:         math.Round(lat*scale) / scale,
+		Lon:         math.Round(lon*scale) / scale,
+		ResultCount: results,
+		Timestamp:   uc.now().UTC().Truncate(time.Minute),
+	}
+	if taxiType != nil {
+		event.TaxiType = string(*taxiType)
+	}
+	uc.analytics.Publish(domain.EventNearbySearchPerformed, event)
+}
+
+// Bounds of route corridor searches
+const (
+	maxRouteWaypoints      = 25
+	maxRouteKm             = 100.0
+	defaultCorridorWidthKm = 0.5
+	maxCorridorWidthKm     = 2.0
+	// routeSearchStepKm is the longest piece of the route covered by one query
+	routeSearchStepKm = 5.0
+)
+
+// FindDriversAlongRoute finds drivers matching the filter within widthKm of
+// the route through the waypoints. The route is searched piece by piece, each
+// piece with a circle just wide enough to cover its stretch of the corridor,
+// and drivers found by several pieces are returned once.
+func (uc *driverUseCase) FindDriversAlongRoute(ctx context.Context, waypoints []domain.Location, widthKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter) ([]*RouteDriverResponse, error) {
+	if len(waypoints) < 2 || len(waypoints) > maxRouteWaypoints {
+		return nil, ErrRouteWaypoints
+	}
+	for _, point := range waypoints {
+		if err := validateCoordinates(point.Lat, point.Lon); err != nil {
+			return nil, err
+		}
+	}
+	if widthKm == 0 {
+		widthKm = defaultCorridorWidthKm
+	}
+	if widthKm < 0 || widthKm > maxCorridorWidthKm {
+		return nil, ErrInvalidCorridorWidth
+	}
+	if taxiType != nil && !taxiType.IsValid() {
+		return nil, fmt.Errorf("invalid taxiType: %s", *taxiType)
+	}
+
+	lengths := make([]float64, len(waypoints)-1)
+	var totalKm float64
+	for i := range lengths {
+		lengths[i] = haversine.Distance(waypoints[i].Lat, waypoints[i].Lon, waypoints[i+1].Lat, waypoints[i+1].Lon)
+		totalKm += lengths[i]
+	}
+	if totalKm > maxRouteKm {
+		return nil, ErrRouteTooLong
+	}
+
+	filter = uc.liveFilter(filter)
+	found := make(map[string]*RouteDriverResponse)
+	for i, lengthKm := range lengths {
+		from, to := waypoints[i], waypoints[i+1]
+		pieces := max(1, int(math.Ceil(lengthKm/routeSearchStepKm)))
+		for p := 0; p < pieces; p++ {
+			t := (float64(p) + 0.5) / float64(pieces)
+			lat := from.Lat + t*(to.Lat-from.Lat)
+			lon := from.Lon + t*(to.Lon-from.Lon)
+
+			drivers, err := uc.repo.FindNearby(ctx, lat, lon, lengthKm/float64(pieces)/2+widthKm, taxiType, filter, domain.NearbyPage{})
+			if err != nil {
+				uc.logger.Error("failed to find drivers along route", zap.Error(err))
+				return nil, errors.New("failed to find drivers along route")
+			}
+
+			for _, driver := range drivers {
+				if _, ok := found[driver.ID]; ok {
+					continue
+				}
+				distanceKm, routeKm := distanceToRoute(driver.Location, waypoints, lengths)
+				if distanceKm > widthKm {
+					continue
+				}
+				found[driver.ID] = &RouteDriverResponse{
+					ID:         driver.ID,
+					FirstName:  driver.FirstName,
+					LastName:   driver.LastName,
+					Plate:      driver.Plate,
+					TaxiType:   string(driver.TaxiType),
+					Location:   driver.Location,
+					DistanceKm: distanceKm,
+					RouteKm:    routeKm,
+				}
+			}
+		}
+	}
+
+	responses := make([]*RouteDriverResponse, 0, len(found))
+	for _, response := range found {
+		responses = append(responses, response)
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		if responses[i].DistanceKm != responses[j].DistanceKm {
+			return responses[i].DistanceKm < responses[j].DistanceKm
+		}
+		return responses[i].RouteKm < responses[j].RouteKm
+	})
+
+	uc.logger.Info("found drivers along route",
+		zap.Int("count", len(responses)), zap.Int("waypoints", len(waypoints)), zap.Float64("routeKm", totalKm))
+	return responses, nil
+}
+
+// distanceToRoute returns the distance from a location to the nearest point of
+// the route and how far along the route that point lies
+func distanceToRoute(location domain.Location, waypoints []domain.Location, lengths []float64) (distanceKm, routeKm float64) {
+	distanceKm = math.Inf(1)
+	var travelled float64
+	for i, lengthKm := range lengths {
+		d, fraction := haversine.DistanceToSegment(location.Lat, location.Lon,
+			waypoints[i].Lat, waypoints[i].Lon, waypoints[i+1].Lat, waypoints[i+1].Lon)
+		if d < distanceKm {
+			distanceKm, routeKm = d, travelled+fraction*lengthKm
+		}
+		travelled += lengthKm
+	}
+	return distanceKm, routeKm
+}
+
+// liveFilter limits the filter to drivers with a recent heartbeat when the
+// caller asked for live drivers, or by default when configured so
+func (uc *driverUseCase) liveFilter(filter domain.DriverFilter) domain.DriverFilter {
+	live := uc.liveByDefault
+	if filter.Live != nil {
+		live = *filter.Live
+	}
+	if live && uc.heartbeatTimeout > 0 {
+		filter.SeenSince = uc.now().Add(-uc.heartbeatTimeout)
+	}
+	return filter
+}
+
+// SetAvailability puts a driver on or off shift. Going on shift requires a
+// verified phone and a licence that has not expired.
+func (uc *driverUseCase) SetAvailability(ctx context.Context, id string, available bool) (*domain.Driver, error) {
+	driver, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	if available && driver.Suspended {
+		return nil, ErrDriverSuspended
+	}
+	if available && !driver.PhoneVerified {
+		return nil, ErrContactNotVerified
+	}
+	if available && (driver.LicenseExpired || (driver.License != nil && driver.License.Expired(uc.now()))) {
+		return nil, ErrLicenseExpired
+	}
+
+	driver.Available = available
+	if err := uc.repo.Update(ctx, id, driver); err != nil {
+		uc.logger.Error("failed to update driver availability", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to update driver")
+	}
+
+	uc.recordShift(ctx, id, available)
+	uc.logger.Info("driver availability changed", append(actorFields(ctx), zap.String("id", id), zap.Bool("available", available))...)
+	uc.publish(ctx, domain.EventDriverUpdated, driver, "")
+	return driver, nil
+}
+
+// SetSuspension suspends or reinstates a driver. Suspending also takes the driver off shift.
+func (uc *driverUseCase) SetSuspension(ctx context.Context, id string, suspended bool, reason string) (*domain.Driver, error) {
+	driver, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	wasAvailable := driver.Available
+	driver.Suspended = suspended
+	driver.SuspendReason = ""
+	if suspended {
+		driver.SuspendReason = strings.TrimSpace(reason)
+		driver.Available = false
+	}
+	if err := uc.repo.Update(ctx, id, driver); err != nil {
+		uc.logger.Error("failed to update driver suspension", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to update driver")
+	}
+
+	if wasAvailable && !driver.Available {
+		uc.recordShift(ctx, id, false)
+	}
+	uc.logger.Info("driver suspension changed", append(actorFields(ctx), zap.String("id", id), zap.Bool("suspended", suspended))...)
+	if suspended {
+		uc.publish(ctx, domain.EventDriverSuspended, driver, driver.SuspendReason)
+	} else {
+		uc.publish(ctx, domain.EventDriverUpdated, driver, "")
+	}
+	return driver, nil
+}
+
+// publish notifies subscribers of a driver change when events are enabled
+func (uc *driverUseCase) publish(ctx context.Context, event string, driver *domain.Driver, reason string) {
+	if uc.events == nil {
+		return
+	}
+	uc.events.PublishDriverEvent(ctx, event, driver, reason)
+}
+
+// recordLocation appends to the location history; failures only lose statistics, so they are logged
+func (uc *driverUseCase) recordLocation(ctx context.Context, id string, location domain.Location) {
+	if uc.activity == nil {
+		return
+	}
+	if err := uc.activity.RecordLocation(ctx, id, location, uc.now()); err != nil {
+		uc.logger.Warn("failed to record driver location", zap.Error(err), zap.String("id", id))
+	}
+}
+
+// recordShift opens or closes the driver's shift
+func (uc *driverUseCase) recordShift(ctx context.Context, id string, onShift bool) {
+	if uc.activity == nil {
+		return
+	}
+	var err error
+	if onShift {
+		err = uc.activity.StartShift(ctx, id, uc.now())
+	} else {
+		err = uc.activity.EndShift(ctx, id, uc.now())
+	}
+	if err != nil {
+		uc.logger.Warn("failed to record driver shift", zap.Error(err), zap.String("id", id), zap.Bool("onShift", onShift))
+	}
+}
+
+// DeleteDriver removes a driver, e.g. to roll back an onboarding that failed part-way
+func (uc *driverUseCase) DeleteDriver(ctx context.Context, id string) error {
+	// Only scoped callers need the driver loaded for the ownership check
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleFleetAdmin {
+		driver, err := uc.repo.GetByID(ctx, id)
+		if err != nil {
+			return errors.New("driver not found")
+		}
+		if err := authorizeDriver(ctx, driver); err != nil {
+			return err
+		}
 	}
-	if !req.TaxiType.IsValid() {
-		return fmt.Errorf("invalid taxiType: %s. Must be one of: sari, turkuaz, siyah", req.TaxiType)
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		if err.Error() == "driver not found" || err.Error() == "invalid driver ID" {
+			return errors.New("driver not found")
+		}
+		uc.logger.Error("failed to delete driver", zap.Error(err), zap.String("id", id))
+		return errors.New("failed to delete driver")
 	}
-	if req.CarBrand == "" {
-		return errors.New("carBrand is required")
+
+	uc.logger.Info("driver deleted", append(actorFields(ctx), zap.String("id", id))...)
+	return nil
+}
+
+// applyContactUpdate validates and applies phone/email changes, resetting verification on change
+func (uc *driverUseCase) applyContactUpdate(ctx context.Context, driver *domain.Driver, phone, email *string) error {
+	fieldRules := uc.rules.For(driver.FleetID)
+	newPhone, newEmail := "", ""
+	if phone != nil {
+		newPhone = normalizePhone(*phone)
+		if err := validatePhone(newPhone); err != nil {
+			return err
+		}
+		if err := fieldRules.CheckFormat(rules.FieldPhone, newPhone); err != nil {
+			return err
+		}
 	}
-	if req.CarModel == "" {
-		return errors.New("carModel is required")
+	if email != nil {
+		newEmail = normalizeEmail(*email)
+		if err := validateEmail(newEmail); err != nil {
+			return err
+		}
+		if err := fieldRules.CheckFormat(rules.FieldEmail, newEmail); err != nil {
+			return err
+		}
+	}
+
+	phoneChanged := phone != nil && newPhone != driver.Phone
+	emailChanged := email != nil && newEmail != driver.Email
+	checkPhone, checkEmail := "", ""
+	if phoneChanged {
+		checkPhone = newPhone
 	}
-	if err := uc.validateLocation(req.Lat, req.Lon); err != nil {
+	if emailChanged {
+		checkEmail = newEmail
+	}
+	if err := uc.ensureContactAvailable(ctx, driver.ID, checkPhone, checkEmail); err != nil {
 		return err
 	}
+
+	if phoneChanged {
+		driver.Phone = newPhone
+		driver.PhoneVerified = false
+		// An unverified driver cannot stay on shift
+		driver.Available = false
+	}
+	if emailChanged {
+		driver.Email = newEmail
+		driver.EmailVerified = false
+	}
 	return nil
 }
 
-// validatePlate validates Turkish license plate format (simplified: 2-3 digits + 1-3 letters + 1-4 digits)
-func (uc *driverUseCase) validatePlate(plate string) error {
+// ensureContactAvailable checks that the phone/email are not used by another driver
+func (uc *driverUseCase) ensureContactAvailable(ctx context.Context, driverID, phone, email string) error {
+	if phone != "" {
+		existing, err := uc.repo.GetByPhone(ctx, phone)
+		if err != nil && err.Error() != "driver not found" {
+			uc.logger.Error("failed to check phone uniqueness", zap.Error(err))
+			return errors.New("failed to validate contact details")
+		}
+		if existing != nil && existing.ID != driverID {
+			return ErrPhoneTaken
+		}
+	}
+	if email != "" {
+		existing, err := uc.repo.GetByEmail(ctx, email)
+		if err != nil && err.Error() != "driver not found" {
+			uc.logger.Error("failed to check email uniqueness", zap.Error(err))
+			return errors.New("failed to validate contact details")
+		}
+		if existing != nil && existing.ID != driverID {
+			return ErrEmailTaken
+		}
+	}
+	return nil
+}
+
+// ensurePlateAvailable checks that the plate is not used by another driver
+func (uc *driverUseCase) ensurePlateAvailable(ctx context.Context, driverID, plate string) error {
 	if plate == "" {
-		return errors.New("plate is required")
+		return nil
+	}
+	existing, err := uc.repo.GetByPlate(ctx, plate)
+	if err != nil && err.Error() != "driver not found" {
+		uc.logger.Error("failed to check plate uniqueness", zap.Error(err))
+		return errors.New("failed to validate plate")
+	}
+	if existing != nil && existing.ID != driverID {
+		return ErrPlateTaken
+	}
+	return nil
+}
+
+// contactTaken maps a repository write rejected by a unique index, which
+// happens when a request on another instance stored the same phone, email or
+// plate after this one checked them, to the error the availability check returns
+func contactTaken(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrDuplicatePhone):
+		return ErrPhoneTaken
+	case errors.Is(err, domain.ErrDuplicateEmail):
+		return ErrEmailTaken
+	case errors.Is(err, domain.ErrDuplicatePlate):
+		return ErrPlateTaken
+	}
+	return nil
+}
+
+// lockKey is the key of the lock held on a driver, plate, phone or email while
+// it is written; an empty value gives an empty key, which takes no lock
+func lockKey(kind, value string) string {
+	if value == "" {
+		return ""
 	}
-	// Turkish plate format: 34ABC123 or 34AB123 or 34A123
-	plateRegex := regexp.MustCompile(`^[0-9]{2,3}[A-Z]{1,3}[0-9]{1,4}$`)
-	if !plateRegex.MatchString(strings.ToUpper(plate)) {
-		return errors.New("plate must be in format: 2-3 digits, 1-3 letters, 1-4 digits (e.g., 34ABC123)")
+	return kind + ":" + value
+}
+
+// validateCreateRequest validates the create driver request under the rules
+// of the driver's tenant and normalizes its plate
+func (uc *driverUseCase) validateCreateRequest(req *CreateDriverRequest) error {
+	fieldRules := uc.rules.For(req.FleetID)
+	if err := fieldRules.Check(rules.FieldFirstName, req.FirstName); err != nil {
+		return err
+	}
+	if err := fieldRules.Check(rules.FieldLastName, req.LastName); err != nil {
+		return err
+	}
+	normalized, err := fieldRules.NormalizePlate(req.Plate)
+	if err != nil {
+		return err
+	}
+	req.Plate = normalized
+	if err := fieldRules.CheckTaxiType(req.TaxiType); err != nil {
+		return err
+	}
+	if err := fieldRules.Check(rules.FieldCarBrand, req.CarBrand); err != nil {
+		return err
+	}
+	if err := fieldRules.Check(rules.FieldCarModel, req.CarModel); err != nil {
+		return err
+	}
+	location, err := uc.normalizeLocation(req.Lat, req.Lon)
+	if err != nil {
+		return err
+	}
+	req.Lat, req.Lon = location.Lat, location.Lon
+	if req.Phone != "" {
+		if err := validatePhone(normalizePhone(req.Phone)); err != nil {
+			return err
+		}
+	}
+	if err := fieldRules.Check(rules.FieldPhone, normalizePhone(req.Phone)); err != nil {
+		return err
+	}
+	if req.Email != "" {
+		if err := validateEmail(normalizeEmail(req.Email)); err != nil {
+			return err
+		}
+	}
+	if err := fieldRules.Check(rules.FieldEmail, normalizeEmail(req.Email)); err != nil {
+		return err
+	}
+	if req.License == nil && fieldRules.Requires(rules.FieldLicense) {
+		return &rules.ValidationError{Field: rules.FieldLicense, Message: "license is required"}
 	}
 	return nil
 }
 
+// checkUpdate validates a new value for a driver field; required fields cannot
+// be cleared
+func checkUpdate(fieldRules *rules.Ruleset, field, value string) error {
+	if value == "" && fieldRules.Requires(field) {
+		return &rules.ValidationError{Field: field, Message: field + " cannot be empty"}
+	}
+	return fieldRules.CheckFormat(field, value)
+}
+
 // validateLocation validates latitude and longitude
 func (uc *driverUseCase) validateLocation(lat, lon float64) error {
+	return validateCoordinates(lat, lon)
+}
+
+// normalizeLocation validates a position a driver is stored with and, with a
+// service area, rounds it and checks it is inside
+func (uc *driverUseCase) normalizeLocation(lat, lon float64) (domain.Location, error) {
+	if err := uc.validateLocation(lat, lon); err != nil {
+		return domain.Location{}, err
+	}
+	location := domain.Location{Lat: lat, Lon: lon}
+	if uc.area == nil {
+		return location, nil
+	}
+	normalized, err := uc.area.Normalize(location)
+	switch {
+	case errors.Is(err, geofence.ErrUnknownLocation):
+		return domain.Location{}, ErrUnknownLocation
+	case errors.Is(err, geofence.ErrOutsideServiceArea):
+		return domain.Location{}, ErrOutsideServiceArea
+	}
+	return normalized, err
+}
+
+// validateCoordinates checks that latitude and longitude are within range
+func validateCoordinates(lat, lon float64) error {
 	if lat < -90 || lat > 90 {
 		return errors.New("latitude must be between -90 and 90")
 	}
@@ -3,9 +3,14 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/geofence"
+	"github.com/bitaksi/driver-service/internal/rules"
 	"go.uber.org/zap"
 )
 
@@ -17,6 +22,10 @@ type mockDriverRepository struct {
 	shouldFailList       bool
 	shouldFailGet        bool
 	shouldFailFindNearby bool
+	// nearbyPage is the page FindNearby was last asked for
+	nearbyPage domain.NearbyPage
+	// writeErr is returned by Create and Update when set
+	writeErr error
 }
 
 func newMockDriverRepository() *mockDriverRepository {
@@ -29,6 +38,9 @@ func (m *mockDriverRepository) Create(ctx interface{}, driver *domain.Driver) er
 	if m.shouldFailCreate {
 		return errors.New("repository error")
 	}
+	if m.writeErr != nil {
+		return m.writeErr
+	}
 	if driver.Plate == "" {
 		return errors.New("plate is required")
 	}
@@ -44,6 +56,9 @@ func (m *mockDriverRepository) Update(ctx interface{}, id string, driver *domain
 	if m.shouldFailUpdate {
 		return errors.New("repository error")
 	}
+	if m.writeErr != nil {
+		return m.writeErr
+	}
 	if _, exists := m.drivers[id]; !exists {
 		return errors.New("driver not found")
 	}
@@ -62,13 +77,15 @@ func (m *mockDriverRepository) GetByID(ctx interface{}, id string) (*domain.Driv
 	return driver, nil
 }
 
-func (m *mockDriverRepository) List(ctx interface{}, page, pageSize int) ([]*domain.Driver, int64, error) {
+func (m *mockDriverRepository) List(ctx interface{}, filter domain.DriverFilter, page, pageSize int) ([]*domain.Driver, int64, error) {
 	if m.shouldFailList {
 		return nil, 0, errors.New("repository error")
 	}
 	drivers := make([]*domain.Driver, 0, len(m.drivers))
 	for _, driver := range m.drivers {
-		drivers = append(drivers, driver)
+		if filter.FleetID == "" || driver.FleetID == filter.FleetID {
+			drivers = append(drivers, driver)
+		}
 	}
 	// Simulate pagination
 	start := (page - 1) * pageSize
@@ -85,17 +102,59 @@ func (m *mockDriverRepository) List(ctx interface{}, page, pageSize int) ([]*dom
 	return drivers[start:end], int64(len(drivers)), nil
 }
 
-func (m *mockDriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType) ([]*domain.Driver, error) {
+func (m *mockDriverRepository) FindNearby(ctx interface{}, lat, lon float64, radiusKm float64, taxiType *domain.TaxiType, filter domain.DriverFilter, page domain.NearbyPage) ([]*domain.Driver, error) {
+	m.nearbyPage = page
 	if m.shouldFailFindNearby {
 		return nil, errors.New("repository error")
 	}
 	drivers := make([]*domain.Driver, 0)
 	for _, driver := range m.drivers {
+		if filter.FleetID != "" && driver.FleetID != filter.FleetID {
+			continue
+		}
+		if !filter.SeenSince.IsZero() && (driver.LastSeenAt == nil || driver.LastSeenAt.Before(filter.SeenSince)) {
+			continue
+		}
 		if taxiType == nil || driver.TaxiType == *taxiType {
 			drivers = append(drivers, driver)
 		}
 	}
-	return drivers, nil
+	return page.Apply(drivers), nil
+}
+
+func (m *mockDriverRepository) GetByPhone(ctx interface{}, phone string) (*domain.Driver, error) {
+	for _, driver := range m.drivers {
+		if driver.Phone == phone {
+			return driver, nil
+		}
+	}
+	return nil, errors.New("driver not found")
+}
+
+func (m *mockDriverRepository) GetByEmail(ctx interface{}, email string) (*domain.Driver, error) {
+	for _, driver := range m.drivers {
+		if driver.Email == email {
+			return driver, nil
+		}
+	}
+	return nil, errors.New("driver not found")
+}
+
+func (m *mockDriverRepository) GetByPlate(ctx interface{}, plate string) (*domain.Driver, error) {
+	for _, driver := range m.drivers {
+		if driver.Plate == plate {
+			return driver, nil
+		}
+	}
+	return nil, errors.New("driver not found")
+}
+
+func (m *mockDriverRepository) Delete(ctx interface{}, id string) error {
+	if _, ok := m.drivers[id]; !ok {
+		return errors.New("driver not found")
+	}
+	delete(m.drivers, id)
+	return nil
 }
 
 func TestDriverUseCase_CreateDriver(t *testing.T) {
@@ -194,7 +253,7 @@ func TestDriverUseCase_CreateDriver(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "valid plate format 3 digits",
+			name: "three digit province code",
 			req: &CreateDriverRequest{
 				FirstName: "Ahmet",
 				LastName:  "Demir",
@@ -205,6 +264,36 @@ func TestDriverUseCase_CreateDriver(t *testing.T) {
 				Lat:       41.0431,
 				Lon:       29.0099,
 			},
+			wantErr: true,
+			errMsg:  "plate must be in format",
+		},
+		{
+			name: "province code out of range",
+			req: &CreateDriverRequest{
+				FirstName: "Ahmet",
+				LastName:  "Demir",
+				Plate:     "82ABC123",
+				TaxiType:  domain.TaxiTypeSari,
+				CarBrand:  "Toyota",
+				CarModel:  "Corolla",
+				Lat:       41.0431,
+				Lon:       29.0099,
+			},
+			wantErr: true,
+			errMsg:  "province code 82",
+		},
+		{
+			name: "plate with spaces is normalized",
+			req: &CreateDriverRequest{
+				FirstName: "Ahmet",
+				LastName:  "Demir",
+				Plate:     "34 abc 123",
+				TaxiType:  domain.TaxiTypeSari,
+				CarBrand:  "Toyota",
+				CarModel:  "Corolla",
+				Lat:       41.0431,
+				Lon:       29.0099,
+			},
 			wantErr: false,
 		},
 		{
@@ -384,7 +473,7 @@ func TestDriverUseCase_UpdateDriver(t *testing.T) {
 			name: "update plate",
 			id:   driverID,
 			req: &UpdateDriverRequest{
-				Plate: stringPtr("34XYZ789"),
+				Plate: stringPtr("34KYZ789"),
 			},
 			wantErr: false,
 		},
@@ -558,7 +647,7 @@ func TestDriverUseCase_ListDrivers(t *testing.T) {
 		req := &CreateDriverRequest{
 			FirstName: "Driver",
 			LastName:  "Test",
-			Plate:     "34ABC" + string(rune('0'+i)),
+			Plate:     "34ABC1" + string(rune('0'+i)),
 			TaxiType:  domain.TaxiTypeSari,
 			CarBrand:  "Toyota",
 			CarModel:  "Corolla",
@@ -628,7 +717,7 @@ func TestDriverUseCase_ListDrivers(t *testing.T) {
 				req := &CreateDriverRequest{
 					FirstName: "Driver",
 					LastName:  "Test",
-					Plate:     "34ABC" + string(rune('0'+i)),
+					Plate:     "34ABC1" + string(rune('0'+i)),
 					TaxiType:  domain.TaxiTypeSari,
 					CarBrand:  "Toyota",
 					CarModel:  "Corolla",
@@ -642,7 +731,7 @@ func TestDriverUseCase_ListDrivers(t *testing.T) {
 				repo.shouldFailList = true
 			}
 
-			response, err := uc.ListDrivers(context.Background(), tt.page, tt.pageSize)
+			response, err := uc.ListDrivers(context.Background(), domain.DriverFilter{}, tt.page, tt.pageSize)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
@@ -659,6 +748,47 @@ func TestDriverUseCase_ListDrivers(t *testing.T) {
 	}
 }
 
+func TestDriverUseCase_ListDriversPaging(t *testing.T) {
+	repo := newMockDriverRepository()
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("driver-%d", i)
+		repo.drivers[id] = &domain.Driver{ID: id}
+	}
+
+	tests := []struct {
+		name         string
+		opts         []DriverUseCaseOption
+		page         int
+		pageSize     int
+		wantPageSize int
+		wantPages    int
+		wantNext     bool
+		wantPrev     bool
+	}{
+		{name: "first of three", page: 1, pageSize: 2, wantPageSize: 2, wantPages: 3, wantNext: true},
+		{name: "middle", page: 2, pageSize: 2, wantPageSize: 2, wantPages: 3, wantNext: true, wantPrev: true},
+		{name: "last", page: 3, pageSize: 2, wantPageSize: 2, wantPages: 3, wantPrev: true},
+		{name: "past the end", page: 5, pageSize: 2, wantPageSize: 2, wantPages: 3, wantPrev: true},
+		{name: "built-in default", page: 1, wantPageSize: 20, wantPages: 1},
+		{name: "configured default", opts: []DriverUseCaseOption{WithPageSizes(4, 10)}, page: 1, wantPageSize: 4, wantPages: 2, wantNext: true},
+		{name: "configured max", opts: []DriverUseCaseOption{WithPageSizes(4, 10)}, page: 1, pageSize: 50, wantPageSize: 10, wantPages: 1},
+		{name: "default above max", opts: []DriverUseCaseOption{WithPageSizes(30, 3)}, page: 1, wantPageSize: 3, wantPages: 2, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewDriverUseCase(repo, zap.NewNop(), tt.opts...)
+			resp, err := uc.ListDrivers(context.Background(), domain.DriverFilter{}, tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.PageSize != tt.wantPageSize || resp.TotalPages != tt.wantPages || resp.HasNext != tt.wantNext || resp.HasPrev != tt.wantPrev {
+				t.Errorf("got pageSize=%d totalPages=%d hasNext=%v hasPrev=%v", resp.PageSize, resp.TotalPages, resp.HasNext, resp.HasPrev)
+			}
+		})
+	}
+}
+
 func TestDriverUseCase_GetDriver(t *testing.T) {
 	logger := zap.NewNop()
 	repo := newMockDriverRepository()
@@ -733,7 +863,7 @@ func TestDriverUseCase_FindNearbyDrivers(t *testing.T) {
 		plate    string
 	}{
 		{41.0431, 29.0099, "34ABC123"}, // Close to search point
-		{41.0082, 28.9784, "34XYZ789"}, // Close to search point
+		{41.0082, 28.9784, "34KYZ789"}, // Close to search point
 		{39.9334, 32.8597, "06DEF456"}, // Far (Ankara)
 	}
 
@@ -818,7 +948,7 @@ func TestDriverUseCase_FindNearbyDrivers(t *testing.T) {
 					plate    string
 				}{
 					{41.0431, 29.0099, "34ABC123"},
-					{41.0082, 28.9784, "34XYZ789"},
+					{41.0082, 28.9784, "34KYZ789"},
 					{39.9334, 32.8597, "06DEF456"},
 				}
 
@@ -841,7 +971,7 @@ func TestDriverUseCase_FindNearbyDrivers(t *testing.T) {
 				repo.shouldFailFindNearby = true
 			}
 
-			drivers, err := uc.FindNearbyDrivers(context.Background(), tt.lat, tt.lon, tt.taxiType)
+			drivers, err := uc.FindNearbyDrivers(context.Background(), tt.lat, tt.lon, tt.taxiType, domain.DriverFilter{}, domain.NearbyPage{})
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error but got none")
@@ -884,3 +1014,901 @@ func containsMiddle(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDriverUseCase_FindNearbyDriversLive(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	fresh := now.Add(-30 * time.Second)
+	stale := now.Add(-10 * time.Minute)
+	repo := newMockDriverRepository()
+	repo.drivers["fresh"] = &domain.Driver{ID: "fresh", TaxiType: domain.TaxiTypeSari, LastSeenAt: &fresh}
+	repo.drivers["stale"] = &domain.Driver{ID: "stale", TaxiType: domain.TaxiTypeSari, LastSeenAt: &stale}
+	repo.drivers["never"] = &domain.Driver{ID: "never", TaxiType: domain.TaxiTypeSari}
+
+	live, notLive := true, false
+	tests := []struct {
+		name          string
+		liveByDefault bool
+		live          *bool
+		wantCount     int
+	}{
+		{name: "filter off by default", wantCount: 3},
+		{name: "filter on by default", liveByDefault: true, wantCount: 1},
+		{name: "request opts in", live: &live, wantCount: 1},
+		{name: "request opts out", liveByDefault: true, live: &notLive, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewDriverUseCase(repo, zap.NewNop(), WithHeartbeatFilter(2*time.Minute, tt.liveByDefault))
+			uc.(*driverUseCase).now = func() time.Time { return now }
+
+			drivers, err := uc.FindNearbyDrivers(context.Background(), 41.0431, 29.0099, nil, domain.DriverFilter{Live: tt.live}, domain.NearbyPage{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(drivers) != tt.wantCount {
+				t.Errorf("expected %d drivers, got %d", tt.wantCount, len(drivers))
+			}
+		})
+	}
+}
+
+func TestDriverUseCase_FindNearbyDriversStaleness(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	located := now.Add(-90 * time.Second)
+	updated := now.Add(-5 * time.Minute)
+	skewed := now.Add(3 * time.Second)
+	repo := newMockDriverRepository()
+	repo.drivers["reported"] = &domain.Driver{ID: "reported", TaxiType: domain.TaxiTypeSari, LocationUpdatedAt: &located, UpdatedAt: now}
+	repo.drivers["legacy"] = &domain.Driver{ID: "legacy", TaxiType: domain.TaxiTypeSari, UpdatedAt: updated}
+	repo.drivers["skewed"] = &domain.Driver{ID: "skewed", TaxiType: domain.TaxiTypeSari, LocationUpdatedAt: &skewed}
+	uc := NewDriverUseCase(repo, zap.NewNop())
+	uc.(*driverUseCase).now = func() time.Time { return now }
+
+	drivers, err := uc.FindNearbyDrivers(context.Background(), 41.0431, 29.0099, nil, domain.DriverFilter{}, domain.NearbyPage{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]struct {
+		at    time.Time
+		stale int
+	}{
+		"reported": {located, 90},
+		"legacy":   {updated, 300},
+		"skewed":   {skewed, 0},
+	}
+	if len(drivers) != len(want) {
+		t.Fatalf("expected %d drivers, got %d", len(want), len(drivers))
+	}
+	for _, driver := range drivers {
+		w := want[driver.ID]
+		if !driver.LastLocationUpdate.Equal(w.at) || driver.StaleSeconds != w.stale {
+			t.Errorf("%s: expected %v and %ds, got %v and %ds", driver.ID, w.at, w.stale, driver.LastLocationUpdate, driver.StaleSeconds)
+		}
+	}
+}
+
+func TestDriverUseCase_FindNearbyDriversPaging(t *testing.T) {
+	repo := newMockDriverRepository()
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("driver-%d", i)
+		repo.drivers[id] = &domain.Driver{ID: id, TaxiType: domain.TaxiTypeSari}
+	}
+
+	tests := []struct {
+		name     string
+		opts     []DriverUseCaseOption
+		page     domain.NearbyPage
+		wantPage domain.NearbyPage
+		wantLen  int
+	}{
+		{name: "built-in default", wantPage: domain.NearbyPage{Limit: 100}, wantLen: 5},
+		{name: "requested", page: domain.NearbyPage{Limit: 2, Offset: 1}, wantPage: domain.NearbyPage{Limit: 2, Offset: 1}, wantLen: 2},
+		{name: "configured default", opts: []DriverUseCaseOption{WithNearbyLimits(3, 10)}, wantPage: domain.NearbyPage{Limit: 3}, wantLen: 3},
+		{name: "configured max", opts: []DriverUseCaseOption{WithNearbyLimits(3, 4)}, page: domain.NearbyPage{Limit: 50}, wantPage: domain.NearbyPage{Limit: 4}, wantLen: 4},
+		{name: "default above max", opts: []DriverUseCaseOption{WithNearbyLimits(30, 2)}, wantPage: domain.NearbyPage{Limit: 2}, wantLen: 2},
+		{name: "negative offset", page: domain.NearbyPage{Limit: 2, Offset: -3}, wantPage: domain.NearbyPage{Limit: 2}, wantLen: 2},
+		{name: "past the end", page: domain.NearbyPage{Limit: 2, Offset: 9}, wantPage: domain.NearbyPage{Limit: 2, Offset: 9}, wantLen: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewDriverUseCase(repo, zap.NewNop(), tt.opts...)
+			drivers, err := uc.FindNearbyDrivers(context.Background(), 41.0431, 29.0099, nil, domain.DriverFilter{}, tt.page)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repo.nearbyPage != tt.wantPage {
+				t.Errorf("expected the repository to be asked for %+v, got %+v", tt.wantPage, repo.nearbyPage)
+			}
+			if len(drivers) != tt.wantLen {
+				t.Errorf("expected %d drivers, got %d", tt.wantLen, len(drivers))
+			}
+		})
+	}
+}
+
+func TestDriverUseCase_UpdateDriverStampsLocation(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	repo := newMockDriverRepository()
+	repo.drivers["d1"] = &domain.Driver{ID: "d1", FirstName: "Ahmet", LastName: "Demir", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	uc := NewDriverUseCase(repo, zap.NewNop())
+	uc.(*driverUseCase).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	brand := "Fiat"
+	driver, err := uc.UpdateDriver(ctx, "d1", &UpdateDriverRequest{CarBrand: &brand})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.LocationUpdatedAt != nil {
+		t.Fatalf("expected no location timestamp without a position, got %v", driver.LocationUpdatedAt)
+	}
+
+	// The same position reported again is still a fresh report
+	lat, lon := 41.0431, 29.0099
+	driver, err = uc.UpdateDriver(ctx, "d1", &UpdateDriverRequest{Lat: &lat, Lon: &lon})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.LocationUpdatedAt == nil || !driver.LocationUpdatedAt.Equal(now) {
+		t.Errorf("expected location timestamp %v, got %v", now, driver.LocationUpdatedAt)
+	}
+}
+
+func TestDriverUseCase_ServiceArea(t *testing.T) {
+	area, err := geofence.Load("", 4)
+	if err != nil {
+		t.Fatalf("failed to load service area: %v", err)
+	}
+	repo := newMockDriverRepository()
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithServiceArea(area))
+	ctx := context.Background()
+	newRequest := func(lat, lon float64) *CreateDriverRequest {
+		return &CreateDriverRequest{
+			FirstName: "Ahmet",
+			LastName:  "Demir",
+			Plate:     "34ABC123",
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Lat:       lat,
+			Lon:       lon,
+		}
+	}
+
+	driver, err := uc.CreateDriver(ctx, newRequest(41.043149, 29.009951))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.Location != (domain.Location{Lat: 41.0431, Lon: 29.01}) {
+		t.Errorf("expected the location rounded to 41.0431,29.01, got %v", driver.Location)
+	}
+
+	if _, err := uc.CreateDriver(ctx, newRequest(0, 0)); !errors.Is(err, ErrUnknownLocation) {
+		t.Errorf("expected ErrUnknownLocation for 0,0, got %v", err)
+	}
+	if _, err := uc.CreateDriver(ctx, newRequest(37.9838, 23.7275)); !errors.Is(err, ErrOutsideServiceArea) {
+		t.Errorf("expected ErrOutsideServiceArea for Athens, got %v", err)
+	}
+	if _, err := uc.CreateDriver(ctx, newRequest(91, 29)); err == nil || err.Error() != "latitude must be between -90 and 90" {
+		t.Errorf("expected the range error first, got %v", err)
+	}
+
+	lat, lon := 42.6977, 23.3219
+	if _, err := uc.UpdateDriver(ctx, driver.ID, &UpdateDriverRequest{Lat: &lat, Lon: &lon}); !errors.Is(err, ErrOutsideServiceArea) {
+		t.Errorf("expected ErrOutsideServiceArea for Sofia, got %v", err)
+	}
+	if stored := repo.drivers[driver.ID]; stored.Location != (domain.Location{Lat: 41.0431, Lon: 29.01}) {
+		t.Errorf("expected the stored location to be kept, got %v", stored.Location)
+	}
+}
+
+// recordingAnalytics keeps published analytics events
+type recordingAnalytics struct {
+	events []interface{}
+}
+
+func (p *recordingAnalytics) Publish(eventType string, data interface{}) {
+	p.events = append(p.events, data)
+}
+
+func TestDriverUseCase_SearchAnalytics(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 2, 33, 0, time.UTC)
+	repo := newMockDriverRepository()
+	repo.drivers["near"] = &domain.Driver{ID: "near", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	publisher := &recordingAnalytics{}
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithSearchAnalytics(publisher, SearchAnalyticsOptions{SampleRate: 0.5, Precision: 2}))
+	uc.(*driverUseCase).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Searches drawn above the sample rate are not reported
+	uc.(*driverUseCase).sample = func() float64 { return 0.7 }
+	if _, err := uc.FindNearbyDrivers(ctx, 41.0431, 29.0099, nil, domain.DriverFilter{}, domain.NearbyPage{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(publisher.events) != 0 {
+		t.Fatalf("expected no event, got %d", len(publisher.events))
+	}
+
+	uc.(*driverUseCase).sample = func() float64 { return 0.2 }
+	taxiType := domain.TaxiTypeSari
+	if _, err := uc.FindNearbyDrivers(ctx, 41.0431, 29.0099, &taxiType, domain.DriverFilter{}, domain.NearbyPage{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(publisher.events))
+	}
+	event := publisher.events[0].(*domain.NearbySearchPerformed)
+	want := domain.NearbySearchPerformed{
+		Lat:         41.04,
+		Lon:         29.01,
+		TaxiType:    "sari",
+		ResultCount: 1,
+		Timestamp:   time.Date(2025, 12, 6, 1, 2, 0, 0, time.UTC),
+	}
+	if *event != want {
+		t.Errorf("expected %+v, got %+v", want, *event)
+	}
+
+	// Failed searches are not reported
+	if _, err := uc.FindNearbyDrivers(ctx, 91, 29.0099, nil, domain.DriverFilter{}, domain.NearbyPage{}); err == nil {
+		t.Fatal("expected an error for an invalid latitude")
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("expected failed searches to be skipped, got %d events", len(publisher.events))
+	}
+}
+
+func TestDriverUseCase_FindDriversAlongRoute(t *testing.T) {
+	repo := newMockDriverRepository()
+	uc := NewDriverUseCase(repo, zap.NewNop())
+	ctx := context.Background()
+
+	// A route heading east along the 41st parallel and then north
+	route := []domain.Location{{Lat: 41.0, Lon: 29.0}, {Lat: 41.0, Lon: 29.1}, {Lat: 41.1, Lon: 29.1}}
+	drivers := map[string]domain.Location{
+		"start":   {Lat: 41.002, Lon: 29.001},   // ~0.2 km off the first waypoint
+		"corner":  {Lat: 41.0, Lon: 29.1},       // on the route
+		"north":   {Lat: 41.05, Lon: 29.104},    // ~0.34 km east of the second leg
+		"outside": {Lat: 41.02, Lon: 29.05},     // ~2.2 km north of the first leg
+		"far":     {Lat: 39.9334, Lon: 32.8597}, // Ankara
+	}
+	for id, location := range drivers {
+		repo.drivers[id] = &domain.Driver{ID: id, TaxiType: domain.TaxiTypeSari, Location: location}
+	}
+
+	results, err := uc.FindDriversAlongRoute(ctx, route, 0, nil, domain.DriverFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The route is searched in several pieces and the mock returns every driver
+	// for each of them; each driver must still be listed once
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	if strings.Join(ids, ",") != "corner,start,north" {
+		t.Fatalf("expected corner,start,north sorted by distance, got %v", ids)
+	}
+	if results[0].RouteKm < 8.3 || results[0].RouteKm > 8.5 {
+		t.Errorf("expected the corner about 8.4 km along the route, got %.2f", results[0].RouteKm)
+	}
+
+	wide, err := uc.FindDriversAlongRoute(ctx, route, 2, nil, domain.DriverFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(wide) != 3 {
+		t.Errorf("expected 3 drivers within 2 km, got %d", len(wide))
+	}
+
+	tests := []struct {
+		name      string
+		waypoints []domain.Location
+		widthKm   float64
+		wantErr   error
+	}{
+		{name: "single waypoint", waypoints: route[:1], wantErr: ErrRouteWaypoints},
+		{name: "too many waypoints", waypoints: make([]domain.Location, maxRouteWaypoints+1), wantErr: ErrRouteWaypoints},
+		{name: "corridor too wide", waypoints: route, widthKm: 2.5, wantErr: ErrInvalidCorridorWidth},
+		{name: "negative corridor", waypoints: route, widthKm: -1, wantErr: ErrInvalidCorridorWidth},
+		{name: "route too long", waypoints: []domain.Location{{Lat: 41.0, Lon: 29.0}, {Lat: 39.9334, Lon: 32.8597}}, wantErr: ErrRouteTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.FindDriversAlongRoute(ctx, tt.waypoints, tt.widthKm, nil, domain.DriverFilter{}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := uc.FindDriversAlongRoute(ctx, []domain.Location{{Lat: 41.0, Lon: 29.0}, {Lat: 100, Lon: 29.0}}, 0, nil, domain.DriverFilter{}); err == nil {
+		t.Error("expected an error for an invalid waypoint")
+	}
+
+	repo.shouldFailFindNearby = true
+	if _, err := uc.FindDriversAlongRoute(ctx, route, 0, nil, domain.DriverFilter{}); err == nil {
+		t.Error("expected an error when the repository fails")
+	}
+}
+
+func TestDriverUseCase_ValidationRules(t *testing.T) {
+	engine, err := rules.New(rules.Config{
+		Countries: map[string]rules.Rules{
+			"AZ": {PlatePattern: `^[0-9]{2}[A-Z]{2}[0-9]{3}$`, TaxiTypes: []domain.TaxiType{domain.TaxiTypeSari}},
+		},
+		Tenants: map[string]rules.Rules{
+			"fleet-az": {Country: "AZ", RequiredFields: []string{rules.FieldFirstName, rules.FieldLastName, rules.FieldPhone}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := newMockDriverRepository()
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithValidationRules(engine))
+	ctx := context.Background()
+
+	newRequest := func() *CreateDriverRequest {
+		return &CreateDriverRequest{
+			FirstName: "Elvin",
+			LastName:  "Mammadov",
+			Plate:     "10-ab-123",
+			TaxiType:  domain.TaxiTypeSari,
+			Lat:       40.4093,
+			Lon:       49.8671,
+			Phone:     "+994501234567",
+			FleetID:   "fleet-az",
+		}
+	}
+
+	// The tenant does not require a car brand or model but does require a phone
+	driver, err := uc.CreateDriver(ctx, newRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.Plate != "10AB123" {
+		t.Errorf("expected the plate to be normalized, got %s", driver.Plate)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(req *CreateDriverRequest)
+		wantMsg string
+	}{
+		{name: "missing phone", modify: func(req *CreateDriverRequest) { req.Phone = "" }, wantMsg: "phone is required"},
+		{name: "turkish plate", modify: func(req *CreateDriverRequest) { req.Plate = "34ABC123" }, wantMsg: "plate must be in the AZ format"},
+		{name: "disallowed taxi type", modify: func(req *CreateDriverRequest) { req.TaxiType = domain.TaxiTypeSiyah }, wantMsg: "invalid taxiType: siyah. Must be one of: sari"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newRequest()
+			tt.modify(req)
+			_, err := uc.CreateDriver(ctx, req)
+			var validationErr *rules.ValidationError
+			if !errors.As(err, &validationErr) || err.Error() != tt.wantMsg {
+				t.Errorf("expected validation error %q, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+
+	// Updates follow the driver's tenant rules too
+	if _, err := uc.UpdateDriver(ctx, driver.ID, &UpdateDriverRequest{Plate: stringPtr("34ABC123")}); err == nil {
+		t.Error("expected a Turkish plate to be rejected for the tenant")
+	}
+	if _, err := uc.UpdateDriver(ctx, driver.ID, &UpdateDriverRequest{CarModel: stringPtr("")}); err != nil {
+		t.Errorf("expected the optional carModel to be clearable, got %v", err)
+	}
+	if _, err := uc.UpdateDriver(ctx, driver.ID, &UpdateDriverRequest{FirstName: stringPtr("")}); err == nil || err.Error() != "firstName cannot be empty" {
+		t.Errorf("expected firstName cannot be empty, got %v", err)
+	}
+
+	// Drivers outside the tenant keep the built-in rules
+	req := newRequest()
+	req.FleetID, req.Plate, req.CarBrand, req.CarModel = "", "34ABC123", "Toyota", ""
+	if _, err := uc.CreateDriver(ctx, req); err == nil || err.Error() != "carModel is required" {
+		t.Errorf("expected carModel is required, got %v", err)
+	}
+}
+
+func TestDriverUseCase_CreateDriverContact(t *testing.T) {
+	logger := zap.NewNop()
+
+	baseRequest := func() *CreateDriverRequest {
+		return &CreateDriverRequest{
+			FirstName: "Ahmet",
+			LastName:  "Demir",
+			Plate:     "34ABC123",
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Lat:       41.0431,
+			Lon:       29.0099,
+		}
+	}
+
+	t.Run("normalizes phone and email", func(t *testing.T) {
+		uc := NewDriverUseCase(newMockDriverRepository(), logger)
+		req := baseRequest()
+		req.Phone = "+90 532 123-45-67"
+		req.Email = " Ahmet.Demir@Example.com "
+
+		driver, err := uc.CreateDriver(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if driver.Phone != "+905321234567" {
+			t.Errorf("expected normalized phone, got %q", driver.Phone)
+		}
+		if driver.Email != "ahmet.demir@example.com" {
+			t.Errorf("expected normalized email, got %q", driver.Email)
+		}
+		if driver.PhoneVerified || driver.EmailVerified {
+			t.Errorf("new contacts must not be verified")
+		}
+	})
+
+	t.Run("rejects non E.164 phone", func(t *testing.T) {
+		uc := NewDriverUseCase(newMockDriverRepository(), logger)
+		req := baseRequest()
+		req.Phone = "05321234567"
+
+		if _, err := uc.CreateDriver(context.Background(), req); !errors.Is(err, ErrInvalidPhone) {
+			t.Errorf("expected ErrInvalidPhone, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid email", func(t *testing.T) {
+		uc := NewDriverUseCase(newMockDriverRepository(), logger)
+		req := baseRequest()
+		req.Email = "Ahmet <ahmet@example.com>"
+
+		if _, err := uc.CreateDriver(context.Background(), req); !errors.Is(err, ErrInvalidEmail) {
+			t.Errorf("expected ErrInvalidEmail, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate phone", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["existing"] = &domain.Driver{ID: "existing", Phone: "+905321234567"}
+		uc := NewDriverUseCase(repo, logger)
+		req := baseRequest()
+		req.Phone = "+905321234567"
+
+		if _, err := uc.CreateDriver(context.Background(), req); !errors.Is(err, ErrPhoneTaken) {
+			t.Errorf("expected ErrPhoneTaken, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate email", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["existing"] = &domain.Driver{ID: "existing", Email: "ahmet@example.com"}
+		uc := NewDriverUseCase(repo, logger)
+		req := baseRequest()
+		req.Email = "ahmet@example.com"
+
+		if _, err := uc.CreateDriver(context.Background(), req); !errors.Is(err, ErrEmailTaken) {
+			t.Errorf("expected ErrEmailTaken, got %v", err)
+		}
+	})
+
+	t.Run("rejects duplicate plate", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["existing"] = &domain.Driver{ID: "existing", Plate: "34ABC123"}
+		uc := NewDriverUseCase(repo, logger)
+		req := baseRequest()
+		req.Plate = "34 abc 123"
+
+		if _, err := uc.CreateDriver(context.Background(), req); !errors.Is(err, ErrPlateTaken) {
+			t.Errorf("expected ErrPlateTaken, got %v", err)
+		}
+	})
+}
+
+func TestDriverUseCase_ConcurrentMutations(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["d1"] = &domain.Driver{ID: "d1", Plate: "06AB123", TaxiType: domain.TaxiTypeSari}
+	uc := NewDriverUseCase(repo, zap.NewNop()).(*driverUseCase)
+	ctx := context.Background()
+	req := &CreateDriverRequest{
+		FirstName: "Ahmet",
+		LastName:  "Demir",
+		Plate:     "34 abc 123",
+		Phone:     "+905321234567",
+		TaxiType:  domain.TaxiTypeSari,
+		CarBrand:  "Toyota",
+		CarModel:  "Corolla",
+		Lat:       41.0431,
+		Lon:       29.0099,
+	}
+	newPhone := "+90 532 765 43 21"
+
+	// A request still writing the same plate or driver turns the next one away
+	unlock, _ := uc.locks.TryLock("plate:34ABC123", "driver:d1")
+	if _, err := uc.CreateDriver(ctx, req); !errors.Is(err, ErrDriverChangeInProgress) {
+		t.Errorf("expected ErrDriverChangeInProgress for a plate being created, got %v", err)
+	}
+	if _, err := uc.UpdateDriver(ctx, "d1", &UpdateDriverRequest{Phone: &newPhone}); !errors.Is(err, ErrDriverChangeInProgress) {
+		t.Errorf("expected ErrDriverChangeInProgress for a driver being updated, got %v", err)
+	}
+	unlock()
+
+	// A phone stored by another instance after the check is still a conflict
+	repo.writeErr = domain.ErrDuplicatePhone
+	if _, err := uc.CreateDriver(ctx, req); !errors.Is(err, ErrPhoneTaken) {
+		t.Errorf("expected ErrPhoneTaken, got %v", err)
+	}
+	repo.writeErr = domain.ErrDuplicateEmail
+	if _, err := uc.UpdateDriver(ctx, "d1", &UpdateDriverRequest{Phone: &newPhone}); !errors.Is(err, ErrEmailTaken) {
+		t.Errorf("expected ErrEmailTaken, got %v", err)
+	}
+	repo.writeErr = domain.ErrDuplicatePlate
+	if _, err := uc.CreateDriver(ctx, req); !errors.Is(err, ErrPlateTaken) {
+		t.Errorf("expected ErrPlateTaken, got %v", err)
+	}
+
+	// A new plate is held while the update that moves to it is written
+	newPlate := "35 abc 12"
+	unlock, _ = uc.locks.TryLock("plate:35ABC12")
+	if _, err := uc.UpdateDriver(ctx, "d1", &UpdateDriverRequest{Plate: &newPlate}); !errors.Is(err, ErrDriverChangeInProgress) {
+		t.Errorf("expected ErrDriverChangeInProgress for a plate being taken, got %v", err)
+	}
+	unlock()
+
+	repo.writeErr = nil
+	if _, err := uc.CreateDriver(ctx, req); err != nil {
+		t.Errorf("expected the create to succeed once the locks are released, got %v", err)
+	}
+	if uc.locks.Held() != 0 {
+		t.Errorf("expected every lock released, %d held", uc.locks.Held())
+	}
+}
+
+func TestDriverUseCase_UpdateDriverContact(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("changing phone resets verification and availability", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{
+			ID:            "driver-1",
+			Phone:         "+905321234567",
+			PhoneVerified: true,
+			Available:     true,
+		}
+		uc := NewDriverUseCase(repo, logger)
+
+		driver, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Phone: stringPtr("+905329876543")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if driver.PhoneVerified || driver.Available {
+			t.Errorf("expected phone verification and availability to be reset")
+		}
+	})
+
+	t.Run("keeping the same phone keeps verification", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true}
+		uc := NewDriverUseCase(repo, logger)
+
+		driver, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Phone: stringPtr("+90 532 123 45 67")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !driver.PhoneVerified {
+			t.Errorf("expected phone to stay verified")
+		}
+	})
+
+	t.Run("plate used by another driver", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Plate: "34ABC123"}
+		repo.drivers["driver-2"] = &domain.Driver{ID: "driver-2", Plate: "06AB123"}
+		uc := NewDriverUseCase(repo, logger)
+
+		if _, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Plate: stringPtr("06 ab 123")}); !errors.Is(err, ErrPlateTaken) {
+			t.Errorf("expected ErrPlateTaken, got %v", err)
+		}
+		if _, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Plate: stringPtr("34 abc 123")}); err != nil {
+			t.Errorf("expected the driver's own plate to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("phone used by another driver", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+		repo.drivers["driver-2"] = &domain.Driver{ID: "driver-2", Phone: "+905321234567"}
+		uc := NewDriverUseCase(repo, logger)
+
+		_, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Phone: stringPtr("+905321234567")})
+		if !errors.Is(err, ErrPhoneTaken) {
+			t.Errorf("expected ErrPhoneTaken, got %v", err)
+		}
+	})
+}
+
+func TestDriverUseCase_Tags(t *testing.T) {
+	logger := zap.NewNop()
+
+	t.Run("tags and attributes are normalized", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Tags: []string{"quiet"}}
+		uc := NewDriverUseCase(repo, logger)
+
+		driver, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{
+			Tags:       []string{" Wheelchair-Accessible", "pet-friendly", "PET-FRIENDLY"},
+			Attributes: map[string]string{"Language": " en "},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fmt.Sprint(driver.Tags) != "[pet-friendly wheelchair-accessible]" {
+			t.Errorf("unexpected tags: %v", driver.Tags)
+		}
+		if fmt.Sprint(driver.Attributes) != "map[language:en]" {
+			t.Errorf("unexpected attributes: %v", driver.Attributes)
+		}
+	})
+
+	t.Run("missing lists are kept and empty ones clear", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Tags: []string{"quiet"}, Attributes: map[string]string{"language": "en"}}
+		uc := NewDriverUseCase(repo, logger)
+
+		driver, err := uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{CarBrand: stringPtr("Honda")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(driver.Tags) != 1 || len(driver.Attributes) != 1 {
+			t.Errorf("expected tags and attributes to be kept, got %v %v", driver.Tags, driver.Attributes)
+		}
+
+		driver, err = uc.UpdateDriver(context.Background(), "driver-1", &UpdateDriverRequest{Tags: []string{}, Attributes: map[string]string{}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(driver.Tags) != 0 || len(driver.Attributes) != 0 {
+			t.Errorf("expected tags and attributes to be cleared, got %v %v", driver.Tags, driver.Attributes)
+		}
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		tooMany := make([]string, domain.MaxDriverTags+1)
+		manyAttributes := make(map[string]string, domain.MaxDriverAttributes+1)
+		for i := range tooMany {
+			tooMany[i] = fmt.Sprintf("tag-%d", i)
+			manyAttributes[tooMany[i]] = "yes"
+		}
+		cases := []struct {
+			name string
+			req  *UpdateDriverRequest
+			want error
+		}{
+			{"invalid tag", &UpdateDriverRequest{Tags: []string{"pet friendly"}}, ErrInvalidTags},
+			{"long tag", &UpdateDriverRequest{Tags: []string{strings.Repeat("a", 33)}}, ErrInvalidTags},
+			{"too many tags", &UpdateDriverRequest{Tags: tooMany}, ErrInvalidTags},
+			{"invalid key", &UpdateDriverRequest{Attributes: map[string]string{"a.b": "x"}}, ErrInvalidAttributes},
+			{"empty value", &UpdateDriverRequest{Attributes: map[string]string{"language": " "}}, ErrInvalidAttributes},
+			{"long value", &UpdateDriverRequest{Attributes: map[string]string{"note": strings.Repeat("ş", 65)}}, ErrInvalidAttributes},
+			{"duplicate key", &UpdateDriverRequest{Attributes: map[string]string{"language": "en", "Language": "tr"}}, ErrInvalidAttributes},
+			{"too many attributes", &UpdateDriverRequest{Attributes: manyAttributes}, ErrInvalidAttributes},
+		}
+		for _, c := range cases {
+			repo := newMockDriverRepository()
+			repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+			uc := NewDriverUseCase(repo, logger)
+
+			if _, err := uc.UpdateDriver(context.Background(), "driver-1", c.req); !errors.Is(err, c.want) {
+				t.Errorf("%s: expected %v, got %v", c.name, c.want, err)
+			}
+		}
+	})
+}
+
+func TestDriverUseCase_SetAvailability(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name      string
+		driver    *domain.Driver
+		available bool
+		wantErr   error
+	}{
+		{
+			name:      "verified driver goes on shift",
+			driver:    &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true},
+			available: true,
+		},
+		{
+			name:      "unverified driver cannot go on shift",
+			driver:    &domain.Driver{ID: "driver-1", Phone: "+905321234567"},
+			available: true,
+			wantErr:   ErrContactNotVerified,
+		},
+		{
+			name:      "suspended driver cannot go on shift",
+			driver:    &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true, Suspended: true},
+			available: true,
+			wantErr:   ErrDriverSuspended,
+		},
+		{
+			name:      "driver with an expired license cannot go on shift",
+			driver:    &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true, LicenseExpired: true},
+			available: true,
+			wantErr:   ErrLicenseExpired,
+		},
+		{
+			name:      "unverified driver can go off shift",
+			driver:    &domain.Driver{ID: "driver-1", Available: true},
+			available: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockDriverRepository()
+			repo.drivers[tt.driver.ID] = tt.driver
+			uc := NewDriverUseCase(repo, logger)
+
+			driver, err := uc.SetAvailability(context.Background(), tt.driver.ID, tt.available)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if driver.Available != tt.available {
+				t.Errorf("expected available=%v, got %v", tt.available, driver.Available)
+			}
+		})
+	}
+
+	t.Run("driver not found", func(t *testing.T) {
+		uc := NewDriverUseCase(newMockDriverRepository(), logger)
+		if _, err := uc.SetAvailability(context.Background(), "missing", true); err == nil || err.Error() != "driver not found" {
+			t.Errorf("expected driver not found, got %v", err)
+		}
+	})
+}
+
+// recordingPublisher remembers published driver events
+type recordingPublisher struct {
+	events  []string
+	reasons []string
+}
+
+func (p *recordingPublisher) PublishDriverEvent(ctx interface{}, event string, driver *domain.Driver, reason string) {
+	p.events = append(p.events, event)
+	p.reasons = append(p.reasons, reason)
+}
+
+func TestDriverUseCase_SetSuspension(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", PhoneVerified: true, Available: true}
+	events := &recordingPublisher{}
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithEvents(events))
+
+	driver, err := uc.SetSuspension(context.Background(), "driver-1", true, " expired license ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !driver.Suspended || driver.Available || driver.SuspendReason != "expired license" {
+		t.Errorf("expected a suspended driver off shift, got %+v", driver)
+	}
+
+	driver, err = uc.SetSuspension(context.Background(), "driver-1", false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.Suspended || driver.SuspendReason != "" {
+		t.Errorf("expected a reinstated driver, got %+v", driver)
+	}
+
+	if len(events.events) != 2 || events.events[0] != domain.EventDriverSuspended || events.events[1] != domain.EventDriverUpdated {
+		t.Errorf("unexpected events: %v", events.events)
+	}
+	if events.reasons[0] != "expired license" {
+		t.Errorf("expected the suspension reason in the event, got %q", events.reasons[0])
+	}
+
+	if _, err := uc.SetSuspension(context.Background(), "missing", true, ""); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+}
+
+func TestDriverUseCase_DeleteDriver(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	uc := NewDriverUseCase(repo, zap.NewNop())
+
+	if err := uc.DeleteDriver(context.Background(), "driver-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.DeleteDriver(context.Background(), "driver-1"); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+}
+
+func TestDriverUseCase_FleetAdminScope(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["own"] = &domain.Driver{ID: "own", FleetID: "fleet-1", FirstName: "Ahmet"}
+	repo.drivers["other"] = &domain.Driver{ID: "other", FleetID: "fleet-2", FirstName: "Ayşe"}
+	uc := NewDriverUseCase(repo, zap.NewNop())
+	ctx := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "admin-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+
+	if _, err := uc.UpdateDriver(ctx, "own", &UpdateDriverRequest{FirstName: stringPtr("Mehmet")}); err != nil {
+		t.Errorf("expected the fleet's own driver to be updated, got %v", err)
+	}
+	if _, err := uc.UpdateDriver(ctx, "other", &UpdateDriverRequest{FirstName: stringPtr("Mehmet")}); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet, got %v", err)
+	}
+	if _, err := uc.SetSuspension(ctx, "other", true, ""); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet, got %v", err)
+	}
+	if err := uc.DeleteDriver(ctx, "other"); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet, got %v", err)
+	}
+	if repo.drivers["other"].FirstName != "Ayşe" {
+		t.Error("expected the other fleet's driver to be unchanged")
+	}
+
+	// Callers without a forwarded identity are not scoped
+	if err := uc.DeleteDriver(context.Background(), "other"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type countingMetrics struct {
+	created  int
+	searches []int
+	located  int
+}
+
+func (m *countingMetrics) DriverCreated()           { m.created++ }
+func (m *countingMetrics) NearbySearch(results int) { m.searches = append(m.searches, results) }
+func (m *countingMetrics) LocationUpdated()         { m.located++ }
+
+func TestDriverUseCase_Metrics(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["near"] = &domain.Driver{ID: "near", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	metrics := &countingMetrics{}
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithMetrics(metrics))
+	ctx := context.Background()
+
+	if _, err := uc.CreateDriver(ctx, &CreateDriverRequest{
+		FirstName: "Ahmet",
+		LastName:  "Demir",
+		Plate:     "34ABC123",
+		TaxiType:  domain.TaxiTypeSari,
+		CarBrand:  "Toyota",
+		CarModel:  "Corolla",
+		Lat:       41.0431,
+		Lon:       29.0099,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.FindNearbyDrivers(ctx, 41.0431, 29.0099, nil, domain.DriverFilter{}, domain.NearbyPage{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lat, lon := 41.05, 29.01
+	if _, err := uc.UpdateDriver(ctx, "near", &UpdateDriverRequest{Lat: &lat, Lon: &lon}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Updates without a position are not location updates
+	if _, err := uc.UpdateDriver(ctx, "near", &UpdateDriverRequest{FirstName: stringPtr("Mehmet")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if metrics.created != 1 {
+		t.Errorf("created = %d, want 1", metrics.created)
+	}
+	if len(metrics.searches) != 1 || metrics.searches[0] != 2 {
+		t.Errorf("searches = %v, want [2]", metrics.searches)
+	}
+	if metrics.located != 1 {
+		t.Errorf("located = %d, want 1", metrics.located)
+	}
+}
@@ -0,0 +1,321 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Limits of the adjustment and payout listings
+const (
+	defaultEarningsListLimit = 50
+	maxEarningsListLimit     = 200
+)
+
+// EarningsUseCase defines the interface for driver earnings, adjustments and payouts
+type EarningsUseCase interface {
+	RecordTrip(ctx context.Context, trip *domain.Trip) error
+	GetDriverEarnings(ctx context.Context, driverID string, period domain.EarningsPeriod, from, to *time.Time) (*EarningsSummary, error)
+	CreateAdjustment(ctx context.Context, req *CreateAdjustmentRequest) (*domain.Earning, error)
+	ListAdjustments(ctx context.Context, driverID string, limit int) ([]*domain.Earning, error)
+	CreatePayout(ctx context.Context, req *CreatePayoutRequest) (*domain.Payout, error)
+	GetPayout(ctx context.Context, id string) (*domain.Payout, error)
+	ListPayouts(ctx context.Context, limit int) ([]*domain.Payout, error)
+}
+
+// EarningsOptions holds the earnings settings
+type EarningsOptions struct {
+	// CommissionRate is the share of each fare kept by the platform, between 0 and 1
+	CommissionRate float64
+	Currency       string
+	// Location is the time zone days and weeks are bucketed in; UTC when nil
+	Location *time.Location
+}
+
+// EarningsSummary is a driver's earnings over a range, bucketed by day or week
+type EarningsSummary struct {
+	DriverID string                  `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Period   domain.EarningsPeriod   `json:"period" example:"daily"`
+	From     time.Time               `json:"from" example:"2025-11-06T00:00:00+03:00"`
+	To       time.Time               `json:"to" example:"2025-12-06T00:00:00+03:00"`
+	Timezone string                  `json:"timezone" example:"Europe/Istanbul"`
+	Currency string                  `json:"currency" example:"TRY"`
+	Totals   domain.EarningsTotals   `json:"totals"`
+	Buckets  []domain.EarningsBucket `json:"buckets"`
+}
+
+// CreateAdjustmentRequest represents a manual correction of a driver's earnings
+type CreateAdjustmentRequest struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011" binding:"required"`
+	// Amount is credited to the driver; negative amounts deduct
+	Amount float64 `json:"amount" example:"50"`
+	Reason string  `json:"reason" example:"Toll reimbursement for trip 6571f1f77bcf86cd79943901" binding:"required"`
+}
+
+// CreatePayoutRequest selects the unpaid earnings a payout batch covers
+type CreatePayoutRequest struct {
+	// From is optional; without it every unpaid entry before To is paid out
+	From *time.Time `json:"from,omitempty" example:"2025-12-01T00:00:00+03:00"`
+	// To defaults to the start of the current day
+	To *time.Time `json:"to,omitempty" example:"2025-12-08T00:00:00+03:00"`
+}
+
+// earningsUseCase implements EarningsUseCase
+type earningsUseCase struct {
+	repo       domain.EarningsRepository
+	driverRepo domain.DriverRepository
+	opts       EarningsOptions
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewEarningsUseCase creates a new earnings use case
+func NewEarningsUseCase(repo domain.EarningsRepository, driverRepo domain.DriverRepository, opts EarningsOptions, logger *zap.Logger) EarningsUseCase {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &earningsUseCase{
+		repo:       repo,
+		driverRepo: driverRepo,
+		opts:       opts,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// RecordTrip credits the driver of a completed trip with its fare less commission
+func (uc *earningsUseCase) RecordTrip(ctx context.Context, trip *domain.Trip) error {
+	if trip.Status != domain.TripStatusCompleted || trip.DriverID == "" || trip.CompletedAt == nil {
+		return ErrInvalidTripState
+	}
+
+	commission := roundMoney(trip.Fare * uc.opts.CommissionRate)
+	earning := &domain.Earning{
+		DriverID:   trip.DriverID,
+		Type:       domain.EarningTypeTrip,
+		TripID:     trip.ID,
+		Gross:      trip.Fare,
+		Commission: commission,
+		Net:        roundMoney(trip.Fare - commission),
+		Currency:   uc.opts.Currency,
+		EarnedAt:   *trip.CompletedAt,
+	}
+	if err := uc.repo.Record(ctx, earning); err != nil {
+		uc.logger.Error("failed to record trip earning", zap.Error(err), zap.String("tripId", trip.ID))
+		return errors.New("failed to record trip earning")
+	}
+	return nil
+}
+
+// GetDriverEarnings sums the driver's ledger by day or week. The range ends now
+// by default and starts 30 days (daily) or 12 weeks (weekly) earlier.
+func (uc *earningsUseCase) GetDriverEarnings(ctx context.Context, driverID string, period domain.EarningsPeriod, from, to *time.Time) (*EarningsSummary, error) {
+	if period == "" {
+		period = domain.EarningsPeriodDaily
+	}
+	if !period.IsValid() {
+		return nil, ErrInvalidEarningsPeriod
+	}
+
+	end := uc.now().In(uc.opts.Location).Truncate(time.Minute)
+	if to != nil {
+		end = to.In(uc.opts.Location)
+	}
+	start := end.AddDate(0, 0, -30)
+	if period == domain.EarningsPeriodWeekly {
+		start = end.AddDate(0, 0, -12*7)
+	}
+	if from != nil {
+		start = from.In(uc.opts.Location)
+	}
+	if !start.Before(end) {
+		return nil, ErrInvalidStatsRange
+	}
+	if end.Sub(start) > maxStatsRange {
+		return nil, ErrEarningsRangeTooLong
+	}
+
+	if _, err := uc.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	buckets, err := uc.repo.Summarize(ctx, driverID, start, end, period, uc.opts.Location)
+	if err != nil {
+		uc.logger.Error("failed to summarize driver earnings", zap.Error(err), zap.String("id", driverID))
+		return nil, errors.New("failed to get driver earnings")
+	}
+
+	summary := &EarningsSummary{
+		DriverID: driverID,
+		Period:   period,
+		From:     start,
+		To:       end,
+		Timezone: uc.opts.Location.String(),
+		Currency: uc.opts.Currency,
+		Buckets:  buckets,
+	}
+	for _, bucket := range buckets {
+		summary.Totals.Add(bucket.EarningsTotals)
+	}
+	return summary, nil
+}
+
+// CreateAdjustment records a manual credit or deduction. The entry keeps the
+// reason and the admin who made it, and is logged for audit.
+func (uc *earningsUseCase) CreateAdjustment(ctx context.Context, req *CreateAdjustmentRequest) (*domain.Earning, error) {
+	amount := roundMoney(req.Amount)
+	if amount == 0 || math.IsInf(req.Amount, 0) || math.IsNaN(req.Amount) {
+		return nil, ErrInvalidAdjustmentAmount
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrAdjustmentReasonRequired
+	}
+	if _, err := uc.driverRepo.GetByID(ctx, req.DriverID); err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	earning := &domain.Earning{
+		DriverID: req.DriverID,
+		Type:     domain.EarningTypeAdjustment,
+		Net:      amount,
+		Currency: uc.opts.Currency,
+		Reason:   reason,
+		EarnedAt: uc.now(),
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok {
+		earning.CreatedBy = identity.UserID
+	}
+	if err := uc.repo.Record(ctx, earning); err != nil {
+		uc.logger.Error("failed to record earning adjustment", zap.Error(err), zap.String("driverId", req.DriverID))
+		return nil, errors.New("failed to create adjustment")
+	}
+
+	uc.logger.Info("earning adjustment recorded", append(actorFields(ctx),
+		zap.String("adjustmentId", earning.ID),
+		zap.String("driverId", earning.DriverID),
+		zap.Float64("amount", earning.Net),
+		zap.String("reason", earning.Reason),
+	)...)
+	return earning, nil
+}
+
+// ListAdjustments returns the adjustment audit trail, newest first
+func (uc *earningsUseCase) ListAdjustments(ctx context.Context, driverID string, limit int) ([]*domain.Earning, error) {
+	adjustments, err := uc.repo.ListAdjustments(ctx, driverID, clampEarningsLimit(limit))
+	if err != nil {
+		uc.logger.Error("failed to list earning adjustments", zap.Error(err))
+		return nil, errors.New("failed to list adjustments")
+	}
+	return adjustments, nil
+}
+
+// CreatePayout batches every unpaid entry earned in the range into a payout.
+// Each entry is paid out once: entries already in a payout are skipped.
+func (uc *earningsUseCase) CreatePayout(ctx context.Context, req *CreatePayoutRequest) (*domain.Payout, error) {
+	now := uc.now().In(uc.opts.Location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.opts.Location)
+	if req.To != nil {
+		to = *req.To
+	}
+	if to.After(now) {
+		return nil, ErrPayoutInFuture
+	}
+	if req.From != nil && !req.From.Before(to) {
+		return nil, ErrInvalidStatsRange
+	}
+
+	payout := &domain.Payout{
+		From:     req.From,
+		To:       to,
+		Currency: uc.opts.Currency,
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok {
+		payout.CreatedBy = identity.UserID
+	}
+	if err := uc.repo.CreatePayout(ctx, payout); err != nil {
+		uc.logger.Error("failed to create payout", zap.Error(err))
+		return nil, errors.New("failed to create payout")
+	}
+	if len(payout.Lines) == 0 {
+		return nil, ErrNothingToPay
+	}
+
+	uc.logger.Info("payout created", append(actorFields(ctx),
+		zap.String("payoutId", payout.ID),
+		zap.Int("drivers", payout.Drivers),
+		zap.Float64("total", payout.Total),
+	)...)
+	return payout, nil
+}
+
+// GetPayout retrieves a payout with its lines
+func (uc *earningsUseCase) GetPayout(ctx context.Context, id string) (*domain.Payout, error) {
+	payout, err := uc.repo.GetPayout(ctx, id)
+	if err != nil {
+		if err.Error() == "payout not found" {
+			return nil, ErrPayoutNotFound
+		}
+		uc.logger.Error("failed to get payout", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get payout")
+	}
+	return payout, nil
+}
+
+// ListPayouts returns the newest payouts first, without their lines
+func (uc *earningsUseCase) ListPayouts(ctx context.Context, limit int) ([]*domain.Payout, error) {
+	payouts, err := uc.repo.ListPayouts(ctx, clampEarningsLimit(limit))
+	if err != nil {
+		uc.logger.Error("failed to list payouts", zap.Error(err))
+		return nil, errors.New("failed to list payouts")
+	}
+	return payouts, nil
+}
+
+// WritePayoutCSV writes a payout as CSV for finance, one row per driver
+func WritePayoutCSV(w io.Writer, payout *domain.Payout) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"payoutId", "driverId", "trips", "gross", "commission", "adjustments", "net", "currency"})
+	for _, line := range payout.Lines {
+		out.Write([]string{
+			payout.ID,
+			line.DriverID,
+			strconv.Itoa(line.Trips),
+			formatMoney(line.Gross),
+			formatMoney(line.Commission),
+			formatMoney(line.Adjustments),
+			formatMoney(line.Net),
+			payout.Currency,
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func clampEarningsLimit(limit int) int {
+	if limit <= 0 {
+		return defaultEarningsListLimit
+	}
+	if limit > maxEarningsListLimit {
+		return maxEarningsListLimit
+	}
+	return limit
+}
+
+// roundMoney rounds an amount to kuruş
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func formatMoney(amount float64) string {
+	return fmt.Sprintf("%.2f", amount)
+}
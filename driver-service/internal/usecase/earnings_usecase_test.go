@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockEarningsRepository keeps the ledger and payouts in memory
+type mockEarningsRepository struct {
+	earnings   []*domain.Earning
+	payouts    []*domain.Payout
+	shouldFail bool
+}
+
+func (m *mockEarningsRepository) Record(ctx interface{}, earning *domain.Earning) error {
+	if m.shouldFail {
+		return errors.New("repository error")
+	}
+	for _, existing := range m.earnings {
+		if earning.TripID != "" && existing.TripID == earning.TripID {
+			return nil
+		}
+	}
+	earning.ID = fmt.Sprintf("earning-%d", len(m.earnings)+1)
+	stored := *earning
+	m.earnings = append(m.earnings, &stored)
+	return nil
+}
+
+func (m *mockEarningsRepository) Summarize(ctx interface{}, driverID string, from, to time.Time, period domain.EarningsPeriod, location *time.Location) ([]domain.EarningsBucket, error) {
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	byStart := map[time.Time]*domain.EarningsBucket{}
+	for _, earning := range m.earnings {
+		if earning.DriverID != driverID || earning.EarnedAt.Before(from) || !earning.EarnedAt.Before(to) {
+			continue
+		}
+		at := earning.EarnedAt.In(location)
+		start := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, location)
+		if period == domain.EarningsPeriodWeekly {
+			start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+		}
+		bucket, ok := byStart[start]
+		if !ok {
+			bucket = &domain.EarningsBucket{Start: start}
+			byStart[start] = bucket
+		}
+		bucket.Add(totalsOf(earning))
+	}
+	buckets := []domain.EarningsBucket{}
+	for _, bucket := range byStart {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets, nil
+}
+
+func (m *mockEarningsRepository) ListAdjustments(ctx interface{}, driverID string, limit int) ([]*domain.Earning, error) {
+	var adjustments []*domain.Earning
+	for i := len(m.earnings) - 1; i >= 0 && len(adjustments) < limit; i-- {
+		earning := m.earnings[i]
+		if earning.Type == domain.EarningTypeAdjustment && (driverID == "" || earning.DriverID == driverID) {
+			adjustments = append(adjustments, earning)
+		}
+	}
+	return adjustments, nil
+}
+
+func (m *mockEarningsRepository) CreatePayout(ctx interface{}, payout *domain.Payout) error {
+	if m.shouldFail {
+		return errors.New("repository error")
+	}
+	id := fmt.Sprintf("payout-%d", len(m.payouts)+1)
+	lines := map[string]*domain.PayoutLine{}
+	for _, earning := range m.earnings {
+		if earning.PayoutID != "" || !earning.EarnedAt.Before(payout.To) || (payout.From != nil && earning.EarnedAt.Before(*payout.From)) {
+			continue
+		}
+		earning.PayoutID = id
+		line, ok := lines[earning.DriverID]
+		if !ok {
+			line = &domain.PayoutLine{DriverID: earning.DriverID}
+			lines[earning.DriverID] = line
+		}
+		line.Add(totalsOf(earning))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	payout.ID = id
+	for _, line := range lines {
+		payout.Lines = append(payout.Lines, *line)
+		payout.Total += line.Net
+	}
+	sort.Slice(payout.Lines, func(i, j int) bool { return payout.Lines[i].DriverID < payout.Lines[j].DriverID })
+	payout.Drivers = len(payout.Lines)
+	m.payouts = append(m.payouts, payout)
+	return nil
+}
+
+func (m *mockEarningsRepository) GetPayout(ctx interface{}, id string) (*domain.Payout, error) {
+	for _, payout := range m.payouts {
+		if payout.ID == id {
+			return payout, nil
+		}
+	}
+	return nil, errors.New("payout not found")
+}
+
+func (m *mockEarningsRepository) ListPayouts(ctx interface{}, limit int) ([]*domain.Payout, error) {
+	return m.payouts, nil
+}
+
+// totalsOf returns the totals a single ledger entry contributes
+func totalsOf(earning *domain.Earning) domain.EarningsTotals {
+	if earning.Type == domain.EarningTypeTrip {
+		return domain.EarningsTotals{Trips: 1, Gross: earning.Gross, Commission: earning.Commission, Net: earning.Net}
+	}
+	return domain.EarningsTotals{Adjustments: earning.Net, Net: earning.Net}
+}
+
+func newTestEarningsUseCase(now time.Time) (*earningsUseCase, *mockEarningsRepository, *mockDriverRepository) {
+	istanbul := time.FixedZone("Europe/Istanbul", 3*60*60)
+	repo := &mockEarningsRepository{}
+	driverRepo := newMockDriverRepository()
+	driverRepo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	driverRepo.drivers["driver-2"] = &domain.Driver{ID: "driver-2"}
+	uc := NewEarningsUseCase(repo, driverRepo, EarningsOptions{CommissionRate: 0.15, Currency: "TRY", Location: istanbul}, zap.NewNop()).(*earningsUseCase)
+	uc.now = func() time.Time { return now }
+	return uc, repo, driverRepo
+}
+
+func completedTrip(id, driverID string, fare float64, at time.Time) *domain.Trip {
+	return &domain.Trip{ID: id, DriverID: driverID, Status: domain.TripStatusCompleted, Fare: fare, CompletedAt: &at}
+}
+
+func TestEarningsUseCase_RecordTrip(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	uc, repo, _ := newTestEarningsUseCase(now)
+
+	if err := uc.RecordTrip(ctx, completedTrip("trip-1", "driver-1", 232.4, now)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.earnings) != 1 {
+		t.Fatalf("expected one ledger entry, got %d", len(repo.earnings))
+	}
+	earning := repo.earnings[0]
+	if earning.Gross != 232.4 || earning.Commission != 34.86 || earning.Net != 197.54 || earning.Currency != "TRY" {
+		t.Errorf("unexpected trip earning %+v", earning)
+	}
+	if !earning.EarnedAt.Equal(now) || earning.TripID != "trip-1" {
+		t.Errorf("expected the earning dated at completion for trip-1, got %+v", earning)
+	}
+
+	// Recording the same trip again does not pay the driver twice
+	if err := uc.RecordTrip(ctx, completedTrip("trip-1", "driver-1", 232.4, now)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.earnings) != 1 {
+		t.Errorf("expected the trip to be recorded once, got %d entries", len(repo.earnings))
+	}
+
+	accepted := completedTrip("trip-2", "driver-1", 100, now)
+	accepted.Status = domain.TripStatusAccepted
+	if err := uc.RecordTrip(ctx, accepted); !errors.Is(err, ErrInvalidTripState) {
+		t.Errorf("expected ErrInvalidTripState for an unfinished trip, got %v", err)
+	}
+}
+
+func TestEarningsUseCase_GetDriverEarnings(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 10, 12, 0, 0, 0, time.UTC) // Wednesday
+	uc, repo, _ := newTestEarningsUseCase(now)
+
+	// 22:30 UTC on Monday the 8th is already Tuesday the 9th in Istanbul
+	for i, at := range []time.Time{
+		time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 8, 22, 30, 0, 0, time.UTC),
+		time.Date(2025, 12, 5, 9, 0, 0, 0, time.UTC),
+	} {
+		if err := uc.RecordTrip(ctx, completedTrip(fmt.Sprintf("trip-%d", i), "driver-1", 100, at)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	repo.Record(ctx, &domain.Earning{DriverID: "driver-1", Type: domain.EarningTypeAdjustment, Net: -20, EarnedAt: now.Add(-time.Hour)})
+	repo.Record(ctx, &domain.Earning{DriverID: "driver-2", Type: domain.EarningTypeTrip, Gross: 500, Net: 425, EarnedAt: now})
+
+	daily, err := uc.GetDriverEarnings(ctx, "driver-1", "", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if daily.Period != domain.EarningsPeriodDaily || len(daily.Buckets) != 4 {
+		t.Fatalf("expected 4 daily buckets, got %+v", daily.Buckets)
+	}
+	if day := daily.Buckets[1]; day.Start.Day() != 8 || day.Trips != 1 {
+		t.Errorf("expected one trip on the 8th, got %+v", day)
+	}
+	if day := daily.Buckets[2]; day.Start.Day() != 9 || day.Trips != 1 {
+		t.Errorf("expected the late trip on the 9th local time, got %+v", day)
+	}
+	want := domain.EarningsTotals{Trips: 3, Gross: 300, Commission: 45, Adjustments: -20, Net: 235}
+	if daily.Totals != want {
+		t.Errorf("expected totals %+v, got %+v", want, daily.Totals)
+	}
+
+	weekly, err := uc.GetDriverEarnings(ctx, "driver-1", domain.EarningsPeriodWeekly, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(weekly.Buckets) != 2 || weekly.Buckets[1].Start.Weekday() != time.Monday || weekly.Buckets[1].Trips != 2 {
+		t.Errorf("expected two weeks starting on Monday, got %+v", weekly.Buckets)
+	}
+
+	if _, err := uc.GetDriverEarnings(ctx, "driver-1", "monthly", nil, nil); !errors.Is(err, ErrInvalidEarningsPeriod) {
+		t.Errorf("expected ErrInvalidEarningsPeriod, got %v", err)
+	}
+	from := now.AddDate(-2, 0, 0)
+	if _, err := uc.GetDriverEarnings(ctx, "driver-1", "", &from, nil); !errors.Is(err, ErrEarningsRangeTooLong) {
+		t.Errorf("expected ErrEarningsRangeTooLong, got %v", err)
+	}
+	if _, err := uc.GetDriverEarnings(ctx, "missing", "", nil, nil); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+}
+
+func TestEarningsUseCase_CreateAdjustment(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	uc, repo, _ := newTestEarningsUseCase(now)
+	ctx := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "ops-admin"})
+
+	adjustment, err := uc.CreateAdjustment(ctx, &CreateAdjustmentRequest{DriverID: "driver-1", Amount: 49.999, Reason: " Toll reimbursement "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if adjustment.Type != domain.EarningTypeAdjustment || adjustment.Net != 50 || adjustment.Gross != 0 {
+		t.Errorf("unexpected adjustment %+v", adjustment)
+	}
+	if adjustment.Reason != "Toll reimbursement" || adjustment.CreatedBy != "ops-admin" || !adjustment.EarnedAt.Equal(now) {
+		t.Errorf("expected the reason and caller in the audit trail, got %+v", adjustment)
+	}
+
+	tests := []struct {
+		name string
+		req  CreateAdjustmentRequest
+		want error
+	}{
+		{"zero amount", CreateAdjustmentRequest{DriverID: "driver-1", Amount: 0.001, Reason: "x"}, ErrInvalidAdjustmentAmount},
+		{"missing reason", CreateAdjustmentRequest{DriverID: "driver-1", Amount: 10, Reason: "  "}, ErrAdjustmentReasonRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateAdjustment(ctx, &tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if _, err := uc.CreateAdjustment(ctx, &CreateAdjustmentRequest{DriverID: "missing", Amount: 10, Reason: "x"}); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+
+	adjustments, err := uc.ListAdjustments(ctx, "driver-1", 0)
+	if err != nil || len(adjustments) != 1 || len(repo.earnings) != 1 {
+		t.Errorf("expected the one adjustment in the trail, got %v (%v)", adjustments, err)
+	}
+}
+
+func TestEarningsUseCase_CreatePayout(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC) // 12:00 in Istanbul
+	uc, repo, _ := newTestEarningsUseCase(now)
+
+	uc.RecordTrip(ctx, completedTrip("trip-1", "driver-1", 200, now.AddDate(0, 0, -3)))
+	uc.RecordTrip(ctx, completedTrip("trip-2", "driver-2", 100, now.AddDate(0, 0, -1)))
+	uc.RecordTrip(ctx, completedTrip("trip-3", "driver-1", 100, now.Add(-time.Hour))) // today, not yet due
+	repo.Record(ctx, &domain.Earning{DriverID: "driver-1", Type: domain.EarningTypeAdjustment, Net: 30, EarnedAt: now.AddDate(0, 0, -2)})
+
+	payout, err := uc.CreatePayout(ctx, &CreatePayoutRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantTo := time.Date(2025, 12, 8, 0, 0, 0, 0, uc.opts.Location)
+	if !payout.To.Equal(wantTo) || payout.From != nil {
+		t.Errorf("expected the payout to end at the start of today, got %v", payout.To)
+	}
+	if payout.Drivers != 2 || payout.Total != 285 || payout.Currency != "TRY" {
+		t.Fatalf("unexpected payout %+v", payout)
+	}
+	if line := payout.Lines[0]; line.DriverID != "driver-1" || line.Trips != 1 || line.Net != 200 || line.Adjustments != 30 {
+		t.Errorf("unexpected line %+v", line)
+	}
+
+	// Entries are paid out once
+	if _, err := uc.CreatePayout(ctx, &CreatePayoutRequest{}); !errors.Is(err, ErrNothingToPay) {
+		t.Errorf("expected ErrNothingToPay for an already paid range, got %v", err)
+	}
+	future := now.Add(time.Hour)
+	if _, err := uc.CreatePayout(ctx, &CreatePayoutRequest{To: &future}); !errors.Is(err, ErrPayoutInFuture) {
+		t.Errorf("expected ErrPayoutInFuture, got %v", err)
+	}
+
+	if _, err := uc.GetPayout(ctx, "unknown"); !errors.Is(err, ErrPayoutNotFound) {
+		t.Errorf("expected ErrPayoutNotFound, got %v", err)
+	}
+	stored, err := uc.GetPayout(ctx, payout.ID)
+	if err != nil || stored.ID != payout.ID {
+		t.Errorf("expected the stored payout, got %v (%v)", stored, err)
+	}
+}
+
+func TestWritePayoutCSV(t *testing.T) {
+	payout := &domain.Payout{ID: "payout-1", Currency: "TRY", Lines: []domain.PayoutLine{
+		{DriverID: "driver-1", EarningsTotals: domain.EarningsTotals{Trips: 2, Gross: 300, Commission: 45, Adjustments: -20.5, Net: 234.5}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WritePayoutCSV(&buf, payout); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "payoutId,driverId,trips,gross,commission,adjustments,net,currency\n" +
+		"payout-1,driver-1,2,300.00,45.00,-20.50,234.50,TRY\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import "errors"
+
+// Errors returned by the use cases that handlers map to specific HTTP responses
+var (
+	ErrInvalidPhone             = errors.New("phone must be in E.164 format (e.g., +905321234567)")
+	ErrInvalidEmail             = errors.New("email must be a valid email address")
+	ErrPhoneTaken               = errors.New("phone is already registered to another driver")
+	ErrEmailTaken               = errors.New("email is already registered to another driver")
+	ErrPlateTaken               = errors.New("plate is already registered to another driver")
+	ErrContactNotVerified       = errors.New("phone must be verified before going on shift")
+	ErrPhoneMissing             = errors.New("driver has no phone number to verify")
+	ErrPhoneAlreadyVerified     = errors.New("phone is already verified")
+	ErrVerificationNotFound     = errors.New("no pending phone verification")
+	ErrVerificationExpired      = errors.New("verification code has expired")
+	ErrInvalidCode              = errors.New("invalid verification code")
+	ErrTooManyAttempts          = errors.New("too many verification attempts, request a new code")
+	ErrVerificationSendLimit    = errors.New("verification code was sent recently, try again later")
+	ErrTripNotFound             = errors.New("trip not found")
+	ErrOfferNotActive           = errors.New("trip has no active offer for this driver")
+	ErrInvalidTripState         = errors.New("trip status does not allow this action")
+	ErrTripConflict             = errors.New("trip was modified concurrently, retry the request")
+	ErrInvalidRating            = errors.New("rating must be between 1 and 5")
+	ErrPickupRequired           = errors.New("pickup location is required")
+	ErrDropoffRequired          = errors.New("dropoff location is required")
+	ErrInvalidDistance          = errors.New("distanceKm cannot be negative")
+	ErrInvalidDocumentType      = errors.New("invalid document type")
+	ErrInvalidDocumentURL       = errors.New("document url must be an absolute http(s) URL")
+	ErrDocumentExpired          = errors.New("document has already expired")
+	ErrDocumentNotFound         = errors.New("document not found")
+	ErrInvalidStatsRange        = errors.New("from must be before to")
+	ErrStatsRangeTooLong        = errors.New("stats range cannot exceed 366 days")
+	ErrUtilizationRangeTooLong  = errors.New("utilization report range cannot exceed 92 days")
+	ErrFleetNotFound            = errors.New("fleet not found")
+	ErrFleetNameRequired        = errors.New("fleet name is required")
+	ErrFleetNameTaken           = errors.New("fleet name already exists")
+	ErrIndexSyncRunning         = errors.New("an index sync is already running")
+	ErrIndexSyncNotFound        = errors.New("no index sync has been started")
+	ErrDriverSuspended          = errors.New("driver is suspended")
+	ErrWebhookNotFound          = errors.New("webhook subscription not found")
+	ErrInvalidWebhookURL        = errors.New("webhook url must be an absolute http(s) URL")
+	ErrWebhookHTTPSRequired     = errors.New("webhook url must use https")
+	ErrWebhookPrivateTarget     = errors.New("webhook url must not point to a loopback, private or link-local address")
+	ErrInvalidWebhookEvent      = errors.New("invalid webhook event")
+	ErrWebhookSecretTooShort    = errors.New("webhook secret must be at least 16 characters")
+	ErrDeliveryNotFound         = errors.New("webhook delivery not found")
+	ErrDeliveryInProgress       = errors.New("webhook delivery is being sent, retry later")
+	ErrRiderNotFound            = errors.New("rider not found")
+	ErrRiderPhoneTaken          = errors.New("phone is already registered to another rider")
+	ErrNameRequired             = errors.New("firstName and lastName are required")
+	ErrFavoriteNotFound         = errors.New("favorite location not found")
+	ErrFavoriteLabelRequired    = errors.New("favorite location label is required")
+	ErrTooManyFavorites         = errors.New("a rider can save at most 20 favorite locations")
+	ErrInvalidSyncMarker        = errors.New("since must be an RFC3339 timestamp or a nextToken from an earlier sync")
+	ErrInvalidStreamPosition    = errors.New("afterId must be a driver ID")
+	ErrDriverNotInFleet         = errors.New("driver does not belong to your fleet")
+	ErrFleetScope               = errors.New("fleet admins can only create drivers in their own fleet")
+	ErrTripNotOwned             = errors.New("trip does not belong to you")
+	ErrRiderMismatch            = errors.New("riders can only request trips for themselves")
+	ErrInvalidLicense           = errors.New("invalid license")
+	ErrLicenseExpired           = errors.New("driver license has expired")
+	ErrInvalidLicenseWindow     = errors.New("days must be between 1 and 365")
+	ErrRouteWaypoints           = errors.New("route must have between 2 and 25 waypoints")
+	ErrRouteTooLong             = errors.New("route cannot be longer than 100 km")
+	ErrInvalidCorridorWidth     = errors.New("widthKm must be between 0 and 2")
+	ErrInvalidEarningsPeriod    = errors.New("period must be daily or weekly")
+	ErrEarningsRangeTooLong     = errors.New("earnings range cannot exceed 366 days")
+	ErrInvalidFare              = errors.New("fare cannot be negative")
+	ErrInvalidAdjustmentAmount  = errors.New("amount must be a non-zero number")
+	ErrAdjustmentReasonRequired = errors.New("reason is required")
+	ErrPayoutInFuture           = errors.New("to cannot be in the future")
+	ErrNothingToPay             = errors.New("no unpaid earnings in range")
+	ErrPayoutNotFound           = errors.New("payout not found")
+	ErrPhotoRequired            = errors.New("photo file is required")
+	ErrPhotoTooLarge            = errors.New("photo exceeds the maximum upload size")
+	ErrInvalidPhotoFormat       = errors.New("photo must be a JPEG or PNG image")
+	ErrPhotoDimensions          = errors.New("photo dimensions are out of range")
+	ErrIdentityDocumentsMissing = errors.New("a license document is required for identity verification")
+	ErrIdentityAlreadyVerified  = errors.New("identity is already verified")
+	ErrKYCCheckPending          = errors.New("an identity check is already pending")
+	ErrKYCCheckNotFound         = errors.New("no identity check found")
+	ErrInvalidKYCSignature      = errors.New("invalid webhook signature")
+	ErrInvalidKYCWebhookBody    = errors.New("webhook body must carry a reference and a valid status")
+	ErrUnknownLocation          = errors.New("location 0,0 is not a valid position")
+	ErrOutsideServiceArea       = errors.New("location is outside the service area")
+	ErrDriverNotOwned           = errors.New("device tokens only act on their own driver")
+	ErrDeviceIDRequired         = errors.New("deviceId is required")
+	ErrTooManyDeviceTokens      = errors.New("a driver can have at most 10 active device tokens")
+	ErrDeviceTokenNotFound      = errors.New("device token not found")
+	ErrDeviceTokenRevoked       = errors.New("device token is revoked")
+	ErrInvalidDeviceToken       = errors.New("invalid or revoked device token")
+	ErrInvalidExportFormat      = errors.New("format must be ndjson or json")
+	ErrExportNotFound           = errors.New("export not found")
+	ErrExportNotReady           = errors.New("export has not completed")
+	ErrTooManyJobs              = errors.New("too many jobs are waiting, retry later")
+	ErrInvalidTags              = errors.New("invalid tags")
+	ErrInvalidAttributes        = errors.New("invalid attributes")
+	ErrShiftNotFound            = errors.New("shift not found")
+	ErrInvalidShiftTimes        = errors.New("endsAt must be after startsAt")
+	ErrShiftTooLong             = errors.New("a shift cannot be longer than 24 hours")
+	ErrShiftInPast              = errors.New("endsAt must be in the future")
+	ErrShiftConflict            = errors.New("shift overlaps another shift of the driver")
+	ErrShiftStarted             = errors.New("shift has started, only its end can change")
+	ErrShiftEnded               = errors.New("shift has ended")
+	ErrCalendarRangeTooLong     = errors.New("calendar range cannot exceed 92 days")
+	ErrImplausibleLocation      = errors.New("location is too far from the previous one for the time since it was reported")
+	ErrInvalidAnomalyLimit      = errors.New("limit must be between 1 and 1000")
+	ErrDriverBlacklisted        = errors.New("driver is on the blacklist")
+	ErrBlacklistUnavailable     = errors.New("blacklist check is unavailable, retry later")
+	ErrInvalidSearchQuery       = errors.New("q must contain 1 to 5 words of letters or digits")
+	ErrInvalidBulkUpdate        = errors.New("invalid bulk update")
+	ErrDriverChangeInProgress   = errors.New("another request is changing the same driver, plate, phone or email; retry shortly")
+	ErrIncidentNotFound         = errors.New("incident not found")
+	ErrInvalidIncidentCategory  = errors.New("category must be safety, conduct, vehicle, fare, route or other")
+	ErrInvalidIncidentSeverity  = errors.New("severity must be low, medium, high or critical")
+	ErrInvalidIncidentStatus    = errors.New("status must be open, investigating or resolved")
+	ErrInvalidIncidentText      = errors.New("description is required and cannot exceed 2000 characters")
+	ErrIncidentTripRequired     = errors.New("riders must name the trip the incident happened on")
+	ErrIncidentTripMismatch     = errors.New("trip was not driven by this driver")
+	ErrIncidentTransition       = errors.New("incident status does not allow this change")
+	ErrIncidentOutcome          = errors.New("outcome must be upheld or dismissed, and is only given when resolving")
+	ErrIncidentReportOnly       = errors.New("riders can only report incidents")
+	ErrIncidentSeverityConfirm  = errors.New("severity is only confirmed when moving to investigating")
+	ErrQuotaExceeded            = errors.New("fleet has reached its driver quota")
+)
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/jobrunner"
+	"go.uber.org/zap"
+)
+
+// Export formats
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatJSON   = "json"
+)
+
+// exportJobKind names driver export jobs in the job runner
+const exportJobKind = "export"
+
+// exportPageSize is how many drivers an export reads at once
+const exportPageSize = 1000
+
+// ExportRequest represents a request to export drivers
+type ExportRequest struct {
+	// Format is ndjson (one driver per line, the default) or json (an array)
+	Format string `json:"format" example:"ndjson" enums:"ndjson,json"`
+	// FleetID only exports drivers of this fleet; fleet admins always export their own
+	FleetID string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+}
+
+// ExportUseCase defines the interface for exporting drivers in the background
+type ExportUseCase interface {
+	StartExport(ctx context.Context, req *ExportRequest, actor string) (*jobrunner.Job, error)
+	GetExport(ctx context.Context, id string) (*jobrunner.Job, error)
+	// OpenExport opens the file of a completed export; the caller closes it
+	OpenExport(ctx context.Context, id string) (*os.File, *jobrunner.Job, error)
+}
+
+// exportUseCase implements ExportUseCase
+type exportUseCase struct {
+	repo     domain.DriverPageRepository
+	runner   *jobrunner.Runner
+	logger   *zap.Logger
+	pageSize int
+	now      func() time.Time
+}
+
+// NewExportUseCase creates a new export use case running exports on runner
+func NewExportUseCase(repo domain.DriverPageRepository, runner *jobrunner.Runner, logger *zap.Logger) ExportUseCase {
+	return &exportUseCase{
+		repo:     repo,
+		runner:   runner,
+		logger:   logger,
+		pageSize: exportPageSize,
+		now:      time.Now,
+	}
+}
+
+// StartExport queues the export and returns its job. Fleet admins only
+// export, and later see, their own fleet's drivers.
+func (uc *exportUseCase) StartExport(ctx context.Context, req *ExportRequest, actor string) (*jobrunner.Job, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = ExportFormatNDJSON
+	}
+	if format != ExportFormatNDJSON && format != ExportFormatJSON {
+		return nil, ErrInvalidExportFormat
+	}
+	filter := domain.DriverFilter{FleetID: req.FleetID}
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+
+	job, err := uc.runner.Submit(exportJobKind, filter.FleetID, strings.TrimSpace(actor), func(ctx context.Context, run *jobrunner.Run) error {
+		return uc.export(ctx, run, filter, format)
+	})
+	if err != nil {
+		if errors.Is(err, jobrunner.ErrQueueFull) {
+			return nil, ErrTooManyJobs
+		}
+		uc.logger.Error("failed to queue export", zap.Error(err))
+		return nil, errors.New("failed to start export")
+	}
+
+	uc.logger.Info("audit: driver export started", append(actorFields(ctx),
+		zap.String("jobId", job.ID),
+		zap.String("actor", job.Actor),
+		zap.String("fleetId", filter.FleetID),
+		zap.String("format", format),
+	)...)
+	return job, nil
+}
+
+// GetExport returns the status and progress of an export
+func (uc *exportUseCase) GetExport(ctx context.Context, id string) (*jobrunner.Job, error) {
+	job, err := uc.runner.Get(id)
+	if err != nil || !canSeeExport(ctx, job) {
+		return nil, ErrExportNotFound
+	}
+	return job, nil
+}
+
+// OpenExport opens the file of a completed export
+func (uc *exportUseCase) OpenExport(ctx context.Context, id string) (*os.File, *jobrunner.Job, error) {
+	f, job, err := uc.runner.Open(id)
+	if job == nil || !canSeeExport(ctx, job) {
+		if f != nil {
+			f.Close()
+		}
+		return nil, nil, ErrExportNotFound
+	}
+	if err != nil {
+		if errors.Is(err, jobrunner.ErrNoResult) {
+			return nil, job, ErrExportNotReady
+		}
+		uc.logger.Error("failed to open export", zap.Error(err), zap.String("jobId", id))
+		return nil, nil, errors.New("failed to open export")
+	}
+	return f, job, nil
+}
+
+// canSeeExport hides other fleets' exports from fleet admins
+func canSeeExport(ctx context.Context, job *jobrunner.Job) bool {
+	if job.Kind != exportJobKind {
+		return false
+	}
+	identity, ok := domain.IdentityFromContext(ctx)
+	return !ok || identity.Role != domain.RoleFleetAdmin || job.Owner == identity.TenantID
+}
+
+// exportedDriver is a driver as written to an export file
+type exportedDriver struct {
+	*domain.Driver
+	// ChangedDuringExport marks drivers updated after the export started; their
+	// record is newer than the snapshot
+	ChangedDuringExport bool `json:"changedDuringExport,omitempty"`
+}
+
+// export writes every driver matching the filter to the job's result file.
+//
+// The export is a snapshot of the drivers that existed when it started: pages
+// are read in ID order after the last ID written, so drivers created or
+// deleted meanwhile never shift a page and no driver is skipped or written
+// twice, and drivers created after the start are left out. Drivers are read
+// as they are when their page is reached, so one updated after the start is
+// written with the update and flagged, and one deleted before its page is
+// reached is left out.
+func (uc *exportUseCase) export(ctx context.Context, run *jobrunner.Run, filter domain.DriverFilter, format string) error {
+	contentType := "application/x-ndjson"
+	if format == ExportFormatJSON {
+		contentType = "application/json"
+	}
+	out, err := run.Output(fmt.Sprintf("drivers.%s", format), contentType)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	encoder := json.NewEncoder(w)
+
+	startedAt := uc.now()
+	filter.CreatedBefore = startedAt
+	total, err := uc.repo.CountDrivers(ctx, filter)
+	if err != nil {
+		uc.logger.Error("failed to count drivers for export", zap.Error(err))
+		return errors.New("failed to count drivers")
+	}
+	run.SetTotal(total)
+
+	if format == ExportFormatJSON {
+		w.WriteString("[")
+	}
+	exported := 0
+	afterID := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		drivers, err := uc.repo.ListDriversAfter(ctx, filter, afterID, uc.pageSize)
+		if err != nil {
+			uc.logger.Error("failed to list drivers for export", zap.Error(err), zap.String("afterId", afterID))
+			return errors.New("failed to list drivers")
+		}
+		changed := int64(0)
+		for _, driver := range drivers {
+			if format == ExportFormatJSON && exported > 0 {
+				w.WriteString(",")
+			}
+			record := exportedDriver{Driver: driver, ChangedDuringExport: driver.UpdatedAt.After(startedAt)}
+			if record.ChangedDuringExport {
+				changed++
+			}
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("failed to write driver %s: %w", driver.ID, err)
+			}
+			exported++
+		}
+		run.Add(int64(len(drivers)))
+		run.AddChanged(changed)
+		if len(drivers) < uc.pageSize {
+			break
+		}
+		afterID = drivers[len(drivers)-1].ID
+	}
+	if format == ExportFormatJSON {
+		w.WriteString("]\n")
+	}
+	return w.Flush()
+}
@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/jobrunner"
+	"go.uber.org/zap"
+)
+
+func newTestExportUseCase(t *testing.T, repo domain.DriverPageRepository) ExportUseCase {
+	t.Helper()
+	runner, err := jobrunner.New(jobrunner.Options{Dir: t.TempDir()}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go runner.Run(ctx)
+	return NewExportUseCase(repo, runner, zap.NewNop())
+}
+
+// readExport waits for an export to complete and returns its file
+func readExport(t *testing.T, uc ExportUseCase, ctx context.Context, id string) (*jobrunner.Job, string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		f, job, err := uc.OpenExport(ctx, id)
+		if errors.Is(err, ErrExportNotReady) && job.Status != jobrunner.StatusFailed {
+			time.Sleep(5 * time.Millisecond)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		return job, string(data)
+	}
+	t.Fatalf("export %s did not complete", id)
+	return nil, ""
+}
+
+func newExportRepository() *mockDriverRepository {
+	repo := newMockDriverRepository()
+	repo.drivers["d1"] = &domain.Driver{ID: "d1", Plate: "34ABC1", FleetID: "fleet-1"}
+	repo.drivers["d2"] = &domain.Driver{ID: "d2", Plate: "34ABC2", FleetID: "fleet-1"}
+	repo.drivers["d3"] = &domain.Driver{ID: "d3", Plate: "34ABC3", FleetID: "fleet-2"}
+	return repo
+}
+
+func (m *mockDriverRepository) exportable(filter domain.DriverFilter) []*domain.Driver {
+	drivers := make([]*domain.Driver, 0, len(m.drivers))
+	for _, driver := range m.drivers {
+		if filter.FleetID != "" && driver.FleetID != filter.FleetID {
+			continue
+		}
+		if !filter.CreatedBefore.IsZero() && driver.CreatedAt.After(filter.CreatedBefore) {
+			continue
+		}
+		drivers = append(drivers, driver)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].ID < drivers[j].ID })
+	return drivers
+}
+
+func (m *mockDriverRepository) CountDrivers(ctx interface{}, filter domain.DriverFilter) (int64, error) {
+	return int64(len(m.exportable(filter))), nil
+}
+
+func (m *mockDriverRepository) ListDriversAfter(ctx interface{}, filter domain.DriverFilter, afterID string, limit int) ([]*domain.Driver, error) {
+	if m.shouldFailList {
+		return nil, errors.New("repository error")
+	}
+	page := []*domain.Driver{}
+	for _, driver := range m.exportable(filter) {
+		if driver.ID > afterID && len(page) < limit {
+			page = append(page, driver)
+		}
+	}
+	return page, nil
+}
+
+// concurrentWrites changes drivers before an export reads a page, as other
+// requests would while it runs
+type concurrentWrites struct {
+	*mockDriverRepository
+	pages  int
+	before map[int]func()
+}
+
+func (r *concurrentWrites) ListDriversAfter(ctx interface{}, filter domain.DriverFilter, afterID string, limit int) ([]*domain.Driver, error) {
+	r.pages++
+	if write := r.before[r.pages]; write != nil {
+		write()
+	}
+	return r.mockDriverRepository.ListDriversAfter(ctx, filter, afterID, limit)
+}
+
+func TestExportUseCase_NDJSON(t *testing.T) {
+	uc := newTestExportUseCase(t, newExportRepository())
+	ctx := context.Background()
+
+	job, err := uc.StartExport(ctx, &ExportRequest{}, "ops@bitaksi.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Kind != "export" || job.Actor != "ops@bitaksi.com" {
+		t.Errorf("unexpected job: %+v", job)
+	}
+
+	job, data := readExport(t, uc, ctx, job.ID)
+	lines := strings.Split(strings.TrimSpace(data), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), data)
+	}
+	for _, line := range lines {
+		var driver domain.Driver
+		if err := json.Unmarshal([]byte(line), &driver); err != nil {
+			t.Errorf("invalid line %q: %v", line, err)
+		}
+	}
+	if job.Total != 3 || job.Processed != 3 {
+		t.Errorf("expected 3/3 processed, got %d/%d", job.Processed, job.Total)
+	}
+	if job.Result.FileName != "drivers.ndjson" || job.Result.ContentType != "application/x-ndjson" {
+		t.Errorf("unexpected result: %+v", job.Result)
+	}
+}
+
+func TestExportUseCase_JSONForFleet(t *testing.T) {
+	uc := newTestExportUseCase(t, newExportRepository())
+	ctx := context.Background()
+
+	job, err := uc.StartExport(ctx, &ExportRequest{Format: "JSON", FleetID: "fleet-1"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	job, data := readExport(t, uc, ctx, job.ID)
+	var drivers []domain.Driver
+	if err := json.Unmarshal([]byte(data), &drivers); err != nil {
+		t.Fatalf("invalid JSON array %q: %v", data, err)
+	}
+	if len(drivers) != 2 {
+		t.Errorf("expected 2 drivers of fleet-1, got %d", len(drivers))
+	}
+	if job.Owner != "fleet-1" || job.Result.FileName != "drivers.json" {
+		t.Errorf("unexpected job: %+v", job)
+	}
+}
+
+func TestExportUseCase_InvalidFormat(t *testing.T) {
+	uc := newTestExportUseCase(t, newExportRepository())
+
+	if _, err := uc.StartExport(context.Background(), &ExportRequest{Format: "csv"}, ""); !errors.Is(err, ErrInvalidExportFormat) {
+		t.Errorf("expected ErrInvalidExportFormat, got %v", err)
+	}
+}
+
+func TestExportUseCase_FleetAdminScope(t *testing.T) {
+	uc := newTestExportUseCase(t, newExportRepository())
+	fleet1 := domain.ContextWithIdentity(context.Background(), domain.Identity{Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	fleet2 := domain.ContextWithIdentity(context.Background(), domain.Identity{Role: domain.RoleFleetAdmin, TenantID: "fleet-2"})
+
+	// A fleet admin asking for another fleet still exports their own
+	job, err := uc.StartExport(fleet2, &ExportRequest{FleetID: "fleet-1"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Owner != "fleet-2" {
+		t.Errorf("expected the export to be scoped to fleet-2, got %q", job.Owner)
+	}
+	_, data := readExport(t, uc, fleet2, job.ID)
+	if strings.Count(data, "\n") != 1 || !strings.Contains(data, `"34ABC3"`) {
+		t.Errorf("expected only fleet-2's driver, got %q", data)
+	}
+
+	if _, err := uc.GetExport(fleet1, job.ID); !errors.Is(err, ErrExportNotFound) {
+		t.Errorf("expected ErrExportNotFound for another fleet, got %v", err)
+	}
+	if _, _, err := uc.OpenExport(fleet1, job.ID); !errors.Is(err, ErrExportNotFound) {
+		t.Errorf("expected ErrExportNotFound for another fleet, got %v", err)
+	}
+	if _, err := uc.GetExport(context.Background(), job.ID); err != nil {
+		t.Errorf("expected admins to see every export, got %v", err)
+	}
+}
+
+func TestExportUseCase_ListFailure(t *testing.T) {
+	repo := newExportRepository()
+	repo.shouldFailList = true
+	uc := newTestExportUseCase(t, repo)
+	ctx := context.Background()
+
+	job, err := uc.StartExport(ctx, &ExportRequest{}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != jobrunner.StatusFailed && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		job, _ = uc.GetExport(ctx, job.ID)
+	}
+	if job.Status != jobrunner.StatusFailed || job.Error != "failed to list drivers" {
+		t.Errorf("expected the export to fail, got %+v", job)
+	}
+	if _, _, err := uc.OpenExport(ctx, job.ID); !errors.Is(err, ErrExportNotReady) {
+		t.Errorf("expected ErrExportNotReady, got %v", err)
+	}
+}
+
+func TestExportUseCase_SnapshotDuringWrites(t *testing.T) {
+	startedAt := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	later := startedAt.Add(time.Second)
+	repo := newMockDriverRepository()
+	for _, id := range []string{"d1", "d2", "d3", "d4", "d5", "d6"} {
+		repo.drivers[id] = &domain.Driver{ID: id, CreatedAt: startedAt.Add(-time.Hour), UpdatedAt: startedAt.Add(-time.Hour)}
+	}
+	writes := &concurrentWrites{mockDriverRepository: repo, before: map[int]func(){
+		2: func() {
+			// Offset pages would now skip d3 and pick up the new drivers
+			delete(repo.drivers, "d1")
+			repo.drivers["d0"] = &domain.Driver{ID: "d0", CreatedAt: later, UpdatedAt: later}
+			repo.drivers["d9"] = &domain.Driver{ID: "d9", CreatedAt: later, UpdatedAt: later}
+			repo.drivers["d5"].UpdatedAt = later
+		},
+		3: func() {
+			delete(repo.drivers, "d6")
+		},
+	}}
+
+	runner, err := jobrunner.New(jobrunner.Options{Dir: t.TempDir()}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runner.Run(runCtx)
+	uc := NewExportUseCase(writes, runner, zap.NewNop()).(*exportUseCase)
+	uc.pageSize = 2
+	uc.now = func() time.Time { return startedAt }
+	ctx := context.Background()
+
+	job, err := uc.StartExport(ctx, &ExportRequest{}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	job, data := readExport(t, uc, ctx, job.ID)
+
+	var ids []string
+	changed := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		var record struct {
+			ID                  string `json:"id"`
+			ChangedDuringExport bool   `json:"changedDuringExport"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		ids = append(ids, record.ID)
+		changed[record.ID] = record.ChangedDuringExport
+	}
+	if strings.Join(ids, ",") != "d1,d2,d3,d4,d5" {
+		t.Errorf("expected d1-d5 exactly once, got %v", ids)
+	}
+	if !changed["d5"] || changed["d4"] {
+		t.Errorf("expected only d5 to be flagged, got %v", changed)
+	}
+	if job.Total != 6 || job.Processed != 5 || job.Changed != 1 {
+		t.Errorf("expected 5/6 processed with 1 changed, got %d/%d with %d", job.Processed, job.Total, job.Changed)
+	}
+}
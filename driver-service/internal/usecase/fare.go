@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/routing"
+	"go.uber.org/zap"
+)
+
+// FareTariff prices a route. The metered fare is multiplied per taxi type and
+// never drops below Minimum.
+type FareTariff struct {
+	Base      float64
+	PerKm     float64
+	PerMinute float64
+	Minimum   float64
+	Currency  string
+	// Multipliers scale the fare of premium taxi types, e.g. siyah -> 2
+	Multipliers map[domain.TaxiType]float64
+}
+
+// Price returns the fare of a route for the taxi type, rounded to kuruş
+func (t FareTariff) Price(route routing.Route, taxiType *domain.TaxiType) float64 {
+	amount := t.Base + t.PerKm*route.DistanceKm + t.PerMinute*route.DurationSec/60
+	if taxiType != nil {
+		if multiplier, ok := t.Multipliers[*taxiType]; ok && multiplier > 0 {
+			amount *= multiplier
+		}
+	}
+	if amount < t.Minimum {
+		amount = t.Minimum
+	}
+	return math.Round(amount*100) / 100
+}
+
+// EstimateFareRequest represents a request for a fare quote
+type EstimateFareRequest struct {
+	Pickup   domain.Location  `json:"pickup"`
+	Dropoff  domain.Location  `json:"dropoff"`
+	TaxiType *domain.TaxiType `json:"taxiType,omitempty" example:"sari"`
+}
+
+// EstimateFare quotes the fare from pickup to dropoff without requesting a trip
+func (uc *tripUseCase) EstimateFare(ctx context.Context, req *EstimateFareRequest) (*domain.FareEstimate, error) {
+	if req.Pickup.Lat == 0 && req.Pickup.Lon == 0 {
+		return nil, ErrPickupRequired
+	}
+	if req.Dropoff.Lat == 0 && req.Dropoff.Lon == 0 {
+		return nil, ErrDropoffRequired
+	}
+	if err := validateCoordinates(req.Pickup.Lat, req.Pickup.Lon); err != nil {
+		return nil, err
+	}
+	if err := validateCoordinates(req.Dropoff.Lat, req.Dropoff.Lon); err != nil {
+		return nil, err
+	}
+	if req.TaxiType != nil && !req.TaxiType.IsValid() {
+		return nil, fmt.Errorf("invalid taxiType: %s", *req.TaxiType)
+	}
+
+	estimate, err := uc.estimateFare(ctx, req.Pickup, req.Dropoff, req.TaxiType)
+	if err != nil {
+		uc.logger.Error("failed to estimate fare", zap.Error(err))
+		return nil, errors.New("failed to estimate fare")
+	}
+	return estimate, nil
+}
+
+// tripFare is the fare of a completed trip: the metered fare when one was
+// reported, otherwise the reported distance priced with the tariff, falling
+// back to the quote when no distance was reported either
+func (uc *tripUseCase) tripFare(trip *domain.Trip, req *CompleteTripRequest) float64 {
+	switch {
+	case req.Fare != nil:
+		return math.Round(*req.Fare*100) / 100
+	case req.DistanceKm > 0:
+		route := routing.Route{DistanceKm: req.DistanceKm}
+		if trip.Estimate != nil && trip.Estimate.DistanceKm > 0 {
+			// Scale the quoted duration to the distance actually driven
+			route.DurationSec = float64(trip.Estimate.DurationSec) * req.DistanceKm / trip.Estimate.DistanceKm
+		}
+		return uc.tariff.Price(route, trip.TaxiType)
+	case trip.Estimate != nil:
+		return trip.Estimate.Amount
+	}
+	return 0
+}
+
+// estimateFare routes pickup to dropoff and prices the route
+func (uc *tripUseCase) estimateFare(ctx context.Context, pickup, dropoff domain.Location, taxiType *domain.TaxiType) (*domain.FareEstimate, error) {
+	routes, err := uc.router.Routes(ctx, pickup, []domain.Location{dropoff})
+	if err != nil {
+		return nil, err
+	}
+	route := routes[0]
+	return &domain.FareEstimate{
+		DistanceKm:  math.Round(route.DistanceKm*100) / 100,
+		DurationSec: int(math.Round(route.DurationSec)),
+		Amount:      uc.tariff.Price(route, taxiType),
+		Currency:    uc.tariff.Currency,
+		Provider:    uc.router.Name(),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// FleetUseCase defines the interface for fleet management
+type FleetUseCase interface {
+	CreateFleet(ctx context.Context, req *CreateFleetRequest) (*domain.Fleet, error)
+	GetFleet(ctx context.Context, id string) (*domain.Fleet, error)
+	ListFleets(ctx context.Context) ([]*domain.Fleet, error)
+	AssignDriver(ctx context.Context, driverID, fleetID string) (*domain.Driver, error)
+	GetQuota(ctx context.Context, fleetID string) (*domain.DriverQuota, error)
+}
+
+// CreateFleetRequest represents the request to create a fleet
+type CreateFleetRequest struct {
+	Name        string `json:"name" example:"Kadıköy Taksi" binding:"required"`
+	CompanyName string `json:"companyName,omitempty" example:"Kadıköy Taksi Ltd. Şti."`
+}
+
+// AssignFleetRequest represents the request to move a driver into a fleet.
+// An empty fleetId removes the driver from its fleet.
+type AssignFleetRequest struct {
+	FleetID string `json:"fleetId" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+}
+
+// fleetUseCase implements FleetUseCase
+type fleetUseCase struct {
+	fleets  domain.FleetRepository
+	drivers domain.DriverRepository
+	quotas  *DriverQuotas
+	logger  *zap.Logger
+}
+
+// FleetUseCaseOption configures optional fleet use case behaviour
+type FleetUseCaseOption func(*fleetUseCase)
+
+// WithFleetQuotas refuses drivers moved into a fleet that has used up its
+// quota and reports fleet quota usage
+func WithFleetQuotas(quotas *DriverQuotas) FleetUseCaseOption {
+	return func(uc *fleetUseCase) {
+		uc.quotas = quotas
+	}
+}
+
+// NewFleetUseCase creates a new fleet use case
+func NewFleetUseCase(fleets domain.FleetRepository, drivers domain.DriverRepository, logger *zap.Logger, opts ...FleetUseCaseOption) FleetUseCase {
+	uc := &fleetUseCase{
+		fleets:  fleets,
+		drivers: drivers,
+		logger:  logger,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// CreateFleet creates a new fleet with a unique name
+func (uc *fleetUseCase) CreateFleet(ctx context.Context, req *CreateFleetRequest) (*domain.Fleet, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrFleetNameRequired
+	}
+
+	fleet := &domain.Fleet{
+		Name:        name,
+		CompanyName: strings.TrimSpace(req.CompanyName),
+	}
+	if err := uc.fleets.Create(ctx, fleet); err != nil {
+		if err.Error() == ErrFleetNameTaken.Error() {
+			return nil, ErrFleetNameTaken
+		}
+		uc.logger.Error("failed to create fleet", zap.Error(err))
+		return nil, errors.New("failed to create fleet")
+	}
+
+	uc.logger.Info("fleet created", append(actorFields(ctx), zap.String("id", fleet.ID), zap.String("name", fleet.Name))...)
+	return fleet, nil
+}
+
+// GetFleet retrieves a fleet by ID
+func (uc *fleetUseCase) GetFleet(ctx context.Context, id string) (*domain.Fleet, error) {
+	fleet, err := uc.fleets.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrFleetNotFound
+	}
+	return fleet, nil
+}
+
+// ListFleets returns all fleets
+func (uc *fleetUseCase) ListFleets(ctx context.Context) ([]*domain.Fleet, error) {
+	fleets, err := uc.fleets.List(ctx)
+	if err != nil {
+		uc.logger.Error("failed to list fleets", zap.Error(err))
+		return nil, errors.New("failed to list fleets")
+	}
+	return fleets, nil
+}
+
+// AssignDriver moves a driver into a fleet, or out of its fleet when fleetID is empty
+func (uc *fleetUseCase) AssignDriver(ctx context.Context, driverID, fleetID string) (*domain.Driver, error) {
+	driver, err := uc.drivers.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if fleetID != "" {
+		if _, err := uc.fleets.GetByID(ctx, fleetID); err != nil {
+			return nil, ErrFleetNotFound
+		}
+	}
+	if driver.FleetID == fleetID {
+		return driver, nil
+	}
+
+	fleetDrivers, err := uc.quotas.admit(ctx, fleetID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := driver.FleetID
+	driver.FleetID = fleetID
+	if err := uc.drivers.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to assign driver to fleet", zap.Error(err), zap.String("id", driverID))
+		return nil, errors.New("failed to update driver")
+	}
+	uc.quotas.joined(fleetID, fleetDrivers)
+
+	uc.logger.Info("driver fleet changed", append(actorFields(ctx),
+		zap.String("id", driverID),
+		zap.String("from", previous),
+		zap.String("to", fleetID),
+	)...)
+	return driver, nil
+}
+
+// GetQuota reports how many drivers a fleet has against its quota. It needs
+// WithFleetQuotas, which counts the drivers.
+func (uc *fleetUseCase) GetQuota(ctx context.Context, fleetID string) (*domain.DriverQuota, error) {
+	if uc.quotas == nil {
+		return nil, errors.New("fleet quotas are not configured")
+	}
+	if _, err := uc.fleets.GetByID(ctx, fleetID); err != nil {
+		return nil, ErrFleetNotFound
+	}
+
+	usage, err := uc.quotas.Usage(ctx, fleetID)
+	if err != nil {
+		uc.logger.Error("failed to count fleet drivers", zap.Error(err), zap.String("fleetId", fleetID))
+		return nil, err
+	}
+	return usage, nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockFleetRepository is an in-memory fleet repository
+type mockFleetRepository struct {
+	fleets     map[string]*domain.Fleet
+	shouldFail bool
+}
+
+func newMockFleetRepository() *mockFleetRepository {
+	return &mockFleetRepository{fleets: make(map[string]*domain.Fleet)}
+}
+
+func (m *mockFleetRepository) Create(ctx interface{}, fleet *domain.Fleet) error {
+	if m.shouldFail {
+		return errors.New("repository error")
+	}
+	for _, existing := range m.fleets {
+		if existing.Name == fleet.Name {
+			return errors.New("fleet name already exists")
+		}
+	}
+	fleet.ID = "fleet-" + fleet.Name
+	m.fleets[fleet.ID] = fleet
+	return nil
+}
+
+func (m *mockFleetRepository) GetByID(ctx interface{}, id string) (*domain.Fleet, error) {
+	fleet, ok := m.fleets[id]
+	if !ok {
+		return nil, errors.New("fleet not found")
+	}
+	return fleet, nil
+}
+
+func (m *mockFleetRepository) List(ctx interface{}) ([]*domain.Fleet, error) {
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	fleets := make([]*domain.Fleet, 0, len(m.fleets))
+	for _, fleet := range m.fleets {
+		fleets = append(fleets, fleet)
+	}
+	return fleets, nil
+}
+
+func TestFleetUseCase_CreateFleet(t *testing.T) {
+	uc := NewFleetUseCase(newMockFleetRepository(), newMockDriverRepository(), zap.NewNop())
+	ctx := context.Background()
+
+	fleet, err := uc.CreateFleet(ctx, &CreateFleetRequest{Name: "  Kadikoy  ", CompanyName: "Kadikoy Ltd"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fleet.ID == "" || fleet.Name != "Kadikoy" {
+		t.Errorf("unexpected fleet %+v", fleet)
+	}
+
+	if _, err := uc.CreateFleet(ctx, &CreateFleetRequest{Name: "Kadikoy"}); !errors.Is(err, ErrFleetNameTaken) {
+		t.Errorf("expected ErrFleetNameTaken, got %v", err)
+	}
+	if _, err := uc.CreateFleet(ctx, &CreateFleetRequest{Name: "   "}); !errors.Is(err, ErrFleetNameRequired) {
+		t.Errorf("expected ErrFleetNameRequired, got %v", err)
+	}
+	if _, err := uc.GetFleet(ctx, "missing"); !errors.Is(err, ErrFleetNotFound) {
+		t.Errorf("expected ErrFleetNotFound, got %v", err)
+	}
+}
+
+func TestFleetUseCase_AssignDriver(t *testing.T) {
+	fleets := newMockFleetRepository()
+	fleets.fleets["fleet-1"] = &domain.Fleet{ID: "fleet-1", Name: "Kadikoy"}
+	drivers := newMockDriverRepository()
+	drivers.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	uc := NewFleetUseCase(fleets, drivers, zap.NewNop())
+	ctx := context.Background()
+
+	driver, err := uc.AssignDriver(ctx, "driver-1", "fleet-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.FleetID != "fleet-1" || drivers.drivers["driver-1"].FleetID != "fleet-1" {
+		t.Errorf("expected driver to be in fleet-1, got %q", driver.FleetID)
+	}
+
+	if _, err := uc.AssignDriver(ctx, "driver-1", "fleet-2"); !errors.Is(err, ErrFleetNotFound) {
+		t.Errorf("expected ErrFleetNotFound, got %v", err)
+	}
+	if _, err := uc.AssignDriver(ctx, "missing", "fleet-1"); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+
+	driver, err = uc.AssignDriver(ctx, "driver-1", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.FleetID != "" {
+		t.Errorf("expected driver to leave the fleet, got %q", driver.FleetID)
+	}
+}
+
+func TestDriverUseCase_FleetFilters(t *testing.T) {
+	fleets := newMockFleetRepository()
+	fleets.fleets["fleet-1"] = &domain.Fleet{ID: "fleet-1", Name: "Kadikoy"}
+	repo := newMockDriverRepository()
+	repo.drivers["a"] = &domain.Driver{ID: "a", FleetID: "fleet-1", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	repo.drivers["b"] = &domain.Driver{ID: "b", TaxiType: domain.TaxiTypeSari, Location: domain.Location{Lat: 41.0431, Lon: 29.0099}}
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithFleets(fleets))
+	ctx := context.Background()
+
+	list, err := uc.ListDrivers(ctx, domain.DriverFilter{FleetID: "fleet-1"}, 1, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.TotalCount != 1 || list.Drivers[0].ID != "a" {
+		t.Errorf("expected only driver a, got %d drivers", list.TotalCount)
+	}
+
+	nearby, err := uc.FindNearbyDrivers(ctx, 41.0431, 29.0099, nil, domain.DriverFilter{FleetID: "fleet-1"}, domain.NearbyPage{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nearby) != 1 || nearby[0].ID != "a" {
+		t.Errorf("expected only driver a nearby, got %d drivers", len(nearby))
+	}
+
+	_, err = uc.CreateDriver(ctx, &CreateDriverRequest{
+		FirstName: "Ahmet",
+		LastName:  "Demir",
+		Plate:     "34ABC123",
+		TaxiType:  domain.TaxiTypeSari,
+		CarBrand:  "Toyota",
+		CarModel:  "Corolla",
+		Lat:       41.0431,
+		Lon:       29.0099,
+		FleetID:   "fleet-2",
+	})
+	if !errors.Is(err, ErrFleetNotFound) {
+		t.Errorf("expected ErrFleetNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// HeartbeatUseCase defines the interface for driver app heartbeats
+type HeartbeatUseCase interface {
+	Heartbeat(ctx context.Context, driverID string) error
+	OnlineStats(ctx context.Context, filter domain.DriverFilter) (*domain.OnlineStats, error)
+}
+
+// heartbeatUseCase implements HeartbeatUseCase
+type heartbeatUseCase struct {
+	repo    domain.HeartbeatRepository
+	timeout time.Duration
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewHeartbeatUseCase creates a new heartbeat use case. Drivers count as online
+// while their latest heartbeat is younger than timeout.
+func NewHeartbeatUseCase(repo domain.HeartbeatRepository, timeout time.Duration, logger *zap.Logger) HeartbeatUseCase {
+	return &heartbeatUseCase{
+		repo:    repo,
+		timeout: timeout,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Heartbeat marks the driver as seen now
+func (uc *heartbeatUseCase) Heartbeat(ctx context.Context, driverID string) error {
+	if err := uc.repo.RecordHeartbeat(ctx, driverID, uc.now().UTC()); err != nil {
+		switch err.Error() {
+		case "driver not found", "invalid driver ID":
+			return errors.New("driver not found")
+		}
+		uc.logger.Error("failed to record heartbeat", zap.Error(err), zap.String("id", driverID))
+		return errors.New("failed to record heartbeat")
+	}
+	return nil
+}
+
+// OnlineStats counts the drivers matching the filter that are online and offline
+func (uc *heartbeatUseCase) OnlineStats(ctx context.Context, filter domain.DriverFilter) (*domain.OnlineStats, error) {
+	now := uc.now().UTC()
+	online, total, err := uc.repo.CountOnline(ctx, filter, now.Add(-uc.timeout))
+	if err != nil {
+		uc.logger.Error("failed to count online drivers", zap.Error(err))
+		return nil, errors.New("failed to get online stats")
+	}
+
+	return &domain.OnlineStats{
+		Total:               total,
+		Online:              online,
+		Offline:             total - online,
+		HeartbeatTimeoutSec: int(uc.timeout / time.Second),
+		AsOf:                now,
+	}, nil
+}
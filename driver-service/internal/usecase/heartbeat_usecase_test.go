@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockHeartbeatRepository keeps last-seen times per driver
+type mockHeartbeatRepository struct {
+	lastSeen map[string]time.Time
+	fleets   map[string]string
+}
+
+func (m *mockHeartbeatRepository) RecordHeartbeat(ctx interface{}, driverID string, at time.Time) error {
+	if _, ok := m.fleets[driverID]; !ok {
+		return errors.New("driver not found")
+	}
+	m.lastSeen[driverID] = at
+	return nil
+}
+
+func (m *mockHeartbeatRepository) CountOnline(ctx interface{}, filter domain.DriverFilter, since time.Time) (int64, int64, error) {
+	var online, total int64
+	for id, fleetID := range m.fleets {
+		if filter.FleetID != "" && fleetID != filter.FleetID {
+			continue
+		}
+		total++
+		if seen, ok := m.lastSeen[id]; ok && !seen.Before(since) {
+			online++
+		}
+	}
+	return online, total, nil
+}
+
+func TestHeartbeatUseCase(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	repo := &mockHeartbeatRepository{
+		lastSeen: map[string]time.Time{"driver-2": now.Add(-5 * time.Minute)},
+		fleets:   map[string]string{"driver-1": "fleet-1", "driver-2": "fleet-1", "driver-3": "fleet-2"},
+	}
+	uc := NewHeartbeatUseCase(repo, 2*time.Minute, zap.NewNop()).(*heartbeatUseCase)
+	uc.now = func() time.Time { return now }
+
+	if err := uc.Heartbeat(context.Background(), "driver-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.Heartbeat(context.Background(), "missing"); err == nil || err.Error() != "driver not found" {
+		t.Errorf("expected driver not found, got %v", err)
+	}
+
+	stats, err := uc.OnlineStats(context.Background(), domain.DriverFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Total != 3 || stats.Online != 1 || stats.Offline != 2 || stats.HeartbeatTimeoutSec != 120 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	stats, err = uc.OnlineStats(context.Background(), domain.DriverFilter{FleetID: "fleet-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stats.Total != 1 || stats.Online != 0 || stats.Offline != 1 {
+		t.Errorf("unexpected fleet stats: %+v", stats)
+	}
+}
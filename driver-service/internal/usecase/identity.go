@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// actorFields attributes an audit log entry to the caller, when the request came with one
+func actorFields(ctx context.Context) []zap.Field {
+	identity, ok := domain.IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("actorId", identity.UserID),
+		zap.String("actorRole", identity.Role),
+		zap.String("actorTenantId", identity.TenantID),
+	}
+}
+
+// authorizeDriver rejects fleet admins acting on a driver outside their fleet
+// and driver devices acting on another driver
+func authorizeDriver(ctx context.Context, driver *domain.Driver) error {
+	identity, ok := domain.IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	switch {
+	case identity.Role == domain.RoleFleetAdmin && driver.FleetID != identity.TenantID:
+		return ErrDriverNotInFleet
+	case identity.Role == domain.RoleDriverDevice && driver.ID != identity.UserID:
+		return ErrDriverNotOwned
+	}
+	return nil
+}
+
+// authorizeTrip rejects riders acting on another rider's trip
+func authorizeTrip(ctx context.Context, trip *domain.Trip) error {
+	identity, ok := domain.IdentityFromContext(ctx)
+	if ok && identity.Role == domain.RoleRider && trip.RiderID != identity.UserID {
+		return ErrTripNotOwned
+	}
+	return nil
+}
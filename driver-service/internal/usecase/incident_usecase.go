@@ -0,0 +1,392 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Limits of incident reports and listings
+const (
+	maxIncidentText         = 2000
+	defaultIncidentPageSize = 20
+	maxIncidentPageSize     = 100
+)
+
+// incidentSuspensionReason marks suspensions made by severe incidents, so
+// dismissing the incidents only lifts those
+const incidentSuspensionReason = "incident under investigation"
+
+// IncidentUseCase defines the interface for complaints about drivers
+type IncidentUseCase interface {
+	ReportIncident(ctx context.Context, driverID string, req *ReportIncidentRequest) (*domain.Incident, error)
+	GetIncident(ctx context.Context, id string) (*domain.Incident, error)
+	ListIncidents(ctx context.Context, filter domain.IncidentFilter, page, pageSize int) (*ListIncidentsResponse, error)
+	UpdateIncidentStatus(ctx context.Context, id string, req *IncidentStatusRequest) (*domain.Incident, error)
+}
+
+// ReportIncidentRequest represents the request to report an incident against a driver
+type ReportIncidentRequest struct {
+	Category    domain.IncidentCategory `json:"category" example:"conduct" binding:"required"`
+	Severity    domain.IncidentSeverity `json:"severity" example:"medium" binding:"required"`
+	Description string                  `json:"description" example:"Driver was on the phone during the whole ride" binding:"required"`
+	// TripID links the trip the incident happened on; riders must give one of their trips
+	TripID string `json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+}
+
+// IncidentStatusRequest represents the request to move an incident's investigation on
+type IncidentStatusRequest struct {
+	Status domain.IncidentStatus `json:"status" example:"resolved" binding:"required"`
+	// Outcome is required when resolving: upheld or dismissed
+	Outcome domain.IncidentOutcome `json:"outcome,omitempty" example:"dismissed"`
+	Note    string                 `json:"note,omitempty" example:"Rider confirmed the driver used a hands-free set"`
+	// Severity confirms or corrects the reported severity when moving to
+	// investigating; a confirmed high or critical severity takes effect then
+	Severity domain.IncidentSeverity `json:"severity,omitempty" example:"high"`
+}
+
+// ListIncidentsResponse represents a page of incidents, newest first
+type ListIncidentsResponse struct {
+	Incidents  []*domain.Incident `json:"incidents"`
+	TotalCount int64              `json:"totalCount" example:"1"`
+	Page       int                `json:"page" example:"1"`
+	PageSize   int                `json:"pageSize" example:"20"`
+	TotalPages int                `json:"totalPages" example:"1"`
+	HasNext    bool               `json:"hasNext" example:"false"`
+	HasPrev    bool               `json:"hasPrev" example:"false"`
+}
+
+// suspensionSetter suspends and reinstates drivers with the events and shift
+// records of the driver use case
+type suspensionSetter interface {
+	SetSuspension(ctx context.Context, id string, suspended bool, reason string) (*domain.Driver, error)
+}
+
+// incidentUseCase implements IncidentUseCase
+type incidentUseCase struct {
+	repo       domain.IncidentRepository
+	driverRepo domain.DriverRepository
+	tripRepo   domain.TripRepository
+	drivers    suspensionSetter
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewIncidentUseCase creates a new incident use case. Drivers are suspended
+// and reinstated through drivers, so subscribers hear of it.
+func NewIncidentUseCase(repo domain.IncidentRepository, driverRepo domain.DriverRepository, tripRepo domain.TripRepository, drivers DriverUseCase, logger *zap.Logger) IncidentUseCase {
+	return &incidentUseCase{
+		repo:       repo,
+		driverRepo: driverRepo,
+		tripRepo:   tripRepo,
+		drivers:    drivers,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// ReportIncident records an incident against the driver. High and critical
+// incidents reported by operators and fleet admins suspend the driver until
+// they are dismissed; critical ones also revoke the driver's identity
+// verification, which takes a new identity check. Riders choose their own
+// severity, so their reports are stored open without effects until an
+// investigator confirms the severity.
+func (uc *incidentUseCase) ReportIncident(ctx context.Context, driverID string, req *ReportIncidentRequest) (*domain.Incident, error) {
+	description := strings.TrimSpace(req.Description)
+	switch {
+	case !req.Category.IsValid():
+		return nil, ErrInvalidIncidentCategory
+	case !req.Severity.IsValid():
+		return nil, ErrInvalidIncidentSeverity
+	case description == "" || utf8.RuneCountInString(description) > maxIncidentText:
+		return nil, ErrInvalidIncidentText
+	}
+
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+	identity, _ := domain.IdentityFromContext(ctx)
+	tripID := strings.TrimSpace(req.TripID)
+	if tripID == "" && identity.Role == domain.RoleRider {
+		return nil, ErrIncidentTripRequired
+	}
+	if tripID != "" {
+		trip, err := uc.tripRepo.GetByID(ctx, tripID)
+		if err != nil {
+			if err.Error() == ErrTripNotFound.Error() {
+				return nil, ErrTripNotFound
+			}
+			uc.logger.Error("failed to get incident trip", zap.Error(err), zap.String("tripId", tripID))
+			return nil, errors.New("failed to report incident")
+		}
+		if err := authorizeTrip(ctx, trip); err != nil {
+			return nil, err
+		}
+		if trip.DriverID != driver.ID {
+			return nil, ErrIncidentTripMismatch
+		}
+	}
+
+	now := uc.now().UTC()
+	incident := &domain.Incident{
+		DriverID:     driver.ID,
+		FleetID:      driver.FleetID,
+		TripID:       tripID,
+		Category:     req.Category,
+		Severity:     req.Severity,
+		Description:  description,
+		Status:       domain.IncidentStatusOpen,
+		ReportedBy:   identity.UserID,
+		ReporterRole: identity.Role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	// The effects are applied before the incident is stored, so a failed
+	// report leaves the driver suspended rather than unchecked
+	if identity.Role == "" || identity.Role == domain.RoleFleetAdmin {
+		if err := uc.applyEffects(ctx, driver, incident); err != nil {
+			uc.logger.Error("failed to apply incident effects", zap.Error(err), zap.String("driverId", driver.ID))
+			return nil, errors.New("failed to report incident")
+		}
+	}
+	if err := uc.repo.Create(ctx, incident); err != nil {
+		uc.logger.Error("failed to create incident", zap.Error(err),
+			zap.String("driverId", driver.ID), zap.Bool("driverSuspended", incident.DriverSuspended))
+		return nil, errors.New("failed to report incident")
+	}
+
+	uc.logger.Info("incident reported", append(actorFields(ctx),
+		zap.String("id", incident.ID),
+		zap.String("driverId", incident.DriverID),
+		zap.String("tripId", incident.TripID),
+		zap.String("category", string(incident.Category)),
+		zap.String("severity", string(incident.Severity)),
+		zap.Bool("driverSuspended", incident.DriverSuspended),
+		zap.Bool("identityRevoked", incident.IdentityRevoked),
+	)...)
+	return incident, nil
+}
+
+// applyEffects suspends the driver of a severe incident and revokes the
+// identity verification for a critical one, recording what it changed
+func (uc *incidentUseCase) applyEffects(ctx context.Context, driver *domain.Driver, incident *domain.Incident) error {
+	if !incident.Severity.IsSevere() {
+		return nil
+	}
+	if incident.Severity == domain.IncidentSeverityCritical && driver.IdentityVerified {
+		driver.IdentityVerified = false
+		if err := uc.driverRepo.Update(ctx, driver.ID, driver); err != nil {
+			return err
+		}
+		incident.IdentityRevoked = true
+	}
+	if !driver.Suspended {
+		if _, err := uc.drivers.SetSuspension(ctx, driver.ID, true, incidentSuspensionReason); err != nil {
+			return err
+		}
+		incident.DriverSuspended = true
+	}
+	return nil
+}
+
+// GetIncident returns an incident the caller may see
+func (uc *incidentUseCase) GetIncident(ctx context.Context, id string) (*domain.Incident, error) {
+	if err := checkIncidentReader(ctx); err != nil {
+		return nil, err
+	}
+	incident, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == ErrIncidentNotFound.Error() {
+			return nil, ErrIncidentNotFound
+		}
+		uc.logger.Error("failed to get incident", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get incident")
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleFleetAdmin && incident.FleetID != identity.TenantID {
+		return nil, ErrDriverNotInFleet
+	}
+	return incident, nil
+}
+
+// ListIncidents returns a page of the incidents matching the filter, newest
+// first. Fleet admins only see incidents reported while the driver was in
+// their fleet.
+func (uc *incidentUseCase) ListIncidents(ctx context.Context, filter domain.IncidentFilter, page, pageSize int) (*ListIncidentsResponse, error) {
+	if err := checkIncidentReader(ctx); err != nil {
+		return nil, err
+	}
+	switch {
+	case filter.Category != "" && !filter.Category.IsValid():
+		return nil, ErrInvalidIncidentCategory
+	case filter.Severity != "" && !filter.Severity.IsValid():
+		return nil, ErrInvalidIncidentSeverity
+	case filter.Status != "" && !filter.Status.IsValid():
+		return nil, ErrInvalidIncidentStatus
+	}
+	if filter.DriverID != "" {
+		driver, err := uc.driverRepo.GetByID(ctx, filter.DriverID)
+		if err != nil {
+			return nil, errors.New("driver not found")
+		}
+		if err := authorizeDriver(ctx, driver); err != nil {
+			return nil, err
+		}
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultIncidentPageSize
+	}
+	if pageSize > maxIncidentPageSize {
+		pageSize = maxIncidentPageSize
+	}
+	incidents, total, err := uc.repo.List(ctx, filter, page, pageSize)
+	if err != nil {
+		uc.logger.Error("failed to list incidents", zap.Error(err))
+		return nil, errors.New("failed to list incidents")
+	}
+	if incidents == nil {
+		incidents = []*domain.Incident{}
+	}
+
+	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
+	return &ListIncidentsResponse{
+		Incidents:  incidents,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}, nil
+}
+
+// UpdateIncidentStatus moves an incident from open to investigating or
+// resolved, or from investigating to resolved. Moving to investigating with a
+// severity confirms it, applying the effects of a high or critical one that
+// the report did not already apply. Dismissing the last unresolved
+// incident that suspended the driver reinstates the driver; identity
+// verification revoked by a critical incident is not restored.
+func (uc *incidentUseCase) UpdateIncidentStatus(ctx context.Context, id string, req *IncidentStatusRequest) (*domain.Incident, error) {
+	if !req.Status.IsValid() {
+		return nil, ErrInvalidIncidentStatus
+	}
+	resolving := req.Status == domain.IncidentStatusResolved
+	if resolving != req.Outcome.IsValid() || (!resolving && req.Outcome != "") {
+		return nil, ErrIncidentOutcome
+	}
+	if req.Severity != "" {
+		if !req.Severity.IsValid() {
+			return nil, ErrInvalidIncidentSeverity
+		}
+		if req.Status != domain.IncidentStatusInvestigating {
+			return nil, ErrIncidentSeverityConfirm
+		}
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxIncidentText {
+		return nil, ErrInvalidIncidentText
+	}
+
+	incident, err := uc.GetIncident(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	from := incident.Status
+	if !from.CanMoveTo(req.Status) {
+		return nil, ErrIncidentTransition
+	}
+
+	now := uc.now().UTC()
+	incident.Status = req.Status
+	incident.UpdatedAt = now
+	if note != "" {
+		incident.Note = note
+	}
+	if resolving {
+		incident.Outcome = req.Outcome
+		incident.ResolvedAt = &now
+	}
+	if req.Severity != "" {
+		incident.Severity = req.Severity
+		// As when reporting, the effects come first so a failed update leaves
+		// the driver suspended rather than unchecked
+		driver, err := uc.driverRepo.GetByID(ctx, incident.DriverID)
+		if err != nil {
+			return nil, errors.New("driver not found")
+		}
+		if err := uc.applyEffects(ctx, driver, incident); err != nil {
+			uc.logger.Error("failed to apply incident effects", zap.Error(err), zap.String("id", id), zap.String("driverId", driver.ID))
+			return nil, errors.New("failed to update incident")
+		}
+	}
+	ok, err := uc.repo.Transition(ctx, incident, from)
+	if err != nil {
+		uc.logger.Error("failed to update incident", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to update incident")
+	}
+	if !ok {
+		// Someone else moved it on meanwhile
+		return nil, ErrIncidentTransition
+	}
+
+	uc.logger.Info("incident status changed", append(actorFields(ctx),
+		zap.String("id", id),
+		zap.String("driverId", incident.DriverID),
+		zap.String("from", string(from)),
+		zap.String("status", string(incident.Status)),
+		zap.String("outcome", string(incident.Outcome)),
+		zap.String("severity", string(incident.Severity)),
+		zap.Bool("driverSuspended", incident.DriverSuspended),
+		zap.Bool("identityRevoked", incident.IdentityRevoked),
+	)...)
+	if resolving && incident.Outcome == domain.IncidentOutcomeDismissed && incident.DriverSuspended {
+		uc.liftSuspension(ctx, incident)
+	}
+	return incident, nil
+}
+
+// liftSuspension reinstates the driver of a dismissed incident unless another
+// unresolved incident still holds the driver, or the suspension was since
+// replaced by one for another reason. Failures are logged: the incident is
+// resolved either way and the driver can be reinstated by hand.
+func (uc *incidentUseCase) liftSuspension(ctx context.Context, incident *domain.Incident) {
+	remaining, err := uc.repo.CountUnresolvedSuspensions(ctx, incident.DriverID)
+	if err != nil {
+		uc.logger.Warn("failed to check incident suspensions", zap.Error(err), zap.String("driverId", incident.DriverID))
+		return
+	}
+	if remaining > 0 {
+		return
+	}
+	driver, err := uc.driverRepo.GetByID(ctx, incident.DriverID)
+	if err != nil || !driver.Suspended || driver.SuspendReason != incidentSuspensionReason {
+		return
+	}
+	if _, err := uc.drivers.SetSuspension(ctx, driver.ID, false, ""); err != nil {
+		uc.logger.Warn("failed to reinstate driver after dismissed incident", zap.Error(err),
+			zap.String("id", incident.ID), zap.String("driverId", incident.DriverID))
+	}
+}
+
+// checkIncidentReader rejects riders, who report incidents but do not see them
+func checkIncidentReader(ctx context.Context) error {
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleRider {
+		return ErrIncidentReportOnly
+	}
+	return nil
+}
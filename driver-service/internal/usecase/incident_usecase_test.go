@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockIncidentRepository is an in-memory IncidentRepository
+type mockIncidentRepository struct {
+	incidents []*domain.Incident
+}
+
+func (m *mockIncidentRepository) Create(ctx interface{}, incident *domain.Incident) error {
+	incident.ID = "incident-" + strconv.Itoa(len(m.incidents)+1)
+	copied := *incident
+	m.incidents = append(m.incidents, &copied)
+	return nil
+}
+
+func (m *mockIncidentRepository) find(id string) *domain.Incident {
+	for _, incident := range m.incidents {
+		if incident.ID == id {
+			return incident
+		}
+	}
+	return nil
+}
+
+func (m *mockIncidentRepository) GetByID(ctx interface{}, id string) (*domain.Incident, error) {
+	incident := m.find(id)
+	if incident == nil {
+		return nil, errors.New("incident not found")
+	}
+	copied := *incident
+	return &copied, nil
+}
+
+func (m *mockIncidentRepository) List(ctx interface{}, filter domain.IncidentFilter, page, pageSize int) ([]*domain.Incident, int64, error) {
+	var matched []*domain.Incident
+	for i := len(m.incidents) - 1; i >= 0; i-- {
+		incident := m.incidents[i]
+		if (filter.DriverID == "" || incident.DriverID == filter.DriverID) &&
+			(filter.FleetID == "" || incident.FleetID == filter.FleetID) &&
+			(filter.Severity == "" || incident.Severity == filter.Severity) &&
+			(filter.Status == "" || incident.Status == filter.Status) {
+			copied := *incident
+			matched = append(matched, &copied)
+		}
+	}
+	start := (page - 1) * pageSize
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := start + pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	return matched[start:end], int64(len(matched)), nil
+}
+
+func (m *mockIncidentRepository) Transition(ctx interface{}, incident *domain.Incident, from domain.IncidentStatus) (bool, error) {
+	stored := m.find(incident.ID)
+	if stored == nil || stored.Status != from {
+		return false, nil
+	}
+	*stored = *incident
+	return true, nil
+}
+
+func (m *mockIncidentRepository) CountUnresolvedSuspensions(ctx interface{}, driverID string) (int64, error) {
+	var count int64
+	for _, incident := range m.incidents {
+		if incident.DriverID == driverID && incident.DriverSuspended && incident.Status != domain.IncidentStatusResolved {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func newTestIncidentUseCase() (*incidentUseCase, *mockIncidentRepository, *mockDriverRepository) {
+	driverRepo := newMockDriverRepository()
+	driverRepo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", FleetID: "fleet-1", IdentityVerified: true, Available: true}
+	driverRepo.drivers["driver-2"] = &domain.Driver{ID: "driver-2", FleetID: "fleet-2"}
+	tripRepo := newMockTripRepository()
+	tripRepo.trips["trip-1"] = &domain.Trip{ID: "trip-1", RiderID: "rider-1", DriverID: "driver-1", Status: domain.TripStatusCompleted}
+	repo := &mockIncidentRepository{}
+	uc := NewIncidentUseCase(repo, driverRepo, tripRepo, NewDriverUseCase(driverRepo, zap.NewNop()), zap.NewNop()).(*incidentUseCase)
+	uc.now = func() time.Time { return time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC) }
+	return uc, repo, driverRepo
+}
+
+func TestIncidentUseCase_ReportIncident(t *testing.T) {
+	uc, _, driverRepo := newTestIncidentUseCase()
+	ctx := context.Background()
+	rider := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "rider-1", Role: domain.RoleRider})
+	otherRider := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "rider-2", Role: domain.RoleRider})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		driver  string
+		req     ReportIncidentRequest
+		wantErr error
+	}{
+		{"unknown category", ctx, "driver-1", ReportIncidentRequest{Category: "noise", Severity: "low", Description: "x"}, ErrInvalidIncidentCategory},
+		{"unknown severity", ctx, "driver-1", ReportIncidentRequest{Category: "fare", Severity: "urgent", Description: "x"}, ErrInvalidIncidentSeverity},
+		{"blank description", ctx, "driver-1", ReportIncidentRequest{Category: "fare", Severity: "low", Description: "  "}, ErrInvalidIncidentText},
+		{"rider without trip", rider, "driver-1", ReportIncidentRequest{Category: "fare", Severity: "low", Description: "Overcharged"}, ErrIncidentTripRequired},
+		{"another rider's trip", otherRider, "driver-1", ReportIncidentRequest{Category: "fare", Severity: "low", Description: "Overcharged", TripID: "trip-1"}, ErrTripNotOwned},
+		{"trip of another driver", ctx, "driver-2", ReportIncidentRequest{Category: "fare", Severity: "low", Description: "Overcharged", TripID: "trip-1"}, ErrIncidentTripMismatch},
+		{"unknown trip", ctx, "driver-1", ReportIncidentRequest{Category: "fare", Severity: "low", Description: "Overcharged", TripID: "trip-9"}, ErrTripNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.ReportIncident(tt.ctx, tt.driver, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReportIncident() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	incident, err := uc.ReportIncident(rider, "driver-1", &ReportIncidentRequest{Category: "fare", Severity: "medium", Description: " Overcharged ", TripID: "trip-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if incident.Status != domain.IncidentStatusOpen || incident.Description != "Overcharged" || incident.ReportedBy != "rider-1" || incident.FleetID != "fleet-1" {
+		t.Errorf("unexpected incident: %+v", incident)
+	}
+	if incident.DriverSuspended || driverRepo.drivers["driver-1"].Suspended {
+		t.Error("expected a medium incident to leave the driver alone")
+	}
+
+	incident, err = uc.ReportIncident(ctx, "driver-1", &ReportIncidentRequest{Category: "safety", Severity: "critical", Description: "Drove through red lights"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	driver := driverRepo.drivers["driver-1"]
+	if !incident.DriverSuspended || !incident.IdentityRevoked {
+		t.Errorf("expected the critical incident to record its effects, got %+v", incident)
+	}
+	if !driver.Suspended || driver.SuspendReason != incidentSuspensionReason || driver.Available || driver.IdentityVerified {
+		t.Errorf("expected the driver suspended, off shift and unverified, got %+v", driver)
+	}
+}
+
+func TestIncidentUseCase_UpdateIncidentStatus(t *testing.T) {
+	uc, repo, driverRepo := newTestIncidentUseCase()
+	ctx := context.Background()
+	first, _ := uc.ReportIncident(ctx, "driver-1", &ReportIncidentRequest{Category: "safety", Severity: "high", Description: "Speeding"})
+	second, _ := uc.ReportIncident(ctx, "driver-1", &ReportIncidentRequest{Category: "conduct", Severity: "high", Description: "Shouting"})
+	if !first.DriverSuspended || second.DriverSuspended {
+		t.Fatalf("expected only the first incident to suspend the driver, got %v and %v", first.DriverSuspended, second.DriverSuspended)
+	}
+
+	if _, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "resolved"}); !errors.Is(err, ErrIncidentOutcome) {
+		t.Errorf("expected resolving without an outcome to fail, got %v", err)
+	}
+	if _, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "investigating", Outcome: "upheld"}); !errors.Is(err, ErrIncidentOutcome) {
+		t.Errorf("expected an outcome without resolving to fail, got %v", err)
+	}
+	if _, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "open"}); !errors.Is(err, ErrIncidentTransition) {
+		t.Errorf("expected open to open to fail, got %v", err)
+	}
+	if _, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "investigating", Note: "Calling the rider"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolved, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "resolved", Outcome: "dismissed"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.ResolvedAt == nil || resolved.Note != "Calling the rider" {
+		t.Errorf("unexpected resolved incident: %+v", resolved)
+	}
+	if _, err := uc.UpdateIncidentStatus(ctx, first.ID, &IncidentStatusRequest{Status: "investigating"}); !errors.Is(err, ErrIncidentTransition) {
+		t.Errorf("expected resolved incidents to stay resolved, got %v", err)
+	}
+	if driverRepo.drivers["driver-1"].Suspended {
+		t.Error("expected the dismissed incident to reinstate the driver")
+	}
+
+	// Upheld incidents keep the driver suspended
+	third, _ := uc.ReportIncident(ctx, "driver-1", &ReportIncidentRequest{Category: "safety", Severity: "high", Description: "Speeding again"})
+	if _, err := uc.UpdateIncidentStatus(ctx, third.ID, &IncidentStatusRequest{Status: "resolved", Outcome: "upheld"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !driverRepo.drivers["driver-1"].Suspended {
+		t.Error("expected the upheld incident to keep the driver suspended")
+	}
+	if n, _ := repo.CountUnresolvedSuspensions(ctx, "driver-1"); n != 0 {
+		t.Errorf("CountUnresolvedSuspensions() = %d, want 0", n)
+	}
+}
+
+func TestIncidentUseCase_RiderReportsWaitForConfirmation(t *testing.T) {
+	uc, _, driverRepo := newTestIncidentUseCase()
+	ctx := context.Background()
+	rider := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "rider-1", Role: domain.RoleRider})
+
+	incident, err := uc.ReportIncident(rider, "driver-1", &ReportIncidentRequest{Category: "safety", Severity: "critical", Description: "Drove through red lights", TripID: "trip-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	driver := driverRepo.drivers["driver-1"]
+	if incident.Status != domain.IncidentStatusOpen || incident.DriverSuspended || incident.IdentityRevoked {
+		t.Errorf("expected the rider's report stored open without effects, got %+v", incident)
+	}
+	if driver.Suspended || !driver.IdentityVerified {
+		t.Fatalf("expected a rider's report to leave the driver alone, got %+v", driver)
+	}
+
+	if _, err := uc.UpdateIncidentStatus(ctx, incident.ID, &IncidentStatusRequest{Status: "resolved", Outcome: "upheld", Severity: "high"}); !errors.Is(err, ErrIncidentSeverityConfirm) {
+		t.Errorf("expected confirming a severity while resolving to fail, got %v", err)
+	}
+	if _, err := uc.UpdateIncidentStatus(ctx, incident.ID, &IncidentStatusRequest{Status: "investigating", Severity: "urgent"}); !errors.Is(err, ErrInvalidIncidentSeverity) {
+		t.Errorf("expected an unknown severity to fail, got %v", err)
+	}
+
+	// The investigator downgrades it to high: suspended, still verified
+	confirmed, err := uc.UpdateIncidentStatus(ctx, incident.ID, &IncidentStatusRequest{Status: "investigating", Severity: "high"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if confirmed.Severity != domain.IncidentSeverityHigh || !confirmed.DriverSuspended || confirmed.IdentityRevoked {
+		t.Errorf("expected the confirmed incident to record the suspension, got %+v", confirmed)
+	}
+	if !driver.Suspended || driver.SuspendReason != incidentSuspensionReason || !driver.IdentityVerified {
+		t.Errorf("expected the driver suspended and still verified, got %+v", driver)
+	}
+
+	if _, err := uc.UpdateIncidentStatus(ctx, incident.ID, &IncidentStatusRequest{Status: "resolved", Outcome: "dismissed"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if driver.Suspended {
+		t.Error("expected dismissing the confirmed incident to reinstate the driver")
+	}
+}
+
+func TestIncidentUseCase_ListIncidents(t *testing.T) {
+	uc, _, _ := newTestIncidentUseCase()
+	ctx := context.Background()
+	uc.ReportIncident(ctx, "driver-1", &ReportIncidentRequest{Category: "fare", Severity: "low", Description: "Overcharged"})
+	uc.ReportIncident(ctx, "driver-2", &ReportIncidentRequest{Category: "route", Severity: "medium", Description: "Long detour"})
+
+	fleetAdmin := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "admin-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-2"})
+	list, err := uc.ListIncidents(fleetAdmin, domain.IncidentFilter{}, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list.TotalCount != 1 || list.Incidents[0].DriverID != "driver-2" || list.PageSize != defaultIncidentPageSize {
+		t.Errorf("expected the fleet admin to see their fleet's incident, got %+v", list)
+	}
+	if _, err := uc.ListIncidents(fleetAdmin, domain.IncidentFilter{DriverID: "driver-1"}, 1, 20); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected another fleet's driver to be refused, got %v", err)
+	}
+	if _, err := uc.GetIncident(fleetAdmin, "incident-1"); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected another fleet's incident to be refused, got %v", err)
+	}
+
+	rider := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "rider-1", Role: domain.RoleRider})
+	if _, err := uc.ListIncidents(rider, domain.IncidentFilter{}, 1, 20); !errors.Is(err, ErrIncidentReportOnly) {
+		t.Errorf("expected riders to be refused, got %v", err)
+	}
+	if _, err := uc.ListIncidents(ctx, domain.IncidentFilter{Status: "closed"}, 1, 20); !errors.Is(err, ErrInvalidIncidentStatus) {
+		t.Errorf("expected an unknown status filter to fail, got %v", err)
+	}
+
+	list, _ = uc.ListIncidents(ctx, domain.IncidentFilter{Severity: domain.IncidentSeverityLow}, 1, 20)
+	if list.TotalCount != 1 || list.Incidents[0].DriverID != "driver-1" {
+		t.Errorf("expected the severity filter to apply, got %+v", list)
+	}
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// IndexUseCase defines the interface for inspecting and rebuilding MongoDB indexes
+type IndexUseCase interface {
+	Report(ctx context.Context) (*domain.IndexReport, error)
+	StartSync(ctx context.Context, actor string) (*domain.IndexSyncJob, error)
+	SyncStatus() (*domain.IndexSyncJob, error)
+}
+
+// indexUseCase implements IndexUseCase. Only the latest sync job is kept and
+// at most one runs at a time.
+type indexUseCase struct {
+	catalog domain.IndexCatalog
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu  sync.Mutex
+	job *domain.IndexSyncJob
+}
+
+// NewIndexUseCase creates a new index use case
+func NewIndexUseCase(catalog domain.IndexCatalog, logger *zap.Logger) IndexUseCase {
+	return &indexUseCase{
+		catalog: catalog,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Report compares the expected indexes with the existing ones
+func (uc *indexUseCase) Report(ctx context.Context) (*domain.IndexReport, error) {
+	return uc.catalog.Report(ctx)
+}
+
+// StartSync creates the missing indexes in the background and returns the job
+// tracking its progress. Mismatched indexes are skipped because fixing them
+// needs a drop that could hurt a live service.
+func (uc *indexUseCase) StartSync(ctx context.Context, actor string) (*domain.IndexSyncJob, error) {
+	uc.mu.Lock()
+	if uc.job != nil && uc.job.Status == domain.IndexSyncRunning {
+		uc.mu.Unlock()
+		return nil, ErrIndexSyncRunning
+	}
+	uc.mu.Unlock()
+
+	report, err := uc.catalog.Report(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	started := uc.now().UTC()
+	job := &domain.IndexSyncJob{
+		ID:        fmt.Sprintf("idxsync-%d", started.UnixMilli()),
+		Status:    domain.IndexSyncRunning,
+		Actor:     actor,
+		StartedAt: started,
+		Created:   []string{},
+		Failed:    []domain.IndexSyncError{},
+		Skipped:   []string{},
+	}
+	var missing []domain.IndexStatus
+	for _, index := range report.Indexes {
+		switch index.State {
+		case domain.IndexMissing:
+			missing = append(missing, index)
+		case domain.IndexMismatch:
+			job.Skipped = append(job.Skipped, index.Collection+"."+index.Name)
+		}
+	}
+	job.Total = len(missing)
+
+	uc.mu.Lock()
+	if uc.job != nil && uc.job.Status == domain.IndexSyncRunning {
+		uc.mu.Unlock()
+		return nil, ErrIndexSyncRunning
+	}
+	uc.job = job
+	snapshot := copyIndexSyncJob(job)
+	uc.mu.Unlock()
+
+	uc.logger.Info("audit: index sync started",
+		zap.String("jobId", job.ID),
+		zap.String("actor", actor),
+		zap.Int("missing", len(missing)),
+		zap.Strings("skipped", job.Skipped),
+	)
+
+	// The sync outlives the request that started it
+	go uc.runSync(job, missing)
+
+	return snapshot, nil
+}
+
+// SyncStatus returns the progress of the latest sync job
+func (uc *indexUseCase) SyncStatus() (*domain.IndexSyncJob, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.job == nil {
+		return nil, ErrIndexSyncNotFound
+	}
+	return copyIndexSyncJob(uc.job), nil
+}
+
+func (uc *indexUseCase) runSync(job *domain.IndexSyncJob, missing []domain.IndexStatus) {
+	for _, index := range missing {
+		name := index.Collection + "." + index.Name
+		uc.mu.Lock()
+		job.Current = name
+		uc.mu.Unlock()
+
+		start := uc.now()
+		err := uc.catalog.CreateIndex(context.Background(), index.Collection, index.Name)
+
+		uc.mu.Lock()
+		job.Completed++
+		if err != nil {
+			job.Failed = append(job.Failed, domain.IndexSyncError{Collection: index.Collection, Name: index.Name, Error: err.Error()})
+		} else {
+			job.Created = append(job.Created, name)
+		}
+		uc.mu.Unlock()
+
+		if err != nil {
+			uc.logger.Error("audit: index creation failed", zap.String("jobId", job.ID), zap.String("index", name), zap.Error(err))
+			continue
+		}
+		uc.logger.Info("audit: index created",
+			zap.String("jobId", job.ID),
+			zap.String("index", name),
+			zap.Duration("took", uc.now().Sub(start)),
+		)
+	}
+
+	uc.mu.Lock()
+	finished := uc.now().UTC()
+	job.FinishedAt = &finished
+	job.Current = ""
+	job.Status = domain.IndexSyncCompleted
+	if len(job.Failed) > 0 {
+		job.Status = domain.IndexSyncFailed
+	}
+	uc.mu.Unlock()
+
+	uc.logger.Info("audit: index sync finished",
+		zap.String("jobId", job.ID),
+		zap.String("actor", job.Actor),
+		zap.String("status", job.Status),
+		zap.Int("created", len(job.Created)),
+		zap.Int("failed", len(job.Failed)),
+	)
+}
+
+// copyIndexSyncJob copies a job so callers never read it while the sync updates it
+func copyIndexSyncJob(job *domain.IndexSyncJob) *domain.IndexSyncJob {
+	c := *job
+	c.Created = append([]string{}, job.Created...)
+	c.Failed = append([]domain.IndexSyncError{}, job.Failed...)
+	c.Skipped = append([]string{}, job.Skipped...)
+	if job.FinishedAt != nil {
+		finished := *job.FinishedAt
+		c.FinishedAt = &finished
+	}
+	return &c
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockIndexCatalog reports a fixed set of indexes and blocks CreateIndex until released
+type mockIndexCatalog struct {
+	report  *domain.IndexReport
+	release chan struct{}
+	fail    map[string]bool
+}
+
+func (m *mockIndexCatalog) Report(ctx interface{}) (*domain.IndexReport, error) {
+	return m.report, nil
+}
+
+func (m *mockIndexCatalog) CreateIndex(ctx interface{}, collection, name string) error {
+	<-m.release
+	if m.fail[name] {
+		return errors.New("E11000 duplicate key error")
+	}
+	return nil
+}
+
+func waitForSync(t *testing.T, uc IndexUseCase) *domain.IndexSyncJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := uc.SyncStatus()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if job.Status != domain.IndexSyncRunning {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("index sync did not finish")
+	return nil
+}
+
+func TestIndexUseCase_Sync(t *testing.T) {
+	catalog := &mockIndexCatalog{
+		report: &domain.IndexReport{Indexes: []domain.IndexStatus{
+			{Collection: "drivers", Name: "phone_unique", State: domain.IndexPresent},
+			{Collection: "drivers", Name: "fleetId_createdAt", State: domain.IndexMissing},
+			{Collection: "trips", Name: "status_offerExpiresAt", State: domain.IndexMissing},
+			{Collection: "fleets", Name: "name_unique", State: domain.IndexMismatch},
+			{Collection: "fleets", Name: "legacy", State: domain.IndexUnexpected},
+		}},
+		release: make(chan struct{}),
+		fail:    map[string]bool{"status_offerExpiresAt": true},
+	}
+	uc := NewIndexUseCase(catalog, zap.NewNop())
+
+	if _, err := uc.SyncStatus(); !errors.Is(err, ErrIndexSyncNotFound) {
+		t.Fatalf("expected ErrIndexSyncNotFound, got %v", err)
+	}
+
+	job, err := uc.StartSync(context.Background(), "ops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Status != domain.IndexSyncRunning || job.Total != 2 || job.Actor != "ops" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if len(job.Skipped) != 1 || job.Skipped[0] != "fleets.name_unique" {
+		t.Errorf("expected the mismatched index to be skipped, got %v", job.Skipped)
+	}
+
+	if _, err := uc.StartSync(context.Background(), "ops"); !errors.Is(err, ErrIndexSyncRunning) {
+		t.Errorf("expected ErrIndexSyncRunning, got %v", err)
+	}
+
+	close(catalog.release)
+	job = waitForSync(t, uc)
+	if job.Status != domain.IndexSyncFailed {
+		t.Errorf("expected failed status, got %s", job.Status)
+	}
+	if job.Completed != 2 || job.Current != "" || job.FinishedAt == nil {
+		t.Errorf("unexpected progress: %+v", job)
+	}
+	if len(job.Created) != 1 || job.Created[0] != "drivers.fleetId_createdAt" {
+		t.Errorf("unexpected created indexes: %v", job.Created)
+	}
+	if len(job.Failed) != 1 || job.Failed[0].Name != "status_offerExpiresAt" {
+		t.Errorf("unexpected failures: %v", job.Failed)
+	}
+
+	// A finished sync can be started again
+	if _, err := uc.StartSync(context.Background(), "ops"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	waitForSync(t, uc)
+}
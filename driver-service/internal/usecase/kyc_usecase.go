@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/kyc"
+	"go.uber.org/zap"
+)
+
+// kycPollBatch bounds the pending checks polled at once
+const kycPollBatch = 100
+
+// KYCUseCase defines the interface for driver identity verification
+type KYCUseCase interface {
+	SubmitIdentity(ctx context.Context, driverID string) (*domain.KYCCheck, error)
+	GetIdentityCheck(ctx context.Context, driverID string) (*domain.KYCCheck, error)
+	// HandleWebhook applies a decision the provider sent to the inbound webhook
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) (*domain.KYCCheck, error)
+	// PollPending asks the provider about pending checks and returns how many were decided
+	PollPending(ctx context.Context) (int, error)
+}
+
+// KYCOptions holds the identity verification settings
+type KYCOptions struct {
+	// WebhookSecret signs the provider's webhook calls; webhooks are refused without it
+	WebhookSecret string
+	// Blacklist is checked again before the identity is submitted, since the
+	// driver's details may have changed since they were created
+	Blacklist BlacklistCheck
+}
+
+// kycUseCase implements KYCUseCase
+type kycUseCase struct {
+	driverRepo domain.DriverRepository
+	kycRepo    domain.KYCRepository
+	provider   kyc.Provider
+	opts       KYCOptions
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewKYCUseCase creates a new identity verification use case
+func NewKYCUseCase(driverRepo domain.DriverRepository, kycRepo domain.KYCRepository, provider kyc.Provider, opts KYCOptions, logger *zap.Logger) KYCUseCase {
+	return &kycUseCase{
+		driverRepo: driverRepo,
+		kycRepo:    kycRepo,
+		provider:   provider,
+		opts:       opts,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// SubmitIdentity sends the driver's name and documents to the provider and
+// records a pending check. A license document is required.
+func (uc *kycUseCase) SubmitIdentity(ctx context.Context, driverID string) (*domain.KYCCheck, error) {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+	if driver.IdentityVerified {
+		return nil, ErrIdentityAlreadyVerified
+	}
+	if latest, err := uc.kycRepo.GetLatest(ctx, driverID); err == nil && latest.Status == domain.KYCStatusPending {
+		return nil, ErrKYCCheckPending
+	}
+	hasLicense := false
+	for _, doc := range driver.Documents {
+		if doc.Type == domain.DocumentTypeLicense {
+			hasLicense = true
+		}
+	}
+	if !hasLicense {
+		return nil, ErrIdentityDocumentsMissing
+	}
+	if err := uc.opts.Blacklist.run(ctx, uc.logger, driver); err != nil {
+		return nil, err
+	}
+
+	reference, err := uc.provider.Submit(ctx, &kyc.Submission{
+		DriverID:  driver.ID,
+		FirstName: driver.FirstName,
+		LastName:  driver.LastName,
+		Documents: driver.Documents,
+	})
+	if err != nil {
+		uc.logger.Error("failed to submit identity check", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to submit identity check")
+	}
+
+	check := &domain.KYCCheck{
+		DriverID:    driverID,
+		Provider:    uc.provider.Name(),
+		Reference:   reference,
+		Status:      domain.KYCStatusPending,
+		SubmittedAt: uc.now().UTC(),
+	}
+	if err := uc.kycRepo.Create(ctx, check); err != nil {
+		uc.logger.Error("failed to save identity check", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to submit identity check")
+	}
+
+	uc.logger.Info("identity check submitted", append(actorFields(ctx),
+		zap.String("driverId", driverID), zap.String("provider", check.Provider), zap.String("reference", reference))...)
+	return check, nil
+}
+
+// GetIdentityCheck returns the driver's latest identity check
+func (uc *kycUseCase) GetIdentityCheck(ctx context.Context, driverID string) (*domain.KYCCheck, error) {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	check, err := uc.kycRepo.GetLatest(ctx, driverID)
+	if err != nil {
+		if err.Error() == "kyc check not found" {
+			return nil, ErrKYCCheckNotFound
+		}
+		return nil, err
+	}
+	return check, nil
+}
+
+// HandleWebhook verifies the webhook signature and applies the decision
+func (uc *kycUseCase) HandleWebhook(ctx context.Context, header http.Header, body []byte) (*domain.KYCCheck, error) {
+	decision, err := kyc.ParseWebhook(uc.opts.WebhookSecret, header, body, uc.now())
+	if err != nil {
+		if errors.Is(err, kyc.ErrInvalidSignature) {
+			uc.logger.Warn("kyc webhook with invalid signature rejected")
+			return nil, ErrInvalidKYCSignature
+		}
+		if errors.Is(err, kyc.ErrInvalidWebhookBody) {
+			return nil, ErrInvalidKYCWebhookBody
+		}
+		return nil, err
+	}
+
+	check, err := uc.kycRepo.GetByReference(ctx, uc.provider.Name(), decision.Reference)
+	if err != nil {
+		if err.Error() == "kyc check not found" {
+			return nil, ErrKYCCheckNotFound
+		}
+		return nil, err
+	}
+	if err := uc.apply(ctx, check, decision); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// PollPending asks the provider about every pending check
+func (uc *kycUseCase) PollPending(ctx context.Context) (int, error) {
+	pending, err := uc.kycRepo.ListPending(ctx, uc.now(), kycPollBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	decided := 0
+	for _, check := range pending {
+		// Checks submitted to a provider no longer configured are left alone
+		if check.Provider != uc.provider.Name() {
+			continue
+		}
+		decision, err := uc.provider.Check(ctx, check.Reference)
+		if err != nil {
+			uc.logger.Warn("failed to poll identity check", zap.Error(err), zap.String("id", check.ID))
+			continue
+		}
+		if decision.Status == domain.KYCStatusPending {
+			continue
+		}
+		if err := uc.apply(ctx, check, decision); err != nil {
+			uc.logger.Warn("failed to apply identity check decision", zap.Error(err), zap.String("id", check.ID))
+			continue
+		}
+		decided++
+	}
+	return decided, nil
+}
+
+// apply records a decision and updates the driver when the identity was
+// approved. Repeated decisions, e.g. a webhook after a poll, are ignored.
+func (uc *kycUseCase) apply(ctx context.Context, check *domain.KYCCheck, decision *kyc.Decision) error {
+	if decision.Status == domain.KYCStatusPending {
+		return nil
+	}
+	now := uc.now().UTC()
+	changed, err := uc.kycRepo.Decide(ctx, check.ID, decision.Status, decision.Reason, now)
+	if err != nil {
+		return fmt.Errorf("failed to record identity check decision: %w", err)
+	}
+	if !changed {
+		return nil
+	}
+	check.Status, check.Reason, check.DecidedAt = decision.Status, decision.Reason, &now
+
+	if decision.Status == domain.KYCStatusApproved {
+		driver, err := uc.driverRepo.GetByID(ctx, check.DriverID)
+		if err != nil {
+			return fmt.Errorf("failed to load verified driver: %w", err)
+		}
+		driver.IdentityVerified = true
+		if err := uc.driverRepo.Update(ctx, driver.ID, driver); err != nil {
+			return fmt.Errorf("failed to mark identity verified: %w", err)
+		}
+	}
+
+	uc.logger.Info("identity check decided",
+		zap.String("driverId", check.DriverID), zap.String("status", string(decision.Status)), zap.String("reason", decision.Reason))
+	return nil
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/kyc"
+	"github.com/bitaksi/driver-service/internal/webhook"
+	"go.uber.org/zap"
+)
+
+// mockKYCRepository is an in-memory KYCRepository
+type mockKYCRepository struct {
+	checks []*domain.KYCCheck
+}
+
+func (m *mockKYCRepository) Create(ctx interface{}, check *domain.KYCCheck) error {
+	check.ID = check.Reference
+	copied := *check
+	m.checks = append(m.checks, &copied)
+	return nil
+}
+
+func (m *mockKYCRepository) GetLatest(ctx interface{}, driverID string) (*domain.KYCCheck, error) {
+	for i := len(m.checks) - 1; i >= 0; i-- {
+		if m.checks[i].DriverID == driverID {
+			copied := *m.checks[i]
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("kyc check not found")
+}
+
+func (m *mockKYCRepository) GetByReference(ctx interface{}, provider, reference string) (*domain.KYCCheck, error) {
+	for _, check := range m.checks {
+		if check.Provider == provider && check.Reference == reference {
+			copied := *check
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("kyc check not found")
+}
+
+func (m *mockKYCRepository) ListPending(ctx interface{}, submittedUntil time.Time, limit int) ([]*domain.KYCCheck, error) {
+	var pending []*domain.KYCCheck
+	for _, check := range m.checks {
+		if check.Status == domain.KYCStatusPending && !check.SubmittedAt.After(submittedUntil) {
+			copied := *check
+			pending = append(pending, &copied)
+		}
+	}
+	return pending, nil
+}
+
+func (m *mockKYCRepository) Decide(ctx interface{}, id string, status domain.KYCStatus, reason string, at time.Time) (bool, error) {
+	for _, check := range m.checks {
+		if check.ID == id && check.Status == domain.KYCStatusPending {
+			check.Status, check.Reason, check.DecidedAt = status, reason, &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestKYCUseCase(t *testing.T) (*kycUseCase, *mockDriverRepository, *mockKYCRepository) {
+	t.Helper()
+	driverRepo := newMockDriverRepository()
+	kycRepo := &mockKYCRepository{}
+	uc := NewKYCUseCase(driverRepo, kycRepo, kyc.NewSandboxProvider(), KYCOptions{WebhookSecret: "kyc-webhook-secret"}, zap.NewNop()).(*kycUseCase)
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+	return uc, driverRepo, kycRepo
+}
+
+func licensedDriver(id, lastName, number string) *domain.Driver {
+	return &domain.Driver{
+		ID: id, FirstName: "Ahmet", LastName: lastName, Plate: "34ABC" + id,
+		Documents: []domain.DriverDocument{{Type: domain.DocumentTypeLicense, Number: number, URL: "https://files.example.com/l.pdf"}},
+	}
+}
+
+func TestKYCUseCase_SubmitAndPoll(t *testing.T) {
+	uc, driverRepo, _ := newTestKYCUseCase(t)
+	ctx := context.Background()
+	driverRepo.drivers["d1"] = licensedDriver("d1", "Demir", "TR-1")
+	driverRepo.drivers["d2"] = licensedDriver("d2", "Demir", "REJECT-1")
+	driverRepo.drivers["d3"] = &domain.Driver{ID: "d3", FirstName: "Ali", LastName: "Kaya"}
+
+	if _, err := uc.SubmitIdentity(ctx, "d3"); !errors.Is(err, ErrIdentityDocumentsMissing) {
+		t.Fatalf("submit without license: err = %v, want ErrIdentityDocumentsMissing", err)
+	}
+	for _, id := range []string{"d1", "d2"} {
+		check, err := uc.SubmitIdentity(ctx, id)
+		if err != nil {
+			t.Fatalf("SubmitIdentity(%s): %v", id, err)
+		}
+		if check.Status != domain.KYCStatusPending || check.Provider != kyc.ProviderSandbox {
+			t.Errorf("new check = %+v, want pending sandbox check", check)
+		}
+	}
+	if _, err := uc.SubmitIdentity(ctx, "d1"); !errors.Is(err, ErrKYCCheckPending) {
+		t.Errorf("second submit: err = %v, want ErrKYCCheckPending", err)
+	}
+
+	decided, err := uc.PollPending(ctx)
+	if err != nil || decided != 2 {
+		t.Fatalf("PollPending = %d, %v; want 2 decided", decided, err)
+	}
+	if !driverRepo.drivers["d1"].IdentityVerified {
+		t.Error("approved driver was not marked identity verified")
+	}
+	if driverRepo.drivers["d2"].IdentityVerified {
+		t.Error("rejected driver was marked identity verified")
+	}
+	check, err := uc.GetIdentityCheck(ctx, "d2")
+	if err != nil || check.Status != domain.KYCStatusRejected || check.Reason == "" {
+		t.Errorf("GetIdentityCheck(d2) = %+v, %v; want rejected with a reason", check, err)
+	}
+	if _, err := uc.SubmitIdentity(ctx, "d1"); !errors.Is(err, ErrIdentityAlreadyVerified) {
+		t.Errorf("submit after approval: err = %v, want ErrIdentityAlreadyVerified", err)
+	}
+}
+
+func TestKYCUseCase_HandleWebhook(t *testing.T) {
+	uc, driverRepo, _ := newTestKYCUseCase(t)
+	ctx := context.Background()
+	driverRepo.drivers["d1"] = licensedDriver("d1", "Demir", "PENDING-1")
+
+	check, err := uc.SubmitIdentity(ctx, "d1")
+	if err != nil {
+		t.Fatalf("SubmitIdentity: %v", err)
+	}
+	if decided, _ := uc.PollPending(ctx); decided != 0 {
+		t.Fatalf("a check the provider has not decided was decided")
+	}
+
+	body := []byte(`{"reference":"` + check.Reference + `","status":"approved"}`)
+	signed := http.Header{}
+	signed.Set(kyc.SignatureHeader, webhook.Sign("kyc-webhook-secret", uc.now(), body))
+
+	forged := http.Header{}
+	forged.Set(kyc.SignatureHeader, webhook.Sign("wrong-secret", uc.now(), body))
+	if _, err := uc.HandleWebhook(ctx, forged, body); !errors.Is(err, ErrInvalidKYCSignature) {
+		t.Fatalf("forged webhook: err = %v, want ErrInvalidKYCSignature", err)
+	}
+
+	decided, err := uc.HandleWebhook(ctx, signed, body)
+	if err != nil || decided.Status != domain.KYCStatusApproved {
+		t.Fatalf("HandleWebhook = %+v, %v; want approved", decided, err)
+	}
+	if !driverRepo.drivers["d1"].IdentityVerified {
+		t.Error("driver was not marked identity verified")
+	}
+
+	// A repeated call changes nothing
+	if _, err := uc.HandleWebhook(ctx, signed, body); err != nil {
+		t.Errorf("repeated webhook: %v", err)
+	}
+
+	unknown := []byte(`{"reference":"sbx_unknown","status":"approved"}`)
+	signed.Set(kyc.SignatureHeader, webhook.Sign("kyc-webhook-secret", uc.now(), unknown))
+	if _, err := uc.HandleWebhook(ctx, signed, unknown); !errors.Is(err, ErrKYCCheckNotFound) {
+		t.Errorf("unknown reference: err = %v, want ErrKYCCheckNotFound", err)
+	}
+}
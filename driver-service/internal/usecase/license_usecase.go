@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Expiry report windows, in days
+const (
+	defaultLicenseWindowDays = 30
+	maxLicenseWindowDays     = 365
+)
+
+var licenseNumberPattern = regexp.MustCompile(`^[A-Z0-9-]{5,20}$`)
+
+// LicenseUseCase defines the interface for tracking driver licence expiry
+type LicenseUseCase interface {
+	ExpiringLicenses(ctx context.Context, days int, filter domain.DriverFilter) (*ExpiringLicensesResponse, error)
+	FlagExpiredLicenses(ctx context.Context) (int, error)
+}
+
+// ExpiringLicense is a driver whose licence expires within the report window
+type ExpiringLicense struct {
+	DriverID  string               `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	FirstName string               `json:"firstName" example:"Ahmet"`
+	LastName  string               `json:"lastName" example:"Demir"`
+	Plate     string               `json:"plate" example:"34ABC123"`
+	FleetID   string               `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	License   domain.DriverLicense `json:"license"`
+	// DaysLeft is negative once the licence has expired
+	DaysLeft int  `json:"daysLeft" example:"12"`
+	Expired  bool `json:"expired" example:"false"`
+	// Flagged drivers have been taken off dispatch by the licence check
+	Flagged bool `json:"flagged" example:"false"`
+}
+
+// ExpiringLicensesResponse lists licences expiring within a number of days, soonest first
+type ExpiringLicensesResponse struct {
+	Days     int               `json:"days" example:"30"`
+	AsOf     time.Time         `json:"asOf" example:"2025-12-06T01:00:00Z"`
+	Count    int               `json:"count" example:"1"`
+	Licenses []ExpiringLicense `json:"licenses"`
+}
+
+// licenseUseCase implements LicenseUseCase
+type licenseUseCase struct {
+	repo     domain.LicenseRepository
+	activity domain.ActivityRepository
+	events   domain.DriverEventPublisher
+	logger   *zap.Logger
+	now      func() time.Time
+}
+
+// NewLicenseUseCase creates a new licence use case. Flagged drivers have their
+// shift closed in activity and are announced to events; either may be nil.
+func NewLicenseUseCase(repo domain.LicenseRepository, activity domain.ActivityRepository, events domain.DriverEventPublisher, logger *zap.Logger) LicenseUseCase {
+	return &licenseUseCase{
+		repo:     repo,
+		activity: activity,
+		events:   events,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// ExpiringLicenses lists the drivers whose licence expires within days, already expired ones included
+func (uc *licenseUseCase) ExpiringLicenses(ctx context.Context, days int, filter domain.DriverFilter) (*ExpiringLicensesResponse, error) {
+	if days == 0 {
+		days = defaultLicenseWindowDays
+	}
+	if days < 0 || days > maxLicenseWindowDays {
+		return nil, ErrInvalidLicenseWindow
+	}
+
+	now := uc.now().UTC()
+	drivers, err := uc.repo.ListExpiringLicenses(ctx, filter, now.AddDate(0, 0, days))
+	if err != nil {
+		uc.logger.Error("failed to list expiring licenses", zap.Error(err))
+		return nil, errors.New("failed to list expiring licenses")
+	}
+
+	licenses := make([]ExpiringLicense, 0, len(drivers))
+	for _, driver := range drivers {
+		if driver.License == nil {
+			continue
+		}
+		licenses = append(licenses, ExpiringLicense{
+			DriverID:  driver.ID,
+			FirstName: driver.FirstName,
+			LastName:  driver.LastName,
+			Plate:     driver.Plate,
+			FleetID:   driver.FleetID,
+			License:   *driver.License,
+			DaysLeft:  int(math.Floor(driver.License.ExpiresAt.Sub(now).Hours() / 24)),
+			Expired:   driver.License.Expired(now),
+			Flagged:   driver.LicenseExpired,
+		})
+	}
+
+	return &ExpiringLicensesResponse{
+		Days:     days,
+		AsOf:     now,
+		Count:    len(licenses),
+		Licenses: licenses,
+	}, nil
+}
+
+// FlagExpiredLicenses takes drivers whose licence expired off dispatch and
+// alerts webhook subscribers; it returns how many drivers were flagged
+func (uc *licenseUseCase) FlagExpiredLicenses(ctx context.Context) (int, error) {
+	now := uc.now().UTC()
+	drivers, err := uc.repo.FlagExpiredLicenses(ctx, now)
+	if err != nil {
+		uc.logger.Error("failed to flag expired licenses", zap.Error(err))
+		return 0, errors.New("failed to flag expired licenses")
+	}
+
+	for _, driver := range drivers {
+		if driver.Available && uc.activity != nil {
+			if err := uc.activity.EndShift(ctx, driver.ID, now); err != nil {
+				uc.logger.Warn("failed to end shift", zap.Error(err), zap.String("id", driver.ID))
+			}
+		}
+		driver.Available = false
+		driver.LicenseExpired = true
+		uc.logger.Info("driver license expired, taken off dispatch", zap.String("id", driver.ID), zap.Time("expiresAt", driver.License.ExpiresAt))
+		if uc.events != nil {
+			uc.events.PublishDriverEvent(ctx, domain.EventDriverLicenseExpired, driver, "license expired")
+		}
+	}
+	return len(drivers), nil
+}
+
+// normalizeLicense validates a licence and returns it with number and class in canonical form
+func normalizeLicense(license *domain.DriverLicense, now time.Time) (*domain.DriverLicense, error) {
+	normalized := &domain.DriverLicense{
+		Number:    strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(license.Number), " ", "")),
+		Class:     domain.LicenseClass(strings.ToUpper(strings.TrimSpace(string(license.Class)))),
+		ExpiresAt: license.ExpiresAt.UTC(),
+	}
+	if !licenseNumberPattern.MatchString(normalized.Number) {
+		return nil, fmt.Errorf("%w: number must be 5-20 letters, digits or dashes", ErrInvalidLicense)
+	}
+	if !normalized.Class.IsValid() {
+		return nil, fmt.Errorf("%w: class %q does not allow driving a taxi", ErrInvalidLicense, license.Class)
+	}
+	if normalized.ExpiresAt.IsZero() {
+		return nil, fmt.Errorf("%w: expiresAt is required", ErrInvalidLicense)
+	}
+	if normalized.Expired(now) {
+		return nil, ErrLicenseExpired
+	}
+	return normalized, nil
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockLicenseRepository serves licence queries from drivers kept in memory
+type mockLicenseRepository struct {
+	drivers    []*domain.Driver
+	shouldFail bool
+}
+
+func (m *mockLicenseRepository) ListExpiringLicenses(ctx interface{}, filter domain.DriverFilter, before time.Time) ([]*domain.Driver, error) {
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	var drivers []*domain.Driver
+	for _, driver := range m.drivers {
+		if driver.License == nil || !driver.License.ExpiresAt.Before(before) {
+			continue
+		}
+		if filter.FleetID != "" && driver.FleetID != filter.FleetID {
+			continue
+		}
+		drivers = append(drivers, driver)
+	}
+	return drivers, nil
+}
+
+func (m *mockLicenseRepository) FlagExpiredLicenses(ctx interface{}, now time.Time) ([]*domain.Driver, error) {
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	var flagged []*domain.Driver
+	for _, driver := range m.drivers {
+		if driver.License == nil || driver.LicenseExpired || !driver.License.Expired(now) {
+			continue
+		}
+		previous := *driver
+		driver.LicenseExpired, driver.Available = true, false
+		flagged = append(flagged, &previous)
+	}
+	return flagged, nil
+}
+
+func TestLicenseUseCase_ExpiringLicenses(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	license := func(days int) *domain.DriverLicense {
+		return &domain.DriverLicense{Number: "TR-1234567", Class: "B", ExpiresAt: now.AddDate(0, 0, days)}
+	}
+	repo := &mockLicenseRepository{drivers: []*domain.Driver{
+		{ID: "expired", License: license(-2), LicenseExpired: true},
+		{ID: "soon", License: license(10), FleetID: "fleet-1"},
+		{ID: "later", License: license(90)},
+		{ID: "none"},
+	}}
+	uc := NewLicenseUseCase(repo, nil, nil, zap.NewNop()).(*licenseUseCase)
+	uc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	report, err := uc.ExpiringLicenses(ctx, 0, domain.DriverFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Days != 30 || report.Count != 2 {
+		t.Fatalf("expected 2 licenses within the default 30 days, got %d within %d", report.Count, report.Days)
+	}
+	if got := report.Licenses[0]; got.DriverID != "expired" || !got.Expired || !got.Flagged || got.DaysLeft != -2 {
+		t.Errorf("expected the expired license first, got %+v", got)
+	}
+	if got := report.Licenses[1]; got.DriverID != "soon" || got.Expired || got.DaysLeft != 10 {
+		t.Errorf("expected the license expiring in 10 days, got %+v", got)
+	}
+
+	report, err = uc.ExpiringLicenses(ctx, 365, domain.DriverFilter{FleetID: "fleet-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Count != 1 || report.Licenses[0].DriverID != "soon" {
+		t.Errorf("expected only the fleet's driver, got %+v", report.Licenses)
+	}
+
+	for _, days := range []int{-1, 366} {
+		if _, err := uc.ExpiringLicenses(ctx, days, domain.DriverFilter{}); !errors.Is(err, ErrInvalidLicenseWindow) {
+			t.Errorf("days=%d: expected ErrInvalidLicenseWindow, got %v", days, err)
+		}
+	}
+
+	repo.shouldFail = true
+	if _, err := uc.ExpiringLicenses(ctx, 30, domain.DriverFilter{}); err == nil {
+		t.Error("expected an error when the repository fails")
+	}
+}
+
+func TestLicenseUseCase_FlagExpiredLicenses(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	repo := &mockLicenseRepository{drivers: []*domain.Driver{
+		{ID: "on-shift", Available: true, License: &domain.DriverLicense{Number: "TR-1234567", Class: "B", ExpiresAt: now.Add(-time.Hour)}},
+		{ID: "off-shift", License: &domain.DriverLicense{Number: "TR-7654321", Class: "B", ExpiresAt: now}},
+		{ID: "valid", Available: true, License: &domain.DriverLicense{Number: "TR-1111111", Class: "B", ExpiresAt: now.Add(time.Hour)}},
+	}}
+	activity := newMockActivityRepository()
+	activity.openShifts["on-shift"] = true
+	activity.openShifts["valid"] = true
+	events := &recordingPublisher{}
+	uc := NewLicenseUseCase(repo, activity, events, zap.NewNop()).(*licenseUseCase)
+	uc.now = func() time.Time { return now }
+
+	flagged, err := uc.FlagExpiredLicenses(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagged != 2 {
+		t.Errorf("expected 2 drivers flagged, got %d", flagged)
+	}
+	if activity.openShifts["on-shift"] || !activity.openShifts["valid"] {
+		t.Errorf("expected only the expired driver's shift to end, open shifts: %v", activity.openShifts)
+	}
+	if len(events.events) != 2 || events.events[0] != domain.EventDriverLicenseExpired {
+		t.Errorf("expected two license expired events, got %v", events.events)
+	}
+
+	// Flagged drivers are not flagged again
+	if flagged, _ := uc.FlagExpiredLicenses(context.Background()); flagged != 0 {
+		t.Errorf("expected no drivers flagged on the second run, got %d", flagged)
+	}
+}
+
+func TestNormalizeLicense(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+
+	license, err := normalizeLicense(&domain.DriverLicense{Number: " tr 1234567 ", Class: "d1e", ExpiresAt: now.AddDate(1, 0, 0)}, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if license.Number != "TR1234567" || license.Class != "D1E" {
+		t.Errorf("expected a normalized license, got %+v", license)
+	}
+
+	tests := []struct {
+		name    string
+		license domain.DriverLicense
+		wantErr error
+	}{
+		{"short number", domain.DriverLicense{Number: "TR1", Class: "B", ExpiresAt: now.AddDate(1, 0, 0)}, ErrInvalidLicense},
+		{"motorcycle class", domain.DriverLicense{Number: "TR1234567", Class: "A2", ExpiresAt: now.AddDate(1, 0, 0)}, ErrInvalidLicense},
+		{"missing expiry", domain.DriverLicense{Number: "TR1234567", Class: "B"}, ErrInvalidLicense},
+		{"expired", domain.DriverLicense{Number: "TR1234567", Class: "B", ExpiresAt: now}, ErrLicenseExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := normalizeLicense(&tt.license, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/imaging"
+	"github.com/bitaksi/driver-service/internal/storage"
+	"go.uber.org/zap"
+)
+
+// PhotoUseCase defines the interface for driver profile pictures
+type PhotoUseCase interface {
+	UploadPhoto(ctx context.Context, driverID string, data []byte) (*domain.Driver, error)
+}
+
+// PhotoOptions bounds accepted pictures and sets the sizes they are stored in
+type PhotoOptions struct {
+	// MaxBytes is the largest upload accepted
+	MaxBytes int64
+	// MinDimension and MaxDimension bound each side of an upload in pixels
+	MinDimension int
+	MaxDimension int
+	// FullSize is the longest side the full-size copy is scaled down to
+	FullSize int
+	// ThumbnailSizes are the sides of the square thumbnails in pixels
+	ThumbnailSizes []int
+	// Quality is the JPEG quality (1-100) the copies are encoded with
+	Quality int
+}
+
+// photoUseCase implements PhotoUseCase
+type photoUseCase struct {
+	repo   domain.DriverRepository
+	store  storage.Store
+	opts   PhotoOptions
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewPhotoUseCase creates a new photo use case
+func NewPhotoUseCase(repo domain.DriverRepository, store storage.Store, opts PhotoOptions, logger *zap.Logger) PhotoUseCase {
+	if opts.FullSize <= 0 {
+		opts.FullSize = 1024
+	}
+	if opts.Quality <= 0 || opts.Quality > 100 {
+		opts.Quality = 85
+	}
+	return &photoUseCase{
+		repo:   repo,
+		store:  store,
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// UploadPhoto validates the picture, stores it in every configured size and
+// sets it as the driver's photo. The previous photo's files are removed once
+// the driver has been updated.
+func (uc *photoUseCase) UploadPhoto(ctx context.Context, driverID string, data []byte) (*domain.Driver, error) {
+	if len(data) == 0 {
+		return nil, ErrPhotoRequired
+	}
+	if uc.opts.MaxBytes > 0 && int64(len(data)) > uc.opts.MaxBytes {
+		return nil, ErrPhotoTooLarge
+	}
+
+	// Check the header before decoding so huge pictures are never held in memory
+	width, height, err := imaging.Size(data)
+	if err != nil {
+		return nil, ErrInvalidPhotoFormat
+	}
+	if width < uc.opts.MinDimension || height < uc.opts.MinDimension ||
+		(uc.opts.MaxDimension > 0 && (width > uc.opts.MaxDimension || height > uc.opts.MaxDimension)) {
+		return nil, fmt.Errorf("%w: got %dx%d, each side must be between %d and %d pixels",
+			ErrPhotoDimensions, width, height, uc.opts.MinDimension, uc.opts.MaxDimension)
+	}
+
+	driver, err := uc.repo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, ErrInvalidPhotoFormat
+	}
+
+	// Each upload gets its own prefix so URLs never change content and CDNs can cache them
+	uploadedAt := uc.now().UTC()
+	prefix := "drivers/" + driverID + "/photo/" + strconv.FormatInt(uploadedAt.UnixNano(), 36) + "/"
+	full := imaging.Fit(img, uc.opts.FullSize)
+	photo := &domain.DriverPhoto{
+		Thumbnails: make(map[string]string, len(uc.opts.ThumbnailSizes)),
+		Width:      full.Bounds().Dx(),
+		Height:     full.Bounds().Dy(),
+		UploadedAt: uploadedAt,
+	}
+
+	if photo.URL, err = uc.put(ctx, photo, prefix+"full.jpg", full); err != nil {
+		return nil, err
+	}
+	for _, size := range uc.opts.ThumbnailSizes {
+		name := strconv.Itoa(size)
+		if photo.Thumbnails[name], err = uc.put(ctx, photo, prefix+name+".jpg", imaging.Thumbnail(img, size)); err != nil {
+			return nil, err
+		}
+	}
+
+	previous := driver.Photo
+	driver.Photo = photo
+	if err := uc.repo.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to save driver photo", zap.Error(err), zap.String("id", driverID))
+		uc.remove(ctx, photo)
+		return nil, errors.New("failed to update driver")
+	}
+	if previous != nil {
+		uc.remove(ctx, previous)
+	}
+
+	uc.logger.Info("driver photo uploaded", append(actorFields(ctx), zap.String("id", driverID), zap.Int("bytes", len(data)))...)
+	return driver, nil
+}
+
+// put encodes and stores one copy, recording its key on the photo. Files
+// already stored for the photo are removed when it fails.
+func (uc *photoUseCase) put(ctx context.Context, photo *domain.DriverPhoto, key string, img image.Image) (string, error) {
+	var url string
+	data, err := imaging.EncodeJPEG(img, uc.opts.Quality)
+	if err == nil {
+		url, err = uc.store.Put(ctx, key, "image/jpeg", data)
+	}
+	if err != nil {
+		uc.logger.Error("failed to store driver photo", zap.Error(err), zap.String("key", key))
+		uc.remove(ctx, photo)
+		return "", errors.New("failed to store photo")
+	}
+
+	photo.Keys = append(photo.Keys, key)
+	return url, nil
+}
+
+// remove deletes a photo's files; failures only leave unreferenced files behind
+func (uc *photoUseCase) remove(ctx context.Context, photo *domain.DriverPhoto) {
+	for _, key := range photo.Keys {
+		if err := uc.store.Delete(ctx, key); err != nil {
+			uc.logger.Warn("failed to delete photo file", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
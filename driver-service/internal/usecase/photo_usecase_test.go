@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/storage"
+	"go.uber.org/zap"
+)
+
+// memoryStore is an in-memory storage.Store
+type memoryStore struct {
+	files map[string][]byte
+	fail  bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{files: make(map[string][]byte)}
+}
+
+func (s *memoryStore) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	if s.fail {
+		return "", errors.New("disk full")
+	}
+	s.files[key] = data
+	return "https://cdn.example.com/" + key, nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+var _ storage.Store = (*memoryStore)(nil)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func testPhotoOptions() PhotoOptions {
+	return PhotoOptions{
+		MaxBytes:       1 << 20,
+		MinDimension:   64,
+		MaxDimension:   1000,
+		FullSize:       200,
+		ThumbnailSizes: []int{128, 64},
+	}
+}
+
+func TestPhotoUseCase_UploadPhoto(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	store := newMemoryStore()
+	uc := NewPhotoUseCase(repo, store, testPhotoOptions(), zap.NewNop())
+	ctx := context.Background()
+
+	driver, err := uc.UploadPhoto(ctx, "driver-1", testPNG(t, 400, 300))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	photo := driver.Photo
+	if photo == nil {
+		t.Fatal("expected the driver to have a photo")
+	}
+	if photo.Width != 200 || photo.Height != 150 {
+		t.Errorf("expected the full-size copy to be scaled to 200x150, got %dx%d", photo.Width, photo.Height)
+	}
+	if !strings.HasPrefix(photo.URL, "https://cdn.example.com/drivers/driver-1/photo/") || !strings.HasSuffix(photo.URL, "/full.jpg") {
+		t.Errorf("unexpected url %q", photo.URL)
+	}
+	if len(photo.Thumbnails) != 2 || photo.Thumbnails["64"] == "" || photo.Thumbnails["128"] == "" {
+		t.Errorf("expected 64 and 128 thumbnails, got %v", photo.Thumbnails)
+	}
+	if len(store.files) != 3 {
+		t.Errorf("expected 3 stored files, got %d", len(store.files))
+	}
+	if repo.drivers["driver-1"].Photo == nil {
+		t.Error("expected the photo to be saved")
+	}
+
+	// Replacing the photo removes the previous files
+	first := photo.Keys
+	if _, err := uc.UploadPhoto(ctx, "driver-1", testPNG(t, 300, 300)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range first {
+		if _, ok := store.files[key]; ok {
+			t.Errorf("expected previous file %s to be removed", key)
+		}
+	}
+	if len(store.files) != 3 {
+		t.Errorf("expected 3 stored files after replacing, got %d", len(store.files))
+	}
+}
+
+func TestPhotoUseCase_UploadValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+		errMsg  string
+	}{
+		{name: "empty", data: nil, wantErr: ErrPhotoRequired},
+		{name: "too large", data: make([]byte, 1<<20+1), wantErr: ErrPhotoTooLarge},
+		{name: "not an image", data: []byte("%PDF-1.4"), wantErr: ErrInvalidPhotoFormat},
+		{name: "too small", data: testPNG(t, 32, 300), wantErr: ErrPhotoDimensions},
+		{name: "too big", data: testPNG(t, 1200, 300), wantErr: ErrPhotoDimensions},
+		{name: "driver not found", data: testPNG(t, 100, 100), errMsg: "driver not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemoryStore()
+			uc := NewPhotoUseCase(newMockDriverRepository(), store, testPhotoOptions(), zap.NewNop())
+
+			_, err := uc.UploadPhoto(context.Background(), "missing", tt.data)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.errMsg != "" && (err == nil || err.Error() != tt.errMsg) {
+				t.Errorf("expected %q, got %v", tt.errMsg, err)
+			}
+			if len(store.files) != 0 {
+				t.Errorf("expected nothing to be stored, got %d files", len(store.files))
+			}
+		})
+	}
+}
+
+func TestPhotoUseCase_StoreFailure(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+	store := newMemoryStore()
+	store.fail = true
+	uc := NewPhotoUseCase(repo, store, testPhotoOptions(), zap.NewNop())
+
+	if _, err := uc.UploadPhoto(context.Background(), "driver-1", testPNG(t, 100, 100)); err == nil {
+		t.Fatal("expected an error when the store fails")
+	}
+	if repo.drivers["driver-1"].Photo != nil {
+		t.Error("expected the driver to be left without a photo")
+	}
+}
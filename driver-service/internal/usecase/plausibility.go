@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/pkg/haversine"
+	"go.uber.org/zap"
+)
+
+// LocationPlausibilityOptions controls how location updates implying an
+// implausible speed are handled
+type LocationPlausibilityOptions struct {
+	// MaxSpeedKmh is the fastest speed a driver can plausibly travel between updates
+	MaxSpeedKmh float64
+	// MinDistanceKm ignores shorter jumps, so GPS jitter between updates sent
+	// moments apart is not taken for speed
+	MinDistanceKm float64
+	// Reject refuses anomalous updates instead of only flagging them
+	Reject bool
+}
+
+// WithLocationPlausibility compares every location update with the driver's
+// previous position and records the ones implying a speed above the limit,
+// which catches spoofed GPS. With opts.Reject the update is refused as well.
+func WithLocationPlausibility(anomalies domain.LocationAnomalyRecorder, opts LocationPlausibilityOptions) DriverUseCaseOption {
+	return func(uc *driverUseCase) {
+		uc.anomalies = anomalies
+		uc.plausibility = opts
+	}
+}
+
+// checkLocationJump records a move of the driver to location at at when it
+// implies an implausible speed, and returns ErrImplausibleLocation when such
+// moves are rejected
+func (uc *driverUseCase) checkLocationJump(ctx context.Context, driver *domain.Driver, location domain.Location, at time.Time) error {
+	if uc.anomalies == nil {
+		return nil
+	}
+	anomaly := detectLocationJump(driver, location, at, uc.plausibility)
+	if anomaly == nil {
+		return nil
+	}
+	anomaly.Rejected = uc.plausibility.Reject
+
+	uc.logger.Warn("implausible driver location jump", append(actorFields(ctx),
+		zap.String("id", driver.ID),
+		zap.Float64("distanceKm", anomaly.DistanceKm),
+		zap.Float64("elapsedSec", anomaly.ElapsedSec),
+		zap.Float64("speedKmh", anomaly.SpeedKmh),
+		zap.Bool("rejected", anomaly.Rejected),
+	)...)
+	// Losing the record only loses statistics
+	if err := uc.anomalies.RecordLocationAnomaly(ctx, anomaly); err != nil {
+		uc.logger.Warn("failed to record location anomaly", zap.Error(err), zap.String("id", driver.ID))
+	}
+	if anomaly.Rejected {
+		return ErrImplausibleLocation
+	}
+	return nil
+}
+
+// detectLocationJump returns the anomaly a move of the driver to location at
+// at is, or nil when the move is plausible or there is no previous position
+func detectLocationJump(driver *domain.Driver, location domain.Location, at time.Time, opts LocationPlausibilityOptions) *domain.LocationAnomaly {
+	previous := driver.Location
+	if opts.MaxSpeedKmh <= 0 || driver.LocationUpdatedAt == nil || (previous.Lat == 0 && previous.Lon == 0) {
+		return nil
+	}
+	distanceKm := haversine.Distance(previous.Lat, previous.Lon, location.Lat, location.Lon)
+	if distanceKm < opts.MinDistanceKm || distanceKm == 0 {
+		return nil
+	}
+	// Updates sent at the same moment count as a second apart
+	elapsed := at.Sub(*driver.LocationUpdatedAt)
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+	speedKmh := distanceKm / elapsed.Hours()
+	if speedKmh <= opts.MaxSpeedKmh {
+		return nil
+	}
+	return &domain.LocationAnomaly{
+		DriverID:   driver.ID,
+		From:       previous,
+		To:         location,
+		DistanceKm: distanceKm,
+		ElapsedSec: elapsed.Seconds(),
+		SpeedKmh:   speedKmh,
+		DetectedAt: at,
+	}
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+func TestDetectLocationJump(t *testing.T) {
+	reportedAt := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	taksim := domain.Location{Lat: 41.0370, Lon: 28.9850}
+	opts := LocationPlausibilityOptions{MaxSpeedKmh: 250, MinDistanceKm: 0.5}
+
+	tests := []struct {
+		name     string
+		driver   *domain.Driver
+		location domain.Location
+		at       time.Time
+		opts     LocationPlausibilityOptions
+		wantJump bool
+	}{
+		{
+			name:     "no previous fix",
+			driver:   &domain.Driver{ID: "d1"},
+			location: domain.Location{Lat: 39.9208, Lon: 32.8541},
+			at:       reportedAt,
+			opts:     opts,
+		},
+		{
+			name:     "jitter below the minimum distance",
+			driver:   &domain.Driver{ID: "d1", Location: taksim, LocationUpdatedAt: &reportedAt},
+			location: domain.Location{Lat: 41.0380, Lon: 28.9860},
+			at:       reportedAt,
+			opts:     opts,
+		},
+		{
+			name:     "plausible speed",
+			driver:   &domain.Driver{ID: "d1", Location: taksim, LocationUpdatedAt: &reportedAt},
+			location: domain.Location{Lat: 41.0431, Lon: 29.0099},
+			at:       reportedAt.Add(5 * time.Minute),
+			opts:     opts,
+		},
+		{
+			name:     "jump to Ankara",
+			driver:   &domain.Driver{ID: "d1", Location: taksim, LocationUpdatedAt: &reportedAt},
+			location: domain.Location{Lat: 39.9208, Lon: 32.8541},
+			at:       reportedAt.Add(time.Minute),
+			opts:     opts,
+			wantJump: true,
+		},
+		{
+			name:     "disabled",
+			driver:   &domain.Driver{ID: "d1", Location: taksim, LocationUpdatedAt: &reportedAt},
+			location: domain.Location{Lat: 39.9208, Lon: 32.8541},
+			at:       reportedAt.Add(time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anomaly := detectLocationJump(tt.driver, tt.location, tt.at, tt.opts)
+			if (anomaly != nil) != tt.wantJump {
+				t.Fatalf("expected jump %v, got %+v", tt.wantJump, anomaly)
+			}
+			if anomaly != nil && (anomaly.SpeedKmh <= tt.opts.MaxSpeedKmh || anomaly.ElapsedSec != 60 || anomaly.From != taksim) {
+				t.Errorf("unexpected anomaly %+v", anomaly)
+			}
+		})
+	}
+}
+
+func TestDriverUseCase_LocationPlausibility(t *testing.T) {
+	reportedAt := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	taksim := domain.Location{Lat: 41.0370, Lon: 28.9850}
+	ankaraLat, ankaraLon := 39.9208, 32.8541
+	ctx := context.Background()
+
+	newUseCase := func(reject bool) (*driverUseCase, *mockDriverRepository, *mockActivityRepository) {
+		repo := newMockDriverRepository()
+		previous := reportedAt
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Location: taksim, LocationUpdatedAt: &previous}
+		activity := newMockActivityRepository()
+		uc := NewDriverUseCase(repo, zap.NewNop(), WithLocationPlausibility(activity, LocationPlausibilityOptions{
+			MaxSpeedKmh:   250,
+			MinDistanceKm: 0.5,
+			Reject:        reject,
+		})).(*driverUseCase)
+		uc.now = func() time.Time { return reportedAt.Add(time.Minute) }
+		return uc, repo, activity
+	}
+
+	t.Run("flagged jumps are applied", func(t *testing.T) {
+		uc, _, activity := newUseCase(false)
+		driver, err := uc.UpdateDriver(ctx, "driver-1", &UpdateDriverRequest{Lat: &ankaraLat, Lon: &ankaraLon})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if driver.Location.Lat != ankaraLat {
+			t.Errorf("expected the location to be updated, got %+v", driver.Location)
+		}
+		if len(activity.anomalies) != 1 || activity.anomalies[0].Rejected {
+			t.Errorf("expected one flagged anomaly, got %+v", activity.anomalies)
+		}
+	})
+
+	t.Run("rejected jumps keep the previous location", func(t *testing.T) {
+		uc, repo, activity := newUseCase(true)
+		_, err := uc.UpdateDriver(ctx, "driver-1", &UpdateDriverRequest{Lat: &ankaraLat, Lon: &ankaraLon})
+		if !errors.Is(err, ErrImplausibleLocation) {
+			t.Fatalf("expected ErrImplausibleLocation, got %v", err)
+		}
+		if repo.drivers["driver-1"].Location != taksim {
+			t.Errorf("expected the location to be kept, got %+v", repo.drivers["driver-1"].Location)
+		}
+		if len(activity.anomalies) != 1 || !activity.anomalies[0].Rejected {
+			t.Errorf("expected one rejected anomaly, got %+v", activity.anomalies)
+		}
+	})
+}
+
+func TestStatsUseCase_GetLocationAnomalies(t *testing.T) {
+	activity := newMockActivityRepository()
+	activity.anomalies = []*domain.LocationAnomaly{{DriverID: "driver-1"}, {DriverID: "driver-1"}, {DriverID: "driver-2"}}
+	uc := NewStatsUseCase(newMockDriverRepository(), activity, time.Minute, zap.NewNop())
+	ctx := context.Background()
+
+	report, err := uc.GetLocationAnomalies(ctx, nil, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Drivers) != 2 || report.Drivers[0].Count != 2 {
+		t.Errorf("unexpected report %+v", report.Drivers)
+	}
+	if report.To.Sub(report.From) != 30*24*time.Hour {
+		t.Errorf("expected the last 30 days, got %v - %v", report.From, report.To)
+	}
+
+	for _, limit := range []int{-1, 1001} {
+		if _, err := uc.GetLocationAnomalies(ctx, nil, nil, limit); !errors.Is(err, ErrInvalidAnomalyLimit) {
+			t.Errorf("limit %d: expected ErrInvalidAnomalyLimit, got %v", limit, err)
+		}
+	}
+}
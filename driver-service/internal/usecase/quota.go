@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// QuotaOptions are the driver seats tenants pay for. Tenants are fleets.
+type QuotaOptions struct {
+	// MaxDrivers is the quota of fleets without one of their own; 0 is unlimited
+	MaxDrivers int
+	// Tenants are quotas by fleet ID; 0 makes a fleet unlimited
+	Tenants map[string]int
+	// Thresholds are the usage percentages, in ascending order, that emit a
+	// TenantQuotaThresholdReached event when a driver joining reaches them
+	Thresholds []int
+}
+
+// DriverQuotas refuses drivers joining a fleet that has used up its quota.
+// Quotas are soft: the fleet's drivers are counted before one joins, so
+// concurrent creations may overshoot a quota by the requests in flight.
+type DriverQuotas struct {
+	drivers domain.DriverPageRepository
+	opts    QuotaOptions
+	logger  *zap.Logger
+	now     func() time.Time
+
+	// events is nil unless threshold events are published
+	events domain.EventPublisher
+}
+
+// NewDriverQuotas creates the quotas of opts, counting drivers in drivers.
+// Threshold events go to events; nil only logs them.
+func NewDriverQuotas(drivers domain.DriverPageRepository, opts QuotaOptions, events domain.EventPublisher, logger *zap.Logger) *DriverQuotas {
+	return &DriverQuotas{
+		drivers: drivers,
+		opts:    opts,
+		events:  events,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// limit returns the quota of a fleet; 0 is unlimited
+func (q *DriverQuotas) limit(tenantID string) int {
+	if max, ok := q.opts.Tenants[tenantID]; ok {
+		return max
+	}
+	return q.opts.MaxDrivers
+}
+
+// Usage counts the drivers of a fleet against its quota
+func (q *DriverQuotas) Usage(ctx context.Context, tenantID string) (*domain.DriverQuota, error) {
+	drivers, err := q.drivers.CountDrivers(ctx, domain.DriverFilter{FleetID: tenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := q.opts.Thresholds
+	if thresholds == nil {
+		thresholds = []int{}
+	}
+	usage := &domain.DriverQuota{
+		TenantID:   tenantID,
+		MaxDrivers: q.limit(tenantID),
+		Drivers:    drivers,
+		Thresholds: thresholds,
+	}
+	if usage.MaxDrivers > 0 {
+		remaining := int64(usage.MaxDrivers) - drivers
+		if remaining < 0 {
+			remaining = 0
+		}
+		usage.Remaining = &remaining
+		usage.UsedPercent = math.Round(float64(drivers)*1000/float64(usage.MaxDrivers)) / 10
+	}
+	return usage, nil
+}
+
+// admit returns how many drivers a fleet has before one more joins, and
+// ErrQuotaExceeded when its quota is used up. Drivers outside a fleet, and
+// fleets without a quota, are always admitted and not counted.
+func (q *DriverQuotas) admit(ctx context.Context, tenantID string) (int64, error) {
+	if q == nil || tenantID == "" {
+		return 0, nil
+	}
+	max := q.limit(tenantID)
+	if max <= 0 {
+		return 0, nil
+	}
+
+	drivers, err := q.drivers.CountDrivers(ctx, domain.DriverFilter{FleetID: tenantID})
+	if err != nil {
+		return 0, err
+	}
+	if drivers >= int64(max) {
+		q.logger.Warn("driver refused, fleet quota used up", append(actorFields(ctx),
+			zap.String("fleetId", tenantID),
+			zap.Int64("drivers", drivers),
+			zap.Int("maxDrivers", max),
+		)...)
+		return drivers, fmt.Errorf("%w of %d drivers", ErrQuotaExceeded, max)
+	}
+	return drivers, nil
+}
+
+// joined emits an event for every threshold a driver joining a fleet that
+// had before drivers took its usage to
+func (q *DriverQuotas) joined(tenantID string, before int64) {
+	if q == nil || tenantID == "" {
+		return
+	}
+	max := q.limit(tenantID)
+	if max <= 0 {
+		return
+	}
+
+	after := before + 1
+	for _, threshold := range q.opts.Thresholds {
+		// Compare in whole numbers so 80% of 5 seats is reached at exactly 4
+		seats := int64(threshold) * int64(max)
+		if before*100 >= seats || after*100 < seats {
+			continue
+		}
+		q.logger.Warn("fleet reached a quota threshold",
+			zap.String("fleetId", tenantID),
+			zap.Int("threshold", threshold),
+			zap.Int64("drivers", after),
+			zap.Int("maxDrivers", max),
+		)
+		if q.events != nil {
+			q.events.Publish(domain.EventTenantQuotaThresholdReached, domain.TenantQuotaThresholdReached{
+				TenantID:   tenantID,
+				Threshold:  threshold,
+				Drivers:    after,
+				MaxDrivers: max,
+				Timestamp:  q.now().UTC(),
+			})
+		}
+	}
+}
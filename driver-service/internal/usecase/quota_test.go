@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+func TestDriverQuotas_CreateDriver(t *testing.T) {
+	fleets := newMockFleetRepository()
+	fleets.fleets["fleet-1"] = &domain.Fleet{ID: "fleet-1", Name: "Kadikoy"}
+	fleets.fleets["fleet-2"] = &domain.Fleet{ID: "fleet-2", Name: "Besiktas"}
+	repo := newMockDriverRepository()
+	publisher := &recordingAnalytics{}
+	quotas := NewDriverQuotas(repo, QuotaOptions{
+		MaxDrivers: 2,
+		Tenants:    map[string]int{"fleet-2": 0},
+		Thresholds: []int{50, 100},
+	}, publisher, zap.NewNop())
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithFleets(fleets), WithDriverQuotas(quotas))
+	ctx := context.Background()
+
+	create := func(plate, fleetID string) error {
+		_, err := uc.CreateDriver(ctx, &CreateDriverRequest{
+			FirstName: "Ahmet",
+			LastName:  "Demir",
+			Plate:     plate,
+			TaxiType:  domain.TaxiTypeSari,
+			CarBrand:  "Toyota",
+			CarModel:  "Corolla",
+			Lat:       41.0431,
+			Lon:       29.0099,
+			FleetID:   fleetID,
+		})
+		return err
+	}
+
+	for _, plate := range []string{"34ABC110", "34ABC120"} {
+		if err := create(plate, "fleet-1"); err != nil {
+			t.Fatalf("unexpected error creating %s: %v", plate, err)
+		}
+	}
+	err := create("34ABC130", "fleet-1")
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if err.Error() != "fleet has reached its driver quota of 2 drivers" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	// Drivers outside a fleet and fleets with a quota of 0 are not capped
+	for _, plate := range []string{"34ABC140", "34ABC150", "34ABC160"} {
+		if err := create(plate, "fleet-2"); err != nil {
+			t.Errorf("unexpected error creating %s in an unlimited fleet: %v", plate, err)
+		}
+	}
+	if err := create("34ABC170", ""); err != nil {
+		t.Errorf("unexpected error creating a driver outside a fleet: %v", err)
+	}
+
+	if len(publisher.events) != 2 {
+		t.Fatalf("expected an event at 50%% and at 100%%, got %+v", publisher.events)
+	}
+	for i, threshold := range []int{50, 100} {
+		event, ok := publisher.events[i].(domain.TenantQuotaThresholdReached)
+		if !ok || event.TenantID != "fleet-1" || event.Threshold != threshold || event.Drivers != int64(i+1) || event.MaxDrivers != 2 {
+			t.Errorf("unexpected event %+v", publisher.events[i])
+		}
+	}
+}
+
+func TestDriverQuotas_AssignDriver(t *testing.T) {
+	fleets := newMockFleetRepository()
+	fleets.fleets["fleet-1"] = &domain.Fleet{ID: "fleet-1", Name: "Kadikoy"}
+	drivers := newMockDriverRepository()
+	drivers.drivers["a"] = &domain.Driver{ID: "a", FleetID: "fleet-1"}
+	drivers.drivers["b"] = &domain.Driver{ID: "b", FleetID: "fleet-1"}
+	drivers.drivers["c"] = &domain.Driver{ID: "c", FleetID: "fleet-1"}
+	drivers.drivers["d"] = &domain.Driver{ID: "d"}
+	quotas := NewDriverQuotas(drivers, QuotaOptions{Tenants: map[string]int{"fleet-1": 3}, Thresholds: []int{80}}, nil, zap.NewNop())
+	uc := NewFleetUseCase(fleets, drivers, zap.NewNop(), WithFleetQuotas(quotas))
+	ctx := context.Background()
+
+	if _, err := uc.AssignDriver(ctx, "d", "fleet-1"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if drivers.drivers["d"].FleetID != "" {
+		t.Error("expected the refused driver to stay outside the fleet")
+	}
+	// A driver already in the fleet takes no new seat
+	if _, err := uc.AssignDriver(ctx, "a", "fleet-1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	quota, err := uc.GetQuota(ctx, "fleet-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quota.MaxDrivers != 3 || quota.Drivers != 3 || quota.Remaining == nil || *quota.Remaining != 0 || quota.UsedPercent != 100 {
+		t.Errorf("unexpected quota %+v", quota)
+	}
+
+	// Leaving the fleet frees a seat
+	if _, err := uc.AssignDriver(ctx, "c", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.AssignDriver(ctx, "d", "fleet-1"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := uc.GetQuota(ctx, "missing"); !errors.Is(err, ErrFleetNotFound) {
+		t.Errorf("expected ErrFleetNotFound, got %v", err)
+	}
+}
+
+func TestDriverQuotas_Usage(t *testing.T) {
+	drivers := newMockDriverRepository()
+	drivers.drivers["a"] = &domain.Driver{ID: "a", FleetID: "fleet-1"}
+	drivers.drivers["b"] = &domain.Driver{ID: "b", FleetID: "fleet-2"}
+	quotas := NewDriverQuotas(drivers, QuotaOptions{Tenants: map[string]int{"fleet-1": 3}}, nil, zap.NewNop())
+
+	usage, err := quotas.Usage(context.Background(), "fleet-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.Drivers != 1 || *usage.Remaining != 2 || usage.UsedPercent != 33.3 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+
+	// Fleets without a quota report their drivers only
+	usage, err = quotas.Usage(context.Background(), "fleet-2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.MaxDrivers != 0 || usage.Drivers != 1 || usage.Remaining != nil || usage.UsedPercent != 0 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/storage"
+	"go.uber.org/zap"
+)
+
+// defaultRetentionBatchSize bounds how many drivers one retention run erases
+const defaultRetentionBatchSize = 100
+
+// RetentionUseCase defines the interface for erasing driver personal data
+type RetentionUseCase interface {
+	// ErasePersonalData deletes the driver if needed and erases its personal
+	// data at once, recording who asked and why
+	ErasePersonalData(ctx context.Context, driverID, actor, reason string) (*domain.Erasure, error)
+	// EraseExpired erases the personal data of drivers deleted longer ago than
+	// the retention window and purges old location history; it returns how
+	// many drivers it erased
+	EraseExpired(ctx context.Context) (int, error)
+	ListErasures(ctx context.Context, driverID string, limit int) ([]*domain.Erasure, error)
+}
+
+// RetentionOptions holds the retention windows
+type RetentionOptions struct {
+	// DeletedDriverRetention is how long a deleted driver's remaining personal
+	// data is kept, e.g. for disputes, before the retention job erases it
+	DeletedDriverRetention time.Duration
+	// LocationRetention is how long location history is kept; zero keeps it
+	// until the TTL index expires it
+	LocationRetention time.Duration
+	// BatchSize is the most drivers one run erases; the rest wait for the next run
+	BatchSize int
+}
+
+// retentionUseCase implements RetentionUseCase
+type retentionUseCase struct {
+	repo    domain.RetentionRepository
+	drivers domain.DriverRepository
+	files   storage.Store
+	opts    RetentionOptions
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewRetentionUseCase creates a new retention use case. Photo files of erased
+// drivers are deleted from files.
+func NewRetentionUseCase(repo domain.RetentionRepository, drivers domain.DriverRepository, files storage.Store, opts RetentionOptions, logger *zap.Logger) RetentionUseCase {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultRetentionBatchSize
+	}
+	return &retentionUseCase{
+		repo:    repo,
+		drivers: drivers,
+		files:   files,
+		opts:    opts,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// ErasePersonalData works on live and already deleted drivers alike. A live
+// driver is deleted first, leaving the tombstone delta syncs report.
+func (uc *retentionUseCase) ErasePersonalData(ctx context.Context, driverID, actor, reason string) (*domain.Erasure, error) {
+	var files []string
+	driver, err := uc.drivers.GetByID(ctx, driverID)
+	if err == nil {
+		if err := authorizeDriver(ctx, driver); err != nil {
+			return nil, err
+		}
+		// Deleting the driver drops the photo from the document, so its files are collected first
+		if driver.Photo != nil {
+			files = driver.Photo.Keys
+		}
+		if err := uc.drivers.Delete(ctx, driverID); err != nil && err.Error() != "driver not found" {
+			uc.logger.Error("failed to delete driver for erasure", zap.Error(err), zap.String("driverId", driverID))
+			return nil, errors.New("failed to erase personal data")
+		}
+	}
+
+	erasure, err := uc.erase(ctx, driverID, files)
+	if err != nil {
+		return nil, err
+	}
+	erasure.Trigger = domain.ErasureTriggerRequest
+	erasure.RequestedBy = strings.TrimSpace(actor)
+	erasure.Reason = strings.TrimSpace(reason)
+	if err := uc.repo.RecordErasure(ctx, erasure); err != nil {
+		// The data is gone either way; the log below still records the erasure
+		uc.logger.Error("failed to record erasure", zap.Error(err), zap.String("driverId", driverID))
+	}
+
+	uc.logger.Info("audit: driver personal data erased", append(actorFields(ctx),
+		zap.String("driverId", driverID),
+		zap.String("trigger", string(erasure.Trigger)),
+		zap.String("requestedBy", erasure.RequestedBy),
+		zap.String("reason", erasure.Reason),
+		zap.Any("records", erasure.Records),
+		zap.Int("files", erasure.Files),
+	)...)
+	return erasure, nil
+}
+
+// EraseExpired erases one batch of drivers; a failed driver is logged and
+// retried on the next run
+func (uc *retentionUseCase) EraseExpired(ctx context.Context) (int, error) {
+	now := uc.now()
+	if uc.opts.LocationRetention > 0 {
+		purged, err := uc.repo.PurgeLocations(ctx, now.Add(-uc.opts.LocationRetention))
+		if err != nil {
+			return 0, err
+		}
+		if purged > 0 {
+			uc.logger.Info("expired location history purged", zap.Int64("points", purged))
+		}
+	}
+
+	ids, err := uc.repo.ListExpired(ctx, now.Add(-uc.opts.DeletedDriverRetention), uc.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return erased, ctx.Err()
+		}
+		erasure, err := uc.erase(ctx, id, nil)
+		if err != nil {
+			uc.logger.Warn("failed to erase expired driver", zap.Error(err), zap.String("driverId", id))
+			continue
+		}
+		erasure.Trigger = domain.ErasureTriggerRetention
+		if err := uc.repo.RecordErasure(ctx, erasure); err != nil {
+			uc.logger.Error("failed to record erasure", zap.Error(err), zap.String("driverId", id))
+		}
+		uc.logger.Info("audit: driver personal data erased",
+			zap.String("driverId", id),
+			zap.String("trigger", string(erasure.Trigger)),
+			zap.Any("records", erasure.Records),
+			zap.Int("files", erasure.Files),
+		)
+		erased++
+	}
+	return erased, nil
+}
+
+// ListErasures returns the erasure audit trail, newest first
+func (uc *retentionUseCase) ListErasures(ctx context.Context, driverID string, limit int) ([]*domain.Erasure, error) {
+	erasures, err := uc.repo.ListErasures(ctx, driverID, clampEarningsLimit(limit))
+	if err != nil {
+		uc.logger.Error("failed to list erasures", zap.Error(err))
+		return nil, errors.New("failed to list erasures")
+	}
+	return erasures, nil
+}
+
+// erase anonymizes a deleted driver and deletes its files, the given ones and
+// any the tombstone still referenced
+func (uc *retentionUseCase) erase(ctx context.Context, driverID string, files []string) (*domain.Erasure, error) {
+	at := uc.now()
+	records, remaining, err := uc.repo.Anonymize(ctx, driverID, at)
+	if err != nil {
+		if err.Error() == "driver not found" {
+			return nil, errors.New("driver not found")
+		}
+		uc.logger.Error("failed to anonymize driver", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to erase personal data")
+	}
+
+	erasure := &domain.Erasure{DriverID: driverID, Records: records, ErasedAt: at}
+	seen := make(map[string]bool, len(files)+len(remaining))
+	for _, key := range append(files, remaining...) {
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := uc.files.Delete(ctx, key); err != nil {
+			// Logged with its key so it can be removed by hand
+			uc.logger.Warn("failed to delete driver file", zap.Error(err), zap.String("key", key))
+			continue
+		}
+		erasure.Files++
+	}
+	return erasure, nil
+}
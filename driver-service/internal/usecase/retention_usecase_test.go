@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockRetentionRepository keeps tombstones and erasures in memory
+type mockRetentionRepository struct {
+	deletedAt    map[string]time.Time
+	anonymized   map[string]bool
+	files        map[string][]string
+	erasures     []*domain.Erasure
+	purgedBefore time.Time
+	shouldFail   bool
+}
+
+func newMockRetentionRepository() *mockRetentionRepository {
+	return &mockRetentionRepository{
+		deletedAt:  make(map[string]time.Time),
+		anonymized: make(map[string]bool),
+		files:      make(map[string][]string),
+	}
+}
+
+func (m *mockRetentionRepository) ListExpired(ctx interface{}, deletedBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+	for id, at := range m.deletedAt {
+		if at.Before(deletedBefore) && !m.anonymized[id] && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *mockRetentionRepository) Anonymize(ctx interface{}, driverID string, at time.Time) (map[string]int64, []string, error) {
+	if m.shouldFail {
+		return nil, nil, errors.New("repository error")
+	}
+	if _, ok := m.deletedAt[driverID]; !ok {
+		return nil, nil, errors.New("driver not found")
+	}
+	m.anonymized[driverID] = true
+	return map[string]int64{"drivers": 1, "driver_locations": 12}, m.files[driverID], nil
+}
+
+func (m *mockRetentionRepository) PurgeLocations(ctx interface{}, before time.Time) (int64, error) {
+	m.purgedBefore = before
+	return 0, nil
+}
+
+func (m *mockRetentionRepository) RecordErasure(ctx interface{}, erasure *domain.Erasure) error {
+	erasure.ID = "erasure-" + erasure.DriverID
+	m.erasures = append(m.erasures, erasure)
+	return nil
+}
+
+func (m *mockRetentionRepository) ListErasures(ctx interface{}, driverID string, limit int) ([]*domain.Erasure, error) {
+	return m.erasures, nil
+}
+
+// tombstoningDriverRepository records deletions as tombstones in the retention repository
+type tombstoningDriverRepository struct {
+	*mockDriverRepository
+	retention *mockRetentionRepository
+	now       time.Time
+}
+
+func (r *tombstoningDriverRepository) Delete(ctx interface{}, id string) error {
+	if err := r.mockDriverRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.retention.deletedAt[id] = r.now
+	return nil
+}
+
+func TestRetentionUseCase_ErasePersonalData(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	newUseCase := func() (*retentionUseCase, *mockRetentionRepository, *mockDriverRepository, *memoryStore) {
+		retention := newMockRetentionRepository()
+		drivers := newMockDriverRepository()
+		files := newMemoryStore()
+		repo := &tombstoningDriverRepository{mockDriverRepository: drivers, retention: retention, now: now}
+		uc := NewRetentionUseCase(retention, repo, files, RetentionOptions{DeletedDriverRetention: 30 * 24 * time.Hour}, zap.NewNop()).(*retentionUseCase)
+		uc.now = func() time.Time { return now }
+		return uc, retention, drivers, files
+	}
+
+	t.Run("deletes a live driver and erases it with its photo", func(t *testing.T) {
+		uc, retention, drivers, files := newUseCase()
+		files.files["drivers/d1/photo/x/full.jpg"] = []byte("jpeg")
+		drivers.drivers["d1"] = &domain.Driver{ID: "d1", Photo: &domain.DriverPhoto{Keys: []string{"drivers/d1/photo/x/full.jpg"}}}
+
+		erasure, err := uc.ErasePersonalData(ctx, "d1", " ops-admin ", "KVKK request #4711")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := drivers.drivers["d1"]; ok {
+			t.Error("expected the driver to be deleted")
+		}
+		if !retention.anonymized["d1"] || len(files.files) != 0 || erasure.Files != 1 {
+			t.Errorf("expected the driver and its photo to be erased, got %+v", erasure)
+		}
+		if erasure.Trigger != domain.ErasureTriggerRequest || erasure.RequestedBy != "ops-admin" || erasure.Reason != "KVKK request #4711" {
+			t.Errorf("unexpected audit fields %+v", erasure)
+		}
+		if len(retention.erasures) != 1 || retention.erasures[0].ID == "" {
+			t.Error("expected the erasure to be recorded")
+		}
+	})
+
+	t.Run("erases an already deleted driver", func(t *testing.T) {
+		uc, retention, _, _ := newUseCase()
+		retention.deletedAt["d2"] = now.Add(-time.Hour)
+
+		if _, err := uc.ErasePersonalData(ctx, "d2", "", ""); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !retention.anonymized["d2"] {
+			t.Error("expected the tombstone to be erased")
+		}
+	})
+
+	t.Run("unknown driver", func(t *testing.T) {
+		uc, retention, _, _ := newUseCase()
+		if _, err := uc.ErasePersonalData(ctx, "missing", "", ""); err == nil || err.Error() != "driver not found" {
+			t.Errorf("expected driver not found, got %v", err)
+		}
+		if len(retention.erasures) != 0 {
+			t.Error("expected no erasure to be recorded")
+		}
+	})
+
+	t.Run("fleet admins only erase their own drivers", func(t *testing.T) {
+		uc, retention, drivers, _ := newUseCase()
+		drivers.drivers["d3"] = &domain.Driver{ID: "d3", FleetID: "fleet-a"}
+		fleetCtx := domain.ContextWithIdentity(ctx, domain.Identity{UserID: "admin", Role: domain.RoleFleetAdmin, TenantID: "fleet-b"})
+
+		if _, err := uc.ErasePersonalData(fleetCtx, "d3", "", ""); !errors.Is(err, ErrDriverNotInFleet) {
+			t.Errorf("expected ErrDriverNotInFleet, got %v", err)
+		}
+		if retention.anonymized["d3"] {
+			t.Error("expected the driver to be kept")
+		}
+	})
+}
+
+func TestRetentionUseCase_EraseExpired(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	retention := newMockRetentionRepository()
+	retention.deletedAt["old"] = now.AddDate(0, 0, -31)
+	retention.deletedAt["recent"] = now.AddDate(0, 0, -29)
+	retention.deletedAt["done"] = now.AddDate(0, 0, -90)
+	retention.anonymized["done"] = true
+
+	uc := NewRetentionUseCase(retention, newMockDriverRepository(), newMemoryStore(), RetentionOptions{
+		DeletedDriverRetention: 30 * 24 * time.Hour,
+		LocationRetention:      180 * 24 * time.Hour,
+	}, zap.NewNop()).(*retentionUseCase)
+	uc.now = func() time.Time { return now }
+
+	erased, err := uc.EraseExpired(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if erased != 1 || !retention.anonymized["old"] || retention.anonymized["recent"] {
+		t.Errorf("expected only the driver past the window to be erased, got %d", erased)
+	}
+	if len(retention.erasures) != 1 || retention.erasures[0].Trigger != domain.ErasureTriggerRetention {
+		t.Errorf("expected a retention erasure to be recorded, got %+v", retention.erasures)
+	}
+	if !retention.purgedBefore.Equal(now.AddDate(0, 0, -180)) {
+		t.Errorf("expected location history before %v to be purged, got %v", now.AddDate(0, 0, -180), retention.purgedBefore)
+	}
+}
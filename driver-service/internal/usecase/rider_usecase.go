@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// RiderUseCase defines the interface for rider registration and profiles
+type RiderUseCase interface {
+	RegisterRider(ctx context.Context, req *RegisterRiderRequest) (*domain.Rider, error)
+	GetRider(ctx context.Context, id string) (*domain.Rider, error)
+	UpdateRider(ctx context.Context, id string, req *UpdateRiderRequest) (*domain.Rider, error)
+	AddFavoriteLocation(ctx context.Context, riderID string, req *FavoriteLocationRequest) (*domain.Rider, error)
+	RemoveFavoriteLocation(ctx context.Context, riderID, favoriteID string) (*domain.Rider, error)
+}
+
+// RegisterRiderRequest represents the request to register a rider
+type RegisterRiderRequest struct {
+	FirstName string `json:"firstName" example:"Elif" binding:"required"`
+	LastName  string `json:"lastName" example:"Yılmaz" binding:"required"`
+	Phone     string `json:"phone" example:"+905551234567" binding:"required"`
+	Email     string `json:"email,omitempty" example:"elif@example.com"`
+}
+
+// UpdateRiderRequest represents a partial rider profile update
+type UpdateRiderRequest struct {
+	FirstName *string `json:"firstName,omitempty" example:"Elif"`
+	LastName  *string `json:"lastName,omitempty" example:"Kaya"`
+	Phone     *string `json:"phone,omitempty" example:"+905557654321"`
+	Email     *string `json:"email,omitempty" example:"elif.kaya@example.com"`
+}
+
+// FavoriteLocationRequest represents a place to save for a rider
+type FavoriteLocationRequest struct {
+	Label    string          `json:"label" example:"Home" binding:"required"`
+	Address  string          `json:"address,omitempty" example:"Caferağa Mah. Moda Cad. No:1, Kadıköy"`
+	Location domain.Location `json:"location"`
+}
+
+// riderUseCase implements RiderUseCase
+type riderUseCase struct {
+	riders domain.RiderRepository
+	logger *zap.Logger
+}
+
+// NewRiderUseCase creates a new rider use case
+func NewRiderUseCase(riders domain.RiderRepository, logger *zap.Logger) RiderUseCase {
+	return &riderUseCase{
+		riders: riders,
+		logger: logger,
+	}
+}
+
+// RegisterRider creates a rider with a unique phone number
+func (uc *riderUseCase) RegisterRider(ctx context.Context, req *RegisterRiderRequest) (*domain.Rider, error) {
+	rider := &domain.Rider{
+		FirstName:         strings.TrimSpace(req.FirstName),
+		LastName:          strings.TrimSpace(req.LastName),
+		Phone:             normalizePhone(req.Phone),
+		Email:             normalizeEmail(req.Email),
+		FavoriteLocations: []domain.FavoriteLocation{},
+	}
+	if err := validateRider(rider); err != nil {
+		return nil, err
+	}
+
+	if err := uc.riders.Create(ctx, rider); err != nil {
+		if err.Error() == ErrRiderPhoneTaken.Error() {
+			return nil, ErrRiderPhoneTaken
+		}
+		uc.logger.Error("failed to register rider", zap.Error(err))
+		return nil, errors.New("failed to register rider")
+	}
+
+	uc.logger.Info("rider registered", zap.String("id", rider.ID))
+	return rider, nil
+}
+
+// GetRider retrieves a rider by ID
+func (uc *riderUseCase) GetRider(ctx context.Context, id string) (*domain.Rider, error) {
+	rider, err := uc.riders.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == ErrRiderNotFound.Error() {
+			return nil, ErrRiderNotFound
+		}
+		uc.logger.Error("failed to get rider", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get rider")
+	}
+	return rider, nil
+}
+
+// UpdateRider applies a partial profile update
+func (uc *riderUseCase) UpdateRider(ctx context.Context, id string, req *UpdateRiderRequest) (*domain.Rider, error) {
+	rider, err := uc.GetRider(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstName != nil {
+		rider.FirstName = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil {
+		rider.LastName = strings.TrimSpace(*req.LastName)
+	}
+	if req.Phone != nil {
+		rider.Phone = normalizePhone(*req.Phone)
+	}
+	if req.Email != nil {
+		rider.Email = normalizeEmail(*req.Email)
+	}
+	if err := validateRider(rider); err != nil {
+		return nil, err
+	}
+
+	if err := uc.save(ctx, rider); err != nil {
+		return nil, err
+	}
+	return rider, nil
+}
+
+// AddFavoriteLocation saves a place for the rider
+func (uc *riderUseCase) AddFavoriteLocation(ctx context.Context, riderID string, req *FavoriteLocationRequest) (*domain.Rider, error) {
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return nil, ErrFavoriteLabelRequired
+	}
+	if err := validateCoordinates(req.Location.Lat, req.Location.Lon); err != nil {
+		return nil, err
+	}
+
+	rider, err := uc.GetRider(ctx, riderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rider.FavoriteLocations) >= domain.MaxFavoriteLocations {
+		return nil, ErrTooManyFavorites
+	}
+
+	id, err := newFavoriteID()
+	if err != nil {
+		return nil, err
+	}
+	rider.FavoriteLocations = append(rider.FavoriteLocations, domain.FavoriteLocation{
+		ID:       id,
+		Label:    label,
+		Address:  strings.TrimSpace(req.Address),
+		Location: req.Location,
+	})
+
+	if err := uc.save(ctx, rider); err != nil {
+		return nil, err
+	}
+	return rider, nil
+}
+
+// RemoveFavoriteLocation deletes a saved place
+func (uc *riderUseCase) RemoveFavoriteLocation(ctx context.Context, riderID, favoriteID string) (*domain.Rider, error) {
+	rider, err := uc.GetRider(ctx, riderID)
+	if err != nil {
+		return nil, err
+	}
+	if rider.FavoriteLocation(favoriteID) == nil {
+		return nil, ErrFavoriteNotFound
+	}
+
+	kept := make([]domain.FavoriteLocation, 0, len(rider.FavoriteLocations)-1)
+	for _, fav := range rider.FavoriteLocations {
+		if fav.ID != favoriteID {
+			kept = append(kept, fav)
+		}
+	}
+	rider.FavoriteLocations = kept
+
+	if err := uc.save(ctx, rider); err != nil {
+		return nil, err
+	}
+	return rider, nil
+}
+
+// save stores the rider and maps repository errors
+func (uc *riderUseCase) save(ctx context.Context, rider *domain.Rider) error {
+	if err := uc.riders.Update(ctx, rider); err != nil {
+		switch err.Error() {
+		case ErrRiderPhoneTaken.Error():
+			return ErrRiderPhoneTaken
+		case ErrRiderNotFound.Error():
+			return ErrRiderNotFound
+		}
+		uc.logger.Error("failed to update rider", zap.Error(err), zap.String("id", rider.ID))
+		return errors.New("failed to update rider")
+	}
+	return nil
+}
+
+// validateRider checks the normalized profile fields
+func validateRider(rider *domain.Rider) error {
+	if rider.FirstName == "" || rider.LastName == "" {
+		return ErrNameRequired
+	}
+	if err := validatePhone(rider.Phone); err != nil {
+		return err
+	}
+	if rider.Email != "" {
+		if err := validateEmail(rider.Email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFavoriteID returns a short random ID for a favorite location
+func newFavoriteID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate favorite ID: %w", err)
+	}
+	return "fav_" + hex.EncodeToString(b), nil
+}
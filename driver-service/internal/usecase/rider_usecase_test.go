@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockRiderRepository keeps riders in memory and enforces unique phones
+type mockRiderRepository struct {
+	riders map[string]*domain.Rider
+	nextID int
+}
+
+func newMockRiderRepository() *mockRiderRepository {
+	return &mockRiderRepository{riders: make(map[string]*domain.Rider)}
+}
+
+func (m *mockRiderRepository) phoneTaken(rider *domain.Rider) bool {
+	for id, stored := range m.riders {
+		if id != rider.ID && stored.Phone == rider.Phone {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mockRiderRepository) Create(ctx interface{}, rider *domain.Rider) error {
+	if m.phoneTaken(rider) {
+		return errors.New("phone is already registered to another rider")
+	}
+	m.nextID++
+	rider.ID = fmt.Sprintf("rider-%d", m.nextID)
+	copied := *rider
+	m.riders[rider.ID] = &copied
+	return nil
+}
+
+func (m *mockRiderRepository) Update(ctx interface{}, rider *domain.Rider) error {
+	if _, ok := m.riders[rider.ID]; !ok {
+		return errors.New("rider not found")
+	}
+	if m.phoneTaken(rider) {
+		return errors.New("phone is already registered to another rider")
+	}
+	copied := *rider
+	copied.FavoriteLocations = append([]domain.FavoriteLocation(nil), rider.FavoriteLocations...)
+	m.riders[rider.ID] = &copied
+	return nil
+}
+
+func (m *mockRiderRepository) GetByID(ctx interface{}, id string) (*domain.Rider, error) {
+	stored, ok := m.riders[id]
+	if !ok {
+		return nil, errors.New("rider not found")
+	}
+	copied := *stored
+	copied.FavoriteLocations = append([]domain.FavoriteLocation(nil), stored.FavoriteLocations...)
+	return &copied, nil
+}
+
+func (m *mockRiderRepository) GetByPhone(ctx interface{}, phone string) (*domain.Rider, error) {
+	for _, stored := range m.riders {
+		if stored.Phone == phone {
+			return m.GetByID(ctx, stored.ID)
+		}
+	}
+	return nil, errors.New("rider not found")
+}
+
+func TestRiderUseCase_RegisterRider(t *testing.T) {
+	repo := newMockRiderRepository()
+	uc := NewRiderUseCase(repo, zap.NewNop())
+	ctx := context.Background()
+
+	rider, err := uc.RegisterRider(ctx, &RegisterRiderRequest{
+		FirstName: " Elif ",
+		LastName:  "Yılmaz",
+		Phone:     "+90 555 123 45 67",
+		Email:     "Elif@Example.com",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rider.FirstName != "Elif" || rider.Phone != "+905551234567" || rider.Email != "elif@example.com" {
+		t.Errorf("expected normalized profile, got %+v", rider)
+	}
+
+	tests := []struct {
+		name    string
+		req     RegisterRiderRequest
+		wantErr error
+	}{
+		{name: "phone taken", req: RegisterRiderRequest{FirstName: "Ayşe", LastName: "Demir", Phone: "+905551234567"}, wantErr: ErrRiderPhoneTaken},
+		{name: "invalid phone", req: RegisterRiderRequest{FirstName: "Ayşe", LastName: "Demir", Phone: "0555"}, wantErr: ErrInvalidPhone},
+		{name: "invalid email", req: RegisterRiderRequest{FirstName: "Ayşe", LastName: "Demir", Phone: "+905557654321", Email: "ayse"}, wantErr: ErrInvalidEmail},
+		{name: "blank name", req: RegisterRiderRequest{FirstName: " ", LastName: "Demir", Phone: "+905557654321"}, wantErr: ErrNameRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.RegisterRider(ctx, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRiderUseCase_UpdateRider(t *testing.T) {
+	repo := newMockRiderRepository()
+	uc := NewRiderUseCase(repo, zap.NewNop())
+	ctx := context.Background()
+
+	elif, _ := uc.RegisterRider(ctx, &RegisterRiderRequest{FirstName: "Elif", LastName: "Yılmaz", Phone: "+905551234567"})
+	uc.RegisterRider(ctx, &RegisterRiderRequest{FirstName: "Ayşe", LastName: "Demir", Phone: "+905557654321"})
+
+	lastName := "Kaya"
+	rider, err := uc.UpdateRider(ctx, elif.ID, &UpdateRiderRequest{LastName: &lastName})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rider.LastName != "Kaya" || rider.FirstName != "Elif" {
+		t.Errorf("expected only the last name to change, got %+v", rider)
+	}
+
+	phone := "+905557654321"
+	if _, err := uc.UpdateRider(ctx, elif.ID, &UpdateRiderRequest{Phone: &phone}); !errors.Is(err, ErrRiderPhoneTaken) {
+		t.Errorf("expected ErrRiderPhoneTaken, got %v", err)
+	}
+	if _, err := uc.UpdateRider(ctx, "missing", &UpdateRiderRequest{LastName: &lastName}); !errors.Is(err, ErrRiderNotFound) {
+		t.Errorf("expected ErrRiderNotFound, got %v", err)
+	}
+}
+
+func TestRiderUseCase_FavoriteLocations(t *testing.T) {
+	repo := newMockRiderRepository()
+	uc := NewRiderUseCase(repo, zap.NewNop())
+	ctx := context.Background()
+
+	elif, _ := uc.RegisterRider(ctx, &RegisterRiderRequest{FirstName: "Elif", LastName: "Yılmaz", Phone: "+905551234567"})
+	home := &FavoriteLocationRequest{Label: "Home", Location: domain.Location{Lat: 40.9862, Lon: 29.0254}}
+
+	rider, err := uc.AddFavoriteLocation(ctx, elif.ID, home)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rider.FavoriteLocations) != 1 || rider.FavoriteLocations[0].ID == "" {
+		t.Fatalf("expected one saved place with an ID, got %+v", rider.FavoriteLocations)
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		if _, err := uc.AddFavoriteLocation(ctx, elif.ID, &FavoriteLocationRequest{Label: " "}); !errors.Is(err, ErrFavoriteLabelRequired) {
+			t.Errorf("expected ErrFavoriteLabelRequired, got %v", err)
+		}
+		if _, err := uc.AddFavoriteLocation(ctx, elif.ID, &FavoriteLocationRequest{Label: "Work", Location: domain.Location{Lat: 91}}); err == nil {
+			t.Error("expected an error for invalid coordinates")
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		stored := repo.riders[elif.ID]
+		for len(stored.FavoriteLocations) < domain.MaxFavoriteLocations {
+			stored.FavoriteLocations = append(stored.FavoriteLocations, domain.FavoriteLocation{ID: fmt.Sprintf("fav_%d", len(stored.FavoriteLocations))})
+		}
+		if _, err := uc.AddFavoriteLocation(ctx, elif.ID, home); !errors.Is(err, ErrTooManyFavorites) {
+			t.Errorf("expected ErrTooManyFavorites, got %v", err)
+		}
+		stored.FavoriteLocations = stored.FavoriteLocations[:1]
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		favID := rider.FavoriteLocations[0].ID
+		rider, err := uc.RemoveFavoriteLocation(ctx, elif.ID, favID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rider.FavoriteLocations) != 0 {
+			t.Errorf("expected no saved places, got %+v", rider.FavoriteLocations)
+		}
+		if _, err := uc.RemoveFavoriteLocation(ctx, elif.ID, favID); !errors.Is(err, ErrFavoriteNotFound) {
+			t.Errorf("expected ErrFavoriteNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Limits of planned shifts and calendar queries
+const (
+	maxShiftDuration      = 24 * time.Hour
+	defaultCalendarWindow = 7 * 24 * time.Hour
+	maxCalendarWindow     = 92 * 24 * time.Hour
+)
+
+// ScheduleUseCase defines the interface for shifts planned ahead
+type ScheduleUseCase interface {
+	CreateShift(ctx context.Context, driverID string, req *ScheduleShiftRequest) (*domain.ScheduledShift, error)
+	GetShift(ctx context.Context, id string) (*domain.ScheduledShift, error)
+	UpdateShift(ctx context.Context, id string, req *ScheduleShiftRequest) (*domain.ScheduledShift, error)
+	DeleteShift(ctx context.Context, id string) error
+	// Calendar returns the shifts overlapping a time range by start time
+	Calendar(ctx context.Context, filter domain.ScheduleFilter) (*ShiftCalendar, error)
+	// ApplyDueShifts puts drivers on shift whose shift started and off shift
+	// whose shift ended, and returns how many shifts it started and ended
+	ApplyDueShifts(ctx context.Context) (started, ended int, err error)
+}
+
+// ScheduleShiftRequest represents the request to plan or move a shift
+type ScheduleShiftRequest struct {
+	StartsAt time.Time `json:"startsAt" example:"2025-12-08T06:00:00Z" binding:"required"`
+	EndsAt   time.Time `json:"endsAt" example:"2025-12-08T14:00:00Z" binding:"required"`
+	Note     string    `json:"note,omitempty" example:"Airport rank"`
+}
+
+// ShiftCalendar lists the shifts overlapping [From, To), by start time
+type ShiftCalendar struct {
+	From   time.Time                `json:"from" example:"2025-12-08T00:00:00Z"`
+	To     time.Time                `json:"to" example:"2025-12-15T00:00:00Z"`
+	Count  int                      `json:"count" example:"1"`
+	Shifts []*domain.ScheduledShift `json:"shifts"`
+}
+
+// availabilitySetter puts drivers on and off shift with the checks of the driver use case
+type availabilitySetter interface {
+	SetAvailability(ctx context.Context, id string, available bool) (*domain.Driver, error)
+}
+
+// scheduleUseCase implements ScheduleUseCase
+type scheduleUseCase struct {
+	repo       domain.ScheduleRepository
+	driverRepo domain.DriverRepository
+	drivers    availabilitySetter
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewScheduleUseCase creates a new schedule use case. Drivers are put on and
+// off shift through drivers, so the checks of going on shift by hand apply.
+func NewScheduleUseCase(repo domain.ScheduleRepository, driverRepo domain.DriverRepository, drivers DriverUseCase, logger *zap.Logger) ScheduleUseCase {
+	return &scheduleUseCase{
+		repo:       repo,
+		driverRepo: driverRepo,
+		drivers:    drivers,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// CreateShift plans a shift for the driver. It may not overlap another shift of the driver.
+func (uc *scheduleUseCase) CreateShift(ctx context.Context, driverID string, req *ScheduleShiftRequest) (*domain.ScheduledShift, error) {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+	if err := authorizeDriver(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	now := uc.now().UTC()
+	shift := &domain.ScheduledShift{
+		DriverID:  driver.ID,
+		FleetID:   driver.FleetID,
+		StartsAt:  req.StartsAt.UTC(),
+		EndsAt:    req.EndsAt.UTC(),
+		Note:      strings.TrimSpace(req.Note),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := checkShiftTimes(shift, now); err != nil {
+		return nil, err
+	}
+	if err := uc.checkOverlap(ctx, shift); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Create(ctx, shift); err != nil {
+		uc.logger.Error("failed to create scheduled shift", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to create shift")
+	}
+
+	uc.logger.Info("shift scheduled", append(actorFields(ctx),
+		zap.String("id", shift.ID),
+		zap.String("driverId", shift.DriverID),
+		zap.Time("startsAt", shift.StartsAt),
+		zap.Time("endsAt", shift.EndsAt),
+	)...)
+	return shift, nil
+}
+
+// GetShift returns a shift of a driver the caller may act on
+func (uc *scheduleUseCase) GetShift(ctx context.Context, id string) (*domain.ScheduledShift, error) {
+	shift, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == ErrShiftNotFound.Error() {
+			return nil, ErrShiftNotFound
+		}
+		uc.logger.Error("failed to get scheduled shift", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get shift")
+	}
+	if err := uc.authorizeShift(ctx, shift); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// UpdateShift moves a shift. Once the driver was put on shift only the end
+// can move; ended shifts cannot change.
+func (uc *scheduleUseCase) UpdateShift(ctx context.Context, id string, req *ScheduleShiftRequest) (*domain.ScheduledShift, error) {
+	shift, err := uc.GetShift(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now().UTC()
+	if shift.EndedAt != nil || !shift.EndsAt.After(now) {
+		return nil, ErrShiftEnded
+	}
+	if shift.Started() && !req.StartsAt.Equal(shift.StartsAt) {
+		return nil, ErrShiftStarted
+	}
+	shift.StartsAt = req.StartsAt.UTC()
+	shift.EndsAt = req.EndsAt.UTC()
+	shift.Note = strings.TrimSpace(req.Note)
+	shift.UpdatedAt = now
+	if err := checkShiftTimes(shift, now); err != nil {
+		return nil, err
+	}
+	if err := uc.checkOverlap(ctx, shift); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Update(ctx, shift); err != nil {
+		if err.Error() == ErrShiftNotFound.Error() {
+			// The scheduler ended it meanwhile
+			return nil, ErrShiftEnded
+		}
+		uc.logger.Error("failed to update scheduled shift", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to update shift")
+	}
+
+	uc.logger.Info("shift rescheduled", append(actorFields(ctx),
+		zap.String("id", id),
+		zap.String("driverId", shift.DriverID),
+		zap.Time("startsAt", shift.StartsAt),
+		zap.Time("endsAt", shift.EndsAt),
+	)...)
+	return shift, nil
+}
+
+// DeleteShift cancels a shift. A running shift cannot be deleted, since the
+// driver would stay on shift; its end can be moved instead.
+func (uc *scheduleUseCase) DeleteShift(ctx context.Context, id string) error {
+	shift, err := uc.GetShift(ctx, id)
+	if err != nil {
+		return err
+	}
+	if shift.Started() && shift.StartError == "" && shift.EndedAt == nil {
+		return ErrShiftStarted
+	}
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		if err.Error() == ErrShiftNotFound.Error() {
+			return ErrShiftNotFound
+		}
+		uc.logger.Error("failed to delete scheduled shift", zap.Error(err), zap.String("id", id))
+		return errors.New("failed to delete shift")
+	}
+
+	uc.logger.Info("shift cancelled", append(actorFields(ctx), zap.String("id", id), zap.String("driverId", shift.DriverID))...)
+	return nil
+}
+
+// Calendar returns the shifts overlapping the filter's range, which defaults to
+// the week from now. Fleet admins only see their own fleet's drivers.
+func (uc *scheduleUseCase) Calendar(ctx context.Context, filter domain.ScheduleFilter) (*ShiftCalendar, error) {
+	if filter.From.IsZero() {
+		filter.From = uc.now().UTC()
+	}
+	if filter.To.IsZero() {
+		filter.To = filter.From.Add(defaultCalendarWindow)
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, ErrInvalidStatsRange
+	}
+	if filter.To.Sub(filter.From) > maxCalendarWindow {
+		return nil, ErrCalendarRangeTooLong
+	}
+
+	if filter.DriverID != "" {
+		driver, err := uc.driverRepo.GetByID(ctx, filter.DriverID)
+		if err != nil {
+			return nil, errors.New("driver not found")
+		}
+		if err := authorizeDriver(ctx, driver); err != nil {
+			return nil, err
+		}
+	} else if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleFleetAdmin {
+		filter.FleetID = identity.TenantID
+	}
+
+	shifts, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		uc.logger.Error("failed to list scheduled shifts", zap.Error(err))
+		return nil, errors.New("failed to list shifts")
+	}
+	if shifts == nil {
+		shifts = []*domain.ScheduledShift{}
+	}
+	return &ShiftCalendar{From: filter.From, To: filter.To, Count: len(shifts), Shifts: shifts}, nil
+}
+
+// ApplyDueShifts ends due shifts before starting due ones, so a driver with
+// back-to-back shifts ends up on shift
+func (uc *scheduleUseCase) ApplyDueShifts(ctx context.Context) (started, ended int, err error) {
+	for ctx.Err() == nil {
+		shift, err := uc.repo.ClaimEnd(ctx, uc.now().UTC())
+		if err != nil {
+			return started, ended, err
+		}
+		if shift == nil {
+			break
+		}
+		if _, err := uc.drivers.SetAvailability(ctx, shift.DriverID, false); err != nil {
+			uc.logger.Warn("failed to take driver off scheduled shift", zap.Error(err),
+				zap.String("id", shift.ID), zap.String("driverId", shift.DriverID))
+			continue
+		}
+		ended++
+	}
+
+	for ctx.Err() == nil {
+		shift, err := uc.repo.ClaimStart(ctx, uc.now().UTC())
+		if err != nil {
+			return started, ended, err
+		}
+		if shift == nil {
+			break
+		}
+		if _, err := uc.drivers.SetAvailability(ctx, shift.DriverID, true); err != nil {
+			// The end of a shift that never started is not applied
+			uc.logger.Warn("failed to put driver on scheduled shift", zap.Error(err),
+				zap.String("id", shift.ID), zap.String("driverId", shift.DriverID))
+			if err := uc.repo.SetStartError(ctx, shift.ID, err.Error()); err != nil {
+				uc.logger.Error("failed to record scheduled shift start error", zap.Error(err), zap.String("id", shift.ID))
+			}
+			continue
+		}
+		started++
+	}
+	return started, ended, ctx.Err()
+}
+
+// authorizeShift checks the caller may act on the driver of the shift
+func (uc *scheduleUseCase) authorizeShift(ctx context.Context, shift *domain.ScheduledShift) error {
+	if _, ok := domain.IdentityFromContext(ctx); !ok {
+		return nil
+	}
+	driver, err := uc.driverRepo.GetByID(ctx, shift.DriverID)
+	if err != nil {
+		// Shifts of deleted drivers are only reachable without an identity
+		return ErrShiftNotFound
+	}
+	return authorizeDriver(ctx, driver)
+}
+
+// checkOverlap rejects a shift overlapping another shift of the same driver
+func (uc *scheduleUseCase) checkOverlap(ctx context.Context, shift *domain.ScheduledShift) error {
+	others, err := uc.repo.List(ctx, domain.ScheduleFilter{DriverID: shift.DriverID, From: shift.StartsAt, To: shift.EndsAt})
+	if err != nil {
+		uc.logger.Error("failed to check scheduled shift overlap", zap.Error(err), zap.String("driverId", shift.DriverID))
+		return errors.New("failed to check shift overlap")
+	}
+	for _, other := range others {
+		if other.ID != shift.ID {
+			return fmt.Errorf("%w (%s to %s)", ErrShiftConflict,
+				other.StartsAt.Format(time.RFC3339), other.EndsAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// checkShiftTimes validates the times of a shift planned or moved at now
+func checkShiftTimes(shift *domain.ScheduledShift, now time.Time) error {
+	if !shift.EndsAt.After(shift.StartsAt) {
+		return ErrInvalidShiftTimes
+	}
+	if shift.EndsAt.Sub(shift.StartsAt) > maxShiftDuration {
+		return ErrShiftTooLong
+	}
+	if !shift.EndsAt.After(now) {
+		return ErrShiftInPast
+	}
+	return nil
+}
+
+// WriteScheduleICS writes shifts as an iCalendar (RFC 5545) feed that calendar
+// apps can import or subscribe to
+func WriteScheduleICS(w io.Writer, name string, shifts []*domain.ScheduledShift) error {
+	out := bufio.NewWriter(w)
+	line := func(content string) {
+		// Lines are folded at 75 octets, counting the space continuation lines
+		// start with, without splitting UTF-8 sequences
+		for limit := 75; len(content) > limit; limit = 74 {
+			cut := limit
+			for cut > 0 && content[cut]&0xC0 == 0x80 {
+				cut--
+			}
+			out.WriteString(content[:cut] + "\r\n ")
+			content = content[cut:]
+		}
+		out.WriteString(content + "\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//BiTaksi//Driver Schedule//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + icsText(name))
+	for _, shift := range shifts {
+		line("BEGIN:VEVENT")
+		line("UID:" + shift.ID + "@driver-service.bitaksi")
+		line("DTSTAMP:" + icsTime(shift.UpdatedAt))
+		line("DTSTART:" + icsTime(shift.StartsAt))
+		line("DTEND:" + icsTime(shift.EndsAt))
+		line("SUMMARY:" + icsText("Shift of driver "+shift.DriverID))
+		if shift.Note != "" {
+			line("DESCRIPTION:" + icsText(shift.Note))
+		}
+		line("STATUS:CONFIRMED")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return out.Flush()
+}
+
+// icsTime formats a time as an iCalendar UTC date-time
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes an iCalendar TEXT value
+var icsText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace
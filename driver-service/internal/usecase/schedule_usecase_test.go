@@ -0,0 +1,309 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockScheduleRepository is an in-memory ScheduleRepository
+type mockScheduleRepository struct {
+	shifts []*domain.ScheduledShift
+}
+
+func (m *mockScheduleRepository) Create(ctx interface{}, shift *domain.ScheduledShift) error {
+	shift.ID = "shift-" + strconv.Itoa(len(m.shifts)+1)
+	copied := *shift
+	m.shifts = append(m.shifts, &copied)
+	return nil
+}
+
+func (m *mockScheduleRepository) find(id string) (int, *domain.ScheduledShift) {
+	for i, shift := range m.shifts {
+		if shift.ID == id {
+			return i, shift
+		}
+	}
+	return -1, nil
+}
+
+func (m *mockScheduleRepository) GetByID(ctx interface{}, id string) (*domain.ScheduledShift, error) {
+	_, shift := m.find(id)
+	if shift == nil {
+		return nil, errors.New("shift not found")
+	}
+	copied := *shift
+	return &copied, nil
+}
+
+func (m *mockScheduleRepository) Update(ctx interface{}, shift *domain.ScheduledShift) error {
+	_, stored := m.find(shift.ID)
+	if stored == nil || stored.EndedAt != nil {
+		return errors.New("shift not found")
+	}
+	stored.StartsAt, stored.EndsAt, stored.Note, stored.UpdatedAt = shift.StartsAt, shift.EndsAt, shift.Note, shift.UpdatedAt
+	return nil
+}
+
+func (m *mockScheduleRepository) Delete(ctx interface{}, id string) error {
+	i, _ := m.find(id)
+	if i < 0 {
+		return errors.New("shift not found")
+	}
+	m.shifts = append(m.shifts[:i], m.shifts[i+1:]...)
+	return nil
+}
+
+func (m *mockScheduleRepository) List(ctx interface{}, filter domain.ScheduleFilter) ([]*domain.ScheduledShift, error) {
+	var shifts []*domain.ScheduledShift
+	for _, shift := range m.shifts {
+		if (filter.DriverID == "" || shift.DriverID == filter.DriverID) &&
+			(filter.FleetID == "" || shift.FleetID == filter.FleetID) &&
+			(filter.To.IsZero() || shift.StartsAt.Before(filter.To)) &&
+			(filter.From.IsZero() || shift.EndsAt.After(filter.From)) {
+			copied := *shift
+			shifts = append(shifts, &copied)
+		}
+	}
+	sort.Slice(shifts, func(i, j int) bool { return shifts[i].StartsAt.Before(shifts[j].StartsAt) })
+	return shifts, nil
+}
+
+func (m *mockScheduleRepository) ClaimStart(ctx interface{}, now time.Time) (*domain.ScheduledShift, error) {
+	for _, shift := range m.shifts {
+		if !shift.StartsAt.After(now) && shift.EndsAt.After(now) && shift.StartedAt == nil {
+			shift.StartedAt = &now
+			copied := *shift
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockScheduleRepository) ClaimEnd(ctx interface{}, now time.Time) (*domain.ScheduledShift, error) {
+	for _, shift := range m.shifts {
+		if !shift.EndsAt.After(now) && shift.StartedAt != nil && shift.StartError == "" && shift.EndedAt == nil {
+			shift.EndedAt = &now
+			copied := *shift
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockScheduleRepository) SetStartError(ctx interface{}, id, reason string) error {
+	if _, shift := m.find(id); shift != nil {
+		shift.StartError = reason
+	}
+	return nil
+}
+
+func newTestScheduleUseCase() (*scheduleUseCase, *mockScheduleRepository, *mockDriverRepository, *time.Time) {
+	driverRepo := newMockDriverRepository()
+	driverRepo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", FleetID: "fleet-1", PhoneVerified: true}
+	driverRepo.drivers["driver-2"] = &domain.Driver{ID: "driver-2", FleetID: "fleet-2"}
+	repo := &mockScheduleRepository{}
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	uc := NewScheduleUseCase(repo, driverRepo, NewDriverUseCase(driverRepo, zap.NewNop()), zap.NewNop()).(*scheduleUseCase)
+	uc.now = func() time.Time { return now }
+	return uc, repo, driverRepo, &now
+}
+
+func TestScheduleUseCase_CreateShift(t *testing.T) {
+	uc, _, _, now := newTestScheduleUseCase()
+	ctx := context.Background()
+	base := *now
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	shift, err := uc.CreateShift(ctx, "driver-1", &ScheduleShiftRequest{StartsAt: at(20), EndsAt: at(28), Note: " Airport "})
+	if err != nil {
+		t.Fatalf("CreateShift() error = %v", err)
+	}
+	if shift.ID == "" || shift.FleetID != "fleet-1" || shift.Note != "Airport" {
+		t.Errorf("CreateShift() = %+v", shift)
+	}
+
+	tests := []struct {
+		name     string
+		driverID string
+		req      ScheduleShiftRequest
+		wantErr  error
+	}{
+		{"ends before start", "driver-1", ScheduleShiftRequest{StartsAt: at(40), EndsAt: at(40)}, ErrInvalidShiftTimes},
+		{"too long", "driver-1", ScheduleShiftRequest{StartsAt: at(40), EndsAt: at(65)}, ErrShiftTooLong},
+		{"in the past", "driver-1", ScheduleShiftRequest{StartsAt: at(-8), EndsAt: at(-1)}, ErrShiftInPast},
+		{"overlaps", "driver-1", ScheduleShiftRequest{StartsAt: at(27), EndsAt: at(30)}, ErrShiftConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateShift(ctx, tt.driverID, &tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateShift() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Back-to-back shifts and shifts of other drivers do not overlap
+	if _, err := uc.CreateShift(ctx, "driver-1", &ScheduleShiftRequest{StartsAt: at(28), EndsAt: at(30)}); err != nil {
+		t.Errorf("CreateShift() back to back error = %v", err)
+	}
+	if _, err := uc.CreateShift(ctx, "driver-2", &ScheduleShiftRequest{StartsAt: at(20), EndsAt: at(28)}); err != nil {
+		t.Errorf("CreateShift() for another driver error = %v", err)
+	}
+
+	fleetAdmin := domain.ContextWithIdentity(ctx, domain.Identity{Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	if _, err := uc.CreateShift(fleetAdmin, "driver-2", &ScheduleShiftRequest{StartsAt: at(40), EndsAt: at(48)}); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("CreateShift() for another fleet error = %v, want ErrDriverNotInFleet", err)
+	}
+}
+
+func TestScheduleUseCase_Calendar(t *testing.T) {
+	uc, _, _, now := newTestScheduleUseCase()
+	ctx := context.Background()
+	base := *now
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	for _, s := range []struct {
+		driverID   string
+		start, end int
+	}{{"driver-1", 2, 10}, {"driver-2", 3, 11}, {"driver-1", 200, 208}} {
+		if _, err := uc.CreateShift(ctx, s.driverID, &ScheduleShiftRequest{StartsAt: at(s.start), EndsAt: at(s.end)}); err != nil {
+			t.Fatalf("CreateShift() error = %v", err)
+		}
+	}
+
+	calendar, err := uc.Calendar(ctx, domain.ScheduleFilter{})
+	if err != nil {
+		t.Fatalf("Calendar() error = %v", err)
+	}
+	if calendar.Count != 2 || !calendar.From.Equal(*now) || !calendar.To.Equal(at(7*24)) {
+		t.Errorf("Calendar() = %+v, want this week's 2 shifts", calendar)
+	}
+
+	fleetAdmin := domain.ContextWithIdentity(ctx, domain.Identity{Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	calendar, err = uc.Calendar(fleetAdmin, domain.ScheduleFilter{To: at(30 * 24)})
+	if err != nil {
+		t.Fatalf("Calendar() error = %v", err)
+	}
+	if calendar.Count != 2 || calendar.Shifts[0].DriverID != "driver-1" || calendar.Shifts[1].DriverID != "driver-1" {
+		t.Errorf("Calendar() for fleet admin = %+v, want driver-1's 2 shifts", calendar.Shifts)
+	}
+	if _, err := uc.Calendar(fleetAdmin, domain.ScheduleFilter{DriverID: "driver-2"}); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("Calendar() of another fleet's driver error = %v, want ErrDriverNotInFleet", err)
+	}
+
+	if _, err := uc.Calendar(ctx, domain.ScheduleFilter{From: at(5), To: at(1)}); !errors.Is(err, ErrInvalidStatsRange) {
+		t.Errorf("Calendar() reversed range error = %v, want ErrInvalidStatsRange", err)
+	}
+	if _, err := uc.Calendar(ctx, domain.ScheduleFilter{To: at(93 * 24)}); !errors.Is(err, ErrCalendarRangeTooLong) {
+		t.Errorf("Calendar() long range error = %v, want ErrCalendarRangeTooLong", err)
+	}
+}
+
+func TestScheduleUseCase_ApplyDueShifts(t *testing.T) {
+	uc, repo, driverRepo, now := newTestScheduleUseCase()
+	ctx := context.Background()
+	base := *now
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	first, _ := uc.CreateShift(ctx, "driver-1", &ScheduleShiftRequest{StartsAt: at(1), EndsAt: at(9)})
+	second, _ := uc.CreateShift(ctx, "driver-1", &ScheduleShiftRequest{StartsAt: at(9), EndsAt: at(17)})
+	unverified, _ := uc.CreateShift(ctx, "driver-2", &ScheduleShiftRequest{StartsAt: at(1), EndsAt: at(9)})
+
+	if started, ended, err := uc.ApplyDueShifts(ctx); err != nil || started != 0 || ended != 0 {
+		t.Errorf("ApplyDueShifts() before start = %d, %d, %v", started, ended, err)
+	}
+
+	*now = at(1)
+	started, ended, err := uc.ApplyDueShifts(ctx)
+	if err != nil || started != 1 || ended != 0 {
+		t.Errorf("ApplyDueShifts() at start = %d, %d, %v, want 1 started", started, ended, err)
+	}
+	if !driverRepo.drivers["driver-1"].Available || driverRepo.drivers["driver-2"].Available {
+		t.Error("only the verified driver should be on shift")
+	}
+	if _, shift := repo.find(unverified.ID); shift.StartError != ErrContactNotVerified.Error() {
+		t.Errorf("StartError = %q", shift.StartError)
+	}
+
+	// A running shift can only have its end moved, and cannot be deleted
+	if _, err := uc.UpdateShift(ctx, first.ID, &ScheduleShiftRequest{StartsAt: at(1), EndsAt: at(10)}); !errors.Is(err, ErrShiftConflict) {
+		t.Errorf("UpdateShift() into the next shift error = %v, want ErrShiftConflict", err)
+	}
+	if _, err := uc.UpdateShift(ctx, first.ID, &ScheduleShiftRequest{StartsAt: at(0), EndsAt: at(9)}); !errors.Is(err, ErrShiftStarted) {
+		t.Errorf("UpdateShift() moving the start error = %v, want ErrShiftStarted", err)
+	}
+	if err := uc.DeleteShift(ctx, first.ID); !errors.Is(err, ErrShiftStarted) {
+		t.Errorf("DeleteShift() of a running shift error = %v, want ErrShiftStarted", err)
+	}
+	// A shift that failed to start can be deleted
+	if err := uc.DeleteShift(ctx, unverified.ID); err != nil {
+		t.Errorf("DeleteShift() of a failed shift error = %v", err)
+	}
+
+	// Back-to-back shifts keep the driver on shift
+	*now = at(9)
+	if started, ended, err = uc.ApplyDueShifts(ctx); err != nil || started != 1 || ended != 1 {
+		t.Errorf("ApplyDueShifts() between shifts = %d, %d, %v, want 1 started, 1 ended", started, ended, err)
+	}
+	if !driverRepo.drivers["driver-1"].Available {
+		t.Error("driver should stay on shift")
+	}
+	if _, err := uc.UpdateShift(ctx, first.ID, &ScheduleShiftRequest{StartsAt: first.StartsAt, EndsAt: at(9)}); !errors.Is(err, ErrShiftEnded) {
+		t.Errorf("UpdateShift() of an ended shift error = %v, want ErrShiftEnded", err)
+	}
+
+	*now = at(17)
+	if started, ended, err = uc.ApplyDueShifts(ctx); err != nil || started != 0 || ended != 1 {
+		t.Errorf("ApplyDueShifts() at end = %d, %d, %v, want 1 ended", started, ended, err)
+	}
+	if driverRepo.drivers["driver-1"].Available {
+		t.Error("driver should be off shift")
+	}
+	if _, shift := repo.find(second.ID); shift.EndedAt == nil {
+		t.Error("second shift should have ended")
+	}
+}
+
+func TestWriteScheduleICS(t *testing.T) {
+	start := time.Date(2025, 12, 8, 6, 0, 0, 0, time.UTC)
+	shifts := []*domain.ScheduledShift{{
+		ID:        "shift-1",
+		DriverID:  "driver-1",
+		StartsAt:  start,
+		EndsAt:    start.Add(8 * time.Hour),
+		Note:      "Airport; gate 3, then the city centre and back to the airport rank for the night",
+		UpdatedAt: start.Add(-48 * time.Hour),
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteScheduleICS(&buf, "Fleet 1", shifts); err != nil {
+		t.Fatalf("WriteScheduleICS() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Fleet 1\r\n",
+		"UID:shift-1@driver-service.bitaksi\r\n",
+		"DTSTAMP:20251206T060000Z\r\n",
+		"DTSTART:20251208T060000Z\r\nDTEND:20251208T140000Z\r\n",
+		"DESCRIPTION:Airport\\; gate 3\\, then the city centre and back to the airport\r\n  rank for the night\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteScheduleICS() missing %q in\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/cache"
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// maxStatsRange bounds the aggregation window to keep pipelines cheap
+const maxStatsRange = 366 * 24 * time.Hour
+
+// Number of drivers a location anomaly report lists by default and at most
+const (
+	defaultAnomalyLimit = 50
+	maxAnomalyLimit     = 1000
+)
+
+// StatsUseCase defines the interface for driver statistics
+type StatsUseCase interface {
+	GetDriverStats(ctx context.Context, driverID string, from, to *time.Time) (*domain.DriverStats, error)
+	// GetLocationAnomalies lists the drivers with the most location anomalies
+	GetLocationAnomalies(ctx context.Context, from, to *time.Time, limit int) (*domain.LocationAnomalyReport, error)
+}
+
+// statsUseCase implements StatsUseCase with a short-lived cache in front of the aggregations
+type statsUseCase struct {
+	driverRepo domain.DriverRepository
+	statsRepo  domain.StatsRepository
+	cache      *cache.TTL[string, *domain.DriverStats]
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewStatsUseCase creates a new stats use case; results are cached for cacheTTL
+func NewStatsUseCase(driverRepo domain.DriverRepository, statsRepo domain.StatsRepository, cacheTTL time.Duration, logger *zap.Logger) StatsUseCase {
+	return &statsUseCase{
+		driverRepo: driverRepo,
+		statsRepo:  statsRepo,
+		cache:      cache.NewTTL[string, *domain.DriverStats](cacheTTL, 10000),
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// GetDriverStats aggregates the driver's activity between from and to. The range
+// defaults to the last 30 days; an open end is rounded to the minute so repeated
+// requests hit the cache.
+func (uc *statsUseCase) GetDriverStats(ctx context.Context, driverID string, from, to *time.Time) (*domain.DriverStats, error) {
+	start, end, err := uc.statsRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	key := driverID + "|" + start.Format(time.RFC3339) + "|" + end.Format(time.RFC3339)
+	if stats, ok := uc.cache.Get(key); ok {
+		return stats, nil
+	}
+
+	if _, err := uc.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	stats, err := uc.statsRepo.DriverStats(ctx, driverID, start, end)
+	if err != nil {
+		uc.logger.Error("failed to aggregate driver stats", zap.Error(err), zap.String("id", driverID))
+		return nil, errors.New("failed to get driver stats")
+	}
+
+	uc.cache.Set(key, stats)
+	return stats, nil
+}
+
+// GetLocationAnomalies counts the location anomalies of each driver between
+// from and to, which default to the last 30 days, most anomalies first
+func (uc *statsUseCase) GetLocationAnomalies(ctx context.Context, from, to *time.Time, limit int) (*domain.LocationAnomalyReport, error) {
+	start, end, err := uc.statsRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = defaultAnomalyLimit
+	}
+	if limit < 1 || limit > maxAnomalyLimit {
+		return nil, ErrInvalidAnomalyLimit
+	}
+
+	drivers, err := uc.statsRepo.LocationAnomalies(ctx, start, end, limit)
+	if err != nil {
+		uc.logger.Error("failed to aggregate location anomalies", zap.Error(err))
+		return nil, errors.New("failed to get location anomalies")
+	}
+	return &domain.LocationAnomalyReport{From: start, To: end, Drivers: drivers}, nil
+}
+
+// statsRange resolves a statistics range; it defaults to the 30 days up to now,
+// rounded to the minute so repeated requests hit the cache
+func (uc *statsUseCase) statsRange(from, to *time.Time) (start, end time.Time, err error) {
+	end = uc.now().UTC().Truncate(time.Minute)
+	if to != nil {
+		end = to.UTC()
+	}
+	start = end.Add(-30 * 24 * time.Hour)
+	if from != nil {
+		start = from.UTC()
+	}
+	if !start.Before(end) {
+		return start, end, ErrInvalidStatsRange
+	}
+	if end.Sub(start) > maxStatsRange {
+		return start, end, ErrStatsRangeTooLong
+	}
+	return start, end, nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockActivityRepository records activity calls and serves canned statistics
+type mockActivityRepository struct {
+	locations   []domain.Location
+	openShifts  map[string]bool
+	statsCalls  int
+	lastFrom    time.Time
+	lastTo      time.Time
+	shouldFail  bool
+	onlineHours float64
+	anomalies   []*domain.LocationAnomaly
+}
+
+func newMockActivityRepository() *mockActivityRepository {
+	return &mockActivityRepository{openShifts: make(map[string]bool)}
+}
+
+func (m *mockActivityRepository) RecordLocation(ctx interface{}, driverID string, location domain.Location, at time.Time) error {
+	m.locations = append(m.locations, location)
+	return nil
+}
+
+func (m *mockActivityRepository) StartShift(ctx interface{}, driverID string, at time.Time) error {
+	m.openShifts[driverID] = true
+	return nil
+}
+
+func (m *mockActivityRepository) EndShift(ctx interface{}, driverID string, at time.Time) error {
+	delete(m.openShifts, driverID)
+	return nil
+}
+
+func (m *mockActivityRepository) DriverStats(ctx interface{}, driverID string, from, to time.Time) (*domain.DriverStats, error) {
+	m.statsCalls++
+	m.lastFrom, m.lastTo = from, to
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	return &domain.DriverStats{DriverID: driverID, From: from, To: to, OnlineHours: m.onlineHours}, nil
+}
+
+func (m *mockActivityRepository) RecordLocationAnomaly(ctx interface{}, anomaly *domain.LocationAnomaly) error {
+	m.anomalies = append(m.anomalies, anomaly)
+	return nil
+}
+
+func (m *mockActivityRepository) LocationAnomalies(ctx interface{}, from, to time.Time, limit int) ([]*domain.LocationAnomalyCount, error) {
+	m.lastFrom, m.lastTo = from, to
+	counts := []*domain.LocationAnomalyCount{}
+	for _, anomaly := range m.anomalies {
+		if len(counts) == 0 || counts[len(counts)-1].DriverID != anomaly.DriverID {
+			counts = append(counts, &domain.LocationAnomalyCount{DriverID: anomaly.DriverID})
+		}
+		counts[len(counts)-1].Count++
+	}
+	if len(counts) > limit {
+		counts = counts[:limit]
+	}
+	return counts, nil
+}
+
+func TestStatsUseCase_GetDriverStats(t *testing.T) {
+	now := time.Date(2025, 12, 1, 10, 30, 45, 0, time.UTC)
+	newUseCase := func(activity *mockActivityRepository) *statsUseCase {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1"}
+		uc := NewStatsUseCase(repo, activity, time.Minute, zap.NewNop()).(*statsUseCase)
+		uc.now = func() time.Time { return now }
+		return uc
+	}
+	ctx := context.Background()
+
+	t.Run("defaults to the last 30 days and caches", func(t *testing.T) {
+		activity := newMockActivityRepository()
+		activity.onlineHours = 12.5
+		uc := newUseCase(activity)
+
+		stats, err := uc.GetDriverStats(ctx, "driver-1", nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wantTo := now.Truncate(time.Minute)
+		if !activity.lastTo.Equal(wantTo) || !activity.lastFrom.Equal(wantTo.Add(-30*24*time.Hour)) {
+			t.Errorf("unexpected range %v - %v", activity.lastFrom, activity.lastTo)
+		}
+		if stats.OnlineHours != 12.5 {
+			t.Errorf("expected 12.5 online hours, got %v", stats.OnlineHours)
+		}
+
+		if _, err := uc.GetDriverStats(ctx, "driver-1", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if activity.statsCalls != 1 {
+			t.Errorf("expected cached result, aggregation ran %d times", activity.statsCalls)
+		}
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		uc := newUseCase(newMockActivityRepository())
+		from := now
+		to := now.Add(-time.Hour)
+		if _, err := uc.GetDriverStats(ctx, "driver-1", &from, &to); !errors.Is(err, ErrInvalidStatsRange) {
+			t.Errorf("expected ErrInvalidStatsRange, got %v", err)
+		}
+		from = now.Add(-400 * 24 * time.Hour)
+		if _, err := uc.GetDriverStats(ctx, "driver-1", &from, nil); !errors.Is(err, ErrStatsRangeTooLong) {
+			t.Errorf("expected ErrStatsRangeTooLong, got %v", err)
+		}
+	})
+
+	t.Run("driver not found", func(t *testing.T) {
+		uc := newUseCase(newMockActivityRepository())
+		if _, err := uc.GetDriverStats(ctx, "missing", nil, nil); err == nil || err.Error() != "driver not found" {
+			t.Errorf("expected driver not found, got %v", err)
+		}
+	})
+
+	t.Run("aggregation failure is not cached", func(t *testing.T) {
+		activity := newMockActivityRepository()
+		activity.shouldFail = true
+		uc := newUseCase(activity)
+		if _, err := uc.GetDriverStats(ctx, "driver-1", nil, nil); err == nil {
+			t.Fatal("expected error")
+		}
+		activity.shouldFail = false
+		if _, err := uc.GetDriverStats(ctx, "driver-1", nil, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDriverUseCase_ActivityRecording(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true}
+	activity := newMockActivityRepository()
+	uc := NewDriverUseCase(repo, zap.NewNop(), WithActivityRecording(activity))
+	ctx := context.Background()
+
+	if _, err := uc.SetAvailability(ctx, "driver-1", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !activity.openShifts["driver-1"] {
+		t.Error("expected a shift to be started")
+	}
+
+	lat, lon := 41.0431, 29.0099
+	if _, err := uc.UpdateDriver(ctx, "driver-1", &UpdateDriverRequest{Lat: &lat, Lon: &lon}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(activity.locations) != 1 {
+		t.Errorf("expected one recorded location, got %d", len(activity.locations))
+	}
+
+	if _, err := uc.SetAvailability(ctx, "driver-1", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if activity.openShifts["driver-1"] {
+		t.Error("expected the shift to be ended")
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// StreamUseCase defines the interface for streaming drivers to data pipelines
+type StreamUseCase interface {
+	// StreamDrivers calls fn with every driver matching the filter whose ID is
+	// after afterID, in ID order. Errors fn returns are passed back unchanged.
+	StreamDrivers(ctx context.Context, filter domain.DriverFilter, afterID string, fn func(*domain.Driver) error) error
+}
+
+// streamUseCase implements StreamUseCase
+type streamUseCase struct {
+	repo   domain.DriverStreamRepository
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewStreamUseCase creates a new stream use case
+func NewStreamUseCase(repo domain.DriverStreamRepository, logger *zap.Logger) StreamUseCase {
+	return &streamUseCase{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// StreamDrivers streams the drivers and audits how many were read
+func (uc *streamUseCase) StreamDrivers(ctx context.Context, filter domain.DriverFilter, afterID string, fn func(*domain.Driver) error) error {
+	start := uc.now()
+	streamed := 0
+	err := uc.repo.StreamDrivers(ctx, filter, afterID, func(driver *domain.Driver) error {
+		if err := fn(driver); err != nil {
+			return err
+		}
+		streamed++
+		return nil
+	})
+
+	if err != nil && streamed == 0 && err.Error() == "invalid driver ID" {
+		return ErrInvalidStreamPosition
+	}
+
+	uc.logger.Info("audit: drivers streamed", append(actorFields(ctx),
+		zap.String("fleetId", filter.FleetID),
+		zap.String("afterId", afterID),
+		zap.Int("streamed", streamed),
+		zap.Duration("took", uc.now().Sub(start)),
+		zap.Bool("complete", err == nil),
+	)...)
+	if err != nil && ctx.Err() == nil {
+		uc.logger.Warn("driver stream ended early", zap.Error(err), zap.Int("streamed", streamed))
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockStreamRepository streams drivers kept in memory in ID order
+type mockStreamRepository struct {
+	drivers []*domain.Driver
+}
+
+func (m *mockStreamRepository) StreamDrivers(ctx interface{}, filter domain.DriverFilter, afterID string, fn func(*domain.Driver) error) error {
+	if afterID == "invalid" {
+		return errors.New("invalid driver ID")
+	}
+	for _, driver := range m.drivers {
+		if driver.ID <= afterID || (filter.FleetID != "" && driver.FleetID != filter.FleetID) {
+			continue
+		}
+		if err := fn(driver); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestStreamUseCase_StreamDrivers(t *testing.T) {
+	repo := &mockStreamRepository{drivers: []*domain.Driver{
+		{ID: "d1", FleetID: "fleet-1"},
+		{ID: "d2", FleetID: "fleet-2"},
+		{ID: "d3", FleetID: "fleet-1"},
+	}}
+	uc := NewStreamUseCase(repo, zap.NewNop())
+
+	var ids []string
+	collect := func(driver *domain.Driver) error {
+		ids = append(ids, driver.ID)
+		return nil
+	}
+	if err := uc.StreamDrivers(context.Background(), domain.DriverFilter{FleetID: "fleet-1"}, "", collect); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "d1" || ids[1] != "d3" {
+		t.Errorf("expected d1 and d3, got %v", ids)
+	}
+
+	ids = nil
+	if err := uc.StreamDrivers(context.Background(), domain.DriverFilter{}, "d2", collect); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "d3" {
+		t.Errorf("expected to resume at d3, got %v", ids)
+	}
+
+	if err := uc.StreamDrivers(context.Background(), domain.DriverFilter{}, "invalid", collect); !errors.Is(err, ErrInvalidStreamPosition) {
+		t.Errorf("expected ErrInvalidStreamPosition, got %v", err)
+	}
+
+	// Errors of the writer, such as a client that went away, stop the stream
+	gone := errors.New("client went away")
+	calls := 0
+	err := uc.StreamDrivers(context.Background(), domain.DriverFilter{}, "", func(*domain.Driver) error {
+		calls++
+		return gone
+	})
+	if !errors.Is(err, gone) || calls != 1 {
+		t.Errorf("expected the stream to stop with the writer's error after 1 driver, got %v after %d", err, calls)
+	}
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// SyncUseCase defines the interface for delta syncs of drivers to mobile apps
+type SyncUseCase interface {
+	DriverChanges(ctx context.Context, since string, filter domain.DriverFilter, limit int) (*DriverChangesResponse, error)
+}
+
+// DriverChangesResponse lists the drivers created, updated and deleted since a sync marker
+type DriverChangesResponse struct {
+	Created []*domain.Driver `json:"created"`
+	Updated []*domain.Driver `json:"updated"`
+	Deleted []string         `json:"deleted" example:"507f1f77bcf86cd799439012"`
+	// NextToken is passed as since on the next request, both to fetch the
+	// rest of a large window and for the next sync once HasMore is false
+	NextToken string `json:"nextToken" example:"eyJzIjoiMjAyNS0xMi0wNlQwMTowMDowMFoiLCJ1IjoiMjAyNS0xMi0wNlQwMTowMTozMFoiLCJpIjoiNTA3ZjFmNzdiY2Y4NmNkNzk5NDM5MDExIn0"`
+	HasMore   bool   `json:"hasMore" example:"false"`
+}
+
+// Change page sizes used when a sync request does not choose one, and the largest allowed
+const (
+	defaultChangeLimit = 100
+	maxChangeLimit     = 500
+)
+
+// changeFeedLag holds back the newest changes. Writers stamp updatedAt before
+// their write commits, so a change stamped just before a sync can become
+// visible after it; reading only up to now minus the lag keeps such a change
+// ahead of the client's marker instead of behind it.
+const changeFeedLag = 2 * time.Second
+
+// syncToken is the decoded continuation token. Since is the start of the sync
+// window, which decides whether a changed driver counts as created; UpdatedAt
+// and ID are the position in the change feed to continue after.
+type syncToken struct {
+	Since     time.Time `json:"s"`
+	UpdatedAt time.Time `json:"u"`
+	ID        string    `json:"i,omitempty"`
+}
+
+// syncUseCase implements SyncUseCase
+type syncUseCase struct {
+	repo   domain.DriverChangeRepository
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewSyncUseCase creates a new sync use case
+func NewSyncUseCase(repo domain.DriverChangeRepository, logger *zap.Logger) SyncUseCase {
+	return &syncUseCase{
+		repo:   repo,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// DriverChanges lists the drivers changed since the marker, which is an RFC3339
+// timestamp, a nextToken from an earlier response, or empty for a full sync
+func (uc *syncUseCase) DriverChanges(ctx context.Context, since string, filter domain.DriverFilter, limit int) (*DriverChangesResponse, error) {
+	token, err := parseSyncMarker(since)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = defaultChangeLimit
+	}
+	if limit > maxChangeLimit {
+		limit = maxChangeLimit
+	}
+
+	until := uc.now().UTC().Add(-changeFeedLag)
+	after := domain.ChangePosition{UpdatedAt: token.UpdatedAt, ID: token.ID}
+	drivers, err := uc.repo.ListChanges(ctx, filter, after, until, limit+1)
+	if err != nil {
+		if err.Error() == "invalid driver ID" {
+			return nil, ErrInvalidSyncMarker
+		}
+		uc.logger.Error("failed to list driver changes", zap.Error(err))
+		return nil, errors.New("failed to list driver changes")
+	}
+
+	resp := &DriverChangesResponse{
+		Created: []*domain.Driver{},
+		Updated: []*domain.Driver{},
+		Deleted: []string{},
+		HasMore: len(drivers) > limit,
+	}
+	if resp.HasMore {
+		drivers = drivers[:limit]
+	}
+	for _, driver := range drivers {
+		switch {
+		case driver.DeletedAt != nil:
+			resp.Deleted = append(resp.Deleted, driver.ID)
+		case driver.CreatedAt.After(token.Since) || driver.CreatedAt.Equal(token.Since):
+			resp.Created = append(resp.Created, driver)
+		default:
+			resp.Updated = append(resp.Updated, driver)
+		}
+	}
+
+	// A finished window continues from until, which the next window starts at;
+	// an unfinished one continues after its last driver
+	next := syncToken{Since: until, UpdatedAt: until}
+	if resp.HasMore {
+		last := drivers[len(drivers)-1]
+		next = syncToken{Since: token.Since, UpdatedAt: last.UpdatedAt, ID: last.ID}
+	}
+	resp.NextToken = next.encode()
+	return resp, nil
+}
+
+// parseSyncMarker decodes a since value into the position to list changes after
+func parseSyncMarker(since string) (syncToken, error) {
+	if since == "" {
+		return syncToken{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return syncToken{Since: t, UpdatedAt: t}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil {
+		return syncToken{}, ErrInvalidSyncMarker
+	}
+	var token syncToken
+	if err := json.Unmarshal(raw, &token); err != nil || token.UpdatedAt.IsZero() {
+		return syncToken{}, ErrInvalidSyncMarker
+	}
+	return token, nil
+}
+
+// encode returns the opaque form of the token handed to clients
+func (t syncToken) encode() string {
+	raw, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
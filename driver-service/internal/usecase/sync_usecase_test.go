@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockChangeRepository serves the change feed from drivers kept in memory
+type mockChangeRepository struct {
+	drivers []*domain.Driver
+}
+
+func (m *mockChangeRepository) ListChanges(ctx interface{}, filter domain.DriverFilter, after domain.ChangePosition, until time.Time, limit int) ([]*domain.Driver, error) {
+	sorted := append([]*domain.Driver(nil), m.drivers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].UpdatedAt.Equal(sorted[j].UpdatedAt) {
+			return sorted[i].UpdatedAt.Before(sorted[j].UpdatedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var changes []*domain.Driver
+	for _, driver := range sorted {
+		if filter.FleetID != "" && driver.FleetID != filter.FleetID {
+			continue
+		}
+		if driver.UpdatedAt.Before(after.UpdatedAt) || !driver.UpdatedAt.Before(until) {
+			continue
+		}
+		if after.ID != "" && driver.UpdatedAt.Equal(after.UpdatedAt) && driver.ID <= after.ID {
+			continue
+		}
+		if len(changes) == limit {
+			break
+		}
+		changes = append(changes, driver)
+	}
+	return changes, nil
+}
+
+func changeIDs(drivers []*domain.Driver) []string {
+	ids := make([]string, len(drivers))
+	for i, driver := range drivers {
+		ids[i] = driver.ID
+	}
+	return ids
+}
+
+func TestSyncUseCase_DriverChanges(t *testing.T) {
+	base := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	deletedAt := at(30)
+
+	repo := &mockChangeRepository{drivers: []*domain.Driver{
+		{ID: "d1", CreatedAt: at(-60), UpdatedAt: at(-30)},
+		{ID: "d2", CreatedAt: at(-60), UpdatedAt: at(10)},
+		{ID: "d3", CreatedAt: at(20), UpdatedAt: at(20)},
+		{ID: "d4", CreatedAt: at(-60), UpdatedAt: at(30), DeletedAt: &deletedAt},
+		{ID: "d5", CreatedAt: at(30), UpdatedAt: at(30)},
+	}}
+	uc := NewSyncUseCase(repo, zap.NewNop()).(*syncUseCase)
+	uc.now = func() time.Time { return at(60) }
+	ctx := context.Background()
+
+	t.Run("changes since a timestamp", func(t *testing.T) {
+		resp, err := uc.DriverChanges(ctx, base.Format(time.RFC3339), domain.DriverFilter{}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := fmt.Sprint(changeIDs(resp.Created), changeIDs(resp.Updated), resp.Deleted); got != "[d3 d5] [d2] [d4]" {
+			t.Errorf("expected created [d3 d5], updated [d2], deleted [d4], got %s", got)
+		}
+		if resp.HasMore {
+			t.Error("expected the window to be complete")
+		}
+
+		// Nothing changed since, so the next sync is empty
+		next, err := uc.DriverChanges(ctx, resp.NextToken, domain.DriverFilter{}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(next.Created)+len(next.Updated)+len(next.Deleted) != 0 {
+			t.Errorf("expected no changes, got %+v", next)
+		}
+	})
+
+	t.Run("large windows continue with the token", func(t *testing.T) {
+		var created, updated, deleted []string
+		since := base.Format(time.RFC3339)
+		for page := 0; ; page++ {
+			if page == 5 {
+				t.Fatal("sync did not finish")
+			}
+			resp, err := uc.DriverChanges(ctx, since, domain.DriverFilter{}, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			created = append(created, changeIDs(resp.Created)...)
+			updated = append(updated, changeIDs(resp.Updated)...)
+			deleted = append(deleted, resp.Deleted...)
+			since = resp.NextToken
+			if !resp.HasMore {
+				break
+			}
+		}
+		// d5 shares d4's updatedAt and is still classified against the window start
+		if got := fmt.Sprint(created, updated, deleted); got != "[d3 d5] [d2] [d4]" {
+			t.Errorf("expected the same changes as one page, got %s", got)
+		}
+	})
+
+	t.Run("newest changes are held back", func(t *testing.T) {
+		uc.now = func() time.Time { return at(30) }
+		defer func() { uc.now = func() time.Time { return at(60) } }()
+
+		resp, err := uc.DriverChanges(ctx, base.Format(time.RFC3339), domain.DriverFilter{}, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(resp.Deleted) != 0 || len(resp.Created) != 1 {
+			t.Errorf("expected only d3 before the lag, got %+v", resp)
+		}
+	})
+
+	t.Run("invalid marker", func(t *testing.T) {
+		for _, since := range []string{"yesterday", "bm90LWpzb24"} {
+			if _, err := uc.DriverChanges(ctx, since, domain.DriverFilter{}, 0); !errors.Is(err, ErrInvalidSyncMarker) {
+				t.Errorf("%q: expected ErrInvalidSyncMarker, got %v", since, err)
+			}
+		}
+	})
+}
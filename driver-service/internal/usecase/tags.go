@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+)
+
+// normalizeTags lowercases, sorts and deduplicates tags. Nil stays nil so an
+// update can tell a missing list from an empty one.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, ok := domain.NormalizeTag(tag)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be 1-32 lowercase letters, digits or dashes", ErrInvalidTags, tag)
+		}
+		if !seen[t] {
+			seen[t] = true
+			normalized = append(normalized, t)
+		}
+	}
+	if len(normalized) > domain.MaxDriverTags {
+		return nil, fmt.Errorf("%w: a driver can have at most %d tags", ErrInvalidTags, domain.MaxDriverTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// normalizeAttributes lowercases attribute keys and trims their values
+func normalizeAttributes(attributes map[string]string) (map[string]string, error) {
+	if attributes == nil {
+		return nil, nil
+	}
+	if len(attributes) > domain.MaxDriverAttributes {
+		return nil, fmt.Errorf("%w: a driver can have at most %d attributes", ErrInvalidAttributes, domain.MaxDriverAttributes)
+	}
+	normalized := make(map[string]string, len(attributes))
+	for key, value := range attributes {
+		k, ok := domain.NormalizeTag(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: key %q must be 1-32 lowercase letters, digits or dashes", ErrInvalidAttributes, key)
+		}
+		if _, dup := normalized[k]; dup {
+			return nil, fmt.Errorf("%w: key %q is given twice", ErrInvalidAttributes, k)
+		}
+		v := strings.TrimSpace(value)
+		if v == "" || utf8.RuneCountInString(v) > domain.MaxAttributeValueLength {
+			return nil, fmt.Errorf("%w: value of %q must be 1-%d characters", ErrInvalidAttributes, k, domain.MaxAttributeValueLength)
+		}
+		normalized[k] = v
+	}
+	return normalized, nil
+}
@@ -0,0 +1,492 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/matching"
+	"github.com/bitaksi/driver-service/internal/routing"
+	"go.uber.org/zap"
+)
+
+// TripUseCase defines the interface for trip requests and driver matching
+type TripUseCase interface {
+	RequestTrip(ctx context.Context, req *CreateTripRequest) (*domain.Trip, error)
+	GetTrip(ctx context.Context, id string) (*domain.Trip, error)
+	AcceptOffer(ctx context.Context, tripID, driverID string) (*domain.Trip, error)
+	DeclineOffer(ctx context.Context, tripID, driverID string) (*domain.Trip, error)
+	CompleteTrip(ctx context.Context, tripID string, req *CompleteTripRequest) (*domain.Trip, error)
+	CancelTrip(ctx context.Context, tripID string) (*domain.Trip, error)
+	ExpireOffers(ctx context.Context) (int, error)
+	EstimateFare(ctx context.Context, req *EstimateFareRequest) (*domain.FareEstimate, error)
+}
+
+// CreateTripRequest represents a rider's request for a taxi
+type CreateTripRequest struct {
+	RiderID  string           `json:"riderId,omitempty" example:"rider-42"`
+	Pickup   domain.Location  `json:"pickup"`
+	Dropoff  *domain.Location `json:"dropoff,omitempty"`
+	TaxiType *domain.TaxiType `json:"taxiType,omitempty" example:"sari"`
+}
+
+// TripOfferRequest identifies the driver answering a trip offer
+type TripOfferRequest struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011" binding:"required"`
+}
+
+// CompleteTripRequest represents the request to finish a trip
+type CompleteTripRequest struct {
+	DistanceKm float64  `json:"distanceKm,omitempty" example:"7.4"`
+	Rating     *float64 `json:"rating,omitempty" example:"5"`
+	// Fare is the metered fare; when omitted the distance is priced with the tariff
+	Fare *float64 `json:"fare,omitempty" example:"232.4"`
+}
+
+// MatchingOptions holds the dispatch settings
+type MatchingOptions struct {
+	SearchRadiusKm float64
+	OfferTimeout   time.Duration
+	// MaxOffers is the number of drivers a trip is offered to before giving up
+	MaxOffers int
+}
+
+// expiredOfferBatchSize limits how many expired offers are handled per sweep
+const expiredOfferBatchSize = 100
+
+// tripUseCase implements TripUseCase
+type tripUseCase struct {
+	tripRepo   domain.TripRepository
+	driverRepo domain.DriverRepository
+	strategy   matching.Strategy
+	opts       MatchingOptions
+	logger     *zap.Logger
+	now        func() time.Time
+	// riders is optional; when set, trips must reference a registered rider
+	riders domain.RiderRepository
+	router routing.Router
+	tariff FareTariff
+	// earnings is optional; when set, completed trips are credited to the driver
+	earnings EarningsUseCase
+}
+
+// TripUseCaseOption configures optional trip use case behaviour
+type TripUseCaseOption func(*tripUseCase)
+
+// WithRiders rejects trips whose riderId does not belong to a registered rider
+func WithRiders(riders domain.RiderRepository) TripUseCaseOption {
+	return func(uc *tripUseCase) {
+		uc.riders = riders
+	}
+}
+
+// WithFareEstimation measures pickup distances and trip routes with router and
+// quotes fares for trips requested with a dropoff
+func WithFareEstimation(router routing.Router, tariff FareTariff) TripUseCaseOption {
+	return func(uc *tripUseCase) {
+		uc.router = router
+		uc.tariff = tariff
+	}
+}
+
+// WithEarnings credits drivers with the fare of the trips they complete
+func WithEarnings(earnings EarningsUseCase) TripUseCaseOption {
+	return func(uc *tripUseCase) {
+		uc.earnings = earnings
+	}
+}
+
+// NewTripUseCase creates a new trip use case dispatching with the given strategy
+func NewTripUseCase(
+	tripRepo domain.TripRepository,
+	driverRepo domain.DriverRepository,
+	strategy matching.Strategy,
+	opts MatchingOptions,
+	logger *zap.Logger,
+	options ...TripUseCaseOption,
+) TripUseCase {
+	if opts.SearchRadiusKm <= 0 {
+		opts.SearchRadiusKm = 6
+	}
+	if opts.OfferTimeout <= 0 {
+		opts.OfferTimeout = 15 * time.Second
+	}
+	if opts.MaxOffers <= 0 {
+		opts.MaxOffers = 5
+	}
+	uc := &tripUseCase{
+		tripRepo:   tripRepo,
+		driverRepo: driverRepo,
+		strategy:   strategy,
+		opts:       opts,
+		logger:     logger,
+		now:        time.Now,
+		router:     routing.HaversineRouter{},
+	}
+	for _, option := range options {
+		option(uc)
+	}
+	return uc
+}
+
+// RequestTrip creates a trip and offers it to the first matching driver
+func (uc *tripUseCase) RequestTrip(ctx context.Context, req *CreateTripRequest) (*domain.Trip, error) {
+	if req.Pickup.Lat == 0 && req.Pickup.Lon == 0 {
+		return nil, ErrPickupRequired
+	}
+	if err := validateCoordinates(req.Pickup.Lat, req.Pickup.Lon); err != nil {
+		return nil, err
+	}
+	if req.Dropoff != nil {
+		if err := validateCoordinates(req.Dropoff.Lat, req.Dropoff.Lon); err != nil {
+			return nil, err
+		}
+	}
+	if req.TaxiType != nil && !req.TaxiType.IsValid() {
+		return nil, fmt.Errorf("invalid taxiType: %s", *req.TaxiType)
+	}
+	if identity, ok := domain.IdentityFromContext(ctx); ok && identity.Role == domain.RoleRider && req.RiderID != identity.UserID {
+		return nil, ErrRiderMismatch
+	}
+	if req.RiderID != "" && uc.riders != nil {
+		if _, err := uc.riders.GetByID(ctx, req.RiderID); err != nil {
+			if err.Error() == ErrRiderNotFound.Error() {
+				return nil, ErrRiderNotFound
+			}
+			uc.logger.Error("failed to look up rider", zap.Error(err), zap.String("riderId", req.RiderID))
+			return nil, errors.New("failed to create trip")
+		}
+	}
+
+	trip := &domain.Trip{
+		RiderID:           req.RiderID,
+		Pickup:            req.Pickup,
+		Dropoff:           req.Dropoff,
+		TaxiType:          req.TaxiType,
+		Status:            domain.TripStatusRequested,
+		Strategy:          uc.strategy.Name(),
+		DeclinedDriverIDs: []string{},
+	}
+	if req.Dropoff != nil {
+		// A missing quote does not stop the rider from getting a taxi
+		estimate, err := uc.estimateFare(ctx, req.Pickup, *req.Dropoff, req.TaxiType)
+		if err != nil {
+			uc.logger.Warn("failed to estimate trip fare", zap.Error(err))
+		}
+		trip.Estimate = estimate
+	}
+	if err := uc.tripRepo.Create(ctx, trip); err != nil {
+		uc.logger.Error("failed to create trip", zap.Error(err))
+		return nil, errors.New("failed to create trip")
+	}
+
+	if err := uc.dispatch(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("trip requested",
+		zap.String("tripId", trip.ID),
+		zap.String("status", string(trip.Status)),
+		zap.String("offeredDriverId", trip.OfferedDriverID),
+	)
+	return trip, nil
+}
+
+// GetTrip retrieves a trip by ID
+func (uc *tripUseCase) GetTrip(ctx context.Context, id string) (*domain.Trip, error) {
+	trip, err := uc.tripRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+	if err := authorizeTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+	return trip, nil
+}
+
+// AcceptOffer assigns the trip to the driver holding the active offer
+func (uc *tripUseCase) AcceptOffer(ctx context.Context, tripID, driverID string) (*domain.Trip, error) {
+	trip, err := uc.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+	if err := uc.authorizeTripDriver(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if !uc.hasActiveOffer(trip, driverID) {
+		return nil, ErrOfferNotActive
+	}
+
+	acceptedAt := uc.now()
+	trip.Status = domain.TripStatusAccepted
+	trip.DriverID = driverID
+	trip.OfferedDriverID = ""
+	trip.OfferExpiresAt = nil
+	trip.AcceptedAt = &acceptedAt
+	if err := uc.updateTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	// The driver is busy until the trip finishes
+	uc.setDriverAvailable(ctx, driverID, false)
+
+	uc.logger.Info("trip offer accepted", zap.String("tripId", tripID), zap.String("driverId", driverID))
+	return trip, nil
+}
+
+// DeclineOffer records the decline and re-offers the trip to the next driver
+func (uc *tripUseCase) DeclineOffer(ctx context.Context, tripID, driverID string) (*domain.Trip, error) {
+	trip, err := uc.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+	if err := uc.authorizeTripDriver(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if !uc.hasActiveOffer(trip, driverID) {
+		return nil, ErrOfferNotActive
+	}
+
+	trip.DeclinedDriverIDs = append(trip.DeclinedDriverIDs, driverID)
+	if err := uc.dispatch(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	uc.logger.Info("trip offer declined",
+		zap.String("tripId", tripID),
+		zap.String("driverId", driverID),
+		zap.String("nextDriverId", trip.OfferedDriverID),
+	)
+	return trip, nil
+}
+
+// CompleteTrip finishes an accepted trip and records the rider's rating
+func (uc *tripUseCase) CompleteTrip(ctx context.Context, tripID string, req *CompleteTripRequest) (*domain.Trip, error) {
+	if req.Rating != nil && (*req.Rating < 1 || *req.Rating > 5) {
+		return nil, ErrInvalidRating
+	}
+	if req.DistanceKm < 0 {
+		return nil, ErrInvalidDistance
+	}
+	if req.Fare != nil && *req.Fare < 0 {
+		return nil, ErrInvalidFare
+	}
+
+	trip, err := uc.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+	if err := uc.authorizeTripDriver(ctx, trip.DriverID); err != nil {
+		return nil, err
+	}
+	if trip.Status != domain.TripStatusAccepted {
+		return nil, ErrInvalidTripState
+	}
+
+	now := uc.now()
+	trip.Status = domain.TripStatusCompleted
+	trip.CompletedAt = &now
+	trip.DistanceKm = req.DistanceKm
+	trip.Rating = req.Rating
+	trip.Fare = uc.tripFare(trip, req)
+	if err := uc.updateTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+	if uc.earnings != nil {
+		// The trip stays completed; recording is idempotent per trip, so a failed
+		// entry can be recorded again without paying the driver twice
+		if err := uc.earnings.RecordTrip(ctx, trip); err != nil {
+			uc.logger.Error("failed to credit driver for completed trip", zap.Error(err), zap.String("tripId", tripID))
+		}
+	}
+
+	driver, err := uc.driverRepo.GetByID(ctx, trip.DriverID)
+	if err != nil {
+		uc.logger.Warn("completed trip references missing driver", zap.String("tripId", tripID), zap.String("driverId", trip.DriverID))
+		return trip, nil
+	}
+	if req.Rating != nil {
+		total := driver.Rating*float64(driver.RatingCount) + *req.Rating
+		driver.RatingCount++
+		driver.Rating = total / float64(driver.RatingCount)
+	}
+	driver.Available = true
+	if err := uc.driverRepo.Update(ctx, driver.ID, driver); err != nil {
+		uc.logger.Error("failed to update driver after trip", zap.Error(err), zap.String("driverId", driver.ID))
+	}
+
+	uc.logger.Info("trip completed", zap.String("tripId", tripID), zap.String("driverId", trip.DriverID))
+	return trip, nil
+}
+
+// authorizeTripDriver rejects fleet admins acting for a driver outside their
+// fleet, and driver devices acting for another driver. A driver that cannot be
+// loaded belongs to no fleet, so only callers without a fleet may act for it.
+func (uc *tripUseCase) authorizeTripDriver(ctx context.Context, driverID string) error {
+	if _, ok := domain.IdentityFromContext(ctx); !ok {
+		return nil
+	}
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		driver = &domain.Driver{ID: driverID}
+	}
+	return authorizeDriver(ctx, driver)
+}
+
+// CancelTrip cancels a trip that has not finished yet
+func (uc *tripUseCase) CancelTrip(ctx context.Context, tripID string) (*domain.Trip, error) {
+	trip, err := uc.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, ErrTripNotFound
+	}
+	if err := authorizeTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+	if trip.Status.IsFinal() {
+		return nil, ErrInvalidTripState
+	}
+
+	wasAccepted := trip.Status == domain.TripStatusAccepted
+	trip.Status = domain.TripStatusCancelled
+	trip.OfferedDriverID = ""
+	trip.OfferExpiresAt = nil
+	if err := uc.updateTrip(ctx, trip); err != nil {
+		return nil, err
+	}
+
+	if wasAccepted {
+		uc.setDriverAvailable(ctx, trip.DriverID, true)
+	}
+
+	uc.logger.Info("trip cancelled", append(actorFields(ctx), zap.String("tripId", tripID))...)
+	return trip, nil
+}
+
+// ExpireOffers treats offers that ran out of time as declined and re-offers those trips.
+// It returns the number of trips that were re-dispatched.
+func (uc *tripUseCase) ExpireOffers(ctx context.Context) (int, error) {
+	trips, err := uc.tripRepo.FindExpiredOffers(ctx, uc.now(), expiredOfferBatchSize)
+	if err != nil {
+		uc.logger.Error("failed to find expired offers", zap.Error(err))
+		return 0, errors.New("failed to find expired offers")
+	}
+
+	redispatched := 0
+	for _, trip := range trips {
+		expiredDriverID := trip.OfferedDriverID
+		trip.DeclinedDriverIDs = append(trip.DeclinedDriverIDs, expiredDriverID)
+		if err := uc.dispatch(ctx, trip); err != nil {
+			// A concurrent accept or decline already moved the trip on
+			if errors.Is(err, ErrTripConflict) {
+				continue
+			}
+			uc.logger.Error("failed to re-offer expired trip", zap.Error(err), zap.String("tripId", trip.ID))
+			continue
+		}
+		uc.logger.Info("trip offer expired",
+			zap.String("tripId", trip.ID),
+			zap.String("driverId", expiredDriverID),
+			zap.String("nextDriverId", trip.OfferedDriverID),
+		)
+		redispatched++
+	}
+	return redispatched, nil
+}
+
+// dispatch offers the trip to the driver chosen by the strategy, or marks it as
+// unmatched when no eligible driver is left or the offer limit is reached
+func (uc *tripUseCase) dispatch(ctx context.Context, trip *domain.Trip) error {
+	var candidates []matching.Candidate
+	if trip.OfferCount < uc.opts.MaxOffers {
+		var err error
+		candidates, err = uc.findCandidates(ctx, trip)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(candidates) == 0 {
+		trip.Status = domain.TripStatusNoDriver
+		trip.OfferedDriverID = ""
+		trip.OfferExpiresAt = nil
+		return uc.updateTrip(ctx, trip)
+	}
+
+	chosen := uc.strategy.Select(trip.Pickup, candidates)
+	expiresAt := uc.now().Add(uc.opts.OfferTimeout)
+	trip.Status = domain.TripStatusOffered
+	trip.OfferedDriverID = chosen.Driver.ID
+	trip.OfferExpiresAt = &expiresAt
+	trip.OfferCount++
+	return uc.updateTrip(ctx, trip)
+}
+
+// findCandidates returns available drivers near the pickup that have not declined the trip,
+// sorted by distance
+func (uc *tripUseCase) findCandidates(ctx context.Context, trip *domain.Trip) ([]matching.Candidate, error) {
+	drivers, err := uc.driverRepo.FindNearby(ctx, trip.Pickup.Lat, trip.Pickup.Lon, uc.opts.SearchRadiusKm, trip.TaxiType, domain.DriverFilter{}, domain.NearbyPage{})
+	if err != nil {
+		uc.logger.Error("failed to find drivers for trip", zap.Error(err), zap.String("tripId", trip.ID))
+		return nil, errors.New("failed to match trip")
+	}
+
+	eligible := make([]*domain.Driver, 0, len(drivers))
+	locations := make([]domain.Location, 0, len(drivers))
+	for _, driver := range drivers {
+		if !driver.Available || trip.HasDeclined(driver.ID) {
+			continue
+		}
+		eligible = append(eligible, driver)
+		locations = append(locations, driver.Location)
+	}
+	routes, err := uc.router.Routes(ctx, trip.Pickup, locations)
+	if err != nil {
+		uc.logger.Error("failed to route drivers for trip", zap.Error(err), zap.String("tripId", trip.ID))
+		return nil, errors.New("failed to match trip")
+	}
+
+	candidates := make([]matching.Candidate, len(eligible))
+	for i, driver := range eligible {
+		candidates[i] = matching.Candidate{Driver: driver, DistanceKm: routes[i].DistanceKm}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].DistanceKm < candidates[j].DistanceKm
+	})
+	return candidates, nil
+}
+
+// hasActiveOffer reports whether the trip is currently offered to the driver and the offer has not expired
+func (uc *tripUseCase) hasActiveOffer(trip *domain.Trip, driverID string) bool {
+	return trip.Status == domain.TripStatusOffered &&
+		trip.OfferedDriverID == driverID &&
+		trip.OfferExpiresAt != nil &&
+		uc.now().Before(*trip.OfferExpiresAt)
+}
+
+// updateTrip persists the trip, translating version conflicts
+func (uc *tripUseCase) updateTrip(ctx context.Context, trip *domain.Trip) error {
+	if err := uc.tripRepo.Update(ctx, trip); err != nil {
+		if err.Error() == "trip was modified concurrently" {
+			return ErrTripConflict
+		}
+		uc.logger.Error("failed to update trip", zap.Error(err), zap.String("tripId", trip.ID))
+		return errors.New("failed to update trip")
+	}
+	return nil
+}
+
+// setDriverAvailable updates the driver's availability, logging failures without failing the trip
+func (uc *tripUseCase) setDriverAvailable(ctx context.Context, driverID string, available bool) {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		uc.logger.Warn("trip references missing driver", zap.String("driverId", driverID))
+		return
+	}
+	driver.Available = available
+	if err := uc.driverRepo.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to update driver availability", zap.Error(err), zap.String("driverId", driverID))
+	}
+}
@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/matching"
+	"github.com/bitaksi/driver-service/internal/routing"
+	"go.uber.org/zap"
+)
+
+// mockTripRepository is a mock implementation of TripRepository
+type mockTripRepository struct {
+	trips  map[string]*domain.Trip
+	nextID int
+}
+
+func newMockTripRepository() *mockTripRepository {
+	return &mockTripRepository{trips: make(map[string]*domain.Trip)}
+}
+
+func (m *mockTripRepository) Create(ctx interface{}, trip *domain.Trip) error {
+	m.nextID++
+	trip.ID = fmt.Sprintf("trip-%d", m.nextID)
+	trip.Version = 1
+	stored := *trip
+	m.trips[trip.ID] = &stored
+	return nil
+}
+
+func (m *mockTripRepository) Update(ctx interface{}, trip *domain.Trip) error {
+	stored, exists := m.trips[trip.ID]
+	if !exists {
+		return errors.New("trip not found")
+	}
+	if stored.Version != trip.Version {
+		return errors.New("trip was modified concurrently")
+	}
+	trip.Version++
+	updated := *trip
+	updated.DeclinedDriverIDs = append([]string(nil), trip.DeclinedDriverIDs...)
+	m.trips[trip.ID] = &updated
+	return nil
+}
+
+func (m *mockTripRepository) GetByID(ctx interface{}, id string) (*domain.Trip, error) {
+	stored, exists := m.trips[id]
+	if !exists {
+		return nil, errors.New("trip not found")
+	}
+	trip := *stored
+	trip.DeclinedDriverIDs = append([]string(nil), stored.DeclinedDriverIDs...)
+	return &trip, nil
+}
+
+func (m *mockTripRepository) FindExpiredOffers(ctx interface{}, now time.Time, limit int) ([]*domain.Trip, error) {
+	var trips []*domain.Trip
+	for id, stored := range m.trips {
+		if stored.Status == domain.TripStatusOffered && stored.OfferExpiresAt != nil && !stored.OfferExpiresAt.After(now) {
+			trip, _ := m.GetByID(ctx, id)
+			trips = append(trips, trip)
+		}
+	}
+	return trips, nil
+}
+
+// newTestTripUseCase returns a trip use case with three available drivers at increasing distance
+func newTestTripUseCase(opts MatchingOptions) (*tripUseCase, *mockTripRepository, *mockDriverRepository) {
+	driverRepo := newMockDriverRepository()
+	for i, id := range []string{"near", "mid", "far"} {
+		driverRepo.drivers[id] = &domain.Driver{
+			ID:            id,
+			TaxiType:      domain.TaxiTypeSari,
+			Location:      domain.Location{Lat: 41.0431 + float64(i)*0.005, Lon: 29.0099},
+			PhoneVerified: true,
+			Available:     true,
+		}
+	}
+	tripRepo := newMockTripRepository()
+	uc := NewTripUseCase(tripRepo, driverRepo, matching.NearestStrategy{}, opts, zap.NewNop()).(*tripUseCase)
+	return uc, tripRepo, driverRepo
+}
+
+func TestTripUseCase_RequestTrip(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("offers the trip to the nearest available driver", func(t *testing.T) {
+		uc, _, driverRepo := newTestTripUseCase(MatchingOptions{})
+		driverRepo.drivers["near"].Available = false
+
+		trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: domain.Location{Lat: 41.0431, Lon: 29.0099}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trip.Status != domain.TripStatusOffered {
+			t.Errorf("expected status offered, got %s", trip.Status)
+		}
+		if trip.OfferedDriverID != "mid" {
+			t.Errorf("expected offer to mid, got %s", trip.OfferedDriverID)
+		}
+		if trip.OfferExpiresAt == nil || trip.OfferCount != 1 {
+			t.Errorf("expected an expiring first offer, got count %d", trip.OfferCount)
+		}
+		if trip.Strategy != matching.StrategyNearest {
+			t.Errorf("expected strategy to be recorded, got %s", trip.Strategy)
+		}
+	})
+
+	t.Run("no driver found", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		taxiType := domain.TaxiTypeSiyah
+
+		trip, err := uc.RequestTrip(ctx, &CreateTripRequest{
+			Pickup:   domain.Location{Lat: 41.0431, Lon: 29.0099},
+			TaxiType: &taxiType,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trip.Status != domain.TripStatusNoDriver {
+			t.Errorf("expected status %s, got %s", domain.TripStatusNoDriver, trip.Status)
+		}
+	})
+
+	t.Run("rider must be registered", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		riders := newMockRiderRepository()
+		riders.riders["rider-1"] = &domain.Rider{ID: "rider-1"}
+		WithRiders(riders)(uc)
+		pickup := domain.Location{Lat: 41.0431, Lon: 29.0099}
+
+		if _, err := uc.RequestTrip(ctx, &CreateTripRequest{RiderID: "rider-2", Pickup: pickup}); !errors.Is(err, ErrRiderNotFound) {
+			t.Errorf("expected ErrRiderNotFound, got %v", err)
+		}
+		trip, err := uc.RequestTrip(ctx, &CreateTripRequest{RiderID: "rider-1", Pickup: pickup})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trip.RiderID != "rider-1" {
+			t.Errorf("expected the trip to reference rider-1, got %q", trip.RiderID)
+		}
+	})
+
+	t.Run("pickup required", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+
+		_, err := uc.RequestTrip(ctx, &CreateTripRequest{})
+		if !errors.Is(err, ErrPickupRequired) {
+			t.Errorf("expected ErrPickupRequired, got %v", err)
+		}
+	})
+}
+
+func TestTripUseCase_DeclineAndExpire(t *testing.T) {
+	ctx := context.Background()
+	uc, tripRepo, _ := newTestTripUseCase(MatchingOptions{MaxOffers: 3, OfferTimeout: 10 * time.Second})
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: domain.Location{Lat: 41.0431, Lon: 29.0099}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the driver holding the offer can answer it
+	if _, err := uc.DeclineOffer(ctx, trip.ID, "mid"); !errors.Is(err, ErrOfferNotActive) {
+		t.Fatalf("expected ErrOfferNotActive, got %v", err)
+	}
+
+	trip, err = uc.DeclineOffer(ctx, trip.ID, "near")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trip.OfferedDriverID != "mid" {
+		t.Fatalf("expected re-offer to mid, got %s", trip.OfferedDriverID)
+	}
+
+	// The offer to mid times out and moves on to far
+	now = now.Add(11 * time.Second)
+	count, err := uc.ExpireOffers(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("expected one expired offer, got %d (%v)", count, err)
+	}
+	stored := tripRepo.trips[trip.ID]
+	if stored.OfferedDriverID != "far" {
+		t.Fatalf("expected re-offer to far, got %s", stored.OfferedDriverID)
+	}
+
+	// The offer limit is reached after the third driver
+	if _, err := uc.DeclineOffer(ctx, trip.ID, "far"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored = tripRepo.trips[trip.ID]
+	if stored.Status != domain.TripStatusNoDriver {
+		t.Errorf("expected status %s, got %s", domain.TripStatusNoDriver, stored.Status)
+	}
+	if len(stored.DeclinedDriverIDs) != 3 {
+		t.Errorf("expected 3 declined drivers, got %v", stored.DeclinedDriverIDs)
+	}
+}
+
+func TestTripUseCase_AcceptAndComplete(t *testing.T) {
+	ctx := context.Background()
+	uc, _, driverRepo := newTestTripUseCase(MatchingOptions{})
+	driverRepo.drivers["near"].Rating = 4
+	driverRepo.drivers["near"].RatingCount = 1
+
+	trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: domain.Location{Lat: 41.0431, Lon: 29.0099}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := uc.CompleteTrip(ctx, trip.ID, &CompleteTripRequest{}); !errors.Is(err, ErrInvalidTripState) {
+		t.Fatalf("expected ErrInvalidTripState before acceptance, got %v", err)
+	}
+
+	trip, err = uc.AcceptOffer(ctx, trip.ID, "near")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trip.Status != domain.TripStatusAccepted || trip.DriverID != "near" {
+		t.Fatalf("expected trip accepted by near, got %s/%s", trip.Status, trip.DriverID)
+	}
+	if trip.AcceptedAt == nil {
+		t.Error("expected the acceptance time to be recorded")
+	}
+	if driverRepo.drivers["near"].Available {
+		t.Error("expected driver to be unavailable during the trip")
+	}
+
+	rating := 5.0
+	if _, err := uc.CompleteTrip(ctx, trip.ID, &CompleteTripRequest{Rating: float64Ptr(9)}); !errors.Is(err, ErrInvalidRating) {
+		t.Fatalf("expected ErrInvalidRating, got %v", err)
+	}
+	trip, err = uc.CompleteTrip(ctx, trip.ID, &CompleteTripRequest{DistanceKm: 7.4, Rating: &rating})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trip.Status != domain.TripStatusCompleted || trip.CompletedAt == nil {
+		t.Errorf("expected completed trip, got %s", trip.Status)
+	}
+
+	driver := driverRepo.drivers["near"]
+	if !driver.Available {
+		t.Error("expected driver to be available after the trip")
+	}
+	if driver.RatingCount != 2 || driver.Rating != 4.5 {
+		t.Errorf("expected rating 4.5 over 2 trips, got %v over %d", driver.Rating, driver.RatingCount)
+	}
+}
+
+func TestTripUseCase_AcceptExpiredOffer(t *testing.T) {
+	ctx := context.Background()
+	uc, _, _ := newTestTripUseCase(MatchingOptions{OfferTimeout: 5 * time.Second})
+	now := time.Now()
+	uc.now = func() time.Time { return now }
+
+	trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: domain.Location{Lat: 41.0431, Lon: 29.0099}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(6 * time.Second)
+	if _, err := uc.AcceptOffer(ctx, trip.ID, "near"); !errors.Is(err, ErrOfferNotActive) {
+		t.Errorf("expected ErrOfferNotActive for expired offer, got %v", err)
+	}
+}
+
+func TestTripUseCase_RiderScope(t *testing.T) {
+	uc, tripRepo, _ := newTestTripUseCase(MatchingOptions{})
+	ctx := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "rider-1", Role: domain.RoleRider})
+	pickup := domain.Location{Lat: 41.0431, Lon: 29.0099}
+
+	if _, err := uc.RequestTrip(ctx, &CreateTripRequest{RiderID: "rider-2", Pickup: pickup}); !errors.Is(err, ErrRiderMismatch) {
+		t.Errorf("expected ErrRiderMismatch, got %v", err)
+	}
+	own, err := uc.RequestTrip(ctx, &CreateTripRequest{RiderID: "rider-1", Pickup: pickup})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.GetTrip(ctx, own.ID); err != nil {
+		t.Errorf("expected the rider to read their own trip, got %v", err)
+	}
+
+	tripRepo.trips["other"] = &domain.Trip{ID: "other", RiderID: "rider-2", Status: domain.TripStatusOffered}
+	if _, err := uc.GetTrip(ctx, "other"); !errors.Is(err, ErrTripNotOwned) {
+		t.Errorf("expected ErrTripNotOwned, got %v", err)
+	}
+	if _, err := uc.CancelTrip(ctx, "other"); !errors.Is(err, ErrTripNotOwned) {
+		t.Errorf("expected ErrTripNotOwned, got %v", err)
+	}
+	if tripRepo.trips["other"].Status != domain.TripStatusOffered {
+		t.Error("expected the other rider's trip to stay offered")
+	}
+}
+
+func TestTripUseCase_FleetScope(t *testing.T) {
+	uc, tripRepo, driverRepo := newTestTripUseCase(MatchingOptions{})
+	for _, id := range []string{"near", "mid", "far"} {
+		driverRepo.drivers[id].FleetID = "fleet-1"
+	}
+	otherFleet := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "admin-2", Role: domain.RoleFleetAdmin, TenantID: "fleet-2"})
+	ownFleet := domain.ContextWithIdentity(context.Background(), domain.Identity{UserID: "admin-1", Role: domain.RoleFleetAdmin, TenantID: "fleet-1"})
+	pickup := domain.Location{Lat: 41.0431, Lon: 29.0099}
+
+	trip, err := uc.RequestTrip(context.Background(), &CreateTripRequest{Pickup: pickup})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.AcceptOffer(otherFleet, trip.ID, "near"); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet accepting, got %v", err)
+	}
+	if _, err := uc.DeclineOffer(otherFleet, trip.ID, "near"); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet declining, got %v", err)
+	}
+	if stored := tripRepo.trips[trip.ID]; stored.Status != domain.TripStatusOffered || stored.OfferedDriverID != "near" {
+		t.Fatalf("expected the offer to stand, got %s to %s", stored.Status, stored.OfferedDriverID)
+	}
+
+	if _, err := uc.AcceptOffer(ownFleet, trip.ID, "near"); err != nil {
+		t.Fatalf("expected the driver's fleet admin to accept, got %v", err)
+	}
+	fare := 10000.0
+	if _, err := uc.CompleteTrip(otherFleet, trip.ID, &CompleteTripRequest{Fare: &fare, Rating: float64Ptr(1)}); !errors.Is(err, ErrDriverNotInFleet) {
+		t.Errorf("expected ErrDriverNotInFleet completing, got %v", err)
+	}
+	if stored := tripRepo.trips[trip.ID]; stored.Status != domain.TripStatusAccepted || stored.Fare != 0 {
+		t.Errorf("expected the trip to stay accepted without a fare, got %s", stored.Status)
+	}
+	if driverRepo.drivers["near"].RatingCount != 0 {
+		t.Error("expected the driver's rating to be left alone")
+	}
+	if _, err := uc.CompleteTrip(ownFleet, trip.ID, &CompleteTripRequest{}); err != nil {
+		t.Errorf("expected the driver's fleet admin to complete, got %v", err)
+	}
+}
+
+// fixedRouter returns the same route to every destination
+type fixedRouter struct {
+	route routing.Route
+}
+
+func (r fixedRouter) Name() string { return "fixed" }
+
+func (r fixedRouter) Routes(ctx context.Context, from domain.Location, to []domain.Location) ([]routing.Route, error) {
+	routes := make([]routing.Route, len(to))
+	for i := range to {
+		routes[i] = r.route
+	}
+	return routes, nil
+}
+
+func TestTripUseCase_EstimateFare(t *testing.T) {
+	ctx := context.Background()
+	tariff := FareTariff{
+		Base:        40,
+		PerKm:       20,
+		PerMinute:   1,
+		Minimum:     100,
+		Currency:    "TRY",
+		Multipliers: map[domain.TaxiType]float64{domain.TaxiTypeSiyah: 2},
+	}
+	pickup := domain.Location{Lat: 41.0370, Lon: 28.9850}
+	dropoff := domain.Location{Lat: 40.9903, Lon: 29.0290}
+
+	t.Run("prices the routed distance and duration", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		WithFareEstimation(fixedRouter{routing.Route{DistanceKm: 8.45, DurationSec: 900}}, tariff)(uc)
+		siyah := domain.TaxiTypeSiyah
+
+		estimate, err := uc.EstimateFare(ctx, &EstimateFareRequest{Pickup: pickup, Dropoff: dropoff})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if estimate.Amount != 224 || estimate.DurationSec != 900 || estimate.Provider != "fixed" || estimate.Currency != "TRY" {
+			t.Errorf("unexpected estimate %+v", estimate)
+		}
+		premium, err := uc.EstimateFare(ctx, &EstimateFareRequest{Pickup: pickup, Dropoff: dropoff, TaxiType: &siyah})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if premium.Amount != 448 {
+			t.Errorf("expected the siyah multiplier to apply, got %.2f", premium.Amount)
+		}
+	})
+
+	t.Run("short trips pay the minimum fare", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		WithFareEstimation(fixedRouter{routing.Route{DistanceKm: 0.5, DurationSec: 60}}, tariff)(uc)
+
+		estimate, err := uc.EstimateFare(ctx, &EstimateFareRequest{Pickup: pickup, Dropoff: dropoff})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if estimate.Amount != 100 {
+			t.Errorf("expected the minimum fare, got %.2f", estimate.Amount)
+		}
+	})
+
+	t.Run("dropoff required", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+
+		if _, err := uc.EstimateFare(ctx, &EstimateFareRequest{Pickup: pickup}); !errors.Is(err, ErrDropoffRequired) {
+			t.Errorf("expected ErrDropoffRequired, got %v", err)
+		}
+	})
+
+	t.Run("trips requested with a dropoff carry the estimate", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		WithFareEstimation(fixedRouter{routing.Route{DistanceKm: 8.45, DurationSec: 900}}, tariff)(uc)
+
+		trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: pickup, Dropoff: &dropoff})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if trip.Estimate == nil || trip.Estimate.Amount != 224 {
+			t.Errorf("expected the trip to carry the estimate, got %+v", trip.Estimate)
+		}
+	})
+}
+
+func TestTripUseCase_CompleteTripCreditsDriver(t *testing.T) {
+	ctx := context.Background()
+	tariff := FareTariff{Base: 40, PerKm: 20, Minimum: 100, Currency: "TRY"}
+
+	complete := func(t *testing.T, estimate *domain.FareEstimate, req *CompleteTripRequest) (*domain.Trip, *mockEarningsRepository) {
+		t.Helper()
+		uc, tripRepo, _ := newTestTripUseCase(MatchingOptions{})
+		earnings, repo, _ := newTestEarningsUseCase(time.Now())
+		uc.tariff = tariff
+		WithEarnings(earnings)(uc)
+
+		trip, err := uc.RequestTrip(ctx, &CreateTripRequest{Pickup: domain.Location{Lat: 41.0431, Lon: 29.0099}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tripRepo.trips[trip.ID].Estimate = estimate
+		if _, err := uc.AcceptOffer(ctx, trip.ID, "near"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		trip, err = uc.CompleteTrip(ctx, trip.ID, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return trip, repo
+	}
+
+	t.Run("metered fare", func(t *testing.T) {
+		fare := 187.456
+		trip, repo := complete(t, nil, &CompleteTripRequest{DistanceKm: 7.4, Fare: &fare})
+		if trip.Fare != 187.46 {
+			t.Errorf("expected the metered fare, got %.2f", trip.Fare)
+		}
+		if len(repo.earnings) != 1 || repo.earnings[0].DriverID != "near" || repo.earnings[0].Gross != 187.46 {
+			t.Errorf("expected the driver credited with the fare, got %+v", repo.earnings)
+		}
+	})
+
+	t.Run("distance priced with the tariff", func(t *testing.T) {
+		trip, _ := complete(t, nil, &CompleteTripRequest{DistanceKm: 7.4})
+		if trip.Fare != 188 {
+			t.Errorf("expected 40 + 7.4 km * 20, got %.2f", trip.Fare)
+		}
+	})
+
+	t.Run("quote without a distance", func(t *testing.T) {
+		trip, _ := complete(t, &domain.FareEstimate{DistanceKm: 8, Amount: 232.4}, &CompleteTripRequest{})
+		if trip.Fare != 232.4 {
+			t.Errorf("expected the quoted fare, got %.2f", trip.Fare)
+		}
+	})
+
+	t.Run("negative fare", func(t *testing.T) {
+		uc, _, _ := newTestTripUseCase(MatchingOptions{})
+		fare := -1.0
+		if _, err := uc.CompleteTrip(ctx, "any", &CompleteTripRequest{Fare: &fare}); !errors.Is(err, ErrInvalidFare) {
+			t.Errorf("expected ErrInvalidFare, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// dayLayout formats the calendar days of utilization reports
+const dayLayout = "2006-01-02"
+
+// Bounds of a utilization report, in days
+const (
+	defaultUtilizationDays = 7
+	maxUtilizationDays     = 92
+)
+
+// UtilizationUseCase defines the interface for driver utilization reports
+type UtilizationUseCase interface {
+	GetUtilization(ctx context.Context, from, to *time.Time) (*domain.UtilizationReport, error)
+	// RollUp recomputes the rollups of the last complete days and returns how many days it rolled up
+	RollUp(ctx context.Context) (int, error)
+	// RollUpDay recomputes the rollup of the day at falls on and reports
+	// whether it did; days that are not complete yet are skipped
+	RollUpDay(ctx context.Context, at time.Time) (bool, error)
+}
+
+// UtilizationOptions holds the utilization report settings
+type UtilizationOptions struct {
+	// Location is the time zone days are reported in; UTC when nil
+	Location *time.Location
+	// RollupDays is how many complete days every rollup recomputes, so shifts
+	// and trips that end late still reach the rollup of the days they span
+	RollupDays int
+}
+
+// utilizationUseCase implements UtilizationUseCase. Complete days are read
+// from daily rollups; the current day and days without a rollup are
+// aggregated for the request, and complete ones are rolled up on the way.
+type utilizationUseCase struct {
+	repo   domain.UtilizationRepository
+	opts   UtilizationOptions
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewUtilizationUseCase creates a new utilization use case
+func NewUtilizationUseCase(repo domain.UtilizationRepository, opts UtilizationOptions, logger *zap.Logger) UtilizationUseCase {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.RollupDays <= 0 {
+		opts.RollupDays = 1
+	}
+	return &utilizationUseCase{
+		repo:   repo,
+		opts:   opts,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// GetUtilization reports the days from from to to, both inclusive. The range
+// defaults to the last 7 days up to today and cannot exceed 92 days; days
+// after today are left out.
+func (uc *utilizationUseCase) GetUtilization(ctx context.Context, from, to *time.Time) (*domain.UtilizationReport, error) {
+	today := uc.day(uc.now().In(uc.opts.Location))
+	last := today
+	if to != nil {
+		last = uc.day(*to)
+	}
+	first := last.AddDate(0, 0, 1-defaultUtilizationDays)
+	if from != nil {
+		first = uc.day(*from)
+	}
+	if first.After(last) {
+		return nil, ErrInvalidStatsRange
+	}
+	if first.AddDate(0, 0, maxUtilizationDays).Before(last.AddDate(0, 0, 1)) {
+		return nil, ErrUtilizationRangeTooLong
+	}
+
+	report := &domain.UtilizationReport{
+		From:     first.Format(dayLayout),
+		To:       last.Format(dayLayout),
+		Timezone: uc.opts.Location.String(),
+		Days:     []domain.UtilizationDay{},
+	}
+	if last.After(today) {
+		last = today
+	}
+	if first.After(last) {
+		return report, nil
+	}
+
+	rollups, err := uc.repo.Rollups(ctx, first.Format(dayLayout), last.Format(dayLayout))
+	if err != nil {
+		return nil, errors.New("failed to get utilization")
+	}
+	rolledUp := make(map[string][]domain.UtilizationDay)
+	for _, row := range rollups {
+		rolledUp[row.Day] = append(rolledUp[row.Day], row)
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		key := day.Format(dayLayout)
+		rows, ok := rolledUp[key]
+		if !ok || day.Equal(today) {
+			rows, err = uc.aggregate(ctx, day)
+			if err != nil {
+				return nil, errors.New("failed to get utilization")
+			}
+			if day.Before(today) && len(rows) > 0 {
+				// Later reports read the day from its rollup
+				if err := uc.repo.SaveRollup(ctx, key, rows); err != nil {
+					uc.logger.Warn("failed to roll up utilization", zap.String("day", key), zap.Error(err))
+				}
+			}
+		}
+		for _, row := range rows {
+			row.Partial = day.Equal(today)
+			report.Days = append(report.Days, row)
+		}
+	}
+	return report, nil
+}
+
+// RollUp recomputes the rollups of the RollupDays days before today
+func (uc *utilizationUseCase) RollUp(ctx context.Context) (int, error) {
+	today := uc.day(uc.now().In(uc.opts.Location))
+	for i := 1; i <= uc.opts.RollupDays; i++ {
+		day := today.AddDate(0, 0, -i)
+		rows, err := uc.aggregate(ctx, day)
+		if err != nil {
+			return i - 1, err
+		}
+		if err := uc.repo.SaveRollup(ctx, day.Format(dayLayout), rows); err != nil {
+			return i - 1, err
+		}
+	}
+	return uc.opts.RollupDays, nil
+}
+
+// RollUpDay recomputes the rollup of the day at falls on in the report time zone
+func (uc *utilizationUseCase) RollUpDay(ctx context.Context, at time.Time) (bool, error) {
+	day := uc.day(at.In(uc.opts.Location))
+	if !day.Before(uc.day(uc.now().In(uc.opts.Location))) {
+		return false, nil
+	}
+	rows, err := uc.aggregate(ctx, day)
+	if err != nil {
+		return false, err
+	}
+	if err := uc.repo.SaveRollup(ctx, day.Format(dayLayout), rows); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// aggregate computes the utilization of a day per taxi type
+func (uc *utilizationUseCase) aggregate(ctx context.Context, day time.Time) ([]domain.UtilizationDay, error) {
+	rows, err := uc.repo.AggregateUtilization(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		uc.logger.Error("failed to aggregate utilization", zap.Error(err), zap.Time("day", day))
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Day = day.Format(dayLayout)
+		rows[i].OnlineHours = math.Round(rows[i].OnlineHours*100) / 100
+		rows[i].BusyHours = math.Round(rows[i].BusyHours*100) / 100
+		if rows[i].OnlineHours > 0 {
+			rows[i].Utilization = math.Round(rows[i].BusyHours/rows[i].OnlineHours*10000) / 10000
+		}
+	}
+	return rows, nil
+}
+
+// day returns the start of t's calendar day in the report time zone. The date
+// is taken as written, so a YYYY-MM-DD parsed as UTC names the same day.
+func (uc *utilizationUseCase) day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, uc.opts.Location)
+}
+
+// WriteUtilizationCSV writes a utilization report as CSV, one row per day and taxi type
+func WriteUtilizationCSV(w io.Writer, report *domain.UtilizationReport) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"day", "taxiType", "onlineHours", "busyHours", "utilization", "partial"})
+	for _, row := range report.Days {
+		out.Write([]string{
+			row.Day,
+			string(row.TaxiType),
+			fmt.Sprintf("%.2f", row.OnlineHours),
+			fmt.Sprintf("%.2f", row.BusyHours),
+			fmt.Sprintf("%.4f", row.Utilization),
+			fmt.Sprintf("%t", row.Partial),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
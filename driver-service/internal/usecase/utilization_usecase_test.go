@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockUtilizationRepository serves the same canned utilization for every day
+// and keeps rollups in memory
+type mockUtilizationRepository struct {
+	rows       []domain.UtilizationDay
+	rollups    map[string][]domain.UtilizationDay
+	aggregated []time.Time
+	shouldFail bool
+}
+
+func newMockUtilizationRepository(rows ...domain.UtilizationDay) *mockUtilizationRepository {
+	return &mockUtilizationRepository{rows: rows, rollups: make(map[string][]domain.UtilizationDay)}
+}
+
+func (m *mockUtilizationRepository) AggregateUtilization(ctx interface{}, start, end time.Time) ([]domain.UtilizationDay, error) {
+	if m.shouldFail {
+		return nil, errors.New("repository error")
+	}
+	m.aggregated = append(m.aggregated, start)
+	return append([]domain.UtilizationDay(nil), m.rows...), nil
+}
+
+func (m *mockUtilizationRepository) SaveRollup(ctx interface{}, day string, rows []domain.UtilizationDay) error {
+	m.rollups[day] = rows
+	return nil
+}
+
+func (m *mockUtilizationRepository) Rollups(ctx interface{}, from, to string) ([]domain.UtilizationDay, error) {
+	var rows []domain.UtilizationDay
+	for day := from; day <= to; {
+		rows = append(rows, m.rollups[day]...)
+		next, _ := time.Parse(dayLayout, day)
+		day = next.AddDate(0, 0, 1).Format(dayLayout)
+	}
+	return rows, nil
+}
+
+func TestUtilizationUseCase_GetUtilization(t *testing.T) {
+	istanbul := time.FixedZone("Europe/Istanbul", 3*60*60)
+	// 01:30 in Istanbul is still the previous day in UTC
+	now := time.Date(2025, 12, 6, 22, 30, 0, 0, time.UTC)
+	newUseCase := func(repo *mockUtilizationRepository) *utilizationUseCase {
+		uc := NewUtilizationUseCase(repo, UtilizationOptions{Location: istanbul, RollupDays: 2}, zap.NewNop()).(*utilizationUseCase)
+		uc.now = func() time.Time { return now }
+		return uc
+	}
+	ctx := context.Background()
+	sari := domain.UtilizationDay{TaxiType: domain.TaxiTypeSari, OnlineHours: 10, BusyHours: 6.666}
+
+	t.Run("defaults to the last 7 days in the report time zone", func(t *testing.T) {
+		repo := newMockUtilizationRepository(sari)
+		report, err := newUseCase(repo).GetUtilization(ctx, nil, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.From != "2025-12-01" || report.To != "2025-12-07" || report.Timezone != "Europe/Istanbul" {
+			t.Errorf("unexpected range %s - %s in %s", report.From, report.To, report.Timezone)
+		}
+		if len(report.Days) != 7 {
+			t.Fatalf("expected 7 rows, got %d", len(report.Days))
+		}
+		first, today := report.Days[0], report.Days[6]
+		if first.Day != "2025-12-01" || first.Partial {
+			t.Errorf("unexpected first row %+v", first)
+		}
+		if first.BusyHours != 6.67 || first.Utilization != 0.667 {
+			t.Errorf("expected rounded hours and utilization, got %+v", first)
+		}
+		if today.Day != "2025-12-07" || !today.Partial {
+			t.Errorf("expected today to be partial, got %+v", today)
+		}
+		if !repo.aggregated[0].Equal(time.Date(2025, 12, 1, 0, 0, 0, 0, istanbul)) {
+			t.Errorf("expected days to start at midnight in the report time zone, got %v", repo.aggregated[0])
+		}
+		if len(repo.rollups) != 6 || repo.rollups["2025-12-07"] != nil {
+			t.Errorf("expected the complete days to be rolled up, got %v", repo.rollups)
+		}
+	})
+
+	t.Run("reads complete days from their rollups", func(t *testing.T) {
+		repo := newMockUtilizationRepository(sari)
+		repo.rollups["2025-12-05"] = []domain.UtilizationDay{{Day: "2025-12-05", TaxiType: domain.TaxiTypeSiyah, OnlineHours: 4}}
+		from := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 12, 10, 0, 0, 0, 0, time.UTC)
+
+		report, err := newUseCase(repo).GetUtilization(ctx, &from, &to)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.To != "2025-12-10" {
+			t.Errorf("expected the requested end, got %s", report.To)
+		}
+		// 5th from the rollup, 6th aggregated, 7th partial, nothing after today
+		if len(report.Days) != 3 || report.Days[0].TaxiType != domain.TaxiTypeSiyah {
+			t.Errorf("unexpected rows %+v", report.Days)
+		}
+		if len(repo.aggregated) != 2 {
+			t.Errorf("expected 2 days to be aggregated, got %d", len(repo.aggregated))
+		}
+	})
+
+	t.Run("rejects invalid ranges", func(t *testing.T) {
+		uc := newUseCase(newMockUtilizationRepository())
+		from := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2025, 12, 4, 0, 0, 0, 0, time.UTC)
+		if _, err := uc.GetUtilization(ctx, &from, &to); !errors.Is(err, ErrInvalidStatsRange) {
+			t.Errorf("expected ErrInvalidStatsRange, got %v", err)
+		}
+		from = to.AddDate(0, 0, -92)
+		if _, err := uc.GetUtilization(ctx, &from, &to); !errors.Is(err, ErrUtilizationRangeTooLong) {
+			t.Errorf("expected ErrUtilizationRangeTooLong, got %v", err)
+		}
+		from = to.AddDate(0, 0, -91)
+		if _, err := uc.GetUtilization(ctx, &from, &to); err != nil {
+			t.Errorf("expected 92 days to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := newMockUtilizationRepository()
+		repo.shouldFail = true
+		if _, err := newUseCase(repo).GetUtilization(ctx, nil, nil); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestUtilizationUseCase_RollUp(t *testing.T) {
+	repo := newMockUtilizationRepository(domain.UtilizationDay{TaxiType: domain.TaxiTypeSari, OnlineHours: 8, BusyHours: 2})
+	uc := NewUtilizationUseCase(repo, UtilizationOptions{RollupDays: 3}, zap.NewNop()).(*utilizationUseCase)
+	uc.now = func() time.Time { return time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC) }
+
+	days, err := uc.RollUp(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if days != 3 || len(repo.rollups) != 3 {
+		t.Fatalf("expected 3 days rolled up, got %d (%v)", days, repo.rollups)
+	}
+	rows := repo.rollups["2025-12-03"]
+	if len(rows) != 1 || rows[0].Day != "2025-12-03" || rows[0].Utilization != 0.25 {
+		t.Errorf("unexpected rollup %+v", rows)
+	}
+	if repo.rollups["2025-12-06"] != nil {
+		t.Error("expected today not to be rolled up")
+	}
+}
+
+func TestUtilizationUseCase_RollUpDay(t *testing.T) {
+	istanbul := time.FixedZone("Europe/Istanbul", 3*60*60)
+	repo := newMockUtilizationRepository(domain.UtilizationDay{TaxiType: domain.TaxiTypeSari, OnlineHours: 8, BusyHours: 2})
+	uc := NewUtilizationUseCase(repo, UtilizationOptions{Location: istanbul}, zap.NewNop()).(*utilizationUseCase)
+	uc.now = func() time.Time { return time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC) }
+
+	// 22:30 UTC is already the next day in Istanbul
+	rolled, err := uc.RollUpDay(context.Background(), time.Date(2025, 12, 2, 22, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rolled || len(repo.rollups["2025-12-03"]) != 1 {
+		t.Errorf("expected the Istanbul day to be rolled up, got %v", repo.rollups)
+	}
+
+	rolled, err = uc.RollUpDay(context.Background(), time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC))
+	if err != nil || rolled || repo.rollups["2025-12-06"] != nil {
+		t.Errorf("expected today not to be rolled up, got %v, %v", rolled, err)
+	}
+}
+
+func TestWriteUtilizationCSV(t *testing.T) {
+	report := &domain.UtilizationReport{Days: []domain.UtilizationDay{
+		{Day: "2025-12-01", TaxiType: domain.TaxiTypeSari, OnlineHours: 10, BusyHours: 6.5, Utilization: 0.65},
+	}}
+	var buf bytes.Buffer
+	if err := WriteUtilizationCSV(&buf, report); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "day,taxiType,onlineHours,busyHours,utilization,partial\n2025-12-01,sari,10.00,6.50,0.6500,false\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
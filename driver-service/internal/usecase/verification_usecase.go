@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/sms"
+	"go.uber.org/zap"
+)
+
+// VerificationUseCase defines the interface for driver contact verification
+// and rider sign-in by phone
+type VerificationUseCase interface {
+	SendPhoneCode(ctx context.Context, driverID string) error
+	VerifyPhone(ctx context.Context, driverID, code string) (*domain.Driver, error)
+	SendRiderSignInCode(ctx context.Context, phone string) error
+	SignInRider(ctx context.Context, phone, code string) (*domain.Rider, error)
+}
+
+// VerifyPhoneRequest represents the request to confirm a phone verification code
+type VerifyPhoneRequest struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
+
+// RiderSignInCodeRequest represents the request to text a rider a sign-in code
+type RiderSignInCodeRequest struct {
+	Phone string `json:"phone" example:"+905551234567" binding:"required"`
+}
+
+// RiderSignInRequest represents the request to sign a rider in with a texted code
+type RiderSignInRequest struct {
+	Phone string `json:"phone" example:"+905551234567" binding:"required"`
+	Code  string `json:"code" example:"123456" binding:"required"`
+}
+
+// VerificationOptions holds the one-time-password settings
+type VerificationOptions struct {
+	CodeLength     int
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+}
+
+// verificationUseCase implements VerificationUseCase
+type verificationUseCase struct {
+	driverRepo       domain.DriverRepository
+	riderRepo        domain.RiderRepository
+	verificationRepo domain.VerificationRepository
+	smsProvider      sms.Provider
+	opts             VerificationOptions
+	logger           *zap.Logger
+	now              func() time.Time
+}
+
+// NewVerificationUseCase creates a new verification use case
+func NewVerificationUseCase(
+	driverRepo domain.DriverRepository,
+	riderRepo domain.RiderRepository,
+	verificationRepo domain.VerificationRepository,
+	smsProvider sms.Provider,
+	opts VerificationOptions,
+	logger *zap.Logger,
+) VerificationUseCase {
+	if opts.CodeLength <= 0 {
+		opts.CodeLength = 6
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	return &verificationUseCase{
+		driverRepo:       driverRepo,
+		riderRepo:        riderRepo,
+		verificationRepo: verificationRepo,
+		smsProvider:      smsProvider,
+		opts:             opts,
+		logger:           logger,
+		now:              time.Now,
+	}
+}
+
+// SendPhoneCode generates a one-time code and sends it to the driver's phone
+func (uc *verificationUseCase) SendPhoneCode(ctx context.Context, driverID string) error {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return errors.New("driver not found")
+	}
+	if driver.Phone == "" {
+		return ErrPhoneMissing
+	}
+	if driver.PhoneVerified {
+		return ErrPhoneAlreadyVerified
+	}
+
+	if err := uc.sendCode(ctx, driverID, driver.Phone, zap.String("driverId", driverID)); err != nil {
+		return err
+	}
+	uc.logger.Info("phone verification code sent", zap.String("driverId", driverID))
+	return nil
+}
+
+// VerifyPhone checks the submitted code and marks the driver's phone as verified
+func (uc *verificationUseCase) VerifyPhone(ctx context.Context, driverID, code string) (*domain.Driver, error) {
+	driver, err := uc.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, errors.New("driver not found")
+	}
+
+	if err := uc.checkCode(ctx, driverID, driver.Phone, code, zap.String("driverId", driverID)); err != nil {
+		return nil, err
+	}
+
+	driver.PhoneVerified = true
+	if err := uc.driverRepo.Update(ctx, driverID, driver); err != nil {
+		uc.logger.Error("failed to mark phone verified", zap.Error(err), zap.String("driverId", driverID))
+		return nil, errors.New("failed to verify phone")
+	}
+	if err := uc.verificationRepo.Delete(ctx, driverID); err != nil {
+		uc.logger.Warn("failed to delete used verification", zap.Error(err), zap.String("driverId", driverID))
+	}
+
+	uc.logger.Info("phone verified", zap.String("driverId", driverID))
+	return driver, nil
+}
+
+// SendRiderSignInCode texts a sign-in code to the rider registered with the
+// phone. Unknown phones get the same answer, so the endpoint does not tell
+// who has registered.
+func (uc *verificationUseCase) SendRiderSignInCode(ctx context.Context, phone string) error {
+	rider, err := uc.riderRepo.GetByPhone(ctx, normalizePhone(phone))
+	if err != nil {
+		if err.Error() != ErrRiderNotFound.Error() {
+			uc.logger.Error("failed to get rider by phone", zap.Error(err))
+			return errors.New("failed to send verification code")
+		}
+		return nil
+	}
+	if err := uc.sendCode(ctx, riderSignInKey(rider.ID), rider.Phone, zap.String("riderId", rider.ID)); err != nil {
+		return err
+	}
+	uc.logger.Info("rider sign-in code sent", zap.String("riderId", rider.ID))
+	return nil
+}
+
+// SignInRider checks a code sent by SendRiderSignInCode and returns the rider
+// it was sent to
+func (uc *verificationUseCase) SignInRider(ctx context.Context, phone, code string) (*domain.Rider, error) {
+	rider, err := uc.riderRepo.GetByPhone(ctx, normalizePhone(phone))
+	if err != nil {
+		if err.Error() != ErrRiderNotFound.Error() {
+			uc.logger.Error("failed to get rider by phone", zap.Error(err))
+			return nil, errors.New("failed to sign in")
+		}
+		return nil, ErrVerificationNotFound
+	}
+	key := riderSignInKey(rider.ID)
+	if err := uc.checkCode(ctx, key, rider.Phone, code, zap.String("riderId", rider.ID)); err != nil {
+		return nil, err
+	}
+	if err := uc.verificationRepo.Delete(ctx, key); err != nil {
+		uc.logger.Warn("failed to delete used verification", zap.Error(err), zap.String("riderId", rider.ID))
+	}
+
+	uc.logger.Info("rider signed in", zap.String("riderId", rider.ID))
+	return rider, nil
+}
+
+// riderSignInKey keys a rider's sign-in challenge apart from driver challenges
+func riderSignInKey(riderID string) string {
+	return "rider:" + riderID
+}
+
+// sendCode stores a new challenge under key for phone and texts its code,
+// unless a code was sent to the same phone within the resend cooldown
+func (uc *verificationUseCase) sendCode(ctx context.Context, key, phone string, subject zap.Field) error {
+	now := uc.now()
+	if pending, err := uc.verificationRepo.Get(ctx, key); err == nil && pending.Phone == phone {
+		if now.Sub(pending.CreatedAt) < uc.opts.ResendCooldown {
+			return ErrVerificationSendLimit
+		}
+	}
+
+	code, err := generateCode(uc.opts.CodeLength)
+	if err != nil {
+		uc.logger.Error("failed to generate verification code", zap.Error(err))
+		return errors.New("failed to send verification code")
+	}
+
+	verification := &domain.PhoneVerification{
+		DriverID:  key,
+		Phone:     phone,
+		CodeHash:  hashCode(key, code),
+		ExpiresAt: now.Add(uc.opts.CodeTTL),
+		CreatedAt: now,
+	}
+	if err := uc.verificationRepo.Save(ctx, verification); err != nil {
+		uc.logger.Error("failed to save phone verification", zap.Error(err), subject)
+		return errors.New("failed to send verification code")
+	}
+
+	message := fmt.Sprintf("Your TaxiHub verification code is %s", code)
+	if err := uc.smsProvider.Send(ctx, phone, message); err != nil {
+		uc.logger.Error("failed to send verification sms", zap.Error(err), subject)
+		return errors.New("failed to send verification code")
+	}
+	return nil
+}
+
+// checkCode checks code against the challenge stored under key for phone,
+// counting failed attempts
+func (uc *verificationUseCase) checkCode(ctx context.Context, key, phone, code string, subject zap.Field) error {
+	verification, err := uc.verificationRepo.Get(ctx, key)
+	if err != nil || verification.Phone != phone {
+		return ErrVerificationNotFound
+	}
+	if verification.IsExpired(uc.now()) {
+		return ErrVerificationExpired
+	}
+	if verification.Attempts >= uc.opts.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+
+	expected := []byte(verification.CodeHash)
+	actual := []byte(hashCode(key, code))
+	if subtle.ConstantTimeCompare(expected, actual) != 1 {
+		verification.Attempts++
+		if err := uc.verificationRepo.Save(ctx, verification); err != nil {
+			uc.logger.Error("failed to record verification attempt", zap.Error(err), subject)
+		}
+		return ErrInvalidCode
+	}
+	return nil
+}
+
+// generateCode returns a random numeric code of the given length
+func generateCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
+
+// hashCode hashes a code bound to its challenge key so stored hashes cannot be reused across drivers or riders
+func hashCode(driverID, code string) string {
+	sum := sha256.Sum256([]byte(driverID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// mockVerificationRepository is an in-memory VerificationRepository
+type mockVerificationRepository struct {
+	verifications map[string]*domain.PhoneVerification
+}
+
+func newMockVerificationRepository() *mockVerificationRepository {
+	return &mockVerificationRepository{verifications: make(map[string]*domain.PhoneVerification)}
+}
+
+func (m *mockVerificationRepository) Save(ctx interface{}, v *domain.PhoneVerification) error {
+	copied := *v
+	m.verifications[v.DriverID] = &copied
+	return nil
+}
+
+func (m *mockVerificationRepository) Get(ctx interface{}, driverID string) (*domain.PhoneVerification, error) {
+	v, ok := m.verifications[driverID]
+	if !ok {
+		return nil, errors.New("verification not found")
+	}
+	copied := *v
+	return &copied, nil
+}
+
+func (m *mockVerificationRepository) Delete(ctx interface{}, driverID string) error {
+	delete(m.verifications, driverID)
+	return nil
+}
+
+// fakeSMSProvider records sent messages
+type fakeSMSProvider struct {
+	to       string
+	message  string
+	sendFail bool
+}
+
+func (f *fakeSMSProvider) Send(ctx context.Context, to, message string) error {
+	if f.sendFail {
+		return errors.New("sms gateway down")
+	}
+	f.to = to
+	f.message = message
+	return nil
+}
+
+func (f *fakeSMSProvider) code() string {
+	return regexp.MustCompile(`[0-9]{6}`).FindString(f.message)
+}
+
+func newTestVerificationUseCase(repo *mockDriverRepository, sms *fakeSMSProvider) (*verificationUseCase, *mockVerificationRepository) {
+	verificationRepo := newMockVerificationRepository()
+	uc := NewVerificationUseCase(repo, newMockRiderRepository(), verificationRepo, sms, VerificationOptions{
+		CodeLength:     6,
+		CodeTTL:        5 * time.Minute,
+		MaxAttempts:    3,
+		ResendCooldown: time.Minute,
+	}, zap.NewNop()).(*verificationUseCase)
+	return uc, verificationRepo
+}
+
+func TestVerificationUseCase_SendAndVerify(t *testing.T) {
+	repo := newMockDriverRepository()
+	repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567"}
+	sms := &fakeSMSProvider{}
+	uc, verificationRepo := newTestVerificationUseCase(repo, sms)
+
+	if err := uc.SendPhoneCode(context.Background(), "driver-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sms.to != "+905321234567" || sms.code() == "" {
+		t.Fatalf("expected code to be sent to driver phone, got to=%q message=%q", sms.to, sms.message)
+	}
+	if stored := verificationRepo.verifications["driver-1"]; stored == nil || stored.CodeHash == sms.code() {
+		t.Fatalf("expected hashed code to be stored")
+	}
+
+	driver, err := uc.VerifyPhone(context.Background(), "driver-1", sms.code())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !driver.PhoneVerified {
+		t.Errorf("expected phone to be verified")
+	}
+	if _, ok := verificationRepo.verifications["driver-1"]; ok {
+		t.Errorf("expected used verification to be deleted")
+	}
+}
+
+func TestVerificationUseCase_SendPhoneCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		driver  *domain.Driver
+		sendErr bool
+		wantErr error
+		errMsg  string
+	}{
+		{name: "driver not found", errMsg: "driver not found"},
+		{name: "no phone", driver: &domain.Driver{ID: "driver-1"}, wantErr: ErrPhoneMissing},
+		{name: "already verified", driver: &domain.Driver{ID: "driver-1", Phone: "+905321234567", PhoneVerified: true}, wantErr: ErrPhoneAlreadyVerified},
+		{name: "sms failure", driver: &domain.Driver{ID: "driver-1", Phone: "+905321234567"}, sendErr: true, errMsg: "failed to send verification code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockDriverRepository()
+			if tt.driver != nil {
+				repo.drivers[tt.driver.ID] = tt.driver
+			}
+			uc, _ := newTestVerificationUseCase(repo, &fakeSMSProvider{sendFail: tt.sendErr})
+
+			err := uc.SendPhoneCode(context.Background(), "driver-1")
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.errMsg != "" && (err == nil || err.Error() != tt.errMsg) {
+				t.Errorf("expected %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+
+	t.Run("resend cooldown", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567"}
+		uc, _ := newTestVerificationUseCase(repo, &fakeSMSProvider{})
+
+		if err := uc.SendPhoneCode(context.Background(), "driver-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := uc.SendPhoneCode(context.Background(), "driver-1"); !errors.Is(err, ErrVerificationSendLimit) {
+			t.Errorf("expected ErrVerificationSendLimit, got %v", err)
+		}
+
+		uc.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		if err := uc.SendPhoneCode(context.Background(), "driver-1"); err != nil {
+			t.Errorf("expected resend after cooldown, got %v", err)
+		}
+	})
+}
+
+func TestVerificationUseCase_VerifyPhone(t *testing.T) {
+	setup := func() (*verificationUseCase, *fakeSMSProvider) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567"}
+		sms := &fakeSMSProvider{}
+		uc, _ := newTestVerificationUseCase(repo, sms)
+		if err := uc.SendPhoneCode(context.Background(), "driver-1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return uc, sms
+	}
+
+	t.Run("wrong code then too many attempts", func(t *testing.T) {
+		uc, sms := setup()
+		for i := 0; i < 3; i++ {
+			if _, err := uc.VerifyPhone(context.Background(), "driver-1", "000000x"); !errors.Is(err, ErrInvalidCode) {
+				t.Fatalf("attempt %d: expected ErrInvalidCode, got %v", i, err)
+			}
+		}
+		if _, err := uc.VerifyPhone(context.Background(), "driver-1", sms.code()); !errors.Is(err, ErrTooManyAttempts) {
+			t.Errorf("expected ErrTooManyAttempts, got %v", err)
+		}
+	})
+
+	t.Run("expired code", func(t *testing.T) {
+		uc, sms := setup()
+		uc.now = func() time.Time { return time.Now().Add(10 * time.Minute) }
+		if _, err := uc.VerifyPhone(context.Background(), "driver-1", sms.code()); !errors.Is(err, ErrVerificationExpired) {
+			t.Errorf("expected ErrVerificationExpired, got %v", err)
+		}
+	})
+
+	t.Run("no pending verification", func(t *testing.T) {
+		repo := newMockDriverRepository()
+		repo.drivers["driver-1"] = &domain.Driver{ID: "driver-1", Phone: "+905321234567"}
+		uc, _ := newTestVerificationUseCase(repo, &fakeSMSProvider{})
+		if _, err := uc.VerifyPhone(context.Background(), "driver-1", "123456"); !errors.Is(err, ErrVerificationNotFound) {
+			t.Errorf("expected ErrVerificationNotFound, got %v", err)
+		}
+	})
+}
+
+func TestVerificationUseCase_RiderSignIn(t *testing.T) {
+	sms := &fakeSMSProvider{}
+	uc, verificationRepo := newTestVerificationUseCase(newMockDriverRepository(), sms)
+	riders := newMockRiderRepository()
+	uc.riderRepo = riders
+	rider := &domain.Rider{FirstName: "Elif", LastName: "Yılmaz", Phone: "+905551234567"}
+	if err := riders.Create(context.Background(), rider); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	if err := uc.SendRiderSignInCode(ctx, "+90 555 000 0000"); err != nil || sms.to != "" {
+		t.Fatalf("expected unknown phones to be answered without a text, got err=%v to=%q", err, sms.to)
+	}
+	if _, err := uc.SignInRider(ctx, "+905550000000", "123456"); !errors.Is(err, ErrVerificationNotFound) {
+		t.Errorf("expected ErrVerificationNotFound for an unknown phone, got %v", err)
+	}
+
+	if err := uc.SendRiderSignInCode(ctx, "+90 555 123 45 67"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sms.to != rider.Phone || sms.code() == "" {
+		t.Fatalf("expected a code texted to the rider, got to=%q message=%q", sms.to, sms.message)
+	}
+	if err := uc.SendRiderSignInCode(ctx, rider.Phone); !errors.Is(err, ErrVerificationSendLimit) {
+		t.Errorf("expected ErrVerificationSendLimit, got %v", err)
+	}
+	if _, err := uc.SignInRider(ctx, rider.Phone, "000000x"); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("expected ErrInvalidCode, got %v", err)
+	}
+
+	signedIn, err := uc.SignInRider(ctx, rider.Phone, sms.code())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signedIn.ID != rider.ID {
+		t.Errorf("SignInRider() = %s, want %s", signedIn.ID, rider.ID)
+	}
+	if len(verificationRepo.verifications) != 0 {
+		t.Error("expected the used code to be deleted")
+	}
+	if _, err := uc.SignInRider(ctx, rider.Phone, sms.code()); !errors.Is(err, ErrVerificationNotFound) {
+		t.Errorf("expected a used code to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/webhook"
+	"go.uber.org/zap"
+)
+
+// WebhookUseCase defines the interface for webhook subscriptions and deliveries.
+// It also publishes driver events as webhook deliveries.
+type WebhookUseCase interface {
+	domain.DriverEventPublisher
+	CreateSubscription(ctx context.Context, req *CreateWebhookRequest) (*domain.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, fleetID string) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+// WebhookSender sends one signed delivery and returns the response status
+type WebhookSender interface {
+	Send(ctx context.Context, req webhook.Request) (int, error)
+}
+
+// CreateWebhookRequest represents the request to register a webhook endpoint
+type CreateWebhookRequest struct {
+	URL string `json:"url" example:"https://partner.example.com/hooks/bitaksi" binding:"required"`
+	// Secret signs deliveries; one is generated when empty
+	Secret string `json:"secret,omitempty" example:"whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"`
+	// Events filters the event types to deliver; empty delivers every event
+	Events      []string `json:"events,omitempty" example:"driver.created,driver.suspended"`
+	FleetID     string   `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Description string   `json:"description,omitempty" example:"Kadıköy Taksi dispatch system"`
+}
+
+// WebhookOptions holds the delivery settings
+type WebhookOptions struct {
+	// MaxAttempts is the number of attempts before a delivery is marked failed
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Lease is how long a claimed delivery is reserved for one attempt; it must exceed the sender timeout
+	Lease time.Duration
+	// AllowPrivateTargets accepts endpoints on loopback, private and
+	// link-local addresses, for local development
+	AllowPrivateTargets bool
+	// RequireHTTPS rejects plain http endpoints
+	RequireHTTPS bool
+}
+
+// Limits of the delivery worker and log
+const (
+	deliveryBatchSize       = 50
+	defaultDeliveryLogLimit = 50
+	maxDeliveryLogLimit     = 500
+	minWebhookSecretLength  = 16
+)
+
+// webhookUseCase implements WebhookUseCase
+type webhookUseCase struct {
+	repo   domain.WebhookRepository
+	fleets domain.FleetRepository
+	sender WebhookSender
+	opts   WebhookOptions
+	logger *zap.Logger
+	now    func() time.Time
+	// lookupIP resolves endpoint hosts at registration
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewWebhookUseCase creates a new webhook use case delivering through sender
+func NewWebhookUseCase(repo domain.WebhookRepository, fleets domain.FleetRepository, sender WebhookSender, opts WebhookOptions, logger *zap.Logger) WebhookUseCase {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 8
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = 10 * time.Second
+	}
+	if opts.BackoffMax < opts.BackoffBase {
+		opts.BackoffMax = opts.BackoffBase
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = time.Minute
+	}
+	return &webhookUseCase{
+		repo:     repo,
+		fleets:   fleets,
+		sender:   sender,
+		opts:     opts,
+		logger:   logger,
+		now:      time.Now,
+		lookupIP: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// CreateSubscription registers an endpoint. The returned subscription is the
+// only place its secret is shown. Endpoints on internal addresses are refused
+// unless AllowPrivateTargets is set; hosts that do not resolve yet are
+// accepted, as the sender checks every address it connects to.
+func (uc *webhookUseCase) CreateSubscription(ctx context.Context, req *CreateWebhookRequest) (*domain.WebhookSubscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+	if uc.opts.RequireHTTPS && u.Scheme != "https" {
+		return nil, ErrWebhookHTTPSRequired
+	}
+	if !uc.opts.AllowPrivateTargets {
+		if err := webhook.CheckHost(ctx, uc.lookupIP, u.Hostname()); errors.Is(err, webhook.ErrPrivateTarget) {
+			return nil, ErrWebhookPrivateTarget
+		}
+	}
+	for _, event := range req.Events {
+		if !domain.IsDriverEvent(event) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidWebhookEvent, event)
+		}
+	}
+	secret := req.Secret
+	if secret == "" {
+		secret, err = newWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+	} else if len(secret) < minWebhookSecretLength {
+		return nil, ErrWebhookSecretTooShort
+	}
+	if req.FleetID != "" {
+		if _, err := uc.fleets.GetByID(ctx, req.FleetID); err != nil {
+			return nil, ErrFleetNotFound
+		}
+	}
+
+	sub := &domain.WebhookSubscription{
+		FleetID:     req.FleetID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: strings.TrimSpace(req.Description),
+		CreatedAt:   uc.now().UTC(),
+	}
+	if err := uc.repo.CreateSubscription(ctx, sub); err != nil {
+		uc.logger.Error("failed to create webhook subscription", zap.Error(err))
+		return nil, errors.New("failed to create webhook subscription")
+	}
+
+	uc.logger.Info("webhook subscription created", append(actorFields(ctx),
+		zap.String("id", sub.ID),
+		zap.String("fleetId", sub.FleetID),
+		zap.String("url", sub.URL),
+	)...)
+	return sub, nil
+}
+
+// GetSubscription returns a subscription without its secret
+func (uc *webhookUseCase) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		if err.Error() == ErrWebhookNotFound.Error() {
+			return nil, ErrWebhookNotFound
+		}
+		uc.logger.Error("failed to get webhook subscription", zap.Error(err), zap.String("id", id))
+		return nil, errors.New("failed to get webhook subscription")
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+// ListSubscriptions lists the subscriptions of a fleet, or all of them, without secrets
+func (uc *webhookUseCase) ListSubscriptions(ctx context.Context, fleetID string) ([]*domain.WebhookSubscription, error) {
+	subs, err := uc.repo.ListSubscriptions(ctx, fleetID)
+	if err != nil {
+		uc.logger.Error("failed to list webhook subscriptions", zap.Error(err))
+		return nil, errors.New("failed to list webhook subscriptions")
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+// DeleteSubscription removes a subscription and stops its pending deliveries
+func (uc *webhookUseCase) DeleteSubscription(ctx context.Context, id string) error {
+	if err := uc.repo.DeleteSubscription(ctx, id); err != nil {
+		if err.Error() == ErrWebhookNotFound.Error() {
+			return ErrWebhookNotFound
+		}
+		uc.logger.Error("failed to delete webhook subscription", zap.Error(err), zap.String("id", id))
+		return errors.New("failed to delete webhook subscription")
+	}
+	uc.logger.Info("webhook subscription deleted", append(actorFields(ctx), zap.String("id", id))...)
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a subscription, newest first
+func (uc *webhookUseCase) ListDeliveries(ctx context.Context, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error) {
+	if _, err := uc.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLogLimit
+	}
+	if limit > maxDeliveryLogLimit {
+		limit = maxDeliveryLogLimit
+	}
+
+	deliveries, err := uc.repo.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		uc.logger.Error("failed to list webhook deliveries", zap.Error(err), zap.String("id", subscriptionID))
+		return nil, errors.New("failed to list webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+// RetryDelivery schedules a delivery for an immediate new attempt. Failed
+// deliveries get a fresh set of attempts.
+func (uc *webhookUseCase) RetryDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	if _, err := uc.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	delivery, err := uc.repo.GetDelivery(ctx, deliveryID)
+	if err != nil || delivery.SubscriptionID != subscriptionID {
+		return nil, ErrDeliveryNotFound
+	}
+	if delivery.Status == domain.DeliveryInProgress {
+		return nil, ErrDeliveryInProgress
+	}
+
+	now := uc.now().UTC()
+	if delivery.Status != domain.DeliveryPending {
+		delivery.Attempts = 0
+	}
+	delivery.Status = domain.DeliveryPending
+	delivery.NextAttemptAt = &now
+	delivery.UpdatedAt = now
+	if err := uc.repo.UpdateDelivery(ctx, delivery); err != nil {
+		uc.logger.Error("failed to reschedule webhook delivery", zap.Error(err), zap.String("id", deliveryID))
+		return nil, errors.New("failed to retry webhook delivery")
+	}
+	return delivery, nil
+}
+
+// PublishDriverEvent queues a delivery of the event for every matching
+// subscription. Errors are logged; they never fail the driver change.
+func (uc *webhookUseCase) PublishDriverEvent(ctx interface{}, event string, driver *domain.Driver, reason string) {
+	subs, err := uc.repo.MatchSubscriptions(ctx, event, driver.FleetID)
+	if err != nil {
+		uc.logger.Error("failed to find webhook subscriptions", zap.Error(err), zap.String("event", event))
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	eventID, err := newEventID()
+	if err != nil {
+		uc.logger.Error("failed to create webhook event", zap.Error(err))
+		return
+	}
+	now := uc.now().UTC()
+	payload, err := json.Marshal(&domain.DriverEvent{
+		ID:         eventID,
+		Type:       event,
+		OccurredAt: now,
+		Driver:     driver,
+		Reason:     reason,
+	})
+	if err != nil {
+		uc.logger.Error("failed to encode webhook event", zap.Error(err), zap.String("event", event))
+		return
+	}
+
+	deliveries := make([]*domain.WebhookDelivery, len(subs))
+	for i, sub := range subs {
+		deliveries[i] = &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        payload,
+			Status:         domain.DeliveryPending,
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+	}
+	if err := uc.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		uc.logger.Error("failed to queue webhook deliveries", zap.Error(err), zap.String("event", event), zap.String("driverId", driver.ID))
+		return
+	}
+	uc.logger.Debug("webhook event queued", zap.String("event", event), zap.String("eventId", eventID), zap.Int("subscriptions", len(subs)))
+}
+
+// DeliverDue sends the deliveries that are due and returns how many were attempted
+func (uc *webhookUseCase) DeliverDue(ctx context.Context) (int, error) {
+	attempted := 0
+	for attempted < deliveryBatchSize {
+		now := uc.now().UTC()
+		delivery, err := uc.repo.ClaimDelivery(ctx, now, now.Add(uc.opts.Lease))
+		if err != nil {
+			return attempted, err
+		}
+		if delivery == nil {
+			break
+		}
+		uc.attempt(ctx, delivery)
+		attempted++
+	}
+	return attempted, nil
+}
+
+// attempt sends one claimed delivery and records the outcome
+func (uc *webhookUseCase) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	sub, err := uc.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		if err.Error() != ErrWebhookNotFound.Error() {
+			// Leave the lease to expire so the delivery is retried
+			uc.logger.Error("failed to load webhook subscription", zap.Error(err), zap.String("deliveryId", delivery.ID))
+			return
+		}
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "subscription deleted"
+		delivery.UpdatedAt = uc.now().UTC()
+		uc.saveDelivery(ctx, delivery)
+		return
+	}
+
+	code, sendErr := uc.sender.Send(ctx, webhook.Request{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		Event:      delivery.Event,
+		DeliveryID: delivery.ID,
+		Body:       delivery.Payload,
+	})
+
+	now := uc.now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = code
+	delivery.UpdatedAt = now
+	switch {
+	case sendErr == nil:
+		delivery.Status = domain.DeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= uc.opts.MaxAttempts:
+		delivery.Status = domain.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = sendErr.Error()
+		uc.logger.Warn("webhook delivery failed permanently",
+			zap.String("deliveryId", delivery.ID),
+			zap.String("subscriptionId", sub.ID),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Error(sendErr),
+		)
+	default:
+		next := now.Add(webhook.Backoff(delivery.Attempts, uc.opts.BackoffBase, uc.opts.BackoffMax))
+		delivery.Status = domain.DeliveryPending
+		delivery.NextAttemptAt = &next
+		delivery.LastError = sendErr.Error()
+	}
+	uc.saveDelivery(ctx, delivery)
+}
+
+func (uc *webhookUseCase) saveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) {
+	if err := uc.repo.UpdateDelivery(ctx, delivery); err != nil {
+		uc.logger.Error("failed to record webhook delivery", zap.Error(err), zap.String("deliveryId", delivery.ID))
+	}
+}
+
+// newWebhookSecret returns a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// newEventID returns a random event ID; receivers use it to drop duplicate deliveries
+func newEventID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
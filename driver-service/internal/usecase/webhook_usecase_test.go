@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/internal/domain"
+	"github.com/bitaksi/driver-service/internal/webhook"
+	"go.uber.org/zap"
+)
+
+// mockWebhookRepository keeps subscriptions and deliveries in memory
+type mockWebhookRepository struct {
+	subs       map[string]*domain.WebhookSubscription
+	deliveries map[string]*domain.WebhookDelivery
+	nextID     int
+}
+
+func newMockWebhookRepository() *mockWebhookRepository {
+	return &mockWebhookRepository{
+		subs:       make(map[string]*domain.WebhookSubscription),
+		deliveries: make(map[string]*domain.WebhookDelivery),
+	}
+}
+
+func (m *mockWebhookRepository) id(prefix string) string {
+	m.nextID++
+	return fmt.Sprintf("%s-%d", prefix, m.nextID)
+}
+
+func (m *mockWebhookRepository) CreateSubscription(ctx interface{}, sub *domain.WebhookSubscription) error {
+	sub.ID = m.id("sub")
+	copied := *sub
+	m.subs[sub.ID] = &copied
+	return nil
+}
+
+func (m *mockWebhookRepository) GetSubscription(ctx interface{}, id string) (*domain.WebhookSubscription, error) {
+	sub, ok := m.subs[id]
+	if !ok {
+		return nil, errors.New("webhook subscription not found")
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (m *mockWebhookRepository) ListSubscriptions(ctx interface{}, fleetID string) ([]*domain.WebhookSubscription, error) {
+	subs := make([]*domain.WebhookSubscription, 0)
+	for _, sub := range m.subs {
+		if fleetID == "" || sub.FleetID == fleetID {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	return subs, nil
+}
+
+func (m *mockWebhookRepository) DeleteSubscription(ctx interface{}, id string) error {
+	if _, ok := m.subs[id]; !ok {
+		return errors.New("webhook subscription not found")
+	}
+	delete(m.subs, id)
+	return nil
+}
+
+func (m *mockWebhookRepository) MatchSubscriptions(ctx interface{}, event, fleetID string) ([]*domain.WebhookSubscription, error) {
+	subs := make([]*domain.WebhookSubscription, 0)
+	for _, sub := range m.subs {
+		if (sub.FleetID == "" || sub.FleetID == fleetID) && sub.Wants(event) {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	return subs, nil
+}
+
+func (m *mockWebhookRepository) CreateDeliveries(ctx interface{}, deliveries []*domain.WebhookDelivery) error {
+	for _, d := range deliveries {
+		d.ID = m.id("delivery")
+		copied := *d
+		m.deliveries[d.ID] = &copied
+	}
+	return nil
+}
+
+func (m *mockWebhookRepository) GetDelivery(ctx interface{}, id string) (*domain.WebhookDelivery, error) {
+	d, ok := m.deliveries[id]
+	if !ok {
+		return nil, errors.New("webhook delivery not found")
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (m *mockWebhookRepository) ClaimDelivery(ctx interface{}, now, leaseUntil time.Time) (*domain.WebhookDelivery, error) {
+	var due []*domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		if (d.Status == domain.DeliveryPending || d.Status == domain.DeliveryInProgress) && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt) })
+	due[0].Status = domain.DeliveryInProgress
+	due[0].NextAttemptAt = &leaseUntil
+	copied := *due[0]
+	return &copied, nil
+}
+
+func (m *mockWebhookRepository) UpdateDelivery(ctx interface{}, delivery *domain.WebhookDelivery) error {
+	copied := *delivery
+	m.deliveries[delivery.ID] = &copied
+	return nil
+}
+
+func (m *mockWebhookRepository) ListDeliveries(ctx interface{}, subscriptionID string, limit int) ([]*domain.WebhookDelivery, error) {
+	deliveries := make([]*domain.WebhookDelivery, 0)
+	for _, d := range m.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			copied := *d
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	return deliveries, nil
+}
+
+// mockWebhookSender records requests and fails for URLs listed in fail
+type mockWebhookSender struct {
+	sent []webhook.Request
+	fail map[string]bool
+}
+
+func (m *mockWebhookSender) Send(ctx context.Context, req webhook.Request) (int, error) {
+	m.sent = append(m.sent, req)
+	if m.fail[req.URL] {
+		return 503, errors.New("endpoint returned status 503")
+	}
+	return 200, nil
+}
+
+func TestWebhookUseCase_CreateSubscription(t *testing.T) {
+	fleets := newMockFleetRepository()
+	fleets.fleets["fleet-1"] = &domain.Fleet{ID: "fleet-1"}
+	uc := NewWebhookUseCase(newMockWebhookRepository(), fleets, &mockWebhookSender{}, WebhookOptions{RequireHTTPS: true}, zap.NewNop()).(*webhookUseCase)
+	uc.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "partner.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "rebind.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("10.0.0.5")}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	tests := []struct {
+		name    string
+		req     CreateWebhookRequest
+		wantErr error
+	}{
+		{name: "generated secret", req: CreateWebhookRequest{URL: "https://partner.example.com/hooks", FleetID: "fleet-1"}},
+		{name: "relative url", req: CreateWebhookRequest{URL: "/hooks"}, wantErr: ErrInvalidWebhookURL},
+		{name: "plain http", req: CreateWebhookRequest{URL: "http://partner.example.com/hooks"}, wantErr: ErrWebhookHTTPSRequired},
+		{name: "loopback", req: CreateWebhookRequest{URL: "https://127.0.0.1:8081/api/v1/admin"}, wantErr: ErrWebhookPrivateTarget},
+		{name: "cloud metadata", req: CreateWebhookRequest{URL: "https://169.254.169.254/latest/meta-data"}, wantErr: ErrWebhookPrivateTarget},
+		{name: "private ipv6", req: CreateWebhookRequest{URL: "https://[fd00::1]/hooks"}, wantErr: ErrWebhookPrivateTarget},
+		{name: "host resolving to a private address", req: CreateWebhookRequest{URL: "https://rebind.example.com/hooks"}, wantErr: ErrWebhookPrivateTarget},
+		{name: "host not resolving yet", req: CreateWebhookRequest{URL: "https://new.example.com/hooks"}},
+		{name: "unknown event", req: CreateWebhookRequest{URL: "https://partner.example.com/hooks", Events: []string{"trip.created"}}, wantErr: ErrInvalidWebhookEvent},
+		{name: "short secret", req: CreateWebhookRequest{URL: "https://partner.example.com/hooks", Secret: "abc"}, wantErr: ErrWebhookSecretTooShort},
+		{name: "unknown fleet", req: CreateWebhookRequest{URL: "https://partner.example.com/hooks", FleetID: "fleet-2"}, wantErr: ErrFleetNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := uc.CreateSubscription(context.Background(), &tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sub.Secret) < minWebhookSecretLength {
+				t.Errorf("expected a generated secret, got %q", sub.Secret)
+			}
+
+			stored, err := uc.GetSubscription(context.Background(), sub.ID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stored.Secret != "" {
+				t.Error("expected the secret to be hidden after creation")
+			}
+		})
+	}
+}
+
+func TestWebhookUseCase_Deliveries(t *testing.T) {
+	now := time.Date(2025, 12, 6, 1, 0, 0, 0, time.UTC)
+	repo := newMockWebhookRepository()
+	sender := &mockWebhookSender{fail: map[string]bool{"https://down.example.com": true}}
+	uc := NewWebhookUseCase(repo, newMockFleetRepository(), sender, WebhookOptions{
+		MaxAttempts: 3,
+		BackoffBase: 10 * time.Second,
+		BackoffMax:  time.Minute,
+	}, zap.NewNop()).(*webhookUseCase)
+	uc.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	fleetSub := &domain.WebhookSubscription{FleetID: "fleet-1", URL: "https://fleet.example.com", Secret: "whsec_fleet", Events: []string{domain.EventDriverSuspended}}
+	globalSub := &domain.WebhookSubscription{URL: "https://down.example.com", Secret: "whsec_global"}
+	otherSub := &domain.WebhookSubscription{FleetID: "fleet-2", URL: "https://other.example.com", Secret: "whsec_other"}
+	for _, sub := range []*domain.WebhookSubscription{fleetSub, globalSub, otherSub} {
+		repo.CreateSubscription(ctx, sub)
+	}
+
+	driver := &domain.Driver{ID: "driver-1", FleetID: "fleet-1"}
+	uc.PublishDriverEvent(ctx, domain.EventDriverUpdated, driver, "")
+	uc.PublishDriverEvent(ctx, domain.EventDriverSuspended, driver, "expired license")
+	if len(repo.deliveries) != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", len(repo.deliveries))
+	}
+
+	attempted, err := uc.DeliverDue(ctx)
+	if err != nil || attempted != 3 {
+		t.Fatalf("expected 3 attempts, got %d (%v)", attempted, err)
+	}
+
+	var event domain.DriverEvent
+	for _, req := range sender.sent {
+		if req.URL == fleetSub.URL {
+			if err := json.Unmarshal(req.Body, &event); err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+		}
+	}
+	if event.Type != domain.EventDriverSuspended || event.Reason != "expired license" || event.Driver.ID != "driver-1" {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	// Failed deliveries back off exponentially and give up after MaxAttempts
+	failing, _ := repo.ListDeliveries(ctx, globalSub.ID, 10)
+	for _, d := range failing {
+		if d.Status != domain.DeliveryPending || d.Attempts != 1 || !d.NextAttemptAt.Equal(now.Add(10*time.Second)) {
+			t.Errorf("unexpected retry schedule: %+v", d)
+		}
+	}
+	now = now.Add(10 * time.Second)
+	uc.DeliverDue(ctx)
+	now = now.Add(20 * time.Second)
+	uc.DeliverDue(ctx)
+	failing, _ = repo.ListDeliveries(ctx, globalSub.ID, 10)
+	for _, d := range failing {
+		if d.Status != domain.DeliveryFailed || d.Attempts != 3 || d.LastStatusCode != 503 {
+			t.Errorf("expected a failed delivery after 3 attempts, got %+v", d)
+		}
+	}
+
+	// A retry gives a failed delivery a fresh set of attempts
+	retried, err := uc.RetryDelivery(ctx, globalSub.ID, failing[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if retried.Status != domain.DeliveryPending || retried.Attempts != 0 {
+		t.Errorf("unexpected retried delivery: %+v", retried)
+	}
+	if _, err := uc.RetryDelivery(ctx, fleetSub.ID, failing[0].ID); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound for another subscription, got %v", err)
+	}
+}
+
+func TestWebhookUseCase_CreateSubscription_AllowPrivateTargets(t *testing.T) {
+	uc := NewWebhookUseCase(newMockWebhookRepository(), newMockFleetRepository(), &mockWebhookSender{}, WebhookOptions{AllowPrivateTargets: true}, zap.NewNop())
+
+	if _, err := uc.CreateSubscription(context.Background(), &CreateWebhookRequest{URL: "http://127.0.0.1:9000/hooks"}); err != nil {
+		t.Errorf("expected a local endpoint to be accepted for development, got %v", err)
+	}
+}
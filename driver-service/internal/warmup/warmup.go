@@ -0,0 +1,151 @@
+// Package warmup holds back readiness after startup until the service can
+// answer queries the way it will once it runs: the indexes queries rely on
+// exist and in-memory caches are loaded. Until then an instance is kept out of
+// rotation instead of serving slow collection scans or cold caches.
+package warmup
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Check is a condition the service must meet before it reports ready
+type Check struct {
+	Name string
+	// Run returns nil once the condition is met
+	Run func(ctx context.Context) error
+}
+
+// CheckStatus reports the progress of a check
+type CheckStatus struct {
+	Name   string `json:"name" example:"indexes"`
+	Passed bool   `json:"passed" example:"false"`
+	// Error is why the latest attempt failed
+	Error    string `json:"error,omitempty" example:"2 indexes missing, 0 mismatched"`
+	Attempts int    `json:"attempts" example:"3"`
+	// ElapsedMs is how long the check took to pass, or has been running
+	ElapsedMs int64 `json:"elapsedMs" example:"10250"`
+}
+
+// Warmup runs checks in order, retrying each until it passes, and reports
+// ready once all have passed
+type Warmup struct {
+	checks []Check
+	retry  time.Duration
+	logger *zap.Logger
+	now    func() time.Time
+
+	ready atomic.Bool
+
+	mu       sync.Mutex
+	statuses []CheckStatus
+	started  []time.Time
+}
+
+// New creates a warm-up of checks retried every retry; without checks it is ready at once
+func New(retry time.Duration, logger *zap.Logger, checks ...Check) *Warmup {
+	if retry <= 0 {
+		retry = 5 * time.Second
+	}
+	w := &Warmup{
+		checks:   checks,
+		retry:    retry,
+		logger:   logger,
+		now:      time.Now,
+		statuses: make([]CheckStatus, len(checks)),
+		started:  make([]time.Time, len(checks)),
+	}
+	for i, check := range checks {
+		w.statuses[i].Name = check.Name
+	}
+	if len(checks) == 0 {
+		w.ready.Store(true)
+	}
+	return w
+}
+
+// Run runs the checks until all have passed or ctx is done
+func (w *Warmup) Run(ctx context.Context) {
+	start := w.now()
+	for i, check := range w.checks {
+		if !w.runCheck(ctx, i, check) {
+			return
+		}
+	}
+	w.ready.Store(true)
+	w.logger.Info("warm-up complete, reporting ready", zap.Duration("elapsed", w.now().Sub(start)))
+}
+
+// runCheck retries a check until it passes and reports whether it did
+func (w *Warmup) runCheck(ctx context.Context, i int, check Check) bool {
+	w.mu.Lock()
+	w.started[i] = w.now()
+	w.mu.Unlock()
+
+	for {
+		err := check.Run(ctx)
+		if ctx.Err() != nil {
+			return false
+		}
+
+		w.mu.Lock()
+		status := &w.statuses[i]
+		status.Attempts++
+		status.ElapsedMs = w.now().Sub(w.started[i]).Milliseconds()
+		if err == nil {
+			status.Passed = true
+			status.Error = ""
+		} else {
+			status.Error = err.Error()
+		}
+		attempts, elapsed := status.Attempts, time.Duration(status.ElapsedMs)*time.Millisecond
+		w.mu.Unlock()
+
+		if err == nil {
+			w.logger.Info("warm-up check passed",
+				zap.String("check", check.Name),
+				zap.Int("attempts", attempts),
+				zap.Duration("elapsed", elapsed),
+			)
+			return true
+		}
+		w.logger.Warn("warm-up check failed, not ready yet",
+			zap.String("check", check.Name),
+			zap.Int("attempts", attempts),
+			zap.Duration("retryIn", w.retry),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(w.retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// Ready reports whether every check has passed
+func (w *Warmup) Ready() bool {
+	return w.ready.Load()
+}
+
+// Status returns the progress of every check, in the order they run
+func (w *Warmup) Status() []CheckStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	statuses := make([]CheckStatus, len(w.statuses))
+	copy(statuses, w.statuses)
+	for i := range statuses {
+		// Checks still running report how long they have been
+		if !statuses[i].Passed && !w.started[i].IsZero() {
+			statuses[i].ElapsedMs = w.now().Sub(w.started[i]).Milliseconds()
+		}
+	}
+	return statuses
+}
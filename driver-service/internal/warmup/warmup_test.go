@@ -0,0 +1,88 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestWarmup_Run(t *testing.T) {
+	var order []string
+	indexAttempts := 0
+	w := New(time.Millisecond, zap.NewNop(),
+		Check{Name: "indexes", Run: func(ctx context.Context) error {
+			order = append(order, "indexes")
+			if indexAttempts++; indexAttempts < 3 {
+				return errors.New("1 indexes missing, 0 mismatched")
+			}
+			return nil
+		}},
+		Check{Name: "geo-cache", Run: func(ctx context.Context) error {
+			order = append(order, "geo-cache")
+			return nil
+		}},
+	)
+	if w.Ready() {
+		t.Fatal("expected a warm-up with checks not to be ready before it ran")
+	}
+	if status := w.Status(); len(status) != 2 || status[0].Name != "indexes" || status[0].Passed || status[0].Attempts != 0 {
+		t.Errorf("unexpected status before running: %+v", status)
+	}
+
+	w.Run(context.Background())
+
+	if !w.Ready() {
+		t.Fatal("expected the warm-up to be ready once every check passed")
+	}
+	// A failing check holds back the ones after it
+	if got, want := strings.Join(order, ","), "indexes,indexes,indexes,geo-cache"; got != want {
+		t.Errorf("checks ran in order %s, want %s", got, want)
+	}
+	status := w.Status()
+	if !status[0].Passed || status[0].Attempts != 3 || status[0].Error != "" {
+		t.Errorf("indexes status = %+v, want passed after 3 attempts", status[0])
+	}
+	if !status[1].Passed || status[1].Attempts != 1 {
+		t.Errorf("geo-cache status = %+v, want passed at once", status[1])
+	}
+}
+
+func TestWarmup_RunStopsWithContext(t *testing.T) {
+	w := New(time.Hour, zap.NewNop(), Check{Name: "indexes", Run: func(ctx context.Context) error {
+		return errors.New("1 indexes missing, 0 mismatched")
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+	// Wait for the first attempt before stopping
+	for w.Status()[0].Attempts == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Run to return once its context is done")
+	}
+	if w.Ready() {
+		t.Error("expected a warm-up whose check never passed not to be ready")
+	}
+	if status := w.Status()[0]; status.Passed || status.Error != "1 indexes missing, 0 mismatched" {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestWarmup_WithoutChecks(t *testing.T) {
+	if !New(time.Second, zap.NewNop()).Ready() {
+		t.Error("expected a warm-up without checks to be ready at once")
+	}
+}
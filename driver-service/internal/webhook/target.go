@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// ErrPrivateTarget is returned for endpoints on loopback, private, link-local
+// or other internal addresses, which partners must not reach through us
+var ErrPrivateTarget = errors.New("webhook endpoint is not a public address")
+
+// internalBlocks are ranges IsPublic rejects that net.IP has no predicate for
+var internalBlocks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // shared address space (carrier-grade NAT)
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved, including broadcast
+	"64:ff9b::/96",  // NAT64, which maps onto IPv4 addresses
+)
+
+// IsPublic reports whether deliveries may be sent to ip: loopback, private,
+// link-local, multicast, unspecified and other internal addresses may not
+func IsPublic(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, block := range internalBlocks {
+		if block.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckHost fails with ErrPrivateTarget when host is, or resolves to, an
+// address that is not public. Lookup errors are returned as they are.
+func CheckHost(ctx context.Context, lookup func(ctx context.Context, host string) ([]net.IPAddr, error), host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublic(ip) {
+			return ErrPrivateTarget
+		}
+		return nil
+	}
+	addrs, err := lookup(ctx, host)
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !IsPublic(addr.IP) {
+			return ErrPrivateTarget
+		}
+	}
+	return nil
+}
+
+// dialPublicOnly refuses connections to addresses that are not public. It
+// runs on the address actually dialled, after DNS resolution, so a host that
+// resolved to a public address at registration cannot be pointed inside later.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(net.ParseIP(host)) {
+		return ErrPrivateTarget
+	}
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	blocks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		blocks[i] = block
+	}
+	return blocks
+}
@@ -0,0 +1,156 @@
+// Package webhook signs and sends webhook deliveries.
+//
+// Every request carries an X-Bitaksi-Signature header of the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256>", where the HMAC is computed with the
+// subscription secret over "<t>.<body>". Receivers should recompute it, compare
+// in constant time and reject stale timestamps to prevent replays.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers set on every delivery
+const (
+	SignatureHeader = "X-Bitaksi-Signature"
+	EventHeader     = "X-Bitaksi-Event"
+	DeliveryHeader  = "X-Bitaksi-Delivery"
+)
+
+// Sign returns the signature header value for body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks a signature header against body. Signatures older than
+// tolerance are rejected; a zero tolerance skips the age check.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) bool {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			sig = value
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return false
+		}
+	}
+	expected, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, mac(secret, ts, body))
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Backoff returns the delay before retry number attempt (starting at 1):
+// base doubled for every earlier attempt, capped at max
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	if delay > max {
+		return max
+	}
+	return delay
+}
+
+// Request is one signed delivery
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Body       []byte
+}
+
+// Sender posts signed deliveries to subscriber endpoints
+type Sender struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewSender creates a sender whose requests time out after timeout. Unless
+// allowPrivate is set it refuses to connect to addresses that are not public,
+// so endpoints cannot reach internal services, including by DNS rebinding.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = dialPublicOnly
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would be dialled instead of the endpoint, bypassing the check
+	transport.Proxy = nil
+	return &Sender{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// Endpoints are registered by partners; redirects could point anywhere
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts the delivery and returns the response status. Any status outside
+// 2xx is returned together with an error so the delivery is retried.
+func (s *Sender) Send(ctx context.Context, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Bitaksi-Webhooks/1.0")
+	req.Header.Set(EventHeader, r.Event)
+	req.Header.Set(DeliveryHeader, r.DeliveryID)
+	req.Header.Set(SignatureHeader, Sign(r.Secret, s.now(), r.Body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	// Drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1765000000, 0)
+	body := []byte(`{"type":"driver.created"}`)
+	header := Sign("whsec_test", now, body)
+
+	if !Verify("whsec_test", header, body, 5*time.Minute, now.Add(time.Minute)) {
+		t.Error("expected a valid signature")
+	}
+	if Verify("whsec_other", header, body, 5*time.Minute, now) {
+		t.Error("expected a wrong secret to fail")
+	}
+	if Verify("whsec_test", header, []byte(`{"type":"driver.updated"}`), 5*time.Minute, now) {
+		t.Error("expected a modified body to fail")
+	}
+	if Verify("whsec_test", header, body, 5*time.Minute, now.Add(10*time.Minute)) {
+		t.Error("expected a stale signature to fail")
+	}
+	if Verify("whsec_test", "v1=abc", body, 0, now) {
+		t.Error("expected a header without timestamp to fail")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{10, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, 10*time.Second, time.Hour); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSender_Send(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify("whsec_test", r.Header.Get(SignatureHeader), body, time.Minute, time.Now()) {
+			t.Error("invalid signature")
+		}
+		if r.Header.Get(EventHeader) != "driver.updated" || r.Header.Get(DeliveryHeader) != "delivery-1" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second, true)
+	req := Request{URL: server.URL, Secret: "whsec_test", Event: "driver.updated", DeliveryID: "delivery-1", Body: []byte(`{}`)}
+
+	if code, err := sender.Send(context.Background(), req); err != nil || code != http.StatusOK {
+		t.Errorf("expected success, got %d %v", code, err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if code, err := sender.Send(context.Background(), req); err == nil || code != http.StatusServiceUnavailable {
+		t.Errorf("expected failure with status 503, got %d %v", code, err)
+	}
+}
+
+func TestSender_RefusesPrivateAddresses(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	// localhost passes no address check until it is dialled
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	_, err := NewSender(time.Second, false).Send(context.Background(), Request{URL: url, Secret: "whsec_test", Body: []byte(`{}`)})
+	if !errors.Is(err, ErrPrivateTarget) || called {
+		t.Errorf("expected the loopback endpoint to be refused, got %v (called %v)", err, called)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "224.0.0.1"} {
+		if IsPublic(net.ParseIP(ip)) {
+			t.Errorf("IsPublic(%s) = true", ip)
+		}
+	}
+	for _, ip := range []string{"93.184.216.34", "8.8.8.8", "2606:4700:4700::1111"} {
+		if !IsPublic(net.ParseIP(ip)) {
+			t.Errorf("IsPublic(%s) = false", ip)
+		}
+	}
+}
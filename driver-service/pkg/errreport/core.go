@@ -0,0 +1,134 @@
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/driver-service/pkg/panics"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Core returns a zap core reporting the error entries of the named loggers,
+// and of the loggers below them ("usecase" covers "usecase.rider"), so the
+// errors services already log are reported without touching each call site.
+// Add it to a logger with zap.WrapCore and zapcore.NewTee.
+//
+// Entries are grouped by logger, message and error type, unless they carry a
+// "fingerprint" field; "culprit" and "requestId" fields are used too.
+func Core(reporter Reporter, loggers []string) zapcore.Core {
+	return &core{reporter: reporter, loggers: loggers}
+}
+
+// WithCore returns logger with Core added
+func WithCore(logger *zap.Logger, reporter Reporter, loggers []string) *zap.Logger {
+	reports := Core(reporter, loggers)
+	return logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, reports)
+	}))
+}
+
+type core struct {
+	reporter Reporter
+	loggers  []string
+	fields   []zapcore.Field
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{
+		reporter: c.reporter,
+		loggers:  c.loggers,
+		fields:   append(append([]zapcore.Field{}, c.fields...), fields...),
+	}
+}
+
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) && c.covers(entry.LoggerName) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// covers reports whether entries of the named logger are reported
+func (c *core) covers(name string) bool {
+	for _, logger := range c.loggers {
+		if name == logger || strings.HasPrefix(name, logger+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var err error
+	for _, field := range append(append([]zapcore.Field{}, c.fields...), fields...) {
+		if field.Type == zapcore.ErrorType && field.Key == "error" {
+			err, _ = field.Interface.(error)
+			continue
+		}
+		field.AddTo(enc)
+	}
+
+	event := FromError(entry.Message, err)
+	event.Fingerprint = panics.Fingerprint(err, []string{entry.LoggerName, entry.Message})
+	event.Logger = entry.LoggerName
+	event.Timestamp = entry.Time
+	if entry.Level >= zapcore.DPanicLevel {
+		event.Level = LevelFatal
+	}
+	if entry.Caller.Defined {
+		event.Culprit = entry.Caller.Function
+	}
+	if err == nil {
+		// Without an error the message is the exception, so the tracker
+		// still shows where it was logged
+		event.Type = "error"
+		event.Value = entry.Message
+	}
+	if entry.Caller.Function != "" {
+		event.Frames = []string{entry.Caller.Function}
+	}
+
+	event.Extra = make(map[string]interface{}, len(enc.Fields))
+	for key, value := range enc.Fields {
+		switch key {
+		case "fingerprint":
+			event.Fingerprint = fmt.Sprint(value)
+		case "culprit":
+			event.Culprit = fmt.Sprint(value)
+		case "requestId":
+			event.Tags = map[string]string{"requestId": fmt.Sprint(value)}
+		default:
+			event.Extra[key] = value
+		}
+	}
+	if entry.Stack != "" {
+		event.Extra["stacktrace"] = entry.Stack
+	}
+
+	c.reporter.Report(event)
+	if entry.Level > zapcore.ErrorLevel {
+		// The process may exit right after the entry is written
+		c.flush()
+	}
+	return nil
+}
+
+// Sync waits briefly for queued events, as loggers are synced before exit
+func (c *core) Sync() error {
+	c.flush()
+	return nil
+}
+
+func (c *core) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c.reporter.Flush(ctx)
+}
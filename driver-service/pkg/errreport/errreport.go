@@ -0,0 +1,171 @@
+// Package errreport sends errors and panics to an error tracker such as
+// Sentry, so they are grouped, counted and alerted on instead of only being
+// logged.
+//
+// Events are tagged with the environment and release, sampled, and scrubbed
+// of personal data before they leave the service: values of fields named
+// like passwords, tokens, phone numbers or e-mail addresses are replaced, and
+// e-mail addresses, phone numbers and bearer tokens are masked wherever they
+// appear in messages.
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/bitaksi/driver-service/pkg/panics"
+	"go.uber.org/zap"
+)
+
+// Level is the severity of an event
+type Level string
+
+// Event levels
+const (
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+// Event is an error or panic to report
+type Event struct {
+	Level Level
+	// Message says what failed, such as the log message
+	Message string
+	// Type and Value describe the error or panic value
+	Type  string
+	Value string
+	// Fingerprint groups events of the same bug; empty lets the tracker group them
+	Fingerprint string
+	// Culprit is the function that failed
+	Culprit string
+	// Frames are the functions the error was raised through, innermost first
+	Frames []string
+	// Logger is the name of the logger the event was logged with
+	Logger string
+	// Tags are indexed by the tracker; Extra is only shown with the event
+	Tags  map[string]string
+	Extra map[string]interface{}
+	// Request is the HTTP request that failed, if any
+	Request   *Request
+	Timestamp time.Time
+}
+
+// Request is the HTTP request an event happened in. The query is left out as
+// it may hold personal data.
+type Request struct {
+	Method string
+	URL    string
+}
+
+// FromPanic returns a fatal event for a recovered panic
+func FromPanic(report *panics.Report) *Event {
+	return &Event{
+		Level:       LevelFatal,
+		Message:     "panic: " + fmt.Sprint(report.Value),
+		Type:        fmt.Sprintf("%T", report.Value),
+		Value:       fmt.Sprint(report.Value),
+		Fingerprint: report.Fingerprint,
+		Culprit:     report.Culprit(),
+		Frames:      report.Frames,
+		Timestamp:   time.Now(),
+	}
+}
+
+// FromError returns an error event for err, grouped by message and error type
+func FromError(message string, err error) *Event {
+	event := &Event{
+		Level:       LevelError,
+		Message:     message,
+		Fingerprint: panics.Fingerprint(err, []string{message}),
+		Timestamp:   time.Now(),
+	}
+	if err != nil {
+		event.Type = fmt.Sprintf("%T", err)
+		event.Value = err.Error()
+	}
+	return event
+}
+
+// Reporter sends events to an error tracker
+type Reporter interface {
+	// Report queues event without blocking; events are dropped when the
+	// reporter is behind
+	Report(event *Event)
+	// Flush waits until queued events are sent or ctx is done
+	Flush(ctx context.Context)
+}
+
+// Nop drops every event; it is the reporter when reporting is disabled
+type Nop struct{}
+
+// Report drops the event
+func (Nop) Report(*Event) {}
+
+// Flush returns at once
+func (Nop) Flush(context.Context) {}
+
+// Options configures a reporter
+type Options struct {
+	// DSN is the tracker's project DSN; empty disables reporting
+	DSN         string
+	Environment string
+	// Release defaults to the VCS revision the binary was built from
+	Release    string
+	ServerName string
+	// SampleRate is the share of events sent, from 0 to 1
+	SampleRate float64
+	// ScrubFields are field names whose values are never sent, on top of the defaults
+	ScrubFields []string
+	// QueueSize bounds the events waiting to be sent; 100 by default
+	QueueSize int
+	// Timeout bounds each request to the tracker; 5 seconds by default
+	Timeout time.Duration
+}
+
+// New returns a Sentry reporter for opts, or Nop when opts has no DSN.
+// Failures to send are logged to logger at warn level.
+func New(opts Options, logger *zap.Logger) (Reporter, error) {
+	if opts.DSN == "" {
+		return Nop{}, nil
+	}
+	if opts.Release == "" {
+		opts.Release = buildRevision()
+	}
+	if opts.ServerName == "" {
+		opts.ServerName, _ = os.Hostname()
+	}
+	return NewSentry(opts, logger)
+}
+
+// Recover is deferred at the top of background goroutines. It reports a
+// panic, waits briefly for it to be sent and panics again, so the process
+// still crashes and restarts as it would without reporting.
+func Recover(reporter Reporter) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	reporter.Report(FromPanic(panics.Capture(value)))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	reporter.Flush(ctx)
+	cancel()
+	panic(value)
+}
+
+// buildRevision returns the VCS revision the binary was built from, if known
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+package errreport
+
+import (
+	"regexp"
+	"strings"
+)
+
+// filtered replaces scrubbed values, as Sentry's own scrubbing does
+const filtered = "[Filtered]"
+
+// defaultScrubFields are the field names, compared in lower case and by
+// substring, whose values are never sent
+var defaultScrubFields = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"apikey", "api_key", "phone", "email", "iban", "card",
+	"firstname", "lastname", "fullname", "plate",
+}
+
+// piiPatterns mask personal data wherever it appears in a value
+var piiPatterns = []*regexp.Regexp{
+	// E-mail addresses
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	// Bearer credentials and JWTs
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	// International and Turkish national phone numbers
+	regexp.MustCompile(`\+\d[\d\s-]{8,14}\d`),
+	regexp.MustCompile(`\b0\d{10}\b`),
+}
+
+// scrubber removes personal data from events
+type scrubber struct {
+	fields []string
+}
+
+func newScrubber(extra []string) *scrubber {
+	fields := append([]string{}, defaultScrubFields...)
+	for _, field := range extra {
+		if field = strings.ToLower(strings.TrimSpace(field)); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return &scrubber{fields: fields}
+}
+
+// sensitive reports whether values of the named field are never sent
+func (s *scrubber) sensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, field := range s.fields {
+		if strings.Contains(name, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// text masks personal data in a value
+func (s *scrubber) text(value string) string {
+	for _, pattern := range piiPatterns {
+		value = pattern.ReplaceAllString(value, filtered)
+	}
+	return value
+}
+
+// value scrubs a field value, descending into maps and slices
+func (s *scrubber) value(name string, value interface{}) interface{} {
+	if s.sensitive(name) {
+		return filtered
+	}
+	switch v := value.(type) {
+	case string:
+		return s.text(v)
+	case map[string]interface{}:
+		scrubbed := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			scrubbed[key] = s.value(key, item)
+		}
+		return scrubbed
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.value(name, item)
+		}
+		return scrubbed
+	}
+	return value
+}
+
+// event scrubs an event in place
+func (s *scrubber) event(event *Event) {
+	event.Message = s.text(event.Message)
+	event.Value = s.text(event.Value)
+	for key, value := range event.Tags {
+		if s.sensitive(key) {
+			event.Tags[key] = filtered
+		} else {
+			event.Tags[key] = s.text(value)
+		}
+	}
+	for key, value := range event.Extra {
+		event.Extra[key] = s.value(key, value)
+	}
+	if event.Request != nil {
+		event.Request.URL = s.text(event.Request.URL)
+	}
+}
@@ -0,0 +1,286 @@
+package errreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sentryClient identifies the sender to Sentry
+const sentryClient = "bitaksi-errreport/1.0"
+
+// Sentry sends events to Sentry, or any tracker that speaks its envelope
+// protocol, from a background goroutine
+type Sentry struct {
+	endpoint   string
+	auth       string
+	opts       Options
+	scrub      *scrubber
+	httpClient *http.Client
+	logger     *zap.Logger
+	// sample returns a number in [0, 1) an event's sample rate is compared to
+	sample func() float64
+
+	queue   chan *Event
+	pending atomic.Int64
+	dropped atomic.Int64
+}
+
+// NewSentry creates a reporter sending to the project of opts.DSN, of the form
+// https://<key>@<host>/<project>
+func NewSentry(opts Options, logger *zap.Logger) (*Sentry, error) {
+	endpoint, auth, err := parseDSN(opts.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		return nil, fmt.Errorf("error report sample rate must be between 0 and 1, got %g", opts.SampleRate)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+
+	s := &Sentry{
+		endpoint:   endpoint,
+		auth:       auth,
+		opts:       opts,
+		scrub:      newScrubber(opts.ScrubFields),
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		logger:     logger,
+		sample:     mathrand.Float64,
+		queue:      make(chan *Event, opts.QueueSize),
+	}
+	go s.run()
+	return s, nil
+}
+
+// parseDSN returns the envelope endpoint and auth header of a DSN
+func parseDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid error report DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", errors.New("invalid error report DSN: scheme must be http or https")
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", errors.New("invalid error report DSN: missing public key")
+	}
+	path := strings.Trim(u.Path, "/")
+	slash := strings.LastIndex(path, "/")
+	project := path[slash+1:]
+	if project == "" {
+		return "", "", errors.New("invalid error report DSN: missing project ID")
+	}
+
+	prefix := ""
+	if slash >= 0 {
+		prefix = "/" + path[:slash]
+	}
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project)
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, u.User.Username())
+	if secret, ok := u.User.Password(); ok && secret != "" {
+		auth += ", sentry_secret=" + secret
+	}
+	return endpoint, auth, nil
+}
+
+// Report samples and scrubs event and queues it to be sent
+func (s *Sentry) Report(event *Event) {
+	if s.opts.SampleRate < 1 && s.sample() >= s.opts.SampleRate {
+		return
+	}
+	s.scrub.event(event)
+
+	s.pending.Add(1)
+	select {
+	case s.queue <- event:
+	default:
+		s.pending.Add(-1)
+		if s.dropped.Add(1)%100 == 1 {
+			s.logger.Warn("error report queue full, dropping events", zap.Int64("dropped", s.dropped.Load()))
+		}
+	}
+}
+
+// Flush waits until the queued events are sent or ctx is done
+func (s *Sentry) Flush(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for s.pending.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run sends queued events one at a time
+func (s *Sentry) run() {
+	for event := range s.queue {
+		if err := s.send(event); err != nil {
+			s.logger.Warn("failed to send error report", zap.Error(err))
+		}
+		s.pending.Add(-1)
+	}
+}
+
+// send posts one event as an envelope
+func (s *Sentry) send(event *Event) error {
+	id, err := newEventID()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(s.payload(id, event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal error report: %w", err)
+	}
+
+	var body bytes.Buffer
+	header, _ := json.Marshal(map[string]string{"event_id": id, "sent_at": time.Now().UTC().Format(time.RFC3339)})
+	item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(item)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return fmt.Errorf("failed to create error report request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send error report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error tracker returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sentryEvent is an event in Sentry's format
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   string                 `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       Level                  `json:"level"`
+	Logger      string                 `json:"logger,omitempty"`
+	Message     string                 `json:"message,omitempty"`
+	Culprit     string                 `json:"culprit,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Release     string                 `json:"release,omitempty"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Fingerprint []string               `json:"fingerprint,omitempty"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+	Exception   *sentryExceptions      `json:"exception,omitempty"`
+	Request     *sentryRequest         `json:"request,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// payload converts event to Sentry's format
+func (s *Sentry) payload(id string, event *Event) *sentryEvent {
+	out := &sentryEvent{
+		EventID:     id,
+		Timestamp:   event.Timestamp.UTC().Format(time.RFC3339Nano),
+		Platform:    "go",
+		Level:       event.Level,
+		Logger:      event.Logger,
+		Message:     event.Message,
+		Culprit:     event.Culprit,
+		Environment: s.opts.Environment,
+		Release:     s.opts.Release,
+		ServerName:  s.opts.ServerName,
+		Tags:        event.Tags,
+		Extra:       event.Extra,
+	}
+	if event.Fingerprint != "" {
+		out.Fingerprint = []string{event.Fingerprint}
+	}
+	if event.Type != "" {
+		exception := sentryException{Type: event.Type, Value: event.Value}
+		if len(event.Frames) > 0 {
+			// Sentry lists frames outermost first
+			frames := make([]sentryFrame, 0, len(event.Frames))
+			for i := len(event.Frames) - 1; i >= 0; i-- {
+				frames = append(frames, frame(event.Frames[i]))
+			}
+			exception.Stacktrace = &sentryStacktrace{Frames: frames}
+		}
+		out.Exception = &sentryExceptions{Values: []sentryException{exception}}
+	}
+	if event.Request != nil {
+		out.Request = &sentryRequest{Method: event.Request.Method, URL: event.Request.URL}
+	}
+	return out
+}
+
+// frame splits a function name such as github.com/bitaksi/x/pkg.(*T).Method
+// into its package and function
+func frame(function string) sentryFrame {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return sentryFrame{Function: function, InApp: true}
+	}
+	module := function[:slash+1+dot]
+	return sentryFrame{
+		Function: function[slash+2+dot:],
+		Module:   module,
+		InApp:    strings.HasPrefix(module, "github.com/bitaksi/"),
+	}
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate event ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
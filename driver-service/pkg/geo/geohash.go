@@ -0,0 +1,122 @@
+// Package geo converts coordinates to and from geohashes.
+//
+// A geohash names a cell of a grid over the earth with base32 characters;
+// every character narrows the cell, so hashes sharing a prefix lie in the
+// same larger cell. Map clients use them to bucket positions into tiles.
+package geo
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxPrecision is the longest geohash encoded; 12 characters are a few centimeters
+const MaxPrecision = 12
+
+// base32 is the geohash alphabet, which leaves out a, i, l and o
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// ErrInvalidGeohash is returned for empty or too long geohashes and those
+// with characters outside the geohash alphabet
+var ErrInvalidGeohash = errors.New("invalid geohash")
+
+// Encode returns the geohash of the cell containing lat, lon with precision
+// characters; precision is clamped to 1 through MaxPrecision
+func Encode(lat, lon float64, precision int) string {
+	if precision < 1 {
+		precision = 1
+	}
+	if precision > MaxPrecision {
+		precision = MaxPrecision
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	hash := make([]byte, 0, precision)
+	// Bits alternate between longitude and latitude, longitude first
+	even := true
+	bit, index := 0, 0
+	for len(hash) < precision {
+		if even {
+			index = index<<1 | halve(&lonRange, lon)
+		} else {
+			index = index<<1 | halve(&latRange, lat)
+		}
+		even = !even
+
+		if bit++; bit == 5 {
+			hash = append(hash, base32[index])
+			bit, index = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// halve keeps the half of span holding value and returns 1 for the upper half
+func halve(span *[2]float64, value float64) int {
+	mid := (span[0] + span[1]) / 2
+	if value >= mid {
+		span[0] = mid
+		return 1
+	}
+	span[1] = mid
+	return 0
+}
+
+// Bounds is the cell a geohash names
+type Bounds struct {
+	MinLat, MaxLat float64
+	MinLon, MaxLon float64
+}
+
+// Center returns the middle of the cell
+func (b Bounds) Center() (lat, lon float64) {
+	return (b.MinLat + b.MaxLat) / 2, (b.MinLon + b.MaxLon) / 2
+}
+
+// DecodeBounds returns the cell a geohash names. Hashes are case insensitive.
+func DecodeBounds(hash string) (Bounds, error) {
+	if hash == "" || len(hash) > MaxPrecision {
+		return Bounds{}, ErrInvalidGeohash
+	}
+
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	even := true
+	for _, char := range strings.ToLower(hash) {
+		index := strings.IndexRune(base32, char)
+		if index < 0 {
+			return Bounds{}, ErrInvalidGeohash
+		}
+		for shift := 4; shift >= 0; shift-- {
+			upper := index>>shift&1 == 1
+			if even {
+				narrow(&lonRange, upper)
+			} else {
+				narrow(&latRange, upper)
+			}
+			even = !even
+		}
+	}
+	return Bounds{MinLat: latRange[0], MaxLat: latRange[1], MinLon: lonRange[0], MaxLon: lonRange[1]}, nil
+}
+
+// narrow keeps the upper or lower half of span
+func narrow(span *[2]float64, upper bool) {
+	mid := (span[0] + span[1]) / 2
+	if upper {
+		span[0] = mid
+	} else {
+		span[1] = mid
+	}
+}
+
+// Decode returns the center of the cell a geohash names
+func Decode(hash string) (lat, lon float64, err error) {
+	bounds, err := DecodeBounds(hash)
+	if err != nil {
+		return 0, 0, err
+	}
+	lat, lon = bounds.Center()
+	return lat, lon, nil
+}
@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, lon  float64
+		precision int
+		expected  string
+	}{
+		{name: "Jutland", lat: 57.64911, lon: 10.40744, precision: 11, expected: "u4pruydqqvj"},
+		{name: "origin", lat: 0, lon: 0, precision: 5, expected: "s0000"},
+		{name: "south west corner", lat: -90, lon: -180, precision: 4, expected: "0000"},
+		{name: "north east corner", lat: 90, lon: 180, precision: 4, expected: "zzzz"},
+		{name: "precision below one", lat: 57.64911, lon: 10.40744, precision: 0, expected: "u"},
+		{name: "precision above the maximum", lat: 57.64911, lon: 10.40744, precision: 20, expected: "u4pruydqqvj8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Encode(tt.lat, tt.lon, tt.precision); got != tt.expected {
+				t.Errorf("Encode(%v, %v, %d) = %q, expected %q", tt.lat, tt.lon, tt.precision, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	lat, lon, err := Decode("ezs42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(lat-42.605) > 0.001 || math.Abs(lon+5.603) > 0.001 {
+		t.Errorf("Decode(ezs42) = %v, %v, expected about 42.605, -5.603", lat, lon)
+	}
+
+	upperLat, upperLon, err := Decode("EZS42")
+	if err != nil || upperLat != lat || upperLon != lon {
+		t.Errorf("Decode is expected to ignore case, got %v, %v, %v", upperLat, upperLon, err)
+	}
+
+	for _, hash := range []string{"", "ezs4a", "u4pruydqqvj8u", "ezs 2"} {
+		if _, _, err := Decode(hash); !errors.Is(err, ErrInvalidGeohash) {
+			t.Errorf("Decode(%q) error = %v, expected ErrInvalidGeohash", hash, err)
+		}
+	}
+}
+
+func TestDecodeBounds_ContainsEncodedPoint(t *testing.T) {
+	points := [][2]float64{{41.0431, 29.0099}, {-33.8688, 151.2093}, {64.1466, -21.9426}, {0, 0}}
+	for _, point := range points {
+		for precision := 1; precision <= MaxPrecision; precision++ {
+			bounds, err := DecodeBounds(Encode(point[0], point[1], precision))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if point[0] < bounds.MinLat || point[0] > bounds.MaxLat || point[1] < bounds.MinLon || point[1] > bounds.MaxLon {
+				t.Errorf("cell %+v of precision %d does not contain %v", bounds, precision, point)
+			}
+		}
+	}
+}
@@ -27,3 +27,22 @@ func Distance(lat1, lon1, lat2, lon2 float64) float64 {
 
 	return earthRadiusKm * c
 }
+
+// DistanceToSegment returns the distance in kilometers from a point to the
+// segment between two others, and how far along the segment (0-1) the nearest
+// point lies. The segment is projected onto a plane around the point, which is
+// accurate for the few kilometers between the waypoints of a route.
+func DistanceToSegment(lat, lon, lat1, lon1, lat2, lon2 float64) (distanceKm, fraction float64) {
+	kmPerDegree := earthRadiusKm * math.Pi / 180
+	scale := math.Cos(lat * math.Pi / 180)
+
+	// Segment ends relative to the point
+	ax, ay := (lon1-lon)*scale*kmPerDegree, (lat1-lat)*kmPerDegree
+	bx, by := (lon2-lon)*scale*kmPerDegree, (lat2-lat)*kmPerDegree
+
+	dx, dy := bx-ax, by-ay
+	if lengthSq := dx*dx + dy*dy; lengthSq > 0 {
+		fraction = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	}
+	return math.Hypot(ax+fraction*dx, ay+fraction*dy), fraction
+}
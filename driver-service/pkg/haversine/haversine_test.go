@@ -54,3 +54,38 @@ func TestDistance(t *testing.T) {
 		})
 	}
 }
+
+func TestDistanceToSegment(t *testing.T) {
+	// A segment running 0.1 degrees east along the 41st parallel (about 8.4 km)
+	lat1, lon1, lat2, lon2 := 41.0, 29.0, 41.0, 29.1
+
+	tests := []struct {
+		name         string
+		lat, lon     float64
+		wantDistance float64
+		wantFraction float64
+	}{
+		{name: "on the segment", lat: 41.0, lon: 29.05, wantDistance: 0, wantFraction: 0.5},
+		{name: "beside the middle", lat: 41.01, lon: 29.05, wantDistance: 1.112, wantFraction: 0.5},
+		{name: "before the start", lat: 41.0, lon: 28.99, wantDistance: 0.839, wantFraction: 0},
+		{name: "past the end", lat: 41.0, lon: 29.12, wantDistance: 1.679, wantFraction: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, fraction := DistanceToSegment(tt.lat, tt.lon, lat1, lon1, lat2, lon2)
+			if math.Abs(distance-tt.wantDistance) > 0.01 {
+				t.Errorf("expected distance %.3f km, got %.3f", tt.wantDistance, distance)
+			}
+			if math.Abs(fraction-tt.wantFraction) > 0.001 {
+				t.Errorf("expected fraction %.3f, got %.3f", tt.wantFraction, fraction)
+			}
+		})
+	}
+
+	// A zero-length segment measures the distance to its single point
+	distance, _ := DistanceToSegment(41.01, 29.0, 41.0, 29.0, 41.0, 29.0)
+	if math.Abs(distance-Distance(41.01, 29.0, 41.0, 29.0)) > 0.01 {
+		t.Errorf("expected the distance to the point, got %.3f", distance)
+	}
+}
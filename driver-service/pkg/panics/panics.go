@@ -0,0 +1,98 @@
+// Package panics describes recovered panics for logs and error trackers.
+//
+// A fingerprint groups panics raised at the same place: it covers the type of
+// the panic value and the functions the panic was raised through, but not the
+// message or line numbers, so the same bug keeps one fingerprint across
+// requests with different IDs and across deploys that move code around.
+package panics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// fingerprintFrames is how many functions, innermost first, a fingerprint covers
+const fingerprintFrames = 5
+
+// Recovered panics on /metrics, in total and by fingerprint. They are kept
+// here so services sharing a process, as in the end-to-end tests, share them.
+var (
+	total         = expvar.NewInt("panics_total")
+	byFingerprint = expvar.NewMap("panics_by_fingerprint")
+)
+
+// Report describes a recovered panic
+type Report struct {
+	Value interface{}
+	// Fingerprint is the same for panics of the same type raised through the same functions
+	Fingerprint string
+	// Frames are the functions the panic was raised through, innermost first,
+	// without the runtime's; the first is the culprit
+	Frames []string
+	// Stack is the stack of the goroutine that panicked
+	Stack []byte
+}
+
+// Culprit returns the function that panicked, or "" when it is unknown
+func (r *Report) Culprit() string {
+	if len(r.Frames) == 0 {
+		return ""
+	}
+	return r.Frames[0]
+}
+
+// Capture describes the panic of value. Call it from the deferred function
+// that recovered value, while the stack still holds the panic; elsewhere the
+// report has no frames and the fingerprint only covers the type.
+func Capture(value interface{}) *Report {
+	pcs := make([]uintptr, 64)
+	// Skip runtime.Callers and Capture
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var functions []string
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			// Frames below gopanic are where the panic was raised; runtime
+			// frames such as sigpanic for a nil dereference say nothing of the bug
+			functions = append(functions, frame.Function)
+		}
+		if !more || len(functions) == fingerprintFrames {
+			break
+		}
+	}
+
+	return &Report{
+		Value:       value,
+		Fingerprint: Fingerprint(value, functions),
+		Frames:      functions,
+		Stack:       debug.Stack(),
+	}
+}
+
+// Count counts a recovered panic on /metrics
+func Count(report *Report) {
+	total.Add(1)
+	byFingerprint.Add(report.Fingerprint, 1)
+}
+
+// Fingerprint returns the fingerprint of a panic of value raised through
+// functions, innermost first
+func Fingerprint(value interface{}, functions []string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%T", value)
+	for _, function := range functions {
+		fmt.Fprintf(hash, "\n%s", function)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
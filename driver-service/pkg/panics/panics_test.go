@@ -0,0 +1,98 @@
+package panics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// capturePanic runs fn and captures the panic it raises
+func capturePanic(fn func()) (report *Report) {
+	defer func() {
+		if value := recover(); value != nil {
+			report = Capture(value)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func lookUp(items []int, i int) int {
+	return items[i]
+}
+
+func failWith(message string) {
+	panic(errors.New(message))
+}
+
+func TestCapture(t *testing.T) {
+	outOfRange := func(items []int, i int) *Report {
+		return capturePanic(func() { lookUp(items, i) })
+	}
+	first := outOfRange([]int{1, 2, 3}, 5)
+	second := outOfRange(nil, 7)
+	if first == nil || second == nil {
+		t.Fatal("expected both panics to be captured")
+	}
+
+	// Messages differ but the place is the same
+	if first.Fingerprint != second.Fingerprint {
+		t.Errorf("expected the same fingerprint, got %s and %s", first.Fingerprint, second.Fingerprint)
+	}
+	if len(first.Fingerprint) != 16 {
+		t.Errorf("expected a 16 character fingerprint, got %q", first.Fingerprint)
+	}
+	if culprit := first.Culprit(); !strings.HasSuffix(culprit, "panics.lookUp") {
+		t.Errorf("expected lookUp as the culprit, got %q in %v", culprit, first.Frames)
+	}
+	if len(first.Frames) > fingerprintFrames {
+		t.Errorf("expected at most %d frames, got %d", fingerprintFrames, len(first.Frames))
+	}
+	for _, frame := range first.Frames {
+		if strings.HasPrefix(frame, "runtime.") {
+			t.Errorf("expected no runtime frames, got %v", first.Frames)
+		}
+	}
+	if !strings.Contains(string(first.Stack), "lookUp") {
+		t.Errorf("expected the stack to show where the panic was raised:\n%s", first.Stack)
+	}
+
+	// Another place, or another type at the same place, is another fingerprint
+	notLoaded := func(id int) *Report {
+		return capturePanic(func() { failWith(fmt.Sprintf("driver %d not loaded", id)) })
+	}
+	elsewhere := notLoaded(42)
+	if elsewhere.Fingerprint == first.Fingerprint {
+		t.Error("expected panics raised elsewhere to have another fingerprint")
+	}
+	if culprit := elsewhere.Culprit(); !strings.HasSuffix(culprit, "panics.failWith") {
+		t.Errorf("expected failWith as the culprit, got %q", culprit)
+	}
+	if again := notLoaded(43); again.Fingerprint != elsewhere.Fingerprint {
+		t.Error("expected the same fingerprint for another message")
+	}
+	if Fingerprint("a string", elsewhere.Frames) == elsewhere.Fingerprint {
+		t.Error("expected the panic value type to be part of the fingerprint")
+	}
+}
+
+func TestCount(t *testing.T) {
+	report := &Report{Fingerprint: "0123456789abcdef"}
+	before := total.Value()
+	Count(report)
+	Count(report)
+	if total.Value() != before+2 || byFingerprint.Get(report.Fingerprint).String() != "2" {
+		t.Errorf("expected 2 panics counted, got %d in total and %v", total.Value()-before, byFingerprint.Get(report.Fingerprint))
+	}
+}
+
+func TestCapture_OutsidePanic(t *testing.T) {
+	report := Capture(fmt.Errorf("not panicking"))
+	if len(report.Frames) != 0 || report.Culprit() != "" {
+		t.Errorf("expected no frames outside a panic, got %v", report.Frames)
+	}
+	if report.Fingerprint != Fingerprint(fmt.Errorf("other"), nil) {
+		t.Error("expected the fingerprint to only cover the type outside a panic")
+	}
+}
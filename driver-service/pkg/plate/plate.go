@@ -0,0 +1,183 @@
+// Package plate parses and validates Turkish vehicle registration plates.
+//
+// A plate is a province code (01-81), a group of one to three letters and a
+// group of digits whose length depends on the letters:
+//
+//	99 X 9999
+//	99 XX 999, 99 XX 9999
+//	99 XXX 99, 99 XXX 999
+//
+// Letters come from the Turkish plate alphabet, which has no Q, W or X and no
+// Turkish-specific characters (Ç, Ğ, İ, Ö, Ş, Ü).
+package plate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Province codes run from Adana (01) to Düzce (81)
+const (
+	MinProvince = 1
+	MaxProvince = 81
+)
+
+// Validation error codes
+const (
+	CodeRequired   = "required"
+	CodeFormat     = "invalid_format"
+	CodeProvince   = "invalid_province"
+	CodeLetters    = "invalid_letters"
+	CodeDigitCount = "invalid_digit_count"
+)
+
+const (
+	allowedLetters = "ABCDEFGHIJKLMNOPRSTUVYZ"
+	separators     = "-."
+	formatHint     = "province code (01-81), 1-3 letters and 2-4 digits (e.g., 34ABC123)"
+	minLength      = 2 + 1 + 2
+	maxLength      = 2 + 3 + 4
+)
+
+// digitCounts lists the allowed digit group lengths by letter group length
+var digitCounts = map[int][]int{
+	1: {4},
+	2: {3, 4},
+	3: {2, 3},
+}
+
+// ValidationError describes why a plate was rejected
+type ValidationError struct {
+	Plate  string `json:"plate"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+func (e *ValidationError) Error() string {
+	if e.Code == CodeRequired {
+		return "plate is required"
+	}
+	return "plate must be in format: " + formatHint + "; " + e.Reason
+}
+
+// Plate is a parsed registration plate
+type Plate struct {
+	Province int
+	Letters  string
+	Digits   string
+}
+
+// String returns the canonical form without spaces, e.g. "34ABC123"
+func (p Plate) String() string {
+	return fmt.Sprintf("%02d%s%s", p.Province, p.Letters, p.Digits)
+}
+
+// Display returns the spaced form printed on the plate, e.g. "34 ABC 123"
+func (p Plate) Display() string {
+	return fmt.Sprintf("%02d %s %s", p.Province, p.Letters, p.Digits)
+}
+
+// Parse validates a plate and returns its parts. Case, spaces, hyphens and
+// dots are ignored, so "34 abc-123" parses like "34ABC123".
+func Parse(s string) (Plate, error) {
+	compact := compact(s)
+	if compact == "" {
+		return Plate{}, &ValidationError{Plate: s, Code: CodeRequired, Reason: "plate is empty"}
+	}
+	if len(compact) < minLength || len(compact) > maxLength {
+		return Plate{}, invalid(s, CodeFormat, fmt.Sprintf("%d characters is not a valid plate length", len(compact)))
+	}
+
+	province, rest := compact[:2], compact[2:]
+	if !isDigits(province) {
+		return Plate{}, invalid(s, CodeFormat, "plate must start with a two-digit province code")
+	}
+	code, _ := strconv.Atoi(province)
+	if code < MinProvince || code > MaxProvince {
+		return Plate{}, invalid(s, CodeProvince, fmt.Sprintf("province code %s is not between 01 and 81", province))
+	}
+
+	letterEnd := strings.IndexFunc(rest, unicode.IsDigit)
+	if letterEnd <= 0 {
+		return Plate{}, invalid(s, CodeFormat, "province code must be followed by letters and then digits")
+	}
+	letters, digits := rest[:letterEnd], rest[letterEnd:]
+	if !isDigits(digits) {
+		return Plate{}, invalid(s, CodeFormat, "letters must be followed only by digits")
+	}
+	if len(letters) > 3 {
+		return Plate{}, invalid(s, CodeLetters, fmt.Sprintf("letter group %s is longer than 3 letters", letters))
+	}
+	for _, r := range letters {
+		if !strings.ContainsRune(allowedLetters, r) {
+			return Plate{}, invalid(s, CodeLetters, fmt.Sprintf("letter %q is not used on Turkish plates", r))
+		}
+	}
+
+	allowed := digitCounts[len(letters)]
+	if !containsInt(allowed, len(digits)) {
+		return Plate{}, invalid(s, CodeDigitCount, fmt.Sprintf("%d letter(s) must be followed by %s digits, got %d",
+			len(letters), joinInts(allowed, " or "), len(digits)))
+	}
+
+	return Plate{Province: code, Letters: letters, Digits: digits}, nil
+}
+
+// Normalize validates a plate and returns its canonical form
+func Normalize(s string) (string, error) {
+	p, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// Validate reports whether the plate is valid
+func Validate(s string) error {
+	_, err := Parse(s)
+	return err
+}
+
+func invalid(plate, code, reason string) *ValidationError {
+	return &ValidationError{Plate: plate, Code: code, Reason: reason}
+}
+
+// compact uppercases the plate and drops separators
+func compact(s string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(s) {
+		if unicode.IsSpace(r) || strings.ContainsRune(separators, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func joinInts(values []int, sep string) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, sep)
+}
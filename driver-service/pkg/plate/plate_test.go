@@ -0,0 +1,74 @@
+package plate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		plate    string
+		expected string
+		code     string
+	}{
+		{name: "one letter four digits", plate: "34A1234", expected: "34A1234"},
+		{name: "two letters three digits", plate: "06AB123", expected: "06AB123"},
+		{name: "two letters four digits", plate: "35AB1234", expected: "35AB1234"},
+		{name: "three letters two digits", plate: "01ABC12", expected: "01ABC12"},
+		{name: "three letters three digits", plate: "81ABC123", expected: "81ABC123"},
+		{name: "spacing and case are normalized", plate: " 34 abc 123 ", expected: "34ABC123"},
+		{name: "hyphens and dots are ignored", plate: "34-AB.123", expected: "34AB123"},
+		{name: "empty", plate: "  ", code: CodeRequired},
+		{name: "province 00", plate: "00ABC123", code: CodeProvince},
+		{name: "province 82", plate: "82ABC123", code: CodeProvince},
+		{name: "three digit province", plate: "345ABC123", code: CodeFormat},
+		{name: "no letters", plate: "341234", code: CodeFormat},
+		{name: "letters after digits", plate: "34AB12C", code: CodeFormat},
+		{name: "too many letters", plate: "34ABCD12", code: CodeLetters},
+		{name: "letter not in plate alphabet", plate: "34AXB123", code: CodeLetters},
+		{name: "turkish letter", plate: "34ÇB123", code: CodeLetters},
+		{name: "one letter three digits", plate: "34A123", code: CodeDigitCount},
+		{name: "two letters two digits", plate: "34AB12", code: CodeDigitCount},
+		{name: "three letters four digits", plate: "34ABC1234", code: CodeDigitCount},
+		{name: "too long", plate: "34ABC12345", code: CodeFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.plate)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.expected {
+					t.Errorf("expected %s, got %s", tt.expected, got)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if verr.Code != tt.code {
+				t.Errorf("expected code %s, got %s (%v)", tt.code, verr.Code, err)
+			}
+		})
+	}
+}
+
+func TestPlate_Display(t *testing.T) {
+	p, err := Parse("6ab123")
+	if err == nil {
+		t.Fatalf("expected single-digit province to be rejected, got %+v", p)
+	}
+
+	p, err = Parse("06ab123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Province != 6 || p.Display() != "06 AB 123" {
+		t.Errorf("unexpected plate %+v (%s)", p, p.Display())
+	}
+}
@@ -0,0 +1,144 @@
+// Package testenv starts the backing services integration tests run against.
+//
+// Each service runs in a throwaway Docker container started on first use and
+// removed by Close, so a test run needs Docker but no manually started
+// database. Setting MONGODB_TEST_URI uses that MongoDB instead of a container.
+//
+// Packages share one environment per test binary:
+//
+//	var env = testenv.New()
+//
+//	func TestMain(m *testing.M) {
+//		code := m.Run()
+//		env.Close()
+//		os.Exit(code)
+//	}
+package testenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoImage and MongoTag select the MongoDB image; they match docker-compose.yml
+const (
+	MongoImage = "mongo"
+	MongoTag   = "7.0"
+)
+
+// startTimeout bounds how long a container may take to accept connections
+const startTimeout = 2 * time.Minute
+
+// ErrDockerUnavailable is returned when a service needs a container but Docker cannot be reached
+var ErrDockerUnavailable = errors.New("docker is not available")
+
+// Env holds the services started for a test run
+type Env struct {
+	mu        sync.Mutex
+	pool      *dockertest.Pool
+	poolErr   error
+	resources []*dockertest.Resource
+	mongoURI  string
+	mongoErr  error
+}
+
+// New creates an environment; nothing is started until a service is asked for
+func New() *Env {
+	return &Env{}
+}
+
+// MongoURI returns the connection string of a MongoDB that accepts
+// connections, starting a container on first use
+func (e *Env) MongoURI() (string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.mongoURI != "" || e.mongoErr != nil {
+		return e.mongoURI, e.mongoErr
+	}
+	if uri := os.Getenv("MONGODB_TEST_URI"); uri != "" {
+		e.mongoURI = uri
+		return uri, nil
+	}
+
+	e.mongoURI, e.mongoErr = e.startMongo()
+	return e.mongoURI, e.mongoErr
+}
+
+func (e *Env) startMongo() (string, error) {
+	pool, err := e.dockerPool()
+	if err != nil {
+		return "", err
+	}
+
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: MongoImage,
+		Tag:        MongoTag,
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start mongodb: %w", err)
+	}
+	e.resources = append(e.resources, resource)
+	// A test binary killed by a timeout never calls Close; Docker stops the container after half an hour
+	resource.Expire(30 * 60)
+
+	uri := "mongodb://" + resource.GetHostPort("27017/tcp")
+	if err := pool.Retry(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+		if err != nil {
+			return err
+		}
+		defer client.Disconnect(ctx)
+		return client.Ping(ctx, nil)
+	}); err != nil {
+		return "", fmt.Errorf("mongodb did not become ready: %w", err)
+	}
+	return uri, nil
+}
+
+// dockerPool connects to Docker once
+func (e *Env) dockerPool() (*dockertest.Pool, error) {
+	if e.pool != nil || e.poolErr != nil {
+		return e.pool, e.poolErr
+	}
+	pool, err := dockertest.NewPool("")
+	if err == nil {
+		err = pool.Client.Ping()
+	}
+	if err != nil {
+		e.poolErr = fmt.Errorf("%w: %v", ErrDockerUnavailable, err)
+		return nil, e.poolErr
+	}
+	pool.MaxWait = startTimeout
+	e.pool = pool
+	return pool, nil
+}
+
+// Close removes every container the environment started
+func (e *Env) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var errs []error
+	for _, resource := range e.resources {
+		if err := e.pool.Purge(resource); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	e.resources = nil
+	return errors.Join(errs...)
+}
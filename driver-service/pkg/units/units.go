@@ -0,0 +1,77 @@
+// Package units converts distances between the units clients ask for.
+//
+// Distances are computed and stored in kilometers; they are only converted
+// for presentation, rounded to about a meter in every unit.
+package units
+
+import (
+	"errors"
+	"math"
+)
+
+// Unit is a unit of distance
+type Unit string
+
+// Supported units
+const (
+	Kilometers Unit = "km"
+	Meters     Unit = "m"
+	Miles      Unit = "mi"
+)
+
+// kmPerMile is the length of an international mile
+const kmPerMile = 1.609344
+
+// ErrUnknownUnit is returned for units other than km, m and mi
+var ErrUnknownUnit = errors.New("units must be km, m or mi")
+
+// Parse returns the unit named by s; an empty s is kilometers
+func Parse(s string) (Unit, error) {
+	switch unit := Unit(s); unit {
+	case "":
+		return Kilometers, nil
+	case Kilometers, Meters, Miles:
+		return unit, nil
+	}
+	return "", ErrUnknownUnit
+}
+
+// FromKm converts a distance in kilometers to the unit
+func (u Unit) FromKm(km float64) float64 {
+	switch u {
+	case Meters:
+		return km * 1000
+	case Miles:
+		return km / kmPerMile
+	}
+	return km
+}
+
+// ToKm converts a distance in the unit to kilometers
+func (u Unit) ToKm(distance float64) float64 {
+	switch u {
+	case Meters:
+		return distance / 1000
+	case Miles:
+		return distance * kmPerMile
+	}
+	return distance
+}
+
+// Round rounds a distance in the unit to about a meter: whole meters, three
+// decimals of a kilometer or four of a mile
+func (u Unit) Round(distance float64) float64 {
+	scale := 1000.0
+	switch u {
+	case Meters:
+		scale = 1
+	case Miles:
+		scale = 10000
+	}
+	return math.Round(distance*scale) / scale
+}
+
+// Convert converts a distance in kilometers to the unit, rounded
+func (u Unit) Convert(km float64) float64 {
+	return u.Round(u.FromKm(km))
+}
@@ -0,0 +1,56 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Unit
+	}{
+		{input: "", expected: Kilometers},
+		{input: "km", expected: Kilometers},
+		{input: "m", expected: Meters},
+		{input: "mi", expected: Miles},
+	}
+	for _, tt := range tests {
+		if got, err := Parse(tt.input); err != nil || got != tt.expected {
+			t.Errorf("Parse(%q) = %q, %v, expected %q", tt.input, got, err, tt.expected)
+		}
+	}
+
+	for _, input := range []string{"KM", "miles", "ft"} {
+		if _, err := Parse(input); !errors.Is(err, ErrUnknownUnit) {
+			t.Errorf("Parse(%q) error = %v, expected ErrUnknownUnit", input, err)
+		}
+	}
+}
+
+func TestUnit_Convert(t *testing.T) {
+	tests := []struct {
+		unit     Unit
+		km       float64
+		expected float64
+	}{
+		{unit: Kilometers, km: 1.23456, expected: 1.235},
+		{unit: Meters, km: 1.23456, expected: 1235},
+		{unit: Miles, km: 1.609344, expected: 1},
+		{unit: Miles, km: 5, expected: 3.1069},
+	}
+	for _, tt := range tests {
+		if got := tt.unit.Convert(tt.km); got != tt.expected {
+			t.Errorf("%s.Convert(%v) = %v, expected %v", tt.unit, tt.km, got, tt.expected)
+		}
+	}
+}
+
+func TestUnit_ToKm(t *testing.T) {
+	for _, unit := range []Unit{Kilometers, Meters, Miles} {
+		if got := unit.ToKm(unit.FromKm(7.4)); math.Abs(got-7.4) > 1e-9 {
+			t.Errorf("%s round trip of 7.4 km gave %v", unit, got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestAdminPort checks that ADMIN_PORT moves probes, the admin API and the
+// debug endpoints off the public router
+func TestAdminPort(t *testing.T) {
+	cfg := config.Load()
+	cfg.Admin.Token = "admin-token"
+	cfg.Usage.Enabled = false
+	cfg.Server.AdminPort = "9090"
+	levels, err := logging.NewLevels("error", nil)
+	require.NoError(t, err)
+	gw, err := newGateway(cfg, zap.NewNop(), levels)
+	require.NoError(t, err)
+	defer gw.flushUsage()
+	require.NotNil(t, gw.admin)
+
+	serve := func(router http.Handler, method, path, adminToken string) int {
+		req := httptest.NewRequest(method, path, nil)
+		if adminToken != "" {
+			req.Header.Set("X-Admin-Token", adminToken)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for _, path := range []string{"/health", "/ready", "/admin/system", "/metrics", "/debug/pprof/"} {
+		assert.Equal(t, http.StatusNotFound, serve(gw.router, http.MethodGet, path, "admin-token"), "public router serves %s", path)
+	}
+	assert.Equal(t, http.StatusOK, serve(gw.admin, http.MethodGet, "/health", ""))
+	assert.Equal(t, http.StatusOK, serve(gw.admin, http.MethodGet, "/ready", ""))
+	assert.Equal(t, http.StatusOK, serve(gw.admin, http.MethodGet, "/metrics", ""))
+	assert.Equal(t, http.StatusOK, serve(gw.admin, http.MethodGet, "/debug/pprof/heap", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve(gw.admin, http.MethodGet, "/admin/system", ""), "the admin API still needs the admin token")
+	assert.Equal(t, http.StatusNotFound, serve(gw.admin, http.MethodGet, "/drivers/d1", ""), "admin router serves the public API")
+
+	assert.Empty(t, policy.Default().Unused(registeredRoutes(gw.router, gw.admin)), "rules that match no route")
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/driver-service/app"
+	"github.com/bitaksi/driver-service/pkg/testenv"
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// env starts the MongoDB the end-to-end suite runs against once per test run
+var env = testenv.New()
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if err := env.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to remove test containers: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// smsOutbox stands in for the SMS gateway and keeps the messages sent to each phone
+type smsOutbox struct {
+	mu       sync.Mutex
+	messages map[string][]string
+}
+
+func (o *smsOutbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		To      string `json:"to"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[payload.To] = append(o.messages[payload.To], payload.Message)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (o *smsOutbox) last(phone string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	messages := o.messages[phone]
+	if len(messages) == 0 {
+		return ""
+	}
+	return messages[len(messages)-1]
+}
+
+// stack is the gateway and driver service running in-process
+type stack struct {
+	gateway *httptest.Server
+	sms     *smsOutbox
+	token   string
+}
+
+// startStack boots the driver service against a fresh database and the gateway in front of it
+func startStack(t *testing.T) *stack {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("end-to-end suite skipped in short mode")
+	}
+	uri, err := env.MongoURI()
+	if errors.Is(err, testenv.ErrDockerUnavailable) {
+		t.Skipf("%v; set MONGODB_TEST_URI to run against a running MongoDB", err)
+	}
+	require.NoError(t, err)
+
+	outbox := &smsOutbox{messages: make(map[string][]string)}
+	smsServer := httptest.NewServer(outbox)
+	t.Cleanup(smsServer.Close)
+
+	dsCfg := app.LoadConfig()
+	dsCfg.MongoDB.URI = uri
+	dsCfg.MongoDB.Database = fmt.Sprintf("taxihub_e2e_%d", time.Now().UnixNano())
+	dsCfg.SMS.Provider = "http"
+	dsCfg.SMS.URL = smsServer.URL
+	driverService, err := app.New(dsCfg, zap.NewNop(), nil)
+	require.NoError(t, err)
+	driverService.Start()
+	dsServer := httptest.NewServer(driverService.Handler())
+	t.Cleanup(func() {
+		dsServer.Close()
+		driverService.Close(context.Background())
+	})
+
+	gwCfg := config.Load()
+	gwCfg.DriverService.BaseURL = dsServer.URL
+	gwCfg.JWT.Enabled = true
+	gwCfg.APIKey.Enabled = false
+	gwCfg.RateLimit.Enabled = false
+	gwCfg.Usage.Enabled = false
+	levels, err := logging.NewLevels("error", nil)
+	require.NoError(t, err)
+	gw, err := newGateway(gwCfg, zap.NewNop(), levels)
+	require.NoError(t, err)
+	gwServer := httptest.NewServer(gw.router)
+	t.Cleanup(gwServer.Close)
+
+	s := &stack{gateway: gwServer, sms: outbox}
+	var login struct {
+		Token string `json:"token"`
+	}
+	s.do(t, http.MethodPost, "/auth/login", map[string]string{"username": "dispatcher", "password": "secret"}, http.StatusOK, &login)
+	require.NotEmpty(t, login.Token)
+	s.token = login.Token
+	return s
+}
+
+// do sends a request through the gateway and decodes the response into out
+func (s *stack) do(t *testing.T, method, path string, body interface{}, wantStatus int, out interface{}) {
+	t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, s.gateway.URL+path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&raw)
+	require.Equal(t, wantStatus, resp.StatusCode, "%s %s: %s", method, path, raw)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(raw, out))
+	}
+}
+
+var verificationCode = regexp.MustCompile(`\d{4,8}`)
+
+func TestEndToEnd_DriverGoesOnShiftAndCompletesTrip(t *testing.T) {
+	s := startStack(t)
+	pickup := map[string]float64{"lat": 41.0431, "lon": 29.0099}
+	dropoff := map[string]float64{"lat": 40.9903, "lon": 29.0290}
+
+	// Register a driver through the gateway
+	var driver struct {
+		ID            string `json:"id"`
+		Plate         string `json:"plate"`
+		PhoneVerified bool   `json:"phoneVerified"`
+	}
+	s.do(t, http.MethodPost, "/drivers", map[string]interface{}{
+		"firstName": "Ahmet",
+		"lastName":  "Demir",
+		"plate":     "34 abc 123",
+		"taksiType": "sari",
+		"carBrand":  "Toyota",
+		"carModel":  "Corolla",
+		"lat":       41.0435,
+		"lon":       29.0102,
+		"phone":     "+905321234567",
+	}, http.StatusCreated, &driver)
+	require.NotEmpty(t, driver.ID)
+	assert.Equal(t, "34ABC123", driver.Plate)
+
+	// Drivers cannot go on shift before their phone is verified
+	s.do(t, http.MethodPut, "/drivers/"+driver.ID+"/availability", map[string]bool{"available": true}, http.StatusConflict, nil)
+
+	s.do(t, http.MethodPost, "/drivers/"+driver.ID+"/verify-phone/send", nil, http.StatusAccepted, nil)
+	code := verificationCode.FindString(s.sms.last("+905321234567"))
+	require.NotEmpty(t, code, "expected a verification code by SMS")
+	s.do(t, http.MethodPost, "/drivers/"+driver.ID+"/verify-phone", map[string]string{"code": code}, http.StatusOK, &driver)
+	assert.True(t, driver.PhoneVerified)
+	s.do(t, http.MethodPut, "/drivers/"+driver.ID+"/availability", map[string]bool{"available": true}, http.StatusOK, nil)
+
+	// Riders nearby find the driver
+	var nearby []struct {
+		ID         string  `json:"id"`
+		DistanceKm float64 `json:"distanceKm"`
+	}
+	s.do(t, http.MethodGet, "/drivers/nearby?lat=41.0431&lon=29.0099&taksiType=sari", nil, http.StatusOK, &nearby)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, driver.ID, nearby[0].ID)
+	assert.Less(t, nearby[0].DistanceKm, 0.1)
+
+	// A trip is quoted, offered to the driver, accepted and completed
+	var estimate struct {
+		Amount float64 `json:"amount"`
+	}
+	s.do(t, http.MethodPost, "/trips/estimate", map[string]interface{}{"pickup": pickup, "dropoff": dropoff}, http.StatusOK, &estimate)
+	assert.Greater(t, estimate.Amount, 0.0)
+
+	var trip struct {
+		ID              string `json:"id"`
+		Status          string `json:"status"`
+		OfferedDriverID string `json:"offeredDriverId"`
+		DriverID        string `json:"driverId"`
+		Estimate        *struct {
+			Amount float64 `json:"amount"`
+		} `json:"estimate"`
+	}
+	s.do(t, http.MethodPost, "/trips", map[string]interface{}{"pickup": pickup, "dropoff": dropoff, "taxiType": "sari"}, http.StatusCreated, &trip)
+	require.Equal(t, "offered", trip.Status)
+	assert.Equal(t, driver.ID, trip.OfferedDriverID)
+	require.NotNil(t, trip.Estimate)
+	assert.Equal(t, estimate.Amount, trip.Estimate.Amount)
+
+	s.do(t, http.MethodPost, "/trips/"+trip.ID+"/accept", map[string]string{"driverId": driver.ID}, http.StatusOK, &trip)
+	assert.Equal(t, "accepted", trip.Status)
+	s.do(t, http.MethodPost, "/trips/"+trip.ID+"/complete", map[string]interface{}{"distanceKm": 7.4, "rating": 5}, http.StatusOK, &trip)
+	assert.Equal(t, "completed", trip.Status)
+
+	s.do(t, http.MethodGet, "/trips/"+trip.ID, nil, http.StatusOK, &trip)
+	assert.Equal(t, "completed", trip.Status)
+	assert.Equal(t, driver.ID, trip.DriverID)
+}
+
+func TestEndToEnd_ProtectedRoutesNeedAToken(t *testing.T) {
+	s := startStack(t)
+	s.token = ""
+
+	s.do(t, http.MethodPost, "/drivers", map[string]string{"firstName": "Ahmet"}, http.StatusUnauthorized, nil)
+	s.do(t, http.MethodPost, "/trips", map[string]interface{}{"pickup": map[string]float64{"lat": 41.0431, "lon": 29.0099}}, http.StatusUnauthorized, nil)
+	s.do(t, http.MethodGet, "/drivers/nearby?lat=41.0431&lon=29.0099", nil, http.StatusOK, nil)
+}
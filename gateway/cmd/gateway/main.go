@@ -1,23 +1,44 @@
 package main
 
+//go:generate swag init -d ../.. -g cmd/gateway/main.go -o ../../docs --parseDependency --parseInternal
+
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	_ "github.com/bitaksi/gateway/docs" // swagger docs
+	"github.com/bitaksi/driver-service/pkg/errreport"
+	"github.com/bitaksi/gateway/docs"
+	"github.com/bitaksi/gateway/internal/account"
+	"github.com/bitaksi/gateway/internal/adaptive"
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/coalesce"
 	"github.com/bitaksi/gateway/internal/config"
 	"github.com/bitaksi/gateway/internal/handler"
+	"github.com/bitaksi/gateway/internal/hedge"
+	"github.com/bitaksi/gateway/internal/lifecycle"
+	"github.com/bitaksi/gateway/internal/logging"
 	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/oidc"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/redact"
+	"github.com/bitaksi/gateway/internal/schema"
 	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/slo"
+	"github.com/bitaksi/gateway/internal/tap"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/bitaksi/gateway/internal/usage"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
+	"golang.org/x/net/webdav"
 )
 
 // @title Gateway API
@@ -39,32 +60,33 @@ import (
 // @host localhost:8080
 // @BasePath /
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		runOpenAPI(os.Args[2:])
+		return
+	}
+
 	// Load configuration
 	cfg := config.Load()
 
-	// Initialize logger
-	logger := initLogger(cfg.Logging.Level)
+	// Initialize logger; loggers are named after the package they serve so
+	// LOG_LEVELS and /admin/loglevel can tune them separately
+	logger, logLevels := initLogger(cfg.Logging)
 	defer logger.Sync()
 
-	// Initialize driver service client
-	driverServiceClient := service.NewDriverServiceClient(cfg.DriverService.BaseURL, logger)
-
-	// Initialize handlers
-	driverHandler := handler.NewDriverHandler(driverServiceClient, logger)
-	authHandler := handler.NewAuthHandler(cfg, logger)
-
-	// Initialize rate limiter
-	rateLimiter := middleware.NewRateLimiter(&cfg.RateLimit, logger)
-
-	// Setup router
-	router := setupRouter(driverHandler, authHandler, cfg, logger, rateLimiter)
+	gw, err := newGateway(cfg, logger, logLevels)
+	if err != nil {
+		logger.Fatal("failed to initialize gateway", zap.Error(err))
+	}
 
 	// Start server
 	srv := &http.Server{
-		Addr:         ":" + cfg.Server.Port,
-		Handler:      router,
-		ReadTimeout:  cfg.Server.ReadTimeout,
-		WriteTimeout: cfg.Server.WriteTimeout,
+		Addr:           ":" + cfg.Server.Port,
+		Handler:        gw.router,
+		ReadTimeout:    cfg.Server.ReadTimeout,
+		WriteTimeout:   cfg.Server.WriteTimeout,
+		IdleTimeout:    cfg.Server.IdleTimeout,
+		MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+		ConnState:      gw.limiter.ConnState,
 	}
 
 	// Start server in a goroutine
@@ -75,6 +97,26 @@ func main() {
 		}
 	}()
 
+	// Probes and the admin API on their own port when ADMIN_PORT is set; not
+	// limited by MAX_IN_FLIGHT_REQUESTS so the gateway stays observable
+	var adminSrv *http.Server
+	if gw.admin != nil {
+		adminSrv = &http.Server{
+			Addr:           ":" + cfg.Server.AdminPort,
+			Handler:        gw.admin,
+			ReadTimeout:    cfg.Server.ReadTimeout,
+			WriteTimeout:   cfg.Server.WriteTimeout,
+			IdleTimeout:    cfg.Server.IdleTimeout,
+			MaxHeaderBytes: cfg.Server.MaxHeaderBytes,
+		}
+		go func() {
+			logger.Info("starting admin server", zap.String("port", cfg.Server.AdminPort))
+			if err := adminSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Fatal("failed to start admin server", zap.Error(err))
+			}
+		}()
+	}
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -82,39 +124,416 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	// Fail readiness and let in-flight requests finish (no-op if a preStop hook already drained)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), cfg.Server.DrainTimeout)
+	if err := gw.tracker.Drain(drainCtx); err != nil {
+		logger.Warn("drain timed out", zap.Int64("inFlight", gw.tracker.InFlight()))
+	}
+	cancelDrain()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Fatal("server forced to shutdown", zap.Error(err))
 	}
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(ctx); err != nil {
+			logger.Error("admin server forced to shutdown", zap.Error(err))
+		}
+	}
+
+	// Persist the usage counted since the last flush
+	gw.flushUsage()
+	// Send the error reports still queued
+	gw.reporter.Flush(ctx)
 
 	logger.Info("server exited")
 }
 
-func initLogger(level string) *zap.Logger {
-	var zapConfig zap.Config
-	if level == "debug" {
-		zapConfig = zap.NewDevelopmentConfig()
+// gateway is the wired gateway and what shutting it down needs
+type gateway struct {
+	router *gin.Engine
+	// admin serves probes and the admin API on ADMIN_PORT; nil when unset
+	admin     *gin.Engine
+	limiter   *middleware.ConcurrencyLimiter
+	tracker   *lifecycle.Tracker
+	stopMeter context.CancelFunc
+	meterDone chan struct{}
+	// reporter sends errors and panics to the error tracker
+	reporter errreport.Reporter
+}
+
+// newGateway wires the handlers, middleware and router and starts usage metering
+func newGateway(cfg *config.Config, logger *zap.Logger, logLevels *logging.Levels) (*gateway, error) {
+	// Report panics and the errors of the configured loggers to the error tracker
+	reporter, err := errreport.New(errreport.Options{
+		DSN:         cfg.ErrorReports.DSN,
+		Environment: cfg.ErrorReports.Environment,
+		Release:     cfg.ErrorReports.Release,
+		SampleRate:  cfg.ErrorReports.SampleRate,
+		ScrubFields: cfg.ErrorReports.ScrubFields,
+	}, logger.Named("errreport"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize error reporting: %w", err)
+	}
+	logger = errreport.WithCore(logger, reporter, cfg.ErrorReports.Loggers)
+	handlerLogger := logger.Named("handler")
+	serviceLogger := logger.Named("service")
+
+	// Initialize driver service client
+	driverServiceClient := service.NewDriverServiceClient(cfg.DriverService.BaseURL, serviceLogger)
+	driverServiceClient.Compress(cfg.DriverService.Compression.Enabled, cfg.DriverService.Compression.MinSize)
+	timeouts := cfg.DriverService.Timeouts
+	driverServiceClient.SetTimeouts(service.Timeouts{
+		Nearby:  timeouts.Nearby,
+		Default: timeouts.Default,
+		Bulk:    timeouts.Bulk,
+		Admin:   timeouts.Admin,
+	})
+	if cfg.DriverService.AdminURL != "" {
+		driverServiceClient.RouteAdmin(cfg.DriverService.AdminURL)
+	}
+	if cfg.Auth.TokenExchange.Secret != "" {
+		// The driver service only trusts identity headers on signed calls
+		driverServiceClient.SignCalls(token.NewGatewaySigner(cfg.Auth.TokenExchange, cfg.JWT.Issuer).Token)
+	}
+	var upstreamLimiter *adaptive.Limiter
+	if limit := cfg.DriverService.AdaptiveLimit; limit.Enabled {
+		// Shed load while the driver service is slow instead of piling requests onto it
+		upstreamLimiter = adaptive.New(adaptive.Options{
+			InitialLimit:     limit.InitialLimit,
+			MinLimit:         limit.MinLimit,
+			MaxLimit:         limit.MaxLimit,
+			LatencyThreshold: limit.LatencyThreshold,
+			Backoff:          limit.Backoff,
+		})
+		driverServiceClient.LimitConcurrency(upstreamLimiter, cfg.Server.OverloadRetryAfter)
+	}
+	var hedger *hedge.Hedger
+	if hedging := cfg.DriverService.Hedging; hedging.Enabled {
+		// Cut the tail latency of nearby searches and driver lookups
+		hedger = hedge.New(hedge.Options{
+			Delay:      hedging.Delay,
+			Percentile: hedging.Percentile,
+			Budget:     hedging.Budget,
+			Targets:    hedging.Targets,
+		})
+		driverServiceClient.Hedge(hedger)
+	}
+	var canaryRouter *canary.Router
+	if canaryCfg := cfg.DriverService.Canary; canaryCfg.URL != "" {
+		// Try a new driver service build on a share of the traffic
+		canaryRouter = canary.New(canary.Options{
+			URL:          canaryCfg.URL,
+			Percent:      canaryCfg.Percent,
+			AllowHeader:  canaryCfg.AllowHeader,
+			MaxErrorRate: canaryCfg.MaxErrorRate,
+			MinRequests:  canaryCfg.MinRequests,
+			Window:       canaryCfg.Window,
+			Cooldown:     canaryCfg.Cooldown,
+		})
+		driverServiceClient.RouteCanary(canaryRouter)
+	}
+
+	// Initialize token manager
+	tokens, err := token.NewManager(cfg.JWT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize token manager: %w", err)
+	}
+	if tokens.Algorithm() == token.AlgorithmRS256 && len(cfg.JWT.RSAKeys) == 0 {
+		logger.Warn("no JWT_RSA_KEYS configured, signing with a generated key that changes on restart")
+	}
+
+	// Route authentication comes from the auth policy
+	authPolicy, err := policy.Load(cfg.AuthPolicy.File)
+	if err != nil {
+		return nil, err
+	}
+
+	// Catch malformed driver service responses before they reach clients
+	var responseValidator *schema.Validator
+	if cfg.Validation.Enabled {
+		responseValidator, err = schema.NewValidator(handler.ResponseModels, cfg.Validation.Routes, cfg.Validation.Skip, logger.Named("schema"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure response validation: %w", err)
+		}
+	}
+
+	// Riders and anonymous callers see drivers without the fields meant for dispatchers
+	var redactor *redact.Redactor
+	if cfg.Redaction.Enabled {
+		redactor, err = redact.Load(cfg.Redaction.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var fallbacks map[string]middleware.Fallback
+	if cfg.Fallback.Enabled {
+		fallbacks, err = middleware.LoadFallbacks(cfg.Fallback.File)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Driver apps authenticate with device tokens the driver service issued
+	devices := middleware.NewDeviceAuthenticator(driverServiceClient, cfg.DeviceTokens.CacheTTL, logger.Named("middleware"))
+
+	// Initialize handlers
+	driverHandler := handler.NewDriverHandler(driverServiceClient, handlerLogger)
+	var nearby *coalesce.Group
+	if cfg.Nearby.Coalesce {
+		// Riders in the same area search for nearby drivers at once; share those calls
+		nearby = coalesce.New(cfg.Nearby.CacheTTL)
+		driverHandler.CoalesceNearby(nearby, cfg.Nearby.Precision)
+	}
+	if cfg.Nearby.MaxAge > 0 {
+		// Riders pan the map in bursts; let their apps reuse a search for a few seconds
+		driverHandler.CacheNearby(cfg.Nearby.MaxAge)
+	}
+	if !cfg.DriverService.ValidatePlates {
+		driverHandler.LeavePlatesToDriverService()
+	}
+	if cfg.Pricing.BaseURL != "" {
+		// Riders comparing drivers see what the trip would cost with each
+		pricing := service.NewPricingServiceClient(cfg.Pricing.BaseURL, cfg.Pricing.Concurrency, serviceLogger.Named("pricing"))
+		driverHandler.QuoteFares(pricing, cfg.Pricing.Timeout)
+	}
+	authHandler := handler.NewAuthHandler(cfg, tokens, handlerLogger)
+	if cfg.Auth.OIDC.Issuer != "" {
+		// Enterprise users sign in with their company's identity provider
+		provider, err := oidc.NewProvider(cfg.Auth.OIDC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure OIDC login: %w", err)
+		}
+		if cfg.Auth.OIDC.StateSecret == "" {
+			logger.Warn("no OIDC_STATE_SECRET configured, signing logins with a generated secret that changes on restart")
+		}
+		authHandler.EnableOIDC(provider)
+	}
+	if cfg.Auth.UsersFile != "" {
+		// Logins are checked against password accounts, which users can reset by email
+		users, err := account.Load(cfg.Auth.UsersFile)
+		if err != nil {
+			return nil, err
+		}
+		sender, err := account.NewSender(cfg.Auth.PasswordReset.Mail, logger.Named("mail"))
+		if err != nil {
+			return nil, err
+		}
+		reset, err := account.NewReset(cfg.Auth.PasswordReset, users, sender, logger.Named("audit"))
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Auth.PasswordReset.Secret == "" {
+			logger.Warn("no PASSWORD_RESET_SECRET configured, signing reset links with a generated secret that changes on restart")
+		}
+		authHandler.EnableAccounts(users, reset)
+	}
+	if cfg.Auth.TokenExchange.Secret != "" {
+		// Internal services trade a user's token for one scoped to the driver service
+		authHandler.EnableTokenExchange(token.NewExchanger(cfg.Auth.TokenExchange, cfg.JWT.Issuer))
+	}
+	tripHandler := handler.NewTripHandler(driverServiceClient, handlerLogger)
+	onboardingHandler := handler.NewOnboardingHandler(service.NewOnboardingService(driverServiceClient, serviceLogger), handlerLogger)
+	fleetHandler := handler.NewFleetHandler(driverServiceClient, handlerLogger)
+	webhookHandler := handler.NewWebhookHandler(driverServiceClient, handlerLogger)
+	exportHandler := handler.NewExportHandler(driverServiceClient, handlerLogger)
+	deviceTokenHandler := handler.NewDeviceTokenHandler(driverServiceClient, devices, handlerLogger)
+	scheduleHandler := handler.NewScheduleHandler(driverServiceClient, handlerLogger)
+	incidentHandler := handler.NewIncidentHandler(driverServiceClient, handlerLogger)
+	riderHandler := handler.NewRiderHandler(driverServiceClient, tokens, handlerLogger)
+	openAPIHandler, err := handler.NewOpenAPIHandler(docs.SwaggerInfo.ReadDoc(), cfg.Docs.InternalTags, driverServiceClient, handlerLogger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API spec: %w", err)
+	}
+
+	// Initialize debug taps
+	taps := tap.NewRegistry(tap.Options{
+		BufferSize:   cfg.Tap.BufferSize,
+		MaxBodyBytes: cfg.Tap.MaxBodyBytes,
+		MaxTTL:       cfg.Tap.MaxTTL,
+		Retention:    cfg.Tap.Retention,
+	})
+	tracker := lifecycle.NewTracker()
+
+	// Initialize usage metering
+	var meter *usage.Meter
+	if cfg.Usage.Enabled {
+		var store usage.Store = usage.NewMemoryStore()
+		if cfg.Usage.StorePath != "" {
+			fileStore, err := usage.NewFileStore(cfg.Usage.StorePath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open usage store: %w", err)
+			}
+			store = fileStore
+		}
+		meter = usage.NewMeter(store, logger.Named("usage"))
+	}
+	meterCtx, stopMeter := context.WithCancel(context.Background())
+	meterDone := make(chan struct{})
+	if meter != nil {
+		go func() {
+			defer close(meterDone)
+			defer errreport.Recover(reporter)
+			meter.Run(meterCtx, cfg.Usage.FlushInterval)
+		}()
 	} else {
-		zapConfig = zap.NewProductionConfig()
+		close(meterDone)
+	}
+
+	adminHandler := handler.NewAdminHandler(driverServiceClient, taps, meter, tracker, cfg.Server.DrainTimeout, handlerLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logLevels, handlerLogger)
+
+	// Bound concurrent requests so overload is answered with 503 instead of queueing
+	limiter := middleware.NewConcurrencyLimiter(cfg.Server.MaxInFlight, cfg.Server.OverloadRetryAfter, logger.Named("middleware"))
+	saturationHandler := handler.NewSaturationHandler(limiter, driverServiceClient, upstreamLimiter, hedger, canaryRouter, handlerLogger)
+
+	// Answer clients with 503 during planned maintenance
+	maintenance := middleware.NewMaintenance(cfg.Maintenance)
+	if cfg.Maintenance.Enabled {
+		logger.Warn("gateway starting in maintenance mode")
 	}
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenance, handlerLogger)
 
-	logger, err := zapConfig.Build()
+	// Cap the accounts created per device and IP each day
+	registrationGuard := middleware.NewRegistrationGuard(cfg.Registration, logger.Named("security"))
+	securityHandler := handler.NewSecurityHandler(registrationGuard, handlerLogger)
+
+	// Count requests to critical routes towards their service level objectives
+	var sloTracker *slo.Tracker
+	if cfg.SLO.Enabled {
+		sloTracker = slo.NewTracker(cfg.SLO)
+	}
+	sloHandler := handler.NewSLOHandler(sloTracker, handlerLogger)
+
+	// Initialize rate limiter
+	rateLimiter := middleware.NewRateLimiter(cfg, tokens, logger.Named("middleware"))
+	systemHandler := handler.NewSystemHandler(cfg, driverServiceClient, limiter, rateLimiter, upstreamLimiter, hedger, canaryRouter, nearby, devices, handlerLogger)
+
+	var router, adminRouter *gin.Engine
+	policyHandler := handler.NewPolicyHandler(authPolicy, func() []policy.Route { return registeredRoutes(router, adminRouter) }, handlerLogger)
+
+	// Setup router
+	router, adminRouter = setupRouter(driverHandler, authHandler, tripHandler, onboardingHandler, fleetHandler, webhookHandler, exportHandler, riderHandler, adminHandler, logLevelHandler, openAPIHandler, saturationHandler, systemHandler, securityHandler, sloHandler, policyHandler, maintenanceHandler, deviceTokenHandler, scheduleHandler, incidentHandler, authPolicy, taps, meter, sloTracker, tracker, tokens, devices, cfg, logger, reporter, rateLimiter, limiter, maintenance, registrationGuard, responseValidator, redactor, fallbacks)
+	// Forwarded client IPs are only believed from TRUSTED_PROXIES, so callers
+	// cannot pick the IP that rate limits, registration caps and password
+	// reset limits count
+	for _, engine := range []*gin.Engine{router, adminRouter} {
+		if engine == nil {
+			continue
+		}
+		if err := engine.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+			stopMeter()
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+		}
+	}
+	for _, rule := range authPolicy.Unused(registeredRoutes(router, adminRouter)) {
+		logger.Warn("auth policy rule matches no route", zap.String("method", rule.Method), zap.String("path", rule.Path))
+	}
+
+	return &gateway{
+		router:    router,
+		admin:     adminRouter,
+		limiter:   limiter,
+		tracker:   tracker,
+		stopMeter: stopMeter,
+		meterDone: meterDone,
+		reporter:  reporter,
+	}, nil
+}
+
+// flushUsage stops usage metering and waits for the last flush
+func (g *gateway) flushUsage() {
+	g.stopMeter()
+	<-g.meterDone
+}
+
+// registeredRoutes lists the routes of the routers for the auth policy; a nil
+// router, such as the admin router without ADMIN_PORT, has none
+func registeredRoutes(routers ...*gin.Engine) []policy.Route {
+	var routes []policy.Route
+	for _, router := range routers {
+		if router == nil {
+			continue
+		}
+		for _, route := range router.Routes() {
+			routes = append(routes, policy.Route{Method: route.Method, Path: route.Path})
+		}
+	}
+	return routes
+}
+
+// registerDebugRoutes serves the expvar runtime metrics as JSON on /metrics,
+// the SLO series for Prometheus on /metrics/slo and the pprof profiles under
+// /debug/pprof. Only the admin listener gets them, ahead of the auth policy;
+// they must never be reachable from the public port.
+func registerDebugRoutes(router *gin.Engine, sloHandler *handler.SLOHandler) {
+	router.GET("/metrics", gin.WrapH(expvar.Handler()))
+	router.GET("/metrics/slo", sloHandler.GetMetrics)
+
+	debug := router.Group("/debug/pprof")
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+		// Named profiles such as heap and goroutine
+		debug.GET("/:profile", gin.WrapF(pprof.Index))
+	}
+}
+
+func initLogger(cfg config.LoggingConfig) (*zap.Logger, *logging.Levels) {
+	logger, levels, err := logging.New(cfg)
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize logger: %v", err))
 	}
 
-	return logger
+	return logger, levels
 }
 
 func setupRouter(
 	driverHandler *handler.DriverHandler,
 	authHandler *handler.AuthHandler,
+	tripHandler *handler.TripHandler,
+	onboardingHandler *handler.OnboardingHandler,
+	fleetHandler *handler.FleetHandler,
+	webhookHandler *handler.WebhookHandler,
+	exportHandler *handler.ExportHandler,
+	riderHandler *handler.RiderHandler,
+	adminHandler *handler.AdminHandler,
+	logLevelHandler *handler.LogLevelHandler,
+	openAPIHandler *handler.OpenAPIHandler,
+	saturationHandler *handler.SaturationHandler,
+	systemHandler *handler.SystemHandler,
+	securityHandler *handler.SecurityHandler,
+	sloHandler *handler.SLOHandler,
+	policyHandler *handler.PolicyHandler,
+	maintenanceHandler *handler.MaintenanceHandler,
+	deviceTokenHandler *handler.DeviceTokenHandler,
+	scheduleHandler *handler.ScheduleHandler,
+	incidentHandler *handler.IncidentHandler,
+	authPolicy *policy.Policy,
+	taps *tap.Registry,
+	meter *usage.Meter,
+	sloTracker *slo.Tracker,
+	tracker *lifecycle.Tracker,
+	tokens *token.Manager,
+	devices *middleware.DeviceAuthenticator,
 	cfg *config.Config,
 	logger *zap.Logger,
+	reporter errreport.Reporter,
 	rateLimiter *middleware.RateLimiter,
-) *gin.Engine {
+	limiter *middleware.ConcurrencyLimiter,
+	maintenance *middleware.Maintenance,
+	registrationGuard *middleware.RegistrationGuard,
+	responseValidator *schema.Validator,
+	redactor *redact.Redactor,
+	fallbacks map[string]middleware.Fallback,
+) (*gin.Engine, *gin.Engine) {
 	if cfg.Logging.Level != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -122,48 +541,273 @@ func setupRouter(
 	router := gin.New()
 
 	// Global middleware
-	router.Use(middleware.CORS())
-	router.Use(middleware.ErrorHandler(logger))
-	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.InFlight(tracker))
+	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.Compress(cfg.Compression))
+	router.Use(middleware.ResponseEnvelope(cfg.Envelope))
+	router.Use(middleware.ErrorHandler(logger.Named("middleware")))
+	router.Use(middleware.RequestLogger(logger.Named("middleware")))
+	router.Use(maintenance.Block())
+	if meter != nil {
+		router.Use(middleware.Usage(meter))
+	}
+	if sloTracker != nil {
+		router.Use(middleware.SLO(sloTracker))
+	}
 	router.Use(rateLimiter.Limit())
-	router.Use(gin.Recovery())
+	router.Use(limiter.Limit())
+	router.Use(middleware.Recovery(logger.Named("middleware"), reporter))
+	router.Use(middleware.Tap(taps))
+	router.Use(middleware.UpstreamErrors(cfg.Upstream))
+	if responseValidator != nil {
+		router.Use(middleware.ResponseValidation(responseValidator))
+	}
+	if redactor != nil {
+		router.Use(middleware.ResponseRedaction(redactor))
+	}
+	router.Use(middleware.Authorize(authPolicy, cfg, tokens, devices, logger))
+	if len(fallbacks) > 0 {
+		// After authorization so stubs are only served to callers allowed the route
+		router.Use(middleware.UpstreamFallback(fallbacks, logger.Named("middleware")))
+	}
 
-	// Swagger documentation (before other routes to avoid conflicts)
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// With ADMIN_PORT set, probes and the admin API get their own router without
+	// CORS, maintenance, metering or limits, and the public one only serves the API
+	adminRouter := router
+	var separateAdmin *gin.Engine
+	if cfg.Server.AdminPort != "" {
+		separateAdmin = gin.New()
+		separateAdmin.Use(middleware.RequestID())
+		separateAdmin.Use(middleware.ResponseEnvelope(cfg.Envelope))
+		separateAdmin.Use(middleware.ErrorHandler(logger.Named("middleware")))
+		separateAdmin.Use(middleware.RequestLogger(logger.Named("middleware")))
+		separateAdmin.Use(middleware.Recovery(logger.Named("middleware"), reporter))
+		registerDebugRoutes(separateAdmin, sloHandler)
+		separateAdmin.Use(middleware.UpstreamErrors(cfg.Upstream))
+		if responseValidator != nil {
+			separateAdmin.Use(middleware.ResponseValidation(responseValidator))
+		}
+		separateAdmin.Use(middleware.Authorize(authPolicy, cfg, tokens, devices, logger))
+		adminRouter = separateAdmin
+	}
+
+	// Swagger documentation (before other routes to avoid conflicts); only the
+	// public group is listed here, internal operations are under /admin
+	if cfg.Docs.Enabled {
+		docs := router.Group("")
+		if !cfg.Docs.Public {
+			docs.Use(middleware.DocsAuth(cfg, logger))
+		}
+		docs.GET("/swagger/*any", openAPIHandler.SwaggerUI(ginSwagger.WrapHandler(swaggerFiles.Handler), true))
+		docs.GET("/openapi.json", openAPIHandler.GetOpenAPI)
+	}
 
 	// Health check
-	router.GET("/health", func(c *gin.Context) {
+	adminRouter.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
 
-	// Auth routes (public)
+	// Readiness check; fails while draining so the instance is taken out of rotation
+	adminRouter.GET("/ready", func(c *gin.Context) {
+		if !tracker.Ready() {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	})
+
+	// Authentication of every route is set by the auth policy
 	router.POST("/auth/login", authHandler.Login)
+	router.GET("/auth/.well-known/jwks.json", authHandler.JWKS)
+	router.POST("/auth/introspect", authHandler.Introspect)
+	router.POST("/auth/token/exchange", authHandler.ExchangeToken)
+	router.POST("/auth/password-reset/request", authHandler.RequestPasswordReset)
+	router.POST("/auth/password-reset/confirm", authHandler.ConfirmPasswordReset)
+	router.GET("/auth/oidc/login", authHandler.OIDCLogin)
+	router.GET("/auth/oidc/callback", authHandler.OIDCCallback)
+
+	// New accounts are capped per device fingerprint and IP
+	guardDrivers := registrationGuard.Guard("drivers")
+	guardRiders := registrationGuard.Guard("riders")
 
 	// Driver routes
 	drivers := router.Group("/drivers")
 	{
-		// Protected routes (require JWT)
-		if cfg.JWT.Enabled {
-			drivers.POST("", middleware.JWTAuth(cfg, logger), driverHandler.CreateDriver)
-			drivers.PUT("/:id", middleware.JWTAuth(cfg, logger), driverHandler.UpdateDriver)
-		} else {
-			drivers.POST("", driverHandler.CreateDriver)
-			drivers.PUT("/:id", driverHandler.UpdateDriver)
-		}
-
-		// Public routes (with optional API key protection)
-		if cfg.APIKey.Enabled {
-			// Apply API key to selected endpoints
-			drivers.GET("/nearby", middleware.APIKeyAuth(cfg, logger), driverHandler.FindNearbyDrivers)
-			drivers.GET("", middleware.APIKeyAuth(cfg, logger), driverHandler.ListDrivers)
-			drivers.GET("/:id", driverHandler.GetDriver) // Keep this public
-		} else {
-			// All GET routes are public when API key is disabled
-			drivers.GET("/:id", driverHandler.GetDriver)
-			drivers.GET("", driverHandler.ListDrivers)
-			drivers.GET("/nearby", driverHandler.FindNearbyDrivers)
-		}
-	}
-
-	return router
+		drivers.POST("", guardDrivers, driverHandler.CreateDriver)
+		drivers.PUT("/:id", driverHandler.UpdateDriver)
+		drivers.PUT("/:id/availability", driverHandler.SetAvailability)
+		drivers.PUT("/:id/suspension", driverHandler.SetSuspension)
+		drivers.POST("/:id/verify-phone/send", driverHandler.SendPhoneVerification)
+		drivers.POST("/:id/verify-phone", driverHandler.VerifyPhone)
+		drivers.POST("/:id/kyc", driverHandler.SubmitIdentityCheck)
+		drivers.GET("/:id/kyc", driverHandler.GetIdentityCheck)
+		drivers.GET("/:id/stats", driverHandler.GetDriverStats)
+		drivers.GET("/:id/earnings", driverHandler.GetDriverEarnings)
+		drivers.POST("/:id/heartbeat", driverHandler.Heartbeat)
+		drivers.PUT("/:id/location", driverHandler.UpdateLocation)
+		drivers.POST("/:id/device-tokens", deviceTokenHandler.IssueDeviceToken)
+		drivers.GET("/:id/device-tokens", deviceTokenHandler.ListDriverDeviceTokens)
+		drivers.POST("/:id/shifts", scheduleHandler.CreateShift)
+		drivers.GET("/:id/shifts", scheduleHandler.ListDriverShifts)
+		drivers.POST("/:id/incidents", incidentHandler.ReportIncident)
+		drivers.GET("/:id/incidents", incidentHandler.ListDriverIncidents)
+		drivers.GET("/stats", driverHandler.GetOnlineStats)
+		drivers.PUT("/:id/fleet", fleetHandler.AssignDriver)
+		drivers.DELETE("/:id/personal-data", adminHandler.ErasePersonalData)
+		drivers.GET("/:id", driverHandler.GetDriver)
+		drivers.GET("", driverHandler.ListDrivers)
+		drivers.GET("/changes", driverHandler.GetDriverChanges)
+		drivers.GET("/autocomplete", driverHandler.AutocompleteDrivers)
+		drivers.GET("/nearby", driverHandler.FindNearbyDrivers)
+		drivers.POST("/nearby/route", driverHandler.FindDriversAlongRoute)
+		drivers.POST("/bulk-update", driverHandler.BulkUpdateDrivers)
+		drivers.HEAD("/:id", driverHandler.HeadDriver)
+
+		// HEAD for the other GET routes and Allow headers on OPTIONS
+		handler.RegisterHeadAndOptions(router, drivers)
+	}
+
+	// Clients list the taxi types to offer
+	router.GET("/taxi-types", driverHandler.ListTaxiTypes)
+
+	// Called by the KYC provider
+	router.POST("/kyc/webhook", driverHandler.ReceiveKYCWebhook)
+
+	// Trip routes
+	trips := router.Group("/trips")
+	{
+		trips.POST("", tripHandler.RequestTrip)
+		trips.POST("/estimate", tripHandler.EstimateFare)
+		trips.GET("/:id", tripHandler.GetTrip)
+		trips.POST("/:id/accept", tripHandler.AcceptOffer)
+		trips.POST("/:id/decline", tripHandler.DeclineOffer)
+		trips.POST("/:id/complete", tripHandler.CompleteTrip)
+		trips.POST("/:id/cancel", tripHandler.CancelTrip)
+	}
+
+	// Rider routes; riders only access their own profile
+	riders := router.Group("/riders")
+	{
+		riders.POST("", guardRiders, riderHandler.RegisterRider)
+		riders.POST("/sign-in/send", riderHandler.SendSignInCode)
+		riders.POST("/sign-in", riderHandler.SignIn)
+		riders.GET("/:id", riderHandler.GetRider)
+		riders.PUT("/:id", riderHandler.UpdateRider)
+		riders.POST("/:id/favorites", riderHandler.AddFavoriteLocation)
+		riders.DELETE("/:id/favorites/:favoriteId", riderHandler.RemoveFavoriteLocation)
+	}
+
+	// Fleet routes; fleet admins are limited to their own fleet
+	fleets := router.Group("/fleets")
+	{
+		fleets.POST("", fleetHandler.CreateFleet)
+		fleets.GET("", fleetHandler.ListFleets)
+		fleets.GET("/:id", fleetHandler.GetFleet)
+		fleets.GET("/:id/drivers", fleetHandler.ListFleetDrivers)
+	}
+
+	// Planned shift routes; fleet admins only see their own fleet's calendar
+	shifts := router.Group("/shifts")
+	{
+		shifts.GET("", scheduleHandler.ListShifts)
+		shifts.GET("/:id", scheduleHandler.GetShift)
+		shifts.PUT("/:id", scheduleHandler.UpdateShift)
+		shifts.DELETE("/:id", scheduleHandler.DeleteShift)
+	}
+
+	// Incident routes; fleet admins only see incidents of their own fleet
+	incidents := router.Group("/incidents")
+	{
+		incidents.GET("", incidentHandler.ListIncidents)
+		incidents.GET("/:id", incidentHandler.GetIncident)
+		incidents.PUT("/:id/status", incidentHandler.UpdateIncidentStatus)
+	}
+
+	// Webhook routes; fleet admins only manage their own fleet's subscriptions
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("", webhookHandler.CreateWebhook)
+		webhooks.GET("", webhookHandler.ListWebhooks)
+		webhooks.GET("/:id", webhookHandler.GetWebhook)
+		webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		webhooks.POST("/:id/deliveries/:deliveryId/retry", webhookHandler.RetryDelivery)
+	}
+
+	// Export routes; fleet admins only export their own fleet's drivers
+	exports := router.Group("/exports")
+	{
+		exports.POST("", exportHandler.StartExport)
+		exports.GET("/:id", exportHandler.GetExport)
+		exports.GET("/:id/download", exportHandler.DownloadExport)
+	}
+
+	// Onboarding routes
+	onboarding := router.Group("/onboarding")
+	{
+		onboarding.POST("/drivers", guardDrivers, onboardingHandler.OnboardDriver)
+	}
+
+	// Admin routes (disabled unless ADMIN_TOKEN is set)
+	if cfg.Admin.Token != "" {
+		admin := adminRouter.Group("/admin")
+		{
+			admin.GET("/system", systemHandler.GetSystem)
+			admin.POST("/drain", adminHandler.Drain)
+			admin.GET("/taps", adminHandler.ListTaps)
+			admin.POST("/taps", adminHandler.CreateTap)
+			admin.GET("/taps/:id", adminHandler.GetTap)
+			admin.DELETE("/taps/:id", adminHandler.DeleteTap)
+			admin.GET("/indexes", adminHandler.GetIndexes)
+			admin.POST("/indexes/sync", adminHandler.SyncIndexes)
+			admin.GET("/indexes/sync", adminHandler.GetIndexSync)
+			admin.GET("/failover", adminHandler.GetFailoverStats)
+			admin.GET("/telemetry", adminHandler.GetTelemetryStats)
+			admin.GET("/query-stats", adminHandler.GetQueryStats)
+			admin.GET("/location-anomalies", adminHandler.GetLocationAnomalies)
+			admin.GET("/erasures", adminHandler.ListErasures)
+			admin.GET("/licenses/expiring", adminHandler.GetExpiringLicenses)
+			admin.GET("/validation-rules", adminHandler.GetValidationRules)
+			admin.GET("/tenants/:id/quota", adminHandler.GetTenantQuota)
+			admin.POST("/earnings/adjustments", adminHandler.CreateEarningAdjustment)
+			admin.GET("/earnings/adjustments", adminHandler.ListEarningAdjustments)
+			admin.POST("/payouts", adminHandler.CreatePayout)
+			admin.GET("/payouts", adminHandler.ListPayouts)
+			admin.GET("/payouts/:id", adminHandler.GetPayout)
+			admin.GET("/payouts/:id/export", adminHandler.ExportPayout)
+			admin.GET("/device-tokens", deviceTokenHandler.ListDeviceTokens)
+			admin.DELETE("/device-tokens/:id", deviceTokenHandler.RevokeDeviceToken)
+			admin.POST("/device-tokens/:id/rotate", deviceTokenHandler.RotateDeviceToken)
+			admin.GET("/usage", adminHandler.GetUsage)
+			admin.GET("/reports/utilization", adminHandler.GetUtilizationReport)
+			admin.GET("/security-events", securityHandler.GetSecurityEvents)
+			admin.GET("/slo", sloHandler.GetSLO)
+			admin.GET("/auth-policy", policyHandler.GetAuthPolicy)
+			admin.GET("/maintenance", maintenanceHandler.GetMaintenance)
+			admin.PUT("/maintenance", maintenanceHandler.SetMaintenance)
+			admin.GET("/loglevel", logLevelHandler.GetLogLevels)
+			admin.PUT("/loglevel", logLevelHandler.SetLogLevel)
+			admin.DELETE("/loglevel/:logger", logLevelHandler.ResetLogLevel)
+			admin.GET("/saturation", saturationHandler.GetSaturation)
+			admin.GET("/saturation/driver-service", saturationHandler.GetDriverServiceSaturation)
+			admin.GET("/saturation/upstream", saturationHandler.GetUpstreamLimit)
+			admin.GET("/saturation/hedging", saturationHandler.GetHedging)
+			admin.GET("/saturation/canary", saturationHandler.GetCanary)
+			if cfg.Docs.Enabled {
+				admin.GET("/openapi.json", openAPIHandler.GetInternalOpenAPI)
+			}
+		}
+
+		// The internal Swagger UI also takes the admin token as a Basic password
+		// since browsers cannot send X-Admin-Token, so the policy leaves it to
+		// DocsAuth. It needs its own file handler because gin-swagger pins the
+		// handler's prefix to the first route served.
+		if cfg.Docs.Enabled {
+			internalUI := &webdav.Handler{FileSystem: swaggerFiles.FS, LockSystem: webdav.NewMemLS()}
+			adminRouter.GET("/admin/swagger/*any", middleware.DocsAuth(cfg, logger), openAPIHandler.SwaggerUI(ginSwagger.WrapHandler(internalUI), false))
+		}
+	}
+
+	return router, separateAdmin
 }
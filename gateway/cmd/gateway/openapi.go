@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/bitaksi/gateway/internal/openapi"
+)
+
+// specServices are the services whose committed Swagger docs are checked,
+// relative to the repository root
+var specServices = []string{"driver-service", "gateway"}
+
+// runOpenAPI implements the "openapi" command. "openapi diff" regenerates the
+// specs from the swag annotations and exits non-zero when the committed docs
+// are stale, so CI can catch handlers changed without running make swagger.
+func runOpenAPI(args []string) {
+	if len(args) == 0 || args[0] != "diff" {
+		fmt.Fprintln(os.Stderr, "usage: gateway openapi diff [-root dir] [-swag path]")
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet("openapi diff", flag.ExitOnError)
+	root := flags.String("root", "..", "repository root containing the service directories")
+	swag := flags.String("swag", "swag", "swag binary used to regenerate the specs")
+	flags.Parse(args[1:])
+
+	stale := false
+	for _, name := range specServices {
+		changes, err := diffService(*swag, filepath.Join(*root, name), name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(2)
+		}
+		if len(changes) == 0 {
+			fmt.Printf("%s: docs are up to date\n", name)
+			continue
+		}
+		stale = true
+		fmt.Printf("%s: docs are stale, run make swagger\n", name)
+		for _, change := range changes {
+			fmt.Println("  " + change)
+		}
+	}
+	if stale {
+		os.Exit(1)
+	}
+}
+
+// diffService regenerates a service's spec into a temporary directory and
+// compares it with the committed docs/swagger.json
+func diffService(swag, dir, name string) ([]string, error) {
+	committed, err := os.ReadFile(filepath.Join(dir, "docs", "swagger.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read committed spec: %w", err)
+	}
+
+	out, err := os.MkdirTemp("", "openapi-"+name)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(out)
+
+	cmd := exec.Command(swag, "init",
+		"-g", filepath.Join("cmd", name, "main.go"),
+		"-o", out,
+		"--outputTypes", "json",
+		"--parseDependency", "--parseInternal", "--quiet")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to regenerate spec: %w", err)
+	}
+
+	generated, err := os.ReadFile(filepath.Join(out, "swagger.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated spec: %w", err)
+	}
+	return openapi.Diff(committed, generated)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestBuiltinPolicyMatchesRoutes keeps the built-in policy in step with the
+// routes and with the auth they had before it existed
+func TestBuiltinPolicyMatchesRoutes(t *testing.T) {
+	cfg := config.Load()
+	cfg.Admin.Token = "admin-token"
+	cfg.Docs.Enabled = true
+	cfg.Usage.Enabled = false
+	levels, err := logging.NewLevels("error", nil)
+	require.NoError(t, err)
+	gw, err := newGateway(cfg, zap.NewNop(), levels)
+	require.NoError(t, err)
+	defer gw.flushUsage()
+
+	p := policy.Default()
+	routes := registeredRoutes(gw.router)
+	assert.Empty(t, p.Unused(routes), "rules that match no route")
+
+	want := map[string]string{
+		"GET /health":                     policy.AuthPublic,
+		"POST /auth/login":                policy.AuthPublic,
+		"POST /auth/introspect":           policy.AuthAPIKey,
+		"GET /drivers/:id":                policy.AuthPublic,
+		"HEAD /drivers/:id":               policy.AuthPublic,
+		"HEAD /drivers/nearby":            policy.AuthAPIKey,
+		"OPTIONS /drivers/:id":            policy.AuthPublic,
+		"GET /drivers/nearby":             policy.AuthAPIKey,
+		"PUT /drivers/:id":                policy.AuthJWT,
+		"PUT /drivers/:id/location":       policy.AuthDevice,
+		"POST /drivers/:id/heartbeat":     policy.AuthDevice,
+		"POST /drivers/:id/device-tokens": policy.AuthJWT,
+		"DELETE /admin/device-tokens/:id": policy.AuthAdmin,
+		"POST /drivers":                   policy.AuthJWT,
+		"POST /kyc/webhook":               policy.AuthPublic,
+		"POST /riders":                    policy.AuthPublic,
+		"GET /riders/:id":                 policy.AuthJWT,
+		"POST /trips":                     policy.AuthJWT,
+		"GET /fleets":                     policy.AuthJWT,
+		"POST /onboarding/drivers":        policy.AuthJWT,
+		"POST /admin/drain":               policy.AuthAdmin,
+		"GET /admin/swagger/*any":         policy.AuthPublic,
+		"DELETE /webhooks/:id":            policy.AuthJWT,
+		"POST /trips/:id/accept":          policy.AuthJWT,
+		"GET /auth/.well-known/jwks.json": policy.AuthPublic,
+	}
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route, auth := range want {
+		require.True(t, registered[route], "route %s is not registered", route)
+		method, path, _ := strings.Cut(route, " ")
+		assert.Equal(t, auth, p.Resolve(method, path).Auth, route)
+	}
+
+	// Riders keep out of driver management and of accepting trips
+	assert.False(t, p.Resolve("PUT", "/drivers/:id").Allows(token.RoleRider))
+	assert.False(t, p.Resolve("POST", "/trips/:id/accept").Allows(token.RoleRider))
+	assert.True(t, p.Resolve("POST", "/trips/:id/cancel").Allows(token.RoleRider))
+	assert.True(t, p.Resolve("GET", "/riders/:id").Allows(token.RoleRider))
+	// Device tokens only reach the routes carrying their scope
+	assert.True(t, p.Resolve("POST", "/drivers/:id/heartbeat").Allows(token.RoleFleetAdmin))
+	assert.False(t, p.Resolve("POST", "/drivers/:id/heartbeat").Allows(token.RoleRider))
+}
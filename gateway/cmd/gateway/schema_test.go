@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/handler"
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestResponseModelsMatchRoutes keeps the response models in step with the
+// routes, so a renamed route is not silently left unchecked
+func TestResponseModelsMatchRoutes(t *testing.T) {
+	cfg := config.Load()
+	cfg.Usage.Enabled = false
+	cfg.Validation.Enabled = true
+	levels, err := logging.NewLevels("error", nil)
+	require.NoError(t, err)
+	gw, err := newGateway(cfg, zap.NewNop(), levels)
+	require.NoError(t, err)
+	defer gw.flushUsage()
+
+	registered := map[string]bool{}
+	for _, route := range registeredRoutes(gw.router) {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for route := range handler.ResponseModels {
+		assert.True(t, registered[route], "route %s has a response model but is not registered", route)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestTrustedProxies checks that callers cannot reset the per-IP registration
+// cap by sending their own X-Forwarded-For
+func TestTrustedProxies(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"rider-1","favoriteLocations":[]}`))
+	}))
+	defer upstream.Close()
+
+	newRouter := func(trusted []string) http.Handler {
+		cfg := config.Load()
+		cfg.Usage.Enabled = false
+		cfg.DriverService.BaseURL = upstream.URL
+		cfg.Registration = config.RegistrationGuardConfig{Enabled: true, MaxPerIP: 1}
+		cfg.Server.TrustedProxies = trusted
+		levels, err := logging.NewLevels("error", nil)
+		require.NoError(t, err)
+		gw, err := newGateway(cfg, zap.NewNop(), levels)
+		require.NoError(t, err)
+		t.Cleanup(gw.flushUsage)
+		return gw.router
+	}
+	register := func(router http.Handler, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/riders", strings.NewReader(`{"firstName":"Elif","lastName":"Yılmaz","phone":"+905551234567"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	router := newRouter(nil)
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, register(router, "203.0.113.2"), "a spoofed X-Forwarded-For reset the cap")
+
+	router = newRouter([]string{"10.0.0.0/8"})
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.2"), "clients behind a trusted proxy are counted apart")
+
+	cfg := config.Load()
+	cfg.Usage.Enabled = false
+	cfg.Server.TrustedProxies = []string{"not-an-ip"}
+	levels, err := logging.NewLevels("error", nil)
+	require.NoError(t, err)
+	_, err = newGateway(cfg, zap.NewNop(), levels)
+	assert.ErrorContains(t, err, "TRUSTED_PROXIES")
+}
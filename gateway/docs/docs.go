@@ -23,39 +23,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate and get JWT token",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/auth-policy": {
+            "get": {
+                "description": "Every registered route with the authentication the policy (AUTH_POLICY_FILE, or the built-in one) requires of it, and the policy rules that match no route. API key and JWT requirements are only enforced while API keys and JWT are enabled.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Login",
+                "summary": "Get auth policy",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Authentication successful",
+                        "description": "Effective auth policy",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.LoginResponse"
+                            "$ref": "#/definitions/internal_handler.AuthPolicyResponse"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized - invalid credentials",
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -63,96 +58,159 @@ const docTemplate = `{
                 }
             }
         },
-        "/drivers": {
+        "/admin/device-tokens": {
             "get": {
-                "description": "Get a paginated list of drivers",
+                "description": "List the device tokens of every driver, or of one, newest first, without their values",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "List drivers",
+                "summary": "List device tokens",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list tokens of this driver",
+                        "name": "driverId",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Page size",
-                        "name": "pageSize",
+                        "type": "boolean",
+                        "description": "Only list tokens that are not revoked",
+                        "name": "active",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated list of drivers",
+                        "description": "Tokens",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.ListDriversResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.DeviceToken"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "401": {
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Driver not found",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "security": [
+            }
+        },
+        "/admin/device-tokens/{id}": {
+            "delete": {
+                "description": "Revoke a device token, e.g. of a lost phone. Revoking a revoked token succeeds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke a device token",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "description": "Create a new taxi driver",
-                "consumes": [
-                    "application/json"
-                ],
+                "responses": {
+                    "200": {
+                        "description": "Revoked token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DeviceToken"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/device-tokens/{id}/rotate": {
+            "post": {
+                "description": "Issue a new token for the same device and revoke this one. The new token is only returned in this response.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Create a new driver",
+                "summary": "Rotate a device token",
                 "parameters": [
                     {
-                        "description": "Driver information",
-                        "name": "driver",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.CreateDriverRequest"
-                        }
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Driver created successfully",
+                        "description": "New token",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.Driver"
+                            "$ref": "#/definitions/internal_handler.DeviceToken"
                         }
                     },
-                    "400": {
-                        "description": "Validation error",
+                    "401": {
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "404": {
+                        "description": "Token not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Token is revoked",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -160,52 +218,104 @@ const docTemplate = `{
                 }
             }
         },
-        "/drivers/nearby": {
-            "get": {
-                "description": "Find drivers within 6km radius",
+        "/admin/drain": {
+            "post": {
+                "description": "Fail the readiness probe so no new traffic is routed here, then wait until in-flight requests finish or the timeout elapses. Intended for a Kubernetes preStop hook; readiness stays failed until the process restarts.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Find nearby drivers",
+                "summary": "Drain the gateway",
                 "parameters": [
                     {
-                        "type": "number",
-                        "format": "float64",
-                        "description": "Latitude",
-                        "name": "lat",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "number",
-                        "format": "float64",
-                        "description": "Longitude",
-                        "name": "lon",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "Maximum seconds to wait (defaults to DRAIN_TIMEOUT_SEC)",
+                        "name": "timeoutSec",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "All in-flight requests finished",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DrainResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Requests still in flight when the timeout elapsed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DrainResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/earnings/adjustments": {
+            "get": {
+                "description": "Audit trail of manual earning adjustments, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List earning adjustments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Taxi type (sari, turkuaz, siyah)",
-                        "name": "taksiType",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only list adjustments of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum adjustments to return (max 200)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of nearby drivers sorted by distance",
+                        "description": "Adjustments",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/internal_handler.NearbyDriverResponse"
+                                "$ref": "#/definitions/internal_handler.Earning"
                             }
                         }
                     },
                     "400": {
-                        "description": "Validation error",
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
@@ -217,32 +327,61 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/drivers/{id}": {
-            "get": {
-                "description": "Get driver details by ID",
+            },
+            "post": {
+                "description": "Credit or deduct an amount from a driver's earnings. Adjustments are never edited; each keeps its reason and the operator from X-Admin-User, and is included in the next payout.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Get a driver by ID",
+                "summary": "Adjust driver earnings",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Driver ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops-admin",
+                        "description": "Operator recorded in the audit trail",
+                        "name": "X-Admin-User",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Adjustment",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateAdjustmentRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Driver details",
+                    "201": {
+                        "description": "Adjustment recorded",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.Driver"
+                            "$ref": "#/definitions/internal_handler.Earning"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "404": {
@@ -258,289 +397,11023 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update an existing driver. Location uses top-level lat/lon fields (same format as create).",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/erasures": {
+            "get": {
+                "description": "Audit trail of driver personal data erasures, by the retention job and on request, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "drivers"
+                    "admin"
                 ],
-                "summary": "Update a driver",
+                "summary": "List personal data erasures",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "\"507f1f77bcf86cd799439011\"",
-                        "description": "Driver ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Driver update information",
-                        "name": "driver",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.UpdateDriverRequest"
-                        }
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only list erasures of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum erasures to return (max 200)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Driver updated successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ali\",\"lastName\":\"Kurt\",\"plate\":\"34G99\",\"taxiType\":\"siyah\",\"carBrand\":\"Mercedes\",\"carModel\":\"G Class\",\"location\":{\"lat\":42.0082,\"lon\":28.9784},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:30:00Z\"})",
+                        "description": "Erasures",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.Driver"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.Erasure"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Validation error\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"both lat and lon must be provided together\"}})",
+                        "description": "Invalid limit",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                    "401": {
+                        "description": "Missing or invalid admin token",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/internal_handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
+        },
+        "/admin/failover": {
+            "get": {
+                "description": "Current MongoDB primary, observed primary changes, and how many driver service operations were retried, recovered or answered with 503 since it started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service failover metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Failover metrics",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FailoverStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/indexes": {
+            "get": {
+                "description": "Compare the MongoDB indexes the driver service expects with the ones that exist, listing missing, mismatched and unexpected indexes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service indexes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Index report",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IndexReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/indexes/sync": {
+            "get": {
+                "description": "Get the progress of the latest driver service index sync",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get index sync progress",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest sync job",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IndexSyncJob"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No sync started",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Start building the missing MongoDB indexes in the background; poll GET /admin/indexes/sync for progress. Mismatched indexes are skipped. The request is audit-logged with the caller from X-Admin-User (or the client IP).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create missing driver service indexes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operator name for the audit log",
+                        "name": "X-Admin-User",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Sync started",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IndexSyncJob"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Sync already running",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/licenses/expiring": {
+            "get": {
+                "description": "Drivers whose licence expires within the given number of days, soonest first. Already expired licences are included; flagged drivers have been taken off dispatch by the hourly licence check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List expiring driver licences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 30,
+                        "description": "Report window in days, 1-365 (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only report drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Expiring licences",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ExpiringLicensesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid window",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/location-anomalies": {
+            "get": {
+                "description": "Drivers whose location updates implied an implausible speed, e.g. spoofed GPS, with the number of such updates, how many were rejected and the highest implied speed; most anomalies first. The range defaults to the last 30 days and cannot exceed 366 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Location anomalies per driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of drivers to list (1-1000, default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Anomaly counts per driver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LocationAnomalyReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range or limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/loglevel": {
+            "get": {
+                "description": "Get the root log level and the levels of named loggers that override it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get log levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{\"service\":\"debug\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_logging.LevelsSnapshot"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change the root log level, or override the level of a named logger and the loggers below it. The change lasts until the gateway restarts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change a log level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Logger and level",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetLogLevelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{\"service\":\"debug\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_logging.LevelsSnapshot"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown level\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"unknown log level \\\"verbose\\\"\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/loglevel/{logger}": {
+            "delete": {
+                "description": "Remove the level override of a named logger so it follows the root level again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset a logger's level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "service",
+                        "description": "Logger name",
+                        "name": "logger",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Log levels\" example({\"level\":\"info\",\"loggers\":{}})",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_logging.LevelsSnapshot"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/maintenance": {
+            "get": {
+                "description": "Whether this gateway instance answers client requests with 503 for planned maintenance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get maintenance mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance mode",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.MaintenanceStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "While on, every route but /health, /ready and /admin/maintenance (and the other /admin routes unless MAINTENANCE_ALLOW_ADMIN=false) is answered with 503, a MAINTENANCE error carrying the message and Retry-After. The switch applies to this instance only and lasts until it restarts, when MAINTENANCE_MODE applies again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Switch maintenance mode",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Maintenance mode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetMaintenanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Maintenance mode",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.MaintenanceStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/openapi.json": {
+            "get": {
+                "description": "Get the merged Swagger 2.0 spec including internal operations such as the admin API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the internal merged API spec",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Swagger 2.0 spec",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts": {
+            "get": {
+                "description": "Payout batches, newest first, without their lines.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List payouts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum payouts to return (max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payouts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.Payout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Batch every unpaid trip earning and adjustment in the range into a payout with one line per driver. Entries are paid out once. The range ends at the start of the current day by default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a payout batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops-admin",
+                        "description": "Operator recorded on the payout",
+                        "name": "X-Admin-User",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Payout range",
+                        "name": "payout",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreatePayoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payout created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Payout"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No unpaid earnings in range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts/{id}": {
+            "get": {
+                "description": "A payout batch with what it owes each driver.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get a payout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6573b2f3c4d5e6f7a8b9c0d1\"",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payout",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Payout"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payout not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payouts/{id}/export": {
+            "get": {
+                "description": "Download a payout batch for finance as CSV: payoutId, driverId, trips, gross, commission, adjustments, net, currency.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Export a payout as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6573b2f3c4d5e6f7a8b9c0d1\"",
+                        "description": "Payout ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payout CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payout not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/query-stats": {
+            "get": {
+                "description": "Duration histogram of every MongoDB command per collection since the driver service started, the collections taking the most time first. Commands at or over MONGODB_SLOW_QUERY_MS count as slow and are logged with their redacted filter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service MongoDB query latency",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Query latency per collection and command",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.QueryStats"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reports/utilization": {
+            "get": {
+                "description": "Online hours (time on shift) against busy hours (time between accepting and completing trips) per calendar day and taxi type, with busy over online as utilization. Days are calendar days in the driver service's earnings time zone. The range defaults to the last 7 days and cannot exceed 92 days; today is marked partial. Send format=csv or \"Accept: text/csv\" for a CSV export.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Driver utilization report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "First day (YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-07\"",
+                        "description": "Last day (YYYY-MM-DD, inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Utilization report",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UtilizationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation": {
+            "get": {
+                "description": "Requests in flight against MAX_IN_FLIGHT_REQUESTS, the peak since start, how many requests were rejected with 503 while saturated, and the open connections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report gateway saturation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saturation metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.SaturationStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation/canary": {
+            "get": {
+                "description": "Requests, failures, error rate and average latency of the primary driver service and the CANARY_URL canary side by side, the share of traffic sent to the canary and whether it is in a fallback: when more than CANARY_MAX_ERROR_PERCENT of its requests fail within a window, all traffic stays on the primary for CANARY_COOLDOWN_SEC. enabled is false without CANARY_URL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service canary routing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Canary metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_canary.Stats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation/driver-service": {
+            "get": {
+                "description": "Requests the driver service has in flight against its limit, the peak, rejected requests and open connections",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service saturation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Saturation metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.SaturationStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation/hedging": {
+            "get": {
+                "description": "How many nearby searches and driver lookups were hedged with a second request, how often the hedge answered first, how many slow reads the HEDGE_BUDGET_PERCENT budget left unhedged, and the current hedge delay. enabled is false when HEDGE_ENABLED is off.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service read hedging",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hedging metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_hedge.Stats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/saturation/upstream": {
+            "get": {
+                "description": "The current limit on requests in flight to the driver service, how it moved and the upstream health it is based on: successes, failures, responses slower than UPSTREAM_LIMIT_LATENCY_MS and the average latency. Requests over the limit are answered with 503 and counted as rejected. enabled is false when UPSTREAM_LIMIT_ENABLED is off.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report the adaptive driver service limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Adaptive limit metrics",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_adaptive.Stats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/security-events": {
+            "get": {
+                "description": "Devices and IPs that went over their daily registration caps (REGISTRATION_MAX_PER_DEVICE, REGISTRATION_MAX_PER_IP), newest first. One event is kept per device or IP and day, counting the registrations refused. Events are held in memory on this instance only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List security events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Security events",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SecurityEventsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/slo": {
+            "get": {
+                "description": "Availability (no 5xx) and latency (successful requests within the threshold) of the routes in SLO_OBJECTIVES over the rolling SLO_WINDOWS: the share of good requests, the error budget burn rate (1 spends the budget exactly over the window) and the budget left over the longest window. Counts are kept in memory on this instance and start over on restart. Send format=prometheus or \"Accept: text/plain\" for the same series in the Prometheus text format, also served on /metrics/slo on the admin port.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report service level objectives",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or prometheus",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Service level objectives",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_slo.Report"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SLO tracking disabled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/system": {
+            "get": {
+                "description": "One view for on-call: build and uptime, the configuration with secrets redacted, driver service health with the latency percentiles of recent requests, saturation, the adaptive upstream limit (the gateway sheds load with it instead of a circuit breaker), hedging, canary routing, rate limiting and cache hit rates. status is degraded while the driver service fails its health check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report the state of the gateway",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Gateway state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SystemStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/taps": {
+            "get": {
+                "description": "List active taps and the captured request/response pairs still in the ring buffer",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List debug taps",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Taps and captures",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TapListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Capture full request/response bodies for a route (e.g. \"/drivers/:id\") or a single X-Request-ID until the tap expires",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create debug tap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tap selector and limits",
+                        "name": "tap",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateTapRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tap created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_tap.Tap"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/taps/{id}": {
+            "get": {
+                "description": "Get a tap and the captures it recorded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get debug tap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tap and captures",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TapResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tap not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop a tap and discard its captures",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete debug tap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tap ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Tap deleted"
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tap not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/telemetry": {
+            "get": {
+                "description": "Whether the driver service's MQTT bridge is connected to the broker, its topics, and the location and heartbeat messages it received, applied, rejected (by reason) or left for redelivery since it started",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Report driver service MQTT telemetry metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Telemetry metrics",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TelemetryStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "MQTT bridge disabled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants/{id}/quota": {
+            "get": {
+                "description": "How many drivers a tenant (fleet) has against the quota it pays for, set with TENANT_QUOTA_MAX_DRIVERS and TENANT_QUOTAS in the driver service. Fleets that have used up their quota take no new drivers (402 QUOTA_EXCEEDED).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Tenant driver quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6570a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Fleet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Quota usage",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DriverQuota"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Fleet not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/usage": {
+            "get": {
+                "description": "Requests counted per UTC day, tenant (fleet), subject (JWT user or masked API key), method and route. The range defaults to the last 30 days and cannot exceed 366 days. Send format=csv or \"Accept: text/csv\" for a CSV export.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Usage report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "First day (YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-06\"",
+                        "description": "Last day (YYYY-MM-DD, inclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this tenant (fleet ID)",
+                        "name": "tenant",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this subject, e.g. user:admin or key:sk_live_****z789",
+                        "name": "subject",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Usage report",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UsageReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Usage metering disabled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/validation-rules": {
+            "get": {
+                "description": "The driver field validation rules in effect in the driver service: those of the service country, every configured country and every tenant, after inheritance is applied. With fleetId, only the rules applied to drivers of that fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Inspect driver validation rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only return the rules applied to this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Effective rules",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ValidationRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys for validating RS256 tokens issued by the gateway. The set is empty when tokens are signed with HS256.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "JSON Web Key Set",
+                "responses": {
+                    "200": {
+                        "description": "Key set",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_token.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/introspect": {
+            "post": {
+                "description": "Check whether an access token is valid (RFC 7662). Invalid or expired tokens return {\"active\": false}.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Introspect token",
+                "parameters": [
+                    {
+                        "description": "Token to introspect",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IntrospectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Introspection result",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IntrospectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate and get JWT token. With AUTH_USERS_FILE the password is checked against the user's account; otherwise any username and password are accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Authentication successful",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LoginResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "The identity provider redirects here after sign-in. The authorization code is redeemed, the ID token validated and its role claim mapped to a gateway role; users without a mapped role are refused. Answers with a gateway token, or redirects to OIDC_POST_LOGIN_URL with #token=... when configured.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "SSO callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error reported by the identity provider",
+                        "name": "error",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Authentication successful",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LoginResponse"
+                        }
+                    },
+                    "302": {
+                        "description": "Redirect to OIDC_POST_LOGIN_URL with the token"
+                    },
+                    "400": {
+                        "description": "Login expired, or started in another browser",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Sign-in refused or failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "No role is mapped to the user",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "SSO is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects to the OpenID Connect provider to sign in with the authorization code flow and PKCE. The provider redirects back to /auth/oidc/callback, which issues a gateway token. The login must finish within OIDC_LOGIN_TIMEOUT_SEC in the same browser.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Sign in with SSO",
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the identity provider"
+                    },
+                    "404": {
+                        "description": "SSO is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Identity provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/confirm": {
+            "post": {
+                "description": "Sets the password of the account a reset token was emailed to. Tokens stop working once they expire or a password was set with them. Attempts count towards the client IP's PASSWORD_RESET_MAX_PER_IP and are audit-logged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Set a new password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PasswordResetConfirmRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PasswordResetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired token, or a password that is too short\" example({\"error\":{\"code\":\"INVALID_RESET_TOKEN\",\"message\":\"invalid or expired reset token\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Password accounts are not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password-reset/request": {
+            "post": {
+                "description": "Emails a reset link, valid for PASSWORD_RESET_TTL_MIN and usable once, to the account with the address. The answer is the same whether or not an account has the address. Requests are limited per address (PASSWORD_RESET_MAX_PER_EMAIL) and per client IP (PASSWORD_RESET_MAX_PER_IP) within PASSWORD_RESET_WINDOW_MIN, and audit-logged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Request a password reset",
+                "parameters": [
+                    {
+                        "description": "Email address of the account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Reset link sent if the account exists",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PasswordResetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Password accounts are not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many reset requests\" example({\"error\":{\"code\":\"RATE_LIMIT_EXCEEDED\",\"message\":\"too many password reset attempts, please try again later\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/token/exchange": {
+            "post": {
+                "description": "Exchange a user's access token for a short-lived token an internal service calls the driver service with directly (RFC 8693). The token acts for the same user, with the same role and fleet, limited to the requested scopes: drivers:read allows GET routes, drivers:write the others. It expires after TOKEN_EXCHANGE_TTL_SEC or with the subject token, whichever is first, and is signed with TOKEN_EXCHANGE_SECRET, which the driver service validates it with; the gateway does not accept it.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Exchange a token",
+                "parameters": [
+                    {
+                        "description": "Token to exchange",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TokenExchangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exchanged token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TokenExchangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request, subject token, audience or scope\" example({\"error\":{\"code\":\"INVALID_GRANT\",\"message\":\"invalid or expired subject token\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Token exchange is not configured",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers": {
+            "get": {
+                "description": "Get a paginated list of drivers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "List drivers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only list drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "istanbul",
+                        "description": "Only list drivers in this city; other lists those outside every configured city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pet-friendly,wheelchair-accessible",
+                        "description": "Only list drivers carrying all of these comma-separated tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "language:en",
+                        "description": "Only list drivers with all of these comma-separated key:value attributes; a key alone matches any value",
+                        "name": "attributes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ListDriversResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new taxi driver",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Create a new driver",
+                "parameters": [
+                    {
+                        "description": "Driver information",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateDriverRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device fingerprint; registrations are capped per device and per IP each day",
+                        "name": "X-Device-Fingerprint",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Driver created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admin creating a driver in another fleet, or BLACKLISTED for a driver on the blacklist",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plate, phone or email taken, or REQUEST_IN_PROGRESS while another request creates the same plate, phone or email",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Daily registration cap reached for this device or IP",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and fails closed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/autocomplete": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suggest drivers as a dispatcher types. Every word of q must start a word of the driver's name, the plate or a letter or digit group of the plate, ignoring case and Turkish diacritics. Highlights mark the matched characters; timedOut is set, with no suggestions, when the search ran past its time budget.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Autocomplete drivers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "ahm",
+                        "description": "Start of the name or plate, up to 5 words",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 8,
+                        "description": "Maximum suggestions",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only suggest drivers of this fleet; fleet admins always get their own",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.AutocompleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/bulk-update": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply a patch to every driver matching a filter in a single update, e.g. suspend a fleet for inspection. Fleet admins only reach their own fleet. A filter matching more than BULK_UPDATE_MAX_DRIVERS drivers is refused; set dryRun to only count the drivers. Every update is recorded with the caller for the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Update many drivers at once",
+                "parameters": [
+                    {
+                        "description": "Filter, patch and dry run",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BulkUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matched and modified drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BulkUpdate"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Filter matches too many drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/changes": {
+            "get": {
+                "description": "Get the drivers created, updated and deleted since a marker so apps can update an offline cache. Pass nextToken as since to continue while hasMore is true, and keep the last nextToken for the next sync.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp or nextToken from an earlier response; omit for a full sync",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Maximum changes per response",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only sync drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DriverChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid marker",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/nearby": {
+            "get": {
+                "description": "Find drivers within 6km radius. Identical searches near the same spot are coalesced into one driver service call and cached for NEARBY_CACHE_TTL_MS; X-Cache tells whether the response was fetched for this search alone (MISS), shared between concurrent searches (SHARED) or cached (HIT). Successful results carry Cache-Control: private, max-age=NEARBY_CACHE_MAX_AGE_SEC so apps may reuse them while the map is panned. Callers other than fleet admins and tokens without a role, riders and API key callers included, get driver last names and plates masked unless RESPONSE_REDACTION_FILE says otherwise. While the driver service is unavailable an empty list is returned with X-Degraded: true, unless UPSTREAM_FALLBACK_FILE configures another stub or UPSTREAM_FALLBACK_ENABLED is off.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Find nearby drivers",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude; required unless geohash is given",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude; required unless geohash is given",
+                        "name": "lon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "sxk9kp",
+                        "description": "Search around the center of this geohash cell instead of lat and lon",
+                        "name": "geohash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Taxi type, one of GET /taxi-types",
+                        "name": "taksiType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "istanbul",
+                        "description": "Only return drivers in this city; other returns those outside every configured city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return drivers with a recent heartbeat; defaults to the driver service setting",
+                        "name": "live",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "pet-friendly",
+                        "description": "Only return drivers carrying all of these comma-separated tags",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "language:en",
+                        "description": "Only return drivers with all of these comma-separated key:value attributes; a key alone matches any value",
+                        "name": "attributes",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "km",
+                            "m",
+                            "mi"
+                        ],
+                        "type": "string",
+                        "example": "m",
+                        "description": "Also return distance in this unit, rounded for display; distanceKm stays in kilometers",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most drivers returned, nearest first; defaults to NEARBY_DEFAULT_LIMIT of the driver service and is capped at its NEARBY_MAX_LIMIT",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Nearest drivers to skip, to page through a crowded area",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Add the fare of a trip with each driver from the pricing service; drivers it could not price within PRICING_TIMEOUT_MS are returned without one",
+                        "name": "includeFares",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude the rider is going to, priced with includeFares; give with dropoffLon",
+                        "name": "dropoffLat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude the rider is going to, priced with includeFares; give with dropoffLat",
+                        "name": "dropoffLon",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of nearby drivers sorted by distance, with fares when includeFares is set",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.NearbyDriverWithFare"
+                            }
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "private, max-age=NEARBY_CACHE_MAX_AGE_SEC; no-store on stubs"
+                            },
+                            "X-Degraded": {
+                                "type": "string",
+                                "description": "true when the list is a stub served while the driver service is unavailable"
+                            },
+                            "X-Distance-Unit": {
+                                "type": "string",
+                                "description": "Unit of distance, also given as meta.distanceUnit in the envelope"
+                            },
+                            "X-Fares": {
+                                "type": "string",
+                                "description": "With includeFares: complete when every driver has a fare, partial when some do, unavailable when none could be priced; also given as meta.fares in the envelope"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/nearby/route": {
+            "post": {
+                "description": "Find drivers within widthKm (default 0.5, at most 2) of the route through 2 to 25 ordered waypoints, up to 100 km long. Each driver is returned once, sorted by distance to the route; routeKm tells where along the route the driver is.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Find drivers along a route",
+                "parameters": [
+                    {
+                        "description": "Route and corridor",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RouteSearchRequest"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "km",
+                            "m",
+                            "mi"
+                        ],
+                        "type": "string",
+                        "example": "m",
+                        "description": "Also return distance and routeDistance in this unit, rounded for display; distanceKm and routeKm stay in kilometers",
+                        "name": "units",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Drivers along the route sorted by distance to it",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.RouteDriverResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Distance-Unit": {
+                                "type": "string",
+                                "description": "Unit of distance and routeDistance, also given as meta.distanceUnit in the envelope"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Count drivers that are online (recent heartbeat) and offline. Fleet admins only see their own fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get online driver counts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only count drivers of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Online and offline counts",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.OnlineStats"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}": {
+            "get": {
+                "description": "Get driver details by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get a driver by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing driver. Location uses top-level lat/lon fields (same format as create).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Update a driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver update information",
+                        "name": "driver",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateDriverRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver updated successfully\" example({\"id\":\"507f1f77bcf86cd799439011\",\"firstName\":\"Ali\",\"lastName\":\"Kurt\",\"plate\":\"34G1234\",\"taxiType\":\"siyah\",\"carBrand\":\"Mercedes\",\"carModel\":\"G Class\",\"location\":{\"lat\":42.0082,\"lon\":28.9784},\"createdAt\":\"2025-12-06T01:00:00Z\",\"updatedAt\":\"2025-12-06T01:30:00Z\"})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error, or IMPLAUSIBLE_LOCATION for a rejected location jump\" example({\"error\":{\"code\":\"VALIDATION_ERROR\",\"message\":\"both lat and lon must be provided together\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found\" example({\"error\":{\"code\":\"NOT_FOUND\",\"message\":\"driver not found\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Plate, phone or email taken, or REQUEST_IN_PROGRESS while another request changes the same driver, plate, phone or email",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error\" example({\"error\":{\"code\":\"INTERNAL_ERROR\",\"message\":\"failed to update driver\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Answers like GET /drivers/{id} without a body. The driver service is asked with HEAD too, so the driver is not transferred.",
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Check a driver exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver exists"
+                    },
+                    "404": {
+                        "description": "Driver not found"
+                    },
+                    "500": {
+                        "description": "Internal server error"
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/availability": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Put a driver on or off shift. Going on shift requires a verified phone number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Set driver availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Availability",
+                        "name": "availability",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetAvailabilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver availability updated",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone not verified, licence expired or driver suspended\" example({\"error\":{\"code\":\"CONTACT_NOT_VERIFIED\",\"message\":\"phone must be verified before going on shift\"}})",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/device-tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the driver's device tokens, newest first, without their values",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "device-tokens"
+                ],
+                "summary": "List a driver's device tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only list tokens that are not revoked",
+                        "name": "active",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tokens",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.DeviceToken"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Issue a long-lived token for one of the driver's devices. Sent as \"Bearer dtk_...\", it only allows PUT /drivers/{id}/location and POST /drivers/{id}/heartbeat for this driver. Issuing a token for a device revokes the device's previous token; a driver can have 10 active tokens. The token is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "device-tokens"
+                ],
+                "summary": "Issue a device token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Device",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IssueDeviceTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token issued",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DeviceToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many active tokens",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/earnings": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trip earnings, commission and adjustments per day or per week (starting Monday) in the service's time zone. The range defaults to the last 30 days (daily) or 12 weeks (weekly), max 366 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver earnings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "daily",
+                            "weekly"
+                        ],
+                        "type": "string",
+                        "default": "daily",
+                        "description": "Bucket size",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver earnings",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.EarningsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period or range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/fleet": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a driver into a fleet; an empty fleetId removes the driver from its fleet. Not available to fleet admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Assign a driver to a fleet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fleet assignment",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.AssignFleetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated driver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admins cannot reassign drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/heartbeat": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark the driver as seen now. Driver apps call this periodically with their device token (scope heartbeat); it takes no body.",
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Record a driver heartbeat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Heartbeat recorded"
+                    },
+                    "403": {
+                        "description": "Device token of another driver or driver of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Incidents reported against the driver, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Driver incidents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "open",
+                        "description": "open, investigating or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high",
+                        "description": "low, medium, high or critical",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "safety",
+                        "description": "safety, conduct, vehicle, fare, route or other",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6571f1f77bcf86cd79943901",
+                        "description": "Only incidents of this trip",
+                        "name": "tripId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 20,
+                        "description": "Page size, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incidents",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ListIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Report an incident against the driver, optionally linked to a trip the driver drove; riders must link one of their own trips. High and critical incidents reported by operators and fleet admins suspend the driver until they are dismissed; critical ones also revoke the driver's identity verification, which then needs a new identity check. Rider reports are stored open without effects until an investigator confirms their severity.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Report an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ReportIncidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Incident reported",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet, or the trip to another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver or trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/kyc": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the driver's latest identity check",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Get identity check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest check",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.KYCCheck"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver or check not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send the driver's name and documents to the KYC provider. The check starts pending; identityVerified is set on the driver once the provider approves it. When a blacklist is configured the driver is looked up in it first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Submit identity check",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Check submitted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.KYCCheck"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet, or BLACKLISTED for a driver on the blacklist",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Identity verified, check pending or license document missing",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and fails closed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/location": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the driver's position and nothing else. Driver apps call this with their device token (scope location:update); fleet admin tokens work too. Besides JSON the position can be sent compactly as application/x-protobuf (message LocationUpdate { double lat = 1; double lon = 2; }) or application/vnd.bitaksi.location (8 bytes: latitude and longitude as big-endian int32 in 1e-7 degrees). Compact reports are answered with 204 and no body unless Accept lists application/json.",
+                "consumes": [
+                    "application/json",
+                    "application/x-protobuf",
+                    "application/vnd.bitaksi.location"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Report a driver's location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Position",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver location updated",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "204": {
+                        "description": "Compact location report accepted"
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or revoked token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Device token of another driver or driver of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/personal-data": {
+            "delete": {
+                "description": "Erase a driver's personal data at once on a KVKK/GDPR request instead of waiting for the retention window; a live driver is deleted first. Names, contact details, plate, attributes, location history, documents, licence, photos, device tokens and phone verifications are erased, while trips, shifts and earnings are kept for statistics and the books. The erasure is recorded with the operator from X-Admin-User and the reason.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Erase a driver's personal data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "ops-admin",
+                        "description": "Operator recorded in the audit trail",
+                        "name": "X-Admin-User",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"KVKK request #4711\"",
+                        "description": "Why the data is erased, e.g. a ticket number",
+                        "name": "reason",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Erasure record",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Erasure"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/shifts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shifts of the driver overlapping the range, by start time. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or \"Accept: text/calendar\" for an iCalendar export.",
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Driver shift calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-08\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-15\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or ics",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ShiftCalendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Plan a shift for the driver. The driver is put on shift when it starts and off shift when it ends. Shifts of a driver cannot overlap and last at most 24 hours.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Schedule a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift times",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ScheduleShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Shift scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ScheduledShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlaps another shift",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Completed trips, distance driven, online hours and average rating over a date range (default: last 30 days, max 366 days)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Get driver statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-11-01\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-01\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver statistics",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DriverStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/suspension": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspended drivers are taken off shift, cannot go on shift again and are left out of nearby searches. Webhook subscribers receive a driver.suspended event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "drivers"
+                ],
+                "summary": "Suspend or reinstate a driver",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Suspension",
+                        "name": "suspension",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetSuspensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Driver suspension updated",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/verify-phone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirm the one-time code sent to the driver's phone",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Verify phone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verification code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.VerifyPhoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Phone verified",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Driver"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired code",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/drivers/{id}/verify-phone/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a one-time verification code to the driver's phone via SMS",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Send phone verification code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"507f1f77bcf86cd799439011\"",
+                        "description": "Driver ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Code sent",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone missing or already verified",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Code sent recently",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export every driver, or one fleet's, in the background; fleet admins always export their own fleet. The export is a snapshot of the drivers that existed when it started; drivers updated since are flagged with changedDuringExport. Poll GET /exports/{id} until status is completed, then download the file from GET /exports/{id}/download. Exports are kept for JOB_RETENTION_HOURS after they finish.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Start a driver export",
+                "parameters": [
+                    {
+                        "description": "Format and fleet; an empty body exports every driver as NDJSON",
+                        "name": "export",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Export queued",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Job"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the export status"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many jobs waiting",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Status and progress of an export: processed against total drivers, and once completed the file name, type and size to download.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Get a driver export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"job-9f86d081884c7d65\"",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exports/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "The exported drivers, as NDJSON (one driver per line) or a JSON array. The file is streamed from the driver service as it is read.",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Download a driver export",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"job-9f86d081884c7d65\"",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported drivers",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Export not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Export still running or failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List all fleets. Not available to fleet admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "List fleets",
+                "responses": {
+                    "200": {
+                        "description": "Fleets",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.Fleet"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admins cannot list fleets",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a fleet (taxi company). Not available to fleet admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Create a fleet",
+                "parameters": [
+                    {
+                        "description": "Fleet information",
+                        "name": "fleet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateFleetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Fleet created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Fleet"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admins cannot create fleets",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Fleet name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleets/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a fleet by ID. Fleet admins can only read their own fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "Get a fleet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6570a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Fleet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Fleet"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Fleet not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fleets/{id}/drivers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a paginated list of the drivers in a fleet. Fleet admins can only list their own fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fleets"
+                ],
+                "summary": "List fleet drivers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6570a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Fleet ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of drivers",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ListDriversResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Incidents matching the filters, newest first. Fleet admins only see incidents reported while the driver was in their fleet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Incident queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only incidents of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only incidents of this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "open",
+                        "description": "open, investigating or resolved",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "high",
+                        "description": "low, medium, high or critical",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "safety",
+                        "description": "safety, conduct, vehicle, fare, route or other",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "6571f1f77bcf86cd79943901",
+                        "description": "Only incidents of this trip",
+                        "name": "tripId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "example": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "example": 20,
+                        "description": "Page size, at most 100",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incidents",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ListIncidentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get an incident with its investigation status and outcome",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get an incident",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6575a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incident",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Incident"
+                        }
+                    },
+                    "403": {
+                        "description": "Incident of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an incident from open to investigating or resolved, or from investigating to resolved; resolved incidents cannot change. Moving to investigating with a severity confirms it: a high or critical one then suspends the driver, and a critical one revokes identity verification, if the report did not already. Resolving takes an outcome: upheld keeps a suspension the incident caused, dismissed lifts it unless another unresolved incident holds the driver. Revoked identity verification is not restored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Move an incident's investigation on",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6575a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.IncidentStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Incident updated",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Incident of another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Incident not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Status does not allow the change",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/kyc/webhook": {
+            "post": {
+                "description": "Inbound webhook for the KYC provider, passed to the driver service unchanged. The body {\"reference\",\"status\",\"reason\"} must be signed in the X-KYC-Signature header as \"t=\u003cunix seconds\u003e,v1=\u003chex HMAC-SHA256 of '\u003ct\u003e.\u003cbody\u003e'\u003e\" with the shared webhook secret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "verification"
+                ],
+                "summary": "Receive KYC decision",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "t=1733446800,v1=5d41402abc4b2a76b9719d911017c592",
+                        "description": "Webhook signature",
+                        "name": "X-KYC-Signature",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Decision applied",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.KYCCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid signature",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown check",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/onboarding/drivers": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a driver, attach their documents, send a phone verification code and optionally put them on shift in one call. If a required step fails, the completed steps are rolled back and the response reports each step's outcome.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "onboarding"
+                ],
+                "summary": "Onboard a driver",
+                "parameters": [
+                    {
+                        "description": "Driver, documents and availability",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.OnboardingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device fingerprint; registrations are capped per device and per IP each day",
+                        "name": "X-Device-Fingerprint",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Driver onboarded (status completed or pending_verification)",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.OnboardingResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Fleet admin onboarding into another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Rolled back after a conflict, e.g. phone already registered",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.OnboardingResult"
+                        }
+                    },
+                    "429": {
+                        "description": "Daily registration cap reached for this device or IP",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Rolled back, or rollback failed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.OnboardingResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/openapi.json": {
+            "get": {
+                "description": "Get the gateway's Swagger 2.0 spec merged with the driver service's. Driver service operations are listed under /driver-service/api/v1 with the driver-service tag and their definitions are prefixed with \"driver-service.\". Operations tagged with one of SWAGGER_INTERNAL_TAGS are left out; GET /admin/openapi.json lists them. When the driver service is unreachable only the gateway spec is returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "docs"
+                ],
+                "summary": "Get the merged API spec",
+                "responses": {
+                    "200": {
+                        "description": "Swagger 2.0 spec",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/riders": {
+            "post": {
+                "description": "Register a rider and get an access token with the rider role. Phone numbers must be unique.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Register a rider",
+                "parameters": [
+                    {
+                        "description": "Rider information",
+                        "name": "rider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RegisterRiderRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device fingerprint; registrations are capped per device and per IP each day",
+                        "name": "X-Device-Fingerprint",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rider registered",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RiderRegistrationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone already registered",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Daily registration cap reached for this device or IP",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/sign-in": {
+            "post": {
+                "description": "Sign a rider in with the code texted by /riders/sign-in/send and get a new access token with the rider role, e.g. once the previous one has expired.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Sign a rider in",
+                "parameters": [
+                    {
+                        "description": "Rider phone and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RiderSignInRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider signed in",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RiderSignInResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or expired code",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts; request a new code",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/sign-in/send": {
+            "post": {
+                "description": "Text a one-time sign-in code to the rider registered with the phone. Unknown phones get the same answer, so the response does not tell whether the phone is registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Send a rider sign-in code",
+                "parameters": [
+                    {
+                        "description": "Rider phone",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RiderSignInCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Code sent if the phone is registered\" example({\"status\":\"sent\"})",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Code sent recently",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a rider's profile and favorite locations. Riders can only read their own profile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Get a rider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Rider"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a rider's profile; only the fields in the request body change. Riders can only update their own profile.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Update a rider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Profile fields to change",
+                        "name": "rider",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateRiderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Phone already registered",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}/favorites": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save a labelled place, such as home or work, for quick trip requests. A rider can save up to 20 places.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Save a favorite location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Place to save",
+                        "name": "favorite",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FavoriteLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rider with the saved place",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Rider"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many favorite locations",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/riders/{id}/favorites/{favoriteId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a saved place from the rider's favorites",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "riders"
+                ],
+                "summary": "Remove a favorite location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6573a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Rider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"fav_3f9a1c2b\"",
+                        "description": "Favorite location ID",
+                        "name": "favoriteId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rider without the removed place",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Rider"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rider or favorite location not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shifts overlapping the range, by start time, for a fleet or a driver. Fleet admins only see their own fleet. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or \"Accept: text/calendar\" for an iCalendar export.",
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Shift calendar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "6570a1f2c3d4e5f6a7b8c9d0",
+                        "description": "Only shifts of this fleet's drivers",
+                        "name": "fleetId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "507f1f77bcf86cd799439011",
+                        "description": "Only shifts of this driver",
+                        "name": "driverId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-08\"",
+                        "description": "Range start (RFC3339 or YYYY-MM-DD, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"2025-12-15\"",
+                        "description": "Range end (RFC3339 or YYYY-MM-DD, exclusive)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or ics",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shifts",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ShiftCalendar"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Driver not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/shifts/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a planned shift, with when the driver was put on and off shift",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ScheduledShift"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a shift. Once the driver was put on shift only its end can move; ended shifts cannot change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Reschedule a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift times",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ScheduleShiftRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ScheduledShift"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Overlaps another shift, or the shift started or ended",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a planned shift. A running shift cannot be deleted; move its end instead.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Cancel a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6574a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Shift deleted"
+                    },
+                    "403": {
+                        "description": "Driver belongs to another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Shift is running",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/taxi-types": {
+            "get": {
+                "description": "The taxi types the service runs, in the order clients should show them, with their name, seats and icon. With fleetId, only the types drivers of that fleet may register with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxi-types"
+                ],
+                "summary": "List taxi types",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list the types this fleet's drivers may register with",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Taxi types",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TaxiTypeCatalog"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a ride request; the driver service offers it to a driver chosen by the configured matching strategy.\nRiders request trips for themselves: riderId is set from their token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Request a trip",
+                "parameters": [
+                    {
+                        "description": "Ride request",
+                        "name": "trip",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateTripRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Trip created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Trip for another rider",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/estimate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Quote the fare from pickup to dropoff without requesting a trip. Distances are road distances when the driver service routes with OSRM.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Estimate a fare",
+                "parameters": [
+                    {
+                        "description": "Pickup and dropoff",
+                        "name": "route",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.EstimateFareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fare estimate",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FareEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a trip and its current offer by ID. Riders can only read their own trips.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Get trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider's trip",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accept the trip offered to the driver. Not available to riders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Accept trip offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver answering the offer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TripOfferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Riders cannot answer offers, nor fleet admins for another fleet's driver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No active offer",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a trip that has not finished. Riders can only cancel their own trips.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Cancel trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "403": {
+                        "description": "Another rider's trip",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Trip already finished",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Finish an accepted trip, optionally rating the driver from 1 to 5. Not available to riders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Complete trip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trip summary",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CompleteTripRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip completed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Riders cannot complete trips, nor fleet admins those of another fleet's driver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Trip not accepted",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trips/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Decline the trip offered to the driver; the trip is re-offered to the next driver. Not available to riders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trips"
+                ],
+                "summary": "Decline trip offer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6571f1f77bcf86cd79943901\"",
+                        "description": "Trip ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Driver answering the offer",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TripOfferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Trip re-offered",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.Trip"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Riders cannot answer offers, nor fleet admins for another fleet's driver",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Trip not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "No active offer",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List webhook subscriptions without their secrets. Fleet admins only see their own fleet's subscriptions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list subscriptions of this fleet",
+                        "name": "fleetId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscriptions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.WebhookSubscription"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe an endpoint to driver events (driver.created, driver.updated, driver.suspended, driver.license_expired). Deliveries are signed with HMAC-SHA256 in the X-Bitaksi-Signature header and retried with exponential backoff. The secret is only returned in this response. The url must use https outside debug mode and must not point to a loopback, private or link-local address. Fleet admins always subscribe to their own fleet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Subscription",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WebhookSubscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a webhook subscription by ID without its secret",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WebhookSubscription"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a subscription; its pending deliveries are not retried",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Subscription deleted"
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delivery log of a subscription, newest first, with attempts, last response status and next retry. Deliveries are kept for 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum deliveries to return (max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries/{deliveryId}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a delivery again as soon as possible. Failed deliveries get a fresh set of attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Retry a webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "\"6572a1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "\"6572b1f2c3d4e5f6a7b8c9d0\"",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Delivery rescheduled",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WebhookDelivery"
+                        }
+                    },
+                    "403": {
+                        "description": "Another fleet",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Delivery in progress",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
+        "github_com_bitaksi_gateway_internal_adaptive.Stats": {
+            "type": "object",
+            "properties": {
+                "averageLatencyMs": {
+                    "description": "AverageLatencyMs is a moving average of upstream response times",
+                    "type": "number",
+                    "example": 48.5
+                },
+                "decreases": {
+                    "description": "Decreases counts how often congestion lowered the limit",
+                    "type": "integer",
+                    "example": 9
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "failures": {
+                    "type": "integer",
+                    "example": 17
+                },
+                "inFlight": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "latencyThresholdMs": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 87
+                },
+                "maxLimit": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "minLimit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "rejected": {
+                    "description": "Rejected counts requests answered with 503 without reaching the upstream",
+                    "type": "integer",
+                    "example": 42
+                },
+                "slowResponses": {
+                    "description": "SlowResponses counts successes slower than the latency threshold",
+                    "type": "integer",
+                    "example": 120
+                },
+                "successes": {
+                    "type": "integer",
+                    "example": 98231
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.BulkFilter": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Fiat"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Egea"
+                },
+                "city": {
+                    "type": "string",
+                    "example": "istanbul"
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "507f1f77bcf86cd799439011"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly"
+                    ]
+                },
+                "taksiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.CreateDriverRequest": {
+            "type": "object",
+            "required": [
+                "carBrand",
+                "carModel",
+                "firstName",
+                "lastName",
+                "lat",
+                "lon",
+                "plate",
+                "taksiType"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are at most 20 keys, named like tags, with values of up to 64 characters",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ahmet.demir@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "fleetId": {
+                    "description": "FleetID places the driver in a fleet; fleet admins always create drivers in their own fleet",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905321234567"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "tags": {
+                    "description": "Tags are at most 20 lowercase words joined by dashes, e.g. pet-friendly",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taksiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.Driver": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "carBrand": {
+                    "type": "string"
+                },
+                "carModel": {
+                    "type": "string"
+                },
+                "city": {
+                    "description": "City is the city the location is in, or other outside every configured city",
+                    "type": "string",
+                    "example": "istanbul"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set on the tombstone a deleted driver leaves for delta syncs",
+                    "type": "string"
+                },
+                "documents": {
+                    "description": "Documents holds at most one document per type",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverDocument"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "fleetId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identityVerified": {
+                    "description": "IdentityVerified is set once the KYC provider approved the driver's identity",
+                    "type": "boolean"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "lastSeenAt": {
+                    "type": "string"
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                },
+                "licenseExpired": {
+                    "description": "LicenseExpired drivers have been taken off dispatch until the licence is renewed",
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "object",
+                    "properties": {
+                        "lat": {
+                            "type": "number"
+                        },
+                        "lon": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "locationUpdatedAt": {
+                    "description": "LocationUpdatedAt is when the location was last reported",
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "phoneVerified": {
+                    "type": "boolean"
+                },
+                "photo": {
+                    "description": "Photo is the driver's profile picture, if one was uploaded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverPhoto"
+                        }
+                    ]
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "suspended": {
+                    "type": "boolean"
+                },
+                "suspensionReason": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags and Attributes describe what the driver offers, for dispatch filters",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taxiType": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.DriverDocument": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "license",
+                        "registration",
+                        "insurance"
+                    ],
+                    "example": "license"
+                },
+                "uploadedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://files.bitaksi.com/docs/license.pdf"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.DriverLicense": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "type": "string",
+                    "enum": [
+                        "B",
+                        "BE",
+                        "C1",
+                        "C1E",
+                        "C",
+                        "CE",
+                        "D1",
+                        "D1E",
+                        "D",
+                        "DE"
+                    ],
+                    "example": "B"
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.DriverPatch": {
+            "type": "object",
+            "properties": {
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "suspended": {
+                    "description": "Suspended suspends or reinstates the drivers; suspending takes them off shift",
+                    "type": "boolean",
+                    "example": true
+                },
+                "suspensionReason": {
+                    "type": "string",
+                    "example": "fleet inspection"
+                },
+                "taksiType": {
+                    "type": "string",
+                    "example": "turkuaz"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.DriverPhoto": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer",
+                    "example": 768
+                },
+                "thumbnails": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "uploadedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/full.jpg"
+                },
+                "width": {
+                    "type": "integer",
+                    "example": 1024
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.DriverSuggestion": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.SearchHighlight"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Ahmet Demir"
+                },
+                "plate": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.EarningsBucket": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00+03:00"
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.EarningsTotals": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.ExpiringLicense": {
+            "type": "object",
+            "properties": {
+                "daysLeft": {
+                    "description": "DaysLeft is negative once the licence has expired",
+                    "type": "integer",
+                    "example": 12
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "expired": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "flagged": {
+                    "description": "Flagged drivers have been taken off dispatch by the licence check",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.FareEstimate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 232.4
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "durationSec": {
+                    "type": "integer",
+                    "example": 1080
+                },
+                "provider": {
+                    "description": "Provider is the routing provider that measured the route: haversine or osrm",
+                    "type": "string",
+                    "example": "osrm"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.FareQuote": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 185.5
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.FavoriteLocation": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Caferağa Mah. Moda Cad. No:1, Kadıköy"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "fav_3f9a1c2b"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Home"
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.Incident": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "safety",
+                        "conduct",
+                        "vehicle",
+                        "fare",
+                        "route",
+                        "other"
+                    ],
+                    "example": "conduct"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Driver was on the phone during the whole ride"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "driverSuspended": {
+                    "description": "DriverSuspended is set when reporting the incident suspended the driver",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fleetId": {
+                    "description": "FleetID is the driver's fleet when the incident was reported",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6575a1f2c3d4e5f6a7b8c9d0"
+                },
+                "identityRevoked": {
+                    "description": "IdentityRevoked is set when reporting the incident revoked the driver's identity verification",
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "description": "Note is the latest remark of the investigation",
+                    "type": "string",
+                    "example": "Rider confirmed the driver used a hands-free set"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "upheld",
+                        "dismissed"
+                    ],
+                    "example": "dismissed"
+                },
+                "reportedBy": {
+                    "description": "ReportedBy and ReporterRole identify the caller who reported it; empty for operators",
+                    "type": "string",
+                    "example": "rider-42"
+                },
+                "reporterRole": {
+                    "type": "string",
+                    "example": "rider"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2025-12-07T09:30:00Z"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "critical"
+                    ],
+                    "example": "medium"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "investigating",
+                        "resolved"
+                    ],
+                    "example": "open"
+                },
+                "tripId": {
+                    "description": "TripID is the trip the incident happened on, if any",
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.IndexStatus": {
+            "type": "object",
+            "properties": {
+                "actualKeys": {
+                    "type": "string",
+                    "example": "fleetId:1"
+                },
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "keys": {
+                    "type": "string",
+                    "example": "fleetId:1,createdAt:-1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "fleetId_createdAt"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "present",
+                        "missing",
+                        "mismatch",
+                        "unexpected"
+                    ],
+                    "example": "present"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.IndexSyncError": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "E11000 duplicate key error"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "phone_unique"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.JobResult": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "type": "string",
+                    "example": "application/x-ndjson"
+                },
+                "fileName": {
+                    "type": "string",
+                    "example": "drivers.ndjson"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 48210331
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.Location": {
+            "type": "object",
+            "properties": {
+                "geohash": {
+                    "description": "Geohash is the cell of the coordinates, rendered when the driver service sets GEOHASH_PRECISION",
+                    "type": "string",
+                    "example": "sxk9kp"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.LocationAnomalyCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "lastDetectedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "maxSpeedKmh": {
+                    "type": "number",
+                    "example": 11520
+                },
+                "rejected": {
+                    "description": "Rejected is how many of the updates were refused",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.PayoutLine": {
+            "type": "object",
+            "properties": {
+                "adjustments": {
+                    "type": "number",
+                    "example": 50
+                },
+                "commission": {
+                    "type": "number",
+                    "example": 418.32
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 2788.8
+                },
+                "net": {
+                    "type": "number",
+                    "example": 2420.48
+                },
+                "trips": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.QueryBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 47890
+                },
+                "leMs": {
+                    "type": "number",
+                    "example": 50
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.Rider": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "elif@example.com"
+                },
+                "favoriteLocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.FavoriteLocation"
+                    }
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Yılmaz"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.ScheduledShift": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "endedAt": {
+                    "description": "EndedAt is when the driver was taken off shift",
+                    "type": "string",
+                    "example": "2025-12-08T14:00:09Z"
+                },
+                "endsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T14:00:00Z"
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6574a1f2c3d4e5f6a7b8c9d0"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Airport rank"
+                },
+                "startError": {
+                    "description": "StartError is why the driver could not be put on shift",
+                    "type": "string",
+                    "example": "phone must be verified before going on shift"
+                },
+                "startedAt": {
+                    "description": "StartedAt is when the driver was put on shift",
+                    "type": "string",
+                    "example": "2025-12-08T06:00:12Z"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.SearchHighlight": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "name"
+                },
+                "length": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.TaxiType": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "description": "Capacity is the number of passenger seats",
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Standard city taxi"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "taxi-yellow"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "sari"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Sarı Taksi"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.TelemetryCounts": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 182101
+                },
+                "received": {
+                    "type": "integer",
+                    "example": 182340
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 230
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.UploadDocumentRequest": {
+            "type": "object",
+            "required": [
+                "type",
+                "url"
+            ],
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "number": {
+                    "type": "string",
+                    "example": "TR-1234567"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "license",
+                        "registration",
+                        "insurance"
+                    ],
+                    "example": "license"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://files.bitaksi.com/docs/license.pdf"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.UtilizationDay": {
+            "type": "object",
+            "properties": {
+                "busyHours": {
+                    "type": "number",
+                    "example": 268.1
+                },
+                "day": {
+                    "type": "string",
+                    "example": "2025-12-01"
+                },
+                "onlineHours": {
+                    "type": "number",
+                    "example": 412.5
+                },
+                "partial": {
+                    "description": "Partial is set on the current day",
+                    "type": "boolean",
+                    "example": false
+                },
+                "taxiType": {
+                    "type": "string",
+                    "example": "sari"
+                },
+                "utilization": {
+                    "type": "number",
+                    "example": 0.65
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_apimodel.ValidationRuleset": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string",
+                    "example": "TR"
+                },
+                "fieldPatterns": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "plateExample": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "platePattern": {
+                    "description": "PlatePattern is empty where the Turkish plate format applies",
+                    "type": "string",
+                    "example": "^[0-9]{2}[A-Z]{2}[0-9]{3}$"
+                },
+                "requiredFields": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "firstName",
+                        "lastName",
+                        "carBrand",
+                        "carModel"
+                    ]
+                },
+                "taxiTypes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sari",
+                        "turkuaz",
+                        "siyah"
+                    ]
+                },
+                "tenant": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_canary.Stats": {
+            "type": "object",
+            "properties": {
+                "canary": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_canary.UpstreamStats"
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "fallbackUntil": {
+                    "type": "string",
+                    "example": "2025-12-06T12:05:00Z"
+                },
+                "fallbacks": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "percent": {
+                    "description": "Percent is the configured share of requests sent to the canary",
+                    "type": "number",
+                    "example": 5
+                },
+                "primary": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_canary.UpstreamStats"
+                },
+                "state": {
+                    "description": "State is \"active\", or \"fallback\" while traffic stays on the primary",
+                    "type": "string",
+                    "example": "active"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "http://driver-service-canary:8081"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_canary.UpstreamStats": {
+            "type": "object",
+            "properties": {
+                "avgLatencyMs": {
+                    "type": "number",
+                    "example": 18.4
+                },
+                "errorRatePercent": {
+                    "type": "number",
+                    "example": 0.02
+                },
+                "failures": {
+                    "description": "Failures counts transport errors, timeouts and 5xx responses",
+                    "type": "integer",
+                    "example": 12
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 48210
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_coalesce.Stats": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "description": "Entries is how many responses are cached, expired ones not yet swept included",
+                    "type": "integer",
+                    "example": 412
+                },
+                "hitRate": {
+                    "description": "HitRate is the share of calls answered without an upstream call of their own",
+                    "type": "number",
+                    "example": 0.82
+                },
+                "hits": {
+                    "type": "integer",
+                    "example": 90412
+                },
+                "misses": {
+                    "type": "integer",
+                    "example": 20311
+                },
+                "shared": {
+                    "type": "integer",
+                    "example": 5120
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_hedge.Stats": {
+            "type": "object",
+            "properties": {
+                "budgetExhausted": {
+                    "description": "BudgetExhausted counts slow requests not hedged because the budget was spent",
+                    "type": "integer",
+                    "example": 35
+                },
+                "budgetPercent": {
+                    "type": "number",
+                    "example": 10
+                },
+                "delayMs": {
+                    "type": "number",
+                    "example": 84.5
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "hedgeWins": {
+                    "description": "HedgeWins counts hedged requests the hedge answered first",
+                    "type": "integer",
+                    "example": 1630
+                },
+                "hedged": {
+                    "description": "Hedged counts the requests a hedge was sent for",
+                    "type": "integer",
+                    "example": 2210
+                },
+                "primaryWins": {
+                    "description": "PrimaryWins counts hedged requests the first request still answered first",
+                    "type": "integer",
+                    "example": 580
+                },
+                "requests": {
+                    "description": "Requests counts the requests that could have been hedged",
+                    "type": "integer",
+                    "example": 48210
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_logging.LevelsSnapshot": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "info"
+                },
+                "loggers": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_middleware.DeviceCacheStats": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer",
+                    "example": 8120
+                },
+                "hitRate": {
+                    "type": "number",
+                    "example": 0.962
+                },
+                "hits": {
+                    "type": "integer",
+                    "example": 512930
+                },
+                "misses": {
+                    "description": "Misses were verified with the driver service",
+                    "type": "integer",
+                    "example": 20311
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_middleware.MaintenanceStatus": {
+            "type": "object",
+            "properties": {
+                "allowAdmin": {
+                    "description": "AllowAdmin keeps the /admin routes served during maintenance",
+                    "type": "boolean",
+                    "example": true
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "type": "string",
+                    "example": "TaxiHub is undergoing planned maintenance, please try again shortly"
+                },
+                "rejected": {
+                    "description": "Rejected counts the requests answered with 503 since then",
+                    "type": "integer",
+                    "example": 1520
+                },
+                "retryAfterSec": {
+                    "description": "RetryAfterSec is the Retry-After sent to clients",
+                    "type": "integer",
+                    "example": 300
+                },
+                "since": {
+                    "description": "Since is when maintenance mode was last switched on",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_middleware.RateLimitStats": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "integer",
+                    "example": 1829310
+                },
+                "clients": {
+                    "description": "Clients is how many IPs, subjects and API keys have a bucket",
+                    "type": "integer",
+                    "example": 3120
+                },
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 412
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_middleware.SaturationStats": {
+            "type": "object",
+            "properties": {
+                "inFlight": {
+                    "type": "integer",
+                    "example": 420
+                },
+                "maxInFlight": {
+                    "description": "MaxInFlight is 0 when requests are not limited",
+                    "type": "integer",
+                    "example": 1000
+                },
+                "openConnections": {
+                    "type": "integer",
+                    "example": 230
+                },
+                "peakInFlight": {
+                    "type": "integer",
+                    "example": 812
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "saturation": {
+                    "description": "Saturation is InFlight / MaxInFlight, or 0 without a limit",
+                    "type": "number",
+                    "example": 0.42
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_middleware.SecurityEvent": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "string",
+                    "enum": [
+                        "device",
+                        "ip"
+                    ],
+                    "example": "device"
+                },
+                "firstSeen": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "lastSeen": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "rejected": {
+                    "description": "Rejected counts the registrations refused today",
+                    "type": "integer",
+                    "example": 4
+                },
+                "scope": {
+                    "description": "Scope is the kind of account being created",
+                    "type": "string",
+                    "enum": [
+                        "drivers",
+                        "riders"
+                    ],
+                    "example": "drivers"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "registration_limit_exceeded"
+                },
+                "value": {
+                    "description": "Value is the device fingerprint or client IP that went over the limit",
+                    "type": "string",
+                    "example": "c0ffee42"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_policy.Rule": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description is for reviewers and not interpreted",
+                    "type": "string"
+                },
+                "method": {
+                    "description": "Method is an HTTP method or \"*\" for all; GET rules also cover HEAD",
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Path is a route as registered, e.g. /drivers/:id; a trailing /* also\nmatches the path itself and every route below it",
+                    "type": "string"
+                },
+                "roles": {
+                    "description": "Roles limits jwt and device routes to tokens carrying one of these roles.\nTokens without a role have full access and always pass; empty allows any role.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope": {
+                    "description": "Scope is the device token scope a device route requires",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_service.LatencyStats": {
+            "type": "object",
+            "properties": {
+                "maxMs": {
+                    "type": "number",
+                    "example": 512.3
+                },
+                "p50Ms": {
+                    "type": "number",
+                    "example": 12.4
+                },
+                "p90Ms": {
+                    "type": "number",
+                    "example": 38.1
+                },
+                "p99Ms": {
+                    "type": "number",
+                    "example": 140.7
+                },
+                "samples": {
+                    "description": "Samples is how many responses the percentiles are taken over",
+                    "type": "integer",
+                    "example": 1000
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_service.OnboardingResult": {
+            "type": "object",
+            "properties": {
+                "driver": {
+                    "type": "object"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "error": {
+                    "description": "FailedStep is the error that triggered the rollback",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.UpstreamError"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "completed",
+                        "pending_verification",
+                        "rolled_back",
+                        "rollback_failed"
+                    ],
+                    "example": "completed"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.OnboardingStep"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_service.OnboardingStep": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.UpstreamError"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "create_driver"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "done",
+                        "pending",
+                        "failed",
+                        "compensated",
+                        "skipped"
+                    ],
+                    "example": "done"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_service.UpstreamError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_slo.IndicatorStatus": {
+            "type": "object",
+            "properties": {
+                "budgetRemaining": {
+                    "description": "BudgetRemaining is the share of the error budget left over the longest\nwindow; it goes negative once the budget is overspent",
+                    "type": "number",
+                    "example": 0.82
+                },
+                "objective": {
+                    "description": "Objective is the target share of good requests",
+                    "type": "number",
+                    "example": 0.999
+                },
+                "thresholdMs": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_slo.WindowStatus"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_slo.ObjectiveStatus": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_slo.IndicatorStatus"
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 41
+                },
+                "latency": {
+                    "description": "Latency is omitted when the objective has no latency threshold",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_slo.IndicatorStatus"
+                        }
+                    ]
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nearby"
+                },
+                "requests": {
+                    "description": "Requests, Errors and SlowRequests count since the gateway started",
+                    "type": "integer",
+                    "example": 184220
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/drivers/nearby"
+                },
+                "slowRequests": {
+                    "type": "integer",
+                    "example": 960
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_slo.Report": {
+            "type": "object",
+            "properties": {
+                "generatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T10:00:00Z"
+                },
+                "objectives": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_slo.ObjectiveStatus"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_slo.WindowStatus": {
+            "type": "object",
+            "properties": {
+                "bad": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "burnRate": {
+                    "description": "BurnRate is how much faster than sustainable the error budget is spent;\nat 1 it lasts exactly the window",
+                    "type": "number",
+                    "example": 0.59
+                },
+                "ratio": {
+                    "description": "Ratio is the share of good requests; 1 without requests",
+                    "type": "number",
+                    "example": 0.99941
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 5120
+                },
+                "window": {
+                    "type": "string",
+                    "example": "1h"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_tap.Capture": {
+            "type": "object",
+            "properties": {
+                "capturedAt": {
+                    "type": "string"
+                },
+                "latencyMs": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/drivers/507f1f77bcf86cd799439011"
+                },
+                "requestBody": {
+                    "type": "string"
+                },
+                "requestHeaders": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "requestTruncated": {
+                    "type": "boolean"
+                },
+                "responseBody": {
+                    "type": "string"
+                },
+                "responseHeaders": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "responseTruncated": {
+                    "type": "boolean"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/drivers/:id"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "tapId": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_tap.Tap": {
+            "type": "object",
+            "properties": {
+                "captured": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "a1b2c3d4e5f60718"
+                },
+                "maxBodyBytes": {
+                    "type": "integer",
+                    "example": 65536
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "req-123"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/drivers/:id"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_token.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string",
+                    "example": "RS256"
+                },
+                "e": {
+                    "type": "string",
+                    "example": "AQAB"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "kty": {
+                    "type": "string",
+                    "example": "RSA"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string",
+                    "example": "sig"
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_token.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_token.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_bitaksi_gateway_internal_usage.Record": {
+            "type": "object",
+            "properties": {
+                "day": {
+                    "type": "string",
+                    "example": "2025-12-06"
+                },
+                "errors": {
+                    "description": "Errors counts responses with a 4xx or 5xx status",
+                    "type": "integer",
+                    "example": 12
+                },
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/api/v1/drivers/nearby"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "key:sk_live_****z789"
+                },
+                "tenant": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "internal_handler.AssignFleetRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "internal_handler.AuthPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Default applies to routes no rule matches",
+                    "type": "string",
+                    "example": "jwt"
+                },
+                "routes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handler.RouteAuth"
+                    }
+                },
+                "unusedRules": {
+                    "description": "UnusedRules match no route, typically a typo in the policy file",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_policy.Rule"
+                    }
+                }
+            }
+        },
+        "internal_handler.AutocompleteResponse": {
+            "type": "object",
+            "properties": {
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverSuggestion"
+                    }
+                },
+                "timedOut": {
+                    "description": "TimedOut is set when the search ran past its time budget and returned no suggestions",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "internal_handler.BuildInfo": {
+            "type": "object",
+            "properties": {
+                "goVersion": {
+                    "type": "string",
+                    "example": "go1.21.5"
+                },
+                "modified": {
+                    "description": "Modified is set when the binary was built from a tree with uncommitted changes",
+                    "type": "boolean"
+                },
+                "revision": {
+                    "type": "string",
+                    "example": "a56ad58c1f0e"
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T09:00:00Z"
+                },
+                "uptimeSec": {
+                    "type": "integer",
+                    "example": 86400
+                },
+                "version": {
+                    "type": "string",
+                    "example": "(devel)"
+                }
+            }
+        },
+        "internal_handler.BulkUpdate": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "type": "string",
+                    "example": "fleet-admin"
+                },
+                "actorRole": {
+                    "type": "string",
+                    "example": "fleet_admin"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "filter": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.BulkFilter"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6578a1f2c3d4e5f6a7b8c9d0"
+                },
+                "matched": {
+                    "description": "Matched is the number of drivers the filter matched",
+                    "type": "integer",
+                    "example": 42
+                },
+                "modified": {
+                    "description": "Modified is the number of drivers the patch changed, or would change on a dry run",
+                    "type": "integer",
+                    "example": 40
+                },
+                "patch": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverPatch"
+                },
+                "tenantId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                }
+            }
+        },
+        "internal_handler.BulkUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "description": "DryRun counts the drivers the update would change without changing them",
+                    "type": "boolean",
+                    "example": true
+                },
+                "filter": {
+                    "description": "Filter must set at least one field; fleet admins are always limited to their own fleet",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.BulkFilter"
+                        }
+                    ]
+                },
+                "patch": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverPatch"
+                }
+            }
+        },
+        "internal_handler.CacheStats": {
+            "type": "object",
+            "properties": {
+                "deviceTokens": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.DeviceCacheStats"
+                },
+                "nearby": {
+                    "description": "Nearby is the nearby search micro-cache; absent when NEARBY_COALESCE is off",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_coalesce.Stats"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.CompleteTripRequest": {
+            "type": "object",
+            "properties": {
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "fare": {
+                    "description": "Fare is the metered fare; when omitted the distance is priced with the tariff",
+                    "type": "number",
+                    "example": 232.4
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 5
+                }
+            }
+        },
+        "internal_handler.CreateAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "driverId",
+                "reason"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "Amount is credited to the driver; negative amounts deduct",
+                    "type": "number",
+                    "example": 50
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Toll reimbursement for trip 6571f1f77bcf86cd79943901"
+                }
+            }
+        },
         "internal_handler.CreateDriverRequest": {
             "type": "object",
             "required": [
-                "carBrand",
-                "carModel",
-                "firstName",
-                "lastName",
-                "lat",
-                "lon",
-                "plate",
-                "taksiType"
+                "carBrand",
+                "carModel",
+                "firstName",
+                "lastName",
+                "lat",
+                "lon",
+                "plate",
+                "taksiType"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "Attributes are at most 20 keys, named like tags, with values of up to 64 characters",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "carBrand": {
+                    "type": "string",
+                    "example": "Toyota"
+                },
+                "carModel": {
+                    "type": "string",
+                    "example": "Corolla"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ahmet.demir@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Ahmet"
+                },
+                "fleetId": {
+                    "description": "FleetID places the driver in a fleet; fleet admins always create drivers in their own fleet",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Demir"
+                },
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                },
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905321234567"
+                },
+                "plate": {
+                    "type": "string",
+                    "example": "34ABC123"
+                },
+                "tags": {
+                    "description": "Tags are at most 20 lowercase words joined by dashes, e.g. pet-friendly",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taksiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "internal_handler.CreateFleetRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "companyName": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi Ltd. Şti."
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi"
+                }
+            }
+        },
+        "internal_handler.CreatePayoutRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From is optional; without it every unpaid entry before To is paid out",
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00+03:00"
+                },
+                "to": {
+                    "description": "To defaults to the start of the current day",
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00+03:00"
+                }
+            }
+        },
+        "internal_handler.CreateTapRequest": {
+            "type": "object",
+            "properties": {
+                "maxBodyBytes": {
+                    "type": "integer",
+                    "example": 16384
+                },
+                "requestId": {
+                    "type": "string",
+                    "example": "req-123"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "/drivers/:id"
+                },
+                "ttlSeconds": {
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
+        "internal_handler.CreateTripRequest": {
+            "type": "object",
+            "required": [
+                "pickup"
+            ],
+            "properties": {
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "riderId": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "taxiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "internal_handler.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Kadıköy Taksi dispatch system"
+                },
+                "events": {
+                    "description": "Events filters the event types to deliver; empty delivers every event",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "driver.created",
+                        "driver.suspended"
+                    ]
+                },
+                "fleetId": {
+                    "description": "FleetID limits the subscription to one fleet; fleet admins always subscribe to their own fleet",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "secret": {
+                    "description": "Secret signs deliveries; one is generated when empty",
+                    "type": "string",
+                    "example": "whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://partner.example.com/hooks/bitaksi"
+                }
+            }
+        },
+        "internal_handler.DeviceToken": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "deviceId": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastUsedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T09:30:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mehmet's Android"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "dtk_4f1c9a7e"
+                },
+                "replacedBy": {
+                    "description": "ReplacedBy is the token a rotation issued in place of this one",
+                    "type": "string",
+                    "example": "6573b1f2c3d4e5f6a7b8c9d0"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "location:update",
+                        "heartbeat"
+                    ]
+                },
+                "token": {
+                    "description": "Token is only returned when the token is issued or rotated",
+                    "type": "string",
+                    "example": "dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a"
+                }
+            }
+        },
+        "internal_handler.DrainResponse": {
+            "type": "object",
+            "properties": {
+                "inFlight": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "drained",
+                        "timeout"
+                    ],
+                    "example": "drained"
+                },
+                "waitedMs": {
+                    "type": "integer",
+                    "example": 420
+                }
+            }
+        },
+        "internal_handler.Driver": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "available": {
+                    "type": "boolean"
+                },
+                "carBrand": {
+                    "type": "string"
+                },
+                "carModel": {
+                    "type": "string"
+                },
+                "city": {
+                    "description": "City is the city the location is in, or other outside every configured city",
+                    "type": "string",
+                    "example": "istanbul"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt is set on the tombstone a deleted driver leaves for delta syncs",
+                    "type": "string"
+                },
+                "documents": {
+                    "description": "Documents holds at most one document per type",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverDocument"
+                    }
+                },
+                "email": {
+                    "type": "string"
+                },
+                "emailVerified": {
+                    "type": "boolean"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "fleetId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identityVerified": {
+                    "description": "IdentityVerified is set once the KYC provider approved the driver's identity",
+                    "type": "boolean"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "lastSeenAt": {
+                    "type": "string"
+                },
+                "license": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                },
+                "licenseExpired": {
+                    "description": "LicenseExpired drivers have been taken off dispatch until the licence is renewed",
+                    "type": "boolean"
+                },
+                "location": {
+                    "type": "object",
+                    "properties": {
+                        "lat": {
+                            "type": "number"
+                        },
+                        "lon": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "locationUpdatedAt": {
+                    "description": "LocationUpdatedAt is when the location was last reported",
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "phoneVerified": {
+                    "type": "boolean"
+                },
+                "photo": {
+                    "description": "Photo is the driver's profile picture, if one was uploaded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverPhoto"
+                        }
+                    ]
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "ratingCount": {
+                    "type": "integer"
+                },
+                "suspended": {
+                    "type": "boolean"
+                },
+                "suspensionReason": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags and Attributes describe what the driver offers, for dispatch filters",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly",
+                        "wheelchair-accessible"
+                    ]
+                },
+                "taxiType": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.DriverChangesResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Driver"
+                    }
+                },
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hasMore": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "nextToken": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Driver"
+                    }
+                }
+            }
+        },
+        "internal_handler.DriverQuota": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "maxDrivers": {
+                    "description": "MaxDrivers is the quota; 0 is unlimited",
+                    "type": "integer",
+                    "example": 50
+                },
+                "remaining": {
+                    "description": "Remaining is left out for unlimited tenants",
+                    "type": "integer",
+                    "example": 8
+                },
+                "tenantId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "thresholds": {
+                    "description": "Thresholds are the usage percentages a TenantQuotaThresholdReached event is emitted at",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        80,
+                        100
+                    ]
+                },
+                "usedPercent": {
+                    "type": "number",
+                    "example": 84
+                }
+            }
+        },
+        "internal_handler.DriverStats": {
+            "type": "object",
+            "properties": {
+                "averageRating": {
+                    "type": "number",
+                    "example": 4.8
+                },
+                "completedTrips": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "distanceDrivenKm": {
+                    "type": "number",
+                    "example": 528.3
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-01T00:00:00Z"
+                },
+                "locationAnomalies": {
+                    "description": "LocationAnomalies counts location updates implying an implausible speed",
+                    "type": "integer",
+                    "example": 0
+                },
+                "onlineHours": {
+                    "type": "number",
+                    "example": 96.5
+                },
+                "ratingCount": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "tripDistanceKm": {
+                    "type": "number",
+                    "example": 311.6
+                }
+            }
+        },
+        "internal_handler.Earning": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "type": "number",
+                    "example": 0
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "earnedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "gross": {
+                    "type": "number",
+                    "example": 0
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
+                },
+                "net": {
+                    "type": "number",
+                    "example": 50
+                },
+                "payoutId": {
+                    "type": "string",
+                    "example": "6573b2f3c4d5e6f7a8b9c0d1"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Toll reimbursement"
+                },
+                "tripId": {
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "trip",
+                        "adjustment"
+                    ],
+                    "example": "adjustment"
+                }
+            }
+        },
+        "internal_handler.EarningsSummary": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.EarningsBucket"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-06T00:00:00+03:00"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly"
+                    ],
+                    "example": "daily"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Istanbul"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-06T00:00:00+03:00"
+                },
+                "totals": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.EarningsTotals"
+                }
+            }
+        },
+        "internal_handler.Erasure": {
+            "type": "object",
+            "properties": {
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "erasedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "files": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6574a1f2c3d4e5f6a7b8c9d0"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "KVKK request #4711"
+                },
+                "records": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "requestedBy": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "trigger": {
+                    "type": "string",
+                    "enum": [
+                        "retention",
+                        "request"
+                    ],
+                    "example": "request"
+                }
+            }
+        },
+        "internal_handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "object",
+                    "properties": {
+                        "code": {
+                            "type": "string"
+                        },
+                        "message": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "upstream": {
+                    "description": "Upstream is set on errors returned or caused by the driver service",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handler.UpstreamInfo"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.EstimateFareRequest": {
+            "type": "object",
+            "required": [
+                "dropoff",
+                "pickup"
+            ],
+            "properties": {
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "taxiType": {
+                    "type": "string",
+                    "example": "sari"
+                }
+            }
+        },
+        "internal_handler.ExpiringLicensesResponse": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "days": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "licenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.ExpiringLicense"
+                    }
+                }
+            }
+        },
+        "internal_handler.ExportRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "description": "FleetID only exports drivers of this fleet; fleet admins always export their own",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "format": {
+                    "description": "Format is ndjson (one driver per line, the default) or json (an array)",
+                    "type": "string",
+                    "enum": [
+                        "ndjson",
+                        "json"
+                    ],
+                    "example": "ndjson"
+                }
+            }
+        },
+        "internal_handler.FailoverStats": {
+            "type": "object",
+            "properties": {
+                "lastPrimaryChangeAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "lastTransientAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:03Z"
+                },
+                "primary": {
+                    "type": "string",
+                    "example": "mongo-1:27017"
+                },
+                "primaryChanges": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "recovered": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "retries": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "transientErrors": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "unavailable": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handler.FareEstimate": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 232.4
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "distanceKm": {
+                    "type": "number",
+                    "example": 7.4
+                },
+                "durationSec": {
+                    "type": "integer",
+                    "example": 1080
+                },
+                "provider": {
+                    "description": "Provider is the routing provider that measured the route: haversine or osrm",
+                    "type": "string",
+                    "example": "osrm"
+                }
+            }
+        },
+        "internal_handler.FavoriteLocationRequest": {
+            "type": "object",
+            "required": [
+                "label"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "Caferağa Mah. Moda Cad. No:1, Kadıköy"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Home"
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                }
+            }
+        },
+        "internal_handler.Fleet": {
+            "type": "object",
+            "properties": {
+                "companyName": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.Incident": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "safety",
+                        "conduct",
+                        "vehicle",
+                        "fare",
+                        "route",
+                        "other"
+                    ],
+                    "example": "conduct"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Driver was on the phone during the whole ride"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "driverSuspended": {
+                    "description": "DriverSuspended is set when reporting the incident suspended the driver",
+                    "type": "boolean",
+                    "example": false
+                },
+                "fleetId": {
+                    "description": "FleetID is the driver's fleet when the incident was reported",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6575a1f2c3d4e5f6a7b8c9d0"
+                },
+                "identityRevoked": {
+                    "description": "IdentityRevoked is set when reporting the incident revoked the driver's identity verification",
+                    "type": "boolean",
+                    "example": false
+                },
+                "note": {
+                    "description": "Note is the latest remark of the investigation",
+                    "type": "string",
+                    "example": "Rider confirmed the driver used a hands-free set"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "upheld",
+                        "dismissed"
+                    ],
+                    "example": "dismissed"
+                },
+                "reportedBy": {
+                    "description": "ReportedBy and ReporterRole identify the caller who reported it; empty for operators",
+                    "type": "string",
+                    "example": "rider-42"
+                },
+                "reporterRole": {
+                    "type": "string",
+                    "example": "rider"
+                },
+                "resolvedAt": {
+                    "type": "string",
+                    "example": "2025-12-07T09:30:00Z"
+                },
+                "severity": {
+                    "type": "string",
+                    "enum": [
+                        "low",
+                        "medium",
+                        "high",
+                        "critical"
+                    ],
+                    "example": "medium"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "investigating",
+                        "resolved"
+                    ],
+                    "example": "open"
+                },
+                "tripId": {
+                    "description": "TripID is the trip the incident happened on, if any",
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "internal_handler.IncidentStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Rider confirmed the driver used a hands-free set"
+                },
+                "outcome": {
+                    "description": "Outcome is required when resolving: upheld or dismissed",
+                    "type": "string",
+                    "example": "dismissed"
+                },
+                "severity": {
+                    "description": "Severity confirms or corrects the reported severity when moving to\ninvestigating; a confirmed high or critical severity takes effect then",
+                    "type": "string",
+                    "example": "high"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "resolved"
+                }
+            }
+        },
+        "internal_handler.IndexReport": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "inSync": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "indexes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.IndexStatus"
+                    }
+                },
+                "mismatched": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "missing": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "unexpected": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "internal_handler.IndexSyncJob": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "ops@bitaksi.com"
+                },
+                "completed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "current": {
+                    "type": "string",
+                    "example": "drivers.fleetId_createdAt"
+                },
+                "failed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.IndexSyncError"
+                    }
+                },
+                "finishedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:05Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "idxsync-1733446800000"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "completed",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "internal_handler.IntrospectRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.IntrospectResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exp": {
+                    "type": "integer",
+                    "example": 1735689600
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "iat": {
+                    "type": "integer",
+                    "example": 1735603200
+                },
+                "iss": {
+                    "type": "string",
+                    "example": "https://gateway.bitaksi.com"
+                },
+                "jti": {
+                    "type": "string",
+                    "example": "9f1c2e7a4b3d8e6f0a1b2c3d4e5f6a7b"
+                },
+                "kid": {
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "nbf": {
+                    "type": "integer",
+                    "example": 1735603200
+                },
+                "riderId": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "fleet_admin"
+                },
+                "sub": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "internal_handler.IssueDeviceTokenRequest": {
+            "type": "object",
+            "required": [
+                "deviceId"
+            ],
+            "properties": {
+                "deviceId": {
+                    "type": "string",
+                    "example": "a1b2c3d4-e5f6-7890-abcd-ef1234567890"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Mehmet's Android"
+                }
+            }
+        },
+        "internal_handler.Job": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "changed": {
+                    "description": "Changed counts exported drivers updated after the export started",
+                    "type": "integer",
+                    "example": 12
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "failed to list drivers"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is when a finished job and its file are deleted",
+                    "type": "string",
+                    "example": "2025-12-07T01:04:10Z"
+                },
+                "finishedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:04:10Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "job-9f86d081884c7d65"
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "export"
+                },
+                "owner": {
+                    "description": "Owner is the fleet whose drivers the job covers; empty for all fleets",
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 420000
+                },
+                "result": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.JobResult"
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:01Z"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "completed",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "description": "Total is 0 until the job knows how many drivers it processes",
+                    "type": "integer",
+                    "example": 1000000
+                }
+            }
+        },
+        "internal_handler.KYCCheck": {
+            "type": "object",
+            "properties": {
+                "decidedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:05:00Z"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d1"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "sandbox"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "document is not legible"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "sbx_9f3c2a1b7e6d5c4a"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "example": "pending"
+                },
+                "submittedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "internal_handler.ListDriversResponse": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Driver"
+                    }
+                },
+                "hasNext": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "hasPrev": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "totalCount": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handler.ListIncidentsResponse": {
+            "type": "object",
+            "properties": {
+                "hasNext": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "hasPrev": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Incident"
+                    }
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "totalCount": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "totalPages": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handler.LocationAnomalyReport": {
+            "type": "object",
+            "properties": {
+                "drivers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.LocationAnomalyCount"
+                    }
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-11-06T00:00:00Z"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-06T00:00:00Z"
+                }
+            }
+        },
+        "internal_handler.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.NearbyDriverWithFare": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "Distance is DistanceKm in the units asked for; left out when none were",
+                    "type": "number",
+                    "example": 500
+                },
+                "distanceKm": {
+                    "type": "number"
+                },
+                "durationSec": {
+                    "description": "DurationSec is the estimated travel time to the search location",
+                    "type": "integer",
+                    "example": 240
+                },
+                "fare": {
+                    "description": "Fare is left out for drivers the pricing service could not price in time",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.FareQuote"
+                        }
+                    ]
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastLocationUpdate": {
+                    "description": "LastLocationUpdate is when the driver's position was last reported",
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "staleSeconds": {
+                    "description": "StaleSeconds is the age of the position at search time; gray out drivers whose position is old",
+                    "type": "integer",
+                    "example": 42
+                },
+                "taxiType": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.OnboardingRequest": {
+            "type": "object",
+            "required": [
+                "driver"
+            ],
+            "properties": {
+                "available": {
+                    "description": "Available puts the driver on shift once onboarded; it stays pending until the phone is verified",
+                    "type": "boolean",
+                    "example": false
+                },
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.UploadDocumentRequest"
+                    }
+                },
+                "driver": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.CreateDriverRequest"
+                }
+            }
+        },
+        "internal_handler.OnlineStats": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "heartbeatTimeoutSec": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "offline": {
+                    "type": "integer",
+                    "example": 70
+                },
+                "online": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 250
+                }
+            }
+        },
+        "internal_handler.PasswordResetConfirmRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct-horse-battery"
+                },
+                "token": {
+                    "description": "Token is the token of the emailed reset link",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "dispatch@example.com"
+                }
+            }
+        },
+        "internal_handler.PasswordResetResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "if the email belongs to an account, a reset link is on its way"
+                }
+            }
+        },
+        "internal_handler.Payout": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "ops-admin"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "TRY"
+                },
+                "drivers": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573b2f3c4d5e6f7a8b9c0d1"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.PayoutLine"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00Z"
+                },
+                "total": {
+                    "type": "number",
+                    "example": 2420.48
+                }
+            }
+        },
+        "internal_handler.QueryStats": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.QueryBucket"
+                    }
+                },
+                "collection": {
+                    "type": "string",
+                    "example": "drivers"
+                },
+                "command": {
+                    "type": "string",
+                    "example": "find"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 48210
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "maxMs": {
+                    "type": "number",
+                    "example": 812.4
+                },
+                "slow": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "totalMs": {
+                    "type": "number",
+                    "example": 96420.5
+                }
+            }
+        },
+        "internal_handler.RegisterRiderRequest": {
+            "type": "object",
+            "required": [
+                "firstName",
+                "lastName",
+                "phone"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "elif@example.com"
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Yılmaz"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                }
+            }
+        },
+        "internal_handler.ReportIncidentRequest": {
+            "type": "object",
+            "required": [
+                "category",
+                "description",
+                "severity"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "example": "conduct"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Driver was on the phone during the whole ride"
+                },
+                "severity": {
+                    "type": "string",
+                    "example": "medium"
+                },
+                "tripId": {
+                    "description": "TripID links the trip the incident happened on; riders must give one of their trips",
+                    "type": "string",
+                    "example": "6571f1f77bcf86cd79943901"
+                }
+            }
+        },
+        "internal_handler.Rider": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "elif@example.com"
+                },
+                "favoriteLocations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.FavoriteLocation"
+                    }
+                },
+                "firstName": {
+                    "type": "string",
+                    "example": "Elif"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6573a1f2c3d4e5f6a7b8c9d0"
+                },
+                "lastName": {
+                    "type": "string",
+                    "example": "Yılmaz"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "internal_handler.RiderRegistrationResponse": {
+            "type": "object",
+            "properties": {
+                "rider": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Rider"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.RiderSignInCodeRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                }
+            }
+        },
+        "internal_handler.RiderSignInRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "phone"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905551234567"
+                }
+            }
+        },
+        "internal_handler.RiderSignInResponse": {
+            "type": "object",
+            "properties": {
+                "rider": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Rider"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.RouteAuth": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "type": "string",
+                    "enum": [
+                        "public",
+                        "apikey",
+                        "jwt",
+                        "admin"
+                    ],
+                    "example": "jwt"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "PUT"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/drivers/:id"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "fleet_admin"
+                    ]
+                }
+            }
+        },
+        "internal_handler.RouteDriverResponse": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "description": "Distance and RouteDistance are DistanceKm and RouteKm in the units asked\nfor; left out when none were",
+                    "type": "number",
+                    "example": 200
+                },
+                "distanceKm": {
+                    "description": "DistanceKm is the straight-line distance from the driver to the route",
+                    "type": "number",
+                    "example": 0.2
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "location": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "plate": {
+                    "type": "string"
+                },
+                "routeDistance": {
+                    "type": "number",
+                    "example": 3400
+                },
+                "routeKm": {
+                    "description": "RouteKm is how far along the route, from its first waypoint, the driver is closest to it",
+                    "type": "number",
+                    "example": 3.4
+                },
+                "taxiType": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.RouteSearchRequest": {
+            "type": "object",
+            "properties": {
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "live": {
+                    "description": "Live only returns drivers with a recent heartbeat; omitted leaves it to the driver service setting",
+                    "type": "boolean",
+                    "example": true
+                },
+                "taksiType": {
+                    "type": "string",
+                    "example": "sari"
+                },
+                "waypoints": {
+                    "description": "Waypoints are the ordered points of the route, 2 to 25 of them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                    }
+                },
+                "widthKm": {
+                    "description": "WidthKm is how far from the route drivers may be; defaults to 0.5, at most 2",
+                    "type": "number",
+                    "example": 0.5
+                }
+            }
+        },
+        "internal_handler.ScheduleShiftRequest": {
+            "type": "object",
+            "required": [
+                "endsAt",
+                "startsAt"
+            ],
+            "properties": {
+                "endsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T14:00:00Z"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Airport rank"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                }
+            }
+        },
+        "internal_handler.ScheduledShift": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "endedAt": {
+                    "description": "EndedAt is when the driver was taken off shift",
+                    "type": "string",
+                    "example": "2025-12-08T14:00:09Z"
+                },
+                "endsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T14:00:00Z"
+                },
+                "fleetId": {
+                    "type": "string",
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6574a1f2c3d4e5f6a7b8c9d0"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Airport rank"
+                },
+                "startError": {
+                    "description": "StartError is why the driver could not be put on shift",
+                    "type": "string",
+                    "example": "phone must be verified before going on shift"
+                },
+                "startedAt": {
+                    "description": "StartedAt is when the driver was put on shift",
+                    "type": "string",
+                    "example": "2025-12-08T06:00:12Z"
+                },
+                "startsAt": {
+                    "type": "string",
+                    "example": "2025-12-08T06:00:00Z"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                }
+            }
+        },
+        "internal_handler.SecurityEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.SecurityEvent"
+                    }
+                }
+            }
+        },
+        "internal_handler.SetAvailabilityRequest": {
+            "type": "object",
+            "required": [
+                "available"
+            ],
+            "properties": {
+                "available": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handler.SetLogLevelRequest": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "example": "debug"
+                },
+                "logger": {
+                    "description": "Logger is the logger name, such as \"service\" or \"handler\"; empty changes the root level",
+                    "type": "string",
+                    "example": "service"
+                }
+            }
+        },
+        "internal_handler.SetMaintenanceRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "description": "Message is shown to clients; empty uses the default message",
+                    "type": "string",
+                    "example": "Scheduled maintenance until 03:00 UTC"
+                },
+                "retryAfterSec": {
+                    "description": "RetryAfterSec is sent as Retry-After; 0 keeps the current value",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 600
+                }
+            }
+        },
+        "internal_handler.SetSuspensionRequest": {
+            "type": "object",
+            "required": [
+                "suspended"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "expired license"
+                },
+                "suspended": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handler.ShiftCalendar": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-08T00:00:00Z"
+                },
+                "shifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.ScheduledShift"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-15T00:00:00Z"
+                }
+            }
+        },
+        "internal_handler.SystemStatus": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "$ref": "#/definitions/internal_handler.BuildInfo"
+                },
+                "caches": {
+                    "$ref": "#/definitions/internal_handler.CacheStats"
+                },
+                "canary": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_canary.Stats"
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "driverService": {
+                    "$ref": "#/definitions/internal_handler.UpstreamHealth"
+                },
+                "hedging": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_hedge.Stats"
+                },
+                "rateLimit": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.RateLimitStats"
+                },
+                "saturation": {
+                    "description": "Saturation is the gateway's own requests in flight against MAX_IN_FLIGHT_REQUESTS",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_middleware.SaturationStats"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status is \"ok\", or \"degraded\" while the driver service fails its health check",
+                    "type": "string",
+                    "example": "ok"
+                },
+                "upstreamLimit": {
+                    "description": "UpstreamLimit is the adaptive limit that sheds load from a slow or failing driver service",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_adaptive.Stats"
+                        }
+                    ]
+                }
+            }
+        },
+        "internal_handler.TapListResponse": {
+            "type": "object",
+            "properties": {
+                "captures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_tap.Capture"
+                    }
+                },
+                "taps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_tap.Tap"
+                    }
+                }
+            }
+        },
+        "internal_handler.TapResponse": {
+            "type": "object",
+            "properties": {
+                "captures": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_tap.Capture"
+                    }
+                },
+                "tap": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_tap.Tap"
+                }
+            }
+        },
+        "internal_handler.TaxiTypeCatalog": {
+            "type": "object",
+            "properties": {
+                "taxiTypes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.TaxiType"
+                    }
+                }
+            }
+        },
+        "internal_handler.TelemetryStats": {
+            "type": "object",
+            "properties": {
+                "broker": {
+                    "type": "string",
+                    "example": "tcp://mqtt:1883"
+                },
+                "connected": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "lastDisconnectAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "lastMessageAt": {
+                    "type": "string",
+                    "example": "2025-12-06T09:30:00Z"
+                },
+                "messages": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.TelemetryCounts"
+                    }
+                },
+                "qos": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reconnects": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rejections": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "drivers/+/location",
+                        "drivers/+/heartbeat"
+                    ]
+                }
+            }
+        },
+        "internal_handler.TokenExchangeRequest": {
+            "type": "object",
+            "required": [
+                "grant_type",
+                "subject_token",
+                "subject_token_type"
+            ],
+            "properties": {
+                "audience": {
+                    "description": "Audience is the service the token is for; driver-service when empty",
+                    "type": "string",
+                    "example": "driver-service"
+                },
+                "grant_type": {
+                    "type": "string",
+                    "example": "urn:ietf:params:oauth:grant-type:token-exchange"
+                },
+                "requested_token_type": {
+                    "type": "string",
+                    "example": "urn:ietf:params:oauth:token-type:access_token"
+                },
+                "scope": {
+                    "description": "Scope is a space separated list; drivers:read when empty",
+                    "type": "string",
+                    "example": "drivers:read drivers:write"
+                },
+                "subject_token": {
+                    "description": "SubjectToken is the access token of the user the service acts for",
+                    "type": "string"
+                },
+                "subject_token_type": {
+                    "type": "string",
+                    "example": "urn:ietf:params:oauth:token-type:access_token"
+                }
+            }
+        },
+        "internal_handler.TokenExchangeResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "issued_token_type": {
+                    "type": "string",
+                    "example": "urn:ietf:params:oauth:token-type:access_token"
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "drivers:read"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "internal_handler.Trip": {
+            "type": "object",
+            "properties": {
+                "acceptedAt": {
+                    "type": "string"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "declinedDriverIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "distanceKm": {
+                    "type": "number"
+                },
+                "driverId": {
+                    "type": "string"
+                },
+                "dropoff": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.FareEstimate"
+                },
+                "fare": {
+                    "type": "number",
+                    "example": 232.4
+                },
+                "id": {
+                    "type": "string"
+                },
+                "offerCount": {
+                    "type": "integer"
+                },
+                "offerExpiresAt": {
+                    "type": "string"
+                },
+                "offeredDriverId": {
+                    "type": "string"
+                },
+                "pickup": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.Location"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 5
+                },
+                "riderId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "requested",
+                        "offered",
+                        "accepted",
+                        "completed",
+                        "cancelled",
+                        "no_driver_found"
+                    ]
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "taxiType": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.TripOfferRequest": {
+            "type": "object",
+            "required": [
+                "driverId"
             ],
             "properties": {
+                "driverId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "internal_handler.UpdateDriverRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attributes replaces the driver's attributes; an empty object removes them",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "carBrand": {
                     "type": "string",
-                    "example": "Toyota"
+                    "example": "Mercedes"
                 },
                 "carModel": {
                     "type": "string",
-                    "example": "Corolla"
+                    "example": "G Class"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ali.kurt@example.com"
                 },
                 "firstName": {
                     "type": "string",
-                    "example": "Ahmet"
+                    "example": "Ali"
                 },
                 "lastName": {
                     "type": "string",
-                    "example": "Demir"
+                    "example": "Kurt"
                 },
                 "lat": {
                     "type": "number",
-                    "example": 41.0431
+                    "example": 42.0082
+                },
+                "license": {
+                    "description": "License replaces the driver's licence; a valid one clears the expired flag",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.DriverLicense"
+                        }
+                    ]
                 },
                 "lon": {
                     "type": "number",
-                    "example": 29.0099
+                    "example": 28.9784
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+905329876543"
                 },
                 "plate": {
                     "type": "string",
-                    "example": "34ABC123"
+                    "example": "34G1234"
+                },
+                "tags": {
+                    "description": "Tags replaces the driver's tags; an empty list removes them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pet-friendly"
+                    ]
                 },
                 "taksiType": {
                     "type": "string",
-                    "enum": [
-                        "sari",
-                        "turkuaz",
-                        "siyah"
-                    ],
-                    "example": "sari"
+                    "example": "siyah"
                 }
             }
         },
-        "internal_handler.Driver": {
+        "internal_handler.UpdateLocationRequest": {
             "type": "object",
+            "required": [
+                "lat",
+                "lon"
+            ],
             "properties": {
-                "carBrand": {
-                    "type": "string"
-                },
-                "carModel": {
-                    "type": "string"
+                "lat": {
+                    "type": "number",
+                    "example": 41.0431
                 },
-                "createdAt": {
-                    "type": "string"
+                "lon": {
+                    "type": "number",
+                    "example": 29.0099
+                }
+            }
+        },
+        "internal_handler.UpdateRiderRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "elif.kaya@example.com"
                 },
                 "firstName": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Elif"
                 },
                 "lastName": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Kaya"
                 },
-                "location": {
-                    "type": "object",
-                    "properties": {
-                        "lat": {
-                            "type": "number"
-                        },
-                        "lon": {
-                            "type": "number"
+                "phone": {
+                    "type": "string",
+                    "example": "+905557654321"
+                }
+            }
+        },
+        "internal_handler.UpstreamHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error tells why the health check failed",
+                    "type": "string",
+                    "example": "driver service health check returned 503"
+                },
+                "healthCheckMs": {
+                    "description": "HealthCheckMs is how long the health check made for this report took",
+                    "type": "number",
+                    "example": 3.2
+                },
+                "latency": {
+                    "description": "Latency covers the recent requests the gateway forwarded",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_bitaksi_gateway_internal_service.LatencyStats"
                         }
-                    }
+                    ]
                 },
-                "plate": {
-                    "type": "string"
+                "status": {
+                    "description": "Status is \"up\" or \"down\"",
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "internal_handler.UpstreamInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code and Message are the driver service's own, set when its error was translated",
+                    "type": "string",
+                    "example": "INTERNAL_ERROR"
                 },
-                "taxiType": {
-                    "type": "string"
+                "message": {
+                    "type": "string",
+                    "example": "failed to create driver"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "service": {
+                    "type": "string",
+                    "example": "driver-service"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 500
                 }
             }
         },
-        "internal_handler.ErrorResponse": {
+        "internal_handler.UsageReport": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "properties": {
-                        "code": {
-                            "type": "string"
-                        },
-                        "message": {
-                            "type": "string"
-                        }
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-01"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_usage.Record"
                     }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-06"
+                },
+                "totalErrors": {
+                    "type": "integer",
+                    "example": 312
+                },
+                "totalRequests": {
+                    "type": "integer",
+                    "example": 48210
                 }
             }
         },
-        "internal_handler.ListDriversResponse": {
+        "internal_handler.UtilizationReport": {
             "type": "object",
             "properties": {
-                "drivers": {
+                "days": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/internal_handler.Driver"
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.UtilizationDay"
                     }
                 },
-                "page": {
-                    "type": "integer"
+                "from": {
+                    "type": "string",
+                    "example": "2025-12-01"
                 },
-                "pageSize": {
-                    "type": "integer"
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Istanbul"
                 },
-                "totalCount": {
-                    "type": "integer"
+                "to": {
+                    "type": "string",
+                    "example": "2025-12-07"
                 }
             }
         },
-        "internal_handler.LoginRequest": {
+        "internal_handler.ValidationRulesResponse": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "countries": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.ValidationRuleset"
+                    }
                 },
-                "username": {
-                    "type": "string"
+                "country": {
+                    "description": "Country is the market the driver service runs in; Default holds its rules",
+                    "type": "string",
+                    "example": "TR"
+                },
+                "default": {
+                    "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.ValidationRuleset"
+                },
+                "tenants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_bitaksi_gateway_internal_apimodel.ValidationRuleset"
+                    }
                 }
             }
         },
-        "internal_handler.LoginResponse": {
+        "internal_handler.VerifyPhoneRequest": {
             "type": "object",
+            "required": [
+                "code"
+            ],
             "properties": {
-                "token": {
-                    "type": "string"
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
-        "internal_handler.NearbyDriverResponse": {
+        "internal_handler.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "distanceKm": {
-                    "type": "number"
+                "attempts": {
+                    "type": "integer",
+                    "example": 2
                 },
-                "firstName": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:00Z"
+                },
+                "deliveredAt": {
                     "type": "string"
                 },
+                "event": {
+                    "type": "string",
+                    "example": "driver.suspended"
+                },
+                "eventId": {
+                    "type": "string",
+                    "example": "evt_5f0c2a9e8b7d4c3a"
+                },
                 "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "6572b1f2c3d4e5f6a7b8c9d0"
                 },
-                "lastName": {
-                    "type": "string"
+                "lastError": {
+                    "type": "string",
+                    "example": "endpoint returned status 503"
                 },
-                "plate": {
-                    "type": "string"
+                "lastStatusCode": {
+                    "type": "integer",
+                    "example": 503
                 },
-                "taxiType": {
-                    "type": "string"
+                "nextAttemptAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:40Z"
+                },
+                "payload": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivering",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "pending"
+                },
+                "subscriptionId": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2025-12-06T01:00:10Z"
                 }
             }
         },
-        "internal_handler.UpdateDriverRequest": {
+        "internal_handler.WebhookSubscription": {
             "type": "object",
             "properties": {
-                "carBrand": {
+                "createdAt": {
                     "type": "string",
-                    "example": "Mercedes"
+                    "example": "2025-12-06T01:00:00Z"
                 },
-                "carModel": {
+                "description": {
                     "type": "string",
-                    "example": "G Class"
+                    "example": "Kadıköy Taksi dispatch system"
                 },
-                "firstName": {
-                    "type": "string",
-                    "example": "Ali"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "driver.created",
+                        "driver.suspended"
+                    ]
                 },
-                "lastName": {
+                "fleetId": {
                     "type": "string",
-                    "example": "Kurt"
-                },
-                "lat": {
-                    "type": "number",
-                    "example": 42.0082
+                    "example": "6570a1f2c3d4e5f6a7b8c9d0"
                 },
-                "lon": {
-                    "type": "number",
-                    "example": 28.9784
+                "id": {
+                    "type": "string",
+                    "example": "6572a1f2c3d4e5f6a7b8c9d0"
                 },
-                "plate": {
+                "secret": {
+                    "description": "Secret is only returned when the subscription is created",
                     "type": "string",
-                    "example": "34G99"
+                    "example": "whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"
                 },
-                "taksiType": {
+                "url": {
                     "type": "string",
-                    "enum": [
-                        "sari",
-                        "turkuaz",
-                        "siyah"
-                    ],
-                    "example": "siyah"
+                    "example": "https://partner.example.com/hooks/bitaksi"
                 }
             }
         }
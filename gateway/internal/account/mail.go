@@ -0,0 +1,87 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"go.uber.org/zap"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers emails
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewSender creates the sender selected by cfg.Sender: "log" or "smtp"
+func NewSender(cfg config.MailConfig, logger *zap.Logger) (Sender, error) {
+	switch cfg.Sender {
+	case "", "log":
+		return &LogSender{logger: logger}, nil
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			return nil, errors.New("SMTP_HOST is required with MAIL_SENDER=smtp")
+		}
+		return &SMTPSender{cfg: cfg}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail sender %q: must be log or smtp", cfg.Sender)
+	}
+}
+
+// LogSender logs emails instead of sending them, for development. Bodies may
+// hold reset links, so they are only logged at debug level.
+type LogSender struct {
+	logger *zap.Logger
+}
+
+// Send logs the email
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Info("email not sent, MAIL_SENDER is log", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+	s.logger.Debug("email body", zap.String("to", msg.To), zap.String("body", msg.Body))
+	return nil
+}
+
+// SMTPSender sends emails through an SMTP server, with STARTTLS when the
+// server offers it and PLAIN authentication when a username is configured
+type SMTPSender struct {
+	cfg config.MailConfig
+}
+
+// Send sends the email
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return errors.New("email header contains a line break")
+	}
+
+	var auth smtp.Auth
+	if s.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+	}
+	body := "From: " + s.cfg.From + "\r\n" +
+		"To: " + msg.To + "\r\n" +
+		"Subject: " + msg.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(msg.Body, "\n", "\r\n")
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	if err := smtp.SendMail(addr, auth, s.cfg.From, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package account
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidToken is returned for a reset token that was altered, has
+	// expired or was already used
+	ErrInvalidToken = errors.New("invalid or expired reset token")
+	// ErrRateLimited is returned when an address or client IP asked too often
+	ErrRateLimited = errors.New("too many password reset attempts")
+)
+
+// sendTimeout bounds the delivery of one reset email
+const sendTimeout = 30 * time.Second
+
+// resetClaims is the signed payload of a reset token. Password is a
+// fingerprint of the password hash the token was issued for, so the token
+// stops working once the password changed.
+type resetClaims struct {
+	Username  string `json:"u"`
+	ExpiresAt int64  `json:"exp"`
+	Password  string `json:"pw"`
+}
+
+// Reset runs password resets: it emails signed, time-limited links to users
+// who forgot their password and sets the new password they choose. Every
+// step is written to the audit log.
+type Reset struct {
+	cfg    config.PasswordResetConfig
+	users  *Store
+	sender Sender
+	secret []byte
+	limits *limiter
+	audit  *zap.Logger
+	now    func() time.Time
+	wg     sync.WaitGroup
+}
+
+// NewReset creates a password reset flow for the users. An empty secret is
+// replaced by a generated one, so links only work on the instance that sent
+// them until it restarts.
+func NewReset(cfg config.PasswordResetConfig, users *Store, sender Sender, logger *zap.Logger) (*Reset, error) {
+	if cfg.TokenTTL <= 0 {
+		cfg.TokenTTL = 30 * time.Minute
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Hour
+	}
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate password reset secret: %w", err)
+		}
+	}
+	return &Reset{
+		cfg:    cfg,
+		users:  users,
+		sender: sender,
+		secret: secret,
+		limits: newLimiter(cfg.Window),
+		audit:  logger,
+		now:    time.Now,
+	}, nil
+}
+
+// Request emails a reset link to the user with the address. Unknown addresses
+// are answered the same way and the email is sent in the background, so
+// callers cannot tell which addresses have an account.
+func (r *Reset) Request(email, ip string) error {
+	email = normalizeEmail(email)
+	if !r.limits.allow("ip:"+ip, r.cfg.MaxPerIP) || !r.limits.allow("email:"+email, r.cfg.MaxPerEmail) {
+		r.audit.Warn("audit: password reset rate limited", zap.String("step", "request"), zap.String("ip", ip))
+		return ErrRateLimited
+	}
+
+	user, ok := r.users.FindByEmail(email)
+	if !ok {
+		r.audit.Info("audit: password reset requested for an unknown email", zap.String("ip", ip))
+		return nil
+	}
+	token, expiresAt := r.issue(user)
+	r.audit.Info("audit: password reset requested",
+		zap.String("username", user.Username),
+		zap.String("ip", ip),
+		zap.Time("expiresAt", expiresAt),
+	)
+
+	msg := Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    r.body(token, expiresAt),
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := r.sender.Send(ctx, msg); err != nil {
+			r.audit.Error("audit: password reset email failed", zap.String("username", user.Username), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Confirm sets the password of the user the token was issued to, and returns
+// the username
+func (r *Reset) Confirm(token, password, ip string) (string, error) {
+	if !r.limits.allow("ip:"+ip, r.cfg.MaxPerIP) {
+		r.audit.Warn("audit: password reset rate limited", zap.String("step", "confirm"), zap.String("ip", ip))
+		return "", ErrRateLimited
+	}
+
+	user, err := r.open(token)
+	if err != nil {
+		r.audit.Warn("audit: password reset rejected", zap.String("ip", ip), zap.Error(err))
+		return "", err
+	}
+	if err := r.users.SetPassword(user.Username, password); err != nil {
+		if !errors.Is(err, ErrWeakPassword) {
+			r.audit.Error("audit: password reset failed", zap.String("username", user.Username), zap.Error(err))
+		}
+		return "", err
+	}
+	r.audit.Info("audit: password reset completed", zap.String("username", user.Username), zap.String("ip", ip))
+	return user.Username, nil
+}
+
+// Wait blocks until the emails being sent have been handed to the sender
+func (r *Reset) Wait() {
+	r.wg.Wait()
+}
+
+// issue signs a token for the user's current password
+func (r *Reset) issue(user User) (string, time.Time) {
+	expiresAt := r.now().Add(r.cfg.TokenTTL)
+	data, _ := json.Marshal(resetClaims{
+		Username:  user.Username,
+		ExpiresAt: expiresAt.Unix(),
+		Password:  fingerprint(user.PasswordHash),
+	})
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + r.sign(payload), expiresAt
+}
+
+// open returns the user a token was issued to, rejecting altered and expired
+// tokens and tokens issued for a password that has since changed
+func (r *Reset) open(token string) (User, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(r.sign(payload))) {
+		return User{}, ErrInvalidToken
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return User{}, ErrInvalidToken
+	}
+	var claims resetClaims
+	if err := json.Unmarshal(data, &claims); err != nil || r.now().Unix() > claims.ExpiresAt {
+		return User{}, ErrInvalidToken
+	}
+	user, ok := r.users.Get(claims.Username)
+	if !ok || !hmac.Equal([]byte(claims.Password), []byte(fingerprint(user.PasswordHash))) {
+		return User{}, ErrInvalidToken
+	}
+	return user, nil
+}
+
+func (r *Reset) sign(payload string) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// body is the text of the reset email: a link to LinkURL with the token, or
+// the token itself when no page is configured
+func (r *Reset) body(token string, expiresAt time.Time) string {
+	action := "Use this code to choose a new password:\n\n" + token
+	if r.cfg.LinkURL != "" {
+		separator := "?"
+		if strings.Contains(r.cfg.LinkURL, "?") {
+			separator = "&"
+		}
+		action = "Open this link to choose a new password:\n\n" + r.cfg.LinkURL + separator + "token=" + url.QueryEscape(token)
+	}
+	return "Someone asked to reset the password of your account.\n\n" + action +
+		"\n\nIt works once, until " + expiresAt.UTC().Format("2006-01-02 15:04 MST") +
+		". If you did not ask for it, ignore this email; your password stays the same.\n"
+}
+
+// fingerprint identifies a password hash without revealing it
+func fingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// limiter counts attempts per key in fixed windows
+type limiter struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+func newLimiter(window time.Duration) *limiter {
+	return &limiter{window: window, now: time.Now, counts: make(map[string]int)}
+}
+
+// allow counts an attempt for key and reports whether it is within max for
+// the current window; a max below 1 allows everything
+func (l *limiter) allow(key string, max int) bool {
+	if max < 1 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now := l.now(); now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = make(map[string]int)
+	}
+	l.counts[key]++
+	return l.counts[key] <= max
+}
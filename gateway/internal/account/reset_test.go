@@ -0,0 +1,144 @@
+package account
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func writeUsers(t *testing.T, password string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "users.json")
+	data := `{"users": [{"username": "dispatcher", "email": "Dispatch@Example.com", "passwordHash": "` + string(hash) + `"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	return path
+}
+
+// tokenFrom returns the token of the reset link in an email
+func tokenFrom(t *testing.T, msg Message) string {
+	t.Helper()
+	i := strings.Index(msg.Body, "http")
+	require.GreaterOrEqual(t, i, 0, "no link in %q", msg.Body)
+	link, err := url.Parse(strings.Fields(msg.Body[i:])[0])
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func TestMain(m *testing.M) {
+	hashCost = bcrypt.MinCost
+	os.Exit(m.Run())
+}
+
+func TestStore(t *testing.T) {
+	path := writeUsers(t, "old-password")
+	users, err := Load(path)
+	require.NoError(t, err)
+
+	assert.True(t, users.Authenticate("dispatcher", "old-password"))
+	assert.False(t, users.Authenticate("dispatcher", "wrong"))
+	assert.False(t, users.Authenticate("nobody", "old-password"))
+	user, ok := users.FindByEmail(" dispatch@example.COM ")
+	assert.True(t, ok)
+	assert.Equal(t, "dispatcher", user.Username)
+
+	assert.ErrorIs(t, users.SetPassword("dispatcher", "short"), ErrWeakPassword)
+	assert.ErrorIs(t, users.SetPassword("nobody", "new-password"), ErrUnknownUser)
+	require.NoError(t, users.SetPassword("dispatcher", "new-password"))
+
+	// The change is written to the file
+	reloaded, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, reloaded.Authenticate("dispatcher", "new-password"))
+	assert.False(t, reloaded.Authenticate("dispatcher", "old-password"))
+}
+
+func TestReset(t *testing.T) {
+	users, err := Load(writeUsers(t, "old-password"))
+	require.NoError(t, err)
+	sender := &recordingSender{}
+	reset, err := NewReset(config.PasswordResetConfig{
+		Secret:      "test-secret",
+		TokenTTL:    30 * time.Minute,
+		LinkURL:     "https://app.bitaksi.com/reset-password",
+		MaxPerEmail: 3,
+		MaxPerIP:    20,
+	}, users, sender, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	reset.now = func() time.Time { return now }
+
+	// Unknown addresses get the same answer and no email
+	require.NoError(t, reset.Request("nobody@example.com", "203.0.113.7"))
+	require.NoError(t, reset.Request("dispatch@example.com", "203.0.113.7"))
+	reset.Wait()
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "Dispatch@Example.com", sender.sent[0].To)
+	token := tokenFrom(t, sender.sent[0])
+
+	_, err = reset.Confirm(token[:len(token)-2]+"xx", "new-password", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrInvalidToken, "an altered token")
+	_, err = reset.Confirm(token, "short", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrWeakPassword)
+
+	username, err := reset.Confirm(token, "new-password", "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, "dispatcher", username)
+	assert.True(t, users.Authenticate("dispatcher", "new-password"))
+	_, err = reset.Confirm(token, "another-password", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrInvalidToken, "a token works once")
+
+	// Tokens expire
+	require.NoError(t, reset.Request("dispatch@example.com", "203.0.113.7"))
+	reset.Wait()
+	expired := tokenFrom(t, sender.sent[1])
+	now = now.Add(31 * time.Minute)
+	_, err = reset.Confirm(expired, "another-password", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestReset_RateLimit(t *testing.T) {
+	users, err := Load(writeUsers(t, "old-password"))
+	require.NoError(t, err)
+	reset, err := NewReset(config.PasswordResetConfig{MaxPerEmail: 2, MaxPerIP: 3, Window: time.Hour}, users, &recordingSender{}, zap.NewNop())
+	require.NoError(t, err)
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	reset.limits.now = func() time.Time { return now }
+
+	assert.NoError(t, reset.Request("dispatch@example.com", "203.0.113.7"))
+	assert.NoError(t, reset.Request("DISPATCH@example.com", "203.0.113.8"))
+	assert.ErrorIs(t, reset.Request("dispatch@example.com", "203.0.113.9"), ErrRateLimited, "the address asked too often")
+	assert.NoError(t, reset.Request("other@example.com", "203.0.113.7"))
+	_, err = reset.Confirm("token", "new-password", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = reset.Confirm("token", "new-password", "203.0.113.7")
+	assert.ErrorIs(t, err, ErrRateLimited, "the IP tried too often")
+
+	now = now.Add(time.Hour)
+	assert.NoError(t, reset.Request("dispatch@example.com", "203.0.113.7"), "a new window starts over")
+	reset.Wait()
+}
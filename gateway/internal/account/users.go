@@ -0,0 +1,185 @@
+// Package account keeps the password accounts users log in with and lets them
+// reset a forgotten password with a signed, time-limited link sent by email.
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the shortest password that can be set
+const MinPasswordLength = 10
+
+// ErrWeakPassword is returned for a new password shorter than MinPasswordLength
+var ErrWeakPassword = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+
+// ErrUnknownUser is returned when setting the password of a user that does not exist
+var ErrUnknownUser = errors.New("unknown user")
+
+// User is a password account. PasswordHash is a bcrypt hash, e.g. from
+// htpasswd -bnBC 10 "" password.
+type User struct {
+	Username     string `json:"username"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"passwordHash"`
+}
+
+type usersFile struct {
+	Users []User `json:"users"`
+}
+
+// hashCost is the bcrypt cost of new password hashes
+var hashCost = bcrypt.DefaultCost
+
+// dummyHash is compared against when the username is unknown, so a failed
+// login takes as long whether or not the user exists
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+// Store holds the accounts of a users file and writes password changes back
+// to it. Each gateway instance reads its own copy of the file.
+type Store struct {
+	path string
+
+	mu      sync.RWMutex
+	users   map[string]User
+	byEmail map[string]string
+}
+
+// Load reads the users file
+func Load(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users file: %w", err)
+	}
+	var file usersFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid users file: %w", err)
+	}
+
+	s := &Store{
+		path:    path,
+		users:   make(map[string]User, len(file.Users)),
+		byEmail: make(map[string]string, len(file.Users)),
+	}
+	for _, user := range file.Users {
+		if user.Username == "" || user.PasswordHash == "" {
+			return nil, errors.New("invalid users file: every user needs a username and a passwordHash")
+		}
+		if _, ok := s.users[user.Username]; ok {
+			return nil, fmt.Errorf("invalid users file: user %q is listed twice", user.Username)
+		}
+		s.users[user.Username] = user
+		if email := normalizeEmail(user.Email); email != "" {
+			if _, ok := s.byEmail[email]; ok {
+				return nil, fmt.Errorf("invalid users file: email of %q is used by another user", user.Username)
+			}
+			s.byEmail[email] = user.Username
+		}
+	}
+	return s, nil
+}
+
+// Authenticate reports whether password is the user's
+func (s *Store) Authenticate(username, password string) bool {
+	s.mu.RLock()
+	user, ok := s.users[username]
+	s.mu.RUnlock()
+
+	hash := dummyHash
+	if ok {
+		hash = []byte(user.PasswordHash)
+	}
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil && ok
+}
+
+// Get returns the user with the username
+func (s *Store) Get(username string) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[username]
+	return user, ok
+}
+
+// FindByEmail returns the user with the email address, compared case-insensitively
+func (s *Store) FindByEmail(email string) (User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	username, ok := s.byEmail[normalizeEmail(email)]
+	if !ok {
+		return User{}, false
+	}
+	return s.users[username], true
+}
+
+// SetPassword hashes the user's new password and writes the users file. The
+// change is undone when the file cannot be written.
+func (s *Store) SetPassword(username, password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrWeakPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), hashCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[username]
+	if !ok {
+		return ErrUnknownUser
+	}
+	previous := user
+	user.PasswordHash = string(hash)
+	s.users[username] = user
+	if err := s.save(); err != nil {
+		s.users[username] = previous
+		return err
+	}
+	return nil
+}
+
+// save replaces the users file with the accounts in memory, through a
+// temporary file so a crash never leaves it half written
+func (s *Store) save() error {
+	file := usersFile{Users: make([]User, 0, len(s.users))}
+	for _, user := range s.users {
+		file.Users = append(file.Users, user)
+	}
+	sort.Slice(file.Users, func(i, j int) bool { return file.Users[i].Username < file.Users[j].Username })
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".users-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
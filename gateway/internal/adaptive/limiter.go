@@ -0,0 +1,196 @@
+// Package adaptive limits the requests in flight to an upstream service with a
+// limit that follows the upstream's health.
+//
+// The limit is adjusted AIMD style: every successful, timely response while the
+// limit is in use raises it by 1/limit (about one per round of requests), and a
+// failure or a response slower than the latency threshold multiplies it by the
+// backoff factor. Only requests sent after the last decrease can lower the
+// limit again, so a burst of slow responses that were already in flight counts
+// as one congestion signal instead of collapsing the limit to its minimum.
+package adaptive
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Outcome classifies a finished request for the limiter
+type Outcome int
+
+const (
+	// Success is a response the upstream produced normally
+	Success Outcome = iota
+	// Failure is a transport error or a 5xx response
+	Failure
+	// Ignore drops the sample, e.g. when the caller gave up before the upstream answered
+	Ignore
+)
+
+// latencyWeight is how much a new sample moves the average latency
+const latencyWeight = 0.1
+
+// Options configures a Limiter
+type Options struct {
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	// LatencyThreshold marks responses slower than it as a congestion signal
+	LatencyThreshold time.Duration
+	// Backoff multiplies the limit on congestion, between 0 and 1
+	Backoff float64
+}
+
+// Stats is a snapshot of the limiter and the upstream health it observed
+type Stats struct {
+	Enabled  bool `json:"enabled" example:"true"`
+	Limit    int  `json:"limit" example:"87"`
+	MinLimit int  `json:"minLimit" example:"10"`
+	MaxLimit int  `json:"maxLimit" example:"500"`
+	InFlight int  `json:"inFlight" example:"12"`
+	// Rejected counts requests answered with 503 without reaching the upstream
+	Rejected  int64 `json:"rejected" example:"42"`
+	Successes int64 `json:"successes" example:"98231"`
+	Failures  int64 `json:"failures" example:"17"`
+	// SlowResponses counts successes slower than the latency threshold
+	SlowResponses int64 `json:"slowResponses" example:"120"`
+	// Decreases counts how often congestion lowered the limit
+	Decreases int64 `json:"decreases" example:"9"`
+	// AverageLatencyMs is a moving average of upstream response times
+	AverageLatencyMs   float64 `json:"averageLatencyMs" example:"48.5"`
+	LatencyThresholdMs int64   `json:"latencyThresholdMs" example:"1000"`
+}
+
+// Limiter bounds concurrent upstream requests with an adaptive limit
+type Limiter struct {
+	opts Options
+	now  func() time.Time
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	lastDecrease time.Time
+	latency      float64 // moving average in milliseconds
+	rejected     int64
+	successes    int64
+	failures     int64
+	slow         int64
+	decreases    int64
+}
+
+// New creates a limiter. Limits are clamped so 1 <= min <= initial <= max, and a
+// backoff outside (0, 1) falls back to 0.9.
+func New(opts Options) *Limiter {
+	if opts.MinLimit < 1 {
+		opts.MinLimit = 1
+	}
+	if opts.MaxLimit < opts.MinLimit {
+		opts.MaxLimit = opts.MinLimit
+	}
+	if opts.InitialLimit < opts.MinLimit {
+		opts.InitialLimit = opts.MinLimit
+	}
+	if opts.InitialLimit > opts.MaxLimit {
+		opts.InitialLimit = opts.MaxLimit
+	}
+	if opts.Backoff <= 0 || opts.Backoff >= 1 {
+		opts.Backoff = 0.9
+	}
+	return &Limiter{
+		opts:  opts,
+		now:   time.Now,
+		limit: float64(opts.InitialLimit),
+	}
+}
+
+// Acquire reserves a slot for a request. It returns false when the limit is
+// reached; otherwise the request must call release with its outcome once the
+// upstream has answered.
+func (l *Limiter) Acquire() (release func(Outcome), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight >= l.currentLimit() {
+		l.rejected++
+		return nil, false
+	}
+	l.inFlight++
+	inFlight := l.inFlight
+	start := l.now()
+
+	var once sync.Once
+	return func(outcome Outcome) {
+		once.Do(func() { l.release(start, inFlight, outcome) })
+	}, true
+}
+
+// release records a finished request and adjusts the limit
+func (l *Limiter) release(start time.Time, inFlight int, outcome Outcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if outcome == Ignore {
+		return
+	}
+
+	now := l.now()
+	elapsed := now.Sub(start)
+	ms := float64(elapsed) / float64(time.Millisecond)
+	if l.successes+l.failures == 0 {
+		l.latency = ms
+	} else {
+		l.latency += latencyWeight * (ms - l.latency)
+	}
+
+	congested := outcome == Failure
+	if outcome == Failure {
+		l.failures++
+	} else {
+		l.successes++
+		if l.opts.LatencyThreshold > 0 && elapsed > l.opts.LatencyThreshold {
+			l.slow++
+			congested = true
+		}
+	}
+
+	if congested {
+		if start.After(l.lastDecrease) {
+			l.limit = math.Max(float64(l.opts.MinLimit), math.Floor(l.limit*l.opts.Backoff))
+			l.lastDecrease = now
+			l.decreases++
+		}
+		return
+	}
+	// Only grow while the limit is actually used, so an idle period does not
+	// leave it far above what the upstream was shown to handle
+	if float64(inFlight)*2 >= l.limit {
+		l.limit = math.Min(float64(l.opts.MaxLimit), l.limit+1/l.limit)
+	}
+}
+
+// currentLimit is the whole number of requests allowed in flight
+func (l *Limiter) currentLimit() int {
+	return int(l.limit)
+}
+
+// Stats returns a snapshot of the limiter
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return Stats{
+		Enabled:            true,
+		Limit:              l.currentLimit(),
+		MinLimit:           l.opts.MinLimit,
+		MaxLimit:           l.opts.MaxLimit,
+		InFlight:           l.inFlight,
+		Rejected:           l.rejected,
+		Successes:          l.successes,
+		Failures:           l.failures,
+		SlowResponses:      l.slow,
+		Decreases:          l.decreases,
+		AverageLatencyMs:   math.Round(l.latency*10) / 10,
+		LatencyThresholdMs: l.opts.LatencyThreshold.Milliseconds(),
+	}
+}
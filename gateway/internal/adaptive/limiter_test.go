@@ -0,0 +1,124 @@
+package adaptive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances only when told to
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+func newTestLimiter(opts Options) (*Limiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)}
+	l := New(opts)
+	l.now = clock.Now
+	return l, clock
+}
+
+func TestLimiter_RejectsOverLimit(t *testing.T) {
+	l, _ := newTestLimiter(Options{InitialLimit: 2, MinLimit: 1, MaxLimit: 10})
+
+	first, ok := l.Acquire()
+	require.True(t, ok)
+	_, ok = l.Acquire()
+	require.True(t, ok)
+	_, ok = l.Acquire()
+	assert.False(t, ok, "a third request exceeds the limit of 2")
+
+	first(Success)
+	first(Success) // releasing twice frees one slot only
+	_, ok = l.Acquire()
+	assert.True(t, ok)
+
+	stats := l.Stats()
+	assert.Equal(t, 2, stats.InFlight)
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(1), stats.Successes)
+}
+
+func TestLimiter_AdditiveIncrease(t *testing.T) {
+	l, clock := newTestLimiter(Options{InitialLimit: 2, MinLimit: 1, MaxLimit: 3, LatencyThreshold: time.Second})
+
+	// Requests that use the limit and answer in time raise it by 1/limit each
+	for i := 0; i < 5; i++ {
+		first, ok := l.Acquire()
+		require.True(t, ok)
+		second, ok := l.Acquire()
+		require.True(t, ok)
+		clock.Advance(10 * time.Millisecond)
+		first(Success)
+		second(Success)
+	}
+	assert.Equal(t, 3, l.Stats().Limit, "the limit grows up to MaxLimit")
+	assert.Equal(t, 10.0, l.Stats().AverageLatencyMs)
+}
+
+func TestLimiter_IdleDoesNotGrow(t *testing.T) {
+	l, _ := newTestLimiter(Options{InitialLimit: 10, MinLimit: 1, MaxLimit: 100})
+
+	for i := 0; i < 50; i++ {
+		release, _ := l.Acquire()
+		release(Success)
+	}
+	assert.Equal(t, 10, l.Stats().Limit, "a single request in flight does not use a limit of 10")
+}
+
+func TestLimiter_MultiplicativeDecrease(t *testing.T) {
+	l, clock := newTestLimiter(Options{InitialLimit: 20, MinLimit: 5, MaxLimit: 100, LatencyThreshold: 500 * time.Millisecond, Backoff: 0.5})
+
+	// Slow responses to requests that were in flight together lower the limit once
+	releases := make([]func(Outcome), 3)
+	for i := range releases {
+		release, ok := l.Acquire()
+		require.True(t, ok)
+		releases[i] = release
+	}
+	clock.Advance(time.Second)
+	for _, release := range releases {
+		release(Success)
+	}
+	stats := l.Stats()
+	assert.Equal(t, 10, stats.Limit)
+	assert.Equal(t, int64(3), stats.SlowResponses)
+	assert.Equal(t, int64(1), stats.Decreases)
+
+	// A failure of a request sent after the decrease lowers it again, down to MinLimit
+	for i := 0; i < 3; i++ {
+		clock.Advance(time.Millisecond)
+		release, ok := l.Acquire()
+		require.True(t, ok)
+		clock.Advance(time.Millisecond)
+		release(Failure)
+	}
+	stats = l.Stats()
+	assert.Equal(t, 5, stats.Limit)
+	assert.Equal(t, int64(3), stats.Failures)
+}
+
+func TestLimiter_IgnoredOutcome(t *testing.T) {
+	l, clock := newTestLimiter(Options{InitialLimit: 4, MinLimit: 1, MaxLimit: 10, LatencyThreshold: time.Millisecond})
+
+	release, ok := l.Acquire()
+	require.True(t, ok)
+	clock.Advance(time.Second)
+	release(Ignore)
+
+	stats := l.Stats()
+	assert.Equal(t, 4, stats.Limit)
+	assert.Equal(t, 0, stats.InFlight)
+	assert.Zero(t, stats.Successes+stats.Failures)
+}
+
+func TestNew_ClampsOptions(t *testing.T) {
+	l := New(Options{InitialLimit: 0, MinLimit: 0, MaxLimit: 0, Backoff: 2})
+	stats := l.Stats()
+	assert.Equal(t, 1, stats.Limit)
+	assert.Equal(t, 1, stats.MinLimit)
+	assert.Equal(t, 1, stats.MaxLimit)
+	assert.Equal(t, 0.9, l.opts.Backoff)
+}
@@ -0,0 +1,11 @@
+// Package apimodel holds the request and response models the gateway shares
+// with the driver service. They mirror the driver service's DTOs field for
+// field so the gateway's Swagger documents what clients actually receive.
+//
+// go generate snapshots the driver service's definitions from its Swagger
+// document into testdata/driver-service.json, and the tests fail when a model
+// and the snapshot disagree: regenerate after changing a driver service DTO and
+// update the model to match.
+package apimodel
+
+//go:generate go test -run TestDriverServiceParity -update .
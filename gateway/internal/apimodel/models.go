@@ -0,0 +1,756 @@
+package apimodel
+
+// Driver represents a taxi driver
+type Driver struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Plate     string `json:"plate"`
+	TaxiType  string `json:"taxiType"`
+	CarBrand  string `json:"carBrand"`
+	CarModel  string `json:"carModel"`
+	Location  struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"location"`
+	// City is the city the location is in, or other outside every configured city
+	City string `json:"city,omitempty" example:"istanbul"`
+	// LocationUpdatedAt is when the location was last reported
+	LocationUpdatedAt string `json:"locationUpdatedAt,omitempty"`
+	Phone             string `json:"phone,omitempty"`
+	Email             string `json:"email,omitempty"`
+	PhoneVerified     bool   `json:"phoneVerified"`
+	EmailVerified     bool   `json:"emailVerified"`
+	// IdentityVerified is set once the KYC provider approved the driver's identity
+	IdentityVerified bool           `json:"identityVerified"`
+	Available        bool           `json:"available"`
+	Suspended        bool           `json:"suspended"`
+	SuspendReason    string         `json:"suspensionReason,omitempty"`
+	License          *DriverLicense `json:"license,omitempty"`
+	// LicenseExpired drivers have been taken off dispatch until the licence is renewed
+	LicenseExpired bool    `json:"licenseExpired,omitempty"`
+	Rating         float64 `json:"rating"`
+	RatingCount    int     `json:"ratingCount"`
+	FleetID        string  `json:"fleetId,omitempty"`
+	// Tags and Attributes describe what the driver offers, for dispatch filters
+	Tags       []string          `json:"tags,omitempty" example:"pet-friendly,wheelchair-accessible"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	LastSeenAt string            `json:"lastSeenAt,omitempty"`
+	// Photo is the driver's profile picture, if one was uploaded
+	Photo *DriverPhoto `json:"photo,omitempty"`
+	// Documents holds at most one document per type
+	Documents []DriverDocument `json:"documents,omitempty"`
+	CreatedAt string           `json:"createdAt"`
+	UpdatedAt string           `json:"updatedAt"`
+	// DeletedAt is set on the tombstone a deleted driver leaves for delta syncs
+	DeletedAt string `json:"deletedAt,omitempty"`
+}
+
+// DriverDocument is a document a driver uploaded, such as a licence or an insurance policy
+type DriverDocument struct {
+	Type       string `json:"type" example:"license" enums:"license,registration,insurance"`
+	URL        string `json:"url" example:"https://files.bitaksi.com/docs/license.pdf"`
+	Number     string `json:"number,omitempty" example:"TR-1234567"`
+	ExpiresAt  string `json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+	UploadedAt string `json:"uploadedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// KYCCheck is an identity check of a driver by the KYC provider
+type KYCCheck struct {
+	ID          string `json:"id" example:"6570a1f2c3d4e5f6a7b8c9d1"`
+	DriverID    string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Provider    string `json:"provider" example:"sandbox"`
+	Reference   string `json:"reference" example:"sbx_9f3c2a1b7e6d5c4a"`
+	Status      string `json:"status" example:"pending" enums:"pending,approved,rejected"`
+	Reason      string `json:"reason,omitempty" example:"document is not legible"`
+	SubmittedAt string `json:"submittedAt" example:"2025-12-06T01:00:00Z"`
+	DecidedAt   string `json:"decidedAt,omitempty" example:"2025-12-06T01:05:00Z"`
+}
+
+// DriverPhoto is a driver's profile picture with its square thumbnails keyed by side in pixels
+type DriverPhoto struct {
+	URL        string            `json:"url" example:"https://cdn.bitaksi.com/media/drivers/507f1f77bcf86cd799439011/photo/1733446800/full.jpg"`
+	Thumbnails map[string]string `json:"thumbnails"`
+	Width      int               `json:"width" example:"1024"`
+	Height     int               `json:"height" example:"768"`
+	UploadedAt string            `json:"uploadedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// DriverLicense is the driving licence a driver holds
+type DriverLicense struct {
+	Number    string `json:"number" example:"TR-1234567"`
+	Class     string `json:"class" example:"B" enums:"B,BE,C1,C1E,C,CE,D1,D1E,D,DE"`
+	ExpiresAt string `json:"expiresAt" example:"2030-01-01T00:00:00Z"`
+}
+
+// ExpiringLicense is a driver whose licence expires within the report window
+type ExpiringLicense struct {
+	DriverID  string        `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	FirstName string        `json:"firstName" example:"Ahmet"`
+	LastName  string        `json:"lastName" example:"Demir"`
+	Plate     string        `json:"plate" example:"34ABC123"`
+	FleetID   string        `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	License   DriverLicense `json:"license"`
+	// DaysLeft is negative once the licence has expired
+	DaysLeft int  `json:"daysLeft" example:"12"`
+	Expired  bool `json:"expired" example:"false"`
+	// Flagged drivers have been taken off dispatch by the licence check
+	Flagged bool `json:"flagged" example:"false"`
+}
+
+// ExpiringLicensesResponse lists licences expiring within a number of days, soonest first
+type ExpiringLicensesResponse struct {
+	Days     int               `json:"days" example:"30"`
+	AsOf     string            `json:"asOf" example:"2025-12-06T01:00:00Z"`
+	Count    int               `json:"count" example:"1"`
+	Licenses []ExpiringLicense `json:"licenses"`
+}
+
+// ValidationRuleset is the effective driver validation rules of one country or tenant
+type ValidationRuleset struct {
+	Country string `json:"country" example:"TR"`
+	Tenant  string `json:"tenant,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// PlatePattern is empty where the Turkish plate format applies
+	PlatePattern   string            `json:"platePattern,omitempty" example:"^[0-9]{2}[A-Z]{2}[0-9]{3}$"`
+	PlateExample   string            `json:"plateExample" example:"34ABC123"`
+	RequiredFields []string          `json:"requiredFields" example:"firstName,lastName,carBrand,carModel"`
+	TaxiTypes      []string          `json:"taxiTypes" example:"sari,turkuaz,siyah"`
+	FieldPatterns  map[string]string `json:"fieldPatterns,omitempty"`
+}
+
+// ValidationRulesResponse lists the effective rules of every country and tenant
+type ValidationRulesResponse struct {
+	// Country is the market the driver service runs in; Default holds its rules
+	Country   string                       `json:"country" example:"TR"`
+	Default   ValidationRuleset            `json:"default"`
+	Countries map[string]ValidationRuleset `json:"countries"`
+	Tenants   map[string]ValidationRuleset `json:"tenants"`
+}
+
+// DriverQuota is the driver seats a tenant (fleet) pays for and how many are taken
+type DriverQuota struct {
+	TenantID string `json:"tenantId" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// MaxDrivers is the quota; 0 is unlimited
+	MaxDrivers int   `json:"maxDrivers" example:"50"`
+	Drivers    int64 `json:"drivers" example:"42"`
+	// Remaining is left out for unlimited tenants
+	Remaining   *int64  `json:"remaining,omitempty" example:"8"`
+	UsedPercent float64 `json:"usedPercent" example:"84"`
+	// Thresholds are the usage percentages a TenantQuotaThresholdReached event is emitted at
+	Thresholds []int `json:"thresholds" example:"80,100"`
+}
+
+// EarningsTotals sums earnings ledger entries
+type EarningsTotals struct {
+	Trips       int     `json:"trips" example:"12"`
+	Gross       float64 `json:"gross" example:"2788.8"`
+	Commission  float64 `json:"commission" example:"418.32"`
+	Adjustments float64 `json:"adjustments" example:"50"`
+	Net         float64 `json:"net" example:"2420.48"`
+}
+
+// EarningsBucket sums a driver's earnings over one day or week
+type EarningsBucket struct {
+	Start string `json:"start" example:"2025-12-01T00:00:00+03:00"`
+	EarningsTotals
+}
+
+// EarningsSummary is a driver's earnings over a range, bucketed by day or week
+type EarningsSummary struct {
+	DriverID string           `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Period   string           `json:"period" example:"daily" enums:"daily,weekly"`
+	From     string           `json:"from" example:"2025-11-06T00:00:00+03:00"`
+	To       string           `json:"to" example:"2025-12-06T00:00:00+03:00"`
+	Timezone string           `json:"timezone" example:"Europe/Istanbul"`
+	Currency string           `json:"currency" example:"TRY"`
+	Totals   EarningsTotals   `json:"totals"`
+	Buckets  []EarningsBucket `json:"buckets"`
+}
+
+// Earning is an entry in a driver's earnings ledger: a trip or a manual adjustment
+type Earning struct {
+	ID         string  `json:"id" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	DriverID   string  `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Type       string  `json:"type" example:"adjustment" enums:"trip,adjustment"`
+	TripID     string  `json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+	Gross      float64 `json:"gross" example:"0"`
+	Commission float64 `json:"commission" example:"0"`
+	Net        float64 `json:"net" example:"50"`
+	Currency   string  `json:"currency" example:"TRY"`
+	Reason     string  `json:"reason,omitempty" example:"Toll reimbursement"`
+	CreatedBy  string  `json:"createdBy,omitempty" example:"ops-admin"`
+	PayoutID   string  `json:"payoutId,omitempty" example:"6573b2f3c4d5e6f7a8b9c0d1"`
+	EarnedAt   string  `json:"earnedAt" example:"2025-12-06T01:00:00Z"`
+	CreatedAt  string  `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// Erasure is the audit record of erasing a driver's personal data
+type Erasure struct {
+	ID          string           `json:"id" example:"6574a1f2c3d4e5f6a7b8c9d0"`
+	DriverID    string           `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Trigger     string           `json:"trigger" example:"request" enums:"retention,request"`
+	RequestedBy string           `json:"requestedBy,omitempty" example:"ops-admin"`
+	Reason      string           `json:"reason,omitempty" example:"KVKK request #4711"`
+	Records     map[string]int64 `json:"records"`
+	Files       int              `json:"files" example:"4"`
+	ErasedAt    string           `json:"erasedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// BulkFilter selects the drivers a bulk update changes; every set field must match
+type BulkFilter struct {
+	IDs        []string          `json:"ids,omitempty" example:"507f1f77bcf86cd799439011"`
+	FleetID    string            `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	City       string            `json:"city,omitempty" example:"istanbul"`
+	TaxiType   string            `json:"taksiType,omitempty" example:"sari"`
+	CarBrand   string            `json:"carBrand,omitempty" example:"Fiat"`
+	CarModel   string            `json:"carModel,omitempty" example:"Egea"`
+	Tags       []string          `json:"tags,omitempty" example:"pet-friendly"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// DriverPatch is the fields a bulk update sets; omitted fields are left alone
+type DriverPatch struct {
+	CarBrand *string `json:"carBrand,omitempty" example:"Toyota"`
+	CarModel *string `json:"carModel,omitempty" example:"Corolla"`
+	TaxiType *string `json:"taksiType,omitempty" example:"turkuaz"`
+	// Suspended suspends or reinstates the drivers; suspending takes them off shift
+	Suspended     *bool  `json:"suspended,omitempty" example:"true"`
+	SuspendReason string `json:"suspensionReason,omitempty" example:"fleet inspection"`
+}
+
+// BulkUpdate is the audit record of a bulk update
+type BulkUpdate struct {
+	ID     string      `json:"id" example:"6578a1f2c3d4e5f6a7b8c9d0"`
+	Filter BulkFilter  `json:"filter"`
+	Patch  DriverPatch `json:"patch"`
+	DryRun bool        `json:"dryRun" example:"false"`
+	// Matched is the number of drivers the filter matched
+	Matched int64 `json:"matched" example:"42"`
+	// Modified is the number of drivers the patch changed, or would change on a dry run
+	Modified  int64  `json:"modified" example:"40"`
+	ActorID   string `json:"actorId,omitempty" example:"fleet-admin"`
+	ActorRole string `json:"actorRole,omitempty" example:"fleet_admin"`
+	TenantID  string `json:"tenantId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	CreatedAt string `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// JobResult describes the file a background job produced
+type JobResult struct {
+	FileName    string `json:"fileName" example:"drivers.ndjson"`
+	ContentType string `json:"contentType" example:"application/x-ndjson"`
+	Size        int64  `json:"size" example:"48210331"`
+}
+
+// Job is a background job, such as a driver export, and its progress
+type Job struct {
+	ID     string `json:"id" example:"job-9f86d081884c7d65"`
+	Kind   string `json:"kind" example:"export"`
+	Status string `json:"status" example:"running" enums:"queued,running,completed,failed"`
+	// Owner is the fleet whose drivers the job covers; empty for all fleets
+	Owner string `json:"owner,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Actor string `json:"actor,omitempty" example:"ops-admin"`
+	// Total is 0 until the job knows how many drivers it processes
+	Total     int64 `json:"total" example:"1000000"`
+	Processed int64 `json:"processed" example:"420000"`
+	// Changed counts exported drivers updated after the export started
+	Changed    int64      `json:"changed,omitempty" example:"12"`
+	Error      string     `json:"error,omitempty" example:"failed to list drivers"`
+	Result     *JobResult `json:"result,omitempty"`
+	CreatedAt  string     `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	StartedAt  string     `json:"startedAt,omitempty" example:"2025-12-06T01:00:01Z"`
+	FinishedAt string     `json:"finishedAt,omitempty" example:"2025-12-06T01:04:10Z"`
+	// ExpiresAt is when a finished job and its file are deleted
+	ExpiresAt string `json:"expiresAt,omitempty" example:"2025-12-07T01:04:10Z"`
+}
+
+// PayoutLine is what a payout owes one driver
+type PayoutLine struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	EarningsTotals
+}
+
+// Payout is a batch of unpaid earnings handed to finance for settlement
+type Payout struct {
+	ID        string       `json:"id" example:"6573b2f3c4d5e6f7a8b9c0d1"`
+	From      string       `json:"from,omitempty" example:"2025-12-01T00:00:00Z"`
+	To        string       `json:"to" example:"2025-12-08T00:00:00Z"`
+	Currency  string       `json:"currency" example:"TRY"`
+	Drivers   int          `json:"drivers" example:"1"`
+	Total     float64      `json:"total" example:"2420.48"`
+	Lines     []PayoutLine `json:"lines"`
+	CreatedBy string       `json:"createdBy,omitempty" example:"ops-admin"`
+	CreatedAt string       `json:"createdAt" example:"2025-12-08T06:00:00Z"`
+}
+
+// ListDriversResponse represents a paginated list of drivers
+type ListDriversResponse struct {
+	Drivers    []Driver `json:"drivers"`
+	TotalCount int64    `json:"totalCount"`
+	Page       int      `json:"page"`
+	PageSize   int      `json:"pageSize"`
+	TotalPages int      `json:"totalPages" example:"1"`
+	HasNext    bool     `json:"hasNext" example:"false"`
+	HasPrev    bool     `json:"hasPrev" example:"false"`
+}
+
+// DriverChangesResponse lists the drivers created, updated and deleted since a sync marker
+type DriverChangesResponse struct {
+	Created   []Driver `json:"created"`
+	Updated   []Driver `json:"updated"`
+	Deleted   []string `json:"deleted"`
+	NextToken string   `json:"nextToken"`
+	HasMore   bool     `json:"hasMore" example:"false"`
+}
+
+// AutocompleteResponse lists the drivers whose name or plate starts with the query words
+type AutocompleteResponse struct {
+	Suggestions []DriverSuggestion `json:"suggestions"`
+	// TimedOut is set when the search ran past its time budget and returned no suggestions
+	TimedOut bool `json:"timedOut" example:"false"`
+}
+
+// DriverSuggestion is a compact driver for autocomplete lists
+type DriverSuggestion struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name" example:"Ahmet Demir"`
+	Plate      string            `json:"plate"`
+	Highlights []SearchHighlight `json:"highlights"`
+}
+
+// SearchHighlight marks the characters of a suggestion's name or plate the query matched
+type SearchHighlight struct {
+	Field  string `json:"field" example:"name"`
+	Start  int    `json:"start" example:"0"`
+	Length int    `json:"length" example:"2"`
+}
+
+// NearbyDriverResponse represents a driver in nearby search results
+type NearbyDriverResponse struct {
+	ID         string  `json:"id"`
+	FirstName  string  `json:"firstName"`
+	LastName   string  `json:"lastName"`
+	Plate      string  `json:"plate"`
+	TaxiType   string  `json:"taxiType"`
+	DistanceKm float64 `json:"distanceKm"`
+	// Distance is DistanceKm in the units asked for; left out when none were
+	Distance *float64 `json:"distance,omitempty" example:"500"`
+	// DurationSec is the estimated travel time to the search location
+	DurationSec int `json:"durationSec" example:"240"`
+	// LastLocationUpdate is when the driver's position was last reported
+	LastLocationUpdate string `json:"lastLocationUpdate" example:"2025-12-06T01:00:00Z"`
+	// StaleSeconds is the age of the position at search time; gray out drivers whose position is old
+	StaleSeconds int `json:"staleSeconds" example:"42"`
+}
+
+// FareQuote is the pricing service's estimate of what a trip would cost
+type FareQuote struct {
+	Amount   float64 `json:"amount" example:"185.5"`
+	Currency string  `json:"currency" example:"TRY"`
+}
+
+// NearbyDriverWithFare is a nearby driver with the fare of a trip with them,
+// returned when nearby drivers are searched with includeFares
+type NearbyDriverWithFare struct {
+	NearbyDriverResponse
+	// Fare is left out for drivers the pricing service could not price in time
+	Fare *FareQuote `json:"fare,omitempty"`
+}
+
+// RouteSearchRequest represents the request to find drivers along a route
+type RouteSearchRequest struct {
+	// Waypoints are the ordered points of the route, 2 to 25 of them
+	Waypoints []Location `json:"waypoints"`
+	// WidthKm is how far from the route drivers may be; defaults to 0.5, at most 2
+	WidthKm  float64 `json:"widthKm,omitempty" example:"0.5"`
+	TaxiType string  `json:"taksiType,omitempty" example:"sari"`
+	FleetID  string  `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// Live only returns drivers with a recent heartbeat; omitted leaves it to the driver service setting
+	Live *bool `json:"live,omitempty" example:"true"`
+}
+
+// RouteDriverResponse represents a driver in route corridor search results
+type RouteDriverResponse struct {
+	ID        string   `json:"id"`
+	FirstName string   `json:"firstName"`
+	LastName  string   `json:"lastName"`
+	Plate     string   `json:"plate"`
+	TaxiType  string   `json:"taxiType"`
+	Location  Location `json:"location"`
+	// DistanceKm is the straight-line distance from the driver to the route
+	DistanceKm float64 `json:"distanceKm" example:"0.2"`
+	// RouteKm is how far along the route, from its first waypoint, the driver is closest to it
+	RouteKm float64 `json:"routeKm" example:"3.4"`
+	// Distance and RouteDistance are DistanceKm and RouteKm in the units asked
+	// for; left out when none were
+	Distance      *float64 `json:"distance,omitempty" example:"200"`
+	RouteDistance *float64 `json:"routeDistance,omitempty" example:"3400"`
+}
+
+// Location represents geographic coordinates
+type Location struct {
+	Lat float64 `json:"lat" example:"41.0431"`
+	Lon float64 `json:"lon" example:"29.0099"`
+	// Geohash is the cell of the coordinates, rendered when the driver service sets GEOHASH_PRECISION
+	Geohash string `json:"geohash,omitempty" example:"sxk9kp"`
+}
+
+// Trip represents a ride request and its assignment to a driver
+type Trip struct {
+	ID                string        `json:"id"`
+	RiderID           string        `json:"riderId,omitempty"`
+	Pickup            Location      `json:"pickup"`
+	Dropoff           *Location     `json:"dropoff,omitempty"`
+	TaxiType          string        `json:"taxiType,omitempty"`
+	Status            string        `json:"status" enums:"requested,offered,accepted,completed,cancelled,no_driver_found"`
+	Strategy          string        `json:"strategy"`
+	DriverID          string        `json:"driverId,omitempty"`
+	OfferedDriverID   string        `json:"offeredDriverId,omitempty"`
+	OfferExpiresAt    string        `json:"offerExpiresAt,omitempty"`
+	DeclinedDriverIDs []string      `json:"declinedDriverIds"`
+	OfferCount        int           `json:"offerCount"`
+	DistanceKm        float64       `json:"distanceKm,omitempty"`
+	Estimate          *FareEstimate `json:"estimate,omitempty"`
+	Fare              float64       `json:"fare,omitempty" example:"232.4"`
+	Rating            *float64      `json:"rating,omitempty" example:"5"`
+	CreatedAt         string        `json:"createdAt"`
+	UpdatedAt         string        `json:"updatedAt"`
+	AcceptedAt        string        `json:"acceptedAt,omitempty"`
+	CompletedAt       string        `json:"completedAt,omitempty"`
+}
+
+// TaxiType is a taxi type the service runs and what clients show for it
+type TaxiType struct {
+	ID          string `json:"id" example:"sari"`
+	Name        string `json:"name" example:"Sarı Taksi"`
+	Description string `json:"description,omitempty" example:"Standard city taxi"`
+	// Capacity is the number of passenger seats
+	Capacity int    `json:"capacity" example:"4"`
+	Icon     string `json:"icon,omitempty" example:"taxi-yellow"`
+}
+
+// TaxiTypeCatalog lists the taxi types clients may offer, in display order
+type TaxiTypeCatalog struct {
+	TaxiTypes []TaxiType `json:"taxiTypes"`
+}
+
+// FareEstimate is a fare quoted for a route before the trip is taken
+type FareEstimate struct {
+	DistanceKm  float64 `json:"distanceKm" example:"7.4"`
+	DurationSec int     `json:"durationSec" example:"1080"`
+	Amount      float64 `json:"amount" example:"232.4"`
+	Currency    string  `json:"currency" example:"TRY"`
+	// Provider is the routing provider that measured the route: haversine or osrm
+	Provider string `json:"provider" example:"osrm"`
+}
+
+// Rider is a passenger who requests trips
+type Rider struct {
+	ID                string             `json:"id" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	FirstName         string             `json:"firstName" example:"Elif"`
+	LastName          string             `json:"lastName" example:"Yılmaz"`
+	Phone             string             `json:"phone" example:"+905551234567"`
+	Email             string             `json:"email,omitempty" example:"elif@example.com"`
+	FavoriteLocations []FavoriteLocation `json:"favoriteLocations"`
+	CreatedAt         string             `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt         string             `json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// FavoriteLocation is a place a rider saved for quick trip requests
+type FavoriteLocation struct {
+	ID       string   `json:"id" example:"fav_3f9a1c2b"`
+	Label    string   `json:"label" example:"Home"`
+	Address  string   `json:"address,omitempty" example:"Caferağa Mah. Moda Cad. No:1, Kadıköy"`
+	Location Location `json:"location"`
+}
+
+// RiderRegistrationResponse is a registered rider with an access token for the rider role
+type RiderRegistrationResponse struct {
+	Rider Rider  `json:"rider"`
+	Token string `json:"token"`
+}
+
+// RiderSignInResponse is a signed-in rider with an access token for the rider role
+type RiderSignInResponse struct {
+	Rider Rider  `json:"rider"`
+	Token string `json:"token"`
+}
+
+// Fleet groups the drivers of a taxi company
+type Fleet struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	CompanyName string `json:"companyName,omitempty"`
+	CreatedAt   string `json:"createdAt"`
+	UpdatedAt   string `json:"updatedAt"`
+}
+
+// DriverStats aggregates a driver's activity over a date range
+type DriverStats struct {
+	DriverID         string  `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	From             string  `json:"from" example:"2025-11-01T00:00:00Z"`
+	To               string  `json:"to" example:"2025-12-01T00:00:00Z"`
+	CompletedTrips   int     `json:"completedTrips" example:"42"`
+	TripDistanceKm   float64 `json:"tripDistanceKm" example:"311.6"`
+	DistanceDrivenKm float64 `json:"distanceDrivenKm" example:"528.3"`
+	OnlineHours      float64 `json:"onlineHours" example:"96.5"`
+	AverageRating    float64 `json:"averageRating" example:"4.8"`
+	RatingCount      int     `json:"ratingCount" example:"37"`
+	// LocationAnomalies counts location updates implying an implausible speed
+	LocationAnomalies int `json:"locationAnomalies" example:"0"`
+}
+
+// OnlineStats counts drivers by heartbeat liveness
+type OnlineStats struct {
+	Total               int64  `json:"total" example:"250"`
+	Online              int64  `json:"online" example:"180"`
+	Offline             int64  `json:"offline" example:"70"`
+	HeartbeatTimeoutSec int    `json:"heartbeatTimeoutSec" example:"120"`
+	AsOf                string `json:"asOf" example:"2025-12-06T01:00:00Z"`
+}
+
+// UtilizationDay compares how long the drivers of a taxi type were online on a
+// day with how long they spent on trips
+type UtilizationDay struct {
+	Day         string  `json:"day" example:"2025-12-01"`
+	TaxiType    string  `json:"taxiType" example:"sari"`
+	OnlineHours float64 `json:"onlineHours" example:"412.5"`
+	BusyHours   float64 `json:"busyHours" example:"268.1"`
+	Utilization float64 `json:"utilization" example:"0.65"`
+	// Partial is set on the current day
+	Partial bool `json:"partial,omitempty" example:"false"`
+}
+
+// UtilizationReport lists driver utilization per day and taxi type
+type UtilizationReport struct {
+	From     string           `json:"from" example:"2025-12-01"`
+	To       string           `json:"to" example:"2025-12-07"`
+	Timezone string           `json:"timezone" example:"Europe/Istanbul"`
+	Days     []UtilizationDay `json:"days"`
+}
+
+// LocationAnomalyCount counts the location updates of one driver that implied
+// an implausible speed, e.g. from a spoofed GPS position
+type LocationAnomalyCount struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	Count    int    `json:"count" example:"7"`
+	// Rejected is how many of the updates were refused
+	Rejected       int     `json:"rejected" example:"2"`
+	MaxSpeedKmh    float64 `json:"maxSpeedKmh" example:"11520"`
+	LastDetectedAt string  `json:"lastDetectedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// LocationAnomalyReport lists the drivers with location anomalies in a range,
+// most anomalies first
+type LocationAnomalyReport struct {
+	From    string                 `json:"from" example:"2025-11-06T00:00:00Z"`
+	To      string                 `json:"to" example:"2025-12-06T00:00:00Z"`
+	Drivers []LocationAnomalyCount `json:"drivers"`
+}
+
+// WebhookSubscription is a partner endpoint registered for driver events
+type WebhookSubscription struct {
+	ID      string `json:"id" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	FleetID string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	URL     string `json:"url" example:"https://partner.example.com/hooks/bitaksi"`
+	// Secret is only returned when the subscription is created
+	Secret      string   `json:"secret,omitempty" example:"whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"`
+	Events      []string `json:"events,omitempty" example:"driver.created,driver.suspended"`
+	Description string   `json:"description,omitempty" example:"Kadıköy Taksi dispatch system"`
+	CreatedAt   string   `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// DeviceToken is a long-lived credential of one driver device, limited to its
+// driver's location updates and heartbeats
+type DeviceToken struct {
+	ID       string `json:"id" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	DeviceID string `json:"deviceId" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890"`
+	Name     string `json:"name,omitempty" example:"Mehmet's Android"`
+	// Token is only returned when the token is issued or rotated
+	Token      string   `json:"token,omitempty" example:"dtk_4f1c9a7e2b6d8c0e3a5f7b9d1c3e5a7f9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a"`
+	Prefix     string   `json:"prefix" example:"dtk_4f1c9a7e"`
+	Scopes     []string `json:"scopes" example:"location:update,heartbeat"`
+	CreatedAt  string   `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	LastUsedAt string   `json:"lastUsedAt,omitempty" example:"2025-12-06T09:30:00Z"`
+	RevokedAt  string   `json:"revokedAt,omitempty"`
+	// ReplacedBy is the token a rotation issued in place of this one
+	ReplacedBy string `json:"replacedBy,omitempty" example:"6573b1f2c3d4e5f6a7b8c9d0"`
+}
+
+// ScheduledShift is a shift a fleet plans ahead for a driver. The driver is
+// put on shift when it starts and off shift when it ends.
+type ScheduledShift struct {
+	ID       string `json:"id" example:"6574a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	FleetID  string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	StartsAt string `json:"startsAt" example:"2025-12-08T06:00:00Z"`
+	EndsAt   string `json:"endsAt" example:"2025-12-08T14:00:00Z"`
+	Note     string `json:"note,omitempty" example:"Airport rank"`
+	// StartedAt is when the driver was put on shift
+	StartedAt string `json:"startedAt,omitempty" example:"2025-12-08T06:00:12Z"`
+	// StartError is why the driver could not be put on shift
+	StartError string `json:"startError,omitempty" example:"phone must be verified before going on shift"`
+	// EndedAt is when the driver was taken off shift
+	EndedAt   string `json:"endedAt,omitempty" example:"2025-12-08T14:00:09Z"`
+	CreatedAt string `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt string `json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// ShiftCalendar lists the shifts overlapping a time range, by start time
+type ShiftCalendar struct {
+	From   string           `json:"from" example:"2025-12-08T00:00:00Z"`
+	To     string           `json:"to" example:"2025-12-15T00:00:00Z"`
+	Count  int              `json:"count" example:"1"`
+	Shifts []ScheduledShift `json:"shifts"`
+}
+
+// Incident is a complaint about a driver reported by a rider or by
+// operations. High and critical incidents suspend the driver while they are
+// investigated; critical ones also revoke the identity verification.
+type Incident struct {
+	ID       string `json:"id" example:"6575a1f2c3d4e5f6a7b8c9d0"`
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011"`
+	// FleetID is the driver's fleet when the incident was reported
+	FleetID string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	// TripID is the trip the incident happened on, if any
+	TripID      string `json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+	Category    string `json:"category" example:"conduct" enums:"safety,conduct,vehicle,fare,route,other"`
+	Severity    string `json:"severity" example:"medium" enums:"low,medium,high,critical"`
+	Description string `json:"description" example:"Driver was on the phone during the whole ride"`
+	Status      string `json:"status" example:"open" enums:"open,investigating,resolved"`
+	// ReportedBy and ReporterRole identify the caller who reported it; empty for operators
+	ReportedBy   string `json:"reportedBy,omitempty" example:"rider-42"`
+	ReporterRole string `json:"reporterRole,omitempty" example:"rider"`
+	// DriverSuspended is set when reporting the incident suspended the driver
+	DriverSuspended bool `json:"driverSuspended" example:"false"`
+	// IdentityRevoked is set when reporting the incident revoked the driver's identity verification
+	IdentityRevoked bool   `json:"identityRevoked" example:"false"`
+	Outcome         string `json:"outcome,omitempty" example:"dismissed" enums:"upheld,dismissed"`
+	// Note is the latest remark of the investigation
+	Note       string `json:"note,omitempty" example:"Rider confirmed the driver used a hands-free set"`
+	CreatedAt  string `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt  string `json:"updatedAt" example:"2025-12-06T01:00:00Z"`
+	ResolvedAt string `json:"resolvedAt,omitempty" example:"2025-12-07T09:30:00Z"`
+}
+
+// ListIncidentsResponse is a page of incidents, newest first
+type ListIncidentsResponse struct {
+	Incidents  []Incident `json:"incidents"`
+	TotalCount int64      `json:"totalCount" example:"1"`
+	Page       int        `json:"page" example:"1"`
+	PageSize   int        `json:"pageSize" example:"20"`
+	TotalPages int        `json:"totalPages" example:"1"`
+	HasNext    bool       `json:"hasNext" example:"false"`
+	HasPrev    bool       `json:"hasPrev" example:"false"`
+}
+
+// WebhookDelivery is one event sent to one subscription, including its retries
+type WebhookDelivery struct {
+	ID             string                 `json:"id" example:"6572b1f2c3d4e5f6a7b8c9d0"`
+	SubscriptionID string                 `json:"subscriptionId" example:"6572a1f2c3d4e5f6a7b8c9d0"`
+	EventID        string                 `json:"eventId" example:"evt_5f0c2a9e8b7d4c3a"`
+	Event          string                 `json:"event" example:"driver.suspended"`
+	Payload        map[string]interface{} `json:"payload"`
+	Status         string                 `json:"status" example:"pending" enums:"pending,delivering,succeeded,failed"`
+	Attempts       int                    `json:"attempts" example:"2"`
+	NextAttemptAt  string                 `json:"nextAttemptAt,omitempty" example:"2025-12-06T01:00:40Z"`
+	LastStatusCode int                    `json:"lastStatusCode,omitempty" example:"503"`
+	LastError      string                 `json:"lastError,omitempty" example:"endpoint returned status 503"`
+	DeliveredAt    string                 `json:"deliveredAt,omitempty"`
+	CreatedAt      string                 `json:"createdAt" example:"2025-12-06T01:00:00Z"`
+	UpdatedAt      string                 `json:"updatedAt" example:"2025-12-06T01:00:10Z"`
+}
+
+// IndexStatus compares one expected MongoDB index with what exists
+type IndexStatus struct {
+	Collection string `json:"collection" example:"drivers"`
+	Name       string `json:"name" example:"fleetId_createdAt"`
+	Keys       string `json:"keys" example:"fleetId:1,createdAt:-1"`
+	ActualKeys string `json:"actualKeys,omitempty" example:"fleetId:1"`
+	State      string `json:"state" example:"present" enums:"present,missing,mismatch,unexpected"`
+}
+
+// IndexReport lists expected and existing driver service indexes
+type IndexReport struct {
+	Indexes    []IndexStatus `json:"indexes"`
+	Missing    int           `json:"missing" example:"1"`
+	Mismatched int           `json:"mismatched" example:"0"`
+	Unexpected int           `json:"unexpected" example:"0"`
+	InSync     bool          `json:"inSync" example:"false"`
+	CheckedAt  string        `json:"checkedAt" example:"2025-12-06T01:00:00Z"`
+}
+
+// FailoverStats reports MongoDB failover metrics of the driver service
+type FailoverStats struct {
+	Primary             string `json:"primary" example:"mongo-1:27017"`
+	PrimaryChanges      int64  `json:"primaryChanges" example:"2"`
+	LastPrimaryChangeAt string `json:"lastPrimaryChangeAt,omitempty" example:"2025-12-06T01:00:00Z"`
+	TransientErrors     int64  `json:"transientErrors" example:"14"`
+	Retries             int64  `json:"retries" example:"12"`
+	Recovered           int64  `json:"recovered" example:"9"`
+	Unavailable         int64  `json:"unavailable" example:"1"`
+	LastTransientAt     string `json:"lastTransientAt,omitempty" example:"2025-12-06T01:00:03Z"`
+}
+
+// TelemetryStats reports what the driver service's MQTT bridge received
+type TelemetryStats struct {
+	Broker           string                     `json:"broker" example:"tcp://mqtt:1883"`
+	Connected        bool                       `json:"connected" example:"true"`
+	Topics           []string                   `json:"topics" example:"drivers/+/location,drivers/+/heartbeat"`
+	QoS              int                        `json:"qos" example:"1"`
+	Messages         map[string]TelemetryCounts `json:"messages"`
+	Rejections       map[string]int64           `json:"rejections"`
+	Reconnects       int64                      `json:"reconnects" example:"1"`
+	LastMessageAt    string                     `json:"lastMessageAt,omitempty" example:"2025-12-06T09:30:00Z"`
+	LastDisconnectAt string                     `json:"lastDisconnectAt,omitempty" example:"2025-12-06T01:00:00Z"`
+}
+
+// TelemetryCounts counts the MQTT messages of one kind
+type TelemetryCounts struct {
+	Received  int64 `json:"received" example:"182340"`
+	Processed int64 `json:"processed" example:"182101"`
+	Rejected  int64 `json:"rejected" example:"230"`
+	Failed    int64 `json:"failed" example:"9"`
+}
+
+// QueryStats is the latency of one MongoDB command on one driver service
+// collection since the service started
+type QueryStats struct {
+	Collection string        `json:"collection" example:"drivers"`
+	Command    string        `json:"command" example:"find"`
+	Count      int64         `json:"count" example:"48210"`
+	Errors     int64         `json:"errors" example:"3"`
+	Slow       int64         `json:"slow" example:"12"`
+	TotalMs    float64       `json:"totalMs" example:"96420.5"`
+	MaxMs      float64       `json:"maxMs" example:"812.4"`
+	Buckets    []QueryBucket `json:"buckets"`
+}
+
+// QueryBucket is a cumulative bucket of a QueryStats histogram; the last one,
+// without leMs, counts every command
+type QueryBucket struct {
+	LeMs  float64 `json:"leMs,omitempty" example:"50"`
+	Count int64   `json:"count" example:"47890"`
+}
+
+// IndexSyncError records an index that could not be created
+type IndexSyncError struct {
+	Collection string `json:"collection" example:"drivers"`
+	Name       string `json:"name" example:"phone_unique"`
+	Error      string `json:"error" example:"E11000 duplicate key error"`
+}
+
+// IndexSyncJob tracks the creation of missing indexes
+type IndexSyncJob struct {
+	ID         string           `json:"id" example:"idxsync-1733446800000"`
+	Status     string           `json:"status" example:"running" enums:"running,completed,failed"`
+	Actor      string           `json:"actor,omitempty" example:"ops@bitaksi.com"`
+	StartedAt  string           `json:"startedAt" example:"2025-12-06T01:00:00Z"`
+	FinishedAt string           `json:"finishedAt,omitempty" example:"2025-12-06T01:00:05Z"`
+	Total      int              `json:"total" example:"2"`
+	Completed  int              `json:"completed" example:"1"`
+	Current    string           `json:"current,omitempty" example:"drivers.fleetId_createdAt"`
+	Created    []string         `json:"created"`
+	Failed     []IndexSyncError `json:"failed"`
+	Skipped    []string         `json:"skipped"`
+}
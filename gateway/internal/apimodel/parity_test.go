@@ -0,0 +1,307 @@
+package apimodel
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the driver service snapshot from its Swagger document")
+
+const (
+	snapshotPath = "testdata/driver-service.json"
+	// driverServiceSwagger is only there in a checkout of the whole repository
+	driverServiceSwagger = "../../../driver-service/docs/swagger.json"
+	gatewaySwagger       = "../../docs/swagger.json"
+	// definitionPrefix starts the driver service's definition names
+	definitionPrefix = "github_com_bitaksi_driver-service_internal_"
+)
+
+// mirrors pairs every model with the driver service definition it mirrors.
+// OnboardingRequest, UpdateLocationRequest, FareQuote and NearbyDriverWithFare
+// only exist in the gateway.
+var mirrors = []struct {
+	model    interface{}
+	upstream string
+}{
+	{Driver{}, "domain.Driver"},
+	{DriverDocument{}, "domain.DriverDocument"},
+	{DriverLicense{}, "domain.DriverLicense"},
+	{DriverPhoto{}, "domain.DriverPhoto"},
+	{KYCCheck{}, "domain.KYCCheck"},
+	{Location{}, "domain.Location"},
+	{Fleet{}, "domain.Fleet"},
+	{Rider{}, "domain.Rider"},
+	{FavoriteLocation{}, "domain.FavoriteLocation"},
+	{Trip{}, "domain.Trip"},
+	{FareEstimate{}, "domain.FareEstimate"},
+	{TaxiType{}, "taxitype.Type"},
+	{TaxiTypeCatalog{}, "taxitype.Catalog"},
+	{DriverStats{}, "domain.DriverStats"},
+	{OnlineStats{}, "domain.OnlineStats"},
+	{UtilizationDay{}, "domain.UtilizationDay"},
+	{UtilizationReport{}, "domain.UtilizationReport"},
+	{LocationAnomalyCount{}, "domain.LocationAnomalyCount"},
+	{LocationAnomalyReport{}, "domain.LocationAnomalyReport"},
+	{Earning{}, "domain.Earning"},
+	{EarningsTotals{}, "domain.EarningsTotals"},
+	{EarningsBucket{}, "domain.EarningsBucket"},
+	{Payout{}, "domain.Payout"},
+	{PayoutLine{}, "domain.PayoutLine"},
+	{WebhookSubscription{}, "domain.WebhookSubscription"},
+	{WebhookDelivery{}, "domain.WebhookDelivery"},
+	{DeviceToken{}, "domain.DeviceToken"},
+	{ScheduledShift{}, "domain.ScheduledShift"},
+	{ShiftCalendar{}, "usecase.ShiftCalendar"},
+	{Incident{}, "domain.Incident"},
+	{ListIncidentsResponse{}, "usecase.ListIncidentsResponse"},
+	{IndexReport{}, "domain.IndexReport"},
+	{IndexStatus{}, "domain.IndexStatus"},
+	{IndexSyncJob{}, "domain.IndexSyncJob"},
+	{IndexSyncError{}, "domain.IndexSyncError"},
+	{FailoverStats{}, "domain.FailoverStats"},
+	{TelemetryStats{}, "domain.TelemetryStats"},
+	{TelemetryCounts{}, "domain.TelemetryCounts"},
+	{QueryStats{}, "domain.QueryStats"},
+	{QueryBucket{}, "domain.QueryBucket"},
+	{Erasure{}, "domain.Erasure"},
+	{BulkFilter{}, "domain.BulkFilter"},
+	{DriverPatch{}, "domain.DriverPatch"},
+	{BulkUpdate{}, "domain.BulkUpdate"},
+	{Job{}, "jobrunner.Job"},
+	{JobResult{}, "jobrunner.Result"},
+	{ValidationRuleset{}, "rules.Ruleset"},
+	{ValidationRulesResponse{}, "rules.Effective"},
+	{DriverQuota{}, "domain.DriverQuota"},
+	{ListDriversResponse{}, "usecase.ListDriversResponse"},
+	{DriverChangesResponse{}, "usecase.DriverChangesResponse"},
+	{AutocompleteResponse{}, "usecase.AutocompleteResponse"},
+	{DriverSuggestion{}, "usecase.DriverSuggestion"},
+	{SearchHighlight{}, "usecase.SearchHighlight"},
+	{NearbyDriverResponse{}, "usecase.NearbyDriverResponse"},
+	{RouteSearchRequest{}, "usecase.RouteSearchRequest"},
+	{RouteDriverResponse{}, "usecase.RouteDriverResponse"},
+	{EarningsSummary{}, "usecase.EarningsSummary"},
+	{ExpiringLicense{}, "usecase.ExpiringLicense"},
+	{ExpiringLicensesResponse{}, "usecase.ExpiringLicensesResponse"},
+	{CreateDriverRequest{}, "usecase.CreateDriverRequest"},
+	{UpdateDriverRequest{}, "usecase.UpdateDriverRequest"},
+	{SetAvailabilityRequest{}, "usecase.SetAvailabilityRequest"},
+	{SetSuspensionRequest{}, "usecase.SetSuspensionRequest"},
+	{IssueDeviceTokenRequest{}, "usecase.IssueDeviceTokenRequest"},
+	{ScheduleShiftRequest{}, "usecase.ScheduleShiftRequest"},
+	{ReportIncidentRequest{}, "usecase.ReportIncidentRequest"},
+	{IncidentStatusRequest{}, "usecase.IncidentStatusRequest"},
+	{CreateWebhookRequest{}, "usecase.CreateWebhookRequest"},
+	{VerifyPhoneRequest{}, "usecase.VerifyPhoneRequest"},
+	{CreateFleetRequest{}, "usecase.CreateFleetRequest"},
+	{AssignFleetRequest{}, "usecase.AssignFleetRequest"},
+	{RegisterRiderRequest{}, "usecase.RegisterRiderRequest"},
+	{UpdateRiderRequest{}, "usecase.UpdateRiderRequest"},
+	{FavoriteLocationRequest{}, "usecase.FavoriteLocationRequest"},
+	{CreateTripRequest{}, "usecase.CreateTripRequest"},
+	{EstimateFareRequest{}, "usecase.EstimateFareRequest"},
+	{TripOfferRequest{}, "usecase.TripOfferRequest"},
+	{CompleteTripRequest{}, "usecase.CompleteTripRequest"},
+	{UploadDocumentRequest{}, "usecase.UploadDocumentRequest"},
+	{CreateAdjustmentRequest{}, "usecase.CreateAdjustmentRequest"},
+	{CreatePayoutRequest{}, "usecase.CreatePayoutRequest"},
+	{ExportRequest{}, "usecase.ExportRequest"},
+	{BulkUpdateRequest{}, "usecase.BulkUpdateRequest"},
+}
+
+// queries pairs every query model with the driver service operation it is forwarded to
+var queries = []struct {
+	model     interface{}
+	operation string
+}{
+	{ListDriversQuery{}, "GET /drivers"},
+}
+
+// snapshot is the part of the driver service's Swagger document the models mirror
+type snapshot struct {
+	// Definitions maps a definition to the JSON types of its properties
+	Definitions map[string]map[string]string `json:"definitions"`
+	// Queries maps an operation to its query parameters
+	Queries map[string][]string `json:"queries"`
+}
+
+// swaggerDoc is the subset of a Swagger 2.0 document the snapshot is taken from
+type swaggerDoc struct {
+	Paths       map[string]map[string]swaggerOperation `json:"paths"`
+	Definitions map[string]swaggerSchema               `json:"definitions"`
+}
+
+type swaggerOperation struct {
+	Parameters []struct {
+		Name string `json:"name"`
+		In   string `json:"in"`
+	} `json:"parameters"`
+}
+
+type swaggerSchema struct {
+	Type       string                   `json:"type"`
+	Ref        string                   `json:"$ref"`
+	AllOf      []swaggerSchema          `json:"allOf"`
+	Properties map[string]swaggerSchema `json:"properties"`
+	// AdditionalProperties is a schema or a boolean
+	AdditionalProperties json.RawMessage `json:"additionalProperties"`
+}
+
+func readSwagger(t *testing.T, path string) *swaggerDoc {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var doc swaggerDoc
+	require.NoError(t, json.Unmarshal(data, &doc))
+	return &doc
+}
+
+// takeSnapshot extracts the mirrored definitions and operations from the
+// driver service's Swagger document
+func takeSnapshot(t *testing.T, doc *swaggerDoc) *snapshot {
+	snap := &snapshot{Definitions: map[string]map[string]string{}, Queries: map[string][]string{}}
+	for _, m := range mirrors {
+		def, ok := doc.Definitions[definitionPrefix+m.upstream]
+		require.True(t, ok, "the driver service has no definition %s", m.upstream)
+		props := map[string]string{}
+		for name, prop := range def.Properties {
+			props[name] = doc.jsonType(prop)
+		}
+		snap.Definitions[m.upstream] = props
+	}
+	for _, q := range queries {
+		snap.Queries[q.operation] = queryParameters(t, doc, q.operation)
+	}
+	return snap
+}
+
+// queryParameters returns the sorted query parameters of an operation such as "GET /drivers"
+func queryParameters(t *testing.T, doc *swaggerDoc, operation string) []string {
+	method, path, _ := strings.Cut(operation, " ")
+	op, ok := doc.Paths[path][strings.ToLower(method)]
+	require.True(t, ok, "no operation %s", operation)
+	names := []string{}
+	for _, param := range op.Parameters {
+		if param.In == "query" {
+			names = append(names, param.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonType is the JSON type a schema describes, following references
+func (d *swaggerDoc) jsonType(s swaggerSchema) string {
+	if len(s.AllOf) == 1 {
+		s = s.AllOf[0]
+	}
+	if s.Ref != "" {
+		return d.jsonType(d.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")])
+	}
+	if s.Type == "" && (s.Properties != nil || s.AdditionalProperties != nil) {
+		return "object"
+	}
+	return s.Type
+}
+
+// modelTypes returns the JSON types of a model's fields by their JSON names
+func modelTypes(t reflect.Type) map[string]string {
+	props := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" {
+			// Embedded structs are flattened into the JSON object
+			for embedded, kind := range modelTypes(field.Type) {
+				props[embedded] = kind
+			}
+			continue
+		}
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		props[name] = goJSONType(field.Type)
+	}
+	return props
+}
+
+// goJSONType is the JSON type a Go type encodes to
+func goJSONType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return goJSONType(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func TestDriverServiceParity(t *testing.T) {
+	if *update {
+		snap := takeSnapshot(t, readSwagger(t, driverServiceSwagger))
+		data, err := json.MarshalIndent(snap, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(snapshotPath, append(data, '\n'), 0o644))
+	}
+
+	data, err := os.ReadFile(snapshotPath)
+	require.NoError(t, err)
+	var snap snapshot
+	require.NoError(t, json.Unmarshal(data, &snap))
+
+	t.Run("snapshot is current", func(t *testing.T) {
+		if _, err := os.Stat(driverServiceSwagger); err != nil {
+			t.Skip("the driver service is not checked out next to the gateway")
+		}
+		current := takeSnapshot(t, readSwagger(t, driverServiceSwagger))
+		assert.Equal(t, current, &snap, "the driver service changed; run go generate ./internal/apimodel")
+	})
+
+	for _, m := range mirrors {
+		model := reflect.TypeOf(m.model)
+		t.Run(model.Name(), func(t *testing.T) {
+			upstream, ok := snap.Definitions[m.upstream]
+			require.True(t, ok, "%s is not in the snapshot; run go generate ./internal/apimodel", m.upstream)
+			assert.Equal(t, upstream, modelTypes(model), "%s drifted from %s", model.Name(), m.upstream)
+		})
+	}
+
+	gateway := readSwagger(t, gatewaySwagger)
+	for _, q := range queries {
+		model := reflect.TypeOf(q.model)
+		t.Run(model.Name(), func(t *testing.T) {
+			var params []string
+			for i := 0; i < model.NumField(); i++ {
+				params = append(params, model.Field(i).Tag.Get("form"))
+			}
+			sort.Strings(params)
+			assert.Equal(t, snap.Queries[q.operation], params, "%s drifted from the driver service's %s", model.Name(), q.operation)
+			assert.Equal(t, params, queryParameters(t, gateway, q.operation), "the gateway's %s documents other parameters", q.operation)
+		})
+	}
+}
+
+func TestListDriversQuery_Values(t *testing.T) {
+	assert.Empty(t, ListDriversQuery{}.Values())
+	assert.Equal(t, "fleetId=f1&page=2", ListDriversQuery{Page: "2", FleetID: "f1"}.Values().Encode())
+}
@@ -0,0 +1,37 @@
+package apimodel
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// ListDriversQuery is the pagination and filtering of a driver list. Fields are
+// forwarded as given and validated by the driver service; a filter the driver
+// service gains only needs a field here to reach it.
+type ListDriversQuery struct {
+	Page     string `form:"page"`
+	PageSize string `form:"pageSize"`
+	FleetID  string `form:"fleetId"`
+	City     string `form:"city"`
+	// Tags and Attributes are comma-separated, e.g. pet-friendly and language:en
+	Tags       string `form:"tags"`
+	Attributes string `form:"attributes"`
+}
+
+// Values returns the query's non-empty fields keyed by their parameter names
+func (q ListDriversQuery) Values() url.Values {
+	return queryValues(q)
+}
+
+// queryValues collects the non-empty string fields of a query struct by their form tags
+func queryValues(query interface{}) url.Values {
+	values := url.Values{}
+	v := reflect.ValueOf(query)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("form")
+		if value := v.Field(i).String(); name != "" && value != "" {
+			values.Set(name, value)
+		}
+	}
+	return values
+}
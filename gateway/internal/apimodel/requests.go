@@ -0,0 +1,232 @@
+package apimodel
+
+// CreateDriverRequest represents the request to create a driver
+type CreateDriverRequest struct {
+	FirstName string  `json:"firstName" example:"Ahmet" binding:"required"`
+	LastName  string  `json:"lastName" example:"Demir" binding:"required"`
+	Plate     string  `json:"plate" example:"34ABC123" binding:"required"`
+	TaxiType  string  `json:"taksiType" example:"sari" binding:"required"`
+	CarBrand  string  `json:"carBrand" example:"Toyota" binding:"required"`
+	CarModel  string  `json:"carModel" example:"Corolla" binding:"required"`
+	Lat       float64 `json:"lat" example:"41.0431" binding:"required"`
+	Lon       float64 `json:"lon" example:"29.0099" binding:"required"`
+	Phone     string  `json:"phone,omitempty" example:"+905321234567"`
+	Email     string  `json:"email,omitempty" example:"ahmet.demir@example.com"`
+	// FleetID places the driver in a fleet; fleet admins always create drivers in their own fleet
+	FleetID string         `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	License *DriverLicense `json:"license,omitempty"`
+	// Tags are at most 20 lowercase words joined by dashes, e.g. pet-friendly
+	Tags []string `json:"tags,omitempty" example:"pet-friendly,wheelchair-accessible"`
+	// Attributes are at most 20 keys, named like tags, with values of up to 64 characters
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// UpdateDriverRequest represents the request to update a driver
+type UpdateDriverRequest struct {
+	FirstName *string  `json:"firstName,omitempty" example:"Ali"`
+	LastName  *string  `json:"lastName,omitempty" example:"Kurt"`
+	Plate     *string  `json:"plate,omitempty" example:"34G1234"`
+	TaxiType  *string  `json:"taksiType,omitempty" example:"siyah"`
+	CarBrand  *string  `json:"carBrand,omitempty" example:"Mercedes"`
+	CarModel  *string  `json:"carModel,omitempty" example:"G Class"`
+	Lat       *float64 `json:"lat,omitempty" example:"42.0082"`
+	Lon       *float64 `json:"lon,omitempty" example:"28.9784"`
+	Phone     *string  `json:"phone,omitempty" example:"+905329876543"`
+	Email     *string  `json:"email,omitempty" example:"ali.kurt@example.com"`
+	// License replaces the driver's licence; a valid one clears the expired flag
+	License *DriverLicense `json:"license,omitempty"`
+	// Tags replaces the driver's tags; an empty list removes them
+	Tags []string `json:"tags,omitempty" example:"pet-friendly"`
+	// Attributes replaces the driver's attributes; an empty object removes them
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// SetAvailabilityRequest represents the request to go on or off shift
+type SetAvailabilityRequest struct {
+	Available bool `json:"available" example:"true" binding:"required"`
+}
+
+// UpdateLocationRequest represents a position report of a driver app
+type UpdateLocationRequest struct {
+	Lat *float64 `json:"lat" example:"41.0431" binding:"required"`
+	Lon *float64 `json:"lon" example:"29.0099" binding:"required"`
+}
+
+// IssueDeviceTokenRequest represents the request to issue a device token
+type IssueDeviceTokenRequest struct {
+	DeviceID string `json:"deviceId" example:"a1b2c3d4-e5f6-7890-abcd-ef1234567890" binding:"required"`
+	Name     string `json:"name,omitempty" example:"Mehmet's Android"`
+}
+
+// ReportIncidentRequest represents the request to report an incident against a driver
+type ReportIncidentRequest struct {
+	Category    string `json:"category" example:"conduct" binding:"required"`
+	Severity    string `json:"severity" example:"medium" binding:"required"`
+	Description string `json:"description" example:"Driver was on the phone during the whole ride" binding:"required"`
+	// TripID links the trip the incident happened on; riders must give one of their trips
+	TripID string `json:"tripId,omitempty" example:"6571f1f77bcf86cd79943901"`
+}
+
+// IncidentStatusRequest represents the request to move an incident's investigation on
+type IncidentStatusRequest struct {
+	Status string `json:"status" example:"resolved" binding:"required"`
+	// Outcome is required when resolving: upheld or dismissed
+	Outcome string `json:"outcome,omitempty" example:"dismissed"`
+	Note    string `json:"note,omitempty" example:"Rider confirmed the driver used a hands-free set"`
+	// Severity confirms or corrects the reported severity when moving to
+	// investigating; a confirmed high or critical severity takes effect then
+	Severity string `json:"severity,omitempty" example:"high"`
+}
+
+// ScheduleShiftRequest represents the request to plan or move a shift
+type ScheduleShiftRequest struct {
+	StartsAt string `json:"startsAt" example:"2025-12-08T06:00:00Z" binding:"required"`
+	EndsAt   string `json:"endsAt" example:"2025-12-08T14:00:00Z" binding:"required"`
+	Note     string `json:"note,omitempty" example:"Airport rank"`
+}
+
+// SetSuspensionRequest represents the request to suspend or reinstate a driver
+type SetSuspensionRequest struct {
+	Suspended bool   `json:"suspended" example:"true" binding:"required"`
+	Reason    string `json:"reason,omitempty" example:"expired license"`
+}
+
+// CreateWebhookRequest represents the request to register a webhook endpoint
+type CreateWebhookRequest struct {
+	URL string `json:"url" example:"https://partner.example.com/hooks/bitaksi" binding:"required"`
+	// Secret signs deliveries; one is generated when empty
+	Secret string `json:"secret,omitempty" example:"whsec_3f9a1c0d7e2b4a6c8e0f1a3b5c7d9e1f"`
+	// Events filters the event types to deliver; empty delivers every event
+	Events []string `json:"events,omitempty" example:"driver.created,driver.suspended"`
+	// FleetID limits the subscription to one fleet; fleet admins always subscribe to their own fleet
+	FleetID     string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Description string `json:"description,omitempty" example:"Kadıköy Taksi dispatch system"`
+}
+
+// VerifyPhoneRequest represents the request to confirm a phone verification code
+type VerifyPhoneRequest struct {
+	Code string `json:"code" example:"123456" binding:"required"`
+}
+
+// CreateFleetRequest represents the request to create a fleet
+type CreateFleetRequest struct {
+	Name        string `json:"name" example:"Kadıköy Taksi" binding:"required"`
+	CompanyName string `json:"companyName,omitempty" example:"Kadıköy Taksi Ltd. Şti."`
+}
+
+// AssignFleetRequest moves a driver into a fleet; an empty fleetId removes the driver from its fleet
+type AssignFleetRequest struct {
+	FleetID string `json:"fleetId" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+}
+
+// RegisterRiderRequest represents the request to register a rider
+type RegisterRiderRequest struct {
+	FirstName string `json:"firstName" example:"Elif" binding:"required"`
+	LastName  string `json:"lastName" example:"Yılmaz" binding:"required"`
+	Phone     string `json:"phone" example:"+905551234567" binding:"required"`
+	Email     string `json:"email,omitempty" example:"elif@example.com"`
+}
+
+// RiderSignInCodeRequest represents the request to text a rider a sign-in code
+type RiderSignInCodeRequest struct {
+	Phone string `json:"phone" example:"+905551234567" binding:"required"`
+}
+
+// RiderSignInRequest represents the request to sign a rider in with a texted code
+type RiderSignInRequest struct {
+	Phone string `json:"phone" example:"+905551234567" binding:"required"`
+	Code  string `json:"code" example:"123456" binding:"required"`
+}
+
+// UpdateRiderRequest represents a partial rider profile update
+type UpdateRiderRequest struct {
+	FirstName *string `json:"firstName,omitempty" example:"Elif"`
+	LastName  *string `json:"lastName,omitempty" example:"Kaya"`
+	Phone     *string `json:"phone,omitempty" example:"+905557654321"`
+	Email     *string `json:"email,omitempty" example:"elif.kaya@example.com"`
+}
+
+// FavoriteLocationRequest represents a place to save for a rider
+type FavoriteLocationRequest struct {
+	Label    string   `json:"label" example:"Home" binding:"required"`
+	Address  string   `json:"address,omitempty" example:"Caferağa Mah. Moda Cad. No:1, Kadıköy"`
+	Location Location `json:"location"`
+}
+
+// CreateTripRequest represents a rider's request for a taxi. Riders always
+// request trips for themselves; riderId is filled in from their token.
+type CreateTripRequest struct {
+	RiderID  string    `json:"riderId,omitempty" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	Pickup   Location  `json:"pickup" binding:"required"`
+	Dropoff  *Location `json:"dropoff,omitempty"`
+	TaxiType string    `json:"taxiType,omitempty" example:"sari"`
+}
+
+// EstimateFareRequest asks for a fare quote between two locations
+type EstimateFareRequest struct {
+	Pickup   Location `json:"pickup" binding:"required"`
+	Dropoff  Location `json:"dropoff" binding:"required"`
+	TaxiType string   `json:"taxiType,omitempty" example:"sari"`
+}
+
+// TripOfferRequest identifies the driver answering a trip offer
+type TripOfferRequest struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011" binding:"required"`
+}
+
+// CompleteTripRequest represents the request to finish a trip
+type CompleteTripRequest struct {
+	DistanceKm float64  `json:"distanceKm,omitempty" example:"7.4"`
+	Rating     *float64 `json:"rating,omitempty" example:"5"`
+	// Fare is the metered fare; when omitted the distance is priced with the tariff
+	Fare *float64 `json:"fare,omitempty" example:"232.4"`
+}
+
+// UploadDocumentRequest represents a driver document reference
+type UploadDocumentRequest struct {
+	Type      string `json:"type" example:"license" enums:"license,registration,insurance" binding:"required"`
+	Number    string `json:"number,omitempty" example:"TR-1234567"`
+	URL       string `json:"url" example:"https://files.bitaksi.com/docs/license.pdf" binding:"required"`
+	ExpiresAt string `json:"expiresAt,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+// OnboardingRequest represents the request to onboard a driver in one call
+type OnboardingRequest struct {
+	Driver    CreateDriverRequest     `json:"driver" binding:"required"`
+	Documents []UploadDocumentRequest `json:"documents,omitempty" binding:"dive"`
+	// Available puts the driver on shift once onboarded; it stays pending until the phone is verified
+	Available bool `json:"available,omitempty" example:"false"`
+}
+
+// CreateAdjustmentRequest represents a manual correction of a driver's earnings
+type CreateAdjustmentRequest struct {
+	DriverID string `json:"driverId" example:"507f1f77bcf86cd799439011" binding:"required"`
+	// Amount is credited to the driver; negative amounts deduct
+	Amount float64 `json:"amount" example:"50"`
+	Reason string  `json:"reason" example:"Toll reimbursement for trip 6571f1f77bcf86cd79943901" binding:"required"`
+}
+
+// CreatePayoutRequest selects the unpaid earnings a payout batch covers
+type CreatePayoutRequest struct {
+	// From is optional; without it every unpaid entry before To is paid out
+	From string `json:"from,omitempty" example:"2025-12-01T00:00:00+03:00"`
+	// To defaults to the start of the current day
+	To string `json:"to,omitempty" example:"2025-12-08T00:00:00+03:00"`
+}
+
+// BulkUpdateRequest represents the request to patch every driver matching a filter
+type BulkUpdateRequest struct {
+	// Filter must set at least one field; fleet admins are always limited to their own fleet
+	Filter BulkFilter  `json:"filter"`
+	Patch  DriverPatch `json:"patch"`
+	// DryRun counts the drivers the update would change without changing them
+	DryRun bool `json:"dryRun,omitempty" example:"true"`
+}
+
+// ExportRequest selects the drivers a background export covers
+type ExportRequest struct {
+	// Format is ndjson (one driver per line, the default) or json (an array)
+	Format string `json:"format" example:"ndjson" enums:"ndjson,json"`
+	// FleetID only exports drivers of this fleet; fleet admins always export their own
+	FleetID string `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+}
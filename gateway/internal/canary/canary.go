@@ -0,0 +1,212 @@
+// Package canary routes a share of driver service traffic to a second upstream
+// running a new build, and keeps separate score of how both upstreams answer.
+//
+// A request goes to the canary with the configured probability, or because
+// its caller asked for it with the X-Canary header. When the canary fails too
+// large a share of its requests within a window, all traffic falls back to the
+// primary for a cooldown before the canary gets its share again.
+package canary
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Header lets a caller pick the upstream of its request: "true" sends it to
+// the canary and "false" keeps it on the primary. Responses from the canary
+// carry it set to "true".
+const Header = "X-Canary"
+
+// Options configures a Router
+type Options struct {
+	// URL is the base URL of the canary driver service
+	URL string
+	// Percent is the share of requests sent to the canary, between 0 and 1
+	Percent float64
+	// AllowHeader lets callers pick the upstream with the X-Canary header
+	AllowHeader bool
+	// MaxErrorRate is the share of failed canary requests in a window, between
+	// 0 and 1, above which traffic falls back to the primary
+	MaxErrorRate float64
+	// MinRequests is how many canary requests a window needs before its error
+	// rate counts, so a single failure does not end the canary
+	MinRequests int
+	// Window is how long canary failures are counted before the count restarts
+	Window time.Duration
+	// Cooldown is how long traffic stays on the primary after a fallback
+	Cooldown time.Duration
+}
+
+// UpstreamStats counts the requests one upstream answered
+type UpstreamStats struct {
+	Requests int64 `json:"requests" example:"48210"`
+	// Failures counts transport errors, timeouts and 5xx responses
+	Failures         int64   `json:"failures" example:"12"`
+	ErrorRatePercent float64 `json:"errorRatePercent" example:"0.02"`
+	AvgLatencyMs     float64 `json:"avgLatencyMs" example:"18.4"`
+}
+
+// Stats is a snapshot of the router
+type Stats struct {
+	Enabled bool   `json:"enabled" example:"true"`
+	URL     string `json:"url,omitempty" example:"http://driver-service-canary:8081"`
+	// Percent is the configured share of requests sent to the canary
+	Percent float64 `json:"percent" example:"5"`
+	// State is "active", or "fallback" while traffic stays on the primary
+	State         string        `json:"state,omitempty" example:"active"`
+	FallbackUntil *time.Time    `json:"fallbackUntil,omitempty" example:"2025-12-06T12:05:00Z"`
+	Fallbacks     int64         `json:"fallbacks" example:"1"`
+	Primary       UpstreamStats `json:"primary"`
+	Canary        UpstreamStats `json:"canary"`
+}
+
+// upstreamCounts are the running totals of one upstream
+type upstreamCounts struct {
+	requests int64
+	failures int64
+	latency  time.Duration
+}
+
+func (u upstreamCounts) stats() UpstreamStats {
+	stats := UpstreamStats{Requests: u.requests, Failures: u.failures}
+	if u.requests > 0 {
+		stats.ErrorRatePercent = math.Round(float64(u.failures)/float64(u.requests)*10000) / 100
+		stats.AvgLatencyMs = math.Round(float64(u.latency)/float64(u.requests)/float64(time.Millisecond)*10) / 10
+	}
+	return stats
+}
+
+// Router decides which upstream each request goes to
+type Router struct {
+	opts Options
+	now  func() time.Time
+	rand func() float64
+
+	mu             sync.Mutex
+	primary        upstreamCounts
+	canary         upstreamCounts
+	windowStart    time.Time
+	windowRequests int
+	windowFailures int
+	fallbackUntil  time.Time
+	fallbacks      int64
+}
+
+// New creates a router. A percent outside [0, 1] is clamped, an error rate
+// outside (0, 1] falls back to 0.05 and a window or cooldown that is not
+// positive to a minute and five minutes.
+func New(opts Options) *Router {
+	opts.URL = strings.TrimRight(opts.URL, "/")
+	opts.Percent = math.Max(0, math.Min(1, opts.Percent))
+	if opts.MaxErrorRate <= 0 || opts.MaxErrorRate > 1 {
+		opts.MaxErrorRate = 0.05
+	}
+	if opts.MinRequests < 1 {
+		opts.MinRequests = 1
+	}
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 5 * time.Minute
+	}
+	return &Router{
+		opts: opts,
+		now:  time.Now,
+		rand: rand.Float64,
+	}
+}
+
+// URL returns the base URL of the canary
+func (r *Router) URL() string {
+	return r.opts.URL
+}
+
+// Pick reports whether a request goes to the canary. header is the request's
+// X-Canary value, which decides when callers may pick; otherwise the request
+// joins the canary's share of traffic. Nothing goes to the canary during a
+// fallback, whatever the header says.
+func (r *Router) Pick(header string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.now().Before(r.fallbackUntil) {
+		return false
+	}
+	if r.opts.AllowHeader {
+		switch strings.ToLower(strings.TrimSpace(header)) {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+	}
+	return r.opts.Percent > 0 && r.rand() < r.opts.Percent
+}
+
+// Observe records how an upstream answered a request. Too many canary
+// failures within the window start a fallback, which it reports.
+func (r *Router) Observe(canary bool, elapsed time.Duration, failed bool) (fellBack bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := &r.primary
+	if canary {
+		counts = &r.canary
+	}
+	counts.requests++
+	counts.latency += elapsed
+	if failed {
+		counts.failures++
+	}
+	now := r.now()
+	if !canary || now.Before(r.fallbackUntil) {
+		// Canary requests still in flight when it fell back do not count
+		// towards the window after the cooldown
+		return false
+	}
+
+	if now.Sub(r.windowStart) >= r.opts.Window {
+		r.windowStart = now
+		r.windowRequests, r.windowFailures = 0, 0
+	}
+	r.windowRequests++
+	if failed {
+		r.windowFailures++
+	}
+	if r.windowRequests < r.opts.MinRequests ||
+		float64(r.windowFailures)/float64(r.windowRequests) <= r.opts.MaxErrorRate {
+		return false
+	}
+	r.fallbackUntil = now.Add(r.opts.Cooldown)
+	r.fallbacks++
+	// The canary starts over with a clean window once the cooldown ends
+	r.windowStart = r.fallbackUntil
+	r.windowRequests, r.windowFailures = 0, 0
+	return true
+}
+
+// Stats returns a snapshot of the router
+func (r *Router) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := Stats{
+		Enabled:   true,
+		URL:       r.opts.URL,
+		Percent:   r.opts.Percent * 100,
+		State:     "active",
+		Fallbacks: r.fallbacks,
+		Primary:   r.primary.stats(),
+		Canary:    r.canary.stats(),
+	}
+	if r.now().Before(r.fallbackUntil) {
+		until := r.fallbackUntil.UTC()
+		stats.State = "fallback"
+		stats.FallbackUntil = &until
+	}
+	return stats
+}
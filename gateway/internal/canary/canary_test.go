@@ -0,0 +1,66 @@
+package canary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_Pick(t *testing.T) {
+	r := New(Options{URL: "http://canary:8081/", Percent: 0.05, AllowHeader: true})
+	assert.Equal(t, "http://canary:8081", r.URL())
+
+	r.rand = func() float64 { return 0.04 }
+	assert.True(t, r.Pick(""), "inside the canary's share")
+	assert.False(t, r.Pick("false"), "the header keeps a request on the primary")
+	r.rand = func() float64 { return 0.5 }
+	assert.False(t, r.Pick(""))
+	assert.True(t, r.Pick("TRUE"), "the header sends a request to the canary")
+	assert.False(t, r.Pick("yes"), "other values leave it to the share")
+
+	noHeader := New(Options{URL: "http://canary:8081", Percent: 0})
+	assert.False(t, noHeader.Pick("true"), "the header is ignored unless allowed")
+}
+
+func TestRouter_Fallback(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	r := New(Options{URL: "http://canary:8081", Percent: 1, AllowHeader: true, MaxErrorRate: 0.2, MinRequests: 5, Window: time.Minute, Cooldown: 5 * time.Minute})
+	r.now = func() time.Time { return now }
+
+	// Failures of the primary never end the canary
+	for i := 0; i < 10; i++ {
+		assert.False(t, r.Observe(false, 10*time.Millisecond, true))
+	}
+	// A failure below MinRequests does not count yet
+	assert.False(t, r.Observe(true, 20*time.Millisecond, true))
+	for i := 0; i < 3; i++ {
+		assert.False(t, r.Observe(true, 20*time.Millisecond, false))
+	}
+	assert.True(t, r.Observe(true, 20*time.Millisecond, true), "2 of 5 failed")
+
+	assert.False(t, r.Pick("true"), "nothing goes to the canary during a fallback")
+	stats := r.Stats()
+	assert.Equal(t, "fallback", stats.State)
+	assert.Equal(t, int64(1), stats.Fallbacks)
+	assert.Equal(t, now.Add(5*time.Minute), *stats.FallbackUntil)
+	assert.Equal(t, UpstreamStats{Requests: 10, Failures: 10, ErrorRatePercent: 100, AvgLatencyMs: 10}, stats.Primary)
+	assert.Equal(t, UpstreamStats{Requests: 5, Failures: 2, ErrorRatePercent: 40, AvgLatencyMs: 20}, stats.Canary)
+
+	// Late canary failures do not start another fallback or carry over
+	assert.False(t, r.Observe(true, time.Millisecond, true))
+	now = now.Add(5 * time.Minute)
+	assert.True(t, r.Pick(""), "the canary gets its share back after the cooldown")
+	assert.Equal(t, "active", r.Stats().State)
+	for i := 0; i < 4; i++ {
+		assert.False(t, r.Observe(true, time.Millisecond, false))
+	}
+	assert.False(t, r.Observe(true, time.Millisecond, true), "1 of 5 failed")
+
+	// Counting restarts with every window
+	now = now.Add(time.Minute)
+	for i := 0; i < 4; i++ {
+		assert.False(t, r.Observe(true, time.Millisecond, false))
+	}
+	assert.False(t, r.Observe(true, time.Millisecond, true))
+}
@@ -0,0 +1,173 @@
+// Package coalesce shares upstream responses between identical requests.
+//
+// Concurrent requests with the same key wait for a single upstream call and
+// receive its response. Successful responses are then kept for a short TTL,
+// so a burst of identical queries (e.g. riders in one area searching for
+// nearby drivers) reaches the upstream service once per TTL at most.
+package coalesce
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// maxEntries triggers a sweep of expired entries when the cache grows past it
+const maxEntries = 10000
+
+// Outcome tells how a response was obtained
+type Outcome string
+
+// Outcomes reported by Group.Do
+const (
+	// Miss means the caller made the upstream call itself
+	Miss Outcome = "MISS"
+	// Shared means one upstream call answered concurrent identical calls, this one included
+	Shared Outcome = "SHARED"
+	// Hit means the response came from the micro-cache
+	Hit Outcome = "HIT"
+)
+
+// Response is a buffered upstream response
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type entry struct {
+	response  *Response
+	expiresAt time.Time
+}
+
+// Group coalesces and briefly caches upstream calls by key
+type Group struct {
+	ttl    time.Duration
+	flight singleflight.Group
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+
+	hits   atomic.Int64
+	shared atomic.Int64
+	misses atomic.Int64
+}
+
+// Stats counts how calls were answered since the group was created
+type Stats struct {
+	// Entries is how many responses are cached, expired ones not yet swept included
+	Entries int   `json:"entries" example:"412"`
+	Hits    int64 `json:"hits" example:"90412"`
+	Shared  int64 `json:"shared" example:"5120"`
+	Misses  int64 `json:"misses" example:"20311"`
+	// HitRate is the share of calls answered without an upstream call of their own
+	HitRate float64 `json:"hitRate" example:"0.82"`
+}
+
+// New creates a group that caches successful responses for ttl; a ttl of 0
+// only coalesces concurrent calls
+func New(ttl time.Duration) *Group {
+	return &Group{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Do returns the response for key, calling fetch only when no identical call
+// is in flight and no fresh response is cached. Only 2xx responses are cached;
+// errors and other statuses are shared with concurrent callers only.
+func (g *Group) Do(key string, fetch func() (*http.Response, error)) (*Response, Outcome, error) {
+	if response, ok := g.lookup(key); ok {
+		g.hits.Add(1)
+		return response, Hit, nil
+	}
+
+	value, err, shared := g.flight.Do(key, func() (interface{}, error) {
+		resp, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		response := &Response{StatusCode: resp.StatusCode, Header: resp.Header.Clone(), Body: body}
+		if response.StatusCode >= 200 && response.StatusCode < 300 {
+			g.store(key, response)
+		}
+		return response, nil
+	})
+
+	outcome := Miss
+	if shared {
+		outcome = Shared
+		g.shared.Add(1)
+	} else {
+		g.misses.Add(1)
+	}
+	if err != nil {
+		return nil, outcome, err
+	}
+	return value.(*Response), outcome, nil
+}
+
+// Stats returns the cache size and how calls were answered
+func (g *Group) Stats() Stats {
+	g.mu.Lock()
+	entries := len(g.entries)
+	g.mu.Unlock()
+
+	stats := Stats{Entries: entries, Hits: g.hits.Load(), Shared: g.shared.Load(), Misses: g.misses.Load()}
+	if total := stats.Hits + stats.Shared + stats.Misses; total > 0 {
+		stats.HitRate = math.Round(float64(stats.Hits+stats.Shared)/float64(total)*1000) / 1000
+	}
+	return stats
+}
+
+func (g *Group) lookup(key string) (*Response, bool) {
+	if g.ttl <= 0 {
+		return nil, false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	cached, ok := g.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !g.now().Before(cached.expiresAt) {
+		delete(g.entries, key)
+		return nil, false
+	}
+	return cached.response, true
+}
+
+func (g *Group) store(key string, response *Response) {
+	if g.ttl <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if len(g.entries) >= maxEntries {
+		for k, cached := range g.entries {
+			if !now.Before(cached.expiresAt) {
+				delete(g.entries, k)
+			}
+		}
+		if len(g.entries) >= maxEntries {
+			return
+		}
+	}
+	g.entries[key] = entry{response: response, expiresAt: now.Add(g.ttl)}
+}
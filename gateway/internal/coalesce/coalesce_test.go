@@ -0,0 +1,102 @@
+package coalesce
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestGroup_CoalescesConcurrentCalls(t *testing.T) {
+	group := New(0)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (*http.Response, error) {
+		calls.Add(1)
+		<-release
+		return response(http.StatusOK, `[]`), nil
+	}
+
+	var wg sync.WaitGroup
+	outcomes := make([]Outcome, 5)
+	for i := range outcomes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, outcome, err := group.Do("41.043|29.010", fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, `[]`, string(resp.Body))
+			outcomes[i] = outcome
+		}(i)
+	}
+	// Give every caller time to join the call in flight
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, outcome := range outcomes {
+		assert.Equal(t, Shared, outcome)
+	}
+
+	// Without a TTL nothing is cached
+	_, outcome, err := group.Do("41.043|29.010", func() (*http.Response, error) {
+		calls.Add(1)
+		return response(http.StatusOK, `[]`), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Miss, outcome)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestGroup_CachesSuccessfulResponses(t *testing.T) {
+	now := time.Date(2025, 12, 6, 12, 0, 0, 0, time.UTC)
+	group := New(time.Second)
+	group.now = func() time.Time { return now }
+	calls := 0
+	fetch := func(status int) func() (*http.Response, error) {
+		return func() (*http.Response, error) {
+			calls++
+			return response(status, `{}`), nil
+		}
+	}
+
+	_, outcome, err := group.Do("a", fetch(http.StatusOK))
+	require.NoError(t, err)
+	assert.Equal(t, Miss, outcome)
+
+	resp, outcome, err := group.Do("a", fetch(http.StatusOK))
+	require.NoError(t, err)
+	assert.Equal(t, Hit, outcome)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Second)
+	_, outcome, _ = group.Do("a", fetch(http.StatusOK))
+	assert.Equal(t, Miss, outcome)
+	assert.Equal(t, 2, calls)
+
+	// Errors are never cached
+	group.Do("b", fetch(http.StatusServiceUnavailable))
+	_, outcome, _ = group.Do("b", fetch(http.StatusOK))
+	assert.Equal(t, Miss, outcome)
+
+	_, _, err = group.Do("c", func() (*http.Response, error) { return nil, errors.New("connection refused") })
+	assert.Error(t, err)
+	assert.Equal(t, Stats{Entries: 2, Hits: 1, Misses: 5, HitRate: 0.167}, group.Stats())
+}
@@ -7,103 +7,1047 @@ import (
 	"time"
 )
 
-// Config holds all configuration for the gateway
+// Config holds all configuration for the gateway. Fields tagged secret are
+// redacted from Snapshot.
 type Config struct {
 	Server        ServerConfig
 	DriverService DriverServiceConfig
 	Logging       LoggingConfig
 	JWT           JWTConfig
+	Auth          AuthConfig
 	RateLimit     RateLimitConfig
 	APIKey        APIKeyConfig
+	CORS          CORSConfig
+	Compression   CompressionConfig
+	Envelope      EnvelopeConfig
+	Admin         AdminConfig
+	Maintenance   MaintenanceConfig
+	AuthPolicy    AuthPolicyConfig
+	DeviceTokens  DeviceTokenConfig
+	Tap           TapConfig
+	Usage         UsageConfig
+	SLO           SLOConfig
+	Nearby        NearbyConfig
+	Docs          DocsConfig
+	Registration  RegistrationGuardConfig
+	Upstream      UpstreamErrorsConfig
+	Validation    ResponseValidationConfig
+	Redaction     RedactionConfig
+	Fallback      FallbackConfig
+	ErrorReports  ErrorReportConfig
+	Pricing       PricingConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port         string
+	Port string
+	// AdminPort moves /health, /ready, /metrics, pprof and the /admin API to
+	// a second listener so only the public API is served on Port; empty
+	// serves everything but pprof and /metrics on Port
+	AdminPort    string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// IdleTimeout closes keep-alive connections that sit unused
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+	// MaxInFlight bounds the requests served at once; 0 disables the limit
+	MaxInFlight int
+	// OverloadRetryAfter is the Retry-After sent with 503s while saturated
+	OverloadRetryAfter time.Duration
+	// DrainTimeout bounds how long a drain waits for in-flight requests
+	DrainTimeout time.Duration
+	// TrustedProxies lists the proxy IPs and CIDRs whose X-Forwarded-For and
+	// X-Real-IP name the client; empty trusts none, so the client IP is the
+	// connection's peer address
+	TrustedProxies []string
 }
 
 // DriverServiceConfig holds driver service configuration
 type DriverServiceConfig struct {
 	BaseURL string
+	// AdminURL is where admin calls and health checks go when the driver
+	// service serves them on its ADMIN_PORT; empty uses BaseURL
+	AdminURL      string
+	AdaptiveLimit AdaptiveLimitConfig
+	Hedging       HedgingConfig
+	Canary        CanaryConfig
+	Compression   UpstreamCompressionConfig
+	Timeouts      UpstreamTimeoutsConfig
+	// ValidatePlates rejects plates that are not in the Turkish format before
+	// they reach the driver service; turn it off when the driver service's
+	// validation rules accept other formats
+	ValidatePlates bool
+}
+
+// AdaptiveLimitConfig bounds the requests in flight to the driver service with a
+// limit that shrinks while it is slow or failing and grows back as it recovers
+type AdaptiveLimitConfig struct {
+	Enabled      bool
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	// LatencyThreshold is the response time above which the limit is lowered
+	LatencyThreshold time.Duration
+	// Backoff multiplies the limit when the driver service is congested
+	Backoff float64
+}
+
+// HedgingConfig sends a second request for latency-sensitive reads the driver
+// service is slow to answer, and uses whichever answers first
+type HedgingConfig struct {
+	Enabled bool
+	// Delay is how long a read may take before it is hedged; with a
+	// Percentile it is the least the gateway waits
+	Delay time.Duration
+	// Percentile of recent response times to wait before hedging; 0 always waits Delay
+	Percentile float64
+	// Budget is the share of reads that may be hedged
+	Budget float64
+	// Targets are driver service instances hedges go to; empty uses BaseURL
+	Targets []string
+}
+
+// CanaryConfig sends a share of driver service traffic to a second instance
+// running a new build, and takes it back when the canary fails too often
+type CanaryConfig struct {
+	// URL is the canary driver service; empty turns canary routing off
+	URL string
+	// Percent is the share of requests sent to the canary
+	Percent float64
+	// AllowHeader lets callers send a request to the canary, or keep it on the
+	// primary, with the X-Canary header
+	AllowHeader bool
+	// MaxErrorRate is the share of failed canary requests within a window
+	// above which all traffic falls back to the primary
+	MaxErrorRate float64
+	// MinRequests is how many canary requests a window needs before its error rate counts
+	MinRequests int
+	Window      time.Duration
+	// Cooldown is how long traffic stays on the primary after a fallback
+	Cooldown time.Duration
+}
+
+// UpstreamCompressionConfig gzips traffic between the gateway and the driver
+// service. Turn it off where CPU is scarcer than bandwidth, e.g. when both run
+// on the same host.
+type UpstreamCompressionConfig struct {
+	// Enabled asks for compressed responses and compresses request bodies once
+	// the driver service advertised that it accepts them
+	Enabled bool
+	// MinSize is the smallest request body in bytes that is compressed
+	MinSize int
+}
+
+// UpstreamTimeoutsConfig bounds how long a driver service call may take, by
+// the class of endpoint it is for; a call over its timeout is answered with
+// 504 UPSTREAM_TIMEOUT. Zero leaves a class without a timeout.
+type UpstreamTimeoutsConfig struct {
+	// Nearby covers the nearby and along-route searches riders wait on
+	Nearby time.Duration
+	// Default covers driver, rider, trip and fleet reads and writes
+	Default time.Duration
+	// Bulk covers file transfers, document uploads and export downloads, and
+	// bulk driver updates
+	Bulk time.Duration
+	// Admin covers admin calls and health checks
+	Admin time.Duration
 }
 
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level string
+	// Encoding is "json" or "console"; empty picks console for debug and json otherwise
+	Encoding string
+	// Outputs are "stdout", "stderr" or file paths; files are rotated
+	Outputs []string
+	// Levels overrides the level of named loggers, e.g. "mongodb" -> "debug"
+	Levels   map[string]string
+	Rotation LogRotationConfig
+}
+
+// LogRotationConfig controls rotation of file log outputs
+type LogRotationConfig struct {
+	MaxSizeMB int
+	// MaxAgeDays and MaxBackups of 0 keep rotated files forever
+	MaxAgeDays int
+	MaxBackups int
+	Compress   bool
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret     string
+	Secret     string `secret:"true"`
 	Expiration time.Duration
 	Enabled    bool
+	// Algorithm is "HS256" (shared secret) or "RS256" (keys published via JWKS)
+	Algorithm string
+	// RSAKeys are PEM private keys by key ID; all are published, ActiveKeyID signs new tokens
+	RSAKeys     []KeyFile
+	ActiveKeyID string
+	// AcceptHS256 keeps accepting secret-signed tokens in RS256 mode while clients migrate
+	AcceptHS256 bool
+	// Issuer is set as "iss" on issued tokens and, when non-empty, required on validated ones
+	Issuer string
+	// Audience is set as "aud" on issued tokens; validated tokens must name at least one entry
+	Audience []string
+	// ClockSkew is the leeway applied to "exp", "nbf" and "iat"
+	ClockSkew time.Duration
+	// ReplayProtection rejects a "jti" that was already used within ReplayWindow
+	ReplayProtection bool
+	ReplayWindow     time.Duration
+}
+
+// AuthConfig holds login settings
+type AuthConfig struct {
+	// FleetAdmins maps usernames to the fleet they administer; their tokens carry
+	// the fleet_admin role and can only manage drivers of that fleet
+	FleetAdmins map[string]string
+	OIDC        OIDCConfig
+	// UsersFile is a JSON file of password accounts; when set, logins are
+	// checked against it and passwords can be reset by email. Empty accepts any
+	// username and password.
+	UsersFile     string
+	PasswordReset PasswordResetConfig
+	TokenExchange TokenExchangeConfig
+}
+
+// TokenExchangeConfig controls the tokens internal services exchange access
+// tokens for to call the driver service directly. It is disabled when Secret
+// is empty.
+type TokenExchangeConfig struct {
+	// Secret signs exchanged tokens; the driver service validates them with the same secret
+	Secret string `secret:"true"`
+	// TTL is the longest an exchanged token lives
+	TTL time.Duration
+}
+
+// PasswordResetConfig controls the emailed links that let users of UsersFile
+// set a new password
+type PasswordResetConfig struct {
+	// Secret signs reset tokens; a generated secret only suits a single instance
+	Secret string `secret:"true"`
+	// TokenTTL is how long a reset link works
+	TokenTTL time.Duration
+	// LinkURL is the page users open to set their password; the token is
+	// appended as the token query parameter
+	LinkURL string
+	// MaxPerEmail and MaxPerIP bound the reset requests per address and per
+	// client IP within Window; MaxPerIP also bounds confirmations
+	MaxPerEmail int
+	MaxPerIP    int
+	Window      time.Duration
+	Mail        MailConfig
+}
+
+// MailConfig selects how emails are delivered
+type MailConfig struct {
+	// Sender is "log", which only logs that an email would be sent, or "smtp"
+	Sender       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string `secret:"true"`
+}
+
+// OIDCConfig signs users in with an external OpenID Connect provider, e.g. a
+// company's SSO, using the authorization code flow with PKCE; the gateway
+// then issues its own access token. It is disabled when Issuer is empty.
+type OIDCConfig struct {
+	// Issuer is the provider's issuer URL; its metadata is discovered from
+	// Issuer + "/.well-known/openid-configuration"
+	Issuer       string
+	ClientID     string
+	ClientSecret string `secret:"true"`
+	// RedirectURL is the gateway's /auth/oidc/callback as registered with the provider
+	RedirectURL string
+	Scopes      []string
+	// UsernameClaim names the ID token claim used as the username of issued
+	// tokens; users without it are named after their subject
+	UsernameClaim string
+	// RoleClaim names the ID token claim, a string or a list such as groups,
+	// whose values are looked up in Roles
+	RoleClaim string
+	// Roles maps role claim values to gateway roles; users matching none are
+	// refused unless their username is one of FleetAdmins
+	Roles map[string]OIDCRole
+	// StateSecret signs the cookie carrying a login across the redirect; a
+	// generated secret only suits a single instance
+	StateSecret string `secret:"true"`
+	// LoginTimeout is how long a user has to sign in at the provider
+	LoginTimeout time.Duration
+	// PostLoginURL receives the issued token in its fragment as #token=...;
+	// the callback answers with JSON when empty
+	PostLoginURL string
+}
+
+// OIDCRole is the access granted to users with a role claim value
+type OIDCRole struct {
+	// Role is empty for full access or fleet_admin
+	Role string
+	// FleetID scopes a fleet_admin to their fleet
+	FleetID string
+}
+
+// KeyFile identifies a key stored on disk
+type KeyFile struct {
+	ID   string
+	Path string
 }
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Enabled  bool
+	Enabled bool
+	// Requests per Window apply to each IP without valid credentials
 	Requests int
 	Window   time.Duration
+	// AuthenticatedRequests per AuthenticatedWindow apply to each JWT subject or API key
+	AuthenticatedRequests int
+	AuthenticatedWindow   time.Duration
 }
 
 // APIKeyConfig holds API key configuration
 type APIKeyConfig struct {
 	Enabled bool
-	Keys    []string
+	Keys    []string `secret:"true"`
+}
+
+// CORSConfig holds CORS policy configuration
+type CORSConfig struct {
+	// AllowedOrigins may contain "*" or wildcard subdomain patterns such as "https://*.bitaksi.com"
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CompressionConfig holds response compression configuration
+type CompressionConfig struct {
+	Enabled bool
+	// MinSize is the smallest body in bytes that is compressed
+	MinSize int
+	// Level is a gzip level from 1 (fastest) to 9 (smallest); -1 uses the default
+	Level int
+	// ContentTypes lists compressible media types; "text/*" matches every text type
+	ContentTypes []string
+}
+
+// EnvelopeConfig controls the {data, meta, error} response envelope
+type EnvelopeConfig struct {
+	// APIKeys get the envelope unless they refuse it with X-Response-Envelope: false
+	APIKeys []string `secret:"true"`
+}
+
+// AdminConfig holds configuration for the operational /admin API
+type AdminConfig struct {
+	// Token must be sent in the X-Admin-Token header; the admin API is disabled when empty
+	Token string `secret:"true"`
+}
+
+// AuthPolicyConfig locates the route auth policy
+type AuthPolicyConfig struct {
+	// File is a JSON policy; the built-in policy applies when empty
+	File string
+}
+
+// DeviceTokenConfig controls how driver device tokens are checked
+type DeviceTokenConfig struct {
+	// CacheTTL is how long a token the driver service accepted is trusted
+	// without asking again, and so how long a revoked token may keep working
+	CacheTTL time.Duration
+}
+
+// TapConfig limits the debug taps that capture proxied traffic
+type TapConfig struct {
+	BufferSize   int
+	MaxBodyBytes int
+	MaxTTL       time.Duration
+	Retention    time.Duration
+}
+
+// UsageConfig holds usage metering configuration
+type UsageConfig struct {
+	Enabled bool
+	// StorePath is the JSON file usage is persisted to; counts are kept in memory only when empty
+	StorePath     string
+	FlushInterval time.Duration
+}
+
+// SLOConfig holds the service level objectives of critical routes
+type SLOConfig struct {
+	Enabled    bool
+	Objectives []SLOObjective
+	// Windows are the rolling windows error budget burn is reported over; the
+	// longest one is the budget period
+	Windows []time.Duration
+}
+
+// SLOObjective is the service level objective of one route
+type SLOObjective struct {
+	// Name labels the objective's series, e.g. "nearby"
+	Name   string
+	Method string
+	// Route is the route as registered, e.g. "/drivers/:id"
+	Route string
+	// Availability is the share of requests to answer without a 5xx, e.g. 0.999
+	Availability float64
+	// LatencyThreshold is how fast successful requests should be answered; zero
+	// leaves latency without an objective
+	LatencyThreshold time.Duration
+	// Latency is the share of successful requests to answer within LatencyThreshold
+	Latency float64
+}
+
+// NearbyConfig holds coalescing of GET /drivers/nearby searches
+type NearbyConfig struct {
+	Coalesce bool
+	// Precision is the number of decimal places coordinates are rounded to; 3 is about 100m
+	Precision int
+	// CacheTTL keeps successful responses for identical searches; 0 only coalesces concurrent ones
+	CacheTTL time.Duration
+	// MaxAge lets clients reuse a successful search through Cache-Control; 0 sends no caching headers
+	MaxAge time.Duration
+}
+
+// DocsConfig controls the Swagger UI and the merged spec
+type DocsConfig struct {
+	Enabled bool
+	// Public serves /swagger and /openapi.json without the admin token
+	Public bool
+	// InternalTags mark operations left out of the public group; they are only
+	// listed under /admin/swagger and /admin/openapi.json
+	InternalTags []string
+}
+
+// MaintenanceConfig sets the maintenance mode the gateway starts in; it can be
+// switched at runtime through /admin/maintenance
+type MaintenanceConfig struct {
+	Enabled bool
+	// Message is shown to clients while in maintenance
+	Message string
+	// RetryAfter is the Retry-After sent with maintenance 503s
+	RetryAfter time.Duration
+	// AllowAdmin keeps all /admin routes served during maintenance;
+	// /admin/maintenance itself always is
+	AllowAdmin bool
+}
+
+// RegistrationGuardConfig caps the driver and rider accounts created per
+// device fingerprint and per client IP each UTC day
+type RegistrationGuardConfig struct {
+	Enabled bool
+	// MaxPerDevice and MaxPerIP of 0 lift that cap
+	MaxPerDevice int
+	MaxPerIP     int
+	// RequireDevice rejects registrations without an X-Device-Fingerprint header
+	RequireDevice bool
+}
+
+// UpstreamErrorsConfig controls how driver service errors reach clients
+type UpstreamErrorsConfig struct {
+	// Translate replaces driver service 5xx responses with gateway errors;
+	// 4xx responses always keep their status and body
+	Translate bool
+	// Tag adds an "upstream" member naming the service and its status to errors from it
+	Tag bool
+}
+
+// ResponseValidationConfig checks successful driver service responses against
+// the models the gateway documents; responses that do not match become 502s
+type ResponseValidationConfig struct {
+	Enabled bool
+	// Routes names the routes checked as "METHOD /path", e.g. "GET /drivers/:id";
+	// empty checks every route with a model
+	Routes []string
+	// Skip names routes left unchecked
+	Skip []string
+}
+
+// RedactionConfig hides fields of driver service responses by caller role
+type RedactionConfig struct {
+	Enabled bool
+	// File is a JSON policy of fields to omit or mask by role; empty masks
+	// driver last names and plates for every caller but fleet admins and
+	// tokens without a role
+	File string
+}
+
+// FallbackConfig serves stub responses on some routes while the driver
+// service is unavailable, so clients keep working in a reduced mode
+type FallbackConfig struct {
+	Enabled bool
+	// File is a JSON file of stubs by route; empty stubs GET /drivers/nearby
+	// with an empty list
+	File string
+}
+
+// ErrorReportConfig sends errors and panics to a Sentry-compatible error tracker
+type ErrorReportConfig struct {
+	// DSN is the tracker's project DSN; empty disables reporting
+	DSN         string `secret:"true"`
+	Environment string
+	// Release defaults to the VCS revision the binary was built from
+	Release string
+	// SampleRate is the share of errors sent, from 0 to 1
+	SampleRate float64
+	// Loggers are the loggers whose error entries are reported, with the
+	// loggers below them; panics are always reported
+	Loggers []string
+	// ScrubFields are field names whose values are never sent, on top of the defaults
+	ScrubFields []string
+}
+
+// PricingConfig holds the pricing service that estimates fares for
+// GET /drivers/nearby?includeFares=true
+type PricingConfig struct {
+	// BaseURL is the pricing service; empty answers includeFares searches without fares
+	BaseURL string
+	// Timeout bounds all the estimates of one search; drivers not priced by then get no fare
+	Timeout time.Duration
+	// Concurrency is how many estimates of one search are requested at once
+	Concurrency int
 }
 
 // Load loads configuration from environment variables
 func Load() *Config {
 	readTimeout, _ := strconv.Atoi(getEnv("READ_TIMEOUT_SEC", "30"))
 	writeTimeout, _ := strconv.Atoi(getEnv("WRITE_TIMEOUT_SEC", "30"))
+	idleTimeout, _ := strconv.Atoi(getEnv("IDLE_TIMEOUT_SEC", "120"))
+	maxHeaderBytes, _ := strconv.Atoi(getEnv("MAX_HEADER_BYTES", "1048576"))
+	maxInFlight, _ := strconv.Atoi(getEnv("MAX_IN_FLIGHT_REQUESTS", "1000"))
+	overloadRetryAfter, _ := strconv.Atoi(getEnv("OVERLOAD_RETRY_AFTER_SEC", "1"))
+	drainTimeout, _ := strconv.Atoi(getEnv("DRAIN_TIMEOUT_SEC", "30"))
 	jwtExpiration, _ := strconv.Atoi(getEnv("JWT_EXPIRATION_HOURS", "24"))
 	rateLimitRequests, _ := strconv.Atoi(getEnv("RATE_LIMIT_REQUESTS", "100"))
 	rateLimitWindow, _ := strconv.Atoi(getEnv("RATE_LIMIT_WINDOW_SEC", "60"))
+	rateLimitAuthRequests, _ := strconv.Atoi(getEnv("RATE_LIMIT_AUTHENTICATED_REQUESTS", "300"))
+	rateLimitAuthWindow, _ := strconv.Atoi(getEnv("RATE_LIMIT_AUTHENTICATED_WINDOW_SEC", strconv.Itoa(rateLimitWindow)))
 	jwtEnabled := getEnv("JWT_ENABLED", "true") == "true"
 	rateLimitEnabled := getEnv("RATE_LIMIT_ENABLED", "true") == "true"
 	apiKeyEnabled := getEnv("API_KEY_ENABLED", "false") == "true"
+	nearbyPrecision, _ := strconv.Atoi(getEnv("NEARBY_COALESCE_PRECISION", "3"))
+	nearbyCacheTTL, _ := strconv.Atoi(getEnv("NEARBY_CACHE_TTL_MS", "1000"))
+	nearbyMaxAge, _ := strconv.Atoi(getEnv("NEARBY_CACHE_MAX_AGE_SEC", "3"))
+	deviceTokenCacheTTL, _ := strconv.Atoi(getEnv("DEVICE_TOKEN_CACHE_TTL_SEC", "30"))
+
+	logLevel := getEnv("LOG_LEVEL", "info")
 
 	// Parse API keys from environment (comma-separated)
-	apiKeysStr := getEnv("API_KEYS", "")
-	var apiKeys []string
-	if apiKeysStr != "" {
-		keys := strings.Split(apiKeysStr, ",")
-		for _, key := range keys {
-			trimmed := strings.TrimSpace(key)
-			if trimmed != "" {
-				apiKeys = append(apiKeys, trimmed)
-			}
-		}
-	}
+	apiKeys := splitList(getEnv("API_KEYS", ""))
 
 	return &Config{
 		Server: ServerConfig{
-			Port:         getEnv("PORT", "8080"),
-			ReadTimeout:  time.Duration(readTimeout) * time.Second,
-			WriteTimeout: time.Duration(writeTimeout) * time.Second,
+			Port:               getEnv("PORT", "8080"),
+			AdminPort:          getEnv("ADMIN_PORT", ""),
+			ReadTimeout:        time.Duration(readTimeout) * time.Second,
+			WriteTimeout:       time.Duration(writeTimeout) * time.Second,
+			IdleTimeout:        time.Duration(idleTimeout) * time.Second,
+			MaxHeaderBytes:     maxHeaderBytes,
+			MaxInFlight:        maxInFlight,
+			OverloadRetryAfter: time.Duration(overloadRetryAfter) * time.Second,
+			DrainTimeout:       time.Duration(drainTimeout) * time.Second,
+			TrustedProxies:     splitList(getEnv("TRUSTED_PROXIES", "")),
 		},
 		DriverService: DriverServiceConfig{
-			BaseURL: getEnv("DRIVER_SERVICE_URL", "http://driver-service:8081"),
-		},
-		Logging: LoggingConfig{
-			Level: getEnv("LOG_LEVEL", "info"),
-		},
-		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			Expiration: time.Duration(jwtExpiration) * time.Hour,
-			Enabled:    jwtEnabled,
+			BaseURL:        getEnv("DRIVER_SERVICE_URL", "http://driver-service:8081"),
+			AdminURL:       getEnv("DRIVER_SERVICE_ADMIN_URL", ""),
+			AdaptiveLimit:  loadAdaptiveLimitConfig(),
+			Hedging:        loadHedgingConfig(),
+			Canary:         loadCanaryConfig(),
+			Compression:    loadUpstreamCompressionConfig(),
+			Timeouts:       loadUpstreamTimeoutsConfig(),
+			ValidatePlates: getEnv("VALIDATE_PLATES", "true") == "true",
 		},
+		Logging: loadLoggingConfig(logLevel),
+		JWT:     loadJWTConfig(jwtEnabled, time.Duration(jwtExpiration)*time.Hour),
+		Auth:    loadAuthConfig(),
 		RateLimit: RateLimitConfig{
-			Enabled:  rateLimitEnabled,
-			Requests: rateLimitRequests,
-			Window:   time.Duration(rateLimitWindow) * time.Second,
+			Enabled:               rateLimitEnabled,
+			Requests:              rateLimitRequests,
+			Window:                time.Duration(rateLimitWindow) * time.Second,
+			AuthenticatedRequests: rateLimitAuthRequests,
+			AuthenticatedWindow:   time.Duration(rateLimitAuthWindow) * time.Second,
 		},
 		APIKey: APIKeyConfig{
 			Enabled: apiKeyEnabled,
 			Keys:    apiKeys,
 		},
+		CORS:        loadCORSConfig(logLevel == "debug"),
+		Compression: loadCompressionConfig(),
+		Envelope:    EnvelopeConfig{APIKeys: splitList(getEnv("RESPONSE_ENVELOPE_API_KEYS", ""))},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Maintenance: loadMaintenanceConfig(),
+		AuthPolicy: AuthPolicyConfig{
+			File: getEnv("AUTH_POLICY_FILE", ""),
+		},
+		DeviceTokens: DeviceTokenConfig{
+			CacheTTL: time.Duration(deviceTokenCacheTTL) * time.Second,
+		},
+		Tap:   loadTapConfig(),
+		Usage: loadUsageConfig(),
+		SLO:   loadSLOConfig(),
+		Nearby: NearbyConfig{
+			Coalesce:  getEnv("NEARBY_COALESCE_ENABLED", "true") == "true",
+			Precision: nearbyPrecision,
+			CacheTTL:  time.Duration(nearbyCacheTTL) * time.Millisecond,
+			MaxAge:    time.Duration(nearbyMaxAge) * time.Second,
+		},
+		Docs:         loadDocsConfig(logLevel == "debug"),
+		Registration: loadRegistrationGuardConfig(),
+		Upstream: UpstreamErrorsConfig{
+			Translate: getEnv("UPSTREAM_ERROR_TRANSLATE", "true") == "true",
+			Tag:       getEnv("UPSTREAM_ERROR_TAG", "true") == "true",
+		},
+		Validation: ResponseValidationConfig{
+			Enabled: getEnv("UPSTREAM_VALIDATION_ENABLED", "false") == "true",
+			Routes:  splitList(getEnv("UPSTREAM_VALIDATION_ROUTES", "")),
+			Skip:    splitList(getEnv("UPSTREAM_VALIDATION_SKIP", "")),
+		},
+		Redaction: RedactionConfig{
+			Enabled: getEnv("RESPONSE_REDACTION_ENABLED", "true") == "true",
+			File:    getEnv("RESPONSE_REDACTION_FILE", ""),
+		},
+		Fallback: FallbackConfig{
+			Enabled: getEnv("UPSTREAM_FALLBACK_ENABLED", "true") == "true",
+			File:    getEnv("UPSTREAM_FALLBACK_FILE", ""),
+		},
+		ErrorReports: loadErrorReportConfig(),
+		Pricing:      loadPricingConfig(),
+	}
+}
+
+// loadJWTConfig loads token settings. JWT_RSA_KEYS is a comma-separated list of
+// "kid:path" entries; the active key defaults to the last one listed.
+func loadJWTConfig(enabled bool, expiration time.Duration) JWTConfig {
+	clockSkew, _ := strconv.Atoi(getEnv("JWT_CLOCK_SKEW_SEC", "30"))
+	replayWindow, _ := strconv.Atoi(getEnv("JWT_REPLAY_WINDOW_SEC", "300"))
+
+	var keys []KeyFile
+	for _, item := range splitList(getEnv("JWT_RSA_KEYS", "")) {
+		id, path, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		keys = append(keys, KeyFile{ID: strings.TrimSpace(id), Path: strings.TrimSpace(path)})
+	}
+	activeKeyID := ""
+	if len(keys) > 0 {
+		activeKeyID = keys[len(keys)-1].ID
+	}
+
+	return JWTConfig{
+		Secret:           getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+		Expiration:       expiration,
+		Enabled:          enabled,
+		Algorithm:        strings.ToUpper(getEnv("JWT_ALGORITHM", "HS256")),
+		RSAKeys:          keys,
+		ActiveKeyID:      getEnv("JWT_ACTIVE_KID", activeKeyID),
+		AcceptHS256:      getEnv("JWT_ACCEPT_HS256", "false") == "true",
+		Issuer:           getEnv("JWT_ISSUER", ""),
+		Audience:         splitList(getEnv("JWT_AUDIENCE", "")),
+		ClockSkew:        time.Duration(clockSkew) * time.Second,
+		ReplayProtection: getEnv("JWT_REPLAY_PROTECTION", "false") == "true",
+		ReplayWindow:     time.Duration(replayWindow) * time.Second,
+	}
+}
+
+// loadAuthConfig loads login settings. FLEET_ADMINS is a comma-separated list
+// of "username:fleetId" entries.
+func loadAuthConfig() AuthConfig {
+	fleetAdmins := make(map[string]string)
+	for _, item := range splitList(getEnv("FLEET_ADMINS", "")) {
+		username, fleetID, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		fleetAdmins[strings.TrimSpace(username)] = strings.TrimSpace(fleetID)
+	}
+	return AuthConfig{
+		FleetAdmins:   fleetAdmins,
+		OIDC:          loadOIDCConfig(),
+		UsersFile:     getEnv("AUTH_USERS_FILE", ""),
+		PasswordReset: loadPasswordResetConfig(),
+		TokenExchange: loadTokenExchangeConfig(),
+	}
+}
+
+// loadTokenExchangeConfig loads the token exchange settings
+func loadTokenExchangeConfig() TokenExchangeConfig {
+	ttl, err := strconv.Atoi(getEnv("TOKEN_EXCHANGE_TTL_SEC", "300"))
+	if err != nil || ttl <= 0 {
+		ttl = 300
+	}
+	return TokenExchangeConfig{
+		Secret: getEnv("TOKEN_EXCHANGE_SECRET", ""),
+		TTL:    time.Duration(ttl) * time.Second,
+	}
+}
+
+// loadPasswordResetConfig loads the password reset and email settings
+func loadPasswordResetConfig() PasswordResetConfig {
+	ttl, err := strconv.Atoi(getEnv("PASSWORD_RESET_TTL_MIN", "30"))
+	if err != nil || ttl <= 0 {
+		ttl = 30
+	}
+	maxPerEmail, err := strconv.Atoi(getEnv("PASSWORD_RESET_MAX_PER_EMAIL", "3"))
+	if err != nil || maxPerEmail <= 0 {
+		maxPerEmail = 3
+	}
+	maxPerIP, err := strconv.Atoi(getEnv("PASSWORD_RESET_MAX_PER_IP", "20"))
+	if err != nil || maxPerIP <= 0 {
+		maxPerIP = 20
+	}
+	window, err := strconv.Atoi(getEnv("PASSWORD_RESET_WINDOW_MIN", "60"))
+	if err != nil || window <= 0 {
+		window = 60
+	}
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+
+	return PasswordResetConfig{
+		Secret:      getEnv("PASSWORD_RESET_SECRET", ""),
+		TokenTTL:    time.Duration(ttl) * time.Minute,
+		LinkURL:     getEnv("PASSWORD_RESET_URL", ""),
+		MaxPerEmail: maxPerEmail,
+		MaxPerIP:    maxPerIP,
+		Window:      time.Duration(window) * time.Minute,
+		Mail: MailConfig{
+			Sender:       getEnv("MAIL_SENDER", "log"),
+			From:         getEnv("MAIL_FROM", "no-reply@bitaksi.com"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     smtpPort,
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+	}
+}
+
+// loadOIDCConfig loads the SSO provider. OIDC_ROLE_MAPPING is a comma-separated
+// list of "value:admin" and "value:fleet_admin:fleetId" entries.
+func loadOIDCConfig() OIDCConfig {
+	loginTimeout, _ := strconv.Atoi(getEnv("OIDC_LOGIN_TIMEOUT_SEC", "600"))
+
+	roles := make(map[string]OIDCRole)
+	for _, item := range splitList(getEnv("OIDC_ROLE_MAPPING", "")) {
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		value, role := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch {
+		case role == "admin":
+			roles[value] = OIDCRole{}
+		case role == "fleet_admin" && len(parts) == 3:
+			roles[value] = OIDCRole{Role: role, FleetID: strings.TrimSpace(parts[2])}
+		}
+	}
+
+	return OIDCConfig{
+		Issuer:        strings.TrimSuffix(getEnv("OIDC_ISSUER", ""), "/"),
+		ClientID:      getEnv("OIDC_CLIENT_ID", ""),
+		ClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:   getEnv("OIDC_REDIRECT_URL", ""),
+		Scopes:        splitList(getEnv("OIDC_SCOPES", "openid,email,profile")),
+		UsernameClaim: getEnv("OIDC_USERNAME_CLAIM", "email"),
+		RoleClaim:     getEnv("OIDC_ROLE_CLAIM", "groups"),
+		Roles:         roles,
+		StateSecret:   getEnv("OIDC_STATE_SECRET", ""),
+		LoginTimeout:  time.Duration(loginTimeout) * time.Second,
+		PostLoginURL:  getEnv("OIDC_POST_LOGIN_URL", ""),
+	}
+}
+
+// loadCORSConfig loads the CORS policy. Development mode defaults to a permissive
+// policy, while release mode denies cross-origin requests unless origins are configured.
+func loadCORSConfig(devMode bool) CORSConfig {
+	defaultOrigins := ""
+	defaultCredentials := "false"
+	if devMode {
+		defaultOrigins = "*"
+		defaultCredentials = "true"
+	}
+	maxAge, _ := strconv.Atoi(getEnv("CORS_MAX_AGE_SEC", "600"))
+
+	return CORSConfig{
+		AllowedOrigins:   splitList(getEnv("CORS_ALLOWED_ORIGINS", defaultOrigins)),
+		AllowedMethods:   splitList(getEnv("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,OPTIONS")),
+		AllowedHeaders:   splitList(getEnv("CORS_ALLOWED_HEADERS", "Content-Type,Content-Length,Accept-Encoding,X-CSRF-Token,Authorization,Accept,Origin,Cache-Control,X-Requested-With,X-API-Key,X-Device-Fingerprint,X-Response-Envelope,X-Consistency-Token,X-Canary")),
+		AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", defaultCredentials) == "true",
+		MaxAge:           time.Duration(maxAge) * time.Second,
+	}
+}
+
+// loadDocsConfig loads the API docs settings. Development mode serves the public
+// group to everyone, while release mode requires the admin token unless
+// SWAGGER_PUBLIC is set.
+func loadDocsConfig(devMode bool) DocsConfig {
+	defaultPublic := "false"
+	if devMode {
+		defaultPublic = "true"
+	}
+
+	return DocsConfig{
+		Enabled:      getEnv("SWAGGER_ENABLED", "true") == "true",
+		Public:       getEnv("SWAGGER_PUBLIC", defaultPublic) == "true",
+		InternalTags: splitList(getEnv("SWAGGER_INTERNAL_TAGS", "admin")),
+	}
+}
+
+// loadAdaptiveLimitConfig loads the adaptive concurrency limit on driver service calls
+func loadAdaptiveLimitConfig() AdaptiveLimitConfig {
+	initial, _ := strconv.Atoi(getEnv("UPSTREAM_LIMIT_INITIAL", "100"))
+	minLimit, _ := strconv.Atoi(getEnv("UPSTREAM_LIMIT_MIN", "10"))
+	maxLimit, _ := strconv.Atoi(getEnv("UPSTREAM_LIMIT_MAX", "500"))
+	latency, _ := strconv.Atoi(getEnv("UPSTREAM_LIMIT_LATENCY_MS", "1000"))
+	backoff, _ := strconv.ParseFloat(getEnv("UPSTREAM_LIMIT_BACKOFF", "0.9"), 64)
+
+	return AdaptiveLimitConfig{
+		Enabled:          getEnv("UPSTREAM_LIMIT_ENABLED", "true") == "true",
+		InitialLimit:     initial,
+		MinLimit:         minLimit,
+		MaxLimit:         maxLimit,
+		LatencyThreshold: time.Duration(latency) * time.Millisecond,
+		Backoff:          backoff,
+	}
+}
+
+// loadHedgingConfig loads the hedging of driver service reads
+func loadHedgingConfig() HedgingConfig {
+	delay, _ := strconv.Atoi(getEnv("HEDGE_DELAY_MS", "50"))
+	percentile, _ := strconv.ParseFloat(getEnv("HEDGE_PERCENTILE", "95"), 64)
+	budget, _ := strconv.ParseFloat(getEnv("HEDGE_BUDGET_PERCENT", "10"), 64)
+
+	return HedgingConfig{
+		Enabled:    getEnv("HEDGE_ENABLED", "false") == "true",
+		Delay:      time.Duration(delay) * time.Millisecond,
+		Percentile: percentile,
+		Budget:     budget / 100,
+		Targets:    splitList(getEnv("HEDGE_TARGETS", "")),
+	}
+}
+
+// loadCanaryConfig loads the canary routing of driver service traffic
+func loadCanaryConfig() CanaryConfig {
+	percent, _ := strconv.ParseFloat(getEnv("CANARY_PERCENT", "0"), 64)
+	maxErrorRate, _ := strconv.ParseFloat(getEnv("CANARY_MAX_ERROR_PERCENT", "5"), 64)
+	minRequests, _ := strconv.Atoi(getEnv("CANARY_MIN_REQUESTS", "20"))
+	window, _ := strconv.Atoi(getEnv("CANARY_WINDOW_SEC", "60"))
+	cooldown, _ := strconv.Atoi(getEnv("CANARY_COOLDOWN_SEC", "300"))
+
+	return CanaryConfig{
+		URL:          getEnv("CANARY_URL", ""),
+		Percent:      percent / 100,
+		AllowHeader:  getEnv("CANARY_HEADER_ENABLED", "true") == "true",
+		MaxErrorRate: maxErrorRate / 100,
+		MinRequests:  minRequests,
+		Window:       time.Duration(window) * time.Second,
+		Cooldown:     time.Duration(cooldown) * time.Second,
+	}
+}
+
+// loadUpstreamCompressionConfig loads the compression of driver service traffic
+func loadUpstreamCompressionConfig() UpstreamCompressionConfig {
+	minSize, _ := strconv.Atoi(getEnv("DRIVER_SERVICE_COMPRESSION_MIN_SIZE", "1024"))
+
+	return UpstreamCompressionConfig{
+		Enabled: getEnv("DRIVER_SERVICE_COMPRESSION", "true") == "true",
+		MinSize: minSize,
+	}
+}
+
+// loadUpstreamTimeoutsConfig loads the driver service timeouts per endpoint class
+func loadUpstreamTimeoutsConfig() UpstreamTimeoutsConfig {
+	nearby, _ := strconv.Atoi(getEnv("UPSTREAM_TIMEOUT_NEARBY_MS", "2000"))
+	defaultTimeout, _ := strconv.Atoi(getEnv("UPSTREAM_TIMEOUT_DEFAULT_MS", "5000"))
+	bulk, _ := strconv.Atoi(getEnv("UPSTREAM_TIMEOUT_BULK_MS", "120000"))
+	admin, _ := strconv.Atoi(getEnv("UPSTREAM_TIMEOUT_ADMIN_MS", "30000"))
+
+	return UpstreamTimeoutsConfig{
+		Nearby:  time.Duration(nearby) * time.Millisecond,
+		Default: time.Duration(defaultTimeout) * time.Millisecond,
+		Bulk:    time.Duration(bulk) * time.Millisecond,
+		Admin:   time.Duration(admin) * time.Millisecond,
+	}
+}
+
+// loadMaintenanceConfig loads the maintenance mode the gateway starts in
+func loadMaintenanceConfig() MaintenanceConfig {
+	retryAfter, _ := strconv.Atoi(getEnv("MAINTENANCE_RETRY_AFTER_SEC", "300"))
+
+	return MaintenanceConfig{
+		Enabled:    getEnv("MAINTENANCE_MODE", "false") == "true",
+		Message:    getEnv("MAINTENANCE_MESSAGE", ""),
+		RetryAfter: time.Duration(retryAfter) * time.Second,
+		AllowAdmin: getEnv("MAINTENANCE_ALLOW_ADMIN", "true") == "true",
+	}
+}
+
+func loadRegistrationGuardConfig() RegistrationGuardConfig {
+	maxPerDevice, _ := strconv.Atoi(getEnv("REGISTRATION_MAX_PER_DEVICE", "3"))
+	maxPerIP, _ := strconv.Atoi(getEnv("REGISTRATION_MAX_PER_IP", "20"))
+
+	return RegistrationGuardConfig{
+		Enabled:       getEnv("REGISTRATION_GUARD_ENABLED", "true") == "true",
+		MaxPerDevice:  maxPerDevice,
+		MaxPerIP:      maxPerIP,
+		RequireDevice: getEnv("REGISTRATION_REQUIRE_DEVICE", "false") == "true",
+	}
+}
+
+// loadTapConfig loads the ring buffer size and limits for debug taps
+func loadTapConfig() TapConfig {
+	bufferSize, _ := strconv.Atoi(getEnv("TAP_BUFFER_SIZE", "200"))
+	maxBodyBytes, _ := strconv.Atoi(getEnv("TAP_MAX_BODY_BYTES", "65536"))
+	maxTTL, _ := strconv.Atoi(getEnv("TAP_MAX_TTL_SEC", "3600"))
+	retention, _ := strconv.Atoi(getEnv("TAP_RETENTION_SEC", "3600"))
+
+	return TapConfig{
+		BufferSize:   bufferSize,
+		MaxBodyBytes: maxBodyBytes,
+		MaxTTL:       time.Duration(maxTTL) * time.Second,
+		Retention:    time.Duration(retention) * time.Second,
+	}
+}
+
+// loadCompressionConfig loads the response compression settings
+func loadCompressionConfig() CompressionConfig {
+	minSize, _ := strconv.Atoi(getEnv("COMPRESSION_MIN_SIZE", "1024"))
+	level, _ := strconv.Atoi(getEnv("COMPRESSION_LEVEL", "-1"))
+
+	return CompressionConfig{
+		Enabled:      getEnv("COMPRESSION_ENABLED", "true") == "true",
+		MinSize:      minSize,
+		Level:        level,
+		ContentTypes: splitList(getEnv("COMPRESSION_CONTENT_TYPES", "application/json,application/problem+json,application/x-ndjson,text/*")),
+	}
+}
+
+// loadUsageConfig loads the usage metering store and flush interval
+func loadUsageConfig() UsageConfig {
+	flushInterval, _ := strconv.Atoi(getEnv("USAGE_FLUSH_INTERVAL_SEC", "60"))
+	if flushInterval <= 0 {
+		flushInterval = 60
+	}
+
+	return UsageConfig{
+		Enabled:       getEnv("USAGE_METERING_ENABLED", "true") == "true",
+		StorePath:     getEnv("USAGE_STORE_PATH", ""),
+		FlushInterval: time.Duration(flushInterval) * time.Second,
+	}
+}
+
+// defaultSLOObjectives covers logging in, searching for drivers, registering
+// them, their location updates and requesting trips
+const defaultSLOObjectives = "login|POST /auth/login|99.9|500|99," +
+	"nearby|GET /drivers/nearby|99.9|300|99," +
+	"create-driver|POST /drivers|99.5|1000|95," +
+	"update-location|PUT /drivers/:id/location|99.9|200|99," +
+	"request-trip|POST /trips|99.9|1000|99"
+
+// loadSLOConfig loads the service level objectives. SLO_OBJECTIVES lists
+// "name|METHOD /route|availability %|latency threshold ms|latency %" entries;
+// a zero threshold leaves latency without an objective.
+func loadSLOConfig() SLOConfig {
+	var objectives []SLOObjective
+	for _, item := range splitList(getEnv("SLO_OBJECTIVES", defaultSLOObjectives)) {
+		parts := strings.Split(item, "|")
+		if len(parts) != 5 {
+			continue
+		}
+		method, route, ok := strings.Cut(strings.TrimSpace(parts[1]), " ")
+		availability, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if !ok || err != nil || availability <= 0 || availability >= 100 {
+			continue
+		}
+		thresholdMs, _ := strconv.Atoi(strings.TrimSpace(parts[3]))
+		latency, _ := strconv.ParseFloat(strings.TrimSpace(parts[4]), 64)
+		if thresholdMs <= 0 || latency <= 0 || latency >= 100 {
+			thresholdMs, latency = 0, 0
+		}
+		objectives = append(objectives, SLOObjective{
+			Name:             strings.TrimSpace(parts[0]),
+			Method:           strings.ToUpper(method),
+			Route:            strings.TrimSpace(route),
+			Availability:     availability / 100,
+			LatencyThreshold: time.Duration(thresholdMs) * time.Millisecond,
+			Latency:          latency / 100,
+		})
+	}
+
+	var windows []time.Duration
+	for _, item := range splitList(getEnv("SLO_WINDOWS", "5m,30m,1h,2h,6h,24h,72h")) {
+		if window, err := time.ParseDuration(item); err == nil && window >= time.Minute {
+			windows = append(windows, window)
+		}
+	}
+
+	return SLOConfig{
+		Enabled:    getEnv("SLO_ENABLED", "true") == "true",
+		Objectives: objectives,
+		Windows:    windows,
+	}
+}
+
+// loadErrorReportConfig loads the error tracker settings
+func loadErrorReportConfig() ErrorReportConfig {
+	sampleRate, err := strconv.ParseFloat(getEnv("ERROR_REPORTING_SAMPLE_RATE", "1"), 64)
+	if err != nil || sampleRate < 0 || sampleRate > 1 {
+		sampleRate = 1
+	}
+
+	return ErrorReportConfig{
+		DSN:         getEnv("ERROR_REPORTING_DSN", ""),
+		Environment: getEnv("ERROR_REPORTING_ENVIRONMENT", "production"),
+		Release:     getEnv("ERROR_REPORTING_RELEASE", ""),
+		SampleRate:  sampleRate,
+		Loggers:     splitList(getEnv("ERROR_REPORTING_LOGGERS", "handler,service,usage")),
+		ScrubFields: splitList(getEnv("ERROR_REPORTING_SCRUB_FIELDS", "")),
+	}
+}
+
+// loadPricingConfig loads the pricing service settings
+func loadPricingConfig() PricingConfig {
+	timeout, _ := strconv.Atoi(getEnv("PRICING_TIMEOUT_MS", "800"))
+	concurrency, err := strconv.Atoi(getEnv("PRICING_CONCURRENCY", "8"))
+	if err != nil || concurrency < 1 {
+		concurrency = 8
+	}
+
+	return PricingConfig{
+		BaseURL:     getEnv("PRICING_SERVICE_URL", ""),
+		Timeout:     time.Duration(timeout) * time.Millisecond,
+		Concurrency: concurrency,
+	}
+}
+
+// loadLoggingConfig loads the log outputs, rotation and LOG_LEVELS ("name=level,name=level") overrides
+func loadLoggingConfig(level string) LoggingConfig {
+	maxSize, _ := strconv.Atoi(getEnv("LOG_MAX_SIZE_MB", "100"))
+	maxAge, _ := strconv.Atoi(getEnv("LOG_MAX_AGE_DAYS", "0"))
+	maxBackups, _ := strconv.Atoi(getEnv("LOG_MAX_BACKUPS", "0"))
+
+	levels := make(map[string]string)
+	for _, item := range splitList(getEnv("LOG_LEVELS", "")) {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		levels[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return LoggingConfig{
+		Level:    level,
+		Encoding: getEnv("LOG_ENCODING", ""),
+		Outputs:  splitList(getEnv("LOG_OUTPUTS", "stdout")),
+		Levels:   levels,
+		Rotation: LogRotationConfig{
+			MaxSizeMB:  maxSize,
+			MaxAgeDays: maxAge,
+			MaxBackups: maxBackups,
+			Compress:   getEnv("LOG_COMPRESS", "false") == "true",
+		},
 	}
 }
 
@@ -113,3 +1057,14 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// splitList splits a comma-separated value into trimmed, non-empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
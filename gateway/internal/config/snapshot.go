@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Redacted replaces the value of a secret that is set
+const Redacted = "[REDACTED]"
+
+// Snapshot returns the configuration as JSON-friendly maps for operators.
+// Keys are camelCased field names, durations read like "30s", and fields
+// tagged secret show Redacted when set, one per entry for lists, so it is
+// still visible whether and how many are configured.
+func (c *Config) Snapshot() map[string]interface{} {
+	return snapshotStruct(reflect.ValueOf(*c))
+}
+
+func snapshotStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := camelCase(field.Name)
+		if field.Tag.Get("secret") == "true" {
+			out[name] = redact(v.Field(i))
+			continue
+		}
+		out[name] = snapshotValue(v.Field(i))
+	}
+	return out
+}
+
+func snapshotValue(v reflect.Value) interface{} {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return snapshotStruct(v)
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = snapshotValue(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
+
+// camelCase lowercases the leading initialism or letter of a field name, so
+// JWT becomes jwt, APIKey apiKey and BaseURL baseURL
+func camelCase(name string) string {
+	upper := 0
+	for upper < len(name) && unicode.IsUpper(rune(name[upper])) {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		// The last capital starts the next word
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:]
+}
+
+// redact hides a secret string or list of secrets; empty ones stay empty
+func redact(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = Redacted
+		}
+		return items
+	default:
+		if v.IsZero() {
+			return ""
+		}
+		return Redacted
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Snapshot(t *testing.T) {
+	cfg := &Config{
+		Server: ServerConfig{Port: "8080", ReadTimeout: 30 * time.Second},
+		JWT:    JWTConfig{Secret: "jwt-secret", RSAKeys: []KeyFile{{ID: "k1", Path: "/keys/k1.pem"}}},
+		APIKey: APIKeyConfig{Enabled: true, Keys: []string{"key-one", "key-two"}},
+	}
+
+	snapshot := cfg.Snapshot()
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	for _, secret := range []string{"jwt-secret", "key-one", "key-two"} {
+		assert.NotContains(t, string(data), secret)
+	}
+
+	server := snapshot["server"].(map[string]interface{})
+	assert.Equal(t, "8080", server["port"])
+	assert.Equal(t, "30s", server["readTimeout"])
+
+	jwt := snapshot["jwt"].(map[string]interface{})
+	assert.Equal(t, Redacted, jwt["secret"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "k1", "path": "/keys/k1.pem"}}, jwt["rsaKeys"])
+	assert.Equal(t, []string{Redacted, Redacted}, snapshot["apiKey"].(map[string]interface{})["keys"])
+	assert.Equal(t, "", snapshot["admin"].(map[string]interface{})["token"], "unset secrets stay empty")
+}
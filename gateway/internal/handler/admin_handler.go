@@ -0,0 +1,831 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/lifecycle"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/tap"
+	"github.com/bitaksi/gateway/internal/usage"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminHandler handles operational requests under /admin
+type AdminHandler struct {
+	driverService *service.DriverServiceClient
+	taps          *tap.Registry
+	meter         *usage.Meter
+	lifecycle     *lifecycle.Tracker
+	drainTimeout  time.Duration
+	logger        *zap.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(driverService *service.DriverServiceClient, taps *tap.Registry, meter *usage.Meter, tracker *lifecycle.Tracker, drainTimeout time.Duration, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		driverService: driverService,
+		taps:          taps,
+		meter:         meter,
+		lifecycle:     tracker,
+		drainTimeout:  drainTimeout,
+		logger:        logger,
+	}
+}
+
+// DrainResponse reports the outcome of a drain
+type DrainResponse struct {
+	Status   string `json:"status" example:"drained" enums:"drained,timeout"`
+	InFlight int64  `json:"inFlight" example:"0"`
+	WaitedMs int64  `json:"waitedMs" example:"420"`
+}
+
+// Drain handles POST /admin/drain
+// @Summary Drain the gateway
+// @Description Fail the readiness probe so no new traffic is routed here, then wait until in-flight requests finish or the timeout elapses. Intended for a Kubernetes preStop hook; readiness stays failed until the process restarts.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param timeoutSec query int false "Maximum seconds to wait (defaults to DRAIN_TIMEOUT_SEC)"
+// @Success 200 {object} DrainResponse "All in-flight requests finished"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 504 {object} DrainResponse "Requests still in flight when the timeout elapsed"
+// @Router /admin/drain [post]
+func (h *AdminHandler) Drain(c *gin.Context) {
+	timeout := h.drainTimeout
+	if raw := c.Query("timeoutSec"); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds < 0 {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "timeoutSec must be a non-negative integer")
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	start := time.Now()
+	h.logger.Info("draining gateway", zap.Int64("inFlight", h.lifecycle.InFlight()), zap.Duration("timeout", timeout))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+	err := h.lifecycle.Drain(ctx)
+
+	resp := DrainResponse{
+		Status:   "drained",
+		InFlight: h.lifecycle.InFlight(),
+		WaitedMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		resp.Status = "timeout"
+		h.logger.Warn("drain timed out", zap.Int64("inFlight", resp.InFlight))
+		c.JSON(http.StatusGatewayTimeout, resp)
+		return
+	}
+
+	h.logger.Info("gateway drained", zap.Int64("waitedMs", resp.WaitedMs))
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateTapRequest represents the request to start capturing traffic
+type CreateTapRequest struct {
+	Route        string `json:"route,omitempty" example:"/drivers/:id"`
+	RequestID    string `json:"requestId,omitempty" example:"req-123"`
+	TTLSeconds   int    `json:"ttlSeconds,omitempty" example:"600"`
+	MaxBodyBytes int    `json:"maxBodyBytes,omitempty" example:"16384"`
+}
+
+// TapListResponse lists the active taps and every retained capture
+type TapListResponse struct {
+	Taps     []tap.Tap     `json:"taps"`
+	Captures []tap.Capture `json:"captures"`
+}
+
+// TapResponse is a tap with its captures
+type TapResponse struct {
+	Tap      tap.Tap       `json:"tap"`
+	Captures []tap.Capture `json:"captures"`
+}
+
+// ListTaps handles GET /admin/taps
+// @Summary List debug taps
+// @Description List active taps and the captured request/response pairs still in the ring buffer
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} TapListResponse "Taps and captures"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/taps [get]
+func (h *AdminHandler) ListTaps(c *gin.Context) {
+	taps := h.taps.List()
+	sort.Slice(taps, func(i, j int) bool { return taps[i].CreatedAt.Before(taps[j].CreatedAt) })
+
+	c.JSON(http.StatusOK, TapListResponse{Taps: taps, Captures: h.taps.Captures("")})
+}
+
+// CreateTap handles POST /admin/taps
+// @Summary Create debug tap
+// @Description Capture full request/response bodies for a route (e.g. "/drivers/:id") or a single X-Request-ID until the tap expires
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param tap body CreateTapRequest true "Tap selector and limits"
+// @Success 201 {object} tap.Tap "Tap created"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/taps [post]
+func (h *AdminHandler) CreateTap(c *gin.Context) {
+	var req CreateTapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	t, err := h.taps.Add(tap.Spec{
+		Route:        req.Route,
+		RequestID:    req.RequestID,
+		TTL:          time.Duration(req.TTLSeconds) * time.Second,
+		MaxBodyBytes: req.MaxBodyBytes,
+	})
+	if err != nil {
+		if errors.Is(err, tap.ErrSelectorRequired) || errors.Is(err, tap.ErrTTLTooLong) {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		h.logger.Error("failed to create tap", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create tap")
+		return
+	}
+
+	h.logger.Info("debug tap created",
+		zap.String("id", t.ID),
+		zap.String("route", t.Route),
+		zap.String("requestId", t.RequestID),
+		zap.Time("expiresAt", t.ExpiresAt),
+	)
+	c.JSON(http.StatusCreated, t)
+}
+
+// GetTap handles GET /admin/taps/:id
+// @Summary Get debug tap
+// @Description Get a tap and the captures it recorded
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Tap ID"
+// @Success 200 {object} TapResponse "Tap and captures"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Tap not found or expired"
+// @Router /admin/taps/{id} [get]
+func (h *AdminHandler) GetTap(c *gin.Context) {
+	t, ok := h.taps.Get(c.Param("id"))
+	if !ok {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "tap not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, TapResponse{Tap: t, Captures: h.taps.Captures(t.ID)})
+}
+
+// DeleteTap handles DELETE /admin/taps/:id
+// @Summary Delete debug tap
+// @Description Stop a tap and discard its captures
+// @Tags admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Tap ID"
+// @Success 204 "Tap deleted"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Tap not found"
+// @Router /admin/taps/{id} [delete]
+func (h *AdminHandler) DeleteTap(c *gin.Context) {
+	if !h.taps.Remove(c.Param("id")) {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "tap not found")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetIndexes handles GET /admin/indexes
+// @Summary Report driver service indexes
+// @Description Compare the MongoDB indexes the driver service expects with the ones that exist, listing missing, mismatched and unexpected indexes
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} IndexReport "Index report"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/indexes [get]
+func (h *AdminHandler) GetIndexes(c *gin.Context) {
+	resp, err := h.driverService.GetIndexes()
+	if err != nil {
+		h.logger.Error("failed to forward index report request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list indexes")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// SyncIndexes handles POST /admin/indexes/sync
+// @Summary Create missing driver service indexes
+// @Description Start building the missing MongoDB indexes in the background; poll GET /admin/indexes/sync for progress. Mismatched indexes are skipped. The request is audit-logged with the caller from X-Admin-User (or the client IP).
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-User header string false "Operator name for the audit log"
+// @Success 202 {object} IndexSyncJob "Sync started"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 409 {object} ErrorResponse "Sync already running"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/indexes/sync [post]
+func (h *AdminHandler) SyncIndexes(c *gin.Context) {
+	actor := c.GetHeader("X-Admin-User")
+	if actor == "" {
+		actor = c.ClientIP()
+	}
+
+	resp, err := h.driverService.SyncIndexes(actor)
+	if err != nil {
+		h.logger.Error("failed to forward index sync request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to start index sync")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.logger.Info("audit: index sync requested",
+		zap.String("actor", actor),
+		zap.String("ip", c.ClientIP()),
+		zap.String("requestId", c.GetString("requestID")),
+		zap.Int("status", resp.StatusCode),
+	)
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetIndexSync handles GET /admin/indexes/sync
+// @Summary Get index sync progress
+// @Description Get the progress of the latest driver service index sync
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} IndexSyncJob "Latest sync job"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "No sync started"
+// @Router /admin/indexes/sync [get]
+func (h *AdminHandler) GetIndexSync(c *gin.Context) {
+	resp, err := h.driverService.GetIndexSync()
+	if err != nil {
+		h.logger.Error("failed to forward index sync status request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get index sync")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetFailoverStats handles GET /admin/failover
+// @Summary Report driver service failover metrics
+// @Description Current MongoDB primary, observed primary changes, and how many driver service operations were retried, recovered or answered with 503 since it started
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} FailoverStats "Failover metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/failover [get]
+func (h *AdminHandler) GetFailoverStats(c *gin.Context) {
+	resp, err := h.driverService.GetFailoverStats()
+	if err != nil {
+		h.logger.Error("failed to forward failover stats request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get failover stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetTelemetryStats handles GET /admin/telemetry
+// @Summary Report driver service MQTT telemetry metrics
+// @Description Whether the driver service's MQTT bridge is connected to the broker, its topics, and the location and heartbeat messages it received, applied, rejected (by reason) or left for redelivery since it started
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} TelemetryStats "Telemetry metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "MQTT bridge disabled"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/telemetry [get]
+func (h *AdminHandler) GetTelemetryStats(c *gin.Context) {
+	resp, err := h.driverService.GetTelemetryStats()
+	if err != nil {
+		h.logger.Error("failed to forward telemetry stats request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get telemetry stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetQueryStats handles GET /admin/query-stats
+// @Summary Report driver service MongoDB query latency
+// @Description Duration histogram of every MongoDB command per collection since the driver service started, the collections taking the most time first. Commands at or over MONGODB_SLOW_QUERY_MS count as slow and are logged with their redacted filter.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {array} QueryStats "Query latency per collection and command"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/query-stats [get]
+func (h *AdminHandler) GetQueryStats(c *gin.Context) {
+	resp, err := h.driverService.GetQueryStats()
+	if err != nil {
+		h.logger.Error("failed to forward query stats request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get query stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetExpiringLicenses handles GET /admin/licenses/expiring
+// @Summary List expiring driver licences
+// @Description Drivers whose licence expires within the given number of days, soonest first. Already expired licences are included; flagged drivers have been taken off dispatch by the hourly licence check.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param days query int false "Report window in days, 1-365 (default 30)" example(30)
+// @Param fleetId query string false "Only report drivers of this fleet" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} ExpiringLicensesResponse "Expiring licences"
+// @Failure 400 {object} ErrorResponse "Invalid window"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/licenses/expiring [get]
+func (h *AdminHandler) GetExpiringLicenses(c *gin.Context) {
+	resp, err := h.driverService.GetExpiringLicenses(c.Query("days"), c.Query("fleetId"))
+	if err != nil {
+		h.logger.Error("failed to forward expiring licenses request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list expiring licenses")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetValidationRules handles GET /admin/validation-rules
+// @Summary Inspect driver validation rules
+// @Description The driver field validation rules in effect in the driver service: those of the service country, every configured country and every tenant, after inheritance is applied. With fleetId, only the rules applied to drivers of that fleet.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param fleetId query string false "Only return the rules applied to this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Success 200 {object} ValidationRulesResponse "Effective rules"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/validation-rules [get]
+func (h *AdminHandler) GetValidationRules(c *gin.Context) {
+	resp, err := h.driverService.GetValidationRules(c.Query("fleetId"))
+	if err != nil {
+		h.logger.Error("failed to forward validation rules request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get validation rules")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetTenantQuota handles GET /admin/tenants/:id/quota
+// @Summary Tenant driver quota
+// @Description How many drivers a tenant (fleet) has against the quota it pays for, set with TENANT_QUOTA_MAX_DRIVERS and TENANT_QUOTAS in the driver service. Fleets that have used up their quota take no new drivers (402 QUOTA_EXCEEDED).
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Fleet ID" example("6570a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} DriverQuota "Quota usage"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Fleet not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/tenants/{id}/quota [get]
+func (h *AdminHandler) GetTenantQuota(c *gin.Context) {
+	resp, err := h.driverService.GetTenantQuota(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward tenant quota request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get tenant quota")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// adminCaller returns a client that attributes requests to the operator named
+// in X-Admin-User, so adjustments and payouts record who made them
+func (h *AdminHandler) adminCaller(c *gin.Context) *service.DriverServiceClient {
+	return h.driverService.WithIdentity(service.Identity{UserID: c.GetHeader("X-Admin-User")})
+}
+
+// CreateEarningAdjustment handles POST /admin/earnings/adjustments
+// @Summary Adjust driver earnings
+// @Description Credit or deduct an amount from a driver's earnings. Adjustments are never edited; each keeps its reason and the operator from X-Admin-User, and is included in the next payout.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-User header string false "Operator recorded in the audit trail" example(ops-admin)
+// @Param adjustment body CreateAdjustmentRequest true "Adjustment"
+// @Success 201 {object} Earning "Adjustment recorded"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/earnings/adjustments [post]
+func (h *AdminHandler) CreateEarningAdjustment(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := h.adminCaller(c).CreateEarningAdjustment(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward earning adjustment", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create adjustment")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListEarningAdjustments handles GET /admin/earnings/adjustments
+// @Summary List earning adjustments
+// @Description Audit trail of manual earning adjustments, newest first.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param driverId query string false "Only list adjustments of this driver" example(507f1f77bcf86cd799439011)
+// @Param limit query int false "Maximum adjustments to return (max 200)" default(50)
+// @Success 200 {array} Earning "Adjustments"
+// @Failure 400 {object} ErrorResponse "Invalid limit"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/earnings/adjustments [get]
+func (h *AdminHandler) ListEarningAdjustments(c *gin.Context) {
+	resp, err := h.driverService.ListEarningAdjustments(c.Query("driverId"), c.Query("limit"))
+	if err != nil {
+		h.logger.Error("failed to forward list adjustments request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list adjustments")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ErasePersonalData handles DELETE /drivers/:id/personal-data
+// @Summary Erase a driver's personal data
+// @Description Erase a driver's personal data at once on a KVKK/GDPR request instead of waiting for the retention window; a live driver is deleted first. Names, contact details, plate, attributes, location history, documents, licence, photos, device tokens and phone verifications are erased, while trips, shifts and earnings are kept for statistics and the books. The erasure is recorded with the operator from X-Admin-User and the reason.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-User header string false "Operator recorded in the audit trail" example(ops-admin)
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param reason query string false "Why the data is erased, e.g. a ticket number" example("KVKK request #4711")
+// @Success 200 {object} Erasure "Erasure record"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/personal-data [delete]
+func (h *AdminHandler) ErasePersonalData(c *gin.Context) {
+	resp, err := h.driverService.ErasePersonalData(c.Param("id"), c.GetHeader("X-Admin-User"), c.Query("reason"))
+	if err != nil {
+		h.logger.Error("failed to forward personal data erasure", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to erase personal data")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListErasures handles GET /admin/erasures
+// @Summary List personal data erasures
+// @Description Audit trail of driver personal data erasures, by the retention job and on request, newest first.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param driverId query string false "Only list erasures of this driver" example(507f1f77bcf86cd799439011)
+// @Param limit query int false "Maximum erasures to return (max 200)" default(50)
+// @Success 200 {array} Erasure "Erasures"
+// @Failure 400 {object} ErrorResponse "Invalid limit"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/erasures [get]
+func (h *AdminHandler) ListErasures(c *gin.Context) {
+	resp, err := h.driverService.ListErasures(c.Query("driverId"), c.Query("limit"))
+	if err != nil {
+		h.logger.Error("failed to forward list erasures request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list erasures")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// CreatePayout handles POST /admin/payouts
+// @Summary Create a payout batch
+// @Description Batch every unpaid trip earning and adjustment in the range into a payout with one line per driver. Entries are paid out once. The range ends at the start of the current day by default.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param X-Admin-User header string false "Operator recorded on the payout" example(ops-admin)
+// @Param payout body CreatePayoutRequest false "Payout range"
+// @Success 201 {object} Payout "Payout created"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 409 {object} ErrorResponse "No unpaid earnings in range"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/payouts [post]
+func (h *AdminHandler) CreatePayout(c *gin.Context) {
+	var payload interface{}
+	if c.Request.ContentLength > 0 {
+		body, err := bindRequestBody(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		payload = body.payload()
+	}
+
+	resp, err := h.adminCaller(c).CreatePayout(payload)
+	if err != nil {
+		h.logger.Error("failed to forward payout request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create payout")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListPayouts handles GET /admin/payouts
+// @Summary List payouts
+// @Description Payout batches, newest first, without their lines.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param limit query int false "Maximum payouts to return (max 200)" default(50)
+// @Success 200 {array} Payout "Payouts"
+// @Failure 400 {object} ErrorResponse "Invalid limit"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/payouts [get]
+func (h *AdminHandler) ListPayouts(c *gin.Context) {
+	resp, err := h.driverService.ListPayouts(c.Query("limit"))
+	if err != nil {
+		h.logger.Error("failed to forward list payouts request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list payouts")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetPayout handles GET /admin/payouts/:id
+// @Summary Get a payout
+// @Description A payout batch with what it owes each driver.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Payout ID" example("6573b2f3c4d5e6f7a8b9c0d1")
+// @Success 200 {object} Payout "Payout"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Payout not found"
+// @Router /admin/payouts/{id} [get]
+func (h *AdminHandler) GetPayout(c *gin.Context) {
+	resp, err := h.driverService.GetPayout(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get payout request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get payout")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ExportPayout handles GET /admin/payouts/:id/export
+// @Summary Export a payout as CSV
+// @Description Download a payout batch for finance as CSV: payoutId, driverId, trips, gross, commission, adjustments, net, currency.
+// @Tags admin
+// @Produce text/csv
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Payout ID" example("6573b2f3c4d5e6f7a8b9c0d1")
+// @Success 200 {string} string "Payout CSV"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Payout not found"
+// @Router /admin/payouts/{id}/export [get]
+func (h *AdminHandler) ExportPayout(c *gin.Context) {
+	resp, err := h.driverService.ExportPayout(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward payout export request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to export payout")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetUtilizationReport handles GET /admin/reports/utilization
+// @Summary Driver utilization report
+// @Description Online hours (time on shift) against busy hours (time between accepting and completing trips) per calendar day and taxi type, with busy over online as utilization. Days are calendar days in the driver service's earnings time zone. The range defaults to the last 7 days and cannot exceed 92 days; today is marked partial. Send format=csv or "Accept: text/csv" for a CSV export.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param X-Admin-Token header string true "Admin token"
+// @Param from query string false "First day (YYYY-MM-DD, inclusive)" example("2025-12-01")
+// @Param to query string false "Last day (YYYY-MM-DD, inclusive)" example("2025-12-07")
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} UtilizationReport "Utilization report"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/reports/utilization [get]
+func (h *AdminHandler) GetUtilizationReport(c *gin.Context) {
+	query := url.Values{}
+	for _, name := range []string{"from", "to", "format"} {
+		if value := c.Query(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+	// The Accept header is not forwarded, so a CSV request is passed on as the format
+	if query.Get("format") == "" && c.NegotiateFormat(gin.MIMEJSON, "text/csv") == "text/csv" {
+		query.Set("format", "csv")
+	}
+
+	resp, err := h.driverService.GetUtilizationReport(query)
+	if err != nil {
+		h.logger.Error("failed to forward utilization report request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get utilization report")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetLocationAnomalies handles GET /admin/location-anomalies
+// @Summary Location anomalies per driver
+// @Description Drivers whose location updates implied an implausible speed, e.g. spoofed GPS, with the number of such updates, how many were rejected and the highest implied speed; most anomalies first. The range defaults to the last 30 days and cannot exceed 366 days.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Param limit query int false "Number of drivers to list (1-1000, default 50)"
+// @Success 200 {object} LocationAnomalyReport "Anomaly counts per driver"
+// @Failure 400 {object} ErrorResponse "Invalid range or limit"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/location-anomalies [get]
+func (h *AdminHandler) GetLocationAnomalies(c *gin.Context) {
+	query := url.Values{}
+	for _, name := range []string{"from", "to", "limit"} {
+		if value := c.Query(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	resp, err := h.driverService.GetLocationAnomalies(query)
+	if err != nil {
+		h.logger.Error("failed to forward location anomalies request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get location anomalies")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// maxUsageRange bounds usage queries to a year of daily records
+const maxUsageRange = 366 * 24 * time.Hour
+
+// UsageReport lists metered requests over a day range
+type UsageReport struct {
+	From          string         `json:"from" example:"2025-12-01"`
+	To            string         `json:"to" example:"2025-12-06"`
+	TotalRequests int64          `json:"totalRequests" example:"48210"`
+	TotalErrors   int64          `json:"totalErrors" example:"312"`
+	Records       []usage.Record `json:"records"`
+}
+
+// GetUsage handles GET /admin/usage
+// @Summary Usage report
+// @Description Requests counted per UTC day, tenant (fleet), subject (JWT user or masked API key), method and route. The range defaults to the last 30 days and cannot exceed 366 days. Send format=csv or "Accept: text/csv" for a CSV export.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param X-Admin-Token header string true "Admin token"
+// @Param from query string false "First day (YYYY-MM-DD, inclusive)" example("2025-12-01")
+// @Param to query string false "Last day (YYYY-MM-DD, inclusive)" example("2025-12-06")
+// @Param tenant query string false "Only this tenant (fleet ID)"
+// @Param subject query string false "Only this subject, e.g. user:admin or key:sk_live_****z789"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} UsageReport "Usage report"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Usage metering disabled"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/usage [get]
+func (h *AdminHandler) GetUsage(c *gin.Context) {
+	if h.meter == nil {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "usage metering is disabled")
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(usage.DayLayout, raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "to must be a YYYY-MM-DD date")
+			return
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -29)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(usage.DayLayout, raw)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must be a YYYY-MM-DD date")
+			return
+		}
+		from = parsed
+	}
+	fromDay, toDay := from.Format(usage.DayLayout), to.Format(usage.DayLayout)
+	if fromDay > toDay {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "from must not be after to")
+		return
+	}
+	if to.Sub(from) > maxUsageRange {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "usage range cannot exceed 366 days")
+		return
+	}
+
+	records, err := h.meter.Query(c.Request.Context(), usage.Filter{
+		From:    fromDay,
+		To:      toDay,
+		Tenant:  c.Query("tenant"),
+		Subject: c.Query("subject"),
+	})
+	if err != nil {
+		h.logger.Error("failed to query usage", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to query usage")
+		return
+	}
+
+	if c.Query("format") == "csv" || c.NegotiateFormat(gin.MIMEJSON, "text/csv") == "text/csv" {
+		writeUsageCSV(c, fromDay, toDay, records)
+		return
+	}
+
+	report := UsageReport{From: fromDay, To: toDay, Records: records}
+	for _, r := range records {
+		report.TotalRequests += r.Requests
+		report.TotalErrors += r.Errors
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func writeUsageCSV(c *gin.Context, from, to string, records []usage.Record) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s.csv"`, from, to))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"day", "tenant", "subject", "method", "route", "requests", "errors"})
+	for _, r := range records {
+		w.Write([]string{
+			r.Day, r.Tenant, r.Subject, r.Method, r.Route,
+			strconv.FormatInt(r.Requests, 10),
+			strconv.FormatInt(r.Errors, 10),
+		})
+	}
+	w.Flush()
+}
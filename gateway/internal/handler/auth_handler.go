@@ -2,28 +2,50 @@ package handler
 
 import (
 	"net/http"
-	"time"
 
+	"github.com/bitaksi/gateway/internal/account"
 	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/oidc"
+	"github.com/bitaksi/gateway/internal/token"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
 // AuthHandler handles authentication requests
 type AuthHandler struct {
 	config *config.Config
-	logger *zap.Logger
+	tokens *token.Manager
+	sso    *oidc.Provider
+	// users and reset are nil unless AUTH_USERS_FILE is set
+	users *account.Store
+	reset *account.Reset
+	// exchanger is nil unless TOKEN_EXCHANGE_SECRET is set
+	exchanger *token.Exchanger
+	logger    *zap.Logger
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(cfg *config.Config, logger *zap.Logger) *AuthHandler {
+func NewAuthHandler(cfg *config.Config, tokens *token.Manager, logger *zap.Logger) *AuthHandler {
 	return &AuthHandler{
 		config: cfg,
+		tokens: tokens,
 		logger: logger,
 	}
 }
 
+// EnableOIDC lets users sign in with the OpenID Connect provider alongside
+// password login
+func (h *AuthHandler) EnableOIDC(provider *oidc.Provider) {
+	h.sso = provider
+}
+
+// EnableAccounts checks logins against the password accounts and lets their
+// users reset a forgotten password
+func (h *AuthHandler) EnableAccounts(users *account.Store, reset *account.Reset) {
+	h.users = users
+	h.reset = reset
+}
+
 // LoginRequest represents a login request
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
@@ -37,7 +59,7 @@ type LoginResponse struct {
 
 // Login handles POST /auth/login
 // @Summary Login
-// @Description Authenticate and get JWT token
+// @Description Authenticate and get JWT token. With AUTH_USERS_FILE the password is checked against the user's account; otherwise any username and password are accepted.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -52,9 +74,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	// Simple mock authentication (in production, use proper user database)
-	// For demo purposes, accept any username/password or use hardcoded admin
-	if req.Username == "" || req.Password == "" {
+	// Without a users file any username and password are accepted, for demos
+	if req.Username == "" || req.Password == "" || (h.users != nil && !h.users.Authenticate(req.Username, req.Password)) {
 		h.respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid credentials")
 		return
 	}
@@ -70,16 +91,98 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, LoginResponse{Token: token})
 }
 
-// generateToken generates a JWT token for the user
+// generateToken generates a JWT token for the user. Configured fleet admins
+// get the fleet_admin role scoped to their fleet.
 func (h *AuthHandler) generateToken(username string) (string, error) {
-	claims := jwt.MapClaims{
-		"username": username,
-		"exp":      time.Now().Add(h.config.JWT.Expiration).Unix(),
-		"iat":      time.Now().Unix(),
+	if fleetID, ok := h.config.Auth.FleetAdmins[username]; ok {
+		return h.tokens.Issue(username, token.WithRole(token.RoleFleetAdmin), token.WithFleet(fleetID))
 	}
+	return h.tokens.Issue(username)
+}
+
+// IntrospectRequest represents a token introspection request
+type IntrospectRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(h.config.JWT.Secret))
+// IntrospectResponse describes whether a token is active and, if so, its claims
+type IntrospectResponse struct {
+	Active    bool     `json:"active" example:"true"`
+	Username  string   `json:"username,omitempty" example:"admin"`
+	Subject   string   `json:"sub,omitempty" example:"admin"`
+	Role      string   `json:"role,omitempty" example:"fleet_admin"`
+	FleetID   string   `json:"fleetId,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	RiderID   string   `json:"riderId,omitempty" example:"6573a1f2c3d4e5f6a7b8c9d0"`
+	TokenType string   `json:"token_type,omitempty" example:"Bearer"`
+	ExpiresAt int64    `json:"exp,omitempty" example:"1735689600"`
+	IssuedAt  int64    `json:"iat,omitempty" example:"1735603200"`
+	NotBefore int64    `json:"nbf,omitempty" example:"1735603200"`
+	Issuer    string   `json:"iss,omitempty" example:"https://gateway.bitaksi.com"`
+	Audience  []string `json:"aud,omitempty"`
+	TokenID   string   `json:"jti,omitempty" example:"9f1c2e7a4b3d8e6f0a1b2c3d4e5f6a7b"`
+	KeyID     string   `json:"kid,omitempty" example:"2025-01"`
+}
+
+// JWKS handles GET /auth/.well-known/jwks.json
+// @Summary JSON Web Key Set
+// @Description Public keys for validating RS256 tokens issued by the gateway. The set is empty when tokens are signed with HS256.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} token.JWKS "Key set"
+// @Router /auth/.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.tokens.JWKS())
+}
+
+// Introspect handles POST /auth/introspect
+// @Summary Introspect token
+// @Description Check whether an access token is valid (RFC 7662). Invalid or expired tokens return {"active": false}.
+// @Tags auth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param request body IntrospectRequest true "Token to introspect"
+// @Success 200 {object} IntrospectResponse "Introspection result"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Router /auth/introspect [post]
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	var req IntrospectRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	claims, err := h.tokens.Parse(req.Token)
+	if err != nil {
+		h.logger.Debug("introspected inactive token", zap.Error(err))
+		c.JSON(http.StatusOK, IntrospectResponse{Active: false})
+		return
+	}
+
+	subject, _ := claims.Raw["sub"].(string)
+	resp := IntrospectResponse{
+		Active:    true,
+		Username:  claims.Username,
+		Subject:   subject,
+		Role:      claims.Role,
+		FleetID:   claims.FleetID,
+		RiderID:   claims.RiderID,
+		TokenType: "Bearer",
+		Issuer:    claims.Issuer,
+		Audience:  claims.Audience,
+		TokenID:   claims.ID,
+		KeyID:     claims.KeyID,
+	}
+	if !claims.ExpiresAt.IsZero() {
+		resp.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if !claims.IssuedAt.IsZero() {
+		resp.IssuedAt = claims.IssuedAt.Unix()
+	}
+	if !claims.NotBefore.IsZero() {
+		resp.NotBefore = claims.NotBefore.Unix()
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *AuthHandler) respondError(c *gin.Context, status int, code, message string) {
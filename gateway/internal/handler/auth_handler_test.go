@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/bitaksi/gateway/internal/account"
 	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/oidc"
+	"github.com/bitaksi/gateway/internal/token"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestNewAuthHandler(t *testing.T) {
@@ -22,10 +29,12 @@ func TestNewAuthHandler(t *testing.T) {
 		},
 	}
 	logger := zap.NewNop()
-	handler := NewAuthHandler(cfg, logger)
+	tokens := token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration)
+	handler := NewAuthHandler(cfg, tokens, logger)
 
 	assert.NotNil(t, handler)
 	assert.Equal(t, cfg, handler.config)
+	assert.Equal(t, tokens, handler.tokens)
 	assert.Equal(t, logger, handler.logger)
 }
 
@@ -99,7 +108,7 @@ func TestAuthHandler_Login(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler := NewAuthHandler(cfg, logger)
+			handler := NewAuthHandler(cfg, token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration), logger)
 
 			router := gin.New()
 			gin.SetMode(gin.TestMode)
@@ -135,9 +144,338 @@ func TestAuthHandler_generateToken(t *testing.T) {
 		},
 	}
 	logger := zap.NewNop()
-	handler := NewAuthHandler(cfg, logger)
+	handler := NewAuthHandler(cfg, token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration), logger)
 
-	token, err := handler.generateToken("testuser")
+	tokenString, err := handler.generateToken("testuser")
 	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, tokenString)
+}
+
+func TestAuthHandler_Introspect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT: config.JWTConfig{
+			Secret:     "test-secret-key-for-testing",
+			Expiration: time.Hour,
+		},
+	}
+	handler := NewAuthHandler(cfg, token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration), zap.NewNop())
+	router := gin.New()
+	router.POST("/auth/introspect", handler.Introspect)
+
+	valid, err := handler.generateToken("testuser")
+	assert.NoError(t, err)
+	other, err := token.NewHS256Manager("another-secret", time.Hour).Issue("testuser")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectActive   bool
+	}{
+		{
+			name:           "valid token",
+			contentType:    "application/json",
+			body:           `{"token":"` + valid + `"}`,
+			expectedStatus: http.StatusOK,
+			expectActive:   true,
+		},
+		{
+			name:           "valid token as form",
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "token=" + valid,
+			expectedStatus: http.StatusOK,
+			expectActive:   true,
+		},
+		{
+			name:           "token signed with another secret",
+			contentType:    "application/json",
+			body:           `{"token":"` + other + `"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "garbage token",
+			contentType:    "application/json",
+			body:           `{"token":"not-a-jwt"}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing token",
+			contentType:    "application/json",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/auth/introspect", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var response IntrospectResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectActive, response.Active)
+			if tt.expectActive {
+				assert.Equal(t, "testuser", response.Username)
+				assert.NotZero(t, response.ExpiresAt)
+			} else {
+				assert.Empty(t, response.Username)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_LoginFleetAdmin(t *testing.T) {
+	cfg := &config.Config{
+		JWT:  config.JWTConfig{Secret: "test-secret", Expiration: time.Hour},
+		Auth: config.AuthConfig{FleetAdmins: map[string]string{"kadikoy-admin": "fleet-1"}},
+	}
+	tokens := token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration)
+	handler := NewAuthHandler(cfg, tokens, zap.NewNop())
+
+	router := setupGatewayRouter()
+	router.POST("/auth/login", handler.Login)
+
+	login := func(username string) *token.Claims {
+		body, _ := json.Marshal(map[string]string{"username": username, "password": "secret"})
+		req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp LoginResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		claims, err := tokens.Parse(resp.Token)
+		assert.NoError(t, err)
+		return claims
+	}
+
+	claims := login("kadikoy-admin")
+	assert.Equal(t, token.RoleFleetAdmin, claims.Role)
+	assert.Equal(t, "fleet-1", claims.FleetID)
+
+	claims = login("admin")
+	assert.Empty(t, claims.Role)
+	assert.Empty(t, claims.FleetID)
+}
+
+func TestAuthHandler_OIDC(t *testing.T) {
+	cfg := &config.Config{
+		JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour},
+		Auth: config.AuthConfig{
+			FleetAdmins: map[string]string{"ayse@kadikoytaksi.com": "fleet-2"},
+			OIDC: config.OIDCConfig{
+				Issuer:      "https://sso.example.com",
+				ClientID:    "gateway",
+				RedirectURL: "https://gateway.bitaksi.com/auth/oidc/callback",
+				RoleClaim:   "groups",
+				Roles:       map[string]config.OIDCRole{"ops": {}, "kadikoy": {Role: token.RoleFleetAdmin, FleetID: "fleet-1"}},
+			},
+		},
+	}
+	tokens := token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration)
+
+	t.Run("not configured", func(t *testing.T) {
+		router := setupGatewayRouter()
+		router.GET("/auth/oidc/login", NewAuthHandler(cfg, tokens, zap.NewNop()).OIDCLogin)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/auth/oidc/login", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	provider, err := oidc.NewProvider(cfg.Auth.OIDC)
+	assert.NoError(t, err)
+	handler := NewAuthHandler(cfg, tokens, zap.NewNop())
+	handler.EnableOIDC(provider)
+	router := setupGatewayRouter()
+	router.GET("/auth/oidc/callback", handler.OIDCCallback)
+
+	callback := func(query string, login *oidc.Login) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/auth/oidc/callback?"+query, nil)
+		if login != nil {
+			req.AddCookie(&http.Cookie{Name: oidcCookie, Value: provider.Seal(login)})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	login := &oidc.Login{State: "state-1", Nonce: "nonce", Verifier: "verifier", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+
+	t.Run("missing login cookie", func(t *testing.T) {
+		w := callback("state=state-1&code=abc", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_STATE")
+	})
+
+	t.Run("state of another login", func(t *testing.T) {
+		w := callback("state=state-2&code=abc", login)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_STATE")
+	})
+
+	t.Run("refused by the provider", func(t *testing.T) {
+		w := callback("state=state-1&error=access_denied", login)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Header().Get("Set-Cookie"), "Max-Age=0", "the login cookie is cleared")
+	})
+
+	t.Run("role mapping", func(t *testing.T) {
+		identity := func(username string, groups ...interface{}) *oidc.Identity {
+			return &oidc.Identity{Username: username, Claims: jwt.MapClaims{"groups": groups}}
+		}
+		claimsOf := func(opts []token.IssueOption) *token.Claims {
+			issued, err := tokens.Issue("user", opts...)
+			assert.NoError(t, err)
+			claims, err := tokens.Parse(issued)
+			assert.NoError(t, err)
+			return claims
+		}
+
+		opts, ok := handler.ssoAccess(identity("mehmet@bitaksi.com", "ops"))
+		assert.True(t, ok)
+		assert.Empty(t, claimsOf(opts).Role)
+
+		opts, ok = handler.ssoAccess(identity("ayse@kadikoytaksi.com", "kadikoy"))
+		assert.True(t, ok)
+		assert.Equal(t, "fleet-1", claimsOf(opts).FleetID, "the role claim wins over FLEET_ADMINS")
+
+		opts, ok = handler.ssoAccess(identity("ayse@kadikoytaksi.com"))
+		assert.True(t, ok)
+		assert.Equal(t, "fleet-2", claimsOf(opts).FleetID)
+
+		_, ok = handler.ssoAccess(identity("guest@example.com", "drivers"))
+		assert.False(t, ok)
+	})
+}
+
+func TestAuthHandler_PasswordReset(t *testing.T) {
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour}}
+	handler := NewAuthHandler(cfg, token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration), zap.NewNop())
+
+	router := setupGatewayRouter()
+	router.POST("/auth/login", handler.Login)
+	router.POST("/auth/password-reset/request", handler.RequestPasswordReset)
+	router.POST("/auth/password-reset/confirm", handler.ConfirmPasswordReset)
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/auth/password-reset/request", map[string]string{"email": "dispatch@example.com"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "users.json")
+	users := `{"users": [{"username": "dispatcher", "email": "dispatch@example.com", "passwordHash": "` + string(hash) + `"}]}`
+	assert.NoError(t, os.WriteFile(path, []byte(users), 0o600))
+	store, err := account.Load(path)
+	assert.NoError(t, err)
+	sender, err := account.NewSender(config.MailConfig{Sender: "log"}, zap.NewNop())
+	assert.NoError(t, err)
+	reset, err := account.NewReset(config.PasswordResetConfig{MaxPerIP: 20}, store, sender, zap.NewNop())
+	assert.NoError(t, err)
+	handler.EnableAccounts(store, reset)
+
+	// With a users file, logins need the right password
+	w = post("/auth/login", map[string]string{"username": "dispatcher", "password": "wrong-password"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = post("/auth/login", map[string]string{"username": "dispatcher", "password": "old-password"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = post("/auth/password-reset/request", map[string]string{"email": "not-an-email"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = post("/auth/password-reset/request", map[string]string{"email": "nobody@example.com"})
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = post("/auth/password-reset/confirm", map[string]string{"token": "forged.token", "password": "new-password"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_RESET_TOKEN")
+}
+
+func TestAuthHandler_ExchangeToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT:  config.JWTConfig{Secret: "test-secret-key-for-testing", Expiration: time.Hour},
+		Auth: config.AuthConfig{FleetAdmins: map[string]string{"dispatch": "6570a1f2c3d4e5f6a7b8c9d0"}},
+	}
+	handler := NewAuthHandler(cfg, token.NewHS256Manager(cfg.JWT.Secret, cfg.JWT.Expiration), zap.NewNop())
+	router := gin.New()
+	router.POST("/auth/token/exchange", handler.ExchangeToken)
+
+	exchange := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/auth/token/exchange", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	subject, err := handler.generateToken("dispatch")
+	assert.NoError(t, err)
+	grant := "grant_type=urn:ietf:params:oauth:grant-type:token-exchange&subject_token_type=urn:ietf:params:oauth:token-type:access_token&subject_token="
+
+	t.Run("not configured", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, exchange(grant+subject).Code)
+	})
+
+	handler.EnableTokenExchange(token.NewExchanger(config.TokenExchangeConfig{Secret: "exchange-secret", TTL: 5 * time.Minute}, ""))
+
+	t.Run("exchanges a token scoped to the driver service", func(t *testing.T) {
+		w := exchange(grant + subject + "&scope=drivers:read+drivers:write")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		var response TokenExchangeResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Bearer", response.TokenType)
+		assert.Equal(t, tokenTypeAccessToken, response.IssuedTokenType)
+		assert.Equal(t, int64(300), response.ExpiresIn)
+		assert.Equal(t, "drivers:read drivers:write", response.Scope)
+
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(response.AccessToken, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("exchange-secret"), nil
+		}, jwt.WithAudience(token.AudienceDriverService))
+		assert.NoError(t, err)
+		assert.Equal(t, "dispatch", claims["sub"])
+		assert.Equal(t, token.RoleFleetAdmin, claims["role"])
+		assert.Equal(t, "6570a1f2c3d4e5f6a7b8c9d0", claims["fleetId"])
+
+		// The exchanged token is no access token of the gateway
+		_, err = handler.tokens.Parse(response.AccessToken)
+		assert.ErrorIs(t, err, token.ErrInvalidToken)
+	})
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode string
+	}{
+		{name: "other grant type", body: "grant_type=password&subject_token_type=urn:ietf:params:oauth:token-type:access_token&subject_token=" + subject, expectedCode: "UNSUPPORTED_GRANT_TYPE"},
+		{name: "invalid subject token", body: grant + "not-a-jwt", expectedCode: "INVALID_GRANT"},
+		{name: "unknown audience", body: grant + subject + "&audience=billing-service", expectedCode: "INVALID_TARGET"},
+		{name: "unknown scope", body: grant + subject + "&scope=drivers:delete", expectedCode: "INVALID_SCOPE"},
+		{name: "missing subject token", body: "grant_type=urn:ietf:params:oauth:grant-type:token-exchange", expectedCode: "VALIDATION_ERROR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := exchange(tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedCode)
+		})
+	}
 }
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceTokenHandler proxies driver device token requests to the driver
+// service. Tokens revoked or rotated through it stop working on this gateway
+// at once; other gateways stop accepting them within DEVICE_TOKEN_CACHE_TTL_SEC.
+type DeviceTokenHandler struct {
+	driverService *service.DriverServiceClient
+	devices       *middleware.DeviceAuthenticator
+	logger        *zap.Logger
+}
+
+// NewDeviceTokenHandler creates a new device token handler; devices may be nil
+func NewDeviceTokenHandler(driverService *service.DriverServiceClient, devices *middleware.DeviceAuthenticator, logger *zap.Logger) *DeviceTokenHandler {
+	return &DeviceTokenHandler{
+		driverService: driverService,
+		devices:       devices,
+		logger:        logger,
+	}
+}
+
+// IssueDeviceToken handles POST /drivers/:id/device-tokens
+// @Summary Issue a device token
+// @Description Issue a long-lived token for one of the driver's devices. Sent as "Bearer dtk_...", it only allows PUT /drivers/{id}/location and POST /drivers/{id}/heartbeat for this driver. Issuing a token for a device revokes the device's previous token; a driver can have 10 active tokens. The token is only returned in this response.
+// @Tags device-tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body IssueDeviceTokenRequest true "Device"
+// @Success 201 {object} DeviceToken "Token issued"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 409 {object} ErrorResponse "Too many active tokens"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/device-tokens [post]
+func (h *DeviceTokenHandler) IssueDeviceToken(c *gin.Context) {
+	var req IssueDeviceTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).IssueDeviceToken(c.Param("id"), req)
+	if err != nil {
+		h.logger.Error("failed to forward issue device token request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to issue device token")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListDriverDeviceTokens handles GET /drivers/:id/device-tokens
+// @Summary List a driver's device tokens
+// @Description List the driver's device tokens, newest first, without their values
+// @Tags device-tokens
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param active query bool false "Only list tokens that are not revoked"
+// @Success 200 {array} DeviceToken "Tokens"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/device-tokens [get]
+func (h *DeviceTokenHandler) ListDriverDeviceTokens(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).ListDriverDeviceTokens(c.Param("id"), c.Query("active"))
+	if err != nil {
+		h.logger.Error("failed to forward list device tokens request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list device tokens")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListDeviceTokens handles GET /admin/device-tokens
+// @Summary List device tokens
+// @Description List the device tokens of every driver, or of one, newest first, without their values
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param driverId query string false "Only list tokens of this driver"
+// @Param active query bool false "Only list tokens that are not revoked"
+// @Success 200 {array} DeviceToken "Tokens"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Router /admin/device-tokens [get]
+func (h *DeviceTokenHandler) ListDeviceTokens(c *gin.Context) {
+	resp, err := h.driverService.ListDeviceTokens(c.Query("driverId"), c.Query("active"))
+	if err != nil {
+		h.logger.Error("failed to forward list device tokens request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list device tokens")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// RevokeDeviceToken handles DELETE /admin/device-tokens/:id
+// @Summary Revoke a device token
+// @Description Revoke a device token, e.g. of a lost phone. Revoking a revoked token succeeds.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Token ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} DeviceToken "Revoked token"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Router /admin/device-tokens/{id} [delete]
+func (h *DeviceTokenHandler) RevokeDeviceToken(c *gin.Context) {
+	resp, err := h.driverService.RevokeDeviceToken(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward revoke device token request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to revoke device token")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forget(c.Param("id"), resp)
+	forwardResponse(c, resp, h.logger)
+}
+
+// RotateDeviceToken handles POST /admin/device-tokens/:id/rotate
+// @Summary Rotate a device token
+// @Description Issue a new token for the same device and revoke this one. The new token is only returned in this response.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Token ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 201 {object} DeviceToken "New token"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "Token not found"
+// @Failure 409 {object} ErrorResponse "Token is revoked"
+// @Router /admin/device-tokens/{id}/rotate [post]
+func (h *DeviceTokenHandler) RotateDeviceToken(c *gin.Context) {
+	resp, err := h.driverService.RotateDeviceToken(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward rotate device token request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to rotate device token")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forget(c.Param("id"), resp)
+	forwardResponse(c, resp, h.logger)
+}
+
+// forget drops a token the driver service revoked from the verification cache
+func (h *DeviceTokenHandler) forget(id string, resp *http.Response) {
+	if h.devices != nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		h.devices.Forget(id)
+	}
+}
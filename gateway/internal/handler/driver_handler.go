@@ -1,18 +1,47 @@
 package handler
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"math"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/bitaksi/driver-service/pkg/geo"
+	"github.com/bitaksi/driver-service/pkg/plate"
+	"github.com/bitaksi/driver-service/pkg/units"
+	"github.com/bitaksi/gateway/internal/coalesce"
+	"github.com/bitaksi/gateway/internal/locationwire"
 	"github.com/bitaksi/gateway/internal/service"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// maxKYCWebhookBody bounds the KYC webhook body read before forwarding
+const maxKYCWebhookBody = 1 << 20
+
 // DriverHandler handles HTTP requests for drivers in the gateway
 type DriverHandler struct {
 	driverService *service.DriverServiceClient
 	logger        *zap.Logger
+
+	// nearby shares nearby searches for the same rounded location; nil forwards every search
+	nearby          *coalesce.Group
+	nearbyPrecision int
+	// nearbyMaxAge is how long clients may reuse a nearby search; 0 sends no caching headers
+	nearbyMaxAge time.Duration
+	// skipPlates forwards plates unchecked for the driver service to validate
+	skipPlates bool
+	// pricing estimates fares for nearby searches with includeFares; nil leaves them out
+	pricing *service.PricingServiceClient
+	// pricingTimeout bounds the estimates of one search; 0 leaves them to the client timeout
+	pricingTimeout time.Duration
 }
 
 // NewDriverHandler creates a new driver handler
@@ -23,6 +52,29 @@ func NewDriverHandler(driverService *service.DriverServiceClient, logger *zap.Lo
 	}
 }
 
+// CoalesceNearby shares nearby searches whose coordinates agree to precision
+// decimal places. Searches are answered for the rounded location.
+func (h *DriverHandler) CoalesceNearby(group *coalesce.Group, precision int) *DriverHandler {
+	h.nearby = group
+	h.nearbyPrecision = precision
+	return h
+}
+
+// CacheNearby lets clients and HTTP caches reuse successful nearby searches
+// for maxAge, e.g. while a rider pans the map
+func (h *DriverHandler) CacheNearby(maxAge time.Duration) *DriverHandler {
+	h.nearbyMaxAge = maxAge
+	return h
+}
+
+// QuoteFares lets nearby searches with includeFares add the fare of a trip
+// with each driver, estimated by pricing within timeout
+func (h *DriverHandler) QuoteFares(pricing *service.PricingServiceClient, timeout time.Duration) *DriverHandler {
+	h.pricing = pricing
+	h.pricingTimeout = timeout
+	return h
+}
+
 // CreateDriver handles POST /drivers
 // @Summary Create a new driver
 // @Description Create a new taxi driver
@@ -31,18 +83,27 @@ func NewDriverHandler(driverService *service.DriverServiceClient, logger *zap.Lo
 // @Produce json
 // @Security BearerAuth
 // @Param driver body CreateDriverRequest true "Driver information"
+// @Param X-Device-Fingerprint header string false "Device fingerprint; registrations are capped per device and per IP each day"
 // @Success 201 {object} Driver "Driver created successfully"
 // @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Fleet admin creating a driver in another fleet, or BLACKLISTED for a driver on the blacklist"
+// @Failure 409 {object} ErrorResponse "Plate, phone or email taken, or REQUEST_IN_PROGRESS while another request creates the same plate, phone or email"
+// @Failure 429 {object} ErrorResponse "Daily registration cap reached for this device or IP"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and fails closed"
 // @Router /drivers [post]
 func (h *DriverHandler) CreateDriver(c *gin.Context) {
-	var body map[string]interface{}
-	if err := c.ShouldBindJSON(&body); err != nil {
+	body, err := bindRequestBody(c)
+	if err != nil {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 		return
 	}
 
-	resp, err := h.driverService.CreateDriver(body)
+	if !h.normalizePlate(c, body) || !scopeNewDriver(c, body) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).CreateDriver(body.payload())
 	if err != nil {
 		h.logger.Error("failed to forward create driver request", zap.Error(err))
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create driver")
@@ -53,6 +114,38 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 	h.forwardResponse(c, resp)
 }
 
+// GetDriverEarnings handles GET /drivers/:id/earnings
+// @Summary Get driver earnings
+// @Description Trip earnings, commission and adjustments per day or per week (starting Monday) in the service's time zone. The range defaults to the last 30 days (daily) or 12 weeks (weekly), max 366 days.
+// @Tags drivers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param period query string false "Bucket size" Enums(daily, weekly) default(daily)
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Success 200 {object} EarningsSummary "Driver earnings"
+// @Failure 400 {object} ErrorResponse "Invalid period or range"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/earnings [get]
+func (h *DriverHandler) GetDriverEarnings(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetDriverEarnings(c.Param("id"), c.Query("period"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.logger.Error("failed to forward driver earnings request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get driver earnings")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
 // UpdateDriver handles PUT /drivers/:id
 // @Summary Update a driver
 // @Description Update an existing driver. Location uses top-level lat/lon fields (same format as create).
@@ -61,10 +154,12 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
-// @Param driver body UpdateDriverRequest true "Driver update information" example({"firstName":"Ali","lastName":"Kurt","plate":"34G99","taksiType":"siyah","carBrand":"Mercedes","carModel":"G Class","lat":42.0082,"lon":28.9784})
-// @Success 200 {object} Driver "Driver updated successfully" example({"id":"507f1f77bcf86cd799439011","firstName":"Ali","lastName":"Kurt","plate":"34G99","taxiType":"siyah","carBrand":"Mercedes","carModel":"G Class","location":{"lat":42.0082,"lon":28.9784},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:30:00Z"})
-// @Failure 400 {object} ErrorResponse "Validation error" example({"error":{"code":"VALIDATION_ERROR","message":"both lat and lon must be provided together"}})
+// @Param driver body UpdateDriverRequest true "Driver update information" example({"firstName":"Ali","lastName":"Kurt","plate":"34G1234","taksiType":"siyah","carBrand":"Mercedes","carModel":"G Class","lat":42.0082,"lon":28.9784})
+// @Success 200 {object} Driver "Driver updated successfully" example({"id":"507f1f77bcf86cd799439011","firstName":"Ali","lastName":"Kurt","plate":"34G1234","taxiType":"siyah","carBrand":"Mercedes","carModel":"G Class","location":{"lat":42.0082,"lon":28.9784},"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:30:00Z"})
+// @Failure 400 {object} ErrorResponse "Validation error, or IMPLAUSIBLE_LOCATION for a rejected location jump" example({"error":{"code":"VALIDATION_ERROR","message":"both lat and lon must be provided together"}})
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
 // @Failure 404 {object} ErrorResponse "Driver not found" example({"error":{"code":"NOT_FOUND","message":"driver not found"}})
+// @Failure 409 {object} ErrorResponse "Plate, phone or email taken, or REQUEST_IN_PROGRESS while another request changes the same driver, plate, phone or email"
 // @Failure 500 {object} ErrorResponse "Internal server error" example({"error":{"code":"INTERNAL_ERROR","message":"failed to update driver"}})
 // @Router /drivers/{id} [put]
 func (h *DriverHandler) UpdateDriver(c *gin.Context) {
@@ -74,13 +169,17 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 		return
 	}
 
-	var body map[string]interface{}
-	if err := c.ShouldBindJSON(&body); err != nil {
+	body, err := bindRequestBody(c)
+	if err != nil {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
 		return
 	}
 
-	resp, err := h.driverService.UpdateDriver(id, body)
+	if !h.normalizePlate(c, body) || !h.authorizeDriver(c, id) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).UpdateDriver(id, body.payload())
 	if err != nil {
 		h.logger.Error("failed to forward update driver request", zap.Error(err))
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update driver")
@@ -108,7 +207,7 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.driverService.GetDriver(id)
+	resp, err := forCaller(c, h.driverService).GetDriver(id)
 	if err != nil {
 		h.logger.Error("failed to forward get driver request", zap.Error(err))
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get driver")
@@ -119,6 +218,29 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 	h.forwardResponse(c, resp)
 }
 
+// HeadDriver handles HEAD /drivers/:id
+// @Summary Check a driver exists
+// @Description Answers like GET /drivers/{id} without a body. The driver service is asked with HEAD too, so the driver is not transferred.
+// @Tags drivers
+// @Param id path string true "Driver ID"
+// @Success 200 "Driver exists"
+// @Failure 404 "Driver not found"
+// @Failure 500 "Internal server error"
+// @Router /drivers/{id} [head]
+func (h *DriverHandler) HeadDriver(c *gin.Context) {
+	c.Writer = &headWriter{ResponseWriter: c.Writer}
+
+	resp, err := forCaller(c, h.driverService).HeadDriver(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward head driver request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get driver")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
 // ListDrivers handles GET /drivers
 // @Summary List drivers
 // @Description Get a paginated list of drivers
@@ -126,15 +248,23 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(20)
+// @Param fleetId query string false "Only list drivers of this fleet"
+// @Param city query string false "Only list drivers in this city; other lists those outside every configured city" example(istanbul)
+// @Param tags query string false "Only list drivers carrying all of these comma-separated tags" example(pet-friendly,wheelchair-accessible)
+// @Param attributes query string false "Only list drivers with all of these comma-separated key:value attributes; a key alone matches any value" example(language:en)
 // @Success 200 {object} ListDriversResponse "Paginated list of drivers"
 // @Failure 400 {object} ErrorResponse "Validation error"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /drivers [get]
 func (h *DriverHandler) ListDrivers(c *gin.Context) {
-	page := c.DefaultQuery("page", "")
-	pageSize := c.DefaultQuery("pageSize", "")
+	var query ListDriversQuery
+	// String fields always bind; the driver service validates the values
+	_ = c.ShouldBindQuery(&query)
+	if scope, scoped := scopedFleet(c); scoped {
+		query.FleetID = scope
+	}
 
-	resp, err := h.driverService.ListDrivers(page, pageSize)
+	resp, err := forCaller(c, h.driverService).ListDrivers(query)
 	if err != nil {
 		h.logger.Error("failed to forward list drivers request", zap.Error(err))
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list drivers")
@@ -145,15 +275,91 @@ func (h *DriverHandler) ListDrivers(c *gin.Context) {
 	h.forwardResponse(c, resp)
 }
 
+// GetDriverChanges handles GET /drivers/changes
+// @Summary Get driver changes
+// @Description Get the drivers created, updated and deleted since a marker so apps can update an offline cache. Pass nextToken as since to continue while hasMore is true, and keep the last nextToken for the next sync.
+// @Tags drivers
+// @Produce json
+// @Param since query string false "RFC3339 timestamp or nextToken from an earlier response; omit for a full sync"
+// @Param limit query int false "Maximum changes per response" default(100)
+// @Param fleetId query string false "Only sync drivers of this fleet"
+// @Success 200 {object} DriverChangesResponse "Changed drivers"
+// @Failure 400 {object} ErrorResponse "Invalid marker"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/changes [get]
+func (h *DriverHandler) GetDriverChanges(c *gin.Context) {
+	fleetID := c.Query("fleetId")
+	if scope, scoped := scopedFleet(c); scoped {
+		fleetID = scope
+	}
+
+	resp, err := forCaller(c, h.driverService).GetDriverChanges(c.Query("since"), c.Query("limit"), fleetID)
+	if err != nil {
+		h.logger.Error("failed to forward driver changes request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list driver changes")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// AutocompleteDrivers handles GET /drivers/autocomplete
+// @Summary Autocomplete drivers
+// @Description Suggest drivers as a dispatcher types. Every word of q must start a word of the driver's name, the plate or a letter or digit group of the plate, ignoring case and Turkish diacritics. Highlights mark the matched characters; timedOut is set, with no suggestions, when the search ran past its time budget.
+// @Tags drivers
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Start of the name or plate, up to 5 words" example(ahm)
+// @Param limit query int false "Maximum suggestions" example(8)
+// @Param fleetId query string false "Only suggest drivers of this fleet; fleet admins always get their own"
+// @Success 200 {object} AutocompleteResponse "Suggestions"
+// @Failure 400 {object} ErrorResponse "Invalid query"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/autocomplete [get]
+func (h *DriverHandler) AutocompleteDrivers(c *gin.Context) {
+	fleetID := c.Query("fleetId")
+	if scope, scoped := scopedFleet(c); scoped {
+		fleetID = scope
+	}
+
+	resp, err := forCaller(c, h.driverService).AutocompleteDrivers(c.Query("q"), c.Query("limit"), fleetID)
+	if err != nil {
+		h.logger.Error("failed to forward driver autocomplete request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to search drivers")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
 // FindNearbyDrivers handles GET /drivers/nearby
 // @Summary Find nearby drivers
-// @Description Find drivers within 6km radius
+// @Description Find drivers within 6km radius. Identical searches near the same spot are coalesced into one driver service call and cached for NEARBY_CACHE_TTL_MS; X-Cache tells whether the response was fetched for this search alone (MISS), shared between concurrent searches (SHARED) or cached (HIT). Successful results carry Cache-Control: private, max-age=NEARBY_CACHE_MAX_AGE_SEC so apps may reuse them while the map is panned. Callers other than fleet admins and tokens without a role, riders and API key callers included, get driver last names and plates masked unless RESPONSE_REDACTION_FILE says otherwise. While the driver service is unavailable an empty list is returned with X-Degraded: true, unless UPSTREAM_FALLBACK_FILE configures another stub or UPSTREAM_FALLBACK_ENABLED is off.
 // @Tags drivers
 // @Produce json
-// @Param lat query float64 true "Latitude"
-// @Param lon query float64 true "Longitude"
-// @Param taksiType query string false "Taxi type (sari, turkuaz, siyah)"
-// @Success 200 {array} NearbyDriverResponse "List of nearby drivers sorted by distance"
+// @Param lat query float64 false "Latitude; required unless geohash is given"
+// @Param lon query float64 false "Longitude; required unless geohash is given"
+// @Param geohash query string false "Search around the center of this geohash cell instead of lat and lon" example(sxk9kp)
+// @Param taksiType query string false "Taxi type, one of GET /taxi-types"
+// @Param fleetId query string false "Only return drivers of this fleet"
+// @Param city query string false "Only return drivers in this city; other returns those outside every configured city" example(istanbul)
+// @Param live query bool false "Only return drivers with a recent heartbeat; defaults to the driver service setting"
+// @Param tags query string false "Only return drivers carrying all of these comma-separated tags" example(pet-friendly)
+// @Param attributes query string false "Only return drivers with all of these comma-separated key:value attributes; a key alone matches any value" example(language:en)
+// @Param units query string false "Also return distance in this unit, rounded for display; distanceKm stays in kilometers" Enums(km, m, mi) example(m)
+// @Param limit query int false "Most drivers returned, nearest first; defaults to NEARBY_DEFAULT_LIMIT of the driver service and is capped at its NEARBY_MAX_LIMIT"
+// @Param offset query int false "Nearest drivers to skip, to page through a crowded area" default(0)
+// @Param includeFares query bool false "Add the fare of a trip with each driver from the pricing service; drivers it could not price within PRICING_TIMEOUT_MS are returned without one"
+// @Param dropoffLat query float64 false "Latitude the rider is going to, priced with includeFares; give with dropoffLon"
+// @Param dropoffLon query float64 false "Longitude the rider is going to, priced with includeFares; give with dropoffLat"
+// @Success 200 {array} NearbyDriverWithFare "List of nearby drivers sorted by distance, with fares when includeFares is set"
+// @Header 200 {string} Cache-Control "private, max-age=NEARBY_CACHE_MAX_AGE_SEC; no-store on stubs"
+// @Header 200 {string} X-Degraded "true when the list is a stub served while the driver service is unavailable"
+// @Header 200 {string} X-Distance-Unit "Unit of distance, also given as meta.distanceUnit in the envelope"
+// @Header 200 {string} X-Fares "With includeFares: complete when every driver has a fare, partial when some do, unavailable when none could be priced; also given as meta.fares in the envelope"
 // @Failure 400 {object} ErrorResponse "Validation error"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Router /drivers/nearby [get]
@@ -162,43 +368,844 @@ func (h *DriverHandler) FindNearbyDrivers(c *gin.Context) {
 	lon := c.Query("lon")
 	taksiType := c.Query("taksiType")
 
+	if geohash := c.Query("geohash"); geohash != "" {
+		if lat != "" || lon != "" {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "give either lat and lon or geohash, not both")
+			return
+		}
+		// Searches by geohash are searches around the cell center, so they coalesce with coordinate searches
+		latValue, lonValue, err := geo.Decode(geohash)
+		if err != nil {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "invalid geohash")
+			return
+		}
+		lat = strconv.FormatFloat(latValue, 'f', -1, 64)
+		lon = strconv.FormatFloat(lonValue, 'f', -1, 64)
+	}
+
 	if lat == "" || lon == "" {
 		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "lat and lon are required")
 		return
 	}
+	unit, ok := h.distanceUnit(c)
+	if !ok {
+		return
+	}
+	fares, ok := h.fareQuery(c, lat, lon)
+	if !ok {
+		return
+	}
+
+	fleetID := c.Query("fleetId")
+	if scope, scoped := scopedFleet(c); scoped {
+		fleetID = scope
+	}
+
+	city, tags, attributes := c.Query("city"), c.Query("tags"), c.Query("attributes")
+	limit, offset := c.Query("limit"), c.Query("offset")
+	if h.nearby != nil {
+		h.findNearbyCoalesced(c, lat, lon, taksiType, fleetID, city, c.Query("live"), tags, attributes, unit, limit, offset, fares)
+		return
+	}
 
-	resp, err := h.driverService.FindNearbyDrivers(lat, lon, taksiType)
+	resp, err := forCaller(c, h.driverService).FindNearbyDrivers(lat, lon, taksiType, fleetID, city, c.Query("live"), tags, attributes, unit, limit, offset)
 	if err != nil {
 		h.logger.Error("failed to forward find nearby drivers request", zap.Error(err))
 		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to find nearby drivers")
 		return
 	}
 	defer resp.Body.Close()
+	if fares == nil {
+		h.cacheNearby(c, resp.StatusCode)
+		h.forwardResponse(c, resp)
+		return
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("failed to read response body", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to read response")
+		return
+	}
+	h.cacheNearby(c, resp.StatusCode)
+	writeResponse(c, resp.StatusCode, resp.Header, h.addFares(c, resp.StatusCode, body, fares))
+}
+
+// FindDriversAlongRoute handles POST /drivers/nearby/route
+// @Summary Find drivers along a route
+// @Description Find drivers within widthKm (default 0.5, at most 2) of the route through 2 to 25 ordered waypoints, up to 100 km long. Each driver is returned once, sorted by distance to the route; routeKm tells where along the route the driver is.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param route body RouteSearchRequest true "Route and corridor"
+// @Param units query string false "Also return distance and routeDistance in this unit, rounded for display; distanceKm and routeKm stay in kilometers" Enums(km, m, mi) example(m)
+// @Success 200 {array} RouteDriverResponse "Drivers along the route sorted by distance to it"
+// @Header 200 {string} X-Distance-Unit "Unit of distance and routeDistance, also given as meta.distanceUnit in the envelope"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/nearby/route [post]
+func (h *DriverHandler) FindDriversAlongRoute(c *gin.Context) {
+	unit, ok := h.distanceUnit(c)
+	if !ok {
+		return
+	}
+	body, err := bindRequestBody(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if scope, scoped := scopedFleet(c); scoped {
+		body.set("fleetId", scope)
+	}
+
+	resp, err := forCaller(c, h.driverService).FindDriversAlongRoute(body.payload(), unit)
+	if err != nil {
+		h.logger.Error("failed to forward find drivers along route request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to find drivers along route")
+		return
+	}
+	defer resp.Body.Close()
 
 	h.forwardResponse(c, resp)
 }
 
-// forwardResponse forwards the response from the driver service to the client
-func (h *DriverHandler) forwardResponse(c *gin.Context, resp *http.Response) {
-	// Copy status code
-	c.Status(resp.StatusCode)
+// BulkUpdateDrivers handles POST /drivers/bulk-update
+// @Summary Update many drivers at once
+// @Description Apply a patch to every driver matching a filter in a single update, e.g. suspend a fleet for inspection. Fleet admins only reach their own fleet. A filter matching more than BULK_UPDATE_MAX_DRIVERS drivers is refused; set dryRun to only count the drivers. Every update is recorded with the caller for the audit trail.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param update body BulkUpdateRequest true "Filter, patch and dry run"
+// @Success 200 {object} BulkUpdate "Matched and modified drivers"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 409 {object} ErrorResponse "Filter matches too many drivers"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/bulk-update [post]
+func (h *DriverHandler) BulkUpdateDrivers(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	// Fleet admins are scoped to their fleet by the driver service from the forwarded identity
+	resp, err := forCaller(c, h.driverService).BulkUpdateDrivers(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward bulk update request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update drivers")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// findNearbyCoalesced answers a nearby search from a concurrent or recent
+// identical search when there is one. Coordinates that do not parse are
+// forwarded as they are so the driver service reports the error.
+func (h *DriverHandler) findNearbyCoalesced(c *gin.Context, lat, lon, taksiType, fleetID, city, live, tags, attributes, unit, limit, offset string, fares *fareQuery) {
+	if latValue, err := strconv.ParseFloat(lat, 64); err == nil && !math.IsNaN(latValue) && !math.IsInf(latValue, 0) {
+		lat = strconv.FormatFloat(roundTo(latValue, h.nearbyPrecision), 'f', h.nearbyPrecision, 64)
+	}
+	if lonValue, err := strconv.ParseFloat(lon, 64); err == nil && !math.IsNaN(lonValue) && !math.IsInf(lonValue, 0) {
+		lon = strconv.FormatFloat(roundTo(lonValue, h.nearbyPrecision), 'f', h.nearbyPrecision, 64)
+	}
+	key := lat + "|" + lon + "|" + taksiType + "|" + fleetID + "|" + city + "|" + live + "|" + tags + "|" + attributes + "|" + unit + "|" + limit + "|" + offset
+
+	client := forCaller(c, h.driverService)
+	resp, outcome, err := h.nearby.Do(key, func() (*http.Response, error) {
+		return client.FindNearbyDrivers(lat, lon, taksiType, fleetID, city, live, tags, attributes, unit, limit, offset)
+	})
+	if err != nil {
+		h.logger.Error("failed to forward find nearby drivers request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to find nearby drivers")
+		return
+	}
+
+	c.Header("X-Cache", string(outcome))
+	h.cacheNearby(c, resp.StatusCode)
+	// Fares are estimated per search, after the shared driver list, so they are
+	// neither coalesced nor cached and each rider's dropoff is priced
+	writeResponse(c, resp.StatusCode, resp.Header, h.addFares(c, resp.StatusCode, resp.Body, fares))
+}
+
+// distanceUnit validates the units of a nearby search and records the unit
+// distances come in for the response envelope. The unit is forwarded as given,
+// empty when the caller asked for none.
+func (h *DriverHandler) distanceUnit(c *gin.Context) (string, bool) {
+	unit, err := units.Parse(c.Query("units"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "units must be km, m or mi")
+		return "", false
+	}
+	c.Set("distanceUnit", string(unit))
+	if c.Query("units") == "" {
+		return "", true
+	}
+	return string(unit), true
+}
+
+// nearbyVary are the request headers a nearby search response depends on: the
+// token decides the fleet scope and redaction, the envelope header the body shape
+var nearbyVary = []string{"Authorization", "X-Response-Envelope"}
+
+// cacheNearby marks a successful nearby search as reusable by the caller for
+// nearbyMaxAge. Errors are left uncached so a retry reaches the gateway.
+func (h *DriverHandler) cacheNearby(c *gin.Context, status int) {
+	if h.nearbyMaxAge <= 0 || status < 200 || status >= 300 {
+		return
+	}
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(h.nearbyMaxAge/time.Second)))
+	addVary(c, nearbyVary...)
+}
+
+// Fare outcomes of a nearby search with includeFares, sent as X-Fares and meta.fares
+const (
+	faresComplete    = "complete"
+	faresPartial     = "partial"
+	faresUnavailable = "unavailable"
+)
+
+// fareQuery is where the rider of a nearby search with includeFares is picked
+// up and, if known, dropped off
+type fareQuery struct {
+	pickup  Location
+	dropoff *Location
+}
+
+// fareQuery validates includeFares and the dropoff of a nearby search around
+// lat and lon. It returns nil when fares were not asked for.
+func (h *DriverHandler) fareQuery(c *gin.Context, lat, lon string) (*fareQuery, bool) {
+	include := false
+	if value := c.Query("includeFares"); value != "" {
+		var err error
+		if include, err = strconv.ParseBool(value); err != nil {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "includeFares must be true or false")
+			return nil, false
+		}
+	}
+	if !include {
+		return nil, true
+	}
+
+	// A pickup the driver service cannot parse is answered by its own validation error
+	query := &fareQuery{}
+	query.pickup.Lat, _ = strconv.ParseFloat(lat, 64)
+	query.pickup.Lon, _ = strconv.ParseFloat(lon, 64)
+	dropoffLat, dropoffLon := c.Query("dropoffLat"), c.Query("dropoffLon")
+	if dropoffLat == "" && dropoffLon == "" {
+		return query, true
+	}
+	latValue, latErr := strconv.ParseFloat(dropoffLat, 64)
+	lonValue, lonErr := strconv.ParseFloat(dropoffLon, 64)
+	if latErr != nil || lonErr != nil || latValue < -90 || latValue > 90 || lonValue < -180 || lonValue > 180 {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "dropoffLat and dropoffLon must be given together as valid coordinates")
+		return nil, false
+	}
+	query.dropoff = &Location{Lat: latValue, Lon: lonValue}
+	return query, true
+}
+
+// addFares adds the fare of a trip with each driver of a successful nearby
+// search to body. Drivers the pricing service could not price in time are
+// left without one rather than failing the search; X-Fares tells the caller
+// whether the fares are complete. Other responses are returned as they are.
+func (h *DriverHandler) addFares(c *gin.Context, status int, body []byte, query *fareQuery) []byte {
+	if query == nil || status < 200 || status >= 300 {
+		return body
+	}
+	var drivers []json.RawMessage
+	if err := json.Unmarshal(body, &drivers); err != nil {
+		return body
+	}
+	outcome := func(value string) {
+		c.Header("X-Fares", value)
+		c.Set("fares", value)
+	}
+	if h.pricing == nil {
+		outcome(faresUnavailable)
+		return body
+	}
+
+	quotes := make([]service.FareQuoteRequest, len(drivers))
+	for i, raw := range drivers {
+		var driver NearbyDriverResponse
+		_ = json.Unmarshal(raw, &driver)
+		quotes[i] = service.FareQuoteRequest{
+			DriverID:          driver.ID,
+			TaxiType:          driver.TaxiType,
+			Pickup:            query.pickup,
+			Dropoff:           query.dropoff,
+			PickupDistanceKm:  driver.DistanceKm,
+			PickupDurationSec: driver.DurationSec,
+		}
+	}
+
+	ctx, cancel := c.Request.Context(), func() {}
+	if h.pricingTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, h.pricingTimeout)
+	}
+	defer cancel()
+	fares, failed := h.pricing.WithRequestID(c.GetString("requestID")).EstimateFares(ctx, quotes)
+	switch {
+	case failed == 0:
+		outcome(faresComplete)
+	case failed == len(drivers):
+		outcome(faresUnavailable)
+		return body
+	default:
+		outcome(faresPartial)
+	}
+
+	for i, fare := range fares {
+		if fare == nil {
+			continue
+		}
+		if withFare, ok := withField(drivers[i], "fare", fare); ok {
+			drivers[i] = withFare
+		}
+	}
+	enriched, err := json.Marshal(drivers)
+	if err != nil {
+		return body
+	}
+	return enriched
+}
+
+// withField appends a field to the JSON object raw, keeping the fields it has
+// in their order
+func withField(raw json.RawMessage, name string, value interface{}) (json.RawMessage, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) < 2 || raw[0] != '{' || raw[len(raw)-1] != '}' {
+		return nil, false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	field := append(strconv.AppendQuote(nil, name), ':')
+	field = append(field, data...)
+
+	out := make(json.RawMessage, 0, len(raw)+len(field)+1)
+	out = append(out, raw[:len(raw)-1]...)
+	if len(bytes.TrimSpace(raw[1:len(raw)-1])) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, field...)
+	return append(out, '}'), true
+}
+
+// roundTo rounds value to the given number of decimal places
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(value*scale) / scale
+}
+
+// SetAvailability handles PUT /drivers/:id/availability
+// @Summary Set driver availability
+// @Description Put a driver on or off shift. Going on shift requires a verified phone number.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param availability body SetAvailabilityRequest true "Availability"
+// @Success 200 {object} Driver "Driver availability updated"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 409 {object} ErrorResponse "Phone not verified, licence expired or driver suspended" example({"error":{"code":"CONTACT_NOT_VERIFIED","message":"phone must be verified before going on shift"}})
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/availability [put]
+func (h *DriverHandler) SetAvailability(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SetAvailability(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward set availability request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update driver")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// UpdateLocation handles PUT /drivers/:id/location
+// @Summary Report a driver's location
+// @Description Update the driver's position and nothing else. Driver apps call this with their device token (scope location:update); fleet admin tokens work too. Besides JSON the position can be sent compactly as application/x-protobuf (message LocationUpdate { double lat = 1; double lon = 2; }) or application/vnd.bitaksi.location (8 bytes: latitude and longitude as big-endian int32 in 1e-7 degrees). Compact reports are answered with 204 and no body unless Accept lists application/json.
+// @Tags drivers
+// @Accept json
+// @Accept application/x-protobuf
+// @Accept application/vnd.bitaksi.location
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param location body UpdateLocationRequest true "Position"
+// @Success 200 {object} Driver "Driver location updated"
+// @Success 204 "Compact location report accepted"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 401 {object} ErrorResponse "Missing, invalid or revoked token"
+// @Failure 403 {object} ErrorResponse "Device token of another driver or driver of another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/location [put]
+func (h *DriverHandler) UpdateLocation(c *gin.Context) {
+	var req UpdateLocationRequest
+	compact := locationwire.IsBinary(c.ContentType())
+	if compact {
+		loc, err := decodeBinaryLocation(c)
+		if err != nil {
+			h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		req.Lat, req.Lon = &loc.Lat, &loc.Lon
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	body := map[string]float64{"lat": *req.Lat, "lon": *req.Lon}
+	resp, err := forCaller(c, h.driverService).UpdateDriver(c.Param("id"), body)
+	if err != nil {
+		h.logger.Error("failed to forward location update", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update location")
+		return
+	}
+	defer resp.Body.Close()
+
+	// Compact reports are acknowledged without the driver unless it is asked for
+	if compact && resp.StatusCode < http.StatusMultipleChoices && !acceptsJSON(c) {
+		io.Copy(io.Discard, resp.Body)
+		c.Status(http.StatusNoContent)
+		return
+	}
+	h.forwardResponse(c, resp)
+}
+
+// maxBinaryLocationSize bounds binary location reports, which are a few dozen bytes
+const maxBinaryLocationSize = 1 << 10
 
-	// Copy headers
-	for key, values := range resp.Header {
-		for _, value := range values {
-			c.Header(key, value)
+// decodeBinaryLocation reads a location report in one of the binary formats
+func decodeBinaryLocation(c *gin.Context) (locationwire.Location, error) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBinaryLocationSize+1))
+	if err != nil {
+		return locationwire.Location{}, fmt.Errorf("failed to read location: %w", err)
+	}
+	if len(data) > maxBinaryLocationSize {
+		return locationwire.Location{}, fmt.Errorf("location report exceeds %d bytes", maxBinaryLocationSize)
+	}
+	return locationwire.Decode(c.ContentType(), data)
+}
+
+// acceptsJSON reports whether the client explicitly listed JSON in Accept;
+// wildcards do not count
+func acceptsJSON(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && (mediaType == gin.MIMEJSON || strings.HasSuffix(mediaType, "+json")) {
+			return true
 		}
 	}
+	return false
+}
+
+// SetSuspension handles PUT /drivers/:id/suspension
+// @Summary Suspend or reinstate a driver
+// @Description Suspended drivers are taken off shift, cannot go on shift again and are left out of nearby searches. Webhook subscribers receive a driver.suspended event.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param suspension body SetSuspensionRequest true "Suspension"
+// @Success 200 {object} Driver "Driver suspension updated"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/suspension [put]
+func (h *DriverHandler) SetSuspension(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SetSuspension(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward set suspension request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update driver")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// GetDriverStats handles GET /drivers/:id/stats
+// @Summary Get driver statistics
+// @Description Completed trips, distance driven, online hours and average rating over a date range (default: last 30 days, max 366 days)
+// @Tags drivers
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-11-01")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-01")
+// @Success 200 {object} DriverStats "Driver statistics"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/stats [get]
+func (h *DriverHandler) GetDriverStats(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetDriverStats(c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.logger.Error("failed to forward driver stats request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get driver stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// Heartbeat handles POST /drivers/:id/heartbeat
+// @Summary Record a driver heartbeat
+// @Description Mark the driver as seen now. Driver apps call this periodically with their device token (scope heartbeat); it takes no body.
+// @Tags drivers
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 204 "Heartbeat recorded"
+// @Failure 403 {object} ErrorResponse "Device token of another driver or driver of another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/heartbeat [post]
+func (h *DriverHandler) Heartbeat(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).Heartbeat(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward heartbeat", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to record heartbeat")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// GetOnlineStats handles GET /drivers/stats
+// @Summary Get online driver counts
+// @Description Count drivers that are online (recent heartbeat) and offline. Fleet admins only see their own fleet.
+// @Tags drivers
+// @Produce json
+// @Security BearerAuth
+// @Param fleetId query string false "Only count drivers of this fleet"
+// @Success 200 {object} OnlineStats "Online and offline counts"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/stats [get]
+func (h *DriverHandler) GetOnlineStats(c *gin.Context) {
+	fleetID := c.Query("fleetId")
+	if scope, scoped := scopedFleet(c); scoped {
+		fleetID = scope
+	}
+
+	resp, err := forCaller(c, h.driverService).GetOnlineStats(fleetID)
+	if err != nil {
+		h.logger.Error("failed to forward online stats request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get online stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// ListTaxiTypes handles GET /taxi-types
+// @Summary List taxi types
+// @Description The taxi types the service runs, in the order clients should show them, with their name, seats and icon. With fleetId, only the types drivers of that fleet may register with.
+// @Tags taxi-types
+// @Produce json
+// @Param fleetId query string false "Only list the types this fleet's drivers may register with"
+// @Success 200 {object} TaxiTypeCatalog "Taxi types"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /taxi-types [get]
+func (h *DriverHandler) ListTaxiTypes(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).ListTaxiTypes(c.Query("fleetId"))
+	if err != nil {
+		h.logger.Error("failed to forward list taxi types request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list taxi types")
+		return
+	}
+	defer resp.Body.Close()
 
+	h.forwardResponse(c, resp)
+}
+
+// SendPhoneVerification handles POST /drivers/:id/verify-phone/send
+// @Summary Send phone verification code
+// @Description Send a one-time verification code to the driver's phone via SMS
+// @Tags verification
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 202 {object} map[string]string "Code sent"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 409 {object} ErrorResponse "Phone missing or already verified"
+// @Failure 429 {object} ErrorResponse "Code sent recently"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/verify-phone/send [post]
+func (h *DriverHandler) SendPhoneVerification(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SendPhoneVerification(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward send phone verification request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to send verification code")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// VerifyPhone handles POST /drivers/:id/verify-phone
+// @Summary Verify phone
+// @Description Confirm the one-time code sent to the driver's phone
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body VerifyPhoneRequest true "Verification code"
+// @Success 200 {object} Driver "Phone verified"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 429 {object} ErrorResponse "Too many attempts"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/verify-phone [post]
+func (h *DriverHandler) VerifyPhone(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).VerifyPhone(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward verify phone request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to verify phone")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// SubmitIdentityCheck handles POST /drivers/:id/kyc
+// @Summary Submit identity check
+// @Description Send the driver's name and documents to the KYC provider. The check starts pending; identityVerified is set on the driver once the provider approves it. When a blacklist is configured the driver is looked up in it first.
+// @Tags verification
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 202 {object} KYCCheck "Check submitted"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet, or BLACKLISTED for a driver on the blacklist"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 409 {object} ErrorResponse "Identity verified, check pending or license document missing"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 503 {object} ErrorResponse "BLACKLIST_UNAVAILABLE when the blacklist cannot be reached and fails closed"
+// @Router /drivers/{id}/kyc [post]
+func (h *DriverHandler) SubmitIdentityCheck(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SubmitIdentityCheck(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward submit identity check request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to submit identity check")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// GetIdentityCheck handles GET /drivers/:id/kyc
+// @Summary Get identity check
+// @Description Get the driver's latest identity check
+// @Tags verification
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Success 200 {object} KYCCheck "Latest check"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver or check not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/kyc [get]
+func (h *DriverHandler) GetIdentityCheck(c *gin.Context) {
+	if !h.authorizeDriver(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetIdentityCheck(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get identity check request", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get identity check")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// ReceiveKYCWebhook handles POST /kyc/webhook
+// @Summary Receive KYC decision
+// @Description Inbound webhook for the KYC provider, passed to the driver service unchanged. The body {"reference","status","reason"} must be signed in the X-KYC-Signature header as "t=<unix seconds>,v1=<hex HMAC-SHA256 of '<t>.<body>'>" with the shared webhook secret.
+// @Tags verification
+// @Accept json
+// @Produce json
+// @Param X-KYC-Signature header string true "Webhook signature" example(t=1733446800,v1=5d41402abc4b2a76b9719d911017c592)
+// @Success 200 {object} KYCCheck "Decision applied"
+// @Failure 400 {object} ErrorResponse "Invalid body"
+// @Failure 401 {object} ErrorResponse "Invalid signature"
+// @Failure 404 {object} ErrorResponse "Unknown check"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /kyc/webhook [post]
+func (h *DriverHandler) ReceiveKYCWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxKYCWebhookBody))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "failed to read webhook body")
+		return
+	}
+
+	resp, err := h.driverService.ForwardKYCWebhook(body, c.GetHeader("X-KYC-Signature"))
+	if err != nil {
+		h.logger.Error("failed to forward kyc webhook", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to process webhook")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.forwardResponse(c, resp)
+}
+
+// LeavePlatesToDriverService forwards plates as they are, for markets whose
+// plates the driver service's validation rules accept but the Turkish format does not
+func (h *DriverHandler) LeavePlatesToDriverService() *DriverHandler {
+	h.skipPlates = true
+	return h
+}
+
+// normalizePlate rejects invalid plates at the edge and rewrites valid ones to
+// their canonical form. It reports whether the request may be forwarded.
+func (h *DriverHandler) normalizePlate(c *gin.Context, body *requestBody) bool {
+	value, ok := body.get("plate")
+	if !ok || h.skipPlates {
+		return true
+	}
+	raw, ok := value.(string)
+	if !ok {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "plate must be a string")
+		return false
+	}
+	normalized, err := plate.Normalize(raw)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return false
+	}
+	body.set("plate", normalized)
+	return true
+}
+
+// authorizeDriver lets fleet admins through only for drivers of their own
+// fleet. It looks the driver up in the driver service and writes the response
+// itself when the request must stop.
+func (h *DriverHandler) authorizeDriver(c *gin.Context, id string) bool {
+	scope, scoped := scopedFleet(c)
+	if !scoped {
+		return true
+	}
+
+	resp, err := forCaller(c, h.driverService).GetDriver(id)
+	if err != nil {
+		h.logger.Error("failed to look up driver fleet", zap.Error(err), zap.String("id", id))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up driver")
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		h.forwardResponse(c, resp)
+		return false
+	}
+
+	var driver struct {
+		FleetID string `json:"fleetId"`
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &driver)
+	}
+	if err != nil {
+		h.logger.Error("failed to decode driver", zap.Error(err), zap.String("id", id))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up driver")
+		return false
+	}
+
+	if driver.FleetID == "" || driver.FleetID != scope {
+		h.respondError(c, http.StatusForbidden, "FORBIDDEN", "driver does not belong to your fleet")
+		return false
+	}
+	return true
+}
+
+// forwardResponse forwards the response from the driver service to the client
+func (h *DriverHandler) forwardResponse(c *gin.Context, resp *http.Response) {
+	forwardResponse(c, resp, h.logger)
+}
+
+// forwardResponse copies the status, headers and body of an upstream response to the client.
+// Upstream errors are re-rendered as problem details when the client asked for them.
+func forwardResponse(c *gin.Context, resp *http.Response, logger *zap.Logger) {
 	// Copy body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		h.logger.Error("failed to read response body", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to read response")
+		logger.Error("failed to read response body", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to read response")
 		return
 	}
 
-	c.Data(resp.StatusCode, resp.Header.Get("Content-Type"), body)
+	writeResponse(c, resp.StatusCode, resp.Header, body)
 }
 
 func (h *DriverHandler) respondError(c *gin.Context, status int, code, message string) {
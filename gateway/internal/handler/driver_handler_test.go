@@ -7,11 +7,22 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/bitaksi/gateway/internal/coalesce"
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/locationwire"
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/redact"
+	"github.com/bitaksi/gateway/internal/schema"
 	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -312,6 +323,56 @@ func TestDriverHandler_GetDriver(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_HeadDriver(t *testing.T) {
+	logger := zap.NewNop()
+	var methods []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/drivers/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockServer.Close()
+
+	handler := NewDriverHandler(service.NewDriverServiceClient(mockServer.URL, logger), logger)
+	router := setupGatewayRouter()
+	drivers := router.Group("/drivers")
+	drivers.GET("/:id", handler.GetDriver)
+	drivers.PUT("/:id", handler.UpdateDriver)
+	drivers.GET("/:id/stats", handler.GetDriverStats)
+	drivers.HEAD("/:id", handler.HeadDriver)
+	RegisterHeadAndOptions(router, drivers)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodHead, "/drivers/test-id")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, w.Body.Len())
+	assert.Equal(t, []string{http.MethodHead}, methods, "the driver is not fetched")
+
+	w = serve(http.MethodHead, "/drivers/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Zero(t, w.Body.Len())
+
+	// Other GET routes answer HEAD through their GET handler
+	w = serve(http.MethodHead, "/drivers/test-id/stats")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Zero(t, w.Body.Len())
+
+	w = serve(http.MethodOptions, "/drivers/test-id")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, HEAD, PUT, OPTIONS", w.Header().Get("Allow"))
+	w = serve(http.MethodOptions, "/drivers/test-id/stats")
+	assert.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get("Allow"))
+}
+
 func TestDriverHandler_ListDrivers(t *testing.T) {
 	logger := zap.NewNop()
 
@@ -499,6 +560,282 @@ func TestDriverHandler_FindNearbyDrivers(t *testing.T) {
 	}
 }
 
+func TestDriverHandler_FindNearbyDrivers_Coalesced(t *testing.T) {
+	logger := zap.NewNop()
+	var queries []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"driver-1","distanceKm":0.4}]`))
+	}))
+	defer mockServer.Close()
+
+	handler := NewDriverHandler(service.NewDriverServiceClient(mockServer.URL, logger), logger).
+		CoalesceNearby(coalesce.New(time.Minute), 3)
+	router := setupGatewayRouter()
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+	for i, query := range []string{"lat=41.04312&lon=29.00991", "lat=41.0429&lon=29.0101"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?"+query, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id":"driver-1","distanceKm":0.4}]`, w.Body.String())
+		if i == 0 {
+			assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		} else {
+			assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+		}
+	}
+
+	// Both searches round to the same spot, which is what the driver service is asked about
+	assert.Equal(t, []string{"lat=41.043&lon=29.010"}, queries)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&taksiType=siyah", nil))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Len(t, queries, 2)
+
+	// Distances in another unit are another response
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&units=m", nil))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, "lat=41.043&lon=29.010&units=m", queries[2])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&units=yards", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, queries, 3)
+
+	// So is another page of the same search
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&limit=10&offset=10", nil))
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, "lat=41.043&lon=29.010&limit=10&offset=10", queries[3])
+}
+
+func TestDriverHandler_FindNearbyDrivers_Geohash(t *testing.T) {
+	logger := zap.NewNop()
+	var queries []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer mockServer.Close()
+
+	handler := NewDriverHandler(service.NewDriverServiceClient(mockServer.URL, logger), logger).
+		CoalesceNearby(coalesce.New(time.Minute), 3)
+	router := setupGatewayRouter()
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+	// The cell center is searched around, so it coalesces with a search by coordinates
+	for _, query := range []string{"geohash=sxk9kp", "lat=41.0419&lon=29.0091"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?"+query, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, []string{"lat=41.042&lon=29.009"}, queries)
+
+	for _, query := range []string{"geohash=sxk9ka", "geohash=sxk9kp&lat=41.0431&lon=29.0099"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+	}
+	assert.Len(t, queries, 1)
+}
+
+func TestDriverHandler_FindNearbyDrivers_CacheHeaders(t *testing.T) {
+	logger := zap.NewNop()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("lat") == "north" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"VALIDATION_ERROR","message":"invalid latitude"}}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer mockServer.Close()
+
+	client := service.NewDriverServiceClient(mockServer.URL, logger)
+	handlers := map[string]*DriverHandler{
+		"forwarded": NewDriverHandler(client, logger).CacheNearby(3 * time.Second),
+		"coalesced": NewDriverHandler(client, logger).CacheNearby(3*time.Second).CoalesceNearby(coalesce.New(time.Minute), 3),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			router := setupGatewayRouter()
+			router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "private, max-age=3", w.Header().Get("Cache-Control"))
+			assert.Equal(t, []string{"Authorization", "X-Response-Envelope"}, w.Header().Values("Vary"))
+
+			// Errors are not cached
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=north&lon=29.0099", nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Empty(t, w.Header().Get("Cache-Control"))
+		})
+	}
+
+	// Without a max age no caching headers are sent
+	router := setupGatewayRouter()
+	router.GET("/drivers/nearby", NewDriverHandler(client, logger).FindNearbyDrivers)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099", nil))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("Vary"))
+}
+
+func TestDriverHandler_FindNearbyDrivers_Fares(t *testing.T) {
+	logger := zap.NewNop()
+	drivers := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"driver-1","taxiType":"sari","distanceKm":0.4,"durationSec":60},{"id":"driver-2","taxiType":"siyah","distanceKm":1.2,"durationSec":180}]`))
+	}))
+	defer drivers.Close()
+	var mu sync.Mutex
+	var quotes []service.FareQuoteRequest
+	failing := map[string]bool{"driver-2": true}
+	pricing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/quotes", r.URL.Path)
+		var quote service.FareQuoteRequest
+		json.NewDecoder(r.Body).Decode(&quote)
+		mu.Lock()
+		quotes = append(quotes, quote)
+		failed := failing[quote.DriverID]
+		mu.Unlock()
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"amount":185.5,"currency":"TRY"}`))
+	}))
+	defer pricing.Close()
+
+	client := service.NewDriverServiceClient(drivers.URL, logger)
+	fares := service.NewPricingServiceClient(pricing.URL, 2, logger)
+	handlers := map[string]*DriverHandler{
+		"forwarded": NewDriverHandler(client, logger).QuoteFares(fares, time.Second),
+		"coalesced": NewDriverHandler(client, logger).QuoteFares(fares, time.Second).CoalesceNearby(coalesce.New(time.Minute), 3),
+	}
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			quotes = nil
+			router := setupGatewayRouter()
+			router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+
+			// A driver that could not be priced is still returned, without a fare
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&includeFares=true&dropoffLat=41.0082&dropoffLon=28.9784", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "partial", w.Header().Get("X-Fares"))
+			assert.JSONEq(t, `[
+				{"id":"driver-1","taxiType":"sari","distanceKm":0.4,"durationSec":60,"fare":{"amount":185.5,"currency":"TRY"}},
+				{"id":"driver-2","taxiType":"siyah","distanceKm":1.2,"durationSec":180}
+			]`, w.Body.String())
+			require.Len(t, quotes, 2)
+			for _, quote := range quotes {
+				// The rider is picked up where they searched, not at the rounded spot
+				assert.Equal(t, Location{Lat: 41.0431, Lon: 29.0099}, quote.Pickup)
+				assert.Equal(t, &Location{Lat: 41.0082, Lon: 28.9784}, quote.Dropoff)
+				if quote.DriverID == "driver-2" {
+					assert.Equal(t, "siyah", quote.TaxiType)
+					assert.Equal(t, 1.2, quote.PickupDistanceKm)
+					assert.Equal(t, 180, quote.PickupDurationSec)
+				}
+			}
+
+			// Without includeFares the pricing service is not asked
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099", nil))
+			assert.Empty(t, w.Header().Get("X-Fares"))
+			assert.NotContains(t, w.Body.String(), "fare")
+			assert.Len(t, quotes, 2)
+
+			for _, query := range []string{"includeFares=maybe", "includeFares=true&dropoffLat=41.0082", "includeFares=true&dropoffLat=91&dropoffLon=28.9784"} {
+				w = httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&"+query, nil))
+				assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			}
+		})
+	}
+
+	mu.Lock()
+	failing["driver-1"] = true
+	mu.Unlock()
+	router := setupGatewayRouter()
+	router.GET("/drivers/nearby", handlers["forwarded"].FindNearbyDrivers)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&includeFares=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "unavailable", w.Header().Get("X-Fares"))
+	assert.NotContains(t, w.Body.String(), "fare")
+
+	// Without a pricing service the drivers are returned as they are
+	router = setupGatewayRouter()
+	router.GET("/drivers/nearby", NewDriverHandler(client, logger).FindNearbyDrivers)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby?lat=41.0431&lon=29.0099&includeFares=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "unavailable", w.Header().Get("X-Fares"))
+}
+
+func TestDriverHandler_FindDriversAlongRoute(t *testing.T) {
+	logger := zap.NewNop()
+	var forwarded map[string]interface{}
+	var forwardedQuery string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/drivers/nearby/route", r.URL.Path)
+		forwardedQuery = r.URL.RawQuery
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":"driver-1","distanceKm":0.2,"routeKm":3.4}]`))
+	}))
+	defer mockServer.Close()
+
+	handler := NewDriverHandler(service.NewDriverServiceClient(mockServer.URL, logger), logger)
+	router := setupGatewayRouter()
+	router.POST("/drivers/nearby/route", handler.FindDriversAlongRoute)
+	scoped := setupGatewayRouter()
+	scoped.POST("/drivers/nearby/route", asRole(token.RoleFleetAdmin, "fleet-1"), handler.FindDriversAlongRoute)
+
+	body := `{"waypoints":[{"lat":41.0431,"lon":29.0099},{"lat":41.1086,"lon":29.0436}],"widthKm":1,"fleetId":"fleet-2"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/drivers/nearby/route", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":"driver-1","distanceKm":0.2,"routeKm":3.4}]`, w.Body.String())
+	assert.Equal(t, "fleet-2", forwarded["fleetId"])
+	assert.Len(t, forwarded["waypoints"], 2)
+
+	// Fleet admins only search their own fleet
+	w = httptest.NewRecorder()
+	scoped.ServeHTTP(w, httptest.NewRequest("POST", "/drivers/nearby/route", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "fleet-1", forwarded["fleetId"])
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/drivers/nearby/route", bytes.NewBufferString(`not json`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Units are forwarded as given and validated first
+	assert.Empty(t, forwardedQuery)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/drivers/nearby/route?units=mi", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "units=mi", forwardedQuery)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/drivers/nearby/route?units=ft", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "units must be km, m or mi")
+}
+
 func TestDriverHandler_forwardResponse(t *testing.T) {
 	logger := zap.NewNop()
 	realService := service.NewDriverServiceClient("http://localhost:8081", logger)
@@ -611,3 +948,511 @@ func TestDriverHandler_respondError(t *testing.T) {
 	assert.Equal(t, "TEST_ERROR", errorObj["code"])
 	assert.Equal(t, "test message", errorObj["message"])
 }
+
+func TestDriverHandler_ProblemDetails(t *testing.T) {
+	logger := zap.NewNop()
+	handler := NewDriverHandler(service.NewDriverServiceClient("http://localhost:8081", logger), logger)
+
+	router := setupGatewayRouter()
+	router.GET("/drivers/:id", func(c *gin.Context) {
+		handler.forwardResponse(c, &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"error":{"code":"NOT_FOUND","message":"driver not found"}}`)),
+			Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}, "Content-Length": []string{"59"}},
+		})
+	})
+	router.GET("/fail", func(c *gin.Context) {
+		handler.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "lat is required")
+	})
+	router.GET("/unavailable", func(c *gin.Context) {
+		handler.forwardResponse(c, &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"error":{"code":"SERVICE_UNAVAILABLE","message":"database is temporarily unavailable, retry later"}}`)),
+			Header:     http.Header{"Content-Type": []string{"application/json"}, "Retry-After": []string{"5"}},
+		})
+	})
+
+	t.Run("upstream error is converted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/drivers/42", nil)
+		req.Header.Set("Accept", "application/problem+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		var details map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+		assert.Equal(t, "/problems/not-found", details["type"])
+		assert.Equal(t, "Not Found", details["title"])
+		assert.Equal(t, float64(http.StatusNotFound), details["status"])
+		assert.Equal(t, "driver not found", details["detail"])
+		assert.Equal(t, "/drivers/42", details["instance"])
+		assert.Equal(t, "NOT_FOUND", details["code"])
+	})
+
+	t.Run("upstream error is forwarded with its source by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/42", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"NOT_FOUND","message":"driver not found"},"upstream":{"service":"driver-service","status":404}}`, w.Body.String())
+	})
+
+	t.Run("upstream retry hint is kept", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/unavailable", nil)
+		req.Header.Set("Accept", "application/problem+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "5", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"code":"SERVICE_UNAVAILABLE"`)
+	})
+
+	t.Run("gateway error", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/fail", nil)
+		req.Header.Set("Accept", "application/json, application/problem+json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"code":"VALIDATION_ERROR"`)
+	})
+}
+
+func TestWriteResponse_UpstreamErrors(t *testing.T) {
+	router := setupGatewayRouter()
+	upstream := func(status int, contentType, body string, header http.Header) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if header == nil {
+				header = http.Header{}
+			}
+			header.Set("Content-Type", contentType)
+			writeResponse(c, status, header, []byte(body))
+		}
+	}
+	router.POST("/drivers", upstream(http.StatusBadRequest, "application/json",
+		`{"error":{"code":"VALIDATION_ERROR","message":"plate is invalid","field":"plate"}}`,
+		http.Header{"Access-Control-Allow-Origin": []string{"*"}, "X-Request-Id": []string{"req-1"}}))
+	router.GET("/missing", upstream(http.StatusNotFound, "text/plain", "404 page not found", nil))
+	router.GET("/broken", upstream(http.StatusInternalServerError, "application/json",
+		`{"error":{"code":"INTERNAL_ERROR","message":"failed to list drivers"}}`, nil))
+	router.GET("/slow", upstream(http.StatusGatewayTimeout, "text/html", "<html>timeout</html>", nil))
+	router.GET("/untranslated", func(c *gin.Context) {
+		c.Set("upstreamErrors", config.UpstreamErrorsConfig{})
+		upstream(http.StatusInternalServerError, "application/json", `{"error":{"code":"INTERNAL_ERROR","message":"failed"}}`, nil)(c)
+	})
+
+	t.Run("4xx keeps status and body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/drivers", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"VALIDATION_ERROR","message":"plate is invalid","field":"plate"},"upstream":{"service":"driver-service","status":400}}`, w.Body.String())
+		assert.Equal(t, "req-1", w.Header().Get("X-Request-Id"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), "upstream CORS headers are dropped")
+	})
+
+	t.Run("unstructured 4xx gets an error body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.JSONEq(t, `{"error":{"code":"NOT_FOUND","message":"Not Found"},"upstream":{"service":"driver-service","status":404}}`, w.Body.String())
+	})
+
+	t.Run("5xx is translated", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/broken", nil))
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"UPSTREAM_ERROR","message":"driver service failed to handle the request"},"upstream":{"service":"driver-service","status":500,"code":"INTERNAL_ERROR","message":"failed to list drivers"}}`, w.Body.String())
+
+		req := httptest.NewRequest("GET", "/slow", nil)
+		req.Header.Set("Accept", "application/problem+json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+		var details map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+		assert.Equal(t, "UPSTREAM_TIMEOUT", details["code"])
+		assert.Equal(t, map[string]interface{}{"service": "driver-service", "status": float64(504)}, details["upstream"])
+	})
+
+	t.Run("translation can be turned off", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/untranslated", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error":{"code":"INTERNAL_ERROR","message":"failed"}}`, w.Body.String())
+	})
+}
+
+func TestWriteResponse_ResponseValidation(t *testing.T) {
+	validator, err := schema.NewValidator(ResponseModels, nil, []string{"GET /drivers/nearby"}, zap.NewNop())
+	assert.NoError(t, err)
+
+	router := setupGatewayRouter()
+	router.Use(middleware.ResponseValidation(validator))
+	upstream := func(status int, body string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			writeResponse(c, status, http.Header{"Content-Type": []string{"application/json"}, "Etag": []string{`"v1"`}}, []byte(body))
+		}
+	}
+	valid := `{"id":"d1","firstName":"Ali","lastName":"Kurt","plate":"34G1234","taxiType":"sari","carBrand":"Fiat","carModel":"Egea",` +
+		`"location":{"lat":41.0,"lon":29.0},"phoneVerified":true,"emailVerified":false,"identityVerified":false,"available":true,` +
+		`"suspended":false,"rating":4.8,"ratingCount":12,"createdAt":"2025-12-06T01:00:00Z","updatedAt":"2025-12-06T01:00:00Z"}`
+	router.GET("/drivers/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "valid":
+			upstream(http.StatusOK, valid)(c)
+		case "truncated":
+			upstream(http.StatusOK, valid[:40])(c)
+		case "missing":
+			upstream(http.StatusNotFound, `{"error":{"code":"NOT_FOUND","message":"driver not found"}}`)(c)
+		default:
+			upstream(http.StatusOK, `{"id":"d1","rating":"high"}`)(c)
+		}
+	})
+	router.GET("/drivers/nearby", upstream(http.StatusOK, `not json`))
+
+	t.Run("matching response is forwarded", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/valid", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, valid, w.Body.String())
+		assert.Equal(t, `"v1"`, w.Header().Get("Etag"))
+	})
+
+	t.Run("malformed response becomes 502", func(t *testing.T) {
+		for _, id := range []string{"truncated", "mistyped"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/"+id, nil))
+
+			assert.Equal(t, http.StatusBadGateway, w.Code, id)
+			assert.JSONEq(t, `{"error":{"code":"BAD_UPSTREAM","message":"driver service returned a malformed response"},"upstream":{"service":"driver-service","status":200}}`, w.Body.String(), id)
+			assert.Empty(t, w.Header().Get("Etag"), "upstream headers describe the rejected body")
+		}
+	})
+
+	t.Run("errors and skipped routes are not checked", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/nearby", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "not json", w.Body.String())
+	})
+}
+
+func TestWriteResponse_Redaction(t *testing.T) {
+	redactor, err := redact.New(redact.DefaultPolicy)
+	assert.NoError(t, err)
+
+	router := setupGatewayRouter()
+	router.Use(middleware.ResponseRedaction(redactor))
+	// Stands in for Authorize, which sets the claims of the caller's token
+	router.Use(func(c *gin.Context) {
+		if username := c.GetHeader("X-Test-User"); username != "" {
+			c.Set("username", username)
+		}
+		if role := c.GetHeader("X-Test-Role"); role != "" {
+			c.Set("role", role)
+		}
+	})
+	body := `[{"driver":{"id":"d1","firstName":"Ali","lastName":"Kurt","plate":"34G1234","phone":"+905551234567"},"distanceKm":1.2}]`
+	upstream := func(c *gin.Context) {
+		writeResponse(c, http.StatusOK, http.Header{"Content-Type": []string{"application/json"}}, []byte(body))
+	}
+	router.GET("/drivers/nearby", upstream)
+	router.GET("/taxi-types", upstream)
+
+	tests := []struct {
+		name     string
+		path     string
+		username string
+		role     string
+		want     string
+	}{
+		{
+			name:     "rider",
+			path:     "/drivers/nearby",
+			username: "rider-1",
+			role:     "rider",
+			want:     `[{"driver":{"id":"d1","firstName":"Ali","lastName":"K***","plate":"3***"},"distanceKm":1.2}]`,
+		},
+		{name: "dispatcher", path: "/drivers/nearby", username: "dispatcher", want: body},
+		{name: "fleet admin", path: "/drivers/nearby", username: "fleet", role: "fleet_admin", want: body},
+		{name: "without a token", path: "/drivers/nearby", want: `[{"driver":{"id":"d1","firstName":"Ali","lastName":"K***","plate":"3***"},"distanceKm":1.2}]`},
+		{name: "rider on a route the policy does not list", path: "/taxi-types", username: "rider-1", role: "rider", want: body},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("X-Test-User", tt.username)
+			req.Header.Set("X-Test-Role", tt.role)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.want, w.Body.String())
+			if tt.path == "/drivers/nearby" {
+				assert.Equal(t, "Authorization", w.Header().Get("Vary"))
+			} else {
+				assert.Empty(t, w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestDriverHandler_RedactionWithoutToken(t *testing.T) {
+	driver := `{"id":"d1","firstName":"Ali","lastName":"Kurt","plate":"34G1234","phone":"+905551234567","email":"ali@example.com",` +
+		`"license":{"number":"TR-1234567","class":"B","expiresAt":"2030-01-01T00:00:00Z"},"documents":[{"type":"registration","url":"https://files.example.com/d1/registration.pdf"}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/drivers/nearby":
+			w.Write([]byte("[" + driver + "]"))
+			return
+		case "/api/v1/drivers":
+			w.Write([]byte(`{"drivers":[` + driver + `],"total":1,"page":1,"pageSize":20}`))
+			return
+		}
+		w.Write([]byte(driver))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{
+		JWT:    config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Enabled: true},
+		APIKey: config.APIKeyConfig{Enabled: true, Keys: []string{"key-1234567890"}},
+	}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+	redactor, err := redact.New(redact.DefaultPolicy)
+	require.NoError(t, err)
+
+	logger := zap.NewNop()
+	handler := NewDriverHandler(service.NewDriverServiceClient(server.URL, logger), logger)
+	router := setupGatewayRouter()
+	router.Use(middleware.ResponseRedaction(redactor))
+	router.Use(middleware.Authorize(policy.Default(), cfg, tokens, nil, logger))
+	router.GET("/drivers", handler.ListDrivers)
+	router.GET("/drivers/nearby", handler.FindNearbyDrivers)
+	router.GET("/drivers/:id", handler.GetDriver)
+
+	dispatcher, err := tokens.Issue("dispatcher")
+	require.NoError(t, err)
+	redacted := `{"id":"d1","firstName":"Ali","lastName":"K***","plate":"3***"}`
+
+	tests := []struct {
+		name   string
+		path   string
+		header map[string]string
+		want   string
+	}{
+		{name: "public driver read", path: "/drivers/d1", want: redacted},
+		{name: "nearby with an API key", path: "/drivers/nearby?lat=41.0431&lon=29.0099", header: map[string]string{"X-API-Key": "key-1234567890"}, want: "[" + redacted + "]"},
+		{name: "dispatcher", path: "/drivers/d1", header: map[string]string{"Authorization": "Bearer " + dispatcher}, want: driver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.want, w.Body.String())
+		})
+	}
+
+	// Contact details never reach callers without a token
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/drivers/d1", nil))
+	var public map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &public))
+	assert.NotContains(t, public, "phone")
+	assert.NotContains(t, public, "email")
+	assert.NotContains(t, w.Body.String(), "+905551234567")
+
+	// Neither do the licence and onboarding documents
+	assert.NotContains(t, public, "license")
+	assert.NotContains(t, w.Body.String(), "TR-1234567")
+	assert.NotContains(t, public, "documents")
+	assert.NotContains(t, w.Body.String(), "registration.pdf")
+
+	// API keys are off by default, which opens driver lists to every caller
+	open := setupGatewayRouter()
+	open.Use(middleware.ResponseRedaction(redactor))
+	open.Use(middleware.Authorize(policy.Default(), &config.Config{JWT: cfg.JWT}, tokens, nil, logger))
+	open.GET("/drivers", handler.ListDrivers)
+	w = httptest.NewRecorder()
+	open.ServeHTTP(w, httptest.NewRequest("GET", "/drivers", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"drivers":[`+redacted+`],"total":1,"page":1,"pageSize":20}`, w.Body.String())
+}
+
+func TestDriverHandler_PlateValidation(t *testing.T) {
+	var forwarded map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = nil
+		json.NewDecoder(r.Body).Decode(&forwarded)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"507f1f77bcf86cd799439011"}`))
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	handler := NewDriverHandler(service.NewDriverServiceClient(server.URL, logger), logger)
+	router := setupGatewayRouter()
+	router.POST("/drivers", handler.CreateDriver)
+
+	post := func(plate interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{"firstName": "Ahmet", "plate": plate})
+		req := httptest.NewRequest("POST", "/drivers", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("34 abc 123")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "34ABC123", forwarded["plate"])
+
+	forwarded = nil
+	w = post("82ABC123")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "province code 82")
+	assert.Nil(t, forwarded, "invalid plates are not forwarded")
+
+	w = post(34)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// With plate checks left to the driver service's validation rules, other formats pass through
+	handler.LeavePlatesToDriverService()
+	w = post("10-AB-123")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "10-AB-123", forwarded["plate"])
+}
+
+func TestDriverHandler_ForwardsRequestBytes(t *testing.T) {
+	var forwarded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		forwarded = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"507f1f77bcf86cd799439011"}`))
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	handler := NewDriverHandler(service.NewDriverServiceClient(server.URL, logger), logger)
+	router := setupGatewayRouter()
+	router.POST("/drivers", handler.CreateDriver)
+
+	// Members out of order, an ID beyond float64 precision, number formats and
+	// an unknown nested field all reach the driver service as sent; only the
+	// plate is rewritten to its canonical form
+	body := `{"plate": "34 abc 123", "lon":29.0100, "lat":4.1e1, "externalId":9007199254740993, "meta":{"z":1,"a":[1.50,2]}, "firstName":"Ahmet"}`
+	req := httptest.NewRequest("POST", "/drivers", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"plate": "34ABC123", "lon":29.0100, "lat":4.1e1, "externalId":9007199254740993, "meta":{"z":1,"a":[1.50,2]}, "firstName":"Ahmet"}`, forwarded)
+}
+
+func TestDriverHandler_UpdateLocation_Binary(t *testing.T) {
+	var forwarded string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		forwarded = string(body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"507f1f77bcf86cd799439011","location":{"type":"Point","coordinates":[29.0099,41.0431]}}`))
+	}))
+	defer server.Close()
+
+	logger := zap.NewNop()
+	handler := NewDriverHandler(service.NewDriverServiceClient(server.URL, logger), logger)
+	router := setupGatewayRouter()
+	router.PUT("/drivers/:id/location", handler.UpdateLocation)
+
+	location := locationwire.Location{Lat: 41.0431, Lon: 29.0099}
+	tests := []struct {
+		name           string
+		contentType    string
+		accept         string
+		body           []byte
+		expectedStatus int
+		forwarded      string
+	}{
+		{
+			name:           "json",
+			contentType:    "application/json",
+			body:           []byte(`{"lat":41.0431,"lon":29.0099}`),
+			expectedStatus: http.StatusOK,
+			forwarded:      `{"lat":41.0431,"lon":29.0099}`,
+		},
+		{
+			name:           "protobuf",
+			contentType:    locationwire.ContentTypeProtobuf,
+			body:           locationwire.EncodeProtobuf(location),
+			expectedStatus: http.StatusNoContent,
+			forwarded:      `{"lat":41.0431,"lon":29.0099}`,
+		},
+		{
+			name:           "packed with driver in response",
+			contentType:    locationwire.ContentTypePacked,
+			accept:         "application/json",
+			body:           locationwire.EncodePacked(location),
+			expectedStatus: http.StatusOK,
+			forwarded:      `{"lat":41.0431,"lon":29.0099}`,
+		},
+		{
+			name:           "truncated packed",
+			contentType:    locationwire.ContentTypePacked,
+			body:           []byte{1, 2, 3},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "oversized report",
+			contentType:    locationwire.ContentTypeProtobuf,
+			body:           make([]byte, 2048),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarded = ""
+			req := httptest.NewRequest(http.MethodPut, "/drivers/507f1f77bcf86cd799439011/location", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.forwarded != "" {
+				assert.JSONEq(t, tt.forwarded, forwarded)
+			} else {
+				assert.Empty(t, forwarded)
+			}
+			if tt.expectedStatus == http.StatusNoContent {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
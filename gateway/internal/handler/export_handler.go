@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExportHandler proxies background driver export requests to the driver
+// service. Fleet admins only export, and see the exports of, their own fleet.
+type ExportHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// StartExport handles POST /exports
+// @Summary Start a driver export
+// @Description Export every driver, or one fleet's, in the background; fleet admins always export their own fleet. The export is a snapshot of the drivers that existed when it started; drivers updated since are flagged with changedDuringExport. Poll GET /exports/{id} until status is completed, then download the file from GET /exports/{id}/download. Exports are kept for JOB_RETENTION_HOURS after they finish.
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param export body ExportRequest false "Format and fleet; an empty body exports every driver as NDJSON"
+// @Success 202 {object} Job "Export queued"
+// @Header 202 {string} Location "URL of the export status"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 429 {object} ErrorResponse "Too many jobs waiting"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /exports [post]
+func (h *ExportHandler) StartExport(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil && err != io.EOF {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	caller := callerIdentity(c)
+	resp, err := forCaller(c, h.driverService).StartExport(body.payload(), caller.UserID)
+	if err != nil {
+		h.logger.Error("failed to forward start export request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to start export")
+		return
+	}
+	defer resp.Body.Close()
+
+	// The driver service points at its own route
+	if location := resp.Header.Get("Location"); location != "" {
+		resp.Header.Set("Location", strings.TrimPrefix(location, "/api/v1"))
+	}
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetExport handles GET /exports/:id
+// @Summary Get a driver export
+// @Description Status and progress of an export: processed against total drivers, and once completed the file name, type and size to download.
+// @Tags exports
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Export ID" example("job-9f86d081884c7d65")
+// @Success 200 {object} Job "Export"
+// @Failure 404 {object} ErrorResponse "Export not found or expired"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).GetExport(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get export request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get export")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// DownloadExport handles GET /exports/:id/download
+// @Summary Download a driver export
+// @Description The exported drivers, as NDJSON (one driver per line) or a JSON array. The file is streamed from the driver service as it is read.
+// @Tags exports
+// @Produce json
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param id path string true "Export ID" example("job-9f86d081884c7d65")
+// @Success 200 {file} file "Exported drivers"
+// @Failure 404 {object} ErrorResponse "Export not found or expired"
+// @Failure 409 {object} ErrorResponse "Export still running or failed"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).DownloadExport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward download export request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to download export")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		forwardResponse(c, resp, h.logger)
+		return
+	}
+	// Exports can be large, so they are streamed instead of buffered
+	copyHeaders(c, resp.Header)
+	c.DataFromReader(http.StatusOK, resp.ContentLength, resp.Header.Get("Content-Type"), resp.Body, nil)
+}
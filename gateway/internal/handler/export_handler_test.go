@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestExportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var got *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		switch r.URL.Path {
+		case "/api/v1/exports":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Location", "/api/v1/exports/job-1")
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":"job-1","status":"queued"}`))
+		case "/api/v1/exports/job-1/download":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="drivers.ndjson"`)
+			w.Write([]byte("{\"id\":\"d1\"}\n{\"id\":\"d2\"}\n"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"code":"EXPORT_NOT_READY","message":"export has not completed"}}`))
+		}
+	}))
+	defer upstream.Close()
+
+	h := NewExportHandler(service.NewDriverServiceClient(upstream.URL, zap.NewNop()), zap.NewNop())
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("username", "fleet-ops")
+		c.Next()
+	}, asRole(token.RoleFleetAdmin, "fleet-1"))
+	router.POST("/exports", h.StartExport)
+	router.GET("/exports/:id/download", h.DownloadExport)
+
+	t.Run("start", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exports", strings.NewReader(`{"format":"json"}`)))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "/exports/job-1", w.Header().Get("Location"))
+		assert.Equal(t, "fleet-ops", got.Header.Get("X-Admin-Actor"))
+		assert.Equal(t, "fleet-1", got.Header.Get(service.TenantIDHeader))
+	})
+
+	t.Run("start without body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exports", nil))
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("download", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/exports/job-1/download", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="drivers.ndjson"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "{\"id\":\"d1\"}\n{\"id\":\"d2\"}\n", w.Body.String())
+	})
+
+	t.Run("download before completion", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/exports/job-2/download", nil))
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "EXPORT_NOT_READY")
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FleetHandler handles fleet requests in the gateway. Fleet admins may only
+// read their own fleet; creating fleets and moving drivers between fleets is
+// reserved for unrestricted users.
+type FleetHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewFleetHandler creates a new fleet handler
+func NewFleetHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *FleetHandler {
+	return &FleetHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// CreateFleet handles POST /fleets
+// @Summary Create a fleet
+// @Description Create a fleet (taxi company). Not available to fleet admins.
+// @Tags fleets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param fleet body CreateFleetRequest true "Fleet information"
+// @Success 201 {object} Fleet "Fleet created"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Fleet admins cannot create fleets"
+// @Failure 409 {object} ErrorResponse "Fleet name already exists"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /fleets [post]
+func (h *FleetHandler) CreateFleet(c *gin.Context) {
+	if !requireUnscoped(c) {
+		return
+	}
+
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).CreateFleet(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward create fleet request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create fleet")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListFleets handles GET /fleets
+// @Summary List fleets
+// @Description List all fleets. Not available to fleet admins.
+// @Tags fleets
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Fleet "Fleets"
+// @Failure 403 {object} ErrorResponse "Fleet admins cannot list fleets"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /fleets [get]
+func (h *FleetHandler) ListFleets(c *gin.Context) {
+	if !requireUnscoped(c) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).ListFleets()
+	if err != nil {
+		h.logger.Error("failed to forward list fleets request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list fleets")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetFleet handles GET /fleets/:id
+// @Summary Get a fleet
+// @Description Get a fleet by ID. Fleet admins can only read their own fleet.
+// @Tags fleets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Fleet ID" example("6570a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} Fleet "Fleet"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 404 {object} ErrorResponse "Fleet not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /fleets/{id} [get]
+func (h *FleetHandler) GetFleet(c *gin.Context) {
+	if !requireFleet(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetFleet(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get fleet request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get fleet")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListFleetDrivers handles GET /fleets/:id/drivers
+// @Summary List fleet drivers
+// @Description Get a paginated list of the drivers in a fleet. Fleet admins can only list their own fleet.
+// @Tags fleets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Fleet ID" example("6570a1f2c3d4e5f6a7b8c9d0")
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(20)
+// @Success 200 {object} ListDriversResponse "Paginated list of drivers"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /fleets/{id}/drivers [get]
+func (h *FleetHandler) ListFleetDrivers(c *gin.Context) {
+	if !requireFleet(c, c.Param("id")) {
+		return
+	}
+
+	var query ListDriversQuery
+	// String fields always bind; the driver service validates the values
+	_ = c.ShouldBindQuery(&query)
+	query.FleetID = c.Param("id")
+
+	resp, err := forCaller(c, h.driverService).ListDrivers(query)
+	if err != nil {
+		h.logger.Error("failed to forward list fleet drivers request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list drivers")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// AssignDriver handles PUT /drivers/:id/fleet
+// @Summary Assign a driver to a fleet
+// @Description Move a driver into a fleet; an empty fleetId removes the driver from its fleet. Not available to fleet admins.
+// @Tags fleets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param assignment body AssignFleetRequest true "Fleet assignment"
+// @Success 200 {object} Driver "Updated driver"
+// @Failure 400 {object} ErrorResponse "Unknown fleet"
+// @Failure 403 {object} ErrorResponse "Fleet admins cannot reassign drivers"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/fleet [put]
+func (h *FleetHandler) AssignDriver(c *gin.Context) {
+	if !requireUnscoped(c) {
+		return
+	}
+
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).AssignFleet(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward fleet assignment request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update driver")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// scopedFleet returns the fleet a fleet admin is restricted to. ok is false
+// for callers without the fleet_admin role.
+func scopedFleet(c *gin.Context) (fleetID string, ok bool) {
+	if c.GetString("role") != token.RoleFleetAdmin {
+		return "", false
+	}
+	return c.GetString("fleetId"), true
+}
+
+// requireUnscoped rejects fleet admins
+func requireUnscoped(c *gin.Context) bool {
+	if _, scoped := scopedFleet(c); scoped {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "fleet admins cannot perform this action")
+		return false
+	}
+	return true
+}
+
+// requireFleet rejects fleet admins of any other fleet
+func requireFleet(c *gin.Context, fleetID string) bool {
+	if scope, scoped := scopedFleet(c); scoped && scope != fleetID {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "fleet admins can only access their own fleet")
+		return false
+	}
+	return true
+}
+
+// scopeNewDriver places drivers created by a fleet admin in the admin's fleet
+func scopeNewDriver(c *gin.Context, body *requestBody) bool {
+	scope, scoped := scopedFleet(c)
+	if !scoped {
+		return true
+	}
+	value, _ := body.get("fleetId")
+	if requested, ok := value.(string); ok && requested != "" && requested != scope {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "fleet admins can only create drivers in their own fleet")
+		return false
+	}
+	body.set("fleetId", scope)
+	return true
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeFleetUpstream serves drivers by ID and records the requests it receives
+type fakeFleetUpstream struct {
+	driverFleets map[string]string
+	requests     []string
+	lastBody     map[string]interface{}
+}
+
+func (f *fakeFleetUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	f.lastBody = nil
+	json.NewDecoder(r.Body).Decode(&f.lastBody)
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/api/v1/drivers/") {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/drivers/")
+		fleetID, ok := f.driverFleets[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"driver not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "fleetId": fleetID})
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{}`))
+}
+
+// asRole simulates the claims the JWT middleware puts in the context
+func asRole(role, fleetID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role != "" {
+			c.Set("role", role)
+			c.Set("fleetId", fleetID)
+		}
+		c.Next()
+	}
+}
+
+func TestFleetAdminScope(t *testing.T) {
+	upstream := &fakeFleetUpstream{driverFleets: map[string]string{
+		"own":         "fleet-1",
+		"other":       "fleet-2",
+		"independent": "",
+	}}
+	server := httptest.NewServer(upstream)
+	defer server.Close()
+
+	logger := zap.NewNop()
+	client := service.NewDriverServiceClient(server.URL, logger)
+	drivers := NewDriverHandler(client, logger)
+	fleets := NewFleetHandler(client, logger)
+
+	newRouter := func(role, fleetID string) *gin.Engine {
+		router := setupGatewayRouter()
+		router.Use(asRole(role, fleetID))
+		router.POST("/drivers", drivers.CreateDriver)
+		router.PUT("/drivers/:id", drivers.UpdateDriver)
+		router.GET("/drivers", drivers.ListDrivers)
+		router.PUT("/drivers/:id/fleet", fleets.AssignDriver)
+		router.POST("/fleets", fleets.CreateFleet)
+		router.GET("/fleets/:id", fleets.GetFleet)
+		router.GET("/fleets/:id/drivers", fleets.ListFleetDrivers)
+		return router
+	}
+	do := func(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	admin := newRouter(token.RoleFleetAdmin, "fleet-1")
+
+	t.Run("updates drivers of own fleet", func(t *testing.T) {
+		w := do(admin, "PUT", "/drivers/own", map[string]interface{}{"carBrand": "Honda"})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("cannot update drivers of other fleets", func(t *testing.T) {
+		for _, id := range []string{"other", "independent"} {
+			upstream.requests = nil
+			w := do(admin, "PUT", "/drivers/"+id, map[string]interface{}{"carBrand": "Honda"})
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Equal(t, []string{"GET /api/v1/drivers/" + id}, upstream.requests, "update is not forwarded")
+		}
+	})
+
+	t.Run("missing driver is a 404", func(t *testing.T) {
+		w := do(admin, "PUT", "/drivers/missing", map[string]interface{}{"carBrand": "Honda"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("creates drivers in own fleet", func(t *testing.T) {
+		w := do(admin, "POST", "/drivers", map[string]interface{}{"firstName": "Ahmet"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "fleet-1", upstream.lastBody["fleetId"])
+
+		w = do(admin, "POST", "/drivers", map[string]interface{}{"firstName": "Ahmet", "fleetId": "fleet-2"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("lists are forced to own fleet", func(t *testing.T) {
+		upstream.requests = nil
+		do(admin, "GET", "/drivers?fleetId=fleet-2", nil)
+		assert.Equal(t, []string{"GET /api/v1/drivers?fleetId=fleet-1"}, upstream.requests)
+
+		assert.Equal(t, http.StatusOK, do(admin, "GET", "/fleets/fleet-1/drivers", nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(admin, "GET", "/fleets/fleet-2/drivers", nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(admin, "GET", "/fleets/fleet-2", nil).Code)
+	})
+
+	t.Run("cannot manage fleets", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, do(admin, "POST", "/fleets", map[string]interface{}{"name": "x"}).Code)
+		assert.Equal(t, http.StatusForbidden, do(admin, "PUT", "/drivers/own/fleet", map[string]interface{}{"fleetId": ""}).Code)
+	})
+
+	t.Run("unrestricted users are not scoped", func(t *testing.T) {
+		router := newRouter("", "")
+		upstream.requests = nil
+		w := do(router, "PUT", "/drivers/other", map[string]interface{}{"carBrand": "Honda"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"PUT /api/v1/drivers/other"}, upstream.requests)
+
+		assert.Equal(t, http.StatusOK, do(router, "PUT", "/drivers/other/fleet", map[string]interface{}{"fleetId": "fleet-1"}).Code)
+		assert.Equal(t, http.StatusOK, do(router, "GET", "/fleets/fleet-2", nil).Code)
+	})
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+)
+
+// callerIdentity returns the caller verified by the JWT or device token
+// authentication. It is empty on routes without either.
+func callerIdentity(c *gin.Context) service.Identity {
+	identity := service.Identity{
+		UserID: c.GetString("username"),
+		Role:   c.GetString("role"),
+	}
+	switch identity.Role {
+	case token.RoleFleetAdmin:
+		identity.TenantID = c.GetString("fleetId")
+	case token.RoleRider:
+		identity.UserID = c.GetString("riderId")
+	case token.RoleDriverDevice:
+		identity.UserID = c.GetString("driverId")
+	}
+	return identity
+}
+
+// forCaller returns a client that forwards requests to the driver service on
+// behalf of the caller, along with the request ID, and the consistency token and
+// canary choice the caller sent
+func forCaller(c *gin.Context, client *service.DriverServiceClient) *service.DriverServiceClient {
+	return client.WithIdentity(callerIdentity(c)).
+		WithRequestID(c.GetString("requestID")).
+		WithConsistencyToken(c.GetHeader(service.ConsistencyTokenHeader)).
+		WithCanaryHeader(c.GetHeader(canary.Header))
+}
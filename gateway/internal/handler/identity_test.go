@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestIdentityForwarding(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"trip-1","riderId":"rider-1"}`))
+	}))
+	defer server.Close()
+
+	client := service.NewDriverServiceClient(server.URL, zap.NewNop())
+	trips := NewTripHandler(client, zap.NewNop())
+
+	tests := []struct {
+		name   string
+		claims gin.HandlerFunc
+		want   map[string]string
+	}{
+		{
+			name:   "fleet admin",
+			claims: claimsOf("ops@fleet-1", token.RoleFleetAdmin, "fleet-1", ""),
+			want:   map[string]string{"X-User-Id": "ops@fleet-1", "X-User-Role": "fleet_admin", "X-Tenant-Id": "fleet-1"},
+		},
+		{
+			name:   "rider acts as itself",
+			claims: claimsOf("rider-1", token.RoleRider, "", "rider-1"),
+			want:   map[string]string{"X-User-Id": "rider-1", "X-User-Role": "rider", "X-Tenant-Id": ""},
+		},
+		{
+			name:   "unauthenticated",
+			claims: claimsOf("", "", "", ""),
+			want:   map[string]string{"X-User-Id": "", "X-User-Role": "", "X-Tenant-Id": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupGatewayRouter()
+			router.Use(tt.claims)
+			router.GET("/trips/:id", trips.GetTrip)
+
+			req := httptest.NewRequest("GET", "/trips/trip-1", nil)
+			// Identity headers from the client are never passed on
+			req.Header.Set("X-User-Id", "spoofed")
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			for name, value := range tt.want {
+				assert.Equal(t, value, got.Get(name), name)
+			}
+		})
+	}
+}
+
+// claimsOf simulates the claims the JWT middleware puts in the context
+func claimsOf(username, role, fleetID, riderID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if username != "" {
+			c.Set("username", username)
+		}
+		if role != "" {
+			c.Set("role", role)
+			c.Set("fleetId", fleetID)
+			c.Set("riderId", riderID)
+		}
+		c.Next()
+	}
+}
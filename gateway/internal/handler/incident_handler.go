@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// incidentFilters are the query parameters incident listings forward
+var incidentFilters = []string{"status", "severity", "category", "tripId", "page", "pageSize"}
+
+// IncidentHandler proxies incident requests to the driver service
+type IncidentHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewIncidentHandler creates a new incident handler
+func NewIncidentHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *IncidentHandler {
+	return &IncidentHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// ReportIncident handles POST /drivers/:id/incidents
+// @Summary Report an incident
+// @Description Report an incident against the driver, optionally linked to a trip the driver drove; riders must link one of their own trips. High and critical incidents reported by operators and fleet admins suspend the driver until they are dismissed; critical ones also revoke the driver's identity verification, which then needs a new identity check. Rider reports are stored open without effects until an investigator confirms their severity.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body ReportIncidentRequest true "Incident"
+// @Success 201 {object} Incident "Incident reported"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet, or the trip to another rider"
+// @Failure 404 {object} ErrorResponse "Driver or trip not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/incidents [post]
+func (h *IncidentHandler) ReportIncident(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).ReportIncident(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward report incident request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to report incident")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListDriverIncidents handles GET /drivers/:id/incidents
+// @Summary Driver incidents
+// @Description Incidents reported against the driver, newest first
+// @Tags incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param status query string false "open, investigating or resolved" example(open)
+// @Param severity query string false "low, medium, high or critical" example(high)
+// @Param category query string false "safety, conduct, vehicle, fare, route or other" example(safety)
+// @Param tripId query string false "Only incidents of this trip" example(6571f1f77bcf86cd79943901)
+// @Param page query int false "Page number" default(1) example(1)
+// @Param pageSize query int false "Page size, at most 100" default(20) example(20)
+// @Success 200 {object} ListIncidentsResponse "Incidents"
+// @Failure 400 {object} ErrorResponse "Invalid filter"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/incidents [get]
+func (h *IncidentHandler) ListDriverIncidents(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).ListDriverIncidents(c.Param("id"), incidentQuery(c))
+	if err != nil {
+		h.logger.Error("failed to forward list driver incidents request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list incidents")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListIncidents handles GET /incidents
+// @Summary Incident queue
+// @Description Incidents matching the filters, newest first. Fleet admins only see incidents reported while the driver was in their fleet.
+// @Tags incidents
+// @Produce json
+// @Security BearerAuth
+// @Param driverId query string false "Only incidents of this driver" example(507f1f77bcf86cd799439011)
+// @Param fleetId query string false "Only incidents of this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param status query string false "open, investigating or resolved" example(open)
+// @Param severity query string false "low, medium, high or critical" example(high)
+// @Param category query string false "safety, conduct, vehicle, fare, route or other" example(safety)
+// @Param tripId query string false "Only incidents of this trip" example(6571f1f77bcf86cd79943901)
+// @Param page query int false "Page number" default(1) example(1)
+// @Param pageSize query int false "Page size, at most 100" default(20) example(20)
+// @Success 200 {object} ListIncidentsResponse "Incidents"
+// @Failure 400 {object} ErrorResponse "Invalid filter"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents [get]
+func (h *IncidentHandler) ListIncidents(c *gin.Context) {
+	query := incidentQuery(c)
+	for _, name := range []string{"driverId", "fleetId"} {
+		if value := c.Query(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+
+	resp, err := forCaller(c, h.driverService).ListIncidents(query)
+	if err != nil {
+		h.logger.Error("failed to forward list incidents request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list incidents")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// incidentQuery copies the incident filters to forward
+func incidentQuery(c *gin.Context) url.Values {
+	query := url.Values{}
+	for _, name := range incidentFilters {
+		if value := c.Query(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+	return query
+}
+
+// GetIncident handles GET /incidents/:id
+// @Summary Get an incident
+// @Description Get an incident with its investigation status and outcome
+// @Tags incidents
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID" example("6575a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} Incident "Incident"
+// @Failure 403 {object} ErrorResponse "Incident of another fleet"
+// @Failure 404 {object} ErrorResponse "Incident not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents/{id} [get]
+func (h *IncidentHandler) GetIncident(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).GetIncident(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get incident request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get incident")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// UpdateIncidentStatus handles PUT /incidents/:id/status
+// @Summary Move an incident's investigation on
+// @Description Move an incident from open to investigating or resolved, or from investigating to resolved; resolved incidents cannot change. Moving to investigating with a severity confirms it: a high or critical one then suspends the driver, and a critical one revokes identity verification, if the report did not already. Resolving takes an outcome: upheld keeps a suspension the incident caused, dismissed lifts it unless another unresolved incident holds the driver. Revoked identity verification is not restored.
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Incident ID" example("6575a1f2c3d4e5f6a7b8c9d0")
+// @Param request body IncidentStatusRequest true "New status"
+// @Success 200 {object} Incident "Incident updated"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Incident of another fleet"
+// @Failure 404 {object} ErrorResponse "Incident not found"
+// @Failure 409 {object} ErrorResponse "Status does not allow the change"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /incidents/{id}/status [put]
+func (h *IncidentHandler) UpdateIncidentStatus(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).UpdateIncidentStatus(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward update incident status request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update incident")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
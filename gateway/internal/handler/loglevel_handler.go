@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/logging"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetLogLevelRequest changes the level of the root logger or of a named logger
+type SetLogLevelRequest struct {
+	// Logger is the logger name, such as "service" or "handler"; empty changes the root level
+	Logger string `json:"logger" example:"service"`
+	Level  string `json:"level" binding:"required" example:"debug"`
+}
+
+// LogLevelHandler changes the gateway's log levels without a restart
+type LogLevelHandler struct {
+	levels *logging.Levels
+	logger *zap.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler
+func NewLogLevelHandler(levels *logging.Levels, logger *zap.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		levels: levels,
+		logger: logger,
+	}
+}
+
+// GetLogLevels handles GET /admin/loglevel
+// @Summary Get log levels
+// @Description Get the root log level and the levels of named loggers that override it
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} logging.LevelsSnapshot "Log levels" example({"level":"info","loggers":{"service":"debug"}})
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/loglevel [get]
+func (h *LogLevelHandler) GetLogLevels(c *gin.Context) {
+	c.JSON(http.StatusOK, h.levels.Snapshot())
+}
+
+// SetLogLevel handles PUT /admin/loglevel
+// @Summary Change a log level
+// @Description Change the root log level, or override the level of a named logger and the loggers below it. The change lasts until the gateway restarts.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body SetLogLevelRequest true "Logger and level"
+// @Success 200 {object} logging.LevelsSnapshot "Log levels" example({"level":"info","loggers":{"service":"debug"}})
+// @Failure 400 {object} ErrorResponse "Unknown level" example({"error":{"code":"VALIDATION_ERROR","message":"unknown log level \"verbose\""}})
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/loglevel [put]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	level, err := logging.ParseLevel(req.Level)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	h.levels.SetLevel(req.Logger, level)
+	h.logger.Info("log level changed", zap.String("logger", req.Logger), zap.String("level", level.String()))
+	c.JSON(http.StatusOK, h.levels.Snapshot())
+}
+
+// ResetLogLevel handles DELETE /admin/loglevel/{logger}
+// @Summary Reset a logger's level
+// @Description Remove the level override of a named logger so it follows the root level again
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param logger path string true "Logger name" example(service)
+// @Success 200 {object} logging.LevelsSnapshot "Log levels" example({"level":"info","loggers":{}})
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/loglevel/{logger} [delete]
+func (h *LogLevelHandler) ResetLogLevel(c *gin.Context) {
+	h.levels.ResetLevel(c.Param("logger"))
+	h.logger.Info("log level reset", zap.String("logger", c.Param("logger")))
+	c.JSON(http.StatusOK, h.levels.Snapshot())
+}
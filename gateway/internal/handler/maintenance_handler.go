@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SetMaintenanceRequest switches maintenance mode on or off
+type SetMaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required" example:"true"`
+	// Message is shown to clients; empty uses the default message
+	Message string `json:"message" example:"Scheduled maintenance until 03:00 UTC"`
+	// RetryAfterSec is sent as Retry-After; 0 keeps the current value
+	RetryAfterSec int `json:"retryAfterSec" binding:"min=0" example:"600"`
+}
+
+// MaintenanceHandler lets operators switch the gateway's maintenance mode
+type MaintenanceHandler struct {
+	maintenance *middleware.Maintenance
+	logger      *zap.Logger
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenance *middleware.Maintenance, logger *zap.Logger) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenance: maintenance,
+		logger:      logger,
+	}
+}
+
+// GetMaintenance handles GET /admin/maintenance
+// @Summary Get maintenance mode
+// @Description Whether this gateway instance answers client requests with 503 for planned maintenance
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} middleware.MaintenanceStatus "Maintenance mode"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, h.maintenance.Status())
+}
+
+// SetMaintenance handles PUT /admin/maintenance
+// @Summary Switch maintenance mode
+// @Description While on, every route but /health, /ready and /admin/maintenance (and the other /admin routes unless MAINTENANCE_ALLOW_ADMIN=false) is answered with 503, a MAINTENANCE error carrying the message and Retry-After. The switch applies to this instance only and lasts until it restarts, when MAINTENANCE_MODE applies again.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body SetMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} middleware.MaintenanceStatus "Maintenance mode"
+// @Failure 400 {object} ErrorResponse "Invalid request"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/maintenance [put]
+func (h *MaintenanceHandler) SetMaintenance(c *gin.Context) {
+	var req SetMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if *req.Enabled {
+		h.maintenance.Enable(req.Message, time.Duration(req.RetryAfterSec)*time.Second)
+		h.logger.Warn("maintenance mode switched on", zap.String("message", req.Message), zap.Int("retryAfterSec", req.RetryAfterSec))
+	} else {
+		h.maintenance.Disable()
+		h.logger.Info("maintenance mode switched off")
+	}
+	c.JSON(http.StatusOK, h.maintenance.Status())
+}
@@ -1,36 +1,97 @@
 package handler
 
-// Driver represents a taxi driver
-type Driver struct {
-	ID        string `json:"id"`
-	FirstName string `json:"firstName"`
-	LastName  string `json:"lastName"`
-	Plate     string `json:"plate"`
-	TaxiType  string `json:"taxiType"`
-	CarBrand  string `json:"carBrand"`
-	CarModel  string `json:"carModel"`
-	Location  struct {
-		Lat float64 `json:"lat"`
-		Lon float64 `json:"lon"`
-	} `json:"location"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
-}
+import "github.com/bitaksi/gateway/internal/apimodel"
 
-// ListDriversResponse represents a paginated list of drivers
-type ListDriversResponse struct {
-	Drivers    []Driver `json:"drivers"`
-	TotalCount int64    `json:"totalCount"`
-	Page       int      `json:"page"`
-	PageSize   int      `json:"pageSize"`
-}
-
-// NearbyDriverResponse represents a driver in nearby search results
-type NearbyDriverResponse struct {
-	ID         string  `json:"id"`
-	FirstName  string  `json:"firstName"`
-	LastName   string  `json:"lastName"`
-	Plate      string  `json:"plate"`
-	TaxiType   string  `json:"taxiType"`
-	DistanceKm float64 `json:"distanceKm"`
-}
+// The request and response models are shared with the driver service client
+// and kept in step with the driver service in apimodel; the aliases let the
+// handlers and their Swagger annotations refer to them by their short names.
+type (
+	Driver                    = apimodel.Driver
+	DriverDocument            = apimodel.DriverDocument
+	KYCCheck                  = apimodel.KYCCheck
+	DriverPhoto               = apimodel.DriverPhoto
+	DriverLicense             = apimodel.DriverLicense
+	ExpiringLicense           = apimodel.ExpiringLicense
+	ExpiringLicensesResponse  = apimodel.ExpiringLicensesResponse
+	ValidationRuleset         = apimodel.ValidationRuleset
+	ValidationRulesResponse   = apimodel.ValidationRulesResponse
+	DriverQuota               = apimodel.DriverQuota
+	EarningsTotals            = apimodel.EarningsTotals
+	EarningsBucket            = apimodel.EarningsBucket
+	EarningsSummary           = apimodel.EarningsSummary
+	Earning                   = apimodel.Earning
+	Erasure                   = apimodel.Erasure
+	BulkFilter                = apimodel.BulkFilter
+	DriverPatch               = apimodel.DriverPatch
+	BulkUpdate                = apimodel.BulkUpdate
+	Job                       = apimodel.Job
+	PayoutLine                = apimodel.PayoutLine
+	Payout                    = apimodel.Payout
+	ListDriversResponse       = apimodel.ListDriversResponse
+	DriverChangesResponse     = apimodel.DriverChangesResponse
+	AutocompleteResponse      = apimodel.AutocompleteResponse
+	DriverSuggestion          = apimodel.DriverSuggestion
+	SearchHighlight           = apimodel.SearchHighlight
+	NearbyDriverResponse      = apimodel.NearbyDriverResponse
+	NearbyDriverWithFare      = apimodel.NearbyDriverWithFare
+	FareQuote                 = apimodel.FareQuote
+	RouteSearchRequest        = apimodel.RouteSearchRequest
+	RouteDriverResponse       = apimodel.RouteDriverResponse
+	Location                  = apimodel.Location
+	Trip                      = apimodel.Trip
+	FareEstimate              = apimodel.FareEstimate
+	TaxiType                  = apimodel.TaxiType
+	TaxiTypeCatalog           = apimodel.TaxiTypeCatalog
+	Rider                     = apimodel.Rider
+	FavoriteLocation          = apimodel.FavoriteLocation
+	RiderRegistrationResponse = apimodel.RiderRegistrationResponse
+	RiderSignInResponse       = apimodel.RiderSignInResponse
+	Fleet                     = apimodel.Fleet
+	DriverStats               = apimodel.DriverStats
+	OnlineStats               = apimodel.OnlineStats
+	UtilizationReport         = apimodel.UtilizationReport
+	LocationAnomalyReport     = apimodel.LocationAnomalyReport
+	WebhookSubscription       = apimodel.WebhookSubscription
+	DeviceToken               = apimodel.DeviceToken
+	ScheduledShift            = apimodel.ScheduledShift
+	ShiftCalendar             = apimodel.ShiftCalendar
+	Incident                  = apimodel.Incident
+	ListIncidentsResponse     = apimodel.ListIncidentsResponse
+	WebhookDelivery           = apimodel.WebhookDelivery
+	IndexStatus               = apimodel.IndexStatus
+	IndexReport               = apimodel.IndexReport
+	FailoverStats             = apimodel.FailoverStats
+	TelemetryStats            = apimodel.TelemetryStats
+	QueryStats                = apimodel.QueryStats
+	IndexSyncError            = apimodel.IndexSyncError
+	IndexSyncJob              = apimodel.IndexSyncJob
+	CreateDriverRequest       = apimodel.CreateDriverRequest
+	UpdateDriverRequest       = apimodel.UpdateDriverRequest
+	SetAvailabilityRequest    = apimodel.SetAvailabilityRequest
+	UpdateLocationRequest     = apimodel.UpdateLocationRequest
+	IssueDeviceTokenRequest   = apimodel.IssueDeviceTokenRequest
+	ScheduleShiftRequest      = apimodel.ScheduleShiftRequest
+	ReportIncidentRequest     = apimodel.ReportIncidentRequest
+	IncidentStatusRequest     = apimodel.IncidentStatusRequest
+	SetSuspensionRequest      = apimodel.SetSuspensionRequest
+	CreateWebhookRequest      = apimodel.CreateWebhookRequest
+	VerifyPhoneRequest        = apimodel.VerifyPhoneRequest
+	CreateFleetRequest        = apimodel.CreateFleetRequest
+	AssignFleetRequest        = apimodel.AssignFleetRequest
+	RegisterRiderRequest      = apimodel.RegisterRiderRequest
+	RiderSignInCodeRequest    = apimodel.RiderSignInCodeRequest
+	RiderSignInRequest        = apimodel.RiderSignInRequest
+	UpdateRiderRequest        = apimodel.UpdateRiderRequest
+	FavoriteLocationRequest   = apimodel.FavoriteLocationRequest
+	CreateTripRequest         = apimodel.CreateTripRequest
+	EstimateFareRequest       = apimodel.EstimateFareRequest
+	TripOfferRequest          = apimodel.TripOfferRequest
+	CompleteTripRequest       = apimodel.CompleteTripRequest
+	UploadDocumentRequest     = apimodel.UploadDocumentRequest
+	OnboardingRequest         = apimodel.OnboardingRequest
+	CreateAdjustmentRequest   = apimodel.CreateAdjustmentRequest
+	CreatePayoutRequest       = apimodel.CreatePayoutRequest
+	ExportRequest             = apimodel.ExportRequest
+	BulkUpdateRequest         = apimodel.BulkUpdateRequest
+	ListDriversQuery          = apimodel.ListDriversQuery
+)
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/oidc"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// oidcCookie carries a login across the redirect to the identity provider
+const oidcCookie = "oidc_login"
+
+// OIDCLogin handles GET /auth/oidc/login
+// @Summary Sign in with SSO
+// @Description Redirects to the OpenID Connect provider to sign in with the authorization code flow and PKCE. The provider redirects back to /auth/oidc/callback, which issues a gateway token. The login must finish within OIDC_LOGIN_TIMEOUT_SEC in the same browser.
+// @Tags auth
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} ErrorResponse "SSO is not configured"
+// @Failure 502 {object} ErrorResponse "Identity provider unavailable"
+// @Router /auth/oidc/login [get]
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	if h.sso == nil {
+		h.respondError(c, http.StatusNotFound, "NOT_FOUND", "SSO login is not configured")
+		return
+	}
+
+	login, authURL, err := h.sso.Start(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to start SSO login", zap.Error(err))
+		h.respondError(c, http.StatusBadGateway, "OIDC_UNAVAILABLE", "identity provider is unavailable")
+		return
+	}
+
+	h.setLoginCookie(c, h.sso.Seal(login), int(h.sso.LoginTimeout().Seconds()))
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback handles GET /auth/oidc/callback
+// @Summary SSO callback
+// @Description The identity provider redirects here after sign-in. The authorization code is redeemed, the ID token validated and its role claim mapped to a gateway role; users without a mapped role are refused. Answers with a gateway token, or redirects to OIDC_POST_LOGIN_URL with #token=... when configured.
+// @Tags auth
+// @Produce json
+// @Param code query string false "Authorization code"
+// @Param state query string true "Login state"
+// @Param error query string false "Error reported by the identity provider"
+// @Success 200 {object} LoginResponse "Authentication successful"
+// @Success 302 "Redirect to OIDC_POST_LOGIN_URL with the token"
+// @Failure 400 {object} ErrorResponse "Login expired, or started in another browser"
+// @Failure 401 {object} ErrorResponse "Sign-in refused or failed"
+// @Failure 403 {object} ErrorResponse "No role is mapped to the user"
+// @Failure 404 {object} ErrorResponse "SSO is not configured"
+// @Failure 502 {object} ErrorResponse "Identity provider unavailable"
+// @Router /auth/oidc/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	if h.sso == nil {
+		h.respondError(c, http.StatusNotFound, "NOT_FOUND", "SSO login is not configured")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+
+	// A login is used once, however it ends
+	sealed, _ := c.Cookie(oidcCookie)
+	h.setLoginCookie(c, "", -1)
+
+	login, err := h.sso.Open(sealed)
+	if err != nil || subtle.ConstantTimeCompare([]byte(login.State), []byte(c.Query("state"))) != 1 {
+		h.respondError(c, http.StatusBadRequest, "INVALID_STATE", "login expired or was started in another browser; sign in again")
+		return
+	}
+	if reason := c.Query("error"); reason != "" {
+		h.logger.Info("identity provider refused sign-in", zap.String("error", reason), zap.String("description", c.Query("error_description")))
+		h.respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "sign-in was refused by the identity provider: "+reason)
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", "code is required")
+		return
+	}
+
+	identity, err := h.sso.Authenticate(c.Request.Context(), code, login)
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnavailable) {
+			h.logger.Error("identity provider unavailable", zap.Error(err))
+			h.respondError(c, http.StatusBadGateway, "OIDC_UNAVAILABLE", "identity provider is unavailable")
+			return
+		}
+		h.logger.Warn("SSO sign-in failed", zap.Error(err))
+		h.respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "sign-in failed")
+		return
+	}
+
+	opts, ok := h.ssoAccess(identity)
+	if !ok {
+		h.logger.Warn("SSO user has no mapped role", zap.String("username", identity.Username), zap.String("subject", identity.Subject))
+		h.respondError(c, http.StatusForbidden, "FORBIDDEN", "no role is granted to this account")
+		return
+	}
+	issued, err := h.tokens.Issue(identity.Username, append(opts, token.WithIdentityProvider(h.sso.Issuer()))...)
+	if err != nil {
+		h.logger.Error("failed to generate token", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to generate token")
+		return
+	}
+	h.logger.Info("SSO sign-in", zap.String("username", identity.Username))
+
+	if target := h.config.Auth.OIDC.PostLoginURL; target != "" {
+		// The fragment never reaches servers or their logs
+		c.Redirect(http.StatusFound, target+"#token="+url.QueryEscape(issued))
+		return
+	}
+	c.JSON(http.StatusOK, LoginResponse{Token: issued})
+}
+
+// ssoAccess maps the identity's role claim to token options; without a mapped
+// value, usernames listed in FLEET_ADMINS still sign in as fleet admins
+func (h *AuthHandler) ssoAccess(identity *oidc.Identity) ([]token.IssueOption, bool) {
+	if role, ok := h.sso.Access(identity); ok {
+		if role.Role == token.RoleFleetAdmin {
+			return []token.IssueOption{token.WithRole(token.RoleFleetAdmin), token.WithFleet(role.FleetID)}, true
+		}
+		return nil, true
+	}
+	if fleetID, ok := h.config.Auth.FleetAdmins[identity.Username]; ok {
+		return []token.IssueOption{token.WithRole(token.RoleFleetAdmin), token.WithFleet(fleetID)}, true
+	}
+	return nil, false
+}
+
+// setLoginCookie sets or, with a negative maxAge, clears the login cookie. It
+// is sent on the provider's top-level redirect back, so SameSite is Lax.
+func (h *AuthHandler) setLoginCookie(c *gin.Context, value string, maxAge int) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		Path:     "/auth/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(h.config.Auth.OIDC.RedirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+}
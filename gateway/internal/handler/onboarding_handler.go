@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OnboardingHandler handles driver onboarding requests in the gateway
+type OnboardingHandler struct {
+	onboarding *service.OnboardingService
+	logger     *zap.Logger
+}
+
+// NewOnboardingHandler creates a new onboarding handler
+func NewOnboardingHandler(onboarding *service.OnboardingService, logger *zap.Logger) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboarding: onboarding,
+		logger:     logger,
+	}
+}
+
+// OnboardDriver handles POST /onboarding/drivers
+// @Summary Onboard a driver
+// @Description Create a driver, attach their documents, send a phone verification code and optionally put them on shift in one call. If a required step fails, the completed steps are rolled back and the response reports each step's outcome.
+// @Tags onboarding
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body OnboardingRequest true "Driver, documents and availability"
+// @Param X-Device-Fingerprint header string false "Device fingerprint; registrations are capped per device and per IP each day"
+// @Success 201 {object} service.OnboardingResult "Driver onboarded (status completed or pending_verification)"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Fleet admin onboarding into another fleet"
+// @Failure 409 {object} service.OnboardingResult "Rolled back after a conflict, e.g. phone already registered"
+// @Failure 429 {object} ErrorResponse "Daily registration cap reached for this device or IP"
+// @Failure 500 {object} service.OnboardingResult "Rolled back, or rollback failed"
+// @Router /onboarding/drivers [post]
+func (h *OnboardingHandler) OnboardDriver(c *gin.Context) {
+	var req OnboardingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if scope, scoped := scopedFleet(c); scoped {
+		if req.Driver.FleetID != "" && req.Driver.FleetID != scope {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "fleet admins can only create drivers in their own fleet")
+			return
+		}
+		req.Driver.FleetID = scope
+	}
+
+	documents := make([]service.OnboardingDocument, len(req.Documents))
+	for i, doc := range req.Documents {
+		documents[i] = service.OnboardingDocument{Type: doc.Type, Body: doc}
+	}
+
+	result := h.onboarding.Onboard(&service.OnboardingRequest{
+		Driver:    req.Driver,
+		Documents: documents,
+		Available: req.Available,
+		Caller:    callerIdentity(c),
+	})
+
+	c.JSON(onboardingStatus(result), result)
+}
+
+// onboardingStatus picks the HTTP status for an onboarding outcome. Client errors
+// reported by the failed step are passed through; anything else is a server error.
+func onboardingStatus(result *service.OnboardingResult) int {
+	switch result.Status {
+	case service.OnboardingCompleted, service.OnboardingPendingVerification:
+		return http.StatusCreated
+	case service.OnboardingRolledBack:
+		if failed := result.FailedStep; failed != nil && failed.Status >= 400 && failed.Status < 500 {
+			return failed.Status
+		}
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/openapi"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// openAPICacheTTL is how long a merged spec is served before the driver
+// service's spec is fetched again
+const openAPICacheTTL = 5 * time.Minute
+
+// OpenAPIHandler serves the gateway spec merged with the driver service's.
+// The public group leaves out operations tagged with one of the internal tags;
+// the internal group lists everything.
+type OpenAPIHandler struct {
+	gatewaySpec   []byte
+	publicSpec    []byte
+	internalTags  []string
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+
+	mu       sync.Mutex
+	public   cachedSpec
+	internal cachedSpec
+}
+
+type cachedSpec struct {
+	data     []byte
+	mergedAt time.Time
+}
+
+// NewOpenAPIHandler creates a new OpenAPI handler
+func NewOpenAPIHandler(gatewaySpec string, internalTags []string, driverService *service.DriverServiceClient, logger *zap.Logger) (*OpenAPIHandler, error) {
+	publicSpec, err := openapi.Public([]byte(gatewaySpec), internalTags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAPIHandler{
+		gatewaySpec:   []byte(gatewaySpec),
+		publicSpec:    publicSpec,
+		internalTags:  internalTags,
+		driverService: driverService,
+		logger:        logger,
+	}, nil
+}
+
+// SwaggerUI wraps a Swagger UI handler so its doc.json request is answered
+// with the gateway's own spec for the group rather than the generated one
+func (h *OpenAPIHandler) SwaggerUI(ui gin.HandlerFunc, public bool) gin.HandlerFunc {
+	spec := h.gatewaySpec
+	if public {
+		spec = h.publicSpec
+	}
+	return func(c *gin.Context) {
+		if c.Param("any") == "/doc.json" {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+			return
+		}
+		ui(c)
+	}
+}
+
+// GetOpenAPI handles GET /openapi.json
+// @Summary Get the merged API spec
+// @Description Get the gateway's Swagger 2.0 spec merged with the driver service's. Driver service operations are listed under /driver-service/api/v1 with the driver-service tag and their definitions are prefixed with "driver-service.". Operations tagged with one of SWAGGER_INTERNAL_TAGS are left out; GET /admin/openapi.json lists them. When the driver service is unreachable only the gateway spec is returned.
+// @Tags docs
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Swagger 2.0 spec"
+// @Router /openapi.json [get]
+func (h *OpenAPIHandler) GetOpenAPI(c *gin.Context) {
+	h.serve(c, &h.public, h.publicSpec, true)
+}
+
+// GetInternalOpenAPI handles GET /admin/openapi.json
+// @Summary Get the internal merged API spec
+// @Description Get the merged Swagger 2.0 spec including internal operations such as the admin API
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} map[string]interface{} "Swagger 2.0 spec"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/openapi.json [get]
+func (h *OpenAPIHandler) GetInternalOpenAPI(c *gin.Context) {
+	h.serve(c, &h.internal, h.gatewaySpec, false)
+}
+
+func (h *OpenAPIHandler) serve(c *gin.Context, cache *cachedSpec, gatewaySpec []byte, public bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if cache.data == nil || time.Since(cache.mergedAt) > openAPICacheTTL {
+		spec, err := h.merge(c, gatewaySpec, public)
+		if err != nil {
+			// Not cached, so the next request tries the driver service again
+			h.logger.Warn("serving gateway spec without the driver service", zap.Error(err))
+			c.Data(http.StatusOK, "application/json; charset=utf-8", gatewaySpec)
+			return
+		}
+		cache.data = spec
+		cache.mergedAt = time.Now()
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", cache.data)
+}
+
+func (h *OpenAPIHandler) merge(c *gin.Context, gatewaySpec []byte, public bool) ([]byte, error) {
+	upstream, err := h.driverService.GetOpenAPISpec(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	// Filter before merging, since merging retags driver service operations
+	if public {
+		if upstream, err = openapi.Public(upstream, h.internalTags); err != nil {
+			return nil, err
+		}
+	}
+	return openapi.Merge(gatewaySpec, upstream)
+}
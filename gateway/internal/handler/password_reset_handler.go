@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/account"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PasswordResetRequest asks for a password reset link
+type PasswordResetRequest struct {
+	Email string `json:"email" binding:"required,email" example:"dispatch@example.com"`
+}
+
+// PasswordResetConfirmRequest sets a new password with a reset token
+type PasswordResetConfirmRequest struct {
+	// Token is the token of the emailed reset link
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
+}
+
+// PasswordResetResponse acknowledges a password reset step
+type PasswordResetResponse struct {
+	Message string `json:"message" example:"if the email belongs to an account, a reset link is on its way"`
+}
+
+// RequestPasswordReset handles POST /auth/password-reset/request
+// @Summary Request a password reset
+// @Description Emails a reset link, valid for PASSWORD_RESET_TTL_MIN and usable once, to the account with the address. The answer is the same whether or not an account has the address. Requests are limited per address (PASSWORD_RESET_MAX_PER_EMAIL) and per client IP (PASSWORD_RESET_MAX_PER_IP) within PASSWORD_RESET_WINDOW_MIN, and audit-logged.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body PasswordResetRequest true "Email address of the account"
+// @Success 202 {object} PasswordResetResponse "Reset link sent if the account exists"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 404 {object} ErrorResponse "Password accounts are not configured"
+// @Failure 429 {object} ErrorResponse "Too many reset requests" example({"error":{"code":"RATE_LIMIT_EXCEEDED","message":"too many password reset attempts, please try again later"}})
+// @Router /auth/password-reset/request [post]
+func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
+	if h.reset == nil {
+		h.respondError(c, http.StatusNotFound, "NOT_FOUND", "password reset is not configured")
+		return
+	}
+	var req PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if err := h.reset.Request(req.Email, c.ClientIP()); err != nil {
+		h.respondResetError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, PasswordResetResponse{Message: "if the email belongs to an account, a reset link is on its way"})
+}
+
+// ConfirmPasswordReset handles POST /auth/password-reset/confirm
+// @Summary Set a new password
+// @Description Sets the password of the account a reset token was emailed to. Tokens stop working once they expire or a password was set with them. Attempts count towards the client IP's PASSWORD_RESET_MAX_PER_IP and are audit-logged.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body PasswordResetConfirmRequest true "Reset token and new password"
+// @Success 200 {object} PasswordResetResponse "Password changed"
+// @Failure 400 {object} ErrorResponse "Invalid or expired token, or a password that is too short" example({"error":{"code":"INVALID_RESET_TOKEN","message":"invalid or expired reset token"}})
+// @Failure 404 {object} ErrorResponse "Password accounts are not configured"
+// @Failure 429 {object} ErrorResponse "Too many attempts"
+// @Router /auth/password-reset/confirm [post]
+func (h *AuthHandler) ConfirmPasswordReset(c *gin.Context) {
+	if h.reset == nil {
+		h.respondError(c, http.StatusNotFound, "NOT_FOUND", "password reset is not configured")
+		return
+	}
+	var req PasswordResetConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	if _, err := h.reset.Confirm(req.Token, req.Password, c.ClientIP()); err != nil {
+		h.respondResetError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, PasswordResetResponse{Message: "password changed"})
+}
+
+func (h *AuthHandler) respondResetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, account.ErrRateLimited):
+		h.respondError(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "too many password reset attempts, please try again later")
+	case errors.Is(err, account.ErrInvalidToken):
+		h.respondError(c, http.StatusBadRequest, "INVALID_RESET_TOKEN", err.Error())
+	case errors.Is(err, account.ErrWeakPassword):
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+	default:
+		h.logger.Error("password reset failed", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to reset password")
+	}
+}
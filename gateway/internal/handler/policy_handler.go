@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RouteAuth is the authentication a registered route requires
+type RouteAuth struct {
+	Method string   `json:"method" example:"PUT"`
+	Path   string   `json:"path" example:"/drivers/:id"`
+	Auth   string   `json:"auth" example:"jwt" enums:"public,apikey,jwt,admin"`
+	Roles  []string `json:"roles,omitempty" example:"fleet_admin"`
+}
+
+// AuthPolicyResponse is the effective auth policy of the gateway
+type AuthPolicyResponse struct {
+	// Default applies to routes no rule matches
+	Default string      `json:"default" example:"jwt"`
+	Routes  []RouteAuth `json:"routes"`
+	// UnusedRules match no route, typically a typo in the policy file
+	UnusedRules []policy.Rule `json:"unusedRules"`
+}
+
+// PolicyHandler exposes the auth policy to operators
+type PolicyHandler struct {
+	policy *policy.Policy
+	routes func() []policy.Route
+	logger *zap.Logger
+}
+
+// NewPolicyHandler creates a new policy handler; routes lists the registered routes
+func NewPolicyHandler(p *policy.Policy, routes func() []policy.Route, logger *zap.Logger) *PolicyHandler {
+	return &PolicyHandler{
+		policy: p,
+		routes: routes,
+		logger: logger,
+	}
+}
+
+// GetAuthPolicy handles GET /admin/auth-policy
+// @Summary Get auth policy
+// @Description Every registered route with the authentication the policy (AUTH_POLICY_FILE, or the built-in one) requires of it, and the policy rules that match no route. API key and JWT requirements are only enforced while API keys and JWT are enabled.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} AuthPolicyResponse "Effective auth policy"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/auth-policy [get]
+func (h *PolicyHandler) GetAuthPolicy(c *gin.Context) {
+	routes := h.routes()
+	response := AuthPolicyResponse{
+		Default:     h.policy.Default,
+		Routes:      make([]RouteAuth, len(routes)),
+		UnusedRules: h.policy.Unused(routes),
+	}
+	for i, route := range routes {
+		rule := h.policy.Resolve(route.Method, route.Path)
+		response.Routes[i] = RouteAuth{Method: route.Method, Path: route.Path, Auth: rule.Auth, Roles: rule.Roles}
+	}
+	if response.UnusedRules == nil {
+		response.UnusedRules = []policy.Rule{}
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errNotObject is returned for request bodies that are valid JSON but not an object
+var errNotObject = errors.New("request body must be a JSON object")
+
+// requestBody is a JSON object request body that is forwarded to the driver
+// service byte for byte. Handlers read members to validate them and set the
+// few they rewrite; every other member keeps its exact bytes, so large
+// numbers are not turned into floats and member order and unknown fields
+// reach the driver service as the client sent them.
+type requestBody struct {
+	data    []byte
+	members map[string]json.RawMessage
+}
+
+// bindRequestBody reads the request body and checks that it is a JSON object.
+// An empty body is io.EOF, like for ShouldBindJSON.
+func bindRequestBody(c *gin.Context) (*requestBody, error) {
+	data, err := c.GetRawData()
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, io.EOF
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, errNotObject
+		}
+		return nil, err
+	}
+	if members == nil {
+		return nil, errNotObject
+	}
+	return &requestBody{data: data, members: members}, nil
+}
+
+// get returns the decoded value of a member; numbers are json.Number
+func (b *requestBody) get(key string) (interface{}, bool) {
+	raw, ok := b.members[key]
+	if !ok {
+		return nil, false
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	// The member was parsed with the body already
+	_ = decoder.Decode(&value)
+	return value, true
+}
+
+// set replaces the value of a member, or appends the member when the body
+// does not have it. Only the member's value changes in the forwarded bytes.
+func (b *requestBody) set(key, value string) {
+	encoded, _ := json.Marshal(value)
+
+	spans := b.valueSpans(key)
+	if len(spans) == 0 {
+		end := bytes.LastIndexByte(b.data, '}')
+		member, _ := json.Marshal(key)
+		member = append(append(member, ':'), encoded...)
+		if len(b.members) > 0 {
+			member = append([]byte{','}, member...)
+		}
+		b.data = append(b.data[:end:end], append(member, b.data[end:]...)...)
+	} else {
+		// Every duplicate is replaced so no reader can pick up another value
+		var out []byte
+		last := 0
+		for _, span := range spans {
+			out = append(append(out, b.data[last:span[0]]...), encoded...)
+			last = span[1]
+		}
+		b.data = append(out, b.data[last:]...)
+	}
+	b.members[key] = encoded
+}
+
+// valueSpans returns the byte ranges of the values of the top-level members
+// named key
+func (b *requestBody) valueSpans(key string) [][2]int {
+	decoder := json.NewDecoder(bytes.NewReader(b.data))
+	// The body was validated as an object when it was bound
+	if _, err := decoder.Token(); err != nil {
+		return nil
+	}
+	var spans [][2]int
+	for decoder.More() {
+		name, err := decoder.Token()
+		if err != nil {
+			return spans
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return spans
+		}
+		if name == key {
+			end := int(decoder.InputOffset())
+			spans = append(spans, [2]int{end - len(value), end})
+		}
+	}
+	return spans
+}
+
+// payload is what is forwarded: the body as received with the members set,
+// or nil without a body
+func (b *requestBody) payload() interface{} {
+	if b == nil {
+		return nil
+	}
+	return b.data
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bindTestBody(t *testing.T, body string) (*requestBody, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	return bindRequestBody(c)
+}
+
+func TestBindRequestBody(t *testing.T) {
+	_, err := bindTestBody(t, "  ")
+	assert.Equal(t, io.EOF, err)
+
+	for _, body := range []string{`[1,2]`, `"text"`, `null`} {
+		_, err = bindTestBody(t, body)
+		assert.Equal(t, errNotObject, err, body)
+	}
+
+	_, err = bindTestBody(t, `{"a":`)
+	assert.Error(t, err)
+
+	b, err := bindTestBody(t, `{"id":9007199254740993,"name":"x"}`)
+	require.NoError(t, err)
+	value, ok := b.get("id")
+	assert.True(t, ok)
+	assert.Equal(t, json.Number("9007199254740993"), value)
+	_, ok = b.get("missing")
+	assert.False(t, ok)
+}
+
+func TestRequestBody_Set(t *testing.T) {
+	tests := []struct {
+		name, body, key, value, want string
+	}{
+		{"replaces the value in place", `{"a":1, "fleetId" : "f2" ,"b":2}`, "fleetId", "f1", `{"a":1, "fleetId" : "f1" ,"b":2}`},
+		{"replaces every duplicate", `{"fleetId":"f2","fleetId":{"x":1}}`, "fleetId", "f1", `{"fleetId":"f1","fleetId":"f1"}`},
+		{"leaves nested members alone", `{"meta":{"fleetId":"f2"}}`, "fleetId", "f1", `{"meta":{"fleetId":"f2"},"fleetId":"f1"}`},
+		{"appends to an empty object", "{ }\n", "fleetId", "f1", "{ \"fleetId\":\"f1\"}\n"},
+		{"escapes the value", `{"a":1}`, "note", `say "hi"`, `{"a":1,"note":"say \"hi\""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bindTestBody(t, tt.body)
+			require.NoError(t, err)
+			b.set(tt.key, tt.value)
+			assert.Equal(t, tt.want, string(b.payload().([]byte)))
+			value, _ := b.get(tt.key)
+			assert.Equal(t, tt.value, value)
+		})
+	}
+
+	var missing *requestBody
+	assert.Nil(t, missing.payload(), "no body forwards nothing")
+}
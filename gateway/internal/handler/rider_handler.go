@@ -0,0 +1,308 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RiderHandler handles rider requests in the gateway. Registration and phone
+// sign-in are public and return a token with the rider role; riders may only
+// access their own profile, and fleet admins cannot access riders at all.
+type RiderHandler struct {
+	driverService *service.DriverServiceClient
+	tokens        *token.Manager
+	logger        *zap.Logger
+}
+
+// NewRiderHandler creates a new rider handler
+func NewRiderHandler(driverService *service.DriverServiceClient, tokens *token.Manager, logger *zap.Logger) *RiderHandler {
+	return &RiderHandler{
+		driverService: driverService,
+		tokens:        tokens,
+		logger:        logger,
+	}
+}
+
+// RegisterRider handles POST /riders
+// @Summary Register a rider
+// @Description Register a rider and get an access token with the rider role. Phone numbers must be unique.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param rider body RegisterRiderRequest true "Rider information"
+// @Param X-Device-Fingerprint header string false "Device fingerprint; registrations are capped per device and per IP each day"
+// @Success 201 {object} RiderRegistrationResponse "Rider registered"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 409 {object} ErrorResponse "Phone already registered"
+// @Failure 429 {object} ErrorResponse "Daily registration cap reached for this device or IP"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders [post]
+func (h *RiderHandler) RegisterRider(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).RegisterRider(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward rider registration", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to register rider")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.respondWithToken(c, resp, http.StatusCreated, "failed to register rider")
+}
+
+// SendSignInCode handles POST /riders/sign-in/send
+// @Summary Send a rider sign-in code
+// @Description Text a one-time sign-in code to the rider registered with the phone. Unknown phones get the same answer, so the response does not tell whether the phone is registered.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param request body RiderSignInCodeRequest true "Rider phone"
+// @Success 202 {object} map[string]string "Code sent if the phone is registered" example({"status":"sent"})
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 429 {object} ErrorResponse "Code sent recently"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/sign-in/send [post]
+func (h *RiderHandler) SendSignInCode(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SendRiderSignInCode(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward rider sign-in code request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to send verification code")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// SignIn handles POST /riders/sign-in
+// @Summary Sign a rider in
+// @Description Sign a rider in with the code texted by /riders/sign-in/send and get a new access token with the rider role, e.g. once the previous one has expired.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Param request body RiderSignInRequest true "Rider phone and code"
+// @Success 200 {object} RiderSignInResponse "Rider signed in"
+// @Failure 400 {object} ErrorResponse "Invalid or expired code"
+// @Failure 429 {object} ErrorResponse "Too many attempts; request a new code"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/sign-in [post]
+func (h *RiderHandler) SignIn(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).SignInRider(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward rider sign-in", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to sign in")
+		return
+	}
+	defer resp.Body.Close()
+
+	h.respondWithToken(c, resp, http.StatusOK, "failed to sign in")
+}
+
+// respondWithToken answers a driver service response carrying a rider with
+// the rider and a token with the rider role, or forwards any other response
+func (h *RiderHandler) respondWithToken(c *gin.Context, resp *http.Response, status int, message string) {
+	if resp.StatusCode != status {
+		forwardResponse(c, resp, h.logger)
+		return
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("failed to read rider", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", message)
+		return
+	}
+	var rider struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &rider); err != nil || rider.ID == "" {
+		h.logger.Error("failed to decode rider", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", message)
+		return
+	}
+
+	tokenString, err := h.tokens.Issue(rider.ID, token.WithRole(token.RoleRider), token.WithRider(rider.ID))
+	if err != nil {
+		h.logger.Error("failed to generate rider token", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to generate token")
+		return
+	}
+
+	c.JSON(status, gin.H{"rider": json.RawMessage(raw), "token": tokenString})
+}
+
+// GetRider handles GET /riders/:id
+// @Summary Get a rider
+// @Description Get a rider's profile and favorite locations. Riders can only read their own profile.
+// @Tags riders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} Rider "Rider"
+// @Failure 403 {object} ErrorResponse "Another rider"
+// @Failure 404 {object} ErrorResponse "Rider not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/{id} [get]
+func (h *RiderHandler) GetRider(c *gin.Context) {
+	if !requireRider(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetRider(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get rider request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get rider")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// UpdateRider handles PUT /riders/:id
+// @Summary Update a rider
+// @Description Update a rider's profile; only the fields in the request body change. Riders can only update their own profile.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param rider body UpdateRiderRequest true "Profile fields to change"
+// @Success 200 {object} Rider "Updated rider"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Another rider"
+// @Failure 404 {object} ErrorResponse "Rider not found"
+// @Failure 409 {object} ErrorResponse "Phone already registered"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/{id} [put]
+func (h *RiderHandler) UpdateRider(c *gin.Context) {
+	if !requireRider(c, c.Param("id")) {
+		return
+	}
+
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).UpdateRider(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward update rider request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update rider")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// AddFavoriteLocation handles POST /riders/:id/favorites
+// @Summary Save a favorite location
+// @Description Save a labelled place, such as home or work, for quick trip requests. A rider can save up to 20 places.
+// @Tags riders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param favorite body FavoriteLocationRequest true "Place to save"
+// @Success 201 {object} Rider "Rider with the saved place"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Another rider"
+// @Failure 404 {object} ErrorResponse "Rider not found"
+// @Failure 409 {object} ErrorResponse "Too many favorite locations"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/{id}/favorites [post]
+func (h *RiderHandler) AddFavoriteLocation(c *gin.Context) {
+	if !requireRider(c, c.Param("id")) {
+		return
+	}
+
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).AddFavoriteLocation(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward favorite location", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update rider")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// RemoveFavoriteLocation handles DELETE /riders/:id/favorites/:favoriteId
+// @Summary Remove a favorite location
+// @Description Remove a saved place from the rider's favorites
+// @Tags riders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Rider ID" example("6573a1f2c3d4e5f6a7b8c9d0")
+// @Param favoriteId path string true "Favorite location ID" example("fav_3f9a1c2b")
+// @Success 200 {object} Rider "Rider without the removed place"
+// @Failure 403 {object} ErrorResponse "Another rider"
+// @Failure 404 {object} ErrorResponse "Rider or favorite location not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /riders/{id}/favorites/{favoriteId} [delete]
+func (h *RiderHandler) RemoveFavoriteLocation(c *gin.Context) {
+	if !requireRider(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).RemoveFavoriteLocation(c.Param("id"), c.Param("favoriteId"))
+	if err != nil {
+		h.logger.Error("failed to forward favorite location removal", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update rider")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// scopedRider returns the rider a caller with the rider role acts as. ok is
+// false for callers without the rider role.
+func scopedRider(c *gin.Context) (riderID string, ok bool) {
+	if c.GetString("role") != token.RoleRider {
+		return "", false
+	}
+	return c.GetString("riderId"), true
+}
+
+// requireRider rejects fleet admins and riders other than riderID
+func requireRider(c *gin.Context, riderID string) bool {
+	if !requireUnscoped(c) {
+		return false
+	}
+	if scope, scoped := scopedRider(c); scoped && scope != riderID {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "riders can only access their own profile")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRiderUpstream registers riders and serves trips by ID
+type fakeRiderUpstream struct {
+	tripRiders map[string]string
+	requests   []string
+	lastBody   map[string]interface{}
+}
+
+func (f *fakeRiderUpstream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.requests = append(f.requests, r.Method+" "+r.URL.RequestURI())
+	f.lastBody = nil
+	json.NewDecoder(r.Body).Decode(&f.lastBody)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/riders":
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"rider-1","firstName":"Elif","favoriteLocations":[]}`))
+	case r.Method == "POST" && r.URL.Path == "/api/v1/riders/sign-in":
+		if f.lastBody["code"] != "123456" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"INVALID_CODE","message":"invalid verification code"}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"rider-1","firstName":"Elif","favoriteLocations":[]}`))
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/api/v1/trips/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/trips/")
+		riderID, ok := f.tripRiders[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"trip not found"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id": id, "riderId": riderID})
+	default:
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}
+}
+
+// asRider simulates the claims the JWT middleware puts in the context for a rider token
+func asRider(riderID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("role", token.RoleRider)
+		c.Set("riderId", riderID)
+		c.Next()
+	}
+}
+
+func TestRiderScope(t *testing.T) {
+	upstream := &fakeRiderUpstream{tripRiders: map[string]string{
+		"own":   "rider-1",
+		"other": "rider-2",
+	}}
+	server := httptest.NewServer(upstream)
+	defer server.Close()
+
+	logger := zap.NewNop()
+	client := service.NewDriverServiceClient(server.URL, logger)
+	tokens := token.NewHS256Manager("test-secret", time.Hour)
+	riders := NewRiderHandler(client, tokens, logger)
+	trips := NewTripHandler(client, logger)
+
+	newRouter := func(scope gin.HandlerFunc) *gin.Engine {
+		router := setupGatewayRouter()
+		router.Use(scope)
+		router.POST("/riders", riders.RegisterRider)
+		router.POST("/riders/sign-in", riders.SignIn)
+		router.GET("/riders/:id", riders.GetRider)
+		router.POST("/riders/:id/favorites", riders.AddFavoriteLocation)
+		router.POST("/trips", trips.RequestTrip)
+		router.GET("/trips/:id", trips.GetTrip)
+		router.POST("/trips/:id/cancel", trips.CancelTrip)
+		return router
+	}
+	do := func(router *gin.Engine, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	rider := newRouter(asRider("rider-1"))
+
+	t.Run("registration returns a rider token", func(t *testing.T) {
+		w := do(newRouter(asRole("", "")), "POST", "/riders", map[string]interface{}{"firstName": "Elif"})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var resp struct {
+			Rider Rider  `json:"rider"`
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "rider-1", resp.Rider.ID)
+
+		claims, err := tokens.Parse(resp.Token)
+		require.NoError(t, err)
+		assert.Equal(t, token.RoleRider, claims.Role)
+		assert.Equal(t, "rider-1", claims.RiderID)
+	})
+
+	t.Run("signing in returns a rider token", func(t *testing.T) {
+		anonymous := newRouter(asRole("", ""))
+		w := do(anonymous, "POST", "/riders/sign-in", map[string]interface{}{"phone": "+905551234567", "code": "000000"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.NotContains(t, w.Body.String(), "token")
+
+		w = do(anonymous, "POST", "/riders/sign-in", map[string]interface{}{"phone": "+905551234567", "code": "123456"})
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Rider Rider  `json:"rider"`
+			Token string `json:"token"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "rider-1", resp.Rider.ID)
+
+		claims, err := tokens.Parse(resp.Token)
+		require.NoError(t, err)
+		assert.Equal(t, token.RoleRider, claims.Role)
+		assert.Equal(t, "rider-1", claims.RiderID)
+	})
+
+	t.Run("riders only access their own profile", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(rider, "GET", "/riders/rider-1", nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(rider, "GET", "/riders/rider-2", nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(rider, "POST", "/riders/rider-2/favorites", map[string]interface{}{"label": "Home"}).Code)
+	})
+
+	t.Run("fleet admins cannot access riders", func(t *testing.T) {
+		admin := newRouter(asRole(token.RoleFleetAdmin, "fleet-1"))
+		assert.Equal(t, http.StatusForbidden, do(admin, "GET", "/riders/rider-1", nil).Code)
+	})
+
+	t.Run("trips are requested for the rider", func(t *testing.T) {
+		w := do(rider, "POST", "/trips", map[string]interface{}{"pickup": map[string]float64{"lat": 41.04, "lon": 29.01}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "rider-1", upstream.lastBody["riderId"])
+
+		w = do(rider, "POST", "/trips", map[string]interface{}{"riderId": "rider-2"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("riders only see and cancel their own trips", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, do(rider, "GET", "/trips/own", nil).Code)
+		assert.Equal(t, http.StatusForbidden, do(rider, "GET", "/trips/other", nil).Code)
+		assert.Equal(t, http.StatusNotFound, do(rider, "GET", "/trips/missing", nil).Code)
+
+		upstream.requests = nil
+		assert.Equal(t, http.StatusForbidden, do(rider, "POST", "/trips/other/cancel", nil).Code)
+		assert.Equal(t, []string{"GET /api/v1/trips/other"}, upstream.requests, "cancel is not forwarded")
+	})
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/adaptive"
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/hedge"
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SaturationStatsSource reports how busy the HTTP server is
+type SaturationStatsSource interface {
+	Stats() middleware.SaturationStats
+}
+
+// SaturationHandler exposes the concurrency saturation of the gateway and the driver service
+type SaturationHandler struct {
+	source        SaturationStatsSource
+	driverService *service.DriverServiceClient
+	// upstream is nil when driver service calls are not adaptively limited
+	upstream *adaptive.Limiter
+	// hedger is nil when driver service reads are not hedged
+	hedger *hedge.Hedger
+	// canary is nil without a canary driver service
+	canary *canary.Router
+	logger *zap.Logger
+}
+
+// NewSaturationHandler creates a new saturation handler
+func NewSaturationHandler(source SaturationStatsSource, driverService *service.DriverServiceClient, upstream *adaptive.Limiter, hedger *hedge.Hedger, router *canary.Router, logger *zap.Logger) *SaturationHandler {
+	return &SaturationHandler{
+		source:        source,
+		driverService: driverService,
+		upstream:      upstream,
+		hedger:        hedger,
+		canary:        router,
+		logger:        logger,
+	}
+}
+
+// GetSaturation handles GET /admin/saturation
+// @Summary Report gateway saturation
+// @Description Requests in flight against MAX_IN_FLIGHT_REQUESTS, the peak since start, how many requests were rejected with 503 while saturated, and the open connections
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} middleware.SaturationStats "Saturation metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/saturation [get]
+func (h *SaturationHandler) GetSaturation(c *gin.Context) {
+	c.JSON(http.StatusOK, h.source.Stats())
+}
+
+// GetDriverServiceSaturation handles GET /admin/saturation/driver-service
+// @Summary Report driver service saturation
+// @Description Requests the driver service has in flight against its limit, the peak, rejected requests and open connections
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} middleware.SaturationStats "Saturation metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /admin/saturation/driver-service [get]
+func (h *SaturationHandler) GetDriverServiceSaturation(c *gin.Context) {
+	resp, err := h.driverService.GetSaturationStats()
+	if err != nil {
+		h.logger.Error("failed to forward saturation stats request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get saturation stats")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetUpstreamLimit handles GET /admin/saturation/upstream
+// @Summary Report the adaptive driver service limit
+// @Description The current limit on requests in flight to the driver service, how it moved and the upstream health it is based on: successes, failures, responses slower than UPSTREAM_LIMIT_LATENCY_MS and the average latency. Requests over the limit are answered with 503 and counted as rejected. enabled is false when UPSTREAM_LIMIT_ENABLED is off.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} adaptive.Stats "Adaptive limit metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/saturation/upstream [get]
+func (h *SaturationHandler) GetUpstreamLimit(c *gin.Context) {
+	if h.upstream == nil {
+		c.JSON(http.StatusOK, adaptive.Stats{})
+		return
+	}
+	c.JSON(http.StatusOK, h.upstream.Stats())
+}
+
+// GetHedging handles GET /admin/saturation/hedging
+// @Summary Report driver service read hedging
+// @Description How many nearby searches and driver lookups were hedged with a second request, how often the hedge answered first, how many slow reads the HEDGE_BUDGET_PERCENT budget left unhedged, and the current hedge delay. enabled is false when HEDGE_ENABLED is off.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} hedge.Stats "Hedging metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/saturation/hedging [get]
+func (h *SaturationHandler) GetHedging(c *gin.Context) {
+	if h.hedger == nil {
+		c.JSON(http.StatusOK, hedge.Stats{})
+		return
+	}
+	c.JSON(http.StatusOK, h.hedger.Stats())
+}
+
+// GetCanary handles GET /admin/saturation/canary
+// @Summary Report driver service canary routing
+// @Description Requests, failures, error rate and average latency of the primary driver service and the CANARY_URL canary side by side, the share of traffic sent to the canary and whether it is in a fallback: when more than CANARY_MAX_ERROR_PERCENT of its requests fail within a window, all traffic stays on the primary for CANARY_COOLDOWN_SEC. enabled is false without CANARY_URL.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} canary.Stats "Canary metrics"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/saturation/canary [get]
+func (h *SaturationHandler) GetCanary(c *gin.Context) {
+	if h.canary == nil {
+		c.JSON(http.StatusOK, canary.Stats{})
+		return
+	}
+	c.JSON(http.StatusOK, h.canary.Stats())
+}
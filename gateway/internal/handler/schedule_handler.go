@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// mimeCalendar is the media type of iCalendar exports
+const mimeCalendar = "text/calendar"
+
+// ScheduleHandler proxies planned shift requests to the driver service
+type ScheduleHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// CreateShift handles POST /drivers/:id/shifts
+// @Summary Schedule a shift
+// @Description Plan a shift for the driver. The driver is put on shift when it starts and off shift when it ends. Shifts of a driver cannot overlap and last at most 24 hours.
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param request body ScheduleShiftRequest true "Shift times"
+// @Success 201 {object} ScheduledShift "Shift scheduled"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 409 {object} ErrorResponse "Overlaps another shift"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/shifts [post]
+func (h *ScheduleHandler) CreateShift(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).CreateShift(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward create shift request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create shift")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListDriverShifts handles GET /drivers/:id/shifts
+// @Summary Driver shift calendar
+// @Description Shifts of the driver overlapping the range, by start time. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or "Accept: text/calendar" for an iCalendar export.
+// @Tags schedule
+// @Produce json
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path string true "Driver ID" example("507f1f77bcf86cd799439011")
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-12-08")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-15")
+// @Param format query string false "json (default) or ics"
+// @Success 200 {object} ShiftCalendar "Shifts"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /drivers/{id}/shifts [get]
+func (h *ScheduleHandler) ListDriverShifts(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).ListDriverShifts(c.Param("id"), calendarQuery(c, "from", "to", "format"))
+	if err != nil {
+		h.logger.Error("failed to forward list driver shifts request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list shifts")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListShifts handles GET /shifts
+// @Summary Shift calendar
+// @Description Shifts overlapping the range, by start time, for a fleet or a driver. Fleet admins only see their own fleet. The range defaults to the week from now and cannot exceed 92 days. Send format=ics or "Accept: text/calendar" for an iCalendar export.
+// @Tags schedule
+// @Produce json
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param fleetId query string false "Only shifts of this fleet's drivers" example(6570a1f2c3d4e5f6a7b8c9d0)
+// @Param driverId query string false "Only shifts of this driver" example(507f1f77bcf86cd799439011)
+// @Param from query string false "Range start (RFC3339 or YYYY-MM-DD, inclusive)" example("2025-12-08")
+// @Param to query string false "Range end (RFC3339 or YYYY-MM-DD, exclusive)" example("2025-12-15")
+// @Param format query string false "json (default) or ics"
+// @Success 200 {object} ShiftCalendar "Shifts"
+// @Failure 400 {object} ErrorResponse "Invalid range"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Driver not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts [get]
+func (h *ScheduleHandler) ListShifts(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).ListShifts(calendarQuery(c, "fleetId", "driverId", "from", "to", "format"))
+	if err != nil {
+		h.logger.Error("failed to forward list shifts request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list shifts")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// calendarQuery copies the named query parameters to forward
+func calendarQuery(c *gin.Context, names ...string) url.Values {
+	query := url.Values{}
+	for _, name := range names {
+		if value := c.Query(name); value != "" {
+			query.Set(name, value)
+		}
+	}
+	// The Accept header is not forwarded, so an iCalendar request is passed on as the format
+	if query.Get("format") == "" && c.NegotiateFormat(gin.MIMEJSON, mimeCalendar) == mimeCalendar {
+		query.Set("format", "ics")
+	}
+	return query
+}
+
+// GetShift handles GET /shifts/:id
+// @Summary Get a shift
+// @Description Get a planned shift, with when the driver was put on and off shift
+// @Tags schedule
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} ScheduledShift "Shift"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [get]
+func (h *ScheduleHandler) GetShift(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).GetShift(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get shift request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get shift")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// UpdateShift handles PUT /shifts/:id
+// @Summary Reschedule a shift
+// @Description Move a shift. Once the driver was put on shift only its end can move; ended shifts cannot change.
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Param request body ScheduleShiftRequest true "Shift times"
+// @Success 200 {object} ScheduledShift "Shift rescheduled"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found"
+// @Failure 409 {object} ErrorResponse "Overlaps another shift, or the shift started or ended"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [put]
+func (h *ScheduleHandler) UpdateShift(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).UpdateShift(c.Param("id"), body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward update shift request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to update shift")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// DeleteShift handles DELETE /shifts/:id
+// @Summary Cancel a shift
+// @Description Delete a planned shift. A running shift cannot be deleted; move its end instead.
+// @Tags schedule
+// @Security BearerAuth
+// @Param id path string true "Shift ID" example("6574a1f2c3d4e5f6a7b8c9d0")
+// @Success 204 "Shift deleted"
+// @Failure 403 {object} ErrorResponse "Driver belongs to another fleet"
+// @Failure 404 {object} ErrorResponse "Shift not found"
+// @Failure 409 {object} ErrorResponse "Shift is running"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /shifts/{id} [delete]
+func (h *ScheduleHandler) DeleteShift(c *gin.Context) {
+	resp, err := forCaller(c, h.driverService).DeleteShift(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward delete shift request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete shift")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
@@ -0,0 +1,50 @@
+package handler
+
+// ResponseModels are the models successful driver service responses are
+// checked against when response validation is on, by route as registered on
+// the router. Routes whose responses the gateway rewrites or streams are left out.
+var ResponseModels = map[string]interface{}{
+	"POST /drivers":                  Driver{},
+	"GET /drivers":                   ListDriversResponse{},
+	"GET /drivers/:id":               Driver{},
+	"PUT /drivers/:id":               Driver{},
+	"PUT /drivers/:id/availability":  Driver{},
+	"PUT /drivers/:id/suspension":    Driver{},
+	"PUT /drivers/:id/location":      Driver{},
+	"PUT /drivers/:id/fleet":         Driver{},
+	"POST /drivers/:id/verify-phone": Driver{},
+	"GET /drivers/:id/kyc":           KYCCheck{},
+	"POST /drivers/:id/kyc":          KYCCheck{},
+	"GET /drivers/:id/stats":         DriverStats{},
+	"GET /drivers/:id/earnings":      EarningsSummary{},
+	"POST /drivers/:id/shifts":       ScheduledShift{},
+	"POST /drivers/:id/incidents":    Incident{},
+	"GET /drivers/:id/incidents":     ListIncidentsResponse{},
+	"GET /drivers/stats":             OnlineStats{},
+	"GET /drivers/changes":           DriverChangesResponse{},
+	"GET /drivers/autocomplete":      AutocompleteResponse{},
+	"GET /drivers/nearby":            []NearbyDriverResponse{},
+	"POST /drivers/nearby/route":     []RouteDriverResponse{},
+	"POST /drivers/bulk-update":      BulkUpdate{},
+	"POST /trips":                    Trip{},
+	"POST /trips/estimate":           FareEstimate{},
+	"GET /taxi-types":                TaxiTypeCatalog{},
+	"GET /trips/:id":                 Trip{},
+	"POST /trips/:id/accept":         Trip{},
+	"POST /trips/:id/decline":        Trip{},
+	"POST /trips/:id/complete":       Trip{},
+	"POST /trips/:id/cancel":         Trip{},
+	"GET /riders/:id":                Rider{},
+	"PUT /riders/:id":                Rider{},
+	"POST /fleets":                   Fleet{},
+	"GET /fleets":                    []Fleet{},
+	"GET /fleets/:id":                Fleet{},
+	"GET /fleets/:id/drivers":        ListDriversResponse{},
+	"GET /shifts/:id":                ScheduledShift{},
+	"PUT /shifts/:id":                ScheduledShift{},
+	"GET /incidents":                 ListIncidentsResponse{},
+	"GET /incidents/:id":             Incident{},
+	"PUT /incidents/:id/status":      Incident{},
+	"GET /exports/:id":               Job{},
+	"POST /exports":                  Job{},
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SecurityEventSource reports suspicious activity seen by the gateway
+type SecurityEventSource interface {
+	Events() []middleware.SecurityEvent
+}
+
+// SecurityEventsResponse lists recorded security events
+type SecurityEventsResponse struct {
+	Events []middleware.SecurityEvent `json:"events"`
+}
+
+// SecurityHandler exposes security events to operators
+type SecurityHandler struct {
+	source SecurityEventSource
+	logger *zap.Logger
+}
+
+// NewSecurityHandler creates a new security handler
+func NewSecurityHandler(source SecurityEventSource, logger *zap.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		source: source,
+		logger: logger,
+	}
+}
+
+// GetSecurityEvents handles GET /admin/security-events
+// @Summary List security events
+// @Description Devices and IPs that went over their daily registration caps (REGISTRATION_MAX_PER_DEVICE, REGISTRATION_MAX_PER_IP), newest first. One event is kept per device or IP and day, counting the registrations refused. Events are held in memory on this instance only.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} SecurityEventsResponse "Security events"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/security-events [get]
+func (h *SecurityHandler) GetSecurityEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, SecurityEventsResponse{Events: h.source.Events()})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/slo"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SLOHandler reports the service level objectives of critical routes
+type SLOHandler struct {
+	// tracker is nil when SLO_ENABLED is off
+	tracker *slo.Tracker
+	logger  *zap.Logger
+}
+
+// NewSLOHandler creates a new service level objective handler
+func NewSLOHandler(tracker *slo.Tracker, logger *zap.Logger) *SLOHandler {
+	return &SLOHandler{
+		tracker: tracker,
+		logger:  logger,
+	}
+}
+
+// GetSLO handles GET /admin/slo
+// @Summary Report service level objectives
+// @Description Availability (no 5xx) and latency (successful requests within the threshold) of the routes in SLO_OBJECTIVES over the rolling SLO_WINDOWS: the share of good requests, the error budget burn rate (1 spends the budget exactly over the window) and the budget left over the longest window. Counts are kept in memory on this instance and start over on restart. Send format=prometheus or "Accept: text/plain" for the same series in the Prometheus text format, also served on /metrics/slo on the admin port.
+// @Tags admin
+// @Produce json
+// @Produce plain
+// @Param X-Admin-Token header string true "Admin token"
+// @Param format query string false "json (default) or prometheus"
+// @Success 200 {object} slo.Report "Service level objectives"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 404 {object} ErrorResponse "SLO tracking disabled"
+// @Router /admin/slo [get]
+func (h *SLOHandler) GetSLO(c *gin.Context) {
+	if h.tracker == nil {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "SLO tracking is disabled")
+		return
+	}
+	if c.Query("format") == "prometheus" || c.NegotiateFormat(gin.MIMEJSON, gin.MIMEPlain) == gin.MIMEPlain {
+		h.GetMetrics(c)
+		return
+	}
+	c.JSON(http.StatusOK, h.tracker.Report())
+}
+
+// GetMetrics serves the indicator series in the Prometheus text format
+func (h *SLOHandler) GetMetrics(c *gin.Context) {
+	if h.tracker == nil {
+		respondError(c, http.StatusNotFound, "NOT_FOUND", "SLO tracking is disabled")
+		return
+	}
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", slo.ContentType)
+	if err := h.tracker.WritePrometheus(c.Writer); err != nil {
+		h.logger.Warn("failed to write SLO metrics", zap.Error(err))
+	}
+}
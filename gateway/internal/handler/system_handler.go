@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/adaptive"
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/coalesce"
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/hedge"
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// healthCheckTimeout bounds the driver service health check of GET /admin/system
+const healthCheckTimeout = 2 * time.Second
+
+// SystemStatus is the consolidated view of the gateway for on-call
+type SystemStatus struct {
+	// Status is "ok", or "degraded" while the driver service fails its health check
+	Status        string                 `json:"status" example:"ok"`
+	Build         BuildInfo              `json:"build"`
+	Config        map[string]interface{} `json:"config"`
+	DriverService UpstreamHealth         `json:"driverService"`
+	// Saturation is the gateway's own requests in flight against MAX_IN_FLIGHT_REQUESTS
+	Saturation middleware.SaturationStats `json:"saturation"`
+	// UpstreamLimit is the adaptive limit that sheds load from a slow or failing driver service
+	UpstreamLimit adaptive.Stats            `json:"upstreamLimit"`
+	Hedging       hedge.Stats               `json:"hedging"`
+	Canary        canary.Stats              `json:"canary"`
+	RateLimit     middleware.RateLimitStats `json:"rateLimit"`
+	Caches        CacheStats                `json:"caches"`
+}
+
+// BuildInfo identifies the running gateway binary
+type BuildInfo struct {
+	Version  string `json:"version" example:"(devel)"`
+	Revision string `json:"revision,omitempty" example:"a56ad58c1f0e"`
+	// Modified is set when the binary was built from a tree with uncommitted changes
+	Modified  bool      `json:"modified"`
+	GoVersion string    `json:"goVersion" example:"go1.21.5"`
+	StartedAt time.Time `json:"startedAt" example:"2025-12-06T09:00:00Z"`
+	UptimeSec int64     `json:"uptimeSec" example:"86400"`
+}
+
+// UpstreamHealth is the driver service health as the gateway sees it
+type UpstreamHealth struct {
+	// Status is "up" or "down"
+	Status string `json:"status" example:"up"`
+	// Error tells why the health check failed
+	Error string `json:"error,omitempty" example:"driver service health check returned 503"`
+	// HealthCheckMs is how long the health check made for this report took
+	HealthCheckMs float64 `json:"healthCheckMs" example:"3.2"`
+	// Latency covers the recent requests the gateway forwarded
+	Latency service.LatencyStats `json:"latency"`
+}
+
+// CacheStats reports the hit rates of the gateway caches
+type CacheStats struct {
+	// Nearby is the nearby search micro-cache; absent when NEARBY_COALESCE is off
+	Nearby       *coalesce.Stats             `json:"nearby,omitempty"`
+	DeviceTokens middleware.DeviceCacheStats `json:"deviceTokens"`
+}
+
+// SystemHandler reports the state of the whole gateway in one response
+type SystemHandler struct {
+	cfg           *config.Config
+	driverService *service.DriverServiceClient
+	saturation    SaturationStatsSource
+	rateLimiter   *middleware.RateLimiter
+	// upstream, hedger, canary and nearby are nil when the feature is off
+	upstream  *adaptive.Limiter
+	hedger    *hedge.Hedger
+	canary    *canary.Router
+	nearby    *coalesce.Group
+	devices   *middleware.DeviceAuthenticator
+	startedAt time.Time
+	logger    *zap.Logger
+}
+
+// NewSystemHandler creates a new system handler; the gateway's uptime is counted from now
+func NewSystemHandler(cfg *config.Config, driverService *service.DriverServiceClient, saturation SaturationStatsSource, rateLimiter *middleware.RateLimiter, upstream *adaptive.Limiter, hedger *hedge.Hedger, router *canary.Router, nearby *coalesce.Group, devices *middleware.DeviceAuthenticator, logger *zap.Logger) *SystemHandler {
+	return &SystemHandler{
+		cfg:           cfg,
+		driverService: driverService,
+		saturation:    saturation,
+		rateLimiter:   rateLimiter,
+		upstream:      upstream,
+		hedger:        hedger,
+		canary:        router,
+		nearby:        nearby,
+		devices:       devices,
+		startedAt:     time.Now(),
+		logger:        logger,
+	}
+}
+
+// GetSystem handles GET /admin/system
+// @Summary Report the state of the gateway
+// @Description One view for on-call: build and uptime, the configuration with secrets redacted, driver service health with the latency percentiles of recent requests, saturation, the adaptive upstream limit (the gateway sheds load with it instead of a circuit breaker), hedging, canary routing, rate limiting and cache hit rates. status is degraded while the driver service fails its health check.
+// @Tags admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} SystemStatus "Gateway state"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Router /admin/system [get]
+func (h *SystemHandler) GetSystem(c *gin.Context) {
+	status := SystemStatus{
+		Status:        "ok",
+		Build:         h.buildInfo(),
+		Config:        h.cfg.Snapshot(),
+		DriverService: h.driverServiceHealth(c.Request.Context()),
+		Saturation:    h.saturation.Stats(),
+		RateLimit:     h.rateLimiter.Stats(),
+		Caches:        CacheStats{DeviceTokens: h.devices.CacheStats()},
+	}
+	if status.DriverService.Status != "up" {
+		status.Status = "degraded"
+	}
+	if h.upstream != nil {
+		status.UpstreamLimit = h.upstream.Stats()
+	}
+	if h.hedger != nil {
+		status.Hedging = h.hedger.Stats()
+	}
+	if h.canary != nil {
+		status.Canary = h.canary.Stats()
+	}
+	if h.nearby != nil {
+		nearby := h.nearby.Stats()
+		status.Caches.Nearby = &nearby
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// buildInfo reads the version and VCS revision the Go toolchain stamped into the binary
+func (h *SystemHandler) buildInfo() BuildInfo {
+	info := BuildInfo{
+		GoVersion: runtime.Version(),
+		StartedAt: h.startedAt.UTC(),
+		UptimeSec: int64(time.Since(h.startedAt).Seconds()),
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Version = build.Main.Version
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// driverServiceHealth checks the driver service's health within healthCheckTimeout
+func (h *SystemHandler) driverServiceHealth(ctx context.Context) UpstreamHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	health := UpstreamHealth{Status: "up"}
+	elapsed, err := h.driverService.CheckHealth(ctx)
+	if err != nil {
+		h.logger.Warn("driver service health check failed", zap.Error(err))
+		health.Status = "down"
+		health.Error = err.Error()
+	}
+	health.HealthCheckMs = math.Round(float64(elapsed)/float64(time.Millisecond)*10) / 10
+	health.Latency = h.driverService.LatencyStats()
+	return health
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/coalesce"
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/middleware"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSystemHandler_GetSystem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	healthy := true
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		JWT:   config.JWTConfig{Secret: "jwt-secret", Expiration: time.Hour},
+		Admin: config.AdminConfig{Token: "admin-token"},
+	}
+	client := service.NewDriverServiceClient(upstream.URL, zap.NewNop())
+	h := NewSystemHandler(cfg, client,
+		middleware.NewConcurrencyLimiter(100, time.Second, zap.NewNop()),
+		middleware.NewRateLimiter(cfg, nil, zap.NewNop()),
+		nil, nil, nil, coalesce.New(time.Second),
+		middleware.NewDeviceAuthenticator(nil, time.Minute, zap.NewNop()),
+		zap.NewNop())
+
+	router := gin.New()
+	router.GET("/admin/system", h.GetSystem)
+	get := func() (string, SystemStatus) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/admin/system", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var status SystemStatus
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		return w.Body.String(), status
+	}
+
+	body, status := get()
+	assert.Equal(t, "ok", status.Status)
+	assert.Equal(t, "up", status.DriverService.Status)
+	assert.Equal(t, 1, status.DriverService.Latency.Samples)
+	assert.NotEmpty(t, status.Build.GoVersion)
+	assert.NotNil(t, status.Caches.Nearby)
+	assert.Equal(t, "1h0m0s", status.Config["jwt"].(map[string]interface{})["expiration"])
+	assert.NotContains(t, body, "jwt-secret")
+	assert.NotContains(t, body, "admin-token")
+
+	healthy = false
+	_, status = get()
+	assert.Equal(t, "degraded", status.Status)
+	assert.Equal(t, "down", status.DriverService.Status)
+	assert.Equal(t, "driver service health check returned 503", status.DriverService.Error)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Token exchange identifiers (RFC 8693)
+const (
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	tokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+	tokenTypeJWT           = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// TokenExchangeRequest exchanges a user's access token for a token scoped to a service
+type TokenExchangeRequest struct {
+	GrantType string `json:"grant_type" form:"grant_type" binding:"required" example:"urn:ietf:params:oauth:grant-type:token-exchange"`
+	// SubjectToken is the access token of the user the service acts for
+	SubjectToken     string `json:"subject_token" form:"subject_token" binding:"required"`
+	SubjectTokenType string `json:"subject_token_type" form:"subject_token_type" binding:"required" example:"urn:ietf:params:oauth:token-type:access_token"`
+	// Audience is the service the token is for; driver-service when empty
+	Audience string `json:"audience" form:"audience" example:"driver-service"`
+	// Scope is a space separated list; drivers:read when empty
+	Scope              string `json:"scope" form:"scope" example:"drivers:read drivers:write"`
+	RequestedTokenType string `json:"requested_token_type" form:"requested_token_type" example:"urn:ietf:params:oauth:token-type:access_token"`
+}
+
+// TokenExchangeResponse is an exchanged token
+type TokenExchangeResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type" example:"urn:ietf:params:oauth:token-type:access_token"`
+	TokenType       string `json:"token_type" example:"Bearer"`
+	ExpiresIn       int64  `json:"expires_in" example:"300"`
+	Scope           string `json:"scope" example:"drivers:read"`
+}
+
+// EnableTokenExchange lets internal services exchange access tokens for
+// tokens scoped to the driver service
+func (h *AuthHandler) EnableTokenExchange(exchanger *token.Exchanger) {
+	h.exchanger = exchanger
+}
+
+// ExchangeToken handles POST /auth/token/exchange
+// @Summary Exchange a token
+// @Description Exchange a user's access token for a short-lived token an internal service calls the driver service with directly (RFC 8693). The token acts for the same user, with the same role and fleet, limited to the requested scopes: drivers:read allows GET routes, drivers:write the others. It expires after TOKEN_EXCHANGE_TTL_SEC or with the subject token, whichever is first, and is signed with TOKEN_EXCHANGE_SECRET, which the driver service validates it with; the gateway does not accept it.
+// @Tags auth
+// @Accept json,x-www-form-urlencoded
+// @Produce json
+// @Param request body TokenExchangeRequest true "Token to exchange"
+// @Success 200 {object} TokenExchangeResponse "Exchanged token"
+// @Failure 400 {object} ErrorResponse "Invalid request, subject token, audience or scope" example({"error":{"code":"INVALID_GRANT","message":"invalid or expired subject token"}})
+// @Failure 404 {object} ErrorResponse "Token exchange is not configured"
+// @Router /auth/token/exchange [post]
+func (h *AuthHandler) ExchangeToken(c *gin.Context) {
+	if h.exchanger == nil {
+		h.respondError(c, http.StatusNotFound, "NOT_FOUND", "token exchange is not configured")
+		return
+	}
+	var req TokenExchangeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if req.GrantType != grantTypeTokenExchange {
+		h.respondError(c, http.StatusBadRequest, "UNSUPPORTED_GRANT_TYPE", "grant_type must be "+grantTypeTokenExchange)
+		return
+	}
+	if req.SubjectTokenType != tokenTypeAccessToken && req.SubjectTokenType != tokenTypeJWT {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "subject_token_type must be an access token or a JWT")
+		return
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != tokenTypeAccessToken {
+		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "only access tokens can be requested")
+		return
+	}
+
+	// The subject token only proves who the service acts for, so it is not
+	// counted against the replay window
+	subject, err := h.tokens.Parse(req.SubjectToken)
+	if err != nil {
+		h.logger.Debug("rejected token exchange", zap.Error(err))
+		h.respondError(c, http.StatusBadRequest, "INVALID_GRANT", "invalid or expired subject token")
+		return
+	}
+
+	audience := req.Audience
+	if audience == "" {
+		audience = token.AudienceDriverService
+	}
+	exchanged, err := h.exchanger.Exchange(subject, audience, strings.Fields(req.Scope))
+	switch {
+	case errors.Is(err, token.ErrInvalidTarget):
+		h.respondError(c, http.StatusBadRequest, "INVALID_TARGET", "audience must be "+token.AudienceDriverService)
+		return
+	case errors.Is(err, token.ErrInvalidScope):
+		h.respondError(c, http.StatusBadRequest, "INVALID_SCOPE", "scope must be drivers:read or drivers:write")
+		return
+	case err != nil:
+		h.logger.Error("failed to exchange token", zap.Error(err))
+		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to exchange token")
+		return
+	}
+
+	h.logger.Info("exchanged token",
+		zap.String("username", subject.Username),
+		zap.String("audience", audience),
+		zap.Strings("scopes", exchanged.Scopes),
+		zap.String("api_key", c.GetString("api_key")),
+	)
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, TokenExchangeResponse{
+		AccessToken:     exchanged.Token,
+		IssuedTokenType: tokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(exchanged.ExpiresIn.Seconds()),
+		Scope:           strings.Join(exchanged.Scopes, " "),
+	})
+}
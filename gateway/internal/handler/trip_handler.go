@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TripHandler handles HTTP requests for trips in the gateway
+type TripHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewTripHandler creates a new trip handler
+func NewTripHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *TripHandler {
+	return &TripHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// RequestTrip handles POST /trips
+// @Summary Request a trip
+// @Description Create a ride request; the driver service offers it to a driver chosen by the configured matching strategy.
+// @Description Riders request trips for themselves: riderId is set from their token.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param trip body CreateTripRequest true "Ride request"
+// @Success 201 {object} Trip "Trip created"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Trip for another rider"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips [post]
+func (h *TripHandler) RequestTrip(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if riderID, scoped := scopedRider(c); scoped {
+		value, _ := body.get("riderId")
+		if requested, ok := value.(string); ok && requested != "" && requested != riderID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "riders can only request trips for themselves")
+			return
+		}
+		body.set("riderId", riderID)
+	}
+
+	resp, err := forCaller(c, h.driverService).RequestTrip(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward trip request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create trip")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// EstimateFare handles POST /trips/estimate
+// @Summary Estimate a fare
+// @Description Quote the fare from pickup to dropoff without requesting a trip. Distances are road distances when the driver service routes with OSRM.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param route body EstimateFareRequest true "Pickup and dropoff"
+// @Success 200 {object} FareEstimate "Fare estimate"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/estimate [post]
+func (h *TripHandler) EstimateFare(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).EstimateFare(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward fare estimate request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to estimate fare")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetTrip handles GET /trips/:id
+// @Summary Get trip
+// @Description Get a trip and its current offer by ID. Riders can only read their own trips.
+// @Tags trips
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Success 200 {object} Trip "Trip found"
+// @Failure 403 {object} ErrorResponse "Another rider's trip"
+// @Failure 404 {object} ErrorResponse "Trip not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/{id} [get]
+func (h *TripHandler) GetTrip(c *gin.Context) {
+	if !h.authorizeTrip(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetTrip(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get trip request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get trip")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// AcceptOffer handles POST /trips/:id/accept
+// @Summary Accept trip offer
+// @Description Accept the trip offered to the driver. Not available to riders.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body TripOfferRequest true "Driver answering the offer"
+// @Success 200 {object} Trip "Trip accepted"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 404 {object} ErrorResponse "Trip not found"
+// @Failure 403 {object} ErrorResponse "Riders cannot answer offers, nor fleet admins for another fleet's driver"
+// @Failure 409 {object} ErrorResponse "No active offer"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/{id}/accept [post]
+func (h *TripHandler) AcceptOffer(c *gin.Context) {
+	h.forwardAction(c, "accept", true)
+}
+
+// DeclineOffer handles POST /trips/:id/decline
+// @Summary Decline trip offer
+// @Description Decline the trip offered to the driver; the trip is re-offered to the next driver. Not available to riders.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body TripOfferRequest true "Driver answering the offer"
+// @Success 200 {object} Trip "Trip re-offered"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 404 {object} ErrorResponse "Trip not found"
+// @Failure 403 {object} ErrorResponse "Riders cannot answer offers, nor fleet admins for another fleet's driver"
+// @Failure 409 {object} ErrorResponse "No active offer"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/{id}/decline [post]
+func (h *TripHandler) DeclineOffer(c *gin.Context) {
+	h.forwardAction(c, "decline", true)
+}
+
+// CompleteTrip handles POST /trips/:id/complete
+// @Summary Complete trip
+// @Description Finish an accepted trip, optionally rating the driver from 1 to 5. Not available to riders.
+// @Tags trips
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Param request body CompleteTripRequest false "Trip summary"
+// @Success 200 {object} Trip "Trip completed"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 404 {object} ErrorResponse "Trip not found"
+// @Failure 403 {object} ErrorResponse "Riders cannot complete trips, nor fleet admins those of another fleet's driver"
+// @Failure 409 {object} ErrorResponse "Trip not accepted"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/{id}/complete [post]
+func (h *TripHandler) CompleteTrip(c *gin.Context) {
+	h.forwardAction(c, "complete", false)
+}
+
+// CancelTrip handles POST /trips/:id/cancel
+// @Summary Cancel trip
+// @Description Cancel a trip that has not finished. Riders can only cancel their own trips.
+// @Tags trips
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Trip ID" example("6571f1f77bcf86cd79943901")
+// @Success 200 {object} Trip "Trip cancelled"
+// @Failure 403 {object} ErrorResponse "Another rider's trip"
+// @Failure 404 {object} ErrorResponse "Trip not found"
+// @Failure 409 {object} ErrorResponse "Trip already finished"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /trips/{id}/cancel [post]
+func (h *TripHandler) CancelTrip(c *gin.Context) {
+	if !h.authorizeTrip(c, c.Param("id")) {
+		return
+	}
+	h.forwardAction(c, "cancel", false)
+}
+
+// forwardAction forwards a trip state change, binding the JSON body when one is required or present
+func (h *TripHandler) forwardAction(c *gin.Context, action string, bodyRequired bool) {
+	var payload interface{}
+	if bodyRequired || c.Request.ContentLength > 0 {
+		body, err := bindRequestBody(c)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+			return
+		}
+		payload = body.payload()
+	}
+
+	resp, err := forCaller(c, h.driverService).TripAction(c.Param("id"), action, payload)
+	if err != nil {
+		h.logger.Error("failed to forward trip action", zap.Error(err), zap.String("action", action))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to "+action+" trip")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// authorizeTrip lets riders through only for their own trips; other callers are not checked
+func (h *TripHandler) authorizeTrip(c *gin.Context, id string) bool {
+	riderID, scoped := scopedRider(c)
+	if !scoped {
+		return true
+	}
+
+	resp, err := forCaller(c, h.driverService).GetTrip(id)
+	if err != nil {
+		h.logger.Error("failed to look up trip rider", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up trip")
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		forwardResponse(c, resp, h.logger)
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("failed to read trip", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up trip")
+		return false
+	}
+
+	var trip struct {
+		RiderID string `json:"riderId"`
+	}
+	if err := json.Unmarshal(body, &trip); err != nil {
+		h.logger.Error("failed to decode trip", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up trip")
+		return false
+	}
+	if trip.RiderID != riderID {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "trip does not belong to you")
+		return false
+	}
+	return true
+}
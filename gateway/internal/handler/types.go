@@ -1,6 +1,9 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+)
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
@@ -8,12 +11,11 @@ type ErrorResponse struct {
 		Code    string `json:"code"`
 		Message string `json:"message"`
 	} `json:"error"`
+	// Upstream is set on errors returned or caused by the driver service
+	Upstream *UpstreamInfo `json:"upstream,omitempty"`
 }
 
-// respondError is a helper function to send error responses
+// respondError sends an error response in the format negotiated with the client
 func respondError(c *gin.Context, status int, code, message string) {
-	var errResp ErrorResponse
-	errResp.Error.Code = code
-	errResp.Error.Message = message
-	c.JSON(status, errResp)
+	problem.Respond(c, status, code, message)
 }
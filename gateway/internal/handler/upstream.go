@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/bitaksi/gateway/internal/redact"
+	"github.com/bitaksi/gateway/internal/schema"
+	"github.com/gin-gonic/gin"
+)
+
+// upstreamService names the driver service in upstream error tags
+const upstreamService = "driver-service"
+
+// UpstreamInfo tags an error that came from the driver service
+type UpstreamInfo struct {
+	Service string `json:"service" example:"driver-service"`
+	Status  int    `json:"status" example:"500"`
+	// Code and Message are the driver service's own, set when its error was translated
+	Code    string `json:"code,omitempty" example:"INTERNAL_ERROR"`
+	Message string `json:"message,omitempty" example:"failed to create driver"`
+}
+
+// gatewayErrors are what driver service 5xx responses are translated into
+var gatewayErrors = map[int]struct {
+	status        int
+	code, message string
+}{
+	http.StatusServiceUnavailable: {http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "driver service is unavailable, retry later"},
+	http.StatusGatewayTimeout:     {http.StatusGatewayTimeout, "UPSTREAM_TIMEOUT", "driver service timed out"},
+}
+
+// skippedHeaders are upstream headers never copied to the client: the gateway
+// sets its own CORS headers, and the content headers describe the upstream body,
+// which may be rewritten
+var skippedHeaders = map[string]bool{
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Content-Encoding":  true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+	"Keep-Alive":        true,
+}
+
+// upstreamErrors returns the error rendering set by the UpstreamErrors middleware
+func upstreamErrors(c *gin.Context) config.UpstreamErrorsConfig {
+	if cfg, ok := c.Get("upstreamErrors"); ok {
+		return cfg.(config.UpstreamErrorsConfig)
+	}
+	return config.UpstreamErrorsConfig{Translate: true, Tag: true}
+}
+
+// responseValidator returns the validator set by the ResponseValidation
+// middleware, or nil when responses are not checked
+func responseValidator(c *gin.Context) *schema.Validator {
+	if v, ok := c.Get("responseValidator"); ok {
+		return v.(*schema.Validator)
+	}
+	return nil
+}
+
+// responseRedactor returns the redactor set by the ResponseRedaction
+// middleware, or nil when responses are returned in full
+func responseRedactor(c *gin.Context) *redact.Redactor {
+	if r, ok := c.Get("responseRedactor"); ok {
+		return r.(*redact.Redactor)
+	}
+	return nil
+}
+
+// redactionRole is the role responses are redacted for: the token's role,
+// redact.Anonymous without a token, and none for tokens without a role
+func redactionRole(c *gin.Context) string {
+	if role := c.GetString("role"); role != "" {
+		return role
+	}
+	if c.GetString("username") != "" {
+		return ""
+	}
+	return redact.Anonymous
+}
+
+// writeResponse writes a buffered upstream response to the client. Successful
+// responses are copied as they are, with the fields the caller's role may not
+// see redacted, unless response validation finds they do not match the
+// route's model: those become 502 BAD_UPSTREAM. 4xx errors keep their status and body;
+// 5xx errors are translated into gateway errors. Errors are tagged with an
+// "upstream" member and re-rendered as problem details when the client asked.
+func writeResponse(c *gin.Context, status int, header http.Header, body []byte) {
+	if v := responseValidator(c); v != nil && status < 300 {
+		if err := v.Check(c.Request.Method+" "+c.FullPath(), status, body); err != nil {
+			// The handler may have marked the response as reusable before it was checked
+			c.Writer.Header().Del("Cache-Control")
+			info := UpstreamInfo{Service: upstreamService, Status: status}
+			respondUpstreamError(c, upstreamErrors(c), http.StatusBadGateway, "BAD_UPSTREAM", "driver service returned a malformed response", info)
+			return
+		}
+	}
+
+	route := c.Request.Method + " " + c.FullPath()
+	redactor := responseRedactor(c)
+	if redactor != nil && status < 300 {
+		body = redactor.Redact(redactionRole(c), route, body)
+	}
+
+	copyHeaders(c, header)
+	if redactor != nil && redactor.Covers(route) {
+		// Keep shared caches from serving one role's response to another
+		addVary(c, "Authorization")
+	}
+	if status < 400 {
+		c.Data(status, header.Get("Content-Type"), body)
+		return
+	}
+
+	cfg := upstreamErrors(c)
+	if status >= 500 && !cfg.Translate {
+		c.Data(status, header.Get("Content-Type"), body)
+		return
+	}
+
+	var upstream struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	structured := json.Unmarshal(body, &upstream) == nil && upstream.Error.Code != ""
+	info := UpstreamInfo{Service: upstreamService, Status: status}
+
+	if status >= 500 {
+		translated, ok := gatewayErrors[status]
+		if !ok {
+			translated.status, translated.code, translated.message = http.StatusBadGateway, "UPSTREAM_ERROR", "driver service failed to handle the request"
+		}
+		if structured {
+			info.Code, info.Message = upstream.Error.Code, upstream.Error.Message
+		}
+		respondUpstreamError(c, cfg, translated.status, translated.code, translated.message, info)
+		return
+	}
+
+	if !structured {
+		// e.g. the router's plain-text 404 or 405
+		respondUpstreamError(c, cfg, status, statusCode(status), http.StatusText(status), info)
+		return
+	}
+	if problem.Wanted(c.Request) {
+		respondUpstreamError(c, cfg, status, upstream.Error.Code, upstream.Error.Message, info)
+		return
+	}
+
+	// Keep members of the error body the gateway does not know about
+	if cfg.Tag {
+		var members map[string]json.RawMessage
+		if json.Unmarshal(body, &members) == nil {
+			members["upstream"], _ = json.Marshal(info)
+			if tagged, err := json.Marshal(members); err == nil {
+				body = tagged
+			}
+		}
+	}
+	c.Data(status, "application/json; charset=utf-8", body)
+}
+
+// respondUpstreamError writes an error, tagged with info when tagging is on
+func respondUpstreamError(c *gin.Context, cfg config.UpstreamErrorsConfig, status int, code, message string, info UpstreamInfo) {
+	if !cfg.Tag {
+		respondError(c, status, code, message)
+		return
+	}
+	problem.RespondWith(c, status, code, message, map[string]interface{}{"upstream": info})
+}
+
+// copyHeaders copies upstream response headers the client should see
+func copyHeaders(c *gin.Context, header http.Header) {
+	for key, values := range header {
+		if skippedHeaders[key] || strings.HasPrefix(key, "Access-Control-") {
+			continue
+		}
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
+}
+
+// addVary adds request headers to the response's Vary header, skipping those already listed
+func addVary(c *gin.Context, names ...string) {
+	header := c.Writer.Header()
+	listed := make(map[string]bool)
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			listed[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	for _, name := range names {
+		if !listed[http.CanonicalHeaderKey(name)] {
+			header.Add("Vary", name)
+			listed[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// statusCode derives an error code from a status, e.g. 405 becomes METHOD_NOT_ALLOWED
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "UPSTREAM_ERROR"
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler proxies webhook subscription requests to the driver service.
+// Fleet admins only see and manage the subscriptions of their own fleet.
+type WebhookHandler struct {
+	driverService *service.DriverServiceClient
+	logger        *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(driverService *service.DriverServiceClient, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// CreateWebhook handles POST /webhooks
+// @Summary Register a webhook endpoint
+// @Description Subscribe an endpoint to driver events (driver.created, driver.updated, driver.suspended, driver.license_expired). Deliveries are signed with HMAC-SHA256 in the X-Bitaksi-Signature header and retried with exponential backoff. The secret is only returned in this response. The url must use https outside debug mode and must not point to a loopback, private or link-local address. Fleet admins always subscribe to their own fleet.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body CreateWebhookRequest true "Subscription"
+// @Success 201 {object} WebhookSubscription "Subscription created"
+// @Failure 400 {object} ErrorResponse "Validation error"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	body, err := bindRequestBody(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "VALIDATION_ERROR", err.Error())
+		return
+	}
+	if scope, scoped := scopedFleet(c); scoped {
+		value, _ := body.get("fleetId")
+		if requested, ok := value.(string); ok && requested != "" && requested != scope {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "fleet admins can only subscribe to their own fleet")
+			return
+		}
+		body.set("fleetId", scope)
+	}
+
+	resp, err := forCaller(c, h.driverService).CreateWebhook(body.payload())
+	if err != nil {
+		h.logger.Error("failed to forward create webhook request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to create webhook subscription")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListWebhooks handles GET /webhooks
+// @Summary List webhook subscriptions
+// @Description List webhook subscriptions without their secrets. Fleet admins only see their own fleet's subscriptions.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param fleetId query string false "Only list subscriptions of this fleet"
+// @Success 200 {array} WebhookSubscription "Subscriptions"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	fleetID := c.Query("fleetId")
+	if scope, scoped := scopedFleet(c); scoped {
+		fleetID = scope
+	}
+
+	resp, err := forCaller(c, h.driverService).ListWebhooks(fleetID)
+	if err != nil {
+		h.logger.Error("failed to forward list webhooks request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list webhook subscriptions")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// GetWebhook handles GET /webhooks/:id
+// @Summary Get a webhook subscription
+// @Description Get a webhook subscription by ID without its secret
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Success 200 {object} WebhookSubscription "Subscription"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	if !h.authorizeWebhook(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).GetWebhook(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward get webhook request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to get webhook subscription")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+// @Summary Delete a webhook subscription
+// @Description Remove a subscription; its pending deliveries are not retried
+// @Tags webhooks
+// @Security BearerAuth
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Success 204 "Subscription deleted"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	if !h.authorizeWebhook(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).DeleteWebhook(c.Param("id"))
+	if err != nil {
+		h.logger.Error("failed to forward delete webhook request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to delete webhook subscription")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+// @Summary List webhook deliveries
+// @Description Delivery log of a subscription, newest first, with attempts, last response status and next retry. Deliveries are kept for 30 days.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Param limit query int false "Maximum deliveries to return (max 500)" default(50)
+// @Success 200 {array} WebhookDelivery "Deliveries"
+// @Failure 400 {object} ErrorResponse "Invalid limit"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 404 {object} ErrorResponse "Subscription not found"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	if !h.authorizeWebhook(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).ListWebhookDeliveries(c.Param("id"), c.Query("limit"))
+	if err != nil {
+		h.logger.Error("failed to forward list webhook deliveries request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list webhook deliveries")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// RetryDelivery handles POST /webhooks/:id/deliveries/:deliveryId/retry
+// @Summary Retry a webhook delivery
+// @Description Send a delivery again as soon as possible. Failed deliveries get a fresh set of attempts.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Subscription ID" example("6572a1f2c3d4e5f6a7b8c9d0")
+// @Param deliveryId path string true "Delivery ID" example("6572b1f2c3d4e5f6a7b8c9d0")
+// @Success 202 {object} WebhookDelivery "Delivery rescheduled"
+// @Failure 403 {object} ErrorResponse "Another fleet"
+// @Failure 404 {object} ErrorResponse "Delivery not found"
+// @Failure 409 {object} ErrorResponse "Delivery in progress"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /webhooks/{id}/deliveries/{deliveryId}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	if !h.authorizeWebhook(c, c.Param("id")) {
+		return
+	}
+
+	resp, err := forCaller(c, h.driverService).RetryWebhookDelivery(c.Param("id"), c.Param("deliveryId"))
+	if err != nil {
+		h.logger.Error("failed to forward retry webhook delivery request", zap.Error(err))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to retry webhook delivery")
+		return
+	}
+	defer resp.Body.Close()
+
+	forwardResponse(c, resp, h.logger)
+}
+
+// authorizeWebhook lets fleet admins through only for their own fleet's
+// subscriptions. Unrestricted users are never checked.
+func (h *WebhookHandler) authorizeWebhook(c *gin.Context, id string) bool {
+	if _, scoped := scopedFleet(c); !scoped {
+		return true
+	}
+
+	resp, err := forCaller(c, h.driverService).GetWebhook(id)
+	if err != nil {
+		h.logger.Error("failed to look up webhook fleet", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up webhook subscription")
+		return false
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		forwardResponse(c, resp, h.logger)
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.logger.Error("failed to read webhook subscription", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up webhook subscription")
+		return false
+	}
+
+	scope, _ := scopedFleet(c)
+	var sub struct {
+		FleetID string `json:"fleetId"`
+	}
+	if err := json.Unmarshal(body, &sub); err != nil {
+		h.logger.Error("failed to decode webhook subscription", zap.Error(err), zap.String("id", id))
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to look up webhook subscription")
+		return false
+	}
+	if sub.FleetID != scope {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "webhook subscription does not belong to your fleet")
+		return false
+	}
+	return true
+}
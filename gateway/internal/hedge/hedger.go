@@ -0,0 +1,208 @@
+// Package hedge decides when a slow upstream read gets a second, hedged
+// request and keeps score of which request answered first.
+//
+// A hedge is sent once the first request has been outstanding for the hedge
+// delay: either a fixed delay or a percentile of recent response times, so
+// only the slowest few percent of requests are duplicated. A budget caps the
+// share of requests that may be hedged, so a slow upstream does not get twice
+// the traffic exactly when it can least take it.
+package hedge
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sampleSize is how many recent response times the delay percentile is taken over
+const sampleSize = 1000
+
+// recomputeEvery is how many new samples make the delay percentile stale
+const recomputeEvery = 100
+
+// maxBurst is how many hedges an idle period can save up
+const maxBurst = 10
+
+// Options configures a Hedger
+type Options struct {
+	// Delay is how long a request may take before it is hedged; with a
+	// Percentile it is the floor under the observed delay
+	Delay time.Duration
+	// Percentile of recent response times to wait before hedging, e.g. 95; 0
+	// always waits Delay
+	Percentile float64
+	// Budget is the share of requests that may be hedged, between 0 and 1
+	Budget float64
+	// Targets are the upstream base URLs hedges are sent to in turn; without
+	// targets they go to the first request's URL and the load balancer
+	Targets []string
+}
+
+// Stats is a snapshot of the hedger
+type Stats struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Requests counts the requests that could have been hedged
+	Requests int64 `json:"requests" example:"48210"`
+	// Hedged counts the requests a hedge was sent for
+	Hedged int64 `json:"hedged" example:"2210"`
+	// HedgeWins counts hedged requests the hedge answered first
+	HedgeWins int64 `json:"hedgeWins" example:"1630"`
+	// PrimaryWins counts hedged requests the first request still answered first
+	PrimaryWins int64 `json:"primaryWins" example:"580"`
+	// BudgetExhausted counts slow requests not hedged because the budget was spent
+	BudgetExhausted int64   `json:"budgetExhausted" example:"35"`
+	DelayMs         float64 `json:"delayMs" example:"84.5"`
+	BudgetPercent   float64 `json:"budgetPercent" example:"10"`
+}
+
+// Hedger holds the hedge delay, the budget and the statistics shared by all
+// hedged requests to an upstream
+type Hedger struct {
+	opts Options
+
+	mu          sync.Mutex
+	samples     []time.Duration
+	next        int
+	sinceUpdate int
+	delay       time.Duration
+	tokens      float64
+	target      int
+	requests    int64
+	hedged      int64
+	hedgeWins   int64
+	primaryWins int64
+	exhausted   int64
+}
+
+// New creates a hedger. A budget outside (0, 1] falls back to 0.1 and a
+// percentile outside [0, 100) to 95.
+func New(opts Options) *Hedger {
+	if opts.Budget <= 0 || opts.Budget > 1 {
+		opts.Budget = 0.1
+	}
+	if opts.Percentile < 0 || opts.Percentile >= 100 {
+		opts.Percentile = 95
+	}
+	if opts.Delay < 0 {
+		opts.Delay = 0
+	}
+	return &Hedger{
+		opts:    opts,
+		samples: make([]time.Duration, 0, sampleSize),
+		delay:   opts.Delay,
+		tokens:  1,
+	}
+}
+
+// Begin counts a hedgeable request and earns the budget its share of a hedge
+func (h *Hedger) Begin() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests++
+	h.tokens = math.Min(maxBurst, h.tokens+h.opts.Budget)
+}
+
+// Delay returns how long to wait for a request before hedging it
+func (h *Hedger) Delay() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.delay
+}
+
+// Allow spends a hedge from the budget and reports whether there was one
+func (h *Hedger) Allow() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.tokens < 1 {
+		h.exhausted++
+		return false
+	}
+	h.tokens--
+	h.hedged++
+	return true
+}
+
+// Target returns the base URL of the next hedge; primary when there are no targets
+func (h *Hedger) Target(primary string) string {
+	if len(h.opts.Targets) == 0 {
+		return primary
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	target := h.opts.Targets[h.target%len(h.opts.Targets)]
+	h.target++
+	return target
+}
+
+// Observe records the response time of a request, which moves the delay when
+// it follows a percentile
+func (h *Hedger) Observe(elapsed time.Duration) {
+	if h.opts.Percentile == 0 {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.samples) < sampleSize {
+		h.samples = append(h.samples, elapsed)
+	} else {
+		h.samples[h.next] = elapsed
+		h.next = (h.next + 1) % sampleSize
+	}
+	h.sinceUpdate++
+	// Until there are enough samples for the percentile to mean something, the
+	// floor applies
+	if len(h.samples) >= recomputeEvery && h.sinceUpdate >= recomputeEvery {
+		h.sinceUpdate = 0
+		h.delay = h.percentile()
+	}
+}
+
+// percentile returns the delay percentile of the samples, at least the floor
+func (h *Hedger) percentile() time.Duration {
+	sorted := make([]time.Duration, len(h.samples))
+	copy(sorted, h.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	index := int(math.Ceil(h.opts.Percentile/100*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if sorted[index] < h.opts.Delay {
+		return h.opts.Delay
+	}
+	return sorted[index]
+}
+
+// Won records which request of a hedged pair answered first
+func (h *Hedger) Won(hedge bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hedge {
+		h.hedgeWins++
+	} else {
+		h.primaryWins++
+	}
+}
+
+// Stats returns a snapshot of the hedger
+func (h *Hedger) Stats() Stats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return Stats{
+		Enabled:         true,
+		Requests:        h.requests,
+		Hedged:          h.hedged,
+		HedgeWins:       h.hedgeWins,
+		PrimaryWins:     h.primaryWins,
+		BudgetExhausted: h.exhausted,
+		DelayMs:         math.Round(float64(h.delay)/float64(time.Millisecond)*10) / 10,
+		BudgetPercent:   h.opts.Budget * 100,
+	}
+}
@@ -0,0 +1,75 @@
+package hedge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedger_Budget(t *testing.T) {
+	h := New(Options{Delay: 10 * time.Millisecond, Percentile: 0, Budget: 0.25})
+
+	// The first hedge is free; after that every fourth request earns one
+	h.Begin()
+	assert.True(t, h.Allow())
+	assert.False(t, h.Allow(), "the budget is spent")
+	for i := 0; i < 4; i++ {
+		h.Begin()
+	}
+	assert.True(t, h.Allow())
+	assert.False(t, h.Allow())
+
+	stats := h.Stats()
+	assert.Equal(t, int64(5), stats.Requests)
+	assert.Equal(t, int64(2), stats.Hedged)
+	assert.Equal(t, int64(2), stats.BudgetExhausted)
+	assert.Equal(t, 25.0, stats.BudgetPercent)
+}
+
+func TestHedger_BudgetBurstIsCapped(t *testing.T) {
+	h := New(Options{Budget: 1})
+	for i := 0; i < 100; i++ {
+		h.Begin()
+	}
+	allowed := 0
+	for h.Allow() {
+		allowed++
+	}
+	assert.Equal(t, maxBurst, allowed)
+}
+
+func TestHedger_PercentileDelay(t *testing.T) {
+	h := New(Options{Delay: 5 * time.Millisecond, Percentile: 90, Budget: 0.1})
+	assert.Equal(t, 5*time.Millisecond, h.Delay(), "the floor applies until there are samples")
+
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, 90*time.Millisecond, h.Delay())
+	assert.Equal(t, 90.0, h.Stats().DelayMs)
+
+	// Fast responses bring the delay down, but not below the floor
+	for i := 0; i < sampleSize; i++ {
+		h.Observe(time.Millisecond)
+	}
+	assert.Equal(t, 5*time.Millisecond, h.Delay())
+}
+
+func TestHedger_FixedDelay(t *testing.T) {
+	h := New(Options{Delay: 20 * time.Millisecond, Percentile: 0})
+	for i := 0; i < 200; i++ {
+		h.Observe(time.Second)
+	}
+	assert.Equal(t, 20*time.Millisecond, h.Delay())
+}
+
+func TestHedger_Targets(t *testing.T) {
+	h := New(Options{})
+	assert.Equal(t, "http://primary", h.Target("http://primary"))
+
+	h = New(Options{Targets: []string{"http://a", "http://b"}})
+	assert.Equal(t, "http://a", h.Target("http://primary"))
+	assert.Equal(t, "http://b", h.Target("http://primary"))
+	assert.Equal(t, "http://a", h.Target("http://primary"))
+}
@@ -0,0 +1,81 @@
+// Package lifecycle tracks in-flight requests and readiness so the gateway can be
+// drained before it is stopped during a rolling update.
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Tracker counts in-flight requests and reports readiness
+type Tracker struct {
+	inFlight atomic.Int64
+	draining atomic.Bool
+
+	mu   sync.Mutex
+	idle chan struct{}
+}
+
+// NewTracker creates a ready tracker with no requests in flight
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Begin marks the start of a request
+func (t *Tracker) Begin() {
+	t.inFlight.Add(1)
+}
+
+// End marks the end of a request and wakes drain waiters when none are left
+func (t *Tracker) End() {
+	if t.inFlight.Add(-1) > 0 {
+		return
+	}
+	t.mu.Lock()
+	if t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+	t.mu.Unlock()
+}
+
+// InFlight returns the number of requests being served
+func (t *Tracker) InFlight() int64 {
+	return t.inFlight.Load()
+}
+
+// Ready reports whether the instance should receive new traffic
+func (t *Tracker) Ready() bool {
+	return !t.draining.Load()
+}
+
+// Draining reports whether a drain has started
+func (t *Tracker) Draining() bool {
+	return t.draining.Load()
+}
+
+// Drain fails readiness and waits until no requests are in flight or ctx is done.
+// Readiness stays failed afterwards; the instance is expected to shut down.
+func (t *Tracker) Drain(ctx context.Context) error {
+	t.draining.Store(true)
+
+	for {
+		t.mu.Lock()
+		if t.inFlight.Load() <= 0 {
+			t.mu.Unlock()
+			return nil
+		}
+		if t.idle == nil {
+			t.idle = make(chan struct{})
+		}
+		idle := t.idle
+		t.mu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package lifecycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker_DrainWaitsForInFlight(t *testing.T) {
+	tracker := NewTracker()
+	assert.True(t, tracker.Ready())
+
+	tracker.Begin()
+	tracker.Begin()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.Drain(context.Background())
+	}()
+
+	assert.Eventually(t, tracker.Draining, time.Second, time.Millisecond)
+	assert.False(t, tracker.Ready())
+
+	tracker.End()
+	select {
+	case <-done:
+		t.Fatal("drain returned while a request was still in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	tracker.End()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("drain did not return after the last request finished")
+	}
+	assert.False(t, tracker.Ready(), "readiness stays failed after draining")
+}
+
+func TestTracker_DrainTimeout(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Begin()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := tracker.Drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(1), tracker.InFlight())
+}
+
+func TestTracker_DrainIdle(t *testing.T) {
+	tracker := NewTracker()
+	assert.NoError(t, tracker.Drain(context.Background()))
+}
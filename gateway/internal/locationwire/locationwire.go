@@ -0,0 +1,184 @@
+// Package locationwire decodes driver location reports sent in compact binary
+// formats, so driver apps reporting every few seconds over mobile data do not
+// pay for JSON field names and decimal text on each ping.
+//
+// Two formats are accepted besides JSON. ContentTypeProtobuf is the protobuf
+// encoding of
+//
+//	message LocationUpdate {
+//	  double lat = 1;
+//	  double lon = 2;
+//	}
+//
+// with unknown fields skipped, so apps may send a newer message.
+// ContentTypePacked is eight bytes: latitude and longitude as big-endian
+// signed 32-bit integers in units of 1e-7 degrees, about a centimetre.
+package locationwire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+)
+
+// Content types of the binary formats
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypePacked   = "application/vnd.bitaksi.location"
+)
+
+// PackedSize is the length of a packed location report
+const PackedSize = 8
+
+// packedScale is the number of packed units per degree
+const packedScale = 1e7
+
+// ErrUnsupported is returned for content types that are not a binary format
+var ErrUnsupported = errors.New("unsupported location content type")
+
+// Location is a decoded position report
+type Location struct {
+	Lat float64
+	Lon float64
+}
+
+// validate rejects coordinates JSON could not carry or that are off the globe
+func (loc Location) validate() error {
+	if math.IsNaN(loc.Lat) || loc.Lat < -90 || loc.Lat > 90 {
+		return fmt.Errorf("lat %v is out of range", loc.Lat)
+	}
+	if math.IsNaN(loc.Lon) || loc.Lon < -180 || loc.Lon > 180 {
+		return fmt.Errorf("lon %v is out of range", loc.Lon)
+	}
+	return nil
+}
+
+// IsBinary reports whether a content type is one of the binary formats
+func IsBinary(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == ContentTypeProtobuf || mediaType == ContentTypePacked)
+}
+
+// Decode decodes a report in the binary format named by contentType
+func Decode(contentType string, data []byte) (Location, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Location{}, ErrUnsupported
+	}
+	switch mediaType {
+	case ContentTypeProtobuf:
+		return DecodeProtobuf(data)
+	case ContentTypePacked:
+		return DecodePacked(data)
+	default:
+		return Location{}, ErrUnsupported
+	}
+}
+
+// DecodePacked decodes the eight byte packed format
+func DecodePacked(data []byte) (Location, error) {
+	if len(data) != PackedSize {
+		return Location{}, fmt.Errorf("packed location must be %d bytes, got %d", PackedSize, len(data))
+	}
+	loc := Location{
+		Lat: float64(int32(binary.BigEndian.Uint32(data[0:4]))) / packedScale,
+		Lon: float64(int32(binary.BigEndian.Uint32(data[4:8]))) / packedScale,
+	}
+	return loc, loc.validate()
+}
+
+// EncodePacked encodes a location in the packed format, rounding to 1e-7 degrees
+func EncodePacked(loc Location) []byte {
+	data := make([]byte, PackedSize)
+	binary.BigEndian.PutUint32(data[0:4], uint32(int32(math.Round(loc.Lat*packedScale))))
+	binary.BigEndian.PutUint32(data[4:8], uint32(int32(math.Round(loc.Lon*packedScale))))
+	return data
+}
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// DecodeProtobuf decodes a LocationUpdate message; both fields are required
+func DecodeProtobuf(data []byte) (Location, error) {
+	var loc Location
+	var hasLat, hasLon bool
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return Location{}, errors.New("malformed protobuf field key")
+		}
+		data = data[n:]
+		field, wireType := key>>3, key&7
+
+		if wireType == wireFixed64 && (field == 1 || field == 2) {
+			if len(data) < 8 {
+				return Location{}, errors.New("truncated protobuf double")
+			}
+			value := math.Float64frombits(binary.LittleEndian.Uint64(data))
+			data = data[8:]
+			if field == 1 {
+				loc.Lat, hasLat = value, true
+			} else {
+				loc.Lon, hasLon = value, true
+			}
+			continue
+		}
+
+		size, err := fieldSize(wireType, data)
+		if err != nil {
+			return Location{}, fmt.Errorf("protobuf field %d: %w", field, err)
+		}
+		data = data[size:]
+	}
+	if !hasLat || !hasLon {
+		return Location{}, errors.New("lat and lon are required")
+	}
+	return loc, loc.validate()
+}
+
+// fieldSize is the length of an unknown field's value
+func fieldSize(wireType uint64, data []byte) (int, error) {
+	switch wireType {
+	case wireVarint:
+		_, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, errors.New("malformed varint")
+		}
+		return n, nil
+	case wireFixed64:
+		if len(data) < 8 {
+			return 0, errors.New("truncated fixed64")
+		}
+		return 8, nil
+	case wireFixed32:
+		if len(data) < 4 {
+			return 0, errors.New("truncated fixed32")
+		}
+		return 4, nil
+	case wireBytes:
+		length, n := binary.Uvarint(data)
+		if n <= 0 || length > uint64(len(data)-n) {
+			return 0, errors.New("truncated length-delimited value")
+		}
+		return n + int(length), nil
+	default:
+		return 0, fmt.Errorf("unsupported wire type %d", wireType)
+	}
+}
+
+// EncodeProtobuf encodes a location as a LocationUpdate message
+func EncodeProtobuf(loc Location) []byte {
+	data := make([]byte, 0, 18)
+	data = append(data, 1<<3|wireFixed64)
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(loc.Lat))
+	data = append(data, 2<<3|wireFixed64)
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(loc.Lon))
+	return data
+}
@@ -0,0 +1,97 @@
+package locationwire
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var istanbul = Location{Lat: 41.0431, Lon: 29.0099}
+
+func TestDecode(t *testing.T) {
+	loc, err := Decode(ContentTypeProtobuf, EncodeProtobuf(istanbul))
+	require.NoError(t, err)
+	assert.Equal(t, istanbul, loc)
+
+	loc, err = Decode(ContentTypePacked+"; charset=binary", EncodePacked(istanbul))
+	require.NoError(t, err)
+	assert.InDelta(t, istanbul.Lat, loc.Lat, 1e-7)
+	assert.InDelta(t, istanbul.Lon, loc.Lon, 1e-7)
+
+	loc, err = Decode(ContentTypePacked, EncodePacked(Location{Lat: -33.8688, Lon: -151.2093}))
+	require.NoError(t, err)
+	assert.InDelta(t, -151.2093, loc.Lon, 1e-7, "negative coordinates survive the packed format")
+
+	_, err = Decode("application/json", []byte(`{}`))
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.True(t, IsBinary("application/x-protobuf"))
+	assert.False(t, IsBinary("application/json"))
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	// A newer message with a varint timestamp (field 3), a string (field 4)
+	// and a float accuracy (field 5) around the coordinates
+	data := []byte{3<<3 | wireVarint, 0xe8, 0x07, 4<<3 | wireBytes, 2, 'o', 'k'}
+	data = append(data, EncodeProtobuf(istanbul)...)
+	data = append(data, 5<<3|wireFixed32, 0, 0, 0x80, 0x3f)
+	loc, err := DecodeProtobuf(data)
+	require.NoError(t, err)
+	assert.Equal(t, istanbul, loc)
+
+	for name, data := range map[string][]byte{
+		"empty":            {},
+		"missing lon":      EncodeProtobuf(istanbul)[:9],
+		"truncated double": EncodeProtobuf(istanbul)[:12],
+		"truncated string": {4<<3 | wireBytes, 5, 'o'},
+		"group wire type":  {3<<3 | 3},
+		"out of range":     EncodeProtobuf(Location{Lat: 91, Lon: 29}),
+	} {
+		_, err := DecodeProtobuf(data)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDecodePacked(t *testing.T) {
+	_, err := DecodePacked([]byte{1, 2, 3})
+	assert.Error(t, err)
+	_, err = DecodePacked([]byte{0x7f, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	assert.Error(t, err, "214 degrees latitude")
+}
+
+// The benchmarks report the size of a report in each format besides the
+// decoding cost
+func BenchmarkDecodeJSON(b *testing.B) {
+	data := []byte(`{"lat":41.0431,"lon":29.0099}`)
+	for i := 0; i < b.N; i++ {
+		var req struct {
+			Lat *float64 `json:"lat"`
+			Lon *float64 `json:"lon"`
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes/report")
+}
+
+func BenchmarkDecodeProtobuf(b *testing.B) {
+	data := EncodeProtobuf(istanbul)
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeProtobuf(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes/report")
+}
+
+func BenchmarkDecodePacked(b *testing.B) {
+	data := EncodePacked(istanbul)
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodePacked(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(len(data)), "bytes/report")
+}
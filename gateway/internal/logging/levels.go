@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels holds the level of the root logger and overrides for named loggers.
+// A logger named "service.onboarding" uses the override for "service.onboarding", then
+// the one for "service", then the root level. Levels can change while the
+// service runs.
+type Levels struct {
+	mu        sync.RWMutex
+	root      zapcore.Level
+	overrides map[string]zapcore.Level
+	// lowest is the most verbose level any logger is at, so entries below it
+	// are dropped without looking up overrides
+	lowest atomic.Int32
+}
+
+// LevelsSnapshot is the current level configuration
+type LevelsSnapshot struct {
+	Level   string            `json:"level" example:"info"`
+	Loggers map[string]string `json:"loggers"`
+}
+
+// NewLevels creates levels with a root level and overrides by logger name
+func NewLevels(root string, overrides map[string]string) (*Levels, error) {
+	rootLevel, err := ParseLevel(root)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Levels{root: rootLevel, overrides: make(map[string]zapcore.Level)}
+	for name, value := range overrides {
+		level, err := ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("logger %q: %w", name, err)
+		}
+		l.overrides[name] = level
+	}
+	l.updateLowest()
+	return l, nil
+}
+
+// ParseLevel parses a level name; an empty name is info
+func ParseLevel(name string) (zapcore.Level, error) {
+	if name == "" {
+		return zapcore.InfoLevel, nil
+	}
+	level, err := zapcore.ParseLevel(strings.ToLower(name))
+	if err != nil {
+		return level, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// Root returns the level of loggers without an override
+func (l *Levels) Root() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.root
+}
+
+// Level returns the level the logger with the given name logs at
+func (l *Levels) Level(name string) zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for name != "" {
+		if level, ok := l.overrides[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.root
+}
+
+// Enabled reports whether the named logger logs entries at level
+func (l *Levels) Enabled(name string, level zapcore.Level) bool {
+	if !l.anyEnabled(level) {
+		return false
+	}
+	return level >= l.Level(name)
+}
+
+// SetLevel changes the level of the named logger; an empty name changes the root level
+func (l *Levels) SetLevel(name string, level zapcore.Level) {
+	l.mu.Lock()
+	if name == "" {
+		l.root = level
+	} else {
+		l.overrides[name] = level
+	}
+	l.mu.Unlock()
+	l.updateLowest()
+}
+
+// ResetLevel removes the override of the named logger so it follows its parent again
+func (l *Levels) ResetLevel(name string) {
+	l.mu.Lock()
+	delete(l.overrides, name)
+	l.mu.Unlock()
+	l.updateLowest()
+}
+
+// Snapshot returns the root level and all overrides
+func (l *Levels) Snapshot() LevelsSnapshot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	loggers := make(map[string]string, len(l.overrides))
+	for name, level := range l.overrides {
+		loggers[name] = level.String()
+	}
+	return LevelsSnapshot{Level: l.root.String(), Loggers: loggers}
+}
+
+func (l *Levels) anyEnabled(level zapcore.Level) bool {
+	return level >= zapcore.Level(l.lowest.Load())
+}
+
+func (l *Levels) updateLowest() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	lowest := l.root
+	for _, level := range l.overrides {
+		if level < lowest {
+			lowest = level
+		}
+	}
+	l.lowest.Store(int32(lowest))
+}
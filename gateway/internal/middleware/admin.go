@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminAuth returns a middleware that requires the configured admin token in X-Admin-Token
+func AdminAuth(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if checkAdminToken(c, cfg, logger) {
+			c.Next()
+		}
+	}
+}
+
+// checkAdminToken aborts the request unless it carries the admin token and
+// reports whether it may go on
+func checkAdminToken(c *gin.Context, cfg *config.Config, logger *zap.Logger) bool {
+	token := c.GetHeader("X-Admin-Token")
+	if cfg.Admin.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Admin.Token)) != 1 {
+		logger.Warn("rejected admin request", zap.String("path", c.Request.URL.Path), zap.String("ip", c.ClientIP()))
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "valid admin token is required")
+		return false
+	}
+	return true
+}
+
+// DocsAuth is AdminAuth for the Swagger UI. Browsers cannot add X-Admin-Token
+// to page loads, so the token is also accepted as the HTTP Basic password and
+// a Basic challenge is sent when it is missing.
+func DocsAuth(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Admin-Token")
+		if token == "" {
+			_, token, _ = c.Request.BasicAuth()
+		}
+		if cfg.Admin.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cfg.Admin.Token)) != 1 {
+			logger.Warn("rejected docs request", zap.String("path", c.Request.URL.Path), zap.String("ip", c.ClientIP()))
+			c.Header("WWW-Authenticate", `Basic realm="docs"`)
+			problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "valid admin token is required")
+			return
+		}
+
+		c.Next()
+	}
+}
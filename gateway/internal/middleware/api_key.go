@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -12,54 +13,53 @@ import (
 // APIKeyAuth returns a middleware that validates API keys
 func APIKeyAuth(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip API key check if disabled
-		if !cfg.APIKey.Enabled {
+		if checkAPIKey(c, cfg, logger) {
 			c.Next()
-			return
 		}
+	}
+}
 
-		// Get API key from header (support multiple header formats)
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey == "" {
-			// Try Authorization header with "ApiKey" prefix
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" {
-				parts := strings.Split(authHeader, " ")
-				if len(parts) == 2 && strings.ToLower(parts[0]) == "apikey" {
-					apiKey = parts[1]
-				}
-			}
-		}
+// checkAPIKey aborts the request unless it carries a valid API key and reports
+// whether it may go on. Every request may while API keys are disabled.
+func checkAPIKey(c *gin.Context, cfg *config.Config, logger *zap.Logger) bool {
+	// Skip API key check if disabled
+	if !cfg.APIKey.Enabled {
+		return true
+	}
 
-		if apiKey == "" {
-			logger.Debug("API key missing")
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "API key is required",
-				},
-			})
-			c.Abort()
-			return
-		}
+	apiKey := apiKeyFrom(c)
+	if apiKey == "" {
+		logger.Debug("API key missing")
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "API key is required")
+		return false
+	}
 
-		// Validate API key
-		if !isValidAPIKey(apiKey, cfg.APIKey.Keys) {
-			logger.Warn("invalid API key attempted", zap.String("key_prefix", maskAPIKey(apiKey)))
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "invalid API key",
-				},
-			})
-			c.Abort()
-			return
-		}
+	// Validate API key
+	if !isValidAPIKey(apiKey, cfg.APIKey.Keys) {
+		logger.Warn("invalid API key attempted", zap.String("key_prefix", maskAPIKey(apiKey)))
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid API key")
+		return false
+	}
 
-		// Set API key in context for logging/auditing
-		c.Set("api_key", maskAPIKey(apiKey))
-		c.Next()
+	// Set API key in context for logging/auditing
+	c.Set("api_key", maskAPIKey(apiKey))
+	return true
+}
+
+// apiKeyFrom reads the API key from X-API-Key or an "ApiKey" Authorization header
+func apiKeyFrom(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+	// Try Authorization header with "ApiKey" prefix
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "apikey" {
+			return parts[1]
+		}
 	}
+	return ""
 }
 
 // isValidAPIKey checks if the provided API key is valid
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Authorize enforces the auth policy on every route: the route the request
+// matched is looked up in the policy and its API key, bearer token, role,
+// device token or admin token requirement checked. A bearer token sent to a
+// public or API key route is not required but still identifies the caller.
+// Requests that matched no route pass on to the 404 and 405 handling. Register it after the global
+// middleware so it runs where per-route auth did. Without devices, device
+// routes only take bearer tokens.
+func Authorize(p *policy.Policy, cfg *config.Config, tokens *token.Manager, devices *DeviceAuthenticator, logger *zap.Logger) gin.HandlerFunc {
+	// A single authenticator so replay tracking spans all protected routes
+	bearer := newJWTAuthenticator(cfg, tokens, logger)
+
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+
+		rule := p.Resolve(c.Request.Method, route)
+		switch rule.Auth {
+		case policy.AuthPublic:
+			bearer.identify(c)
+		case policy.AuthAPIKey:
+			if !checkAPIKey(c, cfg, logger) {
+				return
+			}
+			bearer.identify(c)
+		case policy.AuthDevice:
+			if presented, ok := presentedDeviceToken(c); ok && devices != nil {
+				if !devices.authenticate(c, presented, rule.Scope) {
+					return
+				}
+				break
+			}
+			fallthrough
+		case policy.AuthJWT:
+			if !bearer.authenticate(c) {
+				return
+			}
+			if role := c.GetString("role"); !rule.Allows(role) {
+				problem.Abort(c, http.StatusForbidden, "FORBIDDEN", fmt.Sprintf("role %s cannot perform this action", role))
+				return
+			}
+		case policy.AuthAdmin:
+			if !checkAdminToken(c, cfg, logger) {
+				return
+			}
+		}
+		c.Next()
+	}
+}
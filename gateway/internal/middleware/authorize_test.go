@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT:    config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Enabled: true},
+		APIKey: config.APIKeyConfig{Enabled: true, Keys: []string{"key-1234567890"}},
+		Admin:  config.AdminConfig{Token: "admin-token"},
+	}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+	p, err := policy.Parse([]byte(`{"routes": [
+		{"method": "GET", "path": "/public", "auth": "public"},
+		{"method": "GET", "path": "/keyed", "auth": "apikey"},
+		{"method": "GET", "path": "/staff/:id", "auth": "jwt", "roles": ["fleet_admin"]},
+		{"method": "*", "path": "/admin/*", "auth": "admin"}
+	]}`))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(Authorize(p, cfg, tokens, nil, zap.NewNop()))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/public", ok)
+	router.GET("/keyed", ok)
+	router.GET("/staff/:id", ok)
+	router.GET("/open", ok)
+	router.POST("/admin/drain", ok)
+
+	staff, err := tokens.Issue("staff")
+	require.NoError(t, err)
+	fleetAdmin, err := tokens.Issue("fleet", token.WithRole(token.RoleFleetAdmin), token.WithFleet("f1"))
+	require.NoError(t, err)
+	rider, err := tokens.Issue("rider-1", token.WithRole(token.RoleRider), token.WithRider("rider-1"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header map[string]string
+		want   int
+	}{
+		{name: "public", method: "GET", path: "/public", want: http.StatusOK},
+		{name: "api key missing", method: "GET", path: "/keyed", want: http.StatusUnauthorized},
+		{name: "api key", method: "GET", path: "/keyed", header: map[string]string{"X-API-Key": "key-1234567890"}, want: http.StatusOK},
+		{name: "token missing", method: "GET", path: "/staff/1", want: http.StatusUnauthorized},
+		{name: "token without role", method: "GET", path: "/staff/1", header: map[string]string{"Authorization": "Bearer " + staff}, want: http.StatusOK},
+		{name: "allowed role", method: "GET", path: "/staff/1", header: map[string]string{"Authorization": "Bearer " + fleetAdmin}, want: http.StatusOK},
+		{name: "other role", method: "GET", path: "/staff/1", header: map[string]string{"Authorization": "Bearer " + rider}, want: http.StatusForbidden},
+		{name: "default is jwt", method: "GET", path: "/open", want: http.StatusUnauthorized},
+		{name: "admin token missing", method: "POST", path: "/admin/drain", want: http.StatusUnauthorized},
+		{name: "admin token", method: "POST", path: "/admin/drain", header: map[string]string{"X-Admin-Token": "admin-token"}, want: http.StatusOK},
+		{name: "unknown route", method: "GET", path: "/missing", want: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestAuthorize_IdentifiesCallersOnOpenRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT:    config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Enabled: true, ReplayProtection: true, ReplayWindow: time.Hour},
+		APIKey: config.APIKeyConfig{Enabled: true, Keys: []string{"key-1234567890"}},
+	}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+	p, err := policy.Parse([]byte(`{"routes": [
+		{"method": "GET", "path": "/public", "auth": "public"},
+		{"method": "GET", "path": "/keyed", "auth": "apikey"}
+	]}`))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(Authorize(p, cfg, tokens, nil, zap.NewNop()))
+	role := func(c *gin.Context) { c.String(http.StatusOK, c.GetString("role")) }
+	router.GET("/public", role)
+	router.GET("/keyed", role)
+
+	rider, err := tokens.Issue("rider-1", token.WithRole(token.RoleRider), token.WithRider("rider-1"))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		path   string
+		header string
+		want   string
+	}{
+		{"/public", "Bearer " + rider, token.RoleRider},
+		{"/keyed", "Bearer " + rider, token.RoleRider},
+		// The same token again: identifying a caller does not use up its jti
+		{"/keyed", "Bearer " + rider, token.RoleRider},
+		{"/keyed", "Bearer invalid", ""},
+		{"/keyed", "", ""},
+	} {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		req.Header.Set("X-API-Key", "key-1234567890")
+		if tt.header != "" {
+			req.Header.Set("Authorization", tt.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, tt.path)
+		assert.Equal(t, tt.want, w.Body.String(), tt.path)
+	}
+}
+
+func TestAuthorize_DisabledAuthLetsCallersThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour}}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(Authorize(policy.Default(), cfg, tokens, nil, zap.NewNop()))
+	router.PUT("/drivers/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/drivers/nearby", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("PUT", "/drivers/d1", nil),
+		httptest.NewRequest("GET", "/drivers/nearby", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code, req.URL.Path)
+	}
+}
+
+type fakeDeviceVerifier struct {
+	tokens map[string]*service.DeviceToken
+	err    error
+	calls  int
+}
+
+func (f *fakeDeviceVerifier) VerifyDeviceToken(_ context.Context, presented string) (*service.DeviceToken, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	device, ok := f.tokens[presented]
+	if !ok {
+		return nil, service.ErrInvalidDeviceToken
+	}
+	return device, nil
+}
+
+func TestAuthorize_DeviceTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Enabled: true}}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+	p, err := policy.Parse([]byte(`{"routes": [
+		{"method": "PUT", "path": "/drivers/:id/location", "auth": "device", "scope": "location:update", "roles": ["fleet_admin"]},
+		{"method": "POST", "path": "/drivers/:id/heartbeat", "auth": "device", "scope": "heartbeat"}
+	]}`))
+	require.NoError(t, err)
+
+	verifier := &fakeDeviceVerifier{tokens: map[string]*service.DeviceToken{
+		"dtk_phone": {ID: "t1", DriverID: "d1", DeviceID: "phone", Scopes: []string{"location:update", "heartbeat"}},
+		"dtk_beat":  {ID: "t2", DriverID: "d1", DeviceID: "watch", Scopes: []string{"heartbeat"}},
+	}}
+	devices := NewDeviceAuthenticator(verifier, time.Minute, zap.NewNop())
+
+	router := gin.New()
+	router.Use(Authorize(p, cfg, tokens, devices, zap.NewNop()))
+	router.PUT("/drivers/:id/location", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"role": c.GetString("role"), "driverId": c.GetString("driverId")})
+	})
+	router.POST("/drivers/:id/heartbeat", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rider, err := tokens.Issue("rider-1", token.WithRole(token.RoleRider), token.WithRider("rider-1"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		bearer string
+		want   int
+	}{
+		{name: "device token", method: "PUT", path: "/drivers/d1/location", bearer: "dtk_phone", want: http.StatusOK},
+		{name: "another driver", method: "PUT", path: "/drivers/d2/location", bearer: "dtk_phone", want: http.StatusForbidden},
+		{name: "missing scope", method: "PUT", path: "/drivers/d1/location", bearer: "dtk_beat", want: http.StatusForbidden},
+		{name: "scope of the route", method: "POST", path: "/drivers/d1/heartbeat", bearer: "dtk_beat", want: http.StatusOK},
+		{name: "revoked token", method: "PUT", path: "/drivers/d1/location", bearer: "dtk_lost", want: http.StatusUnauthorized},
+		{name: "no token", method: "PUT", path: "/drivers/d1/location", want: http.StatusUnauthorized},
+		{name: "jwt of another role", method: "PUT", path: "/drivers/d1/location", bearer: rider, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+
+	t.Run("sets the device's driver", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/drivers/d1/location", nil)
+		req.Header.Set("Authorization", "Bearer dtk_phone")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.JSONEq(t, `{"role":"driver_device","driverId":"d1"}`, w.Body.String())
+	})
+
+	t.Run("verified tokens are cached until forgotten", func(t *testing.T) {
+		send := func() int {
+			req := httptest.NewRequest("POST", "/drivers/d1/heartbeat", nil)
+			req.Header.Set("Authorization", "Bearer dtk_phone")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w.Code
+		}
+		require.Equal(t, http.StatusOK, send())
+		calls, stats := verifier.calls, devices.CacheStats()
+		require.Equal(t, http.StatusOK, send())
+		assert.Equal(t, calls, verifier.calls)
+		assert.Equal(t, stats.Hits+1, devices.CacheStats().Hits)
+		assert.Equal(t, stats.Misses, devices.CacheStats().Misses)
+
+		delete(verifier.tokens, "dtk_phone")
+		devices.Forget("t1")
+		assert.Equal(t, http.StatusUnauthorized, send())
+	})
+
+	t.Run("driver service unavailable", func(t *testing.T) {
+		verifier.err = errors.New("connection refused")
+		defer func() { verifier.err = nil }()
+		req := httptest.NewRequest("POST", "/drivers/d1/heartbeat", nil)
+		req.Header.Set("Authorization", "Bearer dtk_beat_unseen")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	assert.True(t, acceptsGzip("gzip"))
+	assert.True(t, acceptsGzip("br, GZIP;q=0.8"))
+	assert.True(t, acceptsGzip("*"))
+	assert.False(t, acceptsGzip(""))
+	assert.False(t, acceptsGzip("br, deflate"))
+	assert.False(t, acceptsGzip("gzip;q=0"))
+}
+
+func TestCompress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat(`{"id":"driver"},`, 200)
+	router := gin.New()
+	router.Use(Compress(config.CompressionConfig{
+		Enabled:      true,
+		MinSize:      512,
+		Level:        -1,
+		ContentTypes: []string{"application/json", "text/*"},
+	}))
+	router.GET("/large", func(c *gin.Context) { c.Data(http.StatusOK, "application/json", []byte(large)) })
+	router.GET("/small", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) })
+	router.GET("/csv", func(c *gin.Context) { c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(large)) })
+	router.GET("/image", func(c *gin.Context) { c.Data(http.StatusOK, "image/png", []byte(large)) })
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("compresses large allowed responses", func(t *testing.T) {
+		for _, path := range []string{"/large", "/csv"} {
+			w := get(path, "gzip")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Less(t, w.Body.Len(), len(large))
+
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Equal(t, large, string(body))
+		}
+	})
+
+	t.Run("small responses are sent as is", func(t *testing.T) {
+		w := get("/small", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+	})
+
+	t.Run("content types outside the allowlist are sent as is", func(t *testing.T) {
+		w := get("/image", "gzip")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+	})
+
+	t.Run("clients without gzip get plain responses", func(t *testing.T) {
+		w := get("/large", "")
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, w.Body.String())
+	})
+}
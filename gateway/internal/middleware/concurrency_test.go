@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewConcurrencyLimiter(1, 2*time.Second, zap.NewNop())
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/slow", func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/admin/saturation", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	slow := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		router.ServeHTTP(slow, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	}()
+	<-entered
+
+	rejected := httptest.NewRecorder()
+	router.ServeHTTP(rejected, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rejected.Code)
+	assert.Equal(t, "2", rejected.Header().Get("Retry-After"))
+
+	// Admin calls are not limited, so saturation stays observable
+	admin := httptest.NewRecorder()
+	router.ServeHTTP(admin, httptest.NewRequest(http.MethodGet, "/admin/saturation", nil))
+	assert.Equal(t, http.StatusOK, admin.Code)
+
+	stats := limiter.Stats()
+	assert.Equal(t, SaturationStats{MaxInFlight: 1, InFlight: 1, PeakInFlight: 1, Saturation: 1, Rejected: 1}, stats)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, slow.Code)
+	assert.Equal(t, int64(0), limiter.Stats().InFlight)
+	assert.Equal(t, int64(1), limiter.Stats().PeakInFlight)
+}
+
+func TestConcurrencyLimiter_ConnState(t *testing.T) {
+	limiter := NewConcurrencyLimiter(0, time.Second, zap.NewNop())
+	limiter.ConnState(nil, http.StateNew)
+	limiter.ConnState(nil, http.StateNew)
+	limiter.ConnState(nil, http.StateActive)
+	limiter.ConnState(nil, http.StateClosed)
+
+	stats := limiter.Stats()
+	assert.Equal(t, int64(1), stats.OpenConnections)
+	assert.Equal(t, int64(0), stats.MaxInFlight)
+	assert.Zero(t, stats.Saturation)
+}
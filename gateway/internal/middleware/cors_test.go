@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOriginAllowed(t *testing.T) {
+	allowed := []string{"https://dashboard.bitaksi.com", "https://*.partner.com"}
+
+	tests := []struct {
+		name     string
+		origin   string
+		expected bool
+	}{
+		{name: "exact match", origin: "https://dashboard.bitaksi.com", expected: true},
+		{name: "case insensitive", origin: "HTTPS://Dashboard.Bitaksi.com", expected: true},
+		{name: "wildcard subdomain", origin: "https://app.partner.com", expected: true},
+		{name: "nested wildcard subdomain", origin: "https://eu.app.partner.com", expected: true},
+		{name: "wildcard does not match apex", origin: "https://partner.com", expected: false},
+		{name: "wildcard scheme mismatch", origin: "http://app.partner.com", expected: false},
+		{name: "unknown origin", origin: "https://evil.com", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isOriginAllowed(tt.origin, allowed))
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.CORSConfig{
+		AllowedOrigins:   []string{"https://*.bitaksi.com"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+
+	router := gin.New()
+	router.Use(CORS(cfg))
+	router.GET("/drivers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	t.Run("allowed preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/drivers", nil)
+		req.Header.Set("Origin", "https://app.bitaksi.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://app.bitaksi.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, POST", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("disallowed preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/drivers", nil)
+		req.Header.Set("Origin", "https://evil.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("disallowed simple request has no CORS headers", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/drivers", nil)
+		req.Header.Set("Origin", "https://evil.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("wildcard without credentials", func(t *testing.T) {
+		open := gin.New()
+		open.Use(CORS(config.CORSConfig{AllowedOrigins: []string{"*"}}))
+		open.GET("/drivers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+		req := httptest.NewRequest(http.MethodGet, "/drivers", nil)
+		req.Header.Set("Origin", "https://anything.example")
+		w := httptest.NewRecorder()
+		open.ServeHTTP(w, req)
+
+		assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/bitaksi/gateway/internal/service"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceTokenPrefix starts every device token, which tells them apart from JWTs
+const DeviceTokenPrefix = "dtk_"
+
+// maxCachedDeviceTokens bounds the verification cache; it is cleared when full
+const maxCachedDeviceTokens = 100000
+
+// DeviceTokenVerifier checks device tokens with the service that issued them
+type DeviceTokenVerifier interface {
+	VerifyDeviceToken(ctx context.Context, token string) (*service.DeviceToken, error)
+}
+
+// DeviceAuthenticator authenticates driver apps by their device tokens. Tokens
+// the driver service accepted are trusted for a TTL without asking again, so a
+// revoked token keeps working for up to that long on gateways that did not
+// revoke it themselves.
+type DeviceAuthenticator struct {
+	verifier DeviceTokenVerifier
+	ttl      time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu sync.Mutex
+	// cache holds verified tokens by the hash of their value
+	cache  map[string]cachedDeviceToken
+	hits   int64
+	misses int64
+}
+
+// DeviceCacheStats counts how device tokens were verified since start
+type DeviceCacheStats struct {
+	Entries int   `json:"entries" example:"8120"`
+	Hits    int64 `json:"hits" example:"512930"`
+	// Misses were verified with the driver service
+	Misses  int64   `json:"misses" example:"20311"`
+	HitRate float64 `json:"hitRate" example:"0.962"`
+}
+
+type cachedDeviceToken struct {
+	token   *service.DeviceToken
+	expires time.Time
+}
+
+// NewDeviceAuthenticator creates an authenticator verifying tokens through
+// verifier; a ttl of 0 verifies every request
+func NewDeviceAuthenticator(verifier DeviceTokenVerifier, ttl time.Duration, logger *zap.Logger) *DeviceAuthenticator {
+	return &DeviceAuthenticator{
+		verifier: verifier,
+		ttl:      ttl,
+		logger:   logger,
+		now:      time.Now,
+		cache:    make(map[string]cachedDeviceToken),
+	}
+}
+
+// presentedDeviceToken returns the device token the request carries as its bearer token
+func presentedDeviceToken(c *gin.Context) (string, bool) {
+	bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || !strings.HasPrefix(bearer, DeviceTokenPrefix) {
+		return "", false
+	}
+	return bearer, true
+}
+
+// authenticate aborts the request unless the token is valid, carries scope and
+// belongs to the driver in the :id parameter, and reports whether it may go on.
+// The device's driver is set on the context with the driver_device role.
+func (a *DeviceAuthenticator) authenticate(c *gin.Context, presented, scope string) bool {
+	device, err := a.verify(c.Request.Context(), presented)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDeviceToken) {
+			problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or revoked device token")
+			return false
+		}
+		a.logger.Error("failed to verify device token", zap.Error(err))
+		problem.Abort(c, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "device token could not be verified, retry later")
+		return false
+	}
+
+	if !device.HasScope(scope) {
+		problem.Abort(c, http.StatusForbidden, "FORBIDDEN", fmt.Sprintf("device token lacks the %s scope", scope))
+		return false
+	}
+	if device.DriverID != c.Param("id") {
+		a.logger.Warn("device token used for another driver",
+			zap.String("tokenId", device.ID), zap.String("driverId", device.DriverID), zap.String("target", c.Param("id")))
+		problem.Abort(c, http.StatusForbidden, "FORBIDDEN", "device token belongs to another driver")
+		return false
+	}
+
+	c.Set("role", token.RoleDriverDevice)
+	c.Set("driverId", device.DriverID)
+	c.Set("deviceTokenId", device.ID)
+	return true
+}
+
+// verify returns the token from the cache or the driver service
+func (a *DeviceAuthenticator) verify(ctx context.Context, presented string) (*service.DeviceToken, error) {
+	sum := sha256.Sum256([]byte(presented))
+	key := hex.EncodeToString(sum[:])
+
+	now := a.now()
+	a.mu.Lock()
+	cached, ok := a.cache[key]
+	fresh := ok && now.Before(cached.expires)
+	if fresh {
+		a.hits++
+	} else {
+		a.misses++
+	}
+	a.mu.Unlock()
+	if fresh {
+		return cached.token, nil
+	}
+
+	device, err := a.verifier.VerifyDeviceToken(ctx, presented)
+	if err != nil {
+		return nil, err
+	}
+	if a.ttl > 0 {
+		a.mu.Lock()
+		if len(a.cache) >= maxCachedDeviceTokens {
+			a.cache = make(map[string]cachedDeviceToken)
+		}
+		a.cache[key] = cachedDeviceToken{token: device, expires: now.Add(a.ttl)}
+		a.mu.Unlock()
+	}
+	return device, nil
+}
+
+// CacheStats returns the size and hit rate of the verification cache
+func (a *DeviceAuthenticator) CacheStats() DeviceCacheStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := DeviceCacheStats{Entries: len(a.cache), Hits: a.hits, Misses: a.misses}
+	if total := a.hits + a.misses; total > 0 {
+		stats.HitRate = math.Round(float64(a.hits)/float64(total)*1000) / 1000
+	}
+	return stats
+}
+
+// Forget drops a token from the cache so a revocation through this gateway
+// applies at once
+func (a *DeviceAuthenticator) Forget(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for key, cached := range a.cache {
+		if cached.token.ID == id {
+			delete(a.cache, key)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// EnvelopeHeader lets a client ask for ("true") or refuse ("false") the
+// response envelope; it overrides the setting of its API key
+const EnvelopeHeader = "X-Response-Envelope"
+
+// Envelope is a JSON response wrapped with request metadata. Successful
+// responses carry the body in Data; errors carry the error object in Error.
+type Envelope struct {
+	Data  json.RawMessage `json:"data" swaggertype:"object"`
+	Meta  EnvelopeMeta    `json:"meta"`
+	Error json.RawMessage `json:"error" swaggertype:"object"`
+}
+
+// EnvelopeMeta describes the request a response belongs to
+type EnvelopeMeta struct {
+	RequestID  string `json:"requestId" example:"4f2c1a9e0b7d4c3a8e6f5d2b1a0c9e8f"`
+	DurationMs int64  `json:"durationMs" example:"12"`
+	// Degraded marks stub data served while the driver service is unavailable
+	Degraded bool `json:"degraded,omitempty" example:"false"`
+	// DistanceUnit is the unit of distance fields on nearby searches
+	DistanceUnit string `json:"distanceUnit,omitempty" example:"km"`
+	// Fares tells whether every driver of a nearby search with includeFares got a fare
+	Fares string `json:"fares,omitempty" example:"complete" enums:"complete,partial,unavailable"`
+}
+
+// ResponseEnvelope returns a middleware that wraps JSON responses as
+// {data, meta, error} for clients that opt in with EnvelopeHeader or use an
+// API key listed in the config. Other clients, and responses that are not
+// JSON such as CSV exports or event streams, are left unchanged. Register it
+// after Compress so the envelope is what gets compressed.
+func ResponseEnvelope(cfg config.EnvelopeConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" || !wantsEnvelope(c, cfg) {
+			c.Next()
+			return
+		}
+
+		writer := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		start := time.Now()
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		writer.finish(EnvelopeMeta{
+			RequestID:    c.GetString("requestID"),
+			DurationMs:   time.Since(start).Milliseconds(),
+			Degraded:     c.GetInt("degradedStatus") != 0,
+			DistanceUnit: c.GetString("distanceUnit"),
+			Fares:        c.GetString("fares"),
+		})
+	}
+}
+
+// wantsEnvelope reports whether the client opted in to the envelope
+func wantsEnvelope(c *gin.Context, cfg config.EnvelopeConfig) bool {
+	if header := c.GetHeader(EnvelopeHeader); header != "" {
+		on, err := strconv.ParseBool(header)
+		return err == nil && on
+	}
+	if len(cfg.APIKeys) == 0 {
+		return false
+	}
+	apiKey := apiKeyFrom(c)
+	return apiKey != "" && isValidAPIKey(apiKey, cfg.APIKeys)
+}
+
+// envelopeWriter holds back JSON bodies until the handler is done; other
+// bodies are passed through as soon as they are written
+type envelopeWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	decided     bool
+	passThrough bool
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		w.passThrough = !isJSON(w.Header().Get("Content-Type"))
+	}
+	if w.passThrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush gives up on the envelope: a flushed response is being streamed
+func (w *envelopeWriter) Flush() {
+	w.release()
+	w.ResponseWriter.Flush()
+}
+
+func (w *envelopeWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.release()
+	return w.ResponseWriter.Hijack()
+}
+
+// release writes out the buffered body unchanged and stops buffering
+func (w *envelopeWriter) release() {
+	w.decided, w.passThrough = true, true
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish wraps the buffered body and writes it out
+func (w *envelopeWriter) finish(meta EnvelopeMeta) {
+	if w.passThrough || w.buf.Len() == 0 {
+		return
+	}
+	body := w.buf.Bytes()
+	if !json.Valid(body) || w.ResponseWriter.Written() {
+		w.ResponseWriter.Write(body)
+		return
+	}
+
+	envelope := Envelope{Meta: meta}
+	if w.Status() >= http.StatusBadRequest {
+		// Unwrap the default {"error": {...}} format; problem details are the error
+		var wrapped struct {
+			Error json.RawMessage `json:"error"`
+		}
+		if json.Unmarshal(body, &wrapped) == nil && len(wrapped.Error) > 0 {
+			envelope.Error = wrapped.Error
+		} else {
+			envelope.Error = body
+		}
+	} else {
+		envelope.Data = body
+	}
+
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		w.ResponseWriter.Write(body)
+		return
+	}
+	header := w.Header()
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Del("Content-Length")
+	w.ResponseWriter.Write(out)
+}
+
+// isJSON reports whether a content type is JSON, including +json types
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
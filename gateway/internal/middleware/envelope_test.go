@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(ResponseEnvelope(config.EnvelopeConfig{APIKeys: []string{"partner-key"}}))
+	router.GET("/driver", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"id": "d1"}) })
+	router.GET("/missing", func(c *gin.Context) { problem.Abort(c, http.StatusNotFound, "NOT_FOUND", "driver not found") })
+	router.GET("/export", func(c *gin.Context) { c.Data(http.StatusOK, "text/csv", []byte("id\nd1\n")) })
+	router.GET("/nearby", func(c *gin.Context) {
+		c.Set("distanceUnit", "mi")
+		c.Set("fares", "partial")
+		c.JSON(http.StatusOK, []gin.H{{"id": "d1", "distanceKm": 1.5, "distance": 0.9321}})
+	})
+
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(RequestIDHeader, "req-1")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) map[string]json.RawMessage {
+		var envelope map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+		return envelope
+	}
+
+	t.Run("raw by default", func(t *testing.T) {
+		w := get("/driver", nil)
+		assert.JSONEq(t, `{"id":"d1"}`, w.Body.String())
+	})
+
+	t.Run("wraps data when asked", func(t *testing.T) {
+		w := get("/driver", map[string]string{EnvelopeHeader: "true"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		envelope := decode(w)
+		assert.JSONEq(t, `{"id":"d1"}`, string(envelope["data"]))
+		assert.Equal(t, "null", string(envelope["error"]))
+
+		var meta EnvelopeMeta
+		require.NoError(t, json.Unmarshal(envelope["meta"], &meta))
+		assert.Equal(t, "req-1", meta.RequestID)
+	})
+
+	t.Run("carries the distance unit and fares", func(t *testing.T) {
+		var meta EnvelopeMeta
+		require.NoError(t, json.Unmarshal(decode(get("/nearby", map[string]string{EnvelopeHeader: "true"}))["meta"], &meta))
+		assert.Equal(t, "mi", meta.DistanceUnit)
+		assert.Equal(t, "partial", meta.Fares)
+
+		var other EnvelopeMeta
+		require.NoError(t, json.Unmarshal(decode(get("/driver", map[string]string{EnvelopeHeader: "true"}))["meta"], &other))
+		assert.Empty(t, other.DistanceUnit)
+		assert.Empty(t, other.Fares)
+	})
+
+	t.Run("wraps errors", func(t *testing.T) {
+		w := get("/missing", map[string]string{EnvelopeHeader: "true"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		envelope := decode(w)
+		assert.Equal(t, "null", string(envelope["data"]))
+		assert.JSONEq(t, `{"code":"NOT_FOUND","message":"driver not found"}`, string(envelope["error"]))
+	})
+
+	t.Run("per API key", func(t *testing.T) {
+		w := get("/driver", map[string]string{"X-API-Key": "partner-key"})
+		assert.JSONEq(t, `{"id":"d1"}`, string(decode(w)["data"]))
+
+		w = get("/driver", map[string]string{"X-API-Key": "partner-key", EnvelopeHeader: "false"})
+		assert.JSONEq(t, `{"id":"d1"}`, w.Body.String(), "the header overrides the key setting")
+	})
+
+	t.Run("leaves other content types alone", func(t *testing.T) {
+		w := get("/export", map[string]string{EnvelopeHeader: "true"})
+		assert.Equal(t, "id\nd1\n", w.Body.String())
+	})
+}
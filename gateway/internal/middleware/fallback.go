@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DegradedHeader marks stub responses served while the driver service is unavailable
+const DegradedHeader = "X-Degraded"
+
+// Fallback is the response a route answers with instead of an error while the
+// driver service is unavailable
+type Fallback struct {
+	// Status defaults to 200
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// DefaultFallbacks keep riders' driver search working with an empty list
+var DefaultFallbacks = map[string]Fallback{
+	"GET /drivers/nearby": {Status: http.StatusOK, Body: json.RawMessage(`[]`)},
+}
+
+// LoadFallbacks reads stubs by route from a JSON file such as
+// {"GET /drivers/nearby": {"status": 200, "body": []}}; an empty path returns
+// DefaultFallbacks
+func LoadFallbacks(path string) (map[string]Fallback, error) {
+	if path == "" {
+		return DefaultFallbacks, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upstream fallbacks: %w", err)
+	}
+	fallbacks, err := ParseFallbacks(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upstream fallbacks %s: %w", path, err)
+	}
+	return fallbacks, nil
+}
+
+// ParseFallbacks decodes and validates stubs by route
+func ParseFallbacks(data []byte) (map[string]Fallback, error) {
+	var fallbacks map[string]Fallback
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&fallbacks); err != nil {
+		return nil, err
+	}
+	for route, fallback := range fallbacks {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("route %q must be written as \"METHOD /path\"", route)
+		}
+		if fallback.Status == 0 {
+			fallback.Status = http.StatusOK
+		}
+		if fallback.Status < 200 || fallback.Status >= 500 {
+			return nil, fmt.Errorf("route %q: status %d is not a non-error response", route, fallback.Status)
+		}
+		if len(fallback.Body) == 0 {
+			return nil, fmt.Errorf("route %q has no body", route)
+		}
+		fallbacks[route] = fallback
+	}
+	return fallbacks, nil
+}
+
+// UpstreamFallback returns a middleware that answers the listed routes with
+// their stub, marked with DegradedHeader, when the handler fails with a 5xx:
+// the driver service shed the request, was unreachable, timed out or failed.
+// The original status is kept under "degradedStatus" for SLO tracking and the
+// response envelope. Register it after the gateway's own limits and
+// authorization so their errors reach clients unchanged.
+func UpstreamFallback(fallbacks map[string]Fallback, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fallback, ok := fallbacks[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		writer := &fallbackWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.failed == 0 {
+			return
+		}
+		logger.Warn("serving fallback response while driver service is unavailable",
+			zap.String("route", c.FullPath()),
+			zap.Int("status", writer.failed),
+		)
+		c.Set("degradedStatus", writer.failed)
+		header := c.Writer.Header()
+		header.Del("Content-Length")
+		header.Del("Content-Encoding")
+		header.Set("Content-Type", "application/json; charset=utf-8")
+		header.Set(DegradedHeader, "true")
+		// A stub must not outlive the outage in the client's cache
+		header.Set("Cache-Control", "no-store")
+		c.Writer.WriteHeader(fallback.Status)
+		c.Writer.Write(fallback.Body)
+	}
+}
+
+// fallbackWriter holds back 5xx responses so a stub can be written instead;
+// other responses pass through
+type fallbackWriter struct {
+	gin.ResponseWriter
+	// failed is the status of the response held back
+	failed int
+}
+
+func (w *fallbackWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && !w.ResponseWriter.Written() {
+		w.failed = code
+		return
+	}
+	w.failed = 0
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *fallbackWriter) WriteHeaderNow() {
+	if w.failed == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *fallbackWriter) Write(data []byte) (int, error) {
+	if w.failed != 0 {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *fallbackWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *fallbackWriter) Status() int {
+	if w.failed != 0 {
+		return w.failed
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *fallbackWriter) Written() bool {
+	return w.failed != 0 || w.ResponseWriter.Written()
+}
+
+func (w *fallbackWriter) Flush() {
+	if w.failed == 0 {
+		w.ResponseWriter.Flush()
+	}
+}
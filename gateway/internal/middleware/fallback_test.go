@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUpstreamFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	status := http.StatusOK
+	router := gin.New()
+	router.Use(ResponseEnvelope(config.EnvelopeConfig{}))
+	router.Use(UpstreamFallback(DefaultFallbacks, zap.NewNop()))
+	respond := func(c *gin.Context) {
+		if status >= http.StatusBadRequest {
+			c.Header("Retry-After", "5")
+			c.JSON(status, gin.H{"error": gin.H{"code": "SERVICE_UNAVAILABLE", "message": "driver service is overloaded"}})
+			return
+		}
+		c.JSON(status, []gin.H{{"id": "d1"}})
+	}
+	router.GET("/drivers/nearby", respond)
+	router.GET("/drivers/:id", respond)
+
+	get := func(path string, envelope bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if envelope {
+			req.Header.Set(EnvelopeHeader, "true")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("upstream healthy", func(t *testing.T) {
+		status = http.StatusOK
+		w := get("/drivers/nearby", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[{"id":"d1"}]`, w.Body.String())
+		assert.Empty(t, w.Header().Get(DegradedHeader))
+	})
+
+	t.Run("client errors pass through", func(t *testing.T) {
+		status = http.StatusBadRequest
+		w := get("/drivers/nearby", false)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get(DegradedHeader))
+	})
+
+	t.Run("upstream unavailable", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		w := get("/drivers/nearby", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `[]`, w.Body.String())
+		assert.Equal(t, "true", w.Header().Get(DegradedHeader))
+		assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("envelope marks degraded", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		w := get("/drivers/nearby", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var envelope Envelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+		assert.JSONEq(t, `[]`, string(envelope.Data))
+		assert.True(t, envelope.Meta.Degraded)
+	})
+
+	t.Run("route without fallback", func(t *testing.T) {
+		status = http.StatusBadGateway
+		w := get("/drivers/d1", false)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "SERVICE_UNAVAILABLE")
+		assert.Empty(t, w.Header().Get(DegradedHeader))
+	})
+}
+
+func TestLoadFallbacks(t *testing.T) {
+	fallbacks, err := LoadFallbacks("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultFallbacks, fallbacks)
+
+	path := filepath.Join(t.TempDir(), "fallbacks.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"GET /drivers/nearby": {"body": {"drivers": [], "degraded": true}},
+		"POST /trips/estimate": {"status": 202, "body": null}
+	}`), 0o600))
+	fallbacks, err = LoadFallbacks(path)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, fallbacks["GET /drivers/nearby"].Status)
+	assert.JSONEq(t, `{"drivers":[],"degraded":true}`, string(fallbacks["GET /drivers/nearby"].Body))
+	assert.Equal(t, http.StatusAccepted, fallbacks["POST /trips/estimate"].Status)
+
+	for name, data := range map[string]string{
+		"bad route":     `{"/drivers/nearby": {"body": []}}`,
+		"error status":  `{"GET /drivers/nearby": {"status": 503, "body": []}}`,
+		"missing body":  `{"GET /drivers/nearby": {"status": 200}}`,
+		"unknown field": `{"GET /drivers/nearby": {"body": [], "headers": {}}}`,
+	} {
+		_, err := ParseFallbacks([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/bitaksi/gateway/internal/lifecycle"
+	"github.com/gin-gonic/gin"
+)
+
+// InFlight returns a middleware that counts in-flight requests for draining.
+// Probes and admin calls are not counted, so a drain request does not wait on itself.
+func InFlight(tracker *lifecycle.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if path == "/health" || path == "/ready" || strings.HasPrefix(path, "/admin/") {
+			c.Next()
+			return
+		}
+
+		tracker.Begin()
+		defer tracker.End()
+		c.Next()
+	}
+}
@@ -3,78 +3,156 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/bitaksi/gateway/internal/token"
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
-// JWTAuth returns a middleware that validates JWT tokens
-func JWTAuth(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Skip JWT if disabled
-		if !cfg.JWT.Enabled {
-			c.Next()
-			return
-		}
+// jwtAuthenticator validates bearer tokens and keeps the replay window. Issuer,
+// audience and clock skew are checked by the token manager; with replay
+// protection enabled each "jti" is accepted once per replay window, so
+// Authorize creates one authenticator for every route.
+type jwtAuthenticator struct {
+	cfg    *config.Config
+	tokens *token.Manager
+	logger *zap.Logger
+	replay *replayGuard
+}
 
-		// Get token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "authorization header is required",
-				},
-			})
-			c.Abort()
-			return
-		}
+// newJWTAuthenticator creates an authenticator
+func newJWTAuthenticator(cfg *config.Config, tokens *token.Manager, logger *zap.Logger) *jwtAuthenticator {
+	auth := &jwtAuthenticator{cfg: cfg, tokens: tokens, logger: logger}
+	if cfg.JWT.ReplayProtection {
+		auth.replay = newReplayGuard(cfg.JWT.ReplayWindow)
+	}
+	return auth
+}
 
-		// Extract token from "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "invalid authorization header format",
-				},
-			})
-			c.Abort()
-			return
-		}
+// authenticate aborts the request unless it carries a valid token and reports
+// whether it may go on. The token's claims are set on the context. Every
+// request may while JWT is disabled.
+func (a *jwtAuthenticator) authenticate(c *gin.Context) bool {
+	// Skip JWT if disabled
+	if !a.cfg.JWT.Enabled {
+		return true
+	}
 
-		tokenString := parts[1]
+	// Get token from Authorization header
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "authorization header is required")
+		return false
+	}
 
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(cfg.JWT.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			logger.Debug("invalid token", zap.Error(err))
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":    "UNAUTHORIZED",
-					"message": "invalid or expired token",
-				},
-			})
-			c.Abort()
-			return
+	// Extract token from "Bearer <token>"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid authorization header format")
+		return false
+	}
+
+	tokenString := parts[1]
+
+	// Parse and validate token
+	claims, err := a.tokens.Parse(tokenString)
+	if err != nil {
+		a.logger.Debug("invalid token", zap.Error(err))
+		problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "invalid or expired token")
+		return false
+	}
+
+	if a.replay != nil {
+		if claims.ID == "" {
+			a.logger.Debug("token without jti rejected", zap.String("username", claims.Username))
+			problem.Abort(c, http.StatusUnauthorized, "UNAUTHORIZED", "token id (jti) is required")
+			return false
 		}
+		if a.replay.seen(claims.ID) {
+			a.logger.Warn("token replay rejected", zap.String("jti", claims.ID), zap.String("username", claims.Username))
+			problem.Abort(c, http.StatusUnauthorized, "TOKEN_REPLAYED", "token has already been used")
+			return false
+		}
+	}
+
+	setClaims(c, claims)
+	return true
+}
 
-		// Extract claims and set in context
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			if username, ok := claims["username"].(string); ok {
-				c.Set("username", username)
+// identify sets the claims of a valid bearer token on the context without
+// requiring one, so routes open to every caller can still shape responses by
+// role. Invalid tokens are ignored, and the replay window is left alone: the
+// token only identifies the caller here and grants no access.
+func (a *jwtAuthenticator) identify(c *gin.Context) {
+	if !a.cfg.JWT.Enabled {
+		return
+	}
+	tokenString, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		return
+	}
+	claims, err := a.tokens.Parse(tokenString)
+	if err != nil {
+		a.logger.Debug("ignoring invalid token on an open route", zap.Error(err))
+		return
+	}
+	setClaims(c, claims)
+}
+
+// setClaims sets a token's claims in the context
+func setClaims(c *gin.Context, claims *token.Claims) {
+	if claims.Username != "" {
+		c.Set("username", claims.Username)
+	}
+	if claims.Role != "" {
+		c.Set("role", claims.Role)
+		c.Set("fleetId", claims.FleetID)
+		c.Set("riderId", claims.RiderID)
+	}
+}
+
+// replayGuard remembers token IDs for a fixed window
+type replayGuard struct {
+	mu        sync.Mutex
+	window    time.Duration
+	used      map[string]time.Time
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func newReplayGuard(window time.Duration) *replayGuard {
+	if window <= 0 {
+		window = 5 * time.Minute
+	}
+	return &replayGuard{
+		window: window,
+		used:   make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// seen records the token ID and reports whether it was already used within the window
+func (g *replayGuard) seen(jti string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if now.Sub(g.lastPrune) >= g.window {
+		for id, expires := range g.used {
+			if !now.Before(expires) {
+				delete(g.used, id)
 			}
 		}
+		g.lastPrune = now
+	}
 
-		c.Next()
+	if expires, ok := g.used[jti]; ok && now.Before(expires) {
+		return true
 	}
+	g.used[jti] = now.Add(g.window)
+	return false
 }
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/policy"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuthorize_ReplayProtection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:           "test-secret",
+		Expiration:       time.Hour,
+		Enabled:          true,
+		ReplayProtection: true,
+		ReplayWindow:     time.Minute,
+	}}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+
+	p, err := policy.Parse([]byte(`{"default": "jwt", "routes": []}`))
+	require.NoError(t, err)
+
+	// Tokens used on one route are replays on every other
+	router := gin.New()
+	router.Use(Authorize(p, cfg, tokens, nil, zap.NewNop()))
+	router.GET("/a", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/b", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func(path, tokenString string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokenString)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tokenString, err := tokens.Issue("testuser")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, call("/a", tokenString).Code)
+
+	w := call("/b", tokenString)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "TOKEN_REPLAYED")
+
+	fresh, err := tokens.Issue("testuser")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, call("/b", fresh).Code)
+
+	withoutID, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": "testuser",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	w = call("/a", withoutID)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "jti")
+}
+
+func TestReplayGuard_Window(t *testing.T) {
+	now := time.Now()
+	guard := newReplayGuard(time.Minute)
+	guard.now = func() time.Time { return now }
+
+	assert.False(t, guard.seen("a"))
+	assert.True(t, guard.seen("a"))
+
+	now = now.Add(2 * time.Minute)
+	assert.False(t, guard.seen("a"), "ids are forgotten after the window")
+	assert.Len(t, guard.used, 1)
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaintenanceMessage is shown to clients when no message was set
+const DefaultMaintenanceMessage = "TaxiHub is undergoing planned maintenance, please try again shortly"
+
+// maintenancePath switches maintenance mode, so it is served even when other
+// admin routes are not
+const maintenancePath = "/admin/maintenance"
+
+// MaintenanceStatus describes the gateway's maintenance mode
+type MaintenanceStatus struct {
+	Enabled bool   `json:"enabled" example:"true"`
+	Message string `json:"message" example:"TaxiHub is undergoing planned maintenance, please try again shortly"`
+	// RetryAfterSec is the Retry-After sent to clients
+	RetryAfterSec int `json:"retryAfterSec" example:"300"`
+	// AllowAdmin keeps the /admin routes served during maintenance
+	AllowAdmin bool `json:"allowAdmin" example:"true"`
+	// Since is when maintenance mode was last switched on
+	Since *time.Time `json:"since,omitempty"`
+	// Rejected counts the requests answered with 503 since then
+	Rejected int64 `json:"rejected" example:"1520"`
+}
+
+// Maintenance answers every request but health checks with 503 while
+// maintenance mode is on, so planned driver service work shows clients a clear
+// message instead of upstream errors. The switch is kept in memory on this
+// instance and starts from the configured mode.
+type Maintenance struct {
+	mu         sync.RWMutex
+	enabled    bool
+	message    string
+	retryAfter time.Duration
+	allowAdmin bool
+	since      *time.Time
+	rejected   int64
+	now        func() time.Time
+}
+
+// NewMaintenance creates the maintenance switch in the configured mode
+func NewMaintenance(cfg config.MaintenanceConfig) *Maintenance {
+	m := &Maintenance{
+		retryAfter: cfg.RetryAfter,
+		allowAdmin: cfg.AllowAdmin,
+		now:        time.Now,
+	}
+	if cfg.Enabled {
+		m.Enable(cfg.Message, cfg.RetryAfter)
+	} else {
+		m.message = cfg.Message
+	}
+	return m
+}
+
+// Enable switches maintenance mode on. An empty message shows the default one
+// and a retryAfter of 0 or less keeps the current value.
+func (m *Maintenance) Enable(message string, retryAfter time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.enabled {
+		now := m.now()
+		m.since = &now
+		m.rejected = 0
+	}
+	m.enabled = true
+	m.message = message
+	if retryAfter > 0 {
+		m.retryAfter = retryAfter
+	}
+}
+
+// Disable switches maintenance mode off
+func (m *Maintenance) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.enabled = false
+}
+
+// Status reports the current maintenance mode
+func (m *Maintenance) Status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return MaintenanceStatus{
+		Enabled:       m.enabled,
+		Message:       m.messageLocked(),
+		RetryAfterSec: retryAfterSeconds(m.retryAfter),
+		AllowAdmin:    m.allowAdmin,
+		Since:         m.since,
+		Rejected:      m.rejected,
+	}
+}
+
+// Block returns a middleware that rejects requests while in maintenance.
+// Probes always pass so the orchestrator keeps the gateway in rotation, as
+// does /admin/maintenance so maintenance can be switched off again.
+func (m *Maintenance) Block() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if path == "/health" || path == "/ready" || path == maintenancePath {
+			c.Next()
+			return
+		}
+
+		m.mu.Lock()
+		if !m.enabled || (m.allowAdmin && strings.HasPrefix(path, "/admin/")) {
+			m.mu.Unlock()
+			c.Next()
+			return
+		}
+		m.rejected++
+		message := m.messageLocked()
+		retryAfter := retryAfterSeconds(m.retryAfter)
+		m.mu.Unlock()
+
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		problem.RespondWith(c, http.StatusServiceUnavailable, "MAINTENANCE", message, map[string]interface{}{
+			"retryAfterSec": retryAfter,
+		})
+		c.Abort()
+	}
+}
+
+func (m *Maintenance) messageLocked() string {
+	if m.message == "" {
+		return DefaultMaintenanceMessage
+	}
+	return m.message
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int(d.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(m *Maintenance) *gin.Engine {
+		router := gin.New()
+		router.Use(m.Block())
+		for _, path := range []string{"/health", "/api/v1/drivers", "/admin/usage", "/admin/maintenance"} {
+			router.GET(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+		}
+		return router
+	}
+	get := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("off", func(t *testing.T) {
+		router := newRouter(NewMaintenance(config.MaintenanceConfig{RetryAfter: time.Minute}))
+		assert.Equal(t, http.StatusOK, get(router, "/api/v1/drivers").Code)
+	})
+
+	t.Run("on", func(t *testing.T) {
+		m := NewMaintenance(config.MaintenanceConfig{Enabled: true, RetryAfter: 2 * time.Minute, AllowAdmin: true})
+		router := newRouter(m)
+
+		w := get(router, "/api/v1/drivers")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "120", w.Header().Get("Retry-After"))
+		var body struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+			RetryAfterSec int `json:"retryAfterSec"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "MAINTENANCE", body.Error.Code)
+		assert.Equal(t, DefaultMaintenanceMessage, body.Error.Message)
+		assert.Equal(t, 120, body.RetryAfterSec)
+
+		assert.Equal(t, http.StatusOK, get(router, "/health").Code)
+		assert.Equal(t, http.StatusOK, get(router, "/admin/usage").Code)
+
+		status := m.Status()
+		assert.True(t, status.Enabled)
+		assert.NotNil(t, status.Since)
+		assert.Equal(t, int64(1), status.Rejected)
+	})
+
+	t.Run("admin routes blocked", func(t *testing.T) {
+		m := NewMaintenance(config.MaintenanceConfig{Enabled: true, RetryAfter: time.Minute})
+		router := newRouter(m)
+
+		assert.Equal(t, http.StatusServiceUnavailable, get(router, "/admin/usage").Code)
+		// The switch itself stays reachable
+		assert.Equal(t, http.StatusOK, get(router, "/admin/maintenance").Code)
+	})
+
+	t.Run("switched at runtime", func(t *testing.T) {
+		m := NewMaintenance(config.MaintenanceConfig{RetryAfter: time.Minute})
+		router := newRouter(m)
+
+		m.Enable("driver database upgrade until 03:00", 10*time.Minute)
+		w := get(router, "/api/v1/drivers")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "600", w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), "driver database upgrade until 03:00")
+
+		m.Disable()
+		assert.Equal(t, http.StatusOK, get(router, "/api/v1/drivers").Code)
+		assert.False(t, m.Status().Enabled)
+	})
+}
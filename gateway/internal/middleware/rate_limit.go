@@ -1,22 +1,44 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/bitaksi/gateway/internal/token"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter implements a simple rate limiter
+// RateLimiter implements a token-bucket rate limiter. Callers with a valid JWT
+// or API key get a bucket of their own with the authenticated quota, so users
+// sharing an IP behind carrier NAT do not use up each other's requests; other
+// callers are limited per IP with the anonymous quota.
 type RateLimiter struct {
 	clients map[string]*clientLimiter
 	mu      sync.RWMutex
-	config  *config.RateLimitConfig
+	config  *config.Config
+	tokens  *token.Manager
 	logger  *zap.Logger
+
+	allowed  atomic.Int64
+	rejected atomic.Int64
+}
+
+// RateLimitStats is a snapshot of the rate limiter
+type RateLimitStats struct {
+	Enabled bool `json:"enabled" example:"true"`
+	// Clients is how many IPs, subjects and API keys have a bucket
+	Clients  int   `json:"clients" example:"3120"`
+	Allowed  int64 `json:"allowed" example:"1829310"`
+	Rejected int64 `json:"rejected" example:"412"`
 }
 
 type clientLimiter struct {
@@ -24,11 +46,13 @@ type clientLimiter struct {
 	lastSeen time.Time
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(cfg *config.RateLimitConfig, logger *zap.Logger) *RateLimiter {
+// NewRateLimiter creates a new rate limiter. tokens validates bearer tokens
+// when JWT auth is enabled; callers with invalid credentials count as anonymous.
+func NewRateLimiter(cfg *config.Config, tokens *token.Manager, logger *zap.Logger) *RateLimiter {
 	rl := &RateLimiter{
 		clients: make(map[string]*clientLimiter),
 		config:  cfg,
+		tokens:  tokens,
 		logger:  logger,
 	}
 
@@ -42,51 +66,89 @@ func NewRateLimiter(cfg *config.RateLimitConfig, logger *zap.Logger) *RateLimite
 func (rl *RateLimiter) Limit() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Skip rate limiting if disabled
-		if !rl.config.Enabled {
+		if !rl.config.RateLimit.Enabled {
 			c.Next()
 			return
 		}
 
-		// Get client identifier (IP address)
-		clientIP := c.ClientIP()
-
-		// Get or create limiter for this client
-		limiter := rl.getLimiter(clientIP)
+		client, authenticated := rl.identify(c)
+		limiter := rl.getLimiter(client, authenticated)
 
 		// Check if request is allowed
 		if !limiter.Allow() {
-			rl.logger.Warn("rate limit exceeded", zap.String("ip", clientIP))
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": gin.H{
-					"code":    "RATE_LIMIT_EXCEEDED",
-					"message": "too many requests, please try again later",
-				},
-			})
-			c.Abort()
+			rl.rejected.Add(1)
+			rl.logger.Warn("rate limit exceeded", zap.String("client", client), zap.String("ip", c.ClientIP()))
+			problem.Abort(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "too many requests, please try again later")
 			return
 		}
+		rl.allowed.Add(1)
 
 		c.Next()
 	}
 }
 
-func (rl *RateLimiter) getLimiter(clientIP string) *rate.Limiter {
+// Stats returns the limited and rejected requests since start
+func (rl *RateLimiter) Stats() RateLimitStats {
+	rl.mu.RLock()
+	clients := len(rl.clients)
+	rl.mu.RUnlock()
+
+	return RateLimitStats{
+		Enabled:  rl.config.RateLimit.Enabled,
+		Clients:  clients,
+		Allowed:  rl.allowed.Load(),
+		Rejected: rl.rejected.Load(),
+	}
+}
+
+// identify returns the bucket key of the caller: the JWT subject, else the API
+// key, else the client IP, and whether the caller is authenticated. Only valid
+// credentials count, so made-up ones cannot be used to get fresh buckets.
+func (rl *RateLimiter) identify(c *gin.Context) (string, bool) {
+	if rl.config.JWT.Enabled && rl.tokens != nil {
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			if claims, err := rl.tokens.Parse(bearer); err == nil {
+				subject, _ := claims.Raw["sub"].(string)
+				if subject == "" {
+					subject = claims.Username
+				}
+				if subject != "" {
+					return "sub:" + subject, true
+				}
+			}
+		}
+	}
+	if rl.config.APIKey.Enabled {
+		if apiKey := apiKeyFrom(c); apiKey != "" && isValidAPIKey(apiKey, rl.config.APIKey.Keys) {
+			// Hashed so the key never shows up in logs
+			sum := sha256.Sum256([]byte(apiKey))
+			return "key:" + hex.EncodeToString(sum[:8]), true
+		}
+	}
+	return "ip:" + c.ClientIP(), false
+}
+
+func (rl *RateLimiter) getLimiter(client string, authenticated bool) *rate.Limiter {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	client, exists := rl.clients[clientIP]
+	existing, exists := rl.clients[client]
 	if !exists {
 		// Create new limiter: requests per window
-		limiter := rate.NewLimiter(rate.Every(rl.config.Window/time.Duration(rl.config.Requests)), rl.config.Requests)
-		rl.clients[clientIP] = &clientLimiter{
+		requests, window := rl.config.RateLimit.Requests, rl.config.RateLimit.Window
+		if authenticated {
+			requests, window = rl.config.RateLimit.AuthenticatedRequests, rl.config.RateLimit.AuthenticatedWindow
+		}
+		limiter := rate.NewLimiter(rate.Every(window/time.Duration(requests)), requests)
+		rl.clients[client] = &clientLimiter{
 			limiter:  limiter,
 			lastSeen: time.Now(),
 		}
 		return limiter
 	}
 
-	client.lastSeen = time.Now()
-	return client.limiter
+	existing.lastSeen = time.Now()
+	return existing.limiter
 }
 
 // cleanup removes old clients that haven't been seen in a while
@@ -96,9 +158,9 @@ func (rl *RateLimiter) cleanup() {
 
 	for range ticker.C {
 		rl.mu.Lock()
-		for ip, client := range rl.clients {
+		for key, client := range rl.clients {
 			if time.Since(client.lastSeen) > 10*time.Minute {
-				delete(rl.clients, ip)
+				delete(rl.clients, key)
 			}
 		}
 		rl.mu.Unlock()
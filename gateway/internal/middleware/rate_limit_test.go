@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRateLimiter_QuotaPerSubject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{
+		JWT:    config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Enabled: true},
+		APIKey: config.APIKeyConfig{Enabled: true, Keys: []string{"partner-key-0123456789"}},
+		RateLimit: config.RateLimitConfig{
+			Enabled:               true,
+			Requests:              1,
+			Window:                time.Hour,
+			AuthenticatedRequests: 2,
+			AuthenticatedWindow:   time.Hour,
+		},
+	}
+	tokens, err := token.NewManager(cfg.JWT)
+	require.NoError(t, err)
+
+	limiter := NewRateLimiter(cfg, tokens, zap.NewNop())
+	router := gin.New()
+	router.Use(limiter.Limit())
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Every caller shares one IP, as behind carrier NAT
+	call := func(header, value string) int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	alice, err := tokens.Issue("alice")
+	require.NoError(t, err)
+	bob, err := tokens.Issue("bob")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, call("", ""))
+	assert.Equal(t, http.StatusTooManyRequests, call("", ""), "anonymous quota is per IP")
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, call("Authorization", "Bearer "+alice))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, call("Authorization", "Bearer "+alice), "authenticated quota is per subject")
+	assert.Equal(t, http.StatusOK, call("Authorization", "Bearer "+bob), "subjects behind one IP have their own buckets")
+
+	assert.Equal(t, http.StatusOK, call("X-API-Key", "partner-key-0123456789"))
+
+	// Invalid credentials fall back to the exhausted IP bucket
+	assert.Equal(t, http.StatusTooManyRequests, call("Authorization", "Bearer forged"))
+	assert.Equal(t, http.StatusTooManyRequests, call("X-API-Key", "made-up-key-0123456789"))
+
+	assert.Equal(t, RateLimitStats{Enabled: true, Clients: 4, Allowed: 5, Rejected: 4}, limiter.Stats())
+}
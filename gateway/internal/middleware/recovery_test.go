@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitaksi/driver-service/pkg/errreport"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordingReporter keeps the events reported to it
+type recordingReporter struct {
+	events []*errreport.Event
+}
+
+func (r *recordingReporter) Report(event *errreport.Event) { r.events = append(r.events, event) }
+func (r *recordingReporter) Flush(context.Context)         {}
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	reporter := &recordingReporter{}
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(Recovery(zap.New(core), reporter))
+	router.GET("/drivers/:id", func(c *gin.Context) {
+		var drivers []string
+		c.String(http.StatusOK, drivers[len(c.Param("id"))])
+	})
+	router.GET("/streamed", func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+		c.Writer.WriteHeaderNow()
+		panic("stream broke")
+	})
+
+	get := func(path, requestID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(RequestIDHeader, requestID)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	panicsTotal := expvar.Get("panics_total").(*expvar.Int)
+	totalBefore := panicsTotal.Value()
+	w := get("/drivers/42", "req-1")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "INTERNAL_ERROR", body.Error.Code)
+	assert.Equal(t, "an internal error occurred", body.Error.Message)
+
+	entries := logs.FilterMessage("panic recovered").All()
+	require.Len(t, entries, 1)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "req-1", fields["requestId"])
+	assert.Equal(t, "/drivers/:id", fields["route"])
+	assert.Contains(t, fields["panic"], "index out of range")
+	assert.Contains(t, fields["culprit"], "TestRecovery")
+	assert.Contains(t, fields["stack"], "recovery_test.go")
+	fingerprint, _ := fields["fingerprint"].(string)
+	assert.Len(t, fingerprint, 16)
+
+	require.Len(t, reporter.events, 1)
+	event := reporter.events[0]
+	assert.Equal(t, errreport.LevelFatal, event.Level)
+	assert.Equal(t, fingerprint, event.Fingerprint)
+	assert.Equal(t, map[string]string{"requestId": "req-1", "route": "/drivers/:id"}, event.Tags)
+	assert.Equal(t, &errreport.Request{Method: http.MethodGet, URL: "/drivers/42"}, event.Request)
+
+	// Another ID and message at the same place share the fingerprint
+	get("/drivers/7", "req-2")
+	entries = logs.FilterMessage("panic recovered").All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "req-2", entries[1].ContextMap()["requestId"])
+	assert.Equal(t, fingerprint, entries[1].ContextMap()["fingerprint"])
+	assert.Equal(t, totalBefore+2, panicsTotal.Value())
+	assert.Equal(t, "2", expvar.Get("panics_by_fingerprint").(*expvar.Map).Get(fingerprint).String())
+
+	// A response already started is cut short rather than replaced
+	w = get("/streamed", "req-3")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, totalBefore+3, panicsTotal.Value())
+}
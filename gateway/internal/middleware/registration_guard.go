@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/problem"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceFingerprintHeader carries the client's device fingerprint on registrations
+const DeviceFingerprintHeader = "X-Device-Fingerprint"
+
+// maxFingerprintLength bounds the fingerprints kept in memory
+const maxFingerprintLength = 128
+
+// dayLayout names the UTC day the caps apply to
+const dayLayout = "2006-01-02"
+
+// maxSecurityEvents bounds the events kept for GET /admin/security-events
+const maxSecurityEvents = 500
+
+// SecurityEventRegistrationLimit is recorded when a device or IP goes over its daily cap
+const SecurityEventRegistrationLimit = "registration_limit_exceeded"
+
+// SecurityEvent describes suspicious activity seen by the gateway. Repeated
+// rejections of the same device or IP on the same day update one event.
+type SecurityEvent struct {
+	Type string `json:"type" example:"registration_limit_exceeded"`
+	// Scope is the kind of account being created
+	Scope     string `json:"scope" example:"drivers" enums:"drivers,riders"`
+	Dimension string `json:"dimension" example:"device" enums:"device,ip"`
+	// Value is the device fingerprint or client IP that went over the limit
+	Value string `json:"value" example:"c0ffee42"`
+	Limit int    `json:"limit" example:"3"`
+	// Rejected counts the registrations refused today
+	Rejected  int       `json:"rejected" example:"4"`
+	IP        string    `json:"ip" example:"203.0.113.7"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// RegistrationGuard caps the accounts created per device fingerprint and per
+// client IP each UTC day. Attempts count while in progress and are released
+// when the registration fails, so only accounts actually created use up the cap.
+type RegistrationGuard struct {
+	mu     sync.Mutex
+	config config.RegistrationGuardConfig
+	logger *zap.Logger
+	now    func() time.Time
+
+	day    string
+	counts map[string]int
+	events map[string]*SecurityEvent
+	order  []string
+}
+
+// NewRegistrationGuard creates a registration guard
+func NewRegistrationGuard(cfg config.RegistrationGuardConfig, logger *zap.Logger) *RegistrationGuard {
+	return &RegistrationGuard{
+		config: cfg,
+		logger: logger,
+		now:    time.Now,
+		counts: make(map[string]int),
+		events: make(map[string]*SecurityEvent),
+	}
+}
+
+// Guard returns a middleware enforcing the daily caps on registrations of the
+// given scope, e.g. "drivers" or "riders"
+func (g *RegistrationGuard) Guard(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !g.config.Enabled {
+			c.Next()
+			return
+		}
+
+		device := c.GetHeader(DeviceFingerprintHeader)
+		if len(device) > maxFingerprintLength {
+			problem.Abort(c, http.StatusBadRequest, "VALIDATION_ERROR", DeviceFingerprintHeader+" is too long")
+			return
+		}
+		if device == "" && g.config.RequireDevice {
+			problem.Abort(c, http.StatusBadRequest, "DEVICE_FINGERPRINT_REQUIRED", DeviceFingerprintHeader+" header is required")
+			return
+		}
+
+		ip := c.ClientIP()
+		keys, ok := g.reserve(scope, device, ip)
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(g.secondsUntilReset()))
+			problem.Abort(c, http.StatusTooManyRequests, "REGISTRATION_LIMIT_EXCEEDED", "too many registrations from this device or network today")
+			return
+		}
+
+		c.Next()
+
+		if status := c.Writer.Status(); status < 200 || status >= 300 {
+			g.release(keys)
+		}
+	}
+}
+
+// reserve counts a registration against the device and IP, or records a
+// security event and refuses it when either is at its cap
+func (g *RegistrationGuard) reserve(scope, device, ip string) ([]string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+
+	type check struct {
+		dimension, value string
+		limit            int
+	}
+	checks := []check{{"ip", ip, g.config.MaxPerIP}}
+	if device != "" {
+		checks = append([]check{{"device", device, g.config.MaxPerDevice}}, checks...)
+	}
+
+	keys := make([]string, 0, len(checks))
+	for _, ch := range checks {
+		if ch.limit <= 0 {
+			continue
+		}
+		key := scope + "|" + ch.dimension + "|" + ch.value
+		if g.counts[key] >= ch.limit {
+			g.record(key, scope, ch.dimension, ch.value, ch.limit, ip)
+			return nil, false
+		}
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		g.counts[key]++
+	}
+	return keys, true
+}
+
+// release gives back the counts of a registration that did not create an account
+func (g *RegistrationGuard) release(keys []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Counts of a registration that straddled midnight are already gone
+	if g.day != g.now().UTC().Format(dayLayout) {
+		return
+	}
+	for _, key := range keys {
+		if g.counts[key] > 0 {
+			g.counts[key]--
+		}
+	}
+}
+
+// record notes a rejection; the first one of a key each day is logged as a security event
+func (g *RegistrationGuard) record(key, scope, dimension, value string, limit int, ip string) {
+	now := g.now().UTC()
+	eventKey := g.day + "|" + key
+	if event, ok := g.events[eventKey]; ok {
+		event.Rejected++
+		event.LastSeen = now
+		event.IP = ip
+		return
+	}
+
+	g.events[eventKey] = &SecurityEvent{
+		Type:      SecurityEventRegistrationLimit,
+		Scope:     scope,
+		Dimension: dimension,
+		Value:     value,
+		Limit:     limit,
+		Rejected:  1,
+		IP:        ip,
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+	g.order = append(g.order, eventKey)
+	if len(g.order) > maxSecurityEvents {
+		delete(g.events, g.order[0])
+		g.order = g.order[1:]
+	}
+
+	g.logger.Warn("security event",
+		zap.String("event", SecurityEventRegistrationLimit),
+		zap.String("scope", scope),
+		zap.String("dimension", dimension),
+		zap.String("value", value),
+		zap.Int("limit", limit),
+		zap.String("ip", ip),
+	)
+}
+
+// rollover resets the counts when the UTC day changes; events are kept
+func (g *RegistrationGuard) rollover() {
+	day := g.now().UTC().Format(dayLayout)
+	if day != g.day {
+		g.day = day
+		g.counts = make(map[string]int)
+	}
+}
+
+// secondsUntilReset is the time left until the caps reset at UTC midnight
+func (g *RegistrationGuard) secondsUntilReset() int {
+	now := g.now().UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return int(midnight.Sub(now).Round(time.Second) / time.Second)
+}
+
+// Events returns the recorded security events, newest first
+func (g *RegistrationGuard) Events() []SecurityEvent {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Walk the events newest first so ties keep that order
+	events := make([]SecurityEvent, 0, len(g.order))
+	for i := len(g.order) - 1; i >= 0; i-- {
+		events = append(events, *g.events[g.order[i]])
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastSeen.After(events[j].LastSeen)
+	})
+	return events
+}
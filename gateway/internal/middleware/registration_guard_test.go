@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRegistrationGuard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	guard := NewRegistrationGuard(config.RegistrationGuardConfig{
+		Enabled:      true,
+		MaxPerDevice: 2,
+		MaxPerIP:     3,
+	}, zap.NewNop())
+	guard.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.POST("/drivers", guard.Guard("drivers"), func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	router.POST("/riders", guard.Guard("riders"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	register := func(path, device, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		if device != "" {
+			req.Header.Set(DeviceFingerprintHeader, device)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Failed registrations do not use up the cap
+	assert.Equal(t, http.StatusBadRequest, register("/drivers?fail=1", "device-a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusCreated, register("/drivers", "device-a", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusCreated, register("/drivers", "device-a", "10.0.0.1").Code)
+
+	rejected := register("/drivers", "device-a", "10.0.0.2")
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "3600", rejected.Header().Get("Retry-After"))
+	assert.Contains(t, rejected.Body.String(), "REGISTRATION_LIMIT_EXCEEDED")
+	assert.Equal(t, http.StatusTooManyRequests, register("/drivers", "device-a", "10.0.0.3").Code)
+
+	// Riders are counted separately from drivers
+	assert.Equal(t, http.StatusCreated, register("/riders", "device-a", "10.0.0.1").Code)
+
+	// The IP cap also holds for new devices and requests without a fingerprint
+	assert.Equal(t, http.StatusCreated, register("/drivers", "", "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, register("/drivers", "device-b", "10.0.0.1").Code)
+
+	events := guard.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "ip", events[0].Dimension)
+	assert.Equal(t, "10.0.0.1", events[0].Value)
+	assert.Equal(t, "device", events[1].Dimension)
+	assert.Equal(t, "device-a", events[1].Value)
+	assert.Equal(t, SecurityEventRegistrationLimit, events[1].Type)
+	assert.Equal(t, "drivers", events[1].Scope)
+	assert.Equal(t, 2, events[1].Limit)
+	assert.Equal(t, 2, events[1].Rejected)
+	assert.Equal(t, "10.0.0.3", events[1].IP)
+
+	// Caps reset at midnight UTC
+	now = now.Add(2 * time.Hour)
+	assert.Equal(t, http.StatusCreated, register("/drivers", "device-a", "10.0.0.1").Code)
+}
+
+func TestRegistrationGuard_Fingerprint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	guard := NewRegistrationGuard(config.RegistrationGuardConfig{
+		Enabled:       true,
+		MaxPerDevice:  1,
+		RequireDevice: true,
+	}, zap.NewNop())
+	router := gin.New()
+	router.POST("/riders", guard.Guard("riders"), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/riders", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "DEVICE_FINGERPRINT_REQUIRED")
+
+	req := httptest.NewRequest(http.MethodPost, "/riders", nil)
+	req.Header.Set(DeviceFingerprintHeader, string(make([]byte, maxFingerprintLength+1)))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRegistrationGuard_ForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(trusted []string) *gin.Engine {
+		guard := NewRegistrationGuard(config.RegistrationGuardConfig{Enabled: true, MaxPerIP: 1}, zap.NewNop())
+		router := gin.New()
+		require.NoError(t, router.SetTrustedProxies(trusted))
+		router.POST("/riders", guard.Guard("riders"), func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+		return router
+	}
+	register := func(router *gin.Engine, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/riders", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without trusted proxies a spoofed header does not give a new IP
+	router := newRouter(nil)
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusTooManyRequests, register(router, "203.0.113.2"))
+
+	// Behind a trusted proxy the forwarded client IP is counted
+	router = newRouter([]string{"10.0.0.0/8"})
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.1"))
+	assert.Equal(t, http.StatusCreated, register(router, "203.0.113.2"))
+	assert.Equal(t, http.StatusTooManyRequests, register(router, "203.0.113.2"))
+}
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/bitaksi/gateway/internal/slo"
+	"github.com/gin-gonic/gin"
+)
+
+// SLO returns a middleware that counts requests to routes with a service level
+// objective towards their availability and latency indicators. Fallback stubs
+// count with the status of the failure they replaced.
+func SLO(tracker *slo.Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		status := c.Writer.Status()
+		if degraded := c.GetInt("degradedStatus"); degraded != 0 {
+			status = degraded
+		}
+		tracker.Record(c.Request.Method, c.FullPath(), status, time.Since(start))
+	}
+}
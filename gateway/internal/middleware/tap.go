@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/tap"
+	"github.com/gin-gonic/gin"
+)
+
+// redactedHeaders are never written to captures
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"X-Api-Key":     true,
+	"X-Admin-Token": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// Tap returns a middleware that records requests selected by an active debug tap.
+// Untapped requests only pay for a single atomic load.
+func Tap(registry *tap.Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, ok := registry.Match(c.FullPath(), c.Request.URL.Path, c.GetString("requestID"))
+		if !ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		reqBody, reqTruncated := peekBody(c.Request, t.MaxBodyBytes)
+		writer := &captureWriter{ResponseWriter: c.Writer, limit: t.MaxBodyBytes}
+		c.Writer = writer
+
+		c.Next()
+
+		registry.Record(tap.Capture{
+			TapID:             t.ID,
+			RequestID:         c.GetString("requestID"),
+			Method:            c.Request.Method,
+			Path:              c.Request.URL.RequestURI(),
+			Route:             c.FullPath(),
+			Status:            writer.Status(),
+			LatencyMs:         float64(time.Since(start).Microseconds()) / 1000,
+			RequestHeaders:    flattenHeaders(c.Request.Header),
+			RequestBody:       reqBody,
+			RequestTruncated:  reqTruncated,
+			ResponseHeaders:   flattenHeaders(writer.Header()),
+			ResponseBody:      writer.body.String(),
+			ResponseTruncated: writer.truncated,
+		})
+	}
+}
+
+// peekBody reads up to limit bytes of the request body and puts them back so
+// handlers still see the full body
+func peekBody(req *http.Request, limit int) (string, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", false
+	}
+	prefix, _ := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(prefix), req.Body), Closer: req.Body}
+
+	if len(prefix) > limit {
+		return string(prefix[:limit]), true
+	}
+	return string(prefix), false
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter copies the first limit bytes of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *captureWriter) capture(data []byte) {
+	remaining := w.limit - w.body.Len()
+	if remaining <= 0 {
+		w.truncated = w.truncated || len(data) > 0
+		return
+	}
+	if len(data) > remaining {
+		data = data[:remaining]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
+
+// flattenHeaders joins multi-value headers and redacts credentials
+func flattenHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(name)] {
+			flat[name] = "[REDACTED]"
+			continue
+		}
+		flat[name] = strings.Join(values, ", ")
+	}
+	return flat
+}
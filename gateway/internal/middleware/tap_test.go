@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitaksi/gateway/internal/tap"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	registry := tap.NewRegistry(tap.Options{})
+	tp, err := registry.Add(tap.Spec{Route: "/drivers/:id", MaxBodyBytes: 8})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(RequestID(), Tap(registry))
+	router.PUT("/drivers/:id", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "echo:"+string(body))
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("PUT", "/drivers/42", strings.NewReader(`{"firstName":"Ahmet"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// The handler still sees the whole body
+	assert.Equal(t, `echo:{"firstName":"Ahmet"}`, w.Body.String())
+	assert.Equal(t, "req-1", w.Header().Get(RequestIDHeader))
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/health", nil))
+
+	captures := registry.Captures(tp.ID)
+	require.Len(t, captures, 1)
+	capture := captures[0]
+	assert.Equal(t, "req-1", capture.RequestID)
+	assert.Equal(t, "/drivers/:id", capture.Route)
+	assert.Equal(t, http.StatusOK, capture.Status)
+	assert.Equal(t, `{"firstN`, capture.RequestBody)
+	assert.True(t, capture.RequestTruncated)
+	assert.Equal(t, `echo:{"f`, capture.ResponseBody)
+	assert.True(t, capture.ResponseTruncated)
+	assert.Equal(t, "[REDACTED]", capture.RequestHeaders["Authorization"])
+}
+
+func TestRequestID_Generated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/health", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("requestID"))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+	assert.Len(t, w.Header().Get(RequestIDHeader), 32)
+	assert.Equal(t, w.Header().Get(RequestIDHeader), w.Body.String())
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/bitaksi/gateway/internal/redact"
+	"github.com/bitaksi/gateway/internal/schema"
+	"github.com/gin-gonic/gin"
+)
+
+// UpstreamErrors returns a middleware that stores how driver service errors are
+// rendered under "upstreamErrors", where proxying handlers pick it up
+func UpstreamErrors(cfg config.UpstreamErrorsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("upstreamErrors", cfg)
+		c.Next()
+	}
+}
+
+// ResponseValidation returns a middleware that stores the validator of driver
+// service responses under "responseValidator", where proxying handlers pick it up
+func ResponseValidation(validator *schema.Validator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("responseValidator", validator)
+		c.Next()
+	}
+}
+
+// ResponseRedaction returns a middleware that stores the redactor of driver
+// service responses under "responseRedactor", where proxying handlers pick it up
+func ResponseRedaction(redactor *redact.Redactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("responseRedactor", redactor)
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/bitaksi/gateway/internal/usage"
+	"github.com/gin-gonic/gin"
+)
+
+// Usage returns a middleware that meters requests per tenant, subject and
+// route. It reads the identity set by the auth middlewares after the request
+// is handled, so it must be registered before them.
+func Usage(meter *usage.Meter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		meter.Record(c.GetString("fleetId"), usageSubject(c), c.Request.Method, route, c.Writer.Status())
+	}
+}
+
+// usageSubject identifies the caller: the JWT user, else the masked API key
+func usageSubject(c *gin.Context) string {
+	if username := c.GetString("username"); username != "" {
+		return "user:" + username
+	}
+	if key := c.GetString("api_key"); key != "" {
+		return "key:" + key
+	}
+	return "anonymous"
+}
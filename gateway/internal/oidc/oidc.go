@@ -0,0 +1,404 @@
+// Package oidc signs users in with an external OpenID Connect provider using
+// the authorization code flow with PKCE (RFC 7636).
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrUnavailable is returned when the provider cannot be reached or fails
+	ErrUnavailable = errors.New("identity provider unavailable")
+	// ErrRejected is returned when the provider refuses the authorization code
+	ErrRejected = errors.New("identity provider rejected the authorization code")
+	// ErrInvalidIDToken is returned for ID tokens that fail validation
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	// ErrInvalidState is returned for login state that was tampered with or expired
+	ErrInvalidState = errors.New("invalid or expired login state")
+)
+
+// metadataTTL is how long discovered endpoints and signing keys are reused
+const metadataTTL = time.Hour
+
+// clockSkew is the leeway applied to the time claims of ID tokens
+const clockSkew = time.Minute
+
+// metadata is the part of the provider's discovery document the flow needs
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider runs logins against one OpenID Connect provider
+type Provider struct {
+	cfg    config.OIDCConfig
+	client *http.Client
+	secret []byte
+
+	mu        sync.Mutex
+	meta      *metadata
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+
+	now func() time.Time
+}
+
+// NewProvider creates a provider from the OIDC configuration. Metadata is
+// discovered on first use, so the gateway starts while the provider is down.
+func NewProvider(cfg config.OIDCConfig) (*Provider, error) {
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required with OIDC_ISSUER")
+	}
+	if cfg.LoginTimeout <= 0 {
+		cfg.LoginTimeout = 10 * time.Minute
+	}
+
+	secret := []byte(cfg.StateSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate OIDC state secret: %w", err)
+		}
+	}
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+		secret: secret,
+		now:    time.Now,
+	}, nil
+}
+
+// Login is a sign-in in progress; it travels in a signed cookie between the
+// redirect to the provider and the callback
+type Login struct {
+	State     string `json:"s"`
+	Nonce     string `json:"n"`
+	Verifier  string `json:"v"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// Issuer is the provider's issuer URL
+func (p *Provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+// LoginTimeout is how long a login may take
+func (p *Provider) LoginTimeout() time.Duration {
+	return p.cfg.LoginTimeout
+}
+
+// Start begins a login and returns the provider URL to send the user to
+func (p *Provider) Start(ctx context.Context) (*Login, string, error) {
+	meta, err := p.metadata(ctx, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	login := &Login{
+		State:     randomString(),
+		Nonce:     randomString(),
+		Verifier:  randomString(),
+		ExpiresAt: p.now().Add(p.cfg.LoginTimeout).Unix(),
+	}
+	challenge := sha256.Sum256([]byte(login.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.scopes(), " ")},
+		"state":                 {login.State},
+		"nonce":                 {login.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	authURL := meta.AuthorizationEndpoint
+	if strings.Contains(authURL, "?") {
+		authURL += "&" + query.Encode()
+	} else {
+		authURL += "?" + query.Encode()
+	}
+	return login, authURL, nil
+}
+
+// scopes always asks for openid, without which no ID token is issued
+func (p *Provider) scopes() []string {
+	for _, scope := range p.cfg.Scopes {
+		if scope == "openid" {
+			return p.cfg.Scopes
+		}
+	}
+	return append([]string{"openid"}, p.cfg.Scopes...)
+}
+
+// Identity is a user the provider signed in
+type Identity struct {
+	Subject string
+	// Username is the configured username claim, or the subject without one
+	Username string
+	Claims   jwt.MapClaims
+}
+
+// Authenticate redeems the authorization code of the login and validates the
+// ID token the provider returns for it
+func (p *Provider) Authenticate(ctx context.Context, code string, login *Login) (*Identity, error) {
+	meta, err := p.metadata(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	rawIDToken, err := p.exchange(ctx, meta, code, login.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	return p.verify(ctx, meta, rawIDToken, login.Nonce)
+}
+
+// tokenResponse is the provider's answer to a code exchange
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange redeems the code at the token endpoint and returns the raw ID token.
+// Confidential clients authenticate with client_secret_basic.
+func (p *Provider) exchange(ctx context.Context, meta *metadata, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil && resp.StatusCode < 500 {
+		return "", fmt.Errorf("%w: malformed token response: %v", ErrUnavailable, err)
+	}
+	switch {
+	case resp.StatusCode >= 500:
+		return "", fmt.Errorf("%w: token endpoint answered %d", ErrUnavailable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("%w: %s %s", ErrRejected, body.Error, body.ErrorDescription)
+	case body.IDToken == "":
+		return "", fmt.Errorf("%w: token response has no id_token", ErrInvalidIDToken)
+	}
+	return body.IDToken, nil
+}
+
+// verify checks the ID token's signature, issuer, audience, lifetime and nonce
+func (p *Provider) verify(ctx context.Context, meta *metadata, rawIDToken, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		if errors.Is(err, ErrUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// A token issued to several clients names the one it was issued for
+	if azp, ok := claims["azp"].(string); ok && azp != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: issued for %q", ErrInvalidIDToken, azp)
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	subject, _ := claims.GetSubject()
+	if subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+
+	identity := &Identity{Subject: subject, Username: subject, Claims: claims}
+	if username, _ := claims[p.cfg.UsernameClaim].(string); username != "" {
+		identity.Username = username
+	}
+	return identity, nil
+}
+
+// Access returns the access granted by the identity's role claim. Full access
+// wins; otherwise the first mapped value in claim order applies.
+func (p *Provider) Access(identity *Identity) (config.OIDCRole, bool) {
+	var values []string
+	switch claim := identity.Claims[p.cfg.RoleClaim].(type) {
+	case string:
+		values = []string{claim}
+	case []interface{}:
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var granted config.OIDCRole
+	found := false
+	for _, value := range values {
+		role, ok := p.cfg.Roles[value]
+		if !ok {
+			continue
+		}
+		if role.Role == "" {
+			return role, true
+		}
+		if !found {
+			granted, found = role, true
+		}
+	}
+	return granted, found
+}
+
+// metadata returns the discovered provider metadata, fetching it and the
+// signing keys again once they are older than metadataTTL or when forced
+func (p *Provider) metadata(ctx context.Context, force bool) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil && !force && p.now().Sub(p.fetchedAt) < metadataTTL {
+		return p.meta, nil
+	}
+
+	var meta metadata
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, err
+	}
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: discovery names issuer %q", ErrUnavailable, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery document is incomplete", ErrUnavailable)
+	}
+	keys, err := p.fetchKeys(ctx, meta.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.meta, p.keys, p.fetchedAt = &meta, keys, p.now()
+	return p.meta, nil
+}
+
+// key returns the signing key with the ID, fetching the keys again once for
+// an unknown ID so rotated keys are picked up
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	if _, err := p.metadata(ctx, true); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// jwk is an entry of the provider's key set; only RSA signing keys are used
+type jwk struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetchKeys loads the provider's RSA signing keys by key ID
+func (p *Provider) fetchKeys(ctx context.Context, jwksURI string) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := p.getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, target string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s answered %d", ErrUnavailable, target, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("%w: malformed response from %s: %v", ErrUnavailable, target, err)
+	}
+	return nil
+}
+
+// randomString returns 32 random bytes, base64url encoded; as a PKCE verifier
+// that is the recommended 43 characters
+func randomString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
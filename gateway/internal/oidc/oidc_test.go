@@ -0,0 +1,234 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is an identity provider that issues an ID token with claims
+// for the code "good-code" when the PKCE verifier matches the challenge
+type fakeProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	kid       string
+	claims    jwt.MapClaims
+	challenge string
+	nonce     string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := &fakeProvider{key: key, kid: "idp-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.server.URL,
+			"authorization_endpoint": f.server.URL + "/authorize",
+			"token_endpoint":         f.server.URL + "/token",
+			"jwks_uri":               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"kid": f.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(f.key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.PublicKey.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != f.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":   f.server.URL,
+			"aud":   "gateway",
+			"sub":   "user-42",
+			"nonce": f.nonce,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+		for name, value := range f.claims {
+			claims[name] = value
+		}
+		idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		idToken.Header["kid"] = f.kid
+		signed, err := idToken.SignedString(f.key)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeProvider) config() config.OIDCConfig {
+	return config.OIDCConfig{
+		Issuer:        f.server.URL,
+		ClientID:      "gateway",
+		RedirectURL:   "https://gateway.bitaksi.com/auth/oidc/callback",
+		Scopes:        []string{"email"},
+		UsernameClaim: "email",
+		RoleClaim:     "groups",
+		Roles: map[string]config.OIDCRole{
+			"ops":     {},
+			"kadikoy": {Role: "fleet_admin", FleetID: "fleet-1"},
+		},
+	}
+}
+
+// authorize plays the user signing in: it records the challenge and nonce
+// the provider was sent
+func (f *fakeProvider) authorize(t *testing.T, authURL string) {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.Equal(t, f.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	f.challenge, f.nonce = query.Get("code_challenge"), query.Get("nonce")
+}
+
+func TestProvider_Login(t *testing.T) {
+	f := newFakeProvider(t)
+	f.claims = jwt.MapClaims{"email": "ayse@kadikoytaksi.com", "groups": []interface{}{"drivers", "kadikoy"}}
+	p, err := NewProvider(f.config())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	login, authURL, err := p.Start(ctx)
+	require.NoError(t, err)
+	f.authorize(t, authURL)
+
+	opened, err := p.Open(p.Seal(login))
+	require.NoError(t, err)
+	identity, err := p.Authenticate(ctx, "good-code", opened)
+	require.NoError(t, err)
+	assert.Equal(t, "user-42", identity.Subject)
+	assert.Equal(t, "ayse@kadikoytaksi.com", identity.Username)
+
+	role, ok := p.Access(identity)
+	assert.True(t, ok)
+	assert.Equal(t, config.OIDCRole{Role: "fleet_admin", FleetID: "fleet-1"}, role)
+
+	_, err = p.Authenticate(ctx, "bad-code", opened)
+	assert.ErrorIs(t, err, ErrRejected)
+}
+
+func TestProvider_RejectsInvalidIDTokens(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{name: "wrong audience", claims: jwt.MapClaims{"aud": "another-client"}},
+		{name: "wrong issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{name: "issued for another party", claims: jwt.MapClaims{"aud": []string{"gateway", "other"}, "azp": "other"}},
+		{name: "replayed nonce", claims: jwt.MapClaims{"nonce": "from-another-login"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeProvider(t)
+			f.claims = tt.claims
+			p, err := NewProvider(f.config())
+			require.NoError(t, err)
+
+			login, authURL, err := p.Start(context.Background())
+			require.NoError(t, err)
+			f.authorize(t, authURL)
+
+			_, err = p.Authenticate(context.Background(), "good-code", login)
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProvider_Unavailable(t *testing.T) {
+	f := newFakeProvider(t)
+	cfg := f.config()
+	f.server.Close()
+
+	p, err := NewProvider(cfg)
+	require.NoError(t, err)
+	_, _, err = p.Start(context.Background())
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestProvider_LoginState(t *testing.T) {
+	p, err := NewProvider(config.OIDCConfig{ClientID: "gateway", RedirectURL: "https://gateway.bitaksi.com/auth/oidc/callback", LoginTimeout: time.Minute})
+	require.NoError(t, err)
+	login := &Login{State: "state", Nonce: "nonce", Verifier: "verifier", ExpiresAt: time.Now().Add(time.Minute).Unix()}
+	sealed := p.Seal(login)
+
+	opened, err := p.Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, login, opened)
+
+	tampered := p.Seal(&Login{State: "forged", ExpiresAt: login.ExpiresAt})
+	_, err = p.Open(tampered[:len(tampered)-2] + sealed[len(sealed)-2:])
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	other, err := NewProvider(config.OIDCConfig{ClientID: "gateway", RedirectURL: "https://gateway.bitaksi.com/auth/oidc/callback"})
+	require.NoError(t, err)
+	_, err = other.Open(sealed)
+	assert.ErrorIs(t, err, ErrInvalidState, "secrets differ between generated providers")
+
+	p.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = p.Open(sealed)
+	assert.ErrorIs(t, err, ErrInvalidState, "expired")
+}
+
+func TestProvider_Access(t *testing.T) {
+	f := newFakeProvider(t)
+	p, err := NewProvider(f.config())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		groups interface{}
+		want   config.OIDCRole
+		ok     bool
+	}{
+		{name: "full access wins", groups: []interface{}{"kadikoy", "ops"}, want: config.OIDCRole{}, ok: true},
+		{name: "single value", groups: "kadikoy", want: config.OIDCRole{Role: "fleet_admin", FleetID: "fleet-1"}, ok: true},
+		{name: "unmapped", groups: []interface{}{"drivers"}},
+		{name: "no claim"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{}
+			if tt.groups != nil {
+				claims["groups"] = tt.groups
+			}
+			role, ok := p.Access(&Identity{Claims: claims})
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, role)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+// Seal encodes the login for a cookie, signed so it cannot be altered
+func (p *Provider) Seal(login *Login) string {
+	data, _ := json.Marshal(login)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + p.sign(payload)
+}
+
+// Open decodes a sealed login, rejecting altered and expired ones
+func (p *Provider) Open(value string) (*Login, error) {
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.sign(payload))) {
+		return nil, ErrInvalidState
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+
+	var login Login
+	if err := json.Unmarshal(data, &login); err != nil || p.now().Unix() > login.ExpiresAt {
+		return nil, ErrInvalidState
+	}
+	return &login, nil
+}
+
+func (p *Provider) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,249 @@
+// Package openapi compares and merges the Swagger 2.0 specs generated from the
+// services' annotations
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DriverServicePrefix is where driver-service operations and definitions are
+// placed in the merged spec, so they cannot collide with the gateway's
+const DriverServicePrefix = "driver-service"
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch"}
+
+// Diff lists the differences between a committed and a freshly generated
+// spec, one line per operation or definition, e.g. "+ GET /drivers/changes".
+// Lines start with "+" for additions, "-" for removals and "~" for changes.
+func Diff(committed, generated []byte) ([]string, error) {
+	var old, cur map[string]interface{}
+	if err := json.Unmarshal(committed, &old); err != nil {
+		return nil, fmt.Errorf("failed to parse committed spec: %w", err)
+	}
+	if err := json.Unmarshal(generated, &cur); err != nil {
+		return nil, fmt.Errorf("failed to parse generated spec: %w", err)
+	}
+
+	var changes []string
+	oldOps, curOps := operations(old), operations(cur)
+	changes = append(changes, diffMaps(oldOps, curOps, "")...)
+	changes = append(changes, diffMaps(object(old["definitions"]), object(cur["definitions"]), "definition ")...)
+
+	// Anything else, such as info or security definitions
+	for _, key := range unionKeys(old, cur) {
+		if key == "paths" || key == "definitions" {
+			continue
+		}
+		if !reflect.DeepEqual(old[key], cur[key]) {
+			changes = append(changes, "~ "+key)
+		}
+	}
+	return changes, nil
+}
+
+// Merge adds the driver service's operations and definitions to the gateway
+// spec. Driver service paths are listed under /driver-service with their base
+// path, tagged "driver-service", and their definitions are renamed with the
+// same prefix.
+func Merge(gateway, driverService []byte) ([]byte, error) {
+	var merged, upstream map[string]interface{}
+	if err := json.Unmarshal(gateway, &merged); err != nil {
+		return nil, fmt.Errorf("failed to parse gateway spec: %w", err)
+	}
+	if err := json.Unmarshal(driverService, &upstream); err != nil {
+		return nil, fmt.Errorf("failed to parse driver service spec: %w", err)
+	}
+
+	// Point references at the renamed definitions before copying anything over
+	upstream = renameRefs(upstream).(map[string]interface{})
+
+	paths := object(merged["paths"])
+	basePath, _ := upstream["basePath"].(string)
+	basePath = strings.TrimSuffix(basePath, "/")
+	for path, item := range object(upstream["paths"]) {
+		for _, operation := range object(item) {
+			if op, ok := operation.(map[string]interface{}); ok {
+				op["tags"] = []interface{}{DriverServicePrefix}
+			}
+		}
+		paths["/"+DriverServicePrefix+basePath+path] = item
+	}
+	merged["paths"] = paths
+
+	definitions := object(merged["definitions"])
+	for name, definition := range object(upstream["definitions"]) {
+		definitions[DriverServicePrefix+"."+name] = definition
+	}
+	merged["definitions"] = definitions
+
+	return json.Marshal(merged)
+}
+
+// Public removes the operations tagged with any of internalTags, paths left
+// without operations, and the definitions only those operations referenced, so
+// the spec can be shown to API consumers without listing internal routes.
+func Public(spec []byte, internalTags []string) ([]byte, error) {
+	var public map[string]interface{}
+	if err := json.Unmarshal(spec, &public); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	internal := make(map[string]bool, len(internalTags))
+	for _, tag := range internalTags {
+		internal[tag] = true
+	}
+
+	paths := object(public["paths"])
+	for path, item := range paths {
+		operations := object(item)
+		for _, method := range methods {
+			if op, ok := operations[method]; ok && tagged(object(op), internal) {
+				delete(operations, method)
+			}
+		}
+		if !hasOperation(operations) {
+			delete(paths, path)
+		}
+	}
+	public["paths"] = paths
+
+	// Keep the definitions reachable from the remaining paths, following
+	// references between definitions
+	definitions := object(public["definitions"])
+	reachable := make(map[string]bool)
+	queue := refs(paths, nil)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reachable[name] {
+			continue
+		}
+		reachable[name] = true
+		queue = refs(definitions[name], queue)
+	}
+	for name := range definitions {
+		if !reachable[name] {
+			delete(definitions, name)
+		}
+	}
+	if _, ok := public["definitions"]; ok {
+		public["definitions"] = definitions
+	}
+
+	return json.Marshal(public)
+}
+
+func tagged(op map[string]interface{}, tags map[string]bool) bool {
+	list, _ := op["tags"].([]interface{})
+	for _, tag := range list {
+		if name, ok := tag.(string); ok && tags[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOperation(item map[string]interface{}) bool {
+	for _, method := range methods {
+		if _, ok := item[method]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// refs appends the names of the definitions referenced anywhere in value
+func refs(value interface{}, names []string) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				if name, ok := strings.CutPrefix(ref, "#/definitions/"); ok {
+					names = append(names, name)
+				}
+				continue
+			}
+			names = refs(item, names)
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = refs(item, names)
+		}
+	}
+	return names
+}
+
+// operations flattens the paths of a spec into "METHOD /path" keys
+func operations(spec map[string]interface{}) map[string]interface{} {
+	ops := make(map[string]interface{})
+	for path, item := range object(spec["paths"]) {
+		for _, method := range methods {
+			if op, ok := object(item)[method]; ok {
+				ops[strings.ToUpper(method)+" "+path] = op
+			}
+		}
+	}
+	return ops
+}
+
+func diffMaps(old, cur map[string]interface{}, kind string) []string {
+	var changes []string
+	for _, key := range unionKeys(old, cur) {
+		before, inOld := old[key]
+		after, inCur := cur[key]
+		switch {
+		case !inOld:
+			changes = append(changes, "+ "+kind+key)
+		case !inCur:
+			changes = append(changes, "- "+kind+key)
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, "~ "+kind+key)
+		}
+	}
+	return changes
+}
+
+func unionKeys(maps ...map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func renameRefs(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if ref, ok := item.(string); ok && key == "$ref" {
+				v[key] = strings.Replace(ref, "#/definitions/", "#/definitions/"+DriverServicePrefix+".", 1)
+				continue
+			}
+			v[key] = renameRefs(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = renameRefs(item)
+		}
+	}
+	return value
+}
+
+// object returns value as a JSON object, or an empty one
+func object(value interface{}) map[string]interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	return make(map[string]interface{})
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	committed := `{
+		"info": {"title": "Gateway API"},
+		"paths": {
+			"/drivers": {"get": {"summary": "List drivers"}, "post": {"summary": "Create driver"}},
+			"/trips": {"get": {"summary": "List trips"}}
+		},
+		"definitions": {"Driver": {"type": "object"}, "Trip": {"type": "object"}}
+	}`
+	generated := `{
+		"info": {"title": "Gateway API"},
+		"paths": {
+			"/drivers": {"get": {"summary": "List active drivers"}, "post": {"summary": "Create driver"}},
+			"/drivers/changes": {"get": {"summary": "Get driver changes"}}
+		},
+		"definitions": {"Driver": {"type": "object"}, "DriverChanges": {"type": "object"}}
+	}`
+
+	changes, err := Diff([]byte(committed), []byte(generated))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"~ GET /drivers",
+		"+ GET /drivers/changes",
+		"- GET /trips",
+		"+ definition DriverChanges",
+		"- definition Trip",
+	}, changes)
+
+	changes, err = Diff([]byte(committed), []byte(committed))
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = Diff([]byte(committed), []byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestMerge(t *testing.T) {
+	gateway := `{
+		"swagger": "2.0",
+		"basePath": "/",
+		"paths": {"/drivers": {"get": {"tags": ["drivers"]}}},
+		"definitions": {"handler.ErrorResponse": {"type": "object"}}
+	}`
+	driverService := `{
+		"swagger": "2.0",
+		"basePath": "/api/v1",
+		"paths": {"/drivers/{id}": {"get": {"tags": ["drivers"], "responses": {"200": {"schema": {"$ref": "#/definitions/domain.Driver"}}}}}},
+		"definitions": {"handler.ErrorResponse": {"type": "object"}, "domain.Driver": {"type": "object"}}
+	}`
+
+	data, err := Merge([]byte(gateway), []byte(driverService))
+	require.NoError(t, err)
+
+	var merged struct {
+		BasePath    string                                       `json:"basePath"`
+		Paths       map[string]map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{}                       `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &merged))
+
+	assert.Equal(t, "/", merged.BasePath)
+	assert.Contains(t, merged.Paths, "/drivers")
+	op := merged.Paths["/driver-service/api/v1/drivers/{id}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, []interface{}{"driver-service"}, op["tags"])
+	assert.Equal(t, "#/definitions/driver-service.domain.Driver",
+		op["responses"].(map[string]interface{})["200"].(map[string]interface{})["schema"].(map[string]interface{})["$ref"])
+	assert.ElementsMatch(t, []string{"handler.ErrorResponse", "driver-service.handler.ErrorResponse", "driver-service.domain.Driver"}, keys(merged.Definitions))
+}
+
+func TestPublic(t *testing.T) {
+	spec := `{
+		"swagger": "2.0",
+		"paths": {
+			"/drivers": {
+				"get": {"tags": ["drivers"], "responses": {"200": {"schema": {"$ref": "#/definitions/handler.DriverListResponse"}}}},
+				"parameters": []
+			},
+			"/admin/taps": {
+				"get": {"tags": ["admin"], "responses": {"200": {"schema": {"$ref": "#/definitions/handler.TapListResponse"}}}}
+			},
+			"/admin/drain": {
+				"post": {"tags": ["admin"], "responses": {"401": {"schema": {"$ref": "#/definitions/handler.ErrorResponse"}}}}
+			}
+		},
+		"definitions": {
+			"handler.DriverListResponse": {"properties": {"drivers": {"items": {"$ref": "#/definitions/handler.Driver"}}}},
+			"handler.Driver": {"type": "object"},
+			"handler.TapListResponse": {"properties": {"taps": {"items": {"$ref": "#/definitions/tap.Tap"}}}},
+			"tap.Tap": {"type": "object"},
+			"handler.ErrorResponse": {"type": "object"}
+		}
+	}`
+
+	data, err := Public([]byte(spec), []string{"admin"})
+	require.NoError(t, err)
+
+	var public struct {
+		Paths       map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &public))
+
+	assert.ElementsMatch(t, []string{"/drivers"}, keys(public.Paths))
+	assert.ElementsMatch(t, []string{"handler.DriverListResponse", "handler.Driver"}, keys(public.Definitions))
+
+	// Without internal tags nothing is removed
+	data, err = Public([]byte(spec), nil)
+	require.NoError(t, err)
+	var full struct {
+		Paths       map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &full))
+	assert.Len(t, full.Paths, 3)
+	assert.Len(t, full.Definitions, 5)
+
+	_, err = Public([]byte("not json"), []string{"admin"})
+	assert.Error(t, err)
+}
+
+func keys(m map[string]interface{}) []string {
+	var out []string
+	for key := range m {
+		out = append(out, key)
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+// Package policy maps gateway routes to the authentication they require. The
+// policy is a JSON file so the gateway's security posture can be reviewed and
+// changed without code edits; without one the built-in policy applies, which
+// mirrors the routes' historical wiring.
+package policy
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Authentication a route can require
+const (
+	// AuthPublic lets every caller through
+	AuthPublic = "public"
+	// AuthAPIKey requires a valid API key while API keys are enabled
+	AuthAPIKey = "apikey"
+	// AuthJWT requires a valid bearer token while JWT is enabled
+	AuthJWT = "jwt"
+	// AuthAdmin requires the admin token
+	AuthAdmin = "admin"
+	// AuthDevice accepts a driver device token carrying the rule's scope for
+	// the driver in the :id parameter, and otherwise requires jwt
+	AuthDevice = "device"
+)
+
+// AnyMethod matches every HTTP method
+const AnyMethod = "*"
+
+//go:embed default.json
+var builtin []byte
+
+// Rule is the authentication of the routes matching Method and Path
+type Rule struct {
+	// Method is an HTTP method or "*" for all; GET rules also cover HEAD
+	Method string `json:"method"`
+	// Path is a route as registered, e.g. /drivers/:id; a trailing /* also
+	// matches the path itself and every route below it
+	Path string `json:"path"`
+	Auth string `json:"auth"`
+	// Roles limits jwt and device routes to tokens carrying one of these roles.
+	// Tokens without a role have full access and always pass; empty allows any role.
+	Roles []string `json:"roles,omitempty"`
+	// Scope is the device token scope a device route requires
+	Scope string `json:"scope,omitempty"`
+	// Description is for reviewers and not interpreted
+	Description string `json:"description,omitempty"`
+}
+
+// Policy is the route authentication of the gateway. Rules are tried in order
+// and the first match wins; routes no rule matches get Default.
+type Policy struct {
+	Default string `json:"default"`
+	Routes  []Rule `json:"routes"`
+}
+
+// Route is a registered method and path
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Default returns the built-in policy
+func Default() *Policy {
+	p, err := Parse(builtin)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in auth policy: %v", err))
+	}
+	return p
+}
+
+// Load reads the policy file at path; an empty path returns the built-in policy
+func Load(path string) (*Policy, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth policy: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse decodes and validates a policy
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return nil, err
+	}
+	if p.Default == "" {
+		p.Default = AuthJWT
+	}
+	if !validAuth(p.Default) {
+		return nil, fmt.Errorf("unknown default auth %q", p.Default)
+	}
+	if p.Default == AuthDevice {
+		return nil, fmt.Errorf("device auth needs a scope and cannot be the default")
+	}
+	for i := range p.Routes {
+		rule := &p.Routes[i]
+		rule.Method = strings.ToUpper(rule.Method)
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("route %d (%s %s): %w", i+1, rule.Method, rule.Path, err)
+		}
+	}
+	return &p, nil
+}
+
+// validate checks a rule is complete and consistent
+func (r *Rule) validate() error {
+	switch r.Method {
+	case AnyMethod, http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions:
+	default:
+		return fmt.Errorf("unknown method %q", r.Method)
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		return fmt.Errorf("path must start with /")
+	}
+	if !validAuth(r.Auth) {
+		return fmt.Errorf("unknown auth %q", r.Auth)
+	}
+	if len(r.Roles) > 0 && r.Auth != AuthJWT && r.Auth != AuthDevice {
+		return fmt.Errorf("roles only apply to jwt and device routes")
+	}
+	if (r.Scope != "") != (r.Auth == AuthDevice) {
+		return fmt.Errorf("device routes need a scope and only they take one")
+	}
+	if r.Auth == AuthDevice && !strings.Contains(r.Path, "/:id") {
+		return fmt.Errorf("device routes need an :id parameter naming the driver")
+	}
+	return nil
+}
+
+// validAuth reports whether auth is a known requirement
+func validAuth(auth string) bool {
+	return auth == AuthPublic || auth == AuthAPIKey || auth == AuthJWT || auth == AuthAdmin || auth == AuthDevice
+}
+
+// matches reports whether the rule covers the route
+func (r *Rule) matches(method, path string) bool {
+	// HEAD answers like GET, so GET rules cover it
+	if method == http.MethodHead && r.Method == http.MethodGet {
+		method = http.MethodGet
+	}
+	if r.Method != AnyMethod && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "/*"); ok {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+	return r.Path == path
+}
+
+// Resolve returns the rule for a registered route, or a rule carrying the
+// default when none matches
+func (p *Policy) Resolve(method, path string) Rule {
+	for _, rule := range p.Routes {
+		if rule.matches(method, path) {
+			return rule
+		}
+	}
+	return Rule{Method: method, Path: path, Auth: p.Default}
+}
+
+// Unused returns the rules no route matches first, typically a typo or a
+// route that was removed
+func (p *Policy) Unused(routes []Route) []Rule {
+	used := make([]bool, len(p.Routes))
+	for _, route := range routes {
+		for i, rule := range p.Routes {
+			if rule.matches(route.Method, route.Path) {
+				used[i] = true
+				break
+			}
+		}
+	}
+	var unused []Rule
+	for i, rule := range p.Routes {
+		if !used[i] {
+			unused = append(unused, rule)
+		}
+	}
+	return unused
+}
+
+// Allows reports whether a token role may call a route of the rule
+func (r Rule) Allows(role string) bool {
+	if role == "" || len(r.Roles) == 0 {
+		return true
+	}
+	for _, allowed := range r.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
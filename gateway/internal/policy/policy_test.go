@@ -0,0 +1,103 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "unknown auth", data: `{"routes":[{"method":"GET","path":"/a","auth":"oauth"}]}`, wantErr: `unknown auth "oauth"`},
+		{name: "unknown method", data: `{"routes":[{"method":"FETCH","path":"/a","auth":"public"}]}`, wantErr: `unknown method "FETCH"`},
+		{name: "relative path", data: `{"routes":[{"method":"GET","path":"a","auth":"public"}]}`, wantErr: "path must start with /"},
+		{name: "roles without jwt", data: `{"routes":[{"method":"GET","path":"/a","auth":"apikey","roles":["rider"]}]}`, wantErr: "roles only apply to jwt and device routes"},
+		{name: "device without scope", data: `{"routes":[{"method":"PUT","path":"/drivers/:id/location","auth":"device"}]}`, wantErr: "device routes need a scope"},
+		{name: "scope without device", data: `{"routes":[{"method":"PUT","path":"/drivers/:id","auth":"jwt","scope":"heartbeat"}]}`, wantErr: "device routes need a scope"},
+		{name: "device without id", data: `{"routes":[{"method":"GET","path":"/drivers","auth":"device","scope":"heartbeat"}]}`, wantErr: "need an :id parameter"},
+		{name: "unknown default", data: `{"default":"none"}`, wantErr: `unknown default auth "none"`},
+		{name: "device default", data: `{"default":"device"}`, wantErr: "cannot be the default"},
+		{name: "unknown field", data: `{"routes":[{"method":"GET","path":"/a","auth":"public","role":"rider"}]}`, wantErr: "unknown field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPolicy_Resolve(t *testing.T) {
+	p, err := Parse([]byte(`{
+		"default": "admin",
+		"routes": [
+			{"method": "get", "path": "/drivers/:id", "auth": "public"},
+			{"method": "*", "path": "/drivers/*", "auth": "jwt", "roles": ["fleet_admin"]}
+		]
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{"GET", "/drivers/:id", AuthPublic},
+		{"HEAD", "/drivers/:id", AuthPublic},
+		{"PUT", "/drivers/:id", AuthJWT},
+		{"POST", "/drivers", AuthJWT},
+		{"GET", "/drivers/:id/stats", AuthJWT},
+		{"GET", "/driversx", AuthAdmin},
+		{"GET", "/trips", AuthAdmin},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Resolve(tt.method, tt.path).Auth, "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestRule_Allows(t *testing.T) {
+	rule := Rule{Auth: AuthJWT, Roles: []string{"fleet_admin"}}
+	assert.True(t, rule.Allows(""), "tokens without a role have full access")
+	assert.True(t, rule.Allows("fleet_admin"))
+	assert.False(t, rule.Allows("rider"))
+	assert.True(t, Rule{Auth: AuthJWT}.Allows("rider"))
+}
+
+func TestPolicy_Unused(t *testing.T) {
+	p, err := Parse([]byte(`{"routes": [
+		{"method": "GET", "path": "/health", "auth": "public"},
+		{"method": "GET", "path": "/helth", "auth": "public"},
+		{"method": "*", "path": "/trips/*", "auth": "jwt"},
+		{"method": "POST", "path": "/trips", "auth": "public"}
+	]}`))
+	require.NoError(t, err)
+
+	unused := p.Unused([]Route{{"GET", "/health"}, {"POST", "/trips"}})
+	require.Len(t, unused, 2)
+	assert.Equal(t, "/helth", unused[0].Path)
+	// Shadowed by the earlier /trips/* rule
+	assert.Equal(t, "/trips", unused[1].Path)
+}
+
+func TestLoad(t *testing.T) {
+	p, err := Load("")
+	require.NoError(t, err)
+	assert.Equal(t, AuthJWT, p.Default)
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"routes":[{"method":"GET","path":"/health","auth":"apikey"}]}`), 0o600))
+	p, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, AuthAPIKey, p.Resolve("GET", "/health").Auth)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,231 @@
+// Package redact hides fields of driver payloads from callers whose role is
+// not meant to see them, e.g. driver plates and last names from riders, before
+// the gateway returns driver service responses.
+//
+// A policy lists, per role, the routes its rule applies to and the JSON fields
+// it omits or masks. Roles without a rule of their own, anonymous callers
+// included, get the rule of AnyRole, so new roles are redacted until they are
+// given one. Fields are matched by name at any depth, so a rule for "plate"
+// covers a list of drivers, a single driver and a driver nested in a trip
+// alike.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Anonymous is the role of callers without a token. Callers whose token
+// carries no role, such as dispatchers, are never redacted.
+const Anonymous = "anonymous"
+
+// AnyRole names the rule of roles that have none of their own
+const AnyRole = "*"
+
+// maskSuffix replaces all but the first character of a masked value
+const maskSuffix = "***"
+
+// Rule is what a role may not see. A rule without routes or fields, e.g.
+// {"fleet_admin": {}}, lets the role see every field instead of falling back
+// to the AnyRole rule.
+type Rule struct {
+	// Routes are "METHOD /path" as registered, e.g. "GET /drivers/:id"; empty
+	// applies the rule to every route
+	Routes []string `json:"routes,omitempty"`
+	// Omit removes these fields
+	Omit []string `json:"omit,omitempty"`
+	// Mask keeps the first character of these fields, e.g. "D***" for
+	// "Demir"; values other than strings become null
+	Mask []string `json:"mask,omitempty"`
+}
+
+// Policy maps roles to their rule
+type Policy map[string]Rule
+
+// DefaultPolicy shows riders, anonymous callers and any other role without a
+// rule drivers without their last name and plate, and without their contact
+// details, licence or onboarding documents, on every route that reads drivers.
+// Fleet admins see every field.
+var DefaultPolicy = Policy{
+	AnyRole: {
+		Routes: []string{"GET /drivers", "GET /drivers/changes", "GET /drivers/nearby", "POST /drivers/nearby/route", "GET /drivers/:id"},
+		Omit:   []string{"phone", "email", "license", "documents"},
+		Mask:   []string{"lastName", "plate"},
+	},
+	"fleet_admin": {},
+}
+
+// field actions
+const (
+	omit = iota + 1
+	mask
+)
+
+// rule is a validated Rule; a rule without fields redacts nothing
+type rule struct {
+	// routes is nil when the rule applies to every route
+	routes map[string]bool
+	fields map[string]int
+}
+
+// Redactor applies a policy to response bodies
+type Redactor struct {
+	rules map[string]rule
+}
+
+// New validates a policy and creates a redactor for it
+func New(policy Policy) (*Redactor, error) {
+	r := &Redactor{rules: make(map[string]rule, len(policy))}
+	for role, cfg := range policy {
+		if role == "" {
+			return nil, fmt.Errorf("a rule has no role; callers without a token are %q", Anonymous)
+		}
+		compiled := rule{fields: map[string]int{}}
+		if len(cfg.Routes) > 0 {
+			compiled.routes = make(map[string]bool, len(cfg.Routes))
+		}
+		for _, route := range cfg.Routes {
+			method, path, ok := strings.Cut(route, " ")
+			if !ok || method == "" || !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("role %s: route %q must be written as \"METHOD /path\"", role, route)
+			}
+			compiled.routes[route] = true
+		}
+		for action, fields := range map[int][]string{omit: cfg.Omit, mask: cfg.Mask} {
+			for _, field := range fields {
+				if field == "" {
+					return nil, fmt.Errorf("role %s: empty field name", role)
+				}
+				if _, ok := compiled.fields[field]; ok {
+					return nil, fmt.Errorf("role %s: field %q is listed twice", role, field)
+				}
+				compiled.fields[field] = action
+			}
+		}
+		if len(compiled.fields) == 0 && len(cfg.Routes) > 0 {
+			return nil, fmt.Errorf("role %s: no fields to omit or mask", role)
+		}
+		r.rules[role] = compiled
+	}
+	return r, nil
+}
+
+// Load reads a policy from a JSON file such as
+// {"rider": {"routes": ["GET /drivers/nearby"], "mask": ["plate"]}}; an empty
+// path uses DefaultPolicy
+func Load(path string) (*Redactor, error) {
+	if path == "" {
+		return New(DefaultPolicy)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction policy: %w", err)
+	}
+	var policy Policy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid redaction policy %s: %w", path, err)
+	}
+	r, err := New(policy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redaction policy %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ruleOf returns the rule of role, that of AnyRole when it has none, and
+// reports false for callers without a role
+func (r *Redactor) ruleOf(role string) (rule, bool) {
+	if role == "" {
+		return rule{}, false
+	}
+	if rule, ok := r.rules[role]; ok {
+		return rule, true
+	}
+	rule, ok := r.rules[AnyRole]
+	return rule, ok
+}
+
+// Applies reports whether role has fields redacted on route
+func (r *Redactor) Applies(role, route string) bool {
+	rule, ok := r.ruleOf(role)
+	return ok && len(rule.fields) > 0 && (rule.routes == nil || rule.routes[route])
+}
+
+// Covers reports whether any role has fields redacted on route, so responses
+// on it depend on the caller
+func (r *Redactor) Covers(route string) bool {
+	for _, rule := range r.rules {
+		if len(rule.fields) > 0 && (rule.routes == nil || rule.routes[route]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns body with the fields hidden from role on route omitted or
+// masked. Bodies the rule does not touch, and bodies that are not JSON, are
+// returned as they are.
+func (r *Redactor) Redact(role, route string, body []byte) []byte {
+	if !r.Applies(role, route) {
+		return body
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+	rule, _ := r.ruleOf(role)
+	if !rule.apply(value) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// apply redacts value in place and reports whether anything changed
+func (r rule) apply(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			switch r.fields[name] {
+			case omit:
+				delete(v, name)
+				changed = true
+			case mask:
+				v[name] = maskValue(field)
+				changed = true
+			default:
+				changed = r.apply(field) || changed
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			changed = r.apply(item) || changed
+		}
+	}
+	return changed
+}
+
+// maskValue keeps the first character of a string; other values become null
+func maskValue(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	if s == "" {
+		return s
+	}
+	first, _ := utf8.DecodeRuneInString(s)
+	return string(first) + maskSuffix
+}
@@ -0,0 +1,160 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nearby = `[{"driver":{"id":"d1","firstName":"Şükrü","lastName":"Demir","plate":"34ABC123","phone":"+905551234567","rating":4.8},"distanceKm":1.2},` +
+	`{"driver":{"id":"d2","firstName":"Ali","lastName":"","plate":"06AHM06"},"distanceKm":2.5}]`
+
+func TestRedactor_ByRole(t *testing.T) {
+	r, err := New(Policy{
+		"rider":   DefaultPolicy[AnyRole],
+		Anonymous: {Routes: []string{"GET /drivers/:id"}, Omit: []string{"phone"}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		role  string
+		route string
+		body  string
+		want  string
+	}{
+		{
+			name:  "rider on nearby",
+			role:  "rider",
+			route: "GET /drivers/nearby",
+			body:  nearby,
+			want: `[{"driver":{"id":"d1","firstName":"Şükrü","lastName":"D***","plate":"3***","rating":4.8},"distanceKm":1.2},` +
+				`{"driver":{"id":"d2","firstName":"Ali","lastName":"","plate":"0***"},"distanceKm":2.5}]`,
+		},
+		{
+			name:  "rider on a single driver",
+			role:  "rider",
+			route: "GET /drivers/:id",
+			body:  `{"id":"d1","lastName":"Işık","email":"a@example.com","location":{"lat":41.0,"lon":29.0}}`,
+			want:  `{"id":"d1","lastName":"I***","location":{"lat":41.0,"lon":29.0}}`,
+		},
+		{
+			name:  "rider on a route the rule does not list",
+			role:  "rider",
+			route: "GET /trips/:id",
+			body:  `{"id":"t1","rider":{"lastName":"Yılmaz"}}`,
+			want:  `{"id":"t1","rider":{"lastName":"Yılmaz"}}`,
+		},
+		{name: "dispatcher", role: "", route: "GET /drivers/nearby", body: nearby, want: nearby},
+		{name: "fleet admin", role: "fleet_admin", route: "GET /drivers/nearby", body: nearby, want: nearby},
+		{name: "anonymous on nearby", role: Anonymous, route: "GET /drivers/nearby", body: nearby, want: nearby},
+		{
+			name:  "anonymous on a single driver",
+			role:  Anonymous,
+			route: "GET /drivers/:id",
+			body:  `{"id":"d1","lastName":"Demir","phone":"+905551234567"}`,
+			want:  `{"id":"d1","lastName":"Demir"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.want, string(r.Redact(tt.role, tt.route, []byte(tt.body))))
+		})
+	}
+}
+
+func TestRedactor_DefaultPolicy(t *testing.T) {
+	r, err := New(DefaultPolicy)
+	require.NoError(t, err)
+
+	driver := `{"id":"d1","lastName":"Demir","plate":"34ABC123","phone":"+905551234567","email":"a@example.com",` +
+		`"license":{"number":"TR-1234567","class":"B","expiresAt":"2030-01-01T00:00:00Z"},"documents":[{"type":"license","url":"https://files.example.com/d1/license.jpg"}]}`
+	redacted := `{"id":"d1","lastName":"D***","plate":"3***"}`
+	tests := []struct {
+		name  string
+		role  string
+		route string
+		body  string
+		want  string
+	}{
+		{name: "anonymous on a single driver", role: Anonymous, route: "GET /drivers/:id", body: driver, want: redacted},
+		{name: "anonymous on nearby", role: Anonymous, route: "GET /drivers/nearby", body: "[" + driver + "]", want: "[" + redacted + "]"},
+		{name: "rider", role: "rider", route: "POST /drivers/nearby/route", body: "[" + driver + "]", want: "[" + redacted + "]"},
+		{name: "role without a rule", role: "driver_device", route: "GET /drivers/:id", body: driver, want: redacted},
+		{name: "fleet admin", role: "fleet_admin", route: "GET /drivers/:id", body: driver, want: driver},
+		{name: "dispatcher", role: "", route: "GET /drivers/:id", body: driver, want: driver},
+		{name: "anonymous on a list", role: Anonymous, route: "GET /drivers", body: `{"drivers":[` + driver + `],"total":1}`, want: `{"drivers":[` + redacted + `],"total":1}`},
+		{name: "anonymous on changes", role: Anonymous, route: "GET /drivers/changes", body: `{"changes":[{"driver":` + driver + `}]}`, want: `{"changes":[{"driver":` + redacted + `}]}`},
+		{name: "anonymous on a route the rule does not list", role: Anonymous, route: "GET /taxi-types", body: driver, want: driver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.JSONEq(t, tt.want, string(r.Redact(tt.role, tt.route, []byte(tt.body))))
+		})
+	}
+	assert.False(t, r.Applies("fleet_admin", "GET /drivers/nearby"))
+}
+
+func TestRedactor_KeepsUntouchedBodies(t *testing.T) {
+	r, err := New(DefaultPolicy)
+	require.NoError(t, err)
+
+	// Bodies without redacted fields keep their bytes, number formatting included
+	body := []byte(`{"id": "d1", "rating": 4.80}`)
+	assert.Equal(t, body, r.Redact("rider", "GET /drivers/:id", body))
+
+	body = []byte(`not json`)
+	assert.Equal(t, body, r.Redact("rider", "GET /drivers/:id", body))
+
+	// Values other than strings are masked as null
+	assert.JSONEq(t, `{"plate":null}`, string(r.Redact("rider", "GET /drivers/:id", []byte(`{"plate":34}`))))
+
+	assert.True(t, r.Covers("GET /drivers/nearby"))
+	assert.True(t, r.Covers("GET /drivers"))
+	assert.True(t, r.Covers("GET /drivers/changes"))
+	assert.False(t, r.Covers("GET /taxi-types"))
+}
+
+func TestNew_InvalidPolicies(t *testing.T) {
+	for name, policy := range map[string]Policy{
+		"empty role":      {"": {Omit: []string{"plate"}}},
+		"malformed route": {"rider": {Routes: []string{"/drivers/nearby"}, Omit: []string{"plate"}}},
+		"no fields":       {"rider": {Routes: []string{"GET /drivers/nearby"}}},
+		"empty field":     {"rider": {Mask: []string{""}}},
+		"field twice":     {"rider": {Omit: []string{"plate"}, Mask: []string{"plate"}}},
+	} {
+		_, err := New(policy)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	r, err := Load("")
+	require.NoError(t, err)
+	assert.True(t, r.Applies("rider", "GET /drivers/nearby"))
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "redaction.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"driver_device": {"mask": ["lastName"]}}`), 0o600))
+	r, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, r.Applies("driver_device", "GET /trips/:id"))
+	assert.False(t, r.Applies("rider", "GET /drivers/nearby"))
+
+	// A rule without fields exempts the role from the AnyRole rule
+	require.NoError(t, os.WriteFile(path, []byte(`{"*": {"omit": ["phone"]}, "fleet_admin": {}}`), 0o600))
+	r, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, r.Applies("rider", "GET /drivers/nearby"))
+	assert.False(t, r.Applies("fleet_admin", "GET /drivers/nearby"))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"rider": {"hide": ["plate"]}}`), 0o600))
+	_, err = Load(path)
+	assert.Error(t, err)
+
+	_, err = Load(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,290 @@
+// Package schema checks driver service responses against the models the
+// gateway documents for them, so a bad driver service deploy that returns
+// malformed or mistyped JSON is turned into a gateway error instead of
+// reaching clients.
+//
+// A model is a Go value whose JSON encoding is the expected response. A
+// response matches when it is valid JSON, every value has the model's JSON
+// type and every field without omitempty is present. Fields the model does
+// not know about are allowed, so the driver service can add fields first.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// maxViolations bounds the violations reported for one response
+const maxViolations = 10
+
+// maxExcerpt bounds how much of an invalid body is logged
+const maxExcerpt = 256
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Error lists how a response differs from its model
+type Error struct {
+	Violations []string
+}
+
+func (e *Error) Error() string {
+	return "response does not match its schema: " + strings.Join(e.Violations, "; ")
+}
+
+// Validate checks body against model and returns an *Error listing the first
+// violations, or nil when it matches
+func Validate(model interface{}, body []byte) error {
+	if err := validate(reflect.TypeOf(model), body); err != nil {
+		return err
+	}
+	return nil
+}
+
+func validate(t reflect.Type, body []byte) *Error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &Error{Violations: []string{"invalid JSON: " + err.Error()}}
+	}
+	if decoder.More() {
+		return &Error{Violations: []string{"invalid JSON: data after the top-level value"}}
+	}
+
+	c := &checker{}
+	c.check(t, value, "$")
+	if len(c.violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: c.violations}
+}
+
+// checker collects the violations of one value
+type checker struct {
+	violations []string
+}
+
+func (c *checker) fail(path, format string, args ...interface{}) {
+	if len(c.violations) < maxViolations {
+		c.violations = append(c.violations, path+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+func (c *checker) check(t reflect.Type, value interface{}, path string) {
+	if len(c.violations) >= maxViolations {
+		return
+	}
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t, nullable = t.Elem(), true
+	}
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Slice, reflect.Map, reflect.Interface:
+		default:
+			if !nullable {
+				c.fail(path, "expected %s, got null", kindName(t))
+			}
+		}
+		return
+	}
+	// Types with their own encoding, such as time.Time, are not looked into
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			c.fail(path, "expected string, got %s", jsonType(value))
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			c.fail(path, "expected boolean, got %s", jsonType(value))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := value.(json.Number)
+		if !ok {
+			c.fail(path, "expected integer, got %s", jsonType(value))
+		} else if _, err := strconv.ParseInt(n.String(), 10, 64); err != nil {
+			c.fail(path, "expected integer, got %s", n)
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			c.fail(path, "expected number, got %s", jsonType(value))
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			c.fail(path, "expected array, got %s", jsonType(value))
+			return
+		}
+		for i, item := range items {
+			c.check(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		members, ok := value.(map[string]interface{})
+		if !ok {
+			c.fail(path, "expected object, got %s", jsonType(value))
+			return
+		}
+		keys := make([]string, 0, len(members))
+		for key := range members {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			c.check(t.Elem(), members[key], path+"."+key)
+		}
+	case reflect.Struct:
+		members, ok := value.(map[string]interface{})
+		if !ok {
+			c.fail(path, "expected object, got %s", jsonType(value))
+			return
+		}
+		c.checkFields(t, members, path)
+	}
+}
+
+// checkFields checks the members of an object against the fields of a struct
+func (c *checker) checkFields(t reflect.Type, members map[string]interface{}, path string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			c.checkFields(field.Type, members, path)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, ok := members[name]
+		if !ok {
+			if !strings.Contains(options, "omitempty") {
+				c.fail(path+"."+name, "missing")
+			}
+			continue
+		}
+		c.check(field.Type, value, path+"."+name)
+	}
+}
+
+// kindName names the JSON type a Go type encodes to
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Struct:
+		return "object"
+	default:
+		return "integer"
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+// Validator checks the responses of the routes it was given a model for
+type Validator struct {
+	models map[string]reflect.Type
+	logger *zap.Logger
+}
+
+// NewValidator creates a validator for routes, named "METHOD /path" as they
+// are registered on the router, e.g. "GET /drivers/:id". models holds the
+// model of every route that can be checked; an empty routes checks them all
+// and skip leaves routes out. Naming a route without a model is an error.
+func NewValidator(models map[string]interface{}, routes, skip []string, logger *zap.Logger) (*Validator, error) {
+	for _, route := range append(append([]string{}, routes...), skip...) {
+		if _, ok := models[route]; !ok {
+			return nil, fmt.Errorf("no response schema for route %q", route)
+		}
+	}
+	if len(routes) == 0 {
+		for route := range models {
+			routes = append(routes, route)
+		}
+	}
+	skipped := make(map[string]bool, len(skip))
+	for _, route := range skip {
+		skipped[route] = true
+	}
+
+	v := &Validator{models: make(map[string]reflect.Type), logger: logger}
+	for _, route := range routes {
+		if !skipped[route] {
+			v.models[route] = reflect.TypeOf(models[route])
+		}
+	}
+	return v, nil
+}
+
+// Routes returns the routes whose responses are checked
+func (v *Validator) Routes() []string {
+	routes := make([]string, 0, len(v.models))
+	for route := range v.models {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// Check checks a successful response of route. Responses of other routes
+// always pass. A violation is logged with an excerpt of the body and
+// returned as an *Error.
+func (v *Validator) Check(route string, status int, body []byte) error {
+	t, ok := v.models[route]
+	if !ok || status == http.StatusNoContent {
+		return nil
+	}
+	err := validate(t, body)
+	if err == nil {
+		return nil
+	}
+
+	excerpt := body
+	if len(excerpt) > maxExcerpt {
+		excerpt = excerpt[:maxExcerpt]
+	}
+	v.logger.Error("driver service response does not match its schema",
+		zap.String("route", route),
+		zap.Int("status", status),
+		zap.Strings("violations", err.Violations),
+		zap.ByteString("body", excerpt),
+	)
+	return err
+}
@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testLocation struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type testDriver struct {
+	ID         string            `json:"id"`
+	Rating     float64           `json:"rating"`
+	Trips      int               `json:"trips"`
+	Available  bool              `json:"available"`
+	Location   testLocation      `json:"location"`
+	Dropoff    *testLocation     `json:"dropoff,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	SeenAt     time.Time         `json:"seenAt"`
+	internal   string
+}
+
+type testPage struct {
+	Drivers []testDriver `json:"drivers"`
+	Total   int64        `json:"total"`
+}
+
+const validDriver = `{"id":"d1","rating":4.8,"trips":12,"available":true,"location":{"lat":41,"lon":29},"seenAt":"2025-12-06T01:00:00Z"}`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		model      interface{}
+		body       string
+		violations []string
+	}{
+		{
+			name:  "matching object",
+			model: testDriver{},
+			body:  validDriver,
+		},
+		{
+			name:  "unknown fields and optional fields",
+			model: testDriver{},
+			body: `{"id":"d1","rating":5,"trips":0,"available":false,"location":{"lat":0,"lon":0},"seenAt":"x",` +
+				`"dropoff":null,"tags":["pet-friendly"],"attributes":{"seats":"7"},"newField":[1,2]}`,
+		},
+		{
+			name:       "invalid JSON",
+			model:      testDriver{},
+			body:       `{"id":"d1",`,
+			violations: []string{"invalid JSON: unexpected EOF"},
+		},
+		{
+			name:       "trailing data",
+			model:      testDriver{},
+			body:       validDriver + `{}`,
+			violations: []string{"invalid JSON: data after the top-level value"},
+		},
+		{
+			name:  "wrong types and missing fields",
+			model: testDriver{},
+			body:  `{"id":7,"rating":"high","trips":1.5,"available":null,"location":{"lat":41},"tags":"a","attributes":{"seats":7},"seenAt":"x"}`,
+			violations: []string{
+				"$.id: expected string, got number",
+				"$.rating: expected number, got string",
+				"$.trips: expected integer, got 1.5",
+				"$.available: expected boolean, got null",
+				"$.location.lon: missing",
+				"$.tags: expected array, got string",
+				"$.attributes.seats: expected string, got number",
+			},
+		},
+		{
+			name:       "nested array items",
+			model:      testPage{},
+			body:       `{"drivers":[` + validDriver + `,{"id":"d2"}],"total":2}`,
+			violations: []string{"$.drivers[1].rating: missing", "$.drivers[1].trips: missing", "$.drivers[1].available: missing", "$.drivers[1].location: missing", "$.drivers[1].seenAt: missing"},
+		},
+		{
+			name:  "null slice",
+			model: testPage{},
+			body:  `{"drivers":null,"total":0}`,
+		},
+		{
+			name:       "array instead of object",
+			model:      testPage{},
+			body:       `[]`,
+			violations: []string{"$: expected object, got array"},
+		},
+		{
+			name:       "top-level array",
+			model:      []testLocation{},
+			body:       `[{"lat":1,"lon":2},{"lat":"1","lon":2}]`,
+			violations: []string{"$[1].lat: expected number, got string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.model, []byte(tt.body))
+			if tt.violations == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var schemaErr *Error
+			require.ErrorAs(t, err, &schemaErr)
+			assert.Equal(t, tt.violations, schemaErr.Violations)
+		})
+	}
+}
+
+func TestValidate_BoundsViolations(t *testing.T) {
+	body := `[` + `{"lat":"a","lon":"b"},` + `{"lat":"a","lon":"b"},` + `{"lat":"a","lon":"b"},` +
+		`{"lat":"a","lon":"b"},` + `{"lat":"a","lon":"b"},` + `{"lat":"a","lon":"b"}]`
+
+	var schemaErr *Error
+	require.ErrorAs(t, Validate([]testLocation{}, []byte(body)), &schemaErr)
+	assert.Len(t, schemaErr.Violations, maxViolations)
+}
+
+func TestValidator(t *testing.T) {
+	models := map[string]interface{}{
+		"GET /drivers":     testPage{},
+		"GET /drivers/:id": testDriver{},
+	}
+
+	t.Run("checks every route by default", func(t *testing.T) {
+		v, err := NewValidator(models, nil, nil, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET /drivers", "GET /drivers/:id"}, v.Routes())
+
+		assert.NoError(t, v.Check("GET /drivers/:id", 200, []byte(validDriver)))
+		assert.Error(t, v.Check("GET /drivers/:id", 200, []byte(`{}`)))
+		assert.NoError(t, v.Check("GET /drivers/:id", 204, nil), "no content has nothing to check")
+		assert.NoError(t, v.Check("GET /trips/:id", 200, []byte(`garbage`)), "routes without a model pass")
+	})
+
+	t.Run("routes and skip", func(t *testing.T) {
+		v, err := NewValidator(models, []string{"GET /drivers/:id"}, nil, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET /drivers/:id"}, v.Routes())
+
+		v, err = NewValidator(models, nil, []string{"GET /drivers/:id"}, zap.NewNop())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"GET /drivers"}, v.Routes())
+		assert.NoError(t, v.Check("GET /drivers/:id", 200, []byte(`{}`)))
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		_, err := NewValidator(models, []string{"GET /drivers/:id/photo"}, nil, zap.NewNop())
+		assert.EqualError(t, err, `no response schema for route "GET /drivers/:id/photo"`)
+
+		_, err = NewValidator(models, nil, []string{"GET /riders/:id"}, zap.NewNop())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/canary"
+	"go.uber.org/zap"
+)
+
+// RouteCanary sends the router's share of requests to its canary driver
+// service instead of the base URL. Admin calls and health checks stay on the
+// primary.
+func (c *DriverServiceClient) RouteCanary(router *canary.Router) {
+	c.canary = router
+}
+
+// WithCanaryHeader returns a client whose requests are routed by the caller's
+// X-Canary header value when the router allows it. The returned client shares
+// the underlying HTTP client.
+func (c *DriverServiceClient) WithCanaryHeader(value string) *DriverServiceClient {
+	scoped := *c
+	scoped.canaryHeader = value
+	return &scoped
+}
+
+// pickUpstream returns the base URL a request goes to: the canary for its
+// share of traffic, the base URL otherwise
+func (c *DriverServiceClient) pickUpstream() string {
+	if c.canary != nil && c.canary.Pick(c.canaryHeader) {
+		return c.canary.URL()
+	}
+	return c.baseURL
+}
+
+// observeUpstream scores a sent request against the upstream that answered
+// it, and marks responses from the canary. Requests the caller gave up on say
+// nothing about either upstream.
+func (c *DriverServiceClient) observeUpstream(req *http.Request, elapsed time.Duration, resp *http.Response, err error) {
+	if c.canary == nil || isAdminPath(req.URL.Path) {
+		return
+	}
+	if err != nil && req.Context().Err() != nil && !timedOut(req.Context()) {
+		return
+	}
+	isCanary := strings.HasPrefix(req.URL.String(), c.canary.URL()+"/")
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	if c.canary.Observe(isCanary, elapsed, failed) {
+		c.logger.Warn("canary driver service is failing, falling back to the primary",
+			zap.String("canary", c.canary.URL()),
+			zap.Any("stats", c.canary.Stats().Canary),
+		)
+	}
+	if isCanary && err == nil {
+		resp.Header.Set(canary.Header, "true")
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+// ConsistencyTokenHeader carries the driver service's read-your-writes token.
+// Its responses set it; a caller sending it back reads its own writes even
+// when the driver service reads from a secondary.
+const ConsistencyTokenHeader = "X-Consistency-Token"
+
+// WithConsistencyToken returns a client that sends token with every request,
+// so the driver service answers with data at least as new as the response the
+// token came from. An empty token sends none. The returned client shares the
+// underlying HTTP client.
+func (c *DriverServiceClient) WithConsistencyToken(token string) *DriverServiceClient {
+	scoped := *c
+	scoped.consistencyToken = token
+	return &scoped
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrInvalidDeviceToken is returned by VerifyDeviceToken for tokens the driver
+// service does not accept
+var ErrInvalidDeviceToken = errors.New("invalid or revoked device token")
+
+// DeviceToken is a verified driver device token
+type DeviceToken struct {
+	ID       string   `json:"id"`
+	DriverID string   `json:"driverId"`
+	DeviceID string   `json:"deviceId"`
+	Scopes   []string `json:"scopes"`
+}
+
+// HasScope reports whether the token carries scope
+func (t *DeviceToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueDeviceToken forwards a device token request for a driver
+func (c *DriverServiceClient) IssueDeviceToken(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/device-tokens", id), body)
+}
+
+// ListDriverDeviceTokens forwards a request for the device tokens of a driver
+func (c *DriverServiceClient) ListDriverDeviceTokens(id, active string) (*http.Response, error) {
+	return c.doRequest("GET", deviceTokensPath(fmt.Sprintf("/api/v1/drivers/%s/device-tokens", id), "", active), nil)
+}
+
+// ListDeviceTokens forwards a request for the device tokens of every driver, or of one
+func (c *DriverServiceClient) ListDeviceTokens(driverID, active string) (*http.Response, error) {
+	return c.doRequest("GET", deviceTokensPath("/api/v1/admin/device-tokens", driverID, active), nil)
+}
+
+func deviceTokensPath(path, driverID, active string) string {
+	query := url.Values{}
+	if driverID != "" {
+		query.Set("driverId", driverID)
+	}
+	if active != "" {
+		query.Set("active", active)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
+
+// RevokeDeviceToken forwards a device token revocation
+func (c *DriverServiceClient) RevokeDeviceToken(id string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/admin/device-tokens/%s", id), nil)
+}
+
+// RotateDeviceToken forwards a device token rotation
+func (c *DriverServiceClient) RotateDeviceToken(id string) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/admin/device-tokens/%s/rotate", id), nil)
+}
+
+// VerifyDeviceToken asks the driver service whether a device token is valid.
+// Tokens it refuses return ErrInvalidDeviceToken; other errors mean it could
+// not tell.
+func (c *DriverServiceClient) VerifyDeviceToken(ctx context.Context, token string) (*DeviceToken, error) {
+	resp, err := c.doRequestContext(ctx, "POST", "/api/v1/device-tokens/verify", map[string]string{"token": token})
+	if err != nil {
+		return nil, err
+	}
+	body, err := readResponse(resp)
+	if err != nil {
+		var upstream *UpstreamError
+		if errors.As(err, &upstream) && upstream.Status == http.StatusUnauthorized {
+			return nil, ErrInvalidDeviceToken
+		}
+		return nil, err
+	}
+
+	var verified DeviceToken
+	if err := json.Unmarshal(body, &verified); err != nil || verified.DriverID == "" {
+		return nil, fmt.Errorf("driver service returned an unreadable device token")
+	}
+	return &verified, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/bitaksi/gateway/internal/apimodel"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrStopIteration can be returned by a ListAllDrivers callback to stop early
+// without reporting an error
+var ErrStopIteration = errors.New("stop iteration")
+
+// ListAllOptions controls how ListAllDrivers walks the driver list
+type ListAllOptions struct {
+	// PageSize is requested from the driver service, which caps it at 100 (default: 100)
+	PageSize int
+	// FleetID limits the walk to one fleet
+	FleetID string
+	// MaxRetries is how often a page is retried after a transient error (default: 3)
+	MaxRetries int
+	// InitialBackoff doubles after every retry up to MaxBackoff (defaults: 200ms, 5s)
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func (o ListAllOptions) withDefaults() ListAllOptions {
+	if o.PageSize <= 0 {
+		o.PageSize = 100
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	} else if o.MaxRetries == 0 {
+		o.MaxRetries = 3
+	}
+	if o.InitialBackoff <= 0 {
+		o.InitialBackoff = 200 * time.Millisecond
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 5 * time.Second
+	}
+	return o
+}
+
+// driverPage is one page of GET /api/v1/drivers
+type driverPage struct {
+	Drivers    []json.RawMessage `json:"drivers"`
+	TotalCount int64             `json:"totalCount"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"pageSize"`
+}
+
+// ListAllDrivers walks every page of the driver list and calls fn with each
+// driver as raw JSON. Pages that fail with a transport error, 429 or 5xx are
+// retried with exponential backoff. The list is paged by offset, so drivers
+// created during the walk can shift later pages; drivers already seen are
+// skipped rather than delivered twice. The walk stops when ctx is done, when a
+// page fails permanently or when fn returns an error (ErrStopIteration stops
+// it without an error).
+func (c *DriverServiceClient) ListAllDrivers(ctx context.Context, opts ListAllOptions, fn func(driver json.RawMessage) error) error {
+	opts = opts.withDefaults()
+	seen := make(map[string]struct{})
+
+	for page := 1; ; page++ {
+		result, err := c.fetchDriverPage(ctx, page, opts)
+		if err != nil {
+			return err
+		}
+
+		for _, raw := range result.Drivers {
+			var ref struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(raw, &ref) == nil && ref.ID != "" {
+				if _, dup := seen[ref.ID]; dup {
+					continue
+				}
+				seen[ref.ID] = struct{}{}
+			}
+			if err := fn(raw); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		// The service may serve fewer drivers per page than requested
+		pageSize := result.PageSize
+		if pageSize <= 0 {
+			pageSize = opts.PageSize
+		}
+		if len(result.Drivers) < pageSize {
+			return nil
+		}
+	}
+}
+
+// StreamAllDrivers runs ListAllDrivers in the background and delivers drivers
+// on the returned channel, which is closed when the walk ends. The error
+// channel then yields the walk's result (nil on success) and is closed too.
+// Cancel ctx to stop a consumer that does not drain the channel.
+func (c *DriverServiceClient) StreamAllDrivers(ctx context.Context, opts ListAllOptions) (<-chan json.RawMessage, <-chan error) {
+	drivers := make(chan json.RawMessage)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer close(errc)
+		defer close(drivers)
+		errc <- c.ListAllDrivers(ctx, opts, func(driver json.RawMessage) error {
+			select {
+			case drivers <- driver:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	return drivers, errc
+}
+
+// fetchDriverPage requests one page, retrying transient failures
+func (c *DriverServiceClient) fetchDriverPage(ctx context.Context, page int, opts ListAllOptions) (*driverPage, error) {
+	path := listDriversPath(apimodel.ListDriversQuery{
+		Page:     strconv.Itoa(page),
+		PageSize: strconv.Itoa(opts.PageSize),
+		FleetID:  opts.FleetID,
+	})
+	backoff := opts.InitialBackoff
+
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		resp, err := c.doRequestContext(ctx, "GET", path, nil)
+		if err == nil {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			var body []byte
+			if body, err = readResponse(resp); err == nil {
+				var result driverPage
+				if err := json.Unmarshal(body, &result); err != nil {
+					return nil, fmt.Errorf("failed to decode driver page %d: %w", page, err)
+				}
+				return &result, nil
+			}
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !isTransient(err) || attempt >= opts.MaxRetries {
+			return nil, fmt.Errorf("failed to list drivers page %d: %w", page, err)
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		if wait > opts.MaxBackoff {
+			wait = opts.MaxBackoff
+		}
+		c.logger.Warn("retrying driver page",
+			zap.Int("page", page),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// isTransient reports whether a failed request may succeed when retried.
+// Transport errors, rate limiting and server errors are; other statuses are not.
+func isTransient(err error) bool {
+	var upstream *UpstreamError
+	if !errors.As(err, &upstream) {
+		return true
+	}
+	return upstream.Status == http.StatusTooManyRequests || upstream.Status >= 500
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
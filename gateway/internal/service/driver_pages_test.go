@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// pagedDrivers serves a driver list of the given size, capping pages at maxPageSize
+type pagedDrivers struct {
+	mu          sync.Mutex
+	total       int
+	maxPageSize int
+	// failures lists statuses returned, in order, before requests succeed
+	failures []int
+	requests int
+	// shift prepends drivers after the first page, like concurrent creates would
+	shift int
+}
+
+func (p *pagedDrivers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests++
+
+	if len(p.failures) > 0 {
+		status := p.failures[0]
+		p.failures = p.failures[1:]
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"error":{"code":"UPSTREAM","message":"status %d"}}`, status)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if pageSize > p.maxPageSize {
+		pageSize = p.maxPageSize
+	}
+	offset := (page - 1) * pageSize
+	if page > 1 {
+		offset -= p.shift
+	}
+
+	drivers := []map[string]string{}
+	for i := offset; i < offset+pageSize && i < p.total; i++ {
+		drivers = append(drivers, map[string]string{"id": fmt.Sprintf("driver-%d", i)})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"drivers":    drivers,
+		"totalCount": p.total,
+		"page":       page,
+		"pageSize":   pageSize,
+	})
+}
+
+func collectDrivers(t *testing.T, client *DriverServiceClient, opts ListAllOptions) ([]string, error) {
+	t.Helper()
+	var ids []string
+	err := client.ListAllDrivers(context.Background(), opts, func(driver json.RawMessage) error {
+		var d struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(driver, &d))
+		ids = append(ids, d.ID)
+		return nil
+	})
+	return ids, err
+}
+
+func TestDriverServiceClient_ListAllDrivers(t *testing.T) {
+	fastRetry := ListAllOptions{InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+	t.Run("walks every page with the service page size", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 250, maxPageSize: 100}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		ids, err := collectDrivers(t, NewDriverServiceClient(server.URL, zap.NewNop()), ListAllOptions{PageSize: 500})
+		require.NoError(t, err)
+		assert.Len(t, ids, 250)
+		assert.Equal(t, 3, upstream.requests)
+	})
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 5, maxPageSize: 100, failures: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		ids, err := collectDrivers(t, NewDriverServiceClient(server.URL, zap.NewNop()), fastRetry)
+		require.NoError(t, err)
+		assert.Len(t, ids, 5)
+		assert.Equal(t, 3, upstream.requests)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 5, maxPageSize: 100, failures: []int{500, 500, 500}}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		opts := fastRetry
+		opts.MaxRetries = 2
+		_, err := collectDrivers(t, NewDriverServiceClient(server.URL, zap.NewNop()), opts)
+		var upstreamErr *UpstreamError
+		require.ErrorAs(t, err, &upstreamErr)
+		assert.Equal(t, 500, upstreamErr.Status)
+		assert.Equal(t, 3, upstream.requests)
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 5, maxPageSize: 100, failures: []int{http.StatusBadRequest}}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		_, err := collectDrivers(t, NewDriverServiceClient(server.URL, zap.NewNop()), fastRetry)
+		assert.Error(t, err)
+		assert.Equal(t, 1, upstream.requests)
+	})
+
+	t.Run("skips drivers shifted into the next page", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 20, maxPageSize: 10, shift: 2}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		ids, err := collectDrivers(t, NewDriverServiceClient(server.URL, zap.NewNop()), ListAllOptions{PageSize: 10})
+		require.NoError(t, err)
+		assert.Len(t, ids, 20)
+	})
+
+	t.Run("callback can stop the walk", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 250, maxPageSize: 100}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		count := 0
+		err := NewDriverServiceClient(server.URL, zap.NewNop()).ListAllDrivers(context.Background(), ListAllOptions{},
+			func(json.RawMessage) error {
+				if count++; count == 3 {
+					return ErrStopIteration
+				}
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		boom := errors.New("boom")
+		err = NewDriverServiceClient(server.URL, zap.NewNop()).ListAllDrivers(context.Background(), ListAllOptions{},
+			func(json.RawMessage) error { return boom })
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("cancellation stops backoff", func(t *testing.T) {
+		upstream := &pagedDrivers{total: 5, maxPageSize: 100, failures: []int{503, 503, 503}}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := NewDriverServiceClient(server.URL, zap.NewNop()).ListAllDrivers(ctx,
+			ListAllOptions{InitialBackoff: time.Minute, MaxBackoff: time.Minute},
+			func(json.RawMessage) error { return nil })
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestDriverServiceClient_StreamAllDrivers(t *testing.T) {
+	upstream := &pagedDrivers{total: 150, maxPageSize: 100}
+	server := httptest.NewServer(upstream)
+	defer server.Close()
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	drivers, errc := client.StreamAllDrivers(context.Background(), ListAllOptions{})
+	count := 0
+	for range drivers {
+		count++
+	}
+	assert.NoError(t, <-errc)
+	assert.Equal(t, 150, count)
+
+	// A consumer that stops reading cancels the context to release the producer
+	ctx, cancel := context.WithCancel(context.Background())
+	drivers, errc = client.StreamAllDrivers(ctx, ListAllOptions{})
+	<-drivers
+	cancel()
+	for range drivers {
+	}
+	assert.ErrorIs(t, <-errc, context.Canceled)
+}
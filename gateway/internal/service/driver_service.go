@@ -2,22 +2,65 @@ package service
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/bitaksi/gateway/internal/adaptive"
+	"github.com/bitaksi/gateway/internal/apimodel"
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/hedge"
 	"go.uber.org/zap"
 )
 
 // DriverServiceClient handles communication with the driver service
 type DriverServiceClient struct {
-	baseURL    string
+	baseURL string
+	// adminURL receives admin calls and health checks; see RouteAdmin
+	adminURL string
+	// timeouts bound calls by endpoint class; see SetTimeouts
+	timeouts   *Timeouts
 	httpClient *http.Client
 	logger     *zap.Logger
+	// identity is sent with every request; see WithIdentity
+	identity Identity
+	// consistencyToken is sent with every request; see WithConsistencyToken
+	consistencyToken string
+	// requestID is sent with every request; see WithRequestID
+	requestID string
+	// limiter sheds load while the driver service is slow or failing; see LimitConcurrency
+	limiter    *adaptive.Limiter
+	retryAfter time.Duration
+	// hedger sends second requests for slow reads; see Hedge
+	hedger *hedge.Hedger
+	// compress and compressMin configure gzip; see Compress
+	compress    bool
+	compressMin int
+	// gzipRequests is set while the driver service accepts gzip request bodies;
+	// clients scoped with WithIdentity share it
+	gzipRequests *atomic.Bool
+	// latency holds recent response times; clients scoped with WithIdentity share it
+	latency *latencyRecorder
+	// canary receives a share of requests; see RouteCanary
+	canary *canary.Router
+	// canaryHeader is the caller's X-Canary value; see WithCanaryHeader
+	canaryHeader string
+	// sign returns the token sent as GatewayTokenHeader; see SignCalls
+	sign func() (string, error)
 }
 
+// GatewayTokenHeader carries the token the gateway signs its calls with, so
+// the driver service trusts the identity headers sent alongside it
+const GatewayTokenHeader = "X-Gateway-Token"
+
 // NewDriverServiceClient creates a new driver service client
 func NewDriverServiceClient(baseURL string, logger *zap.Logger) *DriverServiceClient {
 	return &DriverServiceClient{
@@ -25,10 +68,55 @@ func NewDriverServiceClient(baseURL string, logger *zap.Logger) *DriverServiceCl
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		logger: logger,
+		logger:       logger,
+		compress:     true,
+		compressMin:  defaultCompressMin,
+		gzipRequests: &atomic.Bool{},
+		latency:      newLatencyRecorder(),
 	}
 }
 
+// defaultCompressMin is the smallest request body compressed unless Compress says otherwise
+const defaultCompressMin = 1024
+
+// Compress configures gzip between the gateway and the driver service. When
+// enabled, which is the default, responses are requested compressed and
+// request bodies of at least minSize bytes are compressed once the driver
+// service has advertised that it accepts them. When disabled nothing is
+// compressed either way, which saves CPU where bandwidth is cheap.
+func (c *DriverServiceClient) Compress(enabled bool, minSize int) {
+	c.compress = enabled
+	c.compressMin = minSize
+}
+
+// LimitConcurrency bounds the requests in flight to the driver service with an
+// adaptive limit. Requests over the limit are answered with 503 and retryAfter
+// without being sent. Admin calls and health checks are not limited so the
+// service stays observable.
+func (c *DriverServiceClient) LimitConcurrency(limiter *adaptive.Limiter, retryAfter time.Duration) {
+	c.limiter = limiter
+	c.retryAfter = retryAfter
+}
+
+// RouteAdmin sends admin calls and health checks to adminURL instead of the
+// base URL, for a driver service serving them on its ADMIN_PORT
+func (c *DriverServiceClient) RouteAdmin(adminURL string) {
+	c.adminURL = adminURL
+}
+
+// SignCalls sends a token from sign with every request. The driver service
+// requires one, or an exchanged token, once TOKEN_EXCHANGE_SECRET is set.
+func (c *DriverServiceClient) SignCalls(sign func() (string, error)) {
+	c.sign = sign
+}
+
+// Hedge sends a second request for latency-sensitive reads that are still
+// unanswered after the hedger's delay, and uses whichever answers first. Only
+// idempotent GETs are hedged.
+func (c *DriverServiceClient) Hedge(hedger *hedge.Hedger) {
+	c.hedger = hedger
+}
+
 // CreateDriver forwards a create driver request to the driver service
 func (c *DriverServiceClient) CreateDriver(body interface{}) (*http.Response, error) {
 	return c.doRequest("POST", "/api/v1/drivers", body)
@@ -41,55 +129,571 @@ func (c *DriverServiceClient) UpdateDriver(id string, body interface{}) (*http.R
 
 // GetDriver forwards a get driver request to the driver service
 func (c *DriverServiceClient) GetDriver(id string) (*http.Response, error) {
-	return c.doRequest("GET", fmt.Sprintf("/api/v1/drivers/%s", id), nil)
+	return c.doHedged(fmt.Sprintf("/api/v1/drivers/%s", id))
 }
 
-// ListDrivers forwards a list drivers request to the driver service
-func (c *DriverServiceClient) ListDrivers(page, pageSize string) (*http.Response, error) {
-	url := "/api/v1/drivers"
-	if page != "" || pageSize != "" {
-		url += "?"
-		if page != "" {
-			url += "page=" + page
-		}
-		if pageSize != "" {
-			if page != "" {
-				url += "&"
-			}
-			url += "pageSize=" + pageSize
-		}
+// HeadDriver asks the driver service whether a driver exists without fetching it
+func (c *DriverServiceClient) HeadDriver(id string) (*http.Response, error) {
+	return c.doRequest("HEAD", fmt.Sprintf("/api/v1/drivers/%s", id), nil)
+}
+
+// ListDrivers forwards a list drivers request to the driver service. An empty
+// FleetID lists drivers of every fleet.
+func (c *DriverServiceClient) ListDrivers(query apimodel.ListDriversQuery) (*http.Response, error) {
+	return c.doRequest("GET", listDriversPath(query), nil)
+}
+
+// listDriversPath builds the driver list URL; empty values are left out
+func listDriversPath(query apimodel.ListDriversQuery) string {
+	path := "/api/v1/drivers"
+	if values := query.Values(); len(values) > 0 {
+		path += "?" + values.Encode()
 	}
-	return c.doRequest("GET", url, nil)
+	return path
 }
 
-// FindNearbyDrivers forwards a find nearby drivers request to the driver service
-func (c *DriverServiceClient) FindNearbyDrivers(lat, lon, taksiType string) (*http.Response, error) {
-	url := fmt.Sprintf("/api/v1/drivers/nearby?lat=%s&lon=%s", lat, lon)
+// GetDriverChanges forwards a delta sync request; empty values are left out
+func (c *DriverServiceClient) GetDriverChanges(since, limit, fleetID string) (*http.Response, error) {
+	path := "/api/v1/drivers/changes"
+	query := url.Values{}
+	if since != "" {
+		query.Set("since", since)
+	}
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if fleetID != "" {
+		query.Set("fleetId", fleetID)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// AutocompleteDrivers forwards a driver autocomplete request to the driver service
+func (c *DriverServiceClient) AutocompleteDrivers(q, limit, fleetID string) (*http.Response, error) {
+	query := url.Values{}
+	query.Set("q", q)
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if fleetID != "" {
+		query.Set("fleetId", fleetID)
+	}
+	return c.doRequest("GET", "/api/v1/drivers/autocomplete?"+query.Encode(), nil)
+}
+
+// FindNearbyDrivers forwards a find nearby drivers request to the driver
+// service. An empty live leaves the heartbeat filter to the driver service
+// default; tags and attributes are comma-separated lists. An empty units
+// leaves distances in kilometers only.
+func (c *DriverServiceClient) FindNearbyDrivers(lat, lon, taksiType, fleetID, city, live, tags, attributes, units, limit, offset string) (*http.Response, error) {
+	path := fmt.Sprintf("/api/v1/drivers/nearby?lat=%s&lon=%s", lat, lon)
 	if taksiType != "" {
-		url += "&taksiType=" + taksiType
+		path += "&taksiType=" + taksiType
+	}
+	if fleetID != "" {
+		path += "&fleetId=" + fleetID
+	}
+	if city != "" {
+		path += "&city=" + url.QueryEscape(city)
+	}
+	if live != "" {
+		path += "&live=" + live
+	}
+	if tags != "" {
+		path += "&tags=" + url.QueryEscape(tags)
+	}
+	if attributes != "" {
+		path += "&attributes=" + url.QueryEscape(attributes)
+	}
+	if units != "" {
+		path += "&units=" + units
+	}
+	if limit != "" {
+		path += "&limit=" + url.QueryEscape(limit)
+	}
+	if offset != "" {
+		path += "&offset=" + url.QueryEscape(offset)
+	}
+	return c.doHedged(path)
+}
+
+// FindDriversAlongRoute forwards a route corridor search to the driver
+// service. An empty units leaves distances in kilometers only.
+func (c *DriverServiceClient) FindDriversAlongRoute(body interface{}, units string) (*http.Response, error) {
+	path := "/api/v1/drivers/nearby/route"
+	if units != "" {
+		path += "?units=" + units
+	}
+	return c.doRequest("POST", path, body)
+}
+
+// BulkUpdateDrivers forwards a fleet-wide driver update to the driver service
+func (c *DriverServiceClient) BulkUpdateDrivers(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/drivers/bulk-update", body)
+}
+
+// Heartbeat forwards a driver heartbeat to the driver service
+func (c *DriverServiceClient) Heartbeat(id string) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/heartbeat", id), nil)
+}
+
+// GetOnlineStats forwards an online driver count request. An empty fleetID
+// counts drivers of every fleet.
+func (c *DriverServiceClient) GetOnlineStats(fleetID string) (*http.Response, error) {
+	path := "/api/v1/drivers/stats"
+	if fleetID != "" {
+		path += "?" + url.Values{"fleetId": {fleetID}}.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// ListTaxiTypes forwards a taxi type listing. A non-empty fleetID only lists
+// the types drivers of that fleet may register with.
+func (c *DriverServiceClient) ListTaxiTypes(fleetID string) (*http.Response, error) {
+	path := "/api/v1/taxi-types"
+	if fleetID != "" {
+		path += "?" + url.Values{"fleetId": {fleetID}}.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// SetAvailability forwards a set availability request to the driver service
+func (c *DriverServiceClient) SetAvailability(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/drivers/%s/availability", id), body)
+}
+
+// GetDriverStats forwards a driver statistics request; empty bounds use the driver service defaults
+func (c *DriverServiceClient) GetDriverStats(id, from, to string) (*http.Response, error) {
+	path := fmt.Sprintf("/api/v1/drivers/%s/stats", id)
+	query := url.Values{}
+	if from != "" {
+		query.Set("from", from)
+	}
+	if to != "" {
+		query.Set("to", to)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetDriverEarnings forwards a driver earnings request; empty parameters use the driver service defaults
+func (c *DriverServiceClient) GetDriverEarnings(id, period, from, to string) (*http.Response, error) {
+	path := fmt.Sprintf("/api/v1/drivers/%s/earnings", id)
+	query := url.Values{}
+	for name, value := range map[string]string{"period": period, "from": from, "to": to} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// CreateFleet forwards a create fleet request to the driver service
+func (c *DriverServiceClient) CreateFleet(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/fleets", body)
+}
+
+// ListFleets forwards a list fleets request to the driver service
+func (c *DriverServiceClient) ListFleets() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/fleets", nil)
+}
+
+// GetFleet forwards a get fleet request to the driver service
+func (c *DriverServiceClient) GetFleet(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/fleets/%s", id), nil)
+}
+
+// AssignFleet forwards a fleet assignment for a driver to the driver service
+func (c *DriverServiceClient) AssignFleet(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/drivers/%s/fleet", id), body)
+}
+
+// SetSuspension forwards a driver suspension or reinstatement to the driver service
+func (c *DriverServiceClient) SetSuspension(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/drivers/%s/suspension", id), body)
+}
+
+// CreateWebhook forwards a webhook subscription to the driver service
+func (c *DriverServiceClient) CreateWebhook(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/webhooks", body)
+}
+
+// ListWebhooks lists webhook subscriptions; an empty fleetID lists all of them
+func (c *DriverServiceClient) ListWebhooks(fleetID string) (*http.Response, error) {
+	path := "/api/v1/webhooks"
+	if fleetID != "" {
+		path += "?" + url.Values{"fleetId": {fleetID}}.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetWebhook forwards a get webhook subscription request to the driver service
+func (c *DriverServiceClient) GetWebhook(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/webhooks/%s", id), nil)
+}
+
+// DeleteWebhook asks the driver service to remove a webhook subscription
+func (c *DriverServiceClient) DeleteWebhook(id string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/webhooks/%s", id), nil)
+}
+
+// ListWebhookDeliveries fetches the delivery log of a subscription; an empty limit uses the default
+func (c *DriverServiceClient) ListWebhookDeliveries(id, limit string) (*http.Response, error) {
+	path := fmt.Sprintf("/api/v1/webhooks/%s/deliveries", id)
+	if limit != "" {
+		path += "?" + url.Values{"limit": {limit}}.Encode()
 	}
-	return c.doRequest("GET", url, nil)
+	return c.doRequest("GET", path, nil)
+}
+
+// RetryWebhookDelivery asks the driver service to send a delivery again
+func (c *DriverServiceClient) RetryWebhookDelivery(id, deliveryID string) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/webhooks/%s/deliveries/%s/retry", id, deliveryID), nil)
+}
+
+// SendPhoneVerification asks the driver service to send a phone verification code
+func (c *DriverServiceClient) SendPhoneVerification(id string) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/verify-phone/send", id), nil)
+}
+
+// VerifyPhone forwards a phone verification code to the driver service
+func (c *DriverServiceClient) VerifyPhone(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/verify-phone", id), body)
+}
+
+// DeleteDriver asks the driver service to remove a driver
+func (c *DriverServiceClient) DeleteDriver(id string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/drivers/%s", id), nil)
+}
+
+// ErasePersonalData asks the driver service to erase a driver's personal data;
+// actor and reason are kept in the erasure's audit record
+func (c *DriverServiceClient) ErasePersonalData(id, actor, reason string) (*http.Response, error) {
+	path := fmt.Sprintf("/api/v1/drivers/%s/personal-data", id)
+	if reason != "" {
+		path += "?" + url.Values{"reason": {reason}}.Encode()
+	}
+	header := http.Header{}
+	header.Set("X-Admin-Actor", actor)
+	return c.doRequestHeader(context.Background(), "DELETE", path, nil, header)
+}
+
+// ListErasures lists the personal data erasures, newest first
+func (c *DriverServiceClient) ListErasures(driverID, limit string) (*http.Response, error) {
+	path := "/api/v1/admin/erasures"
+	query := url.Values{}
+	if driverID != "" {
+		query.Set("driverId", driverID)
+	}
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// StartExport asks the driver service to export drivers in the background;
+// actor is kept in the export's audit record
+func (c *DriverServiceClient) StartExport(body interface{}, actor string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("X-Admin-Actor", actor)
+	return c.doRequestHeader(context.Background(), "POST", "/api/v1/exports", body, header)
+}
+
+// GetExport fetches the status and progress of an export
+func (c *DriverServiceClient) GetExport(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/exports/%s", id), nil)
+}
+
+// DownloadExport fetches the file of a completed export; the caller streams
+// and closes the body
+func (c *DriverServiceClient) DownloadExport(ctx context.Context, id string) (*http.Response, error) {
+	return c.doRequestContext(ctx, "GET", fmt.Sprintf("/api/v1/exports/%s/download", id), nil)
+}
+
+// UploadDocument forwards a driver document reference to the driver service
+func (c *DriverServiceClient) UploadDocument(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/documents", id), body)
+}
+
+// DeleteDocument asks the driver service to remove a driver document by type
+func (c *DriverServiceClient) DeleteDocument(id, docType string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/drivers/%s/documents/%s", id, docType), nil)
+}
+
+// SubmitIdentityCheck asks the driver service to send the driver's identity to the KYC provider
+func (c *DriverServiceClient) SubmitIdentityCheck(id string) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/kyc", id), nil)
+}
+
+// GetIdentityCheck forwards a get identity check request to the driver service
+func (c *DriverServiceClient) GetIdentityCheck(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/drivers/%s/kyc", id), nil)
+}
+
+// ForwardKYCWebhook passes a KYC provider webhook call to the driver service
+// unchanged, together with its signature
+func (c *DriverServiceClient) ForwardKYCWebhook(body []byte, signature string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("X-KYC-Signature", signature)
+	return c.doRequestHeader(context.Background(), "POST", "/api/v1/kyc/webhook", body, header)
+}
+
+// RequestTrip forwards a ride request to the driver service for matching
+func (c *DriverServiceClient) RequestTrip(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/trips", body)
+}
+
+// EstimateFare forwards a fare quote request to the driver service
+func (c *DriverServiceClient) EstimateFare(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/trips/estimate", body)
+}
+
+// GetTrip forwards a get trip request to the driver service
+func (c *DriverServiceClient) GetTrip(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/trips/%s", id), nil)
+}
+
+// TripAction forwards a trip state change (accept, decline, complete, cancel) to the driver service
+func (c *DriverServiceClient) TripAction(id, action string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/trips/%s/%s", id, action), body)
+}
+
+// RegisterRider forwards a rider registration to the driver service
+func (c *DriverServiceClient) RegisterRider(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/riders", body)
+}
+
+// SendRiderSignInCode asks the driver service to text a rider a sign-in code
+func (c *DriverServiceClient) SendRiderSignInCode(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/riders/sign-in/send", body)
+}
+
+// SignInRider forwards a rider's sign-in code to the driver service
+func (c *DriverServiceClient) SignInRider(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/riders/sign-in", body)
+}
+
+// GetRider forwards a get rider request to the driver service
+func (c *DriverServiceClient) GetRider(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/riders/%s", id), nil)
+}
+
+// UpdateRider forwards a rider profile update to the driver service
+func (c *DriverServiceClient) UpdateRider(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/riders/%s", id), body)
+}
+
+// AddFavoriteLocation forwards a saved place for a rider to the driver service
+func (c *DriverServiceClient) AddFavoriteLocation(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/riders/%s/favorites", id), body)
+}
+
+// RemoveFavoriteLocation forwards the removal of a rider's saved place to the driver service
+func (c *DriverServiceClient) RemoveFavoriteLocation(id, favoriteID string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/riders/%s/favorites/%s", id, favoriteID), nil)
+}
+
+// GetIndexes asks the driver service to compare expected and existing MongoDB indexes
+func (c *DriverServiceClient) GetIndexes() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/indexes", nil)
+}
+
+// SyncIndexes starts building missing indexes; actor is recorded in the driver service audit log
+func (c *DriverServiceClient) SyncIndexes(actor string) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("X-Admin-Actor", actor)
+	return c.doRequestHeader(context.Background(), "POST", "/api/v1/admin/indexes/sync", nil, header)
+}
+
+// GetIndexSync reports the progress of the latest index sync
+func (c *DriverServiceClient) GetIndexSync() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/indexes/sync", nil)
+}
+
+// GetFailoverStats reports how the driver service weathered MongoDB failovers
+func (c *DriverServiceClient) GetFailoverStats() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/failover", nil)
+}
+
+// GetTelemetryStats reports what the driver service's MQTT bridge received
+func (c *DriverServiceClient) GetTelemetryStats() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/telemetry", nil)
+}
+
+// GetQueryStats gets the driver service's MongoDB query latency histograms
+func (c *DriverServiceClient) GetQueryStats() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/query-stats", nil)
+}
+
+// GetSaturationStats gets the driver service's concurrency saturation
+func (c *DriverServiceClient) GetSaturationStats() (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/saturation", nil)
+}
+
+// GetValidationRules gets the driver service's effective validation rules; a
+// non-empty fleetID asks for the rules of that fleet's drivers only
+func (c *DriverServiceClient) GetValidationRules(fleetID string) (*http.Response, error) {
+	path := "/api/v1/admin/validation-rules"
+	if fleetID != "" {
+		path += "?" + url.Values{"fleetId": {fleetID}}.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetTenantQuota gets how many drivers a fleet has against its quota
+func (c *DriverServiceClient) GetTenantQuota(fleetID string) (*http.Response, error) {
+	return c.doRequest("GET", "/api/v1/admin/tenants/"+url.PathEscape(fleetID)+"/quota", nil)
+}
+
+// GetExpiringLicenses lists licences expiring within days; empty values are left out
+func (c *DriverServiceClient) GetExpiringLicenses(days, fleetID string) (*http.Response, error) {
+	path := "/api/v1/admin/licenses/expiring"
+	query := url.Values{}
+	if days != "" {
+		query.Set("days", days)
+	}
+	if fleetID != "" {
+		query.Set("fleetId", fleetID)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// CreateEarningAdjustment forwards an earnings adjustment to the driver service
+func (c *DriverServiceClient) CreateEarningAdjustment(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/admin/earnings/adjustments", body)
+}
+
+// ListEarningAdjustments forwards a request for the adjustment audit trail
+func (c *DriverServiceClient) ListEarningAdjustments(driverID, limit string) (*http.Response, error) {
+	path := "/api/v1/admin/earnings/adjustments"
+	query := url.Values{}
+	if driverID != "" {
+		query.Set("driverId", driverID)
+	}
+	if limit != "" {
+		query.Set("limit", limit)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// CreatePayout forwards a payout batch request; a nil body uses the default range
+func (c *DriverServiceClient) CreatePayout(body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", "/api/v1/admin/payouts", body)
+}
+
+// ListPayouts forwards a list payouts request
+func (c *DriverServiceClient) ListPayouts(limit string) (*http.Response, error) {
+	path := "/api/v1/admin/payouts"
+	if limit != "" {
+		path += "?" + url.Values{"limit": {limit}}.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetPayout forwards a get payout request
+func (c *DriverServiceClient) GetPayout(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/admin/payouts/%s", id), nil)
+}
+
+// ExportPayout fetches a payout as CSV
+func (c *DriverServiceClient) ExportPayout(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/admin/payouts/%s/export", id), nil)
+}
+
+// GetUtilizationReport forwards a utilization report request; query carries from, to and format
+func (c *DriverServiceClient) GetUtilizationReport(query url.Values) (*http.Response, error) {
+	path := "/api/v1/admin/reports/utilization"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetLocationAnomalies forwards a location anomaly report request; query carries from, to and limit
+func (c *DriverServiceClient) GetLocationAnomalies(query url.Values) (*http.Response, error) {
+	path := "/api/v1/admin/location-anomalies"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return c.doRequest("GET", path, nil)
+}
+
+// GetOpenAPISpec fetches the Swagger spec the driver service generated from its annotations
+func (c *DriverServiceClient) GetOpenAPISpec(ctx context.Context) ([]byte, error) {
+	resp, err := c.doRequestContext(ctx, "GET", "/swagger/doc.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	return readResponse(resp)
 }
 
 func (c *DriverServiceClient) doRequest(method, path string, body interface{}) (*http.Response, error) {
-	url := c.baseURL + path
+	return c.doRequestContext(context.Background(), method, path, body)
+}
 
-	var reqBody io.Reader
-	if body != nil {
+// doRequestContext sends a request that is abandoned when ctx is done
+func (c *DriverServiceClient) doRequestContext(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequestHeader(ctx, method, path, body, nil)
+}
+
+// doRequestHeader sends a request with extra headers
+func (c *DriverServiceClient) doRequestHeader(ctx context.Context, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	baseURL := c.baseURL
+	if isAdminPath(path) {
+		if c.adminURL != "" {
+			baseURL = c.adminURL
+		}
+	} else {
+		baseURL = c.pickUpstream()
+	}
+	return c.doRequestTo(ctx, baseURL, method, path, body, header)
+}
+
+// isAdminPath reports whether path is an admin call or health check, which are
+// not limited and may be served on the driver service's admin port
+func isAdminPath(path string) bool {
+	return path == "/health" || strings.HasPrefix(path, "/api/v1/admin/")
+}
+
+// doRequestTo sends a request to the driver service instance at baseURL
+func (c *DriverServiceClient) doRequestTo(ctx context.Context, baseURL, method, path string, body interface{}, header http.Header) (*http.Response, error) {
+	url := baseURL + path
+
+	var data []byte
+	if raw, ok := body.([]byte); ok {
+		// Raw bodies are sent byte for byte, so signatures over them still match
+		data = raw
+	} else if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		data = jsonData
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
+	ctx, timeout, cancel := c.withTimeout(ctx, method, path)
+	compressed := c.compressBody(data)
+	req, err := c.newRequest(ctx, method, url, data, compressed, body != nil, header)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		cancel()
+		return nil, err
 	}
 
 	c.logger.Debug("forwarding request to driver service",
@@ -97,8 +701,31 @@ func (c *DriverServiceClient) doRequest(method, path string, body interface{}) (
 		zap.String("url", url),
 	)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req, path)
+	if err == nil && compressed != nil {
+		if resp.StatusCode == http.StatusUnsupportedMediaType {
+			// The instance does not take gzip after all, e.g. during a rollback;
+			// wait for the next advertisement and send this body as it is
+			c.gzipRequests.Store(false)
+			resp.Body.Close()
+			if req, err = c.newRequest(ctx, method, url, data, nil, true, header); err != nil {
+				cancel()
+				return nil, err
+			}
+			resp, err = c.send(req, path)
+		}
+	}
+	if err != nil && timedOut(ctx) {
+		cancel()
+		c.logger.Warn("driver service call timed out",
+			zap.String("method", method),
+			zap.String("url", url),
+			zap.Duration("timeout", timeout),
+		)
+		return timeoutResponse(req, timeout), nil
+	}
 	if err != nil {
+		cancel()
 		c.logger.Error("failed to forward request to driver service",
 			zap.Error(err),
 			zap.String("method", method),
@@ -106,6 +733,215 @@ func (c *DriverServiceClient) doRequest(method, path string, body interface{}) (
 		)
 		return nil, fmt.Errorf("failed to forward request: %w", err)
 	}
+	c.learnEncodings(resp)
 
+	if err := decompressBody(resp); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return resp, nil
 }
+
+// newRequest builds a driver service request carrying data, or compressed in its place
+func (c *DriverServiceClient) newRequest(ctx context.Context, method, url string, data, compressed []byte, hasBody bool, header http.Header) (*http.Request, error) {
+	var reqBody io.Reader
+	switch {
+	case compressed != nil:
+		reqBody = bytes.NewReader(compressed)
+	case hasBody:
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.sign != nil {
+		signed, err := c.sign()
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+		req.Header.Set(GatewayTokenHeader, signed)
+	}
+	c.identity.setHeaders(req.Header)
+	if c.consistencyToken != "" {
+		req.Header.Set(ConsistencyTokenHeader, c.consistencyToken)
+	}
+	if c.requestID != "" {
+		req.Header.Set(requestIDHeader, c.requestID)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if compressed != nil {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	// Setting the header ourselves turns off the transport's implicit gzip
+	// handling, so responses are decompressed by decompressBody instead
+	if c.compress {
+		req.Header.Set("Accept-Encoding", "gzip")
+	} else {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+	return req, nil
+}
+
+// compressBody gzips a request body when compression is on, the body is large
+// enough and the driver service has said it accepts gzip; nil otherwise
+func (c *DriverServiceClient) compressBody(data []byte) []byte {
+	if !c.compress || len(data) == 0 || len(data) < c.compressMin || !c.gzipRequests.Load() {
+		return nil
+	}
+	var buf bytes.Buffer
+	gz, _ := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if _, err := gz.Write(data); err != nil {
+		return nil
+	}
+	if err := gz.Close(); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// learnEncodings remembers whether the driver service accepts gzip request
+// bodies, which it advertises with Accept-Encoding on its responses (RFC 7694).
+// The header is dropped: it speaks for the driver service, not the gateway.
+func (c *DriverServiceClient) learnEncodings(resp *http.Response) {
+	advertised := resp.Header.Get("Accept-Encoding")
+	resp.Header.Del("Accept-Encoding")
+	if c.compress && advertised != "" {
+		c.gzipRequests.Store(strings.Contains(strings.ToLower(advertised), "gzip"))
+	}
+}
+
+// send performs the request within the adaptive concurrency limit
+func (c *DriverServiceClient) send(req *http.Request, path string) (*http.Response, error) {
+	if c.limiter == nil || isAdminPath(path) {
+		return c.timedDo(req)
+	}
+
+	release, ok := c.limiter.Acquire()
+	if !ok {
+		c.logger.Warn("driver service concurrency limit reached, rejecting request",
+			zap.String("method", req.Method),
+			zap.String("path", path),
+			zap.Int("limit", c.limiter.Stats().Limit),
+		)
+		return overloadedResponse(req, c.retryAfter), nil
+	}
+
+	resp, err := c.timedDo(req)
+	switch {
+	case err != nil && req.Context().Err() != nil && !timedOut(req.Context()):
+		// The caller gave up; that says nothing about the driver service
+		release(adaptive.Ignore)
+	case err != nil, resp.StatusCode >= http.StatusInternalServerError:
+		release(adaptive.Failure)
+	default:
+		release(adaptive.Success)
+	}
+	return resp, err
+}
+
+// timedDo sends a request and records how long the driver service took to answer
+func (c *DriverServiceClient) timedDo(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	elapsed := time.Since(start)
+	if err == nil || req.Context().Err() == nil {
+		c.latency.observe(elapsed)
+	}
+	c.observeUpstream(req, elapsed, resp, err)
+	return resp, err
+}
+
+// overloadedResponse is the 503 returned in place of a request shed by the limiter
+func overloadedResponse(req *http.Request, retryAfter time.Duration) *http.Response {
+	body := `{"error":{"code":"SERVICE_UNAVAILABLE","message":"driver service is overloaded, retry later"}}`
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+	return &http.Response{
+		Status:        "503 Service Unavailable",
+		StatusCode:    http.StatusServiceUnavailable,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// decompressBody replaces a gzip-encoded body with its decoded stream. The
+// encoding headers are dropped so handlers copying upstream headers describe
+// the body they actually send.
+func decompressBody(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return nil
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		if err == io.EOF {
+			// Empty body, e.g. 204 with a stray header
+			resp.Header.Del("Content-Encoding")
+			return nil
+		}
+		return fmt.Errorf("failed to decompress driver service response: %w", err)
+	}
+
+	resp.Body = gzipBody{Reader: gz, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody closes both the gzip reader and the underlying connection body
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// readResponse reads and closes a driver service response, turning non-2xx
+// statuses into an UpstreamError
+func readResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read driver service response: %w", err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return body, nil
+	}
+
+	upstream := &UpstreamError{Status: resp.StatusCode, Code: "UPSTREAM_ERROR", Message: http.StatusText(resp.StatusCode)}
+	var errResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &errResp) == nil && errResp.Error.Code != "" {
+		upstream.Code = errResp.Error.Code
+		upstream.Message = errResp.Error.Message
+	}
+	return nil, upstream
+}
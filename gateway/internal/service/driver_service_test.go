@@ -1,12 +1,24 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/bitaksi/gateway/internal/adaptive"
+	"github.com/bitaksi/gateway/internal/apimodel"
+	"github.com/bitaksi/gateway/internal/canary"
+	"github.com/bitaksi/gateway/internal/hedge"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -149,7 +161,7 @@ func TestDriverServiceClient_ListDrivers(t *testing.T) {
 			defer server.Close()
 
 			client := NewDriverServiceClient(server.URL, logger)
-			resp, err := client.ListDrivers(tt.page, tt.pageSize)
+			resp, err := client.ListDrivers(apimodel.ListDriversQuery{Page: tt.page, PageSize: tt.pageSize})
 			assert.NoError(t, err)
 			assert.NotNil(t, resp)
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -196,7 +208,7 @@ func TestDriverServiceClient_FindNearbyDrivers(t *testing.T) {
 			defer server.Close()
 
 			client := NewDriverServiceClient(server.URL, logger)
-			resp, err := client.FindNearbyDrivers(tt.lat, tt.lon, tt.taksiType)
+			resp, err := client.FindNearbyDrivers(tt.lat, tt.lon, tt.taksiType, "", "", "", "", "", "", "", "")
 			assert.NoError(t, err)
 			assert.NotNil(t, resp)
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
@@ -263,3 +275,789 @@ func TestDriverServiceClient_doRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestDriverServiceClient_ContactVerification(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name         string
+		call         func(client *DriverServiceClient) (*http.Response, error)
+		expectedPath string
+		method       string
+	}{
+		{
+			name: "set availability",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.SetAvailability("driver-1", map[string]interface{}{"available": true})
+			},
+			expectedPath: "/api/v1/drivers/driver-1/availability",
+			method:       "PUT",
+		},
+		{
+			name: "send phone verification",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.SendPhoneVerification("driver-1")
+			},
+			expectedPath: "/api/v1/drivers/driver-1/verify-phone/send",
+			method:       "POST",
+		},
+		{
+			name: "verify phone",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.VerifyPhone("driver-1", map[string]interface{}{"code": "123456"})
+			},
+			expectedPath: "/api/v1/drivers/driver-1/verify-phone",
+			method:       "POST",
+		},
+		{
+			name: "submit identity check",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.SubmitIdentityCheck("driver-1")
+			},
+			expectedPath: "/api/v1/drivers/driver-1/kyc",
+			method:       "POST",
+		},
+		{
+			name: "get identity check",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.GetIdentityCheck("driver-1")
+			},
+			expectedPath: "/api/v1/drivers/driver-1/kyc",
+			method:       "GET",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.method, r.Method)
+				assert.Equal(t, tt.expectedPath, r.URL.Path)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			resp, err := tt.call(NewDriverServiceClient(server.URL, logger))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestDriverServiceClient_ForwardKYCWebhook(t *testing.T) {
+	// Whitespace and key order must survive so the signature still matches
+	body := []byte(`{ "status": "approved",  "reference": "sbx_1" }`)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/kyc/webhook", r.URL.Path)
+		assert.Equal(t, "t=1,v1=abc", r.Header.Get("X-KYC-Signature"))
+		received, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := NewDriverServiceClient(server.URL, zap.NewNop()).ForwardKYCWebhook(body, "t=1,v1=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestDriverServiceClient_Trips(t *testing.T) {
+	logger := zap.NewNop()
+
+	tests := []struct {
+		name         string
+		call         func(client *DriverServiceClient) (*http.Response, error)
+		expectedPath string
+		method       string
+	}{
+		{
+			name: "request trip",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.RequestTrip(map[string]interface{}{"pickup": map[string]float64{"lat": 41.04, "lon": 29.01}})
+			},
+			expectedPath: "/api/v1/trips",
+			method:       "POST",
+		},
+		{
+			name: "get trip",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.GetTrip("trip-1")
+			},
+			expectedPath: "/api/v1/trips/trip-1",
+			method:       "GET",
+		},
+		{
+			name: "decline offer",
+			call: func(client *DriverServiceClient) (*http.Response, error) {
+				return client.TripAction("trip-1", "decline", map[string]interface{}{"driverId": "driver-1"})
+			},
+			expectedPath: "/api/v1/trips/trip-1/decline",
+			method:       "POST",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tt.method, r.Method)
+				assert.Equal(t, tt.expectedPath, r.URL.Path)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			resp, err := tt.call(NewDriverServiceClient(server.URL, logger))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestDriverServiceClient_GetDriverStats(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/drivers/driver-1/stats", r.URL.Path)
+		gotQuery = r.URL.RawQuery
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	resp, err := client.GetDriverStats("driver-1", "2025-11-01", "2025-12-01T00:00:00+03:00")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "from=2025-11-01&to=2025-12-01T00%3A00%3A00%2B03%3A00", gotQuery)
+
+	resp, err = client.GetDriverStats("driver-1", "", "")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, gotQuery)
+}
+
+func TestDriverServiceClient_GetDriverChanges(t *testing.T) {
+	var gotURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURI = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	resp, err := client.GetDriverChanges("2025-12-06T01:00:00+03:00", "50", "fleet-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/changes?fleetId=fleet-1&limit=50&since=2025-12-06T01%3A00%3A00%2B03%3A00", gotURI)
+
+	resp, err = client.GetDriverChanges("", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/changes", gotURI)
+}
+
+func TestDriverServiceClient_AutocompleteDrivers(t *testing.T) {
+	var gotURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotURI = r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	resp, err := client.AutocompleteDrivers("şükrü 34", "5", "fleet-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/autocomplete?fleetId=fleet-1&limit=5&q=%C5%9F%C3%BCkr%C3%BC+34", gotURI)
+
+	resp, err = client.AutocompleteDrivers("ahm", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/autocomplete?q=ahm", gotURI)
+}
+
+func TestDriverServiceClient_Heartbeat(t *testing.T) {
+	var gotMethod, gotURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotURI = r.Method, r.URL.RequestURI()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	resp, err := client.Heartbeat("driver-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "POST", gotMethod)
+	assert.Equal(t, "/api/v1/drivers/driver-1/heartbeat", gotURI)
+
+	resp, err = client.GetOnlineStats("fleet-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/stats?fleetId=fleet-1", gotURI)
+
+	resp, err = client.GetTenantQuota("fleet-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "GET", gotMethod)
+	assert.Equal(t, "/api/v1/admin/tenants/fleet-1/quota", gotURI)
+
+	resp, err = client.FindNearbyDrivers("41.0431", "29.0099", "", "", "", "true", "", "", "", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/nearby?lat=41.0431&lon=29.0099&live=true", gotURI)
+
+	resp, err = client.FindNearbyDrivers("41.0431", "29.0099", "", "", "istanbul", "", "pet-friendly,quiet", "language:en", "", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/nearby?lat=41.0431&lon=29.0099&city=istanbul&tags=pet-friendly%2Cquiet&attributes=language%3Aen", gotURI)
+
+	resp, err = client.FindNearbyDrivers("41.0431", "29.0099", "", "", "", "", "", "", "mi", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/nearby?lat=41.0431&lon=29.0099&units=mi", gotURI)
+
+	resp, err = client.FindNearbyDrivers("41.0431", "29.0099", "", "", "", "", "", "", "", "10", "20")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "/api/v1/drivers/nearby?lat=41.0431&lon=29.0099&limit=10&offset=20", gotURI)
+
+	resp, err = client.FindDriversAlongRoute(map[string]interface{}{}, "m")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "POST", gotMethod)
+	assert.Equal(t, "/api/v1/drivers/nearby/route?units=m", gotURI)
+}
+
+func TestDriverServiceClient_Webhooks(t *testing.T) {
+	var gotMethod, gotURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotURI = r.Method, r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	tests := []struct {
+		name       string
+		call       func() (*http.Response, error)
+		wantMethod string
+		wantURI    string
+	}{
+		{"create", func() (*http.Response, error) {
+			return client.CreateWebhook(map[string]interface{}{"url": "https://partner.example.com"})
+		}, "POST", "/api/v1/webhooks"},
+		{"list", func() (*http.Response, error) { return client.ListWebhooks("fleet-1") }, "GET", "/api/v1/webhooks?fleetId=fleet-1"},
+		{"get", func() (*http.Response, error) { return client.GetWebhook("sub-1") }, "GET", "/api/v1/webhooks/sub-1"},
+		{"delete", func() (*http.Response, error) { return client.DeleteWebhook("sub-1") }, "DELETE", "/api/v1/webhooks/sub-1"},
+		{"deliveries", func() (*http.Response, error) { return client.ListWebhookDeliveries("sub-1", "10") }, "GET", "/api/v1/webhooks/sub-1/deliveries?limit=10"},
+		{"retry", func() (*http.Response, error) { return client.RetryWebhookDelivery("sub-1", "delivery-1") }, "POST", "/api/v1/webhooks/sub-1/deliveries/delivery-1/retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.call()
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantMethod, gotMethod)
+			assert.Equal(t, tt.wantURI, gotURI)
+		})
+	}
+}
+
+func TestDriverServiceClient_Riders(t *testing.T) {
+	var gotMethod, gotURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotURI = r.Method, r.URL.RequestURI()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+
+	tests := []struct {
+		name       string
+		call       func() (*http.Response, error)
+		wantMethod string
+		wantURI    string
+	}{
+		{"register", func() (*http.Response, error) {
+			return client.RegisterRider(map[string]interface{}{"phone": "+905551234567"})
+		}, "POST", "/api/v1/riders"},
+		{"get", func() (*http.Response, error) { return client.GetRider("rider-1") }, "GET", "/api/v1/riders/rider-1"},
+		{"update", func() (*http.Response, error) {
+			return client.UpdateRider("rider-1", map[string]interface{}{"lastName": "Kaya"})
+		}, "PUT", "/api/v1/riders/rider-1"},
+		{"add favorite", func() (*http.Response, error) {
+			return client.AddFavoriteLocation("rider-1", map[string]interface{}{"label": "Home"})
+		}, "POST", "/api/v1/riders/rider-1/favorites"},
+		{"remove favorite", func() (*http.Response, error) { return client.RemoveFavoriteLocation("rider-1", "fav_1") }, "DELETE", "/api/v1/riders/rider-1/favorites/fav_1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.call()
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantMethod, gotMethod)
+			assert.Equal(t, tt.wantURI, gotURI)
+		})
+	}
+}
+
+func TestDriverServiceClient_Decompression(t *testing.T) {
+	payload := strings.Repeat(`{"id":"driver"},`, 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write([]byte(payload))
+		gz.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	resp, err := NewDriverServiceClient(server.URL, zap.NewNop()).GetDriver("test-id")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(body))
+	assert.Empty(t, resp.Header.Get("Content-Encoding"))
+	assert.Empty(t, resp.Header.Get("Content-Length"))
+}
+
+func TestDriverServiceClient_RequestCompression(t *testing.T) {
+	large := map[string]string{"firstName": strings.Repeat("a", 2000)}
+
+	t.Run("compresses bodies once the driver service advertises gzip", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			body := io.Reader(r.Body)
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(r.Body)
+				require.NoError(t, err)
+				body = gz
+			}
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"firstName"`)
+
+			w.Header().Set("Accept-Encoding", "gzip")
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		client := NewDriverServiceClient(server.URL, zap.NewNop())
+		for i := 0; i < 2; i++ {
+			resp, err := client.CreateDriver(large)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Empty(t, resp.Header.Get("Accept-Encoding"), "the advertisement is not passed on")
+		}
+		// Small bodies are not worth compressing
+		resp, err := client.CreateDriver(map[string]string{"firstName": "a"})
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, []string{"", "gzip", ""}, encodings)
+	})
+
+	t.Run("falls back when gzip is refused", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			if r.Header.Get("Content-Encoding") != "" {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			if len(encodings) == 1 {
+				w.Header().Set("Accept-Encoding", "gzip")
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := NewDriverServiceClient(server.URL, zap.NewNop())
+		for i := 0; i < 3; i++ {
+			resp, err := client.UpdateDriver("d1", large)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+		assert.Equal(t, []string{"", "gzip", "", ""}, encodings)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "identity", r.Header.Get("Accept-Encoding"))
+			assert.Empty(t, r.Header.Get("Content-Encoding"))
+			w.Header().Set("Accept-Encoding", "gzip")
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := NewDriverServiceClient(server.URL, zap.NewNop())
+		client.Compress(false, 0)
+		for i := 0; i < 2; i++ {
+			resp, err := client.UpdateDriver("d1", large)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+	})
+}
+
+func TestDriverServiceClient_LimitConcurrency(t *testing.T) {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/drivers/slow" {
+			close(entered)
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	limiter := adaptive.New(adaptive.Options{InitialLimit: 1, MinLimit: 1, MaxLimit: 1})
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	client.LimitConcurrency(limiter, 2*time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := client.GetDriver("slow")
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	// The limit is taken, so the request is shed without reaching the driver service
+	resp, err := client.GetDriver("fast")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	assert.Contains(t, string(body), `"SERVICE_UNAVAILABLE"`)
+
+	// Admin calls are not limited
+	resp, err = client.GetSaturationStats()
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	close(release)
+	<-done
+	stats := limiter.Stats()
+	assert.Equal(t, int64(1), stats.Rejected)
+	assert.Equal(t, int64(1), stats.Successes)
+	assert.Equal(t, 0, stats.InFlight)
+}
+
+func TestDriverServiceClient_Hedge(t *testing.T) {
+	slow := make(chan struct{})
+	defer close(slow)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		select {
+		case <-slow:
+		case <-r.Context().Done():
+		}
+		w.Write([]byte(`{"from":"primary"}`))
+	}))
+	defer primary.Close()
+	var hedged []string
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hedged = append(hedged, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"from":"secondary"}`))
+	}))
+	defer secondary.Close()
+
+	client := NewDriverServiceClient(primary.URL, zap.NewNop())
+	hedger := hedge.New(hedge.Options{Delay: 20 * time.Millisecond, Percentile: 0, Budget: 1, Targets: []string{secondary.URL}})
+	client.Hedge(hedger)
+
+	resp, err := client.FindNearbyDrivers("41.0", "29.0", "", "", "", "", "", "", "", "", "")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.JSONEq(t, `{"from":"secondary"}`, string(body), "the hedge answers first")
+	assert.Equal(t, []string{"GET /api/v1/drivers/nearby"}, hedged)
+
+	// Writes are never hedged
+	resp, err = client.Heartbeat("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Len(t, hedged, 1)
+
+	stats := hedger.Stats()
+	assert.Equal(t, int64(1), stats.Requests)
+	assert.Equal(t, int64(1), stats.Hedged)
+	assert.Equal(t, int64(1), stats.HedgeWins)
+}
+
+func TestDriverServiceClient_HedgeNotNeeded(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"id":"d1"}`))
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	hedger := hedge.New(hedge.Options{Delay: time.Second, Percentile: 0, Budget: 1})
+	client.Hedge(hedger)
+
+	resp, err := client.GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, calls, "fast reads are not hedged")
+	assert.Equal(t, int64(0), hedger.Stats().Hedged)
+}
+
+func TestDriverServiceClient_CheckHealth(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/health", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	_, err := client.CheckHealth(context.Background())
+	require.NoError(t, err)
+
+	status = http.StatusServiceUnavailable
+	_, err = client.CheckHealth(context.Background())
+	assert.EqualError(t, err, "driver service health check returned 503")
+
+	assert.Equal(t, 2, client.LatencyStats().Samples)
+}
+
+func TestDriverServiceClient_RouteAdmin(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/drivers/d1", r.URL.Path, "only API calls reach the public port")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer api.Close()
+	var adminPaths []string
+	admin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adminPaths = append(adminPaths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer admin.Close()
+
+	client := NewDriverServiceClient(api.URL, zap.NewNop())
+	client.RouteAdmin(admin.URL)
+
+	resp, err := client.GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.GetIndexes()
+	require.NoError(t, err)
+	resp.Body.Close()
+	_, err = client.WithIdentity(Identity{Role: "admin"}).CheckHealth(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/api/v1/admin/indexes", "/health"}, adminPaths)
+}
+
+func TestDriverServiceClient_Timeouts(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/drivers/nearby" {
+			// Slower than the nearby timeout
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Write([]byte(`{"id":"d1"}`))
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	client.SetTimeouts(Timeouts{Nearby: 20 * time.Millisecond, Default: time.Second})
+
+	resp, err := client.FindNearbyDrivers("41.0", "29.0", "", "", "", "", "", "", "", "", "")
+	require.NoError(t, err, "a timeout is answered, not returned as an error")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.JSONEq(t, `{"error":{"code":"UPSTREAM_TIMEOUT","message":"driver service did not answer within 20ms"}}`, string(body))
+
+	resp, err = client.GetDriver("d1")
+	require.NoError(t, err)
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"id":"d1"}`, string(body), "the body is still readable after the call returned")
+}
+
+func TestDriverServiceClient_TimeoutClasses(t *testing.T) {
+	client := NewDriverServiceClient("http://driver-service", zap.NewNop())
+	assert.Zero(t, client.timeoutFor("GET", "/api/v1/drivers/d1"), "no timeouts until they are set")
+
+	client.SetTimeouts(Timeouts{Nearby: 2 * time.Second, Default: 5 * time.Second, Bulk: 2 * time.Minute, Admin: 30 * time.Second})
+	tests := []struct {
+		method, path string
+		want         time.Duration
+	}{
+		{"GET", "/api/v1/drivers/nearby?lat=41&lon=29", 2 * time.Second},
+		{"POST", "/api/v1/drivers/nearby/route", 2 * time.Second},
+		{"GET", "/api/v1/drivers/d1", 5 * time.Second},
+		{"PUT", "/api/v1/drivers/d1", 5 * time.Second},
+		{"POST", "/api/v1/trips", 5 * time.Second},
+		{"POST", "/api/v1/drivers/d1/documents", 2 * time.Minute},
+		{"POST", "/api/v1/drivers/bulk-update", 2 * time.Minute},
+		{"DELETE", "/api/v1/drivers/d1/documents/license", 5 * time.Second},
+		{"GET", "/api/v1/exports/e1/download", 2 * time.Minute},
+		{"GET", "/api/v1/exports/e1", 5 * time.Second},
+		{"GET", "/api/v1/admin/payouts/p1/export", 2 * time.Minute},
+		{"GET", "/api/v1/admin/indexes", 30 * time.Second},
+		{"GET", "/health", 30 * time.Second},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, client.timeoutFor(tt.method, tt.path), "%s %s", tt.method, tt.path)
+	}
+}
+
+func TestLatencyRecorder_Percentiles(t *testing.T) {
+	recorder := newLatencyRecorder()
+	assert.Equal(t, LatencyStats{}, recorder.stats())
+
+	for i := 1; i <= latencySamples+100; i++ {
+		// The first 100 samples are pushed out of the ring
+		recorder.observe(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, LatencyStats{Samples: latencySamples, P50Ms: 600, P90Ms: 1000, P99Ms: 1090, MaxMs: 1100}, recorder.stats())
+}
+
+func TestDriverServiceClient_WithConsistencyToken(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get(ConsistencyTokenHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	resp, err := client.WithConsistencyToken("1765000000.7").GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"1765000000.7", ""}, tokens, "the token is scoped to the returned client")
+}
+
+func TestDriverServiceClient_WithRequestID(t *testing.T) {
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	resp, err := client.WithRequestID("req-1").GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	resp, err = client.GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"req-1", ""}, ids, "the request ID is scoped to the returned client")
+}
+
+func TestDriverServiceClient_SignCalls(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get(GatewayTokenHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewDriverServiceClient(server.URL, zap.NewNop())
+	resp, err := client.GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	client.SignCalls(func() (string, error) { return "signed", nil })
+	resp, err = client.WithIdentity(Identity{UserID: "admin-1"}).GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"", "signed"}, tokens, "scoped clients sign their calls too")
+
+	client.SignCalls(func() (string, error) { return "", assert.AnError })
+	_, err = client.GetDriver("d1")
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Len(t, tokens, 2, "unsigned calls are not sent")
+}
+
+func TestDriverServiceClient_RouteCanary(t *testing.T) {
+	var primaryPaths []string
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryPaths = append(primaryPaths, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primary.Close()
+	canaryRequests := 0
+	canaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		canaryRequests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer canaryServer.Close()
+
+	client := NewDriverServiceClient(primary.URL, zap.NewNop())
+	router := canary.New(canary.Options{URL: canaryServer.URL, AllowHeader: true, MaxErrorRate: 0.5, MinRequests: 2})
+	client.RouteCanary(router)
+
+	// Callers asking for the canary get it until it fails too often
+	for i := 0; i < 2; i++ {
+		resp, err := client.WithCanaryHeader("true").GetDriver("d1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(canary.Header))
+	}
+	resp, err := client.WithCanaryHeader("true").GetDriver("d1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "traffic fell back to the primary")
+	assert.Empty(t, resp.Header.Get(canary.Header))
+	_, err = client.WithCanaryHeader("true").CheckHealth(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, canaryRequests)
+	assert.Equal(t, []string{"/api/v1/drivers/d1", "/health"}, primaryPaths)
+	stats := router.Stats()
+	assert.Equal(t, "fallback", stats.State)
+	assert.Equal(t, int64(2), stats.Canary.Failures)
+	assert.Equal(t, int64(1), stats.Primary.Requests, "health checks are not scored")
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// hedgeAttempt is the outcome of one request of a hedged pair
+type hedgeAttempt struct {
+	resp   *http.Response
+	err    error
+	hedge  bool
+	cancel context.CancelFunc
+}
+
+// usable reports whether the attempt can answer the caller without waiting
+// for the other request
+func (a hedgeAttempt) usable() bool {
+	return a.err == nil && a.resp.StatusCode < http.StatusInternalServerError
+}
+
+// discard releases an attempt nobody will read
+func (a hedgeAttempt) discard() {
+	a.cancel()
+	if a.resp != nil {
+		a.resp.Body.Close()
+	}
+}
+
+// doHedged sends an idempotent GET. When it is still unanswered after the hedge
+// delay and the budget allows, the same request goes to another driver service
+// instance; the first usable response wins and the other request is cancelled.
+// A request that fails before the delay is not hedged, so hedging never turns
+// into retrying.
+func (c *DriverServiceClient) doHedged(path string) (*http.Response, error) {
+	if c.hedger == nil {
+		return c.doRequest("GET", path, nil)
+	}
+
+	c.hedger.Begin()
+	start := time.Now()
+	results := make(chan hedgeAttempt, 2)
+	send := func(baseURL string, hedge bool) context.CancelFunc {
+		// Each request gets its own context so the winner's body can still be
+		// read after the loser is cancelled
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			resp, err := c.doRequestTo(ctx, baseURL, "GET", path, nil, nil)
+			results <- hedgeAttempt{resp: resp, err: err, hedge: hedge, cancel: cancel}
+		}()
+		return cancel
+	}
+	cancelPrimary := send(c.pickUpstream(), false)
+
+	timer := time.NewTimer(c.hedger.Delay())
+	defer timer.Stop()
+	select {
+	case first := <-results:
+		if first.usable() {
+			c.hedger.Observe(time.Since(start))
+		}
+		return first.deliver()
+	case <-timer.C:
+	}
+
+	if !c.hedger.Allow() {
+		first := <-results
+		if first.usable() {
+			c.hedger.Observe(time.Since(start))
+		}
+		return first.deliver()
+	}
+	cancelHedge := send(c.hedger.Target(c.baseURL), true)
+
+	first := <-results
+	if !first.usable() {
+		second := <-results
+		if !second.usable() {
+			// Both failed; answer with the first request's outcome
+			if second.hedge {
+				second.discard()
+				return first.deliver()
+			}
+			first.discard()
+			return second.deliver()
+		}
+		first.discard()
+		first = second
+	} else {
+		// Cancel the loser and drop its outcome once it gives up
+		if first.hedge {
+			cancelPrimary()
+		} else {
+			cancelHedge()
+		}
+		go func() { (<-results).discard() }()
+	}
+
+	c.hedger.Observe(time.Since(start))
+	c.hedger.Won(first.hedge)
+	return first.deliver()
+}
+
+// deliver hands the attempt to the caller; its context is cancelled once the
+// caller closes the body
+func (a hedgeAttempt) deliver() (*http.Response, error) {
+	if a.err != nil {
+		a.cancel()
+		return nil, a.err
+	}
+	a.resp.Body = cancelOnClose{ReadCloser: a.resp.Body, cancel: a.cancel}
+	return a.resp, nil
+}
+
+// cancelOnClose cancels a request's context when its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
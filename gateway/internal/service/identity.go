@@ -0,0 +1,39 @@
+package service
+
+import "net/http"
+
+// Headers carrying the verified caller identity to the driver service
+const (
+	UserIDHeader   = "X-User-Id"
+	UserRoleHeader = "X-User-Role"
+	TenantIDHeader = "X-Tenant-Id"
+)
+
+// Identity is the caller a request is forwarded for, taken from a verified token
+type Identity struct {
+	UserID string
+	Role   string
+	// TenantID is the fleet a fleet admin manages
+	TenantID string
+}
+
+// WithIdentity returns a client that forwards requests on behalf of identity.
+// The returned client shares the underlying HTTP client.
+func (c *DriverServiceClient) WithIdentity(identity Identity) *DriverServiceClient {
+	scoped := *c
+	scoped.identity = identity
+	return &scoped
+}
+
+// setHeaders writes the identity headers; empty values are left out
+func (i Identity) setHeaders(header http.Header) {
+	for name, value := range map[string]string{
+		UserIDHeader:   i.UserID,
+		UserRoleHeader: i.Role,
+		TenantIDHeader: i.TenantID,
+	} {
+		if value != "" {
+			header.Set(name, value)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ReportIncident forwards a request to report an incident against a driver
+func (c *DriverServiceClient) ReportIncident(driverID string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/incidents", driverID), body)
+}
+
+// ListDriverIncidents forwards a request for a driver's incidents; query carries the filters and page
+func (c *DriverServiceClient) ListDriverIncidents(driverID string, query url.Values) (*http.Response, error) {
+	return c.doRequest("GET", withQuery(fmt.Sprintf("/api/v1/drivers/%s/incidents", driverID), query), nil)
+}
+
+// ListIncidents forwards an incident queue request; query carries the filters and page
+func (c *DriverServiceClient) ListIncidents(query url.Values) (*http.Response, error) {
+	return c.doRequest("GET", withQuery("/api/v1/incidents", query), nil)
+}
+
+// GetIncident forwards a request for an incident
+func (c *DriverServiceClient) GetIncident(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/incidents/%s", id), nil)
+}
+
+// UpdateIncidentStatus forwards a request to move an incident's investigation on
+func (c *DriverServiceClient) UpdateIncidentStatus(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/incidents/%s/status", id), body)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencySamples is how many recent driver service response times the percentiles are taken over
+const latencySamples = 1000
+
+// LatencyStats are percentiles of recent driver service response times
+type LatencyStats struct {
+	// Samples is how many responses the percentiles are taken over
+	Samples int     `json:"samples" example:"1000"`
+	P50Ms   float64 `json:"p50Ms" example:"12.4"`
+	P90Ms   float64 `json:"p90Ms" example:"38.1"`
+	P99Ms   float64 `json:"p99Ms" example:"140.7"`
+	MaxMs   float64 `json:"maxMs" example:"512.3"`
+}
+
+// latencyRecorder keeps the most recent response times in a ring
+type latencyRecorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{samples: make([]time.Duration, 0, latencySamples)}
+}
+
+func (r *latencyRecorder) observe(elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.samples) < latencySamples {
+		r.samples = append(r.samples, elapsed)
+		return
+	}
+	r.samples[r.next] = elapsed
+	r.next = (r.next + 1) % latencySamples
+}
+
+func (r *latencyRecorder) stats() LatencyStats {
+	r.mu.Lock()
+	sorted := append([]time.Duration(nil), r.samples...)
+	r.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return LatencyStats{}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	at := func(percentile float64) float64 {
+		index := int(math.Ceil(percentile/100*float64(len(sorted)))) - 1
+		return milliseconds(sorted[max(index, 0)])
+	}
+	return LatencyStats{
+		Samples: len(sorted),
+		P50Ms:   at(50),
+		P90Ms:   at(90),
+		P99Ms:   at(99),
+		MaxMs:   milliseconds(sorted[len(sorted)-1]),
+	}
+}
+
+// milliseconds rounds a duration to a tenth of a millisecond
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Millisecond)*10) / 10
+}
+
+// LatencyStats returns percentiles of the recent driver service response
+// times, requests shed by the concurrency limit excluded
+func (c *DriverServiceClient) LatencyStats() LatencyStats {
+	return c.latency.stats()
+}
+
+// CheckHealth calls the driver service's health check and returns how long it
+// took to answer; an error when it did not answer 200
+func (c *DriverServiceClient) CheckHealth(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	resp, err := c.doRequestContext(ctx, "GET", "/health", nil)
+	elapsed := time.Since(start)
+	if err != nil {
+		return elapsed, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return elapsed, fmt.Errorf("driver service health check returned %d", resp.StatusCode)
+	}
+	return elapsed, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// Onboarding statuses reported to the caller
+const (
+	OnboardingCompleted           = "completed"
+	OnboardingPendingVerification = "pending_verification"
+	OnboardingRolledBack          = "rolled_back"
+	OnboardingRollbackFailed      = "rollback_failed"
+)
+
+// Step statuses reported per onboarding step
+const (
+	StepDone        = "done"
+	StepPending     = "pending"
+	StepFailed      = "failed"
+	StepCompensated = "compensated"
+	StepSkipped     = "skipped"
+)
+
+// UpstreamError is a non-success response from the driver service
+type UpstreamError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("driver service returned %d %s: %s", e.Status, e.Code, e.Message)
+}
+
+// OnboardingDocument is a document to attach to the new driver
+type OnboardingDocument struct {
+	Type string
+	Body interface{}
+}
+
+// OnboardingRequest describes everything needed to onboard a driver
+type OnboardingRequest struct {
+	Driver    interface{}
+	Documents []OnboardingDocument
+	Available bool
+	// Caller is the identity every step is forwarded for
+	Caller Identity
+}
+
+// OnboardingStep is the outcome of a single onboarding step
+type OnboardingStep struct {
+	Name   string         `json:"name" example:"create_driver"`
+	Status string         `json:"status" example:"done" enums:"done,pending,failed,compensated,skipped"`
+	Error  *UpstreamError `json:"error,omitempty"`
+}
+
+// OnboardingResult is the consolidated outcome of an onboarding
+type OnboardingResult struct {
+	Status   string           `json:"status" example:"completed" enums:"completed,pending_verification,rolled_back,rollback_failed"`
+	DriverID string           `json:"driverId,omitempty" example:"507f1f77bcf86cd799439011"`
+	Driver   json.RawMessage  `json:"driver,omitempty" swaggertype:"object"`
+	Steps    []OnboardingStep `json:"steps"`
+	// FailedStep is the error that triggered the rollback
+	FailedStep *UpstreamError `json:"error,omitempty"`
+}
+
+// sagaStep is one forward action with the compensation that undoes it
+type sagaStep struct {
+	name       string
+	run        func() error
+	compensate func() error
+	// optional steps never trigger a rollback
+	optional bool
+}
+
+// OnboardingService orchestrates driver onboarding across driver service calls.
+// Each step has a compensation; when a required step fails, the completed steps
+// are undone in reverse order so no half-onboarded driver is left behind.
+type OnboardingService struct {
+	drivers *DriverServiceClient
+	logger  *zap.Logger
+}
+
+// NewOnboardingService creates a new onboarding service
+func NewOnboardingService(drivers *DriverServiceClient, logger *zap.Logger) *OnboardingService {
+	return &OnboardingService{
+		drivers: drivers,
+		logger:  logger,
+	}
+}
+
+// Onboard creates the driver, attaches its documents, sends a phone verification
+// code and optionally puts the driver on shift
+func (s *OnboardingService) Onboard(req *OnboardingRequest) *OnboardingResult {
+	result := &OnboardingResult{}
+	drivers := s.drivers.WithIdentity(req.Caller)
+	var created struct {
+		ID    string `json:"id"`
+		Phone string `json:"phone"`
+	}
+
+	steps := []sagaStep{{
+		name: "create_driver",
+		run: func() error {
+			body, err := s.call(drivers.CreateDriver(req.Driver))
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(body, &created); err != nil || created.ID == "" {
+				return &UpstreamError{Status: http.StatusBadGateway, Code: "UPSTREAM_ERROR", Message: "driver service returned no driver ID"}
+			}
+			result.DriverID = created.ID
+			result.Driver = body
+			return nil
+		},
+		compensate: func() error {
+			_, err := s.call(drivers.DeleteDriver(created.ID))
+			return err
+		},
+	}}
+
+	for _, doc := range req.Documents {
+		doc := doc
+		steps = append(steps, sagaStep{
+			name: "upload_document:" + doc.Type,
+			run: func() error {
+				body, err := s.call(drivers.UploadDocument(created.ID, doc.Body))
+				if err == nil {
+					result.Driver = body
+				}
+				return err
+			},
+			compensate: func() error {
+				_, err := s.call(drivers.DeleteDocument(created.ID, doc.Type))
+				return err
+			},
+		})
+	}
+
+	steps = append(steps, sagaStep{
+		name:     "send_phone_verification",
+		optional: true,
+		run: func() error {
+			if created.Phone == "" {
+				return errStepSkipped
+			}
+			_, err := s.call(drivers.SendPhoneVerification(created.ID))
+			return err
+		},
+	})
+
+	if req.Available {
+		steps = append(steps, sagaStep{
+			name: "set_availability",
+			run: func() error {
+				body, err := s.call(drivers.SetAvailability(created.ID, map[string]bool{"available": true}))
+				var upstream *UpstreamError
+				if errors.As(err, &upstream) && upstream.Code == "CONTACT_NOT_VERIFIED" {
+					return errStepPending
+				}
+				if err == nil {
+					result.Driver = body
+				}
+				return err
+			},
+		})
+	}
+
+	s.execute(steps, result)
+	return result
+}
+
+var (
+	errStepSkipped = errors.New("step skipped")
+	errStepPending = errors.New("step pending")
+)
+
+// execute runs the steps in order and compensates completed steps on failure
+func (s *OnboardingService) execute(steps []sagaStep, result *OnboardingResult) {
+	result.Status = OnboardingCompleted
+
+	for i, step := range steps {
+		err := step.run()
+		switch {
+		case err == nil:
+			result.Steps = append(result.Steps, OnboardingStep{Name: step.name, Status: StepDone})
+			continue
+		case errors.Is(err, errStepSkipped):
+			result.Steps = append(result.Steps, OnboardingStep{Name: step.name, Status: StepSkipped})
+			continue
+		case errors.Is(err, errStepPending):
+			result.Steps = append(result.Steps, OnboardingStep{Name: step.name, Status: StepPending})
+			result.Status = OnboardingPendingVerification
+			continue
+		}
+
+		upstream := asUpstreamError(err)
+		result.Steps = append(result.Steps, OnboardingStep{Name: step.name, Status: StepFailed, Error: upstream})
+		if step.optional {
+			s.logger.Warn("optional onboarding step failed", zap.String("step", step.name), zap.Error(err))
+			continue
+		}
+
+		s.logger.Warn("onboarding step failed, rolling back", zap.String("step", step.name), zap.Error(err))
+		result.FailedStep = upstream
+		result.Status = OnboardingRolledBack
+		s.compensate(steps[:i], result)
+		return
+	}
+}
+
+// compensate undoes the completed steps in reverse order
+func (s *OnboardingService) compensate(completed []sagaStep, result *OnboardingResult) {
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensate == nil || result.Steps[i].Status != StepDone {
+			continue
+		}
+		if err := step.compensate(); err != nil {
+			s.logger.Error("onboarding compensation failed",
+				zap.String("step", step.name),
+				zap.String("driverId", result.DriverID),
+				zap.Error(err),
+			)
+			result.Status = OnboardingRollbackFailed
+			continue
+		}
+		result.Steps[i].Status = StepCompensated
+	}
+	if result.Status == OnboardingRolledBack {
+		result.Driver = nil
+	}
+}
+
+// call reads a driver service response, turning non-2xx statuses into an UpstreamError
+func (s *OnboardingService) call(resp *http.Response, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return readResponse(resp)
+}
+
+// asUpstreamError wraps transport failures so every failed step reports a status and code
+func asUpstreamError(err error) *UpstreamError {
+	var upstream *UpstreamError
+	if errors.As(err, &upstream) {
+		return upstream
+	}
+	return &UpstreamError{Status: http.StatusBadGateway, Code: "UPSTREAM_ERROR", Message: err.Error()}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeDriverService records the calls made by the onboarding saga and fails the configured ones
+type fakeDriverService struct {
+	mu    sync.Mutex
+	calls []string
+	fail  map[string]int
+}
+
+func (f *fakeDriverService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	call := r.Method + " " + r.URL.Path
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	status, failed := f.fail[call]
+	f.mu.Unlock()
+
+	if failed {
+		code := "INTERNAL_ERROR"
+		switch status {
+		case http.StatusConflict:
+			code = "CONTACT_NOT_VERIFIED"
+		case http.StatusBadRequest:
+			code = "VALIDATION_ERROR"
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": code, "message": "failed"}})
+		return
+	}
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/drivers":
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "d1", "phone": "+905321234567"})
+	case r.Method == "DELETE":
+		if strings.Contains(r.URL.Path, "/documents/") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": "d1"})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasSuffix(r.URL.Path, "/verify-phone/send"):
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"status": "sent"})
+	default:
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": "d1"})
+	}
+}
+
+func newTestOnboarding(fake *fakeDriverService) (*OnboardingService, func()) {
+	server := httptest.NewServer(fake)
+	return NewOnboardingService(NewDriverServiceClient(server.URL, zap.NewNop()), zap.NewNop()), server.Close
+}
+
+func onboardingRequest(available bool) *OnboardingRequest {
+	return &OnboardingRequest{
+		Driver: map[string]interface{}{"firstName": "Ahmet"},
+		Documents: []OnboardingDocument{
+			{Type: "license", Body: map[string]string{"type": "license", "url": "https://files.example.com/l.pdf"}},
+			{Type: "insurance", Body: map[string]string{"type": "insurance", "url": "https://files.example.com/i.pdf"}},
+		},
+		Available: available,
+	}
+}
+
+func stepStatuses(result *OnboardingResult) map[string]string {
+	statuses := make(map[string]string, len(result.Steps))
+	for _, step := range result.Steps {
+		statuses[step.Name] = step.Status
+	}
+	return statuses
+}
+
+func TestOnboardingService_Completed(t *testing.T) {
+	fake := &fakeDriverService{}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(false))
+
+	assert.Equal(t, OnboardingCompleted, result.Status)
+	assert.Equal(t, "d1", result.DriverID)
+	assert.NotEmpty(t, result.Driver)
+	assert.Equal(t, map[string]string{
+		"create_driver":             StepDone,
+		"upload_document:license":   StepDone,
+		"upload_document:insurance": StepDone,
+		"send_phone_verification":   StepDone,
+	}, stepStatuses(result))
+}
+
+func TestOnboardingService_PendingVerification(t *testing.T) {
+	fake := &fakeDriverService{fail: map[string]int{"PUT /api/v1/drivers/d1/availability": http.StatusConflict}}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(true))
+
+	assert.Equal(t, OnboardingPendingVerification, result.Status)
+	assert.Equal(t, StepPending, stepStatuses(result)["set_availability"])
+	assert.NotContains(t, fake.calls, "DELETE /api/v1/drivers/d1")
+}
+
+func TestOnboardingService_RollsBackOnFailure(t *testing.T) {
+	fake := &fakeDriverService{fail: map[string]int{"POST /api/v1/drivers/d1/documents": http.StatusBadRequest}}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(false))
+
+	assert.Equal(t, OnboardingRolledBack, result.Status)
+	assert.Nil(t, result.Driver)
+	if assert.NotNil(t, result.FailedStep) {
+		assert.Equal(t, http.StatusBadRequest, result.FailedStep.Status)
+		assert.Equal(t, "VALIDATION_ERROR", result.FailedStep.Code)
+	}
+	assert.Equal(t, StepCompensated, result.Steps[0].Status)
+	assert.Equal(t, StepFailed, result.Steps[1].Status)
+	assert.Len(t, result.Steps, 2)
+	assert.Equal(t, "DELETE /api/v1/drivers/d1", fake.calls[len(fake.calls)-1])
+}
+
+func TestOnboardingService_CompensatesInReverseOrder(t *testing.T) {
+	fake := &fakeDriverService{fail: map[string]int{"PUT /api/v1/drivers/d1/availability": http.StatusInternalServerError}}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(true))
+
+	assert.Equal(t, OnboardingRolledBack, result.Status)
+	assert.Equal(t, []string{
+		"DELETE /api/v1/drivers/d1/documents/insurance",
+		"DELETE /api/v1/drivers/d1/documents/license",
+		"DELETE /api/v1/drivers/d1",
+	}, fake.calls[len(fake.calls)-3:])
+}
+
+func TestOnboardingService_RollbackFailed(t *testing.T) {
+	fake := &fakeDriverService{fail: map[string]int{
+		"POST /api/v1/drivers/d1/documents": http.StatusInternalServerError,
+		"DELETE /api/v1/drivers/d1":         http.StatusInternalServerError,
+	}}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(false))
+
+	assert.Equal(t, OnboardingRollbackFailed, result.Status)
+	assert.Equal(t, "d1", result.DriverID)
+	assert.Equal(t, StepDone, result.Steps[0].Status)
+}
+
+func TestOnboardingService_OptionalStepFailureKeepsDriver(t *testing.T) {
+	fake := &fakeDriverService{fail: map[string]int{"POST /api/v1/drivers/d1/verify-phone/send": http.StatusInternalServerError}}
+	svc, closeServer := newTestOnboarding(fake)
+	defer closeServer()
+
+	result := svc.Onboard(onboardingRequest(false))
+
+	assert.Equal(t, OnboardingCompleted, result.Status)
+	assert.Equal(t, StepFailed, stepStatuses(result)["send_phone_verification"])
+	assert.NotContains(t, fake.calls, "DELETE /api/v1/drivers/d1")
+}
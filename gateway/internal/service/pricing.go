@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/apimodel"
+	"go.uber.org/zap"
+)
+
+// maxQuoteBody bounds the pricing service response read for one estimate
+const maxQuoteBody = 64 << 10
+
+// FareQuoteRequest asks what a trip with a driver would cost
+type FareQuoteRequest struct {
+	DriverID string            `json:"driverId"`
+	TaxiType string            `json:"taxiType"`
+	Pickup   apimodel.Location `json:"pickup"`
+	// Dropoff is where the rider is going; without it the pricing service quotes its base fare
+	Dropoff *apimodel.Location `json:"dropoff,omitempty"`
+	// PickupDistanceKm and PickupDurationSec are how far the driver is from the pickup
+	PickupDistanceKm  float64 `json:"pickupDistanceKm"`
+	PickupDurationSec int     `json:"pickupDurationSec"`
+}
+
+// PricingServiceClient handles communication with the pricing service
+type PricingServiceClient struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+	// concurrency bounds the estimates of one EstimateFares call in flight
+	concurrency int
+	// requestID is sent with every request; see WithRequestID
+	requestID string
+}
+
+// NewPricingServiceClient creates a new pricing service client that requests
+// at most concurrency estimates of one search at once
+func NewPricingServiceClient(baseURL string, concurrency int, logger *zap.Logger) *PricingServiceClient {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &PricingServiceClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+		logger:      logger,
+		concurrency: concurrency,
+	}
+}
+
+// WithRequestID returns a client that sends id with every request. An empty
+// id sends none. The returned client shares the underlying HTTP client.
+func (c *PricingServiceClient) WithRequestID(id string) *PricingServiceClient {
+	scoped := *c
+	scoped.requestID = id
+	return &scoped
+}
+
+// EstimateFare asks the pricing service for the fare of one trip
+func (c *PricingServiceClient) EstimateFare(ctx context.Context, quote FareQuoteRequest) (*apimodel.FareQuote, error) {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fare quote request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/v1/quotes", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.requestID != "" {
+		req.Header.Set(requestIDHeader, c.requestID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach pricing service: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxQuoteBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fare quote: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pricing service returned %d for driver %s", resp.StatusCode, quote.DriverID)
+	}
+
+	var fare apimodel.FareQuote
+	if err := json.Unmarshal(body, &fare); err != nil || fare.Currency == "" {
+		return nil, fmt.Errorf("pricing service returned a malformed quote for driver %s", quote.DriverID)
+	}
+	return &fare, nil
+}
+
+// EstimateFares asks for the fares of quotes concurrently, at most the
+// client's concurrency at a time, until ctx is done. Fares are returned in the
+// order of quotes; those that failed or were not estimated in time are nil
+// and counted in failed, so one slow or failing estimate never fails the rest.
+func (c *PricingServiceClient) EstimateFares(ctx context.Context, quotes []FareQuoteRequest) (fares []*apimodel.FareQuote, failed int) {
+	fares = make([]*apimodel.FareQuote, len(quotes))
+	slots := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for i := range quotes {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			// Out of time: the remaining drivers are left without a fare
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fare, err := c.EstimateFare(ctx, quotes[i])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+			fares[i] = fare
+		}(i)
+	}
+	wg.Wait()
+
+	for _, fare := range fares {
+		if fare == nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		if firstErr == nil {
+			firstErr = ctx.Err()
+		}
+		c.logger.Warn("fares missing from nearby search",
+			zap.Int("drivers", len(quotes)),
+			zap.Int("failed", failed),
+			zap.Error(firstErr),
+		)
+	}
+	return fares, failed
+}
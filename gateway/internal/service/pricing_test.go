@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestPricingServiceClient_EstimateFares(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/quotes", r.URL.Path)
+		assert.Equal(t, "req-1", r.Header.Get(requestIDHeader))
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+
+		var quote FareQuoteRequest
+		json.NewDecoder(r.Body).Decode(&quote)
+		switch quote.DriverID {
+		case "failing":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "malformed":
+			w.Write([]byte(`{"amount":"a lot"}`))
+		case "slow":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		default:
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"amount":120,"currency":"TRY"}`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewPricingServiceClient(mockServer.URL, 2, zap.NewNop()).WithRequestID("req-1")
+	quotes := []FareQuoteRequest{{DriverID: "d1"}, {DriverID: "failing"}, {DriverID: "d2"}, {DriverID: "malformed"}, {DriverID: "d3"}, {DriverID: "d4"}}
+	fares, failed := client.EstimateFares(context.Background(), quotes)
+	assert.Equal(t, 2, failed)
+	assert.Len(t, fares, len(quotes))
+	for i, fare := range fares {
+		switch quotes[i].DriverID {
+		case "failing", "malformed":
+			assert.Nil(t, fare)
+		default:
+			if assert.NotNil(t, fare) {
+				assert.Equal(t, 120.0, fare.Amount)
+				assert.Equal(t, "TRY", fare.Currency)
+			}
+		}
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+
+	// Estimates still running at the deadline are given up on
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	fares, failed = client.EstimateFares(ctx, []FareQuoteRequest{{DriverID: "d1"}, {DriverID: "slow"}, {DriverID: "slow"}, {DriverID: "d2"}})
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.NotNil(t, fares[0])
+	assert.Equal(t, 3, failed)
+}
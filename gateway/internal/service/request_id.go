@@ -0,0 +1,13 @@
+package service
+
+// requestIDHeader carries the gateway's request ID to the driver service, so
+// their logs of the same request can be found together
+const requestIDHeader = "X-Request-ID"
+
+// WithRequestID returns a client that sends id with every request. An empty
+// id sends none. The returned client shares the underlying HTTP client.
+func (c *DriverServiceClient) WithRequestID(id string) *DriverServiceClient {
+	scoped := *c
+	scoped.requestID = id
+	return &scoped
+}
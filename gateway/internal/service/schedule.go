@@ -0,0 +1,44 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// CreateShift forwards a request to plan a shift for a driver
+func (c *DriverServiceClient) CreateShift(driverID string, body interface{}) (*http.Response, error) {
+	return c.doRequest("POST", fmt.Sprintf("/api/v1/drivers/%s/shifts", driverID), body)
+}
+
+// ListDriverShifts forwards a driver calendar request; query carries from, to and format
+func (c *DriverServiceClient) ListDriverShifts(driverID string, query url.Values) (*http.Response, error) {
+	return c.doRequest("GET", withQuery(fmt.Sprintf("/api/v1/drivers/%s/shifts", driverID), query), nil)
+}
+
+// ListShifts forwards a calendar request; query carries fleetId, driverId, from, to and format
+func (c *DriverServiceClient) ListShifts(query url.Values) (*http.Response, error) {
+	return c.doRequest("GET", withQuery("/api/v1/shifts", query), nil)
+}
+
+// GetShift forwards a request for a planned shift
+func (c *DriverServiceClient) GetShift(id string) (*http.Response, error) {
+	return c.doRequest("GET", fmt.Sprintf("/api/v1/shifts/%s", id), nil)
+}
+
+// UpdateShift forwards a request to move a planned shift
+func (c *DriverServiceClient) UpdateShift(id string, body interface{}) (*http.Response, error) {
+	return c.doRequest("PUT", fmt.Sprintf("/api/v1/shifts/%s", id), body)
+}
+
+// DeleteShift forwards a request to cancel a planned shift
+func (c *DriverServiceClient) DeleteShift(id string) (*http.Response, error) {
+	return c.doRequest("DELETE", fmt.Sprintf("/api/v1/shifts/%s", id), nil)
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Timeouts bounds how long a driver service call may take, by the class of
+// endpoint it is for. A zero timeout leaves the class unbounded.
+type Timeouts struct {
+	// Nearby covers the nearby and along-route searches
+	Nearby time.Duration
+	// Default covers every call no other class covers
+	Default time.Duration
+	// Bulk covers file transfers, document uploads and export downloads, and
+	// bulk driver updates
+	Bulk time.Duration
+	// Admin covers admin calls and health checks
+	Admin time.Duration
+}
+
+// errTimedOut is the cause of a call cancelled by its class timeout, telling
+// it apart from a caller that gave up
+var errTimedOut = errors.New("driver service call timed out")
+
+// SetTimeouts bounds each call by the timeout of its endpoint class instead of
+// the client's single 30s timeout. A call over its timeout is answered with a
+// 504 UPSTREAM_TIMEOUT response, which handlers pass on like any other driver
+// service error. The timeout covers reading the response body too.
+func (c *DriverServiceClient) SetTimeouts(timeouts Timeouts) {
+	c.timeouts = &timeouts
+	c.httpClient.Timeout = 0
+}
+
+// timeoutFor returns the timeout of the class path belongs to, or 0 when
+// timeouts are not set or the class has none
+func (c *DriverServiceClient) timeoutFor(method, path string) time.Duration {
+	if c.timeouts == nil {
+		return 0
+	}
+	path, _, _ = strings.Cut(path, "?")
+	switch {
+	case isBulkPath(method, path):
+		return c.timeouts.Bulk
+	case isAdminPath(path):
+		return c.timeouts.Admin
+	case strings.HasPrefix(path, "/api/v1/drivers/nearby"):
+		return c.timeouts.Nearby
+	default:
+		return c.timeouts.Default
+	}
+}
+
+// isBulkPath reports whether a call transfers a file or updates many drivers
+func isBulkPath(method, path string) bool {
+	switch {
+	case method == http.MethodPost && path == "/api/v1/drivers/bulk-update":
+		return true
+	case method == http.MethodGet && strings.HasPrefix(path, "/api/v1/exports/") && strings.HasSuffix(path, "/download"):
+		return true
+	case method == http.MethodGet && strings.HasPrefix(path, "/api/v1/admin/payouts/") && strings.HasSuffix(path, "/export"):
+		return true
+	case method == http.MethodPost && strings.HasPrefix(path, "/api/v1/drivers/") && strings.HasSuffix(path, "/documents"):
+		return true
+	}
+	return false
+}
+
+// withTimeout bounds ctx by the timeout of the call's class. The returned
+// cancel must be called once the response body is closed.
+func (c *DriverServiceClient) withTimeout(ctx context.Context, method, path string) (context.Context, time.Duration, context.CancelFunc) {
+	timeout := c.timeoutFor(method, path)
+	if timeout <= 0 {
+		return ctx, 0, func() {}
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errTimedOut)
+	return ctx, timeout, cancel
+}
+
+// timedOut reports whether the call of ctx was cancelled by its class timeout
+func timedOut(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errTimedOut)
+}
+
+// timeoutResponse is the 504 returned in place of a call over its timeout
+func timeoutResponse(req *http.Request, timeout time.Duration) *http.Response {
+	body := fmt.Sprintf(`{"error":{"code":"UPSTREAM_TIMEOUT","message":"driver service did not answer within %s"}}`, timeout)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	return &http.Response{
+		Status:        "504 Gateway Timeout",
+		StatusCode:    http.StatusGatewayTimeout,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,85 @@
+package slo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the Prometheus text exposition format WritePrometheus writes
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the request counters and the precomputed indicator
+// ratios, burn rates and remaining budgets in the Prometheus text format, so
+// alerts can be written against the objectives directly, e.g.
+// gateway_slo_burn_rate{window="1h"} > 14.4 and gateway_slo_burn_rate{window="5m"} > 14.4
+func (t *Tracker) WritePrometheus(w io.Writer) error {
+	report := t.Report()
+	out := bufio.NewWriter(w)
+
+	counter := func(name, help string, value func(ObjectiveStatus) int64) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		for _, o := range report.Objectives {
+			fmt.Fprintf(out, "%s{slo=\"%s\",method=\"%s\",route=\"%s\"} %d\n", name, escape(o.Name), escape(o.Method), escape(o.Route), value(o))
+		}
+	}
+	counter("gateway_sli_requests_total", "Requests to routes with a service level objective.", func(o ObjectiveStatus) int64 {
+		return o.Requests
+	})
+	counter("gateway_sli_errors_total", "Requests to routes with a service level objective answered with a 5xx.", func(o ObjectiveStatus) int64 {
+		return o.Errors
+	})
+	counter("gateway_sli_slow_requests_total", "Requests answered without a 5xx but slower than the latency threshold.", func(o ObjectiveStatus) int64 {
+		return o.SlowRequests
+	})
+
+	type indicator struct {
+		slo, sli string
+		status   *IndicatorStatus
+	}
+	var indicators []indicator
+	for i := range report.Objectives {
+		o := &report.Objectives[i]
+		indicators = append(indicators, indicator{o.Name, SLIAvailability, &o.Availability})
+		if o.Latency != nil {
+			indicators = append(indicators, indicator{o.Name, SLILatency, o.Latency})
+		}
+	}
+
+	gauge := func(name, help string, write func(indicator)) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, ind := range indicators {
+			write(ind)
+		}
+	}
+	gauge("gateway_slo_objective", "Target share of good requests.", func(ind indicator) {
+		fmt.Fprintf(out, "gateway_slo_objective{slo=\"%s\",sli=\"%s\"} %s\n", escape(ind.slo), ind.sli, formatFloat(ind.status.Objective))
+	})
+	gauge("gateway_sli_ratio", "Share of good requests over the rolling window.", func(ind indicator) {
+		for _, window := range ind.status.Windows {
+			fmt.Fprintf(out, "gateway_sli_ratio{slo=\"%s\",sli=\"%s\",window=\"%s\"} %s\n", escape(ind.slo), ind.sli, window.Window, formatFloat(window.Ratio))
+		}
+	})
+	gauge("gateway_slo_burn_rate", "How much faster than sustainable the error budget is spent over the rolling window.", func(ind indicator) {
+		for _, window := range ind.status.Windows {
+			fmt.Fprintf(out, "gateway_slo_burn_rate{slo=\"%s\",sli=\"%s\",window=\"%s\"} %s\n", escape(ind.slo), ind.sli, window.Window, formatFloat(window.BurnRate))
+		}
+	})
+	gauge("gateway_slo_error_budget_remaining", "Share of the error budget left over the longest window.", func(ind indicator) {
+		fmt.Fprintf(out, "gateway_slo_error_budget_remaining{slo=\"%s\",sli=\"%s\"} %s\n", escape(ind.slo), ind.sli, formatFloat(ind.status.BudgetRemaining))
+	})
+
+	return out.Flush()
+}
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
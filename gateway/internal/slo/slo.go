@@ -0,0 +1,254 @@
+// Package slo tracks service level indicators of critical routes and how fast
+// their error budgets burn over rolling windows.
+//
+// Two indicators are kept per objective: availability, the share of requests
+// answered without a 5xx, and latency, the share of those answered within the
+// objective's threshold. Requests are counted in one-minute buckets kept for
+// the longest window, so the report and the precomputed series need no
+// queries over raw metrics. Counts start over when the gateway restarts.
+package slo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+)
+
+// bucketSize is the resolution requests are counted at
+const bucketSize = time.Minute
+
+// Indicator names, used as the sli label of the series
+const (
+	SLIAvailability = "availability"
+	SLILatency      = "latency"
+)
+
+type counts struct {
+	requests int64
+	// errors are requests answered with a 5xx
+	errors int64
+	// slow are requests answered without a 5xx but after the threshold
+	slow int64
+}
+
+func (c *counts) add(other counts) {
+	c.requests += other.requests
+	c.errors += other.errors
+	c.slow += other.slow
+}
+
+type bucket struct {
+	minute int64
+	counts
+}
+
+type objective struct {
+	config.SLOObjective
+
+	mu sync.Mutex
+	// total counts every request since start, for the Prometheus counters
+	total   counts
+	buckets []bucket
+}
+
+// Tracker counts the requests to routes with an objective
+type Tracker struct {
+	objectives []*objective
+	byRoute    map[string]*objective
+	// windows are sorted, the longest last
+	windows []time.Duration
+	now     func() time.Time
+}
+
+// NewTracker creates a tracker for the configured objectives
+func NewTracker(cfg config.SLOConfig) *Tracker {
+	windows := append([]time.Duration(nil), cfg.Windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	if len(windows) == 0 {
+		windows = []time.Duration{time.Hour}
+	}
+	longest := windows[len(windows)-1]
+	size := int((longest + bucketSize - 1) / bucketSize)
+
+	t := &Tracker{
+		byRoute: make(map[string]*objective, len(cfg.Objectives)),
+		windows: windows,
+		now:     time.Now,
+	}
+	for _, cfgObjective := range cfg.Objectives {
+		key := routeKey(cfgObjective.Method, cfgObjective.Route)
+		if _, ok := t.byRoute[key]; ok {
+			continue
+		}
+		o := &objective{SLOObjective: cfgObjective, buckets: make([]bucket, size)}
+		t.objectives = append(t.objectives, o)
+		t.byRoute[key] = o
+	}
+	return t
+}
+
+func routeKey(method, route string) string {
+	return method + " " + route
+}
+
+// Record counts a request to the route; routes without an objective are ignored
+func (t *Tracker) Record(method, route string, status int, latency time.Duration) {
+	o, ok := t.byRoute[routeKey(method, route)]
+	if !ok {
+		return
+	}
+	c := counts{requests: 1}
+	switch {
+	case status >= 500:
+		c.errors = 1
+	case o.LatencyThreshold > 0 && latency > o.LatencyThreshold:
+		c.slow = 1
+	}
+	minute := t.now().Unix() / int64(bucketSize/time.Second)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.total.add(c)
+	b := &o.buckets[minute%int64(len(o.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+	b.add(c)
+}
+
+// Report is the state of every objective
+type Report struct {
+	GeneratedAt time.Time         `json:"generatedAt" example:"2025-12-06T10:00:00Z"`
+	Objectives  []ObjectiveStatus `json:"objectives"`
+}
+
+// ObjectiveStatus is the state of one route's objective
+type ObjectiveStatus struct {
+	Name   string `json:"name" example:"nearby"`
+	Method string `json:"method" example:"GET"`
+	Route  string `json:"route" example:"/drivers/nearby"`
+	// Requests, Errors and SlowRequests count since the gateway started
+	Requests     int64           `json:"requests" example:"184220"`
+	Errors       int64           `json:"errors" example:"41"`
+	SlowRequests int64           `json:"slowRequests" example:"960"`
+	Availability IndicatorStatus `json:"availability"`
+	// Latency is omitted when the objective has no latency threshold
+	Latency *IndicatorStatus `json:"latency,omitempty"`
+}
+
+// IndicatorStatus is how an indicator fares against its objective
+type IndicatorStatus struct {
+	// Objective is the target share of good requests
+	Objective   float64 `json:"objective" example:"0.999"`
+	ThresholdMs int64   `json:"thresholdMs,omitempty" example:"300"`
+	// BudgetRemaining is the share of the error budget left over the longest
+	// window; it goes negative once the budget is overspent
+	BudgetRemaining float64        `json:"budgetRemaining" example:"0.82"`
+	Windows         []WindowStatus `json:"windows"`
+}
+
+// WindowStatus is an indicator over one rolling window
+type WindowStatus struct {
+	Window   string `json:"window" example:"1h"`
+	Requests int64  `json:"requests" example:"5120"`
+	Bad      int64  `json:"bad" example:"3"`
+	// Ratio is the share of good requests; 1 without requests
+	Ratio float64 `json:"ratio" example:"0.99941"`
+	// BurnRate is how much faster than sustainable the error budget is spent;
+	// at 1 it lasts exactly the window
+	BurnRate float64 `json:"burnRate" example:"0.59"`
+}
+
+// Report returns the state of every objective over the rolling windows
+func (t *Tracker) Report() *Report {
+	now := t.now().UTC()
+	report := &Report{GeneratedAt: now, Objectives: make([]ObjectiveStatus, 0, len(t.objectives))}
+	current := now.Unix() / int64(bucketSize/time.Second)
+
+	for _, o := range t.objectives {
+		total, windows := o.snapshot(current, t.windows)
+		status := ObjectiveStatus{
+			Name:         o.Name,
+			Method:       o.Method,
+			Route:        o.Route,
+			Requests:     total.requests,
+			Errors:       total.errors,
+			SlowRequests: total.slow,
+		}
+
+		availability := IndicatorStatus{Objective: o.Availability}
+		for i, window := range t.windows {
+			availability.Windows = append(availability.Windows, windowStatus(window, windows[i].requests, windows[i].errors, o.Availability))
+		}
+		availability.BudgetRemaining = budgetRemaining(availability.Windows)
+		status.Availability = availability
+
+		if o.LatencyThreshold > 0 {
+			latency := &IndicatorStatus{Objective: o.Latency, ThresholdMs: o.LatencyThreshold.Milliseconds()}
+			for i, window := range t.windows {
+				latency.Windows = append(latency.Windows, windowStatus(window, windows[i].requests-windows[i].errors, windows[i].slow, o.Latency))
+			}
+			latency.BudgetRemaining = budgetRemaining(latency.Windows)
+			status.Latency = latency
+		}
+		report.Objectives = append(report.Objectives, status)
+	}
+	return report
+}
+
+// snapshot returns the counts since start and over each window
+func (o *objective) snapshot(current int64, windows []time.Duration) (counts, []counts) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	sums := make([]counts, len(windows))
+	for _, b := range o.buckets {
+		age := current - b.minute
+		if b.requests == 0 || age < 0 {
+			continue
+		}
+		for i, window := range windows {
+			if age < int64(window/bucketSize) {
+				sums[i].add(b.counts)
+			}
+		}
+	}
+	return o.total, sums
+}
+
+func windowStatus(window time.Duration, requests, bad int64, objective float64) WindowStatus {
+	status := WindowStatus{Window: formatWindow(window), Requests: requests, Bad: bad, Ratio: 1}
+	if requests > 0 {
+		errorRatio := float64(bad) / float64(requests)
+		status.Ratio = round(1 - errorRatio)
+		status.BurnRate = round(errorRatio / (1 - objective))
+	}
+	return status
+}
+
+// round drops the floating point noise below a millionth
+func round(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
+
+// budgetRemaining is the error budget left over the longest window, which is
+// listed last
+func budgetRemaining(windows []WindowStatus) float64 {
+	return 1 - windows[len(windows)-1].BurnRate
+}
+
+// formatWindow writes windows the way they are configured, e.g. 5m or 72h
+func formatWindow(window time.Duration) string {
+	switch {
+	case window%time.Hour == 0:
+		return fmt.Sprintf("%dh", window/time.Hour)
+	case window%time.Minute == 0:
+		return fmt.Sprintf("%dm", window/time.Minute)
+	default:
+		return window.String()
+	}
+}
@@ -0,0 +1,95 @@
+package slo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	tracker := NewTracker(config.SLOConfig{
+		Objectives: []config.SLOObjective{
+			{Name: "nearby", Method: "GET", Route: "/drivers/nearby", Availability: 0.99, LatencyThreshold: 300 * time.Millisecond, Latency: 0.9},
+			{Name: "login", Method: "POST", Route: "/auth/login", Availability: 0.999},
+		},
+		Windows: []time.Duration{time.Hour, 5 * time.Minute},
+	})
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestTracker_Report(t *testing.T) {
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	// Half an hour ago: 98 fast, one slow and one failed request
+	now = now.Add(-30 * time.Minute)
+	for i := 0; i < 98; i++ {
+		tracker.Record("GET", "/drivers/nearby", 200, 50*time.Millisecond)
+	}
+	tracker.Record("GET", "/drivers/nearby", 200, time.Second)
+	tracker.Record("GET", "/drivers/nearby", 503, 10*time.Millisecond)
+
+	// Within the last five minutes: ten requests, one failed
+	now = now.Add(28 * time.Minute)
+	for i := 0; i < 9; i++ {
+		tracker.Record("GET", "/drivers/nearby", 429, 10*time.Millisecond)
+	}
+	tracker.Record("GET", "/drivers/nearby", 502, 10*time.Millisecond)
+	tracker.Record("GET", "/drivers/:id", 500, time.Second)
+	now = now.Add(2 * time.Minute)
+
+	report := tracker.Report()
+	require.Len(t, report.Objectives, 2)
+	nearby := report.Objectives[0]
+	assert.Equal(t, int64(110), nearby.Requests)
+	assert.Equal(t, int64(2), nearby.Errors)
+	assert.Equal(t, int64(1), nearby.SlowRequests)
+
+	availability := nearby.Availability
+	require.Len(t, availability.Windows, 2)
+	assert.Equal(t, WindowStatus{Window: "5m", Requests: 10, Bad: 1, Ratio: 0.9, BurnRate: 10}, availability.Windows[0], "10% errors against a 1% budget")
+	assert.Equal(t, "1h", availability.Windows[1].Window)
+	assert.Equal(t, int64(110), availability.Windows[1].Requests)
+	assert.InDelta(t, 1-2.0/110/0.01, availability.BudgetRemaining, 1e-6)
+
+	require.NotNil(t, nearby.Latency)
+	assert.Equal(t, int64(300), nearby.Latency.ThresholdMs)
+	assert.Equal(t, int64(108), nearby.Latency.Windows[1].Requests, "failed requests do not count towards latency")
+	assert.Equal(t, int64(1), nearby.Latency.Windows[1].Bad)
+
+	login := report.Objectives[1]
+	assert.Nil(t, login.Latency)
+	assert.Equal(t, WindowStatus{Window: "5m", Ratio: 1}, login.Availability.Windows[0])
+	assert.Equal(t, 1.0, login.Availability.BudgetRemaining)
+
+	// An hour later the requests have left every window
+	now = now.Add(time.Hour)
+	report = tracker.Report()
+	assert.Equal(t, int64(110), report.Objectives[0].Requests)
+	assert.Equal(t, int64(0), report.Objectives[0].Availability.Windows[1].Requests)
+}
+
+func TestTracker_WritePrometheus(t *testing.T) {
+	now := time.Date(2025, 12, 6, 10, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+	tracker.Record("GET", "/drivers/nearby", 200, time.Second)
+	tracker.Record("GET", "/drivers/nearby", 500, time.Millisecond)
+
+	var out strings.Builder
+	require.NoError(t, tracker.WritePrometheus(&out))
+	metrics := out.String()
+
+	assert.Contains(t, metrics, "# TYPE gateway_sli_requests_total counter\n")
+	assert.Contains(t, metrics, `gateway_sli_requests_total{slo="nearby",method="GET",route="/drivers/nearby"} 2`)
+	assert.Contains(t, metrics, `gateway_sli_errors_total{slo="nearby",method="GET",route="/drivers/nearby"} 1`)
+	assert.Contains(t, metrics, `gateway_slo_objective{slo="login",sli="availability"} 0.999`)
+	assert.Contains(t, metrics, `gateway_sli_ratio{slo="nearby",sli="availability",window="5m"} 0.5`)
+	assert.Contains(t, metrics, `gateway_slo_burn_rate{slo="nearby",sli="latency",window="1h"} 10`)
+	assert.Contains(t, metrics, `gateway_slo_error_budget_remaining{slo="nearby",sli="availability"} -49`)
+	assert.NotContains(t, metrics, `slo="login",sli="latency"`)
+}
@@ -0,0 +1,255 @@
+// Package tap captures proxied request/response pairs for debugging.
+//
+// A tap selects traffic by route (e.g. "/drivers/:id") or by request ID and
+// records matching exchanges into a fixed-size ring buffer. Taps expire on
+// their own, bodies are truncated to a size limit and captures are dropped
+// after a retention period, so taps are safe to leave behind by accident.
+package tap
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned when creating a tap
+var (
+	ErrSelectorRequired = errors.New("route or requestId is required")
+	ErrTTLTooLong       = errors.New("ttl exceeds the maximum allowed")
+)
+
+// Options bounds the memory and lifetime of taps and captures
+type Options struct {
+	BufferSize   int
+	MaxBodyBytes int
+	MaxTTL       time.Duration
+	Retention    time.Duration
+}
+
+// Spec describes a tap to create
+type Spec struct {
+	Route        string
+	RequestID    string
+	TTL          time.Duration
+	MaxBodyBytes int
+}
+
+// Tap is an active capture rule
+type Tap struct {
+	ID           string    `json:"id" example:"a1b2c3d4e5f60718"`
+	Route        string    `json:"route,omitempty" example:"/drivers/:id"`
+	RequestID    string    `json:"requestId,omitempty" example:"req-123"`
+	MaxBodyBytes int       `json:"maxBodyBytes" example:"65536"`
+	Captured     int       `json:"captured" example:"3"`
+	CreatedAt    time.Time `json:"createdAt"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// Capture is a recorded request/response exchange
+type Capture struct {
+	TapID             string            `json:"tapId"`
+	RequestID         string            `json:"requestId,omitempty"`
+	Method            string            `json:"method" example:"PUT"`
+	Path              string            `json:"path" example:"/drivers/507f1f77bcf86cd799439011"`
+	Route             string            `json:"route,omitempty" example:"/drivers/:id"`
+	Status            int               `json:"status" example:"200"`
+	LatencyMs         float64           `json:"latencyMs" example:"12.5"`
+	RequestHeaders    map[string]string `json:"requestHeaders"`
+	RequestBody       string            `json:"requestBody,omitempty"`
+	RequestTruncated  bool              `json:"requestTruncated,omitempty"`
+	ResponseHeaders   map[string]string `json:"responseHeaders"`
+	ResponseBody      string            `json:"responseBody,omitempty"`
+	ResponseTruncated bool              `json:"responseTruncated,omitempty"`
+	CapturedAt        time.Time         `json:"capturedAt"`
+}
+
+// Registry holds the active taps and the ring buffer of captures
+type Registry struct {
+	mu     sync.Mutex
+	opts   Options
+	taps   map[string]*Tap
+	ring   []Capture
+	next   int
+	active atomic.Int32
+	now    func() time.Time
+}
+
+// NewRegistry creates a tap registry with the given limits
+func NewRegistry(opts Options) *Registry {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 200
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = 64 << 10
+	}
+	if opts.MaxTTL <= 0 {
+		opts.MaxTTL = time.Hour
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = time.Hour
+	}
+	return &Registry{
+		opts: opts,
+		taps: make(map[string]*Tap),
+		ring: make([]Capture, 0, opts.BufferSize),
+		now:  time.Now,
+	}
+}
+
+// Add creates a tap. TTL defaults to 10 minutes and the body limit to the registry maximum.
+func (r *Registry) Add(spec Spec) (*Tap, error) {
+	if spec.Route == "" && spec.RequestID == "" {
+		return nil, ErrSelectorRequired
+	}
+	if spec.TTL <= 0 {
+		spec.TTL = 10 * time.Minute
+	}
+	if spec.TTL > r.opts.MaxTTL {
+		return nil, ErrTTLTooLong
+	}
+	if spec.MaxBodyBytes <= 0 || spec.MaxBodyBytes > r.opts.MaxBodyBytes {
+		spec.MaxBodyBytes = r.opts.MaxBodyBytes
+	}
+
+	now := r.now()
+	t := &Tap{
+		ID:           newID(),
+		Route:        spec.Route,
+		RequestID:    spec.RequestID,
+		MaxBodyBytes: spec.MaxBodyBytes,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(spec.TTL),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.taps[t.ID] = t
+	r.active.Store(int32(len(r.taps)))
+	copied := *t
+	return &copied, nil
+}
+
+// Remove deletes a tap and its captures
+func (r *Registry) Remove(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.taps[id]; !ok {
+		return false
+	}
+	delete(r.taps, id)
+	r.active.Store(int32(len(r.taps)))
+	for i := range r.ring {
+		if r.ring[i].TapID == id {
+			r.ring[i] = Capture{}
+		}
+	}
+	return true
+}
+
+// List returns the taps that have not expired
+func (r *Registry) List() []Tap {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+
+	taps := make([]Tap, 0, len(r.taps))
+	for _, t := range r.taps {
+		taps = append(taps, *t)
+	}
+	return taps
+}
+
+// Get returns a tap by ID
+func (r *Registry) Get(id string) (Tap, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+
+	t, ok := r.taps[id]
+	if !ok {
+		return Tap{}, false
+	}
+	return *t, true
+}
+
+// Match returns the tap that selects the request, if any. It is cheap when no tap is active.
+func (r *Registry) Match(route, path, requestID string) (Tap, bool) {
+	if r.active.Load() == 0 {
+		return Tap{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+
+	for _, t := range r.taps {
+		if t.RequestID != "" && t.RequestID == requestID {
+			return *t, true
+		}
+		if t.Route != "" && (t.Route == route || t.Route == path) {
+			return *t, true
+		}
+	}
+	return Tap{}, false
+}
+
+// Record stores a capture, overwriting the oldest one when the buffer is full
+func (r *Registry) Record(c Capture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.taps[c.TapID]
+	if !ok {
+		return
+	}
+	t.Captured++
+
+	if c.CapturedAt.IsZero() {
+		c.CapturedAt = r.now()
+	}
+	if len(r.ring) < cap(r.ring) {
+		r.ring = append(r.ring, c)
+		return
+	}
+	r.ring[r.next] = c
+	r.next = (r.next + 1) % len(r.ring)
+}
+
+// Captures returns the retained captures of a tap, oldest first; an empty ID returns all captures
+func (r *Registry) Captures(tapID string) []Capture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := r.now().Add(-r.opts.Retention)
+	captures := make([]Capture, 0)
+	for i := 0; i < len(r.ring); i++ {
+		c := r.ring[(r.next+i)%len(r.ring)]
+		if c.TapID == "" || c.CapturedAt.Before(cutoff) {
+			continue
+		}
+		if tapID == "" || c.TapID == tapID {
+			captures = append(captures, c)
+		}
+	}
+	return captures
+}
+
+// pruneLocked removes expired taps; captures stay until their retention ends
+func (r *Registry) pruneLocked() {
+	now := r.now()
+	for id, t := range r.taps {
+		if !now.Before(t.ExpiresAt) {
+			delete(r.taps, id)
+		}
+	}
+	r.active.Store(int32(len(r.taps)))
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,96 @@
+package tap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_AddValidation(t *testing.T) {
+	r := NewRegistry(Options{MaxTTL: time.Hour, MaxBodyBytes: 1024})
+
+	_, err := r.Add(Spec{})
+	assert.ErrorIs(t, err, ErrSelectorRequired)
+
+	_, err = r.Add(Spec{Route: "/drivers/:id", TTL: 2 * time.Hour})
+	assert.ErrorIs(t, err, ErrTTLTooLong)
+
+	tp, err := r.Add(Spec{Route: "/drivers/:id", MaxBodyBytes: 1 << 20})
+	require.NoError(t, err)
+	assert.Equal(t, 1024, tp.MaxBodyBytes)
+	assert.Equal(t, 10*time.Minute, tp.ExpiresAt.Sub(tp.CreatedAt))
+}
+
+func TestRegistry_MatchAndExpiry(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry(Options{})
+	r.now = func() time.Time { return now }
+
+	_, ok := r.Match("/drivers/:id", "/drivers/1", "")
+	assert.False(t, ok)
+
+	byRoute, err := r.Add(Spec{Route: "/drivers/:id", TTL: time.Minute})
+	require.NoError(t, err)
+	byRequest, err := r.Add(Spec{RequestID: "req-1", TTL: 5 * time.Minute})
+	require.NoError(t, err)
+
+	matched, ok := r.Match("/drivers/:id", "/drivers/1", "")
+	assert.True(t, ok)
+	assert.Equal(t, byRoute.ID, matched.ID)
+
+	matched, ok = r.Match("/trips", "/trips", "req-1")
+	assert.True(t, ok)
+	assert.Equal(t, byRequest.ID, matched.ID)
+
+	_, ok = r.Match("/trips", "/trips", "req-2")
+	assert.False(t, ok)
+
+	now = now.Add(2 * time.Minute)
+	_, ok = r.Match("/drivers/:id", "/drivers/1", "")
+	assert.False(t, ok, "route tap should have expired")
+	assert.Len(t, r.List(), 1)
+}
+
+func TestRegistry_RingBuffer(t *testing.T) {
+	r := NewRegistry(Options{BufferSize: 3})
+	tp, err := r.Add(Spec{Route: "/drivers"})
+	require.NoError(t, err)
+
+	for _, path := range []string{"/a", "/b", "/c", "/d"} {
+		r.Record(Capture{TapID: tp.ID, Path: path})
+	}
+
+	captures := r.Captures(tp.ID)
+	require.Len(t, captures, 3)
+	assert.Equal(t, "/b", captures[0].Path)
+	assert.Equal(t, "/d", captures[2].Path)
+
+	got, ok := r.Get(tp.ID)
+	require.True(t, ok)
+	assert.Equal(t, 4, got.Captured)
+
+	// Captures for unknown taps are ignored and removing a tap discards its captures
+	r.Record(Capture{TapID: "unknown", Path: "/e"})
+	assert.Len(t, r.Captures(""), 3)
+	assert.True(t, r.Remove(tp.ID))
+	assert.Empty(t, r.Captures(""))
+	assert.False(t, r.Remove(tp.ID))
+}
+
+func TestRegistry_Retention(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry(Options{Retention: time.Minute})
+	r.now = func() time.Time { return now }
+	tp, err := r.Add(Spec{Route: "/drivers", TTL: time.Hour})
+	require.NoError(t, err)
+
+	r.Record(Capture{TapID: tp.ID, Path: "/old"})
+	now = now.Add(2 * time.Minute)
+	r.Record(Capture{TapID: tp.ID, Path: "/new"})
+
+	captures := r.Captures(tp.ID)
+	require.Len(t, captures, 1)
+	assert.Equal(t, "/new", captures[0].Path)
+}
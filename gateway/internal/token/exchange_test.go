@@ -0,0 +1,59 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExchanger_Exchange(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	e := NewExchanger(config.TokenExchangeConfig{Secret: "exchange-secret", TTL: 5 * time.Minute}, "https://gateway.bitaksi.com")
+	e.now = func() time.Time { return now }
+	parse := func(t *testing.T, signed string) jwt.MapClaims {
+		claims := jwt.MapClaims{}
+		_, err := jwt.ParseWithClaims(signed, claims, func(*jwt.Token) (interface{}, error) {
+			return []byte("exchange-secret"), nil
+		}, jwt.WithTimeFunc(func() time.Time { return now }))
+		require.NoError(t, err)
+		return claims
+	}
+
+	t.Run("defaults to reading drivers", func(t *testing.T) {
+		subject := &Claims{Username: "rider-app", Role: RoleRider, RiderID: "6573a1f2c3d4e5f6a7b8c9d0", ExpiresAt: now.Add(time.Hour), Raw: jwt.MapClaims{"sub": "rider-app"}}
+		exchanged, err := e.Exchange(subject, AudienceDriverService, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ScopeDriversRead}, exchanged.Scopes)
+		assert.Equal(t, 5*time.Minute, exchanged.ExpiresIn)
+
+		claims := parse(t, exchanged.Token)
+		assert.Equal(t, "drivers:read", claims["scope"])
+		assert.Equal(t, []interface{}{"driver-service"}, claims["aud"])
+		assert.Equal(t, "https://gateway.bitaksi.com", claims["iss"])
+		assert.Equal(t, "6573a1f2c3d4e5f6a7b8c9d0", claims["riderId"])
+		assert.NotEmpty(t, claims["jti"])
+	})
+
+	t.Run("never outlives the subject token", func(t *testing.T) {
+		subject := &Claims{Username: "admin", ExpiresAt: now.Add(time.Minute), Raw: jwt.MapClaims{}}
+		exchanged, err := e.Exchange(subject, AudienceDriverService, []string{ScopeDriversWrite})
+		require.NoError(t, err)
+		assert.Equal(t, time.Minute, exchanged.ExpiresIn)
+
+		claims := parse(t, exchanged.Token)
+		assert.Equal(t, "admin", claims["sub"])
+		assert.NotContains(t, claims, "role")
+	})
+
+	t.Run("rejects unknown audiences and scopes", func(t *testing.T) {
+		subject := &Claims{Username: "admin", Raw: jwt.MapClaims{}}
+		_, err := e.Exchange(subject, "billing-service", nil)
+		assert.ErrorIs(t, err, ErrInvalidTarget)
+		_, err = e.Exchange(subject, AudienceDriverService, []string{ScopeDriversRead, "drivers:delete"})
+		assert.ErrorIs(t, err, ErrInvalidScope)
+	})
+}
@@ -0,0 +1,39 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewaySigner_Token(t *testing.T) {
+	now := time.Date(2025, 12, 1, 12, 0, 0, 0, time.UTC)
+	s := NewGatewaySigner(config.TokenExchangeConfig{Secret: "exchange-secret", TTL: 4 * time.Minute}, "https://gateway.bitaksi.com")
+	s.now = func() time.Time { return now }
+
+	first, err := s.Token()
+	require.NoError(t, err)
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(first, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("exchange-secret"), nil
+	}, jwt.WithTimeFunc(func() time.Time { return now }), jwt.WithAudience(AudienceGateway))
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", claims["sub"])
+	assert.Equal(t, "https://gateway.bitaksi.com", claims["iss"])
+	assert.Equal(t, float64(now.Add(4*time.Minute).Unix()), claims["exp"])
+	assert.Nil(t, claims["scope"])
+
+	now = now.Add(time.Minute)
+	again, err := s.Token()
+	require.NoError(t, err)
+	assert.Equal(t, first, again, "reused before half the TTL")
+
+	now = now.Add(time.Minute)
+	renewed, err := s.Token()
+	require.NoError(t, err)
+	assert.NotEqual(t, first, renewed, "renewed after half the TTL")
+}
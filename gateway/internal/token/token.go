@@ -0,0 +1,340 @@
+// Package token issues and validates the access tokens signed by the gateway.
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+)
+
+// Roles carried in the "role" claim. Tokens without a role have full access.
+const (
+	RoleFleetAdmin = "fleet_admin"
+	RoleRider      = "rider"
+)
+
+// RoleDriverDevice is the role of requests authenticated with a driver device
+// token instead of an access token; it is never issued in one
+const RoleDriverDevice = "driver_device"
+
+// ErrInvalidToken is returned for tokens that are malformed, expired or wrongly signed
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the validated claims of an access token
+type Claims struct {
+	ID        string
+	Username  string
+	Role      string
+	FleetID   string
+	RiderID   string
+	Issuer    string
+	Audience  []string
+	KeyID     string
+	Algorithm string
+	IssuedAt  time.Time
+	NotBefore time.Time
+	ExpiresAt time.Time
+	Raw       jwt.MapClaims
+}
+
+// signingKey is an RSA key published in the JWKS
+type signingKey struct {
+	id  string
+	key *rsa.PrivateKey
+}
+
+// Manager issues and validates tokens with HS256 or rotating RS256 keys
+type Manager struct {
+	algorithm   string
+	secret      []byte
+	keys        map[string]*signingKey
+	keyOrder    []string
+	active      *signingKey
+	acceptHS256 bool
+	expiration  time.Duration
+	issuer      string
+	audience    []string
+	leeway      time.Duration
+	now         func() time.Time
+}
+
+// NewManager creates a token manager from the JWT configuration. RS256 without
+// configured keys uses a generated key, which only suits single-instance development.
+func NewManager(cfg config.JWTConfig) (*Manager, error) {
+	m := &Manager{
+		algorithm:   cfg.Algorithm,
+		secret:      []byte(cfg.Secret),
+		keys:        make(map[string]*signingKey),
+		acceptHS256: cfg.AcceptHS256,
+		expiration:  cfg.Expiration,
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		leeway:      cfg.ClockSkew,
+		now:         time.Now,
+	}
+	if m.algorithm == "" {
+		m.algorithm = AlgorithmHS256
+	}
+
+	switch m.algorithm {
+	case AlgorithmHS256:
+		return m, nil
+	case AlgorithmRS256:
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", cfg.Algorithm)
+	}
+
+	for _, file := range cfg.RSAKeys {
+		key, err := loadRSAKey(file.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JWT key %q: %w", file.ID, err)
+		}
+		m.addKey(file.ID, key)
+	}
+	if len(m.keys) == 0 {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate JWT key: %w", err)
+		}
+		m.addKey("generated", key)
+		cfg.ActiveKeyID = "generated"
+	}
+
+	active, ok := m.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active JWT key %q is not configured", cfg.ActiveKeyID)
+	}
+	m.active = active
+	return m, nil
+}
+
+// NewHS256Manager creates a manager that signs with a shared secret
+func NewHS256Manager(secret string, expiration time.Duration) *Manager {
+	m, _ := NewManager(config.JWTConfig{Secret: secret, Expiration: expiration, Algorithm: AlgorithmHS256})
+	return m
+}
+
+func (m *Manager) addKey(id string, key *rsa.PrivateKey) {
+	m.keys[id] = &signingKey{id: id, key: key}
+	m.keyOrder = append(m.keyOrder, id)
+}
+
+// Algorithm returns the algorithm used for new tokens
+func (m *Manager) Algorithm() string {
+	return m.algorithm
+}
+
+// IssueOption adds optional claims to an issued token
+type IssueOption func(jwt.MapClaims)
+
+// WithRole sets the "role" claim
+func WithRole(role string) IssueOption {
+	return func(claims jwt.MapClaims) {
+		claims["role"] = role
+	}
+}
+
+// WithFleet sets the "fleetId" claim naming the fleet the user belongs to
+func WithFleet(fleetID string) IssueOption {
+	return func(claims jwt.MapClaims) {
+		claims["fleetId"] = fleetID
+	}
+}
+
+// WithRider sets the "riderId" claim naming the rider the token was issued to
+func WithRider(riderID string) IssueOption {
+	return func(claims jwt.MapClaims) {
+		claims["riderId"] = riderID
+	}
+}
+
+// WithIdentityProvider sets the "idp" claim naming the OpenID Connect issuer
+// the user signed in with
+func WithIdentityProvider(issuer string) IssueOption {
+	return func(claims jwt.MapClaims) {
+		claims["idp"] = issuer
+	}
+}
+
+// Issue creates a signed access token for the user. Every token carries a
+// unique "jti" so it can be tracked for replay.
+func (m *Manager) Issue(username string, opts ...IssueOption) (string, error) {
+	now := m.now()
+	claims := jwt.MapClaims{
+		"jti":      newTokenID(),
+		"sub":      username,
+		"username": username,
+		"exp":      now.Add(m.expiration).Unix(),
+		"iat":      now.Unix(),
+		"nbf":      now.Unix(),
+	}
+	if m.issuer != "" {
+		claims["iss"] = m.issuer
+	}
+	if len(m.audience) > 0 {
+		claims["aud"] = m.audience
+	}
+	for _, opt := range opts {
+		opt(claims)
+	}
+
+	if m.algorithm == AlgorithmRS256 {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = m.active.id
+		return token.SignedString(m.active.key)
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+}
+
+// Parse validates the token signature, its time claims (with the configured
+// clock skew) and, when configured, its issuer and audience, and returns its claims
+func (m *Manager) Parse(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgorithmHS256, AlgorithmRS256}),
+		jwt.WithTimeFunc(m.now),
+		jwt.WithLeeway(m.leeway),
+		jwt.WithIssuedAt(),
+	}
+	if m.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.issuer))
+	}
+
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc, opts...)
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	result := &Claims{Algorithm: token.Method.Alg(), Raw: claims}
+	result.ID, _ = claims["jti"].(string)
+	result.Username, _ = claims["username"].(string)
+	result.Role, _ = claims["role"].(string)
+	result.FleetID, _ = claims["fleetId"].(string)
+	result.RiderID, _ = claims["riderId"].(string)
+	result.Issuer, _ = claims.GetIssuer()
+	result.Audience, _ = claims.GetAudience()
+	result.KeyID, _ = token.Header["kid"].(string)
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		result.IssuedAt = iat.Time
+	}
+	if nbf, err := claims.GetNotBefore(); err == nil && nbf != nil {
+		result.NotBefore = nbf.Time
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		result.ExpiresAt = exp.Time
+	}
+
+	if len(m.audience) > 0 && !intersects(result.Audience, m.audience) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, jwt.ErrTokenInvalidAudience)
+	}
+	return result, nil
+}
+
+// intersects reports whether any token audience is accepted
+func intersects(audience, accepted []string) bool {
+	for _, a := range audience {
+		for _, b := range accepted {
+			if a == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyFunc selects the verification key for a token based on its algorithm and key ID
+func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if m.algorithm == AlgorithmHS256 || m.acceptHS256 {
+			return m.secret, nil
+		}
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := m.keys[kid]; ok {
+			return &key.key.PublicKey, nil
+		}
+	}
+	return nil, jwt.ErrTokenUnverifiable
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty" example:"RSA"`
+	Use string `json:"use" example:"sig"`
+	Kid string `json:"kid" example:"2025-01"`
+	Alg string `json:"alg" example:"RS256"`
+	N   string `json:"n"`
+	E   string `json:"e" example:"AQAB"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys that validate gateway tokens. HS256 secrets are never published.
+func (m *Manager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, id := range m.keyOrder {
+		pub := m.keys[id].key.PublicKey
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Kid: id,
+			Alg: AlgorithmRS256,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return set
+}
+
+// newTokenID returns a random "jti" value
+func newTokenID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// loadRSAKey reads a PKCS#1 or PKCS#8 PEM encoded RSA private key
+func loadRSAKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA private key")
+	}
+	return key, nil
+}
@@ -0,0 +1,204 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bitaksi/gateway/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRSAKey(t *testing.T, dir, name string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name+".pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestManager_HS256(t *testing.T) {
+	m := NewHS256Manager("test-secret", time.Hour)
+
+	tokenString, err := m.Issue("testuser")
+	require.NoError(t, err)
+
+	claims, err := m.Parse(tokenString)
+	require.NoError(t, err)
+	assert.Equal(t, "testuser", claims.Username)
+	assert.Equal(t, AlgorithmHS256, claims.Algorithm)
+	assert.Empty(t, m.JWKS().Keys)
+
+	_, err = NewHS256Manager("other-secret", time.Hour).Parse(tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestManager_Expired(t *testing.T) {
+	m := NewHS256Manager("test-secret", time.Hour)
+	m.now = func() time.Time { return time.Now().Add(-2 * time.Hour) }
+	tokenString, err := m.Issue("testuser")
+	require.NoError(t, err)
+
+	m.now = time.Now
+	_, err = m.Parse(tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestManager_RS256Rotation(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := writeRSAKey(t, dir, "old")
+	newPath := writeRSAKey(t, dir, "new")
+
+	oldManager, err := NewManager(config.JWTConfig{
+		Algorithm:   AlgorithmRS256,
+		Expiration:  time.Hour,
+		RSAKeys:     []config.KeyFile{{ID: "old", Path: oldPath}},
+		ActiveKeyID: "old",
+	})
+	require.NoError(t, err)
+	oldToken, err := oldManager.Issue("testuser")
+	require.NoError(t, err)
+
+	rotated, err := NewManager(config.JWTConfig{
+		Algorithm:   AlgorithmRS256,
+		Expiration:  time.Hour,
+		RSAKeys:     []config.KeyFile{{ID: "old", Path: oldPath}, {ID: "new", Path: newPath}},
+		ActiveKeyID: "new",
+	})
+	require.NoError(t, err)
+
+	newToken, err := rotated.Issue("testuser")
+	require.NoError(t, err)
+	claims, err := rotated.Parse(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "new", claims.KeyID)
+
+	// Tokens signed with the previous key stay valid while it is still published
+	claims, err = rotated.Parse(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "old", claims.KeyID)
+
+	jwks := rotated.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "old", jwks.Keys[0].Kid)
+	assert.Equal(t, "new", jwks.Keys[1].Kid)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	// Once the old key is removed its tokens are rejected
+	_, err = oldManager.Parse(newToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestManager_HS256Fallback(t *testing.T) {
+	legacy, err := NewHS256Manager("test-secret", time.Hour).Issue("testuser")
+	require.NoError(t, err)
+
+	strict, err := NewManager(config.JWTConfig{Algorithm: AlgorithmRS256, Secret: "test-secret", Expiration: time.Hour})
+	require.NoError(t, err)
+	_, err = strict.Parse(legacy)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	lenient, err := NewManager(config.JWTConfig{Algorithm: AlgorithmRS256, Secret: "test-secret", Expiration: time.Hour, AcceptHS256: true})
+	require.NoError(t, err)
+	claims, err := lenient.Parse(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmHS256, claims.Algorithm)
+}
+
+func TestManager_RejectsNoneAlgorithm(t *testing.T) {
+	m := NewHS256Manager("test-secret", time.Hour)
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"username": "testuser",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = m.Parse(unsigned)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestNewManager_Errors(t *testing.T) {
+	_, err := NewManager(config.JWTConfig{Algorithm: "ES256"})
+	assert.Error(t, err)
+
+	_, err = NewManager(config.JWTConfig{
+		Algorithm:   AlgorithmRS256,
+		RSAKeys:     []config.KeyFile{{ID: "missing", Path: filepath.Join(t.TempDir(), "missing.pem")}},
+		ActiveKeyID: "missing",
+	})
+	assert.Error(t, err)
+
+	path := writeRSAKey(t, t.TempDir(), "k1")
+	_, err = NewManager(config.JWTConfig{
+		Algorithm:   AlgorithmRS256,
+		RSAKeys:     []config.KeyFile{{ID: "k1", Path: path}},
+		ActiveKeyID: "k2",
+	})
+	assert.Error(t, err)
+}
+
+func TestManager_IssuerAudienceAndSkew(t *testing.T) {
+	cfg := config.JWTConfig{
+		Secret:     "test-secret",
+		Expiration: time.Hour,
+		Issuer:     "https://gateway.bitaksi.com",
+		Audience:   []string{"taxihub-api", "taxihub-admin"},
+		ClockSkew:  30 * time.Second,
+	}
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+
+	tokenString, err := m.Issue("testuser")
+	require.NoError(t, err)
+	claims, err := m.Parse(tokenString)
+	require.NoError(t, err)
+	assert.NotEmpty(t, claims.ID)
+	assert.Equal(t, cfg.Issuer, claims.Issuer)
+	assert.Equal(t, cfg.Audience, claims.Audience)
+	assert.False(t, claims.NotBefore.IsZero())
+
+	other, err := NewManager(config.JWTConfig{Secret: "test-secret", Expiration: time.Hour, Issuer: "someone-else"})
+	require.NoError(t, err)
+	foreign, err := other.Issue("testuser")
+	require.NoError(t, err)
+	_, err = m.Parse(foreign)
+	assert.ErrorIs(t, err, ErrInvalidToken, "wrong issuer and no audience")
+
+	apiOnly := cfg
+	apiOnly.Audience = []string{"taxihub-api"}
+	narrow, err := NewManager(apiOnly)
+	require.NoError(t, err)
+	_, err = narrow.Parse(tokenString)
+	assert.NoError(t, err, "one matching audience is enough")
+
+	apiOnly.Audience = []string{"billing"}
+	billing, err := NewManager(apiOnly)
+	require.NoError(t, err)
+	_, err = billing.Parse(tokenString)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	// A token issued by a host whose clock runs ahead is accepted within the skew
+	issuedAt := time.Now()
+	m.now = func() time.Time { return issuedAt.Add(20 * time.Second) }
+	ahead, err := m.Issue("testuser")
+	require.NoError(t, err)
+	m.now = func() time.Time { return issuedAt }
+	_, err = m.Parse(ahead)
+	assert.NoError(t, err)
+
+	m.now = func() time.Time { return issuedAt.Add(time.Minute) }
+	early, err := m.Issue("testuser")
+	require.NoError(t, err)
+	m.now = func() time.Time { return issuedAt }
+	_, err = m.Parse(early)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
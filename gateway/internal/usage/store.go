@@ -0,0 +1,135 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryStore keeps records in memory; counts are lost on restart
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[Key]Record
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[Key]Record)}
+}
+
+// Add merges counts into the stored records
+func (s *MemoryStore) Add(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addRecords(s.records, records)
+	return nil
+}
+
+// Query returns the records matching the filter
+func (s *MemoryStore) Query(ctx context.Context, filter Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return queryRecords(s.records, filter), nil
+}
+
+// FileStore keeps records in memory and rewrites a JSON file on every Add, so
+// counts survive restarts. The file is replaced atomically.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	records map[Key]Record
+}
+
+// NewFileStore opens the store at path, loading existing records. A missing
+// file starts an empty store.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path, records: make(map[Key]Record)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage store: %w", err)
+	}
+	var records []Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse usage store %s: %w", path, err)
+	}
+	addRecords(s.records, records)
+	return s, nil
+}
+
+// Add merges counts into the stored records and writes the file
+func (s *FileStore) Add(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[Key]Record, len(s.records)+len(records))
+	for k, r := range s.records {
+		next[k] = r
+	}
+	addRecords(next, records)
+	if err := s.write(next); err != nil {
+		return err
+	}
+	s.records = next
+	return nil
+}
+
+// Query returns the records matching the filter
+func (s *FileStore) Query(ctx context.Context, filter Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return queryRecords(s.records, filter), nil
+}
+
+func (s *FileStore) write(records map[Key]Record) error {
+	all := queryRecords(records, Filter{})
+	data, err := json.Marshal(all)
+	if err != nil {
+		return fmt.Errorf("failed to encode usage records: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write usage store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write usage store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write usage store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace usage store: %w", err)
+	}
+	return nil
+}
+
+func addRecords(dst map[Key]Record, records []Record) {
+	for _, r := range records {
+		existing := dst[r.Key]
+		existing.Key = r.Key
+		existing.Requests += r.Requests
+		existing.Errors += r.Errors
+		dst[r.Key] = existing
+	}
+}
+
+func queryRecords(records map[Key]Record, filter Filter) []Record {
+	matched := make([]Record, 0)
+	for k, r := range records {
+		if filter.matches(k) {
+			matched = append(matched, r)
+		}
+	}
+	sortRecords(matched)
+	return matched
+}
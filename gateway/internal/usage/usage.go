@@ -0,0 +1,213 @@
+// Package usage meters gateway traffic for billing and abuse detection.
+//
+// Requests are counted per day, tenant, subject (API key or JWT user), method
+// and route. Counts accumulate in memory and are flushed to a Store
+// periodically, so recording a request never waits on I/O. Queries merge the
+// stored counts with the ones not flushed yet.
+package usage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DayLayout is the format of Record.Day and of query bounds
+const DayLayout = "2006-01-02"
+
+// Key identifies one usage counter. Days are UTC.
+type Key struct {
+	Day     string `json:"day" example:"2025-12-06"`
+	Tenant  string `json:"tenant,omitempty" example:"6570a1f2c3d4e5f6a7b8c9d0"`
+	Subject string `json:"subject" example:"key:sk_live_****z789"`
+	Method  string `json:"method" example:"GET"`
+	Route   string `json:"route" example:"/api/v1/drivers/nearby"`
+}
+
+// Record is the request count of one key
+type Record struct {
+	Key
+	Requests int64 `json:"requests" example:"1520"`
+	// Errors counts responses with a 4xx or 5xx status
+	Errors int64 `json:"errors" example:"12"`
+}
+
+// Filter selects records in an inclusive day range; empty fields match everything
+type Filter struct {
+	From    string
+	To      string
+	Tenant  string
+	Subject string
+}
+
+func (f Filter) matches(k Key) bool {
+	return (f.From == "" || k.Day >= f.From) &&
+		(f.To == "" || k.Day <= f.To) &&
+		(f.Tenant == "" || k.Tenant == f.Tenant) &&
+		(f.Subject == "" || k.Subject == f.Subject)
+}
+
+// Store persists usage records
+type Store interface {
+	// Add merges counts into the stored records
+	Add(ctx context.Context, records []Record) error
+	Query(ctx context.Context, filter Filter) ([]Record, error)
+}
+
+// Meter counts requests and flushes them to a store
+type Meter struct {
+	mu      sync.Mutex
+	pending map[Key]*Record
+	store   Store
+	logger  *zap.Logger
+	now     func() time.Time
+}
+
+// NewMeter creates a meter that flushes to store
+func NewMeter(store Store, logger *zap.Logger) *Meter {
+	return &Meter{
+		pending: make(map[Key]*Record),
+		store:   store,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Record counts one request
+func (m *Meter) Record(tenant, subject, method, route string, status int) {
+	key := Key{
+		Day:     m.now().UTC().Format(DayLayout),
+		Tenant:  tenant,
+		Subject: subject,
+		Method:  method,
+		Route:   route,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.pending[key]
+	if !ok {
+		r = &Record{Key: key}
+		m.pending[key] = r
+	}
+	r.Requests++
+	if status >= 400 {
+		r.Errors++
+	}
+}
+
+// Flush writes the pending counts to the store. Counts are put back when the
+// store fails so they are retried with the next flush.
+func (m *Meter) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[Key]*Record)
+	m.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	records := make([]Record, 0, len(pending))
+	for _, r := range pending {
+		records = append(records, *r)
+	}
+	if err := m.store.Add(ctx, records); err != nil {
+		m.mu.Lock()
+		for _, r := range records {
+			m.merge(r)
+		}
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// merge adds a record to the pending counts; the caller holds m.mu
+func (m *Meter) merge(r Record) {
+	existing, ok := m.pending[r.Key]
+	if !ok {
+		copied := r
+		m.pending[r.Key] = &copied
+		return
+	}
+	existing.Requests += r.Requests
+	existing.Errors += r.Errors
+}
+
+// Run flushes every interval until ctx is done, then flushes once more
+func (m *Meter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := m.Flush(flushCtx); err != nil {
+				m.logger.Error("failed to flush usage on shutdown", zap.Error(err))
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil {
+				m.logger.Error("failed to flush usage", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Query returns stored and pending records matching the filter, sorted by
+// day, tenant, subject, route and method
+func (m *Meter) Query(ctx context.Context, filter Filter) ([]Record, error) {
+	stored, err := m.store.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[Key]*Record, len(stored))
+	for i := range stored {
+		merged[stored[i].Key] = &stored[i]
+	}
+	m.mu.Lock()
+	for key, r := range m.pending {
+		if !filter.matches(key) {
+			continue
+		}
+		if existing, ok := merged[key]; ok {
+			existing.Requests += r.Requests
+			existing.Errors += r.Errors
+			continue
+		}
+		copied := *r
+		merged[key] = &copied
+	}
+	m.mu.Unlock()
+
+	records := make([]Record, 0, len(merged))
+	for _, r := range merged {
+		records = append(records, *r)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+func sortRecords(records []Record) {
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i].Key, records[j].Key
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Method < b.Method
+	})
+}
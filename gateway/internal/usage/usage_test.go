@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingStore rejects every write
+type failingStore struct{ *MemoryStore }
+
+func (failingStore) Add(ctx context.Context, records []Record) error {
+	return errors.New("disk full")
+}
+
+func TestMeter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 12, 6, 23, 59, 0, 0, time.UTC)
+	meter := NewMeter(NewMemoryStore(), zap.NewNop())
+	meter.now = func() time.Time { return now }
+
+	meter.Record("", "key:sk_live_****z789", "GET", "/api/v1/drivers", 200)
+	meter.Record("", "key:sk_live_****z789", "GET", "/api/v1/drivers", 429)
+	meter.Record("fleet-1", "user:kadikoy", "GET", "/fleets/:id/drivers", 200)
+	require.NoError(t, meter.Flush(ctx))
+
+	now = now.Add(time.Minute)
+	meter.Record("", "key:sk_live_****z789", "GET", "/api/v1/drivers", 200)
+
+	t.Run("merges stored and pending counts", func(t *testing.T) {
+		records, err := meter.Query(ctx, Filter{Subject: "key:sk_live_****z789"})
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "2025-12-06", records[0].Day)
+		assert.Equal(t, int64(2), records[0].Requests)
+		assert.Equal(t, int64(1), records[0].Errors)
+		assert.Equal(t, "2025-12-07", records[1].Day)
+		assert.Equal(t, int64(1), records[1].Requests)
+	})
+
+	t.Run("filters by day and tenant", func(t *testing.T) {
+		records, err := meter.Query(ctx, Filter{From: "2025-12-07", To: "2025-12-07"})
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+
+		records, err = meter.Query(ctx, Filter{Tenant: "fleet-1"})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "user:kadikoy", records[0].Subject)
+	})
+}
+
+func TestMeter_FlushFailureKeepsCounts(t *testing.T) {
+	meter := NewMeter(failingStore{NewMemoryStore()}, zap.NewNop())
+	meter.Record("", "anonymous", "GET", "/health", 200)
+
+	assert.Error(t, meter.Flush(context.Background()))
+	records, err := meter.Query(context.Background(), Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, int64(1), records[0].Requests)
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "usage.json")
+	key := Key{Day: "2025-12-06", Subject: "user:admin", Method: "POST", Route: "/api/v1/drivers"}
+
+	store, err := NewFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Add(ctx, []Record{{Key: key, Requests: 3, Errors: 1}}))
+	require.NoError(t, store.Add(ctx, []Record{{Key: key, Requests: 2}}))
+
+	reopened, err := NewFileStore(path)
+	require.NoError(t, err)
+	records, err := reopened.Query(ctx, Filter{})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, int64(5), records[0].Requests)
+	assert.Equal(t, int64(1), records[0].Errors)
+}